@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy"
+)
+
+var (
+	mintOrgs        []string
+	mintDatasources []string
+	mintTools       []string
+	mintTTL         time.Duration
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage service-account tokens",
+}
+
+var tokenMintCmd = &cobra.Command{
+	Use:   "mint <name>",
+	Short: "Mint a long-lived, scoped token for a CI pipeline",
+	Long: `Mint a service-account access token signed with auth.tokens.secret_key.
+The proxy must run with auth.mode: oauth. The token is printed to stdout; its
+ID and expiry go to stderr. Revoke it by adding the ID to auth.tokens.revoked.
+
+The token is limited to the datasources matching --datasource, within what
+the orgs given with --org are allowed by allowed_orgs and datasource_scopes.
+An MCP server configured with the token in proxy.auth.token limits every
+caller to the tools given with --tool.`,
+	Example: `  panda-proxy token mint nightly-ci --org ethpandaops \
+    --datasource clickhouse:xatu --datasource prometheus --tool execute_python`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTokenMint,
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenMintCmd)
+
+	tokenMintCmd.Flags().StringSliceVar(&mintOrgs, "org", nil, "GitHub org the account acts as a member of (repeatable)")
+	tokenMintCmd.Flags().StringSliceVar(&mintDatasources, "datasource", nil,
+		`datasource pattern the token may use, e.g. "clickhouse:xatu" or "prometheus" (repeatable, required; "*" for all)`)
+	tokenMintCmd.Flags().StringSliceVar(&mintTools, "tool", nil, `MCP tool the token may call (repeatable, required; "*" for all)`)
+	tokenMintCmd.Flags().DurationVar(&mintTTL, "ttl", simpleauth.DefaultServiceAccountTTL,
+		fmt.Sprintf("token lifetime (max %s)", simpleauth.MaxServiceAccountTTL))
+}
+
+func runTokenMint(_ *cobra.Command, args []string) error {
+	cfg, err := proxy.LoadServerConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if cfg.Auth.Mode != proxy.AuthModeOAuth {
+		return fmt.Errorf("service-account tokens require auth.mode %q, got %q", proxy.AuthModeOAuth, cfg.Auth.Mode)
+	}
+
+	token, id, expiresAt, err := simpleauth.MintServiceAccountToken(simpleauth.Config{
+		IssuerURL: cfg.Auth.IssuerURL,
+		Tokens:    cfg.Auth.Tokens,
+	}, simpleauth.ServiceAccount{
+		Name:        args[0],
+		Orgs:        mintOrgs,
+		Datasources: mintDatasources,
+		Tools:       mintTools,
+		TTL:         mintTTL,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Token ID: %s\nExpires at: %s\n", id, expiresAt.Format(time.RFC3339))
+	fmt.Println(token)
+
+	return nil
+}
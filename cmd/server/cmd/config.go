@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/app"
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/configschema"
+	"github.com/ethpandaops/panda/pkg/module"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate the server config",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a config file",
+	Long: `Validate a config file against the config schema, then load it as serve would.
+
+Reports unknown keys (with the closest known key), values of the wrong type
+and deprecated fields with their replacement. The path defaults to --config.
+Exits nonzero when the config has errors; warnings alone pass.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runConfigValidate,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the config JSON Schema",
+	Long: `Print the JSON Schema of the config file, for editor completion and CI checks.
+
+Each module's own config schema is included under $defs as "module.<name>".`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+func runConfigValidate(_ *cobra.Command, args []string) error {
+	path := cfgFile
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	resolvedPath, issues, err := config.Lint(path)
+	if err != nil {
+		return err
+	}
+
+	errorCount := 0
+
+	for _, issue := range issues {
+		if issue.Severity == configschema.SeverityError {
+			errorCount++
+		}
+
+		fmt.Printf("%s: %s: %s\n", resolvedPath, issue.Severity, issue)
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("%s has %d error(s)", resolvedPath, errorCount)
+	}
+
+	// The schema only covers structure; Load resolves secrets and runs the
+	// semantic checks serve would.
+	if _, err := config.Load(resolvedPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is valid\n", resolvedPath)
+
+	return nil
+}
+
+func runConfigSchema(_ *cobra.Command, _ []string) error {
+	schema := config.Schema()
+	schema.Defs = make(map[string]*configschema.Schema, 16)
+
+	for _, m := range app.Modules() {
+		if provider, ok := m.(module.SchemaProvider); ok {
+			schema.Defs["module."+m.Name()] = configschema.Generate(provider.ConfigSchema())
+		}
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling schema: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
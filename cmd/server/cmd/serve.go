@@ -15,7 +15,11 @@ import (
 	"github.com/ethpandaops/panda/pkg/server"
 )
 
-var port int
+var (
+	port        int
+	offlineMode bool
+	stdioMode   bool
+)
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
@@ -28,6 +32,8 @@ func init() {
 	rootCmd.AddCommand(serveCmd)
 
 	serveCmd.Flags().IntVarP(&port, "port", "p", 0, "Port number. Overrides config.")
+	serveCmd.Flags().BoolVar(&offlineMode, "offline", false, "Serve snapshotted data only, without outbound calls. Overrides config.")
+	serveCmd.Flags().BoolVar(&stdioMode, "stdio", false, "Also serve MCP over stdin/stdout alongside HTTP. Overrides config.")
 }
 
 func runServe(_ *cobra.Command, _ []string) error {
@@ -47,6 +53,14 @@ func runServe(_ *cobra.Command, _ []string) error {
 		cfg.Server.Port = port
 	}
 
+	if offlineMode {
+		cfg.Offline.Enabled = true
+	}
+
+	if stdioMode {
+		cfg.Server.Stdio = true
+	}
+
 	// Start observability service (metrics).
 	obsSvc := observability.NewService(log, cfg.Observability)
 	if err := obsSvc.Start(ctx); err != nil {
@@ -77,10 +91,20 @@ func runServe(_ *cobra.Command, _ []string) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// The first signal drains the server: readiness fails, new tool calls
+	// are rejected and in-flight executions get the grace period to finish.
+	// A second signal shuts down immediately.
 	go func() {
 		sig := <-sigCh
-		log.WithField("signal", sig).Info("Received shutdown signal")
-		signal.Stop(sigCh)
+		log.WithField("signal", sig).Info("Received shutdown signal, draining")
+
+		go func() {
+			sig := <-sigCh
+			log.WithField("signal", sig).Warn("Received second shutdown signal, skipping drain")
+			cancel()
+		}()
+
+		svc.Drain(ctx)
 		cancel()
 	}()
 
@@ -0,0 +1,185 @@
+package assertoor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+var _ module.ResourceProvider = (*Module)(nil)
+
+// Module implements the module.Module interface for the assertoor module.
+type Module struct {
+	cfg                 Config
+	cartographoorClient cartographoor.CartographoorClient
+}
+
+// New creates a new assertoor module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "assertoor" }
+
+// Enabled reports whether assertoor operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// Assertoor is enabled by default since it requires no configuration.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		// No config provided, use defaults (enabled = true).
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// No validation needed - config is minimal.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_ASSERTOOR_NETWORKS with network->URL mapping from cartographoor.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	networks := p.networks()
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling assertoor networks: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_ASSERTOOR_NETWORKS": string(networksJSON),
+	}, nil
+}
+
+// DatasourceInfo returns empty since networks are the datasources,
+// and those come from cartographoor.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	for k, v := range queryExamples {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"assertoor": {
+			Description: "Inspect assertoor test runs on devnets and testnets and triage failed runs",
+			Functions: map[string]types.FunctionDoc{
+				"list_networks":      {Signature: "list_networks() -> list[dict]", Description: "List networks with an assertoor instance"},
+				"get_base_url":       {Signature: "get_base_url(network) -> str", Description: "Get the assertoor URL for a network"},
+				"list_runs":          {Signature: "list_runs(network, status=None) -> list[dict]", Description: "List test runs, newest first, optionally only those with a status such as failure"},
+				"get_run":            {Signature: "get_run(network, run_id) -> dict", Description: "Get a test run with the status, result and error of each task"},
+				"summarize_failures": {Signature: "summarize_failures(network, run_id) -> dict", Description: "Get the failing tasks that caused a run to fail, with their errors and the tail of their warning and error logs"},
+				"link_run":           {Signature: "link_run(network, run_id) -> str", Description: "Deep link to a test run in the assertoor UI"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Assertoor Test Runs
+
+Assertoor runs test playbooks against devnets and testnets. Summarize a
+failed run to see which tasks failed and why without reading every log.
+
+` + "```python" + `
+from ethpandaops import assertoor
+
+failed = assertoor.list_runs("hoodi", status="failure")
+if failed:
+    report = assertoor.summarize_failures("hoodi", failed[0]["run_id"])
+    for failure in report["failures"]:
+        print(failure["title"], failure["error"])
+` + "```" + `
+`
+}
+
+// RegisterResources registers the assertoor:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(
+		log.WithField("module", "assertoor"),
+		reg,
+		&http.Client{Timeout: requestTimeout},
+		p.networks,
+	)
+
+	return nil
+}
+
+// SetCartographoorClient implements module.CartographoorAware.
+// This is called by the builder to inject the cartographoor client.
+func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
+	p.cartographoorClient = client
+}
+
+// networks returns the network -> assertoor URL mapping from cartographoor.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.Assertoor != "" {
+			networks[name] = network.ServiceURLs.Assertoor
+		}
+	}
+
+	return networks
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
@@ -0,0 +1,49 @@
+"""Thin assertoor wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_assertoor_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_ASSERTOOR_NETWORKS", "").strip():
+        raise ValueError("Assertoor is not enabled or no assertoor instances are available.")
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_assertoor_available()
+    data = _runtime.invoke_data("assertoor.list_networks")
+    return data.get("networks", [])
+
+
+def get_base_url(network: str) -> str:
+    _require_assertoor_available()
+    data = _runtime.invoke_data("assertoor.get_base_url", {"network": network})
+    return data.get("base_url", "")
+
+
+def list_runs(network: str, status: str | None = None) -> list[dict[str, Any]]:
+    _require_assertoor_available()
+    payload = _runtime.invoke_json("assertoor.list_runs", {"network": network})
+    runs = sorted(payload.get("data") or [], key=lambda run: run.get("run_id", 0), reverse=True)
+    if status is not None:
+        runs = [run for run in runs if run.get("status") == status]
+    return runs
+
+
+def get_run(network: str, run_id: int) -> dict[str, Any]:
+    _require_assertoor_available()
+    payload = _runtime.invoke_json("assertoor.get_run", {"network": network, "run_id": run_id})
+    return payload.get("data") or {}
+
+
+def summarize_failures(network: str, run_id: int) -> dict[str, Any]:
+    _require_assertoor_available()
+    return _runtime.invoke_data("assertoor.summarize_failures", {"network": network, "run_id": run_id})
+
+
+def link_run(network: str, run_id: int) -> str:
+    return f"{get_base_url(network)}/run/{run_id}"
@@ -0,0 +1,359 @@
+package assertoor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// RunsPath lists the test runs an assertoor instance knows about.
+	RunsPath = "/api/v1/test_runs"
+
+	// requestTimeout bounds a single assertoor call made for a resource read.
+	requestTimeout = 30 * time.Second
+
+	// maxListedRuns caps the runs returned by the runs resource, newest first.
+	maxListedRuns = 50
+)
+
+var (
+	// runsURIPattern matches assertoor://network/{name}/runs URIs.
+	runsURIPattern = regexp.MustCompile(`^assertoor://network/([^/]+)/runs$`)
+
+	// runURIPattern matches assertoor://network/{name}/runs/{run_id} URIs.
+	runURIPattern = regexp.MustCompile(`^assertoor://network/([^/]+)/runs/(\d+)$`)
+
+	// failuresURIPattern matches
+	// assertoor://network/{name}/runs/{run_id}/failures URIs.
+	failuresURIPattern = regexp.MustCompile(`^assertoor://network/([^/]+)/runs/(\d+)/failures$`)
+)
+
+// RunPath returns the API path of a test run with its tasks.
+func RunPath(runID uint64) string {
+	return "/api/v1/test_run/" + strconv.FormatUint(runID, 10)
+}
+
+// TaskDetailsPath returns the API path of a task's details, including its log.
+func TaskDetailsPath(runID, taskIndex uint64) string {
+	return RunPath(runID) + "/task/" + strconv.FormatUint(taskIndex, 10) + "/details"
+}
+
+// RunLink returns the assertoor web UI link of a test run.
+func RunLink(baseURL string, runID uint64) string {
+	return strings.TrimRight(baseURL, "/") + "/run/" + strconv.FormatUint(runID, 10)
+}
+
+// NetworkInstance is a network with an assertoor instance.
+type NetworkInstance struct {
+	Name         string `json:"name"`
+	AssertoorURL string `json:"assertoor_url"`
+}
+
+// NetworksListResponse is the response for assertoor://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkInstance `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// RunSummary is one test run in an assertoor run list.
+type RunSummary struct {
+	RunID     uint64 `json:"run_id"`
+	TestID    string `json:"test_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	StartTime int64  `json:"start_time"`
+	StopTime  int64  `json:"stop_time"`
+}
+
+// RunsResponse is the response for assertoor://network/{name}/runs.
+type RunsResponse struct {
+	Network      string         `json:"network"`
+	AssertoorURL string         `json:"assertoor_url"`
+	StatusCounts map[string]int `json:"status_counts"`
+	Runs         []RunSummary   `json:"runs"`
+	// Omitted is the number of older runs left out of Runs.
+	Omitted int    `json:"omitted,omitempty"`
+	Usage   string `json:"usage"`
+}
+
+// RunResponse is the response for assertoor://network/{name}/runs/{run_id}.
+type RunResponse struct {
+	Network string          `json:"network"`
+	Link    string          `json:"link"`
+	Run     json.RawMessage `json:"run"`
+}
+
+// RegisterNetworkResources registers the assertoor:// resources. networks
+// returns the current network -> assertoor URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"assertoor://networks",
+			"Assertoor Instances",
+			mcp.WithResourceDescription("Networks with an assertoor test runner"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"assertoor://network/{name}/runs",
+			"Assertoor Test Runs",
+			mcp.WithTemplateDescription("Recent assertoor test runs on a network, newest first, with counts by status"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: runsURIPattern,
+		Handler: createRunsHandler(httpClient, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"assertoor://network/{name}/runs/{run_id}",
+			"Assertoor Test Run",
+			mcp.WithTemplateDescription("An assertoor test run with the status and result of each task"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Pattern: runURIPattern,
+		Handler: createRunHandler(httpClient, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"assertoor://network/{name}/runs/{run_id}/failures",
+			"Assertoor Test Run Failures",
+			mcp.WithTemplateDescription("Condensed failure report of an assertoor test run: the failing tasks that caused the failure, their errors and the tail of their logs"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Pattern: failuresURIPattern,
+		Handler: createFailuresHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered assertoor resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		instances := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with an assertoor test runner.",
+			Networks:    make([]NetworkInstance, 0, len(instances)),
+			Usage:       "Read assertoor://network/{name}/runs for recent test runs and assertoor://network/{name}/runs/{run_id}/failures to triage a failed run.",
+		}
+
+		for name, baseURL := range instances {
+			response.Networks = append(response.Networks, NetworkInstance{Name: name, AssertoorURL: baseURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createRunsHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, _, err := resolveNetwork(runsURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := FetchData(ctx, httpClient, baseURL, RunsPath)
+		if err != nil {
+			return "", err
+		}
+
+		var runs []RunSummary
+		if err := json.Unmarshal(data, &runs); err != nil {
+			return "", fmt.Errorf("decoding assertoor test runs: %w", err)
+		}
+
+		sort.Slice(runs, func(i, j int) bool { return runs[i].RunID > runs[j].RunID })
+
+		response := &RunsResponse{
+			Network:      network,
+			AssertoorURL: baseURL,
+			StatusCounts: make(map[string]int),
+			Usage:        "Read assertoor://network/" + network + "/runs/{run_id}/failures to triage a failed run.",
+		}
+
+		for _, run := range runs {
+			response.StatusCounts[run.Status]++
+		}
+
+		if len(runs) > maxListedRuns {
+			response.Omitted = len(runs) - maxListedRuns
+			runs = runs[:maxListedRuns]
+		}
+
+		response.Runs = runs
+
+		return marshal(response)
+	}
+}
+
+func createRunHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, runID, err := resolveNetwork(runURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := FetchData(ctx, httpClient, baseURL, RunPath(runID))
+		if err != nil {
+			return "", err
+		}
+
+		return marshal(&RunResponse{
+			Network: network,
+			Link:    RunLink(baseURL, runID),
+			Run:     data,
+		})
+	}
+}
+
+func createFailuresHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, runID, err := resolveNetwork(failuresURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		report, err := SummarizeFailures(ctx, httpClient, baseURL, runID)
+		if err != nil {
+			return "", err
+		}
+
+		report.Network = network
+
+		return marshal(report)
+	}
+}
+
+// resolveNetwork extracts the network and, if the pattern has one, the run ID
+// from uri and looks up the network's assertoor URL.
+func resolveNetwork(
+	pattern *regexp.Regexp,
+	uri string,
+	networks func() map[string]string,
+) (string, string, uint64, error) {
+	matches := pattern.FindStringSubmatch(uri)
+	if len(matches) < 2 {
+		return "", "", 0, fmt.Errorf("invalid assertoor URI: %s", uri)
+	}
+
+	var runID uint64
+
+	if len(matches) > 2 {
+		id, err := strconv.ParseUint(matches[2], 10, 64)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("invalid assertoor run ID %q: %w", matches[2], err)
+		}
+
+		runID = id
+	}
+
+	network := matches[1]
+	instances := networks()
+
+	baseURL, ok := instances[network]
+	if !ok {
+		names := make([]string, 0, len(instances))
+		for name := range instances {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", "", 0, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return network, baseURL, runID, nil
+}
+
+// FetchData reads an assertoor API endpoint and returns the contents of its
+// "data" envelope.
+func FetchData(ctx context.Context, httpClient *http.Client, baseURL, path string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating assertoor request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting assertoor %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading assertoor %s: %w", path, err)
+	}
+
+	var envelope struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Assertoor reports errors such as unknown runs in the envelope status.
+		if json.Unmarshal(body, &envelope) == nil && envelope.Status != "" {
+			return nil, fmt.Errorf("assertoor %s returned %d: %s", path, resp.StatusCode, envelope.Status)
+		}
+
+		return nil, fmt.Errorf("assertoor %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding assertoor %s: %w", path, err)
+	}
+
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		if envelope.Status != "" && envelope.Status != "OK" {
+			return nil, fmt.Errorf("assertoor %s: %s", path, envelope.Status)
+		}
+
+		return nil, fmt.Errorf("assertoor %s returned no data", path)
+	}
+
+	return envelope.Data, nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling assertoor response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,128 @@
+package assertoor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunsHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, RunsPath, r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"status":"OK","data":[
+			{"run_id":1,"test_id":"deposits","name":"Deposits","status":"success"},
+			{"run_id":3,"test_id":"blobs","name":"Blobs","status":"failure"},
+			{"run_id":2,"test_id":"blobs","name":"Blobs","status":"failure"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createRunsHandler(srv.Client(), networks)(context.Background(), "assertoor://network/hoodi/runs")
+	require.NoError(t, err)
+
+	var resp RunsResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "hoodi", resp.Network)
+	assert.Equal(t, map[string]int{"success": 1, "failure": 2}, resp.StatusCounts)
+	require.Len(t, resp.Runs, 3)
+	assert.Equal(t, uint64(3), resp.Runs[0].RunID)
+	assert.Equal(t, uint64(1), resp.Runs[2].RunID)
+}
+
+func TestFailuresHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/test_run/7":
+			_, _ = w.Write([]byte(`{"status":"OK","data":{
+				"run_id":7,"test_id":"blobs","name":"Blob transactions","status":"failure",
+				"tasks":[
+					{"index":1,"parent_index":0,"name":"run_tasks","title":"Blob checks","result":"failure","result_error":"child task failed"},
+					{"index":2,"parent_index":1,"name":"check_clients_are_healthy","title":"Clients healthy","result":"success"},
+					{"index":3,"parent_index":1,"name":"generate_blob_transactions","title":"Send blobs","result":"failure","result_error":"timeout","runtime":60000}
+				]
+			}}`))
+		case "/api/v1/test_run/7/task/3/details":
+			_, _ = w.Write([]byte(`{"status":"OK","data":{"log":[
+				{"time":"2026-01-01T00:00:00Z","level":"info","msg":"sending blobs"},
+				{"time":"2026-01-01T00:01:00Z","level":"error","msg":"transaction not included"}
+			]}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createFailuresHandler(srv.Client(), networks)(context.Background(), "assertoor://network/hoodi/runs/7/failures")
+	require.NoError(t, err)
+
+	var report FailureReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, "hoodi", report.Network)
+	assert.Equal(t, srv.URL+"/run/7", report.Link)
+	assert.Equal(t, 3, report.TaskCount)
+	assert.Equal(t, 2, report.FailedTaskCount)
+	require.Len(t, report.Failures, 1)
+
+	failure := report.Failures[0]
+	assert.Equal(t, uint64(3), failure.Index)
+	assert.Equal(t, []string{"Blob checks"}, failure.Path)
+	assert.Equal(t, "timeout", failure.Error)
+	assert.Equal(t, []string{"2026-01-01T00:01:00Z [error] transaction not included"}, failure.Log)
+}
+
+func TestFailuresHandlerLogError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/test_run/7" {
+			_, _ = w.Write([]byte(`{"status":"OK","data":{"run_id":7,"status":"failure","tasks":[
+				{"index":1,"parent_index":0,"name":"check","result":"failure"}
+			]}}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":"ERROR: task not found"}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+	handler := createFailuresHandler(srv.Client(), networks)
+
+	out, err := handler(context.Background(), "assertoor://network/hoodi/runs/7/failures")
+	require.NoError(t, err)
+
+	var report FailureReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Len(t, report.Failures, 1)
+	assert.Contains(t, report.Failures[0].LogError, "task not found")
+	assert.Empty(t, report.Failures[0].Log)
+
+	_, err = handler(context.Background(), "assertoor://network/mainnet/runs/7/failures")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "mainnet"`)
+}
+
+func TestCondenseLog(t *testing.T) {
+	entries := make([]taskLogEntry, 0, maxLogLines+5)
+	for i := 0; i < maxLogLines+5; i++ {
+		entries = append(entries, taskLogEntry{Level: "info", Message: "progress"})
+	}
+
+	lines := condenseLog(entries)
+	assert.Len(t, lines, maxLogLines)
+
+	long := condenseLog([]taskLogEntry{{Level: "warning", Message: string(make([]byte, 2*maxLogLineLength))}})
+	require.Len(t, long, 1)
+	assert.Len(t, []rune(long[0]), maxLogLineLength+3)
+}
@@ -0,0 +1,265 @@
+package assertoor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxReportedFailures caps the failing tasks whose logs are fetched.
+	maxReportedFailures = 10
+
+	// maxLogLines caps the log lines kept per failing task.
+	maxLogLines = 15
+
+	// maxLogLineLength caps the length of a kept log line.
+	maxLogLineLength = 300
+
+	// resultFailure is the result of a task that failed.
+	resultFailure = "failure"
+)
+
+// FailureReport condenses a test run to the tasks that caused it to fail.
+type FailureReport struct {
+	Network   string `json:"network,omitempty"`
+	RunID     uint64 `json:"run_id"`
+	TestID    string `json:"test_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Link      string `json:"link"`
+	TaskCount int    `json:"task_count"`
+	// FailedTaskCount counts every failed task, including parents that only
+	// failed because a child did.
+	FailedTaskCount int           `json:"failed_task_count"`
+	Failures        []TaskFailure `json:"failures"`
+	// Omitted is the number of root-cause failures left out of Failures.
+	Omitted int `json:"omitted,omitempty"`
+}
+
+// TaskFailure is a failed task with no failed children.
+type TaskFailure struct {
+	Index uint64 `json:"index"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	// Path is the titles of the task's ancestors, outermost first.
+	Path      []string `json:"path,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	RuntimeMS int64    `json:"runtime_ms"`
+	// Log is the tail of the task's warnings and errors, or of its whole log
+	// when it has none.
+	Log      []string `json:"log"`
+	LogError string   `json:"log_error,omitempty"`
+}
+
+// runDetails is the subset of an assertoor test run the report uses.
+type runDetails struct {
+	RunID  uint64        `json:"run_id"`
+	TestID string        `json:"test_id"`
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Tasks  []taskSummary `json:"tasks"`
+}
+
+type taskSummary struct {
+	Index       uint64 `json:"index"`
+	ParentIndex uint64 `json:"parent_index"`
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Result      string `json:"result"`
+	ResultError string `json:"result_error"`
+	Runtime     int64  `json:"runtime"`
+}
+
+type taskLogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"msg"`
+}
+
+// SummarizeFailures fetches a test run and the logs of its root-cause
+// failures. A failed task is a root cause when none of its children failed.
+// Log fetch errors are reported per task instead of failing the report.
+func SummarizeFailures(
+	ctx context.Context,
+	httpClient *http.Client,
+	baseURL string,
+	runID uint64,
+) (*FailureReport, error) {
+	data, err := FetchData(ctx, httpClient, baseURL, RunPath(runID))
+	if err != nil {
+		return nil, err
+	}
+
+	var run runDetails
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("decoding assertoor test run: %w", err)
+	}
+
+	report := &FailureReport{
+		RunID:     runID,
+		TestID:    run.TestID,
+		Name:      run.Name,
+		Status:    run.Status,
+		Link:      RunLink(baseURL, runID),
+		TaskCount: len(run.Tasks),
+		Failures:  make([]TaskFailure, 0),
+	}
+
+	roots := rootCauses(run.Tasks)
+
+	for _, task := range run.Tasks {
+		if task.Result == resultFailure {
+			report.FailedTaskCount++
+		}
+	}
+
+	if len(roots) > maxReportedFailures {
+		report.Omitted = len(roots) - maxReportedFailures
+		roots = roots[:maxReportedFailures]
+	}
+
+	byIndex := make(map[uint64]taskSummary, len(run.Tasks))
+	for _, task := range run.Tasks {
+		byIndex[task.Index] = task
+	}
+
+	for _, task := range roots {
+		failure := TaskFailure{
+			Index:     task.Index,
+			Name:      task.Name,
+			Title:     task.Title,
+			Path:      taskPath(task, byIndex),
+			Error:     task.ResultError,
+			RuntimeMS: task.Runtime,
+			Log:       make([]string, 0),
+		}
+
+		log, err := fetchTaskLog(ctx, httpClient, baseURL, runID, task.Index)
+		if err != nil {
+			failure.LogError = err.Error()
+		} else {
+			failure.Log = condenseLog(log)
+		}
+
+		report.Failures = append(report.Failures, failure)
+	}
+
+	return report, nil
+}
+
+// rootCauses returns the failed tasks none of whose children failed, in task
+// order.
+func rootCauses(tasks []taskSummary) []taskSummary {
+	failedParents := make(map[uint64]struct{}, len(tasks))
+
+	for _, task := range tasks {
+		if task.Result == resultFailure && task.ParentIndex != task.Index {
+			failedParents[task.ParentIndex] = struct{}{}
+		}
+	}
+
+	roots := make([]taskSummary, 0)
+
+	for _, task := range tasks {
+		if task.Result != resultFailure {
+			continue
+		}
+
+		if _, ok := failedParents[task.Index]; ok {
+			continue
+		}
+
+		roots = append(roots, task)
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool { return roots[i].Index < roots[j].Index })
+
+	return roots
+}
+
+// taskPath returns the titles of task's ancestors, outermost first. Root
+// tasks have a parent index of 0, which is not a task.
+func taskPath(task taskSummary, byIndex map[uint64]taskSummary) []string {
+	var path []string
+
+	seen := map[uint64]struct{}{task.Index: {}}
+
+	for parent, ok := byIndex[task.ParentIndex]; ok; parent, ok = byIndex[parent.ParentIndex] {
+		if _, loop := seen[parent.Index]; loop {
+			break
+		}
+
+		seen[parent.Index] = struct{}{}
+
+		title := parent.Title
+		if title == "" {
+			title = parent.Name
+		}
+
+		path = append([]string{title}, path...)
+	}
+
+	return path
+}
+
+func fetchTaskLog(
+	ctx context.Context,
+	httpClient *http.Client,
+	baseURL string,
+	runID, taskIndex uint64,
+) ([]taskLogEntry, error) {
+	data, err := FetchData(ctx, httpClient, baseURL, TaskDetailsPath(runID, taskIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	var details struct {
+		Log []taskLogEntry `json:"log"`
+	}
+
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil, fmt.Errorf("decoding assertoor task details: %w", err)
+	}
+
+	return details.Log, nil
+}
+
+// condenseLog keeps the last warning and error lines of a task log, falling
+// back to the last lines of any level, formatted one entry per line.
+func condenseLog(entries []taskLogEntry) []string {
+	kept := make([]taskLogEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		switch strings.ToLower(entry.Level) {
+		case "warning", "warn", "error", "fatal", "panic":
+			kept = append(kept, entry)
+		}
+	}
+
+	if len(kept) == 0 {
+		kept = entries
+	}
+
+	if len(kept) > maxLogLines {
+		kept = kept[len(kept)-maxLogLines:]
+	}
+
+	lines := make([]string, 0, len(kept))
+
+	for _, entry := range kept {
+		line := fmt.Sprintf("%s [%s] %s", entry.Time.UTC().Format(time.RFC3339), entry.Level, entry.Message)
+
+		if runes := []rune(line); len(runes) > maxLogLineLength {
+			line = string(runes[:maxLogLineLength]) + "..."
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,62 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a single beacon API call made for a resource read.
+const requestTimeout = 30 * time.Second
+
+// client reads the public beacon node HTTP API of a network.
+type client struct {
+	httpClient *http.Client
+}
+
+func newClient() *client {
+	return &client{httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// getData issues a GET against a beacon node and decodes the "data" field
+// of the standard beacon API envelope into out.
+func (c *client) getData(ctx context.Context, baseURL, path string, out any) error {
+	requestURL := strings.TrimRight(baseURL, "/") + path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("beacon node %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("decoding %s data: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package beacon
+
+// Config holds the beacon module configuration.
+// The module is enabled by default since the beacon endpoints it queries
+// are public and require no credentials.
+type Config struct {
+	// Enabled controls whether the beacon module is active.
+	// Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+// IsEnabled returns true if the module is enabled (default: true).
+func (c *Config) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
@@ -0,0 +1,179 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Module implements the module.Module interface for the beacon module.
+type Module struct {
+	cfg                 Config
+	cartographoorClient cartographoor.CartographoorClient
+}
+
+// New creates a new beacon module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "beacon" }
+
+// Enabled reports whether beacon operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// The beacon module is enabled by default since it requires no configuration.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		// No config provided, use defaults (enabled = true).
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// No validation needed - config is minimal.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_BEACON_NETWORKS with network->URL mapping from cartographoor.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	networks := p.networks()
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling beacon networks: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_BEACON_NETWORKS": string(networksJSON),
+	}, nil
+}
+
+// DatasourceInfo returns empty since networks are the datasources,
+// and those come from cartographoor.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	for k, v := range queryExamples {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"beacon": {
+			Description: "Query live chain state from public beacon node APIs, for cross-checking ClickHouse data",
+			Functions: map[string]types.FunctionDoc{
+				"list_networks":            {Signature: "list_networks() -> list[dict]", Description: "List networks with a public beacon node API"},
+				"get_block":                {Signature: "get_block(network, block_id='head') -> dict", Description: "Get a signed beacon block by slot, root, 'head', 'finalized' or 'genesis'"},
+				"get_header":               {Signature: "get_header(network, block_id='head') -> dict", Description: "Get a beacon block header"},
+				"get_validator":            {Signature: "get_validator(network, validator_id, state_id='head') -> dict", Description: "Get a validator by index or pubkey at a state"},
+				"get_finality_checkpoints": {Signature: "get_finality_checkpoints(network, state_id='head') -> dict", Description: "Get justified and finalized checkpoints at a state"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Beacon Node API
+
+Read live chain state from each network's public beacon node to cross-check
+ClickHouse data. Resources: beacon://networks, beacon://networks/{network}/head
+and beacon://networks/{network}/finality.
+
+` + "```python" + `
+from ethpandaops import beacon
+
+# Compare the live finalized epoch with what ClickHouse has ingested
+checkpoints = beacon.get_finality_checkpoints("hoodi")
+print(f"Finalized epoch: {checkpoints['finalized']['epoch']}")
+
+# Fetch a block at a slot seen in ClickHouse
+block = beacon.get_block("hoodi", "123456")
+print(block["message"]["proposer_index"])
+` + "```" + `
+`
+}
+
+// RegisterResources registers the beacon:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(log.WithField("module", "beacon"), reg, newClient(), p.networks)
+
+	return nil
+}
+
+// SetCartographoorClient implements module.CartographoorAware.
+// This is called by the builder to inject the cartographoor client.
+func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
+	p.cartographoorClient = client
+}
+
+// networks returns the network -> beacon URL mapping from cartographoor.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.BeaconRPC != "" {
+			networks[name] = network.ServiceURLs.BeaconRPC
+		}
+	}
+
+	return networks
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
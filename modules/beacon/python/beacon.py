@@ -0,0 +1,56 @@
+"""Thin beacon node API wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_beacon_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_BEACON_NETWORKS", "").strip():
+        raise ValueError("Beacon module is not enabled or no beacon node APIs are available.")
+
+
+def _data(operation_id: str, args: dict[str, Any]) -> dict[str, Any]:
+    _require_beacon_available()
+    payload = _runtime.invoke_json(operation_id, args)
+    if not isinstance(payload, dict):
+        return {}
+    data = payload.get("data")
+    return data if isinstance(data, dict) else {}
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_beacon_available()
+    data = _runtime.invoke_data("beacon.list_networks")
+    return data.get("networks", [])
+
+
+def get_block(network: str, block_id: str | int = "head") -> dict[str, Any]:
+    return _data("beacon.get_block", {"network": network, "block_id": str(block_id)})
+
+
+def get_header(network: str, block_id: str | int = "head") -> dict[str, Any]:
+    return _data("beacon.get_header", {"network": network, "block_id": str(block_id)})
+
+
+def get_validator(
+    network: str, validator_id: str | int, state_id: str | int = "head"
+) -> dict[str, Any]:
+    return _data(
+        "beacon.get_validator",
+        {
+            "network": network,
+            "validator_id": str(validator_id),
+            "state_id": str(state_id),
+        },
+    )
+
+
+def get_finality_checkpoints(network: str, state_id: str | int = "head") -> dict[str, Any]:
+    return _data(
+        "beacon.get_finality_checkpoints",
+        {"network": network, "state_id": str(state_id)},
+    )
@@ -0,0 +1,232 @@
+package beacon
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+var (
+	// headURIPattern matches beacon://networks/{network}/head URIs.
+	headURIPattern = regexp.MustCompile(`^beacon://networks/([^/]+)/head$`)
+
+	// finalityURIPattern matches beacon://networks/{network}/finality URIs.
+	finalityURIPattern = regexp.MustCompile(`^beacon://networks/([^/]+)/finality$`)
+)
+
+// NetworkEndpoint is a network with a public beacon node endpoint.
+type NetworkEndpoint struct {
+	Name      string `json:"name"`
+	BeaconURL string `json:"beacon_url"`
+}
+
+// NetworksListResponse is the response for beacon://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkEndpoint `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// HeadResponse is the response for beacon://networks/{network}/head.
+type HeadResponse struct {
+	Network       string `json:"network"`
+	Slot          string `json:"slot"`
+	ProposerIndex string `json:"proposer_index"`
+	Root          string `json:"root"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+}
+
+// Checkpoint is a beacon chain checkpoint.
+type Checkpoint struct {
+	Epoch string `json:"epoch"`
+	Root  string `json:"root"`
+}
+
+// FinalityResponse is the response for beacon://networks/{network}/finality.
+type FinalityResponse struct {
+	Network           string     `json:"network"`
+	PreviousJustified Checkpoint `json:"previous_justified"`
+	CurrentJustified  Checkpoint `json:"current_justified"`
+	Finalized         Checkpoint `json:"finalized"`
+}
+
+// headerData is the data field of /eth/v1/beacon/headers/{block_id}.
+type headerData struct {
+	Root   string `json:"root"`
+	Header struct {
+		Message struct {
+			Slot          string `json:"slot"`
+			ProposerIndex string `json:"proposer_index"`
+			ParentRoot    string `json:"parent_root"`
+			StateRoot     string `json:"state_root"`
+		} `json:"message"`
+	} `json:"header"`
+}
+
+// finalityData is the data field of
+// /eth/v1/beacon/states/{state_id}/finality_checkpoints.
+type finalityData struct {
+	PreviousJustified Checkpoint `json:"previous_justified"`
+	CurrentJustified  Checkpoint `json:"current_justified"`
+	Finalized         Checkpoint `json:"finalized"`
+}
+
+// RegisterNetworkResources registers the beacon:// resources. networks
+// returns the current network -> beacon URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	client *client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"beacon://networks",
+			"Beacon Node Endpoints",
+			mcp.WithResourceDescription("Networks with a public beacon node API for live chain state queries"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"beacon://networks/{network}/head",
+			"Beacon Chain Head",
+			mcp.WithTemplateDescription("Current head block header of a network, read live from its beacon node"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: headURIPattern,
+		Handler: createHeadHandler(client, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"beacon://networks/{network}/finality",
+			"Beacon Chain Finality",
+			mcp.WithTemplateDescription("Justified and finalized checkpoints of a network, read live from its beacon node"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: finalityURIPattern,
+		Handler: createFinalityHandler(client, networks),
+	})
+
+	log.Debug("Registered beacon resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		endpoints := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with a public beacon node API.",
+			Networks:    make([]NetworkEndpoint, 0, len(endpoints)),
+			Usage:       "Read beacon://networks/{network}/head or beacon://networks/{network}/finality for live chain state.",
+		}
+
+		for name, beaconURL := range endpoints {
+			response.Networks = append(response.Networks, NetworkEndpoint{Name: name, BeaconURL: beaconURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createHeadHandler(client *client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, err := resolveNetwork(headURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		var header headerData
+		if err := client.getData(ctx, baseURL, "/eth/v1/beacon/headers/head", &header); err != nil {
+			return "", err
+		}
+
+		return marshal(&HeadResponse{
+			Network:       network,
+			Slot:          header.Header.Message.Slot,
+			ProposerIndex: header.Header.Message.ProposerIndex,
+			Root:          header.Root,
+			ParentRoot:    header.Header.Message.ParentRoot,
+			StateRoot:     header.Header.Message.StateRoot,
+		})
+	}
+}
+
+func createFinalityHandler(client *client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, err := resolveNetwork(finalityURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		var finality finalityData
+		if err := client.getData(ctx, baseURL, "/eth/v1/beacon/states/head/finality_checkpoints", &finality); err != nil {
+			return "", err
+		}
+
+		return marshal(&FinalityResponse{
+			Network:           network,
+			PreviousJustified: finality.PreviousJustified,
+			CurrentJustified:  finality.CurrentJustified,
+			Finalized:         finality.Finalized,
+		})
+	}
+}
+
+// resolveNetwork extracts the network from a resource URI and looks up its
+// beacon URL.
+func resolveNetwork(
+	pattern *regexp.Regexp,
+	uri string,
+	networks func() map[string]string,
+) (string, string, error) {
+	matches := pattern.FindStringSubmatch(uri)
+	if len(matches) != 2 {
+		return "", "", fmt.Errorf("invalid beacon URI: %s", uri)
+	}
+
+	network := matches[1]
+	endpoints := networks()
+
+	baseURL, ok := endpoints[network]
+	if !ok {
+		names := make([]string, 0, len(endpoints))
+		for name := range endpoints {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return network, baseURL, nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,89 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalityHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/eth/v1/beacon/states/head/finality_checkpoints", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"data":{
+			"previous_justified":{"epoch":"9","root":"0x09"},
+			"current_justified":{"epoch":"10","root":"0x0a"},
+			"finalized":{"epoch":"8","root":"0x08"}
+		}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createFinalityHandler(newClient(), networks)(context.Background(), "beacon://networks/hoodi/finality")
+	require.NoError(t, err)
+
+	var resp FinalityResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "hoodi", resp.Network)
+	assert.Equal(t, Checkpoint{Epoch: "8", Root: "0x08"}, resp.Finalized)
+	assert.Equal(t, "10", resp.CurrentJustified.Epoch)
+}
+
+func TestHeadHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/eth/v1/beacon/headers/head", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"data":{"root":"0xabc","header":{"message":{
+			"slot":"100","proposer_index":"7","parent_root":"0xp","state_root":"0xs"
+		}}}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createHeadHandler(newClient(), networks)(context.Background(), "beacon://networks/hoodi/head")
+	require.NoError(t, err)
+
+	var resp HeadResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, HeadResponse{
+		Network:       "hoodi",
+		Slot:          "100",
+		ProposerIndex: "7",
+		Root:          "0xabc",
+		ParentRoot:    "0xp",
+		StateRoot:     "0xs",
+	}, resp)
+}
+
+func TestResolveNetworkUnknown(t *testing.T) {
+	networks := func() map[string]string {
+		return map[string]string{"mainnet": "http://a", "hoodi": "http://b"}
+	}
+
+	_, _, err := resolveNetwork(headURIPattern, "beacon://networks/sepolia/head", networks)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "sepolia"`)
+	assert.Contains(t, err.Error(), "[hoodi mainnet]")
+}
+
+func TestNetworksListHandlerSorted(t *testing.T) {
+	networks := func() map[string]string {
+		return map[string]string{"mainnet": "http://a", "hoodi": "http://b"}
+	}
+
+	out, err := createNetworksListHandler(networks)(context.Background(), "beacon://networks")
+	require.NoError(t, err)
+
+	var resp NetworksListResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Len(t, resp.Networks, 2)
+	assert.Equal(t, "hoodi", resp.Networks[0].Name)
+	assert.Equal(t, "mainnet", resp.Networks[1].Name)
+}
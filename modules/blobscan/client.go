@@ -0,0 +1,159 @@
+package blobscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// apiPath is where a Blobscan deployment serves its REST API relative
+	// to the explorer URL cartographoor publishes.
+	apiPath = "/api"
+
+	// gasPerBlob is the blob gas consumed by a single blob (EIP-4844).
+	gasPerBlob = 1 << 17
+
+	// requestTimeout bounds a single Blobscan API call.
+	requestTimeout = 30 * time.Second
+
+	// maxResponseBytes bounds a Blobscan API response body.
+	maxResponseBytes = 16 << 20
+)
+
+// Client reads the Blobscan REST API of a network's explorer.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a Blobscan API client.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+
+	return &Client{httpClient: httpClient}
+}
+
+// BlockSummary is the blob usage of a single block.
+type BlockSummary struct {
+	Number        int64  `json:"number"`
+	Hash          string `json:"hash"`
+	Slot          int64  `json:"slot,omitempty"`
+	Timestamp     string `json:"timestamp"`
+	BlobCount     int64  `json:"blob_count"`
+	BlobGasUsed   string `json:"blob_gas_used"`
+	BlobGasPrice  string `json:"blob_gas_price"`
+	ExcessBlobGas string `json:"excess_blob_gas"`
+}
+
+// numeric accepts JSON numbers and numeric strings, since Blobscan encodes
+// big integers as strings.
+type numeric string
+
+func (n *numeric) UnmarshalJSON(data []byte) error {
+	*n = numeric(strings.Trim(string(data), `"`))
+
+	return nil
+}
+
+type blockModel struct {
+	Number        numeric `json:"number"`
+	Hash          string  `json:"hash"`
+	Slot          numeric `json:"slot"`
+	Timestamp     string  `json:"timestamp"`
+	BlobGasUsed   numeric `json:"blobGasUsed"`
+	BlobGasPrice  numeric `json:"blobGasPrice"`
+	ExcessBlobGas numeric `json:"excessBlobGas"`
+}
+
+// Get issues a GET against the Blobscan API and returns the raw body.
+func (c *Client) Get(ctx context.Context, baseURL, path string, params url.Values) ([]byte, error) {
+	requestURL := strings.TrimRight(baseURL, "/") + apiPath + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating Blobscan request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting Blobscan %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading Blobscan response: %w", err)
+	}
+
+	if len(body) > maxResponseBytes {
+		return nil, fmt.Errorf("blobscan %s response exceeds %d bytes", path, maxResponseBytes)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("blobscan %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// RecentBlocks returns blob usage for the latest blocks carrying blobs,
+// newest first.
+func (c *Client) RecentBlocks(ctx context.Context, baseURL string, limit int) ([]BlockSummary, error) {
+	body, err := c.Get(ctx, baseURL, "/blocks", url.Values{
+		"ps":   {strconv.Itoa(limit)},
+		"sort": {"desc"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Blocks []blockModel `json:"blocks"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decoding Blobscan blocks: %w", err)
+	}
+
+	blocks := make([]BlockSummary, 0, len(payload.Blocks))
+	for _, b := range payload.Blocks {
+		blocks = append(blocks, summarizeBlock(b))
+	}
+
+	return blocks, nil
+}
+
+func summarizeBlock(b blockModel) BlockSummary {
+	number, _ := strconv.ParseInt(string(b.Number), 10, 64)
+	slot, _ := strconv.ParseInt(string(b.Slot), 10, 64)
+	gasUsed, _ := strconv.ParseInt(string(b.BlobGasUsed), 10, 64)
+
+	return BlockSummary{
+		Number:        number,
+		Hash:          b.Hash,
+		Slot:          slot,
+		Timestamp:     b.Timestamp,
+		BlobCount:     gasUsed / gasPerBlob,
+		BlobGasUsed:   string(b.BlobGasUsed),
+		BlobGasPrice:  string(b.BlobGasPrice),
+		ExcessBlobGas: string(b.ExcessBlobGas),
+	}
+}
+
+// Link builds a deep link into the Blobscan explorer UI.
+func Link(baseURL, kind, id string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + kind + "/" + url.PathEscape(id)
+}
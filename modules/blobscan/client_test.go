@@ -0,0 +1,72 @@
+package blobscan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentBlocks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/blocks", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("ps"))
+
+		_, _ = w.Write([]byte(`{"blocks":[
+			{"number":21000001,"hash":"0xb1","slot":"10000001","timestamp":"2025-01-01T00:00:12.000Z",
+			 "blobGasUsed":"786432","blobGasPrice":"1","excessBlobGas":"0"},
+			{"number":"21000000","hash":"0xb0","slot":10000000,"timestamp":"2025-01-01T00:00:00.000Z",
+			 "blobGasUsed":"131072","blobGasPrice":"2000000000","excessBlobGas":"393216"}
+		],"totalBlocks":2}`))
+	}))
+	defer srv.Close()
+
+	blocks, err := NewClient(srv.Client()).RecentBlocks(context.Background(), srv.URL+"/", 2)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+
+	assert.Equal(t, BlockSummary{
+		Number:        21000001,
+		Hash:          "0xb1",
+		Slot:          10000001,
+		Timestamp:     "2025-01-01T00:00:12.000Z",
+		BlobCount:     6,
+		BlobGasUsed:   "786432",
+		BlobGasPrice:  "1",
+		ExcessBlobGas: "0",
+	}, blocks[0])
+	assert.Equal(t, int64(21000000), blocks[1].Number)
+	assert.Equal(t, int64(1), blocks[1].BlobCount)
+	assert.Equal(t, "2000000000", blocks[1].BlobGasPrice)
+}
+
+func TestGetReturnsUpstreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "blob not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.Client()).Get(context.Background(), srv.URL, "/blobs/0x01", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "blob not found")
+}
+
+func TestGetRejectsOversizedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(make([]byte, maxResponseBytes+1))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.Client()).Get(context.Background(), srv.URL, "/blobs", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds")
+}
+
+func TestLink(t *testing.T) {
+	assert.Equal(t, "https://blobscan.com/blob/0x01ab", Link("https://blobscan.com/", "blob", "0x01ab"))
+	assert.Equal(t, "https://sepolia.blobscan.com/block/123", Link("https://sepolia.blobscan.com", "block", "123"))
+}
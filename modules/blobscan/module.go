@@ -0,0 +1,179 @@
+package blobscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Module implements the module.Module interface for the Blobscan module.
+type Module struct {
+	cfg                 Config
+	cartographoorClient cartographoor.CartographoorClient
+}
+
+// New creates a new Blobscan module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "blobscan" }
+
+// Enabled reports whether Blobscan operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// Blobscan is enabled by default since it requires no configuration.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		// No config provided, use defaults (enabled = true).
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// No validation needed - config is minimal.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_BLOBSCAN_NETWORKS with network->URL mapping from cartographoor.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	networks := Networks(p.cartographoorClient)
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling blobscan networks: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_BLOBSCAN_NETWORKS": string(networksJSON),
+	}, nil
+}
+
+// DatasourceInfo returns empty since networks are the datasources,
+// and those come from cartographoor.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	for k, v := range queryExamples {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"blobscan": {
+			Description: "Query blob data from Blobscan explorers and generate deep links",
+			Functions: map[string]types.FunctionDoc{
+				"list_networks":     {Signature: "list_networks() -> list[dict]", Description: "List networks with Blobscan explorers"},
+				"get_base_url":      {Signature: "get_base_url(network) -> str", Description: "Get Blobscan base URL for a network"},
+				"get_recent_blocks": {Signature: "get_recent_blocks(network, limit=25) -> list[dict]", Description: "Blob count, blob gas price and excess blob gas of the latest blob-carrying blocks"},
+				"get_block":         {Signature: "get_block(network, number_or_hash) -> dict", Description: "Get a block with its blob transactions"},
+				"get_blob":          {Signature: "get_blob(network, versioned_hash) -> dict", Description: "Get a blob by versioned hash"},
+				"link_block":        {Signature: "link_block(network, number_or_hash) -> str", Description: "Deep link to block"},
+				"link_blob":         {Signature: "link_blob(network, versioned_hash) -> str", Description: "Deep link to blob"},
+				"link_tx":           {Signature: "link_tx(network, tx_hash) -> str", Description: "Deep link to blob transaction"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Blobscan Blob Explorer
+
+Look up blobs, blob counts per block and blob fees without writing SQL.
+
+` + "```python" + `
+from ethpandaops import blobscan
+
+for block in blobscan.get_recent_blocks("mainnet", limit=10):
+    print(block["number"], block["blob_count"], block["blob_gas_price"])
+
+print(blobscan.link_blob("mainnet", "0x01..."))
+` + "```" + `
+`
+}
+
+// RegisterResources registers the blobscan:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(log.WithField("module", "blobscan"), reg, NewClient(nil), func() map[string]string {
+		return Networks(p.cartographoorClient)
+	})
+
+	return nil
+}
+
+// SetCartographoorClient implements module.CartographoorAware.
+// This is called by the builder to inject the cartographoor client.
+func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
+	p.cartographoorClient = client
+}
+
+// Networks returns the network -> Blobscan URL mapping from cartographoor.
+func Networks(client cartographoor.CartographoorClient) map[string]string {
+	if client == nil {
+		return nil
+	}
+
+	active := client.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.Blobscan != "" {
+			networks[name] = network.ServiceURLs.Blobscan
+		}
+	}
+
+	return networks
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
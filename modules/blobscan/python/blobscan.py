@@ -0,0 +1,79 @@
+"""Thin Blobscan wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_blobscan_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_BLOBSCAN_NETWORKS", "").strip():
+        raise ValueError("Blobscan is not enabled or no Blobscan explorers are available.")
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_blobscan_available()
+    data = _runtime.invoke_data("blobscan.list_networks")
+    return data.get("networks", [])
+
+
+def get_base_url(network: str) -> str:
+    _require_blobscan_available()
+    data = _runtime.invoke_data("blobscan.get_base_url", {"network": network})
+    return data.get("base_url", "")
+
+
+def get_recent_blocks(network: str, limit: int = 25) -> list[dict[str, Any]]:
+    _require_blobscan_available()
+    data = _runtime.invoke_data(
+        "blobscan.get_recent_blocks",
+        {"network": network, "limit": limit},
+    )
+    return data.get("blocks", [])
+
+
+def get_block(network: str, number_or_hash: str | int) -> dict[str, Any]:
+    _require_blobscan_available()
+    payload = _runtime.invoke_json(
+        "blobscan.get_block",
+        {"network": network, "number_or_hash": str(number_or_hash)},
+    )
+    return payload if isinstance(payload, dict) else {}
+
+
+def get_blob(network: str, versioned_hash: str) -> dict[str, Any]:
+    _require_blobscan_available()
+    payload = _runtime.invoke_json(
+        "blobscan.get_blob",
+        {"network": network, "versioned_hash": versioned_hash},
+    )
+    return payload if isinstance(payload, dict) else {}
+
+
+def link_block(network: str, number_or_hash: str | int) -> str:
+    _require_blobscan_available()
+    data = _runtime.invoke_data(
+        "blobscan.link_block",
+        {"network": network, "number_or_hash": str(number_or_hash)},
+    )
+    return data.get("url", "")
+
+
+def link_blob(network: str, versioned_hash: str) -> str:
+    _require_blobscan_available()
+    data = _runtime.invoke_data(
+        "blobscan.link_blob",
+        {"network": network, "versioned_hash": versioned_hash},
+    )
+    return data.get("url", "")
+
+
+def link_tx(network: str, tx_hash: str) -> str:
+    _require_blobscan_available()
+    data = _runtime.invoke_data(
+        "blobscan.link_tx",
+        {"network": network, "tx_hash": tx_hash},
+    )
+    return data.get("url", "")
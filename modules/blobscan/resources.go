@@ -0,0 +1,199 @@
+package blobscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// recentBlocksLimit is how many blocks the blocks resource summarizes.
+const recentBlocksLimit = 25
+
+var (
+	// blocksURIPattern matches blobscan://networks/{network}/blocks URIs.
+	blocksURIPattern = regexp.MustCompile(`^blobscan://networks/([^/]+)/blocks$`)
+
+	// blobURIPattern matches blobscan://networks/{network}/blobs/{versioned_hash} URIs.
+	blobURIPattern = regexp.MustCompile(`^blobscan://networks/([^/]+)/blobs/(0x[0-9a-fA-F]{64})$`)
+)
+
+// NetworkInstance is a network with a Blobscan explorer.
+type NetworkInstance struct {
+	Name        string `json:"name"`
+	BlobscanURL string `json:"blobscan_url"`
+}
+
+// NetworksListResponse is the response for blobscan://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkInstance `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// BlocksResponse is the response for blobscan://networks/{network}/blocks.
+type BlocksResponse struct {
+	Network string         `json:"network"`
+	Blocks  []BlockSummary `json:"blocks"`
+}
+
+// BlobResponse is the response for blobscan://networks/{network}/blobs/{versioned_hash}.
+type BlobResponse struct {
+	Network string          `json:"network"`
+	Link    string          `json:"link"`
+	Blob    json.RawMessage `json:"blob"`
+}
+
+// RegisterNetworkResources registers the blobscan:// resources. networks
+// returns the current network -> Blobscan URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	client *Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"blobscan://networks",
+			"Blobscan Explorers",
+			mcp.WithResourceDescription("Networks with a Blobscan blob explorer"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"blobscan://networks/{network}/blocks",
+			"Recent Blob Usage",
+			mcp.WithTemplateDescription("Blob count and blob fee of the latest blocks carrying blobs"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: blocksURIPattern,
+		Handler: createBlocksHandler(client, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"blobscan://networks/{network}/blobs/{versioned_hash}",
+			"Blob",
+			mcp.WithTemplateDescription("A blob by versioned hash, with the transaction and block that carried it"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Pattern: blobURIPattern,
+		Handler: createBlobHandler(client, networks),
+	})
+
+	log.Debug("Registered Blobscan resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		instances := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with a Blobscan blob explorer.",
+			Networks:    make([]NetworkInstance, 0, len(instances)),
+			Usage:       "Read blobscan://networks/{network}/blocks for recent blob usage or blobscan://networks/{network}/blobs/{versioned_hash} for a blob.",
+		}
+
+		for name, blobscanURL := range instances {
+			response.Networks = append(response.Networks, NetworkInstance{Name: name, BlobscanURL: blobscanURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createBlocksHandler(client *Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := blocksURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid Blobscan URI: %s", uri)
+		}
+
+		baseURL, err := lookupNetwork(networks(), matches[1])
+		if err != nil {
+			return "", err
+		}
+
+		blocks, err := client.RecentBlocks(ctx, baseURL, recentBlocksLimit)
+		if err != nil {
+			return "", err
+		}
+
+		return marshal(&BlocksResponse{Network: matches[1], Blocks: blocks})
+	}
+}
+
+func createBlobHandler(client *Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := blobURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 3 {
+			return "", fmt.Errorf("invalid Blobscan URI: %s", uri)
+		}
+
+		network, versionedHash := matches[1], matches[2]
+
+		baseURL, err := lookupNetwork(networks(), network)
+		if err != nil {
+			return "", err
+		}
+
+		body, err := client.Get(ctx, baseURL, "/blobs/"+versionedHash, nil)
+		if err != nil {
+			return "", err
+		}
+
+		if !json.Valid(body) {
+			return "", fmt.Errorf("blobscan blob response is not valid JSON")
+		}
+
+		return marshal(&BlobResponse{
+			Network: network,
+			Link:    Link(baseURL, "blob", versionedHash),
+			Blob:    body,
+		})
+	}
+}
+
+// lookupNetwork returns the Blobscan URL of a network.
+func lookupNetwork(instances map[string]string, network string) (string, error) {
+	baseURL, ok := instances[network]
+	if !ok {
+		names := make([]string, 0, len(instances))
+		for name := range instances {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return baseURL, nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,384 @@
+package cbt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// TransformationsPath lists the transformation models.
+	TransformationsPath = "/api/v1/models/transformations"
+
+	// CoveragePath lists the processed ranges of every transformation.
+	CoveragePath = "/api/v1/models/transformations/coverage"
+
+	// ExternalBoundsPath lists the data bounds of every external model.
+	ExternalBoundsPath = "/api/v1/models/external/bounds"
+
+	// maxReportedGaps caps the gaps a gap report lists, largest first.
+	maxReportedGaps = 100
+)
+
+// ModelLink returns the CBT UI link of a model. IDs are "database.table".
+func ModelLink(baseURL, id string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	if database, table, ok := strings.Cut(id, "."); ok {
+		return fmt.Sprintf("%s/models/%s/%s", baseURL, database, table)
+	}
+
+	return fmt.Sprintf("%s/models/%s", baseURL, id)
+}
+
+// Gap is an unprocessed range between two processed ranges of a
+// transformation. Positions are in the model's interval units, e.g. slots.
+type Gap struct {
+	Model string `json:"model"`
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	Size  uint64 `json:"size"`
+	Link  string `json:"link"`
+}
+
+// GapReport lists the gaps of every transformation, largest first.
+type GapReport struct {
+	Network string `json:"network,omitempty"`
+	MinSize uint64 `json:"min_size"`
+	// ModelsChecked is the number of transformations with coverage.
+	ModelsChecked int `json:"models_checked"`
+	// ModelsWithGaps is the number of transformations with at least one gap.
+	ModelsWithGaps int   `json:"models_with_gaps"`
+	Gaps           []Gap `json:"gaps"`
+	// Omitted is the number of gaps left out of Gaps.
+	Omitted int `json:"omitted,omitempty"`
+}
+
+// ModelLag is how far a transformation trails the data it depends on.
+type ModelLag struct {
+	Model       string `json:"model"`
+	Link        string `json:"link"`
+	CoveredFrom uint64 `json:"covered_from"`
+	CoveredTo   uint64 `json:"covered_to"`
+	// DependencyHead is the furthest position every dependency has reached.
+	// It and Lag are zero when no dependency's head is known.
+	DependencyHead     uint64 `json:"dependency_head,omitempty"`
+	Lag                uint64 `json:"lag"`
+	LimitingDependency string `json:"limiting_dependency,omitempty"`
+}
+
+// LagReport is the processing lag of every incremental transformation,
+// largest first.
+type LagReport struct {
+	Network string     `json:"network,omitempty"`
+	Models  []ModelLag `json:"models"`
+	// Uncovered lists transformations that have processed nothing yet.
+	Uncovered []string `json:"uncovered,omitempty"`
+	Note      string   `json:"note"`
+}
+
+type coverageRange struct {
+	Position uint64 `json:"position"`
+	Interval uint64 `json:"interval"`
+}
+
+type modelCoverage struct {
+	ID     string          `json:"id"`
+	Ranges []coverageRange `json:"ranges"`
+}
+
+type externalBounds struct {
+	ID  string `json:"id"`
+	Min uint64 `json:"min"`
+	Max uint64 `json:"max"`
+}
+
+type transformation struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Dependencies holds model IDs, or lists of IDs of which any one
+	// satisfies the dependency.
+	Dependencies []any `json:"dependencies"`
+}
+
+// CheckGaps returns the gaps of at least minSize in every transformation's
+// processed ranges.
+func CheckGaps(ctx context.Context, httpClient *http.Client, baseURL string, minSize uint64) (*GapReport, error) {
+	var coverage []modelCoverage
+	if err := getList(ctx, httpClient, baseURL, CoveragePath, "coverage", &coverage); err != nil {
+		return nil, err
+	}
+
+	return findGaps(baseURL, coverage, minSize), nil
+}
+
+// Lag returns how far each incremental transformation trails the furthest
+// position all of its dependencies have reached.
+func Lag(ctx context.Context, httpClient *http.Client, baseURL string) (*LagReport, error) {
+	var (
+		models   []transformation
+		coverage []modelCoverage
+		bounds   []externalBounds
+	)
+
+	if err := getList(ctx, httpClient, baseURL, TransformationsPath, "models", &models); err != nil {
+		return nil, err
+	}
+
+	if err := getList(ctx, httpClient, baseURL, CoveragePath, "coverage", &coverage); err != nil {
+		return nil, err
+	}
+
+	if err := getList(ctx, httpClient, baseURL, ExternalBoundsPath, "bounds", &bounds); err != nil {
+		return nil, err
+	}
+
+	return computeLag(baseURL, models, coverage, bounds), nil
+}
+
+func findGaps(baseURL string, coverage []modelCoverage, minSize uint64) *GapReport {
+	report := &GapReport{MinSize: minSize, Gaps: make([]Gap, 0)}
+
+	for _, model := range coverage {
+		if len(model.Ranges) == 0 {
+			continue
+		}
+
+		report.ModelsChecked++
+
+		found := false
+
+		for _, gap := range rangeGaps(model.Ranges) {
+			if gap[1]-gap[0] < minSize {
+				continue
+			}
+
+			found = true
+
+			report.Gaps = append(report.Gaps, Gap{
+				Model: model.ID,
+				Start: gap[0],
+				End:   gap[1],
+				Size:  gap[1] - gap[0],
+				Link:  ModelLink(baseURL, model.ID),
+			})
+		}
+
+		if found {
+			report.ModelsWithGaps++
+		}
+	}
+
+	sort.Slice(report.Gaps, func(i, j int) bool {
+		a, b := report.Gaps[i], report.Gaps[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+
+		return a.Start < b.Start
+	})
+
+	if len(report.Gaps) > maxReportedGaps {
+		report.Omitted = len(report.Gaps) - maxReportedGaps
+		report.Gaps = report.Gaps[:maxReportedGaps]
+	}
+
+	return report
+}
+
+// rangeGaps merges ranges and returns the [start, end) holes between them.
+func rangeGaps(ranges []coverageRange) [][2]uint64 {
+	sorted := append([]coverageRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+
+	var gaps [][2]uint64
+
+	end := sorted[0].Position + sorted[0].Interval
+
+	for _, r := range sorted[1:] {
+		if r.Position > end {
+			gaps = append(gaps, [2]uint64{end, r.Position})
+		}
+
+		end = max(end, r.Position+r.Interval)
+	}
+
+	return gaps
+}
+
+// coveredBounds returns the lowest and highest processed positions.
+func coveredBounds(ranges []coverageRange) (uint64, uint64) {
+	from, to := ranges[0].Position, ranges[0].Position+ranges[0].Interval
+
+	for _, r := range ranges[1:] {
+		from = min(from, r.Position)
+		to = max(to, r.Position+r.Interval)
+	}
+
+	return from, to
+}
+
+func computeLag(
+	baseURL string,
+	models []transformation,
+	coverage []modelCoverage,
+	bounds []externalBounds,
+) *LagReport {
+	heads := make(map[string]uint64, len(coverage)+len(bounds))
+
+	for _, b := range bounds {
+		heads[b.ID] = b.Max
+	}
+
+	covered := make(map[string][2]uint64, len(coverage))
+
+	for _, c := range coverage {
+		if len(c.Ranges) == 0 {
+			continue
+		}
+
+		from, to := coveredBounds(c.Ranges)
+		covered[c.ID] = [2]uint64{from, to}
+		heads[c.ID] = to
+	}
+
+	report := &LagReport{
+		Models: make([]ModelLag, 0, len(models)),
+		Note:   "Positions and lag are in each model's interval units, e.g. slots or block numbers. Scheduled transformations have no position coverage and are not listed.",
+	}
+
+	for _, model := range models {
+		if model.Type != "" && model.Type != "incremental" {
+			continue
+		}
+
+		span, ok := covered[model.ID]
+		if !ok {
+			report.Uncovered = append(report.Uncovered, model.ID)
+			continue
+		}
+
+		lag := ModelLag{
+			Model:       model.ID,
+			Link:        ModelLink(baseURL, model.ID),
+			CoveredFrom: span[0],
+			CoveredTo:   span[1],
+		}
+
+		if head, limiting, ok := dependencyHead(model.Dependencies, heads); ok {
+			lag.DependencyHead = head
+			lag.LimitingDependency = limiting
+
+			if head > span[1] {
+				lag.Lag = head - span[1]
+			}
+		}
+
+		report.Models = append(report.Models, lag)
+	}
+
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].Lag != report.Models[j].Lag {
+			return report.Models[i].Lag > report.Models[j].Lag
+		}
+
+		return report.Models[i].Model < report.Models[j].Model
+	})
+
+	sort.Strings(report.Uncovered)
+
+	return report
+}
+
+// dependencyHead returns the lowest head across dependencies and the
+// dependency it belongs to. A list of alternatives reaches the highest head
+// of its members. Dependencies with unknown heads are ignored.
+func dependencyHead(dependencies []any, heads map[string]uint64) (uint64, string, bool) {
+	var (
+		lowest   uint64
+		limiting string
+		found    bool
+	)
+
+	for _, dep := range dependencies {
+		var (
+			head uint64
+			id   string
+			ok   bool
+		)
+
+		switch d := dep.(type) {
+		case string:
+			head, ok = heads[d]
+			id = d
+		case []any:
+			for _, alt := range d {
+				name, _ := alt.(string)
+				if h, known := heads[name]; known && (!ok || h > head) {
+					head, id, ok = h, name, true
+				}
+			}
+		}
+
+		if ok && (!found || head < lowest) {
+			lowest, limiting, found = head, id, true
+		}
+	}
+
+	return lowest, limiting, found
+}
+
+// getList reads a CBT list endpoint into out. Lists are returned either bare
+// or under key in an object.
+func getList(ctx context.Context, httpClient *http.Client, baseURL, path, key string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating CBT request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting CBT %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading CBT %s: %w", path, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CBT %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '{' {
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(body, &wrapped); err != nil {
+			return fmt.Errorf("decoding CBT %s: %w", path, err)
+		}
+
+		body = wrapped[key]
+		if len(body) == 0 {
+			return nil
+		}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding CBT %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cbt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindGaps(t *testing.T) {
+	coverage := []modelCoverage{
+		{ID: "mainnet.fct_block", Ranges: []coverageRange{
+			{Position: 200, Interval: 50},
+			{Position: 0, Interval: 100},
+			{Position: 90, Interval: 20},
+			{Position: 400, Interval: 10},
+		}},
+		{ID: "mainnet.fct_attestation", Ranges: []coverageRange{
+			{Position: 0, Interval: 10},
+			{Position: 15, Interval: 10},
+		}},
+		{ID: "mainnet.empty"},
+	}
+
+	report := findGaps("https://cbt.mainnet.ethpandaops.io/", coverage, 10)
+
+	assert.Equal(t, 2, report.ModelsChecked)
+	assert.Equal(t, 1, report.ModelsWithGaps)
+	assert.Equal(t, []Gap{
+		{Model: "mainnet.fct_block", Start: 250, End: 400, Size: 150, Link: "https://cbt.mainnet.ethpandaops.io/models/mainnet/fct_block"},
+		{Model: "mainnet.fct_block", Start: 110, End: 200, Size: 90, Link: "https://cbt.mainnet.ethpandaops.io/models/mainnet/fct_block"},
+	}, report.Gaps)
+
+	assert.Len(t, findGaps("", coverage, 1).Gaps, 3)
+}
+
+func TestLagHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(TransformationsPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"mainnet.fct_block","type":"incremental","dependencies":["default.blocks"]},
+			{"id":"mainnet.fct_head","type":"incremental","dependencies":["mainnet.fct_block",["default.a","default.b"]]},
+			{"id":"mainnet.fct_new","type":"incremental","dependencies":["default.blocks"]},
+			{"id":"mainnet.daily","type":"scheduled"}
+		]`))
+	})
+	mux.HandleFunc(CoveragePath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"coverage":[
+			{"id":"mainnet.fct_block","ranges":[{"position":0,"interval":900}]},
+			{"id":"mainnet.fct_head","ranges":[{"position":0,"interval":880}]}
+		]}`))
+	})
+	mux.HandleFunc(ExternalBoundsPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"default.blocks","min":0,"max":1000},
+			{"id":"default.a","min":0,"max":850},
+			{"id":"default.b","min":0,"max":950}
+		]`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"mainnet": srv.URL} }
+
+	out, err := createLagHandler(srv.Client(), networks)(context.Background(), "cbt://mainnet/lag")
+	require.NoError(t, err)
+
+	var report LagReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, "mainnet", report.Network)
+	assert.Equal(t, []string{"mainnet.fct_new"}, report.Uncovered)
+	require.Len(t, report.Models, 2)
+
+	assert.Equal(t, "mainnet.fct_block", report.Models[0].Model)
+	assert.Equal(t, uint64(100), report.Models[0].Lag)
+	assert.Equal(t, "default.blocks", report.Models[0].LimitingDependency)
+
+	assert.Equal(t, "mainnet.fct_head", report.Models[1].Model)
+	assert.Equal(t, uint64(900), report.Models[1].DependencyHead)
+	assert.Equal(t, uint64(20), report.Models[1].Lag)
+	assert.Equal(t, "mainnet.fct_block", report.Models[1].LimitingDependency)
+
+	_, err = createLagHandler(srv.Client(), networks)(context.Background(), "cbt://hoodi/lag")
+	require.Error(t, err)
+}
@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net/http"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
+var _ module.ResourceProvider = (*Module)(nil)
+
 // Module implements the module.Module interface for the CBT module.
 type Module struct {
 	cfg                 Config
@@ -32,6 +37,9 @@ func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
 // CBT is enabled by default since it requires no configuration.
 func (p *Module) DefaultEnabled() bool { return true }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 func (p *Module) Init(rawConfig []byte) error {
 	if len(rawConfig) == 0 {
 		// No config provided, use defaults (enabled = true).
@@ -58,19 +66,8 @@ func (p *Module) SandboxEnv() (map[string]string, error) {
 		return nil, nil
 	}
 
-	if p.cartographoorClient == nil {
-		// Cartographoor client not yet set - return empty.
-		// This will be populated after SetCartographoorClient is called.
-		return nil, nil
-	}
-
-	// Build network -> CBT URL mapping from cartographoor data.
-	networks := p.cartographoorClient.GetActiveNetworks()
-	cbtNetworks := make(map[string]string, len(networks))
-
-	for name := range networks {
-		cbtNetworks[name] = fmt.Sprintf("https://cbt.%s.ethpandaops.io", name)
-	}
+	// Empty until SetCartographoorClient is called.
+	cbtNetworks := p.networks()
 
 	if len(cbtNetworks) == 0 {
 		return nil, nil
@@ -123,6 +120,7 @@ func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 				"get_scheduled_runs":          {Signature: "get_scheduled_runs(network, id=None) -> list|dict", Description: "Get scheduled transformation runs"},
 				"get_interval_types":          {Signature: "get_interval_types(network) -> dict", Description: "Get interval type configurations"},
 				"link_model":                  {Signature: "link_model(network, id) -> str", Description: "Deep link to model in CBT UI"},
+				"check_gaps":                  {Signature: "check_gaps(network, min_size=1) -> dict", Description: "Find unprocessed gaps of at least min_size positions in every transformation, largest first, with CBT UI links"},
 			},
 		},
 	}
@@ -153,6 +151,10 @@ coverage = cbt.get_transformation_coverage("mainnet")
 for c in coverage:
     print(f"  {c.get('id')}: {c}")
 
+# Find unprocessed gaps, largest first
+for gap in cbt.check_gaps("mainnet", min_size=100)["gaps"][:5]:
+    print(f"  {gap['model']}: {gap['start']}-{gap['end']} {gap['link']}")
+
 # Generate a deep link to a model
 link = cbt.link_model("mainnet", "default.beacon_api_eth_v1_events_block")
 print(f"View in CBT: {link}")
@@ -160,6 +162,44 @@ print(f"View in CBT: {link}")
 `
 }
 
+// RegisterResources registers the cbt:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(
+		log.WithField("module", "cbt"),
+		reg,
+		&http.Client{Timeout: requestTimeout},
+		p.networks,
+	)
+
+	return nil
+}
+
+// networks returns the network -> CBT URL mapping for cartographoor's active
+// networks, using the convention https://cbt.{network}.ethpandaops.io.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name := range active {
+		networks[name] = NetworkURL(name)
+	}
+
+	return networks
+}
+
+// NetworkURL returns the CBT URL of a network.
+func NetworkURL(network string) string {
+	return fmt.Sprintf("https://cbt.%s.ethpandaops.io", network)
+}
+
 // SetCartographoorClient implements module.CartographoorAware.
 // This is called by the builder to inject the cartographoor client.
 func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
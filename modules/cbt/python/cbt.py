@@ -125,3 +125,11 @@ def link_model(network: str, id: str) -> str:
         {"network": network, "id": id},
     )
     return data.get("url", "")
+
+
+def check_gaps(network: str, min_size: int = 1) -> dict[str, Any]:
+    _require_cbt_available()
+    return _runtime.invoke_data(
+        "cbt.check_gaps",
+        {"network": network, "min_size": min_size},
+    )
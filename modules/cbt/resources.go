@@ -0,0 +1,84 @@
+package cbt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// requestTimeout bounds the CBT calls made for a resource read.
+const requestTimeout = 30 * time.Second
+
+// lagURIPattern matches cbt://{instance}/lag URIs.
+var lagURIPattern = regexp.MustCompile(`^cbt://([^/]+)/lag$`)
+
+// RegisterNetworkResources registers the cbt:// resources. networks returns
+// the current network -> CBT URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"cbt://{instance}/lag",
+			"CBT Processing Lag",
+			mcp.WithTemplateDescription("How far each incremental CBT transformation of a network trails its dependencies, largest lag first, with CBT UI links"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: lagURIPattern,
+		Handler: createLagHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered CBT resources")
+}
+
+func createLagHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := lagURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid CBT URI: %s", uri)
+		}
+
+		network := matches[1]
+		instances := networks()
+
+		baseURL, ok := instances[network]
+		if !ok {
+			names := make([]string, 0, len(instances))
+			for name := range instances {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return "", fmt.Errorf("unknown CBT instance %q. Available: %v", network, names)
+		}
+
+		report, err := Lag(ctx, httpClient, baseURL)
+		if err != nil {
+			return "", err
+		}
+
+		report.Network = network
+
+		data, err := canonicaljson.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling CBT lag: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
@@ -0,0 +1,19 @@
+package checkpointz
+
+// Config holds the checkpointz module configuration.
+// The module is enabled by default since checkpointz instances
+// are public and require no credentials.
+type Config struct {
+	// Enabled controls whether the checkpointz module is active.
+	// Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+// IsEnabled returns true if the module is enabled (default: true).
+func (c *Config) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
@@ -0,0 +1,180 @@
+package checkpointz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Module implements the module.Module interface for the checkpointz module.
+type Module struct {
+	cfg                 Config
+	cartographoorClient cartographoor.CartographoorClient
+}
+
+// New creates a new checkpointz module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "checkpointz" }
+
+// Enabled reports whether checkpointz operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// Checkpointz is enabled by default since it requires no configuration.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		// No config provided, use defaults (enabled = true).
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// No validation needed - config is minimal.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_CHECKPOINTZ_NETWORKS with network->URL mapping from cartographoor.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	networks := p.networks()
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling checkpointz networks: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_CHECKPOINTZ_NETWORKS": string(networksJSON),
+	}, nil
+}
+
+// DatasourceInfo returns empty since networks are the datasources,
+// and those come from cartographoor.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	for k, v := range queryExamples {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"checkpointz": {
+			Description: "Query checkpoint sync providers for finalized checkpoints, served slots and upstream beacon node health",
+			Functions: map[string]types.FunctionDoc{
+				"list_networks": {Signature: "list_networks() -> list[dict]", Description: "List networks with a checkpointz instance"},
+				"get_base_url":  {Signature: "get_base_url(network) -> str", Description: "Get the checkpoint sync URL for a network, usable as a beacon node's --checkpoint-sync-url"},
+				"get_status":    {Signature: "get_status(network) -> dict", Description: "Get finality and per-upstream status from checkpointz"},
+				"get_finality":  {Signature: "get_finality(network) -> dict", Description: "Get the finalized and justified checkpoints checkpointz serves"},
+				"get_upstreams": {Signature: "get_upstreams(network) -> list[dict]", Description: "Get the upstream beacon nodes checkpointz follows and whether each is healthy"},
+				"get_slots":     {Signature: "get_slots(network) -> list[dict]", Description: "List finalized slots served, with block and state roots"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Checkpointz Checkpoint Sync
+
+Checkpointz serves finalized states for checkpoint sync and tracks the
+health of the beacon nodes it follows.
+
+` + "```python" + `
+from ethpandaops import checkpointz
+
+print(checkpointz.get_finality("hoodi")["finalized"])
+unhealthy = [u for u in checkpointz.get_upstreams("hoodi") if not u["healthy"]]
+` + "```" + `
+`
+}
+
+// RegisterResources registers the checkpointz:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(
+		log.WithField("module", "checkpointz"),
+		reg,
+		&http.Client{Timeout: requestTimeout},
+		p.networks,
+	)
+
+	return nil
+}
+
+// SetCartographoorClient implements module.CartographoorAware.
+// This is called by the builder to inject the cartographoor client.
+func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
+	p.cartographoorClient = client
+}
+
+// networks returns the network -> checkpointz URL mapping from cartographoor.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.CheckpointSync != "" {
+			networks[name] = network.ServiceURLs.CheckpointSync
+		}
+	}
+
+	return networks
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
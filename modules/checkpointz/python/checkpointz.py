@@ -0,0 +1,49 @@
+"""Thin checkpointz wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_checkpointz_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_CHECKPOINTZ_NETWORKS", "").strip():
+        raise ValueError("Checkpointz is not enabled or no checkpoint sync providers are available.")
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_checkpointz_available()
+    data = _runtime.invoke_data("checkpointz.list_networks")
+    return data.get("networks", [])
+
+
+def get_base_url(network: str) -> str:
+    _require_checkpointz_available()
+    data = _runtime.invoke_data("checkpointz.get_base_url", {"network": network})
+    return data.get("base_url", "")
+
+
+def get_status(network: str) -> dict[str, Any]:
+    _require_checkpointz_available()
+    payload = _runtime.invoke_json("checkpointz.get_status", {"network": network})
+    return payload.get("data", {})
+
+
+def get_finality(network: str) -> dict[str, Any]:
+    return get_status(network).get("finality") or {}
+
+
+def get_upstreams(network: str) -> list[dict[str, Any]]:
+    upstreams = get_status(network).get("upstreams") or {}
+    return [
+        {**upstream, "name": upstream.get("name") or key}
+        for key, upstream in sorted(upstreams.items())
+    ]
+
+
+def get_slots(network: str) -> list[dict[str, Any]]:
+    _require_checkpointz_available()
+    payload = _runtime.invoke_json("checkpointz.get_slots", {"network": network})
+    return (payload.get("data") or {}).get("slots", [])
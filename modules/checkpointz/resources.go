@@ -0,0 +1,294 @@
+package checkpointz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// StatusPath serves the finality checkpointz is serving and the health
+	// of each upstream beacon node it follows.
+	StatusPath = "/checkpointz/v1/status"
+
+	// SlotsPath lists the finalized slots checkpointz serves, with their
+	// block and state roots.
+	SlotsPath = "/checkpointz/v1/beacon/slots"
+
+	// requestTimeout bounds a single checkpointz call made for a resource read.
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	// statusURIPattern matches checkpointz://networks/{network}/status URIs.
+	statusURIPattern = regexp.MustCompile(`^checkpointz://networks/([^/]+)/status$`)
+
+	// slotsURIPattern matches checkpointz://networks/{network}/slots URIs.
+	slotsURIPattern = regexp.MustCompile(`^checkpointz://networks/([^/]+)/slots$`)
+)
+
+// NetworkInstance is a network with a checkpoint sync provider.
+type NetworkInstance struct {
+	Name          string `json:"name"`
+	CheckpointURL string `json:"checkpointz_url"`
+}
+
+// NetworksListResponse is the response for checkpointz://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkInstance `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// UpstreamHealth is the health of one beacon node checkpointz follows.
+type UpstreamHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+}
+
+// StatusResponse is the response for checkpointz://networks/{network}/status.
+type StatusResponse struct {
+	Network          string           `json:"network"`
+	CheckpointURL    string           `json:"checkpointz_url"`
+	Finality         json.RawMessage  `json:"finality,omitempty"`
+	Upstreams        []UpstreamHealth `json:"upstreams"`
+	HealthyUpstreams int              `json:"healthy_upstreams"`
+}
+
+// SlotsResponse is the response for checkpointz://networks/{network}/slots.
+type SlotsResponse struct {
+	Network       string          `json:"network"`
+	CheckpointURL string          `json:"checkpointz_url"`
+	Slots         json.RawMessage `json:"slots"`
+}
+
+// RegisterNetworkResources registers the checkpointz:// resources. networks
+// returns the current network -> checkpointz URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"checkpointz://networks",
+			"Checkpoint Sync Providers",
+			mcp.WithResourceDescription("Networks with a checkpointz checkpoint sync provider"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"checkpointz://networks/{network}/status",
+			"Checkpointz Status",
+			mcp.WithTemplateDescription("Finality served by a network's checkpoint sync provider and the health of its upstream beacon nodes"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: statusURIPattern,
+		Handler: createStatusHandler(httpClient, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"checkpointz://networks/{network}/slots",
+			"Checkpointz Finalized Slots",
+			mcp.WithTemplateDescription("Finalized slots served by a network's checkpoint sync provider, with block and state roots"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Pattern: slotsURIPattern,
+		Handler: createSlotsHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered checkpointz resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		instances := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with a checkpointz checkpoint sync provider.",
+			Networks:    make([]NetworkInstance, 0, len(instances)),
+			Usage:       "Read checkpointz://networks/{network}/status for finality and upstream health.",
+		}
+
+		for name, baseURL := range instances {
+			response.Networks = append(response.Networks, NetworkInstance{Name: name, CheckpointURL: baseURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createStatusHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, err := resolveNetwork(statusURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := fetchData(ctx, httpClient, baseURL, StatusPath)
+		if err != nil {
+			return "", err
+		}
+
+		var status struct {
+			Finality  json.RawMessage           `json:"finality"`
+			Upstreams map[string]UpstreamHealth `json:"upstreams"`
+		}
+
+		if err := json.Unmarshal(data, &status); err != nil {
+			return "", fmt.Errorf("decoding checkpointz status: %w", err)
+		}
+
+		response := &StatusResponse{
+			Network:       network,
+			CheckpointURL: baseURL,
+			Finality:      status.Finality,
+			Upstreams:     make([]UpstreamHealth, 0, len(status.Upstreams)),
+		}
+
+		for key, upstream := range status.Upstreams {
+			if upstream.Name == "" {
+				upstream.Name = key
+			}
+
+			if upstream.Healthy {
+				response.HealthyUpstreams++
+			}
+
+			response.Upstreams = append(response.Upstreams, upstream)
+		}
+
+		sort.Slice(response.Upstreams, func(i, j int) bool {
+			return response.Upstreams[i].Name < response.Upstreams[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createSlotsHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, err := resolveNetwork(slotsURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := fetchData(ctx, httpClient, baseURL, SlotsPath)
+		if err != nil {
+			return "", err
+		}
+
+		var slots struct {
+			Slots json.RawMessage `json:"slots"`
+		}
+
+		if err := json.Unmarshal(data, &slots); err != nil {
+			return "", fmt.Errorf("decoding checkpointz slots: %w", err)
+		}
+
+		return marshal(&SlotsResponse{
+			Network:       network,
+			CheckpointURL: baseURL,
+			Slots:         slots.Slots,
+		})
+	}
+}
+
+// resolveNetwork extracts the network from uri and looks up its checkpointz URL.
+func resolveNetwork(pattern *regexp.Regexp, uri string, networks func() map[string]string) (string, string, error) {
+	matches := pattern.FindStringSubmatch(uri)
+	if len(matches) != 2 {
+		return "", "", fmt.Errorf("invalid checkpointz URI: %s", uri)
+	}
+
+	network := matches[1]
+	instances := networks()
+
+	baseURL, ok := instances[network]
+	if !ok {
+		names := make([]string, 0, len(instances))
+		for name := range instances {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return network, baseURL, nil
+}
+
+// fetchData reads a checkpointz endpoint and returns the contents of its
+// "data" envelope.
+func fetchData(ctx context.Context, httpClient *http.Client, baseURL, path string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating checkpointz request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting checkpointz %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpointz %s: %w", path, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("checkpointz %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding checkpointz %s: %w", path, err)
+	}
+
+	if len(envelope.Data) == 0 {
+		return nil, fmt.Errorf("checkpointz %s returned no data", path)
+	}
+
+	return envelope.Data, nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling checkpointz response: %w", err)
+	}
+
+	return string(data), nil
+}
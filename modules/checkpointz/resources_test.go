@@ -0,0 +1,81 @@
+package checkpointz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, StatusPath, r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"data":{
+			"finality":{"finalized":{"epoch":"100","root":"0xaa"}},
+			"upstreams":{
+				"lighthouse-1":{"name":"lighthouse-1","healthy":true},
+				"teku-1":{"healthy":false}
+			}
+		}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createStatusHandler(srv.Client(), networks)(context.Background(), "checkpointz://networks/hoodi/status")
+	require.NoError(t, err)
+
+	var resp StatusResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "hoodi", resp.Network)
+	assert.JSONEq(t, `{"finalized":{"epoch":"100","root":"0xaa"}}`, string(resp.Finality))
+	assert.Equal(t, 1, resp.HealthyUpstreams)
+	assert.Equal(t, []UpstreamHealth{
+		{Name: "lighthouse-1", Healthy: true},
+		{Name: "teku-1", Healthy: false},
+	}, resp.Upstreams)
+}
+
+func TestSlotsHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, SlotsPath, r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"data":{"slots":[{"slot":"3200","block_root":"0xbb"}]}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createSlotsHandler(srv.Client(), networks)(context.Background(), "checkpointz://networks/hoodi/slots")
+	require.NoError(t, err)
+
+	var resp SlotsResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.JSONEq(t, `[{"slot":"3200","block_root":"0xbb"}]`, string(resp.Slots))
+}
+
+func TestStatusHandlerUpstreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	_, err := createStatusHandler(srv.Client(), networks)(context.Background(), "checkpointz://networks/hoodi/status")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned 503")
+}
+
+func TestStatusHandlerUnknownNetwork(t *testing.T) {
+	networks := func() map[string]string { return map[string]string{"hoodi": "http://unused"} }
+
+	_, err := createStatusHandler(http.DefaultClient, networks)(context.Background(), "checkpointz://networks/mainnet/status")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "mainnet"`)
+}
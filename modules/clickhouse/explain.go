@@ -0,0 +1,232 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+// explainTimeout bounds the EXPLAIN queries behind one query plan.
+const explainTimeout = 30 * time.Second
+
+// QueryFunc runs sql on a ClickHouse datasource and returns the response
+// body in ClickHouse's JSON format.
+type QueryFunc func(ctx context.Context, datasource, sql string) ([]byte, error)
+
+// QueryPlan is the estimated cost and plan of a query, from EXPLAIN ESTIMATE
+// and EXPLAIN PLAN.
+type QueryPlan struct {
+	Cluster   string          `json:"cluster"`
+	Estimates []TableEstimate `json:"estimates"`
+	// TotalRows and TotalBytes sum the estimates over all tables read.
+	TotalRows  uint64 `json:"total_rows"`
+	TotalBytes uint64 `json:"total_bytes,omitempty"`
+	Plan       string `json:"plan"`
+}
+
+// TableEstimate is the estimated read from one table. Bytes extrapolates the
+// table's average compressed row size to Rows, so it is an upper bound when
+// only some columns are read; it is zero when table sizes are unavailable.
+type TableEstimate struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	Parts    uint64 `json:"parts"`
+	Rows     uint64 `json:"rows"`
+	Marks    uint64 `json:"marks"`
+	Bytes    uint64 `json:"bytes,omitempty"`
+}
+
+// Explain estimates the rows and bytes sql would read on cluster and returns
+// its query plan, without running it.
+func Explain(ctx context.Context, query QueryFunc, cluster, sql string) (*QueryPlan, error) {
+	sql = strings.TrimSuffix(strings.TrimSpace(sql), ";")
+	if sql == "" {
+		return nil, fmt.Errorf("sql is required")
+	}
+
+	if cluster == "" {
+		return nil, fmt.Errorf("cluster is required")
+	}
+
+	estimate, err := explainQuery(ctx, query, cluster, "EXPLAIN ESTIMATE "+sql)
+	if err != nil {
+		return nil, fmt.Errorf("EXPLAIN ESTIMATE: %w", err)
+	}
+
+	planRows, err := explainQuery(ctx, query, cluster, "EXPLAIN PLAN "+sql)
+	if err != nil {
+		return nil, fmt.Errorf("EXPLAIN PLAN: %w", err)
+	}
+
+	plan := &QueryPlan{
+		Cluster:   cluster,
+		Estimates: make([]TableEstimate, 0, len(estimate.Data)),
+	}
+
+	for _, row := range estimate.Data {
+		est := TableEstimate{
+			Database: asString(row["database"]),
+			Table:    asString(row["table"]),
+			Parts:    asUint(row["parts"]),
+			Rows:     asUint(row["rows"]),
+			Marks:    asUint(row["marks"]),
+		}
+
+		plan.Estimates = append(plan.Estimates, est)
+		plan.TotalRows += est.Rows
+	}
+
+	lines := make([]string, 0, len(planRows.Data))
+	for _, row := range planRows.Data {
+		lines = append(lines, asString(row[pickColumn(planRows.Meta, "explain")]))
+	}
+
+	plan.Plan = strings.Join(lines, "\n")
+
+	// Byte estimates are best-effort: the plan is still useful without them.
+	if sizes, err := tableSizes(ctx, query, cluster, plan.Estimates); err == nil {
+		for i := range plan.Estimates {
+			est := &plan.Estimates[i]
+			if size, ok := sizes[est.Database+"."+est.Table]; ok && size.rows > 0 {
+				est.Bytes = uint64(float64(est.Rows) * float64(size.bytes) / float64(size.rows))
+				plan.TotalBytes += est.Bytes
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+type tableSize struct {
+	rows  uint64
+	bytes uint64
+}
+
+// tableSizes returns the total rows and bytes of the estimated tables, keyed
+// by database.table.
+func tableSizes(ctx context.Context, query QueryFunc, cluster string, estimates []TableEstimate) (map[string]tableSize, error) {
+	if len(estimates) == 0 {
+		return nil, nil
+	}
+
+	tuples := make([]string, 0, len(estimates))
+	for _, est := range estimates {
+		tuples = append(tuples, fmt.Sprintf("(%s, %s)", quoteString(est.Database), quoteString(est.Table)))
+	}
+
+	result, err := explainQuery(ctx, query, cluster, fmt.Sprintf(
+		"SELECT database, name, total_rows, total_bytes FROM system.tables WHERE (database, name) IN (%s)",
+		strings.Join(tuples, ", "),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]tableSize, len(result.Data))
+	for _, row := range result.Data {
+		sizes[asString(row["database"])+"."+asString(row["name"])] = tableSize{
+			rows:  asUint(row["total_rows"]),
+			bytes: asUint(row["total_bytes"]),
+		}
+	}
+
+	return sizes, nil
+}
+
+func explainQuery(ctx context.Context, query QueryFunc, cluster, sql string) (*clickhouseJSONResponse, error) {
+	body, err := query(ctx, cluster, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var result clickhouseJSONResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	if result.Err != nil {
+		return nil, fmt.Errorf("query error (%d): %s", result.Err.Code, result.Err.Message)
+	}
+
+	return &result, nil
+}
+
+// ProxyQueryFunc returns a QueryFunc that sends queries through the proxy.
+func ProxyQueryFunc(proxySvc proxy.Service) QueryFunc {
+	httpClient := &http.Client{Transport: proxySvc.Transport(), Timeout: explainTimeout}
+
+	return func(ctx context.Context, datasource, sql string) ([]byte, error) {
+		baseURL := strings.TrimRight(proxySvc.URL(), "/")
+		if baseURL == "" {
+			return nil, fmt.Errorf("proxy URL is empty")
+		}
+
+		params := url.Values{"default_format": {"JSON"}}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/clickhouse/?"+params.Encode(), strings.NewReader(sql))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		req.Header.Set(handlers.DatasourceHeader, datasource)
+		req.Header.Set("Content-Type", "text/plain")
+
+		tokenID := fmt.Sprintf("clickhouse-explain-%d", time.Now().UnixNano())
+		token := proxySvc.RegisterToken(tokenID)
+
+		defer proxySvc.RevokeToken(tokenID)
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		if err := proxySvc.SignRequest(req); err != nil {
+			return nil, fmt.Errorf("signing request: %w", err)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("executing query: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("query failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		return body, nil
+	}
+}
+
+// asUint converts a ClickHouse JSON number, which is quoted for 64-bit
+// types, to uint64.
+func asUint(value any) uint64 {
+	switch v := value.(type) {
+	case float64:
+		return uint64(v)
+	case string:
+		n, _ := strconv.ParseUint(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// quoteString quotes s as a ClickHouse string literal.
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
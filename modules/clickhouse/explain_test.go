@@ -0,0 +1,64 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	var queries []string
+
+	query := func(_ context.Context, datasource, sql string) ([]byte, error) {
+		assert.Equal(t, "xatu", datasource)
+		queries = append(queries, sql)
+
+		switch {
+		case strings.HasPrefix(sql, "EXPLAIN ESTIMATE"):
+			return []byte(`{"meta":[{"name":"database"},{"name":"table"},{"name":"parts"},{"name":"rows"},{"name":"marks"}],
+				"data":[{"database":"default","table":"blocks","parts":"3","rows":"1000","marks":"2"}]}`), nil
+		case strings.HasPrefix(sql, "EXPLAIN PLAN"):
+			return []byte(`{"meta":[{"name":"explain"}],"data":[{"explain":"Expression"},{"explain":"  ReadFromMergeTree"}]}`), nil
+		case strings.Contains(sql, "system.tables"):
+			return []byte(`{"data":[{"database":"default","name":"blocks","total_rows":"10000","total_bytes":"500000"}]}`), nil
+		}
+
+		return nil, errors.New("unexpected query")
+	}
+
+	plan, err := Explain(context.Background(), query, "xatu", "SELECT count() FROM blocks;")
+	require.NoError(t, err)
+
+	assert.Equal(t, "EXPLAIN ESTIMATE SELECT count() FROM blocks", queries[0])
+	require.Len(t, plan.Estimates, 1)
+	assert.Equal(t, TableEstimate{Database: "default", Table: "blocks", Parts: 3, Rows: 1000, Marks: 2, Bytes: 50000}, plan.Estimates[0])
+	assert.Equal(t, uint64(1000), plan.TotalRows)
+	assert.Equal(t, uint64(50000), plan.TotalBytes)
+	assert.Equal(t, "Expression\n  ReadFromMergeTree", plan.Plan)
+
+	_, err = Explain(context.Background(), query, "xatu", " ")
+	require.Error(t, err)
+}
+
+func TestExplainWithoutTableSizes(t *testing.T) {
+	query := func(_ context.Context, _, sql string) ([]byte, error) {
+		if strings.Contains(sql, "system.tables") {
+			return nil, errors.New("access denied")
+		}
+
+		if strings.HasPrefix(sql, "EXPLAIN ESTIMATE") {
+			return []byte(`{"data":[{"database":"default","table":"blocks","parts":1,"rows":10,"marks":1}]}`), nil
+		}
+
+		return []byte(`{"meta":[{"name":"explain"}],"data":[]}`), nil
+	}
+
+	plan, err := Explain(context.Background(), query, "xatu", "SELECT 1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), plan.TotalRows)
+	assert.Zero(t, plan.TotalBytes)
+}
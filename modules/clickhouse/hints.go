@@ -0,0 +1,173 @@
+package clickhouse
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Error hint kinds reported by the ClickHouse module.
+const (
+	HintKindWrongCluster    = "clickhouse_wrong_cluster"
+	HintKindMissingDatabase = "clickhouse_missing_database"
+	HintKindUnknownDatabase = "clickhouse_unknown_database"
+)
+
+var (
+	// unknownTablePattern matches UNKNOWN_TABLE errors, both the classic
+	// "Table db.name does not exist" and the analyzer's
+	// "Unknown table expression identifier 'name'".
+	unknownTablePattern = regexp.MustCompile(
+		"Table `?([A-Za-z0-9_.]+)`? (?:does not|doesn't) exist|Unknown table expression identifier '([A-Za-z0-9_.]+)'",
+	)
+
+	// unknownDatabasePattern matches UNKNOWN_DATABASE errors.
+	unknownDatabasePattern = regexp.MustCompile("Database `?([A-Za-z0-9_]+)`? (?:does not|doesn't) exist")
+)
+
+// errorHints matches ClickHouse error signatures in output against the
+// discovered schema index and suggests the cluster or database prefix that
+// would have worked.
+func errorHints(clusters map[string]*ClusterTables, output string) []types.ErrorHint {
+	if len(clusters) == 0 || (!strings.Contains(output, "exist") && !strings.Contains(output, "Unknown table")) {
+		return nil
+	}
+
+	clusterNames := make([]string, 0, len(clusters))
+	for name := range clusters {
+		clusterNames = append(clusterNames, name)
+	}
+
+	sort.Strings(clusterNames)
+
+	var hints []types.ErrorHint
+
+	seen := make(map[string]struct{}, 4)
+
+	for _, match := range unknownTablePattern.FindAllStringSubmatch(output, -1) {
+		ref := match[1]
+		if ref == "" {
+			ref = match[2]
+		}
+
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+
+		seen[ref] = struct{}{}
+		hints = append(hints, unknownTableHints(clusters, clusterNames, ref)...)
+	}
+
+	for _, match := range unknownDatabasePattern.FindAllStringSubmatch(output, -1) {
+		database := match[1]
+		if _, ok := seen["db:"+database]; ok {
+			continue
+		}
+
+		seen["db:"+database] = struct{}{}
+
+		if hint, ok := unknownDatabaseHint(clusters, clusterNames, database); ok {
+			hints = append(hints, hint)
+		}
+	}
+
+	return hints
+}
+
+// unknownTableHints suggests where a missing table reference actually lives.
+func unknownTableHints(clusters map[string]*ClusterTables, clusterNames []string, ref string) []types.ErrorHint {
+	database, table := "", ref
+	if idx := strings.LastIndex(ref, "."); idx >= 0 {
+		database, table = ref[:idx], ref[idx+1:]
+	}
+
+	var hints []types.ErrorHint
+
+	for _, clusterName := range clusterNames {
+		schema, ok := clusters[clusterName].Tables[table]
+		if !ok {
+			continue
+		}
+
+		if len(schema.Networks) > 0 {
+			if slices.Contains(schema.Networks, database) {
+				// The reference was valid for this cluster, so the query
+				// was sent to a different one.
+				hints = append(hints, types.ErrorHint{
+					Kind:    HintKindWrongCluster,
+					Message: fmt.Sprintf("`%s` lives on the %s cluster; run this query against %s.", ref, clusterName, clusterName),
+				})
+
+				continue
+			}
+
+			hints = append(hints, types.ErrorHint{
+				Kind: HintKindMissingDatabase,
+				Message: fmt.Sprintf(
+					"`%s` lives on the %s cluster in per-network databases; use a database prefix, e.g. `%s.%s`.",
+					table, clusterName, exampleNetwork(schema.Networks), table,
+				),
+			})
+
+			continue
+		}
+
+		message := fmt.Sprintf("`%s` lives on the %s cluster in its default database; query it there without a database prefix", table, clusterName)
+		if schema.HasNetworkCol {
+			message += " and filter with meta_network_name"
+		}
+
+		hints = append(hints, types.ErrorHint{Kind: HintKindWrongCluster, Message: message + "."})
+	}
+
+	return hints
+}
+
+// unknownDatabaseHint suggests the cluster that has a database, or the
+// network column to use on default-database clusters.
+func unknownDatabaseHint(clusters map[string]*ClusterTables, clusterNames []string, database string) (types.ErrorHint, bool) {
+	var networkColumnCluster string
+
+	for _, clusterName := range clusterNames {
+		for _, schema := range clusters[clusterName].Tables {
+			if slices.Contains(schema.Networks, database) {
+				return types.ErrorHint{
+					Kind:    HintKindWrongCluster,
+					Message: fmt.Sprintf("database `%s` exists on the %s cluster; run this query against %s.", database, clusterName, clusterName),
+				}, true
+			}
+
+			if schema.HasNetworkCol && networkColumnCluster == "" {
+				networkColumnCluster = clusterName
+			}
+		}
+	}
+
+	if networkColumnCluster == "" {
+		return types.ErrorHint{}, false
+	}
+
+	return types.ErrorHint{
+		Kind: HintKindUnknownDatabase,
+		Message: fmt.Sprintf(
+			"no cluster has a `%s` database; on %s drop the prefix and filter with meta_network_name = '%s'.",
+			database, networkColumnCluster, database,
+		),
+	}, true
+}
+
+// exampleNetwork picks the network database to show in a prefix hint.
+func exampleNetwork(networks []string) string {
+	if slices.Contains(networks, "mainnet") {
+		return "mainnet"
+	}
+
+	sorted := slices.Clone(networks)
+	sort.Strings(sorted)
+
+	return sorted[0]
+}
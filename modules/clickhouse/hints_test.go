@@ -0,0 +1,75 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHints(t *testing.T) {
+	clusters := map[string]*ClusterTables{
+		"xatu": {
+			ClusterName: "xatu",
+			Tables: map[string]*TableSchema{
+				"beacon_api_eth_v1_events_block": {Name: "beacon_api_eth_v1_events_block", HasNetworkCol: true},
+			},
+		},
+		"xatu-cbt": {
+			ClusterName: "xatu-cbt",
+			Tables: map[string]*TableSchema{
+				"fct_block_head": {Name: "fct_block_head", Networks: []string{"holesky", "mainnet"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		kind    string
+		message string
+	}{
+		{
+			name:    "missing database prefix",
+			output:  "DB::Exception: Table default.fct_block_head does not exist. (UNKNOWN_TABLE)",
+			kind:    HintKindMissingDatabase,
+			message: "`fct_block_head` lives on the xatu-cbt cluster in per-network databases; use a database prefix, e.g. `mainnet.fct_block_head`.",
+		},
+		{
+			name:    "prefixed table sent to the wrong cluster",
+			output:  "DB::Exception: Unknown table expression identifier 'mainnet.fct_block_head' in scope SELECT",
+			kind:    HintKindWrongCluster,
+			message: "`mainnet.fct_block_head` lives on the xatu-cbt cluster; run this query against xatu-cbt.",
+		},
+		{
+			name:    "default database table sent to the wrong cluster",
+			output:  "DB::Exception: Unknown table expression identifier 'beacon_api_eth_v1_events_block'",
+			kind:    HintKindWrongCluster,
+			message: "`beacon_api_eth_v1_events_block` lives on the xatu cluster in its default database; query it there without a database prefix and filter with meta_network_name.",
+		},
+		{
+			name:    "network database on the wrong cluster",
+			output:  "DB::Exception: Database `holesky` does not exist. (UNKNOWN_DATABASE)",
+			kind:    HintKindWrongCluster,
+			message: "database `holesky` exists on the xatu-cbt cluster; run this query against xatu-cbt.",
+		},
+		{
+			name:    "unknown network database",
+			output:  "DB::Exception: Database hoodi doesn't exist. (UNKNOWN_DATABASE)",
+			kind:    HintKindUnknownDatabase,
+			message: "no cluster has a `hoodi` database; on xatu drop the prefix and filter with meta_network_name = 'hoodi'.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints := errorHints(clusters, tt.output)
+			require.Len(t, hints, 1)
+			assert.Equal(t, tt.kind, hints[0].Kind)
+			assert.Equal(t, tt.message, hints[0].Message)
+		})
+	}
+
+	assert.Empty(t, errorHints(clusters, "DB::Exception: Table default.not_a_table does not exist."))
+	assert.Empty(t, errorHints(clusters, "Traceback: ZeroDivisionError"))
+}
@@ -0,0 +1,141 @@
+package clickhouse
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// tableClausePattern matches the table-level clauses SHOW CREATE TABLE
+	// prints on their own lines after the column list.
+	tableClausePattern = regexp.MustCompile(`(?m)^(PARTITION BY|ORDER BY|PRIMARY KEY|SAMPLE BY|TTL|AS)\s+(.+)$`)
+
+	// viewTargetPattern extracts the target table of a materialized view
+	// from CREATE MATERIALIZED VIEW ... TO target.
+	viewTargetPattern = regexp.MustCompile("^CREATE MATERIALIZED VIEW\\s+\\S+\\s+TO\\s+([`\\w.]+)")
+
+	// viewSourcePattern extracts the tables a view's SELECT reads.
+	viewSourcePattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+([`\\w.]+)")
+
+	// distributedPattern extracts the local table of a Distributed engine:
+	// Distributed(cluster, database, table[, sharding_key]).
+	distributedPattern = regexp.MustCompile(`Distributed\(\s*[^,]+,\s*'?([\w.]+)'?\s*,\s*'?(\w+)'?`)
+)
+
+// MaterializedView describes the data flow of a materialized view.
+type MaterializedView struct {
+	// Target is the table the view writes to; empty when the view stores
+	// its rows itself.
+	Target string `json:"target,omitempty"`
+	// Sources are the tables the view's SELECT reads.
+	Sources []string `json:"sources"`
+}
+
+// TableFeed is a materialized view that writes into a table.
+type TableFeed struct {
+	View    string   `json:"view"`
+	Sources []string `json:"sources"`
+}
+
+// parseTableClauses fills the sorting, partitioning, TTL and lineage fields
+// of schema from the text of its CREATE statement after the column list.
+func parseTableClauses(schema *TableSchema, createStmt, suffix string) {
+	for _, match := range tableClausePattern.FindAllStringSubmatch(suffix, -1) {
+		value := strings.TrimSpace(match[2])
+
+		switch match[1] {
+		case "PARTITION BY":
+			schema.PartitionBy = value
+		case "ORDER BY":
+			schema.OrderBy = value
+		case "PRIMARY KEY":
+			schema.PrimaryKey = value
+		case "SAMPLE BY":
+			schema.SampleBy = value
+		case "TTL":
+			schema.TTL = value
+		}
+
+		// Clauses after AS belong to the view's SELECT, not the table.
+		if match[1] == "AS" {
+			break
+		}
+	}
+
+	if matches := distributedPattern.FindStringSubmatch(suffix); len(matches) > 2 {
+		schema.LocalTable = matches[2]
+	}
+
+	if !strings.HasPrefix(createStmt, "CREATE MATERIALIZED VIEW") {
+		return
+	}
+
+	view := &MaterializedView{Sources: make([]string, 0, 2)}
+
+	if matches := viewTargetPattern.FindStringSubmatch(createStmt); len(matches) > 1 {
+		view.Target = unqualifiedName(matches[1])
+	}
+
+	if _, query, ok := strings.Cut(suffix, "\nAS "); ok {
+		seen := make(map[string]bool, 2)
+
+		for _, match := range viewSourcePattern.FindAllStringSubmatch(query, -1) {
+			source := unqualifiedName(match[1])
+			if source != "" && !seen[source] {
+				seen[source] = true
+				view.Sources = append(view.Sources, source)
+			}
+		}
+	}
+
+	schema.MaterializedView = view
+}
+
+// linkLineage records, on each table of a cluster, the materialized views
+// that write into it. A Distributed table inherits the feeds of its local
+// table, since that is the table agents query.
+func linkLineage(tables map[string]*TableSchema) {
+	for _, schema := range tables {
+		schema.FedBy = nil
+	}
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		view := tables[name].MaterializedView
+		if view == nil || view.Target == "" {
+			continue
+		}
+
+		if target, ok := tables[view.Target]; ok {
+			target.FedBy = append(target.FedBy, TableFeed{View: name, Sources: view.Sources})
+		}
+	}
+
+	for _, name := range names {
+		schema := tables[name]
+		if schema.LocalTable == "" || schema.LocalTable == name {
+			continue
+		}
+
+		if local, ok := tables[schema.LocalTable]; ok && len(local.FedBy) > 0 {
+			schema.FedBy = append(schema.FedBy, local.FedBy...)
+		}
+	}
+}
+
+// unqualifiedName strips the database and backticks from a table reference.
+func unqualifiedName(ref string) string {
+	ref = strings.ReplaceAll(ref, "`", "")
+	if idx := strings.LastIndex(ref, "."); idx != -1 {
+		ref = ref[idx+1:]
+	}
+
+	return ref
+}
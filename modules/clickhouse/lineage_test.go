@@ -0,0 +1,78 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateTable_Clauses(t *testing.T) {
+	schema, err := parseCreateTable("blocks_local", "CREATE TABLE default.blocks_local\n"+
+		"(\n"+
+		"    `slot_start_date_time` DateTime,\n"+
+		"    `meta_network_name` LowCardinality(String)\n"+
+		")\n"+
+		"ENGINE = ReplicatedReplacingMergeTree('/clickhouse/{installation}/{cluster}/tables/{shard}/{database}/{table}', '{replica}')\n"+
+		"PARTITION BY toStartOfMonth(slot_start_date_time)\n"+
+		"ORDER BY (slot_start_date_time, meta_network_name)\n"+
+		"TTL slot_start_date_time + toIntervalMonth(6)\n"+
+		"SETTINGS index_granularity = 8192")
+	require.NoError(t, err)
+
+	assert.Equal(t, "toStartOfMonth(slot_start_date_time)", schema.PartitionBy)
+	assert.Equal(t, "(slot_start_date_time, meta_network_name)", schema.OrderBy)
+	assert.Equal(t, "slot_start_date_time + toIntervalMonth(6)", schema.TTL)
+	assert.Nil(t, schema.MaterializedView)
+
+	distributed, err := parseCreateTable("blocks", "CREATE TABLE default.blocks\n"+
+		"(\n"+
+		"    `slot_start_date_time` DateTime\n"+
+		")\n"+
+		"ENGINE = Distributed('{cluster}', 'default', 'blocks_local', rand())")
+	require.NoError(t, err)
+	assert.Equal(t, "blocks_local", distributed.LocalTable)
+}
+
+func TestParseCreateTable_MaterializedView(t *testing.T) {
+	schema, err := parseCreateTable("blocks_hourly_mv", "CREATE MATERIALIZED VIEW default.blocks_hourly_mv TO default.blocks_hourly_local\n"+
+		"(\n"+
+		"    `hour` DateTime,\n"+
+		"    `blocks` UInt64\n"+
+		")\n"+
+		"AS SELECT toStartOfHour(slot_start_date_time) AS hour, count() AS blocks\n"+
+		"FROM default.blocks_local\n"+
+		"LEFT JOIN `default`.`slots` USING (slot)\n"+
+		"GROUP BY hour\n"+
+		"ORDER BY hour")
+	require.NoError(t, err)
+
+	require.NotNil(t, schema.MaterializedView)
+	assert.Equal(t, "blocks_hourly_local", schema.MaterializedView.Target)
+	assert.Equal(t, []string{"blocks_local", "slots"}, schema.MaterializedView.Sources)
+	assert.Empty(t, schema.OrderBy, "the SELECT's ORDER BY is not the table's")
+	assert.Len(t, schema.Columns, 2)
+}
+
+func TestLinkLineage(t *testing.T) {
+	tables := map[string]*TableSchema{
+		"blocks_hourly_mv": {
+			Name:             "blocks_hourly_mv",
+			MaterializedView: &MaterializedView{Target: "blocks_hourly_local", Sources: []string{"blocks_local"}},
+		},
+		"blocks_hourly_local": {Name: "blocks_hourly_local"},
+		"blocks_hourly":       {Name: "blocks_hourly", LocalTable: "blocks_hourly_local"},
+		"blocks_local":        {Name: "blocks_local"},
+	}
+
+	linkLineage(tables)
+
+	feed := []TableFeed{{View: "blocks_hourly_mv", Sources: []string{"blocks_local"}}}
+	assert.Equal(t, feed, tables["blocks_hourly_local"].FedBy)
+	assert.Equal(t, feed, tables["blocks_hourly"].FedBy)
+	assert.Empty(t, tables["blocks_local"].FedBy)
+
+	// Linking again does not duplicate feeds.
+	linkLineage(tables)
+	assert.Len(t, tables["blocks_hourly"].FedBy, 1)
+}
@@ -0,0 +1,205 @@
+package clickhouse
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Lint finding kinds reported by the ClickHouse module.
+const (
+	LintKindMissingNetworkFilter = "clickhouse_missing_network_filter"
+	LintKindMissingPartition     = "clickhouse_missing_partition_filter"
+)
+
+var (
+	// tableRefPattern matches table references after FROM and JOIN.
+	tableRefPattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+`?([A-Za-z_][A-Za-z0-9_]*(?:`?\\.`?[A-Za-z_][A-Za-z0-9_]*)?)`?")
+
+	// identifierPattern matches identifiers in partition key expressions.
+	identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// sqlFunctions are the clickhouse functions whose first two parameters are a
+// cluster and a SQL query.
+var sqlFunctions = map[string]struct{}{
+	"query":     {},
+	"query_raw": {},
+	"explain":   {},
+}
+
+// lintCalls checks the SQL of ClickHouse calls against the discovered schema:
+// tables queried on a cluster that does not have them, and queries that skip
+// the network or partition filters the cluster rules require.
+func lintCalls(clusters map[string]*ClusterTables, calls []types.PythonCall) []types.LintFinding {
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	clusterNames := make([]string, 0, len(clusters))
+	for name := range clusters {
+		clusterNames = append(clusterNames, name)
+	}
+
+	sort.Strings(clusterNames)
+
+	var findings []types.LintFinding
+
+	for _, call := range calls {
+		if _, ok := sqlFunctions[call.Function]; call.Module != "clickhouse" || !ok {
+			continue
+		}
+
+		cluster, ok := call.Arg(0, "cluster")
+		if !ok {
+			continue
+		}
+
+		sql, ok := call.Arg(1, "sql")
+		if !ok {
+			continue
+		}
+
+		tables, ok := clusters[cluster]
+		if !ok {
+			// Unknown clusters are reported by the generic datasource check.
+			continue
+		}
+
+		for _, ref := range tableRefs(sql) {
+			findings = append(findings, lintTableRef(clusters, clusterNames, cluster, tables, sql, ref, call.Line)...)
+		}
+	}
+
+	return findings
+}
+
+// lintTableRef checks one table reference of a query sent to cluster.
+func lintTableRef(
+	clusters map[string]*ClusterTables,
+	clusterNames []string,
+	cluster string,
+	tables *ClusterTables,
+	sql, ref string,
+	line int,
+) []types.LintFinding {
+	database, table := "", ref
+	if idx := strings.LastIndex(ref, "."); idx >= 0 {
+		database, table = ref[:idx], ref[idx+1:]
+	}
+
+	schema, ok := tables.Tables[table]
+	if !ok || (len(schema.Networks) > 0 && !containsFold(schema.Networks, database)) {
+		// Suggest where the reference would work, as the error hints do
+		// after a failed run.
+		findings := make([]types.LintFinding, 0, 1)
+		for _, hint := range unknownTableHints(clusters, clusterNames, ref) {
+			findings = append(findings, types.LintFinding{
+				Line:     line,
+				Severity: types.LintSeverityWarning,
+				Kind:     hint.Kind,
+				Message:  fmt.Sprintf("%s: %s", cluster, hint.Message),
+			})
+		}
+
+		return findings
+	}
+
+	lowerSQL := strings.ToLower(sql)
+
+	var findings []types.LintFinding
+
+	if schema.HasNetworkCol && len(schema.Networks) == 0 && !strings.Contains(lowerSQL, "meta_network_name") {
+		findings = append(findings, types.LintFinding{
+			Line:     line,
+			Severity: types.LintSeverityWarning,
+			Kind:     LintKindMissingNetworkFilter,
+			Message: fmt.Sprintf(
+				"`%s` on %s holds every network; filter with meta_network_name, e.g. WHERE meta_network_name = 'mainnet'.",
+				table, cluster,
+			),
+		})
+	}
+
+	if columns := partitionColumns(schema); len(columns) > 0 {
+		filtered := false
+
+		for _, column := range columns {
+			if strings.Contains(lowerSQL, strings.ToLower(column)) {
+				filtered = true
+
+				break
+			}
+		}
+
+		if !filtered {
+			findings = append(findings, types.LintFinding{
+				Line:     line,
+				Severity: types.LintSeverityWarning,
+				Kind:     LintKindMissingPartition,
+				Message: fmt.Sprintf(
+					"`%s` is partitioned by %s; filter on %s to avoid scanning every partition and timing out.",
+					table, schema.PartitionBy, strings.Join(columns, " or "),
+				),
+			})
+		}
+	}
+
+	return findings
+}
+
+// tableRefs returns the distinct table references in sql, without quotes.
+func tableRefs(sql string) []string {
+	var (
+		refs []string
+		seen = make(map[string]struct{}, 4)
+	)
+
+	for _, match := range tableRefPattern.FindAllStringSubmatch(sql, -1) {
+		ref := strings.ReplaceAll(match[1], "`", "")
+
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// partitionColumns returns the table's columns used in its partition key.
+func partitionColumns(schema *TableSchema) []string {
+	if schema.PartitionBy == "" {
+		return nil
+	}
+
+	columns := make(map[string]struct{}, len(schema.Columns))
+	for _, column := range schema.Columns {
+		columns[column.Name] = struct{}{}
+	}
+
+	var result []string
+
+	for _, ident := range identifierPattern.FindAllString(schema.PartitionBy, -1) {
+		if _, ok := columns[ident]; ok && !containsFold(result, ident) {
+			result = append(result, ident)
+		}
+	}
+
+	return result
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
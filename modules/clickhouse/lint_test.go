@@ -0,0 +1,99 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func sqlCall(cluster, sql string) types.PythonCall {
+	return types.PythonCall{
+		Module:   "clickhouse",
+		Function: "query",
+		Line:     3,
+		Args: []types.PythonArg{
+			{Value: cluster, Known: true},
+			{Value: sql, Known: true},
+		},
+	}
+}
+
+func TestLintCalls(t *testing.T) {
+	clusters := map[string]*ClusterTables{
+		"xatu": {
+			ClusterName: "xatu",
+			Tables: map[string]*TableSchema{
+				"beacon_api_eth_v1_events_block": {
+					Name:          "beacon_api_eth_v1_events_block",
+					HasNetworkCol: true,
+					PartitionBy:   "toStartOfMonth(slot_start_date_time)",
+					Columns: []TableColumn{
+						{Name: "slot_start_date_time"},
+						{Name: "meta_network_name"},
+					},
+				},
+			},
+		},
+		"xatu-cbt": {
+			ClusterName: "xatu-cbt",
+			Tables: map[string]*TableSchema{
+				"fct_block_head": {Name: "fct_block_head", Networks: []string{"holesky", "mainnet"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		call  types.PythonCall
+		kinds []string
+	}{
+		{
+			name: "filtered query",
+			call: sqlCall("xatu", "SELECT count() FROM beacon_api_eth_v1_events_block "+
+				"WHERE meta_network_name = 'mainnet' AND slot_start_date_time > now() - INTERVAL 1 HOUR"),
+		},
+		{
+			name:  "missing network and partition filters",
+			call:  sqlCall("xatu", "SELECT count() FROM `beacon_api_eth_v1_events_block`"),
+			kinds: []string{LintKindMissingNetworkFilter, LintKindMissingPartition},
+		},
+		{
+			name:  "missing database prefix",
+			call:  sqlCall("xatu-cbt", "SELECT * FROM fct_block_head LIMIT 1"),
+			kinds: []string{HintKindMissingDatabase},
+		},
+		{
+			name:  "table on another cluster",
+			call:  sqlCall("xatu", "SELECT * FROM mainnet.fct_block_head JOIN x ON true"),
+			kinds: []string{HintKindWrongCluster},
+		},
+		{
+			name: "prefixed table on its cluster",
+			call: sqlCall("xatu-cbt", "SELECT * FROM mainnet.fct_block_head"),
+		},
+		{
+			name: "unknown cluster",
+			call: sqlCall("other", "SELECT * FROM fct_block_head"),
+		},
+		{
+			name: "unresolved sql",
+			call: types.PythonCall{Module: "clickhouse", Function: "query", Args: []types.PythonArg{{Value: "xatu", Known: true}, {}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := lintCalls(clusters, []types.PythonCall{tt.call})
+			require.Len(t, findings, len(tt.kinds))
+
+			for i, finding := range findings {
+				assert.Equal(t, tt.kinds[i], finding.Kind)
+				assert.Equal(t, types.LintSeverityWarning, finding.Severity)
+				assert.Equal(t, tt.call.Line, finding.Line)
+			}
+		})
+	}
+}
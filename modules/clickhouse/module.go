@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -17,10 +19,20 @@ import (
 
 // Compile-time interface checks.
 var (
-	_ module.Module            = (*Module)(nil)
-	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.Module                 = (*Module)(nil)
+	_ module.ProxyDiscoverable      = (*Module)(nil)
+	_ module.SnapshotAware          = (*Module)(nil)
+	_ module.SampleRowsAware        = (*Module)(nil)
+	_ module.CoverageTargetProvider = (*Module)(nil)
+	_ module.HealthProber           = (*Module)(nil)
+	_ module.ErrorHinter            = (*Module)(nil)
+	_ module.CodeLinter             = (*Module)(nil)
+	_ module.SchemaProvider         = (*Module)(nil)
 )
 
+// schemaSnapshotFile is the snapshot file name for discovered schemas.
+const schemaSnapshotFile = "clickhouse-schema.json"
+
 // Module implements the module.Module interface for ClickHouse.
 type Module struct {
 	cfg          Config
@@ -28,6 +40,13 @@ type Module struct {
 	log          logrus.FieldLogger
 	schemaClient ClickHouseSchemaClient
 	proxySvc     proxy.Service
+	snapshotDir  string
+	offline      bool
+	sampleRows   types.SampleRowsConfig
+
+	// notifier receives schema resource changes once resources are registered.
+	notifierMu sync.Mutex
+	notifier   module.ResourceNotifier
 }
 
 // New creates a new ClickHouse module.
@@ -45,6 +64,17 @@ func (p *Module) SetProxyClient(client proxy.Service) {
 	p.proxySvc = client
 }
 
+// SetSnapshotDir sets where discovered schemas are snapshotted for offline mode.
+func (p *Module) SetSnapshotDir(dir string, offline bool) {
+	p.snapshotDir = dir
+	p.offline = offline
+}
+
+// SetSampleRows sets which sample rows schema discovery attaches to tables.
+func (p *Module) SetSampleRows(cfg types.SampleRowsConfig) {
+	p.sampleRows = cfg
+}
+
 // InitFromDiscovery initializes the module from discovered datasources.
 func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	var filtered []types.DatasourceInfo
@@ -66,6 +96,9 @@ func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	return nil
 }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 // Init parses the raw YAML config for this module.
 func (p *Module) Init(rawConfig []byte) error {
 	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
@@ -160,6 +193,56 @@ func (p *Module) Examples() map[string]types.ExampleCategory {
 	return result
 }
 
+// CoverageTargets reports every discovered table for examples coverage.
+func (p *Module) CoverageTargets(_ context.Context) ([]types.CoverageTarget, error) {
+	if p.schemaClient == nil {
+		return nil, nil
+	}
+
+	var targets []types.CoverageTarget
+
+	for clusterName, cluster := range p.schemaClient.GetAllTables() {
+		for tableName := range cluster.Tables {
+			targets = append(targets, types.CoverageTarget{
+				Kind:       types.CoverageKindClickHouseTable,
+				Name:       tableName,
+				Datasource: clusterName,
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+// ErrorHints suggests the right cluster or database prefix for unknown table
+// and database errors, using the discovered schema.
+func (p *Module) ErrorHints(output string) []types.ErrorHint {
+	if p.schemaClient == nil {
+		return nil
+	}
+
+	return errorHints(p.schemaClient.GetAllTables(), output)
+}
+
+// LintCalls checks the SQL of clickhouse calls against the discovered schema
+// and the cluster rules before the code runs.
+func (p *Module) LintCalls(calls []types.PythonCall) []types.LintFinding {
+	if p.schemaClient == nil {
+		return nil
+	}
+
+	return lintCalls(p.schemaClient.GetAllTables(), calls)
+}
+
+// ProbeHealth checks each ClickHouse datasource's /ping endpoint through the proxy.
+func (p *Module) ProbeHealth(ctx context.Context) []types.HealthProbe {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	return module.ProbeProxyDatasources(ctx, p.proxySvc, p.datasources, "/clickhouse/ping")
+}
+
 // PythonAPIDocs returns the ClickHouse module documentation.
 func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 	return map[string]types.ModuleDoc{
@@ -189,6 +272,15 @@ func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 					},
 					Returns: "(rows, column_names)",
 				},
+				"explain": {
+					Signature:   "clickhouse.explain(cluster: str, sql: str) -> dict",
+					Description: "Estimate the rows and bytes a query would read, without running it. Use before large queries; also available as the clickhouse://query-plan resource.",
+					Parameters: map[string]string{
+						"cluster": "'xatu' or 'xatu-cbt'",
+						"sql":     "SQL query string",
+					},
+					Returns: "Dict with 'estimates' (per table: 'database', 'table', 'rows', 'bytes', 'parts', 'marks'), 'total_rows', 'total_bytes' and 'plan'",
+				},
 			},
 		},
 	}
@@ -214,16 +306,47 @@ Xatu data is split across **TWO clusters** with **DIFFERENT syntax**:
 - Tables have variants: ` + "`fct_block_canonical`" + ` vs ` + "`fct_block_head`"
 }
 
-// RegisterResources registers ClickHouse schema resources.
+// RegisterResources registers ClickHouse schema and query plan resources.
 func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
 	p.log = log.WithField("module", "clickhouse")
 	if p.schemaClient != nil {
 		RegisterSchemaResources(p.log, reg, p.schemaClient)
 	}
 
+	// Query plans need a live cluster.
+	if p.proxySvc != nil && !p.offline {
+		RegisterQueryPlanResource(p.log, reg, ProxyQueryFunc(p.proxySvc))
+	}
+
+	if notifier, ok := reg.(module.ResourceNotifier); ok {
+		p.notifierMu.Lock()
+		p.notifier = notifier
+		p.notifierMu.Unlock()
+	}
+
 	return nil
 }
 
+// notifySchemaChanged reports the schema resources affected by a refresh.
+func (p *Module) notifySchemaChanged(changed []string) {
+	p.notifierMu.Lock()
+	notifier := p.notifier
+	p.notifierMu.Unlock()
+
+	if notifier == nil {
+		return
+	}
+
+	uris := make([]string, 0, len(changed)+1)
+	uris = append(uris, "clickhouse://tables")
+
+	for _, name := range changed {
+		uris = append(uris, "clickhouse://tables/"+name)
+	}
+
+	notifier.NotifyUpdated(uris...)
+}
+
 // Start performs async initialization (schema discovery).
 func (p *Module) Start(ctx context.Context) error {
 	if p.log == nil {
@@ -272,12 +395,21 @@ func (p *Module) Start(ctx context.Context) error {
 		return nil
 	}
 
+	var snapshotPath string
+	if p.snapshotDir != "" {
+		snapshotPath = filepath.Join(p.snapshotDir, schemaSnapshotFile)
+	}
+
 	p.schemaClient = NewClickHouseSchemaClient(
 		p.log,
 		ClickHouseSchemaConfig{
 			RefreshInterval: p.cfg.SchemaDiscovery.RefreshInterval,
 			QueryTimeout:    DefaultSchemaQueryTimeout,
 			Datasources:     datasources,
+			SnapshotPath:    snapshotPath,
+			Offline:         p.offline,
+			SampleRows:      p.sampleRows,
+			OnRefresh:       p.notifySchemaChanged,
 		},
 		p.proxySvc,
 	)
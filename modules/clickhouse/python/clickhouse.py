@@ -47,3 +47,18 @@ def query_raw(
             "parameters": parameters,
         },
     )
+
+
+def explain(cluster_name: str, sql: str) -> dict[str, Any]:
+    """Estimate the rows and bytes a query would read, without running it."""
+    response = _runtime.invoke(
+        "clickhouse.explain",
+        {
+            "cluster": cluster_name,
+            "sql": sql,
+        },
+    )
+    plan = response.get("data", {})
+    if not isinstance(plan, dict):
+        raise ValueError("Invalid clickhouse.explain response shape")
+    return plan
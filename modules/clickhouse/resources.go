@@ -2,8 +2,8 @@ package clickhouse
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
@@ -11,20 +11,26 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
-// TablesListResponse is the response for clickhouse://tables.
+// TablesListResponse is the response for clickhouse://tables. Pages hold
+// tables in cluster, then table name order.
 type TablesListResponse struct {
 	Description string                           `json:"description"`
 	Clusters    map[string]*ClusterTablesSummary `json:"clusters"`
 	Usage       string                           `json:"usage"`
+	Total       int                              `json:"total,omitempty"`
+	NextCursor  string                           `json:"next_cursor,omitempty"`
 }
 
-// ClusterTablesSummary is a compact summary of tables in a cluster.
+// ClusterTablesSummary is a compact summary of tables in a cluster. The
+// compact view lists only table names.
 type ClusterTablesSummary struct {
-	Tables      []*TableSummary `json:"tables"`
+	Tables      []*TableSummary `json:"tables,omitempty"`
+	TableNames  []string        `json:"table_names,omitempty"`
 	TableCount  int             `json:"table_count"`
 	LastUpdated string          `json:"last_updated"`
 }
@@ -67,13 +73,14 @@ func RegisterSchemaResources(
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.7),
 		),
 		Handler: createTablesListHandler(client),
+		Paged:   true,
 	})
 
 	// clickhouse://tables/{table_name} - Individual table details
 	template := mcp.NewResourceTemplate(
 		"clickhouse://tables/{table_name}",
 		"ClickHouse Table Schema",
-		mcp.WithTemplateDescription("Full schema for a specific ClickHouse table including columns, types, comments, and available networks"),
+		mcp.WithTemplateDescription("Full schema for a specific ClickHouse table including columns, types, comments, available networks, partition and sorting keys, TTL, materialized view lineage, and sample rows when enabled"),
 		mcp.WithTemplateMIMEType("application/json"),
 		mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
 	)
@@ -87,10 +94,56 @@ func RegisterSchemaResources(
 	log.Debug("Registered ClickHouse schema resources")
 }
 
+// queryPlanURIPattern matches clickhouse://query-plan URIs.
+var queryPlanURIPattern = regexp.MustCompile(`^clickhouse://query-plan(\?.*)?$`)
+
+// RegisterQueryPlanResource registers clickhouse://query-plan, which
+// estimates the cost of a query with EXPLAIN before it is run.
+func RegisterQueryPlanResource(log logrus.FieldLogger, reg module.ResourceRegistry, query QueryFunc) {
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"clickhouse://query-plan{?cluster,sql}",
+			"ClickHouse Query Plan",
+			mcp.WithTemplateDescription("Estimated rows, bytes and parts a SQL query would read on a cluster, with its EXPLAIN PLAN, without running it. Check large queries before execute_python."),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: queryPlanURIPattern,
+		Handler: createQueryPlanHandler(query),
+	})
+
+	log.WithField("resource", "clickhouse_query_plan").Debug("Registered ClickHouse query plan resource")
+}
+
+// createQueryPlanHandler creates a handler for clickhouse://query-plan.
+func createQueryPlanHandler(query QueryFunc) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		_, rawQuery, _ := strings.Cut(uri, "?")
+
+		params, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return "", fmt.Errorf("invalid query plan URI: %w", err)
+		}
+
+		plan, err := Explain(ctx, query, params.Get("cluster"), params.Get("sql"))
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling query plan: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
 // createTablesListHandler creates a handler for the clickhouse://tables resource.
 func createTablesListHandler(client ClickHouseSchemaClient) types.ReadHandler {
-	return func(_ context.Context, _ string) (string, error) {
+	return func(ctx context.Context, _ string) (string, error) {
 		allTables := client.GetAllTables()
+		params := types.GetListParams(ctx)
 
 		response := &TablesListResponse{
 			Description: "Available ClickHouse tables across xatu clusters. Use clickhouse://tables/{table_name} for detailed schema.",
@@ -98,15 +151,24 @@ func createTablesListHandler(client ClickHouseSchemaClient) types.ReadHandler {
 			Usage:       "To get detailed schema for a table, access clickhouse://tables/{table_name}",
 		}
 
-		// Build cluster summaries
-		for clusterName, cluster := range allTables {
-			summary := &ClusterTablesSummary{
-				Tables:      make([]*TableSummary, 0, len(cluster.Tables)),
-				TableCount:  len(cluster.Tables),
-				LastUpdated: cluster.LastUpdated.Format("2006-01-02T15:04:05Z"),
-			}
+		type tableRef struct {
+			cluster string
+			table   string
+		}
+
+		// Sort for consistent output and stable pages.
+		clusterNames := make([]string, 0, len(allTables))
+		for clusterName := range allTables {
+			clusterNames = append(clusterNames, clusterName)
+		}
+
+		sort.Strings(clusterNames)
+
+		refs := make([]tableRef, 0, 256)
+
+		for _, clusterName := range clusterNames {
+			cluster := allTables[clusterName]
 
-			// Sort table names for consistent output
 			tableNames := make([]string, 0, len(cluster.Tables))
 			for tableName := range cluster.Tables {
 				tableNames = append(tableNames, tableName)
@@ -115,21 +177,46 @@ func createTablesListHandler(client ClickHouseSchemaClient) types.ReadHandler {
 			sort.Strings(tableNames)
 
 			for _, tableName := range tableNames {
-				schema := cluster.Tables[tableName]
+				refs = append(refs, tableRef{cluster: clusterName, table: tableName})
+			}
+
+			// Pages only list the clusters they hold tables of.
+			if !params.Paged() {
+				response.Clusters[clusterName] = newClusterTablesSummary(cluster)
+			}
+		}
 
-				tableSummary := &TableSummary{
-					Name:          schema.Name,
-					ColumnCount:   len(schema.Columns),
-					HasNetworkCol: schema.HasNetworkCol,
-				}
+		start, end, next := params.Page(len(refs))
+		response.NextCursor = next
 
-				summary.Tables = append(summary.Tables, tableSummary)
+		if params.Paged() {
+			response.Total = len(refs)
+		}
+
+		for _, ref := range refs[start:end] {
+			cluster := allTables[ref.cluster]
+
+			summary, ok := response.Clusters[ref.cluster]
+			if !ok {
+				summary = newClusterTablesSummary(cluster)
+				response.Clusters[ref.cluster] = summary
+			}
+
+			if params.Compact() {
+				summary.TableNames = append(summary.TableNames, ref.table)
+
+				continue
 			}
 
-			response.Clusters[clusterName] = summary
+			schema := cluster.Tables[ref.table]
+			summary.Tables = append(summary.Tables, &TableSummary{
+				Name:          schema.Name,
+				ColumnCount:   len(schema.Columns),
+				HasNetworkCol: schema.HasNetworkCol,
+			})
 		}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling tables list: %w", err)
 		}
@@ -138,6 +225,14 @@ func createTablesListHandler(client ClickHouseSchemaClient) types.ReadHandler {
 	}
 }
 
+// newClusterTablesSummary returns an empty summary of a cluster's tables.
+func newClusterTablesSummary(cluster *ClusterTables) *ClusterTablesSummary {
+	return &ClusterTablesSummary{
+		TableCount:  len(cluster.Tables),
+		LastUpdated: cluster.LastUpdated.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
 // createTableDetailHandler creates a handler for the clickhouse://tables/{table_name} resource.
 func createTableDetailHandler(log logrus.FieldLogger, client ClickHouseSchemaClient) types.ReadHandler {
 	return func(_ context.Context, uri string) (string, error) {
@@ -176,7 +271,7 @@ func createTableDetailHandler(log logrus.FieldLogger, client ClickHouseSchemaCli
 			Clusters: clusters,
 		}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling table detail: %w", err)
 		}
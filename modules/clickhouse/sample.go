@@ -0,0 +1,128 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// redactedValue replaces the values of redacted columns in sample rows.
+const redactedValue = "[redacted]"
+
+// unsampledEngines are table engines whose rows are not sampled: streaming
+// engines consume messages when read, and plain views run their whole query.
+var unsampledEngines = map[string]bool{
+	"Kafka":      true,
+	"RabbitMQ":   true,
+	"NATS":       true,
+	"FileLog":    true,
+	"S3Queue":    true,
+	"AzureQueue": true,
+	"View":       true,
+	"Null":       true,
+}
+
+// fetchSampleRows fetches up to the configured number of rows from a table,
+// with long values truncated and redacted columns replaced. It returns nil
+// for engines that are not sampled.
+func (c *clickhouseSchemaClient) fetchSampleRows(
+	ctx context.Context,
+	datasourceName string,
+	token string,
+	database string,
+	schema *TableSchema,
+) ([]map[string]any, error) {
+	if unsampledEngines[schema.Engine] {
+		return nil, nil
+	}
+
+	table := fmt.Sprintf("`%s`", schema.Name)
+	if database != "" {
+		table = fmt.Sprintf("`%s`.`%s`", database, schema.Name)
+	}
+
+	result, err := c.queryJSON(ctx, datasourceName, token, fmt.Sprintf(
+		"SELECT * FROM %s LIMIT %d", table, c.cfg.SampleRows.Rows,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("sampling rows: %w", err)
+	}
+
+	return sanitizeSampleRows(c.cfg.SampleRows, schema.Name, result.Data), nil
+}
+
+// sanitizeSampleRows truncates long values and redacts matching columns in
+// place.
+func sanitizeSampleRows(cfg types.SampleRowsConfig, table string, rows []map[string]any) []map[string]any {
+	for _, row := range rows {
+		for column, value := range row {
+			if redactColumn(cfg.RedactColumns, table, column) {
+				row[column] = redactedValue
+
+				continue
+			}
+
+			row[column] = truncateSampleValue(value, cfg.MaxValueLength)
+		}
+	}
+
+	return rows
+}
+
+// redactColumn reports whether column matches any of the patterns, either
+// alone or qualified as table.column. Matching ignores case.
+func redactColumn(patterns []string, table, column string) bool {
+	column = strings.ToLower(column)
+	qualified := strings.ToLower(table) + "." + column
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+
+		if ok, _ := path.Match(pattern, column); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, qualified); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// truncateSampleValue shortens strings longer than maxLen characters.
+// Arrays, maps and tuples longer than maxLen when encoded as JSON are
+// replaced by their truncated encoding.
+func truncateSampleValue(value any, maxLen int) any {
+	if maxLen <= 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return truncateRunes(v, maxLen)
+	case []any, map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil || len(data) <= maxLen {
+			return value
+		}
+
+		return truncateRunes(string(data), maxLen)
+	default:
+		return value
+	}
+}
+
+// truncateRunes cuts s to maxLen characters, marking the cut with "...".
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+
+	return string(runes[:maxLen]) + "..."
+}
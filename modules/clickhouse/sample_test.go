@@ -0,0 +1,56 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestSanitizeSampleRows(t *testing.T) {
+	cfg := types.SampleRowsConfig{
+		MaxValueLength: 8,
+		RedactColumns:  []string{"*_ip", "blocks.Proposer"},
+	}
+
+	rows := sanitizeSampleRows(cfg, "blocks", []map[string]any{{
+		"slot":         float64(42),
+		"graffiti":     "hello panda world",
+		"remote_ip":    "10.0.0.1",
+		"proposer":     "0xabc",
+		"short":        "ok",
+		"attestations": []any{"0x0102030405", "0x0607080910"},
+		"empty":        []any{},
+	}})
+
+	assert.Equal(t, []map[string]any{{
+		"slot":         float64(42),
+		"graffiti":     "hello pa...",
+		"remote_ip":    redactedValue,
+		"proposer":     redactedValue,
+		"short":        "ok",
+		"attestations": `["0x0102...`,
+		"empty":        []any{},
+	}}, rows)
+}
+
+func TestRedactColumn(t *testing.T) {
+	patterns := []string{"peer_id", "libp2p_*.remote_*"}
+
+	assert.True(t, redactColumn(patterns, "beacon", "PEER_ID"))
+	assert.True(t, redactColumn(patterns, "libp2p_connected", "remote_ip"))
+	assert.False(t, redactColumn(patterns, "beacon_blocks", "remote_ip"))
+	assert.False(t, redactColumn(nil, "beacon", "peer_id"))
+}
+
+func TestChangedTablesIgnoresSampleRows(t *testing.T) {
+	old := map[string]*ClusterTables{"xatu": {Tables: map[string]*TableSchema{
+		"blocks": {Name: "blocks", SampleRows: []map[string]any{{"slot": float64(1)}}},
+	}}}
+	updated := map[string]*ClusterTables{"xatu": {Tables: map[string]*TableSchema{
+		"blocks": {Name: "blocks", SampleRows: []map[string]any{{"slot": float64(2)}}},
+	}}}
+
+	assert.Empty(t, changedTables(old, updated))
+}
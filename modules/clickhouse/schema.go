@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
@@ -14,8 +15,10 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/offline"
 	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // Pre-compiled regexes for schema parsing.
@@ -55,6 +58,15 @@ type ClickHouseSchemaConfig struct {
 	RefreshInterval time.Duration
 	QueryTimeout    time.Duration
 	Datasources     []SchemaDiscoveryDatasource
+	// SnapshotPath, if set, is where discovered schemas are saved.
+	SnapshotPath string
+	// Offline serves the snapshot at SnapshotPath instead of querying ClickHouse.
+	Offline bool
+	// SampleRows controls the sample rows attached to each table.
+	SampleRows types.SampleRowsConfig
+	// OnRefresh, if set, is called after a refresh with the names of tables
+	// that were added, removed or changed in any cluster.
+	OnRefresh func(changed []string)
 }
 
 // discoveredTable represents a table found during schema discovery.
@@ -85,6 +97,26 @@ type TableSchema struct {
 	HasNetworkCol   bool          `json:"has_network_column"`
 	CreateStatement string        `json:"create_statement,omitempty"`
 	Comment         string        `json:"comment,omitempty"`
+
+	// PartitionBy, OrderBy, PrimaryKey, SampleBy and TTL are the table's
+	// MergeTree clauses. Filtering on the partition key and a prefix of the
+	// sorting key lets ClickHouse skip data.
+	PartitionBy string `json:"partition_by,omitempty"`
+	OrderBy     string `json:"order_by,omitempty"`
+	PrimaryKey  string `json:"primary_key,omitempty"`
+	SampleBy    string `json:"sample_by,omitempty"`
+	TTL         string `json:"ttl,omitempty"`
+
+	// LocalTable is the table behind a Distributed table.
+	LocalTable string `json:"local_table,omitempty"`
+	// MaterializedView is set when the table is a materialized view.
+	MaterializedView *MaterializedView `json:"materialized_view,omitempty"`
+	// FedBy lists the materialized views that write into the table.
+	FedBy []TableFeed `json:"fed_by,omitempty"`
+
+	// SampleRows are example rows, truncated and redacted, fetched when
+	// schema samples are enabled.
+	SampleRows []map[string]any `json:"sample_rows,omitempty"`
 }
 
 // ClusterTables represents tables available in a ClickHouse cluster.
@@ -156,13 +188,17 @@ func NewClickHouseSchemaClient(
 		datasources: make(map[string]string, 2),
 		done:        make(chan struct{}),
 		ready:       make(chan struct{}),
-		httpClient:  &http.Client{},
+		httpClient:  &http.Client{Transport: proxySvc.Transport()},
 	}
 }
 
 // Start initializes the client and starts background refresh.
 // The initial schema fetch runs asynchronously to avoid blocking server startup.
 func (c *clickhouseSchemaClient) Start(ctx context.Context) error {
+	if c.cfg.Offline {
+		return c.startOffline()
+	}
+
 	c.log.WithField("refresh_interval", c.cfg.RefreshInterval).Info("Starting ClickHouse schema client")
 
 	// Initialize proxy-backed datasource mappings.
@@ -210,6 +246,30 @@ func (c *clickhouseSchemaClient) Start(ctx context.Context) error {
 	return nil
 }
 
+// startOffline loads schemas from the snapshot without querying ClickHouse.
+// A missing snapshot leaves the client empty.
+func (c *clickhouseSchemaClient) startOffline() error {
+	defer close(c.ready)
+
+	var clusters map[string]*ClusterTables
+	if err := offline.LoadSnapshot(c.cfg.SnapshotPath, &clusters); err != nil {
+		c.log.WithError(err).Warn("No schema snapshot available, serving no tables (offline)")
+
+		return nil
+	}
+
+	c.mu.Lock()
+	c.clusters = clusters
+	c.mu.Unlock()
+
+	c.log.WithFields(logrus.Fields{
+		"cluster_count": len(clusters),
+		"snapshot":      c.cfg.SnapshotPath,
+	}).Info("ClickHouse schema client serving snapshot (offline)")
+
+	return nil
+}
+
 // initDatasources initializes proxy-backed datasource mappings.
 func (c *clickhouseSchemaClient) initDatasources() error {
 	if c.proxySvc == nil {
@@ -394,12 +454,65 @@ func (c *clickhouseSchemaClient) refresh(ctx context.Context) error {
 
 	// Atomic update.
 	c.mu.Lock()
+	changed := changedTables(c.clusters, newClusters)
 	c.clusters = newClusters
 	c.mu.Unlock()
 
+	if len(changed) > 0 && c.cfg.OnRefresh != nil {
+		c.cfg.OnRefresh(changed)
+	}
+
+	if c.cfg.SnapshotPath != "" && len(newClusters) > 0 {
+		if err := offline.SaveSnapshot(c.cfg.SnapshotPath, newClusters); err != nil {
+			c.log.WithError(err).Warn("Failed to save schema snapshot")
+		}
+	}
+
 	return nil
 }
 
+// changedTables returns the sorted names of tables whose schema differs
+// between old and updated in any cluster.
+func changedTables(old, updated map[string]*ClusterTables) []string {
+	changed := make(map[string]struct{}, 8)
+
+	diff := func(a, b map[string]*ClusterTables) {
+		for clusterName, cluster := range a {
+			var other map[string]*TableSchema
+			if prev, ok := b[clusterName]; ok {
+				other = prev.Tables
+			}
+
+			for name, schema := range cluster.Tables {
+				if prev, ok := other[name]; !ok || !sameSchema(prev, schema) {
+					changed[name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	diff(updated, old)
+	diff(old, updated)
+
+	names := make([]string, 0, len(changed))
+	for name := range changed {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// sameSchema reports whether two table schemas are equal, ignoring sample
+// rows, which differ between refreshes.
+func sameSchema(a, b *TableSchema) bool {
+	x, y := *a, *b
+	x.SampleRows, y.SampleRows = nil, nil
+
+	return reflect.DeepEqual(x, y)
+}
+
 // discoverClusterSchema discovers schema for a single cluster.
 func (c *clickhouseSchemaClient) discoverClusterSchema(
 	ctx context.Context,
@@ -455,6 +568,15 @@ func (c *clickhouseSchemaClient) discoverClusterSchema(
 				schema.Networks = dt.Networks
 			}
 
+			if c.cfg.SampleRows.Enabled {
+				rows, err := c.fetchSampleRows(ctx, datasourceName, token, dt.Database, schema)
+				if err != nil {
+					c.log.WithError(err).WithField("table", dt.Name).Debug("Failed to fetch sample rows")
+				}
+
+				schema.SampleRows = rows
+			}
+
 			mu.Lock()
 			clusterTables.Tables[dt.Name] = schema
 			mu.Unlock()
@@ -463,6 +585,8 @@ func (c *clickhouseSchemaClient) discoverClusterSchema(
 
 	wg.Wait()
 
+	linkLineage(clusterTables.Tables)
+
 	return clusterTables, nil
 }
 
@@ -535,6 +659,10 @@ func (c *clickhouseSchemaClient) queryJSON(ctx context.Context, datasourceName,
 	q.Set("default_format", "JSON")
 	req.URL.RawQuery = q.Encode()
 
+	if err := c.proxySvc.SignRequest(req); err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing query: %w", err)
@@ -829,6 +957,8 @@ outerLoop:
 		schema.Comment = matches[1]
 	}
 
+	parseTableClauses(schema, createStmt, suffix)
+
 	// Parse each column definition.
 	// Column format: `name` Type [DEFAULT expr] [CODEC(...)] [COMMENT 'comment'].
 
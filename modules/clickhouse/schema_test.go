@@ -67,3 +67,26 @@ func TestParseCreateTable_TableComment(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedTables(t *testing.T) {
+	old := map[string]*ClusterTables{
+		"xatu": {Tables: map[string]*TableSchema{
+			"blocks":  {Name: "blocks", Engine: "MergeTree"},
+			"removed": {Name: "removed"},
+			"same":    {Name: "same"},
+		}},
+	}
+
+	updated := map[string]*ClusterTables{
+		"xatu": {Tables: map[string]*TableSchema{
+			"blocks": {Name: "blocks", Engine: "ReplacingMergeTree"},
+			"same":   {Name: "same"},
+		}},
+		"xatu-cbt": {Tables: map[string]*TableSchema{
+			"same": {Name: "same"},
+		}},
+	}
+
+	assert.Equal(t, []string{"blocks", "removed", "same"}, changedTables(old, updated))
+	assert.Empty(t, changedTables(updated, updated))
+}
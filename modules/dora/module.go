@@ -4,13 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
+var (
+	_ module.HealthProber     = (*Module)(nil)
+	_ module.ResourceProvider = (*Module)(nil)
+	_ module.SchemaProvider   = (*Module)(nil)
+)
+
 // Module implements the module.Module interface for the Dora module.
 type Module struct {
 	cfg                 Config
@@ -31,6 +43,9 @@ func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
 // Dora is enabled by default since it requires no configuration.
 func (p *Module) DefaultEnabled() bool { return true }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 func (p *Module) Init(rawConfig []byte) error {
 	if len(rawConfig) == 0 {
 		// No config provided, use defaults (enabled = true).
@@ -56,21 +71,8 @@ func (p *Module) SandboxEnv() (map[string]string, error) {
 		return nil, nil
 	}
 
-	if p.cartographoorClient == nil {
-		// Cartographoor client not yet set - return empty.
-		// This will be populated after SetCartographoorClient is called.
-		return nil, nil
-	}
-
-	// Build network -> Dora URL mapping from cartographoor data.
-	networks := p.cartographoorClient.GetActiveNetworks()
-	doraNetworks := make(map[string]string, len(networks))
-
-	for name, network := range networks {
-		if network.ServiceURLs != nil && network.ServiceURLs.Dora != "" {
-			doraNetworks[name] = network.ServiceURLs.Dora
-		}
-	}
+	// Empty until SetCartographoorClient is called.
+	doraNetworks := p.networks()
 
 	if len(doraNetworks) == 0 {
 		return nil, nil
@@ -116,7 +118,7 @@ func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 			Functions: map[string]types.FunctionDoc{
 				"list_networks":        {Signature: "list_networks() -> list[dict]", Description: "List networks with Dora explorers"},
 				"get_base_url":         {Signature: "get_base_url(network) -> str", Description: "Get Dora base URL for a network"},
-				"get_network_overview": {Signature: "get_network_overview(network) -> dict", Description: "Get epoch, slot, validator counts"},
+				"get_network_overview": {Signature: "get_network_overview(network) -> dict", Description: "Get epoch, wall-clock slot, slot timing (seconds_per_slot, slots_per_epoch, genesis_time) and validator counts"},
 				"get_validator":        {Signature: "get_validator(network, index_or_pubkey) -> dict", Description: "Get validator by index or pubkey"},
 				"get_validators":       {Signature: "get_validators(network, status=None, limit=100) -> list", Description: "List validators with optional filter"},
 				"get_slot":             {Signature: "get_slot(network, slot_or_hash) -> dict", Description: "Get slot by number or hash"},
@@ -159,6 +161,40 @@ print(f"View in Dora: {link}")
 `
 }
 
+// RegisterResources registers the dora:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(
+		log.WithField("module", "dora"),
+		reg,
+		&http.Client{Timeout: requestTimeout},
+		p.networks,
+	)
+
+	return nil
+}
+
+// networks returns the network -> Dora URL mapping from cartographoor.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.Dora != "" {
+			networks[name] = network.ServiceURLs.Dora
+		}
+	}
+
+	return networks
+}
+
 // SetCartographoorClient implements module.CartographoorAware.
 // This is called by the builder to inject the cartographoor client.
 func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
@@ -168,3 +204,39 @@ func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient
 func (p *Module) Start(_ context.Context) error { return nil }
 
 func (p *Module) Stop(_ context.Context) error { return nil }
+
+// ProbeHealth requests the head epoch from each network's Dora instance.
+func (p *Module) ProbeHealth(ctx context.Context) []types.HealthProbe {
+	if !p.cfg.IsEnabled() || p.cartographoorClient == nil {
+		return nil
+	}
+
+	networks := p.cartographoorClient.GetActiveNetworks()
+	names := make([]string, 0, len(networks))
+
+	for name, network := range networks {
+		if network.ServiceURLs != nil && network.ServiceURLs.Dora != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	probes := make([]types.HealthProbe, len(names))
+
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		wg.Add(1)
+
+		go func(i int, name, baseURL string) {
+			defer wg.Done()
+
+			probes[i] = module.ProbeURL(ctx, name, strings.TrimRight(baseURL, "/")+"/api/v1/epoch/head")
+		}(i, name, networks[name].ServiceURLs.Dora)
+	}
+
+	wg.Wait()
+
+	return probes
+}
@@ -0,0 +1,206 @@
+package dora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// requestTimeout bounds a single Dora call made for a resource read.
+const requestTimeout = 30 * time.Second
+
+var (
+	// slotURIPattern matches dora://network/{name}/slot/{slot} URIs, where
+	// slot is a slot number or block root.
+	slotURIPattern = regexp.MustCompile(`^dora://network/([^/]+)/slot/(\d+|0x[0-9a-fA-F]{64})$`)
+
+	// epochURIPattern matches dora://network/{name}/epoch/{epoch} URIs.
+	epochURIPattern = regexp.MustCompile(`^dora://network/([^/]+)/epoch/(\d+|head|latest)$`)
+)
+
+// SlotResponse is the response for dora://network/{name}/slot/{slot}.
+type SlotResponse struct {
+	Network string          `json:"network"`
+	Link    string          `json:"link"`
+	Slot    json.RawMessage `json:"slot"`
+}
+
+// EpochResponse is the response for dora://network/{name}/epoch/{epoch}.
+type EpochResponse struct {
+	Network string          `json:"network"`
+	Link    string          `json:"link"`
+	Epoch   json.RawMessage `json:"epoch"`
+}
+
+// RegisterNetworkResources registers the dora:// resources. networks returns
+// the current network -> Dora URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"dora://network/{name}/slot/{slot}",
+			"Dora Slot",
+			mcp.WithTemplateDescription("A slot by number or block root: proposer, block status, execution block and included operations, from the network's Dora explorer"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: slotURIPattern,
+		Handler: createSlotHandler(httpClient, networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"dora://network/{name}/epoch/{epoch}",
+			"Dora Epoch",
+			mcp.WithTemplateDescription("An epoch by number, or head: finalization, participation, proposed and missed blocks and validator counts, from the network's Dora explorer"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: epochURIPattern,
+		Handler: createEpochHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered Dora resources")
+}
+
+func createSlotHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, slot, err := resolveNetwork(slotURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := fetchData(ctx, httpClient, baseURL, "/api/v1/slot/"+slot)
+		if err != nil {
+			return "", err
+		}
+
+		return marshal(&SlotResponse{
+			Network: network,
+			Link:    strings.TrimRight(baseURL, "/") + "/slot/" + slot,
+			Slot:    data,
+		})
+	}
+}
+
+func createEpochHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		network, baseURL, epoch, err := resolveNetwork(epochURIPattern, uri, networks)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := fetchData(ctx, httpClient, baseURL, "/api/v1/epoch/"+epoch)
+		if err != nil {
+			return "", err
+		}
+
+		link := strings.TrimRight(baseURL, "/") + "/epoch/" + epoch
+		if epoch == "head" || epoch == "latest" {
+			link = strings.TrimRight(baseURL, "/") + "/epochs"
+		}
+
+		return marshal(&EpochResponse{Network: network, Link: link, Epoch: data})
+	}
+}
+
+// resolveNetwork extracts the network and identifier from uri and looks up
+// the network's Dora URL.
+func resolveNetwork(
+	pattern *regexp.Regexp,
+	uri string,
+	networks func() map[string]string,
+) (string, string, string, error) {
+	matches := pattern.FindStringSubmatch(uri)
+	if len(matches) != 3 {
+		return "", "", "", fmt.Errorf("invalid Dora URI: %s", uri)
+	}
+
+	network := matches[1]
+	instances := networks()
+
+	baseURL, ok := instances[network]
+	if !ok {
+		names := make([]string, 0, len(instances))
+		for name := range instances {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", "", "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return network, baseURL, matches[2], nil
+}
+
+// fetchData reads a Dora API endpoint and returns the contents of its
+// "data" envelope.
+func fetchData(ctx context.Context, httpClient *http.Client, baseURL, path string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating Dora request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting Dora %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading Dora %s: %w", path, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dora %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding Dora %s: %w", path, err)
+	}
+
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		if envelope.Status != "" && envelope.Status != "OK" {
+			return nil, fmt.Errorf("dora %s: %s", path, envelope.Status)
+		}
+
+		return nil, fmt.Errorf("dora %s returned no data", path)
+	}
+
+	return envelope.Data, nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling Dora response: %w", err)
+	}
+
+	return string(data), nil
+}
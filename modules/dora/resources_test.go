@@ -0,0 +1,73 @@
+package dora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/slot/123", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"status":"OK","data":{"slot":123,"proposer":42,"status":"Proposed"}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL + "/"} }
+
+	out, err := createSlotHandler(srv.Client(), networks)(context.Background(), "dora://network/hoodi/slot/123")
+	require.NoError(t, err)
+
+	var resp SlotResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "hoodi", resp.Network)
+	assert.Equal(t, srv.URL+"/slot/123", resp.Link)
+	assert.JSONEq(t, `{"slot":123,"proposer":42,"status":"Proposed"}`, string(resp.Slot))
+}
+
+func TestEpochHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/epoch/head", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"status":"OK","data":{"epoch":900,"finalized":false}}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createEpochHandler(srv.Client(), networks)(context.Background(), "dora://network/hoodi/epoch/head")
+	require.NoError(t, err)
+
+	var resp EpochResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, srv.URL+"/epochs", resp.Link)
+	assert.JSONEq(t, `{"epoch":900,"finalized":false}`, string(resp.Epoch))
+}
+
+func TestSlotHandlerErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ERROR: slot not found","data":null}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+	handler := createSlotHandler(srv.Client(), networks)
+
+	_, err := handler(context.Background(), "dora://network/hoodi/slot/999999999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slot not found")
+
+	_, err = handler(context.Background(), "dora://network/mainnet/slot/1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "mainnet"`)
+
+	_, err = handler(context.Background(), "dora://network/hoodi/slot/abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Dora URI")
+}
@@ -35,6 +35,9 @@ func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 // Enabled reports whether ethnode operations should be exposed.
 func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 func (p *Module) Init(rawConfig []byte) error {
 	if len(rawConfig) == 0 {
 		return nil
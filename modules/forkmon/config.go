@@ -0,0 +1,19 @@
+package forkmon
+
+// Config holds the forkmon module configuration.
+// The module is enabled by default since forkmon instances
+// are public and require no credentials.
+type Config struct {
+	// Enabled controls whether the forkmon module is active.
+	// Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+// IsEnabled returns true if the module is enabled (default: true).
+func (c *Config) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
@@ -0,0 +1,178 @@
+package forkmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Module implements the module.Module interface for the forkmon module.
+type Module struct {
+	cfg                 Config
+	cartographoorClient cartographoor.CartographoorClient
+}
+
+// New creates a new forkmon module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "forkmon" }
+
+// Enabled reports whether forkmon operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// Forkmon is enabled by default since it requires no configuration.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		// No config provided, use defaults (enabled = true).
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// No validation needed - config is minimal.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_FORKMON_NETWORKS with network->URL mapping from cartographoor.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	networks := p.networks()
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling forkmon networks: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_FORKMON_NETWORKS": string(networksJSON),
+	}, nil
+}
+
+// DatasourceInfo returns empty since networks are the datasources,
+// and those come from cartographoor.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	for k, v := range queryExamples {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"forkmon": {
+			Description: "Inspect node heads reported by forkmon to spot forks and reorgs, and generate deep links",
+			Functions: map[string]types.FunctionDoc{
+				"list_networks": {Signature: "list_networks() -> list[dict]", Description: "List networks with a forkmon instance"},
+				"get_base_url":  {Signature: "get_base_url(network) -> str", Description: "Get forkmon base URL for a network"},
+				"get_state":     {Signature: "get_state(network) -> Any", Description: "Get the current head reported by each monitored node"},
+				"link_overview": {Signature: "link_overview(network) -> str", Description: "Deep link to the forkmon fork tree"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Forkmon Fork Monitor
+
+Forkmon runs on devnets and tracks the head each monitored node follows.
+Nodes reporting different heads at the same height indicate a fork.
+
+` + "```python" + `
+from ethpandaops import forkmon
+
+state = forkmon.get_state("my-devnet-1")
+print(forkmon.link_overview("my-devnet-1"))
+` + "```" + `
+`
+}
+
+// RegisterResources registers the forkmon:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	RegisterNetworkResources(
+		log.WithField("module", "forkmon"),
+		reg,
+		&http.Client{Timeout: requestTimeout},
+		p.networks,
+	)
+
+	return nil
+}
+
+// SetCartographoorClient implements module.CartographoorAware.
+// This is called by the builder to inject the cartographoor client.
+func (p *Module) SetCartographoorClient(client cartographoor.CartographoorClient) {
+	p.cartographoorClient = client
+}
+
+// networks returns the network -> forkmon URL mapping from cartographoor.
+func (p *Module) networks() map[string]string {
+	if p.cartographoorClient == nil {
+		return nil
+	}
+
+	active := p.cartographoorClient.GetActiveNetworks()
+	networks := make(map[string]string, len(active))
+
+	for name, network := range active {
+		if network.ServiceURLs != nil && network.ServiceURLs.Forkmon != "" {
+			networks[name] = network.ServiceURLs.Forkmon
+		}
+	}
+
+	return networks
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
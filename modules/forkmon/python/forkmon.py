@@ -0,0 +1,36 @@
+"""Thin forkmon wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_forkmon_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_FORKMON_NETWORKS", "").strip():
+        raise ValueError("Forkmon is not enabled or no forkmon instances are available.")
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_forkmon_available()
+    data = _runtime.invoke_data("forkmon.list_networks")
+    return data.get("networks", [])
+
+
+def get_base_url(network: str) -> str:
+    _require_forkmon_available()
+    data = _runtime.invoke_data("forkmon.get_base_url", {"network": network})
+    return data.get("base_url", "")
+
+
+def get_state(network: str) -> Any:
+    _require_forkmon_available()
+    return _runtime.invoke_json("forkmon.get_state", {"network": network})
+
+
+def link_overview(network: str) -> str:
+    _require_forkmon_available()
+    data = _runtime.invoke_data("forkmon.link_overview", {"network": network})
+    return data.get("url", "")
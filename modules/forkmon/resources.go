@@ -0,0 +1,184 @@
+package forkmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// statePath is the forkmon endpoint serving the heads reported by each
+	// monitored node, which the web UI renders as a fork tree.
+	statePath = "/state"
+
+	// requestTimeout bounds a single forkmon call made for a resource read.
+	requestTimeout = 30 * time.Second
+)
+
+// stateURIPattern matches forkmon://networks/{network}/state URIs.
+var stateURIPattern = regexp.MustCompile(`^forkmon://networks/([^/]+)/state$`)
+
+// NetworkInstance is a network with a forkmon instance.
+type NetworkInstance struct {
+	Name       string `json:"name"`
+	ForkmonURL string `json:"forkmon_url"`
+}
+
+// NetworksListResponse is the response for forkmon://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkInstance `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// StateResponse is the response for forkmon://networks/{network}/state.
+type StateResponse struct {
+	Network    string          `json:"network"`
+	ForkmonURL string          `json:"forkmon_url"`
+	State      json.RawMessage `json:"state"`
+}
+
+// RegisterNetworkResources registers the forkmon:// resources. networks
+// returns the current network -> forkmon URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"forkmon://networks",
+			"Forkmon Instances",
+			mcp.WithResourceDescription("Networks with a forkmon instance monitoring node heads for forks and reorgs"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"forkmon://networks/{network}/state",
+			"Forkmon State",
+			mcp.WithTemplateDescription("Current head reported by each node forkmon monitors on a network"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: stateURIPattern,
+		Handler: createStateHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered forkmon resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		instances := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with a forkmon instance.",
+			Networks:    make([]NetworkInstance, 0, len(instances)),
+			Usage:       "Read forkmon://networks/{network}/state for the heads of the monitored nodes.",
+		}
+
+		for name, forkmonURL := range instances {
+			response.Networks = append(response.Networks, NetworkInstance{Name: name, ForkmonURL: forkmonURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling forkmon networks: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+func createStateHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := stateURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid forkmon URI: %s", uri)
+		}
+
+		network := matches[1]
+		instances := networks()
+
+		baseURL, ok := instances[network]
+		if !ok {
+			names := make([]string, 0, len(instances))
+			for name := range instances {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+		}
+
+		state, err := fetchState(ctx, httpClient, baseURL)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(&StateResponse{
+			Network:    network,
+			ForkmonURL: baseURL,
+			State:      state,
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling forkmon state: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// fetchState reads the forkmon state endpoint and checks it is valid JSON.
+func fetchState(ctx context.Context, httpClient *http.Client, baseURL string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+statePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating forkmon request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting forkmon state: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading forkmon state: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("forkmon state returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("forkmon state is not valid JSON")
+	}
+
+	return body, nil
+}
@@ -0,0 +1,52 @@
+package forkmon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, statePath, r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"nodes":[{"name":"geth-1","hash":"0xaa"}]}`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"devnet-1": srv.URL} }
+
+	out, err := createStateHandler(srv.Client(), networks)(context.Background(), "forkmon://networks/devnet-1/state")
+	require.NoError(t, err)
+
+	var resp StateResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "devnet-1", resp.Network)
+	assert.JSONEq(t, `{"nodes":[{"name":"geth-1","hash":"0xaa"}]}`, string(resp.State))
+}
+
+func TestStateHandlerRejectsInvalidJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<html></html>`))
+	}))
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"devnet-1": srv.URL} }
+
+	_, err := createStateHandler(srv.Client(), networks)(context.Background(), "forkmon://networks/devnet-1/state")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid JSON")
+}
+
+func TestStateHandlerUnknownNetwork(t *testing.T) {
+	networks := func() map[string]string { return map[string]string{"devnet-1": "http://unused"} }
+
+	_, err := createStateHandler(http.DefaultClient, networks)(context.Background(), "forkmon://networks/mainnet/state")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "mainnet"`)
+}
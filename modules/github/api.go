@@ -0,0 +1,328 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// maxSearchResults caps the issues and pull requests a search returns.
+	maxSearchResults = 100
+
+	// maxReleases caps the releases listed per repository.
+	maxReleases = 100
+
+	// versionLookback is the number of recent releases scanned for the latest
+	// stable release and any newer prerelease.
+	versionLookback = 20
+)
+
+// Fetcher issues a GET against the GitHub REST API through the proxy and
+// decodes the JSON response into out. path is relative to the API root.
+type Fetcher func(ctx context.Context, path string, params url.Values, out any) error
+
+// Release is a published GitHub release. Notes is the release body in
+// Markdown and is only set when requested.
+type Release struct {
+	Repo        string `json:"repo"`
+	Tag         string `json:"tag"`
+	Name        string `json:"name,omitempty"`
+	PublishedAt string `json:"published_at"`
+	Prerelease  bool   `json:"prerelease,omitempty"`
+	URL         string `json:"url"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// Issue is an issue or pull request matched by a search.
+type Issue struct {
+	Repo        string   `json:"repo"`
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	State       string   `json:"state"`
+	PullRequest bool     `json:"pull_request"`
+	Author      string   `json:"author,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Comments    int      `json:"comments"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	ClosedAt    string   `json:"closed_at,omitempty"`
+	URL         string   `json:"url"`
+}
+
+// SearchQuery is an issue search scoped to configured repositories.
+type SearchQuery struct {
+	// Text is free text and GitHub search qualifiers, e.g. "sync label:bug".
+	Text  string
+	Repos []string
+	// State is "open" or "closed"; empty matches both.
+	State string
+	// Kind is "issue" or "pr"; empty matches both.
+	Kind  string
+	Limit int
+}
+
+// String returns the GitHub search syntax of the query.
+func (q SearchQuery) String() string {
+	terms := make([]string, 0, len(q.Repos)+3)
+
+	if text := strings.TrimSpace(q.Text); text != "" {
+		terms = append(terms, text)
+	}
+
+	if q.Kind != "" {
+		terms = append(terms, "is:"+q.Kind)
+	}
+
+	if q.State != "" {
+		terms = append(terms, "state:"+q.State)
+	}
+
+	for _, repo := range q.Repos {
+		terms = append(terms, "repo:"+repo)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// SearchResult is the response of an issue search.
+type SearchResult struct {
+	Query      string  `json:"query"`
+	TotalCount int     `json:"total_count"`
+	Items      []Issue `json:"items"`
+}
+
+// ClientVersion is the latest release of a client repository.
+type ClientVersion struct {
+	Repo   string   `json:"repo"`
+	Client string   `json:"client,omitempty"`
+	Latest *Release `json:"latest,omitempty"`
+	// LatestPrerelease is set when a prerelease is newer than Latest.
+	LatestPrerelease *Release `json:"latest_prerelease,omitempty"`
+}
+
+// VersionsReport is the latest release of every configured repository.
+type VersionsReport struct {
+	Clients []ClientVersion   `json:"clients"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+type apiRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+	HTMLURL     string `json:"html_url"`
+}
+
+func (r apiRelease) release(repo string, notes bool) Release {
+	release := Release{
+		Repo:        repo,
+		Tag:         r.TagName,
+		Name:        r.Name,
+		PublishedAt: r.PublishedAt,
+		Prerelease:  r.Prerelease,
+		URL:         r.HTMLURL,
+	}
+
+	if notes {
+		release.Notes = r.Body
+	}
+
+	return release
+}
+
+type apiIssue struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	State         string `json:"state"`
+	Comments      int    `json:"comments"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	ClosedAt      string `json:"closed_at"`
+	HTMLURL       string `json:"html_url"`
+	RepositoryURL string `json:"repository_url"`
+	User          struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// repoPath returns the API path of a repository.
+func repoPath(repo string) string {
+	owner, name, _ := strings.Cut(repo, "/")
+
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+// ListReleases returns the newest releases of a repository, excluding
+// drafts. Release notes are included when notes is set.
+func ListReleases(ctx context.Context, fetch Fetcher, repo string, limit int, notes bool) ([]Release, error) {
+	if limit <= 0 || limit > maxReleases {
+		limit = maxReleases
+	}
+
+	var raw []apiRelease
+
+	params := url.Values{"per_page": {strconv.Itoa(limit)}}
+	if err := fetch(ctx, repoPath(repo)+"/releases", params, &raw); err != nil {
+		return nil, fmt.Errorf("listing releases of %s: %w", repo, err)
+	}
+
+	releases := make([]Release, 0, len(raw))
+
+	for _, r := range raw {
+		if r.Draft {
+			continue
+		}
+
+		releases = append(releases, r.release(repo, notes))
+	}
+
+	return releases, nil
+}
+
+// GetRelease returns a release with its notes. tag "latest" selects the
+// latest stable release.
+func GetRelease(ctx context.Context, fetch Fetcher, repo, tag string) (*Release, error) {
+	path := repoPath(repo) + "/releases/latest"
+	if tag != "latest" {
+		path = repoPath(repo) + "/releases/tags/" + url.PathEscape(tag)
+	}
+
+	var raw apiRelease
+	if err := fetch(ctx, path, nil, &raw); err != nil {
+		return nil, fmt.Errorf("getting release %s of %s: %w", tag, repo, err)
+	}
+
+	release := raw.release(repo, true)
+
+	return &release, nil
+}
+
+// SearchIssues searches issues and pull requests of the query's
+// repositories, most recently updated first.
+func SearchIssues(ctx context.Context, fetch Fetcher, query SearchQuery) (*SearchResult, error) {
+	if len(query.Repos) == 0 {
+		return nil, fmt.Errorf("at least one repository is required")
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+
+	q := query.String()
+
+	var raw struct {
+		TotalCount int        `json:"total_count"`
+		Items      []apiIssue `json:"items"`
+	}
+
+	params := url.Values{
+		"q":        {q},
+		"sort":     {"updated"},
+		"order":    {"desc"},
+		"per_page": {strconv.Itoa(limit)},
+	}
+
+	if err := fetch(ctx, "/search/issues", params, &raw); err != nil {
+		return nil, fmt.Errorf("searching issues: %w", err)
+	}
+
+	result := &SearchResult{Query: q, TotalCount: raw.TotalCount, Items: make([]Issue, 0, len(raw.Items))}
+
+	for _, item := range raw.Items {
+		issue := Issue{
+			Repo:        repoFromURL(item.RepositoryURL),
+			Number:      item.Number,
+			Title:       item.Title,
+			State:       item.State,
+			PullRequest: item.PullRequest != nil,
+			Author:      item.User.Login,
+			Comments:    item.Comments,
+			CreatedAt:   item.CreatedAt,
+			UpdatedAt:   item.UpdatedAt,
+			ClosedAt:    item.ClosedAt,
+			URL:         item.HTMLURL,
+		}
+
+		for _, label := range item.Labels {
+			issue.Labels = append(issue.Labels, label.Name)
+		}
+
+		result.Items = append(result.Items, issue)
+	}
+
+	return result, nil
+}
+
+// ClientVersions returns the latest stable release of each repository, and
+// the latest prerelease where one is newer. Repositories whose releases
+// cannot be read are reported in Errors.
+func ClientVersions(ctx context.Context, fetch Fetcher, repos []types.DatasourceInfo) *VersionsReport {
+	report := &VersionsReport{Clients: make([]ClientVersion, 0, len(repos))}
+
+	for _, repo := range repos {
+		releases, err := ListReleases(ctx, fetch, repo.Name, versionLookback, false)
+		if err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string, 1)
+			}
+
+			report.Errors[repo.Name] = err.Error()
+
+			continue
+		}
+
+		report.Clients = append(report.Clients, latestVersions(repo, releases))
+	}
+
+	sort.Slice(report.Clients, func(i, j int) bool {
+		return report.Clients[i].Repo < report.Clients[j].Repo
+	})
+
+	return report
+}
+
+// latestVersions picks the latest stable release and any newer prerelease
+// from releases, which are newest first.
+func latestVersions(repo types.DatasourceInfo, releases []Release) ClientVersion {
+	version := ClientVersion{Repo: repo.Name, Client: repo.Metadata["client"]}
+
+	for i := range releases {
+		release := releases[i]
+
+		if !release.Prerelease {
+			version.Latest = &release
+
+			break
+		}
+
+		if version.LatestPrerelease == nil {
+			version.LatestPrerelease = &release
+		}
+	}
+
+	return version
+}
+
+// repoFromURL returns "owner/repo" from an API repository URL.
+func repoFromURL(repositoryURL string) string {
+	_, repo, ok := strings.Cut(repositoryURL, "/repos/")
+	if !ok {
+		return ""
+	}
+
+	return repo
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// fakeFetcher serves canned JSON bodies by path and records the requests.
+type fakeFetcher struct {
+	bodies   map[string]string
+	requests []string
+}
+
+func (f *fakeFetcher) fetch(_ context.Context, path string, params url.Values, out any) error {
+	f.requests = append(f.requests, path+"?"+params.Encode())
+
+	body, ok := f.bodies[path]
+	if !ok {
+		return errors.New("not found")
+	}
+
+	return json.Unmarshal([]byte(body), out)
+}
+
+func TestSearchQueryString(t *testing.T) {
+	q := SearchQuery{
+		Text:  " sync label:bug ",
+		Repos: []string{"sigp/lighthouse", "ethereum/go-ethereum"},
+		State: "open",
+		Kind:  "pr",
+	}
+
+	assert.Equal(t, "sync label:bug is:pr state:open repo:sigp/lighthouse repo:ethereum/go-ethereum", q.String())
+	assert.Equal(t, "repo:sigp/lighthouse", SearchQuery{Repos: []string{"sigp/lighthouse"}}.String())
+}
+
+func TestSearchIssues(t *testing.T) {
+	f := &fakeFetcher{bodies: map[string]string{
+		"/search/issues": `{"total_count": 42, "items": [
+			{"number": 7, "title": "Missed attestations after upgrade", "state": "open", "comments": 3,
+			 "created_at": "2026-10-01T00:00:00Z", "updated_at": "2026-10-02T00:00:00Z",
+			 "html_url": "https://github.com/sigp/lighthouse/issues/7",
+			 "repository_url": "https://api.github.com/repos/sigp/lighthouse",
+			 "user": {"login": "alice"}, "labels": [{"name": "bug"}]},
+			{"number": 8, "title": "Fix attestation packing", "state": "closed", "comments": 0,
+			 "repository_url": "https://api.github.com/repos/sigp/lighthouse",
+			 "pull_request": {}}
+		]}`,
+	}}
+
+	result, err := SearchIssues(context.Background(), f.fetch, SearchQuery{
+		Text:  "attestation",
+		Repos: []string{"sigp/lighthouse"},
+		Limit: 500,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "attestation repo:sigp/lighthouse", result.Query)
+	assert.Equal(t, 42, result.TotalCount)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, "sigp/lighthouse", result.Items[0].Repo)
+	assert.Equal(t, []string{"bug"}, result.Items[0].Labels)
+	assert.Equal(t, "alice", result.Items[0].Author)
+	assert.False(t, result.Items[0].PullRequest)
+	assert.True(t, result.Items[1].PullRequest)
+	assert.Contains(t, f.requests[0], "per_page=100")
+
+	_, err = SearchIssues(context.Background(), f.fetch, SearchQuery{Text: "attestation"})
+	require.Error(t, err)
+}
+
+func TestClientVersions(t *testing.T) {
+	f := &fakeFetcher{bodies: map[string]string{
+		"/repos/sigp/lighthouse/releases": `[
+			{"tag_name": "v8.0.0-rc.1", "prerelease": true, "body": "rc notes"},
+			{"tag_name": "v7.2.0-draft", "draft": true},
+			{"tag_name": "v7.1.0", "name": "Lighthouse v7.1.0", "body": "notes"},
+			{"tag_name": "v7.0.0"}
+		]`,
+		"/repos/ethereum/go-ethereum/releases": `[{"tag_name": "v1.16.0"}]`,
+	}}
+
+	report := ClientVersions(context.Background(), f.fetch, []types.DatasourceInfo{
+		{Type: "github", Name: "sigp/lighthouse", Metadata: map[string]string{"client": "lighthouse"}},
+		{Type: "github", Name: "ethereum/go-ethereum"},
+		{Type: "github", Name: "missing/repo"},
+	})
+
+	require.Len(t, report.Clients, 2)
+	assert.Equal(t, "ethereum/go-ethereum", report.Clients[0].Repo)
+	assert.Equal(t, "v1.16.0", report.Clients[0].Latest.Tag)
+	assert.Nil(t, report.Clients[0].LatestPrerelease)
+
+	lighthouse := report.Clients[1]
+	assert.Equal(t, "lighthouse", lighthouse.Client)
+	require.NotNil(t, lighthouse.Latest)
+	assert.Equal(t, "v7.1.0", lighthouse.Latest.Tag)
+	assert.Empty(t, lighthouse.Latest.Notes)
+	require.NotNil(t, lighthouse.LatestPrerelease)
+	assert.Equal(t, "v8.0.0-rc.1", lighthouse.LatestPrerelease.Tag)
+
+	assert.Contains(t, report.Errors, "missing/repo")
+}
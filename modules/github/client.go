@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+)
+
+const (
+	// requestTimeout bounds a single GitHub API call made for a resource read.
+	requestTimeout = 30 * time.Second
+
+	// tokenID identifies the proxy token used for resource reads.
+	tokenID = "github-resources"
+)
+
+// client reads the GitHub REST API through the credential proxy.
+type client struct {
+	proxySvc   proxy.Service
+	httpClient *http.Client
+}
+
+func newClient(proxySvc proxy.Service) *client {
+	return &client{
+		proxySvc:   proxySvc,
+		httpClient: &http.Client{Transport: proxySvc.Transport(), Timeout: requestTimeout},
+	}
+}
+
+// getJSON is a Fetcher over the proxy's /github route.
+func (c *client) getJSON(ctx context.Context, path string, params url.Values, out any) error {
+	baseURL := strings.TrimRight(c.proxySvc.URL(), "/")
+	if baseURL == "" {
+		return fmt.Errorf("proxy URL is empty")
+	}
+
+	requestURL := baseURL + "/github" + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	token := c.proxySvc.RegisterToken(tokenID)
+	defer c.proxySvc.RevokeToken(tokenID)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := c.proxySvc.SignRequest(req); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("github %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	return nil
+}
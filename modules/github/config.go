@@ -0,0 +1,15 @@
+package github
+
+// Config holds the GitHub module configuration.
+type Config struct {
+	Repos []RepoConfig `yaml:"repos"`
+}
+
+// RepoConfig describes a repository readable through the proxy.
+type RepoConfig struct {
+	// Name is the "owner/repo" full name.
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Client is the Ethereum client the repository releases, e.g. "geth".
+	Client string `yaml:"client,omitempty" json:"client,omitempty"`
+}
@@ -0,0 +1,238 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Compile-time interface checks.
+var (
+	_ module.Module            = (*Module)(nil)
+	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.ProxyAware        = (*Module)(nil)
+	_ module.ResourceProvider  = (*Module)(nil)
+	_ module.SchemaProvider    = (*Module)(nil)
+)
+
+// Module implements the module.Module interface for GitHub repositories
+// readable through the proxy.
+type Module struct {
+	cfg         Config
+	datasources []types.DatasourceInfo
+	proxySvc    proxy.Service
+}
+
+// New creates a new GitHub module.
+func New() *Module { return &Module{} }
+
+func (p *Module) Name() string { return "github" }
+
+// SetProxyClient injects the proxy service used by the github:// resources.
+func (p *Module) SetProxyClient(client proxy.Service) {
+	p.proxySvc = client
+}
+
+// InitFromDiscovery initializes the module from discovered repositories.
+func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
+	var filtered []types.DatasourceInfo
+
+	for _, ds := range datasources {
+		if ds.Type != "github" {
+			continue
+		}
+
+		filtered = append(filtered, ds)
+	}
+
+	if len(filtered) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	p.datasources = filtered
+
+	return nil
+}
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+// Init parses the raw YAML config for this module.
+func (p *Module) Init(rawConfig []byte) error {
+	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
+		return err
+	}
+
+	// Drop unnamed repositories.
+	validRepos := make([]RepoConfig, 0, len(p.cfg.Repos))
+	for _, repo := range p.cfg.Repos {
+		if repo.Name != "" {
+			validRepos = append(validRepos, repo)
+		}
+	}
+
+	p.cfg.Repos = validRepos
+
+	if len(p.cfg.Repos) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	// Populate internal datasources from config.
+	p.datasources = make([]types.DatasourceInfo, 0, len(p.cfg.Repos))
+	for _, repo := range p.cfg.Repos {
+		info := types.DatasourceInfo{
+			Type:        "github",
+			Name:        repo.Name,
+			Description: repo.Description,
+		}
+		if repo.Client != "" {
+			info.Metadata = map[string]string{"client": repo.Client}
+		}
+
+		p.datasources = append(p.datasources, info)
+	}
+
+	return nil
+}
+
+// ApplyDefaults sets default values before validation.
+func (p *Module) ApplyDefaults() {}
+
+// Validate checks that the parsed config is valid.
+func (p *Module) Validate() error {
+	names := make(map[string]struct{}, len(p.datasources))
+	for i, ds := range p.datasources {
+		owner, name, ok := strings.Cut(ds.Name, "/")
+		if !ok || owner == "" || name == "" {
+			return fmt.Errorf("repos[%d].name must be \"owner/repo\", got %q", i, ds.Name)
+		}
+
+		key := strings.ToLower(ds.Name)
+		if _, exists := names[key]; exists {
+			return fmt.Errorf("repos[%d].name %q is duplicated", i, ds.Name)
+		}
+
+		names[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if len(p.datasources) == 0 {
+		return nil, nil
+	}
+
+	infos := make([]RepoConfig, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		infos = append(infos, RepoConfig{
+			Name:        ds.Name,
+			Description: ds.Description,
+			Client:      ds.Metadata["client"],
+		})
+	}
+
+	infosJSON, err := json.Marshal(infos)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling GitHub repo info: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_GITHUB_REPOS": string(infosJSON),
+	}, nil
+}
+
+// DatasourceInfo returns datasource metadata for datasources:// resources.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	result := make([]types.DatasourceInfo, len(p.datasources))
+	copy(result, p.datasources)
+
+	return result
+}
+
+// Examples returns query examples for the GitHub module.
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	maps.Copy(result, queryExamples)
+
+	return result
+}
+
+// PythonAPIDocs returns the GitHub module documentation.
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	return map[string]types.ModuleDoc{
+		"github": {
+			Description: "Search issues and pull requests, read release notes and check client release versions of configured GitHub repositories",
+			Functions: map[string]types.FunctionDoc{
+				"list_repos": {
+					Signature:   "github.list_repos() -> list[dict]",
+					Description: "List the repositories readable through the proxy. Prefer datasources://github resource.",
+					Returns:     "List of dicts with 'name', 'description', 'client' keys",
+				},
+				"search_issues": {
+					Signature:   "github.search_issues(query: str = '', repos: list[str] = None, state: str = None, kind: str = None, limit: int = 30) -> dict",
+					Description: "Search issues and pull requests, most recently updated first",
+					Parameters: map[string]string{
+						"query": "Free text and GitHub search qualifiers, e.g. 'sync label:bug' or 'is:merged'",
+						"repos": "Optional: 'owner/repo' names (default: all configured repositories)",
+						"state": "Optional: 'open' or 'closed'",
+						"kind":  "Optional: 'issue' or 'pr'",
+						"limit": "Max results (default: 30, max: 100)",
+					},
+					Returns: "Dict with 'query', 'total_count' and 'items' (repo, number, title, state, pull_request, labels, url, created_at, updated_at, closed_at)",
+				},
+				"list_releases": {
+					Signature:   "github.list_releases(repo: str, limit: int = 10, notes: bool = False) -> list[dict]",
+					Description: "List the newest releases of a repository, excluding drafts",
+					Parameters: map[string]string{
+						"repo":  "'owner/repo' name from datasources://github",
+						"limit": "Max releases (default: 10, max: 100)",
+						"notes": "Include release notes (default: False)",
+					},
+					Returns: "List of dicts with 'tag', 'name', 'published_at', 'prerelease', 'url' and optionally 'notes'",
+				},
+				"get_release": {
+					Signature:   "github.get_release(repo: str, tag: str = 'latest') -> dict",
+					Description: "Get a release with its notes",
+					Parameters: map[string]string{
+						"repo": "'owner/repo' name",
+						"tag":  "Release tag, or 'latest' for the latest stable release",
+					},
+					Returns: "Dict with 'tag', 'name', 'published_at', 'prerelease', 'url', 'notes'",
+				},
+				"client_versions": {
+					Signature:   "github.client_versions() -> dict",
+					Description: "Latest stable release of every configured repository, and any newer prerelease. Same data as github://releases.",
+					Returns:     "Dict with 'clients' (repo, client, latest, latest_prerelease) and 'errors' for unreadable repositories",
+				},
+			},
+		},
+	}
+}
+
+// RegisterResources registers the github:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	RegisterReleaseResources(log.WithField("module", "github"), reg, newClient(p.proxySvc).getJSON, p.DatasourceInfo())
+
+	return nil
+}
+
+// Start performs async initialization.
+func (p *Module) Start(_ context.Context) error { return nil }
+
+// Stop cleans up resources.
+func (p *Module) Stop(_ context.Context) error { return nil }
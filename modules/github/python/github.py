@@ -0,0 +1,47 @@
+"""Thin GitHub wrappers over server operations."""
+
+from __future__ import annotations
+
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def list_repos() -> list[dict[str, Any]]:
+    data = _runtime.invoke_data("github.list_repos")
+    return data.get("repos", [])
+
+
+def search_issues(
+    query: str = "",
+    repos: list[str] | None = None,
+    state: str | None = None,
+    kind: str | None = None,
+    limit: int = 30,
+) -> dict[str, Any]:
+    return _runtime.invoke_data(
+        "github.search_issues",
+        {
+            "query": query,
+            "repos": repos,
+            "state": state,
+            "kind": kind,
+            "limit": limit,
+        },
+    )
+
+
+def list_releases(repo: str, limit: int = 10, notes: bool = False) -> list[dict[str, Any]]:
+    data = _runtime.invoke_data(
+        "github.list_releases",
+        {"repo": repo, "limit": limit, "notes": notes},
+    )
+    return data.get("releases", [])
+
+
+def get_release(repo: str, tag: str = "latest") -> dict[str, Any]:
+    return _runtime.invoke_data("github.get_release", {"repo": repo, "tag": tag})
+
+
+def client_versions() -> dict[str, Any]:
+    return _runtime.invoke_data("github.client_versions")
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// releasesCacheTTL is how long github://releases reuses a versions report,
+// keeping reads well inside GitHub's API rate limit.
+const releasesCacheTTL = 10 * time.Minute
+
+// ReleasesResponse is the response for github://releases.
+type ReleasesResponse struct {
+	Description string `json:"description"`
+	*VersionsReport
+	Usage string `json:"usage"`
+}
+
+// releasesCache reuses a versions report for releasesCacheTTL. Reports with
+// errors are not cached.
+type releasesCache struct {
+	fetch Fetcher
+	repos []types.DatasourceInfo
+	now   func() time.Time
+
+	mu        sync.Mutex
+	report    *VersionsReport
+	fetchedAt time.Time
+}
+
+func (c *releasesCache) get(ctx context.Context) *VersionsReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.report != nil && c.now().Sub(c.fetchedAt) < releasesCacheTTL {
+		return c.report
+	}
+
+	report := ClientVersions(ctx, c.fetch, c.repos)
+	if len(report.Errors) == 0 {
+		c.report, c.fetchedAt = report, c.now()
+	}
+
+	return report
+}
+
+// RegisterReleaseResources registers the github:// resources.
+func RegisterReleaseResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	fetch Fetcher,
+	repos []types.DatasourceInfo,
+) {
+	cache := &releasesCache{fetch: fetch, repos: repos, now: time.Now}
+
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"github://releases",
+			"Client Releases",
+			mcp.WithResourceDescription("Latest release of each configured client repository, to correlate network anomalies with client upgrades"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createReleasesHandler(cache),
+	})
+
+	log.Debug("Registered GitHub resources")
+}
+
+func createReleasesHandler(cache *releasesCache) types.ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		return marshal(&ReleasesResponse{
+			Description:    "Latest stable release of each configured repository, and any newer prerelease.",
+			VersionsReport: cache.get(ctx),
+			Usage:          "Read release notes with github.get_release(repo, tag) and search issues with github.search_issues(query).",
+		})
+	}
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling GitHub response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,109 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+const (
+	// requestTimeout bounds a single Grafana API call made for a resource read.
+	requestTimeout = 30 * time.Second
+
+	// tokenID identifies the proxy token used for resource reads.
+	tokenID = "grafana-resources"
+)
+
+// client reads the Grafana HTTP API through the credential proxy.
+type client struct {
+	proxySvc   proxy.Service
+	httpClient *http.Client
+}
+
+func newClient(proxySvc proxy.Service) *client {
+	return &client{
+		proxySvc:   proxySvc,
+		httpClient: &http.Client{Transport: proxySvc.Transport(), Timeout: requestTimeout},
+	}
+}
+
+// getJSON issues a GET against the Grafana API of an instance and decodes
+// the JSON response into out.
+func (c *client) getJSON(ctx context.Context, instance, path string, params url.Values, out any) error {
+	baseURL := strings.TrimRight(c.proxySvc.URL(), "/")
+	if baseURL == "" {
+		return fmt.Errorf("proxy URL is empty")
+	}
+
+	requestURL := baseURL + "/grafana" + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set(handlers.DatasourceHeader, instance)
+
+	token := c.proxySvc.RegisterToken(tokenID)
+	defer c.proxySvc.RevokeToken(tokenID)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := c.proxySvc.SignRequest(req); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("grafana %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	return nil
+}
+
+// searchDashboards lists dashboards on an instance.
+func (c *client) searchDashboards(ctx context.Context, instance string) ([]DashboardSummary, error) {
+	var hits []DashboardSummary
+	if err := c.getJSON(ctx, instance, "/api/search", url.Values{
+		"type":  {"dash-db"},
+		"limit": {"5000"},
+	}, &hits); err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// getDashboard fetches a dashboard model by UID.
+func (c *client) getDashboard(ctx context.Context, instance, uid string) (*dashboardResponse, error) {
+	var resp dashboardResponse
+	if err := c.getJSON(ctx, instance, "/api/dashboards/uid/"+url.PathEscape(uid), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
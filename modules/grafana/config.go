@@ -0,0 +1,13 @@
+package grafana
+
+// Config holds the Grafana module configuration.
+type Config struct {
+	Instances []InstanceConfig `yaml:"instances"`
+}
+
+// InstanceConfig holds configuration for a Grafana instance.
+type InstanceConfig struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	URL         string `yaml:"url,omitempty" json:"url,omitempty"`
+}
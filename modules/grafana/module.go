@@ -0,0 +1,256 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Compile-time interface checks.
+var (
+	_ module.Module            = (*Module)(nil)
+	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.ProxyAware        = (*Module)(nil)
+	_ module.ResourceProvider  = (*Module)(nil)
+	_ module.SchemaProvider    = (*Module)(nil)
+)
+
+// Module implements the module.Module interface for Grafana.
+type Module struct {
+	cfg         Config
+	datasources []types.DatasourceInfo
+	proxySvc    proxy.Service
+}
+
+// New creates a new Grafana module.
+func New() *Module { return &Module{} }
+
+func (p *Module) Name() string { return "grafana" }
+
+// SetProxyClient injects the proxy service used by the grafana:// resources.
+func (p *Module) SetProxyClient(client proxy.Service) {
+	p.proxySvc = client
+}
+
+// InitFromDiscovery initializes the module from discovered datasources.
+func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
+	var filtered []types.DatasourceInfo
+
+	for _, ds := range datasources {
+		if ds.Type != "grafana" {
+			continue
+		}
+
+		filtered = append(filtered, ds)
+	}
+
+	if len(filtered) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	p.datasources = filtered
+
+	return nil
+}
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+// Init parses the raw YAML config for this module.
+func (p *Module) Init(rawConfig []byte) error {
+	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
+		return err
+	}
+
+	// Drop unnamed instances.
+	validInstances := make([]InstanceConfig, 0, len(p.cfg.Instances))
+	for _, inst := range p.cfg.Instances {
+		if inst.Name != "" {
+			validInstances = append(validInstances, inst)
+		}
+	}
+
+	p.cfg.Instances = validInstances
+
+	if len(p.cfg.Instances) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	// Populate internal datasources from config.
+	p.datasources = make([]types.DatasourceInfo, 0, len(p.cfg.Instances))
+	for _, inst := range p.cfg.Instances {
+		p.datasources = append(p.datasources, types.DatasourceInfo{
+			Type:        "grafana",
+			Name:        inst.Name,
+			Description: inst.Description,
+			Metadata: map[string]string{
+				"url": inst.URL,
+			},
+		})
+	}
+
+	return nil
+}
+
+// ApplyDefaults sets default values before validation.
+func (p *Module) ApplyDefaults() {}
+
+// Validate checks that the parsed config is valid.
+func (p *Module) Validate() error {
+	names := make(map[string]struct{}, len(p.datasources))
+	for i, ds := range p.datasources {
+		if ds.Name == "" {
+			return fmt.Errorf("datasource[%d].name is required", i)
+		}
+
+		if _, exists := names[ds.Name]; exists {
+			return fmt.Errorf("datasource[%d].name %q is duplicated", i, ds.Name)
+		}
+
+		names[ds.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if len(p.datasources) == 0 {
+		return nil, nil
+	}
+
+	type datasourceInfo struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	infos := make([]datasourceInfo, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		infos = append(infos, datasourceInfo{
+			Name:        ds.Name,
+			Description: ds.Description,
+		})
+	}
+
+	infosJSON, err := json.Marshal(infos)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Grafana datasource info: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_GRAFANA_DATASOURCES": string(infosJSON),
+	}, nil
+}
+
+// DatasourceInfo returns datasource metadata for datasources:// resources.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	result := make([]types.DatasourceInfo, len(p.datasources))
+	copy(result, p.datasources)
+
+	return result
+}
+
+// Examples returns query examples for the Grafana module.
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	maps.Copy(result, queryExamples)
+
+	return result
+}
+
+// PythonAPIDocs returns the Grafana module documentation.
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	return map[string]types.ModuleDoc{
+		"grafana": {
+			Description: "Search Grafana dashboards, render panels and run panel queries",
+			Functions: map[string]types.FunctionDoc{
+				"list_datasources": {
+					Signature:   "grafana.list_datasources() -> list[dict]",
+					Description: "List available Grafana instances. Prefer datasources://grafana resource.",
+					Returns:     "List of dicts with 'name', 'description', 'url' keys",
+				},
+				"search_dashboards": {
+					Signature:   "grafana.search_dashboards(instance: str, query: str = None, tag: str = None, limit: int = 50) -> list[dict]",
+					Description: "Search dashboards by title and/or tag",
+					Parameters: map[string]string{
+						"instance": "Grafana instance name from datasources://grafana",
+						"query":    "Optional: title substring",
+						"tag":      "Optional: dashboard tag",
+						"limit":    "Max dashboards to return (default: 50)",
+					},
+					Returns: "List of dicts with 'uid', 'title', 'tags', 'folderTitle', 'url' keys",
+				},
+				"get_dashboard": {
+					Signature:   "grafana.get_dashboard(instance: str, uid: str) -> dict",
+					Description: "Get the full dashboard model, including panels and their queries",
+					Parameters: map[string]string{
+						"instance": "Grafana instance name",
+						"uid":      "Dashboard UID",
+					},
+					Returns: "Dict with 'dashboard' and 'meta' keys",
+				},
+				"render_panel": {
+					Signature:   "grafana.render_panel(instance: str, uid: str, panel_id: int, start: str = 'now-6h', end: str = 'now', width: int = 1000, height: int = 500, output_path: str = None) -> str",
+					Description: "Render a dashboard panel to PNG (requires the Grafana image renderer)",
+					Parameters: map[string]string{
+						"instance":    "Grafana instance name",
+						"uid":         "Dashboard UID",
+						"panel_id":    "Panel ID from the dashboard model",
+						"start":       "Start time (Grafana format, e.g. 'now-6h' or epoch ms)",
+						"end":         "End time (default: now)",
+						"width":       "Image width in pixels",
+						"height":      "Image height in pixels",
+						"output_path": "Optional: file path (default: /workspace/grafana-{uid}-{panel_id}.png)",
+					},
+					Returns: "Path of the written PNG file",
+				},
+				"query_datasource": {
+					Signature:   "grafana.query_datasource(instance: str, datasource_uid: str, query: dict, start: str = 'now-1h', end: str = 'now') -> dict",
+					Description: "Run a query against a Grafana-managed datasource, as a dashboard panel would",
+					Parameters: map[string]string{
+						"instance":       "Grafana instance name",
+						"datasource_uid": "UID of the Grafana datasource (see panel 'datasource' fields)",
+						"query":          "Datasource-specific query model, e.g. {'expr': 'up'} for Prometheus",
+						"start":          "Start time (Grafana format)",
+						"end":            "End time (Grafana format)",
+					},
+					Returns: "Raw Grafana /api/ds/query response with 'results' keyed by refId",
+				},
+			},
+		},
+	}
+}
+
+// RegisterResources registers the grafana:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	RegisterDashboardResources(log.WithField("module", "grafana"), reg, newClient(p.proxySvc), p.instanceNames())
+
+	return nil
+}
+
+// Start performs async initialization.
+func (p *Module) Start(_ context.Context) error { return nil }
+
+// Stop cleans up resources.
+func (p *Module) Stop(_ context.Context) error { return nil }
+
+func (p *Module) instanceNames() []string {
+	names := make([]string, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		names = append(names, ds.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,88 @@
+"""Thin Grafana wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def list_datasources() -> list[dict[str, Any]]:
+    data = _runtime.invoke_data("grafana.list_datasources")
+    return data.get("datasources", [])
+
+
+def search_dashboards(
+    instance: str,
+    query: str | None = None,
+    tag: str | None = None,
+    limit: int = 50,
+) -> list[dict[str, Any]]:
+    data = _runtime.invoke_json(
+        "grafana.search_dashboards",
+        {
+            "datasource": instance,
+            "query": query,
+            "tag": tag,
+            "limit": limit,
+        },
+    )
+    return data if isinstance(data, list) else []
+
+
+def get_dashboard(instance: str, uid: str) -> dict[str, Any]:
+    return _runtime.invoke_json(
+        "grafana.get_dashboard",
+        {"datasource": instance, "uid": uid},
+    )
+
+
+def render_panel(
+    instance: str,
+    uid: str,
+    panel_id: int,
+    start: str = "now-6h",
+    end: str = "now",
+    width: int = 1000,
+    height: int = 500,
+    output_path: str | None = None,
+) -> str:
+    image = _runtime.invoke_bytes(
+        "grafana.render_panel",
+        {
+            "datasource": instance,
+            "uid": uid,
+            "panel_id": panel_id,
+            "from": start,
+            "to": end,
+            "width": width,
+            "height": height,
+        },
+    )
+
+    path = output_path or f"/workspace/grafana-{uid}-{panel_id}.png"
+    os.makedirs(os.path.dirname(path) or ".", exist_ok=True)
+    with open(path, "wb") as f:
+        f.write(image)
+
+    return path
+
+
+def query_datasource(
+    instance: str,
+    datasource_uid: str,
+    query: dict[str, Any],
+    start: str = "now-1h",
+    end: str = "now",
+) -> dict[str, Any]:
+    return _runtime.invoke_json(
+        "grafana.query_datasource",
+        {
+            "datasource": instance,
+            "datasource_uid": datasource_uid,
+            "query": query,
+            "from": start,
+            "to": end,
+        },
+    )
@@ -0,0 +1,294 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// dashboardURIPattern matches grafana://dashboards/{instance}/{uid} URIs.
+var dashboardURIPattern = regexp.MustCompile(`^grafana://dashboards/([^/]+)/([^/]+)$`)
+
+// DashboardSummary is a dashboard search hit.
+type DashboardSummary struct {
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags,omitempty"`
+	FolderTitle string   `json:"folderTitle,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+// DashboardsListResponse is the response for grafana://dashboards.
+type DashboardsListResponse struct {
+	Description string                        `json:"description"`
+	Instances   map[string][]DashboardSummary `json:"instances"`
+	Errors      map[string]string             `json:"errors,omitempty"`
+	Usage       string                        `json:"usage"`
+}
+
+// DashboardDetailResponse is the response for grafana://dashboards/{instance}/{uid}.
+type DashboardDetailResponse struct {
+	Instance    string         `json:"instance"`
+	UID         string         `json:"uid"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Folder      string         `json:"folder,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Variables   []string       `json:"variables,omitempty"`
+	Panels      []PanelSummary `json:"panels"`
+}
+
+// PanelSummary describes a dashboard panel and the queries behind it.
+type PanelSummary struct {
+	ID         int          `json:"id"`
+	Title      string       `json:"title,omitempty"`
+	Type       string       `json:"type,omitempty"`
+	Row        string       `json:"row,omitempty"`
+	Datasource string       `json:"datasource,omitempty"`
+	Queries    []PanelQuery `json:"queries,omitempty"`
+}
+
+// PanelQuery is a single panel target.
+type PanelQuery struct {
+	RefID      string `json:"ref_id,omitempty"`
+	Datasource string `json:"datasource,omitempty"`
+	Query      string `json:"query,omitempty"`
+}
+
+// dashboardResponse is the upstream /api/dashboards/uid/{uid} response.
+type dashboardResponse struct {
+	Dashboard dashboardModel `json:"dashboard"`
+	Meta      struct {
+		FolderTitle string `json:"folderTitle"`
+		URL         string `json:"url"`
+	} `json:"meta"`
+}
+
+type dashboardModel struct {
+	UID         string       `json:"uid"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Tags        []string     `json:"tags"`
+	Panels      []panelModel `json:"panels"`
+	Templating  struct {
+		List []struct {
+			Name string `json:"name"`
+		} `json:"list"`
+	} `json:"templating"`
+}
+
+type panelModel struct {
+	ID         int              `json:"id"`
+	Title      string           `json:"title"`
+	Type       string           `json:"type"`
+	Datasource json.RawMessage  `json:"datasource"`
+	Targets    []map[string]any `json:"targets"`
+	Panels     []panelModel     `json:"panels"`
+}
+
+// queryFields are the target fields holding the query text, in order of
+// preference, across the common datasource types.
+var queryFields = []string{"expr", "rawSql", "query", "expression"}
+
+// RegisterDashboardResources registers the grafana:// resources.
+func RegisterDashboardResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	client *client,
+	instances []string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"grafana://dashboards",
+			"Grafana Dashboards",
+			mcp.WithResourceDescription("Dashboards available on each Grafana instance, for finding existing panels relevant to an investigation"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createDashboardsListHandler(client, instances),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"grafana://dashboards/{instance}/{uid}",
+			"Grafana Dashboard",
+			mcp.WithTemplateDescription("Panels of a Grafana dashboard with their datasources and queries"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: dashboardURIPattern,
+		Handler: createDashboardDetailHandler(client, instances),
+	})
+
+	log.Debug("Registered Grafana resources")
+}
+
+func createDashboardsListHandler(client *client, instances []string) types.ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		response := &DashboardsListResponse{
+			Description: "Grafana dashboards by instance.",
+			Instances:   make(map[string][]DashboardSummary, len(instances)),
+			Usage:       "Read grafana://dashboards/{instance}/{uid} for a dashboard's panels and queries.",
+		}
+
+		for _, instance := range instances {
+			dashboards, err := client.searchDashboards(ctx, instance)
+			if err != nil {
+				if response.Errors == nil {
+					response.Errors = make(map[string]string, 1)
+				}
+
+				response.Errors[instance] = err.Error()
+
+				continue
+			}
+
+			sort.Slice(dashboards, func(i, j int) bool {
+				if dashboards[i].Title != dashboards[j].Title {
+					return dashboards[i].Title < dashboards[j].Title
+				}
+
+				return dashboards[i].UID < dashboards[j].UID
+			})
+
+			response.Instances[instance] = dashboards
+		}
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling dashboards: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+func createDashboardDetailHandler(client *client, instances []string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := dashboardURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 3 {
+			return "", fmt.Errorf("invalid dashboard URI: %s", uri)
+		}
+
+		instance, uid := matches[1], matches[2]
+		if !slices.Contains(instances, instance) {
+			return "", fmt.Errorf("unknown Grafana instance %q", instance)
+		}
+
+		dashboard, err := client.getDashboard(ctx, instance, uid)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(summarizeDashboard(instance, dashboard), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling dashboard: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// summarizeDashboard reduces a dashboard model to its panels and queries,
+// flattening collapsed rows.
+func summarizeDashboard(instance string, resp *dashboardResponse) *DashboardDetailResponse {
+	dashboard := resp.Dashboard
+
+	detail := &DashboardDetailResponse{
+		Instance:    instance,
+		UID:         dashboard.UID,
+		Title:       dashboard.Title,
+		Description: dashboard.Description,
+		Tags:        dashboard.Tags,
+		Folder:      resp.Meta.FolderTitle,
+		URL:         resp.Meta.URL,
+		Panels:      make([]PanelSummary, 0, len(dashboard.Panels)),
+	}
+
+	for _, variable := range dashboard.Templating.List {
+		detail.Variables = append(detail.Variables, variable.Name)
+	}
+
+	var row string
+
+	for _, panel := range dashboard.Panels {
+		if panel.Type == "row" {
+			row = panel.Title
+
+			for _, nested := range panel.Panels {
+				detail.Panels = append(detail.Panels, summarizePanel(nested, row))
+			}
+
+			continue
+		}
+
+		detail.Panels = append(detail.Panels, summarizePanel(panel, row))
+	}
+
+	return detail
+}
+
+func summarizePanel(panel panelModel, row string) PanelSummary {
+	summary := PanelSummary{
+		ID:         panel.ID,
+		Title:      panel.Title,
+		Type:       panel.Type,
+		Row:        row,
+		Datasource: datasourceRef(panel.Datasource),
+	}
+
+	for _, target := range panel.Targets {
+		query := PanelQuery{}
+		query.RefID, _ = target["refId"].(string)
+
+		if raw, err := json.Marshal(target["datasource"]); err == nil {
+			query.Datasource = datasourceRef(raw)
+		}
+
+		for _, field := range queryFields {
+			if text, ok := target[field].(string); ok && text != "" {
+				query.Query = text
+
+				break
+			}
+		}
+
+		summary.Queries = append(summary.Queries, query)
+	}
+
+	return summary
+}
+
+// datasourceRef renders a panel or target datasource, which Grafana stores
+// either as a name string or as a {"type", "uid"} reference.
+func datasourceRef(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name
+	}
+
+	var ref struct {
+		UID string `json:"uid"`
+	}
+
+	if err := json.Unmarshal(raw, &ref); err == nil {
+		return ref.UID
+	}
+
+	return ""
+}
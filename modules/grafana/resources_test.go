@@ -0,0 +1,58 @@
+package grafana
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeDashboard(t *testing.T) {
+	const raw = `{
+		"dashboard": {
+			"uid": "abc",
+			"title": "Beacon nodes",
+			"tags": ["consensus"],
+			"templating": {"list": [{"name": "network"}]},
+			"panels": [
+				{
+					"id": 1, "title": "Head slot", "type": "timeseries",
+					"datasource": {"type": "prometheus", "uid": "prom"},
+					"targets": [{"refId": "A", "expr": "beacon_head_slot"}]
+				},
+				{
+					"id": 2, "title": "Details", "type": "row",
+					"panels": [
+						{
+							"id": 3, "title": "Blocks", "type": "table",
+							"datasource": "ClickHouse",
+							"targets": [{"refId": "A", "rawSql": "SELECT 1", "datasource": {"uid": "ch"}}]
+						}
+					]
+				}
+			]
+		},
+		"meta": {"folderTitle": "Ethereum", "url": "/d/abc/beacon-nodes"}
+	}`
+
+	var resp dashboardResponse
+	require.NoError(t, json.Unmarshal([]byte(raw), &resp))
+
+	detail := summarizeDashboard("ops", &resp)
+
+	assert.Equal(t, "ops", detail.Instance)
+	assert.Equal(t, "Ethereum", detail.Folder)
+	assert.Equal(t, []string{"network"}, detail.Variables)
+	require.Len(t, detail.Panels, 2)
+
+	assert.Equal(t, PanelSummary{
+		ID: 1, Title: "Head slot", Type: "timeseries", Datasource: "prom",
+		Queries: []PanelQuery{{RefID: "A", Query: "beacon_head_slot"}},
+	}, detail.Panels[0])
+
+	assert.Equal(t, PanelSummary{
+		ID: 3, Title: "Blocks", Type: "table", Row: "Details", Datasource: "ClickHouse",
+		Queries: []PanelQuery{{RefID: "A", Datasource: "ch", Query: "SELECT 1"}},
+	}, detail.Panels[1])
+}
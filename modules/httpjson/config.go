@@ -0,0 +1,14 @@
+package httpjson
+
+// Config holds the HTTP JSON module configuration.
+type Config struct {
+	Endpoints []EndpointConfig `yaml:"endpoints"`
+}
+
+// EndpointConfig describes an HTTP JSON endpoint served by the proxy.
+type EndpointConfig struct {
+	Name         string   `yaml:"name" json:"name"`
+	Description  string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Network      string   `yaml:"network,omitempty" json:"network,omitempty"`
+	AllowedPaths []string `yaml:"allowed_paths,omitempty" json:"allowed_paths,omitempty"`
+}
@@ -0,0 +1,210 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Compile-time interface checks.
+var (
+	_ module.Module            = (*Module)(nil)
+	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.SchemaProvider    = (*Module)(nil)
+)
+
+// Module implements the module.Module interface for generic HTTP JSON
+// endpoints declared in the proxy config.
+type Module struct {
+	cfg         Config
+	datasources []types.DatasourceInfo
+}
+
+// New creates a new HTTP JSON module.
+func New() *Module { return &Module{} }
+
+func (p *Module) Name() string { return "httpjson" }
+
+// InitFromDiscovery initializes the module from discovered datasources.
+func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
+	var filtered []types.DatasourceInfo
+
+	for _, ds := range datasources {
+		if ds.Type != "httpjson" {
+			continue
+		}
+
+		filtered = append(filtered, ds)
+	}
+
+	if len(filtered) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	p.datasources = filtered
+
+	return nil
+}
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+// Init parses the raw YAML config for this module.
+func (p *Module) Init(rawConfig []byte) error {
+	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
+		return err
+	}
+
+	// Drop unnamed endpoints.
+	validEndpoints := make([]EndpointConfig, 0, len(p.cfg.Endpoints))
+	for _, endpoint := range p.cfg.Endpoints {
+		if endpoint.Name != "" {
+			validEndpoints = append(validEndpoints, endpoint)
+		}
+	}
+
+	p.cfg.Endpoints = validEndpoints
+
+	if len(p.cfg.Endpoints) == 0 {
+		return module.ErrNoValidConfig
+	}
+
+	// Populate internal datasources from config.
+	p.datasources = make([]types.DatasourceInfo, 0, len(p.cfg.Endpoints))
+	for _, endpoint := range p.cfg.Endpoints {
+		info := types.DatasourceInfo{
+			Type:        "httpjson",
+			Name:        endpoint.Name,
+			Description: endpoint.Description,
+			Metadata: map[string]string{
+				"allowed_paths": strings.Join(endpoint.AllowedPaths, ","),
+			},
+		}
+		if endpoint.Network != "" {
+			info.Metadata["network"] = endpoint.Network
+		}
+
+		p.datasources = append(p.datasources, info)
+	}
+
+	return nil
+}
+
+// ApplyDefaults sets default values before validation.
+func (p *Module) ApplyDefaults() {}
+
+// Validate checks that the parsed config is valid.
+func (p *Module) Validate() error {
+	names := make(map[string]struct{}, len(p.datasources))
+	for i, ds := range p.datasources {
+		if ds.Name == "" {
+			return fmt.Errorf("datasource[%d].name is required", i)
+		}
+
+		if _, exists := names[ds.Name]; exists {
+			return fmt.Errorf("datasource[%d].name %q is duplicated", i, ds.Name)
+		}
+
+		names[ds.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if len(p.datasources) == 0 {
+		return nil, nil
+	}
+
+	infos := make([]EndpointConfig, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		infos = append(infos, EndpointConfig{
+			Name:         ds.Name,
+			Description:  ds.Description,
+			Network:      ds.Metadata["network"],
+			AllowedPaths: AllowedPaths(ds),
+		})
+	}
+
+	infosJSON, err := json.Marshal(infos)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling HTTP JSON datasource info: %w", err)
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_HTTPJSON_DATASOURCES": string(infosJSON),
+	}, nil
+}
+
+// DatasourceInfo returns datasource metadata for datasources:// resources.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	result := make([]types.DatasourceInfo, len(p.datasources))
+	copy(result, p.datasources)
+
+	return result
+}
+
+// Examples returns query examples for the HTTP JSON module.
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	maps.Copy(result, queryExamples)
+
+	return result
+}
+
+// PythonAPIDocs returns the HTTP JSON module documentation.
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	return map[string]types.ModuleDoc{
+		"http_json": {
+			Description: "Read operator-declared JSON HTTP services through the credential proxy",
+			Functions: map[string]types.FunctionDoc{
+				"list_datasources": {
+					Signature:   "http_json.list_datasources() -> list[dict]",
+					Description: "List available HTTP JSON endpoints. Prefer datasources://httpjson resource.",
+					Returns:     "List of dicts with 'name', 'description', 'network', 'allowed_paths' keys",
+				},
+				"get": {
+					Signature:   "http_json.get(name: str, path: str, params: dict = None) -> Any",
+					Description: "GET a path on a named endpoint and return the decoded JSON body",
+					Parameters: map[string]string{
+						"name":   "Endpoint name from datasources://httpjson",
+						"path":   "Request path; must match one of the endpoint's allowed_paths (entries ending in '/' are prefixes)",
+						"params": "Optional: query string parameters; list values are repeated",
+					},
+					Returns: "Decoded JSON response (dict, list or scalar)",
+				},
+			},
+		},
+	}
+}
+
+// Start performs async initialization.
+func (p *Module) Start(_ context.Context) error { return nil }
+
+// Stop cleans up resources.
+func (p *Module) Stop(_ context.Context) error { return nil }
+
+// AllowedPaths returns the allowed paths advertised for an HTTP JSON datasource.
+func AllowedPaths(ds types.DatasourceInfo) []string {
+	raw := ds.Metadata["allowed_paths"]
+	if raw == "" {
+		return nil
+	}
+
+	return strings.Split(raw, ",")
+}
+
+// PathAllowed reports whether path may be requested from ds, using the same
+// matching rules the proxy enforces.
+func PathAllowed(ds types.DatasourceInfo, path string) bool {
+	return handlers.HTTPJSONPathAllowed(AllowedPaths(ds), path)
+}
@@ -0,0 +1,50 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestInitFromDiscoveryFiltersHTTPJSON(t *testing.T) {
+	m := New()
+
+	err := m.InitFromDiscovery([]types.DatasourceInfo{{Type: "loki", Name: "logs"}})
+	require.ErrorIs(t, err, module.ErrNoValidConfig)
+
+	require.NoError(t, m.InitFromDiscovery([]types.DatasourceInfo{
+		{Type: "loki", Name: "logs"},
+		{
+			Type:        "httpjson",
+			Name:        "faucet",
+			Description: "Devnet faucet",
+			Metadata:    map[string]string{"allowed_paths": "/api/v1/status,/api/v1/claims/", "network": "devnet-3"},
+		},
+	}))
+
+	env, err := m.SandboxEnv()
+	require.NoError(t, err)
+
+	var endpoints []EndpointConfig
+	require.NoError(t, json.Unmarshal([]byte(env["ETHPANDAOPS_HTTPJSON_DATASOURCES"]), &endpoints))
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "faucet", endpoints[0].Name)
+	assert.Equal(t, "devnet-3", endpoints[0].Network)
+	assert.Equal(t, []string{"/api/v1/status", "/api/v1/claims/"}, endpoints[0].AllowedPaths)
+}
+
+func TestPathAllowed(t *testing.T) {
+	ds := types.DatasourceInfo{Metadata: map[string]string{"allowed_paths": "/api/v1/status,/api/v1/claims/"}}
+
+	assert.True(t, PathAllowed(ds, "/api/v1/status"))
+	assert.True(t, PathAllowed(ds, "/api/v1/claims/0xabc"))
+	assert.False(t, PathAllowed(ds, "/api/v1/status/extra"))
+	assert.False(t, PathAllowed(ds, "/api/v1/claims/../admin"))
+	assert.False(t, PathAllowed(ds, "api/v1/status"))
+	assert.False(t, PathAllowed(types.DatasourceInfo{}, "/api/v1/status"))
+}
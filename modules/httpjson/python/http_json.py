@@ -0,0 +1,23 @@
+"""Thin wrappers for operator-declared JSON HTTP endpoints over server operations."""
+
+from __future__ import annotations
+
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def list_datasources() -> list[dict[str, Any]]:
+    data = _runtime.invoke_data("http_json.list_datasources")
+    return data.get("datasources", [])
+
+
+def get(name: str, path: str, params: dict[str, Any] | None = None) -> Any:
+    return _runtime.invoke_json(
+        "http_json.get",
+        {
+            "datasource": name,
+            "path": path,
+            "params": params or {},
+        },
+    )
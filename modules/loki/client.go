@@ -0,0 +1,106 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+const (
+	// requestTimeout bounds a single Loki API call made for a resource read.
+	requestTimeout = 30 * time.Second
+
+	// tokenID identifies the proxy token used for resource reads.
+	tokenID = "loki-resources"
+
+	// labelLookback is how far back label names and values are collected.
+	labelLookback = 6 * time.Hour
+)
+
+// client reads the Loki label API through the credential proxy.
+type client struct {
+	proxySvc   proxy.Service
+	httpClient *http.Client
+}
+
+func newClient(proxySvc proxy.Service) *client {
+	return &client{
+		proxySvc:   proxySvc,
+		httpClient: &http.Client{Transport: proxySvc.Transport(), Timeout: requestTimeout},
+	}
+}
+
+// labels returns the label names seen on an instance within labelLookback.
+func (c *client) labels(ctx context.Context, instance string) ([]string, error) {
+	return c.getStrings(ctx, instance, "/loki/api/v1/labels")
+}
+
+// labelValues returns the values of a label seen on an instance within
+// labelLookback.
+func (c *client) labelValues(ctx context.Context, instance, label string) ([]string, error) {
+	return c.getStrings(ctx, instance, "/loki/api/v1/label/"+url.PathEscape(label)+"/values")
+}
+
+// getStrings issues a GET against the Loki API of an instance and returns the
+// string list under "data".
+func (c *client) getStrings(ctx context.Context, instance, path string) ([]string, error) {
+	baseURL := strings.TrimRight(c.proxySvc.URL(), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("proxy URL is empty")
+	}
+
+	now := time.Now()
+	params := url.Values{
+		"start": {strconv.FormatInt(now.Add(-labelLookback).UnixNano(), 10)},
+		"end":   {strconv.FormatInt(now.UnixNano(), 10)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/loki"+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set(handlers.DatasourceHeader, instance)
+
+	token := c.proxySvc.RegisterToken(tokenID)
+	defer c.proxySvc.RevokeToken(tokenID)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := c.proxySvc.SignRequest(req); err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return nil, fmt.Errorf("loki %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Data []string `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	return out.Data, nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"sort"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -16,12 +19,19 @@ import (
 var (
 	_ module.Module            = (*Module)(nil)
 	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.ProxyAware        = (*Module)(nil)
+	_ module.HealthProber      = (*Module)(nil)
+	_ module.ResourceProvider  = (*Module)(nil)
+	_ module.SavedQueriesAware = (*Module)(nil)
+	_ module.SchemaProvider    = (*Module)(nil)
 )
 
 // Module implements the module.Module interface for Loki.
 type Module struct {
-	cfg         Config
-	datasources []types.DatasourceInfo
+	cfg          Config
+	datasources  []types.DatasourceInfo
+	proxySvc     proxy.Service
+	savedQueries []types.SavedQuery
 }
 
 // New creates a new Loki module.
@@ -29,6 +39,16 @@ func New() *Module { return &Module{} }
 
 func (p *Module) Name() string { return "loki" }
 
+// SetProxyClient injects the proxy service used for health probes.
+func (p *Module) SetProxyClient(client proxy.Service) {
+	p.proxySvc = client
+}
+
+// SetSavedQueries implements module.SavedQueriesAware.
+func (p *Module) SetSavedQueries(queries []types.SavedQuery) {
+	p.savedQueries = queries
+}
+
 // InitFromDiscovery initializes the module from discovered datasources.
 func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	var filtered []types.DatasourceInfo
@@ -50,6 +70,9 @@ func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	return nil
 }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 // Init parses the raw YAML config for this module.
 func (p *Module) Init(rawConfig []byte) error {
 	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
@@ -152,6 +175,15 @@ func (p *Module) Examples() map[string]types.ExampleCategory {
 	return result
 }
 
+// ProbeHealth checks each Loki datasource's /ready endpoint through the proxy.
+func (p *Module) ProbeHealth(ctx context.Context) []types.HealthProbe {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	return module.ProbeProxyDatasources(ctx, p.proxySvc, p.datasources, "/loki/ready")
+}
+
 // PythonAPIDocs returns the Loki module documentation.
 func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 	return map[string]types.ModuleDoc{
@@ -214,6 +246,24 @@ func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 	}
 }
 
+// RegisterResources registers the loki:// resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	instances := make([]string, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		instances = append(instances, ds.Name)
+	}
+
+	sort.Strings(instances)
+
+	RegisterLabelResources(log.WithField("module", "loki"), reg, newClient(p.proxySvc), instances, p.savedQueries)
+
+	return nil
+}
+
 // Start performs async initialization.
 func (p *Module) Start(_ context.Context) error { return nil }
 
@@ -0,0 +1,272 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// labelCacheTTL is how long label names and values are reused.
+	labelCacheTTL = 5 * time.Minute
+
+	// maxLabelValues caps the values listed per label.
+	maxLabelValues = 500
+
+	// maxSummaryValues caps the values of each key label in loki://labels.
+	maxSummaryValues = 50
+)
+
+// keyLabels are labels whose values loki://labels includes, since they name
+// the jobs and services a query is usually scoped to.
+var keyLabels = []string{"job", "namespace", "app", "service_name", "container", "network"}
+
+// labelValuesURIPattern matches loki://labels/{instance}/{label} URIs.
+var labelValuesURIPattern = regexp.MustCompile(`^loki://labels/([^/]+)/([a-zA-Z_][a-zA-Z0-9_]*)$`)
+
+// InstanceLabels is the label names of a Loki instance and the values of its
+// key labels.
+type InstanceLabels struct {
+	Labels    []string            `json:"labels"`
+	KeyValues map[string][]string `json:"key_values,omitempty"`
+	// Truncated lists key labels with more values than are shown.
+	Truncated []string `json:"truncated,omitempty"`
+}
+
+// LabelsResponse is the response for loki://labels.
+type LabelsResponse struct {
+	Description string                    `json:"description"`
+	Instances   map[string]InstanceLabels `json:"instances"`
+	Errors      map[string]string         `json:"errors,omitempty"`
+	Usage       string                    `json:"usage"`
+}
+
+// LabelValuesResponse is the response for loki://labels/{instance}/{label}.
+type LabelValuesResponse struct {
+	Instance string   `json:"instance"`
+	Label    string   `json:"label"`
+	Values   []string `json:"values"`
+	// Omitted is the number of values left out of Values.
+	Omitted int `json:"omitted,omitempty"`
+}
+
+// SavedQueriesResponse is the response for loki://queries.
+type SavedQueriesResponse struct {
+	Description string             `json:"description"`
+	Queries     []types.SavedQuery `json:"queries"`
+	Usage       string             `json:"usage"`
+}
+
+// labelSource lists label names and values of a Loki instance.
+type labelSource interface {
+	labels(ctx context.Context, instance string) ([]string, error)
+	labelValues(ctx context.Context, instance, label string) ([]string, error)
+}
+
+// labelCache reuses label lookups for labelCacheTTL. Errors are not cached.
+type labelCache struct {
+	source labelSource
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedStrings
+}
+
+type cachedStrings struct {
+	values    []string
+	fetchedAt time.Time
+}
+
+func newLabelCache(source labelSource) *labelCache {
+	return &labelCache{source: source, now: time.Now, entries: make(map[string]cachedStrings)}
+}
+
+func (c *labelCache) labels(ctx context.Context, instance string) ([]string, error) {
+	return c.get(instance, func() ([]string, error) { return c.source.labels(ctx, instance) })
+}
+
+func (c *labelCache) labelValues(ctx context.Context, instance, label string) ([]string, error) {
+	return c.get(instance+"/"+label, func() ([]string, error) { return c.source.labelValues(ctx, instance, label) })
+}
+
+func (c *labelCache) get(key string, fetch func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && c.now().Sub(entry.fetchedAt) < labelCacheTTL {
+		return entry.values, nil
+	}
+
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(values)
+
+	c.mu.Lock()
+	c.entries[key] = cachedStrings{values: values, fetchedAt: c.now()}
+	c.mu.Unlock()
+
+	return values, nil
+}
+
+// RegisterLabelResources registers the loki:// label resources, and
+// loki://queries when there are saved queries.
+func RegisterLabelResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	source labelSource,
+	instances []string,
+	queries []types.SavedQuery,
+) {
+	cache := newLabelCache(source)
+
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"loki://labels",
+			"Loki Labels",
+			mcp.WithResourceDescription("Label names on each Loki instance, with the jobs, namespaces and other key label values that exist, for scoping LogQL selectors"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createLabelsHandler(cache, instances),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"loki://labels/{instance}/{label}",
+			"Loki Label Values",
+			mcp.WithTemplateDescription("Values of a label on a Loki instance"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Pattern: labelValuesURIPattern,
+		Handler: createLabelValuesHandler(cache, instances),
+	})
+
+	if len(queries) > 0 {
+		reg.RegisterStatic(types.StaticResource{
+			Resource: mcp.NewResource(
+				"loki://queries",
+				"Loki Saved Queries",
+				mcp.WithResourceDescription("Operator-curated LogQL queries to start from instead of composing selectors from scratch"),
+				mcp.WithMIMEType("application/json"),
+				mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+			),
+			Handler: createSavedQueriesHandler(queries),
+		})
+	}
+
+	log.Debug("Registered Loki resources")
+}
+
+func createLabelsHandler(cache *labelCache, instances []string) types.ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		response := &LabelsResponse{
+			Description: "Label names seen in the last 6 hours on each Loki instance, with values of key labels.",
+			Instances:   make(map[string]InstanceLabels, len(instances)),
+			Usage:       "Read loki://labels/{instance}/{label} for all values of another label.",
+		}
+
+		for _, instance := range instances {
+			labels, err := cache.labels(ctx, instance)
+			if err != nil {
+				if response.Errors == nil {
+					response.Errors = make(map[string]string, 1)
+				}
+
+				response.Errors[instance] = err.Error()
+
+				continue
+			}
+
+			entry := InstanceLabels{Labels: labels}
+
+			for _, label := range keyLabels {
+				if !slices.Contains(labels, label) {
+					continue
+				}
+
+				values, err := cache.labelValues(ctx, instance, label)
+				if err != nil {
+					continue
+				}
+
+				if len(values) > maxSummaryValues {
+					values = values[:maxSummaryValues]
+					entry.Truncated = append(entry.Truncated, label)
+				}
+
+				if entry.KeyValues == nil {
+					entry.KeyValues = make(map[string][]string, len(keyLabels))
+				}
+
+				entry.KeyValues[label] = values
+			}
+
+			response.Instances[instance] = entry
+		}
+
+		return marshal(response)
+	}
+}
+
+func createLabelValuesHandler(cache *labelCache, instances []string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := labelValuesURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 3 {
+			return "", fmt.Errorf("invalid Loki label URI: %s", uri)
+		}
+
+		instance, label := matches[1], matches[2]
+		if !slices.Contains(instances, instance) {
+			return "", fmt.Errorf("unknown Loki instance %q. Available: %v", instance, instances)
+		}
+
+		values, err := cache.labelValues(ctx, instance, label)
+		if err != nil {
+			return "", err
+		}
+
+		response := &LabelValuesResponse{Instance: instance, Label: label, Values: values}
+
+		if len(values) > maxLabelValues {
+			response.Omitted = len(values) - maxLabelValues
+			response.Values = values[:maxLabelValues]
+		}
+
+		return marshal(response)
+	}
+}
+
+func createSavedQueriesHandler(queries []types.SavedQuery) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		return marshal(&SavedQueriesResponse{
+			Description: "Saved LogQL queries curated by the operators of this server.",
+			Queries:     queries,
+			Usage:       "Run a query with loki.query(datasource, logql). Queries without a datasource work on any Loki instance.",
+		})
+	}
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling Loki response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,84 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	labelNames map[string][]string
+	values     map[string][]string
+	calls      int
+}
+
+func (f *fakeSource) labels(_ context.Context, instance string) ([]string, error) {
+	f.calls++
+
+	names, ok := f.labelNames[instance]
+	if !ok {
+		return nil, errors.New("instance down")
+	}
+
+	return names, nil
+}
+
+func (f *fakeSource) labelValues(_ context.Context, instance, label string) ([]string, error) {
+	f.calls++
+
+	return f.values[instance+"/"+label], nil
+}
+
+func TestLabelsHandler(t *testing.T) {
+	source := &fakeSource{
+		labelNames: map[string][]string{"ops": {"pod", "job", "namespace"}},
+		values: map[string][]string{
+			"ops/job":       {"teku", "geth"},
+			"ops/namespace": {"hoodi"},
+		},
+	}
+
+	cache := newLabelCache(source)
+	handler := createLabelsHandler(cache, []string{"ops", "staging"})
+
+	out, err := handler(context.Background(), "loki://labels")
+	require.NoError(t, err)
+
+	var resp LabelsResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, []string{"job", "namespace", "pod"}, resp.Instances["ops"].Labels)
+	assert.Equal(t, map[string][]string{"job": {"geth", "teku"}, "namespace": {"hoodi"}}, resp.Instances["ops"].KeyValues)
+	assert.Equal(t, "instance down", resp.Errors["staging"])
+
+	// ops is served from the cache; the failed staging lookup is retried.
+	calls := source.calls
+	_, err = handler(context.Background(), "loki://labels")
+	require.NoError(t, err)
+	assert.Equal(t, calls+1, source.calls)
+
+	cache.now = func() time.Time { return time.Now().Add(labelCacheTTL) }
+	_, err = handler(context.Background(), "loki://labels")
+	require.NoError(t, err)
+	assert.Equal(t, calls+5, source.calls)
+}
+
+func TestLabelValuesHandler(t *testing.T) {
+	source := &fakeSource{values: map[string][]string{"ops/job": {"teku", "geth"}}}
+	handler := createLabelValuesHandler(newLabelCache(source), []string{"ops"})
+
+	out, err := handler(context.Background(), "loki://labels/ops/job")
+	require.NoError(t, err)
+
+	var resp LabelValuesResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, []string{"geth", "teku"}, resp.Values)
+
+	_, err = handler(context.Background(), "loki://labels/prod/job")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown Loki instance "prod"`)
+}
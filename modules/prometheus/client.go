@@ -0,0 +1,214 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+const (
+	// requestTimeout bounds a single Prometheus API call made by the server.
+	requestTimeout = 30 * time.Second
+
+	// tokenID identifies the proxy token used for server-side reads.
+	tokenID = "prometheus-metadata"
+
+	// metricNamesPath lists every metric name known to a Prometheus instance.
+	metricNamesPath = "/api/v1/label/__name__/values"
+
+	// metadataPath lists the type, help and unit of metrics.
+	metadataPath = "/api/v1/metadata"
+
+	// labelsPath lists label names, optionally of the series matching a selector.
+	labelsPath = "/api/v1/labels"
+
+	// tsdbStatusPath reports head block cardinality statistics.
+	tsdbStatusPath = "/api/v1/status/tsdb"
+
+	// queryPath evaluates an instant PromQL query.
+	queryPath = "/api/v1/query"
+
+	// tsdbStatusLimit is the number of entries requested per TSDB statistic.
+	tsdbStatusLimit = 100
+)
+
+// MetricMetadata is the type, help and unit a target reports for a metric.
+type MetricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// TSDBStat is a name and count in the TSDB status statistics.
+type TSDBStat struct {
+	Name  string `json:"name"`
+	Value uint64 `json:"value"`
+}
+
+// TSDBStatus is the head block cardinality of a Prometheus instance.
+type TSDBStatus struct {
+	HeadStats struct {
+		NumSeries uint64 `json:"numSeries"`
+	} `json:"headStats"`
+	SeriesCountByMetricName    []TSDBStat `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName []TSDBStat `json:"labelValueCountByLabelName"`
+}
+
+// client reads the Prometheus API through the credential proxy.
+type client struct {
+	proxySvc   proxy.Service
+	httpClient *http.Client
+}
+
+func newClient(proxySvc proxy.Service) *client {
+	return &client{
+		proxySvc:   proxySvc,
+		httpClient: &http.Client{Transport: proxySvc.Transport(), Timeout: requestTimeout},
+	}
+}
+
+// metricNames lists the metric names of a Prometheus datasource.
+func (c *client) metricNames(ctx context.Context, datasource string) ([]string, error) {
+	var names []string
+	if err := c.get(ctx, datasource, metricNamesPath, nil, &names); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// metadata returns the metadata of every metric, or of one metric when
+// metric is set. A metric can have several entries when targets disagree.
+func (c *client) metadata(ctx context.Context, datasource, metric string) (map[string][]MetricMetadata, error) {
+	params := url.Values{}
+	if metric != "" {
+		params.Set("metric", metric)
+	}
+
+	var metadata map[string][]MetricMetadata
+	if err := c.get(ctx, datasource, metadataPath, params, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// labelNames returns the label names on the series of a metric.
+func (c *client) labelNames(ctx context.Context, datasource, metric string) ([]string, error) {
+	var names []string
+	if err := c.get(ctx, datasource, labelsPath, url.Values{"match[]": {metric}}, &names); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// tsdbStatus returns the head block cardinality statistics.
+func (c *client) tsdbStatus(ctx context.Context, datasource string) (*TSDBStatus, error) {
+	var status TSDBStatus
+	if err := c.get(ctx, datasource, tsdbStatusPath, url.Values{"limit": {strconv.Itoa(tsdbStatusLimit)}}, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// seriesCount returns the number of current series of a metric.
+func (c *client) seriesCount(ctx context.Context, datasource, metric string) (uint64, error) {
+	var result struct {
+		Result []struct {
+			Value [2]any `json:"value"`
+		} `json:"result"`
+	}
+
+	params := url.Values{"query": {fmt.Sprintf("count({__name__=%q})", metric)}}
+	if err := c.get(ctx, datasource, queryPath, params, &result); err != nil {
+		return 0, err
+	}
+
+	if len(result.Result) == 0 {
+		return 0, nil
+	}
+
+	value, _ := result.Result[0].Value[1].(string)
+
+	count, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing series count %q: %w", value, err)
+	}
+
+	return uint64(count), nil
+}
+
+// get issues a GET against the Prometheus API of a datasource and decodes
+// the "data" field of the response envelope into out.
+func (c *client) get(ctx context.Context, datasource, path string, params url.Values, out any) error {
+	baseURL := strings.TrimRight(c.proxySvc.URL(), "/")
+	if baseURL == "" {
+		return fmt.Errorf("proxy URL is empty")
+	}
+
+	reqURL := baseURL + "/prometheus" + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set(handlers.DatasourceHeader, datasource)
+
+	token := c.proxySvc.RegisterToken(tokenID)
+	defer c.proxySvc.RevokeToken(tokenID)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := c.proxySvc.SignRequest(req); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("prometheus %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+		Error  string          `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	if payload.Status != "success" {
+		return fmt.Errorf("prometheus error: %s", payload.Error)
+	}
+
+	if err := json.Unmarshal(payload.Data, out); err != nil {
+		return fmt.Errorf("decoding %s data: %w", path, err)
+	}
+
+	return nil
+}
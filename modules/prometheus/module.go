@@ -5,23 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"sort"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // Compile-time interface checks.
 var (
-	_ module.Module            = (*Module)(nil)
-	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.Module                 = (*Module)(nil)
+	_ module.ProxyDiscoverable      = (*Module)(nil)
+	_ module.ProxyAware             = (*Module)(nil)
+	_ module.CoverageTargetProvider = (*Module)(nil)
+	_ module.HealthProber           = (*Module)(nil)
+	_ module.ResourceProvider       = (*Module)(nil)
+	_ module.SchemaProvider         = (*Module)(nil)
 )
 
 // Module implements the module.Module interface for Prometheus.
 type Module struct {
 	cfg         Config
 	datasources []types.DatasourceInfo
+	proxySvc    proxy.Service
 }
 
 // New creates a new Prometheus module.
@@ -29,6 +38,12 @@ func New() *Module { return &Module{} }
 
 func (p *Module) Name() string { return "prometheus" }
 
+// SetProxyClient injects the proxy service used to read metric metadata and
+// probe health.
+func (p *Module) SetProxyClient(client proxy.Service) {
+	p.proxySvc = client
+}
+
 // InitFromDiscovery initializes the module from discovered datasources.
 func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	var filtered []types.DatasourceInfo
@@ -50,6 +65,9 @@ func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
 	return nil
 }
 
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
 // Init parses the raw YAML config for this module.
 func (p *Module) Init(rawConfig []byte) error {
 	if err := yaml.Unmarshal(rawConfig, &p.cfg); err != nil {
@@ -152,6 +170,45 @@ func (p *Module) Examples() map[string]types.ExampleCategory {
 	return result
 }
 
+// CoverageTargets reports every metric name of each Prometheus datasource
+// for examples coverage.
+func (p *Module) CoverageTargets(ctx context.Context) ([]types.CoverageTarget, error) {
+	if p.proxySvc == nil {
+		return nil, nil
+	}
+
+	var (
+		targets []types.CoverageTarget
+		client  = newClient(p.proxySvc)
+	)
+
+	for _, ds := range p.datasources {
+		names, err := client.metricNames(ctx, ds.Name)
+		if err != nil {
+			return nil, fmt.Errorf("listing metrics for %s: %w", ds.Name, err)
+		}
+
+		for _, name := range names {
+			targets = append(targets, types.CoverageTarget{
+				Kind:       types.CoverageKindPrometheusMetric,
+				Name:       name,
+				Datasource: ds.Name,
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+// ProbeHealth checks each Prometheus datasource's /-/ready endpoint through the proxy.
+func (p *Module) ProbeHealth(ctx context.Context) []types.HealthProbe {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	return module.ProbeProxyDatasources(ctx, p.proxySvc, p.datasources, "/prometheus/-/ready")
+}
+
 // PythonAPIDocs returns the Prometheus module documentation.
 func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 	return map[string]types.ModuleDoc{
@@ -207,6 +264,24 @@ func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
 	}
 }
 
+// RegisterResources registers the prometheus:// metric resources.
+func (p *Module) RegisterResources(log logrus.FieldLogger, reg module.ResourceRegistry) error {
+	if p.proxySvc == nil {
+		return nil
+	}
+
+	instances := make([]string, 0, len(p.datasources))
+	for _, ds := range p.datasources {
+		instances = append(instances, ds.Name)
+	}
+
+	sort.Strings(instances)
+
+	RegisterMetricResources(log.WithField("module", "prometheus"), reg, newClient(p.proxySvc), instances)
+
+	return nil
+}
+
 // Start performs async initialization.
 func (p *Module) Start(_ context.Context) error { return nil }
 
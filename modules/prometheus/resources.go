@@ -0,0 +1,247 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// maxListedMetrics caps the metrics prometheus://{instance}/metrics lists.
+	maxListedMetrics = 2000
+
+	// maxHighCardinality caps the metrics and labels listed by series count.
+	maxHighCardinality = 20
+
+	// highCardinalitySeries is the series count above which a metric should
+	// be aggregated or filtered before it is queried.
+	highCardinalitySeries = 10000
+)
+
+var (
+	// metricsURIPattern matches prometheus://{instance}/metrics URIs.
+	metricsURIPattern = regexp.MustCompile(`^prometheus://([^/]+)/metrics$`)
+
+	// metricURIPattern matches prometheus://{instance}/metric/{name} URIs.
+	metricURIPattern = regexp.MustCompile(`^prometheus://([^/]+)/metric/([a-zA-Z_:][a-zA-Z0-9_:]*)$`)
+)
+
+// MetricSummary is one metric in prometheus://{instance}/metrics.
+type MetricSummary struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	// Series is set for the metrics with the most series.
+	Series uint64 `json:"series,omitempty"`
+}
+
+// MetricsResponse is the response for prometheus://{instance}/metrics.
+type MetricsResponse struct {
+	Instance    string          `json:"instance"`
+	TotalSeries uint64          `json:"total_series"`
+	Metrics     []MetricSummary `json:"metrics"`
+	// Omitted is the number of metrics left out of Metrics.
+	Omitted int `json:"omitted,omitempty"`
+	// HighCardinality lists the metrics with the most series, largest first.
+	HighCardinality []TSDBStat `json:"high_cardinality,omitempty"`
+	// HighCardinalityLabels lists the labels with the most values.
+	HighCardinalityLabels []TSDBStat `json:"high_cardinality_labels,omitempty"`
+	Usage                 string     `json:"usage"`
+}
+
+// MetricResponse is the response for prometheus://{instance}/metric/{name}.
+type MetricResponse struct {
+	Instance string           `json:"instance"`
+	Name     string           `json:"name"`
+	Metadata []MetricMetadata `json:"metadata,omitempty"`
+	Labels   []string         `json:"labels"`
+	Series   uint64           `json:"series"`
+	Warning  string           `json:"warning,omitempty"`
+}
+
+// metricSource reads metric metadata and cardinality of a Prometheus
+// instance.
+type metricSource interface {
+	metricNames(ctx context.Context, instance string) ([]string, error)
+	metadata(ctx context.Context, instance, metric string) (map[string][]MetricMetadata, error)
+	labelNames(ctx context.Context, instance, metric string) ([]string, error)
+	tsdbStatus(ctx context.Context, instance string) (*TSDBStatus, error)
+	seriesCount(ctx context.Context, instance, metric string) (uint64, error)
+}
+
+// RegisterMetricResources registers the prometheus:// metric resources.
+func RegisterMetricResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	source metricSource,
+	instances []string,
+) {
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"prometheus://{instance}/metrics",
+			"Prometheus Metrics",
+			mcp.WithTemplateDescription("Metric names and types on a Prometheus instance, with the metrics and labels that have the most series"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Pattern: metricsURIPattern,
+		Handler: createMetricsHandler(source, instances),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"prometheus://{instance}/metric/{name}",
+			"Prometheus Metric",
+			mcp.WithTemplateDescription("Type, help, label names and current series count of a metric, to check it exists and how costly it is to query"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Pattern: metricURIPattern,
+		Handler: createMetricHandler(source, instances),
+	})
+
+	log.Debug("Registered Prometheus resources")
+}
+
+func createMetricsHandler(source metricSource, instances []string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := metricsURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid Prometheus metrics URI: %s", uri)
+		}
+
+		instance := matches[1]
+		if !slices.Contains(instances, instance) {
+			return "", fmt.Errorf("unknown Prometheus instance %q. Available: %v", instance, instances)
+		}
+
+		names, err := source.metricNames(ctx, instance)
+		if err != nil {
+			return "", err
+		}
+
+		metadata, err := source.metadata(ctx, instance, "")
+		if err != nil {
+			return "", err
+		}
+
+		status, err := source.tsdbStatus(ctx, instance)
+		if err != nil {
+			return "", err
+		}
+
+		series := make(map[string]uint64, len(status.SeriesCountByMetricName))
+		for _, stat := range status.SeriesCountByMetricName {
+			series[stat.Name] = stat.Value
+		}
+
+		sort.Strings(names)
+
+		response := &MetricsResponse{
+			Instance:              instance,
+			TotalSeries:           status.HeadStats.NumSeries,
+			Metrics:               make([]MetricSummary, 0, min(len(names), maxListedMetrics)),
+			HighCardinality:       topStats(status.SeriesCountByMetricName),
+			HighCardinalityLabels: topStats(status.LabelValueCountByLabelName),
+			Usage:                 "Read prometheus://" + instance + "/metric/{name} for a metric's help, labels and series count before querying it.",
+		}
+
+		for _, name := range names {
+			if len(response.Metrics) == maxListedMetrics {
+				response.Omitted = len(names) - maxListedMetrics
+				break
+			}
+
+			summary := MetricSummary{Name: name, Series: series[name]}
+			if entries := metadata[name]; len(entries) > 0 {
+				summary.Type = entries[0].Type
+			}
+
+			response.Metrics = append(response.Metrics, summary)
+		}
+
+		return marshal(response)
+	}
+}
+
+func createMetricHandler(source metricSource, instances []string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := metricURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 3 {
+			return "", fmt.Errorf("invalid Prometheus metric URI: %s", uri)
+		}
+
+		instance, name := matches[1], matches[2]
+		if !slices.Contains(instances, instance) {
+			return "", fmt.Errorf("unknown Prometheus instance %q. Available: %v", instance, instances)
+		}
+
+		metadata, err := source.metadata(ctx, instance, name)
+		if err != nil {
+			return "", err
+		}
+
+		labels, err := source.labelNames(ctx, instance, name)
+		if err != nil {
+			return "", err
+		}
+
+		count, err := source.seriesCount(ctx, instance, name)
+		if err != nil {
+			return "", err
+		}
+
+		if count == 0 && len(metadata[name]) == 0 {
+			return "", fmt.Errorf("metric %q not found on Prometheus instance %q. Read prometheus://%s/metrics for valid names", name, instance, instance)
+		}
+
+		response := &MetricResponse{
+			Instance: instance,
+			Name:     name,
+			Metadata: metadata[name],
+			Labels:   labels,
+			Series:   count,
+		}
+
+		if response.Labels == nil {
+			response.Labels = make([]string, 0)
+		}
+
+		if count > highCardinalitySeries {
+			response.Warning = fmt.Sprintf(
+				"%d series. Filter by labels or aggregate with sum by (...) rather than selecting the raw metric.", count,
+			)
+		}
+
+		return marshal(response)
+	}
+}
+
+// topStats returns the first maxHighCardinality stats, largest first.
+func topStats(stats []TSDBStat) []TSDBStat {
+	sorted := append([]TSDBStat(nil), stats...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+
+	if len(sorted) > maxHighCardinality {
+		sorted = sorted[:maxHighCardinality]
+	}
+
+	return sorted
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling Prometheus response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,116 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	names  []string
+	meta   map[string][]MetricMetadata
+	labels map[string][]string
+	status *TSDBStatus
+	counts map[string]uint64
+}
+
+func (f *fakeSource) metricNames(_ context.Context, _ string) ([]string, error) {
+	return f.names, nil
+}
+
+func (f *fakeSource) metadata(_ context.Context, _, metric string) (map[string][]MetricMetadata, error) {
+	if metric == "" {
+		return f.meta, nil
+	}
+
+	if entries, ok := f.meta[metric]; ok {
+		return map[string][]MetricMetadata{metric: entries}, nil
+	}
+
+	return map[string][]MetricMetadata{}, nil
+}
+
+func (f *fakeSource) labelNames(_ context.Context, _, metric string) ([]string, error) {
+	return f.labels[metric], nil
+}
+
+func (f *fakeSource) tsdbStatus(_ context.Context, _ string) (*TSDBStatus, error) {
+	return f.status, nil
+}
+
+func (f *fakeSource) seriesCount(_ context.Context, _, metric string) (uint64, error) {
+	return f.counts[metric], nil
+}
+
+func newFakeSource() *fakeSource {
+	status := &TSDBStatus{
+		SeriesCountByMetricName: []TSDBStat{
+			{Name: "up", Value: 40},
+			{Name: "beacon_peers", Value: 25000},
+		},
+		LabelValueCountByLabelName: []TSDBStat{{Name: "instance", Value: 40}},
+	}
+	status.HeadStats.NumSeries = 25040
+
+	return &fakeSource{
+		names: []string{"up", "beacon_peers", "go_goroutines"},
+		meta: map[string][]MetricMetadata{
+			"up":           {{Type: "gauge", Help: "Target is up"}},
+			"beacon_peers": {{Type: "gauge", Help: "Connected peers"}},
+		},
+		labels: map[string][]string{"beacon_peers": {"__name__", "instance", "client"}},
+		status: status,
+		counts: map[string]uint64{"beacon_peers": 25000, "up": 40},
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	handler := createMetricsHandler(newFakeSource(), []string{"ops"})
+
+	out, err := handler(context.Background(), "prometheus://ops/metrics")
+	require.NoError(t, err)
+
+	var resp MetricsResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, uint64(25040), resp.TotalSeries)
+	assert.Equal(t, []MetricSummary{
+		{Name: "beacon_peers", Type: "gauge", Series: 25000},
+		{Name: "go_goroutines"},
+		{Name: "up", Type: "gauge", Series: 40},
+	}, resp.Metrics)
+	require.Len(t, resp.HighCardinality, 2)
+	assert.Equal(t, "beacon_peers", resp.HighCardinality[0].Name)
+
+	_, err = handler(context.Background(), "prometheus://staging/metrics")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown Prometheus instance "staging"`)
+}
+
+func TestMetricHandler(t *testing.T) {
+	handler := createMetricHandler(newFakeSource(), []string{"ops"})
+
+	out, err := handler(context.Background(), "prometheus://ops/metric/beacon_peers")
+	require.NoError(t, err)
+
+	var resp MetricResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "Connected peers", resp.Metadata[0].Help)
+	assert.Equal(t, []string{"__name__", "instance", "client"}, resp.Labels)
+	assert.Equal(t, uint64(25000), resp.Series)
+	assert.NotEmpty(t, resp.Warning)
+
+	out, err = handler(context.Background(), "prometheus://ops/metric/up")
+	require.NoError(t, err)
+
+	var up MetricResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &up))
+	assert.Empty(t, up.Warning)
+	assert.Empty(t, up.Labels)
+
+	_, err = handler(context.Background(), "prometheus://ops/metric/missing_metric")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,19 @@
+package self
+
+// Config holds the self-monitoring module configuration.
+// The module is enabled by default since the server's own metrics need no
+// credentials.
+type Config struct {
+	// Enabled controls whether the self module is active.
+	// Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+}
+
+// IsEnabled returns true if the module is enabled (default: true).
+func (c *Config) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
@@ -0,0 +1,156 @@
+package self
+
+import (
+	"context"
+	"maps"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Compile-time interface checks.
+var (
+	_ module.Module            = (*Module)(nil)
+	_ module.ProxyDiscoverable = (*Module)(nil)
+	_ module.DefaultEnabled    = (*Module)(nil)
+	_ module.EnabledAware      = (*Module)(nil)
+	_ module.SchemaProvider    = (*Module)(nil)
+)
+
+// Module implements the module.Module interface for self-monitoring.
+// Live snapshots of the server's own registry are always available; history
+// needs a proxy Prometheus instance flagged self_monitoring that scrapes the
+// panda server and proxy /metrics endpoints.
+type Module struct {
+	cfg        Config
+	datasource string
+}
+
+// New creates a new self module.
+func New() *Module {
+	return &Module{}
+}
+
+func (p *Module) Name() string { return "self" }
+
+// Enabled reports whether self operations should be exposed.
+func (p *Module) Enabled() bool { return p.cfg.IsEnabled() }
+
+// DefaultEnabled implements module.DefaultEnabled.
+// The server's own metrics are always available, so no config is needed.
+func (p *Module) DefaultEnabled() bool { return true }
+
+// Datasource returns the proxy Prometheus datasource that scrapes the
+// deployment, or "" when none is configured.
+func (p *Module) Datasource() string { return p.datasource }
+
+// InitFromDiscovery picks the Prometheus datasource flagged self_monitoring.
+// Without one the module falls back to default-enabled snapshot-only mode.
+func (p *Module) InitFromDiscovery(datasources []types.DatasourceInfo) error {
+	for _, ds := range datasources {
+		if ds.Type == "prometheus" && ds.Metadata["self_monitoring"] == "true" {
+			p.datasource = ds.Name
+
+			return nil
+		}
+	}
+
+	return module.ErrNoValidConfig
+}
+
+// ConfigSchema implements module.SchemaProvider.
+func (p *Module) ConfigSchema() any { return Config{} }
+
+func (p *Module) Init(rawConfig []byte) error {
+	if len(rawConfig) == 0 {
+		return nil
+	}
+
+	return yaml.Unmarshal(rawConfig, &p.cfg)
+}
+
+func (p *Module) ApplyDefaults() {
+	// Defaults are handled by Config.IsEnabled().
+}
+
+func (p *Module) Validate() error {
+	// The datasource is checked against the proxy when queried.
+	return nil
+}
+
+// SandboxEnv returns environment variables for the sandbox.
+// Returns ETHPANDAOPS_SELF_DATASOURCE when a scraping datasource was discovered.
+func (p *Module) SandboxEnv() (map[string]string, error) {
+	if !p.cfg.IsEnabled() || p.datasource == "" {
+		return nil, nil
+	}
+
+	return map[string]string{
+		"ETHPANDAOPS_SELF_DATASOURCE": p.datasource,
+	}, nil
+}
+
+// DatasourceInfo returns empty since the scraping datasource is already
+// listed by the Prometheus module.
+func (p *Module) DatasourceInfo() []types.DatasourceInfo {
+	return nil
+}
+
+func (p *Module) Examples() map[string]types.ExampleCategory {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	result := make(map[string]types.ExampleCategory, len(queryExamples))
+	maps.Copy(result, queryExamples)
+
+	return result
+}
+
+func (p *Module) PythonAPIDocs() map[string]types.ModuleDoc {
+	if !p.cfg.IsEnabled() {
+		return nil
+	}
+
+	return map[string]types.ModuleDoc{
+		"self_metrics": {
+			Description: "Inspect the panda deployment's own metrics (tool calls, sandbox load, proxy rate limiting)",
+			Functions: map[string]types.FunctionDoc{
+				"snapshot":    {Signature: "snapshot(prefix='panda_') -> list[dict]", Description: "Current samples from the server's own metrics registry"},
+				"datasource":  {Signature: "datasource() -> str | None", Description: "Prometheus datasource scraping the deployment, if configured"},
+				"query":       {Signature: "query(promql, time=None) -> dict", Description: "Instant PromQL query against the deployment's datasource"},
+				"query_range": {Signature: "query_range(promql, start, end, step) -> dict", Description: "Range PromQL query against the deployment's datasource"},
+			},
+		},
+	}
+}
+
+func (p *Module) GettingStartedSnippet() string {
+	if !p.cfg.IsEnabled() {
+		return ""
+	}
+
+	return `## Self-Monitoring
+
+Inspect the panda deployment itself: tool call errors, sandbox load and
+proxy rate limiting. Search runbooks for "panda deployment" to diagnose it.
+
+` + "```python" + `
+from ethpandaops import self_metrics
+
+# Live values from the server's own metrics registry
+for sample in self_metrics.snapshot("panda_sandbox_executions_in_flight"):
+    print(sample["labels"], sample["value"])
+
+# History, when a Prometheus datasource scrapes the deployment
+if self_metrics.datasource():
+    result = self_metrics.query("sum(rate(panda_proxy_rate_limit_rejections_total[5m]))")
+` + "```" + `
+`
+}
+
+func (p *Module) Start(_ context.Context) error { return nil }
+
+func (p *Module) Stop(_ context.Context) error { return nil }
@@ -0,0 +1,66 @@
+package self
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	calls := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "panda_tool_calls_total"}, []string{"tool"})
+	calls.WithLabelValues("search").Add(3)
+
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "panda_sandbox_execution_duration_seconds",
+		Buckets: []float64{1, 5},
+	})
+	duration.Observe(2)
+
+	other := prometheus.NewGauge(prometheus.GaugeOpts{Name: "go_goroutines_fake"})
+
+	reg.MustRegister(calls, duration, other)
+
+	samples, err := Snapshot(reg, "panda_")
+	require.NoError(t, err)
+
+	byKey := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		byKey[sample.Name+"{"+sample.Labels["tool"]+sample.Labels["le"]+"}"] = sample.Value
+	}
+
+	assert.Equal(t, map[string]float64{
+		"panda_sandbox_execution_duration_seconds_bucket{1}":    0,
+		"panda_sandbox_execution_duration_seconds_bucket{5}":    1,
+		"panda_sandbox_execution_duration_seconds_bucket{+Inf}": 1,
+		"panda_sandbox_execution_duration_seconds_sum{}":        2,
+		"panda_sandbox_execution_duration_seconds_count{}":      1,
+		"panda_tool_calls_total{search}":                        3,
+	}, byKey)
+}
+
+func TestInitFromDiscovery(t *testing.T) {
+	p := New()
+
+	err := p.InitFromDiscovery([]types.DatasourceInfo{{Type: "prometheus", Name: "primary"}})
+	require.ErrorIs(t, err, module.ErrNoValidConfig)
+
+	env, err := p.SandboxEnv()
+	require.NoError(t, err)
+	assert.Empty(t, env)
+
+	require.NoError(t, p.InitFromDiscovery([]types.DatasourceInfo{
+		{Type: "prometheus", Name: "primary"},
+		{Type: "prometheus", Name: "panda", Metadata: map[string]string{"self_monitoring": "true"}},
+	}))
+
+	env, err = p.SandboxEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ETHPANDAOPS_SELF_DATASOURCE": "panda"}, env)
+}
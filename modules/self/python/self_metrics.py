@@ -0,0 +1,52 @@
+"""Thin self-monitoring wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def datasource() -> str | None:
+    """Return the Prometheus datasource scraping this deployment, if any."""
+    return os.environ.get("ETHPANDAOPS_SELF_DATASOURCE", "").strip() or None
+
+
+def _require_datasource() -> str:
+    name = datasource()
+    if name is None:
+        raise ValueError(
+            "No Prometheus datasource scrapes this deployment. "
+            "Flag one proxy Prometheus instance with self_monitoring: true, or use snapshot()."
+        )
+    return name
+
+
+def snapshot(prefix: str = "panda_") -> list[dict[str, Any]]:
+    data = _runtime.invoke_data("self.metrics", {"prefix": prefix})
+    return data.get("samples", [])
+
+
+def query(promql: str, time: str | None = None) -> dict[str, Any]:
+    return _runtime.invoke_json_data(
+        "prometheus.query",
+        {
+            "datasource": _require_datasource(),
+            "query": promql,
+            "time": time,
+        },
+    )
+
+
+def query_range(promql: str, start: str, end: str, step: str) -> dict[str, Any]:
+    return _runtime.invoke_json_data(
+        "prometheus.query_range",
+        {
+            "datasource": _require_datasource(),
+            "query": promql,
+            "start": start,
+            "end": end,
+            "step": step,
+        },
+    )
@@ -0,0 +1,110 @@
+package self
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Sample is a single metric sample from the server's own registry, in the
+// flattened form of the Prometheus text format (histograms expand to
+// _bucket, _sum and _count samples).
+type Sample struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Snapshot gathers the current value of every metric whose name starts with
+// prefix. An empty prefix returns all metrics.
+func Snapshot(gatherer prometheus.Gatherer, prefix string) ([]Sample, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("gathering metrics: %w", err)
+	}
+
+	var samples []Sample
+
+	for _, family := range families {
+		name := family.GetName()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		metricType := strings.ToLower(family.GetType().String())
+
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			add := func(suffix string, value float64, extra ...string) {
+				sampleLabels := labels
+				if len(extra) > 0 {
+					sampleLabels = make(map[string]string, len(labels)+1)
+					for k, v := range labels {
+						sampleLabels[k] = v
+					}
+
+					sampleLabels[extra[0]] = extra[1]
+				}
+
+				samples = append(samples, Sample{
+					Name:   name + suffix,
+					Type:   metricType,
+					Labels: sampleLabels,
+					Value:  value,
+				})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", metric.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", metric.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				histogram := metric.GetHistogram()
+				hasInf := false
+
+				for _, bucket := range histogram.GetBucket() {
+					hasInf = hasInf || math.IsInf(bucket.GetUpperBound(), 1)
+					add("_bucket", float64(bucket.GetCumulativeCount()), "le", formatFloat(bucket.GetUpperBound()))
+				}
+
+				if !hasInf {
+					add("_bucket", float64(histogram.GetSampleCount()), "le", "+Inf")
+				}
+
+				add("_sum", histogram.GetSampleSum())
+				add("_count", float64(histogram.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				summary := metric.GetSummary()
+				for _, quantile := range summary.GetQuantile() {
+					add("", quantile.GetValue(), "quantile", formatFloat(quantile.GetQuantile()))
+				}
+
+				add("_sum", summary.GetSampleSum())
+				add("_count", float64(summary.GetSampleCount()))
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+// formatFloat renders le and quantile label values as Prometheus does.
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
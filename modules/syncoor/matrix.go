@@ -0,0 +1,336 @@
+package syncoor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// ConfigPath lists the report directories a syncoor instance serves.
+	ConfigPath = "/config.json"
+
+	// indexFile is the report index in each report directory.
+	indexFile = "index.json"
+
+	// maxIndexSize bounds a report index that is read.
+	maxIndexSize = 32 << 20
+
+	// maxRunsPerPair caps the sync tests per client pair the matrix
+	// aggregates, newest first.
+	maxRunsPerPair = 10
+
+	// statusSuccess is the status of a sync test that reached the head.
+	statusSuccess = "success"
+
+	// statusRunning is reported for tests that have not finished.
+	statusRunning = "running"
+)
+
+// errNotFound is returned when a syncoor file does not exist.
+var errNotFound = errors.New("not found")
+
+// ClientInfo identifies the client a sync test ran.
+type ClientInfo struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Image   string `json:"image,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// key returns the client type, falling back to its name.
+func (c ClientInfo) key() string {
+	if c.Type != "" {
+		return c.Type
+	}
+
+	return c.Name
+}
+
+// IndexEntry is one sync test in a syncoor report index.
+type IndexEntry struct {
+	RunID               string     `json:"run_id"`
+	Timestamp           int64      `json:"timestamp"`
+	Network             string     `json:"network"`
+	ExecutionClientInfo ClientInfo `json:"execution_client_info"`
+	ConsensusClientInfo ClientInfo `json:"consensus_client_info"`
+	SyncInfo            struct {
+		Start  int64  `json:"start"`
+		End    int64  `json:"end"`
+		Block  uint64 `json:"block"`
+		Slot   uint64 `json:"slot"`
+		Status string `json:"status"`
+	} `json:"sync_info"`
+}
+
+// status returns the test's status. Entries without one count as successful
+// once they have an end time.
+func (e IndexEntry) status() string {
+	switch {
+	case e.SyncInfo.Status != "":
+		return strings.ToLower(e.SyncInfo.Status)
+	case e.SyncInfo.End > e.SyncInfo.Start:
+		return statusSuccess
+	default:
+		return statusRunning
+	}
+}
+
+// Matrix aggregates recent sync tests by execution and consensus client.
+type Matrix struct {
+	Network          string       `json:"network"`
+	SyncoorURL       string       `json:"syncoor_url"`
+	ExecutionClients []string     `json:"execution_clients"`
+	ConsensusClients []string     `json:"consensus_clients"`
+	Cells            []MatrixCell `json:"cells"`
+	// Runs is the number of sync tests aggregated into the cells.
+	Runs int `json:"runs"`
+}
+
+// MatrixCell is the recent sync tests of one client pair. Running tests count
+// toward Runs but neither Passed nor Failed.
+type MatrixCell struct {
+	ExecutionClient string `json:"execution_client"`
+	ConsensusClient string `json:"consensus_client"`
+	Runs            int    `json:"runs"`
+	Passed          int    `json:"passed"`
+	Failed          int    `json:"failed"`
+	LastStatus      string `json:"last_status"`
+	LastRunID       string `json:"last_run_id"`
+	LastRunAt       int64  `json:"last_run_at"`
+	// LastDurationSeconds is the duration of the last finished test.
+	LastDurationSeconds int64 `json:"last_duration_seconds,omitempty"`
+	// AvgDurationSeconds is the mean duration of the passed tests.
+	AvgDurationSeconds int64  `json:"avg_duration_seconds,omitempty"`
+	ExecutionVersion   string `json:"execution_version,omitempty"`
+	ConsensusVersion   string `json:"consensus_version,omitempty"`
+}
+
+// FetchMatrix reads the report indexes of a syncoor instance and aggregates
+// the network's sync tests into a client matrix.
+func FetchMatrix(ctx context.Context, httpClient *http.Client, network, baseURL string) (*Matrix, error) {
+	entries, err := FetchIndex(ctx, httpClient, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildMatrix(network, baseURL, entries), nil
+}
+
+// FetchIndex returns the sync tests of every enabled report directory listed
+// in the instance's config.json, or of its root index.json when it has no
+// config.
+func FetchIndex(ctx context.Context, httpClient *http.Client, baseURL string) ([]IndexEntry, error) {
+	base, err := url.Parse(strings.TrimRight(baseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("parsing syncoor URL: %w", err)
+	}
+
+	directories := []*url.URL{base}
+
+	body, err := get(ctx, httpClient, base.ResolveReference(&url.URL{Path: strings.TrimPrefix(ConfigPath, "/")}).String())
+
+	switch {
+	case errors.Is(err, errNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		if directories, err = parseDirectories(base, body); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []IndexEntry
+
+	for _, dir := range directories {
+		body, err := get(ctx, httpClient, dir.ResolveReference(&url.URL{Path: indexFile}).String())
+		if err != nil {
+			return nil, err
+		}
+
+		var index struct {
+			Entries []IndexEntry `json:"entries"`
+		}
+
+		if err := json.Unmarshal(body, &index); err != nil {
+			return nil, fmt.Errorf("decoding syncoor index %s: %w", dir, err)
+		}
+
+		entries = append(entries, index.Entries...)
+	}
+
+	return entries, nil
+}
+
+// parseDirectories returns the enabled report directory URLs of a syncoor
+// config.json, resolved against base.
+func parseDirectories(base *url.URL, body []byte) ([]*url.URL, error) {
+	var config struct {
+		Directories []struct {
+			Name    string `json:"name"`
+			URL     string `json:"url"`
+			Enabled *bool  `json:"enabled"`
+		} `json:"directories"`
+	}
+
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("decoding syncoor config: %w", err)
+	}
+
+	directories := make([]*url.URL, 0, len(config.Directories))
+
+	for _, dir := range config.Directories {
+		if dir.Enabled != nil && !*dir.Enabled {
+			continue
+		}
+
+		ref, err := url.Parse(strings.TrimRight(dir.URL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("syncoor directory %q: invalid url %q", dir.Name, dir.URL)
+		}
+
+		directories = append(directories, base.ResolveReference(ref))
+	}
+
+	return directories, nil
+}
+
+// BuildMatrix aggregates the newest maxRunsPerPair sync tests of each client
+// pair. Entries for another network are skipped.
+func BuildMatrix(network, baseURL string, entries []IndexEntry) *Matrix {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp > entries[j].Timestamp })
+
+	type pair struct{ el, cl string }
+
+	cells := make(map[pair]*MatrixCell)
+	durations := make(map[pair]int64)
+	executionClients := make(map[string]struct{})
+	consensusClients := make(map[string]struct{})
+
+	matrix := &Matrix{Network: network, SyncoorURL: baseURL}
+
+	for _, entry := range entries {
+		if entry.Network != "" && entry.Network != network {
+			continue
+		}
+
+		key := pair{el: entry.ExecutionClientInfo.key(), cl: entry.ConsensusClientInfo.key()}
+		if key.el == "" || key.cl == "" {
+			continue
+		}
+
+		cell, ok := cells[key]
+		if !ok {
+			// Entries are newest first, so the first seen is the last run.
+			cell = &MatrixCell{
+				ExecutionClient:  key.el,
+				ConsensusClient:  key.cl,
+				LastStatus:       entry.status(),
+				LastRunID:        entry.RunID,
+				LastRunAt:        entry.Timestamp,
+				ExecutionVersion: entry.ExecutionClientInfo.Version,
+				ConsensusVersion: entry.ConsensusClientInfo.Version,
+			}
+			cells[key] = cell
+			executionClients[key.el] = struct{}{}
+			consensusClients[key.cl] = struct{}{}
+		}
+
+		if cell.Runs >= maxRunsPerPair {
+			continue
+		}
+
+		cell.Runs++
+		matrix.Runs++
+
+		status := entry.status()
+		duration := entry.SyncInfo.End - entry.SyncInfo.Start
+
+		if status != statusRunning && cell.LastDurationSeconds == 0 && duration > 0 {
+			cell.LastDurationSeconds = duration
+		}
+
+		switch status {
+		case statusSuccess:
+			cell.Passed++
+
+			if duration > 0 {
+				durations[key] += duration
+			}
+		case statusRunning:
+		default:
+			cell.Failed++
+		}
+	}
+
+	matrix.Cells = make([]MatrixCell, 0, len(cells))
+
+	for key, cell := range cells {
+		if cell.Passed > 0 {
+			cell.AvgDurationSeconds = durations[key] / int64(cell.Passed)
+		}
+
+		matrix.Cells = append(matrix.Cells, *cell)
+	}
+
+	sort.Slice(matrix.Cells, func(i, j int) bool {
+		if matrix.Cells[i].ExecutionClient != matrix.Cells[j].ExecutionClient {
+			return matrix.Cells[i].ExecutionClient < matrix.Cells[j].ExecutionClient
+		}
+
+		return matrix.Cells[i].ConsensusClient < matrix.Cells[j].ConsensusClient
+	})
+
+	matrix.ExecutionClients = sortedKeys(executionClients)
+	matrix.ConsensusClients = sortedKeys(consensusClients)
+
+	return matrix
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// get reads a syncoor file, returning errNotFound on a 404.
+func get(ctx context.Context, httpClient *http.Client, fileURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating syncoor request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting syncoor %s: %w", fileURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("syncoor %s: %w", fileURL, errNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("syncoor %s returned %d", fileURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading syncoor %s: %w", fileURL, err)
+	}
+
+	return body, nil
+}
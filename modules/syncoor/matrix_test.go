@@ -0,0 +1,110 @@
+package syncoor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(runID string, ts int64, el, cl, status string, start, end int64) IndexEntry {
+	e := IndexEntry{
+		RunID:               runID,
+		Timestamp:           ts,
+		Network:             "hoodi",
+		ExecutionClientInfo: ClientInfo{Type: el},
+		ConsensusClientInfo: ClientInfo{Type: cl},
+	}
+	e.SyncInfo.Start = start
+	e.SyncInfo.End = end
+	e.SyncInfo.Status = status
+
+	return e
+}
+
+func TestBuildMatrix(t *testing.T) {
+	entries := []IndexEntry{
+		entry("a", 100, "geth", "lighthouse", "success", 0, 3600),
+		entry("b", 200, "geth", "lighthouse", "timeout", 0, 7200),
+		entry("c", 150, "geth", "lighthouse", "", 0, 1800),
+		entry("d", 300, "nethermind", "teku", "", 0, 0),
+		entry("e", 400, "reth", "prysm", "success", 0, 600),
+	}
+	entries[4].Network = "sepolia"
+
+	matrix := BuildMatrix("hoodi", "https://syncoor.hoodi", entries)
+
+	assert.Equal(t, []string{"geth", "nethermind"}, matrix.ExecutionClients)
+	assert.Equal(t, []string{"lighthouse", "teku"}, matrix.ConsensusClients)
+	assert.Equal(t, 4, matrix.Runs)
+	require.Len(t, matrix.Cells, 2)
+
+	geth := matrix.Cells[0]
+	assert.Equal(t, "geth", geth.ExecutionClient)
+	assert.Equal(t, 3, geth.Runs)
+	assert.Equal(t, 2, geth.Passed)
+	assert.Equal(t, 1, geth.Failed)
+	assert.Equal(t, "timeout", geth.LastStatus)
+	assert.Equal(t, "b", geth.LastRunID)
+	assert.Equal(t, int64(7200), geth.LastDurationSeconds)
+	assert.Equal(t, int64(2700), geth.AvgDurationSeconds)
+
+	nethermind := matrix.Cells[1]
+	assert.Equal(t, statusRunning, nethermind.LastStatus)
+	assert.Zero(t, nethermind.Passed+nethermind.Failed)
+}
+
+func TestMatrixHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"directories":[
+			{"name":"hoodi","url":"reports/hoodi","enabled":true},
+			{"name":"old","url":"reports/old","enabled":false}
+		]}`))
+	})
+	mux.HandleFunc("/reports/hoodi/index.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"entries":[{
+			"run_id":"r1","timestamp":1000,"network":"hoodi",
+			"execution_client_info":{"name":"geth-1","type":"geth","version":"v1.16.0"},
+			"consensus_client_info":{"name":"lighthouse-1","type":"lighthouse"},
+			"sync_info":{"start":1000,"end":4600,"status":"success"}
+		}]}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	networks := func() map[string]string { return map[string]string{"hoodi": srv.URL} }
+
+	out, err := createMatrixHandler(srv.Client(), networks)(context.Background(), "syncoor://network/hoodi/matrix")
+	require.NoError(t, err)
+
+	var matrix Matrix
+	require.NoError(t, json.Unmarshal([]byte(out), &matrix))
+	require.Len(t, matrix.Cells, 1)
+	assert.Equal(t, "v1.16.0", matrix.Cells[0].ExecutionVersion)
+	assert.Equal(t, int64(3600), matrix.Cells[0].LastDurationSeconds)
+
+	_, err = createMatrixHandler(srv.Client(), networks)(context.Background(), "syncoor://network/mainnet/matrix")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown network "mainnet"`)
+}
+
+func TestFetchIndexWithoutConfig(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"entries":[{"run_id":"r1"}]}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	entries, err := FetchIndex(context.Background(), srv.Client(), srv.URL)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "r1", entries[0].RunID)
+}
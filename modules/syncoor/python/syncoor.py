@@ -0,0 +1,30 @@
+"""Thin syncoor wrappers over server operations."""
+
+from __future__ import annotations
+
+import os
+from typing import Any
+
+from ethpandaops import _runtime
+
+
+def _require_syncoor_available() -> None:
+    if not os.environ.get("ETHPANDAOPS_SYNCOOR_NETWORKS", "").strip():
+        raise ValueError("Syncoor is not enabled or no syncoor instances are available.")
+
+
+def list_networks() -> list[dict[str, str]]:
+    _require_syncoor_available()
+    data = _runtime.invoke_data("syncoor.list_networks")
+    return data.get("networks", [])
+
+
+def get_base_url(network: str) -> str:
+    _require_syncoor_available()
+    data = _runtime.invoke_data("syncoor.get_base_url", {"network": network})
+    return data.get("base_url", "")
+
+
+def get_matrix(network: str) -> dict[str, Any]:
+    _require_syncoor_available()
+    return _runtime.invoke_data("syncoor.get_matrix", {"network": network})
@@ -0,0 +1,132 @@
+package syncoor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// requestTimeout bounds the syncoor calls made for a resource read.
+const requestTimeout = 30 * time.Second
+
+// matrixURIPattern matches syncoor://network/{name}/matrix URIs.
+var matrixURIPattern = regexp.MustCompile(`^syncoor://network/([^/]+)/matrix$`)
+
+// NetworkInstance is a network with a syncoor instance.
+type NetworkInstance struct {
+	Name       string `json:"name"`
+	SyncoorURL string `json:"syncoor_url"`
+}
+
+// NetworksListResponse is the response for syncoor://networks.
+type NetworksListResponse struct {
+	Description string            `json:"description"`
+	Networks    []NetworkInstance `json:"networks"`
+	Usage       string            `json:"usage"`
+}
+
+// RegisterNetworkResources registers the syncoor:// resources. networks
+// returns the current network -> syncoor URL mapping.
+func RegisterNetworkResources(
+	log logrus.FieldLogger,
+	reg module.ResourceRegistry,
+	httpClient *http.Client,
+	networks func() map[string]string,
+) {
+	reg.RegisterStatic(types.StaticResource{
+		Resource: mcp.NewResource(
+			"syncoor://networks",
+			"Syncoor Instances",
+			mcp.WithResourceDescription("Networks with a syncoor sync test runner"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createNetworksListHandler(networks),
+	})
+
+	reg.RegisterTemplate(types.TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"syncoor://network/{name}/matrix",
+			"Syncoor Client Matrix",
+			mcp.WithTemplateDescription("Recent sync tests aggregated into an execution x consensus client matrix: pass/fail counts, last status and sync durations per client pair"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Pattern: matrixURIPattern,
+		Handler: createMatrixHandler(httpClient, networks),
+	})
+
+	log.Debug("Registered syncoor resources")
+}
+
+func createNetworksListHandler(networks func() map[string]string) types.ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		instances := networks()
+
+		response := &NetworksListResponse{
+			Description: "Networks with a syncoor sync test runner.",
+			Networks:    make([]NetworkInstance, 0, len(instances)),
+			Usage:       "Read syncoor://network/{name}/matrix for client pair sync health.",
+		}
+
+		for name, baseURL := range instances {
+			response.Networks = append(response.Networks, NetworkInstance{Name: name, SyncoorURL: baseURL})
+		}
+
+		sort.Slice(response.Networks, func(i, j int) bool {
+			return response.Networks[i].Name < response.Networks[j].Name
+		})
+
+		return marshal(response)
+	}
+}
+
+func createMatrixHandler(httpClient *http.Client, networks func() map[string]string) types.ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := matrixURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid syncoor URI: %s", uri)
+		}
+
+		network := matches[1]
+		instances := networks()
+
+		baseURL, ok := instances[network]
+		if !ok {
+			names := make([]string, 0, len(instances))
+			for name := range instances {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return "", fmt.Errorf("unknown network %q. Available: %v", network, names)
+		}
+
+		matrix, err := FetchMatrix(ctx, httpClient, network, baseURL)
+		if err != nil {
+			return "", err
+		}
+
+		return marshal(matrix)
+	}
+}
+
+func marshal(v any) (string, error) {
+	data, err := canonicaljson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling syncoor response: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -13,18 +14,36 @@ import (
 	"github.com/ethpandaops/panda/pkg/cartographoor"
 	"github.com/ethpandaops/panda/pkg/config"
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/offline"
 	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/sandbox"
 	"github.com/ethpandaops/panda/pkg/types"
 
+	assertoormodule "github.com/ethpandaops/panda/modules/assertoor"
+	beaconmodule "github.com/ethpandaops/panda/modules/beacon"
+	blobscanmodule "github.com/ethpandaops/panda/modules/blobscan"
 	cbtmodule "github.com/ethpandaops/panda/modules/cbt"
+	checkpointzmodule "github.com/ethpandaops/panda/modules/checkpointz"
 	clickhousemodule "github.com/ethpandaops/panda/modules/clickhouse"
 	doramodule "github.com/ethpandaops/panda/modules/dora"
 	ethnodemodule "github.com/ethpandaops/panda/modules/ethnode"
+	forkmonmodule "github.com/ethpandaops/panda/modules/forkmon"
+	githubmodule "github.com/ethpandaops/panda/modules/github"
+	grafanamodule "github.com/ethpandaops/panda/modules/grafana"
+	httpjsonmodule "github.com/ethpandaops/panda/modules/httpjson"
 	lokimodule "github.com/ethpandaops/panda/modules/loki"
 	prometheusmodule "github.com/ethpandaops/panda/modules/prometheus"
+	selfmodule "github.com/ethpandaops/panda/modules/self"
+	syncoormodule "github.com/ethpandaops/panda/modules/syncoor"
 )
 
+// networksSnapshotFile is the snapshot file name for cartographoor networks.
+const networksSnapshotFile = "networks.json"
+
+// networksCacheFile is the cache file name for cartographoor networks.
+const networksCacheFile = "networks-cache.json"
+
 // App contains the shared core components used by both the MCP server and CLI.
 type App struct {
 	log logrus.FieldLogger
@@ -72,25 +91,41 @@ func (a *App) Build(ctx context.Context) error {
 	a.log.WithField("backend", sandboxSvc.Name()).Info("Sandbox service started")
 
 	// 3. Create and start proxy client (performs initial discovery).
-	proxyClient := a.buildProxyClient()
-	if err := proxyClient.Start(ctx); err != nil {
-		a.stop(ctx)
+	// Offline, discovery comes from the last online snapshot instead.
+	if a.cfg.Offline.Enabled {
+		a.ProxyClient = a.buildOfflineProxyClient()
+	} else {
+		proxyClient, err := a.buildProxyClient()
+		if err != nil {
+			a.stop(ctx)
+
+			return fmt.Errorf("creating proxy client: %w", err)
+		}
 
-		return fmt.Errorf("starting proxy client: %w", err)
-	}
+		if err := proxyClient.Start(ctx); err != nil {
+			a.stop(ctx)
+
+			return fmt.Errorf("starting proxy client: %w", err)
+		}
 
-	a.ProxyClient = proxyClient
-	a.log.WithField("url", proxyClient.URL()).Info("Proxy client connected")
+		a.ProxyClient = proxyClient
+		a.log.WithField("url", proxyClient.URL()).Info("Proxy client connected")
+
+		a.saveDiscoverySnapshot()
+	}
 
 	// 4. Initialize modules.
-	if err := a.initModules(proxyClient); err != nil {
+	if err := a.initModules(a.ProxyClient); err != nil {
 		a.stop(ctx)
 
 		return fmt.Errorf("initializing modules: %w", err)
 	}
 
-	// 5. Inject proxy client into modules and start all modules.
+	// 5. Inject proxy client and snapshot settings into modules and start all modules.
 	a.injectProxyClient()
+	a.injectSnapshotDir()
+	a.injectSampleRows()
+	a.injectSavedQueries()
 
 	if err := a.ModuleRegistry.StartAll(ctx); err != nil {
 		a.stop(ctx)
@@ -99,12 +134,19 @@ func (a *App) Build(ctx context.Context) error {
 	}
 
 	a.log.Info("All modules started")
+	a.recordModuleHealth()
 
 	// 6. Create and start cartographoor client.
 	cartographoorClient := cartographoor.NewCartographoorClient(a.log, cartographoor.CartographoorConfig{
-		URL:      cartographoor.DefaultCartographoorURL,
-		CacheTTL: cartographoor.DefaultCacheTTL,
-		Timeout:  cartographoor.DefaultHTTPTimeout,
+		URL:          cartographoor.DefaultCartographoorURL,
+		CacheTTL:     a.cfg.Networks.CacheTTL,
+		Timeout:      cartographoor.DefaultHTTPTimeout,
+		SnapshotPath: filepath.Join(a.cfg.Offline.SnapshotDir, networksSnapshotFile),
+		Offline:      a.cfg.Offline.Enabled,
+		CachePath:    filepath.Join(a.cfg.Networks.CacheDir, networksCacheFile),
+		MaxStale:     a.cfg.Networks.MaxStale,
+		Overrides:    a.cfg.Networks.Overrides,
+		Hide:         a.cfg.Networks.Hide,
 	})
 
 	if err := cartographoorClient.Start(ctx); err != nil {
@@ -136,6 +178,10 @@ func (a *App) stop(ctx context.Context) {
 
 	if a.ModuleRegistry != nil {
 		a.ModuleRegistry.StopAll(ctx)
+
+		for _, name := range a.ModuleRegistry.All() {
+			observability.ModuleUp.WithLabelValues(name).Set(0)
+		}
 	}
 
 	if a.ProxyClient != nil {
@@ -147,17 +193,48 @@ func (a *App) stop(ctx context.Context) {
 	}
 }
 
+// recordModuleHealth exports which modules are initialized and started.
+func (a *App) recordModuleHealth() {
+	for _, name := range a.ModuleRegistry.All() {
+		up := 0.0
+		if a.ModuleRegistry.IsInitialized(name) {
+			up = 1
+		}
+
+		observability.ModuleUp.WithLabelValues(name).Set(up)
+	}
+}
+
+// Modules returns new instances of every compiled-in module.
+func Modules() []module.Module {
+	return []module.Module{
+		assertoormodule.New(),
+		beaconmodule.New(),
+		blobscanmodule.New(),
+		cbtmodule.New(),
+		checkpointzmodule.New(),
+		clickhousemodule.New(),
+		doramodule.New(),
+		ethnodemodule.New(),
+		forkmonmodule.New(),
+		githubmodule.New(),
+		grafanamodule.New(),
+		httpjsonmodule.New(),
+		lokimodule.New(),
+		prometheusmodule.New(),
+		selfmodule.New(),
+		syncoormodule.New(),
+	}
+}
+
 // registerModules creates a module registry and registers all compiled-in
 // modules without initializing them.
 func (a *App) registerModules() *module.Registry {
 	reg := module.NewRegistry(a.log)
 
-	reg.Add(cbtmodule.New())
-	reg.Add(clickhousemodule.New())
-	reg.Add(doramodule.New())
-	reg.Add(ethnodemodule.New())
-	reg.Add(lokimodule.New())
-	reg.Add(prometheusmodule.New())
+	for _, m := range Modules() {
+		reg.Add(m)
+	}
 
 	return reg
 }
@@ -171,6 +248,9 @@ func (a *App) initModules(proxyClient proxy.Client) error {
 	discovered = append(discovered, proxyClient.ClickHouseDatasourceInfo()...)
 	discovered = append(discovered, proxyClient.PrometheusDatasourceInfo()...)
 	discovered = append(discovered, proxyClient.LokiDatasourceInfo()...)
+	discovered = append(discovered, proxyClient.GrafanaDatasourceInfo()...)
+	discovered = append(discovered, proxyClient.HTTPJSONDatasourceInfo()...)
+	discovered = append(discovered, proxyClient.GitHubDatasourceInfo()...)
 
 	if proxyClient.EthNodeAvailable() {
 		discovered = append(discovered, types.DatasourceInfo{
@@ -214,9 +294,15 @@ func (a *App) initModules(proxyClient proxy.Client) error {
 	return nil
 }
 
-func (a *App) buildProxyClient() proxy.Client {
+func (a *App) buildProxyClient() (proxy.Client, error) {
+	if a.cfg.Proxy.Mode == config.ProxyModeEmbedded {
+		return a.buildEmbeddedProxyClient()
+	}
+
 	cfg := proxy.ClientConfig{
-		URL: a.cfg.Proxy.URL,
+		URL:        a.cfg.Proxy.URL,
+		SigningKey: a.cfg.Proxy.SigningKey,
+		TLS:        a.cfg.Proxy.TLS,
 	}
 
 	if a.cfg.Proxy.Auth != nil {
@@ -224,6 +310,9 @@ func (a *App) buildProxyClient() proxy.Client {
 		cfg.ClientID = a.cfg.Proxy.Auth.ClientID
 		cfg.Resource = strings.TrimSpace(a.cfg.Proxy.Auth.Resource)
 		cfg.RefreshTokenTTL = a.cfg.Proxy.Auth.RefreshTokenTTL
+		cfg.Profile = a.cfg.Proxy.Auth.Profile
+		cfg.CredentialStore = a.cfg.Proxy.Auth.CredentialStore
+		cfg.Token = a.cfg.Proxy.Auth.Token
 
 		if cfg.Resource == "" && strings.TrimSpace(a.cfg.Proxy.Auth.Mode) != "oidc" {
 			cfg.Resource = a.cfg.Proxy.URL
@@ -233,6 +322,71 @@ func (a *App) buildProxyClient() proxy.Client {
 	return proxy.NewClient(a.log, cfg)
 }
 
+// buildEmbeddedProxyClient loads the proxy config and returns a client for a
+// proxy running inside this process.
+func (a *App) buildEmbeddedProxyClient() (proxy.Client, error) {
+	path, err := a.cfg.EmbeddedProxyConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := proxy.LoadServerConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading embedded proxy config: %w", err)
+	}
+
+	a.log.WithField("config", path).Info("Running embedded proxy")
+
+	return proxy.NewEmbeddedClient(a.log, *cfg)
+}
+
+// buildOfflineProxyClient returns a proxy client serving the last discovery
+// snapshot. Without a snapshot no datasources are available.
+func (a *App) buildOfflineProxyClient() proxy.Client {
+	var discovery offline.Discovery
+
+	path := filepath.Join(a.cfg.Offline.SnapshotDir, offline.DiscoveryFile)
+	if err := offline.LoadSnapshot(path, &discovery); err != nil {
+		a.log.WithError(err).Warn("No discovery snapshot available, serving no datasources (offline)")
+	}
+
+	a.log.WithField("snapshot_dir", a.cfg.Offline.SnapshotDir).Info("Offline mode: serving snapshots without outbound calls")
+
+	return offline.NewProxyClient(discovery)
+}
+
+// saveDiscoverySnapshot records the proxy's datasources for offline mode.
+func (a *App) saveDiscoverySnapshot() {
+	path := filepath.Join(a.cfg.Offline.SnapshotDir, offline.DiscoveryFile)
+	if err := offline.SaveSnapshot(path, offline.CaptureDiscovery(a.ProxyClient)); err != nil {
+		a.log.WithError(err).Warn("Failed to save discovery snapshot")
+	}
+}
+
+func (a *App) injectSnapshotDir() {
+	for _, ext := range a.ModuleRegistry.Initialized() {
+		if aware, ok := ext.(module.SnapshotAware); ok {
+			aware.SetSnapshotDir(a.cfg.Offline.SnapshotDir, a.cfg.Offline.Enabled)
+		}
+	}
+}
+
+func (a *App) injectSampleRows() {
+	for _, ext := range a.ModuleRegistry.Initialized() {
+		if aware, ok := ext.(module.SampleRowsAware); ok {
+			aware.SetSampleRows(a.cfg.SchemaSamples)
+		}
+	}
+}
+
+func (a *App) injectSavedQueries() {
+	for _, ext := range a.ModuleRegistry.Initialized() {
+		if aware, ok := ext.(module.SavedQueriesAware); ok {
+			aware.SetSavedQueries(a.cfg.SavedQueries.ForModule(ext.Name()))
+		}
+	}
+}
+
 func (a *App) injectProxyClient() {
 	for _, ext := range a.ModuleRegistry.Initialized() {
 		if aware, ok := ext.(module.ProxyAware); ok {
@@ -20,6 +20,7 @@ import (
 	"html"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -75,6 +76,7 @@ type simpleService struct {
 	cfg             Config
 	github          githubClient
 	secretKey       []byte
+	verifier        *TokenVerifier
 	allowedOrgs     []string
 	issuerURL       string
 	accessTokenTTL  time.Duration
@@ -159,6 +161,11 @@ type tokenClaims struct {
 	GitHubLogin string   `json:"github_login"`
 	GitHubID    int64    `json:"github_id"`
 	Orgs        []string `json:"orgs,omitempty"`
+
+	// Service-account tokens (see MintServiceAccountToken) carry scopes.
+	ServiceAccount bool     `json:"service_account,omitempty"`
+	Datasources    []string `json:"datasources,omitempty"`
+	Tools          []string `json:"tools,omitempty"`
 }
 
 // NewSimpleService creates a new simplified auth service.
@@ -198,6 +205,7 @@ func NewSimpleService(log logrus.FieldLogger, cfg Config) (SimpleService, error)
 		cfg:             cfg,
 		github:          github.NewClient(log, cfg.GitHub.ClientID, cfg.GitHub.ClientSecret),
 		secretKey:       []byte(cfg.Tokens.SecretKey),
+		verifier:        NewTokenVerifier(BearerConfig{IssuerURL: cfg.IssuerURL, Tokens: cfg.Tokens}),
 		allowedOrgs:     cfg.AllowedOrgs,
 		issuerURL:       cfg.IssuerURL,
 		accessTokenTTL:  cfg.AccessTokenTTL,
@@ -257,6 +265,7 @@ func (s *simpleService) MountRoutes(r chi.Router) {
 	r.Get("/auth/authorize", s.handleAuthorize)
 	r.Get("/auth/callback", s.handleCallback)
 	r.Post("/auth/token", s.handleToken)
+	r.Post("/auth/revoke", s.handleRevoke)
 
 	// Device authorization endpoints (RFC 8628).
 	r.Post("/auth/device/code", s.handleDeviceCode)
@@ -302,47 +311,14 @@ func (s *simpleService) Middleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-			baseURL := s.issuerURL
-
-			// Validate token.
-			claims := &tokenClaims{}
-			token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
-				if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-					return nil, fmt.Errorf("unexpected signing method")
-				}
-				return s.secretKey, nil
-			}, jwt.WithIssuer(baseURL), jwt.WithExpirationRequired())
-
-			if err != nil || !token.Valid {
-				s.writeUnauthorized(w, baseURL, "invalid token")
-				return
-			}
-
-			// Validate audience (RFC 8707).
-			audienceValid := false
-			for _, aud := range claims.Audience {
-				if aud == baseURL {
-					audienceValid = true
-					break
-				}
-			}
-			if !audienceValid {
-				s.writeUnauthorized(w, baseURL, "token audience mismatch")
+			user, err := s.verifier.Verify(strings.TrimPrefix(authHeader, "Bearer "))
+			if err != nil {
+				s.writeUnauthorized(w, s.issuerURL, err.Error())
 				return
 			}
 
 			// Attach user info to context.
-			ctx := context.WithValue(r.Context(), authUserKey, &AuthUser{
-				Subject:     claims.Subject,
-				Username:    claims.GitHubLogin,
-				Groups:      append([]string(nil), claims.Orgs...),
-				GitHubLogin: claims.GitHubLogin,
-				GitHubID:    claims.GitHubID,
-				Orgs:        claims.Orgs,
-			})
-
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(WithAuthUser(r.Context(), user)))
 		})
 	}
 }
@@ -355,6 +331,26 @@ type AuthUser struct {
 	GitHubLogin string
 	GitHubID    int64
 	Orgs        []string
+
+	// Datasources and Tools are the scopes of a service-account token. They
+	// are nil for user tokens, which are not restricted by them.
+	Datasources []string
+	Tools       []string
+}
+
+// GetAuthGroups returns the groups and orgs of the authenticated user in
+// context, used for role-based checks. Returns nil when unauthenticated.
+func GetAuthGroups(ctx context.Context) []string {
+	user := GetAuthUser(ctx)
+	if user == nil {
+		return nil
+	}
+
+	groups := make([]string, 0, len(user.Groups)+len(user.Orgs))
+	groups = append(groups, user.Groups...)
+	groups = append(groups, user.Orgs...)
+
+	return groups
 }
 
 // GetAuthUser returns the authenticated user from context.
@@ -363,6 +359,23 @@ func GetAuthUser(ctx context.Context) *AuthUser {
 	return user
 }
 
+// OwnerID returns the identity that owns per-client state such as sessions,
+// executions and MCP tips: the authenticated user's GitHub ID, the subject of
+// a token without one (such as a service account), or "" when auth is
+// disabled.
+func OwnerID(ctx context.Context) string {
+	user := GetAuthUser(ctx)
+	if user == nil {
+		return ""
+	}
+
+	if user.GitHubID == 0 && user.Subject != "" {
+		return user.Subject
+	}
+
+	return strconv.FormatInt(user.GitHubID, 10)
+}
+
 type authUserKeyType string
 
 const authUserKey authUserKeyType = "auth_user"
@@ -444,6 +457,7 @@ func (s *simpleService) handleServerMetadata(w http.ResponseWriter, r *http.Requ
 		"authorization_endpoint":                baseURL + "/auth/authorize",
 		"token_endpoint":                        baseURL + "/auth/token",
 		"device_authorization_endpoint":         baseURL + "/auth/device/code",
+		"revocation_endpoint":                   baseURL + "/auth/revoke",
 		"response_types_supported":              []string{"code"},
 		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "urn:ietf:params:oauth:grant-type:device_code"},
 		"code_challenge_methods_supported":      []string{"S256"},
@@ -798,6 +812,28 @@ func (s *simpleService) handleAuthorizationCodeGrant(w http.ResponseWriter, r *h
 	s.writeTokenResponse(w, accessToken, refreshToken)
 }
 
+// handleRevoke revokes a refresh token (RFC 7009). Access tokens are
+// self-contained and expire on their own, so revoking one is a no-op. Unknown
+// tokens are not an error.
+func (s *simpleService) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	clientID := r.FormValue("client_id")
+
+	if token == "" {
+		s.writeError(w, http.StatusBadRequest, "invalid_request", "missing token")
+		return
+	}
+
+	s.refreshSessionsMu.Lock()
+	if session, ok := s.refreshSessions[token]; ok && session.ClientID == clientID {
+		delete(s.refreshSessions, token)
+		s.log.WithField("login", session.GitHubLogin).Info("Revoked refresh token")
+	}
+	s.refreshSessionsMu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *simpleService) handleRefreshTokenGrant(w http.ResponseWriter, r *http.Request) {
 	refreshToken := r.FormValue("refresh_token")
 	clientID := r.FormValue("client_id")
@@ -241,6 +241,63 @@ func TestHandleTokenRefreshGrantRejectsRemovedOrg(t *testing.T) {
 	}
 }
 
+func TestHandleRevokeDeletesRefreshSession(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestSimpleService(t, []string{"ethpandaops"})
+
+	refreshToken, err := svc.issueRefreshToken(
+		"panda",
+		testIssuerURL,
+		"sam",
+		42,
+		"github-access-token",
+		[]string{"ethpandaops"},
+	)
+	if err != nil {
+		t.Fatalf("issueRefreshToken failed: %v", err)
+	}
+
+	revoke := func(clientID string) {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://internal-proxy/auth/revoke", strings.NewReader(url.Values{
+			"token":           {refreshToken},
+			"token_type_hint": {"refresh_token"},
+			"client_id":       {clientID},
+		}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		svc.handleRevoke(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	}
+
+	revoke("other-client")
+
+	svc.refreshSessionsMu.RLock()
+	session := svc.refreshSessions[refreshToken]
+	svc.refreshSessionsMu.RUnlock()
+	if session == nil {
+		t.Fatal("expected refresh session to survive revocation by another client")
+	}
+
+	revoke("panda")
+
+	svc.refreshSessionsMu.RLock()
+	session = svc.refreshSessions[refreshToken]
+	svc.refreshSessionsMu.RUnlock()
+	if session != nil {
+		t.Fatal("expected refresh session to be revoked")
+	}
+
+	// Revoking an unknown token still succeeds (RFC 7009 section 2.2).
+	revoke("panda")
+}
+
 type tokenResponseBody struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
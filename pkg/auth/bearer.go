@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// BearerConfig configures validation of the access tokens a proxy issues,
+// for services such as the MCP server that accept the same tokens.
+type BearerConfig struct {
+	// IssuerURL must match the proxy's auth.issuer_url.
+	IssuerURL string `yaml:"issuer_url"`
+
+	// Tokens must match the proxy's auth.tokens, so tokens it revokes are
+	// rejected here too.
+	Tokens TokensConfig `yaml:"tokens"`
+}
+
+// Validate checks the bearer configuration.
+func (c *BearerConfig) Validate() error {
+	if strings.TrimSpace(c.IssuerURL) == "" {
+		return errors.New("issuer_url is required")
+	}
+
+	if c.Tokens.SecretKey == "" {
+		return errors.New("tokens.secret_key is required")
+	}
+
+	return nil
+}
+
+// TokenVerifier validates access tokens signed with an issuer's token key
+// and returns the user they were issued to.
+type TokenVerifier struct {
+	issuerURL string
+	secretKey []byte
+	revoked   map[string]struct{} // revoked service-account token IDs
+}
+
+// NewTokenVerifier creates a TokenVerifier for tokens from cfg's issuer.
+func NewTokenVerifier(cfg BearerConfig) *TokenVerifier {
+	v := &TokenVerifier{
+		issuerURL: strings.TrimRight(strings.TrimSpace(cfg.IssuerURL), "/"),
+		secretKey: []byte(cfg.Tokens.SecretKey),
+		revoked:   make(map[string]struct{}, len(cfg.Tokens.Revoked)),
+	}
+
+	for _, id := range cfg.Tokens.Revoked {
+		v.revoked[strings.TrimSpace(id)] = struct{}{}
+	}
+
+	return v
+}
+
+// IssuerURL returns the issuer tokens must come from.
+func (v *TokenVerifier) IssuerURL() string {
+	return v.issuerURL
+}
+
+// Verify validates token and returns its user. The error text is safe to
+// return to the client.
+func (v *TokenVerifier) Verify(token string) (*AuthUser, error) {
+	claims := &tokenClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return v.secretKey, nil
+	}, jwt.WithIssuer(v.issuerURL), jwt.WithExpirationRequired())
+
+	if err != nil || !parsed.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	// Validate audience (RFC 8707).
+	if !slices.Contains(claims.Audience, v.issuerURL) {
+		return nil, errors.New("token audience mismatch")
+	}
+
+	user := &AuthUser{
+		Subject:     claims.Subject,
+		Username:    claims.GitHubLogin,
+		Groups:      append([]string(nil), claims.Orgs...),
+		GitHubLogin: claims.GitHubLogin,
+		GitHubID:    claims.GitHubID,
+		Orgs:        claims.Orgs,
+	}
+
+	if claims.ServiceAccount {
+		if _, revoked := v.revoked[claims.ID]; revoked {
+			return nil, errors.New("token revoked")
+		}
+
+		// Scope claims are non-nil so an empty list denies everything.
+		user.Username = claims.Subject
+		user.Datasources = append([]string{}, claims.Datasources...)
+		user.Tools = append([]string{}, claims.Tools...)
+	}
+
+	return user, nil
+}
+
+// WithAuthUser returns a copy of ctx carrying user as the authenticated
+// user, for middleware that validates tokens outside this package.
+func WithAuthUser(ctx context.Context, user *AuthUser) context.Context {
+	return context.WithValue(ctx, authUserKey, user)
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -28,6 +29,10 @@ type Client interface {
 
 	// Refresh refreshes an access token using a refresh token.
 	Refresh(ctx context.Context, refreshToken string) (*Tokens, error)
+
+	// Revoke revokes a token at the issuer (RFC 7009). tokenTypeHint is
+	// "refresh_token" or "access_token".
+	Revoke(ctx context.Context, token, tokenTypeHint string) error
 }
 
 // Tokens contains the authentication tokens.
@@ -76,6 +81,9 @@ type client struct {
 	http   *http.Client
 	oidc   *OIDCConfig
 	loaded bool
+
+	// minPollInterval is the shortest device flow polling interval.
+	minPollInterval time.Duration
 }
 
 // OIDCConfig contains OIDC discovery configuration.
@@ -84,19 +92,30 @@ type OIDCConfig struct {
 	AuthorizationEndpoint       string   `json:"authorization_endpoint"`
 	TokenEndpoint               string   `json:"token_endpoint"`
 	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	RevocationEndpoint          string   `json:"revocation_endpoint"`
 	JwksURI                     string   `json:"jwks_uri"`
 	ScopesSupported             []string `json:"scopes_supported"`
 }
 
 // deviceAuthResponse is the RFC 8628 device authorization response.
 type deviceAuthResponse struct {
-	DeviceCode      string `json:"device_code"`
-	UserCode        string `json:"user_code"`
-	VerificationURI string `json:"verification_uri"`
-	ExpiresIn       int    `json:"expires_in"`
-	Interval        int    `json:"interval"`
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
 }
 
+// Device flow polling defaults from RFC 8628 section 3.5.
+const (
+	defaultPollInterval = 5 * time.Second
+	slowDownIncrement   = 5 * time.Second
+)
+
+// errSlowDown reports a slow_down response from the token endpoint.
+var errSlowDown = errors.New("slow down")
+
 // New creates a new OAuth client.
 func New(log logrus.FieldLogger, cfg Config) Client {
 	if len(cfg.Scopes) == 0 {
@@ -104,9 +123,10 @@ func New(log logrus.FieldLogger, cfg Config) Client {
 	}
 
 	return &client{
-		log:  log.WithField("component", "oauth-client"),
-		cfg:  cfg,
-		http: &http.Client{Transport: &version.Transport{}, Timeout: 30 * time.Second},
+		log:             log.WithField("component", "oauth-client"),
+		cfg:             cfg,
+		http:            &http.Client{Transport: &version.Transport{}, Timeout: 30 * time.Second},
+		minPollInterval: defaultPollInterval,
 	}
 }
 
@@ -191,13 +211,32 @@ func (c *client) loginDevice(ctx context.Context) (*Tokens, error) {
 	// Display instructions.
 	fmt.Printf("\nOpen %s in your browser\nand enter the code:\n\n  %s\n\n",
 		deviceResp.VerificationURI, deviceResp.UserCode)
+
+	if deviceResp.VerificationURIComplete != "" {
+		fmt.Printf("Or open this URL, which includes the code:\n\n  %s\n\n", deviceResp.VerificationURIComplete)
+	}
+
 	fmt.Println("Waiting for authorization... (press Ctrl+C to cancel)")
 
+	// Stop polling once the device code expires.
+	pollCtx := ctx
+
+	if deviceResp.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+
+		pollCtx, cancel = context.WithTimeout(ctx, time.Duration(deviceResp.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
 	// Poll for token.
-	interval := max(time.Duration(deviceResp.Interval)*time.Second, 5*time.Second)
+	interval := max(time.Duration(deviceResp.Interval)*time.Second, c.minPollInterval)
 
-	tokens, err := c.pollDeviceToken(ctx, deviceResp.DeviceCode, interval)
+	tokens, err := c.pollDeviceToken(pollCtx, deviceResp.DeviceCode, interval)
 	if err != nil {
+		if pollCtx.Err() != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("device code expired, please restart authentication")
+		}
+
 		return nil, err
 	}
 
@@ -263,6 +302,49 @@ func (c *client) Refresh(ctx context.Context, refreshToken string) (*Tokens, err
 	}, nil
 }
 
+// ErrRevocationUnsupported is returned by Revoke when the issuer does not
+// advertise a revocation endpoint.
+var ErrRevocationUnsupported = errors.New("issuer does not support token revocation")
+
+// Revoke revokes a token at the issuer's revocation endpoint.
+func (c *client) Revoke(ctx context.Context, token, tokenTypeHint string) error {
+	if err := c.discover(ctx); err != nil {
+		return fmt.Errorf("discovering OIDC config: %w", err)
+	}
+
+	if c.oidc.RevocationEndpoint == "" {
+		return ErrRevocationUnsupported
+	}
+
+	data := url.Values{
+		"token":     {token},
+		"client_id": {c.cfg.ClientID},
+	}
+	if tokenTypeHint != "" {
+		data.Set("token_type_hint", tokenTypeHint)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.oidc.RevocationEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("revocation endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
 // discover fetches OIDC configuration from the issuer.
 func (c *client) discover(ctx context.Context) error {
 	if c.loaded {
@@ -472,6 +554,7 @@ func (c *client) exchangeCode(ctx context.Context, code, verifier, redirectURI s
 func (c *client) requestDeviceCode(ctx context.Context) (*deviceAuthResponse, error) {
 	data := url.Values{
 		"client_id": {c.cfg.ClientID},
+		"scope":     {strings.Join(c.cfg.Scopes, " ")},
 	}
 	if c.cfg.Resource != "" {
 		data.Set("resource", c.cfg.Resource)
@@ -517,6 +600,13 @@ func (c *client) pollDeviceToken(ctx context.Context, deviceCode string, interva
 			return nil, ctx.Err()
 		case <-ticker.C:
 			tokens, pending, err := c.exchangeDeviceCode(ctx, deviceCode)
+			if errors.Is(err, errSlowDown) {
+				interval += slowDownIncrement
+				ticker.Reset(interval)
+
+				continue
+			}
+
 			if err != nil {
 				return nil, err
 			}
@@ -531,7 +621,8 @@ func (c *client) pollDeviceToken(ctx context.Context, deviceCode string, interva
 }
 
 // exchangeDeviceCode attempts to exchange a device code for tokens.
-// Returns pending=true if the user hasn't authorized yet.
+// Returns pending=true if the user hasn't authorized yet, and errSlowDown
+// when the server asks for a longer polling interval.
 func (c *client) exchangeDeviceCode(ctx context.Context, deviceCode string) (tokens *Tokens, pending bool, err error) {
 	data := url.Values{
 		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
@@ -589,8 +680,10 @@ func (c *client) exchangeDeviceCode(ctx context.Context, deviceCode string) (tok
 	}
 
 	switch errResp.Error {
-	case "authorization_pending", "slow_down":
+	case "authorization_pending":
 		return nil, true, nil
+	case "slow_down":
+		return nil, false, errSlowDown
 	case "expired_token":
 		return nil, false, fmt.Errorf("device code expired, please restart authentication")
 	case "access_denied":
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -219,3 +221,159 @@ func TestFetchBrandingReturnsConfig(t *testing.T) {
 		t.Fatalf("unexpected default tagline: %+v", got.Default)
 	}
 }
+
+// newDeviceFlowServer serves discovery, device authorization and a token
+// endpoint that answers with the given errors before issuing tokens.
+func newDeviceFlowServer(t *testing.T, expiresIn int, tokenErrors ...string) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"issuer":                        srv.URL,
+				"token_endpoint":                srv.URL + "/token",
+				"device_authorization_endpoint": srv.URL + "/device/code",
+			})
+		case "/device/code":
+			if scope := r.FormValue("scope"); !strings.Contains(scope, "offline_access") {
+				t.Errorf("expected device code request scopes to include offline_access, got %q", scope)
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"device_code":               "device-123",
+				"user_code":                 "ABCD-EFGH",
+				"verification_uri":          srv.URL + "/device",
+				"verification_uri_complete": srv.URL + "/device?user_code=ABCD-EFGH",
+				"expires_in":                expiresIn,
+			})
+		case "/token":
+			if r.FormValue("device_code") != "device-123" {
+				t.Errorf("unexpected device code %q", r.FormValue("device_code"))
+			}
+
+			// Codes that expire within a second are never authorized.
+			if len(tokenErrors) > 0 || expiresIn == 1 {
+				code := "authorization_pending"
+				if len(tokenErrors) > 0 {
+					code, tokenErrors = tokenErrors[0], tokenErrors[1:]
+				}
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "access-token",
+				"refresh_token": "refresh-token",
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newDeviceFlowClient(issuerURL string) *client {
+	c := New(logrus.New(), Config{IssuerURL: issuerURL, ClientID: "panda", Headless: true}).(*client)
+	c.minPollInterval = 10 * time.Millisecond
+
+	return c
+}
+
+func TestDeviceLoginPollsUntilAuthorized(t *testing.T) {
+	t.Parallel()
+
+	srv := newDeviceFlowServer(t, 60, "authorization_pending", "authorization_pending")
+
+	tokens, err := newDeviceFlowClient(srv.URL).Login(context.Background())
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if tokens.AccessToken != "access-token" || tokens.RefreshToken != "refresh-token" {
+		t.Fatalf("unexpected tokens: %+v", tokens)
+	}
+}
+
+func TestDeviceLoginReportsDeniedAuthorization(t *testing.T) {
+	t.Parallel()
+
+	srv := newDeviceFlowServer(t, 60, "authorization_pending", "access_denied")
+
+	_, err := newDeviceFlowClient(srv.URL).Login(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected a denied error, got %v", err)
+	}
+}
+
+func TestDeviceLoginStopsWhenCodeExpires(t *testing.T) {
+	t.Parallel()
+
+	srv := newDeviceFlowServer(t, 1)
+
+	_, err := newDeviceFlowClient(srv.URL).Login(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected an expiry error, got %v", err)
+	}
+}
+
+func TestExchangeDeviceCodeSlowDown(t *testing.T) {
+	t.Parallel()
+
+	srv := newDeviceFlowServer(t, 60, "slow_down")
+
+	c := newDeviceFlowClient(srv.URL)
+	if err := c.discover(context.Background()); err != nil {
+		t.Fatalf("discover failed: %v", err)
+	}
+
+	if _, _, err := c.exchangeDeviceCode(context.Background(), "device-123"); !errors.Is(err, errSlowDown) {
+		t.Fatalf("expected errSlowDown, got %v", err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	t.Parallel()
+
+	var revoked url.Values
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"issuer":              "http://example.test",
+				"token_endpoint":      "http://example.test/token",
+				"revocation_endpoint": "http://" + r.Host + "/revoke",
+			})
+		case "/revoke":
+			_ = r.ParseForm()
+			revoked = r.PostForm
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(logrus.New(), Config{IssuerURL: srv.URL, ClientID: "panda"})
+	if err := c.Revoke(context.Background(), "refresh-token", "refresh_token"); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	if revoked.Get("token") != "refresh-token" || revoked.Get("token_type_hint") != "refresh_token" || revoked.Get("client_id") != "panda" {
+		t.Fatalf("unexpected revocation request: %v", revoked)
+	}
+
+	unsupported := newDeviceFlowServer(t, 60)
+	if err := New(logrus.New(), Config{IssuerURL: unsupported.URL, ClientID: "panda"}).Revoke(context.Background(), "refresh-token", ""); !errors.Is(err, ErrRevocationUnsupported) {
+		t.Fatalf("expected ErrRevocationUnsupported, got %v", err)
+	}
+}
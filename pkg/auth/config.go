@@ -23,4 +23,8 @@ type GitHubConfig struct {
 // TokensConfig holds signed access token configuration.
 type TokensConfig struct {
 	SecretKey string `yaml:"secret_key"`
+
+	// Revoked lists the IDs of service-account tokens that are no longer
+	// accepted. `panda-proxy token mint` prints each token's ID.
+	Revoked []string `yaml:"revoked,omitempty"`
 }
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// DatasourceScope grants the members of some groups access to a set of
+// datasources.
+type DatasourceScope struct {
+	// Groups are the GitHub orgs or OIDC groups the scope applies to.
+	Groups []string `yaml:"groups"`
+
+	// Datasources are "type:name" patterns the groups may use, e.g.
+	// "clickhouse:xatu", "clickhouse:xatu-*", "prometheus" (every Prometheus
+	// datasource) or "*" (everything). Names support path.Match globs.
+	Datasources []string `yaml:"datasources"`
+}
+
+// ValidateDatasourceScopes checks that every scope names at least one group
+// and one well-formed datasource pattern.
+func ValidateDatasourceScopes(scopes []DatasourceScope) error {
+	for i, scope := range scopes {
+		if len(scope.Groups) == 0 {
+			return fmt.Errorf("scope[%d]: at least one group is required", i)
+		}
+
+		if len(scope.Datasources) == 0 {
+			return fmt.Errorf("scope[%d]: at least one datasource is required", i)
+		}
+
+		for _, pattern := range scope.Datasources {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				return fmt.Errorf("scope[%d]: datasource pattern cannot be empty", i)
+			}
+
+			_, name, _ := strings.Cut(pattern, ":")
+			if _, err := path.Match(name, ""); err != nil {
+				return fmt.Errorf("scope[%d]: datasource pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DatasourcePolicy decides which datasources each user may see and query.
+// Without scopes every datasource is open. With scopes, an authenticated
+// user may only use the datasources granted to one of their groups, and a
+// user in no scoped group sees none. Callers without an authenticated user
+// (nil groups) are not restricted, matching the proxy's behavior when auth
+// is disabled.
+type DatasourcePolicy struct {
+	grants map[string][]string // group -> datasource patterns
+}
+
+// NewDatasourcePolicy creates a datasource policy from validated scopes. It
+// returns nil, which allows everything, when no scopes are configured.
+func NewDatasourcePolicy(scopes []DatasourceScope) *DatasourcePolicy {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	p := &DatasourcePolicy{grants: make(map[string][]string, len(scopes))}
+
+	for _, scope := range scopes {
+		for _, group := range scope.Groups {
+			group = strings.TrimSpace(group)
+
+			for _, pattern := range scope.Datasources {
+				p.grants[group] = append(p.grants[group], strings.TrimSpace(pattern))
+			}
+		}
+	}
+
+	return p
+}
+
+// Allows reports whether a user with the given groups may use the
+// datasource. Type-level datasources such as ethnode and github pass an
+// empty name and need a grant for the whole type.
+func (p *DatasourcePolicy) Allows(groups []string, dsType, name string) bool {
+	if p == nil || groups == nil {
+		return true
+	}
+
+	for _, group := range groups {
+		for _, pattern := range p.grants[group] {
+			if matchDatasource(pattern, dsType, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AllowsContext is Allows for the user in ctx.
+func (p *DatasourcePolicy) AllowsContext(ctx context.Context, dsType, name string) bool {
+	return p.Allows(GetAuthGroups(ctx), dsType, name)
+}
+
+// Filter returns the datasources a user with the given groups may use.
+func (p *DatasourcePolicy) Filter(groups []string, infos []types.DatasourceInfo) []types.DatasourceInfo {
+	if p == nil || groups == nil {
+		return infos
+	}
+
+	filtered := make([]types.DatasourceInfo, 0, len(infos))
+
+	for _, info := range infos {
+		if p.Allows(groups, info.Type, info.Name) {
+			filtered = append(filtered, info)
+		}
+	}
+
+	return filtered
+}
+
+// matchDatasource reports whether a "type:name" pattern covers a datasource.
+// A bare type, "type:*" and "*" cover every datasource of the type.
+func matchDatasource(pattern, dsType, name string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	patternType, patternName, hasName := strings.Cut(pattern, ":")
+	if patternType != dsType {
+		return false
+	}
+
+	if !hasName || patternName == "*" {
+		return true
+	}
+
+	matched, _ := path.Match(patternName, name)
+
+	return matched && name != ""
+}
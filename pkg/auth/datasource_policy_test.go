@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestDatasourcePolicyAllows(t *testing.T) {
+	t.Parallel()
+
+	policy := NewDatasourcePolicy([]DatasourceScope{
+		{Groups: []string{"researchers"}, Datasources: []string{"clickhouse:xatu-*", "prometheus:public"}},
+		{Groups: []string{"ethpandaops"}, Datasources: []string{"*"}},
+		{Groups: []string{"node-operators"}, Datasources: []string{"ethnode", "loki:*"}},
+	})
+
+	tests := []struct {
+		name    string
+		groups  []string
+		dsType  string
+		dsName  string
+		allowed bool
+	}{
+		{name: "glob grant", groups: []string{"researchers"}, dsType: "clickhouse", dsName: "xatu-cbt", allowed: true},
+		{name: "outside glob", groups: []string{"researchers"}, dsType: "clickhouse", dsName: "xatu"},
+		{name: "exact grant", groups: []string{"researchers"}, dsType: "prometheus", dsName: "public", allowed: true},
+		{name: "other type", groups: []string{"researchers"}, dsType: "loki", dsName: "public"},
+		{name: "type-level datasource needs type grant", groups: []string{"researchers"}, dsType: "ethnode"},
+		{name: "bare type grant", groups: []string{"node-operators"}, dsType: "ethnode", allowed: true},
+		{name: "type wildcard grant", groups: []string{"node-operators"}, dsType: "loki", dsName: "internal", allowed: true},
+		{name: "everything", groups: []string{"other", "ethpandaops"}, dsType: "grafana", dsName: "main", allowed: true},
+		{name: "unscoped group sees nothing", groups: []string{}, dsType: "clickhouse", dsName: "xatu-cbt"},
+		{name: "unauthenticated is not restricted", dsType: "clickhouse", dsName: "xatu", allowed: true},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Allows(tt.groups, tt.dsType, tt.dsName); got != tt.allowed {
+			t.Fatalf("%s: Allows() = %v, want %v", tt.name, got, tt.allowed)
+		}
+	}
+
+	if !NewDatasourcePolicy(nil).Allows([]string{}, "clickhouse", "xatu") {
+		t.Fatalf("empty policy must allow everything")
+	}
+
+	filtered := policy.Filter([]string{"researchers"}, []types.DatasourceInfo{
+		{Type: "clickhouse", Name: "xatu"},
+		{Type: "clickhouse", Name: "xatu-cbt"},
+		{Type: "prometheus", Name: "public"},
+	})
+	if len(filtered) != 2 || filtered[0].Name != "xatu-cbt" || filtered[1].Name != "public" {
+		t.Fatalf("Filter() = %+v", filtered)
+	}
+}
+
+func TestValidateDatasourceScopes(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateDatasourceScopes([]DatasourceScope{{Groups: []string{"a"}, Datasources: []string{"clickhouse:xatu-*"}}}); err != nil {
+		t.Fatalf("ValidateDatasourceScopes() error = %v", err)
+	}
+
+	for _, scope := range []DatasourceScope{
+		{Datasources: []string{"*"}},
+		{Groups: []string{"a"}},
+		{Groups: []string{"a"}, Datasources: []string{" "}},
+		{Groups: []string{"a"}, Datasources: []string{"clickhouse:xatu-["}},
+	} {
+		if err := ValidateDatasourceScopes([]DatasourceScope{scope}); err == nil {
+			t.Fatalf("ValidateDatasourceScopes(%+v) expected an error", scope)
+		}
+	}
+}
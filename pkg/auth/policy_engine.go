@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPolicyDenied is returned when the policy engine denies an action.
+var ErrPolicyDenied = errors.New("denied by policy")
+
+// maxPolicyCacheEntries bounds the decision cache.
+const maxPolicyCacheEntries = 10000
+
+// maxSummarizedArgLength truncates string arguments sent to the policy engine.
+const maxSummarizedArgLength = 256
+
+// PolicyEngineConfig configures an external authorization engine consulted
+// for tool calls and resource reads, after the built-in tool policies.
+type PolicyEngineConfig struct {
+	// OPA evaluates decisions with an Open Policy Agent sidecar.
+	OPA *OPAConfig `yaml:"opa,omitempty"`
+
+	// CacheTTL is how long a decision is reused for identical input.
+	// Defaults to 1m; a negative value disables caching.
+	CacheTTL time.Duration `yaml:"cache_ttl,omitempty"`
+
+	// FailOpen allows actions when the engine cannot be reached. By default
+	// they are denied.
+	FailOpen bool `yaml:"fail_open,omitempty"`
+}
+
+// OPAConfig points at an OPA data API document.
+type OPAConfig struct {
+	// URL is the decision document, e.g.
+	// http://localhost:8181/v1/data/panda/authz. The document may be a
+	// boolean or an object with "allow" and an optional "reason".
+	URL string `yaml:"url"`
+
+	// Timeout bounds each decision request. Defaults to 2s.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Validate checks the policy engine configuration.
+func (c *PolicyEngineConfig) Validate() error {
+	if c.OPA == nil {
+		return errors.New("an engine is required (opa)")
+	}
+
+	if !strings.HasPrefix(c.OPA.URL, "http://") && !strings.HasPrefix(c.OPA.URL, "https://") {
+		return errors.New("opa.url must be an http(s) URL")
+	}
+
+	if c.OPA.Timeout < 0 {
+		return errors.New("opa.timeout cannot be negative")
+	}
+
+	return nil
+}
+
+// PolicyInput describes an action for the policy engine to decide on.
+type PolicyInput struct {
+	// Action is "tool" or "resource".
+	Action   string         `json:"action"`
+	Tool     string         `json:"tool,omitempty"`
+	Resource string         `json:"resource,omitempty"`
+	Args     map[string]any `json:"args,omitempty"`
+	User     PolicyUser     `json:"user"`
+}
+
+// PolicyUser is the caller as seen by the policy engine. Authenticated is
+// false when the server runs without auth.
+type PolicyUser struct {
+	Authenticated bool     `json:"authenticated"`
+	Subject       string   `json:"subject,omitempty"`
+	Username      string   `json:"username,omitempty"`
+	Groups        []string `json:"groups,omitempty"`
+}
+
+// PolicyDecision is the engine's answer.
+type PolicyDecision struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PolicyEngine decides whether an action is allowed.
+type PolicyEngine interface {
+	Decide(ctx context.Context, input PolicyInput) (PolicyDecision, error)
+}
+
+// PolicyAuthorizer consults a policy engine with the user in context,
+// caching decisions per input.
+type PolicyAuthorizer struct {
+	log      logrus.FieldLogger
+	engine   PolicyEngine
+	cacheTTL time.Duration
+	failOpen bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedDecision
+}
+
+type cachedDecision struct {
+	decision PolicyDecision
+	expires  time.Time
+}
+
+// NewPolicyAuthorizer creates an authorizer from validated config, or
+// returns nil when no engine is configured.
+func NewPolicyAuthorizer(log logrus.FieldLogger, cfg *PolicyEngineConfig) *PolicyAuthorizer {
+	if cfg == nil || cfg.OPA == nil {
+		return nil
+	}
+
+	cacheTTL := cfg.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = time.Minute
+	}
+
+	return newPolicyAuthorizer(log, NewOPAEngine(*cfg.OPA), cacheTTL, cfg.FailOpen)
+}
+
+func newPolicyAuthorizer(log logrus.FieldLogger, engine PolicyEngine, cacheTTL time.Duration, failOpen bool) *PolicyAuthorizer {
+	return &PolicyAuthorizer{
+		log:      log.WithField("component", "policy_engine"),
+		engine:   engine,
+		cacheTTL: cacheTTL,
+		failOpen: failOpen,
+		now:      time.Now,
+		cache:    make(map[string]cachedDecision, 64),
+	}
+}
+
+// AuthorizeTool returns an error wrapping ErrPolicyDenied when the engine
+// denies calling tool with args.
+func (p *PolicyAuthorizer) AuthorizeTool(ctx context.Context, tool string, args map[string]any) error {
+	if p == nil {
+		return nil
+	}
+
+	return p.authorize(ctx, PolicyInput{Action: "tool", Tool: tool, Args: SummarizeArgs(args)}, tool)
+}
+
+// AuthorizeResource returns an error wrapping ErrPolicyDenied when the
+// engine denies reading uri.
+func (p *PolicyAuthorizer) AuthorizeResource(ctx context.Context, uri string) error {
+	if p == nil {
+		return nil
+	}
+
+	return p.authorize(ctx, PolicyInput{Action: "resource", Resource: uri}, uri)
+}
+
+func (p *PolicyAuthorizer) authorize(ctx context.Context, input PolicyInput, target string) error {
+	if user := GetAuthUser(ctx); user != nil {
+		input.User = PolicyUser{
+			Authenticated: true,
+			Subject:       user.Subject,
+			Username:      user.Username,
+			Groups:        GetAuthGroups(ctx),
+		}
+	}
+
+	decision, err := p.decide(ctx, input)
+	if err != nil {
+		p.log.WithError(err).WithField("target", target).Warn("Policy engine unavailable")
+
+		if p.failOpen {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s: policy engine unavailable", ErrPolicyDenied, target)
+	}
+
+	if decision.Allow {
+		return nil
+	}
+
+	if decision.Reason != "" {
+		return fmt.Errorf("%w: %s: %s", ErrPolicyDenied, target, decision.Reason)
+	}
+
+	return fmt.Errorf("%w: %s", ErrPolicyDenied, target)
+}
+
+// decide returns a cached decision for input or asks the engine. Failed
+// lookups are not cached.
+func (p *PolicyAuthorizer) decide(ctx context.Context, input PolicyInput) (PolicyDecision, error) {
+	if p.cacheTTL < 0 {
+		return p.engine.Decide(ctx, input)
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("encoding policy input: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+
+	if ok && p.now().Before(cached.expires) {
+		return cached.decision, nil
+	}
+
+	decision, err := p.engine.Decide(ctx, input)
+	if err != nil {
+		return PolicyDecision{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.cache) >= maxPolicyCacheEntries {
+		now := p.now()
+		for k, v := range p.cache {
+			if now.After(v.expires) {
+				delete(p.cache, k)
+			}
+		}
+
+		if len(p.cache) >= maxPolicyCacheEntries {
+			clear(p.cache)
+		}
+	}
+
+	p.cache[key] = cachedDecision{decision: decision, expires: p.now().Add(p.cacheTTL)}
+
+	return decision, nil
+}
+
+// SummarizeArgs reduces tool arguments to what a policy needs: scalars are
+// kept, long strings are truncated and nested values are replaced by their
+// kind and size, so large code payloads never reach the engine.
+func SummarizeArgs(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+
+	summary := make(map[string]any, len(args))
+
+	for name, value := range args {
+		switch v := value.(type) {
+		case string:
+			if len(v) > maxSummarizedArgLength {
+				v = v[:maxSummarizedArgLength]
+			}
+
+			summary[name] = v
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+			summary[name] = map[string]any{"kind": "object", "keys": keys}
+		case []any:
+			summary[name] = map[string]any{"kind": "array", "length": len(v)}
+		default:
+			summary[name] = v
+		}
+	}
+
+	return summary
+}
+
+// OPAEngine asks an Open Policy Agent server for decisions.
+type OPAEngine struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewOPAEngine creates an engine for the OPA data document at cfg.URL.
+func NewOPAEngine(cfg OPAConfig) *OPAEngine {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Second
+	}
+
+	return &OPAEngine{url: cfg.URL, httpClient: &http.Client{Timeout: timeout}}
+}
+
+// Decide posts input to OPA. An undefined document denies.
+func (e *OPAEngine) Decide(ctx context.Context, input PolicyInput) (PolicyDecision, error) {
+	body, err := json.Marshal(map[string]any{"input": input})
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("encoding input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return PolicyDecision{}, fmt.Errorf("querying opa: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return PolicyDecision{}, fmt.Errorf("opa returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return PolicyDecision{}, fmt.Errorf("decoding opa response: %w", err)
+	}
+
+	if len(result.Result) == 0 {
+		return PolicyDecision{Reason: "no policy decision"}, nil
+	}
+
+	var allow bool
+	if err := json.Unmarshal(result.Result, &allow); err == nil {
+		return PolicyDecision{Allow: allow}, nil
+	}
+
+	var decision PolicyDecision
+	if err := json.Unmarshal(result.Result, &decision); err != nil {
+		return PolicyDecision{}, fmt.Errorf("opa result must be a boolean or {allow, reason}: %w", err)
+	}
+
+	return decision, nil
+}
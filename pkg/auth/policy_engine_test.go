@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPolicyAuthorizerWithOPA(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		var body struct {
+			Input PolicyInput `json:"input"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding input: %v", err)
+		}
+
+		switch {
+		case body.Input.Action == "resource":
+			_, _ = w.Write([]byte(`{"result": true}`))
+		case body.Input.User.Username == "alice":
+			_, _ = w.Write([]byte(`{"result": {"allow": true}}`))
+		case body.Input.User.Authenticated:
+			_, _ = w.Write([]byte(`{"result": {"allow": false, "reason": "alice only"}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer opa.Close()
+
+	p := NewPolicyAuthorizer(logrus.New(), &PolicyEngineConfig{OPA: &OPAConfig{URL: opa.URL}})
+
+	anonymous := context.Background()
+	alice := context.WithValue(anonymous, authUserKey, &AuthUser{Username: "alice"})
+	bob := context.WithValue(anonymous, authUserKey, &AuthUser{Username: "bob"})
+
+	if err := p.AuthorizeTool(alice, "execute_python", map[string]any{"code": "print(1)"}); err != nil {
+		t.Fatalf("alice denied: %v", err)
+	}
+
+	err := p.AuthorizeTool(bob, "execute_python", nil)
+	if !errors.Is(err, ErrPolicyDenied) || !strings.Contains(err.Error(), "alice only") {
+		t.Fatalf("bob error = %v, want denial with reason", err)
+	}
+
+	if err := p.AuthorizeTool(anonymous, "search", nil); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("undefined document error = %v, want denial", err)
+	}
+
+	if err := p.AuthorizeResource(bob, "networks://active"); err != nil {
+		t.Fatalf("resource denied: %v", err)
+	}
+
+	before := calls.Load()
+
+	if err := p.AuthorizeTool(alice, "execute_python", map[string]any{"code": "print(1)"}); err != nil {
+		t.Fatalf("cached decision: %v", err)
+	}
+
+	if calls.Load() != before {
+		t.Fatalf("identical input was not served from the cache")
+	}
+}
+
+func TestPolicyAuthorizerUnavailable(t *testing.T) {
+	t.Parallel()
+
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer opa.Close()
+
+	closed := NewPolicyAuthorizer(logrus.New(), &PolicyEngineConfig{OPA: &OPAConfig{URL: opa.URL}})
+	if err := closed.AuthorizeTool(context.Background(), "search", nil); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("fail closed error = %v, want denial", err)
+	}
+
+	open := NewPolicyAuthorizer(logrus.New(), &PolicyEngineConfig{OPA: &OPAConfig{URL: opa.URL}, FailOpen: true})
+	if err := open.AuthorizeTool(context.Background(), "search", nil); err != nil {
+		t.Fatalf("fail open error = %v, want nil", err)
+	}
+}
+
+func TestPolicyAuthorizerCacheExpires(t *testing.T) {
+	t.Parallel()
+
+	engine := &countingEngine{}
+	p := newPolicyAuthorizer(logrus.New(), engine, time.Minute, false)
+
+	now := time.Unix(0, 0)
+	p.now = func() time.Time { return now }
+
+	_ = p.AuthorizeResource(context.Background(), "a://b")
+	_ = p.AuthorizeResource(context.Background(), "a://b")
+
+	now = now.Add(2 * time.Minute)
+	_ = p.AuthorizeResource(context.Background(), "a://b")
+
+	if engine.calls != 2 {
+		t.Fatalf("engine calls = %d, want 2", engine.calls)
+	}
+}
+
+func TestNilPolicyAuthorizerAllows(t *testing.T) {
+	t.Parallel()
+
+	var p *PolicyAuthorizer
+	if err := p.AuthorizeTool(context.Background(), "search", nil); err != nil {
+		t.Fatalf("nil authorizer denied: %v", err)
+	}
+
+	if NewPolicyAuthorizer(logrus.New(), nil) != nil {
+		t.Fatalf("expected nil authorizer without config")
+	}
+}
+
+func TestSummarizeArgs(t *testing.T) {
+	t.Parallel()
+
+	summary := SummarizeArgs(map[string]any{
+		"code":    strings.Repeat("x", 1000),
+		"timeout": float64(30),
+		"env":     map[string]any{"B": "2", "A": "1"},
+		"files":   []any{"a", "b", "c"},
+	})
+
+	if got := len(summary["code"].(string)); got != maxSummarizedArgLength {
+		t.Fatalf("code length = %d, want %d", got, maxSummarizedArgLength)
+	}
+
+	if summary["timeout"] != float64(30) {
+		t.Fatalf("timeout = %v", summary["timeout"])
+	}
+
+	env := summary["env"].(map[string]any)
+	if keys := env["keys"].([]string); len(keys) != 2 || keys[0] != "A" {
+		t.Fatalf("env keys = %v", keys)
+	}
+
+	if files := summary["files"].(map[string]any); files["length"] != 3 {
+		t.Fatalf("files = %v", files)
+	}
+}
+
+func TestPolicyEngineConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		cfg   PolicyEngineConfig
+		valid bool
+	}{
+		{name: "opa", cfg: PolicyEngineConfig{OPA: &OPAConfig{URL: "http://opa:8181/v1/data/panda/authz"}}, valid: true},
+		{name: "no engine", cfg: PolicyEngineConfig{}},
+		{name: "bad url", cfg: PolicyEngineConfig{OPA: &OPAConfig{URL: "opa:8181"}}},
+		{name: "negative timeout", cfg: PolicyEngineConfig{OPA: &OPAConfig{URL: "http://opa", Timeout: -time.Second}}},
+	}
+
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+type countingEngine struct {
+	calls int
+}
+
+func (e *countingEngine) Decide(context.Context, PolicyInput) (PolicyDecision, error) {
+	e.calls++
+
+	return PolicyDecision{Allow: true}, nil
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// ServiceAccountSubjectPrefix prefixes the subject and username of
+	// service-account tokens so they never collide with GitHub users.
+	ServiceAccountSubjectPrefix = "service-account:"
+
+	// DefaultServiceAccountTTL is the lifetime of a service-account token
+	// when none is given.
+	DefaultServiceAccountTTL = 90 * 24 * time.Hour
+
+	// MaxServiceAccountTTL caps service-account token lifetimes so a leaked
+	// token that was never revoked still expires.
+	MaxServiceAccountTTL = 365 * 24 * time.Hour
+)
+
+// serviceAccountNamePattern restricts names to what is safe in logs and
+// query tags.
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// ServiceAccount describes a long-lived token for a non-interactive client
+// such as a CI pipeline.
+type ServiceAccount struct {
+	// Name identifies the account in audit logs and query tags.
+	Name string
+
+	// Orgs are the GitHub orgs the account acts as a member of, for
+	// datasource allowed_orgs rules and datasource scopes.
+	Orgs []string
+
+	// Datasources are "type:name" patterns (see DatasourceScope) the token
+	// may use. They narrow, never widen, what Orgs grant. "*" allows every
+	// datasource Orgs grant.
+	Datasources []string
+
+	// Tools are the MCP tools the token may call. "*" allows every tool.
+	Tools []string
+
+	// TTL is the token lifetime. Defaults to DefaultServiceAccountTTL.
+	TTL time.Duration
+}
+
+// Validate checks the account's name, scopes and lifetime.
+func (sa ServiceAccount) Validate() error {
+	if !serviceAccountNamePattern.MatchString(sa.Name) {
+		return fmt.Errorf(
+			"invalid service account name %q: must start with a lowercase letter or digit and contain only lowercase letters, digits, '.', '_' or '-'",
+			sa.Name,
+		)
+	}
+
+	if len(sa.Datasources) == 0 {
+		return fmt.Errorf("at least one datasource pattern is required (use \"*\" for all)")
+	}
+
+	for _, pattern := range sa.Datasources {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return fmt.Errorf("datasource pattern cannot be empty")
+		}
+
+		_, name, _ := strings.Cut(pattern, ":")
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("datasource pattern %q: %w", pattern, err)
+		}
+	}
+
+	if len(sa.Tools) == 0 {
+		return fmt.Errorf("at least one tool is required (use \"*\" for all)")
+	}
+
+	for _, tool := range sa.Tools {
+		if strings.TrimSpace(tool) == "" {
+			return fmt.Errorf("tool name cannot be empty")
+		}
+	}
+
+	if sa.TTL < 0 || sa.TTL > MaxServiceAccountTTL {
+		return fmt.Errorf("ttl must be between 0 and %s", MaxServiceAccountTTL)
+	}
+
+	return nil
+}
+
+// MintServiceAccountToken signs a service-account access token with the
+// issuer's token key. The token is validated by the same middleware as
+// user tokens and carries the account's datasource and tool scopes. It
+// returns the token and its ID, which tokens.revoked accepts.
+func MintServiceAccountToken(cfg Config, sa ServiceAccount, now time.Time) (token, id string, expiresAt time.Time, err error) {
+	if err := sa.Validate(); err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	issuerURL := strings.TrimRight(strings.TrimSpace(cfg.IssuerURL), "/")
+	if issuerURL == "" {
+		return "", "", time.Time{}, fmt.Errorf("issuer_url is required to mint tokens")
+	}
+
+	if cfg.Tokens.SecretKey == "" {
+		return "", "", time.Time{}, fmt.Errorf("tokens.secret_key is required to mint tokens")
+	}
+
+	ttl := sa.TTL
+	if ttl == 0 {
+		ttl = DefaultServiceAccountTTL
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("generating token ID: %w", err)
+	}
+
+	id = hex.EncodeToString(idBytes)
+	expiresAt = now.Add(ttl)
+
+	claims := &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    issuerURL,
+			Subject:   ServiceAccountSubjectPrefix + sa.Name,
+			Audience:  jwt.ClaimStrings{issuerURL},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Orgs:           trimAll(sa.Orgs),
+		ServiceAccount: true,
+		Datasources:    trimAll(sa.Datasources),
+		Tools:          trimAll(sa.Tools),
+	}
+
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Tokens.SecretKey))
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("signing token: %w", err)
+	}
+
+	return token, id, expiresAt, nil
+}
+
+// ServiceAccountTools returns the tool scopes of a service-account token, or
+// nil when token is not one. The signature is not checked: the server reads
+// the token from its own proxy config, and the proxy verifies it on use.
+func ServiceAccountTools(token string) []string {
+	claims := &tokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil || !claims.ServiceAccount {
+		return nil
+	}
+
+	// An account minted without tools must not fall back to every tool.
+	if claims.Tools == nil {
+		return []string{}
+	}
+
+	return claims.Tools
+}
+
+// AllowsDatasource reports whether the user's token scopes permit the
+// datasource. Only service-account tokens carry datasource scopes; a nil user
+// or a user token is not restricted here.
+func (u *AuthUser) AllowsDatasource(dsType, name string) bool {
+	if u == nil || u.Datasources == nil {
+		return true
+	}
+
+	for _, pattern := range u.Datasources {
+		if matchDatasource(pattern, dsType, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsTool reports whether the user's token scopes permit the MCP tool.
+func (u *AuthUser) AllowsTool(tool string) bool {
+	if u == nil || u.Tools == nil {
+		return true
+	}
+
+	return toolAllowed(u.Tools, tool)
+}
+
+func toolAllowed(tools []string, tool string) bool {
+	for _, allowed := range tools {
+		if allowed == ToolPolicyWildcard || allowed == tool {
+			return true
+		}
+	}
+
+	return false
+}
+
+func trimAll(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed = append(trimmed, strings.TrimSpace(value))
+	}
+
+	return trimmed
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func mintTestServiceAccount(t *testing.T, sa ServiceAccount) (string, string) {
+	t.Helper()
+
+	token, id, _, err := MintServiceAccountToken(Config{
+		IssuerURL: testIssuerURL,
+		Tokens:    TokensConfig{SecretKey: "test-secret"},
+	}, sa, time.Now())
+	if err != nil {
+		t.Fatalf("MintServiceAccountToken failed: %v", err)
+	}
+
+	return token, id
+}
+
+// authenticate runs token through svc's middleware and returns the user it
+// attached, or nil with the response status when the token was rejected.
+func authenticate(t *testing.T, svc *simpleService, token string) (*AuthUser, int) {
+	t.Helper()
+
+	var user *AuthUser
+
+	handler := svc.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		user = GetAuthUser(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://internal-proxy/clickhouse/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return user, rec.Code
+}
+
+func TestServiceAccountTokenCarriesScopes(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestSimpleService(t, []string{"ethpandaops"})
+	token, _ := mintTestServiceAccount(t, ServiceAccount{
+		Name:        "nightly-ci",
+		Orgs:        []string{"ethpandaops"},
+		Datasources: []string{"clickhouse:xatu", "prometheus"},
+		Tools:       []string{"execute_python"},
+	})
+
+	user, status := authenticate(t, svc, token)
+	if user == nil {
+		t.Fatalf("expected the token to authenticate, got status %d", status)
+	}
+
+	if user.Username != "service-account:nightly-ci" || user.Subject != user.Username {
+		t.Fatalf("unexpected identity: %+v", user)
+	}
+
+	if len(user.Groups) != 1 || user.Groups[0] != "ethpandaops" {
+		t.Fatalf("unexpected groups: %v", user.Groups)
+	}
+
+	datasources := []struct {
+		dsType, name string
+		allowed      bool
+	}{
+		{"clickhouse", "xatu", true},
+		{"clickhouse", "xatu-experimental", false},
+		{"prometheus", "internal", true},
+		{"loki", "logs", false},
+		{"ethnode", "", false},
+	}
+
+	for _, ds := range datasources {
+		if got := user.AllowsDatasource(ds.dsType, ds.name); got != ds.allowed {
+			t.Fatalf("AllowsDatasource(%s, %s) = %v, want %v", ds.dsType, ds.name, got, ds.allowed)
+		}
+	}
+
+	policy := NewToolPolicy(nil)
+	ctx := context.WithValue(context.Background(), authUserKey, user)
+
+	if err := policy.Authorize(ctx, "execute_python"); err != nil {
+		t.Fatalf("expected execute_python to be allowed, got %v", err)
+	}
+
+	if err := policy.Authorize(ctx, "manage_session"); !errors.Is(err, ErrToolForbidden) {
+		t.Fatalf("expected manage_session to be forbidden, got %v", err)
+	}
+}
+
+func TestUserTokensAreNotScoped(t *testing.T) {
+	t.Parallel()
+
+	user := &AuthUser{GitHubLogin: "sam", Orgs: []string{"ethpandaops"}}
+
+	if !user.AllowsDatasource("loki", "logs") || !user.AllowsTool("manage_session") {
+		t.Fatal("expected a user token to be unrestricted by token scopes")
+	}
+
+	var anonymous *AuthUser
+	if !anonymous.AllowsDatasource("loki", "logs") || !anonymous.AllowsTool("manage_session") {
+		t.Fatal("expected no user to be unrestricted by token scopes")
+	}
+}
+
+func TestRevokedServiceAccountTokenIsRejected(t *testing.T) {
+	t.Parallel()
+
+	token, id := mintTestServiceAccount(t, ServiceAccount{
+		Name:        "nightly-ci",
+		Datasources: []string{"*"},
+		Tools:       []string{"*"},
+	})
+
+	service, err := NewSimpleService(logrus.New(), Config{
+		Enabled:   true,
+		IssuerURL: testIssuerURL,
+		GitHub:    &GitHubConfig{ClientID: "github-client", ClientSecret: "github-secret"},
+		Tokens:    TokensConfig{SecretKey: "test-secret", Revoked: []string{id}},
+	})
+	if err != nil {
+		t.Fatalf("NewSimpleService failed: %v", err)
+	}
+
+	if user, status := authenticate(t, service.(*simpleService), token); user != nil || status != http.StatusUnauthorized {
+		t.Fatalf("expected a revoked token to be rejected, got user %+v and status %d", user, status)
+	}
+}
+
+func TestServiceAccountValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := ServiceAccount{Name: "ci", Datasources: []string{"*"}, Tools: []string{"*"}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	invalid := map[string]ServiceAccount{
+		"bad name":        {Name: "CI Bot", Datasources: []string{"*"}, Tools: []string{"*"}},
+		"no datasources":  {Name: "ci", Tools: []string{"*"}},
+		"bad pattern":     {Name: "ci", Datasources: []string{"clickhouse:["}, Tools: []string{"*"}},
+		"no tools":        {Name: "ci", Datasources: []string{"*"}},
+		"ttl above max":   {Name: "ci", Datasources: []string{"*"}, Tools: []string{"*"}, TTL: 2 * MaxServiceAccountTTL},
+		"negative ttl":    {Name: "ci", Datasources: []string{"*"}, Tools: []string{"*"}, TTL: -time.Hour},
+		"empty tool name": {Name: "ci", Datasources: []string{"*"}, Tools: []string{" "}},
+	}
+
+	for name, sa := range invalid {
+		if err := sa.Validate(); err == nil {
+			t.Fatalf("%s: expected Validate() to fail", name)
+		}
+	}
+}
+
+func TestProxyTokenToolScopesRestrictEveryCaller(t *testing.T) {
+	t.Parallel()
+
+	token, _ := mintTestServiceAccount(t, ServiceAccount{
+		Name:        "nightly-ci",
+		Datasources: []string{"*"},
+		Tools:       []string{"execute_python"},
+	})
+
+	tools := ServiceAccountTools(token)
+	if len(tools) != 1 || tools[0] != "execute_python" {
+		t.Fatalf("unexpected tool scopes: %v", tools)
+	}
+
+	if got := ServiceAccountTools("not-a-jwt"); got != nil {
+		t.Fatalf("expected no tool scopes for a non-JWT token, got %v", got)
+	}
+
+	// In CI the token only goes to the proxy, so tool calls reach the server
+	// without an authenticated user.
+	policy := NewToolPolicy(nil).WithTokenTools(tools)
+	ctx := context.Background()
+
+	if err := policy.Authorize(ctx, "execute_python"); err != nil {
+		t.Fatalf("expected execute_python to be allowed, got %v", err)
+	}
+
+	if err := policy.Authorize(ctx, "manage_session"); !errors.Is(err, ErrToolForbidden) {
+		t.Fatalf("expected manage_session to be forbidden, got %v", err)
+	}
+}
+
+func TestServiceAccountOwnerIDIsSubject(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestSimpleService(t, []string{"ethpandaops"})
+
+	owners := make(map[string]bool, 2)
+
+	for _, name := range []string{"nightly-ci", "release-ci"} {
+		token, _ := mintTestServiceAccount(t, ServiceAccount{Name: name, Datasources: []string{"*"}, Tools: []string{"*"}})
+
+		user, status := authenticate(t, svc, token)
+		if user == nil {
+			t.Fatalf("expected the token to authenticate, got status %d", status)
+		}
+
+		owner := OwnerID(context.WithValue(context.Background(), authUserKey, user))
+		if owner != ServiceAccountSubjectPrefix+name {
+			t.Fatalf("expected owner %q, got %q", ServiceAccountSubjectPrefix+name, owner)
+		}
+
+		owners[owner] = true
+	}
+
+	if len(owners) != 2 {
+		t.Fatalf("expected service accounts to have distinct owners, got %v", owners)
+	}
+}
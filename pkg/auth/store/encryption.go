@@ -0,0 +1,256 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// PassphraseEnvVar holds a passphrase that encrypted credential files
+	// are keyed from instead of the generated key file.
+	PassphraseEnvVar = "PANDA_CREDENTIALS_KEY"
+
+	envelopeVersion = 1
+	keySize         = 32
+	saltSize        = 16
+
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-SHA256.
+	pbkdf2Iterations = 600_000
+)
+
+// Key sources recorded in an envelope so Load knows how to rebuild the key.
+const (
+	keySourceKeychain   = "keychain"
+	keySourceFile       = "file"
+	keySourcePassphrase = "passphrase"
+)
+
+// envelope is the on-disk format of an encrypted credentials file.
+type envelope struct {
+	Version    int    `json:"version"`
+	KeySource  string `json:"key_source"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// parseEnvelope returns the envelope in data, or nil if data holds plaintext
+// tokens.
+func parseEnvelope(data []byte) (*envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	if len(env.Ciphertext) == 0 {
+		return nil, nil
+	}
+
+	if env.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported credentials envelope version %d", env.Version)
+	}
+
+	return &env, nil
+}
+
+// seal encrypts plaintext with AES-256-GCM under a key from keySource.
+func (s *store) seal(plaintext []byte, keySource string) ([]byte, error) {
+	env := &envelope{Version: envelopeVersion, KeySource: keySource}
+
+	if keySource == keySourcePassphrase {
+		env.Salt = make([]byte, saltSize)
+		if _, err := rand.Read(env.Salt); err != nil {
+			return nil, fmt.Errorf("generating salt: %w", err)
+		}
+	}
+
+	key, err := s.encryptionKey(env, true)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	env.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	env.Ciphertext = gcm.Seal(nil, env.Nonce, plaintext, nil)
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// open decrypts an envelope written by seal.
+func (s *store) open(env *envelope) ([]byte, error) {
+	key, err := s.encryptionKey(env, false)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting credentials (wrong key?): %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// encryptionKey returns the key for env's key source, creating it if create
+// is set and it does not exist yet. The caller must hold s.mu.
+func (s *store) encryptionKey(env *envelope, create bool) ([]byte, error) {
+	cacheKey := env.KeySource + ":" + hex.EncodeToString(env.Salt)
+	if env.KeySource == keySourcePassphrase {
+		// Different passphrases must not share a cached key.
+		sum := sha256.Sum256([]byte(os.Getenv(PassphraseEnvVar)))
+		cacheKey += ":" + hex.EncodeToString(sum[:])
+	}
+
+	if key, ok := s.keys[cacheKey]; ok {
+		return key, nil
+	}
+
+	key, err := s.loadKey(env, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.keys == nil {
+		s.keys = make(map[string][]byte, 1)
+	}
+
+	s.keys[cacheKey] = key
+
+	return key, nil
+}
+
+// loadKey reads or derives the key for env's key source.
+func (s *store) loadKey(env *envelope, create bool) ([]byte, error) {
+	switch env.KeySource {
+	case keySourcePassphrase:
+		passphrase := os.Getenv(PassphraseEnvVar)
+		if passphrase == "" {
+			return nil, fmt.Errorf("credentials are passphrase-encrypted; set $%s", PassphraseEnvVar)
+		}
+
+		return pbkdf2.Key(sha256.New, passphrase, env.Salt, pbkdf2Iterations, keySize)
+	case keySourceFile:
+		key, err := loadOrCreateKeyFile(keyFilePath(), create)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf(
+				"credentials are encrypted with the key file %s, which is missing here; "+
+					"use $%s to share credentials with the server container: %w",
+				keyFilePath(), PassphraseEnvVar, err,
+			)
+		}
+
+		return key, err
+	case keySourceKeychain:
+		kc := openKeychain()
+
+		key, err := kc.get()
+		if errors.Is(err, errKeychainNotFound) && create {
+			key, err = generateKey()
+			if err == nil {
+				err = kc.set(key)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading key from OS keychain: %w", err)
+		}
+
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unknown credentials key source %q", env.KeySource)
+	}
+}
+
+// loadOrCreateKeyFile reads the hex-encoded key at path, generating it first
+// if create is set and the file does not exist.
+func loadOrCreateKeyFile(path string, create bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("invalid credentials key file %s", path)
+		}
+
+		return key, nil
+	}
+
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("reading credentials key file: %w", err)
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating config directory: %w", err)
+	}
+
+	// O_EXCL so two concurrent logins cannot each write a different key.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return loadOrCreateKeyFile(path, false)
+		}
+
+		return nil, fmt.Errorf("creating credentials key file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("writing credentials key file: %w", err)
+	}
+
+	return key, nil
+}
+
+// keyFilePath is where the generated key for encrypted credential files
+// lives. It is kept out of the credentials directory, which the server
+// container mounts, so that directory never carries its own key.
+func keyFilePath() string {
+	home, _ := os.UserHomeDir()
+
+	return filepath.Join(home, ".config", "panda", "encryption.key")
+}
+
+func generateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
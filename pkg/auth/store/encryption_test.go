@@ -0,0 +1,177 @@
+package store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	authclient "github.com/ethpandaops/panda/pkg/auth/client"
+)
+
+type memoryKeychain struct {
+	secret []byte
+}
+
+func (k *memoryKeychain) get() ([]byte, error) {
+	if k.secret == nil {
+		return nil, errKeychainNotFound
+	}
+
+	return k.secret, nil
+}
+
+func (k *memoryKeychain) set(secret []byte) error {
+	k.secret = secret
+
+	return nil
+}
+
+func useKeychain(t *testing.T, kc keychain) {
+	t.Helper()
+
+	previous := openKeychain
+	openKeychain = func() keychain { return kc }
+	t.Cleanup(func() { openKeychain = previous })
+}
+
+func newEncryptedStore(t *testing.T, backend string) Store {
+	t.Helper()
+
+	return New(logrus.New(), Config{
+		Path:    filepath.Join(t.TempDir(), "credentials.json"),
+		Backend: backend,
+	})
+}
+
+func assertRoundTrip(t *testing.T, s Store, wantKeySource string) {
+	t.Helper()
+
+	if err := s.Save(&authclient.Tokens{AccessToken: "secret-access", RefreshToken: "secret-refresh"}); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatalf("reading credentials file: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("secret-")) {
+		t.Fatalf("expected tokens to be encrypted on disk, got %s", data)
+	}
+
+	env, err := parseEnvelope(data)
+	if err != nil || env == nil {
+		t.Fatalf("expected an encrypted envelope, got %v (%s)", err, data)
+	}
+
+	if env.KeySource != wantKeySource {
+		t.Fatalf("expected key source %q, got %q", wantKeySource, env.KeySource)
+	}
+
+	// A fresh store must decrypt from disk rather than use the cached tokens.
+	tokens, err := New(logrus.New(), Config{Path: s.Path()}).Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if tokens == nil || tokens.AccessToken != "secret-access" || tokens.RefreshToken != "secret-refresh" {
+		t.Fatalf("unexpected tokens after round trip: %+v", tokens)
+	}
+}
+
+func TestEncryptedBackendUsesGeneratedKeyFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(PassphraseEnvVar, "")
+
+	assertRoundTrip(t, newEncryptedStore(t, BackendEncrypted), keySourceFile)
+
+	info, err := os.Stat(keyFilePath())
+	if err != nil {
+		t.Fatalf("expected a generated key file: %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("expected key file mode 0600, got %o", perm)
+	}
+
+	credentialsKey := filepath.Join(home, ".config", "panda", "credentials", "encryption.key")
+	if _, err := os.Stat(credentialsKey); !os.IsNotExist(err) {
+		t.Fatalf("expected no key file in the credentials directory, got %v", err)
+	}
+}
+
+func TestEncryptedBackendUsesPassphrase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(PassphraseEnvVar, "correct horse battery staple")
+
+	s := newEncryptedStore(t, BackendEncrypted)
+	assertRoundTrip(t, s, keySourcePassphrase)
+
+	if _, err := os.Stat(keyFilePath()); !os.IsNotExist(err) {
+		t.Fatalf("expected no key file with a passphrase, got %v", err)
+	}
+
+	t.Setenv(PassphraseEnvVar, "wrong")
+
+	if _, err := New(logrus.New(), Config{Path: s.Path()}).Load(); err == nil {
+		t.Fatal("expected Load to fail with the wrong passphrase")
+	}
+
+	t.Setenv(PassphraseEnvVar, "")
+
+	_, err := New(logrus.New(), Config{Path: s.Path()}).Load()
+	if err == nil || !strings.Contains(err.Error(), PassphraseEnvVar) {
+		t.Fatalf("expected a missing passphrase error, got %v", err)
+	}
+}
+
+func TestKeychainBackendStoresKeyInKeychain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	kc := &memoryKeychain{}
+	useKeychain(t, kc)
+
+	assertRoundTrip(t, newEncryptedStore(t, BackendKeychain), keySourceKeychain)
+
+	if len(kc.secret) != keySize {
+		t.Fatalf("expected a %d-byte key in the keychain, got %d bytes", keySize, len(kc.secret))
+	}
+}
+
+func TestKeychainBackendFallsBackToEncryptedFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(PassphraseEnvVar, "")
+	useKeychain(t, unavailableKeychain{})
+
+	assertRoundTrip(t, newEncryptedStore(t, BackendKeychain), keySourceFile)
+}
+
+func TestEncryptedBackendMigratesPlaintextCredentials(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(PassphraseEnvVar, "")
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+
+	if err := New(logrus.New(), Config{Path: path}).Save(&authclient.Tokens{AccessToken: "secret-access"}); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	tokens, err := New(logrus.New(), Config{Path: path, Backend: BackendEncrypted}).Load()
+	if err != nil || tokens == nil || tokens.AccessToken != "secret-access" {
+		t.Fatalf("expected plaintext credentials to load, got %+v, %v", tokens, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading credentials file: %v", err)
+	}
+
+	if env, err := parseEnvelope(data); err != nil || env == nil {
+		t.Fatalf("expected credentials to be encrypted after Load, got %s", data)
+	}
+}
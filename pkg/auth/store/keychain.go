@@ -0,0 +1,39 @@
+package store
+
+import "errors"
+
+const (
+	// keychainService and keychainAccount identify the credentials
+	// encryption key in the OS keychain.
+	keychainService = "panda"
+	keychainAccount = "credentials-key"
+)
+
+var (
+	errKeychainNotFound    = errors.New("keychain item not found")
+	errKeychainUnavailable = errors.New("no OS keychain available")
+)
+
+// keychain stores a single secret in the OS credential store: the macOS
+// Keychain, the Secret Service on Linux or the Windows Credential Manager.
+type keychain interface {
+	// get returns the secret or errKeychainNotFound.
+	get() ([]byte, error)
+
+	// set creates or replaces the secret.
+	set(secret []byte) error
+}
+
+// openKeychain returns the platform keychain. Tests replace it.
+var openKeychain = newKeychain
+
+// unavailableKeychain is used where no OS keychain can be reached.
+type unavailableKeychain struct{}
+
+func (unavailableKeychain) get() ([]byte, error) {
+	return nil, errKeychainUnavailable
+}
+
+func (unavailableKeychain) set([]byte) error {
+	return errKeychainUnavailable
+}
@@ -0,0 +1,57 @@
+//go:build darwin
+
+package store
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit status of security(1) for a missing item.
+const errSecItemNotFound = 44
+
+// macKeychain stores the secret in the login keychain via security(1).
+type macKeychain struct{}
+
+func newKeychain() keychain {
+	if _, err := exec.LookPath("security"); err != nil {
+		return unavailableKeychain{}
+	}
+
+	return macKeychain{}
+}
+
+func (macKeychain) get() ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password",
+		"-s", keychainService, "-a", keychainAccount, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+			return nil, errKeychainNotFound
+		}
+
+		return nil, fmt.Errorf("security find-generic-password: %w", err)
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (macKeychain) set(secret []byte) error {
+	// Pass the command on stdin so the secret never appears in argv.
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		keychainService, keychainAccount, hex.EncodeToString(secret)))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("security add-generic-password: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
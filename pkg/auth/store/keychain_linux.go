@@ -0,0 +1,67 @@
+//go:build linux
+
+package store
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceKeychain stores the secret with the freedesktop Secret Service
+// (GNOME Keyring, KWallet) via secret-tool(1).
+type secretServiceKeychain struct{}
+
+func newKeychain() keychain {
+	// The Secret Service lives on the session bus; without one (SSH sessions,
+	// containers) secret-tool can only fail.
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return unavailableKeychain{}
+	}
+
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return unavailableKeychain{}
+	}
+
+	return secretServiceKeychain{}
+}
+
+func (secretServiceKeychain) get() ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// secret-tool exits 1 without output when nothing matches.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return nil, errKeychainNotFound
+		}
+
+		return nil, fmt.Errorf("secret-tool lookup: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (secretServiceKeychain) set(secret []byte) error {
+	var stderr bytes.Buffer
+
+	// secret-tool reads the secret from stdin, keeping it out of argv.
+	cmd := exec.Command("secret-tool", "store", "--label=panda credentials key",
+		"service", keychainService, "account", keychainAccount)
+	cmd.Stdin = strings.NewReader(hex.EncodeToString(secret))
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool store: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package store
+
+func newKeychain() keychain {
+	return unavailableKeychain{}
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential mirrors the Win32 CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManagerKeychain stores the secret as a generic credential in the
+// Windows Credential Manager.
+type credentialManagerKeychain struct{}
+
+func newKeychain() keychain {
+	if err := procCredReadW.Find(); err != nil {
+		return unavailableKeychain{}
+	}
+
+	return credentialManagerKeychain{}
+}
+
+func credentialTarget() (*uint16, error) {
+	return syscall.UTF16PtrFromString(keychainService + ":" + keychainAccount)
+}
+
+func (credentialManagerKeychain) get() ([]byte, error) {
+	target, err := credentialTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	var cred *credential
+
+	ret, _, callErr := procCredReadW.Call(
+		uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(callErr, errorNotFound) {
+			return nil, errKeychainNotFound
+		}
+
+		return nil, fmt.Errorf("CredReadW: %w", callErr)
+	}
+	defer func() { _, _, _ = procCredFree.Call(uintptr(unsafe.Pointer(cred))) }()
+
+	secret := make([]byte, cred.CredentialBlobSize)
+	copy(secret, unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize))
+
+	return secret, nil
+}
+
+func (credentialManagerKeychain) set(secret []byte) error {
+	target, err := credentialTarget()
+	if err != nil {
+		return err
+	}
+
+	user, err := syscall.UTF16PtrFromString(keychainAccount)
+	if err != nil {
+		return err
+	}
+
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		UserName:           user,
+		CredentialBlobSize: uint32(len(secret)),
+		CredentialBlob:     unsafe.SliceData(secret),
+		Persist:            credPersistLocalMachine,
+	}
+
+	ret, _, callErr := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if ret == 0 {
+		return fmt.Errorf("CredWriteW: %w", callErr)
+	}
+
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +20,45 @@ import (
 	"github.com/ethpandaops/panda/pkg/auth/client"
 )
 
+const (
+	// ProfileEnvVar overrides the credential profile when no explicit profile is given.
+	ProfileEnvVar = "PANDA_PROFILE"
+
+	// DefaultProfile is the profile used when none is selected. Its credentials
+	// live at the pre-profile locations so existing logins keep working.
+	DefaultProfile = "default"
+)
+
+// Credential storage backends.
+const (
+	// BackendFile stores tokens as a plaintext JSON file readable only by the
+	// owner. It is the default.
+	BackendFile = "file"
+
+	// BackendEncrypted encrypts the credentials file with AES-256-GCM under a
+	// generated key file, or under $PANDA_CREDENTIALS_KEY when set.
+	BackendEncrypted = "encrypted"
+
+	// BackendKeychain encrypts the credentials file under a key held in the
+	// OS keychain, falling back to BackendEncrypted when none is available.
+	BackendKeychain = "keychain"
+)
+
+// ValidateBackend returns an error if name is not a known backend. Empty
+// selects BackendFile.
+func ValidateBackend(name string) error {
+	switch name {
+	case "", BackendFile, BackendEncrypted, BackendKeychain:
+		return nil
+	default:
+		return fmt.Errorf("unknown credential store %q (expected %s, %s or %s)",
+			name, BackendFile, BackendEncrypted, BackendKeychain)
+	}
+}
+
+// profileNamePattern restricts profile names to safe path components.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
 // Store manages local credential storage.
 type Store interface {
 	// Path returns the resolved credentials file path.
@@ -54,6 +95,16 @@ type Config struct {
 	// Resource namespaces stored credentials by requested resource.
 	Resource string
 
+	// Profile selects a named credential set so logins against different
+	// deployments (e.g. staging and prod) do not overwrite each other.
+	// Empty or "default" uses the default credential location.
+	Profile string
+
+	// Backend selects how tokens are stored on disk. Encrypted files are
+	// read whatever the backend, so switching backends keeps existing
+	// logins. Defaults to BackendFile.
+	Backend string
+
 	// RefreshBuffer is how long before expiry to refresh the token.
 	RefreshBuffer time.Duration
 
@@ -72,6 +123,14 @@ type store struct {
 	cfg    Config
 	mu     sync.RWMutex
 	tokens *client.Tokens
+
+	// keys caches encryption keys by key source and salt, so passphrase
+	// derivation and keychain lookups do not run on every Load. Guarded by mu.
+	keys map[string][]byte
+
+	// refreshMu serializes refreshes so a rotated refresh token is never
+	// redeemed twice.
+	refreshMu sync.Mutex
 }
 
 // New creates a new credential store.
@@ -99,6 +158,12 @@ func (s *store) Save(tokens *client.Tokens) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.write(tokens)
+}
+
+// write encodes tokens for the configured backend and writes them to disk.
+// The caller must hold s.mu.
+func (s *store) write(tokens *client.Tokens) error {
 	// Ensure directory exists.
 	dir := filepath.Dir(s.cfg.Path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
@@ -111,6 +176,11 @@ func (s *store) Save(tokens *client.Tokens) error {
 		return fmt.Errorf("marshaling tokens: %w", err)
 	}
 
+	data, err = s.encode(data)
+	if err != nil {
+		return fmt.Errorf("encrypting tokens: %w", err)
+	}
+
 	// Write file with secure permissions.
 	if err := os.WriteFile(s.cfg.Path, data, 0600); err != nil {
 		return fmt.Errorf("writing credentials file: %w", err)
@@ -122,6 +192,32 @@ func (s *store) Save(tokens *client.Tokens) error {
 	return nil
 }
 
+// encode returns the file contents for plaintext under the configured backend.
+func (s *store) encode(plaintext []byte) ([]byte, error) {
+	switch s.cfg.Backend {
+	case "", BackendFile:
+		return plaintext, nil
+	case BackendKeychain:
+		data, err := s.seal(plaintext, keySourceKeychain)
+		if err == nil {
+			return data, nil
+		}
+
+		s.log.WithError(err).Warn("OS keychain unavailable, falling back to an encrypted credentials file")
+
+		fallthrough
+	case BackendEncrypted:
+		source := keySourceFile
+		if os.Getenv(PassphraseEnvVar) != "" {
+			source = keySourcePassphrase
+		}
+
+		return s.seal(plaintext, source)
+	default:
+		return nil, ValidateBackend(s.cfg.Backend)
+	}
+}
+
 // Load loads tokens from the store.
 func (s *store) Load() (*client.Tokens, error) {
 	s.mu.Lock()
@@ -137,6 +233,17 @@ func (s *store) Load() (*client.Tokens, error) {
 		return nil, fmt.Errorf("reading credentials file: %w", err)
 	}
 
+	env, err := parseEnvelope(data)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling tokens: %w", err)
+	}
+
+	if env != nil {
+		if data, err = s.open(env); err != nil {
+			return nil, err
+		}
+	}
+
 	var tokens client.Tokens
 	if err := json.Unmarshal(data, &tokens); err != nil {
 		return nil, fmt.Errorf("unmarshaling tokens: %w", err)
@@ -144,6 +251,14 @@ func (s *store) Load() (*client.Tokens, error) {
 
 	s.tokens = &tokens
 
+	// Encrypt plaintext credentials left over from before an encrypting
+	// backend was selected.
+	if env == nil && s.cfg.Backend != "" && s.cfg.Backend != BackendFile {
+		if err := s.write(&tokens); err != nil {
+			s.log.WithError(err).Warn("Failed to encrypt existing credentials")
+		}
+	}
+
 	return &tokens, nil
 }
 
@@ -173,27 +288,39 @@ func (s *store) GetAccessToken() (string, error) {
 	}
 
 	// Check if token needs refresh.
-	if s.needsRefresh(tokens) {
-		if tokens.RefreshToken == "" {
-			if time.Now().Before(tokens.ExpiresAt) {
-				return tokens.AccessToken, nil
-			}
+	if !s.needsRefresh(tokens) {
+		return tokens.AccessToken, nil
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Another caller may have refreshed while we waited.
+	if current, err := s.getTokens(); err == nil && current != nil {
+		tokens = current
+		if !s.needsRefresh(tokens) {
+			return tokens.AccessToken, nil
+		}
+	}
 
-			return "", fmt.Errorf("access token expired and no refresh token available")
+	if tokens.RefreshToken == "" {
+		if time.Now().Before(tokens.ExpiresAt) {
+			return tokens.AccessToken, nil
 		}
 
-		newTokens, err := s.refresh(tokens.RefreshToken)
-		if err != nil {
-			if time.Now().Before(tokens.ExpiresAt) {
-				return tokens.AccessToken, nil
-			}
+		return "", fmt.Errorf("access token expired and no refresh token available")
+	}
 
-			return "", fmt.Errorf("refreshing token: %w", err)
+	newTokens, err := s.refresh(tokens.RefreshToken)
+	if err != nil {
+		if time.Now().Before(tokens.ExpiresAt) {
+			return tokens.AccessToken, nil
 		}
-		tokens = newTokens
+
+		return "", fmt.Errorf("refreshing token: %w", err)
 	}
 
-	return tokens.AccessToken, nil
+	return newTokens.AccessToken, nil
 }
 
 // IsAuthenticated returns true if valid tokens are stored.
@@ -277,13 +404,89 @@ func (s *store) refresh(refreshToken string) (*client.Tokens, error) {
 	return newTokens, nil
 }
 
+// ResolveProfile returns the credential profile to use: explicit (the
+// --profile flag) if set, otherwise $PANDA_PROFILE, otherwise configured
+// (proxy.auth.profile), otherwise DefaultProfile. The result names a
+// directory, so it is validated whichever source it came from.
+func ResolveProfile(explicit, configured string) (string, error) {
+	profile := DefaultProfile
+
+	for _, candidate := range []string{explicit, os.Getenv(ProfileEnvVar), configured} {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			profile = candidate
+
+			break
+		}
+	}
+
+	if err := ValidateProfile(profile); err != nil {
+		return "", err
+	}
+
+	return profile, nil
+}
+
+// ValidateProfile returns an error if name is not a valid profile name.
+func ValidateProfile(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf(
+			"invalid profile %q: must start with a letter or digit and contain only letters, digits, '.', '_' or '-'",
+			name,
+		)
+	}
+
+	return nil
+}
+
+// ListProfiles returns the names of profiles that have stored credentials.
+// The default profile is always included.
+func ListProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(profilesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+
+		return nil, fmt.Errorf("reading profiles directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile && ValidateProfile(entry.Name()) == nil {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+
+	sort.Strings(profiles[1:])
+
+	return profiles, nil
+}
+
+func profilesDir() string {
+	home, _ := os.UserHomeDir()
+
+	return filepath.Join(home, ".config", "panda", "credentials", "profiles")
+}
+
 func defaultCredentialPath(cfg Config) string {
+	key := credentialNamespaceKey(cfg.IssuerURL, cfg.ClientID, cfg.Resource)
+
+	// Named profiles live under credentials/ so they are visible wherever the
+	// credentials directory is mounted (e.g. the server container).
+	if profile := strings.TrimSpace(cfg.Profile); profile != "" && profile != DefaultProfile {
+		filename := "credentials.json"
+		if key != "" {
+			filename = key + ".json"
+		}
+
+		return filepath.Join(profilesDir(), profile, filename)
+	}
+
 	home, _ := os.UserHomeDir()
 	newBaseDir := filepath.Join(home, ".config", "panda")
 	oldBaseDir := filepath.Join(home, ".config", "ethpandaops-mcp")
 
-	key := credentialNamespaceKey(cfg.IssuerURL, cfg.ClientID, cfg.Resource)
-
 	var newPath, oldPath string
 	if key == "" {
 		newPath = filepath.Join(newBaseDir, "credentials.json")
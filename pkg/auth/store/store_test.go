@@ -11,6 +11,83 @@ import (
 	authclient "github.com/ethpandaops/panda/pkg/auth/client"
 )
 
+func TestProfilesUseSeparateCredentialPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ProfileEnvVar, "")
+
+	base := Config{IssuerURL: "https://issuer.example.com", ClientID: "panda"}
+
+	defaultCfg := base
+	stagingCfg := base
+	stagingCfg.Profile = "staging"
+	prodCfg := base
+	prodCfg.Profile = "prod"
+
+	defaultStore := New(logrus.New(), defaultCfg)
+	stagingStore := New(logrus.New(), stagingCfg)
+	prodStore := New(logrus.New(), prodCfg)
+
+	if defaultStore.Path() == stagingStore.Path() || stagingStore.Path() == prodStore.Path() {
+		t.Fatalf("expected distinct credential paths, got %q, %q, %q",
+			defaultStore.Path(), stagingStore.Path(), prodStore.Path())
+	}
+
+	if err := stagingStore.Save(&authclient.Tokens{AccessToken: "staging-token"}); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	tokens, err := prodStore.Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if tokens != nil {
+		t.Fatalf("expected prod profile to be empty, got %+v", tokens)
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles returned error: %v", err)
+	}
+
+	if len(profiles) != 2 || profiles[0] != DefaultProfile || profiles[1] != "staging" {
+		t.Fatalf("unexpected profiles: %v", profiles)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "from-env")
+
+	if got, err := ResolveProfile("explicit", "from-config"); err != nil || got != "explicit" {
+		t.Fatalf("expected explicit profile, got %q, %v", got, err)
+	}
+
+	if got, err := ResolveProfile("", "from-config"); err != nil || got != "from-env" {
+		t.Fatalf("expected env profile to beat the config, got %q, %v", got, err)
+	}
+
+	t.Setenv(ProfileEnvVar, "")
+
+	if got, err := ResolveProfile("", "from-config"); err != nil || got != "from-config" {
+		t.Fatalf("expected config profile, got %q, %v", got, err)
+	}
+
+	if got, err := ResolveProfile("", ""); err != nil || got != DefaultProfile {
+		t.Fatalf("expected default profile, got %q, %v", got, err)
+	}
+
+	t.Setenv(ProfileEnvVar, "../../x")
+
+	if _, err := ResolveProfile("", "from-config"); err == nil {
+		t.Fatal("expected a path-like $PANDA_PROFILE to be rejected")
+	}
+
+	if err := ValidateProfile("../etc"); err == nil {
+		t.Fatal("expected path-like profile to be rejected")
+	}
+}
+
 func TestGetAccessTokenKeepsValidTokenWithoutRefreshToken(t *testing.T) {
 	t.Parallel()
 
@@ -149,3 +226,7 @@ func (s *stubAuthClient) Refresh(_ context.Context, _ string) (*authclient.Token
 		TokenType:    "Bearer",
 	}, nil
 }
+
+func (s *stubAuthClient) Revoke(_ context.Context, _, _ string) error {
+	return nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToolPolicyWildcard is the tool name that matches every tool without a
+// rule of its own.
+const ToolPolicyWildcard = "*"
+
+// ErrToolForbidden is returned when the caller may not use a tool.
+var ErrToolForbidden = errors.New("permission denied")
+
+// ToolRule restricts who may call a set of MCP tools.
+type ToolRule struct {
+	// Tools are the tool names the rule applies to. "*" matches every tool
+	// not named by another rule.
+	Tools []string `yaml:"tools"`
+
+	// AllowedOrgs are the GitHub orgs or OIDC groups allowed to call the
+	// tools. When empty, any authenticated user is allowed.
+	AllowedOrgs []string `yaml:"allowed_orgs,omitempty"`
+}
+
+// ValidateToolRules checks that every rule names at least one tool and that
+// no tool is covered by more than one rule.
+func ValidateToolRules(rules []ToolRule) error {
+	seen := make(map[string]struct{}, len(rules))
+
+	for i, rule := range rules {
+		if len(rule.Tools) == 0 {
+			return fmt.Errorf("rule[%d]: at least one tool is required", i)
+		}
+
+		for _, name := range rule.Tools {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return fmt.Errorf("rule[%d]: tool name cannot be empty", i)
+			}
+
+			if _, ok := seen[name]; ok {
+				return fmt.Errorf("rule[%d]: tool %q is covered by more than one rule", i, name)
+			}
+
+			seen[name] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// ToolPolicy decides which authenticated users may call each MCP tool.
+// Tools without a rule (and no "*" rule) are open to everyone. Rules are
+// matched against the orgs and groups of the user in context, so a tool with
+// a rule is denied to unauthenticated callers.
+type ToolPolicy struct {
+	rules      map[string][]string // tool name -> allowed orgs; empty means any authenticated user
+	tokenTools []string            // tools the server's own proxy token may call; nil means all
+}
+
+// NewToolPolicy creates a tool policy from validated rules.
+func NewToolPolicy(rules []ToolRule) *ToolPolicy {
+	p := &ToolPolicy{rules: make(map[string][]string, len(rules))}
+
+	for _, rule := range rules {
+		for _, name := range rule.Tools {
+			p.rules[strings.TrimSpace(name)] = rule.AllowedOrgs
+		}
+	}
+
+	return p
+}
+
+// WithTokenTools restricts every caller to tools, the tool scopes of the
+// service-account token the server sends to the proxy (see
+// ServiceAccountTools). A nil tools leaves the policy unchanged.
+func (p *ToolPolicy) WithTokenTools(tools []string) *ToolPolicy {
+	p.tokenTools = tools
+
+	return p
+}
+
+// AuthorizeTokenScopes returns an error wrapping ErrToolForbidden when tool
+// is outside the scopes of the token of the user in ctx or of the server's
+// proxy token.
+func (p *ToolPolicy) AuthorizeTokenScopes(ctx context.Context, tool string) error {
+	if user := GetAuthUser(ctx); !user.AllowsTool(tool) {
+		return fmt.Errorf("%w: %s is not in the token's allowed tools", ErrToolForbidden, tool)
+	}
+
+	if p != nil && p.tokenTools != nil && !toolAllowed(p.tokenTools, tool) {
+		return fmt.Errorf("%w: %s is not in the proxy token's allowed tools", ErrToolForbidden, tool)
+	}
+
+	return nil
+}
+
+// Authorize returns an error wrapping ErrToolForbidden when the user in ctx
+// may not call tool.
+func (p *ToolPolicy) Authorize(ctx context.Context, tool string) error {
+	if err := p.AuthorizeTokenScopes(ctx, tool); err != nil {
+		return err
+	}
+
+	if p == nil {
+		return nil
+	}
+
+	allowedOrgs, ok := p.rules[tool]
+	if !ok {
+		allowedOrgs, ok = p.rules[ToolPolicyWildcard]
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if GetAuthUser(ctx) == nil {
+		return fmt.Errorf("%w: %s requires an authenticated user", ErrToolForbidden, tool)
+	}
+
+	if len(allowedOrgs) == 0 {
+		return nil
+	}
+
+	for _, group := range GetAuthGroups(ctx) {
+		for _, allowed := range allowedOrgs {
+			if group == allowed {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %s requires membership in one of: %s",
+		ErrToolForbidden, tool, strings.Join(allowedOrgs, ", "))
+}
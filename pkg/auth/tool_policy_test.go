@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestToolPolicyAuthorize(t *testing.T) {
+	t.Parallel()
+
+	policy := NewToolPolicy([]ToolRule{
+		{Tools: []string{"execute_python"}, AllowedOrgs: []string{"ethpandaops"}},
+		{Tools: []string{"search"}},
+		{Tools: []string{"*"}, AllowedOrgs: []string{"admins"}},
+	})
+
+	anonymous := context.Background()
+	member := context.WithValue(anonymous, authUserKey, &AuthUser{GitHubLogin: "sam", Orgs: []string{"ethpandaops"}})
+	outsider := context.WithValue(anonymous, authUserKey, &AuthUser{GitHubLogin: "alex", Orgs: []string{"other"}})
+	admin := context.WithValue(anonymous, authUserKey, &AuthUser{GitHubLogin: "kim", Groups: []string{"admins"}})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		tool    string
+		allowed bool
+	}{
+		{name: "member runs code", ctx: member, tool: "execute_python", allowed: true},
+		{name: "outsider cannot run code", ctx: outsider, tool: "execute_python"},
+		{name: "anonymous cannot run code", ctx: anonymous, tool: "execute_python"},
+		{name: "any user searches", ctx: outsider, tool: "search", allowed: true},
+		{name: "anonymous cannot search", ctx: anonymous, tool: "search"},
+		{name: "wildcard admits group", ctx: admin, tool: "manage_session", allowed: true},
+		{name: "wildcard denies others", ctx: member, tool: "manage_session"},
+	}
+
+	for _, tt := range tests {
+		err := policy.Authorize(tt.ctx, tt.tool)
+		if tt.allowed && err != nil {
+			t.Fatalf("%s: Authorize() error = %v, want allowed", tt.name, err)
+		}
+
+		if !tt.allowed && !errors.Is(err, ErrToolForbidden) {
+			t.Fatalf("%s: Authorize() error = %v, want ErrToolForbidden", tt.name, err)
+		}
+	}
+
+	if err := NewToolPolicy(nil).Authorize(anonymous, "execute_python"); err != nil {
+		t.Fatalf("empty policy Authorize() error = %v, want allowed", err)
+	}
+}
+
+func TestValidateToolRules(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateToolRules([]ToolRule{{Tools: []string{"search"}}, {Tools: []string{"*"}}}); err != nil {
+		t.Fatalf("ValidateToolRules() error = %v", err)
+	}
+
+	if err := ValidateToolRules([]ToolRule{{AllowedOrgs: []string{"x"}}}); err == nil {
+		t.Fatal("ValidateToolRules() accepted a rule without tools")
+	}
+
+	if err := ValidateToolRules([]ToolRule{{Tools: []string{"search"}}, {Tools: []string{"search"}}}); err == nil {
+		t.Fatal("ValidateToolRules() accepted a duplicated tool")
+	}
+}
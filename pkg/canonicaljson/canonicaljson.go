@@ -0,0 +1,164 @@
+// Package canonicaljson encodes values as canonical JSON: object keys are
+// sorted byte-wise (struct fields included), numbers use one formatting and
+// HTML characters are not escaped. Equal values always encode to identical
+// bytes, so the output can be hashed for cache revalidation.
+package canonicaljson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal returns the compact canonical encoding of v.
+func Marshal(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, tree); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent is like Marshal but indents the output like
+// json.MarshalIndent.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	compact, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact, prefix, indent); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Hash returns a content hash of data in the form "sha256:<hex>".
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func encode(buf *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case json.Number:
+		return encodeNumber(buf, val)
+	case string:
+		encodeString(buf, val)
+	case []any:
+		buf.WriteByte('[')
+
+		for i, item := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			encodeString(buf, key)
+			buf.WriteByte(':')
+
+			if err := encode(buf, val[key]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("canonicaljson: unexpected %T", v)
+	}
+
+	return nil
+}
+
+// encodeNumber keeps integer literals exact, so values beyond float64
+// precision survive, and reformats everything else through float64 so
+// 1.50, 15e-1 and 1.5 all encode as 1.5.
+func encodeNumber(buf *bytes.Buffer, n json.Number) error {
+	s := n.String()
+
+	if !strings.ContainsAny(s, ".eE") {
+		if s == "-0" {
+			s = "0"
+		}
+
+		buf.WriteString(s)
+
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+
+	if f == 0 {
+		buf.WriteString("0")
+
+		return nil
+	}
+
+	out, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(out)
+
+	return nil
+}
+
+func encodeString(buf *bytes.Buffer, s string) {
+	var out bytes.Buffer
+
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	buf.Write(bytes.TrimSuffix(out.Bytes(), []byte("\n")))
+}
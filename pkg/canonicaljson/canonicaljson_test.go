@@ -0,0 +1,51 @@
+package canonicaljson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	type inner struct {
+		Zeta  string `json:"zeta"`
+		Alpha int    `json:"alpha"`
+	}
+
+	v := struct {
+		Name   string          `json:"name"`
+		Inner  inner           `json:"inner"`
+		Raw    json.RawMessage `json:"raw"`
+		Big    json.RawMessage `json:"big"`
+		Labels map[string]any  `json:"labels"`
+	}{
+		Name:   "a<b>&c",
+		Inner:  inner{Zeta: "z", Alpha: 1},
+		Raw:    json.RawMessage(`[1.50, 15e-1, -0.0, 1E21]`),
+		Big:    json.RawMessage(`18446744073709551615`),
+		Labels: map[string]any{"b": true, "a": nil},
+	}
+
+	out, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"big":18446744073709551615,"inner":{"alpha":1,"zeta":"z"},"labels":{"a":null,"b":true},"name":"a<b>&c","raw":[1.5,1.5,0,1e+21]}`,
+		string(out))
+
+	indented, err := MarshalIndent(map[string]int{"b": 2, "a": 1}, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": 2\n}", string(indented))
+}
+
+func TestHash(t *testing.T) {
+	a, err := Marshal(map[string]any{"x": 1.0, "y": []string{"p"}})
+	require.NoError(t, err)
+
+	b, err := Marshal(json.RawMessage(`{"y":["p"],"x":1}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, Hash(a), Hash(b))
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, Hash(a))
+}
@@ -0,0 +1,55 @@
+package cartographoor
+
+import (
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/offline"
+)
+
+// cacheFile is the on-disk cache of the last fetched network data.
+type cacheFile struct {
+	FetchedAt time.Time        `json:"fetched_at"`
+	Result    discovery.Result `json:"result"`
+}
+
+// loadCache returns the cached network data and when it was fetched. It
+// reports false when there is no cache or it is older than MaxStale.
+func (c *cartographoorClient) loadCache() (discovery.Result, time.Time, bool) {
+	if c.cfg.CachePath == "" {
+		return discovery.Result{}, time.Time{}, false
+	}
+
+	var cache cacheFile
+	if err := offline.LoadSnapshot(c.cfg.CachePath, &cache); err != nil {
+		c.log.WithError(err).Debug("No network cache available")
+
+		return discovery.Result{}, time.Time{}, false
+	}
+
+	if age := time.Since(cache.FetchedAt); age > c.cfg.MaxStale {
+		c.log.WithField("age", age.Round(time.Second)).Info("Network cache is too old to serve, fetching")
+
+		return discovery.Result{}, time.Time{}, false
+	}
+
+	c.log.WithFields(logrus.Fields{
+		"fetched_at": cache.FetchedAt,
+		"networks":   len(cache.Result.Networks),
+	}).Info("Serving cached network data")
+
+	return cache.Result, cache.FetchedAt, true
+}
+
+// saveCache writes fetched network data to the cache.
+func (c *cartographoorClient) saveCache(result discovery.Result, fetchedAt time.Time) {
+	if c.cfg.CachePath == "" {
+		return
+	}
+
+	if err := offline.SaveSnapshot(c.cfg.CachePath, cacheFile{FetchedAt: fetchedAt, Result: result}); err != nil {
+		c.log.WithError(err).Warn("Failed to save network cache")
+	}
+}
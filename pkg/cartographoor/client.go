@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/offline"
 )
 
 const (
@@ -24,6 +27,18 @@ const (
 
 	// DefaultHTTPTimeout is the default HTTP request timeout.
 	DefaultHTTPTimeout = 30 * time.Second
+
+	// DefaultMaxStale is how old cached data may be and still be served
+	// while it is revalidated.
+	DefaultMaxStale = 24 * time.Hour
+)
+
+// Where the served network data came from.
+const (
+	SourceNone     = "none"
+	SourceRemote   = "remote"
+	SourceCache    = "cache"
+	SourceSnapshot = "snapshot"
 )
 
 // groupPattern extracts group name from repository (e.g., "ethpandaops/fusaka-devnets" -> "fusaka").
@@ -34,6 +49,42 @@ type CartographoorConfig struct {
 	URL      string
 	CacheTTL time.Duration
 	Timeout  time.Duration
+	// SnapshotPath, if set, is where fetched network data is saved.
+	SnapshotPath string
+	// Offline serves the snapshot at SnapshotPath instead of fetching.
+	Offline bool
+	// CachePath, if set, is where fetched data is cached across restarts.
+	// Cached data younger than MaxStale is served at startup and
+	// revalidated in the background once older than CacheTTL.
+	CachePath string
+	MaxStale  time.Duration
+	// Overrides add or change networks on top of cartographoor's.
+	Overrides []NetworkOverride
+	// Hide lists glob patterns of network names to leave out.
+	Hide []string
+}
+
+// Status describes where the served network data came from and how fresh
+// it is.
+type Status struct {
+	// Source is remote, cache, snapshot or none.
+	Source      string     `json:"source"`
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+	// Stale is true once the data is older than CacheTTL, e.g. while a
+	// cached copy is revalidated or when refreshes are failing.
+	Stale       bool       `json:"stale"`
+	CacheTTL    string     `json:"cache_ttl"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	NextRefresh *time.Time `json:"next_refresh,omitempty"`
+
+	NetworkCount int `json:"network_count"`
+	// Added lists overrides for networks cartographoor does not know,
+	// Overridden those that change a cartographoor network, and Hidden the
+	// networks left out by hide patterns.
+	Added      []string `json:"added,omitempty"`
+	Overridden []string `json:"overridden,omitempty"`
+	Hidden     []string `json:"hidden,omitempty"`
 }
 
 // CartographoorClient fetches and caches network data from cartographoor.
@@ -56,6 +107,14 @@ type CartographoorClient interface {
 	IsDevnet(network discovery.Network) bool
 	// GetClusters returns the xatu clusters for a network.
 	GetClusters(network discovery.Network) []string
+	// OnUpdate registers fn to be called after a refresh with the names of
+	// networks that were added, removed or changed.
+	OnUpdate(fn func(changed []string))
+	// GetLifecycle returns the genesis, fork schedule and planned shutdown
+	// of a network.
+	GetLifecycle(network discovery.Network) Lifecycle
+	// Status returns the refresh status of the network data.
+	Status() Status
 }
 
 type cartographoorClient struct {
@@ -66,7 +125,14 @@ type cartographoorClient struct {
 	mu          sync.RWMutex
 	networks    map[string]discovery.Network
 	groups      map[string][]string // group name -> network names
+	clusters    map[string][]string // network name -> overridden clusters
+	overrides   overrideResult
+	source      string
 	lastUpdated time.Time
+	lastAttempt time.Time
+	lastError   string
+	nextRefresh time.Time
+	listeners   []func(changed []string)
 
 	done chan struct{}
 	wg   sync.WaitGroup
@@ -86,6 +152,10 @@ func NewCartographoorClient(log logrus.FieldLogger, cfg CartographoorConfig) Car
 		cfg.Timeout = DefaultHTTPTimeout
 	}
 
+	if cfg.MaxStale == 0 {
+		cfg.MaxStale = DefaultMaxStale
+	}
+
 	return &cartographoorClient{
 		log: log.WithField("component", "cartographoor"),
 		cfg: cfg,
@@ -94,23 +164,36 @@ func NewCartographoorClient(log logrus.FieldLogger, cfg CartographoorConfig) Car
 		},
 		networks: make(map[string]discovery.Network),
 		groups:   make(map[string][]string),
+		clusters: make(map[string][]string),
+		source:   SourceNone,
 		done:     make(chan struct{}),
 	}
 }
 
 // Start initializes the client and starts background refresh.
 func (c *cartographoorClient) Start(ctx context.Context) error {
+	if c.cfg.Offline {
+		return c.startOffline()
+	}
+
 	c.log.WithField("url", c.cfg.URL).Info("Starting cartographoor client")
 
-	// Initial fetch
-	if err := c.refresh(ctx); err != nil {
+	// Serve cached data right away if there is any; refresh it in the
+	// background once it is older than the TTL.
+	firstRefresh := c.cfg.CacheTTL
+
+	if cached, fetchedAt, ok := c.loadCache(); ok {
+		c.apply(cached, SourceCache, fetchedAt)
+
+		firstRefresh = max(c.cfg.CacheTTL-time.Since(fetchedAt), 0)
+	} else if err := c.refresh(ctx); err != nil {
 		return fmt.Errorf("initial fetch failed: %w", err)
 	}
 
 	// Start background refresh
 	c.wg.Add(1)
 
-	go c.backgroundRefresh()
+	go c.backgroundRefresh(firstRefresh)
 
 	c.log.WithFields(logrus.Fields{
 		"network_count": len(c.networks),
@@ -121,6 +204,26 @@ func (c *cartographoorClient) Start(ctx context.Context) error {
 	return nil
 }
 
+// startOffline loads network data from the snapshot without starting
+// background refresh. A missing snapshot leaves the client empty.
+func (c *cartographoorClient) startOffline() error {
+	var result discovery.Result
+	if err := offline.LoadSnapshot(c.cfg.SnapshotPath, &result); err != nil {
+		c.log.WithError(err).Warn("No network snapshot available, serving no networks (offline)")
+
+		return nil
+	}
+
+	c.apply(result, SourceSnapshot, result.LastUpdate)
+
+	c.log.WithFields(logrus.Fields{
+		"network_count": len(c.networks),
+		"snapshot":      c.cfg.SnapshotPath,
+	}).Info("Cartographoor client serving snapshot (offline)")
+
+	return nil
+}
+
 // Stop stops the background refresh goroutine.
 func (c *cartographoorClient) Stop() error {
 	close(c.done)
@@ -214,6 +317,14 @@ func (c *cartographoorClient) IsDevnet(network discovery.Network) bool {
 
 // GetClusters returns the xatu clusters for a network.
 func (c *cartographoorClient) GetClusters(network discovery.Network) []string {
+	c.mu.RLock()
+	clusters, ok := c.clusters[network.Name]
+	c.mu.RUnlock()
+
+	if ok {
+		return clusters
+	}
+
 	if c.IsDevnet(network) {
 		return []string{"xatu-experimental", "xatu-cbt"}
 	}
@@ -221,33 +332,115 @@ func (c *cartographoorClient) GetClusters(network discovery.Network) []string {
 	return []string{"xatu", "xatu-cbt"}
 }
 
-// backgroundRefresh periodically refreshes the network data.
-func (c *cartographoorClient) backgroundRefresh() {
+// GetLifecycle returns the genesis, fork schedule and planned shutdown of a
+// network.
+func (c *cartographoorClient) GetLifecycle(network discovery.Network) Lifecycle {
+	c.mu.RLock()
+	override := c.overrides.byName[network.Name]
+	c.mu.RUnlock()
+
+	return NetworkLifecycle(network, override, time.Now())
+}
+
+// OnUpdate registers a listener for network changes.
+func (c *cartographoorClient) OnUpdate(fn func(changed []string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.listeners = append(c.listeners, fn)
+}
+
+// Status returns the refresh status of the network data.
+func (c *cartographoorClient) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status := Status{
+		Source:       c.source,
+		CacheTTL:     c.cfg.CacheTTL.String(),
+		LastError:    c.lastError,
+		NetworkCount: len(c.networks),
+		Added:        c.overrides.added,
+		Overridden:   c.overrides.overridden,
+		Hidden:       c.overrides.hidden,
+	}
+
+	if !c.lastUpdated.IsZero() {
+		lastUpdated := c.lastUpdated
+		status.LastUpdated = &lastUpdated
+		status.Stale = !c.cfg.Offline && time.Since(c.lastUpdated) > c.cfg.CacheTTL
+	}
+
+	if !c.lastAttempt.IsZero() {
+		lastAttempt := c.lastAttempt
+		status.LastAttempt = &lastAttempt
+	}
+
+	if !c.nextRefresh.IsZero() {
+		nextRefresh := c.nextRefresh
+		status.NextRefresh = &nextRefresh
+	}
+
+	return status
+}
+
+// backgroundRefresh refreshes the network data after delay and then every
+// CacheTTL.
+func (c *cartographoorClient) backgroundRefresh(delay time.Duration) {
 	defer c.wg.Done()
 
-	ticker := time.NewTicker(c.cfg.CacheTTL)
-	defer ticker.Stop()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	c.scheduleRefresh(delay)
 
 	for {
 		select {
 		case <-c.done:
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
 
 			if err := c.refresh(ctx); err != nil {
-				c.log.WithError(err).Warn("Failed to refresh network data")
+				c.log.WithError(err).Warn("Failed to refresh network data, serving previous data")
 			} else {
-				c.log.WithField("network_count", len(c.networks)).Debug("Refreshed network data")
+				c.log.WithField("network_count", len(c.GetAllNetworks())).Debug("Refreshed network data")
 			}
 
 			cancel()
+
+			timer.Reset(c.cfg.CacheTTL)
+			c.scheduleRefresh(c.cfg.CacheTTL)
 		}
 	}
 }
 
-// refresh fetches the latest network data from cartographoor.
+// scheduleRefresh records when the next background refresh runs.
+func (c *cartographoorClient) scheduleRefresh(delay time.Duration) {
+	c.mu.Lock()
+	c.nextRefresh = time.Now().Add(delay)
+	c.mu.Unlock()
+}
+
+// refresh fetches the latest network data from cartographoor and records
+// the outcome for Status.
 func (c *cartographoorClient) refresh(ctx context.Context) error {
+	err := c.fetch(ctx)
+
+	c.mu.Lock()
+	c.lastAttempt = time.Now()
+	c.lastError = ""
+
+	if err != nil {
+		c.lastError = err.Error()
+	}
+	c.mu.Unlock()
+
+	return err
+}
+
+// fetch fetches the latest network data from cartographoor.
+func (c *cartographoorClient) fetch(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.URL, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
@@ -268,10 +461,30 @@ func (c *cartographoorClient) refresh(ctx context.Context) error {
 		return fmt.Errorf("decoding response: %w", err)
 	}
 
+	fetchedAt := time.Now()
+
+	c.apply(result, SourceRemote, fetchedAt)
+
+	if c.cfg.SnapshotPath != "" {
+		if err := offline.SaveSnapshot(c.cfg.SnapshotPath, result); err != nil {
+			c.log.WithError(err).Warn("Failed to save network snapshot")
+		}
+	}
+
+	c.saveCache(result, fetchedAt)
+
+	return nil
+}
+
+// apply replaces the cached network data with result, after overrides and
+// hide patterns. source and fetchedAt describe where result came from.
+func (c *cartographoorClient) apply(result discovery.Result, source string, fetchedAt time.Time) {
+	overrides := applyOverrides(result.Networks, c.cfg.Overrides, c.cfg.Hide)
+
 	// Build groups map
 	groups := make(map[string][]string, 16)
 
-	for name, network := range result.Networks {
+	for name, network := range overrides.networks {
 		if matches := groupPattern.FindStringSubmatch(network.Repository); len(matches) == 2 {
 			groupName := matches[1]
 			groups[groupName] = append(groups[groupName], name)
@@ -285,10 +498,43 @@ func (c *cartographoorClient) refresh(ctx context.Context) error {
 
 	// Update cache
 	c.mu.Lock()
-	c.networks = result.Networks
+	changed := changedNetworks(c.networks, overrides.networks)
+	c.networks = overrides.networks
 	c.groups = groups
-	c.lastUpdated = time.Now()
+	c.clusters = overrides.clusters
+	c.overrides = overrides
+	c.source = source
+	c.lastUpdated = fetchedAt
+	listeners := c.listeners
 	c.mu.Unlock()
 
-	return nil
+	if len(changed) == 0 {
+		return
+	}
+
+	for _, fn := range listeners {
+		fn(changed)
+	}
+}
+
+// changedNetworks returns the sorted names of networks that differ between
+// old and updated.
+func changedNetworks(old, updated map[string]discovery.Network) []string {
+	var changed []string
+
+	for name, network := range updated {
+		if prev, ok := old[name]; !ok || !reflect.DeepEqual(prev, network) {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range old {
+		if _, ok := updated[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
 }
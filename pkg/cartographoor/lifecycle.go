@@ -0,0 +1,168 @@
+package cartographoor
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+const (
+	// DefaultSecondsPerSlot is the slot duration of networks that don't
+	// override it.
+	DefaultSecondsPerSlot = 12
+
+	// DefaultSlotsPerEpoch is the number of slots in an epoch of networks
+	// that don't override it.
+	DefaultSlotsPerEpoch = 32
+
+	// maxScheduledEpoch bounds fork epochs treated as scheduled, about two
+	// centuries of 12s slots. Larger epochs, such as FAR_FUTURE_EPOCH, mean
+	// the fork is not scheduled.
+	maxScheduledEpoch = 1 << 24
+)
+
+// forkOrder is the order of consensus forks, used to sort forks that
+// activate in the same epoch.
+var forkOrder = []string{"phase0", "altair", "bellatrix", "capella", "deneb", "electra", "fulu", "gloas"}
+
+// Lifecycle is the timeline of a network: genesis, forks, blob capacity
+// changes and planned shutdown. Times are only set when the genesis time is
+// known.
+type Lifecycle struct {
+	GenesisTime    *time.Time `json:"genesis_time,omitempty"`
+	SecondsPerSlot uint64     `json:"seconds_per_slot"`
+	SlotsPerEpoch  uint64     `json:"slots_per_epoch"`
+	// CurrentSlot, CurrentEpoch and CurrentFork are computed when the
+	// lifecycle is read.
+	CurrentSlot  *uint64 `json:"current_slot,omitempty"`
+	CurrentEpoch *uint64 `json:"current_epoch,omitempty"`
+	CurrentFork  string  `json:"current_fork,omitempty"`
+
+	Forks        []ForkEvent         `json:"forks"`
+	BlobSchedule []BlobScheduleEvent `json:"blob_schedule,omitempty"`
+	// Shutdown is when the network is planned to be shut down, from config.
+	Shutdown *time.Time `json:"shutdown,omitempty"`
+}
+
+// ForkEvent is a consensus fork activation.
+type ForkEvent struct {
+	Name  string     `json:"name"`
+	Epoch uint64     `json:"epoch"`
+	Slot  uint64     `json:"slot"`
+	Time  *time.Time `json:"time,omitempty"`
+	// Scheduled is false for forks without an activation epoch.
+	Scheduled bool `json:"scheduled"`
+	Active    bool `json:"active"`
+}
+
+// BlobScheduleEvent is a change to the blob capacity.
+type BlobScheduleEvent struct {
+	Epoch            uint64     `json:"epoch"`
+	Time             *time.Time `json:"time,omitempty"`
+	MaxBlobsPerBlock uint64     `json:"max_blobs_per_block"`
+}
+
+// NetworkLifecycle builds the lifecycle of network as of now. override
+// supplies the slot timing and shutdown date from config; it may be the
+// zero value.
+func NetworkLifecycle(network discovery.Network, override NetworkOverride, now time.Time) Lifecycle {
+	lc := Lifecycle{
+		SecondsPerSlot: DefaultSecondsPerSlot,
+		SlotsPerEpoch:  DefaultSlotsPerEpoch,
+		Forks:          make([]ForkEvent, 0, 8),
+	}
+
+	if override.SecondsPerSlot != 0 {
+		lc.SecondsPerSlot = override.SecondsPerSlot
+	}
+
+	if override.SlotsPerEpoch != 0 {
+		lc.SlotsPerEpoch = override.SlotsPerEpoch
+	}
+
+	if !override.Shutdown.IsZero() {
+		shutdown := override.Shutdown.UTC()
+		lc.Shutdown = &shutdown
+	}
+
+	var genesis time.Time
+
+	if network.GenesisConfig != nil && network.GenesisConfig.GenesisTime != 0 {
+		genesis = time.Unix(int64(network.GenesisConfig.GenesisTime), 0).UTC()
+		lc.GenesisTime = &genesis
+	}
+
+	epochTime := func(epoch uint64) *time.Time {
+		if genesis.IsZero() || epoch > maxScheduledEpoch {
+			return nil
+		}
+
+		t := time.Unix(genesis.Unix()+int64(epoch*lc.SlotsPerEpoch*lc.SecondsPerSlot), 0).UTC()
+
+		return &t
+	}
+
+	var currentEpoch uint64
+
+	if !genesis.IsZero() && !now.Before(genesis) {
+		slot := uint64(now.Sub(genesis)/time.Second) / lc.SecondsPerSlot
+		currentEpoch = slot / lc.SlotsPerEpoch
+		lc.CurrentSlot = &slot
+		lc.CurrentEpoch = &currentEpoch
+	}
+
+	if network.Forks != nil {
+		for name, fork := range network.Forks.Consensus {
+			event := ForkEvent{
+				Name:      name,
+				Epoch:     fork.Epoch,
+				Scheduled: fork.Epoch <= maxScheduledEpoch,
+				Time:      epochTime(fork.Epoch),
+			}
+
+			if event.Scheduled {
+				event.Slot = fork.Epoch * lc.SlotsPerEpoch
+				event.Active = lc.CurrentEpoch != nil && currentEpoch >= fork.Epoch
+			}
+
+			lc.Forks = append(lc.Forks, event)
+		}
+	}
+
+	sort.Slice(lc.Forks, func(i, j int) bool {
+		a, b := lc.Forks[i], lc.Forks[j]
+		if a.Epoch != b.Epoch {
+			return a.Epoch < b.Epoch
+		}
+
+		return forkRank(a.Name) < forkRank(b.Name)
+	})
+
+	for _, fork := range lc.Forks {
+		if fork.Active {
+			lc.CurrentFork = fork.Name
+		}
+	}
+
+	for _, entry := range network.BlobSchedule {
+		lc.BlobSchedule = append(lc.BlobSchedule, BlobScheduleEvent{
+			Epoch:            entry.Epoch,
+			Time:             epochTime(entry.Epoch),
+			MaxBlobsPerBlock: entry.MaxBlobsPerBlock,
+		})
+	}
+
+	return lc
+}
+
+// forkRank returns the position of a fork in forkOrder, placing unknown
+// forks last.
+func forkRank(name string) int {
+	if idx := slices.Index(forkOrder, name); idx != -1 {
+		return idx
+	}
+
+	return len(forkOrder)
+}
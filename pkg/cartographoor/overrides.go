@@ -0,0 +1,212 @@
+package cartographoor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// NetworkOverride adds a network that cartographoor does not know about, such
+// as an internal devnet, or replaces fields of one it does. Zero fields leave
+// the cartographoor value unchanged.
+type NetworkOverride struct {
+	// Name is the network name. A name cartographoor does not list adds a
+	// network, which defaults to active.
+	Name        string `yaml:"name"`
+	Status      string `yaml:"status,omitempty"`
+	ChainID     uint64 `yaml:"chain_id,omitempty"`
+	Repository  string `yaml:"repository,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// ServiceURLs sets service URLs by their cartographoor key, e.g.
+	// jsonRpc, beaconRpc or dora.
+	ServiceURLs map[string]string `yaml:"service_urls,omitempty"`
+
+	// Clusters replaces the xatu clusters derived from the repository.
+	Clusters []string `yaml:"clusters,omitempty"`
+
+	// GenesisTime is the network's genesis as a Unix timestamp.
+	GenesisTime uint64 `yaml:"genesis_time,omitempty"`
+	// Forks sets consensus fork activation epochs by fork name.
+	Forks map[string]uint64 `yaml:"forks,omitempty"`
+	// SecondsPerSlot is the slot duration. Defaults to 12.
+	SecondsPerSlot uint64 `yaml:"seconds_per_slot,omitempty"`
+	// SlotsPerEpoch is the number of slots per epoch. Defaults to 32.
+	SlotsPerEpoch uint64 `yaml:"slots_per_epoch,omitempty"`
+	// Shutdown is when the network is planned to be shut down.
+	Shutdown time.Time `yaml:"shutdown,omitempty"`
+}
+
+// ValidateOverrides checks that overrides are named uniquely, only set known
+// service URLs and that hide patterns are valid.
+func ValidateOverrides(overrides []NetworkOverride, hide []string) error {
+	seen := make(map[string]struct{}, len(overrides))
+
+	for i, o := range overrides {
+		if o.Name == "" {
+			return fmt.Errorf("overrides[%d].name is required", i)
+		}
+
+		if _, dup := seen[o.Name]; dup {
+			return fmt.Errorf("overrides[%d].name %q is used more than once", i, o.Name)
+		}
+
+		seen[o.Name] = struct{}{}
+
+		if _, err := o.serviceURLs(nil); err != nil {
+			return fmt.Errorf("override %q: %w", o.Name, err)
+		}
+	}
+
+	for _, pattern := range hide {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("hide: invalid pattern %q", pattern)
+		}
+	}
+
+	return nil
+}
+
+// apply returns network with the override's fields set.
+func (o NetworkOverride) apply(network discovery.Network) discovery.Network {
+	network.Name = o.Name
+
+	if o.Status != "" {
+		network.Status = o.Status
+	}
+
+	if o.ChainID != 0 {
+		network.ChainID = o.ChainID
+	}
+
+	if o.Repository != "" {
+		network.Repository = o.Repository
+	}
+
+	if o.Description != "" {
+		network.Description = o.Description
+	}
+
+	if o.GenesisTime != 0 {
+		genesis := discovery.GenesisConfig{}
+		if network.GenesisConfig != nil {
+			genesis = *network.GenesisConfig
+		}
+
+		genesis.GenesisTime = o.GenesisTime
+		network.GenesisConfig = &genesis
+	}
+
+	if len(o.Forks) > 0 {
+		forks := make(map[string]discovery.ForkConfig, len(o.Forks))
+		if network.Forks != nil {
+			maps.Copy(forks, network.Forks.Consensus)
+		}
+
+		for name, epoch := range o.Forks {
+			fork := forks[name]
+			fork.Epoch = epoch
+			forks[name] = fork
+		}
+
+		network.Forks = &discovery.ForksConfig{Consensus: forks}
+	}
+
+	// Validated at config load, so errors cannot happen here.
+	if urls, err := o.serviceURLs(network.ServiceURLs); err == nil && urls != nil {
+		network.ServiceURLs = urls
+	}
+
+	return network
+}
+
+// serviceURLs merges the override's service URLs into base. It returns base
+// unchanged when the override sets none.
+func (o NetworkOverride) serviceURLs(base *discovery.ServiceURLs) (*discovery.ServiceURLs, error) {
+	if len(o.ServiceURLs) == 0 {
+		return base, nil
+	}
+
+	data, err := json.Marshal(o.ServiceURLs)
+	if err != nil {
+		return nil, fmt.Errorf("encoding service_urls: %w", err)
+	}
+
+	urls := &discovery.ServiceURLs{}
+	if base != nil {
+		*urls = *base
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(urls); err != nil {
+		return nil, fmt.Errorf("service_urls: %w", err)
+	}
+
+	return urls, nil
+}
+
+// overrideResult is the network list after overrides and hide patterns.
+type overrideResult struct {
+	networks   map[string]discovery.Network
+	clusters   map[string][]string
+	byName     map[string]NetworkOverride
+	added      []string
+	overridden []string
+	hidden     []string
+}
+
+// applyOverrides applies overrides to the upstream networks, then removes
+// networks matching a hide pattern.
+func applyOverrides(upstream map[string]discovery.Network, overrides []NetworkOverride, hide []string) overrideResult {
+	result := overrideResult{
+		networks: make(map[string]discovery.Network, len(upstream)+len(overrides)),
+		clusters: make(map[string][]string, len(overrides)),
+		byName:   make(map[string]NetworkOverride, len(overrides)),
+	}
+
+	for name, network := range upstream {
+		result.networks[name] = network
+	}
+
+	for _, o := range overrides {
+		network, ok := result.networks[o.Name]
+		if ok {
+			result.overridden = append(result.overridden, o.Name)
+		} else {
+			network = discovery.Network{Status: "active"}
+			result.added = append(result.added, o.Name)
+		}
+
+		result.networks[o.Name] = o.apply(network)
+		result.byName[o.Name] = o
+
+		if len(o.Clusters) > 0 {
+			result.clusters[o.Name] = o.Clusters
+		}
+	}
+
+	for name := range result.networks {
+		for _, pattern := range hide {
+			if ok, _ := path.Match(pattern, name); ok {
+				delete(result.networks, name)
+				result.hidden = append(result.hidden, name)
+
+				break
+			}
+		}
+	}
+
+	sort.Strings(result.added)
+	sort.Strings(result.overridden)
+	sort.Strings(result.hidden)
+
+	return result
+}
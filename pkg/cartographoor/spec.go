@@ -0,0 +1,135 @@
+package cartographoor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"gopkg.in/yaml.v3"
+)
+
+// maxConsensusConfigSize bounds the consensus config.yaml that is read.
+const maxConsensusConfigSize = 1 << 20
+
+// presetSlotsPerEpoch is SLOTS_PER_EPOCH of each consensus preset.
+var presetSlotsPerEpoch = map[string]uint64{
+	"mainnet": 32,
+	"minimal": 8,
+}
+
+// ChainSpec is the slot timing of a network from its consensus config.
+// Zero fields were not found.
+type ChainSpec struct {
+	SecondsPerSlot uint64
+	SlotsPerEpoch  uint64
+	// GenesisTime is the genesis as a Unix timestamp. Parsed from a config
+	// it is MIN_GENESIS_TIME plus GENESIS_DELAY, which matches the real
+	// genesis of networks launched at their minimum genesis time.
+	GenesisTime uint64
+}
+
+// SlotAt returns the slot at t, or false if the genesis time is unknown or
+// after t.
+func (s ChainSpec) SlotAt(t time.Time) (uint64, bool) {
+	if s.GenesisTime == 0 || s.SecondsPerSlot == 0 || t.Unix() < int64(s.GenesisTime) {
+		return 0, false
+	}
+
+	return (uint64(t.Unix()) - s.GenesisTime) / s.SecondsPerSlot, true
+}
+
+// ConsensusConfigURL returns the URL of a network's consensus config.yaml, or
+// "" if cartographoor does not list one.
+func ConsensusConfigURL(network discovery.Network) string {
+	if network.GenesisConfig == nil {
+		return ""
+	}
+
+	for _, file := range network.GenesisConfig.ConsensusLayer {
+		if path.Base(file.Path) == "config.yaml" && file.URL != "" {
+			return file.URL
+		}
+	}
+
+	return ""
+}
+
+// FetchChainSpec downloads and parses a consensus config.yaml.
+func FetchChainSpec(ctx context.Context, client *http.Client, configURL string) (ChainSpec, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, configURL, nil)
+	if err != nil {
+		return ChainSpec{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ChainSpec{}, fmt.Errorf("fetching consensus config: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return ChainSpec{}, fmt.Errorf("fetching consensus config: unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConsensusConfigSize))
+	if err != nil {
+		return ChainSpec{}, fmt.Errorf("reading consensus config: %w", err)
+	}
+
+	return ParseChainSpec(data)
+}
+
+// ParseChainSpec extracts slot timing from a consensus config.yaml. Slots
+// per epoch come from SLOTS_PER_EPOCH or else PRESET_BASE, and the slot
+// duration from SLOT_DURATION_MS or else SECONDS_PER_SLOT.
+func ParseChainSpec(data []byte) (ChainSpec, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return ChainSpec{}, fmt.Errorf("decoding consensus config: %w", err)
+	}
+
+	var spec ChainSpec
+
+	if preset, ok := values["PRESET_BASE"].(string); ok {
+		spec.SlotsPerEpoch = presetSlotsPerEpoch[preset]
+	}
+
+	if n := specUint(values["SLOTS_PER_EPOCH"]); n != 0 {
+		spec.SlotsPerEpoch = n
+	}
+
+	spec.SecondsPerSlot = specUint(values["SECONDS_PER_SLOT"])
+
+	if ms := specUint(values["SLOT_DURATION_MS"]); ms >= 1000 {
+		spec.SecondsPerSlot = ms / 1000
+	}
+
+	if minGenesis := specUint(values["MIN_GENESIS_TIME"]); minGenesis != 0 {
+		spec.GenesisTime = minGenesis + specUint(values["GENESIS_DELAY"])
+	}
+
+	return spec, nil
+}
+
+// specUint converts a config value, which may be quoted, to uint64.
+func specUint(value any) uint64 {
+	switch v := value.(type) {
+	case int:
+		if v > 0 {
+			return uint64(v)
+		}
+	case uint64:
+		return v
+	case string:
+		n, _ := strconv.ParseUint(v, 10, 64)
+
+		return n
+	}
+
+	return 0
+}
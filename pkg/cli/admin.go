@@ -0,0 +1,391 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/serverapi"
+)
+
+var adminToken string
+
+var adminCmd = &cobra.Command{
+	GroupID: groupSetup,
+	Use:     "admin",
+	Short:   "Administer a running server",
+	Long: `Administer a running panda server. Requires server.admin_token to be set
+in the server config; pass the same token with --token or $PANDA_ADMIN_TOKEN.
+
+Examples:
+  panda admin modules
+  panda admin disable dora
+  panda admin enable dora
+  panda admin cache
+  panda admin cache add polars==1.9.0 scikit-learn
+  panda admin images
+  panda admin images --pull
+  panda admin audit --user alice --since 1h --status 4xx
+  panda admin run-schedule nightly-block-gaps`,
+}
+
+var adminModulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "List modules and whether they are disabled",
+	Args:  cobra.NoArgs,
+	RunE:  runAdminModules,
+}
+
+var adminDisableCmd = &cobra.Command{
+	Use:   "disable <module>",
+	Short: "Disable a module at runtime",
+	Long: `Stop a module and hide its operations, datasources, resources and docs
+until it is re-enabled. Useful for cutting off an upstream temporarily.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runAdminSetModule(args[0], true)
+	},
+}
+
+var adminEnableCmd = &cobra.Command{
+	Use:   "enable <module>",
+	Short: "Re-enable a module disabled at runtime",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runAdminSetModule(args[0], false)
+	},
+}
+
+var adminCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Show the shared sandbox package cache",
+	Long: `Show the packages in the shared sandbox package cache. The server
+re-verifies every file's checksum first and drops any that fail.
+Requires sandbox.package_cache.enabled in the server config.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminCacheStatus,
+}
+
+var adminCacheAddCmd = &cobra.Command{
+	Use:   "add <package>...",
+	Short: "Build wheels for packages into the shared package cache",
+	Long: `Build wheels for the given packages and their dependencies with the
+sandbox image and add them to the shared package cache. Sandboxes then
+install them with pip without downloading. Accepts pip requirement
+specifiers such as "polars==1.9.0".`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAdminCacheAdd,
+}
+
+var adminImagesPull bool
+
+var adminImagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Show the sandbox images executions use",
+	Long: `Show each sandbox image the server runs executions with: its local
+image ID and registry digest, which execution profiles use it, whether the
+reference is pinned by digest, and when the server last pulled it. --pull
+pulls every image first and verifies pinned digests.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminImages,
+}
+
+var (
+	adminAuditUser       string
+	adminAuditDatasource string
+	adminAuditSince      string
+	adminAuditUntil      string
+	adminAuditStatus     string
+	adminAuditLimit      int
+)
+
+var adminAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Search recent proxy audit entries",
+	Long: `Search the proxy's most recent audit entries, newest first. The proxy
+keeps audit.recent_size entries in memory; use the audit sinks for older
+history. --since and --until accept RFC 3339 times or a duration before
+now such as 1h. --status accepts a code such as 403 or a class such as 4xx.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminModulesCmd)
+	adminCmd.AddCommand(adminDisableCmd)
+	adminCmd.AddCommand(adminEnableCmd)
+	adminCmd.AddCommand(adminCacheCmd)
+	adminCacheCmd.AddCommand(adminCacheAddCmd)
+	adminCmd.AddCommand(adminImagesCmd)
+	adminCmd.AddCommand(adminAuditCmd)
+
+	adminImagesCmd.Flags().BoolVar(&adminImagesPull, "pull", false, "pull every image now before reporting")
+
+	adminAuditCmd.Flags().StringVar(&adminAuditUser, "user", "", "filter by username or subject")
+	adminAuditCmd.Flags().StringVar(&adminAuditDatasource, "datasource", "", "filter by datasource name or type")
+	adminAuditCmd.Flags().StringVar(&adminAuditSince, "since", "", "only entries after this time or duration ago")
+	adminAuditCmd.Flags().StringVar(&adminAuditUntil, "until", "", "only entries before this time or duration ago")
+	adminAuditCmd.Flags().StringVar(&adminAuditStatus, "status", "", "filter by status code or class (e.g. 403, 5xx)")
+	adminAuditCmd.Flags().IntVar(&adminAuditLimit, "limit", 0, "maximum entries to return (default 100, max 1000)")
+
+	adminCmd.PersistentFlags().StringVar(&adminToken, "token", "", "admin token (defaults to $PANDA_ADMIN_TOKEN)")
+}
+
+func resolveAdminToken() (string, error) {
+	if adminToken != "" {
+		return adminToken, nil
+	}
+
+	if token := os.Getenv("PANDA_ADMIN_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	return "", withExitCode(ExitUsage, errors.New("admin token is required: pass --token or set PANDA_ADMIN_TOKEN"))
+}
+
+func runAdminModules(_ *cobra.Command, _ []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	response, err := listAdminModules(context.Background(), token)
+	if err != nil {
+		return fmt.Errorf("listing modules: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	for _, m := range response.Modules {
+		state := "not initialized"
+
+		switch {
+		case m.Disabled:
+			state = "disabled"
+		case m.Initialized:
+			state = "enabled"
+		}
+
+		fmt.Printf("  %-12s  %s\n", m.Name, state)
+	}
+
+	return nil
+}
+
+func runAdminSetModule(name string, disabled bool) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	status, err := setModuleDisabled(context.Background(), token, name, disabled)
+	if err != nil {
+		return fmt.Errorf("updating module %q: %w", name, err)
+	}
+
+	if isJSON() {
+		return printJSON(status)
+	}
+
+	if status.Disabled {
+		fmt.Printf("Module %s disabled.\n", status.Name)
+	} else {
+		fmt.Printf("Module %s enabled.\n", status.Name)
+	}
+
+	return nil
+}
+
+func runAdminCacheStatus(_ *cobra.Command, _ []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	status, err := packageCacheStatus(context.Background(), token)
+	if err != nil {
+		return fmt.Errorf("reading package cache: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(status)
+	}
+
+	printPackageCacheStatus(status)
+
+	return nil
+}
+
+func runAdminCacheAdd(_ *cobra.Command, args []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Building wheels for %d package(s), this can take a few minutes...\n", len(args))
+
+	response, err := addCachedPackages(context.Background(), token, args)
+	if err != nil {
+		return fmt.Errorf("adding packages: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	fmt.Printf("Added %d file(s):\n", len(response.Added))
+
+	for _, entry := range response.Added {
+		fmt.Printf("  %s\n", entry.Name)
+	}
+
+	fmt.Println()
+	printPackageCacheStatus(&response.Status)
+
+	return nil
+}
+
+func runAdminImages(_ *cobra.Command, _ []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	if adminImagesPull {
+		fmt.Fprintln(os.Stderr, "Pulling sandbox images, this can take a few minutes...")
+	}
+
+	response, err := sandboxImages(context.Background(), token, adminImagesPull)
+	if err != nil {
+		return fmt.Errorf("reading sandbox images: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	for _, img := range response.Images {
+		profiles := make([]string, 0, len(img.Profiles))
+		for _, name := range img.Profiles {
+			if name == "" {
+				name = "(default)"
+			}
+
+			profiles = append(profiles, name)
+		}
+
+		fmt.Printf("%s\n", img.Ref)
+		fmt.Printf("  profiles:  %s\n", strings.Join(profiles, ", "))
+
+		if img.Error != "" {
+			fmt.Printf("  error:     %s\n", img.Error)
+		}
+
+		if img.ID != "" {
+			fmt.Printf("  id:        %s\n", img.ID)
+		}
+
+		if img.Digest != "" {
+			fmt.Printf("  digest:    %s\n", img.Digest)
+		}
+
+		switch {
+		case img.Pinned:
+			fmt.Println("  reference: pinned by digest")
+		case img.Floating:
+			fmt.Println("  reference: floating tag")
+		default:
+			fmt.Println("  reference: tag")
+		}
+
+		if img.Created != nil {
+			fmt.Printf("  created:   %s\n", img.Created.Local().Format(time.DateTime))
+		}
+
+		if img.PulledAt != nil {
+			fmt.Printf("  pulled:    %s\n", img.PulledAt.Local().Format(time.DateTime))
+		}
+	}
+
+	return nil
+}
+
+func runAdminAudit(_ *cobra.Command, _ []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+
+	for key, value := range map[string]string{
+		"user":       adminAuditUser,
+		"datasource": adminAuditDatasource,
+		"since":      adminAuditSince,
+		"until":      adminAuditUntil,
+		"status":     adminAuditStatus,
+	} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	if adminAuditLimit > 0 {
+		params.Set("limit", strconv.Itoa(adminAuditLimit))
+	}
+
+	response, err := searchAudit(context.Background(), token, params)
+	if err != nil {
+		return fmt.Errorf("searching audit entries: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	for _, entry := range response.Entries {
+		user := entry.Username
+		if user == "" {
+			user = entry.Subject
+		}
+
+		datasource := entry.DatasourceType
+		if entry.DatasourceName != "" {
+			datasource += "/" + entry.DatasourceName
+		}
+
+		fmt.Printf("  %s  %-16s  %3d  %-24s  %6dms  %s %s\n",
+			entry.Time.Local().Format(time.DateTime), user, entry.Status, datasource,
+			entry.DurationMS, entry.Method, entry.Path)
+	}
+
+	fmt.Printf("%d matching entries (%d retained)\n", len(response.Entries), response.Retained)
+
+	return nil
+}
+
+func printPackageCacheStatus(status *serverapi.PackageCacheStatusResponse) {
+	for _, entry := range status.Entries {
+		fmt.Printf("  %-60s  %8.1f MB  sha256:%s\n", entry.Name, megabytes(entry.Size), entry.SHA256[:12])
+	}
+
+	fmt.Printf("%d file(s), %.1f / %.1f MB used\n", len(status.Entries), megabytes(status.TotalBytes), megabytes(status.MaxBytes))
+
+	for _, name := range status.Removed {
+		fmt.Printf("Removed %s: failed integrity check\n", name)
+	}
+}
+
+func megabytes(size int64) float64 {
+	return float64(size) / (1024 * 1024)
+}
@@ -47,8 +47,10 @@ var authLoginCmd = &cobra.Command{
 
 var authLogoutCmd = &cobra.Command{
 	Use:   "logout",
-	Short: "Remove locally stored proxy credentials",
-	RunE:  runAuthLogout,
+	Short: "Revoke and remove locally stored proxy credentials",
+	Long: `Revoke the stored refresh token at the issuer, then delete the local
+credentials. The credentials are deleted even if revocation fails.`,
+	RunE: runAuthLogout,
 }
 
 var authStatusCmd = &cobra.Command{
@@ -57,14 +59,26 @@ var authStatusCmd = &cobra.Command{
 	RunE:  runAuthStatus,
 }
 
+var authProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List credential profiles",
+	Long: `List credential profiles with stored proxy credentials.
+
+Select a profile with --profile or $PANDA_PROFILE so logins against
+different deployments (e.g. staging and prod) are kept separate.`,
+	RunE: runAuthProfiles,
+}
+
 func init() {
 	rootCmd.AddCommand(authCmd)
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authLogoutCmd)
 	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authProfilesCmd)
 
-	authLoginCmd.Flags().BoolVar(&noBrowser, "no-browser", false,
-		"manual auth flow for SSH/headless environments (auto-detected over SSH)")
+	authLoginCmd.Flags().BoolVar(&noBrowser, "device", false,
+		"log in with a device code, for SSH sessions and containers (auto-detected)")
+	authLoginCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "alias for --device")
 
 	for _, cmd := range []*cobra.Command{authLoginCmd, authLogoutCmd, authStatusCmd} {
 		cmd.Flags().StringVar(&authIssuerURL, "issuer", "", "proxy auth issuer URL (defaults to the configured server's proxy auth issuer)")
@@ -74,22 +88,36 @@ func init() {
 }
 
 func runAuthLogin(_ *cobra.Command, _ []string) error {
+	return runWithJSONResult(authLogin)
+}
+
+// authLogin runs the proxy login flow for the active profile and stores the
+// resulting tokens.
+func authLogin() (*AuthLoginOutput, error) {
 	target, err := resolveAuthTarget(context.Background())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	output := &AuthLoginOutput{Profile: credentialProfile()}
+
 	if !target.enabled {
 		fmt.Println("Proxy authentication is not enabled for the configured server.")
-		return nil
+		return output, nil
 	}
 
 	headless := isHeadlessAuth()
-	if headless && !noBrowser {
-		fmt.Println("SSH session detected, using device authorization flow.")
+	if env := headlessEnvironment(); env != "" && !noBrowser {
+		fmt.Printf("%s detected, using device authorization flow.\n", env)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Device codes stay valid for longer than a browser round trip takes.
+	timeout := 5 * time.Minute
+	if headless {
+		timeout = 15 * time.Minute
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	clientCfg := authclient.Config{
@@ -107,7 +135,7 @@ func runAuthLogin(_ *cobra.Command, _ []string) error {
 
 	tokens, err := client.Login(ctx)
 	if err != nil {
-		return fmt.Errorf("login failed: %w", err)
+		return nil, withExitCode(ExitAuth, fmt.Errorf("login failed: %w", err))
 	}
 
 	store := authstore.New(log, authstore.Config{
@@ -115,10 +143,12 @@ func runAuthLogin(_ *cobra.Command, _ []string) error {
 		IssuerURL:  target.issuerURL,
 		ClientID:   target.clientID,
 		Resource:   target.resource,
+		Profile:    output.Profile,
+		Backend:    credentialStore(),
 	})
 
 	if err := store.Save(tokens); err != nil {
-		return fmt.Errorf("saving tokens: %w", err)
+		return nil, fmt.Errorf("saving tokens: %w", err)
 	}
 
 	fmt.Printf("Authenticated to %s\n", target.issuerURL)
@@ -128,7 +158,12 @@ func runAuthLogin(_ *cobra.Command, _ []string) error {
 	// Restart the server if it's running so it picks up the new credentials.
 	restartServerIfRunning()
 
-	return nil
+	output.Enabled = true
+	output.Issuer = target.issuerURL
+	output.CredentialsPath = store.Path()
+	output.ExpiresAt = &tokens.ExpiresAt
+
+	return output, nil
 }
 
 func runAuthLogout(_ *cobra.Command, _ []string) error {
@@ -137,31 +172,106 @@ func runAuthLogout(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	client := authclient.New(log, authclient.Config{
+		IssuerURL: target.issuerURL,
+		ClientID:  target.clientID,
+		Resource:  target.resource,
+	})
+
 	store := authstore.New(log, authstore.Config{
 		IssuerURL: target.issuerURL,
 		ClientID:  target.clientID,
 		Resource:  target.resource,
+		Profile:   credentialProfile(),
+		Backend:   credentialStore(),
 	})
 
+	output := AuthLogoutOutput{
+		Profile:         credentialProfile(),
+		CredentialsPath: store.Path(),
+	}
+
+	// Revoke before wiping so a copied credentials file stops working too.
+	if tokens, err := store.Load(); err != nil {
+		log.WithError(err).Warn("Could not read stored credentials; skipping revocation")
+	} else if tokens != nil && tokens.RefreshToken != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := client.Revoke(ctx, tokens.RefreshToken, "refresh_token"); err != nil {
+			log.WithError(err).Warn("Could not revoke refresh token at the issuer")
+		} else {
+			output.Revoked = true
+		}
+	}
+
 	if err := store.Clear(); err != nil {
 		return fmt.Errorf("clearing tokens: %w", err)
 	}
 
+	if isJSON() {
+		return printJSON(output)
+	}
+
+	if output.Revoked {
+		fmt.Println("Revoked refresh token")
+	}
+
 	fmt.Printf("Removed credentials at: %s\n", store.Path())
 	return nil
 }
 
 func runAuthStatus(_ *cobra.Command, _ []string) error {
-	target, err := resolveAuthTarget(context.Background())
+	status, err := authStatus()
 	if err != nil {
 		return err
 	}
 
-	if !target.enabled {
+	if isJSON() {
+		return printJSON(status)
+	}
+
+	if status.Status == authStatusDisabled {
 		fmt.Println("Proxy authentication is not enabled for the configured server.")
 		return nil
 	}
 
+	fmt.Printf("Profile: %s\n", status.Profile)
+	fmt.Printf("Issuer: %s\n", status.Issuer)
+	fmt.Printf("Client ID: %s\n", status.ClientID)
+	fmt.Printf("Resource: %s\n", status.Resource)
+	fmt.Printf("Credentials: %s\n", status.CredentialsPath)
+
+	switch status.Status {
+	case authStatusAuthenticated:
+		fmt.Printf("Status: Authenticated (expires in %s)\n", time.Until(*status.ExpiresAt).Round(time.Second))
+		fmt.Printf("Expires at: %s\n", status.ExpiresAt.Format(time.RFC3339))
+	case authStatusExpired:
+		fmt.Printf("Status: Expired (expired at %s)\n", status.ExpiresAt.Format(time.RFC3339))
+	default:
+		fmt.Println("Status: Not authenticated")
+	}
+
+	return nil
+}
+
+// authStatus resolves the proxy auth target and reports the state of the
+// stored credentials for the active profile.
+func authStatus() (*AuthStatusOutput, error) {
+	target, err := resolveAuthTarget(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &AuthStatusOutput{
+		Status:  authStatusDisabled,
+		Profile: credentialProfile(),
+	}
+
+	if !target.enabled {
+		return status, nil
+	}
+
 	client := authclient.New(log, authclient.Config{
 		IssuerURL: target.issuerURL,
 		ClientID:  target.clientID,
@@ -173,33 +283,93 @@ func runAuthStatus(_ *cobra.Command, _ []string) error {
 		IssuerURL:  target.issuerURL,
 		ClientID:   target.clientID,
 		Resource:   target.resource,
+		Profile:    status.Profile,
+		Backend:    credentialStore(),
 	})
 
 	tokens, err := store.Load()
 	if err != nil {
-		return fmt.Errorf("loading tokens: %w", err)
+		return nil, fmt.Errorf("loading tokens: %w", err)
 	}
 
-	fmt.Printf("Issuer: %s\n", target.issuerURL)
-	fmt.Printf("Client ID: %s\n", target.clientID)
-	fmt.Printf("Resource: %s\n", target.resource)
-	fmt.Printf("Credentials: %s\n", store.Path())
+	status.Issuer = target.issuerURL
+	status.ClientID = target.clientID
+	status.Resource = target.resource
+	status.CredentialsPath = store.Path()
+
+	switch {
+	case tokens == nil:
+		status.Status = authStatusNotAuthenticated
+	case tokens.ExpiresAt.After(time.Now()):
+		status.Status = authStatusAuthenticated
+		status.ExpiresAt = &tokens.ExpiresAt
+	default:
+		status.Status = authStatusExpired
+		status.ExpiresAt = &tokens.ExpiresAt
+	}
 
-	if tokens == nil {
-		fmt.Println("Status: Not authenticated")
-		return nil
+	return status, nil
+}
+
+func runAuthProfiles(_ *cobra.Command, _ []string) error {
+	profiles, err := authstore.ListProfiles()
+	if err != nil {
+		return err
 	}
 
-	if tokens.ExpiresAt.After(time.Now()) {
-		fmt.Printf("Status: Authenticated (expires in %s)\n", time.Until(tokens.ExpiresAt).Round(time.Second))
-		fmt.Printf("Expires at: %s\n", tokens.ExpiresAt.Format(time.RFC3339))
-		return nil
+	active := credentialProfile()
+
+	if isJSON() {
+		return printJSON(AuthProfilesOutput{
+			Active:   active,
+			Profiles: profiles,
+		})
+	}
+
+	for _, profile := range profiles {
+		marker := "  "
+		if profile == active {
+			marker = "* "
+		}
+
+		fmt.Printf("%s%s\n", marker, profile)
 	}
 
-	fmt.Printf("Status: Expired (expired at %s)\n", tokens.ExpiresAt.Format(time.RFC3339))
 	return nil
 }
 
+// credentialProfile returns the active credential profile. Resolution order is
+// --profile, $PANDA_PROFILE, proxy.auth.profile from the config file, then
+// "default". The flag and $PANDA_PROFILE are validated before any command
+// runs and the config value when the config loads, so resolution cannot fail
+// here.
+func credentialProfile() string {
+	var configured string
+
+	if strings.TrimSpace(profileName) == "" && strings.TrimSpace(os.Getenv(authstore.ProfileEnvVar)) == "" {
+		if cfg, err := config.LoadClient(cfgFile); err == nil && cfg.Proxy.Auth != nil {
+			configured = cfg.Proxy.Auth.Profile
+		}
+	}
+
+	profile, err := authstore.ResolveProfile(profileName, configured)
+	if err != nil {
+		return authstore.DefaultProfile
+	}
+
+	return profile
+}
+
+// credentialStore returns proxy.auth.credential_store from the config file, or
+// "" for the default backend.
+func credentialStore() string {
+	if cfg, err := config.LoadClient(cfgFile); err == nil && cfg.Proxy.Auth != nil {
+		return cfg.Proxy.Auth.CredentialStore
+	}
+
+	return ""
+}
+
 func resolveAuthTarget(ctx context.Context) (*authTarget, error) {
 	// 1. Explicit CLI flags take priority.
 	if strings.TrimSpace(authIssuerURL) != "" || strings.TrimSpace(authClientID) != "" || strings.TrimSpace(authResource) != "" {
@@ -220,7 +390,7 @@ func resolveAuthTarget(ctx context.Context) (*authTarget, error) {
 		}
 
 		if target.issuerURL == "" {
-			return nil, fmt.Errorf("issuer is required when overriding auth settings")
+			return nil, withExitCode(ExitUsage, fmt.Errorf("issuer is required when overriding auth settings"))
 		}
 
 		return target, nil
@@ -234,11 +404,11 @@ func resolveAuthTarget(ctx context.Context) (*authTarget, error) {
 	// 3. Fall back to querying the running server's proxy auth metadata endpoint.
 	metadata, err := proxyAuthMetadata(ctx)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, withExitCode(ExitConfig, fmt.Errorf(
 			"could not resolve proxy auth settings: no proxy.auth in config and server unreachable (%w). "+
 				"Run 'panda init' to create a config with proxy auth settings, or start the server first",
 			err,
-		)
+		))
 	}
 
 	target := &authTarget{
@@ -315,10 +485,23 @@ func resolveAuthTargetFromConfig() *authTarget {
 }
 
 // isHeadlessAuth returns true when the auth flow should skip the local
-// callback server — either because --no-browser was passed or because
-// an SSH session was detected.
+// callback server — either because --device was passed or because an SSH
+// session or container was detected.
 func isHeadlessAuth() bool {
-	return noBrowser || isSSHSession()
+	return noBrowser || headlessEnvironment() != ""
+}
+
+// headlessEnvironment names the environment without a local browser the
+// process runs in, or returns "" when none is detected.
+func headlessEnvironment() string {
+	switch {
+	case isSSHSession():
+		return "SSH session"
+	case isContainer():
+		return "Container"
+	default:
+		return ""
+	}
 }
 
 // isSSHSession returns true when the process is running inside an SSH session.
@@ -331,3 +514,22 @@ func isSSHSession() bool {
 
 	return false
 }
+
+// containerMarkers are files container runtimes create inside containers.
+var containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+
+// isContainer returns true when the process is running inside a Docker,
+// Podman or Kubernetes container.
+func isContainer() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+
+	for _, marker := range containerMarkers {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
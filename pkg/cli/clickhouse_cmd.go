@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	clickhousemodule "github.com/ethpandaops/panda/modules/clickhouse"
 )
 
 var clickhouseCmd = &cobra.Command{
@@ -18,7 +21,8 @@ var clickhouseCmd = &cobra.Command{
 Examples:
   panda clickhouse list-datasources
   panda clickhouse query xatu "SELECT count() FROM beacon_api_eth_v1_events_block WHERE slot_start_date_time > now() - INTERVAL 1 HOUR"
-  panda clickhouse query xatu "SELECT * FROM beacon_api_eth_v1_events_block LIMIT 5" --json`,
+  panda clickhouse query xatu "SELECT * FROM beacon_api_eth_v1_events_block LIMIT 5" --json
+  panda clickhouse explain xatu "SELECT count() FROM beacon_api_eth_v1_events_block WHERE slot_start_date_time > now() - INTERVAL 1 DAY"`,
 }
 
 func init() {
@@ -27,9 +31,11 @@ func init() {
 	clickhouseCmd.AddCommand(clickhouseListDatasourcesCmd)
 	clickhouseCmd.AddCommand(clickhouseQueryCmd)
 	clickhouseCmd.AddCommand(clickhouseQueryRawCmd)
+	clickhouseCmd.AddCommand(clickhouseExplainCmd)
 
 	clickhouseQueryCmd.ValidArgsFunction = completeDatasourceNames("clickhouse")
 	clickhouseQueryRawCmd.ValidArgsFunction = completeDatasourceNames("clickhouse")
+	clickhouseExplainCmd.ValidArgsFunction = completeDatasourceNames("clickhouse")
 }
 
 var clickhouseListDatasourcesCmd = &cobra.Command{
@@ -71,6 +77,49 @@ var clickhouseQueryRawCmd = &cobra.Command{
 	},
 }
 
+var clickhouseExplainCmd = &cobra.Command{
+	Use:   "explain <cluster> <sql>",
+	Short: "Estimate what a SQL query would read without running it",
+	Long: `Run EXPLAIN ESTIMATE and EXPLAIN PLAN for a SQL query and show the rows,
+bytes and parts it would read per table, followed by the query plan.
+
+Byte estimates extrapolate each table's average compressed row size, so they
+are an upper bound for queries that read only some columns.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		response, err := runServerOperation("clickhouse.explain", map[string]any{
+			"cluster": args[0],
+			"sql":     args[1],
+		})
+		if err != nil {
+			return err
+		}
+
+		if isJSON() {
+			return printJSON(response.Data)
+		}
+
+		data, err := json.Marshal(response.Data)
+		if err != nil {
+			return fmt.Errorf("encoding query plan: %w", err)
+		}
+
+		var plan clickhousemodule.QueryPlan
+		if err := json.Unmarshal(data, &plan); err != nil {
+			return fmt.Errorf("decoding query plan: %w", err)
+		}
+
+		for _, est := range plan.Estimates {
+			fmt.Printf("%s.%s: %d rows, %.1f MB, %d parts, %d marks\n",
+				est.Database, est.Table, est.Rows, megabytes(int64(est.Bytes)), est.Parts, est.Marks)
+		}
+
+		fmt.Printf("Total: %d rows, %.1f MB\n\n%s\n", plan.TotalRows, megabytes(int64(plan.TotalBytes)), plan.Plan)
+
+		return nil
+	},
+}
+
 func runClickHouseOperation(operationID, cluster, sql string, raw bool) error {
 	ctx := context.Background()
 
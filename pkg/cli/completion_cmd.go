@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	GroupID: groupSetup,
+	Use:     "completion <bash|zsh|fish|powershell>",
+	Short:   "Generate shell completion scripts",
+	Long: `Generate a shell completion script for panda.
+
+Bash:
+  source <(panda completion bash)
+  # persist: panda completion bash > /etc/bash_completion.d/panda
+
+Zsh:
+  panda completion zsh > "${fpath[1]}/_panda"
+
+Fish:
+  panda completion fish > ~/.config/fish/completions/panda.fish
+
+PowerShell:
+  panda completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q (expected bash, zsh, fish or powershell)", args[0])
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/resource"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+var coverageKind string
+
+var coverageCmd = &cobra.Command{
+	GroupID: groupDiscovery,
+	Use:     "coverage",
+	Short:   "Show tables and metrics without query examples",
+	Long: `Show which discovered ClickHouse tables and Prometheus metrics are not
+referenced by any query example. Use this to decide where new examples
+would help agents most.
+
+Examples:
+  panda coverage
+  panda coverage --kind clickhouse_table
+  panda coverage --json`,
+	RunE: runCoverage,
+}
+
+func init() {
+	rootCmd.AddCommand(coverageCmd)
+	coverageCmd.Flags().StringVar(&coverageKind, "kind", "", "Filter by kind (clickhouse_table, prometheus_metric)")
+
+	_ = coverageCmd.RegisterFlagCompletionFunc("kind", cobra.FixedCompletions(
+		[]string{types.CoverageKindClickHouseTable, types.CoverageKindPrometheusMetric}, cobra.ShellCompDirectiveNoFileComp,
+	))
+}
+
+func runCoverage(_ *cobra.Command, _ []string) error {
+	response, err := readResource(context.Background(), "examples://coverage")
+	if err != nil {
+		return fmt.Errorf("reading examples coverage: %w", err)
+	}
+
+	var report resource.ExamplesCoverageResponse
+	if err := json.Unmarshal([]byte(response.Content), &report); err != nil {
+		return fmt.Errorf("decoding examples coverage: %w", err)
+	}
+
+	if coverageKind != "" {
+		report = filterCoverage(report, coverageKind)
+	}
+
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	if len(report.Summary) == 0 {
+		fmt.Println("No discovered tables or metrics.")
+	} else {
+		rows := make([][]string, 0, len(report.Summary))
+		for _, summary := range report.Summary {
+			rows = append(rows, []string{
+				summary.Kind,
+				fmt.Sprintf("%d", summary.Total),
+				fmt.Sprintf("%d", summary.Covered),
+				fmt.Sprintf("%d", summary.Uncovered),
+				fmt.Sprintf("%.1f%%", summary.Percent),
+			})
+		}
+
+		printTable([]string{"KIND", "TOTAL", "COVERED", "UNCOVERED", "COVERAGE"}, rows)
+	}
+
+	if len(report.Uncovered) > 0 {
+		fmt.Println()
+		fmt.Println("Without examples:")
+
+		for _, target := range report.Uncovered {
+			fmt.Printf("  %-18s  %-20s  %s\n", target.Kind, target.Datasource, target.Name)
+		}
+	}
+
+	for name, message := range report.Errors {
+		fmt.Printf("\nwarning: %s: %s\n", name, message)
+	}
+
+	return nil
+}
+
+func filterCoverage(report resource.ExamplesCoverageResponse, kind string) resource.ExamplesCoverageResponse {
+	filtered := resource.ExamplesCoverageResponse{
+		Uncovered: make([]types.CoverageTarget, 0),
+		Covered:   make([]resource.CoveredTarget, 0),
+		Errors:    report.Errors,
+	}
+
+	for _, summary := range report.Summary {
+		if summary.Kind == kind {
+			filtered.Summary = append(filtered.Summary, summary)
+		}
+	}
+
+	for _, target := range report.Uncovered {
+		if target.Kind == kind {
+			filtered.Uncovered = append(filtered.Uncovered, target)
+		}
+	}
+
+	for _, target := range report.Covered {
+		if target.Kind == kind {
+			filtered.Covered = append(filtered.Covered, target)
+		}
+	}
+
+	return filtered
+}
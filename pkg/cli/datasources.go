@@ -14,7 +14,8 @@ var datasourcesCmd = &cobra.Command{
 	Use:     "datasources",
 	Short:   "List available datasources from the server",
 	Long: `List all datasources exposed by the configured server, including
-ClickHouse clusters, Prometheus instances, and Loki instances.
+ClickHouse clusters, Prometheus instances, Loki instances, Grafana instances,
+GitHub repositories and HTTP JSON endpoints.
 
 Examples:
   panda datasources                     # List all datasources
@@ -25,10 +26,10 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(datasourcesCmd)
-	datasourcesCmd.Flags().StringVar(&datasourcesType, "type", "", "Filter by type (clickhouse, prometheus, loki)")
+	datasourcesCmd.Flags().StringVar(&datasourcesType, "type", "", "Filter by type (clickhouse, prometheus, loki, grafana, github, httpjson)")
 
 	_ = datasourcesCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(
-		[]string{"clickhouse", "prometheus", "loki"}, cobra.ShellCompDirectiveNoFileComp,
+		[]string{"clickhouse", "prometheus", "loki", "grafana", "github", "httpjson"}, cobra.ShellCompDirectiveNoFileComp,
 	))
 }
 
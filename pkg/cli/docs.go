@@ -26,7 +26,7 @@ Examples:
   panda docs clickhouse       # Show clickhouse module docs
   panda docs --json           # Output as JSON`,
 	RunE:      runDocs,
-	ValidArgs: []string{"clickhouse", "prometheus", "loki", "dora", "storage", "ethnode"},
+	ValidArgs: []string{"clickhouse", "prometheus", "loki", "grafana", "github", "http_json", "dora", "beacon", "forkmon", "blobscan", "checkpointz", "assertoor", "syncoor", "storage", "ethnode", "self_metrics"},
 }
 
 func init() {
@@ -34,29 +34,29 @@ func init() {
 }
 
 func runDocs(_ *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		doc, err := getModuleAPIDoc(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if isJSON() {
+			return printJSON(map[string]any{args[0]: doc.ModuleDoc})
+		}
+
+		return showModule(args[0], doc.ModuleDoc)
+	}
+
 	allDocs, err := getAllPythonAPIDocs(context.Background())
 	if err != nil {
 		return err
 	}
 
 	if isJSON() {
-		if len(args) > 0 {
-			doc, ok := allDocs[args[0]]
-			if !ok {
-				return fmt.Errorf("module %q not found", args[0])
-			}
-
-			return printJSON(map[string]any{args[0]: doc})
-		}
-
 		return printJSON(allDocs)
 	}
 
-	if len(args) == 0 {
-		return listModules(allDocs)
-	}
-
-	return showModule(allDocs, args[0])
+	return listModules(allDocs)
 }
 
 func listModules(docs map[string]types.ModuleDoc) error {
@@ -79,12 +79,7 @@ func listModules(docs map[string]types.ModuleDoc) error {
 	return nil
 }
 
-func showModule(docs map[string]types.ModuleDoc, name string) error {
-	doc, ok := docs[name]
-	if !ok {
-		return fmt.Errorf("module %q not found", name)
-	}
-
+func showModule(name string, doc types.ModuleDoc) error {
 	fmt.Printf("Module: %s\n%s\n\n", name, doc.Description)
 
 	funcNames := make([]string, 0, len(doc.Functions))
@@ -141,3 +136,18 @@ func getAllPythonAPIDocs(ctx context.Context) (map[string]types.ModuleDoc, error
 
 	return payload.Modules, nil
 }
+
+// getModuleAPIDoc reads the docs for a single module from the server.
+func getModuleAPIDoc(ctx context.Context, name string) (*serverapi.ModuleAPIDocResponse, error) {
+	response, err := readResource(ctx, "python://ethpandaops/"+name+".json")
+	if err != nil {
+		return nil, err
+	}
+
+	var payload serverapi.ModuleAPIDocResponse
+	if err := json.Unmarshal([]byte(response.Content), &payload); err != nil {
+		return nil, fmt.Errorf("decoding module docs: %w", err)
+	}
+
+	return &payload, nil
+}
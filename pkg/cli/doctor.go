@@ -0,0 +1,391 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	dockerclient "github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Doctor check results reported in DoctorCheck.Status.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+var doctorSkipPull bool
+
+var doctorCmd = &cobra.Command{
+	GroupID: groupSetup,
+	Use:     "doctor",
+	Short:   "Diagnose the local panda setup",
+	Long: `Run diagnostics against the local panda setup and print a report.
+
+Checks, in order:
+  1. The config file loads, resolves its secrets and validates
+  2. Docker is reachable and provides the sandbox backend's runtime
+  3. The sandbox image can be pulled
+  4. The credential proxy answers its health check
+  5. The server is running and each datasource probe succeeds
+  6. The semantic search embedding backend is usable
+
+Exits nonzero when any check fails. Warnings do not fail the run.
+
+Use --skip-pull to only check that the sandbox image is present locally.`,
+	Example: `  panda doctor
+  panda doctor --skip-pull
+  panda doctor --json`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorSkipPull, "skip-pull", false, "check the local sandbox image instead of pulling it")
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	var report *DoctorOutput
+
+	if err := runWithJSONResult(func() (*DoctorOutput, error) {
+		report = diagnose(cmd.Context())
+
+		if !isJSON() {
+			printDoctorReport(report, colorEnabled())
+		}
+
+		return report, nil
+	}); err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", report.Failed)
+	}
+
+	return nil
+}
+
+// diagnose runs every doctor check. Checks that depend on an earlier one
+// that failed are reported as warnings rather than run.
+func diagnose(ctx context.Context) *DoctorOutput {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report := &DoctorOutput{Checks: make([]DoctorCheck, 0, 8)}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		report.add("config", doctorFail, err.Error())
+		report.add("sandbox", doctorWarn, "skipped: config did not load")
+		report.add("proxy", doctorWarn, "skipped: config did not load")
+	} else {
+		report.add("config", doctorPass, "loaded "+cfg.Path())
+		report.Checks = append(report.Checks, checkSandbox(ctx, cfg)...)
+		report.Checks = append(report.Checks, checkProxy(ctx, cfg))
+	}
+
+	report.Checks = append(report.Checks, checkServer(ctx)...)
+
+	if cfg != nil {
+		report.Checks = append(report.Checks, checkEmbeddings(cfg))
+	}
+
+	for _, check := range report.Checks {
+		if check.Status == doctorFail {
+			report.Failed++
+		}
+	}
+
+	return report
+}
+
+func (r *DoctorOutput) add(name, status, detail string) {
+	r.Checks = append(r.Checks, DoctorCheck{Name: name, Status: status, Detail: detail})
+}
+
+// checkSandbox checks Docker, the runtime the sandbox backend needs and the
+// sandbox image.
+func checkSandbox(ctx context.Context, cfg *config.Config) []DoctorCheck {
+	cli, err := dockerclient.NewClientWithOpts(
+		dockerclient.FromEnv,
+		dockerclient.WithAPIVersionNegotiation(),
+	)
+	if err != nil {
+		return []DoctorCheck{{Name: "docker", Status: doctorFail, Detail: fmt.Sprintf("creating client: %v", err)}}
+	}
+	defer func() { _ = cli.Close() }()
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := cli.Ping(pingCtx); err != nil {
+		return []DoctorCheck{{Name: "docker", Status: doctorFail, Detail: fmt.Sprintf("docker is not running: %v", err)}}
+	}
+
+	checks := []DoctorCheck{{Name: "docker", Status: doctorPass, Detail: "daemon reachable"}}
+
+	if runtime := sandboxRuntime(cfg.Sandbox); runtime != "" {
+		info, err := cli.Info(pingCtx)
+		_, available := info.Runtimes[runtime]
+
+		switch {
+		case err != nil:
+			checks = append(checks, DoctorCheck{Name: "sandbox runtime", Status: doctorFail, Detail: fmt.Sprintf("getting docker info: %v", err)})
+		case !available:
+			checks = append(checks, DoctorCheck{
+				Name:   "sandbox runtime",
+				Status: doctorFail,
+				Detail: fmt.Sprintf("%s backend needs the %q runtime, which docker does not provide", cfg.Sandbox.Backend, runtime),
+			})
+		default:
+			checks = append(checks, DoctorCheck{Name: "sandbox runtime", Status: doctorPass, Detail: runtime})
+		}
+	}
+
+	image := cfg.Sandbox.Image
+
+	if doctorSkipPull {
+		if _, err := cli.ImageInspect(ctx, image); err != nil {
+			return append(checks, DoctorCheck{Name: "sandbox image", Status: doctorWarn, Detail: image + " is not present locally"})
+		}
+
+		return append(checks, DoctorCheck{Name: "sandbox image", Status: doctorPass, Detail: image + " present"})
+	}
+
+	if err := pullImage(cli, image); err != nil {
+		return append(checks, DoctorCheck{Name: "sandbox image", Status: doctorFail, Detail: err.Error()})
+	}
+
+	return append(checks, DoctorCheck{Name: "sandbox image", Status: doctorPass, Detail: image + " pulled"})
+}
+
+// sandboxRuntime returns the Docker runtime the sandbox backend runs
+// containers with, or "" for the default runtime.
+func sandboxRuntime(cfg config.SandboxConfig) string {
+	switch cfg.Backend {
+	case "gvisor":
+		return "runsc"
+	case "firecracker":
+		return cfg.Firecracker.Runtime
+	default:
+		return ""
+	}
+}
+
+// checkProxy probes the credential proxy's unauthenticated health endpoint.
+func checkProxy(ctx context.Context, cfg *config.Config) DoctorCheck {
+	check := DoctorCheck{Name: "proxy"}
+
+	if cfg.Proxy.Mode == config.ProxyModeEmbedded {
+		return checkEmbeddedProxy(cfg)
+	}
+
+	if cfg.Proxy.URL == "" {
+		check.Status, check.Detail = doctorWarn, "proxy.url is not configured"
+
+		return check
+	}
+
+	transport, err := cfg.Proxy.TLS.Transport()
+	if err != nil {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("proxy.tls: %v", err)
+
+		return check
+	}
+
+	if err := probeHealth(ctx, &http.Client{Timeout: 5 * time.Second, Transport: transport}, cfg.Proxy.URL); err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+
+		return check
+	}
+
+	check.Status, check.Detail = doctorPass, cfg.Proxy.URL+" healthy"
+
+	return check
+}
+
+// checkEmbeddedProxy validates the config an embedded proxy would load.
+func checkEmbeddedProxy(cfg *config.Config) DoctorCheck {
+	check := DoctorCheck{Name: "proxy"}
+
+	path, err := cfg.EmbeddedProxyConfigPath()
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+
+		return check
+	}
+
+	if _, err := proxy.LoadServerConfig(path); err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+
+		return check
+	}
+
+	check.Status, check.Detail = doctorPass, "embedded, config "+path
+
+	return check
+}
+
+// probeHealth expects 200 from baseURL's /health endpoint.
+func probeHealth(ctx context.Context, client *http.Client, baseURL string) error {
+	healthURL := strings.TrimRight(baseURL, "/") + "/health"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s is unreachable: %w", healthURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d", healthURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// checkServer checks the server is up and reports one check per datasource
+// probe from its module health.
+func checkServer(ctx context.Context) []DoctorCheck {
+	health, code := serverHealth()
+
+	switch health {
+	case healthHealthy:
+	case healthUnhealthy:
+		return []DoctorCheck{{Name: "server", Status: doctorFail, Detail: fmt.Sprintf("unhealthy (HTTP %d)", code)}}
+	case healthUnreachable:
+		return []DoctorCheck{{Name: "server", Status: doctorWarn, Detail: "not running (start it with 'panda server start')"}}
+	default:
+		return []DoctorCheck{{Name: "server", Status: doctorWarn, Detail: "no server URL configured"}}
+	}
+
+	checks := []DoctorCheck{{Name: "server", Status: doctorPass, Detail: "healthy"}}
+
+	response, err := moduleHealth(ctx)
+	if err != nil {
+		return append(checks, DoctorCheck{Name: "datasources", Status: doctorWarn, Detail: fmt.Sprintf("module health unavailable: %v", err)})
+	}
+
+	return append(checks, datasourceChecks(response.Modules)...)
+}
+
+// datasourceChecks turns module health probes into doctor checks, in module
+// then datasource order.
+func datasourceChecks(modules []types.ModuleHealth) []DoctorCheck {
+	checks := make([]DoctorCheck, 0, len(modules))
+
+	for _, m := range modules {
+		for _, probe := range m.Probes {
+			check := DoctorCheck{
+				Name:   m.Module + "/" + probe.Datasource,
+				Status: doctorPass,
+				Detail: fmt.Sprintf("%.0fms", probe.LatencyMS),
+			}
+
+			if !probe.Healthy {
+				check.Status, check.Detail = doctorFail, probe.Error
+			}
+
+			checks = append(checks, check)
+		}
+	}
+
+	slices.SortStableFunc(checks, func(a, b DoctorCheck) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return checks
+}
+
+// checkEmbeddings checks the semantic search backend has what it needs.
+// The proxy backend is covered by the proxy check.
+func checkEmbeddings(cfg *config.Config) DoctorCheck {
+	search := cfg.SemanticSearch
+	check := DoctorCheck{Name: "embeddings", Status: doctorPass}
+
+	if search.Backend != config.EmbeddingBackendOpenAI {
+		check.Detail = "embedded by the proxy"
+
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%s via %s", search.OpenAI.Model, search.OpenAI.URL)
+
+	if os.Getenv(search.OpenAI.APIKeyEnv) == "" {
+		check.Status = doctorWarn
+		check.Detail += fmt.Sprintf(" (%s is not set)", search.OpenAI.APIKeyEnv)
+	}
+
+	return check
+}
+
+// ANSI colors for the doctor report.
+const (
+	ansiReset  = "\033[0m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiRed    = "\033[31m"
+)
+
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is unset.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printDoctorReport prints one line per check and a summary.
+func printDoctorReport(report *DoctorOutput, color bool) {
+	fmt.Println()
+
+	for _, check := range report.Checks {
+		fmt.Printf("%s  %-24s %s\n", doctorLabel(check.Status, color), check.Name, check.Detail)
+	}
+
+	fmt.Println()
+
+	if report.Failed > 0 {
+		fmt.Printf("%d check(s) failed\n", report.Failed)
+
+		return
+	}
+
+	fmt.Println("All checks passed")
+}
+
+func doctorLabel(status string, color bool) string {
+	label := "[" + strings.ToUpper(status) + "]"
+
+	if !color {
+		return label
+	}
+
+	switch status {
+	case doctorPass:
+		return ansiGreen + label + ansiReset
+	case doctorWarn:
+		return ansiYellow + label + ansiReset
+	default:
+		return ansiRed + label + ansiReset
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestProbeHealth(t *testing.T) {
+	t.Parallel()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(healthy.Close)
+
+	require.NoError(t, probeHealth(context.Background(), healthy.Client(), healthy.URL+"/"))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+
+	require.ErrorContains(t, probeHealth(context.Background(), failing.Client(), failing.URL), "HTTP 503")
+}
+
+func TestDatasourceChecks(t *testing.T) {
+	t.Parallel()
+
+	checks := datasourceChecks([]types.ModuleHealth{
+		{Module: "prometheus", Probes: []types.HealthProbe{{Datasource: "ops", Healthy: true, LatencyMS: 12}}},
+		{Module: "clickhouse", Probes: []types.HealthProbe{{Datasource: "xatu", Error: "connection refused"}}},
+		{Module: "dora", Status: types.HealthStatusNotProbed},
+	})
+
+	assert.Equal(t, []DoctorCheck{
+		{Name: "clickhouse/xatu", Status: doctorFail, Detail: "connection refused"},
+		{Name: "prometheus/ops", Status: doctorPass, Detail: "12ms"},
+	}, checks)
+}
+
+func TestSandboxRuntime(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, sandboxRuntime(config.SandboxConfig{Backend: "docker"}))
+	assert.Equal(t, "runsc", sandboxRuntime(config.SandboxConfig{Backend: "gvisor"}))
+	assert.Equal(t, "kata-fc", sandboxRuntime(config.SandboxConfig{
+		Backend:     "firecracker",
+		Firecracker: config.FirecrackerConfig{Runtime: "kata-fc"},
+	}))
+}
+
+func TestDoctorLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "[WARN]", doctorLabel(doctorWarn, false))
+	assert.Equal(t, ansiRed+"[FAIL]"+ansiReset, doctorLabel(doctorFail, true))
+}
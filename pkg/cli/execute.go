@@ -2,10 +2,12 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,10 +15,13 @@ import (
 )
 
 var (
-	executeCode    string
-	executeFile    string
-	executeTimeout int
-	executeSession string
+	executeCode     string
+	executeFile     string
+	executeTimeout  int
+	executeSession  string
+	executeProfile  string
+	executePackages []string
+	executeNoStream bool
 )
 
 var executeCmd = &cobra.Command{
@@ -29,10 +34,16 @@ flows through the credential proxy.
 
 Code can be provided via --code, --file, or stdin.
 
+Output is streamed to the terminal as the code runs. Use --no-stream to
+print it only once execution completes. --json always waits for the
+complete result.
+
 Examples:
   panda execute --code 'print("hello")'
   panda execute --file script.py
   panda execute --file script.py --session abc123
+  panda execute --file backfill.py --profile heavy
+  panda execute --file fit.py --package scipy --package 'statsmodels>=0.14'
   echo 'print("hello")' | panda execute
   panda execute --json --code 'import pandas; print(pandas.__version__)'`,
 	RunE: runExecute,
@@ -44,6 +55,9 @@ func init() {
 	executeCmd.Flags().StringVar(&executeFile, "file", "", "Path to Python file to execute")
 	executeCmd.Flags().IntVar(&executeTimeout, "timeout", 0, "Execution timeout in seconds (default: from config)")
 	executeCmd.Flags().StringVar(&executeSession, "session", "", "Session ID to reuse")
+	executeCmd.Flags().StringVar(&executeProfile, "profile", "", "Execution profile from the server's sandbox.profiles config")
+	executeCmd.Flags().StringArrayVar(&executePackages, "package", nil, "Python package to install into the session first (repeatable; see sandbox.packages)")
+	executeCmd.Flags().BoolVar(&executeNoStream, "no-stream", false, "Print output only after execution completes")
 
 	_ = executeCmd.RegisterFlagCompletionFunc("file", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"py"}, cobra.ShellCompDirectiveFilterFileExt
@@ -57,18 +71,73 @@ func runExecute(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	ctx := context.Background()
-	result, err := executeCodeRemotely(ctx, serverapi.ExecuteRequest{
+	return runExecution(context.Background(), serverapi.ExecuteRequest{
 		Code:      code,
 		Timeout:   executeTimeout,
 		SessionID: executeSession,
-	})
+		Profile:   executeProfile,
+		Packages:  executePackages,
+	}, !executeNoStream)
+}
+
+// runExecution executes code on the server and prints the result. When
+// stream is set and output is not JSON, stdout and stderr are printed live
+// as the code runs. Interactive terminals get a spinner with elapsed time.
+func runExecution(ctx context.Context, req serverapi.ExecuteRequest, stream bool) error {
+	var spin *spinner
+	if !isJSON() {
+		spin = startSpinner("running")
+	}
+
+	if stream && !isJSON() {
+		stdout, stderr := spin.writer(os.Stdout), spin.writer(os.Stderr)
+
+		result, err := executeCodeStreaming(ctx, req, func(event serverapi.ExecuteStreamEvent) {
+			switch {
+			case event.Type == serverapi.ExecuteEventQueued && event.Position > 0:
+				spin.SetLabel(fmt.Sprintf("queued, position %d", event.Position))
+			case event.Type == serverapi.ExecuteEventQueued:
+				spin.SetLabel("running")
+			case event.Type == serverapi.ExecuteEventStderr:
+				_, _ = io.WriteString(stderr, event.Data)
+			default:
+				_, _ = io.WriteString(stdout, event.Data)
+			}
+		})
+
+		if !errors.Is(err, errStreamUnsupported) {
+			spin.Stop()
+
+			if err != nil {
+				return fmt.Errorf("execution failed: %w", err)
+			}
+
+			printExecuteMetadata(result)
+
+			return codeExitError(result.ExitCode)
+		}
+	}
+
+	result, err := executeCodeRemotely(ctx, req)
+
+	spin.Stop()
+
 	if err != nil {
 		return fmt.Errorf("execution failed: %w", err)
 	}
 
+	return printExecuteResult(result)
+}
+
+// printExecuteResult prints an execution result: the code's stdout and stderr
+// on the matching streams, metadata on stderr, or the whole result as JSON.
+func printExecuteResult(result *serverapi.ExecuteResponse) error {
 	if isJSON() {
-		return printJSON(result)
+		if err := printJSON(result); err != nil {
+			return err
+		}
+
+		return codeExitError(result.ExitCode)
 	}
 
 	// Print stdout to stdout, stderr to stderr.
@@ -80,11 +149,40 @@ func runExecute(_ *cobra.Command, _ []string) error {
 		fmt.Fprint(os.Stderr, result.Stderr)
 	}
 
-	// Print metadata to stderr so stdout stays clean.
+	printExecuteMetadata(result)
+
+	return codeExitError(result.ExitCode)
+}
+
+// printExecuteMetadata prints error hints, rate limit state, output files,
+// artifacts, the structured result and session details to stderr so stdout
+// stays clean.
+func printExecuteMetadata(result *serverapi.ExecuteResponse) {
+	for _, hint := range result.Hints {
+		fmt.Fprintf(os.Stderr, "[hint] %s\n", hint.Message)
+	}
+
+	if rl := result.RateLimit; rl != nil {
+		fmt.Fprintf(os.Stderr, "[rate_limit] next request allowed at %s, full burst at %s\n",
+			rl.RetryAt.Format(time.RFC3339), rl.ResetAt.Format(time.RFC3339))
+	}
+
 	if len(result.OutputFiles) > 0 {
 		fmt.Fprintf(os.Stderr, "[files] %s\n", strings.Join(result.OutputFiles, ", "))
 	}
 
+	for _, artifact := range result.Artifacts {
+		fmt.Fprintf(os.Stderr, "[artifact] %s %s\n", artifact.Name, artifact.URL)
+	}
+
+	if len(result.Result) > 0 {
+		fmt.Fprintf(os.Stderr, "[result] %s\n", result.Result)
+	}
+
+	if result.Usage != nil {
+		fmt.Fprintf(os.Stderr, "[usage] %s\n", result.Usage)
+	}
+
 	if result.SessionID != "" {
 		ttl := result.SessionTTLRemaining
 		if ttl == "" {
@@ -92,12 +190,15 @@ func runExecute(_ *cobra.Command, _ []string) error {
 		}
 		fmt.Fprintf(os.Stderr, "[session] %s (ttl: %s)\n", result.SessionID, ttl)
 	}
+}
 
-	if result.ExitCode != 0 {
-		return fmt.Errorf("exit code %d", result.ExitCode)
+// codeExitError reports a nonzero exit code from the executed code.
+func codeExitError(exitCode int) error {
+	if exitCode == 0 {
+		return nil
 	}
 
-	return nil
+	return withExitCode(ExitCodeFailed, fmt.Errorf("exit code %d", exitCode))
 }
 
 func resolveCode() (string, error) {
@@ -115,7 +216,7 @@ func resolveCode() (string, error) {
 		// Check if stdin has data.
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) != 0 {
-			return "", fmt.Errorf("provide code via --code, --file, or stdin")
+			return "", withExitCode(ExitUsage, fmt.Errorf("provide code via --code, --file, or stdin"))
 		}
 
 		data, err := io.ReadAll(os.Stdin)
@@ -124,7 +225,7 @@ func resolveCode() (string, error) {
 		}
 
 		if len(data) == 0 {
-			return "", fmt.Errorf("no code provided")
+			return "", withExitCode(ExitUsage, fmt.Errorf("no code provided"))
 		}
 
 		return string(data), nil
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/serverapi"
+)
+
+// useTestServer points the CLI config at an httptest server running handler.
+// Callers must not be parallel because it mutates the package-level cfgFile.
+func useTestServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  url: "+srv.URL+"\n"), 0o600))
+
+	original := cfgFile
+	cfgFile = path
+
+	t.Cleanup(func() { cfgFile = original })
+}
+
+func TestExecuteCodeStreaming(t *testing.T) {
+	t.Run("delivers output then result", func(t *testing.T) {
+		useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/execute/stream", r.URL.Path)
+
+			enc := json.NewEncoder(w)
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventStdout, Data: "one\n"})
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventStderr, Data: "warn\n"})
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{
+				Type:   serverapi.ExecuteEventResult,
+				Result: &serverapi.ExecuteResponse{ExecutionID: "exec-1", ExitCode: 3, Result: json.RawMessage(`{"blocks":[1,2]}`)},
+			})
+		})
+
+		var events []serverapi.ExecuteStreamEvent
+
+		result, err := executeCodeStreaming(context.Background(), serverapi.ExecuteRequest{Code: "x"},
+			func(event serverapi.ExecuteStreamEvent) { events = append(events, event) })
+		require.NoError(t, err)
+
+		assert.Equal(t, "exec-1", result.ExecutionID)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.JSONEq(t, `{"blocks":[1,2]}`, string(result.Result))
+		require.Len(t, events, 2)
+		assert.Equal(t, "one\n", events[0].Data)
+		assert.Equal(t, serverapi.ExecuteEventStderr, events[1].Type)
+	})
+
+	t.Run("delivers queued events", func(t *testing.T) {
+		useTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			enc := json.NewEncoder(w)
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventQueued, Position: 2})
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventQueued})
+			_ = enc.Encode(serverapi.ExecuteStreamEvent{
+				Type:   serverapi.ExecuteEventResult,
+				Result: &serverapi.ExecuteResponse{ExecutionID: "exec-1"},
+			})
+		})
+
+		var positions []int
+
+		_, err := executeCodeStreaming(context.Background(), serverapi.ExecuteRequest{Code: "x"},
+			func(event serverapi.ExecuteStreamEvent) {
+				if event.Type == serverapi.ExecuteEventQueued {
+					positions = append(positions, event.Position)
+				}
+			})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 0}, positions)
+	})
+
+	t.Run("error event carries exit code", func(t *testing.T) {
+		useTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(serverapi.ExecuteStreamEvent{
+				Type:   serverapi.ExecuteEventError,
+				Error:  "execution timed out",
+				Status: http.StatusGatewayTimeout,
+			})
+		})
+
+		_, err := executeCodeStreaming(context.Background(), serverapi.ExecuteRequest{Code: "x"},
+			func(serverapi.ExecuteStreamEvent) {})
+		require.Error(t, err)
+		assert.Equal(t, ExitTimeout, exitCodeFor(err))
+	})
+
+	t.Run("truncated stream is an error", func(t *testing.T) {
+		useTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventStdout, Data: "partial"})
+		})
+
+		_, err := executeCodeStreaming(context.Background(), serverapi.ExecuteRequest{Code: "x"},
+			func(serverapi.ExecuteStreamEvent) {})
+		require.ErrorContains(t, err, "without a result")
+	})
+
+	t.Run("older server falls back", func(t *testing.T) {
+		useTestServer(t, http.NotFound)
+
+		_, err := executeCodeStreaming(context.Background(), serverapi.ExecuteRequest{Code: "x"},
+			func(serverapi.ExecuteStreamEvent) {})
+		require.ErrorIs(t, err, errStreamUnsupported)
+	})
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Process exit codes. These form a stable contract so scripts and CI
+// pipelines can branch on the class of failure.
+const (
+	// ExitOK indicates success.
+	ExitOK = 0
+	// ExitError is an unclassified failure.
+	ExitError = 1
+	// ExitUsage indicates invalid flags or arguments.
+	ExitUsage = 2
+	// ExitConfig indicates a missing or invalid configuration.
+	ExitConfig = 3
+	// ExitAuth indicates the user is not authenticated or not authorized.
+	ExitAuth = 4
+	// ExitSandbox indicates the sandbox failed to run the code.
+	ExitSandbox = 5
+	// ExitCodeFailed indicates the executed code ran but exited nonzero.
+	ExitCodeFailed = 6
+	// ExitTimeout indicates an operation exceeded its timeout.
+	ExitTimeout = 7
+	// ExitUnavailable indicates the panda server could not be reached.
+	ExitUnavailable = 8
+)
+
+// exitError attaches an exit code to an error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode tags err with the given exit code. A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &exitError{code: code, err: err}
+}
+
+// exitCodeFor returns the process exit code for an error returned by a command.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+
+	return ExitError
+}
+
+// exitCodeForStatus classifies a server API error status.
+func exitCodeForStatus(status int) int {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuth
+	case http.StatusGatewayTimeout:
+		return ExitTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ExitSandbox
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "unclassified", err: errors.New("boom"), want: ExitError},
+		{name: "tagged", err: withExitCode(ExitConfig, errors.New("bad config")), want: ExitConfig},
+		{
+			name: "tagged and wrapped",
+			err:  fmt.Errorf("execution failed: %w", withExitCode(ExitSandbox, errors.New("no sandbox"))),
+			want: ExitSandbox,
+		},
+		{name: "deadline", err: fmt.Errorf("waiting: %w", context.DeadlineExceeded), want: ExitTimeout},
+		{name: "api unauthorized", err: decodeAPIError(http.StatusUnauthorized, nil), want: ExitAuth},
+		{name: "api gateway timeout", err: decodeAPIError(http.StatusGatewayTimeout, nil), want: ExitTimeout},
+		{name: "api bad gateway", err: decodeAPIError(http.StatusBadGateway, nil), want: ExitSandbox},
+		{name: "api bad request", err: decodeAPIError(http.StatusBadRequest, nil), want: ExitError},
+		{name: "user code", err: codeExitError(3), want: ExitCodeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, exitCodeFor(tt.err))
+		})
+	}
+}
+
+func TestWithExitCodePreservesMessage(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, withExitCode(ExitAuth, nil))
+	assert.EqualError(t, withExitCode(ExitAuth, errors.New("login failed")), "login failed")
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/serverapi"
+)
+
+var (
+	historySession  string
+	historyLimit    int
+	historyNotebook bool
+
+	promoteName        string
+	promoteDescription string
+	promoteCategory    string
+	promoteCluster     string
+)
+
+var historyCmd = &cobra.Command{
+	GroupID: groupWorkflow,
+	Use:     "history",
+	Short:   "Show and re-run previous executions",
+	Long: `Show the execution history recorded by the server and re-run
+previous snippets.
+
+Examples:
+  panda history
+  panda history --session <session-id>
+  panda history --session <session-id> --notebook
+  panda history show <execution-id>
+  panda history rerun <execution-id>
+  panda history promote <execution-id> --name "Block arrival" --description "..."`,
+	RunE: runHistoryList,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <execution-id>",
+	Short: "Show a recorded execution including its code",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryShow,
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <execution-id>",
+	Short: "Execute a recorded snippet again",
+	Long: `Execute the code of a recorded execution again. Runs in a new
+sandbox unless --session is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryRerun,
+}
+
+var historyPromoteCmd = &cobra.Command{
+	Use:   "promote <execution-id>",
+	Short: "Save a successful execution as a searchable example",
+	Long: `Save the code of a successful execution as a query example. Promoted
+examples are stored by the server, show up in examples://queries and are
+returned by search immediately. The server must have user_examples enabled.
+
+Prompts for the name and description when they are not given and stdin is
+a terminal.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryPromote,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyRerunCmd)
+	historyCmd.AddCommand(historyPromoteCmd)
+
+	historyCmd.Flags().StringVar(&historySession, "session", "", "only show executions in this session")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "maximum number of executions to show (default: 50)")
+	historyCmd.Flags().BoolVar(&historyNotebook, "notebook", false, "export the session's executions as a Jupyter notebook and print its URL (needs --session)")
+	historyRerunCmd.Flags().StringVar(&historySession, "session", "", "session ID to run in")
+	historyPromoteCmd.Flags().StringVar(&promoteName, "name", "", "example name")
+	historyPromoteCmd.Flags().StringVar(&promoteDescription, "description", "", "what the example shows")
+	historyPromoteCmd.Flags().StringVar(&promoteCategory, "category", "", "category key (default: promoted)")
+	historyPromoteCmd.Flags().StringVar(&promoteCluster, "cluster", "", "cluster the query runs against, if any")
+
+	_ = historyCmd.RegisterFlagCompletionFunc("session", completeSessionIDs)
+	_ = historyRerunCmd.RegisterFlagCompletionFunc("session", completeSessionIDs)
+}
+
+func runHistoryList(_ *cobra.Command, _ []string) error {
+	if historyNotebook {
+		return runHistoryNotebook()
+	}
+
+	response, err := listExecutions(context.Background(), historySession, historyLimit)
+	if err != nil {
+		return fmt.Errorf("listing executions: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	if len(response.Executions) == 0 {
+		fmt.Println("No recorded executions.")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(response.Executions))
+	for _, record := range response.Executions {
+		session := record.SessionID
+		if session == "" {
+			session = "-"
+		}
+
+		rows = append(rows, []string{
+			record.ExecutionID,
+			record.StartedAt.Local().Format(time.RFC3339),
+			session,
+			fmt.Sprintf("%d", record.ExitCode),
+			fmt.Sprintf("%.1fs", record.DurationSeconds),
+			firstLine(record.Code),
+		})
+	}
+
+	printTable([]string{"EXECUTION", "STARTED", "SESSION", "EXIT", "DURATION", "CODE"}, rows)
+
+	return nil
+}
+
+// runHistoryNotebook has the server turn a session's executions into a
+// notebook in its storage, so the investigation can be picked up in Jupyter.
+func runHistoryNotebook() error {
+	if historySession == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--notebook needs --session"))
+	}
+
+	response, err := exportNotebook(context.Background(), historySession)
+	if err != nil {
+		return fmt.Errorf("exporting notebook: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	fmt.Printf("Exported %d executions from session %s\n%s\n", response.Executions, response.SessionID, response.URL)
+
+	return nil
+}
+
+func runHistoryShow(_ *cobra.Command, args []string) error {
+	record, err := getExecution(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("getting execution: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(record)
+	}
+
+	pairs := [][2]string{
+		{"Execution", record.ExecutionID},
+		{"Started", record.StartedAt.Local().Format(time.RFC3339)},
+		{"Duration", fmt.Sprintf("%.2fs", record.DurationSeconds)},
+		{"Exit code", fmt.Sprintf("%d", record.ExitCode)},
+		{"Code hash", record.CodeHash},
+	}
+
+	if record.SessionID != "" {
+		pairs = append(pairs, [2]string{"Session", record.SessionID})
+	}
+
+	if record.Error != "" {
+		pairs = append(pairs, [2]string{"Error", record.Error})
+	}
+
+	printKeyValue(pairs)
+	fmt.Println()
+	fmt.Println(record.Code)
+
+	return nil
+}
+
+func runHistoryRerun(_ *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	record, err := getExecution(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("getting execution: %w", err)
+	}
+
+	return runExecution(ctx, serverapi.ExecuteRequest{
+		Code:      record.Code,
+		SessionID: historySession,
+	}, true)
+}
+
+func runHistoryPromote(_ *cobra.Command, args []string) error {
+	if promoteName == "" || promoteDescription == "" {
+		if !isTerminal(os.Stdin) {
+			return withExitCode(ExitUsage, errors.New("--name and --description are required"))
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+
+		var err error
+		if promoteName, err = promptLine(reader, "Name", promoteName); err != nil {
+			return err
+		}
+
+		if promoteDescription, err = promptLine(reader, "Description", promoteDescription); err != nil {
+			return err
+		}
+	}
+
+	response, err := promoteExecution(context.Background(), args[0], serverapi.PromoteExampleRequest{
+		Name:        promoteName,
+		Description: promoteDescription,
+		Category:    promoteCategory,
+		Cluster:     promoteCluster,
+	})
+	if err != nil {
+		return fmt.Errorf("promoting execution: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	fmt.Printf("Promoted %s to %q in %s.\n", response.ExecutionID, response.Example.Name, response.CategoryKey)
+
+	if !response.Indexed {
+		fmt.Println("The example is saved but not yet searchable; it is indexed on the next server start.")
+	}
+
+	return nil
+}
+
+// promptLine asks for a value on stderr unless current is already set.
+func promptLine(reader *bufio.Reader, label, current string) (string, error) {
+	if current != "" {
+		return current, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading %s: %w", strings.ToLower(label), err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// firstLine returns the first non-empty line of code, for compact listings.
+func firstLine(code string) string {
+	for _, line := range strings.Split(code, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			return trimmed
+		}
+	}
+
+	return ""
+}
@@ -70,15 +70,21 @@ func init() {
 	initCmd.Flags().BoolVar(&initSkipDocker, "skip-docker", false, "skip Docker check and image pull")
 	initCmd.Flags().BoolVar(&initSkipAuth, "skip-auth", false, "skip authentication step")
 	initCmd.Flags().BoolVar(&initSkipStart, "skip-start", false, "skip starting the server")
-	initCmd.Flags().BoolVar(&noBrowser, "no-browser", false,
-		"manual auth flow for SSH/headless environments (auto-detected over SSH)")
+	initCmd.Flags().BoolVar(&noBrowser, "device", false,
+		"log in with a device code, for SSH sessions and containers (auto-detected)")
+	initCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "alias for --device")
 }
 
 func runInit(_ *cobra.Command, _ []string) error {
+	return runWithJSONResult(initialize)
+}
+
+// initialize performs the init steps and reports what was written and started.
+func initialize() (*InitOutput, error) {
 	// 1. Docker check and image pulls.
 	if !initSkipDocker {
 		if err := checkDockerAndPullImages(); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		fmt.Println("Skipping Docker check and image pulls (--skip-docker)")
@@ -86,12 +92,12 @@ func runInit(_ *cobra.Command, _ []string) error {
 
 	// 2. Write config files.
 	if err := os.MkdirAll(initDir, 0o755); err != nil {
-		return fmt.Errorf("creating config directory %s: %w", initDir, err)
+		return nil, fmt.Errorf("creating config directory %s: %w", initDir, err)
 	}
 
 	absConfigDir, err := filepath.Abs(initDir)
 	if err != nil {
-		return fmt.Errorf("resolving absolute path for %s: %w", initDir, err)
+		return nil, fmt.Errorf("resolving absolute path for %s: %w", initDir, err)
 	}
 
 	// Discover auth settings from the proxy (best-effort, falls back to defaults).
@@ -110,7 +116,7 @@ func runInit(_ *cobra.Command, _ []string) error {
 
 	configCreated, err := writeConfigFile(configPath, configContent, initForce)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	composeContent := buildComposeTemplate(initServerImage, absConfigDir)
@@ -118,7 +124,17 @@ func runInit(_ *cobra.Command, _ []string) error {
 
 	composeCreated, err := writeConfigFile(composePath, composeContent, initForce)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	output := &InitOutput{
+		ConfigPath:     configPath,
+		ConfigCreated:  configCreated > 0,
+		ComposePath:    composePath,
+		ComposeCreated: composeCreated > 0,
+		AuthIssuer:     authCfg.IssuerURL,
+		AuthClientID:   authCfg.ClientID,
+		AuthSkipped:    initSkipAuth,
 	}
 
 	// 3. Print config summary.
@@ -141,7 +157,7 @@ func runInit(_ *cobra.Command, _ []string) error {
 		fmt.Println()
 
 		if skipped, err := initEnsureAuth(); err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+			return nil, fmt.Errorf("authentication failed: %w", err)
 		} else if skipped {
 			fmt.Println("Already authenticated (credentials still valid)")
 		}
@@ -162,16 +178,18 @@ func runInit(_ *cobra.Command, _ []string) error {
 		fmt.Println("Starting server...")
 
 		if err := runDockerCompose(resolveComposeFile(), "up", "-d", "--force-recreate"); err != nil {
-			return fmt.Errorf("starting server: %w", err)
+			return nil, fmt.Errorf("starting server: %w", err)
 		}
 
+		output.ServerStarted = true
+
 		fmt.Println()
 		fmt.Println("Server is starting at http://localhost:2480")
 		fmt.Println("Run 'panda server status' to check health")
 		fmt.Println("Run 'panda datasources' to list available datasources")
 	}
 
-	return nil
+	return output, nil
 }
 
 func writeConfigFile(path, content string, force bool) (int, error) {
@@ -283,6 +301,9 @@ services:
       - "%s"
     ports:
       - "127.0.0.1:2480:2480"
+    environment:
+      # Passphrase for credentials stored with proxy.auth.credential_store: encrypted.
+      - PANDA_CREDENTIALS_KEY
     volumes:
       - /var/run/docker.sock:/var/run/docker.sock
       - /tmp/ethpandaops-panda-sandbox:/tmp/ethpandaops-panda-sandbox
@@ -469,6 +490,8 @@ func initEnsureAuth() (bool, error) {
 		IssuerURL:  target.issuerURL,
 		ClientID:   target.clientID,
 		Resource:   target.resource,
+		Profile:    credentialProfile(),
+		Backend:    credentialStore(),
 	})
 
 	// Try to get a valid access token (refreshes automatically if needed).
@@ -479,5 +502,7 @@ func initEnsureAuth() (bool, error) {
 	// No valid credentials — run the full login flow.
 	fmt.Println("Authenticating...")
 
-	return false, runAuthLogin(nil, nil)
+	_, err = authLogin()
+
+	return false, err
 }
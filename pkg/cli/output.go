@@ -31,6 +31,31 @@ func printJSON(v any) error {
 	return nil
 }
 
+// runWithJSONResult runs a command body that prints human-readable progress
+// and returns a structured result. In JSON mode, progress (including output
+// from child processes) is diverted to stderr while fn runs, and the result
+// is printed to stdout so it is the only document there.
+func runWithJSONResult[T any](fn func() (T, error)) error {
+	if !isJSON() {
+		_, err := fn()
+		return err
+	}
+
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+
+	result, err := func() (T, error) {
+		defer func() { os.Stdout = stdout }()
+
+		return fn()
+	}()
+	if err != nil {
+		return err
+	}
+
+	return printJSON(result)
+}
+
 // printJSONBytes pretty-prints raw JSON bytes, preserving original number
 // precision (avoids float64 round-trip that loses large integers).
 func printJSONBytes(data []byte) error {
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout redirects os.Stdout and os.Stderr while fn runs and returns
+// what was written to each.
+func captureStdout(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	outR, outW, err := os.Pipe()
+	require.NoError(t, err)
+
+	errR, errW, err := os.Pipe()
+	require.NoError(t, err)
+
+	origOut, origErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outW, errW
+
+	fn()
+
+	os.Stdout, os.Stderr = origOut, origErr
+
+	require.NoError(t, outW.Close())
+	require.NoError(t, errW.Close())
+
+	stdout, err := io.ReadAll(outR)
+	require.NoError(t, err)
+
+	stderr, err := io.ReadAll(errR)
+	require.NoError(t, err)
+
+	return string(stdout), string(stderr)
+}
+
+// TestRunWithJSONResult is not parallel because it mutates the package-level
+// outputFormat variable and the process stdout/stderr.
+func TestRunWithJSONResult(t *testing.T) {
+	original := outputFormat
+
+	t.Cleanup(func() { outputFormat = original })
+
+	body := func() (*SessionDestroyOutput, error) {
+		fmt.Println("progress")
+
+		return &SessionDestroyOutput{SessionID: "abc", Destroyed: true}, nil
+	}
+
+	t.Run("text mode prints progress only", func(t *testing.T) {
+		outputFormat = "text"
+
+		stdout, stderr := captureStdout(t, func() {
+			require.NoError(t, runWithJSONResult(body))
+		})
+
+		assert.Equal(t, "progress\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("json mode moves progress to stderr", func(t *testing.T) {
+		outputFormat = "json"
+
+		stdout, stderr := captureStdout(t, func() {
+			require.NoError(t, runWithJSONResult(body))
+		})
+
+		assert.Equal(t, "progress\n", stderr)
+
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+		assert.Equal(t, map[string]any{"session_id": "abc", "destroyed": true}, result)
+	})
+}
@@ -0,0 +1,379 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/ethpandaops/panda/pkg/configpath"
+	"github.com/ethpandaops/panda/pkg/serverapi"
+)
+
+const (
+	replPrompt         = ">>> "
+	replContinuePrompt = "... "
+
+	// replHistorySize bounds the history kept in memory and on disk.
+	replHistorySize = 1000
+)
+
+var (
+	replSession string
+	replProfile string
+	replTimeout int
+	replKeep    bool
+)
+
+var replCmd = &cobra.Command{
+	GroupID: groupWorkflow,
+	Use:     "repl",
+	Short:   "Run Python interactively in a sandbox session",
+	Long: `Open a sandbox session and run Python snippets in it interactively.
+Variables don't carry over between snippets, but files in /workspace do
+(and everything does with the server's sandbox.sessions.kernel mode).
+
+Lines ending in ':' or with open brackets continue on a '...' prompt
+until a blank line. Up and down browse the history, which is kept in
+~/.config/panda/repl_history. Ctrl-C clears the input or interrupts the
+running snippet, and Ctrl-D exits.
+
+The session is destroyed on exit unless it was given with --session or
+--keep is set.
+
+Examples:
+  panda repl
+  panda repl --session abc123
+  panda repl --profile heavy --keep`,
+	Args: cobra.NoArgs,
+	RunE: runREPL,
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+	replCmd.Flags().StringVar(&replSession, "session", "", "Session ID to reuse")
+	replCmd.Flags().StringVar(&replProfile, "profile", "", "Execution profile from the server's sandbox.profiles config")
+	replCmd.Flags().IntVar(&replTimeout, "timeout", 0, "Per-snippet timeout in seconds (default: from config)")
+	replCmd.Flags().BoolVar(&replKeep, "keep", false, "Keep the session on exit")
+
+	_ = replCmd.RegisterFlagCompletionFunc("session", completeSessionIDs)
+}
+
+func runREPL(_ *cobra.Command, _ []string) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return withExitCode(ExitUsage, fmt.Errorf("repl needs an interactive terminal; pipe scripts to 'panda execute' instead"))
+	}
+
+	ctx := context.Background()
+
+	sessionID := replSession
+	if sessionID == "" {
+		created, err := createSession(ctx)
+		if err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+
+		sessionID = created.SessionID
+
+		defer func() {
+			if replKeep {
+				fmt.Printf("Session %s kept\n", sessionID)
+
+				return
+			}
+
+			if err := destroySession(context.Background(), sessionID); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to destroy session %s: %v\n", sessionID, err)
+			}
+		}()
+	}
+
+	fmt.Printf("Session %s. Ctrl-C interrupts, Ctrl-D exits.\n", sessionID)
+
+	history := loadREPLHistory(filepath.Join(configpath.DefaultConfigDir(), "repl_history"))
+	input := &interruptReader{r: os.Stdin}
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{input, os.Stdout}, replPrompt)
+	terminal.History = history
+
+	for {
+		code, err := readSnippet(fd, terminal, input)
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+
+		runSnippet(ctx, serverapi.ExecuteRequest{
+			Code:      code,
+			Timeout:   replTimeout,
+			SessionID: sessionID,
+			Profile:   replProfile,
+		})
+	}
+}
+
+// readSnippet reads one snippet in raw mode, following continuation lines
+// until needsMore is satisfied. Ctrl-C discards the snippet.
+func readSnippet(fd int, terminal *term.Terminal, input *interruptReader) (string, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("setting raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	lines := make([]string, 0, 1)
+	terminal.SetPrompt(replPrompt)
+
+	for {
+		line, err := terminal.ReadLine()
+		if err != nil {
+			return "", err
+		}
+
+		if input.interrupted {
+			input.interrupted = false
+
+			return "", nil
+		}
+
+		lines = append(lines, line)
+
+		if !needsMore(lines) {
+			return strings.Join(lines, "\n"), nil
+		}
+
+		terminal.SetPrompt(replContinuePrompt)
+	}
+}
+
+// runSnippet executes code in cooked mode so Ctrl-C raises SIGINT, which
+// cancels the execution request.
+func runSnippet(ctx context.Context, req serverapi.ExecuteRequest) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	result, err := executeCodeStreaming(ctx, req, func(event serverapi.ExecuteStreamEvent) {
+		switch event.Type {
+		case serverapi.ExecuteEventStdout:
+			_, _ = io.WriteString(os.Stdout, event.Data)
+		case serverapi.ExecuteEventStderr:
+			_, _ = io.WriteString(os.Stderr, event.Data)
+		}
+	})
+	if errors.Is(err, errStreamUnsupported) {
+		if result, err = executeCodeRemotely(ctx, req); err == nil {
+			fmt.Print(result.Stdout)
+			fmt.Fprint(os.Stderr, result.Stderr)
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		fmt.Fprintln(os.Stderr, "\nKeyboardInterrupt")
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	default:
+		// The session is shown once at startup.
+		metadata := *result
+		metadata.SessionID = ""
+		printExecuteMetadata(&metadata)
+	}
+}
+
+// needsMore reports whether a snippet continues on another line: its last
+// line opens a block or continues explicitly, brackets or triple quotes are
+// still open, or it is a block not yet closed by a blank line.
+func needsMore(lines []string) bool {
+	last := strings.TrimRightFunc(lines[len(lines)-1], func(r rune) bool { return r == ' ' || r == '\t' })
+
+	if len(lines) > 1 && last != "" {
+		return true
+	}
+
+	if strings.HasSuffix(last, ":") || strings.HasSuffix(last, "\\") {
+		return true
+	}
+
+	joined := strings.Join(lines, "\n")
+	if strings.Count(joined, `"""`)%2 == 1 || strings.Count(joined, "'''")%2 == 1 {
+		return true
+	}
+
+	return bracketDepth(joined) > 0
+}
+
+// bracketDepth counts unclosed brackets outside strings and comments.
+func bracketDepth(code string) int {
+	depth := 0
+
+	var quote rune
+
+	escaped := false
+	comment := false
+
+	for _, r := range code {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '#':
+			comment = true
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		}
+	}
+
+	return depth
+}
+
+// interruptReader turns Ctrl-C into "clear line, enter" for term.Terminal,
+// which would otherwise end input like Ctrl-D, and records the interrupt.
+type interruptReader struct {
+	r           io.Reader
+	pending     []byte
+	interrupted bool
+}
+
+func (i *interruptReader) Read(p []byte) (int, error) {
+	if len(i.pending) == 0 {
+		buf := make([]byte, len(p))
+
+		n, err := i.r.Read(buf)
+
+		for _, b := range buf[:n] {
+			if b == 3 {
+				i.interrupted = true
+				i.pending = append(i.pending, 21, '\r')
+
+				continue
+			}
+
+			i.pending = append(i.pending, b)
+		}
+
+		if len(i.pending) == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(p, i.pending)
+	i.pending = i.pending[n:]
+
+	return n, nil
+}
+
+// replHistory is a bounded term.History appended to a file so it survives
+// across sessions.
+type replHistory struct {
+	path    string
+	entries []string
+}
+
+// loadREPLHistory reads the most recent entries from path. A missing or
+// unreadable file starts an empty history.
+func loadREPLHistory(path string) *replHistory {
+	h := &replHistory{path: path, entries: make([]string, 0, 64)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer func() { _ = file.Close() }()
+
+	lines := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.push(scanner.Text())
+		lines++
+	}
+
+	// The file is only appended to, so compact it once it has grown well
+	// past what is kept.
+	if lines > 2*replHistorySize {
+		_ = os.WriteFile(path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+	}
+
+	return h
+}
+
+// push adds entry unless it is blank or repeats the latest entry.
+func (h *replHistory) push(entry string) bool {
+	if strings.TrimSpace(entry) == "" {
+		return false
+	}
+
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return false
+	}
+
+	h.entries = append(h.entries, entry)
+
+	if len(h.entries) > replHistorySize {
+		h.entries = h.entries[len(h.entries)-replHistorySize:]
+	}
+
+	return true
+}
+
+// Add implements term.History and appends entry to the history file.
+func (h *replHistory) Add(entry string) {
+	if !h.push(entry) || h.path == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(file, entry)
+	_ = file.Close()
+}
+
+// Len implements term.History.
+func (h *replHistory) Len() int {
+	return len(h.entries)
+}
+
+// At implements term.History. Index 0 is the most recent entry.
+func (h *replHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
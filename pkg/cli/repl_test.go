@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsMore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  bool
+	}{
+		{name: "simple statement", lines: []string{"print(1)"}, want: false},
+		{name: "block opener", lines: []string{"for i in range(3):"}, want: true},
+		{name: "block body", lines: []string{"for i in range(3):", "    print(i)"}, want: true},
+		{name: "block closed by blank line", lines: []string{"for i in range(3):", "    print(i)", ""}, want: false},
+		{name: "open bracket", lines: []string{"df = query("}, want: true},
+		{name: "bracket in string", lines: []string{`print("(")`}, want: false},
+		{name: "bracket in comment", lines: []string{"x = 1  # (todo"}, want: false},
+		{name: "line continuation", lines: []string{`x = 1 + \`}, want: true},
+		{name: "open triple quote", lines: []string{`sql = """`}, want: true},
+		{name: "closed triple quote", lines: []string{`sql = """SELECT 1"""`}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, needsMore(tt.lines))
+		})
+	}
+}
+
+func TestInterruptReader(t *testing.T) {
+	t.Parallel()
+
+	input := &interruptReader{r: strings.NewReader("ab\x03c")}
+
+	data, err := io.ReadAll(input)
+	require.NoError(t, err)
+	assert.Equal(t, "ab\x15\rc", string(data))
+	assert.True(t, input.interrupted)
+
+	// Reads smaller than the expansion still see every byte.
+	input = &interruptReader{r: strings.NewReader("\x03")}
+	buf := make([]byte, 1)
+
+	var got []byte
+
+	for {
+		n, err := input.Read(buf)
+		got = append(got, buf[:n]...)
+
+		if err != nil {
+			break
+		}
+	}
+
+	assert.Equal(t, "\x15\r", string(got))
+}
+
+func TestREPLHistory(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "panda", "repl_history")
+
+	history := loadREPLHistory(path)
+	history.Add("x = 1")
+	history.Add("x = 1")
+	history.Add("  ")
+	history.Add("print(x)")
+
+	require.Equal(t, 2, history.Len())
+	assert.Equal(t, "print(x)", history.At(0))
+	assert.Equal(t, "x = 1", history.At(1))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "x = 1\nprint(x)\n", string(data))
+
+	reloaded := loadREPLHistory(path)
+	assert.Equal(t, 2, reloaded.Len())
+	assert.Equal(t, "print(x)", reloaded.At(0))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +22,9 @@ Examples:
   panda resources
   panda resources read panda://getting-started
   panda resources read python://ethpandaops
+  panda resources read python://ethpandaops/clickhouse
   panda resources read clickhouse://tables
+  panda resources get datasources://clickhouse
   panda resources -o json`,
 	RunE: runResourcesList,
 }
@@ -39,9 +42,24 @@ Examples:
 	RunE: runResourcesRead,
 }
 
+var resourcesGetCmd = &cobra.Command{
+	Use:   "get <uri>",
+	Short: "Read a resource over MCP and print the raw response",
+	Long: `Read a resource over MCP, as a connected client would, and print the
+raw ReadResourceResult. Useful for testing resource handlers without
+wiring up an MCP client.
+
+Examples:
+  panda resources get panda://getting-started
+  panda resources get clickhouse://tables/beacon_api_eth_v1_events_block`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResourcesGet,
+}
+
 func init() {
 	rootCmd.AddCommand(resourcesCmd)
 	resourcesCmd.AddCommand(resourcesReadCmd)
+	resourcesCmd.AddCommand(resourcesGetCmd)
 }
 
 func runResourcesList(_ *cobra.Command, _ []string) error {
@@ -109,3 +127,23 @@ func runResourcesRead(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runResourcesGet(_ *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := connectMCP(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = args[0]
+
+	result, err := client.ReadResource(ctx, request)
+	if err != nil {
+		return fmt.Errorf("reading resource: %w", err)
+	}
+
+	return printJSON(result)
+}
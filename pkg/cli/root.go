@@ -12,6 +12,7 @@ import (
 
 	"github.com/ethpandaops/panda/internal/github"
 	"github.com/ethpandaops/panda/internal/version"
+	authstore "github.com/ethpandaops/panda/pkg/auth/store"
 )
 
 // Command group IDs for cobra help grouping.
@@ -23,9 +24,10 @@ const (
 )
 
 var (
-	cfgFile  string
-	logLevel string
-	log      = logrus.New()
+	cfgFile     string
+	logLevel    string
+	profileName string
+	log         = logrus.New()
 )
 
 // updateResult carries the latest version from the background check.
@@ -39,6 +41,7 @@ var skipUpdateCheckCommands = map[string]bool{
 	"version":    true,
 	"completion": true,
 	"init":       true,
+	"doctor":     true,
 	"help":       true,
 }
 
@@ -59,6 +62,11 @@ New? Start here: panda getting-started`,
 			FullTimestamp: true,
 		})
 
+		// Validates --profile and $PANDA_PROFILE.
+		if _, err := authstore.ResolveProfile(profileName, ""); err != nil {
+			return err
+		}
+
 		if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
 			outputFormat = "json"
 		}
@@ -84,7 +92,7 @@ New? Start here: panda getting-started`,
 // Execute runs the root command.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
@@ -96,10 +104,16 @@ func init() {
 		&cobra.Group{ID: groupSetup, Title: "Setup:"},
 	)
 
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
+
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "",
 		"config file (default: $PANDA_CONFIG, ~/.config/panda/config.yaml, or ./config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
 		"log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "",
+		"credential profile for proxy auth (default: $PANDA_PROFILE or 'default')")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text",
 		"output format (text, json)")
 	rootCmd.PersistentFlags().Bool("json", false,
@@ -112,6 +126,15 @@ func init() {
 	_ = rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
 		[]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp,
 	))
+	_ = rootCmd.RegisterFlagCompletionFunc("profile",
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			profiles, err := authstore.ListProfiles()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return profiles, cobra.ShellCompDirectiveNoFileComp
+		})
 	_ = rootCmd.RegisterFlagCompletionFunc("config",
 		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 			return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
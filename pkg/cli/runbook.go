@@ -0,0 +1,418 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/runbooks"
+)
+
+// Step statuses recorded in the investigation report.
+const (
+	runbookStepRan     = "ran"
+	runbookStepFailed  = "failed"
+	runbookStepSkipped = "skipped"
+	runbookStepNotRun  = "not run"
+)
+
+var (
+	runbookRunSession string
+	runbookRunProfile string
+	runbookRunTimeout int
+	runbookRunKeep    bool
+	runbookRunYes     bool
+	runbookRunReport  string
+	runbookRunVars    []string
+)
+
+var runbookCmd = &cobra.Command{
+	GroupID: groupWorkflow,
+	Use:     "runbook",
+	Short:   "Work through investigation runbooks",
+}
+
+var runbookRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Walk through a runbook's steps and write an investigation report",
+	Long: `Walk through a runbook step by step. Each step's Python snippet is shown
+and run in a sandbox session once confirmed: answer y (or Enter) to run
+it, n to skip it, or q to stop. Step output is shown on stderr as it runs.
+
+When done, a Markdown report with each step, its code, output, and
+artifact links is written to --report, or to stdout.
+
+<name> is the runbook's name or file name, as shown by
+'panda search runbooks'. Placeholders such as {network} in snippets are
+filled in with --var.
+
+The session is destroyed at the end unless it was given with --session
+or --keep is set.
+
+Examples:
+  panda runbook run finality_delay --var network=mainnet
+  panda runbook run "Investigate Finality Delay" --report finality.md
+  panda runbook run slow_query --yes --session abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRunbookRun,
+}
+
+func init() {
+	rootCmd.AddCommand(runbookCmd)
+	runbookCmd.AddCommand(runbookRunCmd)
+
+	runbookRunCmd.Flags().StringVar(&runbookRunSession, "session", "", "Session ID to reuse")
+	runbookRunCmd.Flags().StringVar(&runbookRunProfile, "profile", "", "Execution profile from the server's sandbox.profiles config")
+	runbookRunCmd.Flags().IntVar(&runbookRunTimeout, "timeout", 0, "Per-step timeout in seconds (default: from config)")
+	runbookRunCmd.Flags().BoolVar(&runbookRunKeep, "keep", false, "Keep the session when done")
+	runbookRunCmd.Flags().BoolVarP(&runbookRunYes, "yes", "y", false, "Run every step without asking")
+	runbookRunCmd.Flags().StringVar(&runbookRunReport, "report", "", "Write the Markdown report to this file instead of stdout")
+	runbookRunCmd.Flags().StringArrayVar(&runbookRunVars, "var", nil, "Fill a {key} placeholder in snippets, as key=value (repeatable)")
+
+	_ = runbookRunCmd.RegisterFlagCompletionFunc("session", completeSessionIDs)
+}
+
+// runbookStepResult is a step as it ended up in the investigation.
+type runbookStepResult struct {
+	runbooks.Step
+
+	Status string
+	Result *serverapi.ExecuteResponse
+	Error  string
+}
+
+// runbookReport is everything rendered into the investigation report.
+type runbookReport struct {
+	Runbook   *serverapi.SearchRunbookResult
+	SessionID string
+	Started   time.Time
+	Steps     []runbookStepResult
+}
+
+func runRunbookRun(cmd *cobra.Command, args []string) error {
+	vars, err := parseRunbookVars(runbookRunVars)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	runbook, err := findRunbook(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	steps := runbooks.Steps(runbook.Content)
+
+	sessionID := runbookRunSession
+	if sessionID == "" {
+		created, err := createSession(ctx)
+		if err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+
+		sessionID = created.SessionID
+
+		defer func() {
+			if runbookRunKeep {
+				fmt.Fprintf(os.Stderr, "Session %s kept\n", sessionID)
+
+				return
+			}
+
+			if err := destroySession(context.Background(), sessionID); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to destroy session %s: %v\n", sessionID, err)
+			}
+		}()
+	}
+
+	report := runbookReport{
+		Runbook:   runbook,
+		SessionID: sessionID,
+		Started:   time.Now().UTC(),
+		Steps:     make([]runbookStepResult, 0, len(steps)),
+	}
+
+	fmt.Fprintf(os.Stderr, "%s (%d steps, session %s)\n", runbook.Name, len(steps), sessionID)
+
+	input := bufio.NewReader(os.Stdin)
+	stopped := false
+
+	for i, step := range steps {
+		step.Code = runbooks.FillVars(step.Code, vars)
+		result := runbookStepResult{Step: step, Status: runbookStepNotRun}
+
+		if step.Code != "" && !stopped {
+			fmt.Fprintf(os.Stderr, "\n== Step %d/%d: %s ==\n\n%s\n\n%s\n\n", i+1, len(steps), step.Title, step.Text, step.Code)
+
+			answer := "y"
+			if !runbookRunYes {
+				answer = promptRunbookStep(input)
+			}
+
+			switch answer {
+			case "y":
+				runRunbookStep(ctx, sessionID, &result)
+			case "n":
+				result.Status = runbookStepSkipped
+			default:
+				stopped = true
+			}
+		}
+
+		report.Steps = append(report.Steps, result)
+	}
+
+	return writeRunbookReport(&report)
+}
+
+// findRunbook looks the runbook up by name or file name among the search
+// results for name.
+func findRunbook(ctx context.Context, name string) (*serverapi.SearchRunbookResult, error) {
+	response, err := searchRunbooks(ctx, name, "", 5)
+	if err != nil {
+		return nil, fmt.Errorf("searching runbooks: %w", err)
+	}
+
+	for _, result := range response.Results {
+		if runbookMatches(result, name) {
+			return result, nil
+		}
+	}
+
+	names := make([]string, 0, len(response.Results))
+	for _, result := range response.Results {
+		names = append(names, fmt.Sprintf("%q", result.Name))
+	}
+
+	if len(names) == 0 {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("runbook %q not found", name))
+	}
+
+	return nil, withExitCode(ExitUsage, fmt.Errorf(
+		"runbook %q not found; closest matches: %s", name, strings.Join(names, ", ")))
+}
+
+// runbookMatches reports whether name is the runbook's name or file name,
+// ignoring case and the .md extension.
+func runbookMatches(result *serverapi.SearchRunbookResult, name string) bool {
+	name = strings.TrimSuffix(name, ".md")
+	file := strings.TrimSuffix(path.Base(result.FilePath), ".md")
+
+	return strings.EqualFold(result.Name, name) || (result.FilePath != "" && strings.EqualFold(file, name))
+}
+
+// parseRunbookVars parses key=value --var flags.
+func parseRunbookVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok || key == "" {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("invalid --var %q: expected key=value", flag))
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// promptRunbookStep asks whether to run a step and returns "y", "n" or
+// "q". End of input stops the runbook.
+func promptRunbookStep(input *bufio.Reader) string {
+	for {
+		fmt.Fprint(os.Stderr, "Run this step? [Y/n/q] ")
+
+		line, err := input.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+
+		switch {
+		case answer == "" && err != nil:
+			fmt.Fprintln(os.Stderr)
+
+			return "q"
+		case answer == "" || answer == "y" || answer == "yes":
+			return "y"
+		case answer == "n" || answer == "no" || answer == "s" || answer == "skip":
+			return "n"
+		case answer == "q" || answer == "quit":
+			return "q"
+		}
+	}
+}
+
+// runRunbookStep executes a step's snippet in the session, streaming its
+// output to stderr, and records the outcome.
+func runRunbookStep(ctx context.Context, sessionID string, result *runbookStepResult) {
+	req := serverapi.ExecuteRequest{
+		Code:      result.Code,
+		Timeout:   runbookRunTimeout,
+		SessionID: sessionID,
+		Profile:   runbookRunProfile,
+	}
+
+	response, err := executeCodeStreaming(ctx, req, func(event serverapi.ExecuteStreamEvent) {
+		switch event.Type {
+		case serverapi.ExecuteEventStdout, serverapi.ExecuteEventStderr:
+			_, _ = io.WriteString(os.Stderr, event.Data)
+		}
+	})
+	if errors.Is(err, errStreamUnsupported) {
+		if response, err = executeCodeRemotely(ctx, req); err == nil {
+			fmt.Fprint(os.Stderr, response.Stdout, response.Stderr)
+		}
+	}
+
+	switch {
+	case err != nil:
+		result.Status = runbookStepFailed
+		result.Error = err.Error()
+
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	case response.ExitCode != 0:
+		result.Status = runbookStepFailed
+		result.Result = response
+
+		fmt.Fprintf(os.Stderr, "exit code %d\n", response.ExitCode)
+	default:
+		result.Status = runbookStepRan
+		result.Result = response
+	}
+}
+
+// writeRunbookReport renders the report to --report or stdout.
+func writeRunbookReport(report *runbookReport) error {
+	if runbookRunReport == "" {
+		return renderRunbookReport(os.Stdout, report)
+	}
+
+	file, err := os.Create(runbookRunReport)
+	if err != nil {
+		return fmt.Errorf("creating report: %w", err)
+	}
+
+	if err := renderRunbookReport(file, report); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", runbookRunReport)
+
+	return nil
+}
+
+// renderRunbookReport writes the investigation report as Markdown.
+func renderRunbookReport(w io.Writer, report *runbookReport) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Investigation: %s\n\n", report.Runbook.Name)
+	fmt.Fprintf(&b, "%s\n\n", report.Runbook.Description)
+	fmt.Fprintf(&b, "- Started: %s\n", report.Started.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Session: `%s`\n", report.SessionID)
+
+	if report.Runbook.FilePath != "" {
+		fmt.Fprintf(&b, "- Runbook: `%s`\n", report.Runbook.FilePath)
+	}
+
+	if len(report.Runbook.Prerequisites) > 0 {
+		fmt.Fprintf(&b, "- Prerequisites: %s\n", strings.Join(report.Runbook.Prerequisites, ", "))
+	}
+
+	for i, step := range report.Steps {
+		fmt.Fprintf(&b, "\n## %d. %s\n\n", i+1, step.Title)
+
+		if step.Text != "" {
+			fmt.Fprintf(&b, "%s\n\n", demoteHeadings(step.Text))
+		}
+
+		if step.Code == "" {
+			continue
+		}
+
+		b.WriteString(markdownFence("python", step.Code))
+		fmt.Fprintf(&b, "\n**Status:** %s", step.Status)
+
+		if result := step.Result; result != nil {
+			fmt.Fprintf(&b, " (exit code %d, %.1fs, execution `%s`)", result.ExitCode, result.DurationSeconds, result.ExecutionID)
+		}
+
+		b.WriteString("\n")
+
+		if step.Error != "" {
+			fmt.Fprintf(&b, "\n**Error:** %s\n", step.Error)
+		}
+
+		if step.Result == nil {
+			continue
+		}
+
+		if step.Result.Stdout != "" {
+			b.WriteString("\nOutput:\n\n")
+			b.WriteString(markdownFence("text", step.Result.Stdout))
+		}
+
+		if step.Result.Stderr != "" {
+			b.WriteString("\nStderr:\n\n")
+			b.WriteString(markdownFence("text", step.Result.Stderr))
+		}
+
+		if len(step.Result.Artifacts) > 0 {
+			b.WriteString("\nArtifacts:\n\n")
+
+			for _, artifact := range step.Result.Artifacts {
+				fmt.Fprintf(&b, "- [%s](%s)\n", artifact.Name, artifact.URL)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// markdownFence wraps content in a code fence longer than any backtick
+// run inside it.
+func markdownFence(lang, content string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+
+	return fence + lang + "\n" + strings.TrimRight(content, "\n") + "\n" + fence + "\n"
+}
+
+// demoteHeadings nests the runbook's own headings under the report's step
+// headings.
+func demoteHeadings(text string) string {
+	lines := strings.Split(text, "\n")
+	inFence := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+
+		if !inFence && strings.HasPrefix(trimmed, "#") {
+			lines[i] = "##" + trimmed
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
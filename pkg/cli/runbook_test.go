@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/runbooks"
+)
+
+func TestRunbookMatches(t *testing.T) {
+	t.Parallel()
+
+	result := &serverapi.SearchRunbookResult{Name: "Investigate Finality Delay", FilePath: "finality_delay.md"}
+
+	assert.True(t, runbookMatches(result, "investigate finality delay"))
+	assert.True(t, runbookMatches(result, "finality_delay"))
+	assert.True(t, runbookMatches(result, "finality_delay.md"))
+	assert.False(t, runbookMatches(result, "finality"))
+}
+
+func TestParseRunbookVars(t *testing.T) {
+	t.Parallel()
+
+	vars, err := parseRunbookVars([]string{"network=mainnet", "filter=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"network": "mainnet", "filter": "a=b"}, vars)
+
+	_, err = parseRunbookVars([]string{"network"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, exitCodeFor(err))
+}
+
+func TestPromptRunbookStep(t *testing.T) {
+	t.Parallel()
+
+	input := bufio.NewReader(strings.NewReader("\nmaybe\nskip\nq\n"))
+
+	assert.Equal(t, "y", promptRunbookStep(input))
+	assert.Equal(t, "n", promptRunbookStep(input), "unknown answers ask again")
+	assert.Equal(t, "q", promptRunbookStep(input))
+	assert.Equal(t, "q", promptRunbookStep(input), "end of input stops")
+}
+
+func TestRenderRunbookReport(t *testing.T) {
+	t.Parallel()
+
+	report := &runbookReport{
+		Runbook: &serverapi.SearchRunbookResult{
+			Name:          "Investigate Finality Delay",
+			Description:   "Find why finality stalled.",
+			Prerequisites: []string{"xatu"},
+			FilePath:      "finality_delay.md",
+		},
+		SessionID: "sess-1",
+		Started:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Steps: []runbookStepResult{
+			{
+				Step:   runbooks.Step{Title: "Check participation", Text: "### Details", Code: "print(1)"},
+				Status: runbookStepRan,
+				Result: &serverapi.ExecuteResponse{
+					Stdout:          "has ``` fence\n",
+					ExecutionID:     "exec-1",
+					DurationSeconds: 1.25,
+					Artifacts:       []sandbox.Artifact{{Name: "chart.png", URL: "https://example.com/chart.png"}},
+				},
+			},
+			{
+				Step:   runbooks.Step{Title: "Check peers", Code: "print(2)"},
+				Status: runbookStepSkipped,
+			},
+			{
+				Step:   runbooks.Step{Title: "Notes", Text: "- Gaps"},
+				Status: runbookStepNotRun,
+			},
+		},
+	}
+
+	var out strings.Builder
+	require.NoError(t, renderRunbookReport(&out, report))
+
+	assert.Equal(t, "# Investigation: Investigate Finality Delay\n\n"+
+		"Find why finality stalled.\n\n"+
+		"- Started: 2026-01-02T03:04:05Z\n"+
+		"- Session: `sess-1`\n"+
+		"- Runbook: `finality_delay.md`\n"+
+		"- Prerequisites: xatu\n"+
+		"\n## 1. Check participation\n\n"+
+		"##### Details\n\n"+
+		"```python\nprint(1)\n```\n"+
+		"\n**Status:** ran (exit code 0, 1.2s, execution `exec-1`)\n"+
+		"\nOutput:\n\n"+
+		"````text\nhas ``` fence\n````\n"+
+		"\nArtifacts:\n\n"+
+		"- [chart.png](https://example.com/chart.png)\n"+
+		"\n## 2. Check peers\n\n"+
+		"```python\nprint(2)\n```\n"+
+		"\n**Status:** skipped\n"+
+		"\n## 3. Notes\n\n"+
+		"- Gaps\n\n", out.String())
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/pkg/schedule"
+)
+
+var schedulesCmd = &cobra.Command{
+	GroupID: groupDiscovery,
+	Use:     "schedules",
+	Short:   "Show scheduled jobs and their recent runs",
+	Long: `Show the jobs the server runs on a cron schedule (schedules.jobs in the
+server config) with their next run and latest result, or a job's recent
+runs with their output. Run a job now with 'panda admin run-schedule'.
+
+Examples:
+  panda schedules
+  panda schedules runs nightly-block-gaps`,
+	Args: cobra.NoArgs,
+	RunE: runSchedulesList,
+}
+
+var schedulesRunsCmd = &cobra.Command{
+	Use:   "runs <job>",
+	Short: "Show a job's recent runs, newest first",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSchedulesRuns,
+}
+
+var adminRunScheduleCmd = &cobra.Command{
+	Use:   "run-schedule <job>",
+	Short: "Run a scheduled job now and print the result",
+	Long: `Run a scheduled job now, outside its schedule, and wait for the result.
+Exits nonzero when the run fails.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminRunSchedule,
+}
+
+func init() {
+	rootCmd.AddCommand(schedulesCmd)
+	schedulesCmd.AddCommand(schedulesRunsCmd)
+	adminCmd.AddCommand(adminRunScheduleCmd)
+}
+
+func runSchedulesList(_ *cobra.Command, _ []string) error {
+	response, err := listSchedules(context.Background())
+	if err != nil {
+		return fmt.Errorf("listing schedules: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	if len(response.Jobs) == 0 {
+		fmt.Println("No scheduled jobs.")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(response.Jobs))
+	for _, job := range response.Jobs {
+		last := "-"
+		if job.Running {
+			last = "running"
+		} else if job.LastRun != nil {
+			last = fmt.Sprintf("%s %s", job.LastRun.Status(), job.LastRun.StartedAt.Local().Format(time.RFC3339))
+		}
+
+		next := "never"
+		if !job.NextRun.IsZero() {
+			next = job.NextRun.Local().Format(time.RFC3339)
+		}
+
+		rows = append(rows, []string{job.Name, job.Cron, next, last, job.Source})
+	}
+
+	printTable([]string{"JOB", "CRON", "NEXT RUN", "LAST RUN", "SOURCE"}, rows)
+
+	return nil
+}
+
+func runSchedulesRuns(_ *cobra.Command, args []string) error {
+	response, err := listScheduleRuns(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("listing runs: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	if len(response.Runs) == 0 {
+		fmt.Printf("No runs of %s yet.\n", response.Job)
+
+		return nil
+	}
+
+	for i, run := range response.Runs {
+		if i > 0 {
+			fmt.Println("---")
+		}
+
+		printScheduledRun(&run)
+	}
+
+	return nil
+}
+
+func runAdminRunSchedule(_ *cobra.Command, args []string) error {
+	token, err := resolveAdminToken()
+	if err != nil {
+		return err
+	}
+
+	run, err := runSchedule(context.Background(), token, args[0])
+	if err != nil {
+		return fmt.Errorf("running %s: %w", args[0], err)
+	}
+
+	if isJSON() {
+		if err := printJSON(run); err != nil {
+			return err
+		}
+	} else {
+		printScheduledRun(run)
+	}
+
+	if run.Status() != "success" {
+		return withExitCode(ExitCodeFailed, fmt.Errorf("run of %s %s", args[0], run.Status()))
+	}
+
+	return nil
+}
+
+func printScheduledRun(run *schedule.Run) {
+	fmt.Printf("%s  %s (%s trigger, exit %d, %.1fs)\n",
+		run.StartedAt.Local().Format(time.RFC3339), run.Status(), run.Trigger, run.ExitCode, run.DurationSeconds)
+
+	if run.ExecutionID != "" {
+		fmt.Printf("  Execution: %s\n", run.ExecutionID)
+	}
+
+	if run.URL != "" {
+		fmt.Printf("  Result:    %s\n", run.URL)
+	}
+
+	if run.Error != "" {
+		fmt.Printf("  Error:     %s\n", run.Error)
+	}
+
+	if run.Stdout != "" {
+		fmt.Printf("\n%s\n", run.Stdout)
+	}
+
+	if run.Stderr != "" {
+		fmt.Printf("\nstderr:\n%s\n", run.Stderr)
+	}
+}
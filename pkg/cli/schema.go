@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -103,6 +104,30 @@ func showTable(ctx context.Context, tableName string) error {
 		}
 	}
 
+	for _, clause := range [][2]string{
+		{"Partition by", schema.PartitionBy},
+		{"Order by", schema.OrderBy},
+		{"TTL", schema.TTL},
+		{"Local table", schema.LocalTable},
+	} {
+		if clause[1] != "" {
+			fmt.Printf("%s: %s\n", clause[0], clause[1])
+		}
+	}
+
+	if view := schema.MaterializedView; view != nil {
+		target := view.Target
+		if target == "" {
+			target = schema.Name
+		}
+
+		fmt.Printf("Materialized view: %s -> %s\n", strings.Join(view.Sources, ", "), target)
+	}
+
+	for _, feed := range schema.FedBy {
+		fmt.Printf("Fed by: %s (from %s)\n", feed.View, strings.Join(feed.Sources, ", "))
+	}
+
 	fmt.Println()
 
 	rows := make([][]string, 0, len(schema.Columns))
@@ -112,5 +137,19 @@ func showTable(ctx context.Context, tableName string) error {
 
 	printTable([]string{"NAME", "TYPE", "COMMENT"}, rows)
 
+	if len(schema.SampleRows) > 0 {
+		fmt.Println()
+		fmt.Println("Sample rows:")
+
+		for _, row := range schema.SampleRows {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return fmt.Errorf("encoding sample row: %w", err)
+			}
+
+			fmt.Printf("  %s\n", data)
+		}
+	}
+
 	return nil
 }
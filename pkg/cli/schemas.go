@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// The types below are the stable --json output schemas for commands whose
+// result is produced locally rather than relayed from the server API. Fields
+// may be added over time but existing fields are never renamed or removed,
+// so operator automation can depend on them.
+
+// VersionOutput is the --json output of `panda version`.
+type VersionOutput struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Auth status values reported in AuthStatusOutput.Status.
+const (
+	authStatusDisabled         = "disabled"
+	authStatusNotConfigured    = "not_configured"
+	authStatusNotAuthenticated = "not_authenticated"
+	authStatusAuthenticated    = "authenticated"
+	authStatusExpired          = "expired"
+)
+
+// AuthStatusOutput is the --json output of `panda auth status`.
+type AuthStatusOutput struct {
+	// Status is one of disabled, not_authenticated, authenticated or expired.
+	Status          string     `json:"status"`
+	Profile         string     `json:"profile"`
+	Issuer          string     `json:"issuer,omitempty"`
+	ClientID        string     `json:"client_id,omitempty"`
+	Resource        string     `json:"resource,omitempty"`
+	CredentialsPath string     `json:"credentials_path,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+}
+
+// AuthLoginOutput is the --json output of `panda auth login`.
+type AuthLoginOutput struct {
+	// Enabled is false when the configured server does not use proxy auth,
+	// in which case no login was performed.
+	Enabled         bool       `json:"enabled"`
+	Profile         string     `json:"profile"`
+	Issuer          string     `json:"issuer,omitempty"`
+	CredentialsPath string     `json:"credentials_path,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+}
+
+// AuthLogoutOutput is the --json output of `panda auth logout`.
+type AuthLogoutOutput struct {
+	Profile         string `json:"profile"`
+	CredentialsPath string `json:"credentials_path"`
+	Revoked         bool   `json:"revoked"`
+}
+
+// AuthProfilesOutput is the --json output of `panda auth profiles`.
+type AuthProfilesOutput struct {
+	Active   string   `json:"active"`
+	Profiles []string `json:"profiles"`
+}
+
+// SessionDestroyOutput is the --json output of `panda session destroy`.
+type SessionDestroyOutput struct {
+	SessionID string `json:"session_id"`
+	Destroyed bool   `json:"destroyed"`
+}
+
+// SessionFileOutput is the --json output of `panda session get` when
+// writing to a local file. `panda session put` prints the server response.
+type SessionFileOutput struct {
+	SessionID string `json:"session_id"`
+	Path      string `json:"path"`
+	LocalPath string `json:"local_path"`
+	Size      int64  `json:"size"`
+}
+
+// ServerActionOutput is the --json output of `panda server start|stop|restart|update`.
+type ServerActionOutput struct {
+	Action      string `json:"action"`
+	ComposeFile string `json:"compose_file"`
+}
+
+// Server health values reported in ServerStatusOutput.Health.
+const (
+	healthHealthy     = "healthy"
+	healthUnhealthy   = "unhealthy"
+	healthUnreachable = "unreachable"
+	healthUnknown     = "unknown"
+)
+
+// ServerStatusOutput is the --json output of `panda server status`.
+type ServerStatusOutput struct {
+	ComposeFile string `json:"compose_file"`
+	// Containers holds the entries reported by `docker compose ps --format json`.
+	Containers []map[string]any `json:"containers"`
+	// Health is one of healthy, unhealthy, unreachable or unknown.
+	Health string `json:"health"`
+	// HealthHTTPStatus is the /health status code when the server responded.
+	HealthHTTPStatus int `json:"health_http_status,omitempty"`
+	// Modules holds the /health/modules probe results when the server is healthy.
+	Modules []types.ModuleHealth `json:"modules,omitempty"`
+	// Auth is one of not_configured, not_authenticated or authenticated.
+	Auth     string `json:"auth"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+}
+
+// UpgradeOutput is the --json output of `panda upgrade`.
+type UpgradeOutput struct {
+	CurrentVersion string `json:"current_version"`
+	LatestVersion  string `json:"latest_version"`
+	UpToDate       bool   `json:"up_to_date"`
+	// Upgraded is false when already up to date or the upgrade was cancelled.
+	Upgraded      bool `json:"upgraded"`
+	CLIUpgraded   bool `json:"cli_upgraded"`
+	ServerUpdated bool `json:"server_updated"`
+}
+
+// InitOutput is the --json output of `panda init`.
+type InitOutput struct {
+	ConfigPath     string `json:"config_path"`
+	ConfigCreated  bool   `json:"config_created"`
+	ComposePath    string `json:"compose_path"`
+	ComposeCreated bool   `json:"compose_created"`
+	AuthIssuer     string `json:"auth_issuer"`
+	AuthClientID   string `json:"auth_client_id"`
+	AuthSkipped    bool   `json:"auth_skipped"`
+	ServerStarted  bool   `json:"server_started"`
+}
+
+// DoctorCheck is one check in DoctorOutput.
+type DoctorCheck struct {
+	Name string `json:"name"`
+	// Status is one of pass, warn or fail.
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// DoctorOutput is the --json output of `panda doctor`.
+type DoctorOutput struct {
+	Checks []DoctorCheck `json:"checks"`
+	// Failed counts the checks with status fail.
+	Failed int `json:"failed"`
+}
@@ -20,6 +20,7 @@ var (
 	searchEIPCategory     string
 	searchEIPType         string
 	searchEIPLimit        int
+	searchRateNotUseful   bool
 )
 
 var searchCmd = &cobra.Command{
@@ -34,7 +35,8 @@ Examples:
   panda search "eip-4844"
   panda search examples "attestation participation"
   panda search runbooks "finality delay"
-  panda search eips "account abstraction"`,
+  panda search eips "account abstraction"
+  panda search rate <category-key> <example-name>`,
 	Args: cobra.ArbitraryArgs,
 	RunE: runSearchAll,
 }
@@ -60,11 +62,23 @@ var searchEIPsCmd = &cobra.Command{
 	RunE:  runSearchEIPs,
 }
 
+var searchRateCmd = &cobra.Command{
+	Use:   "rate <category-key> <example-name>",
+	Short: "Mark a query example as useful, ranking it higher",
+	Long: `Record that a query example was useful, or with --not-useful that it
+was not. Examples marked useful rank higher in later searches. Examples
+reused by successful executions are counted automatically. The server must
+have example_usage enabled.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSearchRate,
+}
+
 func init() {
 	rootCmd.AddCommand(searchCmd)
 	searchCmd.AddCommand(searchExamplesCmd)
 	searchCmd.AddCommand(searchRunbooksCmd)
 	searchCmd.AddCommand(searchEIPsCmd)
+	searchCmd.AddCommand(searchRateCmd)
 
 	searchCmd.Flags().IntVar(&searchAllLimit, "limit", 3, "Max results per index (default: 3)")
 	searchCmd.ValidArgsFunction = noCompletions
@@ -82,6 +96,9 @@ func init() {
 	searchEIPsCmd.Flags().StringVar(&searchEIPType, "type", "", "Filter by type (e.g., Standards Track)")
 	searchEIPsCmd.Flags().IntVar(&searchEIPLimit, "limit", 5, "Max results (default: 5, max: 10)")
 	searchEIPsCmd.ValidArgsFunction = noCompletions
+
+	searchRateCmd.Flags().BoolVar(&searchRateNotUseful, "not-useful", false, "Record that the example was not useful")
+	searchRateCmd.ValidArgsFunction = noCompletions
 }
 
 func runSearchAll(cmd *cobra.Command, args []string) error {
@@ -143,6 +160,15 @@ func runSearchAll(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	switch {
+	case examplesErr == nil:
+		printSearchWarning(cmd, examplesResp.Warning)
+	case runbooksErr == nil:
+		printSearchWarning(cmd, runbooksResp.Warning)
+	case eipsErr == nil:
+		printSearchWarning(cmd, eipsResp.Warning)
+	}
+
 	sections := 0
 
 	if examplesErr == nil && len(examplesResp.Results) > 0 {
@@ -210,6 +236,8 @@ func runSearchExamples(cmd *cobra.Command, args []string) error {
 		return printJSON(response)
 	}
 
+	printSearchWarning(cmd, response.Warning)
+
 	if len(response.Results) == 0 {
 		fmt.Println("No matching examples found.")
 		return nil
@@ -220,6 +248,26 @@ func runSearchExamples(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runSearchRate(cmd *cobra.Command, args []string) error {
+	response, err := rateExample(cmd.Context(), serverapi.RateExampleRequest{
+		CategoryKey: args[0],
+		ExampleName: args[1],
+		Useful:      !searchRateNotUseful,
+	})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	fmt.Printf("[%s] %s: %d uses, %d not useful\n",
+		response.CategoryKey, response.ExampleName, response.Uses, response.NotUseful)
+
+	return nil
+}
+
 func runSearchRunbooks(cmd *cobra.Command, args []string) error {
 	response, err := searchRunbooks(cmd.Context(), args[0], searchRunbookTag, searchRunbookLimit)
 	if err != nil {
@@ -230,6 +278,8 @@ func runSearchRunbooks(cmd *cobra.Command, args []string) error {
 		return printJSON(response)
 	}
 
+	printSearchWarning(cmd, response.Warning)
+
 	if len(response.Results) == 0 {
 		fmt.Println("No matching runbooks found.")
 		return nil
@@ -254,6 +304,8 @@ func runSearchEIPs(cmd *cobra.Command, args []string) error {
 		return printJSON(response)
 	}
 
+	printSearchWarning(cmd, response.Warning)
+
 	if len(response.Results) == 0 {
 		fmt.Println("No matching EIPs found.")
 		return nil
@@ -264,6 +316,13 @@ func runSearchEIPs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printSearchWarning notes on stderr that results are keyword matches.
+func printSearchWarning(cmd *cobra.Command, warning string) {
+	if warning != "" {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n\n", warning)
+	}
+}
+
 func printExampleResults(results []*serverapi.SearchExampleResult) {
 	for i, result := range results {
 		if i > 0 {
@@ -278,6 +337,10 @@ func printExampleResults(results []*serverapi.SearchExampleResult) {
 			fmt.Printf("  Cluster: %s\n", result.TargetCluster)
 		}
 
+		if result.Uses > 0 {
+			fmt.Printf("  Used: %d times\n", result.Uses)
+		}
+
 		fmt.Printf("\n%s\n\n", result.Query)
 	}
 }
@@ -292,6 +355,10 @@ func printRunbookResults(results []*serverapi.SearchRunbookResult) {
 		fmt.Printf("  %s\n", result.Description)
 		fmt.Printf("  Tags: %s\n", strings.Join(result.Tags, ", "))
 
+		if result.Source != "" {
+			fmt.Printf("  Source: %s (%s)\n", result.Source, result.FilePath)
+		}
+
 		if len(result.Prerequisites) > 0 {
 			fmt.Printf("  Prerequisites: %s\n",
 				strings.Join(result.Prerequisites, ", "))
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -20,6 +22,7 @@ import (
 	"github.com/ethpandaops/panda/pkg/config"
 	"github.com/ethpandaops/panda/pkg/configpath"
 	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 var composeFile string
@@ -91,24 +94,50 @@ func init() {
 }
 
 func runServerStart(_ *cobra.Command, _ []string) error {
-	return runDockerCompose(resolveComposeFile(), "up", "-d", "--force-recreate")
+	return runServerAction("start", func(compose string) error {
+		return runDockerCompose(compose, "up", "-d", "--force-recreate")
+	})
 }
 
 func runServerStop(_ *cobra.Command, _ []string) error {
-	// Clean up orphaned sandbox containers before compose down,
-	// so the shared network can be removed cleanly.
-	cleanupSandboxContainers()
+	return runServerAction("stop", func(compose string) error {
+		// Clean up orphaned sandbox containers before compose down,
+		// so the shared network can be removed cleanly.
+		cleanupSandboxContainers()
 
-	return runDockerCompose(resolveComposeFile(), "down")
+		return runDockerCompose(compose, "down")
+	})
 }
 
 func runServerRestart(_ *cobra.Command, _ []string) error {
-	return runDockerCompose(resolveComposeFile(), "restart")
+	return runServerAction("restart", func(compose string) error {
+		return runDockerCompose(compose, "restart")
+	})
+}
+
+// runServerAction runs a lifecycle action against the compose file and, in
+// JSON mode, reports it as a ServerActionOutput.
+func runServerAction(action string, fn func(compose string) error) error {
+	compose := resolveComposeFile()
+
+	return runWithJSONResult(func() (*ServerActionOutput, error) {
+		if err := fn(compose); err != nil {
+			return nil, err
+		}
+
+		return &ServerActionOutput{Action: action, ComposeFile: compose}, nil
+	})
 }
 
 func runServerStatus(_ *cobra.Command, _ []string) error {
+	compose := resolveComposeFile()
+
+	if isJSON() {
+		return printJSON(serverStatus(compose))
+	}
+
 	// Show container status.
-	if err := runDockerCompose(resolveComposeFile(), "ps"); err != nil {
+	if err := runDockerCompose(compose, "ps"); err != nil {
 		return err
 	}
 
@@ -126,12 +155,65 @@ func runServerStatus(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// serverStatus collects container, health, auth and proxy status for JSON output.
+// Failures are reported in the result rather than returned.
+func serverStatus(compose string) *ServerStatusOutput {
+	status := &ServerStatusOutput{
+		ComposeFile: compose,
+		Containers:  composeContainers(compose),
+		Auth:        authSummary(),
+	}
+
+	status.Health, status.HealthHTTPStatus = serverHealth()
+
+	if status.Health == healthHealthy {
+		if response, err := moduleHealth(context.Background()); err == nil {
+			status.Modules = response.Modules
+		}
+	}
+
+	if cfg, err := config.LoadClient(cfgFile); err == nil {
+		status.ProxyURL = cfg.Proxy.URL
+	}
+
+	return status
+}
+
+// composeContainers returns the entries from `docker compose ps --format json`.
+// Depending on the compose version this is either a JSON array or one JSON
+// object per line; both are accepted.
+func composeContainers(compose string) []map[string]any {
+	containers := make([]map[string]any, 0)
+
+	out, err := exec.Command("docker", "compose", "-f", compose, "ps", "--format", "json").Output()
+	if err != nil {
+		return containers
+	}
+
+	trimmed := bytes.TrimSpace(out)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		_ = json.Unmarshal(trimmed, &containers)
+		return containers
+	}
+
+	for _, line := range bytes.Split(trimmed, []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err == nil {
+			containers = append(containers, entry)
+		}
+	}
+
+	return containers
+}
+
 func runServerLogs(_ *cobra.Command, _ []string) error {
 	return runDockerCompose(resolveComposeFile(), "logs", "-f")
 }
 
 func runServerUpdate(_ *cobra.Command, _ []string) error {
-	return upgradeServer()
+	return runServerAction("update", func(string) error {
+		return upgradeServer()
+	})
 }
 
 // resolveComposeFile returns the docker-compose file path from
@@ -176,37 +258,93 @@ func runDockerCompose(compose string, args ...string) error {
 // printHealthStatus checks the server's /health endpoint and prints
 // the result.
 func printHealthStatus() {
-	cfg, err := config.LoadClient(cfgFile)
-	if err != nil {
+	health, code := serverHealth()
+
+	switch health {
+	case healthHealthy:
+		fmt.Println("Health: Healthy")
+		printModuleHealth()
+	case healthUnhealthy:
+		fmt.Printf("Health: Unhealthy (HTTP %d)\n", code)
+	case healthUnreachable:
+		fmt.Println("Health: Unreachable")
+	default:
 		fmt.Println("Health: Unknown (config not loaded)")
+	}
+}
+
+// printModuleHealth prints the probed health of each module with its
+// average probe latency. Modules without probes are skipped.
+func printModuleHealth() {
+	response, err := moduleHealth(context.Background())
+	if err != nil {
 		return
 	}
 
+	for _, m := range response.Modules {
+		if m.Status == types.HealthStatusNotProbed {
+			continue
+		}
+
+		var total float64
+		for _, probe := range m.Probes {
+			total += probe.LatencyMS
+		}
+
+		fmt.Printf("  %-12s %-10s %d probe(s), avg %.0fms\n", m.Module, m.Status, len(m.Probes), total/float64(len(m.Probes)))
+
+		for _, probe := range m.Probes {
+			if !probe.Healthy {
+				fmt.Printf("    %s: %s\n", probe.Datasource, probe.Error)
+			}
+		}
+	}
+}
+
+// serverHealth checks the server's /health endpoint and returns the health
+// state along with the HTTP status code when the server responded.
+func serverHealth() (string, int) {
+	cfg, err := config.LoadClient(cfgFile)
+	if err != nil {
+		return healthUnknown, 0
+	}
+
 	healthURL := strings.TrimRight(cfg.ServerURL(), "/") + "/health"
 
 	client := &http.Client{Timeout: 5 * time.Second}
 
 	resp, err := client.Get(healthURL) //nolint:noctx // simple health check
 	if err != nil {
-		fmt.Println("Health: Unreachable")
-		return
+		return healthUnreachable, 0
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusOK {
-		fmt.Println("Health: Healthy")
-	} else {
-		fmt.Printf("Health: Unhealthy (HTTP %d)\n", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return healthUnhealthy, resp.StatusCode
 	}
+
+	return healthHealthy, resp.StatusCode
 }
 
 // printAuthStatus loads auth credentials and prints whether the user
 // is authenticated against the configured proxy.
 func printAuthStatus() {
+	switch authSummary() {
+	case authStatusNotConfigured:
+		fmt.Println("Auth: Not configured")
+	case authStatusAuthenticated:
+		fmt.Println("Auth: Authenticated")
+	default:
+		fmt.Println("Auth: Not authenticated (run 'panda auth login')")
+	}
+}
+
+// authSummary reports whether proxy auth is configured and, if so, whether
+// valid credentials are stored for the active profile.
+func authSummary() string {
 	target := resolveAuthTargetFromConfig()
 	if target == nil {
-		fmt.Println("Auth: Not configured")
-		return
+		return authStatusNotConfigured
 	}
 
 	client := authclient.New(log, authclient.Config{
@@ -220,13 +358,15 @@ func printAuthStatus() {
 		IssuerURL:  target.issuerURL,
 		ClientID:   target.clientID,
 		Resource:   target.resource,
+		Profile:    credentialProfile(),
+		Backend:    credentialStore(),
 	})
 
 	if store.IsAuthenticated() {
-		fmt.Println("Auth: Authenticated")
-	} else {
-		fmt.Println("Auth: Not authenticated (run 'panda auth login')")
+		return authStatusAuthenticated
 	}
+
+	return authStatusNotAuthenticated
 }
 
 // printProxyURL loads the config and prints the configured proxy URL.
@@ -236,9 +376,12 @@ func printProxyURL() {
 		return
 	}
 
-	if cfg.Proxy.URL != "" {
+	switch {
+	case cfg.Proxy.Mode == config.ProxyModeEmbedded:
+		fmt.Println("Proxy: Embedded in server")
+	case cfg.Proxy.URL != "":
 		fmt.Printf("Proxy: %s\n", cfg.Proxy.URL)
-	} else {
+	default:
 		fmt.Println("Proxy: Not configured")
 	}
 }
@@ -9,12 +9,18 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	clickhousemodule "github.com/ethpandaops/panda/modules/clickhouse"
+	authclient "github.com/ethpandaops/panda/pkg/auth/client"
+	authstore "github.com/ethpandaops/panda/pkg/auth/store"
 	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/history"
 	"github.com/ethpandaops/panda/pkg/operations"
+	"github.com/ethpandaops/panda/pkg/schedule"
 	"github.com/ethpandaops/panda/pkg/serverapi"
 )
 
@@ -28,12 +34,46 @@ type rawServerResponse struct {
 func serverBaseURL() (string, error) {
 	cfg, err := config.LoadClient(cfgFile)
 	if err != nil {
-		return "", fmt.Errorf("loading config: %w", err)
+		return "", withExitCode(ExitConfig, fmt.Errorf("loading config: %w", err))
 	}
 
 	return cfg.ServerURL(), nil
 }
 
+// serverAccessToken returns the bearer token sent to a server that requires
+// auth.bearer: proxy.auth.token when set, otherwise the stored login
+// credentials, or "" when there are neither.
+var serverAccessToken = sync.OnceValue(func() string {
+	if cfg, err := config.LoadClient(cfgFile); err == nil && cfg.Proxy.Auth != nil && cfg.Proxy.Auth.Token != "" {
+		return cfg.Proxy.Auth.Token
+	}
+
+	target := resolveAuthTargetFromConfig()
+	if target == nil {
+		return ""
+	}
+
+	store := authstore.New(log, authstore.Config{
+		AuthClient: authclient.New(log, authclient.Config{
+			IssuerURL: target.issuerURL,
+			ClientID:  target.clientID,
+			Resource:  target.resource,
+		}),
+		IssuerURL: target.issuerURL,
+		ClientID:  target.clientID,
+		Resource:  target.resource,
+		Profile:   credentialProfile(),
+		Backend:   credentialStore(),
+	})
+
+	token, err := store.GetAccessToken()
+	if err != nil {
+		return ""
+	}
+
+	return token
+})
+
 func serverDo(
 	ctx context.Context,
 	method, path string,
@@ -41,10 +81,33 @@ func serverDo(
 	query url.Values,
 	headers map[string]string,
 ) ([]byte, int, http.Header, error) {
-	baseURL, err := serverBaseURL()
+	resp, err := serverOpen(ctx, method, path, body, query, headers)
 	if err != nil {
 		return nil, 0, nil, err
 	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header.Clone(), fmt.Errorf("reading response: %w", err)
+	}
+
+	return data, resp.StatusCode, resp.Header.Clone(), nil
+}
+
+// serverOpen sends a request to the server and returns the response with its
+// body unread. The caller must close the body.
+func serverOpen(
+	ctx context.Context,
+	method, path string,
+	body io.Reader,
+	query url.Values,
+	headers map[string]string,
+) (*http.Response, error) {
+	baseURL, err := serverBaseURL()
+	if err != nil {
+		return nil, err
+	}
 
 	reqURL := strings.TrimRight(baseURL, "/") + path
 	if len(query) > 0 {
@@ -53,7 +116,11 @@ func serverDo(
 
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
-		return nil, 0, nil, fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if token := serverAccessToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	for key, value := range headers {
@@ -63,22 +130,16 @@ func serverDo(
 	resp, err := serverHTTP.Do(req)
 	if err != nil {
 		if isConnectionRefused(err) {
-			return nil, 0, nil, fmt.Errorf(
+			return nil, withExitCode(ExitUnavailable, fmt.Errorf(
 				"server is not running at %s — run 'panda init' or 'panda server start' first",
 				baseURL,
-			)
+			))
 		}
 
-		return nil, 0, nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, resp.StatusCode, resp.Header.Clone(), fmt.Errorf("reading response: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return data, resp.StatusCode, resp.Header.Clone(), nil
+	return resp, nil
 }
 
 func serverGetJSON(ctx context.Context, path string, query url.Values, target any) error {
@@ -223,6 +284,72 @@ func executeCodeRemotely(ctx context.Context, req serverapi.ExecuteRequest) (*se
 	return &response, nil
 }
 
+// errStreamUnsupported is returned by executeCodeStreaming when the server
+// predates the streaming execute endpoint.
+var errStreamUnsupported = errors.New("server does not support streaming execution")
+
+// executeCodeStreaming runs code via the streaming execute endpoint, calling
+// onOutput for each stdout, stderr or queued event as it arrives.
+func executeCodeStreaming(
+	ctx context.Context,
+	req serverapi.ExecuteRequest,
+	onOutput func(serverapi.ExecuteStreamEvent),
+) (*serverapi.ExecuteResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	resp, err := serverOpen(
+		ctx,
+		http.MethodPost,
+		"/api/v1/execute/stream",
+		bytes.NewReader(body),
+		nil,
+		map[string]string{"Content-Type": "application/json"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil, errStreamUnsupported
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		var event serverapi.ExecuteStreamEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("execution stream ended without a result")
+			}
+
+			return nil, fmt.Errorf("reading execution stream: %w", err)
+		}
+
+		switch event.Type {
+		case serverapi.ExecuteEventStdout, serverapi.ExecuteEventStderr, serverapi.ExecuteEventQueued:
+			onOutput(event)
+		case serverapi.ExecuteEventResult:
+			if event.Result == nil {
+				return nil, fmt.Errorf("execution stream sent an empty result")
+			}
+
+			return event.Result, nil
+		case serverapi.ExecuteEventError:
+			return nil, apiError(event.Status, event.Error)
+		}
+	}
+}
+
 func listSessions(ctx context.Context) (*serverapi.ListSessionsResponse, error) {
 	var response serverapi.ListSessionsResponse
 	if err := serverGetJSON(ctx, "/api/v1/sessions", nil, &response); err != nil {
@@ -245,6 +372,117 @@ func destroySession(ctx context.Context, sessionID string) error {
 	return serverDelete(ctx, "/api/v1/sessions/"+url.PathEscape(sessionID))
 }
 
+func setSessionEnv(ctx context.Context, sessionID string, env map[string]string) (*serverapi.SessionEnvResponse, error) {
+	var response serverapi.SessionEnvResponse
+	if err := serverPostJSON(
+		ctx, "/api/v1/sessions/"+url.PathEscape(sessionID)+"/env", serverapi.SetSessionEnvRequest{Env: env}, &response,
+	); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func exportNotebook(ctx context.Context, sessionID string) (*serverapi.ExportNotebookResponse, error) {
+	var response serverapi.ExportNotebookResponse
+	if err := serverPostJSON(
+		ctx, "/api/v1/sessions/"+url.PathEscape(sessionID)+"/notebook", struct{}{}, &response,
+	); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func putSessionFile(ctx context.Context, sessionID, filePath string, data []byte) (*serverapi.SessionFileResponse, error) {
+	body, status, _, err := serverDo(
+		ctx,
+		http.MethodPut,
+		sessionFilePath(sessionID, filePath),
+		bytes.NewReader(data),
+		nil,
+		map[string]string{"Content-Type": "application/octet-stream"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 300 {
+		return nil, decodeAPIError(status, body)
+	}
+
+	var response serverapi.SessionFileResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &response, nil
+}
+
+func getSessionFile(ctx context.Context, sessionID, filePath string) ([]byte, error) {
+	body, status, _, err := serverDo(ctx, http.MethodGet, sessionFilePath(sessionID, filePath), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 300 {
+		return nil, decodeAPIError(status, body)
+	}
+
+	return body, nil
+}
+
+func sessionFilePath(sessionID, filePath string) string {
+	segments := strings.Split(strings.TrimLeft(filePath, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return "/api/v1/sessions/" + url.PathEscape(sessionID) + "/files/" + strings.Join(segments, "/")
+}
+
+func listExecutions(ctx context.Context, sessionID string, limit int) (*serverapi.ListExecutionsResponse, error) {
+	query := url.Values{}
+	if sessionID != "" {
+		query.Set("session_id", sessionID)
+	}
+
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var response serverapi.ListExecutionsResponse
+	if err := serverGetJSON(ctx, "/api/v1/executions", query, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func getExecution(ctx context.Context, executionID string) (*history.Record, error) {
+	var record history.Record
+	if err := serverGetJSON(ctx, "/api/v1/executions/"+url.PathEscape(executionID), nil, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func promoteExecution(
+	ctx context.Context,
+	executionID string,
+	req serverapi.PromoteExampleRequest,
+) (*serverapi.PromoteExampleResponse, error) {
+	var response serverapi.PromoteExampleResponse
+	if err := serverPostJSON(
+		ctx, "/api/v1/executions/"+url.PathEscape(executionID)+"/promote", req, &response,
+	); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
 func searchExamples(ctx context.Context, queryText, category string, limit int) (*serverapi.SearchExamplesResponse, error) {
 	query := url.Values{"query": []string{queryText}}
 	if category != "" {
@@ -262,6 +500,15 @@ func searchExamples(ctx context.Context, queryText, category string, limit int)
 	return &response, nil
 }
 
+func rateExample(ctx context.Context, req serverapi.RateExampleRequest) (*serverapi.RateExampleResponse, error) {
+	var response serverapi.RateExampleResponse
+	if err := serverPostJSON(ctx, "/api/v1/search/examples/rate", req, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
 func searchRunbooks(ctx context.Context, queryText, tag string, limit int) (*serverapi.SearchRunbooksResponse, error) {
 	query := url.Values{"query": []string{queryText}}
 	if tag != "" {
@@ -339,9 +586,10 @@ func readResourceWithClientContext(ctx context.Context, uri, clientContext strin
 	}
 
 	return &serverapi.ResourceResponse{
-		URI:      uri,
-		MIMEType: headers.Get("Content-Type"),
-		Content:  string(data),
+		URI:         uri,
+		MIMEType:    headers.Get("Content-Type"),
+		Content:     string(data),
+		ContentHash: strings.Trim(headers.Get("ETag"), `"`),
 	}, nil
 }
 
@@ -373,6 +621,148 @@ func readClickHouseTable(ctx context.Context, tableName string) (*clickhousemodu
 	return &payload, nil
 }
 
+func moduleHealth(ctx context.Context) (*serverapi.ModuleHealthResponse, error) {
+	var response serverapi.ModuleHealthResponse
+	if err := serverGetJSON(ctx, "/health/modules", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func listAdminModules(ctx context.Context, adminToken string) (*serverapi.ListModulesResponse, error) {
+	var response serverapi.ListModulesResponse
+	if err := serverAdminJSON(ctx, http.MethodGet, "/api/v1/admin/modules", adminToken, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func setModuleDisabled(ctx context.Context, adminToken, name string, disabled bool) (*serverapi.ModuleStatus, error) {
+	action := "enable"
+	if disabled {
+		action = "disable"
+	}
+
+	var response serverapi.ModuleStatus
+	path := "/api/v1/admin/modules/" + url.PathEscape(name) + "/" + action
+	if err := serverAdminJSON(ctx, http.MethodPost, path, adminToken, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func packageCacheStatus(ctx context.Context, adminToken string) (*serverapi.PackageCacheStatusResponse, error) {
+	var response serverapi.PackageCacheStatusResponse
+	if err := serverAdminJSON(ctx, http.MethodGet, "/api/v1/admin/package-cache", adminToken, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func addCachedPackages(ctx context.Context, adminToken string, packages []string) (*serverapi.AddPackagesResponse, error) {
+	var response serverapi.AddPackagesResponse
+	if err := serverAdminJSON(
+		ctx, http.MethodPost, "/api/v1/admin/package-cache", adminToken,
+		serverapi.AddPackagesRequest{Packages: packages}, &response,
+	); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func sandboxImages(ctx context.Context, adminToken string, pull bool) (*serverapi.SandboxImagesResponse, error) {
+	method, path := http.MethodGet, "/api/v1/admin/images"
+	if pull {
+		method, path = http.MethodPost, "/api/v1/admin/images/pull"
+	}
+
+	var response serverapi.SandboxImagesResponse
+	if err := serverAdminJSON(ctx, method, path, adminToken, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func listSchedules(ctx context.Context) (*serverapi.ListSchedulesResponse, error) {
+	var response serverapi.ListSchedulesResponse
+	if err := serverGetJSON(ctx, "/api/v1/schedules", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func listScheduleRuns(ctx context.Context, job string) (*serverapi.ScheduleRunsResponse, error) {
+	var response serverapi.ScheduleRunsResponse
+	if err := serverGetJSON(ctx, "/api/v1/schedules/"+url.PathEscape(job)+"/runs", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func runSchedule(ctx context.Context, adminToken, job string) (*schedule.Run, error) {
+	var run schedule.Run
+	if err := serverAdminJSON(
+		ctx, http.MethodPost, "/api/v1/admin/schedules/"+url.PathEscape(job)+"/run", adminToken, nil, &run,
+	); err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func searchAudit(ctx context.Context, adminToken string, params url.Values) (*serverapi.AuditSearchResponse, error) {
+	path := "/api/v1/admin/audit"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var response serverapi.AuditSearchResponse
+	if err := serverAdminJSON(ctx, http.MethodGet, path, adminToken, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// serverAdminJSON calls an admin API endpoint, sending body as JSON when set.
+func serverAdminJSON(ctx context.Context, method, path, adminToken string, body, target any) error {
+	headers := map[string]string{"Authorization": "Bearer " + adminToken}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+
+		reader = bytes.NewReader(payload)
+		headers["Content-Type"] = "application/json"
+	}
+
+	data, status, _, err := serverDo(ctx, method, path, reader, nil, headers)
+	if err != nil {
+		return err
+	}
+
+	if status < 200 || status >= 300 {
+		return decodeAPIError(status, data)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
 func decodeAPIError(status int, data []byte) error {
 	var message string
 
@@ -387,12 +777,20 @@ func decodeAPIError(status int, data []byte) error {
 		message = strings.TrimSpace(string(data))
 	}
 
+	return apiError(status, message)
+}
+
+// apiError builds the error for a failed server API call, tagged with the
+// exit code for its status.
+func apiError(status int, message string) error {
+	code := exitCodeForStatus(status)
+
 	hint := serverErrorHint(status, message)
 	if hint != "" {
-		return fmt.Errorf("HTTP %d: %s\n\n  hint: %s", status, message, hint)
+		return withExitCode(code, fmt.Errorf("HTTP %d: %s\n\n  hint: %s", status, message, hint))
 	}
 
-	return fmt.Errorf("HTTP %d: %s", status, message)
+	return withExitCode(code, fmt.Errorf("HTTP %d: %s", status, message))
 }
 
 func serverErrorHint(status int, _ string) string {
@@ -3,6 +3,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,7 +23,10 @@ between executions, preserving files in /workspace.
 Examples:
   panda session list
   panda session create
-  panda session destroy <session-id>`,
+  panda session destroy <session-id>
+  panda session put <session-id> data.csv
+  panda session get <session-id> results.parquet
+  panda session env <session-id> DEFAULT_NETWORK=sepolia`,
 }
 
 var sessionListCmd = &cobra.Command{
@@ -40,13 +48,47 @@ var sessionDestroyCmd = &cobra.Command{
 	RunE:  runSessionDestroy,
 }
 
+var sessionPutCmd = &cobra.Command{
+	Use:   "put <session-id> <local-file> [workspace-path]",
+	Short: "Upload a file into a session's /workspace",
+	Long: `Upload a local file into a session's /workspace directory.
+The workspace path defaults to the local file's base name.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runSessionPut,
+}
+
+var sessionGetCmd = &cobra.Command{
+	Use:   "get <session-id> <workspace-path> [local-file]",
+	Short: "Download a file from a session's /workspace",
+	Long: `Download a file from a session's /workspace directory.
+Writes to stdout when no local file is given.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runSessionGet,
+}
+
+var sessionEnvCmd = &cobra.Command{
+	Use:   "env <session-id> NAME=VALUE...",
+	Short: "Set env vars for a session's later executions",
+	Long: `Set non-secret env vars that every later execution in the session
+sees, so scripts need not re-declare the same constants. Names must be on
+the server's sandbox.sessions.env_allowlist. NAME= removes a variable.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runSessionEnv,
+}
+
 func init() {
 	rootCmd.AddCommand(sessionCmd)
 	sessionCmd.AddCommand(sessionListCmd)
 	sessionCmd.AddCommand(sessionCreateCmd)
 	sessionCmd.AddCommand(sessionDestroyCmd)
+	sessionCmd.AddCommand(sessionPutCmd)
+	sessionCmd.AddCommand(sessionGetCmd)
+	sessionCmd.AddCommand(sessionEnvCmd)
 
 	sessionDestroyCmd.ValidArgsFunction = completeSessionIDs
+	sessionPutCmd.ValidArgsFunction = completeSessionIDs
+	sessionGetCmd.ValidArgsFunction = completeSessionIDs
+	sessionEnvCmd.ValidArgsFunction = completeSessionIDs
 }
 
 func runSessionList(_ *cobra.Command, _ []string) error {
@@ -100,9 +142,101 @@ func runSessionDestroy(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("destroying session: %w", err)
 	}
 
-	if !isJSON() {
-		fmt.Printf("Session %s destroyed.\n", args[0])
+	if isJSON() {
+		return printJSON(SessionDestroyOutput{SessionID: args[0], Destroyed: true})
 	}
 
+	fmt.Printf("Session %s destroyed.\n", args[0])
+
+	return nil
+}
+
+func runSessionPut(_ *cobra.Command, args []string) error {
+	sessionID, localPath := args[0], args[1]
+
+	workspacePath := filepath.Base(localPath)
+	if len(args) == 3 {
+		workspacePath = args[2]
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	response, err := putSessionFile(context.Background(), sessionID, workspacePath, data)
+	if err != nil {
+		return fmt.Errorf("uploading file: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	fmt.Printf("Uploaded %s to /workspace/%s (%d bytes).\n", localPath, response.Path, response.Size)
+
+	return nil
+}
+
+func runSessionGet(_ *cobra.Command, args []string) error {
+	sessionID, workspacePath := args[0], args[1]
+
+	data, err := getSessionFile(context.Background(), sessionID, workspacePath)
+	if err != nil {
+		return fmt.Errorf("downloading file: %w", err)
+	}
+
+	if len(args) < 3 {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(args[2], data, 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(SessionFileOutput{
+			SessionID: sessionID,
+			Path:      workspacePath,
+			LocalPath: args[2],
+			Size:      int64(len(data)),
+		})
+	}
+
+	fmt.Printf("Downloaded /workspace/%s to %s (%d bytes).\n", workspacePath, args[2], len(data))
+
+	return nil
+}
+
+func runSessionEnv(_ *cobra.Command, args []string) error {
+	sessionID := args[0]
+	env := make(map[string]string, len(args)-1)
+
+	for _, arg := range args[1:] {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid env var %q: expected NAME=VALUE", arg))
+		}
+
+		env[name] = value
+	}
+
+	response, err := setSessionEnv(context.Background(), sessionID, env)
+	if err != nil {
+		return fmt.Errorf("setting session env: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(response)
+	}
+
+	names := slices.Sorted(maps.Keys(response.Env))
+	for _, name := range names {
+		fmt.Printf("  %s=%s\n", name, response.Env[name])
+	}
+
+	fmt.Printf("Session %s has %d env override(s).\n", sessionID, len(names))
+
 	return nil
 }
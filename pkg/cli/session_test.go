@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionFilePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/api/v1/sessions/abc/files/data.csv", sessionFilePath("abc", "data.csv"))
+	assert.Equal(t, "/api/v1/sessions/abc/files/in/my%20file.csv", sessionFilePath("abc", "/in/my file.csv"))
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinner draws an animated status line with the elapsed time on a terminal.
+// Output written through its writers clears the line first, and the spinner
+// only redraws while the cursor sits at the start of a line, so it never
+// interleaves with partial output.
+type spinner struct {
+	mu        sync.Mutex
+	out       io.Writer
+	label     string
+	start     time.Time
+	frame     int
+	drawn     bool
+	lineStart bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// startSpinner starts a spinner on stderr if it is a terminal. It returns
+// nil otherwise; a nil spinner's methods are no-ops.
+func startSpinner(label string) *spinner {
+	if !isTerminal(os.Stderr) {
+		return nil
+	}
+
+	s := &spinner{
+		out:       os.Stderr,
+		label:     label,
+		start:     time.Now(),
+		lineStart: true,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+func (s *spinner) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		s.drawLocked()
+		s.mu.Unlock()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *spinner) drawLocked() {
+	if !s.lineStart {
+		return
+	}
+
+	elapsed := time.Since(s.start).Truncate(100 * time.Millisecond)
+	fmt.Fprintf(s.out, "\r\033[K%s %s %s", spinnerFrames[s.frame%len(spinnerFrames)], s.label, elapsed)
+	s.frame++
+	s.drawn = true
+}
+
+func (s *spinner) clearLocked() {
+	if s.drawn {
+		fmt.Fprint(s.out, "\r\033[K")
+		s.drawn = false
+	}
+}
+
+// writer returns a writer for w that clears the spinner line before writing.
+func (s *spinner) writer(w io.Writer) io.Writer {
+	if s == nil {
+		return w
+	}
+
+	return spinnerWriter{spinner: s, w: w}
+}
+
+// SetLabel replaces the text shown next to the spinner.
+func (s *spinner) SetLabel(label string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.label = label
+	s.mu.Unlock()
+}
+
+// Stop stops the spinner and clears its line.
+func (s *spinner) Stop() {
+	if s == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.done
+
+	s.mu.Lock()
+	s.clearLocked()
+	s.mu.Unlock()
+}
+
+type spinnerWriter struct {
+	spinner *spinner
+	w       io.Writer
+}
+
+func (sw spinnerWriter) Write(p []byte) (int, error) {
+	sw.spinner.mu.Lock()
+	defer sw.spinner.mu.Unlock()
+
+	sw.spinner.clearLocked()
+
+	n, err := sw.w.Write(p)
+	if n > 0 {
+		sw.spinner.lineStart = p[n-1] == '\n'
+	}
+
+	return n, err
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/panda/internal/version"
+)
+
+var toolsCmd = &cobra.Command{
+	GroupID: groupDirect,
+	Use:     "tools",
+	Short:   "List and call the server's MCP tools",
+	Long: `List the server's MCP tools or call one by name over MCP, printing the
+raw MCP response. Useful for testing tool handlers without wiring up an
+MCP client.
+
+Examples:
+  panda tools list
+  panda tools call search '{"type": "examples", "query": "block size"}'
+  echo '{"code": "print(1)"}' | panda tools call execute_python -`,
+	RunE: runToolsList,
+}
+
+var toolsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List MCP tools with their input schemas",
+	Args:  cobra.NoArgs,
+	RunE:  runToolsList,
+}
+
+var toolsCallCmd = &cobra.Command{
+	Use:   "call <tool> [json-args]",
+	Short: "Call an MCP tool and print the raw response",
+	Long: `Call an MCP tool by name with a JSON object of arguments and print the
+raw CallToolResult. Pass - to read the arguments from stdin. Exits
+nonzero when the tool reports an error.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runToolsCall,
+}
+
+func init() {
+	rootCmd.AddCommand(toolsCmd)
+	toolsCmd.AddCommand(toolsListCmd)
+	toolsCmd.AddCommand(toolsCallCmd)
+}
+
+func runToolsList(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	client, err := connectMCP(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	result, err := client.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return fmt.Errorf("listing tools: %w", err)
+	}
+
+	return printJSON(result)
+}
+
+func runToolsCall(_ *cobra.Command, args []string) error {
+	arguments, err := parseToolArguments(args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	client, err := connectMCP(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = args[0]
+	request.Params.Arguments = arguments
+
+	result, err := client.CallTool(ctx, request)
+	if err != nil {
+		return fmt.Errorf("calling tool %s: %w", args[0], err)
+	}
+
+	if err := printJSON(result); err != nil {
+		return err
+	}
+
+	if result.IsError {
+		return fmt.Errorf("tool %s returned an error", args[0])
+	}
+
+	return nil
+}
+
+// parseToolArguments decodes the optional JSON object argument, reading it
+// from stdin when it is "-".
+func parseToolArguments(args []string) (map[string]any, error) {
+	if len(args) == 0 {
+		return map[string]any{}, nil
+	}
+
+	raw := []byte(args[0])
+
+	if args[0] == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+
+		raw = data
+	}
+
+	var arguments map[string]any
+	if err := json.Unmarshal(raw, &arguments); err != nil {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("arguments must be a JSON object: %w", err))
+	}
+
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+
+	return arguments, nil
+}
+
+// connectMCP opens an initialized MCP session with the server's
+// streamable HTTP endpoint. The caller must close the client.
+func connectMCP(ctx context.Context) (*mcpclient.Client, error) {
+	baseURL, err := serverBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mcpclient.NewStreamableHttpClient(baseURL + "/mcp")
+	if err != nil {
+		return nil, fmt.Errorf("creating MCP client: %w", err)
+	}
+
+	if err := client.Start(ctx); err != nil {
+		return nil, fmt.Errorf("starting MCP client: %w", err)
+	}
+
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "panda-cli", Version: version.Version}
+
+	if _, err := client.Initialize(ctx, request); err != nil {
+		_ = client.Close()
+
+		if isConnectionRefused(err) {
+			return nil, withExitCode(ExitUnavailable, fmt.Errorf(
+				"server is not running at %s — run 'panda init' or 'panda server start' first",
+				baseURL,
+			))
+		}
+
+		return nil, fmt.Errorf("initializing MCP session: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolArguments(t *testing.T) {
+	t.Parallel()
+
+	arguments, err := parseToolArguments(nil)
+	require.NoError(t, err)
+	assert.Empty(t, arguments)
+
+	arguments, err = parseToolArguments([]string{`{"query": "blocks", "limit": 3}`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "blocks", "limit": float64(3)}, arguments)
+
+	_, err = parseToolArguments([]string{`["not", "an", "object"]`})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, exitCodeFor(err))
+}
+
+func TestConnectMCP(t *testing.T) {
+	srv := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithResourceCapabilities(false, false))
+	srv.AddTool(mcp.NewTool("echo", mcp.WithString("text")), func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(req.GetString("text", "")), nil
+	})
+	srv.AddResource(mcp.NewResource("panda://test", "test"), func(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, Text: "hello"}}, nil
+	})
+
+	streamable := mcpserver.NewStreamableHTTPServer(srv)
+
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/mcp", r.URL.Path)
+		streamable.ServeHTTP(w, r)
+	})
+
+	ctx := context.Background()
+
+	client, err := connectMCP(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	call := mcp.CallToolRequest{}
+	call.Params.Name = "echo"
+	call.Params.Arguments = map[string]any{"text": "hi"}
+
+	result, err := client.CallTool(ctx, call)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "hi", result.Content[0].(mcp.TextContent).Text)
+
+	read := mcp.ReadResourceRequest{}
+	read.Params.URI = "panda://test"
+
+	resource, err := client.ReadResource(ctx, read)
+	require.NoError(t, err)
+	require.Len(t, resource.Contents, 1)
+	assert.Equal(t, "hello", resource.Contents[0].(mcp.TextResourceContents).Text)
+}
@@ -65,6 +65,12 @@ func init() {
 }
 
 func runUpgrade(_ *cobra.Command, _ []string) error {
+	return runWithJSONResult(upgrade)
+}
+
+// upgrade checks for a newer release and upgrades the CLI and/or server
+// according to the scope flags.
+func upgrade() (*UpgradeOutput, error) {
 	checker := github.NewReleaseChecker(github.RepoOwner, github.RepoName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -74,12 +80,20 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 
 	release, err := checker.LatestRelease(ctx)
 	if err != nil {
-		return fmt.Errorf("checking for updates: %w", err)
+		return nil, fmt.Errorf("checking for updates: %w", err)
+	}
+
+	output := &UpgradeOutput{
+		CurrentVersion: version.Version,
+		LatestVersion:  release.TagName,
 	}
 
 	if !version.IsNewer(version.Version, release.TagName) {
 		fmt.Printf("Already up to date (%s).\n", version.Version)
-		return nil
+
+		output.UpToDate = true
+
+		return output, nil
 	}
 
 	doCLI := !upgradeServerOnly
@@ -105,7 +119,7 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 	if !upgradeYes {
 		if !promptConfirm("Proceed?") {
 			fmt.Println("Upgrade cancelled.")
-			return nil
+			return output, nil
 		}
 
 		fmt.Println()
@@ -113,8 +127,10 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 
 	if doCLI {
 		if err := downloadAndReplaceBinary(release); err != nil {
-			return fmt.Errorf("upgrading CLI: %w", err)
+			return nil, fmt.Errorf("upgrading CLI: %w", err)
 		}
+
+		output.CLIUpgraded = true
 	}
 
 	if doServer {
@@ -125,19 +141,23 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 		// new version added (e.g. group_add for Docker socket perms).
 		if doCLI {
 			if err := execNewBinary("server", "update"); err != nil {
-				return fmt.Errorf("upgrading server: %w", err)
+				return nil, fmt.Errorf("upgrading server: %w", err)
 			}
 		} else {
 			if err := upgradeServer(); err != nil {
-				return fmt.Errorf("upgrading server: %w", err)
+				return nil, fmt.Errorf("upgrading server: %w", err)
 			}
 		}
+
+		output.ServerUpdated = true
 	}
 
 	fmt.Println()
 	fmt.Println("Upgrade complete!")
 
-	return nil
+	output.Upgraded = true
+
+	return output, nil
 }
 
 // downloadAndReplaceBinary downloads the latest CLI binary from a GitHub
@@ -13,14 +13,12 @@ var versionCmd = &cobra.Command{
 	Use:     "version",
 	Short:   "Print version information",
 	RunE: func(_ *cobra.Command, _ []string) error {
-		info := map[string]string{
-			"version":    version.Version,
-			"git_commit": version.GitCommit,
-			"build_time": version.BuildTime,
-		}
-
 		if isJSON() {
-			return printJSON(info)
+			return printJSON(VersionOutput{
+				Version:   version.Version,
+				GitCommit: version.GitCommit,
+				BuildTime: version.BuildTime,
+			})
 		}
 
 		fmt.Printf("panda version %s (commit: %s, built: %s)\n",
@@ -0,0 +1,155 @@
+// Package clientstate holds per-client state for MCP callers. State is
+// scoped by authenticated identity, or by MCP session without auth, so
+// clients sharing one HTTP deployment never observe or evict each other's
+// entries.
+package clientstate
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxClients bounds how many identities are tracked at once.
+	DefaultMaxClients = 1000
+	// DefaultMaxKeys bounds how many keys one identity may mark.
+	DefaultMaxKeys = 1000
+	// DefaultIdleTTL is how long an identity's state survives without use.
+	DefaultIdleTTL = 4 * time.Hour
+)
+
+// Config bounds a Store. Zero values use the defaults.
+type Config struct {
+	MaxClients int
+	MaxKeys    int
+	IdleTTL    time.Duration
+}
+
+// Store tracks keys marked per identity. Identities are opaque strings.
+type Store struct {
+	mu      sync.Mutex
+	cfg     Config
+	now     func() time.Time
+	clients map[string]*client
+}
+
+type client struct {
+	lastSeen time.Time
+	keys     map[string]time.Time
+}
+
+// New creates a Store.
+func New(cfg Config) *Store {
+	if cfg.MaxClients <= 0 {
+		cfg.MaxClients = DefaultMaxClients
+	}
+
+	if cfg.MaxKeys <= 0 {
+		cfg.MaxKeys = DefaultMaxKeys
+	}
+
+	if cfg.IdleTTL <= 0 {
+		cfg.IdleTTL = DefaultIdleTTL
+	}
+
+	return &Store{
+		cfg:     cfg,
+		now:     time.Now,
+		clients: make(map[string]*client, 16),
+	}
+}
+
+// MarkOnce records key for identity and reports whether this is the first
+// time it was marked.
+func (s *Store) MarkOnce(identity, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	c, ok := s.clients[identity]
+	if !ok {
+		if len(s.clients) >= s.cfg.MaxClients {
+			s.evictClientsLocked(now)
+		}
+
+		c = &client{keys: make(map[string]time.Time, 4)}
+		s.clients[identity] = c
+	}
+
+	c.lastSeen = now
+
+	if _, marked := c.keys[key]; marked {
+		return false
+	}
+
+	if len(c.keys) >= s.cfg.MaxKeys {
+		evictOldest(c.keys, now.Add(-s.cfg.IdleTTL))
+	}
+
+	c.keys[key] = now
+
+	return true
+}
+
+// Forget drops all state held for identity.
+func (s *Store) Forget(identity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clients, identity)
+}
+
+// Len returns the number of identities with state.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// evictClientsLocked drops idle identities, or the least recently seen one
+// when none is idle.
+func (s *Store) evictClientsLocked(now time.Time) {
+	lastSeen := make(map[string]time.Time, len(s.clients))
+	for identity, c := range s.clients {
+		lastSeen[identity] = c.lastSeen
+	}
+
+	for _, identity := range evictOldest(lastSeen, now.Add(-s.cfg.IdleTTL)) {
+		delete(s.clients, identity)
+	}
+}
+
+// evictOldest deletes entries older than cutoff from m, or its oldest
+// entry when none is, and returns the deleted keys.
+func evictOldest(m map[string]time.Time, cutoff time.Time) []string {
+	var (
+		evicted []string
+		oldest  string
+		oldestT time.Time
+		found   bool
+	)
+
+	for key, t := range m {
+		if t.Before(cutoff) {
+			evicted = append(evicted, key)
+
+			continue
+		}
+
+		if !found || t.Before(oldestT) {
+			oldest, oldestT, found = key, t, true
+		}
+	}
+
+	if len(evicted) == 0 && found {
+		evicted = append(evicted, oldest)
+	}
+
+	for _, key := range evicted {
+		delete(m, key)
+	}
+
+	return evicted
+}
@@ -0,0 +1,82 @@
+package clientstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(cfg Config) (*Store, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New(cfg)
+	s.now = func() time.Time { return now }
+
+	return s, &now
+}
+
+func TestMarkOnceIsScopedByIdentity(t *testing.T) {
+	s, _ := newTestStore(Config{})
+
+	assert.True(t, s.MarkOnce("alice", "session-1"))
+	assert.False(t, s.MarkOnce("alice", "session-1"))
+	assert.True(t, s.MarkOnce("bob", "session-1"), "another identity has its own state")
+	assert.True(t, s.MarkOnce("", "session-1"), "the anonymous identity is separate")
+	assert.Equal(t, 3, s.Len())
+}
+
+func TestForget(t *testing.T) {
+	s, _ := newTestStore(Config{})
+
+	s.MarkOnce("alice", "session-1")
+	s.Forget("alice")
+
+	assert.Equal(t, 0, s.Len())
+	assert.True(t, s.MarkOnce("alice", "session-1"))
+}
+
+func TestEvictsIdleClientsFirst(t *testing.T) {
+	s, now := newTestStore(Config{MaxClients: 2, IdleTTL: time.Hour})
+
+	s.MarkOnce("idle", "k")
+	*now = now.Add(50 * time.Minute)
+	s.MarkOnce("active", "k")
+	*now = now.Add(20 * time.Minute)
+
+	s.MarkOnce("new", "k")
+
+	assert.Equal(t, 2, s.Len())
+	assert.False(t, s.MarkOnce("active", "k"), "active client kept its state")
+	assert.True(t, s.MarkOnce("idle", "k"), "idle client was evicted")
+}
+
+func TestEvictsLeastRecentlySeenClientWhenFull(t *testing.T) {
+	s, now := newTestStore(Config{MaxClients: 2, IdleTTL: time.Hour})
+
+	s.MarkOnce("a", "k")
+	*now = now.Add(time.Minute)
+	s.MarkOnce("b", "k")
+	*now = now.Add(time.Minute)
+	s.MarkOnce("a", "other")
+	*now = now.Add(time.Minute)
+
+	s.MarkOnce("c", "k")
+
+	assert.Equal(t, 2, s.Len())
+	assert.False(t, s.MarkOnce("a", "k"))
+	assert.True(t, s.MarkOnce("b", "k"), "least recently seen client was evicted")
+}
+
+func TestBoundsKeysPerClient(t *testing.T) {
+	s, now := newTestStore(Config{MaxKeys: 2})
+
+	s.MarkOnce("alice", "k1")
+	*now = now.Add(time.Minute)
+	s.MarkOnce("alice", "k2")
+	*now = now.Add(time.Minute)
+	s.MarkOnce("alice", "k3")
+
+	assert.False(t, s.MarkOnce("alice", "k3"))
+	assert.False(t, s.MarkOnce("alice", "k2"))
+	assert.True(t, s.MarkOnce("alice", "k1"), "oldest key was evicted")
+}
@@ -3,30 +3,485 @@ package config
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/ethpandaops/panda/pkg/auth"
+	authstore "github.com/ethpandaops/panda/pkg/auth/store"
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/config/secrets"
 	"github.com/ethpandaops/panda/pkg/configpath"
+	"github.com/ethpandaops/panda/pkg/cronexpr"
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // Config is the main configuration structure.
 type Config struct {
-	Server        ServerConfig        `yaml:"server"`
-	Sandbox       SandboxConfig       `yaml:"sandbox"`
-	Proxy         ProxyConfig         `yaml:"proxy"`
-	Storage       StorageConfig       `yaml:"storage"`
-	Observability ObservabilityConfig `yaml:"observability"`
+	Server         ServerConfig           `yaml:"server"`
+	Sandbox        SandboxConfig          `yaml:"sandbox"`
+	Proxy          ProxyConfig            `yaml:"proxy"`
+	Storage        StorageConfig          `yaml:"storage"`
+	History        HistoryConfig          `yaml:"history"`
+	Offline        OfflineConfig          `yaml:"offline"`
+	Observability  ObservabilityConfig    `yaml:"observability"`
+	Auth           AuthConfig             `yaml:"auth"`
+	UserExamples   UserExamplesConfig     `yaml:"user_examples"`
+	ExampleUsage   ExampleUsageConfig     `yaml:"example_usage"`
+	SemanticSearch SemanticSearchConfig   `yaml:"semantic_search"`
+	Runbooks       RunbooksConfig         `yaml:"runbooks"`
+	SchemaSamples  types.SampleRowsConfig `yaml:"schema_samples"`
+	Networks       NetworksConfig         `yaml:"networks"`
+	SavedQueries   SavedQueriesConfig     `yaml:"saved_queries"`
+	Schedules      SchedulesConfig        `yaml:"schedules"`
+	Webhooks       WebhooksConfig         `yaml:"webhooks"`
+
+	// Secrets configures the Vault and Kubernetes stores ${vault:...} and
+	// ${k8s:...} references are read from at startup.
+	Secrets secrets.Config `yaml:"secrets,omitempty"`
 
 	path string `yaml:"-"`
 }
 
+// Semantic search embedding backends.
+const (
+	// EmbeddingBackendProxy embeds through the credential proxy.
+	EmbeddingBackendProxy = "proxy"
+	// EmbeddingBackendOpenAI calls an OpenAI-compatible embeddings API.
+	EmbeddingBackendOpenAI = "openai"
+)
+
+// Proxy modes.
+const (
+	// ProxyModeRemote connects to a proxy running as its own process.
+	ProxyModeRemote = "remote"
+	// ProxyModeEmbedded runs the proxy inside the server process.
+	ProxyModeEmbedded = "embedded"
+)
+
+// SemanticSearchConfig selects how the search tool embeds text.
+type SemanticSearchConfig struct {
+	// Backend is "proxy" (default) or "openai".
+	Backend string `yaml:"backend,omitempty"`
+
+	// OpenAI configures the "openai" backend.
+	OpenAI OpenAIEmbeddingConfig `yaml:"openai"`
+}
+
+// OpenAIEmbeddingConfig configures an OpenAI-compatible embeddings API,
+// such as OpenAI itself, OpenRouter or a local Ollama server.
+type OpenAIEmbeddingConfig struct {
+	// URL is the API base URL (default: https://api.openai.com/v1).
+	URL string `yaml:"url,omitempty"`
+
+	// Model is the embedding model (default: text-embedding-3-small).
+	Model string `yaml:"model,omitempty"`
+
+	// APIKeyEnv names the env var holding the API key (default:
+	// OPENAI_API_KEY). The key is optional for servers that need none.
+	APIKeyEnv string `yaml:"api_key_env,omitempty"`
+
+	// Dimensions requests shorter vectors from models that support it.
+	Dimensions int `yaml:"dimensions,omitempty"`
+}
+
+// NetworksConfig controls how network data from cartographoor is cached and
+// adjusted.
+type NetworksConfig struct {
+	// CacheTTL is how often network data is refreshed. Defaults to 5m.
+	CacheTTL time.Duration `yaml:"cache_ttl,omitempty"`
+
+	// MaxStale is how old cached data may be and still be served at startup
+	// while it is refreshed. Defaults to 24h.
+	MaxStale time.Duration `yaml:"max_stale,omitempty"`
+
+	// CacheDir is where fetched network data is cached across restarts.
+	// Defaults to ~/.panda/data/cartographoor.
+	CacheDir string `yaml:"cache_dir,omitempty"`
+
+	// Overrides add networks cartographoor does not list, such as internal
+	// devnets, or change fields of ones it does.
+	Overrides []cartographoor.NetworkOverride `yaml:"overrides,omitempty"`
+
+	// Hide lists glob patterns of network names to leave out, e.g.
+	// deprecated devnets.
+	Hide []string `yaml:"hide,omitempty"`
+}
+
+// SavedQueriesConfig holds operator-curated queries by module.
+type SavedQueriesConfig struct {
+	// Loki queries are listed by the loki://queries resource.
+	Loki []types.SavedQuery `yaml:"loki,omitempty"`
+}
+
+// ForModule returns the saved queries of the named module.
+func (c SavedQueriesConfig) ForModule(name string) []types.SavedQuery {
+	switch name {
+	case "loki":
+		return c.Loki
+	default:
+		return nil
+	}
+}
+
+// validateSavedQueries checks that queries are named uniquely and not empty.
+func validateSavedQueries(field string, queries []types.SavedQuery) error {
+	seen := make(map[string]struct{}, len(queries))
+
+	for i, q := range queries {
+		if q.Name == "" {
+			return fmt.Errorf("%s[%d].name is required", field, i)
+		}
+
+		if strings.TrimSpace(q.Query) == "" {
+			return fmt.Errorf("%s[%d].query is required", field, i)
+		}
+
+		if _, dup := seen[q.Name]; dup {
+			return fmt.Errorf("%s[%d].name %q is used more than once", field, i, q.Name)
+		}
+
+		seen[q.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// SchedulesConfig holds configuration for executions run on a cron schedule.
+type SchedulesConfig struct {
+	// Enabled starts the scheduler. Defaults to false.
+	Enabled bool `yaml:"enabled"`
+
+	// KeepRuns is how many recent runs per job are kept for the schedule://
+	// resources. Defaults to 20.
+	KeepRuns int `yaml:"keep_runs,omitempty"`
+
+	// Jobs are the scheduled executions.
+	Jobs []ScheduledJobConfig `yaml:"jobs,omitempty"`
+}
+
+// ScheduledJobConfig is Python run on a cron schedule. Exactly one of Code,
+// File and Runbook is set.
+type ScheduledJobConfig struct {
+	// Name identifies the job in results and storage paths.
+	Name string `yaml:"name"`
+
+	// Cron is a five-field cron expression evaluated in UTC, or a shorthand
+	// such as @daily.
+	Cron string `yaml:"cron"`
+
+	// Code is the Python to run.
+	Code string `yaml:"code,omitempty"`
+
+	// File is a Python file to run, read on every run. Relative paths are
+	// relative to the config file.
+	File string `yaml:"file,omitempty"`
+
+	// Runbook names a runbook whose snippets are run in order as one script.
+	Runbook string `yaml:"runbook,omitempty"`
+
+	// Vars fill {key} placeholders in the code, such as {network} in runbooks.
+	Vars map[string]string `yaml:"vars,omitempty"`
+
+	// Timeout is the execution timeout in seconds. Defaults to sandbox.timeout.
+	Timeout int `yaml:"timeout,omitempty"`
+
+	// Profile names a sandbox profile. Scheduled runs have no caller, so
+	// the profile must not set allowed_groups.
+	Profile string `yaml:"profile,omitempty"`
+}
+
+// scheduledJobNamePattern restricts job names to what is safe in storage paths.
+var scheduledJobNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Validate checks that jobs are named uniquely, parse, and have one source.
+func (c SchedulesConfig) Validate(profiles map[string]ExecutionProfile) error {
+	if c.KeepRuns < 0 {
+		return errors.New("keep_runs cannot be negative")
+	}
+
+	seen := make(map[string]struct{}, len(c.Jobs))
+
+	for i, job := range c.Jobs {
+		if !scheduledJobNamePattern.MatchString(job.Name) {
+			return fmt.Errorf("jobs[%d].name %q must be letters, digits, '-' or '_'", i, job.Name)
+		}
+
+		if _, dup := seen[job.Name]; dup {
+			return fmt.Errorf("jobs[%d].name %q is used more than once", i, job.Name)
+		}
+
+		seen[job.Name] = struct{}{}
+
+		if _, err := cronexpr.Parse(job.Cron); err != nil {
+			return fmt.Errorf("job %q: %w", job.Name, err)
+		}
+
+		sources := 0
+
+		for _, source := range []string{job.Code, job.File, job.Runbook} {
+			if strings.TrimSpace(source) != "" {
+				sources++
+			}
+		}
+
+		if sources != 1 {
+			return fmt.Errorf("job %q must set exactly one of code, file and runbook", job.Name)
+		}
+
+		if job.Timeout < 0 || job.Timeout > MaxSandboxTimeout {
+			return fmt.Errorf("job %q: timeout must be between 0 and %d seconds", job.Name, MaxSandboxTimeout)
+		}
+
+		if job.Profile != "" {
+			profile, ok := profiles[job.Profile]
+			if !ok {
+				return fmt.Errorf("job %q: unknown sandbox profile %q", job.Name, job.Profile)
+			}
+
+			if len(profile.AllowedGroups) > 0 {
+				return fmt.Errorf("job %q: profile %q sets allowed_groups, which scheduled runs can't satisfy", job.Name, job.Profile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Webhook events endpoints can subscribe to.
+const (
+	// WebhookEventModuleHealth is sent when a module's health status changes.
+	WebhookEventModuleHealth = "module_health"
+	// WebhookEventScheduleFailure is sent when a scheduled run fails or errors.
+	WebhookEventScheduleFailure = "schedule_failure"
+	// WebhookEventLongExecution is sent when an execution is still running
+	// after webhooks.long_execution_threshold.
+	WebhookEventLongExecution = "long_execution"
+)
+
+// Webhook payload formats.
+const (
+	// WebhookFormatGeneric posts the event as JSON, or the rendered template as is.
+	WebhookFormatGeneric = "generic"
+	// WebhookFormatSlack posts {"text": ...} for Slack incoming webhooks.
+	WebhookFormatSlack = "slack"
+	// WebhookFormatDiscord posts {"content": ...} for Discord webhooks.
+	WebhookFormatDiscord = "discord"
+)
+
+// WebhooksConfig holds configuration for webhook notifications.
+type WebhooksConfig struct {
+	// Endpoints receive events. Without any, nothing is sent.
+	Endpoints []WebhookEndpointConfig `yaml:"endpoints,omitempty"`
+
+	// LongExecutionThreshold sends long_execution for executions still
+	// running after this long. 0 disables the event.
+	LongExecutionThreshold time.Duration `yaml:"long_execution_threshold,omitempty"`
+
+	// Attempts is how many times a delivery is tried. Defaults to 3.
+	Attempts int `yaml:"attempts,omitempty"`
+
+	// Backoff is the wait before the first retry, doubled after each
+	// further attempt. Defaults to 2s.
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+
+	// Timeout bounds each request. Defaults to 10s.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// WebhookEndpointConfig is one webhook receiver.
+type WebhookEndpointConfig struct {
+	// Name identifies the endpoint in logs and metrics.
+	Name string `yaml:"name"`
+
+	// URL receives a POST per event.
+	URL string `yaml:"url"`
+
+	// Format is "generic" (default), "slack" or "discord".
+	Format string `yaml:"format,omitempty"`
+
+	// Events limits the events sent. Empty sends every event.
+	Events []string `yaml:"events,omitempty"`
+
+	// Template is a Go text/template rendered with the event. For Slack
+	// and Discord it replaces the message text; for generic endpoints it
+	// is the whole request body.
+	Template string `yaml:"template,omitempty"`
+
+	// Headers are added to every request, e.g. an Authorization header.
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// webhookEvents lists the events endpoints can subscribe to.
+var webhookEvents = []string{WebhookEventModuleHealth, WebhookEventScheduleFailure, WebhookEventLongExecution}
+
+// ParseTemplate parses the endpoint's template, returning nil when it has
+// none. The "json" function encodes a value as JSON, for building generic
+// bodies.
+func (c WebhookEndpointConfig) ParseTemplate() (*template.Template, error) {
+	if c.Template == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(c.Name).Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+
+			return string(data), err
+		},
+	}).Option("missingkey=zero").Parse(c.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Validate checks that endpoints are named uniquely and their URLs,
+// formats, events and templates are valid.
+func (c WebhooksConfig) Validate() error {
+	if c.LongExecutionThreshold < 0 {
+		return errors.New("long_execution_threshold cannot be negative")
+	}
+
+	if c.Attempts < 0 {
+		return errors.New("attempts cannot be negative")
+	}
+
+	if c.Backoff < 0 {
+		return errors.New("backoff cannot be negative")
+	}
+
+	if c.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	seen := make(map[string]struct{}, len(c.Endpoints))
+
+	for i, endpoint := range c.Endpoints {
+		if endpoint.Name == "" {
+			return fmt.Errorf("endpoints[%d].name is required", i)
+		}
+
+		if _, dup := seen[endpoint.Name]; dup {
+			return fmt.Errorf("endpoints[%d].name %q is used more than once", i, endpoint.Name)
+		}
+
+		seen[endpoint.Name] = struct{}{}
+
+		if !strings.HasPrefix(endpoint.URL, "http://") && !strings.HasPrefix(endpoint.URL, "https://") {
+			return fmt.Errorf("endpoint %q: url must be an http(s) URL", endpoint.Name)
+		}
+
+		switch endpoint.Format {
+		case "", WebhookFormatGeneric, WebhookFormatSlack, WebhookFormatDiscord:
+		default:
+			return fmt.Errorf("endpoint %q: format must be generic, slack or discord", endpoint.Name)
+		}
+
+		for _, event := range endpoint.Events {
+			if !slices.Contains(webhookEvents, event) {
+				return fmt.Errorf("endpoint %q: unknown event %q (want one of %s)",
+					endpoint.Name, event, strings.Join(webhookEvents, ", "))
+			}
+		}
+
+		if _, err := endpoint.ParseTemplate(); err != nil {
+			return fmt.Errorf("endpoint %q: %w", endpoint.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// AuthConfig holds server-wide authorization settings.
+type AuthConfig struct {
+	// Bearer validates the proxy-issued bearer tokens clients send to the
+	// MCP and API routes and attaches their user to each request. Tool
+	// policies and datasource scopes need it, since without it no caller is
+	// authenticated.
+	Bearer *auth.BearerConfig `yaml:"bearer,omitempty"`
+
+	// PolicyEngine consults an external engine such as OPA for every tool
+	// call and resource read, after the tool policies.
+	PolicyEngine *auth.PolicyEngineConfig `yaml:"policy_engine,omitempty"`
+}
+
+// UserExamplesConfig holds configuration for query examples promoted from
+// successful executions.
+type UserExamplesConfig struct {
+	// Enabled allows executions to be promoted to search examples. Defaults to false.
+	Enabled bool `yaml:"enabled"`
+
+	// Dir holds the promoted examples as examples.yaml.
+	// Defaults to ~/.panda/data/examples.
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// ExampleUsageConfig holds configuration for ranking search examples by how
+// often they were actually used.
+type ExampleUsageConfig struct {
+	// Enabled records example usage and blends it into example ranking.
+	// Defaults to false.
+	Enabled bool `yaml:"enabled"`
+
+	// Dir holds the usage stats as usage.json.
+	// Defaults to ~/.panda/data/example-usage.
+	Dir string `yaml:"dir,omitempty"`
+
+	// Weight is how much usage can add to an example's similarity score,
+	// from 0 to 1. Defaults to 0.1.
+	Weight float64 `yaml:"weight,omitempty"`
+}
+
+// RunbooksConfig holds configuration for runbooks maintained outside this
+// repository, such as a team's private runbooks.
+type RunbooksConfig struct {
+	// Sources are indexed alongside the built-in runbooks and refreshed
+	// while the server runs.
+	Sources []RunbookSourceConfig `yaml:"sources,omitempty"`
+
+	// CheckoutDir holds the clones of git sources.
+	// Defaults to ~/.panda/data/runbooks.
+	CheckoutDir string `yaml:"checkout_dir,omitempty"`
+}
+
+// RunbookSourceConfig is a directory or git repository of runbook markdown
+// files. Exactly one of Dir and Git is set.
+type RunbookSourceConfig struct {
+	// Name identifies the source in search results.
+	Name string `yaml:"name"`
+
+	// Dir is a local directory of runbooks.
+	Dir string `yaml:"dir,omitempty"`
+
+	// Git is a repository URL to clone.
+	Git string `yaml:"git,omitempty"`
+
+	// Ref is the branch or tag to check out. Defaults to the remote's HEAD.
+	Ref string `yaml:"ref,omitempty"`
+
+	// Path is the runbook directory within the repository. Defaults to the root.
+	Path string `yaml:"path,omitempty"`
+
+	// RefreshInterval is how often the source is re-read or pulled.
+	// Defaults to 15m.
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+}
+
 // StorageConfig holds configuration for local file storage.
 type StorageConfig struct {
 	// BaseDir is the directory where uploaded files are stored.
@@ -36,6 +491,95 @@ type StorageConfig struct {
 	// CacheDir is the directory for the local embedding vector cache.
 	// Defaults to a "cache" sibling of BaseDir.
 	CacheDir string `yaml:"cache_dir,omitempty"`
+
+	// Retention periodically deletes old uploads.
+	Retention StorageRetentionConfig `yaml:"retention,omitempty"`
+
+	// Uploads configures the presigned URLs manage_session put_file hands
+	// out for files too large to send inline.
+	Uploads StorageUploadsConfig `yaml:"uploads,omitempty"`
+}
+
+// StorageUploadsConfig holds configuration for presigned session uploads.
+type StorageUploadsConfig struct {
+	// URLTTL is how long an upload URL stays valid. Defaults to 15m.
+	URLTTL time.Duration `yaml:"url_ttl,omitempty"`
+
+	// SigningKey signs upload URLs. Defaults to a random key per process,
+	// so set it when several server replicas share one base URL.
+	SigningKey string `yaml:"signing_key,omitempty"`
+}
+
+// StorageRetentionConfig holds configuration for upload garbage collection.
+type StorageRetentionConfig struct {
+	// Enabled turns on the background sweep.
+	Enabled bool `yaml:"enabled"`
+
+	// TTL is how long uploads are kept. Defaults to 720h (30 days).
+	TTL time.Duration `yaml:"ttl,omitempty"`
+
+	// Interval is the time between sweeps. Defaults to 1h.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// DryRun logs and counts what would be deleted without deleting it.
+	DryRun bool `yaml:"dry_run,omitempty"`
+
+	// Exclude lists top-level storage directories that are never swept.
+	// Defaults to ["history"], which the history export manages itself.
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// HistoryConfig holds configuration for the execution history log.
+type HistoryConfig struct {
+	// Enabled controls whether executions are recorded. Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Path is the execution log file.
+	// Defaults to ~/.panda/data/history/executions.jsonl.
+	Path string `yaml:"path,omitempty"`
+
+	// MaxEntries is the number of most recent executions kept. Defaults to 10000.
+	MaxEntries int `yaml:"max_entries,omitempty"`
+
+	// Export periodically writes history to day-partitioned Parquet files in storage.
+	Export HistoryExportConfig `yaml:"export,omitempty"`
+}
+
+// HistoryExportConfig holds configuration for the Parquet history export.
+type HistoryExportConfig struct {
+	// Enabled turns on the export. Requires history to be enabled.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the time between exports. Defaults to 1h.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// RetentionDays is how many days of partitions are kept. Defaults to 90.
+	RetentionDays int `yaml:"retention_days,omitempty"`
+
+	// IncludeCode adds the executed code to exported rows. Storage files are
+	// served without authentication, so only the code hash is exported by default.
+	IncludeCode bool `yaml:"include_code,omitempty"`
+}
+
+// IsEnabled returns whether execution history is enabled (defaults to true).
+func (c *HistoryConfig) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
+
+// OfflineConfig holds configuration for offline mode.
+type OfflineConfig struct {
+	// Enabled makes the server serve snapshotted data only, without any
+	// outbound calls. Datasource operations are rejected.
+	Enabled bool `yaml:"enabled"`
+
+	// SnapshotDir is where proxy discovery, network and schema snapshots are
+	// written while online and read from while offline.
+	// Defaults to ~/.panda/data/offline.
+	SnapshotDir string `yaml:"snapshot_dir,omitempty"`
 }
 
 // ServerConfig holds server-specific configuration.
@@ -46,9 +590,68 @@ type ServerConfig struct {
 	SandboxURL string `yaml:"sandbox_url,omitempty"`
 	URL        string `yaml:"url,omitempty"`
 
+	// AdminToken guards the /api/v1/admin endpoints. The admin API is
+	// disabled when empty.
+	AdminToken string `yaml:"admin_token,omitempty"`
+
+	// AdminOrgs are the GitHub orgs or OIDC groups allowed to read the
+	// audit://recent and audit://search resources. The resources are not
+	// registered when empty.
+	AdminOrgs []string `yaml:"admin_orgs,omitempty"`
+
+	// ToolPolicies restrict MCP tools to authenticated users or to members of
+	// specific orgs. Tools without a rule are open to everyone.
+	ToolPolicies []auth.ToolRule `yaml:"tool_policies,omitempty"`
+
+	// DatasourceScopes map user groups to the datasources they may see and
+	// query. Without scopes every user sees every datasource; with scopes,
+	// users in no listed group see none. Requires Auth.Bearer.
+	DatasourceScopes []auth.DatasourceScope `yaml:"datasource_scopes,omitempty"`
+
+	// HealthProbes controls the per-module upstream probes behind /health/modules.
+	HealthProbes HealthProbesConfig `yaml:"health_probes,omitempty"`
+
+	// TLS serves the MCP and HTTP API over TLS. Setting client_ca_file
+	// requires client certificates (mTLS), except on health checks, the
+	// sandbox runtime API and public storage files.
+	TLS tlsconfig.ServerConfig `yaml:"tls,omitempty"`
+
+	// Drain controls graceful shutdown on SIGTERM.
+	Drain DrainConfig `yaml:"drain,omitempty"`
+
+	// Stdio also serves MCP over the process's stdin and stdout, alongside
+	// the HTTP transports, so a local agent that launches the server shares
+	// its sessions, caches and sandbox with remote clients. The server shuts
+	// down when the stdio client disconnects.
+	Stdio bool `yaml:"stdio,omitempty"`
+
 	// Deprecated: Transport is accepted for backwards compatibility but ignored.
-	// The server always runs HTTP with both SSE and streamable-http transports.
-	Transport string `yaml:"transport,omitempty"`
+	// The server always runs HTTP with both SSE and streamable-http transports;
+	// set Stdio to serve stdio as well.
+	Transport string `yaml:"transport,omitempty" deprecated:"ignored; the server always serves HTTP. Set server.stdio to also serve stdio"`
+}
+
+// DrainConfig controls how the server drains before shutting down. While
+// draining, /ready reports 503 and new tool calls and executions are
+// rejected so load balancers move traffic to other replicas.
+type DrainConfig struct {
+	// GracePeriod is how long in-flight executions may run before the server
+	// shuts down anyway (default: 30s).
+	GracePeriod time.Duration `yaml:"grace_period,omitempty"`
+
+	// StateFile is where session metadata (last use and env overrides) is
+	// saved on shutdown when sandbox.sessions.keep_on_shutdown is set, and
+	// restored from on the next start (default: ~/.panda/data/session-state.json).
+	StateFile string `yaml:"state_file,omitempty"`
+}
+
+// HealthProbesConfig holds configuration for module health probes.
+type HealthProbesConfig struct {
+	// Interval is how long a module's probe result is cached. Defaults to 30s.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// Timeout bounds each module's probes. Defaults to 5s.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
 }
 
 // SandboxConfig holds sandbox execution configuration.
@@ -66,11 +669,338 @@ type SandboxConfig struct {
 	// When set, containers are labeled with "io.ethpandaops-panda.instance=<value>".
 	Instance string `yaml:"instance,omitempty"`
 
-	// Session configuration for persistent execution environments.
-	Sessions SessionConfig `yaml:"sessions"`
+	// Session configuration for persistent execution environments.
+	Sessions SessionConfig `yaml:"sessions"`
+
+	// Logging configuration for sandbox executions.
+	Logging SandboxLoggingConfig `yaml:"logging"`
+
+	// Firecracker configuration, used when backend is "firecracker".
+	Firecracker FirecrackerConfig `yaml:"firecracker"`
+
+	// DNS configures name resolution inside sandbox containers.
+	DNS SandboxDNSConfig `yaml:"dns"`
+
+	// Profiles are named execution environments callers can select per
+	// execution instead of the default sizing above (e.g. "light", "heavy").
+	Profiles map[string]ExecutionProfile `yaml:"profiles,omitempty"`
+
+	// GPU configures scheduling of executions whose profile requests GPUs.
+	GPU SandboxGPUConfig `yaml:"gpu"`
+
+	// PackageCache mounts a shared, read-only Python package cache into
+	// sandbox containers so pip installs don't redownload per session.
+	PackageCache SandboxPackageCacheConfig `yaml:"package_cache"`
+
+	// Packages lets executions request extra Python packages, installed
+	// into their session on demand.
+	Packages SandboxPackagesConfig `yaml:"packages"`
+
+	// Images controls how sandbox images are pulled and verified.
+	Images SandboxImagesConfig `yaml:"images"`
+
+	// Pool keeps pre-started session containers ready for new sessions.
+	Pool SandboxPoolConfig `yaml:"pool"`
+
+	// Scheduler caps concurrent executions and queues the excess.
+	Scheduler SandboxSchedulerConfig `yaml:"scheduler"`
+
+	// Artifacts uploads charts that executions save to the session
+	// workspace and returns their URLs with the result.
+	Artifacts SandboxArtifactsConfig `yaml:"artifacts"`
+}
+
+// SandboxArtifactsConfig controls automatic upload of charts and images an
+// execution writes to /workspace. Uploaded files are returned as artifacts
+// with public URLs and, for raster images, a thumbnail.
+type SandboxArtifactsConfig struct {
+	// Enabled turns automatic uploads on. Defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// MaxFiles bounds how many files one execution uploads (default: 10).
+	MaxFiles int `yaml:"max_files,omitempty"`
+
+	// MaxSizeMB skips files larger than this (default: 20).
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+
+	// ThumbnailSize is the longest edge of generated thumbnails in pixels
+	// (default: 320).
+	ThumbnailSize int `yaml:"thumbnail_size,omitempty"`
+}
+
+// IsEnabled returns whether artifact uploads are enabled (defaults to true).
+func (c *SandboxArtifactsConfig) IsEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+
+	return *c.Enabled
+}
+
+// SandboxSchedulerConfig limits how many executions run at once. Executions
+// over a limit wait in a first-in, first-out queue instead of failing.
+type SandboxSchedulerConfig struct {
+	// MaxConcurrent caps executions running at once across all users.
+	// Zero means unlimited.
+	MaxConcurrent int `yaml:"max_concurrent"`
+
+	// MaxPerOwner caps executions running at once for one authenticated
+	// user. Zero means unlimited.
+	MaxPerOwner int `yaml:"max_per_owner"`
+
+	// MaxQueue is how many executions may wait for a slot before new ones
+	// are rejected (default: 100).
+	MaxQueue int `yaml:"max_queue,omitempty"`
+}
+
+// SandboxPoolConfig configures the warm pool of session containers. New
+// sessions with the default profile claim a running container from the
+// pool instead of starting one, and the pool is refilled in the background.
+type SandboxPoolConfig struct {
+	// Size is how many warm containers to keep ready. Zero disables the pool.
+	Size int `yaml:"size"`
+
+	// MaxIdle is how long a warm container waits to be claimed before it
+	// is replaced with a fresh one (default: 15m).
+	MaxIdle time.Duration `yaml:"max_idle,omitempty"`
+}
+
+// SandboxImagesConfig manages the lifecycle of the sandbox images used by
+// the default settings and every execution profile.
+type SandboxImagesConfig struct {
+	// RequireDigest rejects image references not pinned by digest
+	// (image@sha256:...), so every execution uses a known environment.
+	RequireDigest bool `yaml:"require_digest"`
+
+	// PullOnStart pulls every image at startup even when it is present
+	// locally, picking up new pushes to mutable tags.
+	PullOnStart bool `yaml:"pull_on_start"`
+
+	// RepullInterval re-pulls images tagged :latest (or untagged) this
+	// often. Zero disables re-pulling.
+	RepullInterval time.Duration `yaml:"repull_interval,omitempty"`
+}
+
+// SandboxPackagesConfig controls the packages executions may install with
+// execute_python's packages argument. Packages are pip installed into the
+// session's user site, so they last as long as the session.
+type SandboxPackagesConfig struct {
+	// Enabled allows executions to request packages.
+	Enabled bool `yaml:"enabled"`
+
+	// Allowed lists the project names that may be requested. Empty allows
+	// every package not in Denied.
+	Allowed []string `yaml:"allowed,omitempty"`
+
+	// Denied lists project names that may never be requested.
+	Denied []string `yaml:"denied,omitempty"`
+
+	// CacheOnly installs from the package cache alone (pip --no-index), so
+	// nothing is fetched from a package index. Requires package_cache.
+	CacheOnly bool `yaml:"cache_only"`
+
+	// Prewarm builds wheels for every Allowed package into the package
+	// cache at startup. Requires package_cache.
+	Prewarm bool `yaml:"prewarm"`
+
+	// MaxPackages bounds how many packages one execution may request
+	// (default: 10).
+	MaxPackages int `yaml:"max_packages"`
+}
+
+// SandboxPackageCacheConfig configures the shared package cache. The cache is
+// populated through the admin API (panda admin cache add) and mounted at
+// /opt/panda/wheels with pip pointed at it.
+type SandboxPackageCacheConfig struct {
+	// Enabled mounts the cache into sandbox containers.
+	Enabled bool `yaml:"enabled"`
+
+	// Dir is where the cache lives on the server (default: ~/.panda/data/package-cache).
+	Dir string `yaml:"dir,omitempty"`
+
+	// HostDir is Dir as seen by the Docker daemon, when the server itself
+	// runs in a container (Docker-in-Docker), like host_shared_path.
+	HostDir string `yaml:"host_dir,omitempty"`
+
+	// MaxSizeMB caps the total size of cached packages (default: 5120).
+	MaxSizeMB int `yaml:"max_size_mb"`
+}
+
+func (c SandboxImagesConfig) validate(sandbox SandboxConfig) error {
+	if c.RepullInterval < 0 || (c.RepullInterval > 0 && c.RepullInterval < time.Minute) {
+		return errors.New("repull_interval must be at least 1m")
+	}
+
+	if !c.RequireDigest {
+		return nil
+	}
+
+	if !strings.Contains(sandbox.Image, "@sha256:") {
+		return fmt.Errorf("require_digest: sandbox.image %q is not pinned by digest", sandbox.Image)
+	}
+
+	for name, profile := range sandbox.Profiles {
+		if profile.Image != "" && !strings.Contains(profile.Image, "@sha256:") {
+			return fmt.Errorf("require_digest: sandbox.profiles.%s.image %q is not pinned by digest", name, profile.Image)
+		}
+	}
+
+	return nil
+}
+
+func (c SandboxPackagesConfig) validate(packageCacheEnabled bool) error {
+	if c.MaxPackages < 0 {
+		return errors.New("max_packages cannot be negative")
+	}
+
+	if (c.CacheOnly || c.Prewarm) && !packageCacheEnabled {
+		return errors.New("cache_only and prewarm require sandbox.package_cache.enabled")
+	}
+
+	if c.Prewarm && len(c.Allowed) == 0 {
+		return errors.New("prewarm requires an allowed list")
+	}
+
+	for _, name := range append(slices.Clone(c.Allowed), c.Denied...) {
+		if !packageNamePattern.MatchString(name) {
+			return fmt.Errorf("%q is not a package name", name)
+		}
+	}
+
+	return nil
+}
+
+// SandboxGPUConfig limits how many GPU executions run at once. Executions
+// beyond the limit wait for a slot until their context is cancelled.
+type SandboxGPUConfig struct {
+	// MaxConcurrent is the number of GPU executions allowed at once. Defaults to 1.
+	MaxConcurrent int `yaml:"max_concurrent"`
+}
+
+// ExecutionProfile overrides the default sandbox sizing for executions that
+// select it. Unset fields fall back to the top-level sandbox settings.
+type ExecutionProfile struct {
+	Image       string  `yaml:"image,omitempty"`
+	MemoryLimit string  `yaml:"memory_limit,omitempty"`
+	CPULimit    float64 `yaml:"cpu_limit,omitempty"`
+	Timeout     int     `yaml:"timeout,omitempty"`
+	Network     string  `yaml:"network,omitempty"`
+
+	// GPUs passes host GPUs through to the container, like docker's --gpus.
+	// -1 requests all GPUs. Requires the NVIDIA container toolkit on the host.
+	GPUs int `yaml:"gpus,omitempty"`
+
+	// AllowedGroups restricts the profile to users in one of these groups or
+	// GitHub orgs. Empty allows everyone.
+	AllowedGroups []string `yaml:"allowed_groups,omitempty"`
+}
+
+// Allows reports whether a user with the given groups may use the profile.
+func (p *ExecutionProfile) Allows(groups []string) bool {
+	if len(p.AllowedGroups) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.AllowedGroups {
+		if slices.Contains(groups, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UsesGPU reports whether the profile requests GPU passthrough.
+func (p *ExecutionProfile) UsesGPU() bool {
+	return p.GPUs != 0
+}
+
+// ErrUnknownProfile is returned for execution profile names that aren't
+// configured.
+var ErrUnknownProfile = errors.New("unknown execution profile")
+
+// Profile returns the effective settings for the named profile, with unset
+// fields filled from the top-level sandbox settings. An empty name returns
+// the defaults.
+func (c *SandboxConfig) Profile(name string) (ExecutionProfile, error) {
+	profile := ExecutionProfile{}
+
+	if name != "" {
+		p, ok := c.Profiles[name]
+		if !ok {
+			return ExecutionProfile{}, fmt.Errorf("%w %q", ErrUnknownProfile, name)
+		}
+
+		profile = p
+	}
+
+	if profile.Image == "" {
+		profile.Image = c.Image
+	}
+
+	if profile.MemoryLimit == "" {
+		profile.MemoryLimit = c.MemoryLimit
+	}
+
+	if profile.CPULimit == 0 {
+		profile.CPULimit = c.CPULimit
+	}
+
+	if profile.Timeout == 0 {
+		profile.Timeout = c.Timeout
+	}
+
+	if profile.Network == "" {
+		profile.Network = c.Network
+	}
+
+	return profile, nil
+}
+
+// SandboxDNSConfig overrides the resolver configuration of sandbox containers.
+// Use it where datasource hostnames only resolve via DNS servers that
+// Docker's defaults don't use.
+type SandboxDNSConfig struct {
+	// Nameservers replace the resolvers Docker writes to /etc/resolv.conf.
+	Nameservers []string `yaml:"nameservers,omitempty"`
+
+	// Search lists DNS search domains.
+	Search []string `yaml:"search,omitempty"`
+
+	// Options are resolv.conf options such as "ndots:2".
+	Options []string `yaml:"options,omitempty"`
+
+	// ExtraHosts adds /etc/hosts entries in "hostname:ip" form.
+	ExtraHosts []string `yaml:"extra_hosts,omitempty"`
+}
+
+// Validate checks nameservers and extra hosts entries are well formed.
+func (c *SandboxDNSConfig) Validate() error {
+	for _, ns := range c.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return fmt.Errorf("nameserver %q is not an IP address", ns)
+		}
+	}
+
+	for _, entry := range c.ExtraHosts {
+		host, ip, ok := strings.Cut(entry, ":")
+		if !ok || host == "" {
+			return fmt.Errorf("extra_hosts entry %q must be in hostname:ip form", entry)
+		}
+
+		if ip != "host-gateway" && net.ParseIP(ip) == nil {
+			return fmt.Errorf("extra_hosts entry %q has an invalid IP address", entry)
+		}
+	}
+
+	return nil
+}
 
-	// Logging configuration for sandbox executions.
-	Logging SandboxLoggingConfig `yaml:"logging"`
+// FirecrackerConfig holds configuration for the Firecracker microVM backend.
+type FirecrackerConfig struct {
+	// Runtime is the Docker runtime name of the Kata Containers Firecracker
+	// shim registered with the daemon (default: "kata-fc").
+	Runtime string `yaml:"runtime,omitempty"`
 }
 
 // SandboxLoggingConfig holds logging configuration for sandbox executions.
@@ -94,6 +1024,21 @@ type SessionConfig struct {
 	MaxDuration time.Duration `yaml:"max_duration"`
 	// MaxSessions is the maximum number of concurrent sessions allowed.
 	MaxSessions int `yaml:"max_sessions"`
+	// WriteStubs writes generated ethpandaops .pyi type stubs into each new
+	// session's workspace under .stubs/.
+	WriteStubs bool `yaml:"write_stubs"`
+	// EnvAllowlist names the non-secret env vars agents may set for a
+	// session with manage_session's set_env operation. Entries ending in "*"
+	// match by prefix. Empty disables set_env.
+	EnvAllowlist []string `yaml:"env_allowlist,omitempty"`
+	// Kernel runs session executions in a long-lived Python process inside
+	// the session container, so variables, dataframes and imports persist
+	// between executions. Requires a sandbox image with the kernel shim.
+	Kernel bool `yaml:"kernel"`
+	// KeepOnShutdown leaves session containers running when the server
+	// stops, so the next server instance adopts them. Their metadata is
+	// saved to server.drain.state_file.
+	KeepOnShutdown bool `yaml:"keep_on_shutdown"`
 }
 
 // IsEnabled returns whether sessions are enabled (defaults to true).
@@ -114,12 +1059,31 @@ type ObservabilityConfig struct {
 // ProxyConfig holds proxy connection configuration.
 // The MCP server always connects to a proxy server via this config.
 type ProxyConfig struct {
+	// Mode is "remote" (default) to connect to the proxy at URL, or
+	// "embedded" to run the proxy inside the server process.
+	Mode string `yaml:"mode,omitempty"`
+
+	// Config is the proxy config file an embedded proxy loads. Relative
+	// paths are resolved against this file's directory. When unset,
+	// proxy-config.yaml next to this file is used, then the standalone
+	// proxy's search path.
+	Config string `yaml:"config,omitempty"`
+
 	// URL is the base URL of the proxy server (e.g., http://localhost:18081).
+	// It is ignored in embedded mode.
 	URL string `yaml:"url"`
 
 	// Auth configures authentication for the proxy.
 	// Optional - if not set, the proxy must allow unauthenticated access.
 	Auth *ProxyAuthConfig `yaml:"auth,omitempty"`
+
+	// SigningKey is the optional HMAC key used to sign server-to-proxy requests.
+	// Must match the proxy's auth.request_signing.secret_key when that is set.
+	SigningKey string `yaml:"signing_key,omitempty"`
+
+	// TLS configures the CAs and client certificate used to reach a proxy
+	// served over TLS or mTLS.
+	TLS tlsconfig.ClientConfig `yaml:"tls,omitempty"`
 }
 
 // ProxyAuthConfig configures authentication for the proxy.
@@ -142,6 +1106,21 @@ type ProxyAuthConfig struct {
 	// OIDC provider. When set, the client will proactively refresh at 50% of this
 	// duration to keep the refresh token alive via provider rotation.
 	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl,omitempty"`
+
+	// Profile selects the named credential profile created by `panda auth login --profile`.
+	// $PANDA_PROFILE overrides this value. Defaults to "default".
+	Profile string `yaml:"profile,omitempty"`
+
+	// CredentialStore selects how tokens are stored locally: "file" (default,
+	// plaintext with 0600 permissions), "encrypted" (AES-256-GCM under a
+	// generated key file or $PANDA_CREDENTIALS_KEY) or "keychain" (key held in
+	// the OS keychain, falling back to "encrypted").
+	CredentialStore string `yaml:"credential_store,omitempty"`
+
+	// Token is a static bearer token sent instead of `panda auth login`
+	// credentials, such as a service-account token minted with
+	// `panda-proxy token mint` for CI. Usually "${PANDA_PROXY_TOKEN}".
+	Token string `yaml:"token,omitempty"`
 }
 
 // Load loads configuration from a YAML file with environment variable substitution.
@@ -162,16 +1141,32 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("substituting env vars: %w", err)
 	}
 
-	var cfg Config
-	decoder := yaml.NewDecoder(bytes.NewReader([]byte(substituted)))
-	decoder.KnownFields(true)
+	cfg, err := decodeConfig(substituted)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := decoder.Decode(&cfg); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
+	// Resolve Vault and Kubernetes secret references with the stores the
+	// config itself declares, then parse again.
+	if secrets.HasRefs(substituted) {
+		resolved, err := secrets.Apply(context.Background(), cfg.Secrets, substituted)
+		if err != nil {
+			return nil, fmt.Errorf("resolving secrets: %w", err)
+		}
+
+		if cfg, err = decodeConfig(resolved); err != nil {
+			return nil, err
+		}
 	}
 
 	// Apply defaults
-	applyDefaults(&cfg)
+	applyDefaults(cfg)
+
+	for i, job := range cfg.Schedules.Jobs {
+		if job.File != "" && !filepath.IsAbs(job.File) {
+			cfg.Schedules.Jobs[i].File = filepath.Join(filepath.Dir(resolvedPath), job.File)
+		}
+	}
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
@@ -179,6 +1174,19 @@ func Load(path string) (*Config, error) {
 
 	cfg.path = resolvedPath
 
+	return cfg, nil
+}
+
+func decodeConfig(content string) (*Config, error) {
+	var cfg Config
+
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
 	return &cfg, nil
 }
 
@@ -187,6 +1195,17 @@ func (c *Config) Path() string {
 	return c.path
 }
 
+// EmbeddedProxyConfigPath resolves the proxy config file an embedded proxy
+// loads, relative to this config's directory.
+func (c *Config) EmbeddedProxyConfigPath() (string, error) {
+	baseDir := ""
+	if c.path != "" {
+		baseDir = filepath.Dir(c.path)
+	}
+
+	return configpath.ResolveProxyConfigPath(c.Proxy.Config, baseDir)
+}
+
 // envVarWithDefaultPattern matches ${VAR_NAME:-default} patterns.
 var envVarWithDefaultPattern = regexp.MustCompile(`\$\{([^}:]+)(?::-([^}]*))?\}`)
 
@@ -233,6 +1252,14 @@ func applyDefaults(cfg *Config) {
 		cfg.Server.Port = 2480
 	}
 
+	if cfg.Server.HealthProbes.Interval == 0 {
+		cfg.Server.HealthProbes.Interval = 30 * time.Second
+	}
+
+	if cfg.Server.HealthProbes.Timeout == 0 {
+		cfg.Server.HealthProbes.Timeout = 5 * time.Second
+	}
+
 	if cfg.Sandbox.Backend == "" {
 		cfg.Sandbox.Backend = "docker"
 	}
@@ -249,6 +1276,54 @@ func applyDefaults(cfg *Config) {
 		cfg.Sandbox.CPULimit = 1.0
 	}
 
+	if cfg.Sandbox.GPU.MaxConcurrent == 0 {
+		cfg.Sandbox.GPU.MaxConcurrent = 1
+	}
+
+	if cfg.Sandbox.PackageCache.Dir == "" {
+		cfg.Sandbox.PackageCache.Dir = pandaDataDir("package-cache")
+	}
+
+	if cfg.Sandbox.PackageCache.MaxSizeMB == 0 {
+		cfg.Sandbox.PackageCache.MaxSizeMB = 5120
+	}
+
+	if cfg.Sandbox.Scheduler.MaxQueue == 0 {
+		cfg.Sandbox.Scheduler.MaxQueue = 100
+	}
+
+	if cfg.Sandbox.Pool.MaxIdle == 0 {
+		cfg.Sandbox.Pool.MaxIdle = 15 * time.Minute
+	}
+
+	if cfg.Sandbox.Packages.MaxPackages == 0 {
+		cfg.Sandbox.Packages.MaxPackages = 10
+	}
+
+	if cfg.Server.Drain.GracePeriod == 0 {
+		cfg.Server.Drain.GracePeriod = 30 * time.Second
+	}
+
+	if cfg.Server.Drain.StateFile == "" {
+		cfg.Server.Drain.StateFile = pandaDataDir("session-state.json")
+	}
+
+	if cfg.Sandbox.Artifacts.MaxFiles == 0 {
+		cfg.Sandbox.Artifacts.MaxFiles = 10
+	}
+
+	if cfg.Sandbox.Artifacts.MaxSizeMB == 0 {
+		cfg.Sandbox.Artifacts.MaxSizeMB = 20
+	}
+
+	if cfg.Sandbox.Artifacts.ThumbnailSize == 0 {
+		cfg.Sandbox.Artifacts.ThumbnailSize = 320
+	}
+
+	if cfg.Sandbox.Firecracker.Runtime == "" {
+		cfg.Sandbox.Firecracker.Runtime = "kata-fc"
+	}
+
 	// Session defaults.
 	if cfg.Sandbox.Sessions.TTL == 0 {
 		cfg.Sandbox.Sessions.TTL = 30 * time.Minute
@@ -267,6 +1342,10 @@ func applyDefaults(cfg *Config) {
 	}
 
 	// Proxy defaults.
+	if cfg.Proxy.Mode == "" {
+		cfg.Proxy.Mode = ProxyModeRemote
+	}
+
 	if cfg.Proxy.URL == "" {
 		cfg.Proxy.URL = "http://localhost:18081"
 	}
@@ -279,6 +1358,124 @@ func applyDefaults(cfg *Config) {
 	if cfg.Storage.CacheDir == "" {
 		cfg.Storage.CacheDir = filepath.Join(filepath.Dir(cfg.Storage.BaseDir), "cache")
 	}
+
+	if cfg.Storage.Retention.TTL == 0 {
+		cfg.Storage.Retention.TTL = 30 * 24 * time.Hour
+	}
+
+	if cfg.Storage.Retention.Interval == 0 {
+		cfg.Storage.Retention.Interval = time.Hour
+	}
+
+	if cfg.Storage.Retention.Exclude == nil {
+		cfg.Storage.Retention.Exclude = []string{"history"}
+	}
+
+	if cfg.Storage.Uploads.URLTTL == 0 {
+		cfg.Storage.Uploads.URLTTL = 15 * time.Minute
+	}
+
+	// History defaults.
+	if cfg.History.Path == "" {
+		cfg.History.Path = filepath.Join(pandaDataDir("history"), "executions.jsonl")
+	}
+
+	if cfg.History.MaxEntries == 0 {
+		cfg.History.MaxEntries = 10000
+	}
+
+	if cfg.History.Export.Interval == 0 {
+		cfg.History.Export.Interval = time.Hour
+	}
+
+	if cfg.History.Export.RetentionDays == 0 {
+		cfg.History.Export.RetentionDays = 90
+	}
+
+	if cfg.Schedules.KeepRuns == 0 {
+		cfg.Schedules.KeepRuns = 20
+	}
+
+	if cfg.Webhooks.Attempts == 0 {
+		cfg.Webhooks.Attempts = 3
+	}
+
+	if cfg.Webhooks.Backoff == 0 {
+		cfg.Webhooks.Backoff = 2 * time.Second
+	}
+
+	if cfg.Webhooks.Timeout == 0 {
+		cfg.Webhooks.Timeout = 10 * time.Second
+	}
+
+	// Semantic search defaults.
+	if cfg.SemanticSearch.Backend == "" {
+		cfg.SemanticSearch.Backend = EmbeddingBackendProxy
+	}
+
+	if cfg.SemanticSearch.OpenAI.URL == "" {
+		cfg.SemanticSearch.OpenAI.URL = "https://api.openai.com/v1"
+	}
+
+	if cfg.SemanticSearch.OpenAI.Model == "" {
+		cfg.SemanticSearch.OpenAI.Model = "text-embedding-3-small"
+	}
+
+	if cfg.SemanticSearch.OpenAI.APIKeyEnv == "" {
+		cfg.SemanticSearch.OpenAI.APIKeyEnv = "OPENAI_API_KEY"
+	}
+
+	// Example usage defaults.
+	if cfg.ExampleUsage.Dir == "" {
+		cfg.ExampleUsage.Dir = pandaDataDir("example-usage")
+	}
+
+	if cfg.ExampleUsage.Weight == 0 {
+		cfg.ExampleUsage.Weight = 0.1
+	}
+
+	// Runbook source defaults.
+	if cfg.Runbooks.CheckoutDir == "" {
+		cfg.Runbooks.CheckoutDir = pandaDataDir("runbooks")
+	}
+
+	for i := range cfg.Runbooks.Sources {
+		if cfg.Runbooks.Sources[i].RefreshInterval == 0 {
+			cfg.Runbooks.Sources[i].RefreshInterval = 15 * time.Minute
+		}
+	}
+
+	// Schema sample defaults.
+	if cfg.SchemaSamples.Rows == 0 {
+		cfg.SchemaSamples.Rows = 3
+	}
+
+	if cfg.SchemaSamples.MaxValueLength == 0 {
+		cfg.SchemaSamples.MaxValueLength = 100
+	}
+
+	// Network defaults.
+	if cfg.Networks.CacheTTL == 0 {
+		cfg.Networks.CacheTTL = cartographoor.DefaultCacheTTL
+	}
+
+	if cfg.Networks.MaxStale == 0 {
+		cfg.Networks.MaxStale = cartographoor.DefaultMaxStale
+	}
+
+	if cfg.Networks.CacheDir == "" {
+		cfg.Networks.CacheDir = pandaDataDir("cartographoor")
+	}
+
+	// User examples defaults.
+	if cfg.UserExamples.Dir == "" {
+		cfg.UserExamples.Dir = pandaDataDir("examples")
+	}
+
+	// Offline defaults.
+	if cfg.Offline.SnapshotDir == "" {
+		cfg.Offline.SnapshotDir = pandaDataDir("offline")
+	}
 }
 
 func pandaDataDir(subdir string) string {
@@ -290,22 +1487,294 @@ func pandaDataDir(subdir string) string {
 	return filepath.Join(home, ".panda", "data", subdir)
 }
 
+// envAllowlistPattern matches env var names, optionally ending in "*".
+var envAllowlistPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\*?$`)
+
+// packageNamePattern matches a Python project name.
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+
+// runbookSourceNamePattern matches runbook source names, which also name
+// their checkout directory.
+var runbookSourceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Validate checks that each source is named uniquely and has one location.
+func (c RunbooksConfig) Validate() error {
+	seen := make(map[string]struct{}, len(c.Sources))
+
+	for i, src := range c.Sources {
+		if !runbookSourceNamePattern.MatchString(src.Name) {
+			return fmt.Errorf("sources[%d].name %q must be letters, digits, '-' or '_'", i, src.Name)
+		}
+
+		if src.Name == "builtin" {
+			return fmt.Errorf("sources[%d].name %q is reserved", i, src.Name)
+		}
+
+		if _, dup := seen[src.Name]; dup {
+			return fmt.Errorf("sources[%d].name %q is used more than once", i, src.Name)
+		}
+
+		seen[src.Name] = struct{}{}
+
+		if (src.Dir == "") == (src.Git == "") {
+			return fmt.Errorf("source %q must set exactly one of dir and git", src.Name)
+		}
+
+		if src.Dir != "" && (src.Ref != "" || src.Path != "") {
+			return fmt.Errorf("source %q: ref and path only apply to git sources", src.Name)
+		}
+
+		if src.Path != "" && !filepath.IsLocal(src.Path) {
+			return fmt.Errorf("source %q: path must be relative to the repository", src.Name)
+		}
+
+		if src.RefreshInterval < time.Minute {
+			return fmt.Errorf("source %q: refresh_interval must be at least 1m", src.Name)
+		}
+	}
+
+	return nil
+}
+
 // MaxSandboxTimeout is the maximum allowed sandbox timeout in seconds.
 const MaxSandboxTimeout = 600
 
+// MaxSchemaSampleRows is the maximum number of sample rows per table.
+const MaxSchemaSampleRows = 20
+
 // Validate validates the configuration.
 func (c *Config) Validate() error {
 	if c.Sandbox.Image == "" {
 		return errors.New("sandbox.image is required")
 	}
 
+	if err := auth.ValidateToolRules(c.Server.ToolPolicies); err != nil {
+		return fmt.Errorf("server.tool_policies: %w", err)
+	}
+
+	if len(c.Server.ToolPolicies) > 0 && c.Auth.Bearer == nil {
+		return errors.New("server.tool_policies requires auth.bearer, otherwise no caller is authenticated")
+	}
+
+	if err := c.Secrets.Validate(); err != nil {
+		return fmt.Errorf("secrets.%w", err)
+	}
+
+	if err := auth.ValidateDatasourceScopes(c.Server.DatasourceScopes); err != nil {
+		return fmt.Errorf("server.datasource_scopes: %w", err)
+	}
+
+	if len(c.Server.DatasourceScopes) > 0 && c.Auth.Bearer == nil {
+		return errors.New("server.datasource_scopes requires auth.bearer, otherwise no caller is scoped")
+	}
+
+	if c.Auth.Bearer != nil {
+		if err := c.Auth.Bearer.Validate(); err != nil {
+			return fmt.Errorf("auth.bearer: %w", err)
+		}
+	}
+
+	if c.Auth.PolicyEngine != nil {
+		if err := c.Auth.PolicyEngine.Validate(); err != nil {
+			return fmt.Errorf("auth.policy_engine: %w", err)
+		}
+	}
+
+	switch c.SemanticSearch.Backend {
+	case "", EmbeddingBackendProxy, EmbeddingBackendOpenAI:
+	default:
+		return fmt.Errorf("semantic_search.backend must be %q or %q", EmbeddingBackendProxy, EmbeddingBackendOpenAI)
+	}
+
+	if c.SemanticSearch.OpenAI.Dimensions < 0 {
+		return errors.New("semantic_search.openai.dimensions cannot be negative")
+	}
+
+	if c.ExampleUsage.Weight < 0 || c.ExampleUsage.Weight > 1 {
+		return errors.New("example_usage.weight must be between 0 and 1")
+	}
+
+	if err := c.Runbooks.Validate(); err != nil {
+		return fmt.Errorf("runbooks: %w", err)
+	}
+
+	if err := c.Schedules.Validate(c.Sandbox.Profiles); err != nil {
+		return fmt.Errorf("schedules: %w", err)
+	}
+
+	if err := c.Webhooks.Validate(); err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+
+	if c.SchemaSamples.Rows < 1 || c.SchemaSamples.Rows > MaxSchemaSampleRows {
+		return fmt.Errorf("schema_samples.rows must be between 1 and %d", MaxSchemaSampleRows)
+	}
+
+	if c.SchemaSamples.MaxValueLength < 1 {
+		return errors.New("schema_samples.max_value_length must be positive")
+	}
+
+	if c.Networks.CacheTTL < 10*time.Second {
+		return errors.New("networks.cache_ttl must be at least 10s")
+	}
+
+	if c.Networks.MaxStale < 0 {
+		return errors.New("networks.max_stale cannot be negative")
+	}
+
+	if err := cartographoor.ValidateOverrides(c.Networks.Overrides, c.Networks.Hide); err != nil {
+		return fmt.Errorf("networks: %w", err)
+	}
+
+	if err := validateSavedQueries("saved_queries.loki", c.SavedQueries.Loki); err != nil {
+		return err
+	}
+
+	for _, pattern := range c.SchemaSamples.RedactColumns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("schema_samples.redact_columns: invalid pattern %q", pattern)
+		}
+	}
+
+	for _, name := range c.Sandbox.Sessions.EnvAllowlist {
+		if !envAllowlistPattern.MatchString(name) || strings.HasPrefix(name, "ETHPANDAOPS_") {
+			return fmt.Errorf(
+				"sandbox.sessions.env_allowlist: %q must be an env var name or prefix* and not ETHPANDAOPS_*", name,
+			)
+		}
+	}
+
+	if err := c.Server.TLS.Validate(); err != nil {
+		return fmt.Errorf("server.tls: %w", err)
+	}
+
+	if err := c.Proxy.TLS.Validate(); err != nil {
+		return fmt.Errorf("proxy.tls: %w", err)
+	}
+
+	if c.History.Export.Enabled {
+		if !c.History.IsEnabled() {
+			return errors.New("history.export requires history to be enabled")
+		}
+
+		if c.History.Export.Interval < time.Minute {
+			return errors.New("history.export.interval must be at least 1m")
+		}
+
+		if c.History.Export.RetentionDays < 1 {
+			return errors.New("history.export.retention_days must be at least 1")
+		}
+	}
+
+	if c.Storage.Uploads.URLTTL < time.Minute {
+		return errors.New("storage.uploads.url_ttl must be at least 1m")
+	}
+
+	if c.Storage.Retention.Enabled {
+		if c.Storage.Retention.TTL < time.Hour {
+			return errors.New("storage.retention.ttl must be at least 1h")
+		}
+
+		if c.Storage.Retention.Interval < time.Minute {
+			return errors.New("storage.retention.interval must be at least 1m")
+		}
+	}
+
+	if c.Server.HealthProbes.Interval < 0 {
+		return errors.New("server.health_probes.interval cannot be negative")
+	}
+
+	if c.Server.HealthProbes.Timeout < 0 {
+		return errors.New("server.health_probes.timeout cannot be negative")
+	}
+
 	// Validate sandbox timeout is within bounds.
 	if c.Sandbox.Timeout > MaxSandboxTimeout {
 		return fmt.Errorf("sandbox.timeout cannot exceed %d seconds", MaxSandboxTimeout)
 	}
 
-	if c.Proxy.URL == "" {
-		return errors.New("proxy.url is required")
+	if err := c.Sandbox.DNS.Validate(); err != nil {
+		return fmt.Errorf("sandbox.dns: %w", err)
+	}
+
+	for name, profile := range c.Sandbox.Profiles {
+		if name == "" {
+			return errors.New("sandbox.profiles: profile name cannot be empty")
+		}
+
+		if profile.Timeout < 0 || profile.Timeout > MaxSandboxTimeout {
+			return fmt.Errorf("sandbox.profiles.%s.timeout must be between 0 and %d seconds", name, MaxSandboxTimeout)
+		}
+
+		if profile.CPULimit < 0 {
+			return fmt.Errorf("sandbox.profiles.%s.cpu_limit cannot be negative", name)
+		}
+
+		if profile.GPUs < -1 {
+			return fmt.Errorf("sandbox.profiles.%s.gpus must be -1 (all) or a GPU count", name)
+		}
+	}
+
+	if c.Sandbox.PackageCache.MaxSizeMB < 0 {
+		return errors.New("sandbox.package_cache.max_size_mb cannot be negative")
+	}
+
+	if c.Sandbox.Scheduler.MaxConcurrent < 0 || c.Sandbox.Scheduler.MaxPerOwner < 0 || c.Sandbox.Scheduler.MaxQueue < 0 {
+		return errors.New("sandbox.scheduler limits cannot be negative")
+	}
+
+	if c.Sandbox.Pool.Size < 0 {
+		return errors.New("sandbox.pool.size cannot be negative")
+	}
+
+	if c.Server.Drain.GracePeriod < 0 {
+		return errors.New("server.drain.grace_period cannot be negative")
+	}
+
+	if a := c.Sandbox.Artifacts; a.MaxFiles < 0 || a.MaxSizeMB < 0 || a.ThumbnailSize < 0 {
+		return errors.New("sandbox.artifacts limits cannot be negative")
+	}
+
+	if c.Sandbox.Pool.Size > 0 && !c.Sandbox.Sessions.IsEnabled() {
+		return errors.New("sandbox.pool requires sandbox.sessions to be enabled")
+	}
+
+	if c.Sandbox.Pool.Size > 0 && c.Sandbox.Pool.MaxIdle < time.Minute {
+		return errors.New("sandbox.pool.max_idle must be at least 1m")
+	}
+
+	if err := c.Sandbox.Images.validate(c.Sandbox); err != nil {
+		return fmt.Errorf("sandbox.images: %w", err)
+	}
+
+	if err := c.Sandbox.Packages.validate(c.Sandbox.PackageCache.Enabled); err != nil {
+		return fmt.Errorf("sandbox.packages: %w", err)
+	}
+
+	if c.Sandbox.GPU.MaxConcurrent < 0 {
+		return errors.New("sandbox.gpu.max_concurrent cannot be negative")
+	}
+
+	switch c.Proxy.Mode {
+	case "", ProxyModeRemote:
+		if c.Proxy.URL == "" {
+			return errors.New("proxy.url is required")
+		}
+	case ProxyModeEmbedded:
+	default:
+		return fmt.Errorf("proxy.mode must be %q or %q", ProxyModeRemote, ProxyModeEmbedded)
+	}
+
+	if c.Proxy.Auth != nil {
+		if c.Proxy.Auth.Profile != "" {
+			if err := authstore.ValidateProfile(c.Proxy.Auth.Profile); err != nil {
+				return fmt.Errorf("proxy.auth.profile: %w", err)
+			}
+		}
+
+		if err := authstore.ValidateBackend(c.Proxy.Auth.CredentialStore); err != nil {
+			return fmt.Errorf("proxy.auth.credential_store: %w", err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethpandaops/panda/pkg/configpath"
+	"github.com/ethpandaops/panda/pkg/configschema"
+)
+
+// Schema returns the JSON Schema of the config file.
+func Schema() *configschema.Schema {
+	return configschema.Generate(Config{})
+}
+
+// Lint checks the config file at path against Schema after env var
+// substitution, reporting unknown keys, type mismatches and deprecated
+// fields. It returns the resolved path. Semantic checks are left to Load.
+func Lint(path string) (string, []configschema.Issue, error) {
+	resolvedPath, err := configpath.ResolveAppConfigPath(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return resolvedPath, nil, fmt.Errorf("reading config file %s: %w", resolvedPath, err)
+	}
+
+	substituted, err := substituteEnvVars(string(data))
+	if err != nil {
+		return resolvedPath, nil, fmt.Errorf("substituting env vars: %w", err)
+	}
+
+	issues, err := configschema.Validate(Schema(), []byte(substituted))
+	if err != nil {
+		return resolvedPath, nil, err
+	}
+
+	return resolvedPath, issues, nil
+}
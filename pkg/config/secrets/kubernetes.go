@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	defaultKubernetesAPIServer     = "https://kubernetes.default.svc"
+	defaultServiceAccountCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	defaultServiceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// KubernetesConfig configures reads of Kubernetes secrets through the API
+// server, using the pod's service account by default. References name the
+// secret and key, optionally with a namespace:
+// ${k8s:panda/clickhouse-credentials#password}.
+type KubernetesConfig struct {
+	// Namespace is used for references without one (default: the pod's
+	// namespace).
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// APIServer is the Kubernetes API URL (default: the in-cluster service).
+	APIServer string `yaml:"api_server,omitempty"`
+
+	// TokenFile is the bearer token file (default: the service account token).
+	TokenFile string `yaml:"token_file,omitempty"`
+
+	// CAFile verifies the API server (default: the service account CA).
+	CAFile string `yaml:"ca_file,omitempty"`
+}
+
+// KubernetesProvider reads secrets from the Kubernetes API.
+type KubernetesProvider struct {
+	cfg       KubernetesConfig
+	client    *http.Client
+	namespace string
+}
+
+// NewKubernetesProvider creates a Kubernetes provider.
+func NewKubernetesProvider(cfg KubernetesConfig) (*KubernetesProvider, error) {
+	if cfg.APIServer == "" {
+		cfg.APIServer = defaultKubernetesAPIServer
+	}
+
+	if cfg.TokenFile == "" {
+		cfg.TokenFile = defaultServiceAccountTokenFile
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	caFile := cfg.CAFile
+	if caFile == "" {
+		caFile = defaultServiceAccountCAFile
+	}
+
+	if pem, err := os.ReadFile(caFile); err == nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", caFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	} else if cfg.CAFile != "" {
+		return nil, fmt.Errorf("reading ca_file: %w", err)
+	}
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		if data, err := os.ReadFile(defaultServiceAccountNamespace); err == nil {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	return &KubernetesProvider{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: DefaultTimeout, Transport: transport},
+		namespace: namespace,
+	}, nil
+}
+
+// Get returns key from the secret named by path, "<name>" or
+// "<namespace>/<name>".
+func (p *KubernetesProvider) Get(ctx context.Context, path, key string) (string, error) {
+	namespace, name, ok := strings.Cut(path, "/")
+	if !ok {
+		namespace, name = p.namespace, path
+	}
+
+	if namespace == "" {
+		return "", fmt.Errorf("no namespace for secret %q; set secrets.kubernetes.namespace", name)
+	}
+
+	// The token is read per request since kubelet rotates projected tokens.
+	token, err := os.ReadFile(p.cfg.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading service account token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s",
+		strings.TrimRight(p.cfg.APIServer, "/"), url.PathEscape(namespace), url.PathEscape(name))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating kubernetes request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting kubernetes secret: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("reading kubernetes response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("kubernetes returned %d for secret %s/%s", resp.StatusCode, namespace, name)
+	}
+
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("decoding kubernetes secret: %w", err)
+	}
+
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %s/%s", key, namespace, name)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding key %q of secret %s/%s: %w", key, namespace, name, err)
+	}
+
+	return string(value), nil
+}
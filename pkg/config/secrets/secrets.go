@@ -0,0 +1,167 @@
+// Package secrets resolves ${vault:...} and ${k8s:...} references in config
+// files against HashiCorp Vault and Kubernetes secrets.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each request to a secret store.
+const DefaultTimeout = 10 * time.Second
+
+// Config configures the secret stores config files may reference.
+type Config struct {
+	// Vault enables ${vault:<path>#<key>} references.
+	Vault *VaultConfig `yaml:"vault,omitempty"`
+
+	// Kubernetes enables ${k8s:[<namespace>/]<name>#<key>} references.
+	Kubernetes *KubernetesConfig `yaml:"kubernetes,omitempty"`
+
+	// RefreshInterval re-resolves references this often so rotated secrets
+	// are picked up without a restart. 0 resolves them once at startup.
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+}
+
+// Validate checks the secrets config.
+func (c *Config) Validate() error {
+	if c.RefreshInterval < 0 {
+		return fmt.Errorf("refresh_interval cannot be negative")
+	}
+
+	if c.Vault != nil {
+		if err := c.Vault.validate(); err != nil {
+			return fmt.Errorf("vault: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Provider reads secrets from one store.
+type Provider interface {
+	// Get returns the value of key in the secret at path.
+	Get(ctx context.Context, path, key string) (string, error)
+}
+
+// refPattern matches ${vault:<ref>} and ${k8s:<ref>}.
+var refPattern = regexp.MustCompile(`\$\{(vault|k8s):([^}]+)\}`)
+
+// HasRefs reports whether content references a secret store outside of
+// YAML comments.
+func HasRefs(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") && refPattern.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply resolves the secret references in content with the stores in cfg.
+// Content without references is returned unchanged.
+func Apply(ctx context.Context, cfg Config, content string) (string, error) {
+	if !HasRefs(content) {
+		return content, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return "", fmt.Errorf("secrets.%w", err)
+	}
+
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		return "", fmt.Errorf("secrets.%w", err)
+	}
+
+	return resolver.Substitute(ctx, content)
+}
+
+// Resolver substitutes secret references using the configured providers.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver for the stores configured in cfg.
+func NewResolver(cfg Config) (*Resolver, error) {
+	r := &Resolver{providers: make(map[string]Provider, 2)}
+
+	if cfg.Vault != nil {
+		r.providers["vault"] = NewVaultProvider(*cfg.Vault)
+	}
+
+	if cfg.Kubernetes != nil {
+		provider, err := NewKubernetesProvider(*cfg.Kubernetes)
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes: %w", err)
+		}
+
+		r.providers["k8s"] = provider
+	}
+
+	return r, nil
+}
+
+// Substitute replaces every secret reference in content with its value.
+// Lines that are YAML comments are skipped, like env var substitution.
+func (r *Resolver) Substitute(ctx context.Context, content string) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		var resolveErr error
+
+		lines[i] = refPattern.ReplaceAllStringFunc(line, func(match string) string {
+			if resolveErr != nil {
+				return match
+			}
+
+			parts := refPattern.FindStringSubmatch(match)
+
+			value, err := r.resolve(ctx, parts[1], parts[2])
+			if err != nil {
+				resolveErr = fmt.Errorf("resolving %s: %w", match, err)
+
+				return match
+			}
+
+			return value
+		})
+
+		if resolveErr != nil {
+			return "", resolveErr
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// resolve reads one "<path>#<key>" reference from the scheme's provider.
+func (r *Resolver) resolve(ctx context.Context, scheme, ref string) (string, error) {
+	provider, ok := r.providers[scheme]
+	if !ok {
+		return "", fmt.Errorf("secrets.%s is not configured", providerSection(scheme))
+	}
+
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || strings.TrimSpace(path) == "" || strings.TrimSpace(key) == "" {
+		return "", fmt.Errorf("reference must have the form <path>#<key>")
+	}
+
+	return provider.Get(ctx, strings.TrimSpace(path), strings.TrimSpace(key))
+}
+
+func providerSection(scheme string) string {
+	if scheme == "k8s" {
+		return "kubernetes"
+	}
+
+	return scheme
+}
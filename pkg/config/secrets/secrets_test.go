@@ -0,0 +1,150 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVault(t *testing.T) {
+	t.Parallel()
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/panda/clickhouse":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data":     map[string]any{"password": "s3cret"},
+					"metadata": map[string]any{"version": 3},
+				},
+			})
+		case "/v1/kv/redis":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"url": "redis://cache:6379"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(vault.Close)
+
+	cfg := Config{Vault: &VaultConfig{Address: vault.URL, Token: "root"}}
+	content := "password: ${vault:secret/data/panda/clickhouse#password}\n" +
+		"# password: ${vault:does/not/exist#key}\n" +
+		"redis: \"${vault:kv/redis#url}\""
+
+	resolved, err := Apply(context.Background(), cfg, content)
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cret\n"+
+		"# password: ${vault:does/not/exist#key}\n"+
+		"redis: \"redis://cache:6379\"", resolved)
+
+	_, err = Apply(context.Background(), cfg, "password: ${vault:secret/data/panda/clickhouse#username}")
+	require.ErrorContains(t, err, `key "username" not found`)
+}
+
+func TestApplyVaultKubernetesAuth(t *testing.T) {
+	t.Parallel()
+
+	logins := 0
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			if body["role"] != "panda" || body["jwt"] != "sa-token" {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			logins++
+			_ = json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"client_token": "issued"}})
+		case "/v1/kv/proxy":
+			if r.Header.Get("X-Vault-Token") != "issued" {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"token": "abc"}})
+		}
+	}))
+	t.Cleanup(vault.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sa-token\n"), 0o600))
+
+	cfg := Config{Vault: &VaultConfig{Address: vault.URL, KubernetesRole: "panda", TokenFile: tokenFile}}
+
+	resolved, err := Apply(context.Background(), cfg, "token: ${vault:kv/proxy#token}\nagain: ${vault:kv/proxy#token}")
+	require.NoError(t, err)
+	assert.Equal(t, "token: abc\nagain: abc", resolved)
+	assert.Equal(t, 1, logins)
+}
+
+func TestApplyKubernetes(t *testing.T) {
+	t.Parallel()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/namespaces/panda/secrets/clickhouse", "/api/v1/namespaces/other/secrets/clickhouse":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]string{"password": base64.StdEncoding.EncodeToString([]byte("from-" + strings.Split(r.URL.Path, "/")[4]))},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sa-token"), 0o600))
+
+	cfg := Config{Kubernetes: &KubernetesConfig{Namespace: "panda", APIServer: api.URL, TokenFile: tokenFile}}
+
+	resolved, err := Apply(context.Background(), cfg, "a: ${k8s:clickhouse#password}\nb: ${k8s:other/clickhouse#password}")
+	require.NoError(t, err)
+	assert.Equal(t, "a: from-panda\nb: from-other", resolved)
+
+	_, err = Apply(context.Background(), cfg, "a: ${k8s:missing#password}")
+	require.ErrorContains(t, err, "kubernetes returned 404")
+}
+
+func TestApplyErrors(t *testing.T) {
+	t.Parallel()
+
+	unchanged, err := Apply(context.Background(), Config{}, "password: ${CLICKHOUSE_PASSWORD}")
+	require.NoError(t, err)
+	assert.Equal(t, "password: ${CLICKHOUSE_PASSWORD}", unchanged)
+
+	_, err = Apply(context.Background(), Config{}, "password: ${vault:kv/a#b}")
+	require.ErrorContains(t, err, "secrets.vault is not configured")
+
+	_, err = Apply(context.Background(), Config{Vault: &VaultConfig{Address: "http://vault", Token: "t"}}, "password: ${vault:kv/a}")
+	require.ErrorContains(t, err, "<path>#<key>")
+
+	_, err = Apply(context.Background(), Config{RefreshInterval: -1}, "password: ${k8s:a#b}")
+	require.ErrorContains(t, err, "refresh_interval cannot be negative")
+}
@@ -0,0 +1,262 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultServiceAccountTokenFile is where Kubernetes mounts the pod's
+// service account token.
+const defaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// VaultConfig configures reads from HashiCorp Vault. References name the
+// secret's API path without the /v1/ prefix, e.g.
+// ${vault:secret/data/panda/clickhouse#password} for a KV v2 mount.
+type VaultConfig struct {
+	// Address is the Vault URL (default: $VAULT_ADDR).
+	Address string `yaml:"address,omitempty"`
+
+	// Token authenticates to Vault (default: $VAULT_TOKEN). Ignored when
+	// kubernetes_role is set.
+	Token string `yaml:"token,omitempty"`
+
+	// Namespace is the Vault Enterprise namespace (default: $VAULT_NAMESPACE).
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// KubernetesRole logs in with the pod's service account token through
+	// Vault's kubernetes auth method instead of using a static token.
+	KubernetesRole string `yaml:"kubernetes_role,omitempty"`
+
+	// KubernetesMount is the kubernetes auth method's mount path
+	// (default: kubernetes).
+	KubernetesMount string `yaml:"kubernetes_mount,omitempty"`
+
+	// TokenFile is the service account token used for kubernetes auth
+	// (default: the in-cluster service account token).
+	TokenFile string `yaml:"token_file,omitempty"`
+}
+
+func (c *VaultConfig) validate() error {
+	if c.address() == "" {
+		return fmt.Errorf("address is required (or set VAULT_ADDR)")
+	}
+
+	if c.KubernetesRole == "" && c.token() == "" {
+		return fmt.Errorf("token or kubernetes_role is required (or set VAULT_TOKEN)")
+	}
+
+	return nil
+}
+
+func (c *VaultConfig) address() string {
+	if c.Address != "" {
+		return strings.TrimRight(c.Address, "/")
+	}
+
+	return strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+}
+
+func (c *VaultConfig) token() string {
+	if c.Token != "" {
+		return c.Token
+	}
+
+	return os.Getenv("VAULT_TOKEN")
+}
+
+// VaultProvider reads secrets over Vault's HTTP API.
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewVaultProvider creates a Vault provider.
+func NewVaultProvider(cfg VaultConfig) *VaultProvider {
+	p := &VaultProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: DefaultTimeout},
+	}
+
+	if cfg.KubernetesRole == "" {
+		p.token = cfg.token()
+	}
+
+	return p
+}
+
+// Get returns key from the secret at path. KV v2 responses, which nest the
+// secret under data.data, are unwrapped.
+func (p *VaultProvider) Get(ctx context.Context, path, key string) (string, error) {
+	body, status, err := p.read(ctx, path)
+	if err == nil && status == http.StatusForbidden && p.cfg.KubernetesRole != "" {
+		// The login token expired; log in again once.
+		p.setToken("")
+		body, status, err = p.read(ctx, path)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if status != http.StatusOK {
+		return "", fmt.Errorf("vault returned %d for %s: %s", status, path, strings.TrimSpace(string(body)))
+	}
+
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decoding vault response: %w", err)
+	}
+
+	data := resp.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in %s", key, path)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+func (p *VaultProvider) read(ctx context.Context, path string) ([]byte, int, error) {
+	token, err := p.currentToken(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.address()+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating vault request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	p.setNamespace(req)
+
+	return p.do(req)
+}
+
+// currentToken returns the Vault token, logging in with kubernetes auth
+// when none is cached.
+func (p *VaultProvider) currentToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" {
+		return p.token, nil
+	}
+
+	if p.cfg.KubernetesRole == "" {
+		return "", fmt.Errorf("no vault token configured")
+	}
+
+	tokenFile := p.cfg.TokenFile
+	if tokenFile == "" {
+		tokenFile = defaultServiceAccountTokenFile
+	}
+
+	jwt, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading service account token: %w", err)
+	}
+
+	mount := p.cfg.KubernetesMount
+	if mount == "" {
+		mount = "kubernetes"
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"role": p.cfg.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding vault login: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.cfg.address()+"/v1/auth/"+strings.Trim(mount, "/")+"/login", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("creating vault login request: %w", err)
+	}
+
+	p.setNamespace(req)
+
+	body, status, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if status != http.StatusOK {
+		return "", fmt.Errorf("vault login returned %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decoding vault login: %w", err)
+	}
+
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault login returned no token")
+	}
+
+	p.token = resp.Auth.ClientToken
+
+	return p.token, nil
+}
+
+func (p *VaultProvider) setToken(token string) {
+	p.mu.Lock()
+	p.token = token
+	p.mu.Unlock()
+}
+
+func (p *VaultProvider) setNamespace(req *http.Request) {
+	namespace := p.cfg.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+}
+
+func (p *VaultProvider) do(req *http.Request) ([]byte, int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("requesting vault: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading vault response: %w", err)
+	}
+
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,387 @@
+// Package configschema generates JSON Schemas from YAML config structs and
+// checks YAML documents against them, reporting unknown keys, type
+// mismatches and deprecated fields.
+package configschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// draft is the JSON Schema dialect of generated schemas.
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema generated from config structs.
+type Schema struct {
+	Draft  string `json:"$schema,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+
+	// Properties lists the keys of a struct. Structs are closed:
+	// AdditionalProperties is false.
+	Properties map[string]*Schema `json:"properties,omitempty"`
+
+	// AdditionalProperties is false for structs and the value schema for
+	// maps.
+	AdditionalProperties any     `json:"additionalProperties,omitempty"`
+	Items                *Schema `json:"items,omitempty"`
+
+	// Deprecated marks a field that is still accepted but should be
+	// replaced. DeprecationMessage says with what.
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"x-deprecation-message,omitempty"`
+
+	// Defs holds named sub-schemas, such as each module's config.
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	durationType    = reflect.TypeFor[time.Duration]()
+	timeType        = reflect.TypeFor[time.Time]()
+	unmarshalerType = reflect.TypeFor[yaml.Unmarshaler]()
+)
+
+// Generate returns the schema of v, a config struct decoded with yaml.v3.
+// Field names come from yaml tags, and a `deprecated:"..."` tag marks a
+// deprecated field with the replacement to suggest. Types that decode
+// themselves with UnmarshalYAML accept any value.
+func Generate(v any) *Schema {
+	s := generate(reflect.TypeOf(v), make(map[reflect.Type]bool, 8))
+	s.Draft = draft
+
+	return s
+}
+
+func generate(t reflect.Type, inProgress map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return &Schema{}
+	}
+
+	switch t {
+	case durationType:
+		return &Schema{Type: "string", Format: "duration"}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}
+		}
+
+		return &Schema{Type: "array", Items: generate(t.Elem(), inProgress)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generate(t.Elem(), inProgress)}
+	case reflect.Struct:
+		if inProgress[t] {
+			return &Schema{Type: "object"}
+		}
+
+		inProgress[t] = true
+		defer delete(inProgress, t)
+
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema, t.NumField()), AdditionalProperties: false}
+		addFields(s, t, inProgress)
+
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds the yaml fields of struct t to s, flattening inline
+// fields the way yaml.v3 does.
+func addFields(s *Schema, t reflect.Type, inProgress map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if inlined(opts) {
+			inner := generate(field.Type, inProgress)
+
+			for key, prop := range inner.Properties {
+				s.Properties[key] = prop
+			}
+
+			if inner.Properties == nil {
+				s.AdditionalProperties = inner.AdditionalProperties
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		prop := generate(field.Type, inProgress)
+
+		if message, ok := field.Tag.Lookup("deprecated"); ok {
+			prop.Deprecated = true
+			prop.DeprecationMessage = message
+		}
+
+		s.Properties[name] = prop
+	}
+}
+
+func inlined(opts string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "inline" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Issue severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a problem found in a YAML document.
+type Issue struct {
+	// Path is the dotted key path, such as "server.port" or "items[2].name".
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Path, i.Message)
+}
+
+// Validate checks the YAML document in content against s.
+func Validate(s *Schema, content []byte) ([]Issue, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	v := &validator{issues: make([]Issue, 0, 4)}
+	v.walk(s, doc.Content[0], "")
+
+	return v.issues, nil
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func (v *validator) add(node *yaml.Node, path, severity, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+
+	v.issues = append(v.issues, Issue{
+		Path:     path,
+		Line:     node.Line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) walk(s *Schema, node *yaml.Node, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if s.Type == "" || node.Tag == "!!null" {
+		return
+	}
+
+	if !matchesType(s, node) {
+		v.add(node, path, SeverityError, "expected %s, got %s", describeSchema(s), describeNode(node))
+
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		v.walkObject(s, node, path)
+	case "array":
+		for i, item := range node.Content {
+			v.walk(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (v *validator) walkObject(s *Schema, node *yaml.Node, path string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		// Merge keys pull in an anchored mapping checked where it's defined.
+		if key.Value == "<<" {
+			continue
+		}
+
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "." + key.Value
+		}
+
+		if s.Properties == nil {
+			if values, ok := s.AdditionalProperties.(*Schema); ok {
+				v.walk(values, value, keyPath)
+			}
+
+			continue
+		}
+
+		prop, ok := s.Properties[key.Value]
+		if !ok {
+			if suggestion := closestKey(key.Value, s.Properties); suggestion != "" {
+				v.add(key, keyPath, SeverityError, "unknown key (did you mean %q?)", suggestion)
+			} else {
+				v.add(key, keyPath, SeverityError, "unknown key")
+			}
+
+			continue
+		}
+
+		if prop.Deprecated {
+			v.add(key, keyPath, SeverityWarning, "deprecated: %s", prop.DeprecationMessage)
+		}
+
+		v.walk(prop, value, keyPath)
+	}
+}
+
+// matchesType reports whether yaml.v3 can decode node into a value of s.
+func matchesType(s *Schema, node *yaml.Node) bool {
+	switch s.Type {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "duration" && node.Tag != "!!int" {
+			_, err := time.ParseDuration(node.Value)
+
+			return err == nil
+		}
+
+		return true
+	case "integer":
+		return node.Tag == "!!int"
+	case "number":
+		return node.Tag == "!!int" || node.Tag == "!!float"
+	case "boolean":
+		return node.Tag == "!!bool"
+	default:
+		return true
+	}
+}
+
+func describeSchema(s *Schema) string {
+	if s.Format == "duration" {
+		return "a duration such as 30s or 5m"
+	}
+
+	return s.Type
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.Tag {
+	case "!!int":
+		return fmt.Sprintf("integer %s", node.Value)
+	case "!!float":
+		return fmt.Sprintf("number %s", node.Value)
+	case "!!bool":
+		return fmt.Sprintf("boolean %s", node.Value)
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+// closestKey returns the known key within two edits of key, if any.
+func closestKey(key string, known map[string]*Schema) string {
+	best, bestDistance := "", 3
+
+	for candidate := range known {
+		if d := editDistance(key, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,119 @@
+package configschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	Name string `yaml:"name"`
+}
+
+type testConfig struct {
+	testBase `yaml:",inline"`
+
+	Port     int                    `yaml:"port"`
+	Enabled  *bool                  `yaml:"enabled,omitempty"`
+	Interval time.Duration          `yaml:"interval"`
+	Ratio    float64                `yaml:"ratio"`
+	Tags     []string               `yaml:"tags"`
+	Limits   map[string]testLimit   `yaml:"limits"`
+	Extra    map[string]any         `yaml:"extra"`
+	Mode     string                 `yaml:"mode" deprecated:"use profile instead"`
+	Profile  string                 `yaml:"profile"`
+	Ignored  string                 `yaml:"-"`
+	Nested   *testConfig            `yaml:"nested,omitempty"`
+	Raw      map[string]interface{} `yaml:",omitempty"`
+}
+
+type testLimit struct {
+	Max int `yaml:"max"`
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	s := Generate(testConfig{})
+
+	assert.Equal(t, draft, s.Draft)
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, false, s.AdditionalProperties)
+
+	assert.Equal(t, "string", s.Properties["name"].Type, "inline fields are flattened")
+	assert.Equal(t, "integer", s.Properties["port"].Type)
+	assert.Equal(t, "boolean", s.Properties["enabled"].Type)
+	assert.Equal(t, &Schema{Type: "string", Format: "duration"}, s.Properties["interval"])
+	assert.Equal(t, "number", s.Properties["ratio"].Type)
+	assert.Equal(t, "string", s.Properties["tags"].Items.Type)
+	assert.Equal(t, "integer", s.Properties["limits"].AdditionalProperties.(*Schema).Properties["max"].Type)
+	assert.Equal(t, &Schema{}, s.Properties["extra"].AdditionalProperties)
+	assert.True(t, s.Properties["mode"].Deprecated)
+	assert.Equal(t, "use profile instead", s.Properties["mode"].DeprecationMessage)
+	assert.Equal(t, "object", s.Properties["nested"].Type, "recursive types stop at the cycle")
+	assert.Contains(t, s.Properties, "raw")
+	assert.NotContains(t, s.Properties, "ignored")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	content := `name: api
+port: "80"
+interval: soon
+ratio: 1
+tags: [a, b]
+mode: fast
+limits:
+  cpu:
+    max: 2
+    maks: 3
+extra:
+  anything: [1, 2]
+nested:
+  prot: 1
+prot: 1
+profile: {}
+enabled:
+unknownsection: {}
+`
+
+	issues, err := Validate(Generate(testConfig{}), []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Issue{
+		{Path: "port", Line: 2, Severity: SeverityError, Message: `expected integer, got "80"`},
+		{Path: "interval", Line: 3, Severity: SeverityError, Message: `expected a duration such as 30s or 5m, got "soon"`},
+		{Path: "mode", Line: 6, Severity: SeverityWarning, Message: "deprecated: use profile instead"},
+		{Path: "limits.cpu.maks", Line: 10, Severity: SeverityError, Message: `unknown key (did you mean "max"?)`},
+		{Path: "prot", Line: 15, Severity: SeverityError, Message: `unknown key (did you mean "port"?)`},
+		{Path: "profile", Line: 16, Severity: SeverityError, Message: "expected string, got object"},
+		{Path: "unknownsection", Line: 18, Severity: SeverityError, Message: "unknown key"},
+	}, issues)
+}
+
+func TestValidateAnchors(t *testing.T) {
+	t.Parallel()
+
+	content := `limits:
+  base: &base
+    max: 1
+  cpu:
+    <<: *base
+  mem: *base
+tags: &tags [x]
+`
+
+	issues, err := Validate(Generate(testConfig{}), []byte(content))
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	issues, err = Validate(Generate(testConfig{}), []byte(""))
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	_, err = Validate(Generate(testConfig{}), []byte("port: [\n"))
+	require.ErrorContains(t, err, "parsing YAML")
+}
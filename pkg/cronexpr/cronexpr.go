@@ -0,0 +1,197 @@
+// Package cronexpr parses five-field cron expressions and computes when
+// they next match.
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the supported @ shorthands.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField bounds one field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// 7 is accepted as Sunday and folded into 0.
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Expr is a parsed five-field cron expression, evaluated in UTC.
+type Expr struct {
+	expr string
+
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record day fields starting with *. When both day
+	// fields are restricted a day matching either is due, as in cron.
+	domAny, dowAny bool
+}
+
+// Parse parses "minute hour day-of-month month day-of-week", with *,
+// lists, ranges and steps in each field, or one of @hourly, @daily,
+// @midnight, @weekly, @monthly, @yearly and @annually.
+func Parse(expr string) (*Expr, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(parts))
+	}
+
+	var sets [5]uint64
+
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+
+		sets[i] = set
+	}
+
+	// Fold Sunday as 7 into 0.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &Expr{
+		expr:   strings.TrimSpace(expr),
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a comma-separated field into a bit set.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var set uint64
+
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, bounds.name)
+			}
+
+			step = n
+		}
+
+		low, high := bounds.min, bounds.max
+
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+
+			var err error
+			if low, err = parseCronValue(lowPart, bounds); err != nil {
+				return 0, err
+			}
+
+			if high, err = parseCronValue(highPart, bounds); err != nil {
+				return 0, err
+			}
+
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, bounds.name)
+			}
+		default:
+			value, err := parseCronValue(rangePart, bounds)
+			if err != nil {
+				return 0, err
+			}
+
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+func parseCronValue(value string, bounds cronField) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < bounds.min || n > bounds.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (%d-%d)", value, bounds.name, bounds.min, bounds.max)
+	}
+
+	return n, nil
+}
+
+// String returns the expression as given.
+func (c *Expr) String() string {
+	return c.expr
+}
+
+// Next returns the first time after t that the expression matches, in UTC,
+// or the zero time if it never does (such as February 30th).
+func (c *Expr) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+
+	// Every schedule repeats within four years (leap days included).
+	limit := t.AddDate(4, 0, 1)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *Expr) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
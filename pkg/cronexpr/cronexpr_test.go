@@ -0,0 +1,67 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	// A Tuesday.
+	from := time.Date(2026, 3, 10, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2026, 3, 10, 12, 35, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2026, 3, 10, 12, 45, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", want: time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 9 * * 1-5", want: time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)},
+		{expr: "0 0 1 */3 *", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0,30 12 10 3 *", want: time.Date(2027, 3, 10, 12, 0, 0, 0, time.UTC)},
+		{expr: "45,50 12 10 3 *", want: time.Date(2026, 3, 10, 12, 45, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches.
+		{expr: "0 0 13 * 5", want: time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.Next(from))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@sometimes",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -39,13 +39,9 @@ func NewRegistry(
 ) (*Registry, error) {
 	log = log.WithField("component", "eip_registry")
 
-	if cacheDir == "" {
-		userCache, err := os.UserCacheDir()
-		if err != nil {
-			return nil, fmt.Errorf("determining cache directory: %w", err)
-		}
-
-		cacheDir = filepath.Join(userCache, "ethpandaops-panda", "eips")
+	cacheDir, err := resolveCacheDir(cacheDir)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
@@ -101,6 +97,34 @@ func NewRegistry(
 	return buildRegistry(log, cacheDir, newCache), nil
 }
 
+// NewCachedRegistry creates an EIP registry from the disk cache only,
+// without contacting GitHub.
+func NewCachedRegistry(log logrus.FieldLogger, cacheDir string) (*Registry, error) {
+	log = log.WithField("component", "eip_registry")
+
+	cacheDir, err := resolveCacheDir(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadFromCache(log, cacheDir)
+}
+
+// resolveCacheDir returns cacheDir, or the default user cache location
+// when it is empty.
+func resolveCacheDir(cacheDir string) (string, error) {
+	if cacheDir != "" {
+		return cacheDir, nil
+	}
+
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("determining cache directory: %w", err)
+	}
+
+	return filepath.Join(userCache, "ethpandaops-panda", "eips"), nil
+}
+
 // All returns a copy of all EIPs.
 func (r *Registry) All() []types.EIP {
 	r.mu.RLock()
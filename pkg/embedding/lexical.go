@@ -0,0 +1,77 @@
+package embedding
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// lexicalDimensions is the size of the hashed term vectors.
+const lexicalDimensions = 1024
+
+// LexicalEmbedder implements Embedder with hashed term-frequency vectors.
+// It needs no network access or model, so it backs search in offline mode;
+// similarity reflects shared terms rather than meaning.
+type LexicalEmbedder struct{}
+
+// Compile-time interface check.
+var _ Embedder = (*LexicalEmbedder)(nil)
+
+// NewLexical creates a new LexicalEmbedder.
+func NewLexical() *LexicalEmbedder {
+	return &LexicalEmbedder{}
+}
+
+// Embed returns the L2-normalized term vector for text. Text without any
+// terms yields a zero vector.
+func (e *LexicalEmbedder) Embed(text string) ([]float32, error) {
+	vec := make([]float32, lexicalDimensions)
+
+	terms := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, term := range terms {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(term))
+		vec[h.Sum32()%lexicalDimensions]++
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+
+	if norm == 0 {
+		return vec, nil
+	}
+
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vec {
+		vec[i] *= scale
+	}
+
+	return vec, nil
+}
+
+// EmbedBatch returns L2-normalized term vectors for multiple texts.
+func (e *LexicalEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+
+	for i, text := range texts {
+		vec, err := e.Embed(text)
+		if err != nil {
+			return nil, err
+		}
+
+		vectors[i] = vec
+	}
+
+	return vectors, nil
+}
+
+// Close is a no-op.
+func (e *LexicalEmbedder) Close() error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package embedding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+
+	return sum
+}
+
+func TestLexicalEmbedder(t *testing.T) {
+	t.Parallel()
+
+	e := NewLexical()
+
+	vectors, err := e.EmbedBatch([]string{
+		"Block proposal latency by client",
+		"block PROPOSAL latency, by client!",
+		"attestation inclusion distance",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+
+	assert.InDelta(t, 1.0, dot(vectors[0], vectors[0]), 1e-5, "vectors are normalized")
+	assert.InDelta(t, 1.0, dot(vectors[0], vectors[1]), 1e-5, "case and punctuation are ignored")
+	assert.Less(t, dot(vectors[0], vectors[2]), dot(vectors[0], vectors[1]))
+	assert.Zero(t, dot(vectors[3], vectors[3]), "empty text yields a zero vector")
+
+	query, err := e.Embed("proposal latency")
+	require.NoError(t, err)
+	assert.Greater(t, dot(query, vectors[0]), dot(query, vectors[2]))
+}
@@ -0,0 +1,248 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/cache"
+)
+
+// maxOpenAIBatchSize limits how many inputs are sent per embeddings request.
+const maxOpenAIBatchSize = 100
+
+// OpenAIConfig configures an OpenAIEmbedder.
+type OpenAIConfig struct {
+	// URL is the API base URL; requests go to URL + "/embeddings".
+	URL string
+	// Model is the embedding model name.
+	Model string
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+	// Dimensions requests shorter vectors from models that support it.
+	// Zero leaves the model's default.
+	Dimensions int
+}
+
+// openAIRequest is the request body for an OpenAI-compatible embeddings API.
+type openAIRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// openAIResponse is the response body from an OpenAI-compatible embeddings API.
+type openAIResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// OpenAIEmbedder implements Embedder by calling an OpenAI-compatible
+// embeddings endpoint directly, for deployments without an embedding proxy.
+// An optional local cache avoids re-embedding unchanged texts on restart.
+type OpenAIEmbedder struct {
+	log        logrus.FieldLogger
+	cfg        OpenAIConfig
+	httpClient *http.Client
+	localCache cache.Cache
+}
+
+// Compile-time interface check.
+var _ Embedder = (*OpenAIEmbedder)(nil)
+
+// NewOpenAI creates a new OpenAIEmbedder. localCache is optional.
+func NewOpenAI(log logrus.FieldLogger, cfg OpenAIConfig, localCache cache.Cache) *OpenAIEmbedder {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+
+	return &OpenAIEmbedder{
+		log:        log.WithField("component", "openai-embedder"),
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: remoteEmbedTimeout},
+		localCache: localCache,
+	}
+}
+
+// Embed returns the L2-normalized embedding vector for a single text string.
+func (e *OpenAIEmbedder) Embed(text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch([]string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return vectors[0], nil
+}
+
+// EmbedBatch returns L2-normalized embedding vectors for multiple texts.
+// Vectors in the local cache are reused; the rest are requested in batches
+// of maxOpenAIBatchSize.
+func (e *OpenAIEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	vectors := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+
+	for i, text := range texts {
+		keys[i] = e.cacheKey(text)
+	}
+
+	if e.localCache != nil {
+		cached, err := e.localCache.GetMulti(context.Background(), keys)
+		if err != nil {
+			e.log.WithError(err).Warn("Local cache read failed, embedding all texts")
+		}
+
+		for i, key := range keys {
+			if data, ok := cached[key]; ok {
+				var vec []float32
+				if err := json.Unmarshal(data, &vec); err == nil {
+					vectors[i] = vec
+				}
+			}
+		}
+	}
+
+	var misses []int
+
+	for i, v := range vectors {
+		if v == nil {
+			misses = append(misses, i)
+		}
+	}
+
+	toCache := make(map[string][]byte, len(misses))
+
+	for start := 0; start < len(misses); start += maxOpenAIBatchSize {
+		batch := misses[start:min(start+maxOpenAIBatchSize, len(misses))]
+
+		input := make([]string, len(batch))
+		for j, idx := range batch {
+			input[j] = texts[idx]
+		}
+
+		embedded, err := e.callEmbeddings(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, idx := range batch {
+			vectors[idx] = normalize(embedded[j])
+
+			if data, err := json.Marshal(vectors[idx]); err == nil {
+				toCache[keys[idx]] = data
+			}
+		}
+	}
+
+	if e.localCache != nil && len(toCache) > 0 {
+		if err := e.localCache.SetMulti(context.Background(), toCache); err != nil {
+			e.log.WithError(err).Warn("Failed to write local embedding cache")
+		}
+	}
+
+	return vectors, nil
+}
+
+// Close releases resources held by the embedder.
+func (e *OpenAIEmbedder) Close() error {
+	if e.localCache != nil {
+		return e.localCache.Close()
+	}
+
+	return nil
+}
+
+func (e *OpenAIEmbedder) cacheKey(text string) string {
+	model := e.cfg.Model
+	if e.cfg.Dimensions > 0 {
+		model = fmt.Sprintf("%s@%d", model, e.cfg.Dimensions)
+	}
+
+	return model + ":" + sha256Hex(text)
+}
+
+// callEmbeddings embeds texts and returns their vectors in input order.
+func (e *OpenAIEmbedder) callEmbeddings(texts []string) ([][]float32, error) {
+	reqBody, err := json.Marshal(openAIRequest{Model: e.cfg.Model, Input: texts, Dimensions: e.cfg.Dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodPost,
+		e.cfg.URL+"/embeddings", bytes.NewReader(reqBody),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating embeddings request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if e.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling embeddings API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return nil, fmt.Errorf("embeddings API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("decoding embeddings response: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+
+	for _, item := range apiResp.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("embeddings API returned invalid index %d", item.Index)
+		}
+
+		vectors[item.Index] = item.Embedding
+	}
+
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embeddings API returned no vector for input %d", i)
+		}
+	}
+
+	return vectors, nil
+}
+
+// normalize scales vec to unit length in place.
+func normalize(vec []float32) []float32 {
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+
+	if norm == 0 {
+		return vec
+	}
+
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vec {
+		vec[i] *= scale
+	}
+
+	return vec
+}
@@ -0,0 +1,98 @@
+package embedding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockOpenAI creates a test server answering /embeddings with a vector
+// of {len(input), index} for each input, reversed to check index ordering.
+func newMockOpenAI(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		calls.Add(1)
+
+		var req openAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+
+		data := make([]item, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, item{Index: i, Embedding: []float32{float32(len(req.Input[i])), 0}})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestOpenAIEmbedder_EmbedBatch(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := newMockOpenAI(t, &calls)
+	embedder := NewOpenAI(logrus.New(), OpenAIConfig{URL: srv.URL + "/v1/", Model: "test-model", APIKey: "secret"}, nil)
+
+	vectors, err := embedder.EmbedBatch([]string{"a", "bbb"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 2)
+
+	// Vectors come back in input order and L2-normalized.
+	assert.Equal(t, []float32{1, 0}, vectors[0])
+	assert.Equal(t, []float32{1, 0}, vectors[1])
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestOpenAIEmbedder_Batches(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := newMockOpenAI(t, &calls)
+	embedder := NewOpenAI(logrus.New(), OpenAIConfig{URL: srv.URL + "/v1", Model: "test-model", APIKey: "secret"}, nil)
+
+	texts := make([]string, maxOpenAIBatchSize+1)
+	for i := range texts {
+		texts[i] = "text"
+	}
+
+	vectors, err := embedder.EmbedBatch(texts)
+	require.NoError(t, err)
+	assert.Len(t, vectors, len(texts))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestOpenAIEmbedder_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+
+	embedder := NewOpenAI(logrus.New(), OpenAIConfig{URL: srv.URL, Model: "test-model"}, nil)
+
+	_, err := embedder.Embed("hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
@@ -63,6 +63,7 @@ type RemoteEmbedder struct {
 	proxyURL   string
 	httpClient *http.Client
 	tokenFn    func() string
+	signFn     func(*http.Request) error
 	localCache cache.Cache
 	model      string
 }
@@ -91,6 +92,18 @@ func NewRemote(
 	}
 }
 
+// SetRequestSigner sets an optional function used to sign each request to the
+// proxy after the bearer token is attached.
+func (e *RemoteEmbedder) SetRequestSigner(signFn func(*http.Request) error) {
+	e.signFn = signFn
+}
+
+// SetTransport sets the round tripper used for requests to the proxy, e.g.
+// one presenting a client certificate to an mTLS proxy.
+func (e *RemoteEmbedder) SetTransport(transport http.RoundTripper) {
+	e.httpClient.Transport = transport
+}
+
 // Embed returns the L2-normalized embedding vector for a single text string.
 func (e *RemoteEmbedder) Embed(text string) ([]float32, error) {
 	vectors, err := e.EmbedBatch([]string{text})
@@ -355,6 +368,21 @@ func (e *RemoteEmbedder) embedDirect(
 	return vectors, nil
 }
 
+// authorize attaches the bearer token and, when configured, the request signature.
+func (e *RemoteEmbedder) authorize(req *http.Request) error {
+	if token := e.tokenFn(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if e.signFn != nil {
+		if err := e.signFn(req); err != nil {
+			return fmt.Errorf("signing request: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (e *RemoteEmbedder) checkCached(hashes []string) ([]embedResult, error) {
 	reqBody, err := json.Marshal(embedCheckRequest{Hashes: hashes})
 	if err != nil {
@@ -371,8 +399,8 @@ func (e *RemoteEmbedder) checkCached(hashes []string) ([]embedResult, error) {
 
 	req.Header.Set("Content-Type", "application/json")
 
-	if token := e.tokenFn(); token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+	if err := e.authorize(req); err != nil {
+		return nil, err
 	}
 
 	resp, err := e.httpClient.Do(req)
@@ -411,8 +439,8 @@ func (e *RemoteEmbedder) callEmbed(items []embedItem) (*embedResponse, error) {
 
 	req.Header.Set("Content-Type", "application/json")
 
-	if token := e.tokenFn(); token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+	if err := e.authorize(req); err != nil {
+		return nil, err
 	}
 
 	resp, err := e.httpClient.Do(req)
@@ -0,0 +1,287 @@
+// Package exampleusage records which search examples were actually used, so
+// search can rank frequently useful examples first.
+package exampleusage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fileName is the stats file inside the store directory.
+	fileName = "usage.json"
+
+	// shownTTL is how long after a search its examples can be credited to an
+	// execution.
+	shownTTL = 30 * time.Minute
+
+	// maxShownPerOwner bounds how many shown examples are kept per owner.
+	maxShownPerOwner = 50
+
+	// minMatchTokens is the fewest distinct tokens an example query needs
+	// before executed code is compared against it; shorter queries match
+	// too much by chance.
+	minMatchTokens = 3
+
+	// matchFraction is the share of an example query's tokens that executed
+	// code must contain for the example to count as used.
+	matchFraction = 0.6
+)
+
+// tokenPattern matches identifiers and numbers in queries and code.
+var tokenPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|[0-9]+`)
+
+// Key identifies an example.
+type Key struct {
+	CategoryKey string
+	ExampleName string
+}
+
+// Stats counts the signals recorded for one example.
+type Stats struct {
+	CategoryKey string    `json:"category_key"`
+	ExampleName string    `json:"example_name"`
+	Uses        int       `json:"uses"`
+	NotUseful   int       `json:"not_useful"`
+	LastUsed    time.Time `json:"last_used,omitzero"`
+}
+
+// score is the example's net usefulness.
+func (st *Stats) score() int {
+	return max(st.Uses-st.NotUseful, 0)
+}
+
+// shownExample is a search result recently returned to an owner.
+type shownExample struct {
+	key     Key
+	tokens  []string
+	shownAt time.Time
+}
+
+// Store persists example usage stats to a JSON file and remembers the
+// examples recently shown to each owner.
+type Store struct {
+	mu    sync.Mutex
+	path  string
+	stats map[Key]*Stats
+	shown map[string][]shownExample
+	now   func() time.Time
+}
+
+// Open loads (or creates) the store in dir.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating example usage directory: %w", err)
+	}
+
+	s := &Store{
+		path:  filepath.Join(dir, fileName),
+		stats: make(map[Key]*Stats, 32),
+		shown: make(map[string][]shownExample, 8),
+		now:   time.Now,
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading example usage: %w", err)
+	}
+
+	var stored []Stats
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+
+	for i := range stored {
+		s.stats[Key{CategoryKey: stored[i].CategoryKey, ExampleName: stored[i].ExampleName}] = &stored[i]
+	}
+
+	return s, nil
+}
+
+// Shown remembers that the example with query was returned to ownerID, so a
+// successful execution of similar code soon after counts as a use.
+func (s *Store) Shown(ownerID string, key Key, query string) {
+	tokens := queryTokens(query)
+	if len(tokens) < minMatchTokens {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	shown := slices.DeleteFunc(s.shown[ownerID], func(e shownExample) bool {
+		return e.key == key || now.Sub(e.shownAt) > shownTTL
+	})
+
+	shown = append(shown, shownExample{key: key, tokens: tokens, shownAt: now})
+	if len(shown) > maxShownPerOwner {
+		shown = shown[len(shown)-maxShownPerOwner:]
+	}
+
+	s.shown[ownerID] = shown
+}
+
+// RecordExecution credits a use to each example recently shown to ownerID
+// whose query the code largely reuses. A shown example is credited at most
+// once. It returns the credited examples.
+func (s *Store) RecordExecution(ownerID, code string) ([]Key, error) {
+	codeTokens := make(map[string]struct{}, 64)
+	for _, token := range tokenPattern.FindAllString(strings.ToLower(code), -1) {
+		codeTokens[token] = struct{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	var credited []Key
+
+	remaining := s.shown[ownerID][:0]
+	for _, e := range s.shown[ownerID] {
+		if now.Sub(e.shownAt) > shownTTL {
+			continue
+		}
+
+		matched := 0
+		for _, token := range e.tokens {
+			if _, ok := codeTokens[token]; ok {
+				matched++
+			}
+		}
+
+		if float64(matched) < matchFraction*float64(len(e.tokens)) {
+			remaining = append(remaining, e)
+
+			continue
+		}
+
+		st := s.statsLocked(e.key)
+		st.Uses++
+		st.LastUsed = now
+		credited = append(credited, e.key)
+	}
+
+	if len(remaining) == 0 {
+		delete(s.shown, ownerID)
+	} else {
+		s.shown[ownerID] = remaining
+	}
+
+	if len(credited) == 0 {
+		return nil, nil
+	}
+
+	return credited, s.writeLocked()
+}
+
+// Rate records explicit feedback that an example was, or was not, useful.
+func (s *Store) Rate(key Key, useful bool) (Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.statsLocked(key)
+	if useful {
+		st.Uses++
+		st.LastUsed = s.now()
+	} else {
+		st.NotUseful++
+	}
+
+	return *st, s.writeLocked()
+}
+
+// Get returns the stats of an example; they are zero if it was never used.
+func (s *Store) Get(key Key) Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.stats[key]; ok {
+		return *st
+	}
+
+	return Stats{CategoryKey: key.CategoryKey, ExampleName: key.ExampleName}
+}
+
+// Popularity returns the example's net usefulness from 0 to 1, on a log
+// scale relative to the most useful example.
+func (s *Store) Popularity(key Key) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.stats[key]
+	if !ok || st.score() == 0 {
+		return 0
+	}
+
+	best := 0
+	for _, other := range s.stats {
+		best = max(best, other.score())
+	}
+
+	return math.Log1p(float64(st.score())) / math.Log1p(float64(best))
+}
+
+func (s *Store) statsLocked(key Key) *Stats {
+	st, ok := s.stats[key]
+	if !ok {
+		st = &Stats{CategoryKey: key.CategoryKey, ExampleName: key.ExampleName}
+		s.stats[key] = st
+	}
+
+	return st
+}
+
+// writeLocked replaces the stats file atomically. Callers must hold s.mu.
+func (s *Store) writeLocked() error {
+	stored := make([]Stats, 0, len(s.stats))
+	for _, st := range s.stats {
+		stored = append(stored, *st)
+	}
+
+	slices.SortFunc(stored, func(a, b Stats) int {
+		if c := strings.Compare(a.CategoryKey, b.CategoryKey); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ExampleName, b.ExampleName)
+	})
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling example usage: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing example usage: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replacing example usage: %w", err)
+	}
+
+	return nil
+}
+
+// queryTokens returns the distinct lowercase tokens of an example query.
+func queryTokens(query string) []string {
+	tokens := tokenPattern.FindAllString(strings.ToLower(query), -1)
+	slices.Sort(tokens)
+
+	return slices.Compact(tokens)
+}
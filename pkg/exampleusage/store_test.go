@@ -0,0 +1,86 @@
+package exampleusage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const blockQuery = "SELECT slot, block_root FROM beacon_api_eth_v1_events_block WHERE meta_network_name = 'mainnet'"
+
+func TestRecordExecution(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	block := Key{CategoryKey: "blocks", ExampleName: "Block events"}
+	other := Key{CategoryKey: "blobs", ExampleName: "Blob sidecars"}
+
+	store.Shown("alice", block, blockQuery)
+	store.Shown("alice", other, "SELECT blob_index, kzg_commitment FROM beacon_api_eth_v1_events_blob_sidecar")
+
+	// Code reusing the block query, lightly edited, credits only that example.
+	code := "df = clickhouse.query('xatu', \"\"\"" + blockQuery + " AND slot > 100 LIMIT 10\"\"\")"
+
+	credited, err := store.RecordExecution("alice", code)
+	require.NoError(t, err)
+	assert.Equal(t, []Key{block}, credited)
+	assert.Equal(t, 1, store.Get(block).Uses)
+	assert.Zero(t, store.Get(other).Uses)
+
+	// A shown example is credited once, and only to its owner.
+	credited, err = store.RecordExecution("alice", code)
+	require.NoError(t, err)
+	assert.Empty(t, credited)
+
+	store.Shown("bob", block, blockQuery)
+
+	credited, err = store.RecordExecution("alice", code)
+	require.NoError(t, err)
+	assert.Empty(t, credited)
+
+	// Results shown too long ago are not credited.
+	now = now.Add(shownTTL + time.Minute)
+
+	credited, err = store.RecordExecution("bob", code)
+	require.NoError(t, err)
+	assert.Empty(t, credited)
+}
+
+func TestRateAndPopularity(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	popular := Key{CategoryKey: "blocks", ExampleName: "Popular"}
+	rare := Key{CategoryKey: "blocks", ExampleName: "Rare"}
+
+	for range 3 {
+		_, err := store.Rate(popular, true)
+		require.NoError(t, err)
+	}
+
+	stats, err := store.Rate(rare, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Uses)
+
+	assert.InDelta(t, 1.0, store.Popularity(popular), 1e-9)
+	assert.Greater(t, store.Popularity(rare), 0.0)
+	assert.Less(t, store.Popularity(rare), 1.0)
+	assert.Zero(t, store.Popularity(Key{CategoryKey: "blocks", ExampleName: "Unused"}))
+
+	stats, err = store.Rate(rare, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.NotUseful)
+	assert.Zero(t, store.Popularity(rare))
+
+	reopened, err := Open(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reopened.Get(popular).Uses)
+	assert.Equal(t, 1, reopened.Get(rare).NotUseful)
+}
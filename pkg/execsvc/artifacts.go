@@ -0,0 +1,176 @@
+package execsvc
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+// artifactExtensions are the chart and image formats uploaded automatically
+// when an execution saves them to its workspace, mapped to whether a
+// thumbnail can be rendered for them.
+var artifactExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".svg":  false,
+	".webp": false,
+	".html": false,
+	".pdf":  false,
+}
+
+// thumbnailSuffix is appended to an artifact's name for its thumbnail key.
+const thumbnailSuffix = ".thumb.png"
+
+// SetStorage sets the storage workspace charts are uploaded to after each
+// execution. Without it no artifacts are returned.
+func (s *Service) SetStorage(storageSvc storage.Service) {
+	s.storage = storageSvc
+}
+
+// uploadArtifacts uploads the charts and images a session execution wrote
+// to /workspace since startedAt and attaches their URLs to result. Files
+// the code already uploaded itself with storage.upload() keep their URL.
+func (s *Service) uploadArtifacts(
+	ctx context.Context,
+	executionID string,
+	req ExecuteRequest,
+	startedAt time.Time,
+	result *sandbox.ExecutionResult,
+) {
+	cfg := s.cfg.Sandbox.Artifacts
+	if s.storage == nil || !cfg.IsEnabled() || result.SessionID == "" {
+		return
+	}
+
+	files := changedArtifacts(result.SessionFiles, startedAt, cfg)
+	if len(files) == 0 {
+		return
+	}
+
+	log := s.log.WithFields(logrus.Fields{
+		"execution_id": executionID,
+		"session_id":   result.SessionID,
+	})
+
+	uploaded := make(map[string]string, 4)
+
+	if existing, err := s.storage.List(executionID, ""); err == nil {
+		for _, file := range existing {
+			uploaded[file.Key] = file.URL
+		}
+	}
+
+	for _, file := range files {
+		data, err := s.sandboxSvc.ReadSessionFile(ctx, result.SessionID, req.OwnerID, file.Name)
+		if err != nil {
+			log.WithError(err).WithField("file", file.Name).Warn("Failed to read artifact from session")
+
+			continue
+		}
+
+		artifact := sandbox.Artifact{
+			Name:        file.Name,
+			URL:         uploaded[file.Name],
+			ContentType: storage.ContentType(file.Name, data),
+			Size:        int64(len(data)),
+		}
+
+		if artifact.URL == "" {
+			if _, artifact.URL, err = s.storage.Upload(executionID, file.Name, bytes.NewReader(data)); err != nil {
+				log.WithError(err).WithField("file", file.Name).Warn("Failed to upload artifact")
+
+				continue
+			}
+		}
+
+		if artifactExtensions[strings.ToLower(path.Ext(file.Name))] {
+			artifact.ThumbnailURL = s.uploadThumbnail(executionID, file.Name, data, cfg.ThumbnailSize, log)
+		}
+
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+
+	if len(result.Artifacts) == 0 {
+		return
+	}
+
+	if err := s.storage.SetMetadata(storage.Metadata{
+		ExecutionID: executionID,
+		SessionID:   result.SessionID,
+		OwnerID:     req.OwnerID,
+	}); err != nil {
+		log.WithError(err).Warn("Failed to tag artifact uploads")
+	}
+}
+
+// uploadThumbnail stores a scaled-down PNG of an image artifact and returns
+// its URL, or "" when the image can't be thumbnailed.
+func (s *Service) uploadThumbnail(
+	executionID, name string,
+	data []byte,
+	size int,
+	log logrus.FieldLogger,
+) string {
+	thumbnail, err := storage.Thumbnail(data, size)
+	if err != nil {
+		log.WithError(err).WithField("file", name).Debug("Failed to render artifact thumbnail")
+
+		return ""
+	}
+
+	_, url, err := s.storage.Upload(executionID, name+thumbnailSuffix, bytes.NewReader(thumbnail))
+	if err != nil {
+		log.WithError(err).WithField("file", name).Warn("Failed to upload artifact thumbnail")
+
+		return ""
+	}
+
+	return url
+}
+
+// changedArtifacts returns the workspace charts and images modified since
+// startedAt, oldest first, within the configured count and size limits.
+func changedArtifacts(files []sandbox.SessionFile, startedAt time.Time, cfg config.SandboxArtifactsConfig) []sandbox.SessionFile {
+	// Workspace modification times have second precision.
+	since := startedAt.Truncate(time.Second)
+	maxSize := int64(cfg.MaxSizeMB) << 20
+
+	changed := make([]sandbox.SessionFile, 0, len(files))
+
+	for _, file := range files {
+		if _, ok := artifactExtensions[strings.ToLower(path.Ext(file.Name))]; !ok {
+			continue
+		}
+
+		if strings.HasPrefix(file.Name, ".") || strings.HasSuffix(file.Name, thumbnailSuffix) {
+			continue
+		}
+
+		if file.Modified.Before(since) || (maxSize > 0 && file.Size > maxSize) {
+			continue
+		}
+
+		changed = append(changed, file)
+	}
+
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].Modified.Before(changed[j].Modified)
+	})
+
+	if cfg.MaxFiles > 0 && len(changed) > cfg.MaxFiles {
+		changed = changed[:cfg.MaxFiles]
+	}
+
+	return changed
+}
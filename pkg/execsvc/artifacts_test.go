@@ -0,0 +1,41 @@
+package execsvc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+)
+
+func TestChangedArtifacts(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Unix(1000, 500_000_000)
+	cfg := config.SandboxArtifactsConfig{MaxFiles: 3, MaxSizeMB: 1}
+
+	files := []sandbox.SessionFile{
+		{Name: "old.png", Size: 10, Modified: time.Unix(999, 0)},
+		{Name: "data.parquet", Size: 10, Modified: time.Unix(1001, 0)},
+		{Name: "latency.html", Size: 10, Modified: time.Unix(1003, 0)},
+		{Name: "Chart.PNG", Size: 10, Modified: time.Unix(1002, 0)},
+		{Name: "same-second.svg", Size: 10, Modified: time.Unix(1000, 0)},
+		{Name: "huge.png", Size: 2 << 20, Modified: time.Unix(1001, 0)},
+		{Name: "Chart.PNG" + thumbnailSuffix, Size: 10, Modified: time.Unix(1002, 0)},
+		{Name: ".hidden.png", Size: 10, Modified: time.Unix(1001, 0)},
+		{Name: "last.jpg", Size: 10, Modified: time.Unix(1004, 0)},
+	}
+
+	changed := changedArtifacts(files, startedAt, cfg)
+
+	want := []string{"same-second.svg", "Chart.PNG", "latency.html"}
+	if len(changed) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, changed)
+	}
+
+	for i, file := range changed {
+		if file.Name != want[i] {
+			t.Fatalf("artifact %d: expected %q, got %q", i, want[i], file.Name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package execsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrDraining is returned for new executions and sessions while the server
+// drains before shutting down.
+var ErrDraining = errors.New("server is shutting down; retry the request shortly")
+
+// drainPollInterval is how often Drain checks for in-flight executions.
+const drainPollInterval = 100 * time.Millisecond
+
+// Draining reports whether the service has stopped accepting executions.
+func (s *Service) Draining() bool {
+	return s.draining.Load()
+}
+
+// InFlight returns the number of executions running.
+func (s *Service) InFlight() int {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	return len(s.running)
+}
+
+// Drain stops accepting new executions and sessions, then waits for the
+// running executions to finish or ctx to end. It returns the number of
+// executions still running.
+func (s *Service) Drain(ctx context.Context) int {
+	s.draining.Store(true)
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		inFlight := s.InFlight()
+		if inFlight == 0 {
+			return 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return inFlight
+		case <-ticker.C:
+		}
+	}
+}
+
+// SessionsKeptOnShutdown reports whether session containers outlive the
+// server, so their metadata should be saved while draining.
+func (s *Service) SessionsKeptOnShutdown() bool {
+	return s.cfg.Sandbox.Sessions.KeepOnShutdown && s.SessionsEnabled()
+}
+
+// sessionState is the session metadata kept across restarts. Sessions
+// themselves live in their containers; only what the server holds in memory
+// is saved.
+type sessionState struct {
+	SavedAt  time.Time                    `json:"saved_at"`
+	Sessions map[string]sessionStateEntry `json:"sessions"`
+}
+
+type sessionStateEntry struct {
+	OwnerID  string            `json:"owner_id,omitempty"`
+	LastUsed time.Time         `json:"last_used"`
+	Env      map[string]string `json:"env,omitempty"`
+}
+
+// SaveSessionState writes the last use and env overrides of every live
+// session to path, for LoadSessionState on the next start. It returns the
+// number of sessions saved.
+func (s *Service) SaveSessionState(ctx context.Context, path string) (int, error) {
+	sessions, err := s.sandboxSvc.ListSessions(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	state := sessionState{
+		SavedAt:  time.Now().UTC(),
+		Sessions: make(map[string]sessionStateEntry, len(sessions)),
+	}
+
+	s.sessionEnvMu.Lock()
+	for _, session := range sessions {
+		entry := sessionStateEntry{LastUsed: session.LastUsed}
+		if env, ok := s.sessionEnv[session.ID]; ok {
+			entry.OwnerID, entry.Env = env.ownerID, env.env
+		}
+
+		state.Sessions[session.ID] = entry
+	}
+	s.sessionEnvMu.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("encoding session state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("creating session state directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return 0, fmt.Errorf("writing session state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, fmt.Errorf("writing session state: %w", err)
+	}
+
+	return len(state.Sessions), nil
+}
+
+// LoadSessionState restores session metadata saved by SaveSessionState and
+// removes the file, so stale metadata is never applied twice. A missing file
+// restores nothing. It returns the number of sessions restored.
+func (s *Service) LoadSessionState(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("reading session state: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return 0, fmt.Errorf("removing session state: %w", err)
+	}
+
+	var state sessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return 0, fmt.Errorf("decoding session state: %w", err)
+	}
+
+	s.sessionEnvMu.Lock()
+	defer s.sessionEnvMu.Unlock()
+
+	for id, entry := range state.Sessions {
+		if !entry.LastUsed.IsZero() {
+			s.sandboxSvc.RestoreSessionAccess(id, entry.LastUsed)
+		}
+
+		if len(entry.Env) > 0 {
+			s.sessionEnv[id] = sessionEnv{ownerID: entry.OwnerID, env: entry.Env}
+		}
+	}
+
+	return len(state.Sessions), nil
+}
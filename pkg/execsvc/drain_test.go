@@ -0,0 +1,101 @@
+package execsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+)
+
+// stateSandbox implements the session calls SaveSessionState and
+// LoadSessionState use.
+type stateSandbox struct {
+	sandbox.Service
+
+	sessions []sandbox.SessionInfo
+	restored map[string]time.Time
+}
+
+func (f *stateSandbox) ListSessions(context.Context, string) ([]sandbox.SessionInfo, error) {
+	return f.sessions, nil
+}
+
+func (f *stateSandbox) RestoreSessionAccess(sessionID string, lastUsed time.Time) {
+	f.restored[sessionID] = lastUsed
+}
+
+func TestDrain(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{cfg: &config.Config{}, running: make(map[string]RunningExecution, 1)}
+	done := s.trackRunning("exec-1", RunningExecution{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if remaining := s.Drain(ctx); remaining != 1 {
+		t.Fatalf("expected 1 execution still running, got %d", remaining)
+	}
+
+	if _, err := s.Execute(context.Background(), ExecuteRequest{Code: "print(1)"}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("expected ErrDraining, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		done()
+	}()
+
+	if remaining := s.Drain(context.Background()); remaining != 0 {
+		t.Fatalf("expected drain to finish, got %d running", remaining)
+	}
+}
+
+func TestSessionStateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	lastUsed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake := &stateSandbox{
+		sessions: []sandbox.SessionInfo{{ID: "abc", LastUsed: lastUsed}, {ID: "def", LastUsed: lastUsed}},
+		restored: make(map[string]time.Time, 2),
+	}
+
+	saved := &Service{sandboxSvc: fake, sessionEnv: map[string]sessionEnv{
+		"abc":     {ownerID: "42", env: map[string]string{"DEFAULT_NETWORK": "hoodi"}},
+		"expired": {ownerID: "42", env: map[string]string{"DEFAULT_NETWORK": "mainnet"}},
+	}}
+
+	path := filepath.Join(t.TempDir(), "state", "sessions.json")
+
+	n, err := saved.SaveSessionState(context.Background(), path)
+	if err != nil || n != 2 {
+		t.Fatalf("SaveSessionState = %d, %v", n, err)
+	}
+
+	loaded := &Service{sandboxSvc: fake, sessionEnv: make(map[string]sessionEnv, 1)}
+
+	if n, err := loaded.LoadSessionState(path); err != nil || n != 2 {
+		t.Fatalf("LoadSessionState = %d, %v", n, err)
+	}
+
+	if env := loaded.SessionEnv("abc", "42"); env["DEFAULT_NETWORK"] != "hoodi" {
+		t.Fatalf("expected restored env override, got %v", env)
+	}
+
+	if _, ok := loaded.sessionEnv["expired"]; ok {
+		t.Fatalf("overrides of sessions that no longer exist must not be saved")
+	}
+
+	if !fake.restored["def"].Equal(lastUsed) {
+		t.Fatalf("expected last use of def restored, got %v", fake.restored)
+	}
+
+	// The state is applied once.
+	if n, err := loaded.LoadSessionState(path); err != nil || n != 0 {
+		t.Fatalf("second LoadSessionState = %d, %v", n, err)
+	}
+}
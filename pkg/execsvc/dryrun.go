@@ -0,0 +1,254 @@
+package execsvc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/pycheck"
+	"github.com/ethpandaops/panda/pkg/storage"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// datasourceParams are first parameters of module functions that name one
+// of the module's datasources.
+var datasourceParams = map[string]struct{}{
+	"cluster":    {},
+	"datasource": {},
+	"instance":   {},
+	"name":       {},
+	"repo":       {},
+}
+
+// DryRunResult is what a dry run found in code without executing it.
+type DryRunResult struct {
+	// Modules are the ethpandaops modules the code imports or calls.
+	Modules []string
+	// Calls is the number of module function calls found.
+	Calls    int
+	Findings []types.LintFinding
+}
+
+// Errors returns the number of error findings.
+func (r *DryRunResult) Errors() int {
+	count := 0
+
+	for _, finding := range r.Findings {
+		if finding.Severity == types.LintSeverityError {
+			count++
+		}
+	}
+
+	return count
+}
+
+// DryRun checks code without starting a sandbox: syntax, ethpandaops imports
+// and functions against the enabled modules, datasource names against the
+// discovered datasources, and module rules such as ClickHouse network
+// filters.
+func (s *Service) DryRun(code string) (*DryRunResult, error) {
+	if code == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+
+	docs := s.moduleReg.PythonAPIDocs()
+	docs["storage"] = storage.PythonAPIDoc()
+
+	analysis := pycheck.Analyze(code)
+	result := dryRun(analysis, docs, s.moduleReg.DatasourceInfo())
+	result.Findings = append(result.Findings, s.moduleReg.LintCalls(knownCalls(analysis.Calls, docs))...)
+
+	sortFindings(result.Findings)
+
+	return result, nil
+}
+
+// dryRun applies the module-independent checks to an analysis.
+func dryRun(
+	analysis *pycheck.Result,
+	docs map[string]types.ModuleDoc,
+	datasources []types.DatasourceInfo,
+) *DryRunResult {
+	result := &DryRunResult{Calls: len(analysis.Calls)}
+
+	if analysis.SyntaxError != nil {
+		result.Findings = append(result.Findings, *analysis.SyntaxError)
+	}
+
+	modules := make(map[string]struct{}, 4)
+	available := sortedModules(docs)
+
+	checkModule := func(module string, line int) bool {
+		if _, ok := docs[module]; ok {
+			modules[module] = struct{}{}
+
+			return true
+		}
+
+		result.Findings = append(result.Findings, types.LintFinding{
+			Line:     line,
+			Severity: types.LintSeverityError,
+			Kind:     "unknown_module",
+			Message: fmt.Sprintf(
+				"ethpandaops.%s is not available on this server. Available: %s",
+				module, strings.Join(available, ", "),
+			),
+		})
+
+		return false
+	}
+
+	for _, imp := range analysis.Imports {
+		if !checkModule(imp.Module, imp.Line) || imp.Function == "" {
+			continue
+		}
+
+		if _, ok := docs[imp.Module].Functions[imp.Function]; !ok {
+			result.Findings = append(result.Findings, unknownFunction(imp.Module, imp.Function, imp.Line))
+		}
+	}
+
+	namesByType := make(map[string][]string, 8)
+	for _, info := range datasources {
+		namesByType[info.Type] = append(namesByType[info.Type], info.Name)
+	}
+
+	reported := make(map[string]struct{}, len(analysis.Imports))
+	for _, imp := range analysis.Imports {
+		if imp.Function != "" {
+			reported[imp.Module+"."+imp.Function] = struct{}{}
+		}
+	}
+
+	for _, call := range analysis.Calls {
+		if !checkModule(call.Module, call.Line) {
+			continue
+		}
+
+		doc, ok := docs[call.Module].Functions[call.Function]
+		if !ok {
+			if _, done := reported[call.Module+"."+call.Function]; !done {
+				result.Findings = append(result.Findings, unknownFunction(call.Module, call.Function, call.Line))
+			}
+
+			continue
+		}
+
+		if finding, ok := checkDatasourceArg(call, doc, namesByType); ok {
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	for module := range modules {
+		result.Modules = append(result.Modules, module)
+	}
+
+	sort.Strings(result.Modules)
+
+	return result
+}
+
+// checkDatasourceArg reports a datasource argument that names no discovered
+// datasource of the module's type.
+func checkDatasourceArg(
+	call types.PythonCall,
+	doc types.FunctionDoc,
+	namesByType map[string][]string,
+) (types.LintFinding, bool) {
+	param := firstParam(doc.Signature)
+	if _, ok := datasourceParams[param]; !ok {
+		return types.LintFinding{}, false
+	}
+
+	// Datasource types are module names without underscores, e.g. http_json
+	// datasources have type httpjson.
+	names := namesByType[strings.ReplaceAll(call.Module, "_", "")]
+	if len(names) == 0 {
+		return types.LintFinding{}, false
+	}
+
+	value, ok := call.Arg(0, param)
+	if !ok {
+		return types.LintFinding{}, false
+	}
+
+	for _, name := range names {
+		if strings.EqualFold(name, value) {
+			return types.LintFinding{}, false
+		}
+	}
+
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	return types.LintFinding{
+		Line:     call.Line,
+		Severity: types.LintSeverityError,
+		Kind:     "unknown_datasource",
+		Message: fmt.Sprintf(
+			"%s.%s: %s %q is not configured. Available: %s",
+			call.Module, call.Function, param, value, strings.Join(sorted, ", "),
+		),
+	}, true
+}
+
+// firstParam returns the name of the first parameter in a signature such as
+// "clickhouse.query(cluster: str, sql: str) -> pandas.DataFrame".
+func firstParam(signature string) string {
+	_, params, ok := strings.Cut(signature, "(")
+	if !ok {
+		return ""
+	}
+
+	end := strings.IndexAny(params, ",:=)")
+	if end < 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(params[:end])
+}
+
+func unknownFunction(module, function string, line int) types.LintFinding {
+	return types.LintFinding{
+		Line:     line,
+		Severity: types.LintSeverityWarning,
+		Kind:     "unknown_function",
+		Message:  fmt.Sprintf("ethpandaops.%s has no documented function %s", module, function),
+	}
+}
+
+// knownCalls returns the calls of documented functions, which are the ones
+// modules lint.
+func knownCalls(calls []types.PythonCall, docs map[string]types.ModuleDoc) []types.PythonCall {
+	known := make([]types.PythonCall, 0, len(calls))
+
+	for _, call := range calls {
+		if _, ok := docs[call.Module].Functions[call.Function]; ok {
+			known = append(known, call)
+		}
+	}
+
+	return known
+}
+
+func sortedModules(docs map[string]types.ModuleDoc) []string {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// sortFindings orders findings by line, errors first within a line.
+func sortFindings(findings []types.LintFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+
+		return findings[i].Severity == types.LintSeverityError && findings[j].Severity != types.LintSeverityError
+	})
+}
@@ -0,0 +1,87 @@
+package execsvc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/panda/pkg/pycheck"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	docs := map[string]types.ModuleDoc{
+		"clickhouse": {Functions: map[string]types.FunctionDoc{
+			"query": {Signature: "clickhouse.query(cluster: str, sql: str) -> pandas.DataFrame"},
+		}},
+		"http_json": {Functions: map[string]types.FunctionDoc{
+			"get": {Signature: "http_json.get(name: str, path: str, params: dict = None) -> Any"},
+		}},
+		"dora": {Functions: map[string]types.FunctionDoc{
+			"get_slot": {Signature: "get_slot(network, slot_or_hash) -> dict"},
+		}},
+	}
+
+	datasources := []types.DatasourceInfo{
+		{Type: "clickhouse", Name: "xatu"},
+		{Type: "clickhouse", Name: "xatu-cbt"},
+		{Type: "httpjson", Name: "faucet"},
+	}
+
+	code := `from ethpandaops import clickhouse, http_json, dora, loki
+from ethpandaops.clickhouse import quer
+clickhouse.query("xatu", "SELECT 1")
+clickhouse.query(cluster="Xatu-CBT", sql="SELECT 1")
+clickhouse.query("mainnet", "SELECT 1")
+http_json.get("faucets", "/api/v1/status")
+dora.get_slot("hoodi", 1)
+clickhouse.querry("xatu", "SELECT 1")
+`
+
+	result := dryRun(pycheck.Analyze(code), docs, datasources)
+
+	if got := strings.Join(result.Modules, ","); got != "clickhouse,dora,http_json" {
+		t.Fatalf("unexpected modules %q", got)
+	}
+
+	if result.Calls != 6 {
+		t.Fatalf("expected 6 calls, got %d", result.Calls)
+	}
+
+	want := []types.LintFinding{
+		{Line: 1, Severity: types.LintSeverityError, Kind: "unknown_module"},
+		{Line: 2, Severity: types.LintSeverityWarning, Kind: "unknown_function"},
+		{Line: 5, Severity: types.LintSeverityError, Kind: "unknown_datasource"},
+		{Line: 6, Severity: types.LintSeverityError, Kind: "unknown_datasource"},
+		{Line: 8, Severity: types.LintSeverityWarning, Kind: "unknown_function"},
+	}
+
+	if len(result.Findings) != len(want) {
+		t.Fatalf("expected %d findings, got %+v", len(want), result.Findings)
+	}
+
+	for i, finding := range result.Findings {
+		if finding.Line != want[i].Line || finding.Severity != want[i].Severity || finding.Kind != want[i].Kind {
+			t.Fatalf("finding %d: expected %+v, got %+v", i, want[i], finding)
+		}
+	}
+
+	if msg := result.Findings[2].Message; msg != `clickhouse.query: cluster "mainnet" is not configured. Available: xatu, xatu-cbt` {
+		t.Fatalf("unexpected message %q", msg)
+	}
+
+	if result.Errors() != 3 {
+		t.Fatalf("expected 3 errors, got %d", result.Errors())
+	}
+}
+
+func TestDryRunSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	result := dryRun(pycheck.Analyze("print((1)\n"), nil, nil)
+
+	if len(result.Findings) != 1 || result.Findings[0].Kind != "syntax_error" || result.Findings[0].Line != 1 {
+		t.Fatalf("expected a syntax error on line 1, got %+v", result.Findings)
+	}
+}
@@ -0,0 +1,60 @@
+package execsvc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+)
+
+// ErrPackagesDisabled is returned when packages are requested while
+// sandbox.packages is disabled.
+var ErrPackagesDisabled = errors.New("installing packages is disabled: sandbox.packages.enabled is false")
+
+// checkPackages validates requested packages against sandbox.packages.
+// Only the requested projects are checked; their dependencies are resolved
+// by pip, so cache_only is the way to pin everything installable.
+func checkPackages(cfg config.SandboxPackagesConfig, packages []string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	if !cfg.Enabled {
+		return ErrPackagesDisabled
+	}
+
+	if cfg.MaxPackages > 0 && len(packages) > cfg.MaxPackages {
+		return fmt.Errorf("at most %d packages may be requested per execution", cfg.MaxPackages)
+	}
+
+	for _, spec := range packages {
+		name, err := sandbox.PackageName(spec)
+		if err != nil {
+			return err
+		}
+
+		if packageListed(cfg.Denied, name) {
+			return fmt.Errorf("package %q is denied by the server", name)
+		}
+
+		if len(cfg.Allowed) > 0 && !packageListed(cfg.Allowed, name) {
+			return fmt.Errorf(
+				"package %q is not allowed; allowed: %s", name, strings.Join(cfg.Allowed, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+func packageListed(list []string, name string) bool {
+	for _, entry := range list {
+		if sandbox.NormalizePackageName(entry) == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package execsvc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethpandaops/panda/pkg/config"
+)
+
+func TestCheckPackages(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.SandboxPackagesConfig{
+		Enabled:     true,
+		Allowed:     []string{"scipy", "scikit-learn", "Py_Ecc"},
+		Denied:      []string{"scikit-learn"},
+		MaxPackages: 3,
+	}
+
+	tests := []struct {
+		packages []string
+		ok       bool
+	}{
+		{packages: nil, ok: true},
+		{packages: []string{"scipy"}, ok: true},
+		{packages: []string{"scipy>=1.11,<2", "py-ecc==7.0.1"}, ok: true},
+		{packages: []string{"SciPy"}, ok: true},
+		{packages: []string{"scikit_learn"}},
+		{packages: []string{"requests"}},
+		{packages: []string{"--index-url=https://evil.example"}},
+		{packages: []string{"scipy @ https://evil.example/scipy.whl"}},
+		{packages: []string{"scipy", "scipy", "scipy", "scipy"}},
+	}
+
+	for _, tt := range tests {
+		err := checkPackages(cfg, tt.packages)
+		if (err == nil) != tt.ok {
+			t.Errorf("checkPackages(%q) = %v, want ok=%v", tt.packages, err, tt.ok)
+		}
+	}
+}
+
+func TestCheckPackagesOpenAllowlist(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.SandboxPackagesConfig{Enabled: true, Denied: []string{"torch"}}
+
+	if err := checkPackages(cfg, []string{"polars"}); err != nil {
+		t.Errorf("an empty allowlist should allow undenied packages: %v", err)
+	}
+
+	if err := checkPackages(cfg, []string{"Torch"}); err == nil {
+		t.Errorf("denied packages must be rejected")
+	}
+}
+
+func TestCheckPackagesDisabled(t *testing.T) {
+	t.Parallel()
+
+	err := checkPackages(config.SandboxPackagesConfig{}, []string{"scipy"})
+	if !errors.Is(err, ErrPackagesDisabled) {
+		t.Errorf("checkPackages() = %v, want ErrPackagesDisabled", err)
+	}
+}
@@ -0,0 +1,32 @@
+package execsvc
+
+import "github.com/ethpandaops/panda/pkg/types"
+
+// RecordRateLimit records a proxy rate limit rejection of a request made on
+// behalf of a running execution. The last one is returned with the
+// execution's result, so the tool result can tell the agent when to retry.
+// Rejections for executions that are not running are dropped.
+func (s *Service) RecordRateLimit(executionID string, state types.RateLimitState) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	if _, ok := s.running[executionID]; !ok {
+		return
+	}
+
+	s.rateLimits[executionID] = state
+}
+
+// rateLimit returns the last rate limit rejection recorded for an
+// execution, or nil when it was never rate limited.
+func (s *Service) rateLimit(executionID string) *types.RateLimitState {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	state, ok := s.rateLimits[executionID]
+	if !ok {
+		return nil
+	}
+
+	return &state
+}
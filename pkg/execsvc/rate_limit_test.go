@@ -0,0 +1,109 @@
+package execsvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/tokenstore"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// rateLimitedSandbox stands in for code whose operation the proxy rejects:
+// the server sees the rejection for the execution behind the runtime token.
+type rateLimitedSandbox struct {
+	sandbox.Service
+
+	svc    *Service
+	tokens *tokenstore.Store
+	state  *types.RateLimitState
+}
+
+func (f *rateLimitedSandbox) Name() string { return "fake" }
+
+func (f *rateLimitedSandbox) SessionsEnabled() bool { return false }
+
+func (f *rateLimitedSandbox) Execute(_ context.Context, req sandbox.ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	if f.state != nil {
+		f.svc.RecordRateLimit(f.tokens.Validate(req.Env["ETHPANDAOPS_API_TOKEN"]), *f.state)
+	}
+
+	// Output that looks like a rejection is not mistaken for one.
+	return &sandbox.ExecutionResult{
+		ExitCode: 1,
+		Stderr:   `ValueError: {"error":"RATE_LIMITED","limit":99}`,
+	}, nil
+}
+
+func TestExecuteReportsRecordedRateLimit(t *testing.T) {
+	t.Parallel()
+
+	state := types.RateLimitState{
+		Limit:             10,
+		RequestsPerMinute: 60,
+		RetryAfterSeconds: 1,
+		RetryAt:           time.Date(2026, 10, 18, 12, 0, 1, 0, time.UTC),
+		ResetAt:           time.Date(2026, 10, 18, 12, 0, 10, 0, time.UTC),
+	}
+
+	for _, tt := range []struct {
+		name  string
+		state *types.RateLimitState
+	}{
+		{name: "rate limited", state: &state},
+		{name: "not rate limited"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			log := logrus.New()
+			log.SetLevel(logrus.PanicLevel)
+
+			cfg := &config.Config{}
+			cfg.Server.BaseURL = "http://localhost:2480"
+			cfg.Sandbox.Timeout = 30
+
+			tokens := tokenstore.New(time.Hour)
+			t.Cleanup(tokens.Stop)
+
+			fake := &rateLimitedSandbox{tokens: tokens, state: tt.state}
+			fake.svc = New(log, fake, cfg, module.NewRegistry(log), tokens, nil)
+
+			result, err := fake.svc.Execute(context.Background(), ExecuteRequest{Code: "print(1)"})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			switch {
+			case tt.state == nil && result.RateLimit != nil:
+				t.Fatalf("expected no rate limit state, got %+v", result.RateLimit)
+			case tt.state != nil && (result.RateLimit == nil || *result.RateLimit != *tt.state):
+				t.Fatalf("expected rate limit state %+v, got %+v", tt.state, result.RateLimit)
+			}
+
+			if len(fake.svc.rateLimits) != 0 {
+				t.Fatalf("rate limit state outlived the execution: %+v", fake.svc.rateLimits)
+			}
+		})
+	}
+}
+
+func TestRecordRateLimitIgnoresFinishedExecutions(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{
+		running:    make(map[string]RunningExecution, 1),
+		rateLimits: make(map[string]types.RateLimitState, 1),
+	}
+
+	s.RecordRateLimit("exec-1", types.RateLimitState{Limit: 10})
+
+	if s.rateLimit("exec-1") != nil {
+		t.Fatal("recorded rate limit state for an execution that is not running")
+	}
+}
@@ -0,0 +1,55 @@
+package execsvc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// datasourceEnvVars are the sandbox env vars listing a module's datasources,
+// mapped to the datasource type they list.
+var datasourceEnvVars = map[string]string{
+	"ETHPANDAOPS_CLICKHOUSE_DATASOURCES": "clickhouse",
+	"ETHPANDAOPS_PROMETHEUS_DATASOURCES": "prometheus",
+	"ETHPANDAOPS_LOKI_DATASOURCES":       "loki",
+	"ETHPANDAOPS_GRAFANA_DATASOURCES":    "grafana",
+	"ETHPANDAOPS_HTTPJSON_DATASOURCES":   "httpjson",
+}
+
+// scopeSandboxEnv removes the datasources groups may not use from the
+// datasource lists in env, so the sandbox only advertises what the caller
+// can query.
+func (s *Service) scopeSandboxEnv(env map[string]string, groups []string) error {
+	if s.datasources == nil || groups == nil {
+		return nil
+	}
+
+	for name, dsType := range datasourceEnvVars {
+		value, ok := env[name]
+		if !ok {
+			continue
+		}
+
+		var entries []map[string]any
+		if err := json.Unmarshal([]byte(value), &entries); err != nil {
+			return fmt.Errorf("parsing %s: %w", name, err)
+		}
+
+		allowed := make([]map[string]any, 0, len(entries))
+
+		for _, entry := range entries {
+			dsName, _ := entry["name"].(string)
+			if s.datasources.Allows(groups, dsType, dsName) {
+				allowed = append(allowed, entry)
+			}
+		}
+
+		data, err := json.Marshal(allowed)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", name, err)
+		}
+
+		env[name] = string(data)
+	}
+
+	return nil
+}
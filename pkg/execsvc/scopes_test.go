@@ -0,0 +1,50 @@
+package execsvc
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+)
+
+func TestScopeSandboxEnv(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{datasources: auth.NewDatasourcePolicy([]auth.DatasourceScope{
+		{Groups: []string{"researchers"}, Datasources: []string{"clickhouse:xatu"}},
+	})}
+
+	newEnv := func() map[string]string {
+		return map[string]string{
+			"ETHPANDAOPS_CLICKHOUSE_DATASOURCES": `[{"name":"xatu","database":"default"},{"name":"internal"}]`,
+			"ETHPANDAOPS_LOKI_DATASOURCES":       `[{"name":"logs"}]`,
+			"ETHPANDAOPS_API_URL":                "http://localhost:2480",
+		}
+	}
+
+	env := newEnv()
+	if err := s.scopeSandboxEnv(env, []string{"researchers"}); err != nil {
+		t.Fatalf("scopeSandboxEnv() error = %v", err)
+	}
+
+	if got := env["ETHPANDAOPS_CLICKHOUSE_DATASOURCES"]; got != `[{"database":"default","name":"xatu"}]` {
+		t.Fatalf("unexpected clickhouse datasources %s", got)
+	}
+
+	if got := env["ETHPANDAOPS_LOKI_DATASOURCES"]; got != `[]` {
+		t.Fatalf("unexpected loki datasources %s", got)
+	}
+
+	if env["ETHPANDAOPS_API_URL"] != "http://localhost:2480" {
+		t.Fatalf("unrelated env must be kept")
+	}
+
+	// Unauthenticated callers are not restricted.
+	env = newEnv()
+	if err := s.scopeSandboxEnv(env, nil); err != nil {
+		t.Fatalf("scopeSandboxEnv() error = %v", err)
+	}
+
+	if env["ETHPANDAOPS_LOKI_DATASOURCES"] != `[{"name":"logs"}]` {
+		t.Fatalf("expected env untouched without groups, got %v", env)
+	}
+}
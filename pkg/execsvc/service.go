@@ -2,31 +2,101 @@ package execsvc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/auth"
 	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/history"
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/pystub"
 	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
 	"github.com/ethpandaops/panda/pkg/tokenstore"
+	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/pkg/wheelcache"
 )
 
 const (
 	MinTimeout = 1
 	MaxTimeout = 600
+
+	// stubsDir is the workspace directory generated type stubs are written to.
+	stubsDir = ".stubs"
+
+	// tokenRefreshFile is the session workspace file a fresh runtime token is
+	// rotated into before the execution's token expires. The sandbox runtime
+	// re-reads it when the server rejects its token.
+	tokenRefreshFile = ".panda/api_token"
 )
 
+// SandboxError wraps a failure raised by the sandbox backend, as opposed to
+// an invalid request, so callers can report the two differently.
+type SandboxError struct {
+	Err error
+}
+
+func (e *SandboxError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SandboxError) Unwrap() error {
+	return e.Err
+}
+
+// requestErrors are backend errors caused by the request itself, such as an
+// unknown or foreign session, which are returned without a SandboxError.
+var requestErrors = []error{
+	sandbox.ErrSessionsDisabled,
+	sandbox.ErrSessionNotFound,
+	sandbox.ErrSessionNotOwned,
+	sandbox.ErrSessionExpired,
+	sandbox.ErrSessionProfileFixed,
+	sandbox.ErrPackagesNeedSession,
+	config.ErrUnknownProfile,
+}
+
+// isRequestError reports whether err is one of requestErrors.
+func isRequestError(err error) bool {
+	for _, target := range requestErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ExecuteRequest describes a sandbox execution request.
 type ExecuteRequest struct {
 	Code      string
 	Timeout   int
 	SessionID string
 	OwnerID   string
+	// Profile names a configured execution profile. Groups are the caller's
+	// groups and orgs, checked against the profile's allowed groups.
+	Profile string
+	Groups  []string
+	// Packages are pip requirement specifiers to install into the session
+	// first, checked against sandbox.packages.
+	Packages []string
+	// Stdout and Stderr, if set, receive output live as the code runs.
+	Stdout io.Writer
+	Stderr io.Writer
+	// OnQueued, if set, is called while the execution waits for a slot
+	// with its queue position, and with 0 once it starts.
+	OnQueued func(position int)
 }
 
 // Service orchestrates sandbox execution with module-provided env and runtime tokens.
@@ -36,15 +106,44 @@ type Service struct {
 	cfg           *config.Config
 	moduleReg     *module.Registry
 	runtimeTokens *tokenstore.Store
+	history       history.Store
+	scheduler     *sandbox.Scheduler
+	storage       storage.Service
+	datasources   *auth.DatasourcePolicy
+	onSuccess     func(ownerID, code string)
+
+	longRunningAfter time.Duration
+	onLongRunning    func(executionID string, execution RunningExecution)
+
+	sessionEnvMu sync.Mutex
+	sessionEnv   map[string]sessionEnv // session ID -> env overrides
+
+	runningMu  sync.Mutex
+	running    map[string]RunningExecution     // execution ID -> execution
+	rateLimits map[string]types.RateLimitState // execution ID -> last proxy rejection
+
+	// draining rejects new executions and sessions during shutdown.
+	draining atomic.Bool
 }
 
-// New creates a new execution service.
+// RunningExecution identifies who started an execution still in progress.
+type RunningExecution struct {
+	SessionID string
+	OwnerID   string
+	// Groups are the caller's groups, nil when unauthenticated. Operations
+	// the execution calls are scoped to the datasources they grant.
+	Groups []string
+}
+
+// New creates a new execution service. historyStore may be nil to disable
+// execution history.
 func New(
 	log logrus.FieldLogger,
 	sandboxSvc sandbox.Service,
 	cfg *config.Config,
 	moduleReg *module.Registry,
 	runtimeTokens *tokenstore.Store,
+	historyStore history.Store,
 ) *Service {
 	return &Service{
 		log:           log.WithField("component", "exec-service"),
@@ -52,15 +151,76 @@ func New(
 		cfg:           cfg,
 		moduleReg:     moduleReg,
 		runtimeTokens: runtimeTokens,
+		history:       historyStore,
+		scheduler:     sandbox.NewScheduler(cfg.Sandbox.Scheduler),
+		datasources:   auth.NewDatasourcePolicy(cfg.Server.DatasourceScopes),
+		sessionEnv:    make(map[string]sessionEnv, 8),
+		running:       make(map[string]RunningExecution, 8),
+		rateLimits:    make(map[string]types.RateLimitState, 8),
+	}
+}
+
+// SetSuccessHook sets fn to be called with the owner and code of each
+// execution that exits with status 0.
+func (s *Service) SetSuccessHook(fn func(ownerID, code string)) {
+	s.onSuccess = fn
+}
+
+// SetLongRunningHook sets fn to be called once for each execution still
+// running after threshold.
+func (s *Service) SetLongRunningHook(threshold time.Duration, fn func(executionID string, execution RunningExecution)) {
+	s.longRunningAfter = threshold
+	s.onLongRunning = fn
+}
+
+// Running returns the session and owner of an execution in progress.
+func (s *Service) Running(executionID string) (RunningExecution, bool) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	execution, ok := s.running[executionID]
+
+	return execution, ok
+}
+
+func (s *Service) trackRunning(executionID string, execution RunningExecution) func() {
+	s.runningMu.Lock()
+	s.running[executionID] = execution
+	s.runningMu.Unlock()
+
+	return func() {
+		s.runningMu.Lock()
+		delete(s.running, executionID)
+		delete(s.rateLimits, executionID)
+		s.runningMu.Unlock()
 	}
 }
 
 // Execute runs code in the sandbox.
 func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	if s.Draining() {
+		return nil, ErrDraining
+	}
+
 	if req.Code == "" {
 		return nil, fmt.Errorf("code is required")
 	}
 
+	if req.Profile != "" {
+		profile, ok := s.cfg.Sandbox.Profiles[req.Profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown execution profile %q", req.Profile)
+		}
+
+		if !profile.Allows(req.Groups) {
+			return nil, fmt.Errorf("not permitted to use execution profile %q", req.Profile)
+		}
+	}
+
+	if err := checkPackages(s.cfg.Sandbox.Packages, req.Packages); err != nil {
+		return nil, err
+	}
+
 	timeout := req.Timeout
 	if timeout == 0 {
 		timeout = s.cfg.Sandbox.Timeout
@@ -75,10 +235,52 @@ func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*sandbox.Exe
 		return nil, fmt.Errorf("failed to configure sandbox: %w", err)
 	}
 
+	if err := s.scopeSandboxEnv(env, req.Groups); err != nil {
+		return nil, fmt.Errorf("failed to configure sandbox: %w", err)
+	}
+
+	if req.SessionID != "" {
+		// Overrides never replace the env the server and modules provide.
+		for name, value := range s.SessionEnv(req.SessionID, req.OwnerID) {
+			if _, ok := env[name]; !ok {
+				env[name] = value
+			}
+		}
+	}
+
+	// Wait for a slot before issuing the runtime token so queueing doesn't
+	// eat into its lifetime.
+	release, err := s.scheduler.Acquire(ctx, req.OwnerID, req.OnQueued)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Executions still queued when the drain began never start.
+	if s.Draining() {
+		return nil, ErrDraining
+	}
+
 	executionID := uuid.New().String()
 	runtimeToken := s.runtimeTokens.Register(executionID)
 	env["ETHPANDAOPS_API_TOKEN"] = runtimeToken
 	defer s.runtimeTokens.Revoke(executionID)
+	running := RunningExecution{
+		SessionID: req.SessionID,
+		OwnerID:   req.OwnerID,
+		Groups:    req.Groups,
+	}
+	defer s.trackRunning(executionID, running)()
+
+	if s.onLongRunning != nil && s.longRunningAfter > 0 {
+		timer := time.AfterFunc(s.longRunningAfter, func() { s.onLongRunning(executionID, running) })
+		defer timer.Stop()
+	}
+
+	if req.SessionID != "" {
+		stopRefresh := s.startTokenRefresh(ctx, executionID, req.SessionID, req.OwnerID)
+		defer stopRefresh()
+	}
 
 	if req.SessionID == "" && s.sandboxSvc.SessionsEnabled() {
 		canCreate, count, maxAllowed := s.sandboxSvc.CanCreateSession(ctx, req.OwnerID)
@@ -91,13 +293,211 @@ func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*sandbox.Exe
 		}
 	}
 
-	return s.sandboxSvc.Execute(ctx, sandbox.ExecuteRequest{
+	startedAt := time.Now()
+
+	inFlight := observability.SandboxExecutionsInFlight.WithLabelValues(s.sandboxSvc.Name())
+	inFlight.Inc()
+
+	// An unset timeout is left to the backend so the profile's applies.
+	result, err := s.sandboxSvc.Execute(ctx, sandbox.ExecuteRequest{
 		Code:      req.Code,
 		Env:       env,
-		Timeout:   time.Duration(timeout) * time.Second,
+		Timeout:   time.Duration(req.Timeout) * time.Second,
 		SessionID: req.SessionID,
 		OwnerID:   req.OwnerID,
+		Profile:   req.Profile,
+		Packages:  req.Packages,
+		Stdout:    req.Stdout,
+		Stderr:    req.Stderr,
 	})
+
+	inFlight.Dec()
+
+	s.recordHistory(ctx, executionID, req, startedAt, result, err)
+	s.recordMetrics(startedAt, result, err)
+
+	if err != nil {
+		if isRequestError(err) {
+			return nil, err
+		}
+
+		return nil, &SandboxError{Err: err}
+	}
+
+	s.addErrorHints(result)
+	result.RateLimit = s.rateLimit(executionID)
+	s.uploadArtifacts(ctx, executionID, req, startedAt, result)
+
+	if s.onSuccess != nil && result.ExitCode == 0 {
+		s.onSuccess(req.OwnerID, req.Code)
+	}
+
+	return result, nil
+}
+
+// addErrorHints attaches module hints for known error signatures in the
+// execution output and counts them by kind.
+func (s *Service) addErrorHints(result *sandbox.ExecutionResult) {
+	if s.moduleReg == nil || (result.Stderr == "" && result.ExitCode == 0) {
+		return
+	}
+
+	result.Hints = s.moduleReg.ErrorHints(result.Stderr + "\n" + result.Stdout)
+
+	for _, hint := range result.Hints {
+		observability.SandboxErrorHintsTotal.WithLabelValues(hint.Kind).Inc()
+	}
+}
+
+// startTokenRefresh rotates a fresh runtime token into the session workspace
+// ahead of each expiry so long-running executions keep API access. Ephemeral
+// executions are bounded by MaxTimeout, far below the token TTL, and have no
+// workspace to rotate into. The returned func stops the refresher.
+func (s *Service) startTokenRefresh(ctx context.Context, executionID, sessionID, ownerID string) func() {
+	interval := s.runtimeTokens.TTL() * 3 / 4
+	if interval <= 0 {
+		return func() {}
+	}
+
+	refreshCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-refreshCtx.Done():
+				return
+			case <-ticker.C:
+				token := s.runtimeTokens.Register(executionID)
+				if err := s.sandboxSvc.WriteSessionFile(
+					refreshCtx, sessionID, ownerID, tokenRefreshFile, []byte(token),
+				); err != nil {
+					s.log.WithError(err).WithField("session_id", sessionID).Warn("Failed to rotate runtime token into session")
+
+					continue
+				}
+
+				s.log.WithField("session_id", sessionID).Debug("Rotated runtime token into session")
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// recordMetrics records the outcome, duration and resource usage of an
+// execution.
+func (s *Service) recordMetrics(startedAt time.Time, result *sandbox.ExecutionResult, execErr error) {
+	backend := s.sandboxSvc.Name()
+
+	status := "success"
+
+	switch {
+	case errors.Is(execErr, sandbox.ErrExecutionTimeout):
+		status = "timeout"
+	case execErr != nil:
+		status = "error"
+	case result.ExitCode != 0:
+		status = "nonzero_exit"
+	}
+
+	observability.SandboxExecutionsTotal.WithLabelValues(backend, status).Inc()
+	observability.SandboxExecutionDuration.WithLabelValues(backend).Observe(time.Since(startedAt).Seconds())
+
+	if result == nil || result.Usage == nil {
+		return
+	}
+
+	usage := result.Usage
+	observability.SandboxExecutionPeakMemory.WithLabelValues(backend).Observe(float64(usage.PeakMemoryBytes))
+	observability.SandboxExecutionCPUSeconds.WithLabelValues(backend).Observe(usage.CPUSeconds)
+	observability.SandboxExecutionIOBytesTotal.WithLabelValues(backend, "read").Add(float64(usage.ReadBytes))
+	observability.SandboxExecutionIOBytesTotal.WithLabelValues(backend, "write").Add(float64(usage.WrittenBytes))
+	observability.SandboxExecutionNetworkBytesTotal.WithLabelValues(backend, "rx").Add(float64(usage.NetworkRxBytes))
+	observability.SandboxExecutionNetworkBytesTotal.WithLabelValues(backend, "tx").Add(float64(usage.NetworkTxBytes))
+}
+
+// recordHistory appends the outcome of an execution to the history store.
+// Failures are logged rather than returned so history never blocks execution.
+func (s *Service) recordHistory(
+	ctx context.Context,
+	executionID string,
+	req ExecuteRequest,
+	startedAt time.Time,
+	result *sandbox.ExecutionResult,
+	execErr error,
+) {
+	if s.history == nil {
+		return
+	}
+
+	record := history.Record{
+		ExecutionID:     executionID,
+		SessionID:       req.SessionID,
+		OwnerID:         req.OwnerID,
+		CodeHash:        history.CodeHash(req.Code),
+		Code:            req.Code,
+		StartedAt:       startedAt.UTC(),
+		DurationSeconds: time.Since(startedAt).Seconds(),
+	}
+
+	if execErr != nil {
+		record.ExitCode = -1
+		record.Error = execErr.Error()
+	}
+
+	if result != nil {
+		if result.ExecutionID != "" {
+			record.ExecutionID = result.ExecutionID
+		}
+
+		if result.SessionID != "" {
+			record.SessionID = result.SessionID
+		}
+
+		record.ExitCode = result.ExitCode
+		record.DurationSeconds = result.DurationSeconds
+	}
+
+	if err := s.history.Append(context.WithoutCancel(ctx), record); err != nil {
+		s.log.WithError(err).Warn("Failed to record execution history")
+	}
+}
+
+// ListExecutions returns recorded executions matching the filter, newest first.
+func (s *Service) ListExecutions(ctx context.Context, filter history.Filter) ([]history.Record, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("execution history is disabled")
+	}
+
+	return s.history.List(ctx, filter)
+}
+
+// GetExecution returns a recorded execution. If ownerID is non-empty, only
+// executions owned by that caller are returned.
+func (s *Service) GetExecution(ctx context.Context, executionID, ownerID string) (*history.Record, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("execution history is disabled")
+	}
+
+	record, err := s.history.Get(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil || (ownerID != "" && record.OwnerID != "" && record.OwnerID != ownerID) {
+		return nil, fmt.Errorf("execution %s not found", executionID)
+	}
+
+	return record, nil
 }
 
 // SessionsEnabled reports whether the sandbox supports persistent sessions.
@@ -119,17 +519,107 @@ func (s *Service) ListSessions(ctx context.Context, ownerID string) ([]sandbox.S
 
 // CreateSession creates a new persistent sandbox session.
 func (s *Service) CreateSession(ctx context.Context, ownerID string) (string, error) {
+	if s.Draining() {
+		return "", ErrDraining
+	}
+
 	env, err := s.BuildSandboxEnv()
 	if err != nil {
 		return "", fmt.Errorf("building sandbox env: %w", err)
 	}
 
-	return s.sandboxSvc.CreateSession(ctx, ownerID, env)
+	if err := s.scopeSandboxEnv(env, auth.GetAuthGroups(ctx)); err != nil {
+		return "", fmt.Errorf("building sandbox env: %w", err)
+	}
+
+	sessionID, err := s.sandboxSvc.CreateSession(ctx, ownerID, env)
+	if err != nil {
+		return "", err
+	}
+
+	if s.cfg != nil && s.cfg.Sandbox.Sessions.WriteStubs {
+		s.writeStubs(ctx, sessionID, ownerID)
+	}
+
+	return sessionID, nil
+}
+
+// writeStubs writes the generated ethpandaops type stubs into the session
+// workspace. Failures are logged and do not fail session creation.
+func (s *Service) writeStubs(ctx context.Context, sessionID, ownerID string) {
+	docs := s.moduleReg.PythonAPIDocs()
+	docs["storage"] = storage.PythonAPIDoc()
+
+	for _, file := range pystub.Generate(docs) {
+		if err := s.sandboxSvc.WriteSessionFile(
+			ctx, sessionID, ownerID, path.Join(stubsDir, file.Path), []byte(file.Content),
+		); err != nil {
+			s.log.WithError(err).WithField("session_id", sessionID).Warn("Failed to write type stubs to session")
+
+			return
+		}
+	}
 }
 
 // DestroySession destroys a persistent sandbox session.
 func (s *Service) DestroySession(ctx context.Context, sessionID, ownerID string) error {
-	return s.sandboxSvc.DestroySession(ctx, sessionID, ownerID)
+	if err := s.sandboxSvc.DestroySession(ctx, sessionID, ownerID); err != nil {
+		return err
+	}
+
+	s.forgetSessionEnv(sessionID)
+
+	return nil
+}
+
+// WriteSessionFile writes a file into a session's workspace.
+func (s *Service) WriteSessionFile(ctx context.Context, sessionID, ownerID, name string, data []byte) error {
+	return s.sandboxSvc.WriteSessionFile(ctx, sessionID, ownerID, name, data)
+}
+
+// ReadSessionFile reads a file from a session's workspace.
+func (s *Service) ReadSessionFile(ctx context.Context, sessionID, ownerID, name string) ([]byte, error) {
+	return s.sandboxSvc.ReadSessionFile(ctx, sessionID, ownerID, name)
+}
+
+// PackageCacheStatus verifies the shared package cache and reports its contents.
+func (s *Service) PackageCacheStatus(ctx context.Context) (*wheelcache.Report, error) {
+	cacher, ok := s.sandboxSvc.(sandbox.PackageCacher)
+	if !ok {
+		return nil, sandbox.ErrPackageCacheDisabled
+	}
+
+	return cacher.PackageCacheStatus(ctx)
+}
+
+// BuildPackageCache adds wheels for packages to the shared package cache.
+func (s *Service) BuildPackageCache(ctx context.Context, packages []string) (*sandbox.PackageBuildResult, error) {
+	cacher, ok := s.sandboxSvc.(sandbox.PackageCacher)
+	if !ok {
+		return nil, sandbox.ErrPackageCacheDisabled
+	}
+
+	return cacher.BuildPackageCache(ctx, packages)
+}
+
+// ImageStatus reports the configured sandbox images.
+func (s *Service) ImageStatus(ctx context.Context) ([]sandbox.ImageStatus, error) {
+	manager, ok := s.sandboxSvc.(sandbox.ImageManager)
+	if !ok {
+		return nil, sandbox.ErrImagesUnsupported
+	}
+
+	return manager.ImageStatus(ctx)
+}
+
+// PullImages pulls the configured sandbox images now.
+func (s *Service) PullImages(ctx context.Context) ([]sandbox.ImageStatus, error) {
+	manager, ok := s.sandboxSvc.(sandbox.ImageManager)
+	if !ok {
+		return nil, sandbox.ErrImagesUnsupported
+	}
+
+	return manager.PullImages(ctx)
 }
 
 // BuildSandboxEnv collects environment variables from all initialized modules
@@ -0,0 +1,149 @@
+package execsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxSessionEnvVars bounds the overrides kept per session.
+	maxSessionEnvVars = 32
+	// maxSessionEnvValueLength bounds each override value.
+	maxSessionEnvValueLength = 1024
+)
+
+// ErrSessionEnvDisabled is returned when no env overrides are allowlisted.
+var ErrSessionEnvDisabled = errors.New("session env overrides are disabled: sandbox.sessions.env_allowlist is empty")
+
+// reservedEnvPrefix marks the env vars the server and modules provide.
+const reservedEnvPrefix = "ETHPANDAOPS_"
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sessionEnv holds a session's env overrides and the owner that set them.
+type sessionEnv struct {
+	ownerID string
+	env     map[string]string
+}
+
+// SetSessionEnv merges overrides into a session's env and returns the
+// result. Names must match sandbox.sessions.env_allowlist; an empty value
+// removes an override. Overrides apply to every later execution in the
+// session and are kept in memory only.
+func (s *Service) SetSessionEnv(
+	ctx context.Context,
+	sessionID, ownerID string,
+	overrides map[string]string,
+) (map[string]string, error) {
+	allowlist := s.cfg.Sandbox.Sessions.EnvAllowlist
+	if len(allowlist) == 0 {
+		return nil, ErrSessionEnvDisabled
+	}
+
+	for name, value := range overrides {
+		if !envAllowed(allowlist, name) {
+			return nil, fmt.Errorf(
+				"env var %q is not allowed; allowed: %s", name, strings.Join(allowlist, ", "),
+			)
+		}
+
+		if len(value) > maxSessionEnvValueLength {
+			return nil, fmt.Errorf("env var %q is longer than %d bytes", name, maxSessionEnvValueLength)
+		}
+	}
+
+	sessions, err := s.sandboxSvc.ListSessions(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	live := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		live[session.ID] = true
+	}
+
+	if !live[sessionID] {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+
+	s.sessionEnvMu.Lock()
+	defer s.sessionEnvMu.Unlock()
+
+	// Drop overrides of this owner's sessions that have since expired.
+	for id, entry := range s.sessionEnv {
+		if entry.ownerID == ownerID && !live[id] {
+			delete(s.sessionEnv, id)
+		}
+	}
+
+	merged := make(map[string]string, len(overrides))
+	if entry, ok := s.sessionEnv[sessionID]; ok {
+		maps.Copy(merged, entry.env)
+	}
+
+	for name, value := range overrides {
+		if value == "" {
+			delete(merged, name)
+
+			continue
+		}
+
+		merged[name] = value
+	}
+
+	if len(merged) > maxSessionEnvVars {
+		return nil, fmt.Errorf("sessions may set at most %d env vars", maxSessionEnvVars)
+	}
+
+	s.sessionEnv[sessionID] = sessionEnv{ownerID: ownerID, env: merged}
+
+	return maps.Clone(merged), nil
+}
+
+// SessionEnv returns a copy of a session's env overrides.
+func (s *Service) SessionEnv(sessionID, ownerID string) map[string]string {
+	s.sessionEnvMu.Lock()
+	defer s.sessionEnvMu.Unlock()
+
+	entry, ok := s.sessionEnv[sessionID]
+	if !ok || entry.ownerID != ownerID {
+		return nil
+	}
+
+	return maps.Clone(entry.env)
+}
+
+func (s *Service) forgetSessionEnv(sessionID string) {
+	s.sessionEnvMu.Lock()
+	defer s.sessionEnvMu.Unlock()
+
+	delete(s.sessionEnv, sessionID)
+}
+
+// envAllowed reports whether name matches an allowlist entry. Entries ending
+// in "*" match by prefix. Server-provided ETHPANDAOPS_ variables never match.
+func envAllowed(allowlist []string, name string) bool {
+	if !envNamePattern.MatchString(name) || strings.HasPrefix(name, reservedEnvPrefix) {
+		return false
+	}
+
+	for _, allowed := range allowlist {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == allowed {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,32 @@
+package execsvc
+
+import "testing"
+
+func TestEnvAllowed(t *testing.T) {
+	t.Parallel()
+
+	allowlist := []string{"DEFAULT_NETWORK", "PANDA_USER_*"}
+
+	tests := []struct {
+		name    string
+		allowed bool
+	}{
+		{name: "DEFAULT_NETWORK", allowed: true},
+		{name: "PANDA_USER_SLOT", allowed: true},
+		{name: "DEFAULT_NETWORKS"},
+		{name: "PANDA_OTHER"},
+		{name: "ETHPANDAOPS_API_TOKEN"},
+		{name: "BAD-NAME"},
+		{name: ""},
+	}
+
+	for _, tt := range tests {
+		if got := envAllowed(allowlist, tt.name); got != tt.allowed {
+			t.Errorf("envAllowed(%q) = %v, want %v", tt.name, got, tt.allowed)
+		}
+	}
+
+	if envAllowed([]string{"E*"}, "ETHPANDAOPS_API_URL") {
+		t.Errorf("prefix entries must not match reserved ETHPANDAOPS_ vars")
+	}
+}
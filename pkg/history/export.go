@@ -0,0 +1,288 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+const (
+	// ExportNamespace is the storage namespace holding exported history.
+	ExportNamespace = "history"
+
+	// exportFileName is the Parquet file written to each day partition.
+	exportFileName = "executions.parquet"
+
+	// exportPartitionPrefix starts each Hive-style day partition directory.
+	exportPartitionPrefix = "date="
+
+	// exportDateLayout formats partition dates.
+	exportDateLayout = "2006-01-02"
+)
+
+// ExportConfig configures the history exporter.
+type ExportConfig struct {
+	// Interval between exports.
+	Interval time.Duration
+	// RetentionDays is how many days of partitions are kept.
+	RetentionDays int
+	// IncludeCode adds the executed code to exported rows.
+	IncludeCode bool
+}
+
+// exportRow is the Parquet schema of exported executions.
+type exportRow struct {
+	ExecutionID     string    `parquet:"execution_id"`
+	SessionID       string    `parquet:"session_id,optional"`
+	OwnerID         string    `parquet:"owner_id,optional"`
+	CodeHash        string    `parquet:"code_hash"`
+	Code            string    `parquet:"code,optional"`
+	StartedAt       time.Time `parquet:"started_at,timestamp(millisecond)"`
+	DurationSeconds float64   `parquet:"duration_seconds"`
+	ExitCode        int64     `parquet:"exit_code"`
+	Error           string    `parquet:"error,optional"`
+}
+
+// Exporter periodically writes execution history to day-partitioned Parquet
+// files in storage (history/date=YYYY-MM-DD/executions.parquet), so usage
+// can be analyzed with the same tooling the server provides. Each export
+// merges the store's records into the existing partitions, so records that
+// have aged out of the store are kept until the partition expires.
+type Exporter struct {
+	log     logrus.FieldLogger
+	cfg     ExportConfig
+	store   Store
+	storage storage.Service
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewExporter creates a history exporter.
+func NewExporter(log logrus.FieldLogger, cfg ExportConfig, store Store, storageSvc storage.Service) *Exporter {
+	return &Exporter{
+		log:     log.WithField("component", "history_export"),
+		cfg:     cfg,
+		store:   store,
+		storage: storageSvc,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs exports in the background every interval.
+func (e *Exporter) Start() {
+	e.wg.Add(1)
+
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.done:
+				return
+			case <-ticker.C:
+				if err := e.Export(context.Background(), time.Now()); err != nil {
+					e.log.WithError(err).Warn("Failed to export execution history")
+				}
+			}
+		}
+	}()
+
+	e.log.WithFields(logrus.Fields{
+		"interval":       e.cfg.Interval,
+		"retention_days": e.cfg.RetentionDays,
+	}).Info("History exporter started")
+}
+
+// Stop stops the background loop and runs a final export.
+func (e *Exporter) Stop(ctx context.Context) error {
+	close(e.done)
+	e.wg.Wait()
+
+	return e.Export(ctx, time.Now())
+}
+
+// Export writes every retained day of history and removes expired partitions.
+func (e *Exporter) Export(ctx context.Context, now time.Time) error {
+	records, err := e.store.List(ctx, Filter{Limit: math.MaxInt})
+	if err != nil {
+		return fmt.Errorf("listing history: %w", err)
+	}
+
+	cutoff := now.UTC().AddDate(0, 0, -e.cfg.RetentionDays).Format(exportDateLayout)
+
+	days := make(map[string][]exportRow, 8)
+
+	for _, record := range records {
+		day := record.StartedAt.UTC().Format(exportDateLayout)
+		if day < cutoff {
+			continue
+		}
+
+		days[day] = append(days[day], e.row(record))
+	}
+
+	var errs []error
+
+	written := 0
+
+	for day, rows := range days {
+		changed, err := e.writePartition(day, rows)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("partition %s: %w", day, err))
+
+			continue
+		}
+
+		if changed {
+			written++
+		}
+	}
+
+	removed, err := e.removeExpired(cutoff)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if written > 0 || removed > 0 {
+		e.log.WithFields(logrus.Fields{
+			"partitions_written": written,
+			"partitions_removed": removed,
+		}).Debug("Exported execution history")
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *Exporter) row(record Record) exportRow {
+	row := exportRow{
+		ExecutionID:     record.ExecutionID,
+		SessionID:       record.SessionID,
+		OwnerID:         record.OwnerID,
+		CodeHash:        record.CodeHash,
+		StartedAt:       record.StartedAt.UTC(),
+		DurationSeconds: record.DurationSeconds,
+		ExitCode:        int64(record.ExitCode),
+		Error:           record.Error,
+	}
+
+	if e.cfg.IncludeCode {
+		row.Code = record.Code
+	}
+
+	return row
+}
+
+// writePartition merges rows into a day's existing file and rewrites it when
+// new executions were added. It reports whether the file was written.
+func (e *Exporter) writePartition(day string, rows []exportRow) (bool, error) {
+	key := exportPartitionPrefix + day + "/" + exportFileName
+
+	existing, err := e.readPartition(key)
+	if err != nil {
+		return false, err
+	}
+
+	merged := make(map[string]exportRow, len(existing)+len(rows))
+	for _, row := range existing {
+		merged[row.ExecutionID] = row
+	}
+
+	before := len(merged)
+
+	for _, row := range rows {
+		merged[row.ExecutionID] = row
+	}
+
+	if len(merged) == before && len(existing) > 0 {
+		return false, nil
+	}
+
+	out := make([]exportRow, 0, len(merged))
+	for _, row := range merged {
+		out = append(out, row)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartedAt.Equal(out[j].StartedAt) {
+			return out[i].StartedAt.Before(out[j].StartedAt)
+		}
+
+		return out[i].ExecutionID < out[j].ExecutionID
+	})
+
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, out, parquet.Compression(&parquet.Zstd)); err != nil {
+		return false, fmt.Errorf("encoding parquet: %w", err)
+	}
+
+	if _, _, err := e.storage.Upload(ExportNamespace, key, &buf); err != nil {
+		return false, fmt.Errorf("uploading parquet: %w", err)
+	}
+
+	return true, nil
+}
+
+// readPartition returns the rows of an existing partition file, or nil when
+// it does not exist.
+func (e *Exporter) readPartition(key string) ([]exportRow, error) {
+	data, err := e.storage.ReadFile(ExportNamespace, key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading existing partition: %w", err)
+	}
+
+	rows, err := parquet.Read[exportRow](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding existing partition: %w", err)
+	}
+
+	return rows, nil
+}
+
+// removeExpired deletes partitions dated before cutoff.
+func (e *Exporter) removeExpired(cutoff string) (int, error) {
+	files, err := e.storage.List(ExportNamespace, exportPartitionPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("listing exported partitions: %w", err)
+	}
+
+	removed := 0
+
+	for _, file := range files {
+		partition, _, ok := strings.Cut(file.Key, "/")
+		if !ok {
+			continue
+		}
+
+		if strings.TrimPrefix(partition, exportPartitionPrefix) >= cutoff {
+			continue
+		}
+
+		if err := e.storage.Delete(ExportNamespace, file.Key); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", file.Key, err)
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,76 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+func TestExporterWritesDayPartitions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "executions.jsonl"), 2)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	storageSvc := storage.New(afero.NewMemMapFs(), "/data", "http://localhost:2480")
+	exporter := NewExporter(logrus.New(), ExportConfig{Interval: time.Hour, RetentionDays: 7}, store, storageSvc)
+
+	for _, record := range []Record{
+		{ExecutionID: "a", Code: "print(1)", CodeHash: CodeHash("print(1)"), StartedAt: now.Add(-24 * time.Hour)},
+		{ExecutionID: "b", Code: "print(2)", CodeHash: CodeHash("print(2)"), StartedAt: now.Add(-time.Hour), ExitCode: 1},
+	} {
+		require.NoError(t, store.Append(ctx, record))
+	}
+
+	require.NoError(t, exporter.Export(ctx, now))
+
+	// Record "a" ages out of the store but must survive in its partition.
+	require.NoError(t, store.Append(ctx, Record{ExecutionID: "c", StartedAt: now}))
+	require.NoError(t, store.Append(ctx, Record{ExecutionID: "d", StartedAt: now}))
+	require.NoError(t, exporter.Export(ctx, now))
+
+	yesterday := readExportedRows(t, storageSvc, "date=2026-03-09/executions.parquet")
+	require.Len(t, yesterday, 1)
+	assert.Equal(t, "a", yesterday[0].ExecutionID)
+	assert.Empty(t, yesterday[0].Code, "code is only exported when enabled")
+
+	today := readExportedRows(t, storageSvc, "date=2026-03-10/executions.parquet")
+	require.Len(t, today, 3)
+	assert.Equal(t, "b", today[0].ExecutionID)
+	assert.Equal(t, int64(1), today[0].ExitCode)
+
+	// Advancing past the retention window removes old partitions.
+	require.NoError(t, exporter.Export(ctx, now.AddDate(0, 0, 7)))
+
+	files, err := storageSvc.List(ExportNamespace, "")
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "date=2026-03-10/executions.parquet", files[0].Key)
+}
+
+func readExportedRows(t *testing.T, storageSvc storage.Service, key string) []exportRow {
+	t.Helper()
+
+	data, err := storageSvc.ReadFile(ExportNamespace, key)
+	require.NoError(t, err)
+
+	rows, err := parquet.Read[exportRow](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	return rows
+}
@@ -0,0 +1,223 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultMaxEntries is the number of records kept when no limit is configured.
+const DefaultMaxEntries = 10000
+
+// FileStore is a Store backed by an append-only JSON Lines file. Records are
+// also held in memory for querying; once the file holds twice the configured
+// maximum it is compacted down to the newest records.
+type FileStore struct {
+	mu         sync.RWMutex
+	path       string
+	maxEntries int
+	records    []Record
+	lines      int
+	file       *os.File
+}
+
+// Compile-time interface check.
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore opens (or creates) the execution log at path.
+func NewFileStore(path string, maxEntries int) (*FileStore, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating history directory: %w", err)
+	}
+
+	s := &FileStore{
+		path:       path,
+		maxEntries: maxEntries,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening history file: %w", err)
+	}
+
+	s.file = file
+
+	return s, nil
+}
+
+// Append records an execution.
+func (s *FileStore) Append(_ context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("history store is closed")
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing history record: %w", err)
+	}
+
+	s.records = append(s.records, record)
+	s.lines++
+
+	if len(s.records) > s.maxEntries {
+		s.records = s.records[len(s.records)-s.maxEntries:]
+	}
+
+	if s.lines >= 2*s.maxEntries {
+		return s.compact()
+	}
+
+	return nil
+}
+
+// List returns records matching the filter, newest first.
+func (s *FileStore) List(_ context.Context, filter Filter) ([]Record, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]Record, 0, min(limit, len(s.records)))
+
+	for i := len(s.records) - 1; i >= 0 && len(results) < limit; i-- {
+		if filter.matches(&s.records[i]) {
+			results = append(results, s.records[i])
+		}
+	}
+
+	return results, nil
+}
+
+// Get returns a record by execution ID. Returns nil if not found.
+func (s *FileStore) Get(_ context.Context, executionID string) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if s.records[i].ExecutionID == executionID {
+			record := s.records[i]
+
+			return &record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Close closes the underlying file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+// load reads existing records from disk, skipping malformed lines.
+func (s *FileStore) load() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("opening history file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		s.lines++
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+
+		s.records = append(s.records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading history file: %w", err)
+	}
+
+	if len(s.records) > s.maxEntries {
+		s.records = s.records[len(s.records)-s.maxEntries:]
+	}
+
+	return nil
+}
+
+// compact rewrites the file with only the in-memory records. Callers must hold mu.
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating compacted history file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
+	for i := range s.records {
+		if err := encoder.Encode(&s.records[i]); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("writing compacted history: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("flushing compacted history: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing compacted history: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replacing history file: %w", err)
+	}
+
+	_ = s.file.Close()
+
+	s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("reopening history file: %w", err)
+	}
+
+	s.lines = len(s.records)
+
+	return nil
+}
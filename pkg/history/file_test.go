@@ -0,0 +1,101 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "executions.jsonl")
+
+	store, err := NewFileStore(path, 0)
+	require.NoError(t, err)
+
+	records := []Record{
+		{ExecutionID: "a", OwnerID: "1", SessionID: "s1", Code: "print(1)", StartedAt: time.Now()},
+		{ExecutionID: "b", OwnerID: "2", Code: "print(2)", StartedAt: time.Now()},
+		{ExecutionID: "c", OwnerID: "1", Code: "print(3)", StartedAt: time.Now(), ExitCode: 1},
+	}
+
+	for _, record := range records {
+		require.NoError(t, store.Append(ctx, record))
+	}
+
+	all, err := store.List(ctx, Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "c", all[0].ExecutionID, "newest first")
+
+	owned, err := store.List(ctx, Filter{OwnerID: "1"})
+	require.NoError(t, err)
+	require.Len(t, owned, 2)
+
+	inSession, err := store.List(ctx, Filter{SessionID: "s1"})
+	require.NoError(t, err)
+	require.Len(t, inSession, 1)
+	assert.Equal(t, "a", inSession[0].ExecutionID)
+
+	limited, err := store.List(ctx, Filter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+
+	require.NoError(t, store.Close())
+
+	// Records survive a reopen.
+	reopened, err := NewFileStore(path, 0)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = reopened.Close() })
+
+	record, err := reopened.Get(ctx, "b")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "print(2)", record.Code)
+
+	missing, err := reopened.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "executions.jsonl")
+
+	store, err := NewFileStore(path, 3)
+	require.NoError(t, err)
+
+	for i := range 10 {
+		require.NoError(t, store.Append(ctx, Record{ExecutionID: fmt.Sprintf("exec-%d", i)}))
+	}
+
+	require.NoError(t, store.Close())
+
+	reopened, err := NewFileStore(path, 3)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = reopened.Close() })
+
+	records, err := reopened.List(ctx, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "exec-9", records[0].ExecutionID)
+	assert.Equal(t, "exec-7", records[2].ExecutionID)
+}
+
+func TestCodeHash(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, CodeHash("print(1)"), CodeHash("print(1)"))
+	assert.NotEqual(t, CodeHash("print(1)"), CodeHash("print(2)"))
+}
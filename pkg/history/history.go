@@ -0,0 +1,85 @@
+// Package history records sandbox executions so operators and agents can
+// audit what ran and re-run previous snippets.
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultListLimit is the number of records returned when a filter sets no limit.
+	DefaultListLimit = 50
+
+	// codePreviewLength is the number of bytes of code kept by Summary.
+	codePreviewLength = 200
+)
+
+// Record describes a single sandbox execution.
+type Record struct {
+	ExecutionID     string    `json:"execution_id"`
+	SessionID       string    `json:"session_id,omitempty"`
+	OwnerID         string    `json:"owner_id,omitempty"`
+	CodeHash        string    `json:"code_hash"`
+	Code            string    `json:"code"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	ExitCode        int       `json:"exit_code"`
+	// Error is set when the execution failed before producing an exit code.
+	Error string `json:"error,omitempty"`
+}
+
+// Filter narrows the records returned by List.
+type Filter struct {
+	// OwnerID restricts results to one owner. Empty matches all owners.
+	OwnerID string
+	// SessionID restricts results to one session. Empty matches all sessions.
+	SessionID string
+	// Limit caps the number of records returned. Defaults to DefaultListLimit.
+	Limit int
+}
+
+// Store persists execution records.
+type Store interface {
+	// Append records an execution.
+	Append(ctx context.Context, record Record) error
+	// List returns records matching the filter, newest first.
+	List(ctx context.Context, filter Filter) ([]Record, error)
+	// Get returns a record by execution ID. Returns nil if not found.
+	Get(ctx context.Context, executionID string) (*Record, error)
+	// Close releases resources held by the store.
+	Close() error
+}
+
+// CodeHash returns the hex-encoded SHA256 of code, used to group identical snippets.
+func CodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Summary returns a copy of the record with Code truncated to a short preview,
+// for listings. Use Get to retrieve the full code.
+func (r Record) Summary() Record {
+	if len(r.Code) > codePreviewLength {
+		r.Code = strings.ToValidUTF8(r.Code[:codePreviewLength], "") + "..."
+	}
+
+	return r
+}
+
+// matches reports whether record satisfies the owner and session constraints of f.
+func (f Filter) matches(record *Record) bool {
+	if f.OwnerID != "" && record.OwnerID != f.OwnerID {
+		return false
+	}
+
+	if f.SessionID != "" && record.SessionID != f.SessionID {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,134 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+// NotebookNamespace is the storage namespace holding exported notebooks.
+const NotebookNamespace = "notebooks"
+
+// notebook is the subset of the Jupyter nbformat 4 schema that exports use.
+type notebook struct {
+	Cells         []notebookCell `json:"cells"`
+	Metadata      map[string]any `json:"metadata"`
+	NBFormat      int            `json:"nbformat"`
+	NBFormatMinor int            `json:"nbformat_minor"`
+}
+
+type notebookCell struct {
+	ID       string         `json:"id"`
+	CellType string         `json:"cell_type"`
+	Metadata map[string]any `json:"metadata"`
+	Source   []string       `json:"source"`
+	// ExecutionCount and Outputs are set, as null and [], on code cells
+	// only; markdown cells must not have them.
+	ExecutionCount json.RawMessage `json:"execution_count,omitempty"`
+	Outputs        json.RawMessage `json:"outputs,omitempty"`
+}
+
+// Notebook renders a session's executions as a Jupyter notebook: a code cell
+// per execution, oldest first, followed by links to the files it stored.
+// Outputs are not recorded in history, so cells are left unexecuted; the
+// execution ID, exit code and error are kept in each cell's metadata.
+func Notebook(sessionID string, records []Record, files map[string][]storage.File) ([]byte, error) {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(sorted[j].StartedAt)
+	})
+
+	header := fmt.Sprintf("# Session %s\n\n%d executions", sessionID, len(sorted))
+	if len(sorted) > 0 {
+		header += fmt.Sprintf(" from %s to %s",
+			sorted[0].StartedAt.UTC().Format(time.RFC3339),
+			sorted[len(sorted)-1].StartedAt.UTC().Format(time.RFC3339))
+	}
+
+	header += ". Exported from panda; the code uses the ethpandaops library, which needs the panda proxy to reach datasources."
+
+	nb := notebook{
+		Cells: []notebookCell{markdownCell("header", header)},
+		Metadata: map[string]any{
+			"kernelspec":    map[string]any{"name": "python3", "display_name": "Python 3", "language": "python"},
+			"language_info": map[string]any{"name": "python"},
+			"panda":         map[string]any{"session_id": sessionID},
+		},
+		NBFormat:      4,
+		NBFormatMinor: 5,
+	}
+
+	for _, record := range sorted {
+		metadata := map[string]any{
+			"panda": map[string]any{
+				"execution_id": record.ExecutionID,
+				"started_at":   record.StartedAt.UTC().Format(time.RFC3339),
+				"exit_code":    record.ExitCode,
+				"error":        record.Error,
+			},
+		}
+
+		nb.Cells = append(nb.Cells, notebookCell{
+			ID:             record.ExecutionID,
+			CellType:       "code",
+			Metadata:       metadata,
+			Source:         notebookSource(record.Code),
+			ExecutionCount: json.RawMessage("null"),
+			Outputs:        json.RawMessage("[]"),
+		})
+
+		if links := fileLinks(files[record.ExecutionID]); links != "" {
+			nb.Cells = append(nb.Cells, markdownCell(record.ExecutionID+"-files", links))
+		}
+	}
+
+	data, err := json.MarshalIndent(nb, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding notebook: %w", err)
+	}
+
+	return data, nil
+}
+
+func markdownCell(id, text string) notebookCell {
+	return notebookCell{
+		ID:       id,
+		CellType: "markdown",
+		Metadata: map[string]any{},
+		Source:   notebookSource(text),
+	}
+}
+
+// notebookSource splits text into lines that keep their newlines, as
+// nbformat stores multi-line sources.
+func notebookSource(text string) []string {
+	lines := strings.SplitAfter(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return []string{}
+	}
+
+	return lines
+}
+
+// fileLinks lists an execution's stored files as Markdown links.
+func fileLinks(files []storage.File) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString("Files:\n")
+
+	for _, file := range files {
+		fmt.Fprintf(&b, "\n- [%s](%s)", file.Key, file.URL)
+	}
+
+	return b.String()
+}
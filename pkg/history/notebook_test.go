@@ -0,0 +1,61 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+func TestNotebook(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	records := []Record{
+		{ExecutionID: "b", SessionID: "s1", Code: "print(df)\n", StartedAt: start.Add(time.Minute), ExitCode: 1},
+		{ExecutionID: "a", SessionID: "s1", Code: "import pandas\ndf = pandas.DataFrame()", StartedAt: start},
+	}
+	files := map[string][]storage.File{
+		"a": {{Key: "chart.png", URL: "http://localhost:2480/api/v1/storage/files/a/chart.png"}},
+	}
+
+	data, err := Notebook("s1", records, files)
+	require.NoError(t, err)
+
+	var nb map[string]any
+	require.NoError(t, json.Unmarshal(data, &nb))
+
+	assert.Equal(t, float64(4), nb["nbformat"])
+	assert.Equal(t, float64(5), nb["nbformat_minor"])
+
+	cells := nb["cells"].([]any)
+	require.Len(t, cells, 4)
+
+	header := cells[0].(map[string]any)
+	assert.Equal(t, "markdown", header["cell_type"])
+	assert.NotContains(t, header, "outputs")
+	assert.Contains(t, header["source"].([]any)[0], "# Session s1")
+
+	first := cells[1].(map[string]any)
+	assert.Equal(t, "code", first["cell_type"])
+	assert.Equal(t, "a", first["id"])
+	assert.Equal(t, []any{"import pandas\n", "df = pandas.DataFrame()"}, first["source"])
+	assert.Nil(t, first["execution_count"])
+	assert.Contains(t, first, "execution_count")
+	assert.Equal(t, []any{}, first["outputs"])
+
+	links := cells[2].(map[string]any)
+	assert.Equal(t, "markdown", links["cell_type"])
+	assert.Equal(t, []any{
+		"Files:\n", "\n", "- [chart.png](http://localhost:2480/api/v1/storage/files/a/chart.png)",
+	}, links["source"])
+
+	last := cells[3].(map[string]any)
+	assert.Equal(t, []any{"print(df)"}, last["source"])
+	assert.Equal(t, float64(1), last["metadata"].(map[string]any)["panda"].(map[string]any)["exit_code"])
+}
@@ -0,0 +1,302 @@
+// Package maintenance evaluates recurring datasource maintenance windows.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// MaxDuration bounds a single maintenance window.
+const MaxDuration = 7 * 24 * time.Hour
+
+// nextSearchLimit bounds how far ahead Status looks for the next window.
+const nextSearchLimit = 366 * 24 * time.Hour
+
+// Validate checks a window's schedule and duration.
+func Validate(w types.MaintenanceWindow) error {
+	if _, err := parseSchedule(w.Schedule); err != nil {
+		return err
+	}
+
+	if w.Duration < time.Minute || w.Duration > MaxDuration {
+		return fmt.Errorf("duration must be between 1m and %s", MaxDuration)
+	}
+
+	return nil
+}
+
+// Active returns the window in effect at now and when it ends. When several
+// overlap, the one ending last is returned.
+func Active(windows []types.MaintenanceWindow, now time.Time) (types.MaintenanceWindow, time.Time, bool) {
+	var (
+		active types.MaintenanceWindow
+		end    time.Time
+		found  bool
+	)
+
+	now = now.UTC()
+
+	for _, w := range windows {
+		s, err := parseSchedule(w.Schedule)
+		if err != nil {
+			continue
+		}
+
+		start, ok := s.prev(now, now.Add(-w.Duration))
+		if !ok || !now.Before(start.Add(w.Duration)) {
+			continue
+		}
+
+		if windowEnd := start.Add(w.Duration); !found || windowEnd.After(end) {
+			active, end, found = w, windowEnd, true
+		}
+	}
+
+	return active, end, found
+}
+
+// Next returns the earliest window starting after now, within a year.
+func Next(windows []types.MaintenanceWindow, now time.Time) (types.MaintenanceWindow, time.Time, bool) {
+	var (
+		next  types.MaintenanceWindow
+		start time.Time
+		found bool
+	)
+
+	from := now.UTC().Truncate(time.Minute).Add(time.Minute)
+
+	for _, w := range windows {
+		s, err := parseSchedule(w.Schedule)
+		if err != nil {
+			continue
+		}
+
+		t, ok := s.next(from, from.Add(nextSearchLimit))
+		if ok && (!found || t.Before(start)) {
+			next, start, found = w, t, true
+		}
+	}
+
+	return next, start, found
+}
+
+// Status reports a datasource's availability at now, or nil when it has no
+// maintenance windows.
+func Status(windows []types.MaintenanceWindow, now time.Time) *types.DatasourceStatus {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	status := &types.DatasourceStatus{Available: true}
+
+	if w, end, ok := Active(windows, now); ok {
+		status.Available = false
+		status.Reason = Describe(w)
+		status.Until = &end
+	}
+
+	if _, start, ok := Next(windows, now); ok {
+		status.NextMaintenance = &start
+	}
+
+	return status
+}
+
+// Describe returns the window's reason, or a generic one when unset.
+func Describe(w types.MaintenanceWindow) string {
+	if w.Reason != "" {
+		return w.Reason
+	}
+
+	return "scheduled maintenance"
+}
+
+// schedule is a parsed five-field cron expression. Each field is a bitmask
+// of the values it matches.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record a day field starting with "*". As in cron,
+	// when both day fields are restricted a day matches either of them.
+	domAny, dowAny bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+
+	fields = [5]field{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: monthNames},
+		{name: "day of week", min: 0, max: 7, names: dayNames},
+	}
+)
+
+// parseSchedule parses "minute hour day-of-month month day-of-week". Fields
+// accept "*", values, ranges, lists and steps, plus JAN-DEC and SUN-SAT.
+func parseSchedule(expr string) (*schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	var masks [5]uint64
+
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", expr, err)
+		}
+
+		masks[i] = mask
+	}
+
+	// Sunday is both 0 and 7.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+
+	return &schedule{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var mask uint64
+
+	for item := range strings.SplitSeq(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, stepExpr)
+			}
+
+			step = n
+		}
+
+		low, high := f.min, f.max
+
+		if rangeExpr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-")
+
+			var err error
+			if low, err = fieldValue(lowExpr, f); err != nil {
+				return 0, err
+			}
+
+			high = low
+			if isRange {
+				if high, err = fieldValue(highExpr, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+
+			if high < low {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rangeExpr)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+
+	if mask == 0 {
+		return 0, errors.New("empty " + f.name)
+	}
+
+	return mask, nil
+}
+
+func fieldValue(expr string, f field) (int, error) {
+	if v, ok := f.names[strings.ToUpper(expr)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, expr)
+	}
+
+	return v, nil
+}
+
+func (s *schedule) matchesDay(t time.Time) bool {
+	if s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// prev returns the latest firing time at or before t and not before floor.
+func (s *schedule) prev(t, floor time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+
+	for !t.Before(floor) {
+		switch {
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(-time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// next returns the earliest firing time at or after t and before limit.
+func (s *schedule) next(t, limit time.Time) (time.Time, bool) {
+	for t.Before(limit) {
+		switch {
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
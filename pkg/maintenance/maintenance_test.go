@@ -0,0 +1,70 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestActiveAndNext(t *testing.T) {
+	// Sundays 02:00-04:00 UTC. 2026-03-01 is a Sunday.
+	windows := []types.MaintenanceWindow{{Schedule: "0 2 * * SUN", Duration: 2 * time.Hour, Reason: "upgrade"}}
+
+	during := time.Date(2026, 3, 1, 3, 15, 0, 0, time.UTC)
+
+	w, end, ok := Active(windows, during)
+	require.True(t, ok)
+	assert.Equal(t, "upgrade", w.Reason)
+	assert.Equal(t, time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC), end)
+
+	_, _, ok = Active(windows, time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "the window ends exclusively")
+
+	_, _, ok = Active(windows, time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "Monday is outside the window")
+
+	_, start, ok := Next(windows, during)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 8, 2, 0, 0, 0, time.UTC), start)
+
+	status := Status(windows, during)
+	require.NotNil(t, status)
+	assert.False(t, status.Available)
+	assert.Equal(t, "upgrade", status.Reason)
+
+	assert.Nil(t, Status(nil, during))
+}
+
+func TestActiveAcrossMidnight(t *testing.T) {
+	windows := []types.MaintenanceWindow{{Schedule: "30 23 1-7 * *", Duration: 90 * time.Minute}}
+
+	w, end, ok := Active(windows, time.Date(2026, 3, 2, 0, 45, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "scheduled maintenance", Describe(w))
+	assert.Equal(t, time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC), end)
+}
+
+func TestParseSchedule(t *testing.T) {
+	s, err := parseSchedule("*/15 0-6/2 * JAN,jul 7")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1|1<<15|1<<30|1<<45), s.minute)
+	assert.Equal(t, uint64(1|1<<2|1<<4|1<<6), s.hour)
+	assert.Equal(t, uint64(1<<1|1<<7), s.month)
+	assert.NotZero(t, s.dow&1, "7 is Sunday")
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "* * * FOO *"} {
+		_, err := parseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(types.MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour}))
+	require.Error(t, Validate(types.MaintenanceWindow{Schedule: "0 2 * * *"}))
+	require.Error(t, Validate(types.MaintenanceWindow{Schedule: "0 2 * * *", Duration: 8 * 24 * time.Hour}))
+	require.Error(t, Validate(types.MaintenanceWindow{Schedule: "nightly", Duration: time.Hour}))
+}
@@ -0,0 +1,299 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// healthProbeTokenPrefix prefixes the proxy token IDs used for health probes.
+// Each datasource gets its own ID so concurrent probes don't revoke each
+// other's tokens.
+const healthProbeTokenPrefix = "health-probe-"
+
+// HealthChecker probes module upstreams and caches each module's result for
+// an interval, so health endpoints don't put load on upstreams.
+type HealthChecker struct {
+	registry *Registry
+	interval time.Duration
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]types.ModuleHealth
+
+	proxyMu        sync.Mutex
+	proxyProbe     types.HealthProbe
+	proxyCheckedAt time.Time
+}
+
+// NewHealthChecker creates a health checker over the registry's active modules.
+func NewHealthChecker(registry *Registry, interval, timeout time.Duration) *HealthChecker {
+	return &HealthChecker{
+		registry: registry,
+		interval: interval,
+		timeout:  timeout,
+		cache:    make(map[string]types.ModuleHealth, 8),
+	}
+}
+
+// Check returns the health of every active module. Modules whose cached
+// result is older than the interval are probed again, concurrently.
+func (h *HealthChecker) Check(ctx context.Context) []types.ModuleHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	modules := h.registry.active()
+	results := make([]types.ModuleHealth, len(modules))
+	now := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i, ext := range modules {
+		prober, ok := ext.(HealthProber)
+		if !ok {
+			results[i] = types.ModuleHealth{Module: ext.Name(), Status: types.HealthStatusNotProbed}
+
+			continue
+		}
+
+		if cached, ok := h.cache[ext.Name()]; ok && cached.CheckedAt != nil && now.Sub(*cached.CheckedAt) < h.interval {
+			results[i] = cached
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, name string, prober HealthProber) {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			probes := prober.ProbeHealth(probeCtx)
+			checkedAt := time.Now()
+
+			results[i] = types.ModuleHealth{
+				Module:    name,
+				Status:    healthStatus(probes),
+				Probes:    probes,
+				CheckedAt: &checkedAt,
+			}
+		}(i, ext.Name(), prober)
+	}
+
+	wg.Wait()
+
+	for _, result := range results {
+		if result.CheckedAt != nil {
+			h.cache[result.Module] = result
+		}
+	}
+
+	return results
+}
+
+// Watch checks every module each interval until ctx is done and calls fn
+// when a module's status changes. A module first seen unhealthy or degraded
+// is reported too, with an empty previous status.
+func (h *HealthChecker) Watch(ctx context.Context, fn func(previous, current types.ModuleHealth)) {
+	interval := h.interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := make(map[string]types.ModuleHealth, 8)
+
+	for {
+		healthTransitions(last, h.Check(ctx), fn)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthTransitions calls fn for each result whose status differs from the
+// one in last, and records the results in last.
+func healthTransitions(last map[string]types.ModuleHealth, results []types.ModuleHealth, fn func(previous, current types.ModuleHealth)) {
+	for _, result := range results {
+		if result.Status == types.HealthStatusNotProbed {
+			continue
+		}
+
+		previous, seen := last[result.Module]
+		last[result.Module] = result
+
+		switch {
+		case seen && previous.Status != result.Status:
+			fn(previous, result)
+		case !seen && result.Status != types.HealthStatusHealthy:
+			fn(types.ModuleHealth{Module: result.Module}, result)
+		}
+	}
+}
+
+// CheckProxy reports whether the proxy itself is reachable, probing its
+// /health endpoint at most once per interval.
+func (h *HealthChecker) CheckProxy(ctx context.Context, proxySvc proxy.Service) types.HealthProbe {
+	h.proxyMu.Lock()
+	defer h.proxyMu.Unlock()
+
+	if !h.proxyCheckedAt.IsZero() && time.Since(h.proxyCheckedAt) < h.interval {
+		return h.proxyProbe
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	h.proxyProbe = ProbeProxyReachable(probeCtx, proxySvc)
+	h.proxyCheckedAt = time.Now()
+
+	return h.proxyProbe
+}
+
+func healthStatus(probes []types.HealthProbe) string {
+	if len(probes) == 0 {
+		return types.HealthStatusNotProbed
+	}
+
+	healthy := 0
+	for _, probe := range probes {
+		if probe.Healthy {
+			healthy++
+		}
+	}
+
+	switch healthy {
+	case len(probes):
+		return types.HealthStatusHealthy
+	case 0:
+		return types.HealthStatusUnhealthy
+	default:
+		return types.HealthStatusDegraded
+	}
+}
+
+// ProbeProxyDatasources probes each datasource concurrently with a GET to
+// path on the proxy.
+func ProbeProxyDatasources(ctx context.Context, proxySvc proxy.Service, datasources []types.DatasourceInfo, path string) []types.HealthProbe {
+	probes := make([]types.HealthProbe, len(datasources))
+
+	var wg sync.WaitGroup
+
+	for i, ds := range datasources {
+		wg.Add(1)
+
+		go func(i int, name string) {
+			defer wg.Done()
+
+			probes[i] = ProbeProxy(ctx, proxySvc, name, path)
+		}(i, ds.Name)
+	}
+
+	wg.Wait()
+
+	return probes
+}
+
+// ProbeURL probes datasource with a GET to url.
+func ProbeURL(ctx context.Context, datasource, url string) types.HealthProbe {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return types.HealthProbe{Datasource: datasource, Error: err.Error()}
+	}
+
+	return probe(http.DefaultClient, datasource, req)
+}
+
+// ProbeProxyReachable probes the proxy's own /health endpoint.
+func ProbeProxyReachable(ctx context.Context, proxySvc proxy.Service) types.HealthProbe {
+	const name = "proxy"
+
+	baseURL := strings.TrimRight(proxySvc.URL(), "/")
+	if baseURL == "" {
+		return types.HealthProbe{Datasource: name, Error: "proxy URL is empty"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+	if err != nil {
+		return types.HealthProbe{Datasource: name, Error: err.Error()}
+	}
+
+	return probe(&http.Client{Transport: proxySvc.Transport()}, name, req)
+}
+
+// ProbeProxy probes a proxied datasource with a GET to path on the proxy,
+// e.g. "/prometheus/-/ready".
+func ProbeProxy(ctx context.Context, proxySvc proxy.Service, datasource, path string) types.HealthProbe {
+	baseURL := strings.TrimRight(proxySvc.URL(), "/")
+	if baseURL == "" {
+		return types.HealthProbe{Datasource: datasource, Error: "proxy URL is empty"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return types.HealthProbe{Datasource: datasource, Error: err.Error()}
+	}
+
+	req.Header.Set(handlers.DatasourceHeader, datasource)
+
+	tokenID := healthProbeTokenPrefix + datasource
+
+	token := proxySvc.RegisterToken(tokenID)
+	defer proxySvc.RevokeToken(tokenID)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := proxySvc.SignRequest(req); err != nil {
+		return types.HealthProbe{Datasource: datasource, Error: fmt.Sprintf("signing request: %v", err)}
+	}
+
+	return probe(&http.Client{Transport: proxySvc.Transport()}, datasource, req)
+}
+
+func probe(client *http.Client, datasource string, req *http.Request) types.HealthProbe {
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return types.HealthProbe{
+			Datasource: datasource,
+			LatencyMS:  latencyMS(start),
+			Error:      err.Error(),
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	result := types.HealthProbe{
+		Datasource: datasource,
+		LatencyMS:  latencyMS(start),
+		Healthy:    resp.StatusCode >= 200 && resp.StatusCode < 300,
+	}
+
+	if !result.Healthy {
+		result.Error = fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return result
+}
+
+func latencyMS(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
@@ -0,0 +1,146 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+type healthTestExtension struct {
+	baseTestExtension
+	probes []types.HealthProbe
+	calls  int
+}
+
+func (e *healthTestExtension) ProbeHealth(_ context.Context) []types.HealthProbe {
+	e.calls++
+
+	return e.probes
+}
+
+func TestHealthCheckerCachesProbes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	reg := NewRegistry(logrus.New())
+
+	ext := &healthTestExtension{
+		baseTestExtension: baseTestExtension{name: "upstream"},
+		probes: []types.HealthProbe{
+			{Datasource: "a", Healthy: true},
+			{Datasource: "b", Error: "status 503"},
+		},
+	}
+	reg.Add(ext)
+	reg.Add(&baseTestExtension{name: "idle"})
+
+	for _, name := range reg.All() {
+		if err := reg.InitModule(name, nil); err != nil {
+			t.Fatalf("InitModule(%q) error = %v", name, err)
+		}
+	}
+
+	checker := NewHealthChecker(reg, time.Hour, time.Second)
+
+	results := checker.Check(ctx)
+	if len(results) != 2 {
+		t.Fatalf("Check() returned %d modules, want 2", len(results))
+	}
+
+	statuses := make(map[string]string, len(results))
+	for _, result := range results {
+		statuses[result.Module] = result.Status
+	}
+
+	if statuses["upstream"] != types.HealthStatusDegraded || statuses["idle"] != types.HealthStatusNotProbed {
+		t.Fatalf("Check() statuses = %#v, want upstream degraded and idle not_probed", statuses)
+	}
+
+	checker.Check(ctx)
+	if ext.calls != 1 {
+		t.Fatalf("ProbeHealth() called %d times within interval, want 1", ext.calls)
+	}
+
+	expired := NewHealthChecker(reg, 0, time.Second)
+	expired.Check(ctx)
+	expired.Check(ctx)
+	if ext.calls != 3 {
+		t.Fatalf("ProbeHealth() called %d times with zero interval, want 3", ext.calls)
+	}
+}
+
+func TestProbeURL(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	if probe := ProbeURL(ctx, "up", srv.URL+"/up"); !probe.Healthy || probe.Error != "" {
+		t.Fatalf("ProbeURL(up) = %#v, want healthy", probe)
+	}
+
+	probe := ProbeURL(ctx, "down", srv.URL+"/down")
+	if probe.Healthy || probe.Error != "status 503: unavailable" {
+		t.Fatalf("ProbeURL(down) = %#v, want unhealthy with status error", probe)
+	}
+}
+
+func TestHealthTransitions(t *testing.T) {
+	t.Parallel()
+
+	last := make(map[string]types.ModuleHealth, 2)
+
+	var changes []string
+
+	record := func(previous, current types.ModuleHealth) {
+		changes = append(changes, current.Module+":"+previous.Status+"->"+current.Status)
+	}
+
+	healthTransitions(last, []types.ModuleHealth{
+		{Module: "loki", Status: types.HealthStatusHealthy},
+		{Module: "prometheus", Status: types.HealthStatusUnhealthy},
+		{Module: "idle", Status: types.HealthStatusNotProbed},
+	}, record)
+
+	healthTransitions(last, []types.ModuleHealth{
+		{Module: "loki", Status: types.HealthStatusDegraded},
+		{Module: "prometheus", Status: types.HealthStatusUnhealthy},
+	}, record)
+
+	healthTransitions(last, []types.ModuleHealth{
+		{Module: "loki", Status: types.HealthStatusHealthy},
+		{Module: "prometheus", Status: types.HealthStatusHealthy},
+	}, record)
+
+	want := []string{
+		"prometheus:->unhealthy",
+		"loki:healthy->degraded",
+		"loki:degraded->healthy",
+		"prometheus:unhealthy->healthy",
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("transitions = %v, want %v", changes, want)
+	}
+
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", changes, want)
+		}
+	}
+}
@@ -30,6 +30,26 @@ type ProxyAware interface {
 	SetProxyClient(client proxy.Service)
 }
 
+// SnapshotAware is an optional interface for modules that snapshot fetched
+// data for offline mode. It is called before Start.
+type SnapshotAware interface {
+	// SetSnapshotDir sets the directory snapshots are saved to. When offline
+	// is true the module must serve its snapshots and make no outbound calls.
+	SetSnapshotDir(dir string, offline bool)
+}
+
+// SampleRowsAware is an optional interface for modules that can attach
+// sample rows to their schema resources. It is called before Start.
+type SampleRowsAware interface {
+	SetSampleRows(cfg types.SampleRowsConfig)
+}
+
+// SavedQueriesAware is an optional interface for modules that list
+// operator-curated queries. It is called before Start.
+type SavedQueriesAware interface {
+	SetSavedQueries(queries []types.SavedQuery)
+}
+
 // ProxyDiscoverable modules initialize from datasources discovered via the proxy.
 type ProxyDiscoverable interface {
 	// InitFromDiscovery initializes the module from discovered datasources.
@@ -61,6 +81,13 @@ type ResourceRegistry interface {
 	RegisterTemplate(res types.TemplateResource)
 }
 
+// ResourceNotifier is implemented by the ResourceRegistry passed to
+// RegisterResources. Modules whose resource content changes in the
+// background report the changed URIs so connected clients are notified.
+type ResourceNotifier interface {
+	NotifyUpdated(uris ...string)
+}
+
 // SandboxEnvProvider contributes sandbox environment variables.
 type SandboxEnvProvider interface {
 	SandboxEnv() (map[string]string, error)
@@ -76,6 +103,31 @@ type ExamplesProvider interface {
 	Examples() map[string]types.ExampleCategory
 }
 
+// ErrorHinter is implemented by modules that recognize their upstream's error
+// messages in execution output and can suggest a fix.
+type ErrorHinter interface {
+	ErrorHints(output string) []types.ErrorHint
+}
+
+// CodeLinter is implemented by modules that can spot likely mistakes in calls
+// to their Python functions before code runs, such as a query that breaks
+// the module's cluster rules.
+type CodeLinter interface {
+	LintCalls(calls []types.PythonCall) []types.LintFinding
+}
+
+// HealthProber is implemented by modules that can probe their upstreams with
+// a real request. ctx carries the probe timeout.
+type HealthProber interface {
+	ProbeHealth(ctx context.Context) []types.HealthProbe
+}
+
+// CoverageTargetProvider reports discovered objects (tables, metrics) so
+// maintainers can see which ones lack query examples.
+type CoverageTargetProvider interface {
+	CoverageTargets(ctx context.Context) ([]types.CoverageTarget, error)
+}
+
 // PythonAPIDocsProvider contributes Python module docs.
 type PythonAPIDocsProvider interface {
 	PythonAPIDocs() map[string]types.ModuleDoc
@@ -86,6 +138,13 @@ type GettingStartedSnippetProvider interface {
 	GettingStartedSnippet() string
 }
 
+// SchemaProvider is implemented by modules that accept a YAML config in
+// Init. ConfigSchema returns a zero value of that config, from which its
+// JSON Schema is generated.
+type SchemaProvider interface {
+	ConfigSchema() any
+}
+
 // ResourceProvider contributes custom MCP resources.
 type ResourceProvider interface {
 	RegisterResources(log logrus.FieldLogger, reg ResourceRegistry) error
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/maintenance"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -18,6 +21,11 @@ type Registry struct {
 	mu          sync.RWMutex
 	all         map[string]Module
 	initialized []Module
+	disabled    map[string]struct{}
+
+	// exampleSources contribute examples outside any module, such as
+	// examples promoted from executions.
+	exampleSources []ExamplesProvider
 }
 
 // NewRegistry creates a new module registry.
@@ -26,6 +34,7 @@ func NewRegistry(log logrus.FieldLogger) *Registry {
 		log:         log.WithField("component", "module_registry"),
 		all:         make(map[string]Module, 4),
 		initialized: make([]Module, 0, 4),
+		disabled:    make(map[string]struct{}, 2),
 	}
 }
 
@@ -148,12 +157,100 @@ func (r *Registry) IsInitialized(name string) bool {
 	return false
 }
 
+// IsDisabled reports whether the named module was disabled at runtime.
+func (r *Registry) IsDisabled(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.disabled[name]
+
+	return ok
+}
+
+// Disable stops an initialized module and hides it from the aggregated
+// capabilities (sandbox env, datasources, examples, docs) until it is
+// re-enabled.
+func (r *Registry) Disable(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ext, err := r.initializedLocked(name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := r.disabled[name]; ok {
+		return fmt.Errorf("module %q is already disabled", name)
+	}
+
+	if err := ext.Stop(ctx); err != nil {
+		return fmt.Errorf("stopping module %q: %w", name, err)
+	}
+
+	r.disabled[name] = struct{}{}
+
+	r.log.WithField("module", name).Info("Module disabled")
+
+	return nil
+}
+
+// Enable restarts a module previously disabled with Disable.
+func (r *Registry) Enable(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ext, err := r.initializedLocked(name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := r.disabled[name]; !ok {
+		return fmt.Errorf("module %q is not disabled", name)
+	}
+
+	if err := ext.Start(ctx); err != nil {
+		return fmt.Errorf("starting module %q: %w", name, err)
+	}
+
+	delete(r.disabled, name)
+
+	r.log.WithField("module", name).Info("Module enabled")
+
+	return nil
+}
+
+func (r *Registry) initializedLocked(name string) (Module, error) {
+	if _, ok := r.all[name]; !ok {
+		return nil, fmt.Errorf("unknown module %q", name)
+	}
+
+	for _, ext := range r.initialized {
+		if ext.Name() == name {
+			return ext, nil
+		}
+	}
+
+	return nil, fmt.Errorf("module %q is not initialized", name)
+}
+
+// active returns the initialized modules that are not disabled.
+func (r *Registry) active() []Module {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	modules := make([]Module, 0, len(r.initialized))
+	for _, ext := range r.initialized {
+		if _, ok := r.disabled[ext.Name()]; !ok {
+			modules = append(modules, ext)
+		}
+	}
+
+	return modules
+}
+
 // StartAll starts all initialized modules.
 func (r *Registry) StartAll(ctx context.Context) error {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	for _, ext := range modules {
 		if err := ext.Start(ctx); err != nil {
@@ -166,12 +263,9 @@ func (r *Registry) StartAll(ctx context.Context) error {
 	return nil
 }
 
-// StopAll stops all initialized modules.
+// StopAll stops all initialized modules that are not already disabled.
 func (r *Registry) StopAll(ctx context.Context) {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	for _, ext := range modules {
 		if err := ext.Stop(ctx); err != nil {
@@ -182,10 +276,7 @@ func (r *Registry) StopAll(ctx context.Context) {
 
 // SandboxEnv aggregates sandbox environment variables from all initialized modules.
 func (r *Registry) SandboxEnv() (map[string]string, error) {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	env := make(map[string]string, 8)
 
@@ -207,11 +298,10 @@ func (r *Registry) SandboxEnv() (map[string]string, error) {
 }
 
 // DatasourceInfo aggregates datasource info from all initialized modules.
+// Datasources with maintenance windows get their current Status.
 func (r *Registry) DatasourceInfo() []types.DatasourceInfo {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
+	now := time.Now()
 
 	var infos []types.DatasourceInfo
 	for _, ext := range modules {
@@ -220,7 +310,10 @@ func (r *Registry) DatasourceInfo() []types.DatasourceInfo {
 			continue
 		}
 
-		infos = append(infos, provider.DatasourceInfo()...)
+		for _, info := range provider.DatasourceInfo() {
+			info.Status = maintenance.Status(info.Maintenance, now)
+			infos = append(infos, info)
+		}
 	}
 
 	return infos
@@ -228,10 +321,7 @@ func (r *Registry) DatasourceInfo() []types.DatasourceInfo {
 
 // Examples aggregates query examples from all initialized modules.
 func (r *Registry) Examples() map[string]types.ExampleCategory {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	result := make(map[string]types.ExampleCategory, 16)
 
@@ -244,15 +334,118 @@ func (r *Registry) Examples() map[string]types.ExampleCategory {
 		maps.Copy(result, provider.Examples())
 	}
 
+	r.mu.RLock()
+	sources := slices.Clone(r.exampleSources)
+	r.mu.RUnlock()
+
+	// Extra sources extend module categories rather than replacing them.
+	for _, source := range sources {
+		for key, category := range source.Examples() {
+			if existing, ok := result[key]; ok {
+				existing.Examples = append(slices.Clone(existing.Examples), category.Examples...)
+				category = existing
+			}
+
+			result[key] = category
+		}
+	}
+
 	return result
 }
 
+// AddExamplesSource adds examples that are not provided by a module. They
+// are included in Examples regardless of which modules are active.
+func (r *Registry) AddExamplesSource(source ExamplesProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exampleSources = append(r.exampleSources, source)
+}
+
+// CoverageTargets aggregates coverage targets from all initialized modules.
+// Modules that fail to report are returned in errs keyed by module name.
+func (r *Registry) CoverageTargets(ctx context.Context) ([]types.CoverageTarget, map[string]error) {
+	modules := r.active()
+
+	var (
+		targets []types.CoverageTarget
+		errs    map[string]error
+	)
+
+	for _, ext := range modules {
+		provider, ok := ext.(CoverageTargetProvider)
+		if !ok {
+			continue
+		}
+
+		moduleTargets, err := provider.CoverageTargets(ctx)
+		if err != nil {
+			if errs == nil {
+				errs = make(map[string]error, 1)
+			}
+
+			errs[ext.Name()] = err
+
+			continue
+		}
+
+		targets = append(targets, moduleTargets...)
+	}
+
+	return targets, errs
+}
+
+// ErrorHints collects hints for errors in execution output from all active
+// modules. Duplicate messages are dropped.
+func (r *Registry) ErrorHints(output string) []types.ErrorHint {
+	var (
+		hints []types.ErrorHint
+		seen  map[string]struct{}
+	)
+
+	for _, ext := range r.active() {
+		hinter, ok := ext.(ErrorHinter)
+		if !ok {
+			continue
+		}
+
+		for _, hint := range hinter.ErrorHints(output) {
+			if seen == nil {
+				seen = make(map[string]struct{}, 4)
+			}
+
+			if _, ok := seen[hint.Message]; ok {
+				continue
+			}
+
+			seen[hint.Message] = struct{}{}
+			hints = append(hints, hint)
+		}
+	}
+
+	return hints
+}
+
+// LintCalls collects findings for module function calls from all active
+// modules.
+func (r *Registry) LintCalls(calls []types.PythonCall) []types.LintFinding {
+	var findings []types.LintFinding
+
+	for _, ext := range r.active() {
+		linter, ok := ext.(CodeLinter)
+		if !ok {
+			continue
+		}
+
+		findings = append(findings, linter.LintCalls(calls)...)
+	}
+
+	return findings
+}
+
 // PythonAPIDocs aggregates Python API docs from all initialized modules.
 func (r *Registry) PythonAPIDocs() map[string]types.ModuleDoc {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	result := make(map[string]types.ModuleDoc, 8)
 
@@ -271,10 +464,7 @@ func (r *Registry) PythonAPIDocs() map[string]types.ModuleDoc {
 // GettingStartedSnippets aggregates getting-started snippets from
 // all initialized modules.
 func (r *Registry) GettingStartedSnippets() string {
-	r.mu.RLock()
-	modules := make([]Module, len(r.initialized))
-	copy(modules, r.initialized)
-	r.mu.RUnlock()
+	modules := r.active()
 
 	var snippets string
 	for _, ext := range modules {
@@ -130,3 +130,63 @@ func TestRegistryCapabilityAggregation(t *testing.T) {
 		t.Fatalf("GettingStartedSnippets() = %q, want %q", snippets, "hello world\n")
 	}
 }
+
+type lifecycleTestExtension struct {
+	datasourceTestExtension
+	started, stopped int
+}
+
+func (e *lifecycleTestExtension) Start(_ context.Context) error { e.started++; return nil }
+func (e *lifecycleTestExtension) Stop(_ context.Context) error  { e.stopped++; return nil }
+
+func TestRegistryDisableEnable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	reg := NewRegistry(logrus.New())
+
+	ext := &lifecycleTestExtension{
+		datasourceTestExtension: datasourceTestExtension{
+			baseTestExtension: baseTestExtension{name: "upstream"},
+			infos:             []types.DatasourceInfo{{Type: "custom", Name: "demo"}},
+		},
+	}
+	reg.Add(ext)
+	reg.Add(&baseTestExtension{name: "idle"})
+
+	if err := reg.InitModule("upstream", nil); err != nil {
+		t.Fatalf("InitModule() error = %v", err)
+	}
+
+	if err := reg.Disable(ctx, "idle"); err == nil {
+		t.Fatal("Disable() of uninitialized module succeeded, want error")
+	}
+
+	if err := reg.Disable(ctx, "upstream"); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if !reg.IsDisabled("upstream") || ext.stopped != 1 {
+		t.Fatalf("after Disable: disabled=%v stopped=%d, want true and 1", reg.IsDisabled("upstream"), ext.stopped)
+	}
+	if infos := reg.DatasourceInfo(); len(infos) != 0 {
+		t.Fatalf("DatasourceInfo() = %#v, want disabled module hidden", infos)
+	}
+	if err := reg.Disable(ctx, "upstream"); err == nil {
+		t.Fatal("second Disable() succeeded, want error")
+	}
+
+	reg.StopAll(ctx)
+	if ext.stopped != 1 {
+		t.Fatalf("StopAll() stopped disabled module again: stopped=%d", ext.stopped)
+	}
+
+	if err := reg.Enable(ctx, "upstream"); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if reg.IsDisabled("upstream") || ext.started != 1 {
+		t.Fatalf("after Enable: disabled=%v started=%d, want false and 1", reg.IsDisabled("upstream"), ext.started)
+	}
+	if infos := reg.DatasourceInfo(); len(infos) != 1 {
+		t.Fatalf("DatasourceInfo() = %#v, want module restored", infos)
+	}
+}
@@ -42,6 +42,295 @@ var (
 			Help:      "Number of active MCP connections",
 		},
 	)
+
+	// MCPNotificationsTotal counts MCP notifications by result: sent,
+	// coalesced into another pending notification, or deferred because the
+	// client was backpressured.
+	MCPNotificationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "mcp",
+			Name:      "notifications_total",
+			Help:      "Total number of MCP notifications by result",
+		},
+		[]string{"result"},
+	)
+)
+
+// Sandbox execution metrics.
+var (
+	// SandboxExecutionsTotal counts sandbox executions by backend and outcome
+	// (success, nonzero_exit, timeout or error).
+	SandboxExecutionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "executions_total",
+			Help:      "Total number of sandbox executions",
+		},
+		[]string{"backend", "status"},
+	)
+
+	// SandboxExecutionDuration measures sandbox execution duration in seconds.
+	SandboxExecutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "execution_duration_seconds",
+			Help:      "Duration of sandbox executions in seconds",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		},
+		[]string{"backend"},
+	)
+
+	// SandboxExecutionPeakMemory measures the peak memory of executions.
+	SandboxExecutionPeakMemory = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "execution_peak_memory_bytes",
+			Help:      "Peak memory of sandbox executions in bytes",
+			Buckets:   prometheus.ExponentialBuckets(16*1024*1024, 2, 10),
+		},
+		[]string{"backend"},
+	)
+
+	// SandboxExecutionCPUSeconds measures the CPU time of executions.
+	SandboxExecutionCPUSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "execution_cpu_seconds",
+			Help:      "CPU time used by sandbox executions in seconds",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+		[]string{"backend"},
+	)
+
+	// SandboxExecutionIOBytesTotal counts block IO of executions by
+	// direction (read or write).
+	SandboxExecutionIOBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "execution_io_bytes_total",
+			Help:      "Total bytes read and written by sandbox executions",
+		},
+		[]string{"backend", "direction"},
+	)
+
+	// SandboxExecutionNetworkBytesTotal counts network traffic of
+	// executions by direction (rx or tx).
+	SandboxExecutionNetworkBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "execution_network_bytes_total",
+			Help:      "Total network bytes received and sent by sandbox executions",
+		},
+		[]string{"backend", "direction"},
+	)
+
+	// SandboxExecutionsInFlight tracks executions currently running or
+	// waiting for a backend slot.
+	SandboxExecutionsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "executions_in_flight",
+			Help:      "Number of sandbox executions currently in flight",
+		},
+		[]string{"backend"},
+	)
+
+	// SandboxGPUSlotWaiters tracks executions queued for a GPU slot.
+	SandboxGPUSlotWaiters = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "gpu_slot_waiters",
+			Help:      "Number of executions waiting for a GPU slot",
+		},
+	)
+
+	// SandboxErrorHintsTotal counts known error signatures found in execution
+	// output, by hint kind (e.g. clickhouse_wrong_cluster).
+	SandboxErrorHintsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "error_hints_total",
+			Help:      "Total number of error hints added to execution results",
+		},
+		[]string{"kind"},
+	)
+
+	// SandboxImagePullsTotal counts sandbox image pulls by result
+	// (updated, unchanged or error).
+	SandboxImagePullsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "image_pulls_total",
+			Help:      "Total number of sandbox image pulls by result",
+		},
+		[]string{"result"},
+	)
+
+	// SandboxSchedulerQueued tracks executions waiting for a slot.
+	SandboxSchedulerQueued = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "scheduler_queued",
+			Help:      "Number of executions waiting for an execution slot",
+		},
+	)
+
+	// SandboxSchedulerRejectedTotal counts executions rejected because the
+	// scheduler queue was full.
+	SandboxSchedulerRejectedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "scheduler_rejected_total",
+			Help:      "Total number of executions rejected by a full scheduler queue",
+		},
+	)
+
+	// SandboxPoolReady tracks warm pool containers waiting to be claimed.
+	SandboxPoolReady = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "pool_ready",
+			Help:      "Number of warm pool containers ready to be claimed",
+		},
+	)
+
+	// SandboxPoolClaimsTotal counts new sessions by whether a warm pool
+	// container was available (hit or miss).
+	SandboxPoolClaimsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "pool_claims_total",
+			Help:      "Total number of warm pool claims by result",
+		},
+		[]string{"result"},
+	)
+
+	// SandboxPoolRecycledTotal counts warm pool containers replaced after
+	// waiting longer than sandbox.pool.max_idle.
+	SandboxPoolRecycledTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sandbox",
+			Name:      "pool_recycled_total",
+			Help:      "Total number of idle warm pool containers replaced",
+		},
+	)
+)
+
+// Module metrics.
+var (
+	// ModuleUp reports whether each compiled-in module is initialized and
+	// started (1) or inactive (0).
+	ModuleUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "module",
+			Name:      "up",
+			Help:      "Whether the module is initialized and started",
+		},
+		[]string{"module"},
+	)
+)
+
+// Server-to-proxy request metrics. The proxy records its own request metrics
+// under the panda_proxy_ prefix; these measure the same calls as seen from
+// the server, including network time.
+var (
+	// ProxyClientRequestDuration measures server-to-proxy request latency.
+	ProxyClientRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "proxy_client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of server-to-proxy requests in seconds",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"datasource_type", "status_code"},
+	)
+
+	// ProxyClientRateLimitedTotal counts server-to-proxy requests rejected by
+	// the proxy's rate limiter.
+	ProxyClientRateLimitedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "proxy_client",
+			Name:      "rate_limited_total",
+			Help:      "Total number of server-to-proxy requests rejected by rate limiting",
+		},
+		[]string{"datasource_type"},
+	)
+)
+
+// Storage retention metrics. The mode label is "delete" or "dry_run".
+var (
+	// StorageRetentionFilesTotal counts uploads removed by retention sweeps.
+	StorageRetentionFilesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "storage_retention",
+			Name:      "files_total",
+			Help:      "Total number of expired uploads removed (or found, in dry-run mode)",
+		},
+		[]string{"mode"},
+	)
+
+	// StorageRetentionReclaimedBytesTotal counts bytes freed by retention sweeps.
+	StorageRetentionReclaimedBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "storage_retention",
+			Name:      "reclaimed_bytes_total",
+			Help:      "Total bytes of expired uploads removed (or found, in dry-run mode)",
+		},
+		[]string{"mode"},
+	)
+
+	// StorageRetentionSweepErrorsTotal counts failed retention sweeps.
+	StorageRetentionSweepErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "storage_retention",
+			Name:      "sweep_errors_total",
+			Help:      "Total number of failed retention sweeps",
+		},
+	)
+
+	// ScheduledRunsTotal counts scheduled executions by job and outcome.
+	ScheduledRunsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "schedule",
+			Name:      "runs_total",
+			Help:      "Total number of scheduled runs by job and status (success, failure or error)",
+		},
+		[]string{"job", "status"},
+	)
+
+	// WebhookDeliveriesTotal counts webhook deliveries by endpoint, event and result.
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "webhook",
+			Name:      "deliveries_total",
+			Help:      "Total number of webhook deliveries by endpoint, event and result (delivered or failed)",
+		},
+		[]string{"endpoint", "event", "result"},
+	)
 )
 
 func init() {
@@ -50,5 +339,29 @@ func init() {
 		ToolCallsTotal,
 		ToolCallDuration,
 		ActiveConnections,
+		MCPNotificationsTotal,
+		SandboxExecutionsTotal,
+		SandboxExecutionDuration,
+		SandboxExecutionPeakMemory,
+		SandboxExecutionCPUSeconds,
+		SandboxExecutionIOBytesTotal,
+		SandboxExecutionNetworkBytesTotal,
+		SandboxExecutionsInFlight,
+		SandboxGPUSlotWaiters,
+		SandboxErrorHintsTotal,
+		SandboxImagePullsTotal,
+		SandboxSchedulerQueued,
+		SandboxSchedulerRejectedTotal,
+		SandboxPoolReady,
+		SandboxPoolClaimsTotal,
+		SandboxPoolRecycledTotal,
+		ModuleUp,
+		ProxyClientRequestDuration,
+		ProxyClientRateLimitedTotal,
+		StorageRetentionFilesTotal,
+		StorageRetentionReclaimedBytesTotal,
+		StorageRetentionSweepErrorsTotal,
+		ScheduledRunsTotal,
+		WebhookDeliveriesTotal,
 	)
 }
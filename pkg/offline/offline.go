@@ -0,0 +1,73 @@
+// Package offline supports running the server without outbound network
+// access. While online, components save snapshots of the data they fetch;
+// offline, they serve those snapshots instead.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrOffline is returned by operations that need network access while the
+// server runs in offline mode.
+var ErrOffline = errors.New("unavailable in offline mode")
+
+// SaveSnapshot writes v as JSON to path, replacing any previous snapshot
+// atomically.
+func SaveSnapshot(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating snapshot file: %w", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("closing snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("replacing snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot reads the JSON snapshot at path into v.
+func LoadSnapshot(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no snapshot at %s; run the server online once to create it", path)
+		}
+
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package offline
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "snapshot.json")
+
+	require.NoError(t, SaveSnapshot(path, map[string]int{"a": 1}))
+	require.NoError(t, SaveSnapshot(path, map[string]int{"b": 2}))
+
+	var got map[string]int
+	require.NoError(t, LoadSnapshot(path, &got))
+	assert.Equal(t, map[string]int{"b": 2}, got)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestLoadSnapshotMissing(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]int
+	err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"), &got)
+	require.ErrorContains(t, err, "run the server online once")
+}
+
+func TestProxyClient(t *testing.T) {
+	t.Parallel()
+
+	client := NewProxyClient(Discovery{
+		ClickHouse: []types.DatasourceInfo{
+			{Type: "clickhouse", Name: "xatu"},
+			{Type: "clickhouse", Name: "xatu-cbt"},
+		},
+		EthNode:        true,
+		EmbeddingModel: "model",
+	})
+
+	require.NoError(t, client.Start(context.Background()))
+	assert.Equal(t, []string{"xatu", "xatu-cbt"}, client.ClickHouseDatasources())
+	assert.Empty(t, client.PrometheusDatasources())
+	assert.True(t, client.EthNodeAvailable())
+	assert.False(t, client.EmbeddingAvailable(), "embeddings need the proxy")
+	assert.Empty(t, client.URL())
+
+	req, err := http.NewRequest(http.MethodGet, "http://proxy.invalid/", nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, client.SignRequest(req), ErrOffline)
+	require.ErrorIs(t, client.Discover(context.Background()), ErrOffline)
+}
@@ -0,0 +1,141 @@
+package offline
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// DiscoveryFile is the snapshot file name for proxy discovery.
+const DiscoveryFile = "discovery.json"
+
+// Discovery is a snapshot of the datasources a proxy advertised.
+type Discovery struct {
+	ClickHouse     []types.DatasourceInfo `json:"clickhouse,omitempty"`
+	Prometheus     []types.DatasourceInfo `json:"prometheus,omitempty"`
+	Loki           []types.DatasourceInfo `json:"loki,omitempty"`
+	Grafana        []types.DatasourceInfo `json:"grafana,omitempty"`
+	HTTPJSON       []types.DatasourceInfo `json:"httpjson,omitempty"`
+	GitHub         []types.DatasourceInfo `json:"github,omitempty"`
+	EthNode        bool                   `json:"ethnode"`
+	EmbeddingModel string                 `json:"embedding_model,omitempty"`
+}
+
+// CaptureDiscovery snapshots the datasources discovered by a started proxy client.
+func CaptureDiscovery(svc proxy.Service) Discovery {
+	d := Discovery{
+		ClickHouse: svc.ClickHouseDatasourceInfo(),
+		Prometheus: svc.PrometheusDatasourceInfo(),
+		Loki:       svc.LokiDatasourceInfo(),
+		Grafana:    svc.GrafanaDatasourceInfo(),
+		HTTPJSON:   svc.HTTPJSONDatasourceInfo(),
+		GitHub:     svc.GitHubDatasourceInfo(),
+		EthNode:    svc.EthNodeAvailable(),
+	}
+
+	if svc.EmbeddingAvailable() {
+		d.EmbeddingModel = svc.EmbeddingModel()
+	}
+
+	return d
+}
+
+// proxyClient is a proxy.Client that reports datasources from a discovery
+// snapshot and never contacts a proxy.
+type proxyClient struct {
+	discovery Discovery
+}
+
+// Compile-time interface check.
+var _ proxy.Client = (*proxyClient)(nil)
+
+// NewProxyClient returns a proxy client backed by a discovery snapshot.
+// Modules see the snapshotted datasources, but every request that would
+// reach the proxy fails with ErrOffline.
+func NewProxyClient(discovery Discovery) proxy.Client {
+	return &proxyClient{discovery: discovery}
+}
+
+func (c *proxyClient) Start(_ context.Context) error { return nil }
+
+func (c *proxyClient) Stop(_ context.Context) error { return nil }
+
+func (c *proxyClient) URL() string { return "" }
+
+func (c *proxyClient) RegisterToken(_ string) string { return "" }
+
+func (c *proxyClient) RevokeToken(_ string) {}
+
+func (c *proxyClient) SignRequest(_ *http.Request) error { return ErrOffline }
+
+func (c *proxyClient) Transport() http.RoundTripper { return http.DefaultTransport }
+
+func (c *proxyClient) ClickHouseDatasources() []string {
+	return datasourceNames(c.discovery.ClickHouse)
+}
+
+func (c *proxyClient) ClickHouseDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.ClickHouse
+}
+
+func (c *proxyClient) PrometheusDatasources() []string {
+	return datasourceNames(c.discovery.Prometheus)
+}
+
+func (c *proxyClient) PrometheusDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.Prometheus
+}
+
+func (c *proxyClient) LokiDatasources() []string {
+	return datasourceNames(c.discovery.Loki)
+}
+
+func (c *proxyClient) LokiDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.Loki
+}
+
+func (c *proxyClient) GrafanaDatasources() []string {
+	return datasourceNames(c.discovery.Grafana)
+}
+
+func (c *proxyClient) GrafanaDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.Grafana
+}
+
+func (c *proxyClient) HTTPJSONDatasources() []string {
+	return datasourceNames(c.discovery.HTTPJSON)
+}
+
+func (c *proxyClient) HTTPJSONDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.HTTPJSON
+}
+
+func (c *proxyClient) GitHubDatasources() []string {
+	return datasourceNames(c.discovery.GitHub)
+}
+
+func (c *proxyClient) GitHubDatasourceInfo() []types.DatasourceInfo {
+	return c.discovery.GitHub
+}
+
+func (c *proxyClient) EthNodeAvailable() bool { return c.discovery.EthNode }
+
+// EmbeddingAvailable is always false: embeddings require the proxy.
+func (c *proxyClient) EmbeddingAvailable() bool { return false }
+
+func (c *proxyClient) EmbeddingModel() string { return c.discovery.EmbeddingModel }
+
+func (c *proxyClient) Discover(_ context.Context) error { return ErrOffline }
+
+func (c *proxyClient) EnsureAuthenticated(_ context.Context) error { return nil }
+
+func datasourceNames(infos []types.DatasourceInfo) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,89 @@
+// Package prompt provides MCP prompt registration and the canned
+// investigation workflows served as prompts.
+package prompt
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// Handler renders a prompt from its arguments.
+type Handler func(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error)
+
+// Definition describes a prompt's metadata and handler.
+type Definition struct {
+	Prompt  mcp.Prompt
+	Handler Handler
+}
+
+// Registry manages prompt registration and lookup.
+type Registry interface {
+	// Register adds a prompt definition to the registry.
+	Register(def Definition)
+	// Get retrieves a prompt handler by name.
+	// Returns the handler and a boolean indicating if the prompt exists.
+	Get(name string) (Handler, bool)
+	// Definitions returns all registered prompt definitions sorted by name.
+	Definitions() []Definition
+}
+
+type registry struct {
+	log     logrus.FieldLogger
+	mu      sync.RWMutex
+	prompts map[string]Definition
+}
+
+// NewRegistry creates a new prompt registry.
+func NewRegistry(log logrus.FieldLogger) Registry {
+	return &registry{
+		log:     log.WithField("component", "prompt-registry"),
+		prompts: make(map[string]Definition, 8),
+	}
+}
+
+// Register adds a prompt definition to the registry.
+func (r *registry) Register(def Definition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.prompts[def.Prompt.Name]; exists {
+		r.log.WithField("prompt", def.Prompt.Name).Warn("Overwriting existing prompt definition")
+	}
+
+	r.prompts[def.Prompt.Name] = def
+	r.log.WithField("prompt", def.Prompt.Name).Debug("Registered prompt")
+}
+
+// Get retrieves a prompt handler by name.
+func (r *registry) Get(name string) (Handler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, exists := r.prompts[name]
+	if !exists {
+		return nil, false
+	}
+
+	return def.Handler, true
+}
+
+// Definitions returns all registered prompt definitions sorted by name.
+func (r *registry) Definitions() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]Definition, 0, len(r.prompts))
+	for _, def := range r.prompts {
+		defs = append(defs, def)
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Prompt.Name < defs[j].Prompt.Name
+	})
+
+	return defs
+}
@@ -0,0 +1,155 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/ethpandaops/panda/pkg/searchsvc"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// workflowExampleLimit is how many example queries a workflow prompt includes.
+const workflowExampleLimit = 3
+
+// RunbookProvider looks up runbooks by name.
+type RunbookProvider interface {
+	Get(name string) *types.Runbook
+}
+
+// ExampleSearcher finds the module query examples that suit a workflow.
+type ExampleSearcher interface {
+	SearchExamples(query, categoryFilter string, limit int) (*searchsvc.SearchExamplesResponse, error)
+}
+
+type workflowArg struct {
+	name        string
+	description string
+}
+
+// workflow is a canned investigation. Its prompt is the task, followed by
+// the runbook to follow and the example queries that best match it.
+type workflow struct {
+	name        string
+	description string
+	args        []workflowArg
+	// task opens the prompt; {arg} placeholders take the argument values.
+	task string
+	// runbook names the runbook to include, if any.
+	runbook string
+	// exampleQuery searches for the example queries to include.
+	exampleQuery string
+}
+
+var networkArg = workflowArg{
+	name:        "network",
+	description: "Network name, e.g. mainnet, sepolia or a devnet name",
+}
+
+var workflows = []workflow{
+	{
+		name:         "investigate_finality",
+		description:  "Investigate why a network is not finalizing",
+		args:         []workflowArg{networkArg},
+		task:         "Investigate finality on {network}. Establish how long finality has been delayed, then find the cause by following the runbook below. Run queries with execute_python and report the cause, the evidence for it, and which clients or operators are affected.",
+		runbook:      "Investigate Finality Delay",
+		exampleQuery: "finality checkpoints epoch attestation participation",
+	},
+	{
+		name:        "compare_block_arrival",
+		description: "Compare block arrival times between two networks",
+		args: []workflowArg{
+			{name: "network_a", description: "First network name, e.g. mainnet"},
+			{name: "network_b", description: "Second network name, e.g. holesky"},
+		},
+		task:         "Compare block arrival between {network_a} and {network_b}. Run the same queries against both networks over the same time window with execute_python. Report median and p95 arrival time into the slot for each network, broken down by consensus client, and call out any differences that stand out.",
+		exampleQuery: "block arrival propagation timing by client",
+	},
+	{
+		name:         "debug_devnet",
+		description:  "Collect the state of a devnet and debug its issues",
+		args:         []workflowArg{networkArg},
+		task:         "Debug the {network} devnet. Follow the runbook below to check node health, forks, finality and client errors. Report what is broken, which clients are affected and the evidence for each finding.",
+		runbook:      "Debug Devnet",
+		exampleQuery: "devnet node health client errors logs",
+	},
+	{
+		name:         "investigate_blob_propagation",
+		description:  "Check whether blob propagation timing affects engine_getBlobs success",
+		args:         []workflowArg{networkArg},
+		task:         "Investigate blob propagation on {network}. Follow the runbook below to relate blob gossip timing to engine_getBlobs success rates, and report whether slow propagation explains the failures.",
+		runbook:      "Blob Propagation vs engine_getBlobs Success",
+		exampleQuery: "blob sidecar propagation engine_getBlobs",
+	},
+}
+
+// RegisterWorkflows registers a prompt for each canned investigation
+// workflow. runbooks and examples may be nil, in which case prompts leave
+// out the runbook or examples.
+func RegisterWorkflows(reg Registry, runbooks RunbookProvider, examples ExampleSearcher) {
+	for _, w := range workflows {
+		reg.Register(w.definition(runbooks, examples))
+	}
+}
+
+func (w workflow) definition(runbooks RunbookProvider, examples ExampleSearcher) Definition {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(w.description)}
+	for _, arg := range w.args {
+		opts = append(opts, mcp.WithArgument(arg.name, mcp.ArgumentDescription(arg.description), mcp.RequiredArgument()))
+	}
+
+	return Definition{
+		Prompt: mcp.NewPrompt(w.name, opts...),
+		Handler: func(_ context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
+			text, err := w.render(args, runbooks, examples)
+			if err != nil {
+				return nil, err
+			}
+
+			return mcp.NewGetPromptResult(w.description, []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			}), nil
+		},
+	}
+}
+
+// render assembles the prompt text for args.
+func (w workflow) render(args map[string]string, runbooks RunbookProvider, examples ExampleSearcher) (string, error) {
+	replacements := make([]string, 0, 2*len(w.args))
+
+	for _, arg := range w.args {
+		value := strings.TrimSpace(args[arg.name])
+		if value == "" {
+			return "", fmt.Errorf("argument %q is required", arg.name)
+		}
+
+		replacements = append(replacements, "{"+arg.name+"}", value)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(strings.NewReplacer(replacements...).Replace(w.task))
+	b.WriteString("\n")
+
+	if runbooks != nil && w.runbook != "" {
+		if rb := runbooks.Get(w.runbook); rb != nil {
+			fmt.Fprintf(&b, "\n## Runbook: %s\n\n%s\n", rb.Name, rb.Content)
+		}
+	}
+
+	if examples != nil && w.exampleQuery != "" {
+		response, err := examples.SearchExamples(w.exampleQuery, "", workflowExampleLimit)
+		if err == nil && len(response.Results) > 0 {
+			b.WriteString("\n## Example queries\n")
+
+			for _, example := range response.Results {
+				fmt.Fprintf(&b, "\n### %s (%s)\n\n%s\n\n```\n%s\n```\n",
+					example.ExampleName, example.CategoryName, example.Description, strings.TrimSpace(example.Query))
+			}
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,95 @@
+package prompt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/searchsvc"
+	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/runbooks"
+)
+
+type fakeRunbooks map[string]*types.Runbook
+
+func (f fakeRunbooks) Get(name string) *types.Runbook {
+	return f[name]
+}
+
+type fakeExamples struct {
+	query string
+}
+
+func (f *fakeExamples) SearchExamples(query, _ string, _ int) (*searchsvc.SearchExamplesResponse, error) {
+	f.query = query
+
+	return &searchsvc.SearchExamplesResponse{
+		Results: []*searchsvc.SearchExampleResult{{
+			CategoryName: "Finality",
+			ExampleName:  "Finalized checkpoints",
+			Description:  "Latest finalized epoch per node",
+			Query:        "SELECT epoch FROM finality\n",
+		}},
+	}, nil
+}
+
+func TestRegisterWorkflows(t *testing.T) {
+	reg := NewRegistry(logrus.New())
+	examples := &fakeExamples{}
+	provider := fakeRunbooks{
+		"Investigate Finality Delay": {Name: "Investigate Finality Delay", Content: "1. Check participation."},
+	}
+
+	RegisterWorkflows(reg, provider, examples)
+
+	defs := reg.Definitions()
+	require.Len(t, defs, len(workflows))
+
+	handler, ok := reg.Get("investigate_finality")
+	require.True(t, ok)
+
+	result, err := handler(context.Background(), map[string]string{"network": "hoodi"})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+
+	text := result.Messages[0].Content.(mcp.TextContent).Text
+	assert.Contains(t, text, "Investigate finality on hoodi.")
+	assert.Contains(t, text, "## Runbook: Investigate Finality Delay\n\n1. Check participation.")
+	assert.Contains(t, text, "### Finalized checkpoints (Finality)")
+	assert.Contains(t, text, "```\nSELECT epoch FROM finality\n```")
+	assert.NotContains(t, examples.query, "hoodi")
+
+	_, err = handler(context.Background(), map[string]string{"network": " "})
+	require.Error(t, err)
+}
+
+func TestWorkflowWithoutSources(t *testing.T) {
+	reg := NewRegistry(logrus.New())
+	RegisterWorkflows(reg, nil, nil)
+
+	handler, ok := reg.Get("compare_block_arrival")
+	require.True(t, ok)
+
+	result, err := handler(context.Background(), map[string]string{"network_a": "mainnet", "network_b": "holesky"})
+	require.NoError(t, err)
+
+	text := result.Messages[0].Content.(mcp.TextContent).Text
+	assert.Contains(t, text, "between mainnet and holesky")
+	assert.NotContains(t, text, "## Example queries")
+}
+
+func TestWorkflowRunbooksExist(t *testing.T) {
+	reg, err := runbooks.NewRegistry(logrus.New())
+	require.NoError(t, err)
+
+	for _, w := range workflows {
+		if w.runbook != "" {
+			assert.NotNil(t, reg.Get(w.runbook), "workflow %s references unknown runbook %q", w.name, w.runbook)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Package audit exports proxy audit entries to durable sinks (rotating JSONL
+// files, S3 batches and Loki) so audit trails can be retained and queried
+// outside the process log.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// SchemaVersion is the version of the Entry schema. It is bumped whenever a
+// field is renamed or removed; added fields keep the version.
+const SchemaVersion = 1
+
+// Entry is a single audited proxy request.
+type Entry struct {
+	SchemaVersion  int       `json:"schema_version"`
+	Time           time.Time `json:"time"`
+	Method         string    `json:"method"`
+	Path           string    `json:"path"`
+	RemoteAddr     string    `json:"remote_addr"`
+	DatasourceType string    `json:"datasource_type,omitempty"`
+	DatasourceName string    `json:"datasource_name,omitempty"`
+	Status         int       `json:"status"`
+	RequestBytes   int64     `json:"request_bytes"`
+	ResponseBytes  int64     `json:"response_bytes"`
+	DurationMS     int64     `json:"duration_ms"`
+	Subject        string    `json:"subject,omitempty"`
+	Username       string    `json:"username,omitempty"`
+	Groups         []string  `json:"groups,omitempty"`
+	QueryString    string    `json:"query_string,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	// OnBehalfOf and ExecutionID are reported by the MCP server for
+	// requests it makes for a user's execution.
+	OnBehalfOf  string `json:"on_behalf_of,omitempty"`
+	ExecutionID string `json:"execution_id,omitempty"`
+}
+
+// Sink receives batches of audit entries.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Write persists a batch. A failed batch is retried on the next flush.
+	Write(ctx context.Context, entries []Entry) error
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// SinksConfig selects the sinks entries are exported to. Any combination
+// may be enabled.
+type SinksConfig struct {
+	File *FileConfig `yaml:"file,omitempty"`
+	S3   *S3Config   `yaml:"s3,omitempty"`
+	Loki *LokiConfig `yaml:"loki,omitempty"`
+}
+
+// Validate checks every configured sink.
+func (c SinksConfig) Validate() error {
+	if c.File != nil {
+		if err := c.File.Validate(); err != nil {
+			return fmt.Errorf("file: %w", err)
+		}
+	}
+
+	if c.S3 != nil {
+		if err := c.S3.Validate(); err != nil {
+			return fmt.Errorf("s3: %w", err)
+		}
+	}
+
+	if c.Loki != nil {
+		if err := c.Loki.Validate(); err != nil {
+			return fmt.Errorf("loki: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Build creates the configured sinks.
+func (c SinksConfig) Build() ([]Sink, error) {
+	var sinks []Sink
+
+	if c.File != nil {
+		sink, err := NewFileSink(*c.File)
+		if err != nil {
+			return nil, fmt.Errorf("file: %w", err)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if c.S3 != nil {
+		sinks = append(sinks, NewS3Sink(*c.S3))
+	}
+
+	if c.Loki != nil {
+		sinks = append(sinks, NewLokiSink(*c.Loki))
+	}
+
+	return sinks, nil
+}
+
+// DispatcherConfig configures buffering of entries before they reach sinks.
+type DispatcherConfig struct {
+	// BufferSize bounds the entries held per sink while waiting to be
+	// written. The oldest entries are dropped when it is exceeded.
+	BufferSize int
+	// BatchSize flushes early once this many entries are queued.
+	BatchSize int
+	// FlushInterval is the longest an entry waits before being written.
+	FlushInterval time.Duration
+}
+
+// Dispatcher buffers entries and writes them to every sink in batches.
+// Record never blocks the request path; entries that do not fit the buffer
+// are dropped and counted.
+type Dispatcher struct {
+	log   logrus.FieldLogger
+	cfg   DispatcherConfig
+	sinks []Sink
+
+	entries chan Entry
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	// pending holds each sink's entries that are not yet written.
+	pending [][]Entry
+}
+
+// NewDispatcher creates a dispatcher for sinks.
+func NewDispatcher(log logrus.FieldLogger, cfg DispatcherConfig, sinks []Sink) *Dispatcher {
+	return &Dispatcher{
+		log:     log.WithField("component", "audit_dispatcher"),
+		cfg:     cfg,
+		sinks:   sinks,
+		entries: make(chan Entry, cfg.BufferSize),
+		done:    make(chan struct{}),
+		pending: make([][]Entry, len(sinks)),
+	}
+}
+
+// Record queues an entry for export.
+func (d *Dispatcher) Record(entry Entry) {
+	select {
+	case d.entries <- entry:
+	default:
+		DroppedTotal.WithLabelValues("buffer_full").Inc()
+	}
+}
+
+// Start runs the background flush loop.
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(d.cfg.FlushInterval)
+		defer ticker.Stop()
+
+		queued := 0
+
+		for {
+			select {
+			case <-d.done:
+				return
+			case entry := <-d.entries:
+				d.enqueue(entry)
+
+				if queued++; queued >= d.cfg.BatchSize {
+					d.flush(context.Background())
+
+					queued = 0
+				}
+			case <-ticker.C:
+				d.flush(context.Background())
+
+				queued = 0
+			}
+		}
+	}()
+
+	names := make([]string, 0, len(d.sinks))
+	for _, sink := range d.sinks {
+		names = append(names, sink.Name())
+	}
+
+	d.log.WithField("sinks", names).Info("Audit export started")
+}
+
+// Stop drains queued entries, flushes them to every sink and closes the
+// sinks. Entries a sink still fails to accept are reported in the error.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	close(d.done)
+	d.wg.Wait()
+
+	for {
+		select {
+		case entry := <-d.entries:
+			d.enqueue(entry)
+
+			continue
+		default:
+		}
+
+		break
+	}
+
+	d.flush(ctx)
+
+	var errs []error
+
+	for i, sink := range d.sinks {
+		if n := len(d.pending[i]); n > 0 {
+			DroppedTotal.WithLabelValues("shutdown").Add(float64(n))
+			errs = append(errs, fmt.Errorf("%s: %d entries not written", sink.Name(), n))
+		}
+
+		if err := sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", sink.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// enqueue appends entry to every sink's pending batch, dropping the oldest
+// entries of sinks that have fallen behind by more than the buffer size.
+func (d *Dispatcher) enqueue(entry Entry) {
+	for i := range d.sinks {
+		d.pending[i] = append(d.pending[i], entry)
+
+		if over := len(d.pending[i]) - d.cfg.BufferSize; over > 0 {
+			d.pending[i] = d.pending[i][over:]
+			DroppedTotal.WithLabelValues("sink_backlog").Add(float64(over))
+		}
+	}
+}
+
+// flush writes each sink's pending entries, keeping them for the next flush
+// when the write fails.
+func (d *Dispatcher) flush(ctx context.Context) {
+	for i, sink := range d.sinks {
+		if len(d.pending[i]) == 0 {
+			continue
+		}
+
+		if err := sink.Write(ctx, d.pending[i]); err != nil {
+			SinkErrorsTotal.WithLabelValues(sink.Name()).Inc()
+			d.log.WithError(err).WithFields(logrus.Fields{
+				"sink":    sink.Name(),
+				"pending": len(d.pending[i]),
+			}).Warn("Failed to write audit entries")
+
+			continue
+		}
+
+		EntriesWrittenTotal.WithLabelValues(sink.Name()).Add(float64(len(d.pending[i])))
+		d.pending[i] = nil
+	}
+}
+
+// Audit export metrics.
+var (
+	// EntriesWrittenTotal counts audit entries written, by sink.
+	EntriesWrittenTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy_audit",
+			Name:      "entries_written_total",
+			Help:      "Total number of audit entries written to sinks",
+		},
+		[]string{"sink"},
+	)
+
+	// SinkErrorsTotal counts failed batch writes, by sink.
+	SinkErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy_audit",
+			Name:      "sink_errors_total",
+			Help:      "Total number of failed audit batch writes",
+		},
+		[]string{"sink"},
+	)
+
+	// DroppedTotal counts audit entries dropped before reaching a sink, by
+	// reason (buffer_full, sink_backlog or shutdown).
+	DroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy_audit",
+			Name:      "entries_dropped_total",
+			Help:      "Total number of audit entries dropped before export",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(EntriesWrittenTotal, SinkErrorsTotal, DroppedTotal)
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 5
+)
+
+// FileConfig configures a rotating JSONL file sink.
+type FileConfig struct {
+	// Path is the active audit file. Rotated files are Path.1, Path.2, ...
+	Path string `yaml:"path"`
+	// MaxSizeMB rotates the file once it exceeds this size (default: 100).
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+	// MaxBackups is the number of rotated files kept (default: 5).
+	MaxBackups int `yaml:"max_backups,omitempty"`
+}
+
+// ApplyDefaults fills in unset fields.
+func (c *FileConfig) ApplyDefaults() {
+	if c.MaxSizeMB == 0 {
+		c.MaxSizeMB = defaultFileMaxSizeMB
+	}
+
+	if c.MaxBackups == 0 {
+		c.MaxBackups = defaultFileMaxBackups
+	}
+}
+
+// Validate checks the file sink configuration.
+func (c FileConfig) Validate() error {
+	if c.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+
+	if c.MaxSizeMB < 0 {
+		return fmt.Errorf("max_size_mb must not be negative")
+	}
+
+	if c.MaxBackups < 0 {
+		return fmt.Errorf("max_backups must not be negative")
+	}
+
+	return nil
+}
+
+// FileSink appends entries as JSON lines to a size-rotated file.
+type FileSink struct {
+	cfg FileConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens (or creates) the audit file.
+func NewFileSink(cfg FileConfig) (*FileSink, error) {
+	cfg.ApplyDefaults()
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating audit directory: %w", err)
+	}
+
+	s := &FileSink{cfg: cfg}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Name implements Sink.
+func (s *FileSink) Name() string { return "file" }
+
+// Write implements Sink. The batch is written with a single write so a
+// rotation never splits it.
+func (s *FileSink) Write(_ context.Context, entries []Entry) error {
+	data, err := encodeJSONL(entries)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size > 0 && s.size+int64(len(data)) > int64(s.cfg.MaxSizeMB)*1024*1024 {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("rotating audit file: %w", err)
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+
+	if err != nil {
+		return fmt.Errorf("writing audit file: %w", err)
+	}
+
+	return nil
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("opening audit file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("stat audit file: %w", err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts Path.N-1 to Path.N, dropping the oldest, moves the active
+// file to Path.1 and reopens Path.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	for i := s.cfg.MaxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", s.cfg.Path, i)
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", s.cfg.Path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(s.cfg.Path, s.cfg.Path+".1"); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// encodeJSONL encodes entries as newline-delimited JSON.
+func encodeJSONL(entries []Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return nil, fmt.Errorf("encoding audit entry: %w", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LokiConfig configures a sink that pushes entries to Grafana Loki.
+type LokiConfig struct {
+	// URL is the Loki base URL; entries are pushed to <url>/loki/api/v1/push.
+	URL string `yaml:"url"`
+	// TenantID sets the X-Scope-OrgID header for multi-tenant Loki.
+	TenantID string `yaml:"tenant_id,omitempty"`
+	// Username and Password enable basic authentication.
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Labels are added to the stream. "job" defaults to "panda-proxy-audit".
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// Validate checks the Loki sink configuration.
+func (c LokiConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	if u, err := url.Parse(c.URL); err != nil || u.Host == "" {
+		return fmt.Errorf("invalid url %q", c.URL)
+	}
+
+	return nil
+}
+
+// LokiSink pushes each batch as one Loki stream. Lines are the JSON-encoded
+// entries, so they can be parsed with LogQL's json stage.
+type LokiSink struct {
+	cfg        LokiConfig
+	labels     map[string]string
+	httpClient *http.Client
+}
+
+// NewLokiSink creates a Loki sink.
+func NewLokiSink(cfg LokiConfig) *LokiSink {
+	labels := map[string]string{"job": "panda-proxy-audit"}
+	for k, v := range cfg.Labels {
+		labels[k] = v
+	}
+
+	return &LokiSink{
+		cfg:        cfg,
+		labels:     labels,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name implements Sink.
+func (s *LokiSink) Name() string { return "loki" }
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Write implements Sink.
+func (s *LokiSink) Write(ctx context.Context, entries []Entry) error {
+	stream := lokiStream{Stream: s.labels, Values: make([][2]string, 0, len(entries))}
+
+	for i := range entries {
+		line, err := json.Marshal(&entries[i])
+		if err != nil {
+			return fmt.Errorf("encoding audit entry: %w", err)
+		}
+
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(entries[i].Time.UnixNano(), 10),
+			string(line),
+		})
+	}
+
+	body, err := json.Marshal(lokiPush{Streams: []lokiStream{stream}})
+	if err != nil {
+		return fmt.Errorf("encoding push request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(s.cfg.URL, "/")+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if s.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+	}
+
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pushing to loki: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("pushing to loki: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// Close implements Sink.
+func (s *LokiSink) Close() error { return nil }
@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSearchLimit is the number of entries a search returns when no
+	// limit is given.
+	DefaultSearchLimit = 100
+
+	// MaxSearchLimit caps the entries a single search returns.
+	MaxSearchLimit = 1000
+)
+
+// Query filters audit entries. Zero fields match everything.
+type Query struct {
+	// User matches the username or subject exactly.
+	User string
+	// Datasource matches the datasource name or type exactly.
+	Datasource string
+	// Since and Until bound the entry time (inclusive).
+	Since time.Time
+	Until time.Time
+	// Status matches an exact HTTP status ("403") or a class ("4xx").
+	Status string
+	// Limit caps the number of entries returned, newest first.
+	Limit int
+}
+
+// ParseQuery reads a query from URL parameters: user, datasource, since,
+// until, status and limit. since and until accept RFC 3339 times or a
+// duration before now, such as "1h".
+func ParseQuery(values url.Values, now time.Time) (Query, error) {
+	q := Query{
+		User:       strings.TrimSpace(values.Get("user")),
+		Datasource: strings.TrimSpace(values.Get("datasource")),
+		Status:     strings.ToLower(strings.TrimSpace(values.Get("status"))),
+		Limit:      DefaultSearchLimit,
+	}
+
+	var err error
+
+	if q.Since, err = parseTime(values.Get("since"), now); err != nil {
+		return Query{}, fmt.Errorf("invalid since: %w", err)
+	}
+
+	if q.Until, err = parseTime(values.Get("until"), now); err != nil {
+		return Query{}, fmt.Errorf("invalid until: %w", err)
+	}
+
+	if q.Status != "" && !validStatus(q.Status) {
+		return Query{}, fmt.Errorf("invalid status %q: use a code such as 403 or a class such as 4xx", q.Status)
+	}
+
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil || q.Limit <= 0 {
+			return Query{}, fmt.Errorf("invalid limit %q", raw)
+		}
+	}
+
+	q.Limit = min(q.Limit, MaxSearchLimit)
+
+	return q, nil
+}
+
+// Values encodes the query as URL parameters understood by ParseQuery.
+func (q Query) Values() url.Values {
+	values := url.Values{}
+
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	set("user", q.User)
+	set("datasource", q.Datasource)
+	set("status", q.Status)
+
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.UTC().Format(time.RFC3339))
+	}
+
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.UTC().Format(time.RFC3339))
+	}
+
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	return values
+}
+
+// Matches reports whether entry satisfies every filter.
+func (q Query) Matches(entry Entry) bool {
+	if q.User != "" && entry.Username != q.User && entry.Subject != q.User {
+		return false
+	}
+
+	if q.Datasource != "" && entry.DatasourceName != q.Datasource && entry.DatasourceType != q.Datasource {
+		return false
+	}
+
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+
+	if !q.Until.IsZero() && entry.Time.After(q.Until) {
+		return false
+	}
+
+	if q.Status != "" {
+		code := strconv.Itoa(entry.Status)
+		if strings.HasSuffix(q.Status, "xx") {
+			return strings.HasPrefix(code, q.Status[:1])
+		}
+
+		return code == q.Status
+	}
+
+	return true
+}
+
+// SearchResponse is returned by the proxy's /audit endpoint.
+type SearchResponse struct {
+	// Entries are the matching entries, newest first.
+	Entries []Entry `json:"entries"`
+	// Retained is the number of entries held in memory and searched.
+	Retained int `json:"retained"`
+}
+
+// Recent keeps the most recent audit entries in memory for search.
+type Recent struct {
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRecent creates a buffer of the last size entries.
+func NewRecent(size int) *Recent {
+	return &Recent{entries: make([]Entry, size)}
+}
+
+// Add records an entry, evicting the oldest once the buffer is full.
+func (r *Recent) Add(entry Entry) {
+	if len(r.entries) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Search returns up to q.Limit matching entries, newest first.
+func (r *Recent) Search(q Query) SearchResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	retained := r.next
+	if r.full {
+		retained = len(r.entries)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	resp := SearchResponse{Entries: make([]Entry, 0, min(limit, retained)), Retained: retained}
+
+	for i := 1; i <= retained && len(resp.Entries) < limit; i++ {
+		entry := r.entries[(r.next-i+len(r.entries))%len(r.entries)]
+		if q.Matches(entry) {
+			resp.Entries = append(resp.Entries, entry)
+		}
+	}
+
+	return resp
+}
+
+func parseTime(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(raw); err == nil {
+		return now.Add(-d), nil
+	}
+
+	return time.Parse(time.RFC3339, raw)
+}
+
+func validStatus(status string) bool {
+	if len(status) != 3 {
+		return false
+	}
+
+	if strings.HasSuffix(status, "xx") {
+		return status[0] >= '1' && status[0] <= '5'
+	}
+
+	code, err := strconv.Atoi(status)
+
+	return err == nil && code >= 100 && code <= 599
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentSearch(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := NewRecent(3)
+
+	for i, entry := range []Entry{
+		{Username: "alice", DatasourceType: "clickhouse", DatasourceName: "xatu", Status: 200},
+		{Username: "bob", DatasourceType: "prometheus", DatasourceName: "ops", Status: 403},
+		{Subject: "alice-sub", DatasourceType: "clickhouse", DatasourceName: "xatu", Status: 500},
+		{Username: "alice", DatasourceType: "loki", Status: 429},
+	} {
+		entry.Time = base.Add(time.Duration(i) * time.Minute)
+		recent.Add(entry)
+	}
+
+	all := recent.Search(Query{})
+	require.Len(t, all.Entries, 3, "oldest entry is evicted")
+	assert.Equal(t, 3, all.Retained)
+	assert.Equal(t, "loki", all.Entries[0].DatasourceType, "newest first")
+
+	assert.Len(t, recent.Search(Query{User: "alice"}).Entries, 1)
+	assert.Len(t, recent.Search(Query{User: "alice-sub"}).Entries, 1)
+	assert.Len(t, recent.Search(Query{Datasource: "xatu"}).Entries, 1)
+	assert.Len(t, recent.Search(Query{Datasource: "prometheus"}).Entries, 1)
+	assert.Len(t, recent.Search(Query{Status: "4xx"}).Entries, 2)
+	assert.Len(t, recent.Search(Query{Status: "500"}).Entries, 1)
+	assert.Len(t, recent.Search(Query{Since: base.Add(2 * time.Minute)}).Entries, 2)
+	assert.Len(t, recent.Search(Query{Until: base.Add(2 * time.Minute)}).Entries, 2)
+	assert.Len(t, recent.Search(Query{Limit: 1}).Entries, 1)
+}
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	q, err := ParseQuery(url.Values{
+		"user":   {"alice"},
+		"since":  {"1h"},
+		"until":  {"2026-03-01T11:30:00Z"},
+		"status": {"5XX"},
+		"limit":  {"5000"},
+	}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice", q.User)
+	assert.Equal(t, now.Add(-time.Hour), q.Since)
+	assert.Equal(t, now.Add(-30*time.Minute), q.Until)
+	assert.Equal(t, "5xx", q.Status)
+	assert.Equal(t, MaxSearchLimit, q.Limit)
+
+	roundTrip, err := ParseQuery(q.Values(), now)
+	require.NoError(t, err)
+	assert.Equal(t, q, roundTrip)
+
+	defaults, err := ParseQuery(url.Values{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSearchLimit, defaults.Limit)
+
+	for _, bad := range []url.Values{
+		{"since": {"yesterday"}},
+		{"status": {"6xx"}},
+		{"status": {"abc"}},
+		{"limit": {"-1"}},
+	} {
+		_, err := ParseQuery(bad, now)
+		assert.Error(t, err, bad.Encode())
+	}
+}
@@ -0,0 +1,264 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultS3Prefix = "audit/"
+
+// S3Config configures a sink that uploads each batch as a gzipped JSONL
+// object to an S3-compatible bucket.
+type S3Config struct {
+	// Endpoint is the S3 API URL (default: https://s3.<region>.amazonaws.com).
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// Region is the bucket region used for request signing.
+	Region string `yaml:"region"`
+	// Bucket is the destination bucket.
+	Bucket string `yaml:"bucket"`
+	// Prefix is prepended to object keys (default: "audit/").
+	Prefix string `yaml:"prefix,omitempty"`
+	// AccessKeyID and SecretAccessKey are the static credentials.
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	// PathStyle addresses the bucket as <endpoint>/<bucket> instead of
+	// <bucket>.<endpoint>, as most self-hosted S3 implementations require.
+	PathStyle bool `yaml:"path_style,omitempty"`
+}
+
+// ApplyDefaults fills in unset fields.
+func (c *S3Config) ApplyDefaults() {
+	if c.Prefix == "" {
+		c.Prefix = defaultS3Prefix
+	}
+
+	if c.Endpoint == "" && c.Region != "" {
+		c.Endpoint = "https://s3." + c.Region + ".amazonaws.com"
+	}
+}
+
+// Validate checks the S3 sink configuration.
+func (c S3Config) Validate() error {
+	if c.Bucket == "" {
+		return fmt.Errorf("bucket is required")
+	}
+
+	if c.Region == "" {
+		return fmt.Errorf("region is required")
+	}
+
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return fmt.Errorf("access_key_id and secret_access_key are required")
+	}
+
+	if c.Endpoint != "" {
+		if u, err := url.Parse(c.Endpoint); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q", c.Endpoint)
+		}
+	}
+
+	return nil
+}
+
+// S3Sink uploads batches to S3. Objects are keyed by date so they can be
+// queried as a partitioned dataset:
+// <prefix>dt=YYYY-MM-DD/<timestamp>-<random>.jsonl.gz.
+type S3Sink struct {
+	cfg        S3Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Sink creates an S3 sink.
+func NewS3Sink(cfg S3Config) *S3Sink {
+	cfg.ApplyDefaults()
+
+	return &S3Sink{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// Name implements Sink.
+func (s *S3Sink) Name() string { return "s3" }
+
+// Write implements Sink.
+func (s *S3Sink) Write(ctx context.Context, entries []Entry) error {
+	data, err := encodeJSONL(entries)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+
+	gz := gzip.NewWriter(&body)
+	if _, err := gz.Write(data); err != nil {
+		return fmt.Errorf("compressing batch: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compressing batch: %w", err)
+	}
+
+	now := s.now().UTC()
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("generating object key: %w", err)
+	}
+
+	key := fmt.Sprintf("%sdt=%s/%s-%s.jsonl.gz",
+		s.cfg.Prefix, now.Format("2006-01-02"), now.Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Content-Encoding", "gzip")
+	signV4(req, body.Bytes(), s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, "s3", now)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", key, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("uploading %s: status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// Close implements Sink.
+func (s *S3Sink) Close() error { return nil }
+
+func (s *S3Sink) objectURL(key string) string {
+	u, _ := url.Parse(s.cfg.Endpoint)
+
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+
+	return u.String()
+}
+
+// signV4 signs req with AWS Signature Version 4, covering every header set
+// on the request plus host, x-amz-date and x-amz-content-sha256.
+func signV4(req *http.Request, body []byte, accessKey, secretKey, region, service string, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature,
+	))
+}
+
+// canonicalURI encodes each path segment, leaving the separators intact.
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(values url.Values) string {
+	pairs := make([]string, 0, len(values))
+
+	for key, vals := range values {
+		for _, v := range vals {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(v))
+		}
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything except unreserved characters, as
+// SigV4 requires.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
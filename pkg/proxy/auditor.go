@@ -1,27 +1,57 @@
 package proxy
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
 	"github.com/ethpandaops/panda/pkg/proxy/handlers"
 )
 
-// Auditor logs audit entries for proxy requests.
+// Auditor logs audit entries for proxy requests, keeps the most recent in
+// memory for search and, when sinks are configured, exports them through an
+// audit.Dispatcher.
 type Auditor struct {
-	log logrus.FieldLogger
+	log        logrus.FieldLogger
+	dispatcher *audit.Dispatcher
+	recent     *audit.Recent
 }
 
-// NewAuditor creates a new auditor.
-func NewAuditor(log logrus.FieldLogger) *Auditor {
+// NewAuditor creates a new auditor. dispatcher may be nil, in which case
+// entries only go to the process log and the recent buffer.
+func NewAuditor(log logrus.FieldLogger, dispatcher *audit.Dispatcher, recentSize int) *Auditor {
 	return &Auditor{
-		log: log.WithField("component", "auditor"),
+		log:        log.WithField("component", "auditor"),
+		dispatcher: dispatcher,
+		recent:     audit.NewRecent(recentSize),
 	}
 }
 
+// Search returns recent entries matching q, newest first.
+func (a *Auditor) Search(q audit.Query) audit.SearchResponse {
+	return a.recent.Search(q)
+}
+
+// Start starts exporting entries to the configured sinks.
+func (a *Auditor) Start() {
+	if a.dispatcher != nil {
+		a.dispatcher.Start()
+	}
+}
+
+// Stop flushes buffered entries to the sinks and closes them.
+func (a *Auditor) Stop(ctx context.Context) error {
+	if a.dispatcher == nil {
+		return nil
+	}
+
+	return a.dispatcher.Stop(ctx)
+}
+
 // Middleware returns an HTTP middleware that logs audit entries.
 func (a *Auditor) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -34,48 +64,80 @@ func (a *Auditor) Middleware() func(http.Handler) http.Handler {
 			// Call next handler.
 			next.ServeHTTP(wrapped, r)
 
-			// Build audit fields.
-			fields := logrus.Fields{
-				"method":          r.Method,
-				"path":            r.URL.Path,
-				"remote_addr":     r.RemoteAddr,
-				"datasource_type": extractDatasourceType(r.URL.Path),
-				"status":          wrapped.statusCode,
-				"request_bytes":   r.ContentLength,
-				"response_bytes":  wrapped.bytesWritten,
-				"duration_ms":     time.Since(start).Milliseconds(),
+			entry := audit.Entry{
+				SchemaVersion:  audit.SchemaVersion,
+				Time:           start.UTC(),
+				Method:         r.Method,
+				Path:           r.URL.Path,
+				RemoteAddr:     r.RemoteAddr,
+				DatasourceType: extractDatasourceType(r.URL.Path),
+				DatasourceName: r.Header.Get(handlers.DatasourceHeader),
+				Status:         wrapped.statusCode,
+				RequestBytes:   r.ContentLength,
+				ResponseBytes:  int64(wrapped.bytesWritten),
+				DurationMS:     time.Since(start).Milliseconds(),
+				QueryString:    r.URL.RawQuery,
+				UserAgent:      r.UserAgent(),
 			}
 
+			id := handlers.GetRequestIdentity(r.Context())
+			entry.OnBehalfOf, entry.ExecutionID = id.OnBehalfOf, id.ExecutionID
+
 			// Resolve user identity from auth context.
 			if proxyUser := GetAuthUser(r.Context()); proxyUser != nil {
-				fields["subject"] = proxyUser.Subject
-				fields["username"] = proxyUser.Username
-
-				if len(proxyUser.Groups) > 0 {
-					fields["groups"] = proxyUser.Groups
-				}
+				entry.Subject = proxyUser.Subject
+				entry.Username = proxyUser.Username
+				entry.Groups = proxyUser.Groups
 			} else if authUser := simpleauth.GetAuthUser(r.Context()); authUser != nil {
-				fields["subject"] = authUser.Subject
-				fields["username"] = authUser.Username
-
-				if len(authUser.Groups) > 0 {
-					fields["groups"] = authUser.Groups
-				}
+				entry.Subject = authUser.Subject
+				entry.Username = authUser.Username
+				entry.Groups = authUser.Groups
 			}
 
-			if ds := r.Header.Get(handlers.DatasourceHeader); ds != "" {
-				fields["datasource_name"] = ds
-			}
+			a.log.WithFields(auditFields(entry)).Info("Audit")
+			a.recent.Add(entry)
 
-			if r.URL.RawQuery != "" {
-				fields["query_string"] = r.URL.RawQuery
+			if a.dispatcher != nil {
+				a.dispatcher.Record(entry)
 			}
+		})
+	}
+}
 
-			if ua := r.UserAgent(); ua != "" {
-				fields["user_agent"] = ua
-			}
+// auditFields converts an entry to log fields, omitting empty optional
+// fields as the log output always has.
+func auditFields(entry audit.Entry) logrus.Fields {
+	fields := logrus.Fields{
+		"method":          entry.Method,
+		"path":            entry.Path,
+		"remote_addr":     entry.RemoteAddr,
+		"datasource_type": entry.DatasourceType,
+		"status":          entry.Status,
+		"request_bytes":   entry.RequestBytes,
+		"response_bytes":  entry.ResponseBytes,
+		"duration_ms":     entry.DurationMS,
+	}
 
-			a.log.WithFields(fields).Info("Audit")
-		})
+	if entry.Subject != "" || entry.Username != "" {
+		fields["subject"] = entry.Subject
+		fields["username"] = entry.Username
+	}
+
+	if len(entry.Groups) > 0 {
+		fields["groups"] = entry.Groups
 	}
+
+	if entry.DatasourceName != "" {
+		fields["datasource_name"] = entry.DatasourceName
+	}
+
+	if entry.QueryString != "" {
+		fields["query_string"] = entry.QueryString
+	}
+
+	if entry.UserAgent != "" {
+		fields["user_agent"] = entry.UserAgent
+	}
+
+	return fields
 }
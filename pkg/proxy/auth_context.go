@@ -1,6 +1,12 @@
 package proxy
 
-import "context"
+import (
+	"context"
+	"net/http"
+
+	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
 
 // AuthUser represents the authenticated identity for proxy requests.
 type AuthUser struct {
@@ -22,3 +28,34 @@ func GetAuthUser(ctx context.Context) *AuthUser {
 	user, _ := ctx.Value(proxyAuthUserKey).(*AuthUser)
 	return user
 }
+
+// requestUsername returns the authenticated user's name, from either
+// authenticator.
+func requestUsername(ctx context.Context) string {
+	if user := GetAuthUser(ctx); user != nil {
+		return user.Username
+	}
+
+	if user := simpleauth.GetAuthUser(ctx); user != nil {
+		return user.Username
+	}
+
+	return ""
+}
+
+// requestIdentityMiddleware records who a request is for: the authenticated
+// user, plus the user and execution the MCP server reports. The reported
+// headers are stripped, so only handlers with query tagging forward them.
+func requestIdentityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		onBehalfOf, executionID := handlers.TakeIdentityHeaders(r.Header)
+
+		id := handlers.RequestIdentity{
+			User:        handlers.CleanTag(requestUsername(r.Context())),
+			OnBehalfOf:  onBehalfOf,
+			ExecutionID: executionID,
+		}
+
+		next.ServeHTTP(w, r.WithContext(handlers.WithRequestIdentity(r.Context(), id)))
+	})
+}
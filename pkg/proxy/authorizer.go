@@ -12,16 +12,19 @@ import (
 
 // Authorizer enforces per-datasource access control based on GitHub org membership.
 // Rules are built from datasource configs at startup and checked on every request.
+// Datasource scopes further limit each group to the datasources granted to it.
 type Authorizer struct {
-	log   logrus.FieldLogger
-	rules map[string][]string // "type:name" -> allowed_orgs; "type" for type-level rules (ethnode)
+	log    logrus.FieldLogger
+	rules  map[string][]string // "type:name" -> allowed_orgs; "type" for type-level rules (ethnode)
+	scopes *simpleauth.DatasourcePolicy
 }
 
 // NewAuthorizer creates an Authorizer from the server config.
 func NewAuthorizer(log logrus.FieldLogger, cfg ServerConfig) *Authorizer {
 	a := &Authorizer{
-		log:   log.WithField("component", "authorizer"),
-		rules: make(map[string][]string, len(cfg.ClickHouse)+len(cfg.Prometheus)+len(cfg.Loki)+1),
+		log:    log.WithField("component", "authorizer"),
+		rules:  make(map[string][]string, len(cfg.ClickHouse)+len(cfg.Prometheus)+len(cfg.Loki)+len(cfg.Grafana)+len(cfg.HTTPJSON)+2),
+		scopes: simpleauth.NewDatasourcePolicy(cfg.DatasourceScopes),
 	}
 
 	for _, ds := range cfg.ClickHouse {
@@ -42,10 +45,26 @@ func NewAuthorizer(log logrus.FieldLogger, cfg ServerConfig) *Authorizer {
 		}
 	}
 
+	for _, ds := range cfg.Grafana {
+		if len(ds.AllowedOrgs) > 0 {
+			a.rules[ruleKey("grafana", ds.Name)] = ds.AllowedOrgs
+		}
+	}
+
+	for _, ds := range cfg.HTTPJSON {
+		if len(ds.AllowedOrgs) > 0 {
+			a.rules[ruleKey("httpjson", ds.Name)] = ds.AllowedOrgs
+		}
+	}
+
 	if cfg.EthNode != nil && len(cfg.EthNode.AllowedOrgs) > 0 {
 		a.rules[ruleKey("ethnode", "")] = cfg.EthNode.AllowedOrgs
 	}
 
+	if cfg.GitHub != nil && len(cfg.GitHub.AllowedOrgs) > 0 {
+		a.rules[ruleKey("github", "")] = cfg.GitHub.AllowedOrgs
+	}
+
 	return a
 }
 
@@ -76,13 +95,13 @@ func (a *Authorizer) FilterDatasources(ctx context.Context, resp DatasourcesResp
 	}
 
 	filtered := DatasourcesResponse{
-		EthNodeAvailable:   resp.EthNodeAvailable && a.orgsMatch(userOrgs, ruleKey("ethnode", "")),
+		EthNodeAvailable:   resp.EthNodeAvailable && a.allows(ctx, userOrgs, "ethnode", ""),
 		EmbeddingAvailable: resp.EmbeddingAvailable,
 		EmbeddingModel:     resp.EmbeddingModel,
 	}
 
 	for i, name := range resp.ClickHouse {
-		if a.orgsMatch(userOrgs, ruleKey("clickhouse", name)) {
+		if a.allows(ctx, userOrgs, "clickhouse", name) {
 			filtered.ClickHouse = append(filtered.ClickHouse, name)
 
 			if i < len(resp.ClickHouseInfo) {
@@ -92,7 +111,7 @@ func (a *Authorizer) FilterDatasources(ctx context.Context, resp DatasourcesResp
 	}
 
 	for i, name := range resp.Prometheus {
-		if a.orgsMatch(userOrgs, ruleKey("prometheus", name)) {
+		if a.allows(ctx, userOrgs, "prometheus", name) {
 			filtered.Prometheus = append(filtered.Prometheus, name)
 
 			if i < len(resp.PrometheusInfo) {
@@ -102,7 +121,7 @@ func (a *Authorizer) FilterDatasources(ctx context.Context, resp DatasourcesResp
 	}
 
 	for i, name := range resp.Loki {
-		if a.orgsMatch(userOrgs, ruleKey("loki", name)) {
+		if a.allows(ctx, userOrgs, "loki", name) {
 			filtered.Loki = append(filtered.Loki, name)
 
 			if i < len(resp.LokiInfo) {
@@ -111,6 +130,31 @@ func (a *Authorizer) FilterDatasources(ctx context.Context, resp DatasourcesResp
 		}
 	}
 
+	for i, name := range resp.Grafana {
+		if a.allows(ctx, userOrgs, "grafana", name) {
+			filtered.Grafana = append(filtered.Grafana, name)
+
+			if i < len(resp.GrafanaInfo) {
+				filtered.GrafanaInfo = append(filtered.GrafanaInfo, resp.GrafanaInfo[i])
+			}
+		}
+	}
+
+	for i, name := range resp.HTTPJSON {
+		if a.allows(ctx, userOrgs, "httpjson", name) {
+			filtered.HTTPJSON = append(filtered.HTTPJSON, name)
+
+			if i < len(resp.HTTPJSONInfo) {
+				filtered.HTTPJSONInfo = append(filtered.HTTPJSONInfo, resp.HTTPJSONInfo[i])
+			}
+		}
+	}
+
+	if a.allows(ctx, userOrgs, "github", "") {
+		filtered.GitHub = resp.GitHub
+		filtered.GitHubInfo = resp.GitHubInfo
+	}
+
 	return filtered
 }
 
@@ -121,17 +165,26 @@ func (a *Authorizer) isAllowed(ctx context.Context, dsType, dsName string) bool
 		return true // no auth user in context (none mode) → allow
 	}
 
-	// For ethnode, check at type level (no per-name granularity).
-	if dsType == "ethnode" {
-		return a.orgsMatch(userOrgs, ruleKey("ethnode", ""))
+	// For ethnode and github, check at type level (no per-name granularity).
+	if dsType == "ethnode" || dsType == "github" {
+		return a.allows(ctx, userOrgs, dsType, "")
 	}
 
-	// For datasources endpoint, skip middleware check (filtered in handler).
-	if dsType == "datasources" || dsType == "unknown" {
+	// For datasources, audit and quota endpoints, skip middleware check
+	// (enforced in handler).
+	if dsType == "datasources" || dsType == "audit" || dsType == "quota" || dsType == "unknown" {
 		return true
 	}
 
-	return a.orgsMatch(userOrgs, ruleKey(dsType, dsName))
+	return a.allows(ctx, userOrgs, dsType, dsName)
+}
+
+// allows checks the datasource's allowed orgs, the datasource scopes and the
+// scopes of a service-account token.
+func (a *Authorizer) allows(ctx context.Context, userOrgs []string, dsType, dsName string) bool {
+	return a.orgsMatch(userOrgs, ruleKey(dsType, dsName)) &&
+		a.scopes.Allows(userOrgs, dsType, dsName) &&
+		simpleauth.GetAuthUser(ctx).AllowsDatasource(dsType, dsName)
 }
 
 // orgsMatch returns true if the user has access based on the rule for the given key.
@@ -158,15 +211,18 @@ func (a *Authorizer) orgsMatch(userOrgs []string, key string) bool {
 //   - OAuth mode: auth.AuthUser.Orgs
 //   - OIDC mode: proxy.AuthUser.Groups
 //   - None mode: returns nil (no restriction)
+//
+// An authenticated user in no org gets an empty, non-nil slice so they are
+// still restricted.
 func getUserOrgs(ctx context.Context) []string {
 	// Check proxy.AuthUser (OIDC mode).
 	if user := GetAuthUser(ctx); user != nil {
-		return user.Groups
+		return append([]string{}, user.Groups...)
 	}
 
 	// Check auth.AuthUser (OAuth mode).
 	if user := simpleauth.GetAuthUser(ctx); user != nil {
-		return user.Orgs
+		return append([]string{}, user.Orgs...)
 	}
 
 	return nil
@@ -6,11 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -265,3 +268,108 @@ func TestAuthorizerEthnode(t *testing.T) {
 	srv.mux.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusForbidden, rec.Code)
 }
+
+func TestAuthorizerDatasourceScopes(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.DatasourceScopes = []simpleauth.DatasourceScope{
+		{Groups: []string{"researchers"}, Datasources: []string{"clickhouse:public", "loki"}},
+		{Groups: []string{"ethpandaops"}, Datasources: []string{"*"}},
+	}
+	require.NoError(t, simpleauth.ValidateDatasourceScopes(cfg.DatasourceScopes))
+
+	authorizer := NewAuthorizer(logrus.New(), cfg)
+	resp := DatasourcesResponse{
+		ClickHouse: []string{"restricted", "public"},
+		Prometheus: []string{"internal"},
+		Loki:       []string{"logs"},
+	}
+
+	// Researchers only see what their scope grants.
+	ctx := withAuthUser(context.Background(), &AuthUser{Groups: []string{"researchers"}})
+	filtered := authorizer.FilterDatasources(ctx, resp)
+	assert.Equal(t, []string{"public"}, filtered.ClickHouse)
+	assert.Empty(t, filtered.Prometheus)
+	assert.Equal(t, []string{"logs"}, filtered.Loki)
+
+	// Scopes never widen a datasource's allowed_orgs.
+	ctx = withAuthUser(context.Background(), &AuthUser{Groups: []string{"ethpandaops"}})
+	filtered = authorizer.FilterDatasources(ctx, resp)
+	assert.Equal(t, []string{"restricted", "public"}, filtered.ClickHouse)
+
+	// Users in no scoped group, including those in no group at all, see nothing.
+	for _, groups := range [][]string{{"other"}, nil} {
+		ctx = withAuthUser(context.Background(), &AuthUser{Groups: groups})
+		filtered = authorizer.FilterDatasources(ctx, resp)
+		assert.Empty(t, filtered.ClickHouse)
+		assert.Empty(t, filtered.Loki)
+	}
+
+	// Queries are enforced too.
+	assert.True(t, authorizer.isAllowed(withAuthUser(context.Background(), &AuthUser{Groups: []string{"researchers"}}), "clickhouse", "public"))
+	assert.False(t, authorizer.isAllowed(withAuthUser(context.Background(), &AuthUser{Groups: []string{"researchers"}}), "prometheus", "internal"))
+	assert.True(t, authorizer.isAllowed(context.Background(), "prometheus", "internal"))
+}
+
+func TestAuthorizerServiceAccountToken(t *testing.T) {
+	t.Parallel()
+
+	authCfg := simpleauth.Config{
+		Enabled:   true,
+		IssuerURL: "https://proxy.example.com",
+		GitHub:    &simpleauth.GitHubConfig{ClientID: "github-client", ClientSecret: "github-secret"},
+		Tokens:    simpleauth.TokensConfig{SecretKey: "test-secret"},
+	}
+
+	svc, err := simpleauth.NewSimpleService(logrus.New(), authCfg)
+	require.NoError(t, err)
+
+	token, _, _, err := simpleauth.MintServiceAccountToken(authCfg, simpleauth.ServiceAccount{
+		Name:        "nightly-ci",
+		Orgs:        []string{"ethpandaops"},
+		Datasources: []string{"clickhouse:restricted", "loki"},
+		Tools:       []string{"*"},
+	}, time.Now())
+	require.NoError(t, err)
+
+	authorizer := NewAuthorizer(logrus.New(), testConfig())
+	handler := svc.Middleware()(authorizer.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	do := func(path, datasource string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(handlers.DatasourceHeader, datasource)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// The token's orgs satisfy allowed_orgs, its datasources narrow them.
+	assert.Equal(t, http.StatusOK, do("/clickhouse/", "restricted"))
+	assert.Equal(t, http.StatusOK, do("/loki/loki/api/v1/labels", "logs"))
+	assert.Equal(t, http.StatusForbidden, do("/clickhouse/", "public"))
+	assert.Equal(t, http.StatusForbidden, do("/prometheus/api/v1/query", "internal"))
+
+	// Discovery only lists the token's datasources.
+	req := httptest.NewRequest(http.MethodGet, "/datasources", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var filtered DatasourcesResponse
+
+	svc.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		filtered = authorizer.FilterDatasources(r.Context(), DatasourcesResponse{
+			ClickHouse: []string{"restricted", "public"},
+			Prometheus: []string{"internal"},
+			Loki:       []string{"logs"},
+		})
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{"restricted"}, filtered.ClickHouse)
+	assert.Empty(t, filtered.Prometheus)
+	assert.Equal(t, []string{"logs"}, filtered.Loki)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ethpandaops/panda/internal/version"
 	"github.com/ethpandaops/panda/pkg/auth/client"
 	"github.com/ethpandaops/panda/pkg/auth/store"
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -37,6 +38,12 @@ type Client interface {
 	// RevokeToken is a no-op for client-managed bearer tokens.
 	RevokeToken(executionID string)
 
+	// SignRequest adds HMAC signature headers when a signing key is configured.
+	SignRequest(req *http.Request) error
+
+	// Transport returns the round tripper for requests to the proxy.
+	Transport() http.RoundTripper
+
 	// ClickHouseDatasources returns the discovered ClickHouse datasource names.
 	ClickHouseDatasources() []string
 	// ClickHouseDatasourceInfo returns detailed ClickHouse datasource info.
@@ -52,6 +59,21 @@ type Client interface {
 	// LokiDatasourceInfo returns detailed Loki datasource info.
 	LokiDatasourceInfo() []types.DatasourceInfo
 
+	// GrafanaDatasources returns the discovered Grafana instance names.
+	GrafanaDatasources() []string
+	// GrafanaDatasourceInfo returns detailed Grafana instance info.
+	GrafanaDatasourceInfo() []types.DatasourceInfo
+
+	// HTTPJSONDatasources returns the discovered HTTP JSON endpoint names.
+	HTTPJSONDatasources() []string
+	// HTTPJSONDatasourceInfo returns detailed HTTP JSON endpoint info.
+	HTTPJSONDatasourceInfo() []types.DatasourceInfo
+
+	// GitHubDatasources returns the discovered GitHub repository names.
+	GitHubDatasources() []string
+	// GitHubDatasourceInfo returns detailed GitHub repository info.
+	GitHubDatasourceInfo() []types.DatasourceInfo
+
 	// EthNodeAvailable returns true if the proxy has ethnode credentials configured.
 	EthNodeAvailable() bool
 
@@ -83,6 +105,22 @@ type ClientConfig struct {
 	// Leave empty for standard OIDC providers that do not use RFC 8707 resource parameters.
 	Resource string
 
+	// Profile selects the named credential profile to load tokens from.
+	// Empty falls back to $PANDA_PROFILE, then the default profile.
+	Profile string
+
+	// Token is a static bearer token, such as a service-account token for CI.
+	// When set, it is sent instead of stored login credentials.
+	Token string
+
+	// CredentialStore selects the credential store backend tokens are saved
+	// with (see store.Config.Backend).
+	CredentialStore string
+
+	// SigningKey is the optional HMAC key used to sign every request to the proxy.
+	// It must match the proxy's auth.request_signing.secret_key.
+	SigningKey string
+
 	// RefreshTokenTTL is the expected lifetime of the refresh token.
 	// When set, the credential store will refresh at 50% of this duration
 	// to keep the refresh token alive via provider rotation.
@@ -94,6 +132,10 @@ type ClientConfig struct {
 
 	// HTTPTimeout is the timeout for HTTP requests (default: 30 seconds).
 	HTTPTimeout time.Duration
+
+	// TLS configures the CAs and client certificate used to reach a proxy
+	// served over TLS or mTLS.
+	TLS tlsconfig.ClientConfig
 }
 
 // ApplyDefaults sets default values for the client config.
@@ -112,6 +154,7 @@ type proxyClient struct {
 	log        logrus.FieldLogger
 	cfg        ClientConfig
 	httpClient *http.Client
+	transport  http.RoundTripper
 	authClient client.Client
 	credStore  store.Store
 
@@ -130,16 +173,22 @@ var (
 )
 
 // NewClient creates a new proxy client.
-func NewClient(log logrus.FieldLogger, cfg ClientConfig) Client {
+func NewClient(log logrus.FieldLogger, cfg ClientConfig) (Client, error) {
 	cfg.ApplyDefaults()
 
+	transport, err := cfg.TLS.Transport()
+	if err != nil {
+		return nil, fmt.Errorf("configuring proxy TLS: %w", err)
+	}
+
 	c := &proxyClient{
 		log: log.WithField("component", "proxy-client"),
 		cfg: cfg,
 		httpClient: &http.Client{
-			Transport: &version.Transport{},
+			Transport: &version.Transport{Base: transport},
 			Timeout:   cfg.HTTPTimeout,
 		},
+		transport:   transport,
 		datasources: &DatasourcesResponse{},
 		stopCh:      make(chan struct{}),
 	}
@@ -152,23 +201,30 @@ func NewClient(log logrus.FieldLogger, cfg ClientConfig) Client {
 
 	resource := strings.TrimRight(cfg.Resource, "/")
 
-	if issuerURL != "" && cfg.ClientID != "" {
+	if cfg.Token == "" && issuerURL != "" && cfg.ClientID != "" {
 		c.authClient = client.New(log, client.Config{
 			IssuerURL: issuerURL,
 			ClientID:  cfg.ClientID,
 			Resource:  resource,
 		})
 
+		profile, err := store.ResolveProfile("", cfg.Profile)
+		if err != nil {
+			return nil, err
+		}
+
 		c.credStore = store.New(log, store.Config{
 			AuthClient:      c.authClient,
 			IssuerURL:       issuerURL,
 			ClientID:        cfg.ClientID,
 			Resource:        resource,
+			Profile:         profile,
+			Backend:         cfg.CredentialStore,
 			RefreshTokenTTL: cfg.RefreshTokenTTL,
 		})
 	}
 
-	return c
+	return c, nil
 }
 
 // Start starts the client and performs initial discovery.
@@ -189,6 +245,10 @@ func (c *proxyClient) Start(ctx context.Context) error {
 		go c.backgroundRefresh()
 	}
 
+	if c.credStore != nil {
+		go c.backgroundTokenRefresh()
+	}
+
 	return nil
 }
 
@@ -215,6 +275,10 @@ func (c *proxyClient) URL() string {
 }
 
 func (c *proxyClient) RegisterToken(_ string) string {
+	if c.cfg.Token != "" {
+		return c.cfg.Token
+	}
+
 	if c.credStore == nil {
 		return "none"
 	}
@@ -237,6 +301,16 @@ func (c *proxyClient) RevokeToken(_ string) {
 	// No-op: tokens are managed by the proxy control plane.
 }
 
+// Transport returns the round tripper configured for the proxy's TLS.
+func (c *proxyClient) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// SignRequest adds HMAC signature headers to req when a signing key is configured.
+func (c *proxyClient) SignRequest(req *http.Request) error {
+	return SignRequest(req, c.cfg.SigningKey, time.Now())
+}
+
 func namesFromInfo(infos []types.DatasourceInfo) []string {
 	if len(infos) == 0 {
 		return nil
@@ -362,6 +436,78 @@ func (c *proxyClient) LokiDatasourceInfo() []types.DatasourceInfo {
 	return namesToInfo("loki", c.datasources.Loki)
 }
 
+// GrafanaDatasources returns the discovered Grafana instance names.
+func (c *proxyClient) GrafanaDatasources() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.Grafana) > 0 {
+		return append([]string(nil), c.datasources.Grafana...)
+	}
+
+	return namesFromInfo(c.datasources.GrafanaInfo)
+}
+
+// GrafanaDatasourceInfo returns detailed Grafana instance info.
+func (c *proxyClient) GrafanaDatasourceInfo() []types.DatasourceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.GrafanaInfo) > 0 {
+		return normalizeInfo("grafana", c.datasources.GrafanaInfo)
+	}
+
+	return namesToInfo("grafana", c.datasources.Grafana)
+}
+
+// HTTPJSONDatasources returns the discovered HTTP JSON endpoint names.
+func (c *proxyClient) HTTPJSONDatasources() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.HTTPJSON) > 0 {
+		return append([]string(nil), c.datasources.HTTPJSON...)
+	}
+
+	return namesFromInfo(c.datasources.HTTPJSONInfo)
+}
+
+// HTTPJSONDatasourceInfo returns detailed HTTP JSON endpoint info.
+func (c *proxyClient) HTTPJSONDatasourceInfo() []types.DatasourceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.HTTPJSONInfo) > 0 {
+		return normalizeInfo("httpjson", c.datasources.HTTPJSONInfo)
+	}
+
+	return namesToInfo("httpjson", c.datasources.HTTPJSON)
+}
+
+// GitHubDatasources returns the discovered GitHub repository names.
+func (c *proxyClient) GitHubDatasources() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.GitHub) > 0 {
+		return append([]string(nil), c.datasources.GitHub...)
+	}
+
+	return namesFromInfo(c.datasources.GitHubInfo)
+}
+
+// GitHubDatasourceInfo returns detailed GitHub repository info.
+func (c *proxyClient) GitHubDatasourceInfo() []types.DatasourceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.datasources.GitHubInfo) > 0 {
+		return normalizeInfo("github", c.datasources.GitHubInfo)
+	}
+
+	return namesToInfo("github", c.datasources.GitHub)
+}
+
 // EthNodeAvailable returns true if the proxy has ethnode credentials configured.
 func (c *proxyClient) EthNodeAvailable() bool {
 	c.mu.RLock()
@@ -408,6 +554,10 @@ func (c *proxyClient) Discover(ctx context.Context) error {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
+	if err := c.SignRequest(req); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("fetching datasources: %w", err)
@@ -448,10 +598,28 @@ func (c *proxyClient) Discover(ctx context.Context) error {
 		lokiCount = len(datasources.LokiInfo)
 	}
 
+	grafanaCount := len(datasources.Grafana)
+	if grafanaCount == 0 {
+		grafanaCount = len(datasources.GrafanaInfo)
+	}
+
+	httpJSONCount := len(datasources.HTTPJSON)
+	if httpJSONCount == 0 {
+		httpJSONCount = len(datasources.HTTPJSONInfo)
+	}
+
+	gitHubCount := len(datasources.GitHub)
+	if gitHubCount == 0 {
+		gitHubCount = len(datasources.GitHubInfo)
+	}
+
 	c.log.WithFields(logrus.Fields{
 		"clickhouse": clickhouseCount,
 		"prometheus": prometheusCount,
 		"loki":       lokiCount,
+		"grafana":    grafanaCount,
+		"httpjson":   httpJSONCount,
+		"github":     gitHubCount,
 	}).Debug("Discovered datasources from proxy")
 
 	return nil
@@ -477,7 +645,7 @@ func (c *proxyClient) EnsureAuthenticated(_ context.Context) error {
 
 func (c *proxyClient) loadAccessToken() (string, error) {
 	if c.credStore == nil {
-		return "", nil
+		return c.cfg.Token, nil
 	}
 
 	tokens, err := c.credStore.Load()
@@ -497,6 +665,33 @@ func (c *proxyClient) loadAccessToken() (string, error) {
 	return token, nil
 }
 
+// tokenRefreshInterval is how often the stored access token is checked. It is
+// well inside the credential store's refresh buffer, so tokens are refreshed
+// before they expire even when no requests are made.
+const tokenRefreshInterval = time.Minute
+
+// backgroundTokenRefresh keeps the stored tokens fresh so the first request
+// after an idle period does not find an expired access token.
+func (c *proxyClient) backgroundTokenRefresh() {
+	ticker := time.NewTicker(tokenRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			if _, err := c.loadAccessToken(); err != nil {
+				if errors.Is(err, ErrAuthenticationRequired) {
+					c.log.WithError(err).Debug("Proxy token refresh skipped")
+				} else {
+					c.log.WithError(err).Warn("Background proxy token refresh failed")
+				}
+			}
+		}
+	}
+}
+
 // backgroundRefresh periodically refreshes datasource information.
 func (c *proxyClient) backgroundRefresh() {
 	ticker := time.NewTicker(c.cfg.DiscoveryInterval)
@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
+)
+
+// AuditClient searches the proxy's recent audit entries through its /audit
+// endpoint, authenticating as the server.
+type AuditClient struct {
+	svc        Service
+	httpClient *http.Client
+}
+
+// NewAuditClient creates an audit client for the proxy behind svc.
+func NewAuditClient(svc Service) *AuditClient {
+	return &AuditClient{svc: svc, httpClient: newControlHTTPClient(svc)}
+}
+
+// Search returns recent audit entries matching q, newest first.
+func (c *AuditClient) Search(ctx context.Context, q audit.Query) (*audit.SearchResponse, error) {
+	var result audit.SearchResponse
+	if err := getControlJSON(ctx, c.svc, c.httpClient, "/audit", q.Values(), &result); err != nil {
+		return nil, fmt.Errorf("searching audit entries: %w", err)
+	}
+
+	return &result, nil
+}
+
+// QuotaClient reads datasource quota usage through the proxy's /quota
+// endpoint, authenticating as the server.
+type QuotaClient struct {
+	svc        Service
+	httpClient *http.Client
+}
+
+// NewQuotaClient creates a quota client for the proxy behind svc.
+func NewQuotaClient(svc Service) *QuotaClient {
+	return &QuotaClient{svc: svc, httpClient: newControlHTTPClient(svc)}
+}
+
+// Usage returns the current usage of every datasource with a quota.
+func (c *QuotaClient) Usage(ctx context.Context) (*QuotaUsageResponse, error) {
+	var result QuotaUsageResponse
+	if err := getControlJSON(ctx, c.svc, c.httpClient, "/quota", nil, &result); err != nil {
+		return nil, fmt.Errorf("reading quota usage: %w", err)
+	}
+
+	return &result, nil
+}
+
+func newControlHTTPClient(svc Service) *http.Client {
+	return &http.Client{Transport: svc.Transport(), Timeout: 30 * time.Second}
+}
+
+// getControlJSON sends a signed GET to a proxy endpoint with the server's
+// token and decodes the JSON response into target.
+func getControlJSON(
+	ctx context.Context,
+	svc Service,
+	httpClient *http.Client,
+	path string,
+	params url.Values,
+	target any,
+) error {
+	targetURL := strings.TrimRight(svc.URL(), "/") + path
+	if len(params) > 0 {
+		targetURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	tokenID := fmt.Sprintf("control-%d", time.Now().UnixNano())
+	token := svc.RegisterToken(tokenID)
+
+	defer svc.RevokeToken(tokenID)
+
+	if token != "" && token != "none" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if err := svc.SignRequest(req); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("proxy returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ErrDatasourcesChanged is returned by ReloadCredentials when the new config
+// adds, removes or renames datasources, which needs a restart.
+var ErrDatasourcesChanged = errors.New("datasources changed; restart the proxy to apply")
+
+// upstream routes to the handler get returns at request time, so handlers
+// rebuilt by ReloadCredentials serve the routes registered at startup.
+func (s *server) upstream(get func() http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handlersMu.RLock()
+		handler := get()
+		s.handlersMu.RUnlock()
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// ReloadCredentials rebuilds the upstream handlers from cfg so rotated
+// datasource credentials take effect without a restart. In-flight requests
+// finish with the old credentials. The ClickHouse response cache starts
+// empty again.
+func (s *server) ReloadCredentials(cfg ServerConfig) error {
+	if !slices.Equal(datasourceKeys(s.cfg), datasourceKeys(cfg)) {
+		return ErrDatasourcesChanged
+	}
+
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	s.buildHandlers(cfg)
+
+	return nil
+}
+
+// refreshCredentials reloads the config file every interval, resolving its
+// secret references again, and applies changed datasource credentials.
+func (s *server) refreshCredentials(ctx context.Context, path string, interval time.Duration) {
+	log := s.log.WithField("config", path)
+	applied := s.cfg
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cfg, err := LoadServerConfig(path)
+		if err != nil {
+			log.WithError(err).Warn("Failed to reload credentials")
+
+			continue
+		}
+
+		if sameHandlerConfigs(applied, *cfg) {
+			continue
+		}
+
+		if err := s.ReloadCredentials(*cfg); err != nil {
+			log.WithError(err).Warn("Failed to reload credentials")
+
+			continue
+		}
+
+		applied = *cfg
+
+		log.Info("Reloaded datasource credentials")
+	}
+}
+
+// sameHandlerConfigs reports whether a and b build identical upstream
+// handlers.
+func sameHandlerConfigs(a, b ServerConfig) bool {
+	ch1, prom1, loki1, grafana1, httpJSON1, ethNode1, gitHub1 := a.ToHandlerConfigs()
+	ch2, prom2, loki2, grafana2, httpJSON2, ethNode2, gitHub2 := b.ToHandlerConfigs()
+
+	return reflect.DeepEqual(ch1, ch2) &&
+		reflect.DeepEqual(prom1, prom2) &&
+		reflect.DeepEqual(loki1, loki2) &&
+		reflect.DeepEqual(grafana1, grafana2) &&
+		reflect.DeepEqual(httpJSON1, httpJSON2) &&
+		reflect.DeepEqual(ethNode1, ethNode2) &&
+		reflect.DeepEqual(gitHub1, gitHub2) &&
+		a.ClickHouseCache == b.ClickHouseCache
+}
+
+// datasourceKeys lists the datasources cfg configures as "type:name".
+func datasourceKeys(cfg ServerConfig) []string {
+	keys := make([]string, 0, len(cfg.ClickHouse)+len(cfg.Prometheus)+len(cfg.Loki)+len(cfg.Grafana)+len(cfg.HTTPJSON)+2)
+
+	for _, ds := range cfg.ClickHouse {
+		keys = append(keys, ruleKey("clickhouse", ds.Name))
+	}
+
+	for _, ds := range cfg.Prometheus {
+		keys = append(keys, ruleKey("prometheus", ds.Name))
+	}
+
+	for _, ds := range cfg.Loki {
+		keys = append(keys, ruleKey("loki", ds.Name))
+	}
+
+	for _, ds := range cfg.Grafana {
+		keys = append(keys, ruleKey("grafana", ds.Name))
+	}
+
+	for _, ds := range cfg.HTTPJSON {
+		keys = append(keys, ruleKey("httpjson", ds.Name))
+	}
+
+	if cfg.EthNode != nil {
+		keys = append(keys, "ethnode")
+	}
+
+	if cfg.GitHub != nil {
+		for _, repo := range cfg.GitHub.Repos {
+			keys = append(keys, ruleKey("github", repo.Name))
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestReloadCredentials(t *testing.T) {
+	t.Parallel()
+
+	var upstreamAuth []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamAuth = append(upstreamAuth, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	grafanaConfig := func(apiKey string) ServerConfig {
+		cfg := ServerConfig{
+			Auth: AuthConfig{Mode: AuthModeNone},
+			Grafana: []GrafanaInstanceConfig{
+				{BaseDatasourceConfig: BaseDatasourceConfig{Name: "ops"}, URL: upstream.URL, APIKey: apiKey},
+			},
+		}
+		cfg.ApplyDefaults()
+
+		return cfg
+	}
+
+	srv, err := newServer(logrus.New(), grafanaConfig("old-token"), "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	search := func() {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/grafana/api/search", nil)
+		req.Header.Set("X-Datasource", "ops")
+		srv.mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+	}
+
+	search()
+
+	if sameHandlerConfigs(srv.cfg, grafanaConfig("new-token")) {
+		t.Fatalf("expected rotated credentials to be detected")
+	}
+
+	if err := srv.ReloadCredentials(grafanaConfig("new-token")); err != nil {
+		t.Fatalf("ReloadCredentials failed: %v", err)
+	}
+
+	search()
+
+	if len(upstreamAuth) != 2 || upstreamAuth[0] != "Bearer old-token" || upstreamAuth[1] != "Bearer new-token" {
+		t.Fatalf("unexpected upstream auth %v", upstreamAuth)
+	}
+
+	renamed := grafanaConfig("new-token")
+	renamed.Grafana[0].Name = "other"
+
+	if err := srv.ReloadCredentials(renamed); !errors.Is(err, ErrDatasourcesChanged) {
+		t.Fatalf("expected ErrDatasourcesChanged, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
+)
+
+// embeddedListenAddr binds an embedded proxy to a free loopback port. Other
+// local processes can still connect, and so can sandboxes where
+// host.docker.internal reaches the host's loopback (Docker Desktop), so every
+// request must carry a signature from the in-process client.
+const embeddedListenAddr = "127.0.0.1:0"
+
+// embeddedClient runs a proxy server inside the calling process and talks to
+// it through a regular Client over loopback.
+type embeddedClient struct {
+	Client
+
+	server *server
+}
+
+// Compile-time interface check.
+var _ Client = (*embeddedClient)(nil)
+
+// NewEmbeddedClient returns a Client backed by a proxy running in this
+// process with cfg. The proxy listens on a loopback port without TLS or user
+// authentication. Instead it requires requests signed with a key generated
+// for this process and known only to the returned client. Quotas, guardrails
+// and the audit log in cfg still apply.
+func NewEmbeddedClient(log logrus.FieldLogger, cfg ServerConfig) (Client, error) {
+	if cfg.Auth.Mode != AuthModeNone {
+		log.WithField("auth_mode", cfg.Auth.Mode).Warn("Embedded proxy ignores auth settings; it only accepts requests signed by this process")
+	}
+
+	signingKey, err := newEmbeddedSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Auth = AuthConfig{
+		Mode:           AuthModeNone,
+		RequestSigning: RequestSigningConfig{SecretKey: signingKey, MaxClockSkew: defaultMaxClockSkew},
+	}
+	cfg.Server.TLS = tlsconfig.ServerConfig{}
+
+	listener, err := net.Listen("tcp", embeddedListenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("binding embedded proxy: %w", err)
+	}
+
+	cfg.Server.ListenAddr = listener.Addr().String()
+	url := "http://" + cfg.Server.ListenAddr
+
+	srv, err := newServer(log, cfg, url, "")
+	if err != nil {
+		_ = listener.Close()
+
+		return nil, err
+	}
+
+	srv.listener = listener
+
+	client, err := NewClient(log, ClientConfig{URL: url, SigningKey: signingKey})
+	if err != nil {
+		_ = listener.Close()
+
+		return nil, err
+	}
+
+	return &embeddedClient{Client: client, server: srv}, nil
+}
+
+// newEmbeddedSigningKey returns a random request signing key for one
+// embedded proxy.
+func newEmbeddedSigningKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating embedded proxy signing key: %w", err)
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+// Start starts the embedded proxy, then the client's initial discovery.
+func (c *embeddedClient) Start(ctx context.Context) error {
+	if err := c.server.Start(ctx); err != nil {
+		_ = c.server.listener.Close()
+
+		return fmt.Errorf("starting embedded proxy: %w", err)
+	}
+
+	if err := c.Client.Start(ctx); err != nil {
+		_ = c.server.Stop(ctx)
+
+		return err
+	}
+
+	return nil
+}
+
+// Stop stops the client, then the embedded proxy.
+func (c *embeddedClient) Stop(ctx context.Context) error {
+	if err := c.Client.Stop(ctx); err != nil {
+		return err
+	}
+
+	return c.server.Stop(ctx)
+}
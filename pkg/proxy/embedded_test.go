@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+func TestEmbeddedClientServesLoopbackProxy(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	cfg := ServerConfig{
+		// Auth settings are replaced by a per-process signing key.
+		Auth: AuthConfig{Mode: AuthModeOIDC, IssuerURL: "https://issuer.test", ClientID: "panda"},
+		Prometheus: []PrometheusInstanceConfig{
+			{
+				BaseDatasourceConfig: BaseDatasourceConfig{Name: "metrics"},
+				URL:                  upstream.URL,
+				Username:             "user",
+				Password:             "secret",
+			},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	client, err := NewEmbeddedClient(logrus.New(), cfg)
+	if err != nil {
+		t.Fatalf("NewEmbeddedClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	proxyURL, err := url.Parse(client.URL())
+	if err != nil {
+		t.Fatalf("parsing proxy URL: %v", err)
+	}
+
+	if proxyURL.Hostname() != "127.0.0.1" {
+		t.Fatalf("expected a loopback proxy URL, got %s", client.URL())
+	}
+
+	if got := client.PrometheusDatasources(); !slices.Equal(got, []string{"metrics"}) {
+		t.Fatalf("expected discovered datasource metrics, got %v", got)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL()+"/prometheus/api/v1/query?query=up", nil)
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+
+	req.Header.Set(handlers.DatasourceHeader, "metrics")
+
+	// Another local process, or a sandbox reaching the host's loopback,
+	// does not have the signing key.
+	resp, err := (&http.Client{Transport: client.Transport()}).Do(req.Clone(ctx))
+	if err != nil {
+		t.Fatalf("proxy request failed: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected an unsigned request to get status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	if err := client.SignRequest(req); err != nil {
+		t.Fatalf("SignRequest failed: %v", err)
+	}
+
+	resp, err = (&http.Client{Transport: client.Transport()}).Do(req)
+	if err != nil {
+		t.Fatalf("proxy request failed: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if err := client.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	health, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL()+"/health", nil)
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+
+	if resp, err := http.DefaultClient.Do(health); err == nil {
+		_ = resp.Body.Close()
+
+		t.Fatal("expected the embedded proxy to stop listening")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // DatasourceHeader is the HTTP header used to specify which datasource to route to.
@@ -28,12 +30,27 @@ type ClickHouseConfig struct {
 	Secure      bool
 	SkipVerify  bool
 	Timeout     int
+	Guardrails  ClickHouseGuardrails
+
+	// QueryTags tags upstream requests with the user and execution
+	// they were made for.
+	QueryTags bool
+
+	// Resilience configures retries and the circuit breaker.
+	Resilience Resilience
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
 }
 
 // ClickHouseHandler handles requests to ClickHouse clusters.
 type ClickHouseHandler struct {
 	log      logrus.FieldLogger
 	clusters map[string]*clickhouseCluster
+	cache    *responseCache
 }
 
 type clickhouseCluster struct {
@@ -41,13 +58,22 @@ type clickhouseCluster struct {
 	proxy *httputil.ReverseProxy
 }
 
-// NewClickHouseHandler creates a new ClickHouse handler.
-func NewClickHouseHandler(log logrus.FieldLogger, configs []ClickHouseConfig) *ClickHouseHandler {
+// NewClickHouseHandler creates a new ClickHouse handler. A non-nil cache
+// config enables caching of read-only query responses.
+func NewClickHouseHandler(
+	log logrus.FieldLogger,
+	configs []ClickHouseConfig,
+	cacheCfg *ClickHouseCacheConfig,
+) *ClickHouseHandler {
 	h := &ClickHouseHandler{
 		log:      log.WithField("handler", "clickhouse"),
 		clusters: make(map[string]*clickhouseCluster, len(configs)),
 	}
 
+	if cacheCfg != nil {
+		h.cache = newResponseCache(*cacheCfg)
+	}
+
 	for _, cfg := range configs {
 		h.clusters[cfg.Name] = h.createCluster(cfg)
 	}
@@ -70,7 +96,9 @@ func (h *ClickHouseHandler) createCluster(cfg ClickHouseConfig) *clickhouseClust
 	// Create reverse proxy.
 	rp := httputil.NewSingleHostReverseProxy(targetURL)
 
-	rp.Transport = newProxyTransport(cfg.SkipVerify)
+	rp.Transport = newResilientTransport(
+		newUpstreamTransport("clickhouse", cfg.Name, cfg.SkipVerify, cfg.Transport), "clickhouse", cfg.Name, cfg.Resilience,
+	)
 
 	// Customize the director to add auth and database.
 	originalDirector := rp.Director
@@ -91,6 +119,18 @@ func (h *ClickHouseHandler) createCluster(cfg ClickHouseConfig) *clickhouseClust
 			q.Set("database", cfg.Database)
 		}
 
+		// Ask for gzip so large results cross the network compressed; the
+		// response limit decodes them and re-encodes for the client.
+		q.Set("enable_http_compression", "1")
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		// Tag the query with who it is for, for system.query_log.
+		if cfg.QueryTags {
+			id := GetRequestIdentity(req.Context())
+			id.tagClickHouseQuery(q)
+			id.setHeaders(req.Header)
+		}
+
 		req.URL.RawQuery = q.Encode()
 
 		// Set req.Host to the target host. The default director only sets req.URL.Host,
@@ -102,10 +142,12 @@ func (h *ClickHouseHandler) createCluster(cfg ClickHouseConfig) *clickhouseClust
 		req.Header.Del("Host")
 	}
 
+	limit := responseLimit{handler: "clickhouse", datasource: cfg.Name, maxBytes: cfg.MaxResponseBytes, decodeGzip: true}
+	rp.ModifyResponse = limit.apply
+
 	// Error handler.
 	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
-		h.log.WithError(err).WithField("cluster", cfg.Name).Error("Proxy error")
-		http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		writeProxyError(w, h.log.WithField("cluster", cfg.Name), err)
 	}
 
 	return &clickhouseCluster{
@@ -146,13 +188,34 @@ func (h *ClickHouseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r = r.WithContext(timeoutCtx)
 	}
 
+	if err := inspectQuery(r, cluster.cfg.Guardrails); err != nil {
+		clickhouseGuardrailRejections.WithLabelValues(clusterName, err.reason).Inc()
+		h.log.WithFields(logrus.Fields{
+			"cluster": clusterName,
+			"reason":  err.reason,
+		}).Debug("Rejected ClickHouse query")
+		http.Error(w, err.Error(), err.status)
+
+		return
+	}
+
 	h.log.WithFields(logrus.Fields{
 		"cluster": clusterName,
 		"path":    path,
 		"method":  r.Method,
 	}).Debug("Proxying ClickHouse request")
 
-	cluster.proxy.ServeHTTP(w, r)
+	if h.cache != nil {
+		if h.cache.serve(w, r, clusterName, cluster.proxy) {
+			return
+		}
+
+		w.Header().Set(CacheHeader, "BYPASS")
+	}
+
+	// Cached responses are stored decoded, so only uncached requests get
+	// gzip passed through.
+	cluster.proxy.ServeHTTP(w, withClientEncoding(r))
 }
 
 // Clusters returns the list of configured cluster names.
@@ -164,3 +227,21 @@ func (h *ClickHouseHandler) Clusters() []string {
 
 	return names
 }
+
+// CircuitBreakers returns the circuit breaker state of each cluster that has
+// one.
+func (h *ClickHouseHandler) CircuitBreakers() map[string]*types.CircuitBreakerStatus {
+	states := make(map[string]*types.CircuitBreakerStatus, len(h.clusters))
+
+	for name, cluster := range h.clusters {
+		if cluster == nil {
+			continue
+		}
+
+		if status := breakerStatus(cluster.proxy.Transport); status != nil {
+			states[name] = status
+		}
+	}
+
+	return states
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheHeader reports whether a ClickHouse response was served from the
+// proxy cache (HIT), stored in it (MISS) or not cacheable (BYPASS).
+const CacheHeader = "X-Panda-Cache"
+
+// maxCachedQueryBytes bounds the request bodies read for cache lookup.
+// Larger queries are proxied without caching.
+const maxCachedQueryBytes = 256 * 1024
+
+// ClickHouseCacheConfig configures the ClickHouse response cache.
+type ClickHouseCacheConfig struct {
+	// TTL is how long a cached response is served.
+	TTL time.Duration
+	// MaxSizeBytes bounds the total cached response bytes. Responses larger
+	// than a tenth of it are not cached.
+	MaxSizeBytes int64
+}
+
+// clickhouseHeaderPrefix is the canonical prefix of the HTTP headers
+// ClickHouse reads settings such as X-ClickHouse-Database and
+// X-ClickHouse-Format from.
+const clickhouseHeaderPrefix = "X-Clickhouse-"
+
+// uncachedParams are query parameters that vary per request without
+// changing the result.
+var uncachedParams = map[string]bool{
+	"query_id":        true,
+	"session_id":      true,
+	"session_timeout": true,
+	"session_check":   true,
+}
+
+// readOnlyKeywords are the statements whose results may be cached.
+var readOnlyKeywords = []string{"SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXISTS", "EXPLAIN"}
+
+// responseCache is a size-bounded LRU of successful read-only ClickHouse
+// responses keyed on datasource, normalized query and parameters. Access
+// control runs before the handler, so entries are shared across users of
+// the same datasource.
+type responseCache struct {
+	cfg ClickHouseCacheConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+type cacheEntry struct {
+	key     string
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func newResponseCache(cfg ClickHouseCacheConfig) *responseCache {
+	return &responseCache{
+		cfg:     cfg,
+		now:     time.Now,
+		entries: make(map[string]*list.Element, 64),
+		lru:     list.New(),
+	}
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := elem.Value.(*cacheEntry)
+	if c.now().After(entry.expires) {
+		c.remove(elem)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return entry, true
+}
+
+func (c *responseCache) put(entry *cacheEntry) {
+	size := int64(len(entry.body))
+	if size > c.cfg.MaxSizeBytes/10 {
+		return
+	}
+
+	entry.expires = c.now().Add(c.cfg.TTL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += size
+
+	for c.size > c.cfg.MaxSizeBytes {
+		c.remove(c.lru.Back())
+	}
+
+	clickhouseCacheBytes.Set(float64(c.size))
+}
+
+// remove drops elem. Callers must hold c.mu.
+func (c *responseCache) remove(elem *list.Element) {
+	entry, _ := elem.Value.(*cacheEntry)
+
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= int64(len(entry.body))
+	clickhouseCacheBytes.Set(float64(c.size))
+}
+
+// serve answers r from the cache or proxies it and stores a successful
+// response. It returns false without writing when r is not cacheable, so
+// the caller proxies it as usual.
+func (c *responseCache) serve(w http.ResponseWriter, r *http.Request, datasource string, next http.Handler) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		return false
+	}
+
+	var body []byte
+
+	if r.Body != nil && r.Body != http.NoBody {
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxCachedQueryBytes+1))
+		if err != nil {
+			return false
+		}
+
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+
+		if len(data) > maxCachedQueryBytes {
+			return false
+		}
+
+		body = data
+	}
+
+	key, ok := cacheKey(datasource, r.URL.Query(), r.Header, body)
+	if !ok {
+		return false
+	}
+
+	if !strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+		if entry, hit := c.get(key); hit {
+			clickhouseCacheRequests.WithLabelValues(datasource, "hit").Inc()
+
+			for name, values := range entry.header {
+				w.Header()[name] = values
+			}
+
+			w.Header().Set(CacheHeader, "HIT")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(entry.body)
+
+			return true
+		}
+	}
+
+	clickhouseCacheRequests.WithLabelValues(datasource, "miss").Inc()
+
+	w.Header().Set(CacheHeader, "MISS")
+
+	rec := &cacheRecorder{ResponseWriter: w, status: http.StatusOK, limit: c.cfg.MaxSizeBytes / 10}
+	next.ServeHTTP(rec, r)
+
+	if rec.status == http.StatusOK && !rec.overflow && rec.header.Get("X-ClickHouse-Exception-Code") == "" &&
+		!bytes.Contains(rec.buf.Bytes(), []byte("DB::Exception")) {
+		c.put(&cacheEntry{
+			key:    key,
+			header: rec.header,
+			body:   bytes.Clone(rec.buf.Bytes()),
+		})
+	}
+
+	return true
+}
+
+// cacheRecorder passes a response through while keeping a copy of it.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status   int
+	header   http.Header
+	buf      bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (r *cacheRecorder) WriteHeader(status int) {
+	r.status = status
+	r.header = r.ResponseWriter.Header().Clone()
+	r.header.Del(CacheHeader)
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *cacheRecorder) Write(p []byte) (int, error) {
+	if r.header == nil {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.overflow {
+		if int64(r.buf.Len()+len(p)) > r.limit {
+			r.overflow = true
+			r.buf.Reset()
+		} else {
+			r.buf.Write(p)
+		}
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *cacheRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// cacheKey derives the cache key of a request, or reports false when the
+// query is not read-only. The query may be in the body, the "query"
+// parameter, or split across both as ClickHouse allows. X-ClickHouse-*
+// headers, which can set the database and output format, are part of the
+// key like the parameters.
+func cacheKey(datasource string, params url.Values, header http.Header, body []byte) (string, bool) {
+	query := normalizeQuery(joinQuery(params.Get("query"), body))
+	if !isReadOnly(query) {
+		return "", false
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "query" && !uncachedParams[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(datasource))
+	h.Write([]byte{0})
+	h.Write([]byte(query))
+
+	for _, name := range names {
+		for _, value := range params[name] {
+			h.Write([]byte{0})
+			h.Write([]byte(name + "=" + value))
+		}
+	}
+
+	headers := make([]string, 0, 4)
+	for name := range header {
+		if strings.HasPrefix(name, clickhouseHeaderPrefix) {
+			headers = append(headers, name)
+		}
+	}
+
+	sort.Strings(headers)
+
+	for _, name := range headers {
+		for _, value := range header[name] {
+			h.Write([]byte{1})
+			h.Write([]byte(name + ":" + value))
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// normalizeQuery trims the query and a trailing semicolon and collapses
+// whitespace outside quoted strings and identifiers. Runs containing a line
+// break become one newline, so line comments keep ending where they did.
+func normalizeQuery(query string) string {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+
+	var (
+		out   strings.Builder
+		quote rune
+		space rune
+	)
+
+	out.Grow(len(query))
+
+	escaped := false
+
+	for _, ch := range query {
+		if quote != 0 {
+			out.WriteRune(ch)
+
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == quote:
+				quote = 0
+			}
+
+			continue
+		}
+
+		if ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n' {
+			if ch == '\n' || space == 0 {
+				space = ch
+			}
+
+			continue
+		}
+
+		if space != 0 {
+			if space == '\n' {
+				out.WriteByte('\n')
+			} else {
+				out.WriteByte(' ')
+			}
+
+			space = 0
+		}
+
+		if ch == '\'' || ch == '"' || ch == '`' {
+			quote = ch
+		}
+
+		out.WriteRune(ch)
+	}
+
+	return out.String()
+}
+
+// isReadOnly reports whether the normalized query starts with a read-only
+// statement keyword, ignoring leading parentheses.
+func isReadOnly(query string) bool {
+	query = strings.TrimLeft(query, "( \n")
+
+	end := strings.IndexFunc(query, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(query)
+	}
+
+	keyword := strings.ToUpper(query[:end])
+	for _, ro := range readOnlyKeywords {
+		if keyword == ro {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	clickhouseCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "clickhouse_cache_requests_total",
+			Help:      "Total number of cacheable ClickHouse requests by datasource and result (hit or miss)",
+		},
+		[]string{"datasource", "result"},
+	)
+
+	clickhouseCacheBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "clickhouse_cache_bytes",
+			Help:      "Response bytes held in the ClickHouse cache",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(clickhouseCacheRequests, clickhouseCacheBytes)
+}
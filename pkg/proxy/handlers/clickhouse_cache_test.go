@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseCache(t *testing.T) {
+	upstreamCalls := 0
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/tab-separated-values")
+		_, _ = w.Write([]byte("result of " + strings.TrimSpace(string(body))))
+	}))
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{{Name: "xatu", Host: u.Hostname(), Port: port}},
+		&ClickHouseCacheConfig{TTL: time.Minute, MaxSizeBytes: 1 << 20})
+
+	do := func(sql, queryID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/clickhouse/?default_format=JSON&query_id="+queryID, strings.NewReader(sql))
+		req.Header.Set(DatasourceHeader, "xatu")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	first := do("SELECT count()\n  FROM blocks;", "a")
+	assert.Equal(t, "MISS", first.Header().Get(CacheHeader))
+
+	second := do("SELECT  count()\nFROM blocks", "b")
+	assert.Equal(t, "HIT", second.Header().Get(CacheHeader))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "text/tab-separated-values", second.Header().Get("Content-Type"))
+
+	// Whitespace inside string literals is significant.
+	assert.Equal(t, "MISS", do("SELECT 'a  b'", "c").Header().Get(CacheHeader))
+	assert.Equal(t, "MISS", do("SELECT 'a b'", "d").Header().Get(CacheHeader))
+
+	// Statements outside the read-only set are never cached.
+	assert.Equal(t, "BYPASS", do("CHECK TABLE blocks", "e").Header().Get(CacheHeader))
+	assert.Equal(t, "BYPASS", do("CHECK TABLE blocks", "f").Header().Get(CacheHeader))
+
+	assert.Equal(t, 5, upstreamCalls)
+}
+
+func TestClickHouseCacheKey(t *testing.T) {
+	key := func(query string, header http.Header, body string) string {
+		k, ok := cacheKey("xatu", url.Values{"query": []string{query}}, header, []byte(body))
+		require.True(t, ok)
+
+		return k
+	}
+
+	base := key("SELECT 1", http.Header{}, "")
+
+	format := http.Header{}
+	format.Set("X-ClickHouse-Format", "JSON")
+	assert.NotEqual(t, base, key("SELECT 1", format, ""), "output format header")
+
+	database := http.Header{}
+	database.Set("X-ClickHouse-Database", "other")
+	assert.NotEqual(t, base, key("SELECT 1", database, ""), "database header")
+
+	other := http.Header{}
+	other.Set("User-Agent", "test")
+	assert.Equal(t, base, key("SELECT 1", other, ""), "unrelated headers")
+
+	// ClickHouse joins the parameter and the body with a newline, so these
+	// are different queries.
+	assert.NotEqual(t, key("SELECT 1", http.Header{}, "0"), key("SELECT 10", http.Header{}, ""))
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "SELECT 1\nFROM t -- x\nWHERE a = 'x  y'", normalizeQuery("  SELECT   1 \n\n FROM t -- x\n WHERE a = 'x  y' ;"))
+	assert.Equal(t, `SELECT 'it\'s  here'`, normalizeQuery(`SELECT   'it\'s  here'`))
+	assert.True(t, isReadOnly("(SELECT 1) UNION ALL (SELECT 2)"))
+	assert.True(t, isReadOnly("with x as (select 1) select * from x"))
+	assert.False(t, isReadOnly("ALTER TABLE t DELETE WHERE 1"))
+}
+
+func TestResponseCacheEvicts(t *testing.T) {
+	c := newResponseCache(ClickHouseCacheConfig{TTL: time.Minute, MaxSizeBytes: 100})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	c.put(&cacheEntry{key: "a", body: make([]byte, 10)})
+	c.put(&cacheEntry{key: "big", body: make([]byte, 11)})
+
+	_, ok := c.get("big")
+	assert.False(t, ok, "entries over a tenth of the cache are skipped")
+
+	for _, key := range []string{"b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		c.put(&cacheEntry{key: key, body: make([]byte, 10)})
+	}
+
+	_, ok = c.get("a")
+	require.True(t, ok)
+
+	c.put(&cacheEntry{key: "k", body: make([]byte, 10)})
+
+	_, ok = c.get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+
+	_, ok = c.get("a")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok = c.get("a")
+	assert.False(t, ok, "expired entries are not served")
+}
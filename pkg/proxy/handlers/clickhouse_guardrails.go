@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxInspectedQueryBytes bounds the request bodies read for inspection.
+const maxInspectedQueryBytes = 1024 * 1024
+
+// ClickHouseGuardrails configures query inspection for a ClickHouse cluster.
+// Mutating statements are always rejected; the remaining checks apply when
+// set. Inspection is lexical, so ClickHouse's own limits stay the backstop.
+type ClickHouseGuardrails struct {
+	// MaxExecutionTime caps the max_execution_time setting, in seconds.
+	MaxExecutionTime int
+	// MaxRowsToRead caps the max_rows_to_read setting.
+	MaxRowsToRead int64
+	// RequiredFilters lists large tables that must be filtered on one of
+	// their partition columns.
+	RequiredFilters []ClickHouseRequiredFilter
+}
+
+// ClickHouseRequiredFilter requires queries reading Table to filter on at
+// least one of Columns in a WHERE or PREWHERE clause.
+type ClickHouseRequiredFilter struct {
+	Table   string
+	Columns []string
+}
+
+// mutatingKeywords are the statements the proxy never forwards.
+var mutatingKeywords = map[string]bool{
+	"INSERT": true, "ALTER": true, "DROP": true, "CREATE": true, "TRUNCATE": true,
+	"DELETE": true, "UPDATE": true, "RENAME": true, "OPTIMIZE": true, "ATTACH": true,
+	"DETACH": true, "EXCHANGE": true, "GRANT": true, "REVOKE": true, "KILL": true,
+	"SYSTEM": true,
+}
+
+// guardrailError is an inspection failure returned to the caller verbatim.
+type guardrailError struct {
+	status  int
+	reason  string
+	message string
+}
+
+func (e *guardrailError) Error() string {
+	return "clickhouse guardrail: " + e.message
+}
+
+// inspectQuery rejects requests that break the guardrails and caps the
+// cost settings in the request's query parameters. Queries whose SETTINGS
+// clause raises a capped setting past its limit are rejected, since
+// ClickHouse lets the clause override the parameters. The query may be in
+// the body, the "query" parameter, or split across both; ClickHouse joins
+// the two with a newline, and so does the inspection.
+func inspectQuery(r *http.Request, g ClickHouseGuardrails) *guardrailError {
+	var (
+		body      []byte
+		truncated bool
+	)
+
+	params := r.URL.Query()
+
+	if r.Body != nil && r.Body != http.NoBody {
+		// Compressed bodies can't be inspected as text.
+		if encoding := r.Header.Get("Content-Encoding"); (encoding != "" && !strings.EqualFold(encoding, "identity")) ||
+			params.Get("decompress") == "1" {
+			return &guardrailError{
+				status:  http.StatusUnsupportedMediaType,
+				reason:  "compressed",
+				message: "compressed queries cannot be validated; send the query uncompressed",
+			}
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxInspectedQueryBytes+1))
+		if err != nil {
+			return &guardrailError{
+				status:  http.StatusBadRequest,
+				reason:  "unreadable",
+				message: fmt.Sprintf("reading query: %v", err),
+			}
+		}
+
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+
+		if len(data) > maxInspectedQueryBytes {
+			data, truncated = data[:maxInspectedQueryBytes], true
+		}
+
+		body = data
+	}
+
+	tokens := sqlTokens(joinQuery(params.Get("query"), body))
+
+	for _, keyword := range statementKeywords(tokens) {
+		if mutatingKeywords[keyword] {
+			return &guardrailError{
+				status: http.StatusForbidden,
+				reason: "mutation",
+				message: fmt.Sprintf(
+					"%s statements are not allowed; the proxy only accepts read-only queries such as SELECT, SHOW and DESCRIBE",
+					keyword,
+				),
+			}
+		}
+	}
+
+	if len(g.RequiredFilters) > 0 {
+		if truncated {
+			return &guardrailError{
+				status:  http.StatusRequestEntityTooLarge,
+				reason:  "too_large",
+				message: fmt.Sprintf("queries larger than %d bytes cannot be validated; shorten the query", maxInspectedQueryBytes),
+			}
+		}
+
+		if err := checkRequiredFilters(tokens, g.RequiredFilters); err != nil {
+			return err
+		}
+	}
+
+	for _, limit := range []struct {
+		name  string
+		value int64
+	}{
+		{name: "max_execution_time", value: int64(g.MaxExecutionTime)},
+		{name: "max_rows_to_read", value: g.MaxRowsToRead},
+	} {
+		if err := checkQuerySetting(tokens, limit.name, limit.value); err != nil {
+			return err
+		}
+	}
+
+	timeCapped := capSetting(params, "max_execution_time", int64(g.MaxExecutionTime))
+	rowsCapped := capSetting(params, "max_rows_to_read", g.MaxRowsToRead)
+
+	if timeCapped || rowsCapped {
+		r.URL.RawQuery = params.Encode()
+	}
+
+	return nil
+}
+
+// checkRequiredFilters reports the first required-filter table the query
+// reads without filtering on one of its columns.
+func checkRequiredFilters(tokens []string, filters []ClickHouseRequiredFilter) *guardrailError {
+	tables := referencedTables(tokens)
+	filtered := filteredIdentifiers(tokens)
+
+	for _, filter := range filters {
+		if !tables[filter.Table] {
+			continue
+		}
+
+		ok := false
+
+		for _, column := range filter.Columns {
+			if filtered[column] {
+				ok = true
+
+				break
+			}
+		}
+
+		if !ok {
+			return &guardrailError{
+				status: http.StatusBadRequest,
+				reason: "missing_filter",
+				message: fmt.Sprintf(
+					"queries on %s must filter on %s in WHERE or PREWHERE, e.g. WHERE %s >= now() - INTERVAL 1 DAY",
+					filter.Table, strings.Join(filter.Columns, " or "), filter.Columns[0],
+				),
+			}
+		}
+	}
+
+	return nil
+}
+
+// capSetting sets params[name] to limit when it is missing, invalid or
+// larger, and reports whether it changed. A zero limit leaves it alone.
+func capSetting(params url.Values, name string, limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	if current, err := strconv.ParseInt(params.Get(name), 10, 64); err == nil && current > 0 && current <= limit {
+		return false
+	}
+
+	params.Set(name, strconv.FormatInt(limit, 10))
+
+	return true
+}
+
+// checkQuerySetting rejects a query that sets name above limit, or to a
+// value that isn't a positive integer, in a SETTINGS clause or SET
+// statement. A zero limit allows any value.
+func checkQuerySetting(tokens []string, name string, limit int64) *guardrailError {
+	if limit <= 0 {
+		return nil
+	}
+
+	inSettings := false
+
+	for i, token := range tokens {
+		if strings.EqualFold(token, "SETTINGS") || strings.EqualFold(token, "SET") {
+			inSettings = true
+
+			continue
+		}
+
+		if !inSettings || !strings.EqualFold(token, name) || i+1 >= len(tokens) || tokens[i+1] != "=" {
+			continue
+		}
+
+		value := ""
+		if i+2 < len(tokens) {
+			value = tokens[i+2]
+		}
+
+		if current, err := strconv.ParseInt(value, 10, 64); err == nil && current > 0 && current <= limit {
+			continue
+		}
+
+		return &guardrailError{
+			status: http.StatusBadRequest,
+			reason: "setting_override",
+			message: fmt.Sprintf(
+				"%s = %s exceeds the proxy limit of %d; lower it or drop it from the query's SETTINGS",
+				name, value, limit,
+			),
+		}
+	}
+
+	return nil
+}
+
+// sqlTokens splits a query into identifiers, keywords and punctuation,
+// dropping comments and string literals. Quoted identifiers are unquoted.
+func sqlTokens(query string) []string {
+	var tokens []string
+
+	for i := 0; i < len(query); {
+		ch := query[i]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			i++
+		case ch == '-' && strings.HasPrefix(query[i:], "--"), ch == '#':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+
+			i += end + 1
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+
+			i += end + 4
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := quotedEnd(query, i)
+			if ch != '\'' {
+				tokens = append(tokens, query[i+1:max(end-1, i+1)])
+			}
+
+			i = end
+		case isIdentChar(ch):
+			start := i
+			for i < len(query) && isIdentChar(query[i]) {
+				i++
+			}
+
+			tokens = append(tokens, query[start:i])
+		default:
+			tokens = append(tokens, string(ch))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// quotedEnd returns the index just past the quoted section starting at i.
+func quotedEnd(query string, i int) int {
+	quote := query[i]
+
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		}
+	}
+
+	return len(query)
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// joinQuery returns the query ClickHouse runs for a "query" parameter and a
+// body: the two joined by a newline.
+func joinQuery(param string, body []byte) string {
+	if param == "" {
+		return string(body)
+	}
+
+	if len(body) == 0 {
+		return param
+	}
+
+	return param + "\n" + string(body)
+}
+
+// statementKeywords returns the upper-cased first keyword of each
+// semicolon-separated statement, skipping leading parentheses.
+func statementKeywords(tokens []string) []string {
+	var (
+		keywords []string
+		start    = true
+	)
+
+	for _, token := range tokens {
+		switch {
+		case token == ";":
+			start = true
+		case start && token != "(":
+			keywords = append(keywords, strings.ToUpper(token))
+			start = false
+		}
+	}
+
+	return keywords
+}
+
+// referencedTables returns the table names that follow FROM or JOIN,
+// without their database qualifier.
+func referencedTables(tokens []string) map[string]bool {
+	tables := make(map[string]bool, 4)
+
+	for i := 0; i < len(tokens)-1; i++ {
+		if !strings.EqualFold(tokens[i], "FROM") && !strings.EqualFold(tokens[i], "JOIN") {
+			continue
+		}
+
+		name := tokens[i+1]
+		if i+3 < len(tokens) && tokens[i+2] == "." {
+			name = tokens[i+3]
+		}
+
+		tables[name] = true
+	}
+
+	return tables
+}
+
+// filteredIdentifiers returns the identifiers that appear after a WHERE or
+// PREWHERE keyword.
+func filteredIdentifiers(tokens []string) map[string]bool {
+	identifiers := make(map[string]bool, 8)
+	inFilter := false
+
+	for _, token := range tokens {
+		if strings.EqualFold(token, "WHERE") || strings.EqualFold(token, "PREWHERE") {
+			inFilter = true
+
+			continue
+		}
+
+		if inFilter {
+			identifiers[token] = true
+		}
+	}
+
+	return identifiers
+}
+
+var clickhouseGuardrailRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "panda",
+		Subsystem: "proxy",
+		Name:      "clickhouse_guardrail_rejections_total",
+		Help:      "Total number of ClickHouse queries rejected by the proxy guardrails, by datasource and reason",
+	},
+	[]string{"datasource", "reason"},
+)
+
+func init() {
+	prometheus.MustRegister(clickhouseGuardrailRejections)
+}
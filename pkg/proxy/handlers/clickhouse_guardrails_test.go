@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseGuardrails(t *testing.T) {
+	var upstreamQuery url.Values
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamQuery = r.URL.Query()
+
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{{
+		Name: "xatu",
+		Host: u.Hostname(),
+		Port: port,
+		Guardrails: ClickHouseGuardrails{
+			MaxExecutionTime: 60,
+			MaxRowsToRead:    1000000,
+			RequiredFilters: []ClickHouseRequiredFilter{
+				{Table: "beacon_api_eth_v1_events_block", Columns: []string{"slot_start_date_time", "meta_network_name"}},
+			},
+		},
+	}}, nil)
+
+	do := func(target, sql string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(sql))
+		req.Header.Set(DatasourceHeader, "xatu")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		sql    string
+		status int
+		errMsg string
+	}{
+		{name: "select", target: "/clickhouse/", sql: "SELECT 1", status: http.StatusOK},
+		{name: "insert", target: "/clickhouse/", sql: "insert into t values (1)", status: http.StatusForbidden, errMsg: "INSERT statements are not allowed"},
+		{name: "drop in query param", target: "/clickhouse/?query=DROP+TABLE+t", status: http.StatusForbidden, errMsg: "DROP"},
+		{name: "insert after comment in query param", target: "/clickhouse/?query=--+x", sql: "INSERT INTO t VALUES (1)", status: http.StatusForbidden, errMsg: "INSERT"},
+		{name: "insert split across query param and body", target: "/clickhouse/?query=INSERT", sql: "INTO t VALUES (1)", status: http.StatusForbidden, errMsg: "INSERT"},
+		{name: "drop as second statement", target: "/clickhouse/", sql: "SELECT 1; DROP TABLE t", status: http.StatusForbidden, errMsg: "DROP"},
+		{name: "alter after comment", target: "/clickhouse/", sql: "-- cleanup\n/* x */ ALTER TABLE t DELETE WHERE 1", status: http.StatusForbidden, errMsg: "ALTER"},
+		{
+			name:   "large table without filter",
+			target: "/clickhouse/",
+			sql:    "SELECT count() FROM default.beacon_api_eth_v1_events_block",
+			status: http.StatusBadRequest,
+			errMsg: "must filter on slot_start_date_time or meta_network_name",
+		},
+		{
+			name:   "filter column only in select list",
+			target: "/clickhouse/",
+			sql:    "SELECT slot_start_date_time FROM beacon_api_eth_v1_events_block WHERE slot > 1",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "filter column in string literal",
+			target: "/clickhouse/",
+			sql:    "SELECT 1 FROM beacon_api_eth_v1_events_block WHERE x = 'slot_start_date_time'",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "large table with partition filter",
+			target: "/clickhouse/",
+			sql:    "SELECT count() FROM `beacon_api_eth_v1_events_block` WHERE slot_start_date_time > now() - INTERVAL 1 HOUR",
+			status: http.StatusOK,
+		},
+		{
+			name:   "large table with prewhere on network",
+			target: "/clickhouse/",
+			sql:    "SELECT count() FROM beacon_api_eth_v1_events_block PREWHERE meta_network_name = 'mainnet'",
+			status: http.StatusOK,
+		},
+		{
+			name:   "settings clause within limits",
+			target: "/clickhouse/",
+			sql:    "SELECT 1 SETTINGS max_execution_time = 30, max_rows_to_read = 1000",
+			status: http.StatusOK,
+		},
+		{
+			name:   "settings clause raises execution time",
+			target: "/clickhouse/?max_execution_time=10",
+			sql:    "SELECT 1 SETTINGS max_execution_time=3600",
+			status: http.StatusBadRequest,
+			errMsg: "max_execution_time = 3600 exceeds the proxy limit of 60",
+		},
+		{
+			name:   "settings clause removes row limit",
+			target: "/clickhouse/",
+			sql:    "SELECT 1 SETTINGS max_threads = 4, max_rows_to_read = 0",
+			status: http.StatusBadRequest,
+			errMsg: "max_rows_to_read = 0 exceeds the proxy limit of 1000000",
+		},
+		{
+			name:   "set statement",
+			target: "/clickhouse/",
+			sql:    "SET max_rows_to_read = 99999999999",
+			status: http.StatusBadRequest,
+			errMsg: "max_rows_to_read",
+		},
+		{
+			name:   "setting name as column",
+			target: "/clickhouse/",
+			sql:    "SELECT max_rows_to_read FROM t WHERE max_rows_to_read = 99999999999",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(tt.target, tt.sql)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+
+			if tt.errMsg != "" {
+				assert.Contains(t, rec.Body.String(), tt.errMsg)
+			}
+		})
+	}
+
+	require.Equal(t, http.StatusOK, do("/clickhouse/", "SELECT 1").Code)
+	assert.Equal(t, "60", upstreamQuery.Get("max_execution_time"))
+	assert.Equal(t, "1000000", upstreamQuery.Get("max_rows_to_read"))
+
+	require.Equal(t, http.StatusOK, do("/clickhouse/?max_execution_time=10&max_rows_to_read=5000000", "SELECT 1").Code)
+	assert.Equal(t, "10", upstreamQuery.Get("max_execution_time"), "lower limits are kept")
+	assert.Equal(t, "1000000", upstreamQuery.Get("max_rows_to_read"), "higher limits are capped")
+}
+
+func TestClickHouseGuardrailsRejectCompressedQueries(t *testing.T) {
+	h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{{Name: "xatu", Host: "127.0.0.1", Port: 1}}, nil)
+
+	for name, setup := range map[string]func(*http.Request){
+		"content encoding": func(r *http.Request) { r.Header.Set("Content-Encoding", "gzip") },
+		"decompress param": func(r *http.Request) { r.URL.RawQuery = "decompress=1" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/clickhouse/", strings.NewReader("\x1f\x8b compressed"))
+			req.Header.Set(DatasourceHeader, "xatu")
+			setup(req)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		})
+	}
+}
+
+func TestClickHouseGuardrailsRejectMutationsWithoutConfig(t *testing.T) {
+	h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{{Name: "xatu", Host: "127.0.0.1", Port: 1}}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/clickhouse/", strings.NewReader("TRUNCATE TABLE blocks"))
+	req.Header.Set(DatasourceHeader, "xatu")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
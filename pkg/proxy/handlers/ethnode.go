@@ -21,6 +21,12 @@ var validSegment = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$|^[a-z0-9]$`)
 type EthNodeConfig struct {
 	Username string
 	Password string
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
 }
 
 // EthNodeHandler proxies requests to Ethereum beacon and execution nodes.
@@ -31,6 +37,8 @@ type EthNodeHandler struct {
 	cfg    EthNodeConfig
 	mu     sync.RWMutex
 	proxes map[string]*httputil.ReverseProxy
+
+	transport http.RoundTripper
 }
 
 // NewEthNodeHandler creates a new Ethereum node handler.
@@ -39,6 +47,8 @@ func NewEthNodeHandler(log logrus.FieldLogger, cfg EthNodeConfig) *EthNodeHandle
 		log:    log.WithField("handler", "ethnode"),
 		cfg:    cfg,
 		proxes: make(map[string]*httputil.ReverseProxy, 16),
+
+		transport: newUpstreamTransport("ethnode", "ethnode", false, cfg.Transport),
 	}
 }
 
@@ -149,7 +159,8 @@ func (h *EthNodeHandler) getOrCreateProxy(host string) *httputil.ReverseProxy {
 
 	rp := httputil.NewSingleHostReverseProxy(targetURL)
 
-	rp.Transport = newProxyTransport(false)
+	// All nodes share one connection pool.
+	rp.Transport = h.transport
 
 	cfg := h.cfg
 	originalDirector := rp.Director
@@ -169,9 +180,11 @@ func (h *EthNodeHandler) getOrCreateProxy(host string) *httputil.ReverseProxy {
 		req.Header.Del("Host")
 	}
 
+	limit := responseLimit{handler: "ethnode", datasource: "ethnode", maxBytes: cfg.MaxResponseBytes}
+	rp.ModifyResponse = limit.apply
+
 	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
-		h.log.WithError(err).WithField("upstream", host).Error("Proxy error")
-		http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		writeProxyError(w, h.log.WithField("upstream", host), err)
 	}
 
 	h.proxes[host] = rp
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitHubURL is the GitHub REST API base URL.
+const DefaultGitHubURL = "https://api.github.com"
+
+// GitHubConfig holds GitHub API access configuration. A single token is used
+// for every request; only the listed repositories can be read.
+type GitHubConfig struct {
+	URL     string
+	Token   string
+	Repos   []string
+	Timeout int
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
+}
+
+// githubRepoRoutes are the per-repository API routes the proxy forwards,
+// relative to /repos/{owner}/{repo}. Entries ending in "/" match as
+// prefixes, all others must match exactly.
+var githubRepoRoutes = []string{
+	"",
+	"/releases",
+	"/releases/",
+	"/issues",
+	"/issues/",
+	"/pulls",
+	"/pulls/",
+	"/tags",
+}
+
+// GitHubHandler proxies read-only requests to the GitHub REST API for a
+// configured set of repositories.
+type GitHubHandler struct {
+	log   logrus.FieldLogger
+	cfg   GitHubConfig
+	repos map[string]struct{}
+	proxy *httputil.ReverseProxy
+}
+
+// NewGitHubHandler creates a new GitHub handler.
+func NewGitHubHandler(log logrus.FieldLogger, cfg GitHubConfig) *GitHubHandler {
+	if cfg.URL == "" {
+		cfg.URL = DefaultGitHubURL
+	}
+
+	h := &GitHubHandler{
+		log:   log.WithField("handler", "github"),
+		cfg:   cfg,
+		repos: make(map[string]struct{}, len(cfg.Repos)),
+	}
+
+	for _, repo := range cfg.Repos {
+		h.repos[strings.ToLower(repo)] = struct{}{}
+	}
+
+	targetURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to parse URL")
+
+		return h
+	}
+
+	rp := httputil.NewSingleHostReverseProxy(targetURL)
+
+	rp.Transport = newUpstreamTransport("github", "github", false, cfg.Transport)
+
+	originalDirector := rp.Director
+	rp.Director = func(req *http.Request) {
+		originalDirector(req)
+
+		// Remove the sandbox's Authorization header (Bearer token) and the
+		// routing header before adding our own.
+		req.Header.Del("Authorization")
+		req.Header.Del(DatasourceHeader)
+
+		if cfg.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+cfg.Token)
+		}
+
+		if req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", "application/vnd.github+json")
+		}
+
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+		req.Host = req.URL.Host
+		req.Header.Del("Host")
+	}
+
+	limit := responseLimit{handler: "github", datasource: "github", maxBytes: cfg.MaxResponseBytes}
+	rp.ModifyResponse = limit.apply
+
+	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		writeProxyError(w, h.log, err)
+	}
+
+	h.proxy = rp
+
+	return h
+}
+
+// ServeHTTP handles GitHub requests. Paths are relative to the API root,
+// prefixed with /github.
+func (h *GitHubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.proxy == nil {
+		http.Error(w, "github not properly configured", http.StatusInternalServerError)
+
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/github")
+	if path == "" {
+		path = "/"
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, fmt.Sprintf("github route not allowed: %s %s", r.Method, path), http.StatusForbidden)
+
+		return
+	}
+
+	if err := h.checkRoute(path, r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+
+		return
+	}
+
+	r.URL.Path = path
+	r.URL.RawPath = ""
+
+	if h.cfg.Timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(r.Context(), time.Duration(h.cfg.Timeout)*time.Second)
+		defer cancel()
+
+		r = r.WithContext(timeoutCtx)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"path":   path,
+		"method": r.Method,
+	}).Debug("Proxying GitHub request")
+
+	h.proxy.ServeHTTP(w, r)
+}
+
+// Repos returns the configured repositories.
+func (h *GitHubHandler) Repos() []string {
+	return append([]string(nil), h.cfg.Repos...)
+}
+
+// checkRoute allows per-repository reads of configured repositories and
+// issue searches scoped to them with repo: qualifiers.
+func (h *GitHubHandler) checkRoute(path string, query url.Values) error {
+	if strings.Contains(path, "..") {
+		return fmt.Errorf("github route not allowed: %s", path)
+	}
+
+	if path == "/search/issues" {
+		return h.checkSearchQuery(query.Get("q"))
+	}
+
+	rest, ok := strings.CutPrefix(path, "/repos/")
+	if !ok {
+		return fmt.Errorf("github route not allowed: %s", path)
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("github route not allowed: %s", path)
+	}
+
+	repo := parts[0] + "/" + parts[1]
+	if !h.repoAllowed(repo) {
+		return fmt.Errorf("github repository not configured: %s", repo)
+	}
+
+	sub := ""
+	if len(parts) == 3 {
+		sub = "/" + parts[2]
+	}
+
+	for _, route := range githubRepoRoutes {
+		if sub == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(sub, route)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("github route not allowed: %s", path)
+}
+
+// checkSearchQuery requires at least one repo: qualifier and that every one
+// names a configured repository. Qualifiers that widen the scope to whole
+// accounts are rejected, since GitHub ORs them with repo: qualifiers.
+func (h *GitHubHandler) checkSearchQuery(q string) error {
+	found := false
+
+	for _, term := range strings.Fields(q) {
+		term = strings.ToLower(term)
+
+		for _, qualifier := range []string{"org:", "user:", "owner:"} {
+			if strings.HasPrefix(term, qualifier) {
+				return fmt.Errorf("github issue searches may not use %s qualifiers", strings.TrimSuffix(qualifier, ":"))
+			}
+		}
+
+		repo, ok := strings.CutPrefix(term, "repo:")
+		if !ok {
+			continue
+		}
+
+		if !h.repoAllowed(repo) {
+			return fmt.Errorf("github repository not configured: %s", repo)
+		}
+
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("github issue searches must be scoped with repo: qualifiers")
+	}
+
+	return nil
+}
+
+func (h *GitHubHandler) repoAllowed(repo string) bool {
+	_, ok := h.repos[strings.ToLower(repo)]
+
+	return ok
+}
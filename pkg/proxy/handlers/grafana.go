@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// GrafanaConfig holds Grafana proxy configuration for a single instance.
+type GrafanaConfig struct {
+	Name        string
+	Description string
+	URL         string
+	APIKey      string
+	Username    string
+	Password    string
+	SkipVerify  bool
+	Timeout     int
+
+	// QueryTags tags upstream requests with the user and execution
+	// they were made for.
+	QueryTags bool
+
+	// Resilience configures retries and the circuit breaker.
+	Resilience Resilience
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
+}
+
+// grafanaRoutes is the read-only subset of the Grafana HTTP API reachable
+// through the proxy. The service account token could do far more, so
+// anything not listed here is rejected.
+var grafanaRoutes = []Route{
+	{Method: http.MethodGet, Path: "/api/search"},
+	{Method: http.MethodGet, Path: "/api/dashboards/uid/"},
+	{Method: http.MethodGet, Path: "/render/d-solo/"},
+	{Method: http.MethodPost, Path: "/api/ds/query"},
+}
+
+// GrafanaHandler handles requests to Grafana instances.
+type GrafanaHandler struct {
+	log       logrus.FieldLogger
+	instances map[string]*grafanaInstance
+}
+
+type grafanaInstance struct {
+	cfg   GrafanaConfig
+	proxy *httputil.ReverseProxy
+}
+
+// NewGrafanaHandler creates a new Grafana handler.
+func NewGrafanaHandler(log logrus.FieldLogger, configs []GrafanaConfig) *GrafanaHandler {
+	h := &GrafanaHandler{
+		log:       log.WithField("handler", "grafana"),
+		instances: make(map[string]*grafanaInstance, len(configs)),
+	}
+
+	for _, cfg := range configs {
+		h.instances[cfg.Name] = h.createInstance(cfg)
+	}
+
+	return h
+}
+
+func (h *GrafanaHandler) createInstance(cfg GrafanaConfig) *grafanaInstance {
+	targetURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		h.log.WithError(err).WithField("instance", cfg.Name).Error("Failed to parse URL")
+
+		return nil
+	}
+
+	rp := httputil.NewSingleHostReverseProxy(targetURL)
+
+	rp.Transport = newResilientTransport(
+		newUpstreamTransport("grafana", cfg.Name, cfg.SkipVerify, cfg.Transport), "grafana", cfg.Name, cfg.Resilience,
+	)
+
+	originalDirector := rp.Director
+	rp.Director = func(req *http.Request) {
+		originalDirector(req)
+
+		// Remove the sandbox's Authorization header (Bearer token) before adding our own.
+		req.Header.Del("Authorization")
+
+		// Prefer a service account token, fall back to basic auth.
+		switch {
+		case cfg.APIKey != "":
+			req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+		case cfg.Username != "":
+			req.SetBasicAuth(cfg.Username, cfg.Password)
+		}
+
+		req.Host = req.URL.Host
+		req.Header.Del("Host")
+
+		if cfg.QueryTags {
+			GetRequestIdentity(req.Context()).setHeaders(req.Header)
+		}
+	}
+
+	limit := responseLimit{handler: "grafana", datasource: cfg.Name, maxBytes: cfg.MaxResponseBytes}
+	rp.ModifyResponse = limit.apply
+
+	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		writeProxyError(w, h.log.WithField("instance", cfg.Name), err)
+	}
+
+	return &grafanaInstance{
+		cfg:   cfg,
+		proxy: rp,
+	}
+}
+
+// ServeHTTP handles Grafana requests. The instance is specified via X-Datasource header.
+func (h *GrafanaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	instanceName := r.Header.Get(DatasourceHeader)
+	if instanceName == "" {
+		http.Error(w, fmt.Sprintf("missing %s header", DatasourceHeader), http.StatusBadRequest)
+
+		return
+	}
+
+	instance, ok := h.instances[instanceName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown instance: %s", instanceName), http.StatusNotFound)
+
+		return
+	}
+
+	if instance == nil {
+		http.Error(w, fmt.Sprintf("instance %s not properly configured", instanceName), http.StatusInternalServerError)
+
+		return
+	}
+
+	// Strip /grafana prefix from path, keep the rest for the upstream.
+	path := strings.TrimPrefix(r.URL.Path, "/grafana")
+	if path == "" {
+		path = "/"
+	}
+
+	if !routeAllowed(grafanaRoutes, r.Method, path) {
+		http.Error(w, fmt.Sprintf("grafana route not allowed: %s %s", r.Method, path), http.StatusForbidden)
+
+		return
+	}
+
+	r.URL.Path = path
+
+	if instance.cfg.Timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(r.Context(), time.Duration(instance.cfg.Timeout)*time.Second)
+		defer cancel()
+
+		r = r.WithContext(timeoutCtx)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"instance": instanceName,
+		"path":     path,
+		"method":   r.Method,
+	}).Debug("Proxying Grafana request")
+
+	instance.proxy.ServeHTTP(w, r)
+}
+
+// Instances returns the list of configured instance names.
+func (h *GrafanaHandler) Instances() []string {
+	names := make([]string, 0, len(h.instances))
+	for name := range h.instances {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// CircuitBreakers returns the circuit breaker state of each instance that has
+// one.
+func (h *GrafanaHandler) CircuitBreakers() map[string]*types.CircuitBreakerStatus {
+	states := make(map[string]*types.CircuitBreakerStatus, len(h.instances))
+
+	for name, instance := range h.instances {
+		if instance == nil {
+			continue
+		}
+
+		if status := breakerStatus(instance.proxy.Transport); status != nil {
+			states[name] = status
+		}
+	}
+
+	return states
+}
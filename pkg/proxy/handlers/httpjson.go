@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// HTTPJSONConfig holds proxy configuration for a generic JSON HTTP endpoint.
+type HTTPJSONConfig struct {
+	Name         string
+	Description  string
+	URL          string
+	Headers      map[string]string
+	AllowedPaths []string
+	SkipVerify   bool
+	Timeout      int
+
+	// Resilience configures retries and the circuit breaker.
+	Resilience Resilience
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
+}
+
+// HTTPJSONHandler handles read-only requests to operator-declared JSON HTTP
+// services. Each endpoint only exposes the paths listed in its config.
+type HTTPJSONHandler struct {
+	log       logrus.FieldLogger
+	endpoints map[string]*httpJSONEndpoint
+}
+
+type httpJSONEndpoint struct {
+	cfg   HTTPJSONConfig
+	proxy *httputil.ReverseProxy
+}
+
+// NewHTTPJSONHandler creates a new HTTP JSON handler.
+func NewHTTPJSONHandler(log logrus.FieldLogger, configs []HTTPJSONConfig) *HTTPJSONHandler {
+	h := &HTTPJSONHandler{
+		log:       log.WithField("handler", "httpjson"),
+		endpoints: make(map[string]*httpJSONEndpoint, len(configs)),
+	}
+
+	for _, cfg := range configs {
+		h.endpoints[cfg.Name] = h.createEndpoint(cfg)
+	}
+
+	return h
+}
+
+func (h *HTTPJSONHandler) createEndpoint(cfg HTTPJSONConfig) *httpJSONEndpoint {
+	targetURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		h.log.WithError(err).WithField("endpoint", cfg.Name).Error("Failed to parse URL")
+
+		return nil
+	}
+
+	rp := httputil.NewSingleHostReverseProxy(targetURL)
+
+	rp.Transport = newResilientTransport(
+		newUpstreamTransport("httpjson", cfg.Name, cfg.SkipVerify, cfg.Transport), "httpjson", cfg.Name, cfg.Resilience,
+	)
+
+	originalDirector := rp.Director
+	rp.Director = func(req *http.Request) {
+		originalDirector(req)
+
+		// Remove the sandbox's Authorization header (Bearer token) and the
+		// routing header before adding the configured ones.
+		req.Header.Del("Authorization")
+		req.Header.Del(DatasourceHeader)
+
+		for key, value := range cfg.Headers {
+			req.Header.Set(key, value)
+		}
+
+		req.Host = req.URL.Host
+		req.Header.Del("Host")
+	}
+
+	limit := responseLimit{handler: "httpjson", datasource: cfg.Name, maxBytes: cfg.MaxResponseBytes}
+	rp.ModifyResponse = limit.apply
+
+	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		writeProxyError(w, h.log.WithField("endpoint", cfg.Name), err)
+	}
+
+	return &httpJSONEndpoint{
+		cfg:   cfg,
+		proxy: rp,
+	}
+}
+
+// ServeHTTP handles HTTP JSON requests. The endpoint is specified via X-Datasource header.
+func (h *HTTPJSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpointName := r.Header.Get(DatasourceHeader)
+	if endpointName == "" {
+		http.Error(w, fmt.Sprintf("missing %s header", DatasourceHeader), http.StatusBadRequest)
+
+		return
+	}
+
+	endpoint, ok := h.endpoints[endpointName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown endpoint: %s", endpointName), http.StatusNotFound)
+
+		return
+	}
+
+	if endpoint == nil {
+		http.Error(w, fmt.Sprintf("endpoint %s not properly configured", endpointName), http.StatusInternalServerError)
+
+		return
+	}
+
+	// Strip /httpjson prefix from path, keep the rest for the upstream.
+	path := strings.TrimPrefix(r.URL.Path, "/httpjson")
+	if path == "" {
+		path = "/"
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, fmt.Sprintf("httpjson only supports GET, got %s", r.Method), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if !HTTPJSONPathAllowed(endpoint.cfg.AllowedPaths, path) {
+		http.Error(w, fmt.Sprintf("path not allowed for endpoint %s: %s", endpointName, path), http.StatusForbidden)
+
+		return
+	}
+
+	r.URL.Path = path
+	r.URL.RawPath = ""
+
+	if endpoint.cfg.Timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(r.Context(), time.Duration(endpoint.cfg.Timeout)*time.Second)
+		defer cancel()
+
+		r = r.WithContext(timeoutCtx)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"endpoint": endpointName,
+		"path":     path,
+	}).Debug("Proxying HTTP JSON request")
+
+	endpoint.proxy.ServeHTTP(w, r)
+}
+
+// Instances returns the list of configured endpoint names.
+func (h *HTTPJSONHandler) Instances() []string {
+	names := make([]string, 0, len(h.endpoints))
+	for name := range h.endpoints {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// CircuitBreakers returns the circuit breaker state of each endpoint that has
+// one.
+func (h *HTTPJSONHandler) CircuitBreakers() map[string]*types.CircuitBreakerStatus {
+	states := make(map[string]*types.CircuitBreakerStatus, len(h.endpoints))
+
+	for name, endpoint := range h.endpoints {
+		if endpoint == nil {
+			continue
+		}
+
+		if status := breakerStatus(endpoint.proxy.Transport); status != nil {
+			states[name] = status
+		}
+	}
+
+	return states
+}
+
+// HTTPJSONPathAllowed reports whether path is reachable under allowed.
+// Entries ending in "/" match as prefixes, all others must match exactly.
+func HTTPJSONPathAllowed(allowed []string, path string) bool {
+	if !strings.HasPrefix(path, "/") || strings.Contains(path, "..") {
+		return false
+	}
+
+	for _, entry := range allowed {
+		if path == entry || (strings.HasSuffix(entry, "/") && strings.HasPrefix(path, entry)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // Note: DatasourceHeader is defined in clickhouse.go
@@ -23,6 +26,22 @@ type LokiConfig struct {
 	Password    string
 	SkipVerify  bool
 	Timeout     int
+
+	// QueryTags tags upstream requests with the user and execution
+	// they were made for.
+	QueryTags bool
+
+	// Resilience configures retries and the circuit breaker.
+	Resilience Resilience
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// AllowedRoutes extends the built-in read-only route allowlist.
+	AllowedRoutes []Route
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
 }
 
 // LokiHandler handles requests to Loki instances.
@@ -32,8 +51,9 @@ type LokiHandler struct {
 }
 
 type lokiInstance struct {
-	cfg   LokiConfig
-	proxy *httputil.ReverseProxy
+	cfg    LokiConfig
+	proxy  *httputil.ReverseProxy
+	routes []Route
 }
 
 // NewLokiHandler creates a new Loki handler.
@@ -61,7 +81,9 @@ func (h *LokiHandler) createInstance(cfg LokiConfig) *lokiInstance {
 	// Create reverse proxy.
 	rp := httputil.NewSingleHostReverseProxy(targetURL)
 
-	rp.Transport = newProxyTransport(cfg.SkipVerify)
+	rp.Transport = newResilientTransport(
+		newUpstreamTransport("loki", cfg.Name, cfg.SkipVerify, cfg.Transport), "loki", cfg.Name, cfg.Resilience,
+	)
 
 	// Customize the director to add auth.
 	originalDirector := rp.Director
@@ -82,17 +104,34 @@ func (h *LokiHandler) createInstance(cfg LokiConfig) *lokiInstance {
 
 		// Also delete any existing Host header to avoid conflicts.
 		req.Header.Del("Host")
+
+		// Ask for gzip so large results cross the network compressed; the
+		// response limit decodes them and re-encodes for the client.
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		// Tag the query with who it is for, for Loki's query stats.
+		if cfg.QueryTags {
+			id := GetRequestIdentity(req.Context())
+			id.setHeaders(req.Header)
+
+			if tags := id.lokiQueryTags(); tags != "" {
+				req.Header.Set("X-Query-Tags", tags)
+			}
+		}
 	}
 
+	limit := responseLimit{handler: "loki", datasource: cfg.Name, maxBytes: cfg.MaxResponseBytes, decodeGzip: true}
+	rp.ModifyResponse = limit.apply
+
 	// Error handler.
 	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
-		h.log.WithError(err).WithField("instance", cfg.Name).Error("Proxy error")
-		http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		writeProxyError(w, h.log.WithField("instance", cfg.Name), err)
 	}
 
 	return &lokiInstance{
-		cfg:   cfg,
-		proxy: rp,
+		cfg:    cfg,
+		proxy:  rp,
+		routes: append(slices.Clip(lokiRoutes), cfg.AllowedRoutes...),
 	}
 }
 
@@ -125,6 +164,12 @@ func (h *LokiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		path = "/"
 	}
 
+	if !routeAllowed(instance.routes, r.Method, path) {
+		http.Error(w, fmt.Sprintf("loki route not allowed: %s %s", r.Method, path), http.StatusForbidden)
+
+		return
+	}
+
 	r.URL.Path = path
 
 	if instance.cfg.Timeout > 0 {
@@ -140,7 +185,7 @@ func (h *LokiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"method":   r.Method,
 	}).Debug("Proxying Loki request")
 
-	instance.proxy.ServeHTTP(w, r)
+	instance.proxy.ServeHTTP(w, withClientEncoding(r))
 }
 
 // Instances returns the list of configured instance names.
@@ -152,3 +197,21 @@ func (h *LokiHandler) Instances() []string {
 
 	return names
 }
+
+// CircuitBreakers returns the circuit breaker state of each instance that has
+// one.
+func (h *LokiHandler) CircuitBreakers() map[string]*types.CircuitBreakerStatus {
+	states := make(map[string]*types.CircuitBreakerStatus, len(h.instances))
+
+	for name, instance := range h.instances {
+		if instance == nil {
+			continue
+		}
+
+		if status := breakerStatus(instance.proxy.Transport); status != nil {
+			states[name] = status
+		}
+	}
+
+	return states
+}
@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // Note: DatasourceHeader is defined in clickhouse.go
@@ -23,6 +26,23 @@ type PrometheusConfig struct {
 	Password    string
 	SkipVerify  bool
 	Timeout     int
+	Downsample  PrometheusDownsample
+
+	// QueryTags tags upstream requests with the user and execution
+	// they were made for.
+	QueryTags bool
+
+	// Resilience configures retries and the circuit breaker.
+	Resilience Resilience
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportTuning
+
+	// AllowedRoutes extends the built-in read-only route allowlist.
+	AllowedRoutes []Route
+
+	// MaxResponseBytes caps the decoded response body; 0 is unlimited.
+	MaxResponseBytes int64
 }
 
 // PrometheusHandler handles requests to Prometheus instances.
@@ -32,8 +52,9 @@ type PrometheusHandler struct {
 }
 
 type prometheusInstance struct {
-	cfg   PrometheusConfig
-	proxy *httputil.ReverseProxy
+	cfg    PrometheusConfig
+	proxy  *httputil.ReverseProxy
+	routes []Route
 }
 
 // NewPrometheusHandler creates a new Prometheus handler.
@@ -61,7 +82,9 @@ func (h *PrometheusHandler) createInstance(cfg PrometheusConfig) *prometheusInst
 	// Create reverse proxy.
 	rp := httputil.NewSingleHostReverseProxy(targetURL)
 
-	rp.Transport = newProxyTransport(cfg.SkipVerify)
+	rp.Transport = newResilientTransport(
+		newUpstreamTransport("prometheus", cfg.Name, cfg.SkipVerify, cfg.Transport), "prometheus", cfg.Name, cfg.Resilience,
+	)
 
 	// Customize the director to add auth.
 	originalDirector := rp.Director
@@ -82,17 +105,40 @@ func (h *PrometheusHandler) createInstance(cfg PrometheusConfig) *prometheusInst
 
 		// Also delete any existing Host header to avoid conflicts.
 		req.Header.Del("Host")
+
+		if cfg.QueryTags {
+			GetRequestIdentity(req.Context()).setHeaders(req.Header)
+		}
+
+		// Let the transport negotiate compression so range query
+		// responses arrive decoded for downsampling.
+		if cfg.Downsample.enabled() && isRangeQuery(req.URL.Path) {
+			req.Header.Del("Accept-Encoding")
+		}
+	}
+
+	limit := responseLimit{handler: "prometheus", datasource: cfg.Name, maxBytes: cfg.MaxResponseBytes}
+	rp.ModifyResponse = func(resp *http.Response) error {
+		if err := limit.apply(resp); err != nil {
+			return err
+		}
+
+		if !cfg.Downsample.enabled() || resp.Request == nil || !isRangeQuery(resp.Request.URL.Path) {
+			return nil
+		}
+
+		return downsampleResponse(resp, cfg.Downsample, cfg.Name)
 	}
 
 	// Error handler.
 	rp.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
-		h.log.WithError(err).WithField("instance", cfg.Name).Error("Proxy error")
-		http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+		writeProxyError(w, h.log.WithField("instance", cfg.Name), err)
 	}
 
 	return &prometheusInstance{
-		cfg:   cfg,
-		proxy: rp,
+		cfg:    cfg,
+		proxy:  rp,
+		routes: append(slices.Clip(prometheusRoutes), cfg.AllowedRoutes...),
 	}
 }
 
@@ -125,6 +171,12 @@ func (h *PrometheusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		path = "/"
 	}
 
+	if !routeAllowed(instance.routes, r.Method, path) {
+		http.Error(w, fmt.Sprintf("prometheus route not allowed: %s %s", r.Method, path), http.StatusForbidden)
+
+		return
+	}
+
 	r.URL.Path = path
 
 	if instance.cfg.Timeout > 0 {
@@ -152,3 +204,21 @@ func (h *PrometheusHandler) Instances() []string {
 
 	return names
 }
+
+// CircuitBreakers returns the circuit breaker state of each instance that has
+// one.
+func (h *PrometheusHandler) CircuitBreakers() map[string]*types.CircuitBreakerStatus {
+	states := make(map[string]*types.CircuitBreakerStatus, len(h.instances))
+
+	for name, instance := range h.instances {
+		if instance == nil {
+			continue
+		}
+
+		if status := breakerStatus(instance.proxy.Transport); status != nil {
+			states[name] = status
+		}
+	}
+
+	return states
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DownsampledHeader is set on range query responses the proxy reduced. Its
+// value describes what was dropped.
+const DownsampledHeader = "X-Panda-Downsampled"
+
+// maxDownsampledResponseBytes bounds the responses decoded for downsampling.
+// Larger responses pass through untouched.
+const maxDownsampledResponseBytes = 256 * 1024 * 1024
+
+// PrometheusDownsample limits matrix results of range queries. Zero values
+// leave that dimension unlimited.
+type PrometheusDownsample struct {
+	// MaxPointsPerSeries thins each series to at most this many points by
+	// keeping every n-th sample.
+	MaxPointsPerSeries int
+	// MaxSeries keeps only the first MaxSeries series.
+	MaxSeries int
+}
+
+func (d PrometheusDownsample) enabled() bool {
+	return d.MaxPointsPerSeries > 0 || d.MaxSeries > 0
+}
+
+// isRangeQuery reports whether path is the Prometheus range query API.
+func isRangeQuery(path string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(path, "/"), "/api/v1/query_range")
+}
+
+// downsampleResponse rewrites a successful range query response to fit d,
+// adding a Prometheus warning and the DownsampledHeader when it drops data.
+func downsampleResponse(resp *http.Response, d PrometheusDownsample, instance string) error {
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+
+	if resp.ContentLength > maxDownsampledResponseBytes {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownsampledResponseBytes+1))
+	if err != nil {
+		return fmt.Errorf("reading range query response: %w", err)
+	}
+
+	if len(data) > maxDownsampledResponseBytes {
+		// Too large to buffer; stream what was read followed by the rest.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+
+		return nil
+	}
+
+	_ = resp.Body.Close()
+
+	rewritten, note := downsampleMatrix(data, d)
+	if note == "" {
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+
+		return nil
+	}
+
+	prometheusDownsampledTotal.WithLabelValues(instance).Inc()
+
+	resp.Body = io.NopCloser(bytes.NewReader(rewritten))
+	resp.ContentLength = int64(len(rewritten))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(rewritten)))
+	resp.Header.Set(DownsampledHeader, note)
+
+	return nil
+}
+
+// downsampleMatrix applies d to a query_range response body. It returns the
+// new body and a note describing the reduction, or an empty note when the
+// body is not a matrix or already fits.
+func downsampleMatrix(body []byte, d PrometheusDownsample) ([]byte, string) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body, ""
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(envelope["data"], &data); err != nil {
+		return body, ""
+	}
+
+	var resultType string
+	if err := json.Unmarshal(data["resultType"], &resultType); err != nil || resultType != "matrix" {
+		return body, ""
+	}
+
+	var result []map[string]json.RawMessage
+	if err := json.Unmarshal(data["result"], &result); err != nil {
+		return body, ""
+	}
+
+	var notes []string
+
+	if d.MaxSeries > 0 && len(result) > d.MaxSeries {
+		notes = append(notes, fmt.Sprintf("kept %d of %d series", d.MaxSeries, len(result)))
+		result = result[:d.MaxSeries]
+	}
+
+	if d.MaxPointsPerSeries > 0 {
+		thinned, largest := 0, 0
+
+		for _, series := range result {
+			var values []json.RawMessage
+			if err := json.Unmarshal(series["values"], &values); err != nil || len(values) <= d.MaxPointsPerSeries {
+				continue
+			}
+
+			largest = max(largest, len(values))
+			thinned++
+
+			encoded, err := json.Marshal(thinPoints(values, d.MaxPointsPerSeries))
+			if err != nil {
+				return body, ""
+			}
+
+			series["values"] = encoded
+		}
+
+		if thinned > 0 {
+			notes = append(notes, fmt.Sprintf(
+				"thinned %d series from up to %d to at most %d points",
+				thinned, largest, d.MaxPointsPerSeries,
+			))
+		}
+	}
+
+	if len(notes) == 0 {
+		return body, ""
+	}
+
+	note := strings.Join(notes, "; ")
+
+	encodedResult, err := json.Marshal(result)
+	if err != nil {
+		return body, ""
+	}
+
+	data["result"] = encodedResult
+
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return body, ""
+	}
+
+	var warnings []string
+	if raw, ok := envelope["warnings"]; ok {
+		_ = json.Unmarshal(raw, &warnings)
+	}
+
+	warnings = append(warnings, fmt.Sprintf(
+		"panda proxy downsampled this result (%s); use a larger step or a narrower query for full resolution",
+		note,
+	))
+
+	encodedWarnings, err := json.Marshal(warnings)
+	if err != nil {
+		return body, ""
+	}
+
+	envelope["data"] = encodedData
+	envelope["warnings"] = encodedWarnings
+
+	rewritten, err := json.Marshal(envelope)
+	if err != nil {
+		return body, ""
+	}
+
+	return rewritten, note
+}
+
+// thinPoints keeps every n-th point so that at most limit remain.
+func thinPoints(values []json.RawMessage, limit int) []json.RawMessage {
+	stride := (len(values) + limit - 1) / limit
+	thinned := make([]json.RawMessage, 0, limit)
+
+	for i := 0; i < len(values); i += stride {
+		thinned = append(thinned, values[i])
+	}
+
+	return thinned
+}
+
+var prometheusDownsampledTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "panda",
+		Subsystem: "proxy",
+		Name:      "prometheus_downsampled_responses_total",
+		Help:      "Total number of Prometheus range query responses downsampled by the proxy, by instance",
+	},
+	[]string{"instance"},
+)
+
+func init() {
+	prometheus.MustRegister(prometheusDownsampledTotal)
+}
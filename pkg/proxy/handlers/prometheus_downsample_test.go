@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matrixResponse builds a query_range response with the given number of
+// series and points per series.
+func matrixResponse(series, points int) string {
+	result := make([]string, 0, series)
+
+	for s := range series {
+		values := make([]string, 0, points)
+		for p := range points {
+			values = append(values, fmt.Sprintf(`[%d,"%d"]`, 1700000000+p*15, p))
+		}
+
+		result = append(result, fmt.Sprintf(`{"metric":{"instance":"n%d"},"values":[%s]}`, s, strings.Join(values, ",")))
+	}
+
+	return fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[%s]}}`, strings.Join(result, ","))
+}
+
+func TestPrometheusDownsample(t *testing.T) {
+	body := matrixResponse(5, 100)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewPrometheusHandler(logrus.New(), []PrometheusConfig{{
+		Name:       "primary",
+		URL:        upstream.URL,
+		Downsample: PrometheusDownsample{MaxPointsPerSeries: 30, MaxSeries: 3},
+	}})
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(DatasourceHeader, "primary")
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := do("/prometheus/api/v1/query_range?query=up")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "kept 3 of 5 series; thinned 3 series from up to 100 to at most 30 points", rec.Header().Get(DownsampledHeader))
+
+	var resp struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Values [][]any `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+		Warnings []string `json:"warnings"`
+	}
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, "matrix", resp.Data.ResultType)
+	require.Len(t, resp.Data.Result, 3)
+
+	for _, series := range resp.Data.Result {
+		assert.LessOrEqual(t, len(series.Values), 30)
+		assert.Equal(t, "0", series.Values[0][1], "the first point is kept")
+	}
+
+	require.Len(t, resp.Warnings, 1)
+	assert.Contains(t, resp.Warnings[0], "downsampled")
+
+	rec = do("/prometheus/api/v1/query?query=up")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(DownsampledHeader))
+}
+
+func TestDownsampleMatrixLeavesSmallResultsAlone(t *testing.T) {
+	body := []byte(matrixResponse(2, 10))
+
+	rewritten, note := downsampleMatrix(body, PrometheusDownsample{MaxPointsPerSeries: 10, MaxSeries: 2})
+	assert.Empty(t, note)
+	assert.Equal(t, body, rewritten)
+
+	_, note = downsampleMatrix([]byte(`{"status":"error","error":"bad query"}`), PrometheusDownsample{MaxSeries: 1})
+	assert.Empty(t, note)
+}
+
+func TestThinPoints(t *testing.T) {
+	values := make([]json.RawMessage, 101)
+	for i := range values {
+		values[i] = json.RawMessage(fmt.Sprintf("%d", i))
+	}
+
+	thinned := thinPoints(values, 25)
+	assert.LessOrEqual(t, len(thinned), 25)
+	assert.Equal(t, "0", string(thinned[0]))
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Identity headers. The MCP server sends OnBehalfOfHeader and
+// ExecutionIDHeader with requests it makes for a user or an execution; the
+// proxy strips them and, with query tagging on, forwards all three to the
+// upstream.
+const (
+	UserHeader        = "X-Panda-User"
+	OnBehalfOfHeader  = "X-Panda-On-Behalf-Of"
+	ExecutionIDHeader = "X-Panda-Execution-ID"
+)
+
+// maxIdentityLength bounds forwarded identity values.
+const maxIdentityLength = 128
+
+// RequestIdentity names who a proxied request was made for.
+type RequestIdentity struct {
+	// User is the authenticated proxy user.
+	User string `json:"user,omitempty"`
+	// OnBehalfOf is the user the MCP server made the request for, as
+	// reported by the server. It is not verified by the proxy.
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+	// ExecutionID is the sandbox execution behind the request.
+	ExecutionID string `json:"execution_id,omitempty"`
+}
+
+type requestIdentityKey struct{}
+
+// WithRequestIdentity stores id in ctx for the handlers to tag upstream
+// requests with.
+func WithRequestIdentity(ctx context.Context, id RequestIdentity) context.Context {
+	return context.WithValue(ctx, requestIdentityKey{}, id)
+}
+
+// GetRequestIdentity returns the identity stored by WithRequestIdentity.
+func GetRequestIdentity(ctx context.Context) RequestIdentity {
+	id, _ := ctx.Value(requestIdentityKey{}).(RequestIdentity)
+
+	return id
+}
+
+// TakeIdentityHeaders removes the identity headers sent by the MCP server
+// from h and returns their values, cleaned for use in tags.
+func TakeIdentityHeaders(h http.Header) (onBehalfOf, executionID string) {
+	onBehalfOf, executionID = CleanTag(h.Get(OnBehalfOfHeader)), CleanTag(h.Get(ExecutionIDHeader))
+
+	h.Del(OnBehalfOfHeader)
+	h.Del(ExecutionIDHeader)
+	h.Del(UserHeader)
+
+	return onBehalfOf, executionID
+}
+
+// CleanTag drops characters that could break out of a header or tag list,
+// and truncates long values.
+func CleanTag(value string) string {
+	value = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("-_.@:/", r):
+			return r
+		default:
+			return -1
+		}
+	}, value)
+
+	if len(value) > maxIdentityLength {
+		value = value[:maxIdentityLength]
+	}
+
+	return value
+}
+
+func (id RequestIdentity) empty() bool {
+	return id == RequestIdentity{}
+}
+
+// setHeaders adds the identity headers to an upstream request.
+func (id RequestIdentity) setHeaders(h http.Header) {
+	for name, value := range map[string]string{
+		UserHeader:        id.User,
+		OnBehalfOfHeader:  id.OnBehalfOf,
+		ExecutionIDHeader: id.ExecutionID,
+	} {
+		if value != "" {
+			h.Set(name, value)
+		}
+	}
+}
+
+// tagClickHouseQuery sets ClickHouse's log_comment setting to the identity
+// as JSON, so it shows up in system.query_log.
+func (id RequestIdentity) tagClickHouseQuery(q url.Values) {
+	if id.empty() {
+		return
+	}
+
+	comment, _ := json.Marshal(id)
+	q.Set("log_comment", string(comment))
+}
+
+// lokiQueryTags formats the identity for Loki's X-Query-Tags header, which
+// Loki adds to its query statistics logs.
+func (id RequestIdentity) lokiQueryTags() string {
+	tags := make([]string, 0, 3)
+
+	for _, tag := range []struct{ key, value string }{
+		{"user", id.User},
+		{"on_behalf_of", id.OnBehalfOf},
+		{"execution_id", id.ExecutionID},
+	} {
+		if tag.value != "" {
+			tags = append(tags, tag.key+"="+tag.value)
+		}
+	}
+
+	return strings.Join(tags, ",")
+}
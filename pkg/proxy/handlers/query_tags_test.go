@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseQueryTags(t *testing.T) {
+	t.Parallel()
+
+	var got *http.Request
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Clone(r.Context())
+		_, _ = w.Write([]byte("1\n"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	id := RequestIdentity{User: "alice", OnBehalfOf: "12345", ExecutionID: "exec-1"}
+
+	do := func(tagged bool) {
+		h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{
+			{Name: "xatu", Host: u.Hostname(), Port: port, QueryTags: tagged},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/clickhouse/", strings.NewReader("SELECT 1"))
+		req.Header.Set(DatasourceHeader, "xatu")
+		req = req.WithContext(WithRequestIdentity(req.Context(), id))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	do(true)
+
+	var comment RequestIdentity
+	require.NoError(t, json.Unmarshal([]byte(got.URL.Query().Get("log_comment")), &comment))
+	assert.Equal(t, id, comment)
+	assert.Equal(t, "alice", got.Header.Get(UserHeader))
+	assert.Equal(t, "12345", got.Header.Get(OnBehalfOfHeader))
+	assert.Equal(t, "exec-1", got.Header.Get(ExecutionIDHeader))
+
+	do(false)
+
+	assert.Empty(t, got.URL.Query().Get("log_comment"))
+	assert.Empty(t, got.Header.Get(UserHeader))
+}
+
+func TestLokiQueryTags(t *testing.T) {
+	t.Parallel()
+
+	var got http.Header
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{Name: "logs", URL: upstream.URL, QueryTags: true}})
+
+	req := httptest.NewRequest(http.MethodGet, "/loki/loki/api/v1/labels", nil)
+	req.Header.Set(DatasourceHeader, "logs")
+	req = req.WithContext(WithRequestIdentity(req.Context(), RequestIdentity{User: "server", ExecutionID: "exec-1"}))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "user=server,execution_id=exec-1", got.Get("X-Query-Tags"))
+	assert.Equal(t, "exec-1", got.Get(ExecutionIDHeader))
+	assert.Empty(t, got.Get(OnBehalfOfHeader))
+}
+
+func TestTakeIdentityHeaders(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	h.Set(OnBehalfOfHeader, "bob,execution_id=forged")
+	h.Set(ExecutionIDHeader, strings.Repeat("a", 200))
+	h.Set(UserHeader, "mallory")
+
+	onBehalfOf, executionID := TakeIdentityHeaders(h)
+	assert.Equal(t, "bobexecution_idforged", onBehalfOf)
+	assert.Len(t, executionID, maxIdentityLength)
+	assert.Empty(t, h)
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Resilience defaults.
+const (
+	defaultRetryBackoff        = 200 * time.Millisecond
+	defaultBreakerErrorRate    = 0.5
+	defaultBreakerMinRequests  = 10
+	defaultBreakerWindow       = time.Minute
+	defaultBreakerOpenDuration = 30 * time.Second
+
+	// maxRetryBodyBytes bounds the request body buffered for replay.
+	// Requests with larger bodies are sent once.
+	maxRetryBodyBytes = 1 << 20
+)
+
+// Resilience configures retries and a circuit breaker for one upstream.
+type Resilience struct {
+	Retry          RetryPolicy
+	CircuitBreaker CircuitBreakerPolicy
+}
+
+// RetryPolicy retries requests that failed to connect or got a 502, 503 or
+// 504 from the upstream.
+type RetryPolicy struct {
+	// Attempts is the total number of attempts; 0 and 1 disable retries.
+	Attempts int
+	// Backoff is the wait before the first retry, doubled for each
+	// further one (default 200ms).
+	Backoff time.Duration
+}
+
+// CircuitBreakerPolicy stops sending requests to an upstream whose error
+// rate is too high, so callers fail fast instead of waiting on timeouts.
+type CircuitBreakerPolicy struct {
+	Enabled bool
+	// ErrorRate opens the breaker once this fraction of a window's
+	// requests failed (default 0.5).
+	ErrorRate float64
+	// MinRequests is the number of requests a window needs before the
+	// error rate is considered (default 10).
+	MinRequests int
+	// SlowThreshold counts responses slower than this as failures; 0
+	// ignores latency.
+	SlowThreshold time.Duration
+	// Window is how long outcomes are counted before starting over
+	// (default 1m).
+	Window time.Duration
+	// OpenDuration is how long the breaker stays open before a single
+	// probe request is let through (default 30s).
+	OpenDuration time.Duration
+}
+
+// CircuitOpenError is returned for requests rejected by an open breaker.
+type CircuitOpenError struct {
+	Datasource string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(
+		"circuit breaker for %q is open after repeated upstream failures; retry in %s or use another datasource",
+		e.Datasource, e.RetryAfter.Round(time.Second),
+	)
+}
+
+// circuitBreaker tracks upstream outcomes in fixed windows.
+type circuitBreaker struct {
+	policy CircuitBreakerPolicy
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       string
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+func newCircuitBreaker(policy CircuitBreakerPolicy) *circuitBreaker {
+	if policy.ErrorRate <= 0 {
+		policy.ErrorRate = defaultBreakerErrorRate
+	}
+
+	if policy.MinRequests <= 0 {
+		policy.MinRequests = defaultBreakerMinRequests
+	}
+
+	if policy.Window <= 0 {
+		policy.Window = defaultBreakerWindow
+	}
+
+	if policy.OpenDuration <= 0 {
+		policy.OpenDuration = defaultBreakerOpenDuration
+	}
+
+	return &circuitBreaker{policy: policy, now: time.Now, state: types.CircuitBreakerClosed}
+}
+
+// allow reports whether a request may be sent, and if not, how long until
+// the breaker lets a probe through. Half-open breakers admit one probe at
+// a time.
+func (b *circuitBreaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	switch b.state {
+	case types.CircuitBreakerOpen:
+		if wait := b.openedAt.Add(b.policy.OpenDuration).Sub(now); wait > 0 {
+			return false, wait
+		}
+
+		b.state = types.CircuitBreakerHalfOpen
+		b.probing = true
+
+		return true, 0
+	case types.CircuitBreakerHalfOpen:
+		if b.probing {
+			return false, time.Second
+		}
+
+		b.probing = true
+
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// record counts one outcome and reports whether the state changed.
+func (b *circuitBreaker) record(failed bool) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	if b.state == types.CircuitBreakerHalfOpen {
+		b.probing = false
+
+		if failed {
+			b.state, b.openedAt = types.CircuitBreakerOpen, now
+		} else {
+			b.state = types.CircuitBreakerClosed
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+
+		return b.state, true
+	}
+
+	if b.state != types.CircuitBreakerClosed {
+		return b.state, false
+	}
+
+	if now.Sub(b.windowStart) >= b.policy.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+
+	b.requests++
+
+	if failed {
+		b.failures++
+	}
+
+	if b.requests >= b.policy.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.policy.ErrorRate {
+		b.state, b.openedAt = types.CircuitBreakerOpen, now
+
+		return b.state, true
+	}
+
+	return b.state, false
+}
+
+// status reports the breaker's state for the /datasources endpoint.
+func (b *circuitBreaker) status() *types.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := &types.CircuitBreakerStatus{State: b.state}
+
+	if b.state == types.CircuitBreakerClosed && b.now().Sub(b.windowStart) < b.policy.Window {
+		status.Requests, status.Failures = b.requests, b.failures
+	}
+
+	if b.state == types.CircuitBreakerOpen {
+		retryAt := b.openedAt.Add(b.policy.OpenDuration)
+		status.RetryAt = &retryAt
+	}
+
+	return status
+}
+
+// resilientTransport applies a datasource's retry policy and circuit
+// breaker around the upstream transport.
+type resilientTransport struct {
+	base       http.RoundTripper
+	handler    string
+	datasource string
+	retry      RetryPolicy
+	breaker    *circuitBreaker // nil when disabled
+}
+
+// newResilientTransport wraps base, or returns it unchanged when cfg
+// enables neither retries nor the breaker.
+func newResilientTransport(base http.RoundTripper, handler, datasource string, cfg Resilience) http.RoundTripper {
+	if cfg.Retry.Attempts <= 1 && !cfg.CircuitBreaker.Enabled {
+		return base
+	}
+
+	t := &resilientTransport{base: base, handler: handler, datasource: datasource, retry: cfg.Retry}
+
+	if t.retry.Backoff <= 0 {
+		t.retry.Backoff = defaultRetryBackoff
+	}
+
+	if cfg.CircuitBreaker.Enabled {
+		t.breaker = newCircuitBreaker(cfg.CircuitBreaker)
+		circuitBreakerState.WithLabelValues(handler, datasource).Set(breakerStateValue(types.CircuitBreakerClosed))
+	}
+
+	return t
+}
+
+// breakerStatus returns the breaker state of a transport built by
+// newResilientTransport, or nil without a breaker.
+func breakerStatus(rt http.RoundTripper) *types.CircuitBreakerStatus {
+	t, ok := rt.(*resilientTransport)
+	if !ok || t.breaker == nil {
+		return nil
+	}
+
+	return t.breaker.status()
+}
+
+func (t *resilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := max(t.retry.Attempts, 1)
+
+	// Buffer small bodies so they can be replayed.
+	var body []byte
+
+	if attempts > 1 && req.Body != nil && req.Body != http.NoBody {
+		if req.ContentLength < 0 || req.ContentLength > maxRetryBodyBytes {
+			attempts = 1
+		} else {
+			var err error
+
+			body, err = io.ReadAll(req.Body)
+			_ = req.Body.Close()
+
+			if err != nil {
+				return nil, fmt.Errorf("reading request body: %w", err)
+			}
+		}
+	}
+
+	backoff := t.retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		out := req
+
+		if body != nil {
+			out = req.Clone(req.Context())
+			out.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.attempt(out)
+
+		if attempt >= attempts || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+
+		upstreamRetries.WithLabelValues(t.handler, t.datasource).Inc()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// attempt sends req once, through the breaker if there is one.
+func (t *resilientTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.breaker == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if ok, wait := t.breaker.allow(); !ok {
+		circuitBreakerRejections.WithLabelValues(t.handler, t.datasource).Inc()
+
+		return nil, &CircuitOpenError{Datasource: t.datasource, RetryAfter: wait}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	// Requests the client gave up on say nothing about the upstream.
+	failed := (err != nil && req.Context().Err() == nil) ||
+		(resp != nil && resp.StatusCode >= http.StatusInternalServerError) ||
+		(t.breaker.policy.SlowThreshold > 0 && time.Since(start) > t.breaker.policy.SlowThreshold)
+
+	if state, changed := t.breaker.record(failed); changed {
+		circuitBreakerState.WithLabelValues(t.handler, t.datasource).Set(breakerStateValue(state))
+	}
+
+	return resp, err
+}
+
+// retryable reports whether a failed attempt is worth repeating. Requests
+// rejected by an open breaker are not.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var open *CircuitOpenError
+
+		return !errors.As(err, &open)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func breakerStateValue(state string) float64 {
+	switch state {
+	case types.CircuitBreakerHalfOpen:
+		return 1
+	case types.CircuitBreakerOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+var (
+	upstreamRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "upstream_retries_total",
+			Help:      "Total number of retried upstream requests, by handler and datasource",
+		},
+		[]string{"handler", "datasource"},
+	)
+
+	circuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "circuit_breaker_state",
+			Help:      "Circuit breaker state per datasource: 0 closed, 1 half-open, 2 open",
+		},
+		[]string{"handler", "datasource"},
+	)
+
+	circuitBreakerRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "circuit_breaker_rejections_total",
+			Help:      "Total number of requests rejected by an open circuit breaker, by handler and datasource",
+		},
+		[]string{"handler", "datasource"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(upstreamRetries, circuitBreakerState, circuitBreakerRejections)
+}
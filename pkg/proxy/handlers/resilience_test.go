@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func serveLoki(h *LokiHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/loki/loki/api/v1/query_range", strings.NewReader(body))
+	req.Header.Set(DatasourceHeader, "logs")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	// The upstream fails until the failing budget is spent.
+	var calls, failing atomic.Int32
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `query={job="beacon"}`, string(body))
+
+		if failing.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{
+		Name:       "logs",
+		URL:        upstream.URL,
+		Resilience: Resilience{Retry: RetryPolicy{Attempts: 3, Backoff: time.Millisecond}},
+	}})
+
+	// The request body is replayed on every attempt.
+	failing.Store(2)
+
+	rec := serveLoki(h, `query={job="beacon"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"status":"success"}`, rec.Body.String())
+	assert.EqualValues(t, 3, calls.Load())
+
+	// Attempts are capped; the last response is passed on.
+	calls.Store(0)
+	failing.Store(5)
+
+	rec = serveLoki(h, `query={job="beacon"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestCircuitBreakerHandler(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{
+		Name: "logs",
+		URL:  upstream.URL,
+		Resilience: Resilience{CircuitBreaker: CircuitBreakerPolicy{
+			Enabled:      true,
+			MinRequests:  2,
+			OpenDuration: time.Minute,
+		}},
+	}})
+
+	assert.Equal(t, types.CircuitBreakerClosed, h.CircuitBreakers()["logs"].State)
+
+	for range 2 {
+		assert.Equal(t, http.StatusInternalServerError, serveLoki(h, "").Code)
+	}
+
+	// The breaker is open, so the upstream isn't called again.
+	rec := serveLoki(h, "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `circuit breaker for "logs" is open`)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	assert.EqualValues(t, 2, calls.Load())
+
+	status := h.CircuitBreakers()["logs"]
+	require.NotNil(t, status)
+	assert.Equal(t, types.CircuitBreakerOpen, status.State)
+	assert.NotNil(t, status.RetryAt)
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	b := newCircuitBreaker(CircuitBreakerPolicy{
+		Enabled:      true,
+		ErrorRate:    0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		OpenDuration: 30 * time.Second,
+	})
+	b.now = func() time.Time { return now }
+
+	record := func(failed bool) string {
+		ok, _ := b.allow()
+		require.True(t, ok)
+
+		state, _ := b.record(failed)
+
+		return state
+	}
+
+	// Too few requests to judge.
+	assert.Equal(t, types.CircuitBreakerClosed, record(true))
+	assert.Equal(t, types.CircuitBreakerClosed, record(true))
+	assert.Equal(t, types.CircuitBreakerClosed, record(false))
+
+	// Outcomes from an expired window don't count.
+	now = now.Add(time.Minute)
+
+	assert.Equal(t, types.CircuitBreakerClosed, record(true))
+	assert.Equal(t, 1, b.status().Failures)
+
+	assert.Equal(t, types.CircuitBreakerClosed, record(false))
+	assert.Equal(t, types.CircuitBreakerClosed, record(false))
+	assert.Equal(t, types.CircuitBreakerOpen, record(true))
+
+	ok, wait := b.allow()
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+
+	// After the open duration one probe is let through at a time.
+	now = now.Add(30 * time.Second)
+
+	ok, _ = b.allow()
+	assert.True(t, ok)
+	assert.Equal(t, types.CircuitBreakerHalfOpen, b.status().State)
+
+	ok, _ = b.allow()
+	assert.False(t, ok)
+
+	// A failed probe opens the breaker again, a successful one closes it.
+	state, changed := b.record(true)
+	assert.True(t, changed)
+	assert.Equal(t, types.CircuitBreakerOpen, state)
+
+	now = now.Add(30 * time.Second)
+
+	ok, _ = b.allow()
+	require.True(t, ok)
+
+	state, _ = b.record(false)
+	assert.Equal(t, types.CircuitBreakerClosed, state)
+	assert.Zero(t, b.status().Requests)
+}
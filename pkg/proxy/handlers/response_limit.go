@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// ResponseLimitHeader carries the byte limit applied to a response's
+// decoded body. A response cut off mid-stream was larger than this.
+const ResponseLimitHeader = "X-Panda-Response-Limit"
+
+// ResponseTooLargeError reports an upstream response over a handler's size
+// limit.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"upstream response exceeds the proxy's %d byte limit; select fewer rows or columns, aggregate, or narrow the time range",
+		e.Limit,
+	)
+}
+
+// responseLimit bounds a handler's responses.
+type responseLimit struct {
+	handler    string
+	datasource string
+	// maxBytes caps the decoded body; 0 leaves it unlimited.
+	maxBytes int64
+	// decodeGzip decodes gzip bodies the upstream was asked to send, so the
+	// limit applies to their decoded size, and re-encodes them for clients
+	// that accept gzip.
+	decodeGzip bool
+}
+
+type acceptsGzipKey struct{}
+
+// withClientEncoding records whether the client accepts gzip, before the
+// director rewrites Accept-Encoding for the upstream.
+func withClientEncoding(r *http.Request) *http.Request {
+	accepts := strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+
+	return r.WithContext(context.WithValue(r.Context(), acceptsGzipKey{}, accepts))
+}
+
+func clientAcceptsGzip(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	accepts, _ := r.Context().Value(acceptsGzipKey{}).(bool)
+
+	return accepts
+}
+
+// apply enforces the limit on resp. Responses declaring a larger
+// Content-Length fail with a ResponseTooLargeError before anything is sent.
+// Other bodies are counted as they stream and cut off once they pass the
+// limit, which aborts the response.
+func (l responseLimit) apply(resp *http.Response) error {
+	gzipped := l.decodeGzip && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip")
+
+	if l.maxBytes <= 0 && !gzipped {
+		return nil
+	}
+
+	if l.maxBytes > 0 {
+		if !gzipped && resp.ContentLength > l.maxBytes {
+			_ = resp.Body.Close()
+			responseLimitExceeded.WithLabelValues(l.handler, l.datasource).Inc()
+
+			return &ResponseTooLargeError{Limit: l.maxBytes}
+		}
+
+		resp.Header.Set(ResponseLimitHeader, strconv.FormatInt(l.maxBytes, 10))
+	}
+
+	upstream := resp.Body
+	body := upstream
+
+	if gzipped {
+		decoded, err := gzip.NewReader(body)
+		if err != nil {
+			_ = body.Close()
+
+			return fmt.Errorf("decoding gzip response: %w", err)
+		}
+
+		body = readCloser{decoded, func() error {
+			_ = decoded.Close()
+
+			return upstream.Close()
+		}}
+
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+	}
+
+	if l.maxBytes > 0 {
+		body = &limitedBody{ReadCloser: body, remaining: l.maxBytes, limit: l}
+	}
+
+	if gzipped && clientAcceptsGzip(resp.Request) {
+		body = gzipEncode(body)
+
+		resp.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp.Body = body
+
+	return nil
+}
+
+// limitedBody fails reads once more than remaining bytes were read,
+// passing on at most the limit.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     responseLimit
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	if b.remaining < 0 {
+		responseLimitExceeded.WithLabelValues(b.limit.handler, b.limit.datasource).Inc()
+
+		// Only hand on bytes within the limit.
+		return max(n+int(b.remaining), 0), &ResponseTooLargeError{Limit: b.limit.maxBytes}
+	}
+
+	return n, err
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error { return r.close() }
+
+// gzipEncode re-encodes body as gzip while it is read.
+func gzipEncode(body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		gz := gzip.NewWriter(pw)
+
+		_, err := io.Copy(gz, body)
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+
+		_ = body.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// writeProxyError answers a failed proxy request: 413 for responses over
+// the size limit, 503 while the datasource's circuit breaker is open, 502
+// for anything else.
+func writeProxyError(w http.ResponseWriter, log logrus.FieldLogger, err error) {
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		log.WithField("limit", tooLarge.Limit).Warn("Upstream response too large")
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	var open *CircuitOpenError
+	if errors.As(err, &open) {
+		log.Debug("Rejected request, circuit breaker open")
+		w.Header().Set("Retry-After", strconv.Itoa(int(open.RetryAfter.Seconds())+1))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	log.WithError(err).Error("Proxy error")
+	http.Error(w, fmt.Sprintf("proxy error: %v", err), http.StatusBadGateway)
+}
+
+var responseLimitExceeded = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "panda",
+		Subsystem: "proxy",
+		Name:      "response_limit_exceeded_total",
+		Help:      "Total number of upstream responses rejected or cut off for exceeding the response size limit, by handler and datasource",
+	},
+	[]string{"handler", "datasource"},
+)
+
+func init() {
+	prometheus.MustRegister(responseLimitExceeded)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func TestLokiGzipPassthrough(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat(`{"stream":{"job":"beacon"},"values":[["1","log line"]]}`, 100)
+
+	var upstreamEncoding string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(gzipBytes(t, payload))
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{Name: "logs", URL: upstream.URL, MaxResponseBytes: 1 << 20}})
+
+	do := func(acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/loki/loki/api/v1/query_range", nil)
+		req.Header.Set(DatasourceHeader, "logs")
+
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// Clients that don't accept gzip get the decoded body.
+	plain := do("")
+	assert.Equal(t, "gzip", upstreamEncoding)
+	assert.Equal(t, http.StatusOK, plain.Code)
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	assert.Equal(t, payload, plain.Body.String())
+	assert.Equal(t, strconv.Itoa(1<<20), plain.Header().Get(ResponseLimitHeader))
+
+	// Clients that do get it re-encoded.
+	encoded := do("gzip, deflate")
+	assert.Equal(t, "gzip", encoded.Header().Get("Content-Encoding"))
+
+	gz, err := gzip.NewReader(encoded.Body)
+	require.NoError(t, err)
+
+	decoded, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(decoded))
+}
+
+func TestResponseLimitDeclaredLength(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("enable_http_compression"))
+
+		w.Header().Set("Content-Length", "2048")
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 2048))
+	}))
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	h := NewClickHouseHandler(logrus.New(), []ClickHouseConfig{
+		{Name: "xatu", Host: u.Hostname(), Port: port, MaxResponseBytes: 1024},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/clickhouse/", strings.NewReader("SELECT * FROM blocks"))
+	req.Header.Set(DatasourceHeader, "xatu")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "exceeds the proxy's 1024 byte limit")
+}
+
+func TestResponseLimitStreamed(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for range 8 {
+			_, _ = w.Write(bytes.Repeat([]byte("x"), 512))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{Name: "logs", URL: upstream.URL, MaxResponseBytes: 1024}})
+
+	proxy := httptest.NewServer(h)
+	t.Cleanup(proxy.Close)
+
+	req, err := http.NewRequest(http.MethodGet, proxy.URL+"/loki/loki/api/v1/query_range", nil)
+	require.NoError(t, err)
+	req.Header.Set(DatasourceHeader, "logs")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// The status was sent before the body passed the limit, so the
+	// response is cut off instead.
+	body, err := io.ReadAll(resp.Body)
+	require.Error(t, err)
+	assert.LessOrEqual(t, len(body), 1024)
+	assert.Equal(t, "1024", resp.Header.Get(ResponseLimitHeader))
+}
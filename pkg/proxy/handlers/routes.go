@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Route is an upstream API route a handler forwards. Paths ending in "/"
+// match as prefixes, all others must match exactly.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// String formats the route as "METHOD /path", the form ParseRoute accepts.
+func (r Route) String() string {
+	return r.Method + " " + r.Path
+}
+
+// ParseRoute parses a route written as "METHOD /path", e.g.
+// "GET /api/v1/status/config".
+func ParseRoute(s string) (Route, error) {
+	method, path, ok := strings.Cut(strings.TrimSpace(s), " ")
+	path = strings.TrimSpace(path)
+
+	if !ok || method == "" || method != strings.ToUpper(method) || strings.ContainsAny(method, "/ ") {
+		return Route{}, fmt.Errorf("route %q must be \"METHOD /path\"", s)
+	}
+
+	if !strings.HasPrefix(path, "/") || strings.Contains(path, "..") {
+		return Route{}, fmt.Errorf("route %q must have an absolute path without '..'", s)
+	}
+
+	return Route{Method: method, Path: path}, nil
+}
+
+// routeAllowed reports whether method and path match one of routes.
+func routeAllowed(routes []Route, method, path string) bool {
+	if strings.Contains(path, "..") {
+		return false
+	}
+
+	for _, route := range routes {
+		if method != route.Method {
+			continue
+		}
+
+		if path == route.Path || (strings.HasSuffix(route.Path, "/") && strings.HasPrefix(path, route.Path)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readRoutes returns a GET and a POST route for each path. Prometheus and
+// Loki accept form-encoded POSTs on their query endpoints for long queries.
+func readRoutes(paths ...string) []Route {
+	routes := make([]Route, 0, 2*len(paths))
+	for _, path := range paths {
+		routes = append(routes, Route{Method: http.MethodGet, Path: path}, Route{Method: http.MethodPost, Path: path})
+	}
+
+	return routes
+}
+
+// getRoutes returns a GET route for each path.
+func getRoutes(paths ...string) []Route {
+	routes := make([]Route, 0, len(paths))
+	for _, path := range paths {
+		routes = append(routes, Route{Method: http.MethodGet, Path: path})
+	}
+
+	return routes
+}
+
+// prometheusRoutes is the read-only subset of the Prometheus HTTP API
+// reachable through the proxy. Remote write, the admin TSDB API (series
+// deletion, snapshots), lifecycle endpoints and everything else not listed
+// here are rejected, whatever the upstream's own access control allows.
+var prometheusRoutes = append(readRoutes(
+	"/api/v1/query",
+	"/api/v1/query_range",
+	"/api/v1/query_exemplars",
+	"/api/v1/series",
+	"/api/v1/labels",
+), getRoutes(
+	"/api/v1/label/",
+	"/api/v1/metadata",
+	"/api/v1/targets",
+	"/api/v1/targets/metadata",
+	"/api/v1/rules",
+	"/api/v1/alerts",
+	"/api/v1/alertmanagers",
+	"/api/v1/status/buildinfo",
+	"/api/v1/status/runtimeinfo",
+	"/api/v1/status/tsdb",
+	"/-/ready",
+	"/-/healthy",
+)...)
+
+// lokiRoutes is the read-only subset of the Loki HTTP API reachable through
+// the proxy. Pushes, log deletion requests, ruler changes and everything
+// else not listed here are rejected.
+var lokiRoutes = append(readRoutes(
+	"/loki/api/v1/query",
+	"/loki/api/v1/query_range",
+	"/loki/api/v1/labels",
+	"/loki/api/v1/series",
+	"/loki/api/v1/index/stats",
+	"/loki/api/v1/index/volume",
+	"/loki/api/v1/index/volume_range",
+	"/loki/api/v1/patterns",
+	"/loki/api/v1/detected_fields",
+	"/loki/api/v1/detected_labels",
+), getRoutes(
+	"/loki/api/v1/label/",
+	"/loki/api/v1/status/buildinfo",
+	"/ready",
+)...)
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyRouteAllowlists(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	prom := NewPrometheusHandler(logrus.New(), []PrometheusConfig{{
+		Name:          "upstream",
+		URL:           upstream.URL,
+		AllowedRoutes: []Route{{Method: http.MethodGet, Path: "/api/v1/status/config"}},
+	}})
+	loki := NewLokiHandler(logrus.New(), []LokiConfig{{Name: "upstream", URL: upstream.URL}})
+
+	tests := []struct {
+		handler http.Handler
+		method  string
+		path    string
+		allowed bool
+	}{
+		{prom, http.MethodGet, "/prometheus/api/v1/query", true},
+		{prom, http.MethodPost, "/prometheus/api/v1/query_range", true},
+		{prom, http.MethodGet, "/prometheus/api/v1/label/__name__/values", true},
+		{prom, http.MethodGet, "/prometheus/-/ready", true},
+		{prom, http.MethodGet, "/prometheus/api/v1/status/config", true},
+		{prom, http.MethodPost, "/prometheus/api/v1/write", false},
+		{prom, http.MethodPost, "/prometheus/api/v1/admin/tsdb/delete_series", false},
+		{prom, http.MethodPost, "/prometheus/api/v1/admin/tsdb/snapshot", false},
+		{prom, http.MethodDelete, "/prometheus/api/v1/series", false},
+		{prom, http.MethodPost, "/prometheus/-/quit", false},
+		{prom, http.MethodGet, "/prometheus/api/v1/status/flags", false},
+		{prom, http.MethodGet, "/prometheus/api/v1/label/../../admin/tsdb/snapshot", false},
+		{loki, http.MethodGet, "/loki/loki/api/v1/query_range", true},
+		{loki, http.MethodPost, "/loki/loki/api/v1/query", true},
+		{loki, http.MethodGet, "/loki/loki/api/v1/label/job/values", true},
+		{loki, http.MethodGet, "/loki/ready", true},
+		{loki, http.MethodPost, "/loki/loki/api/v1/push", false},
+		{loki, http.MethodPost, "/loki/api/v1/push", false},
+		{loki, http.MethodPost, "/loki/loki/api/v1/delete", false},
+		{loki, http.MethodPost, "/loki/loki/api/v1/rules/default", false},
+		{loki, http.MethodGet, "/loki/config", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			before := hits.Load()
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set(DatasourceHeader, "upstream")
+
+			rec := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rec, req)
+
+			if tc.allowed {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, before+1, hits.Load())
+
+				return
+			}
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Contains(t, rec.Body.String(), "route not allowed")
+			assert.Equal(t, before, hits.Load())
+		})
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	t.Parallel()
+
+	route, err := ParseRoute("GET /api/v1/status/config")
+	require.NoError(t, err)
+	assert.Equal(t, Route{Method: http.MethodGet, Path: "/api/v1/status/config"}, route)
+	assert.Equal(t, "GET /api/v1/status/config", route.String())
+
+	for _, bad := range []string{
+		"/api/v1/query",
+		"get /api/v1/query",
+		"GET api/v1/query",
+		"GET /api/v1/../admin",
+		"GET",
+	} {
+		_, err := ParseRoute(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"strconv"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // Transport defaults for all reverse proxy handlers.
@@ -14,13 +18,34 @@ const (
 	defaultMaxIdleConns        = 100
 	defaultMaxIdleConnsPerHost = 10
 	defaultIdleConnTimeout     = 90 * time.Second
+	defaultTLSSessionCacheSize = 64
 )
 
+// TransportTuning adjusts a datasource's upstream connection pool. Zero
+// values keep the defaults.
+type TransportTuning struct {
+	// MaxIdleConns caps idle connections across all hosts (default 100).
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections kept per host (default 10).
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps connections per host, including active ones;
+	// 0 is unlimited.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer (default 90s).
+	IdleConnTimeout time.Duration
+	// TLSSessionCacheSize is the number of TLS sessions kept for
+	// resumption (default 64); negative disables resumption.
+	TLSSessionCacheSize int
+	// HTTP2 negotiates HTTP/2 with upstreams that support it.
+	HTTP2 bool
+}
+
 // newProxyTransport returns an *http.Transport with sensible defaults for
-// reverse-proxying upstream datasources. Setting skipVerify disables TLS
-// certificate verification on the upstream connection.
-func newProxyTransport(skipVerify bool) *http.Transport {
-	return &http.Transport{
+// reverse-proxying upstream datasources, adjusted by tuning. Setting
+// skipVerify disables TLS certificate verification on the upstream
+// connection.
+func newProxyTransport(skipVerify bool, tuning TransportTuning) *http.Transport {
+	t := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: skipVerify, //nolint:gosec // User-configured per datasource
 		},
@@ -28,6 +53,88 @@ func newProxyTransport(skipVerify bool) *http.Transport {
 		TLSHandshakeTimeout: defaultTLSHandshakeTimeout,
 		MaxIdleConns:        defaultMaxIdleConns,
 		MaxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+		MaxConnsPerHost:     tuning.MaxConnsPerHost,
 		IdleConnTimeout:     defaultIdleConnTimeout,
+		ForceAttemptHTTP2:   tuning.HTTP2,
+	}
+
+	if tuning.MaxIdleConns > 0 {
+		t.MaxIdleConns = tuning.MaxIdleConns
+	}
+
+	if tuning.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = tuning.MaxIdleConnsPerHost
+	}
+
+	if tuning.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = tuning.IdleConnTimeout
+	}
+
+	switch {
+	case tuning.TLSSessionCacheSize > 0:
+		t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(tuning.TLSSessionCacheSize)
+	case tuning.TLSSessionCacheSize == 0:
+		t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(defaultTLSSessionCacheSize)
+	}
+
+	return t
+}
+
+// newUpstreamTransport returns the tuned transport for a datasource,
+// instrumented with connection reuse metrics.
+func newUpstreamTransport(handler, datasource string, skipVerify bool, tuning TransportTuning) http.RoundTripper {
+	return &connTrackingTransport{
+		base:       newProxyTransport(skipVerify, tuning),
+		handler:    handler,
+		datasource: datasource,
 	}
 }
+
+// connTrackingTransport counts whether requests reused a pooled connection
+// and whether new TLS connections resumed a session.
+type connTrackingTransport struct {
+	base       http.RoundTripper
+	handler    string
+	datasource string
+}
+
+func (t *connTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			upstreamConnections.WithLabelValues(t.handler, t.datasource, strconv.FormatBool(info.Reused)).Inc()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				upstreamTLSHandshakes.WithLabelValues(t.handler, t.datasource, strconv.FormatBool(state.DidResume)).Inc()
+			}
+		},
+	}
+
+	return t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+var (
+	upstreamConnections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "upstream_connections_total",
+			Help:      "Total number of connections obtained for upstream requests, by whether a pooled connection was reused",
+		},
+		[]string{"handler", "datasource", "reused"},
+	)
+
+	upstreamTLSHandshakes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "panda",
+			Subsystem: "proxy",
+			Name:      "upstream_tls_handshakes_total",
+			Help:      "Total number of TLS handshakes with upstreams, by whether a session was resumed",
+		},
+		[]string{"handler", "datasource", "resumed"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(upstreamConnections, upstreamTLSHandshakes)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProxyTransportTuning(t *testing.T) {
+	t.Parallel()
+
+	defaults := newProxyTransport(false, TransportTuning{})
+	assert.Equal(t, defaultMaxIdleConns, defaults.MaxIdleConns)
+	assert.Equal(t, defaultMaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost)
+	assert.Zero(t, defaults.MaxConnsPerHost)
+	assert.Equal(t, defaultIdleConnTimeout, defaults.IdleConnTimeout)
+	assert.NotNil(t, defaults.TLSClientConfig.ClientSessionCache)
+	assert.False(t, defaults.ForceAttemptHTTP2)
+
+	tuned := newProxyTransport(true, TransportTuning{
+		MaxIdleConns:        500,
+		MaxIdleConnsPerHost: 50,
+		MaxConnsPerHost:     64,
+		IdleConnTimeout:     5 * time.Minute,
+		TLSSessionCacheSize: -1,
+		HTTP2:               true,
+	})
+	assert.Equal(t, 500, tuned.MaxIdleConns)
+	assert.Equal(t, 50, tuned.MaxIdleConnsPerHost)
+	assert.Equal(t, 64, tuned.MaxConnsPerHost)
+	assert.Equal(t, 5*time.Minute, tuned.IdleConnTimeout)
+	assert.Nil(t, tuned.TLSClientConfig.ClientSessionCache)
+	assert.True(t, tuned.ForceAttemptHTTP2)
+	assert.True(t, tuned.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestUpstreamConnectionReuseMetrics(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	h := NewLokiHandler(logrus.New(), []LokiConfig{{Name: "pooled", URL: upstream.URL}})
+
+	dialed, reused := upstreamConnections.WithLabelValues("loki", "pooled", "false"),
+		upstreamConnections.WithLabelValues("loki", "pooled", "true")
+	dialedBefore, reusedBefore := testutil.ToFloat64(dialed), testutil.ToFloat64(reused)
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/loki/loki/api/v1/labels", nil)
+		req.Header.Set(DatasourceHeader, "pooled")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	// The first request dials; the others reuse its connection.
+	assert.InDelta(t, 1, testutil.ToFloat64(dialed)-dialedBefore, 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(reused)-reusedBefore, 0)
+}
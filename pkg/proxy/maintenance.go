@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/maintenance"
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// MaintenanceErrorCode identifies maintenance rejections in error bodies.
+const MaintenanceErrorCode = "MAINTENANCE"
+
+// MaintenanceError is the body of a request rejected during a maintenance
+// window.
+type MaintenanceError struct {
+	Error      string    `json:"error"`
+	Message    string    `json:"message"`
+	Type       string    `json:"type"`
+	Datasource string    `json:"datasource"`
+	Reason     string    `json:"reason"`
+	Until      time.Time `json:"until"`
+}
+
+// MaintenanceGate rejects requests to datasources inside one of their
+// maintenance windows.
+type MaintenanceGate struct {
+	log     logrus.FieldLogger
+	now     func() time.Time
+	windows map[string][]types.MaintenanceWindow // "type:name" -> windows
+}
+
+// NewMaintenanceGate creates a gate for every datasource with maintenance
+// windows, or returns nil when none has any.
+func NewMaintenanceGate(log logrus.FieldLogger, cfg ServerConfig) *MaintenanceGate {
+	g := &MaintenanceGate{
+		log:     log.WithField("component", "maintenance"),
+		now:     time.Now,
+		windows: make(map[string][]types.MaintenanceWindow, 4),
+	}
+
+	add := func(dsType string, base BaseDatasourceConfig) {
+		if len(base.Maintenance) > 0 {
+			g.windows[ruleKey(dsType, base.Name)] = base.Maintenance
+		}
+	}
+
+	for _, ds := range cfg.ClickHouse {
+		add("clickhouse", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Prometheus {
+		add("prometheus", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Loki {
+		add("loki", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Grafana {
+		add("grafana", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.HTTPJSON {
+		add("httpjson", ds.BaseDatasourceConfig)
+	}
+
+	if len(g.windows) == 0 {
+		return nil
+	}
+
+	return g
+}
+
+// Middleware returns an HTTP middleware that answers requests to a
+// datasource in maintenance with 503 and a MaintenanceError body.
+func (g *MaintenanceGate) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dsType, name := extractDatasourceType(r.URL.Path), r.Header.Get(handlers.DatasourceHeader)
+
+			windows := g.windows[ruleKey(dsType, name)]
+			if len(windows) == 0 {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			now := g.now()
+
+			window, until, active := maintenance.Active(windows, now)
+			if !active {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			reason := maintenance.Describe(window)
+
+			ProxyMaintenanceRejectionsTotal.WithLabelValues(dsType, name).Inc()
+			g.log.WithFields(logrus.Fields{
+				"datasource": name,
+				"until":      until,
+			}).Debug("Rejected request during maintenance window")
+
+			body := MaintenanceError{
+				Error: MaintenanceErrorCode,
+				Message: fmt.Sprintf(
+					"%s %q is unavailable for maintenance (%s) until %s; do not retry before then.",
+					dsType, name, reason, until.Format(time.RFC3339),
+				),
+				Type:       dsType,
+				Datasource: name,
+				Reason:     reason,
+				Until:      until,
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(int(until.Sub(now).Seconds())+1))
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			if err := json.NewEncoder(w).Encode(body); err != nil {
+				g.log.WithError(err).Error("Failed to encode maintenance error")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestMaintenanceGate(t *testing.T) {
+	cfg := ServerConfig{
+		ClickHouse: []ClickHouseClusterConfig{
+			{BaseDatasourceConfig: BaseDatasourceConfig{
+				Name: "xatu",
+				Maintenance: []types.MaintenanceWindow{
+					{Schedule: "0 2 * * *", Duration: time.Hour, Reason: "ClickHouse upgrade"},
+				},
+			}},
+			{BaseDatasourceConfig: BaseDatasourceConfig{Name: "other"}},
+		},
+	}
+
+	gate := NewMaintenanceGate(logrus.New(), cfg)
+	require.NotNil(t, gate)
+
+	now := time.Date(2026, 3, 1, 2, 30, 0, 0, time.UTC)
+	gate.now = func() time.Time { return now }
+
+	handler := gate.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(datasource string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/clickhouse/", nil)
+		req.Header.Set(handlers.DatasourceHeader, datasource)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := do("xatu")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1801", rec.Header().Get("Retry-After"))
+
+	var body MaintenanceError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, MaintenanceErrorCode, body.Error)
+	assert.Equal(t, "ClickHouse upgrade", body.Reason)
+	assert.Equal(t, time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), body.Until)
+
+	assert.Equal(t, http.StatusOK, do("other").Code)
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, http.StatusOK, do("xatu").Code)
+
+	assert.Nil(t, NewMaintenanceGate(logrus.New(), ServerConfig{}))
+}
+
+func TestValidateMaintenance(t *testing.T) {
+	cfg := ServerConfig{
+		Prometheus: []PrometheusInstanceConfig{
+			{BaseDatasourceConfig: BaseDatasourceConfig{
+				Name:        "prom",
+				Maintenance: []types.MaintenanceWindow{{Schedule: "0 2 * *", Duration: time.Hour}},
+			}},
+		},
+	}
+
+	err := cfg.validateMaintenance()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prometheus[0].maintenance[0]")
+}
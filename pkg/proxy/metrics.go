@@ -74,6 +74,29 @@ var (
 		},
 		[]string{"datasource_type"},
 	)
+
+	// ProxyQuotaRejectionsTotal counts requests rejected by datasource quotas.
+	ProxyQuotaRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: proxyMetricsNamespace,
+			Subsystem: proxyMetricsSubsystem,
+			Name:      "quota_rejections_total",
+			Help:      "Total number of requests rejected by datasource quotas, by reason (queries or bytes_scanned)",
+		},
+		[]string{"datasource_type", "datasource", "reason"},
+	)
+
+	// ProxyMaintenanceRejectionsTotal counts requests rejected during
+	// datasource maintenance windows.
+	ProxyMaintenanceRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: proxyMetricsNamespace,
+			Subsystem: proxyMetricsSubsystem,
+			Name:      "maintenance_rejections_total",
+			Help:      "Total number of requests rejected during datasource maintenance windows",
+		},
+		[]string{"datasource_type", "datasource"},
+	)
 )
 
 // Embedding metrics.
@@ -140,6 +163,8 @@ func init() {
 		ProxyResponseSizeBytes,
 		ProxyActiveRequests,
 		ProxyRateLimitRejectionsTotal,
+		ProxyQuotaRejectionsTotal,
+		ProxyMaintenanceRejectionsTotal,
 		EmbeddingRequestsTotal,
 		EmbeddingRequestDurationSeconds,
 		EmbeddingTokensTotal,
@@ -210,6 +235,18 @@ func (s *server) metricsDatasourceLabel(dsType, candidate string) string {
 				return candidate
 			}
 		}
+	case "grafana":
+		for _, cfg := range s.cfg.Grafana {
+			if cfg.Name == candidate {
+				return candidate
+			}
+		}
+	case "httpjson":
+		for _, cfg := range s.cfg.HTTPJSON {
+			if cfg.Name == candidate {
+				return candidate
+			}
+		}
 	}
 
 	return "unknown"
@@ -259,12 +296,22 @@ func extractDatasourceType(path string) string {
 		return "prometheus"
 	case "loki":
 		return "loki"
+	case "grafana":
+		return "grafana"
+	case "httpjson":
+		return "httpjson"
+	case "github":
+		return "github"
 	case "beacon", "execution":
 		return "ethnode"
 	case "datasources":
 		return "datasources"
 	case "embed":
 		return "embed"
+	case "audit":
+		return "audit"
+	case "quota":
+		return "quota"
 	default:
 		return "unknown"
 	}
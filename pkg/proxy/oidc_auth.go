@@ -14,9 +14,46 @@ import (
 	"github.com/ethpandaops/panda/internal/version"
 )
 
+// maxOIDCClockSkew caps the configurable clock skew. Larger values would keep
+// expired tokens usable for too long.
+const maxOIDCClockSkew = 5 * time.Minute
+
+// oidcSigningAlgorithms are the JWS algorithms that may be accepted. HMAC and
+// "none" are excluded: an OIDC issuer signs with keys published in its JWKS.
+var oidcSigningAlgorithms = map[string]bool{
+	oidc.RS256: true, oidc.RS384: true, oidc.RS512: true,
+	oidc.PS256: true, oidc.PS384: true, oidc.PS512: true,
+	oidc.ES256: true, oidc.ES384: true, oidc.ES512: true,
+	oidc.EdDSA: true,
+}
+
 type OIDCAuthenticatorConfig struct {
 	IssuerURL string
 	ClientID  string
+
+	// SigningAlgorithms are the accepted JWS algorithms, e.g. "ES256" or
+	// "EdDSA". Empty accepts those the issuer advertises in its discovery
+	// document, or only RS256 if it advertises none.
+	SigningAlgorithms []string
+
+	// ClockSkew is how long after expiry a token is still accepted, to
+	// tolerate clocks drifting between the issuer and the proxy.
+	ClockSkew time.Duration
+}
+
+// validateOIDCVerification checks the signing algorithms and clock skew.
+func validateOIDCVerification(algorithms []string, clockSkew time.Duration) error {
+	for _, alg := range algorithms {
+		if !oidcSigningAlgorithms[alg] {
+			return fmt.Errorf("unsupported signing algorithm %q", alg)
+		}
+	}
+
+	if clockSkew < 0 || clockSkew > maxOIDCClockSkew {
+		return fmt.Errorf("clock skew must be between 0 and %s", maxOIDCClockSkew)
+	}
+
+	return nil
 }
 
 type oidcAuthenticator struct {
@@ -48,6 +85,9 @@ func NewOIDCAuthenticator(log logrus.FieldLogger, cfg OIDCAuthenticatorConfig) (
 	if cfg.ClientID == "" {
 		return nil, fmt.Errorf("client ID is required")
 	}
+	if err := validateOIDCVerification(cfg.SigningAlgorithms, cfg.ClockSkew); err != nil {
+		return nil, err
+	}
 
 	return &oidcAuthenticator{
 		log: log.WithFields(logrus.Fields{
@@ -71,11 +111,25 @@ func (a *oidcAuthenticator) Start(ctx context.Context) error {
 		return fmt.Errorf("discovering OIDC provider: %w", err)
 	}
 
+	verifierCfg := &oidc.Config{
+		ClientID:             a.cfg.ClientID,
+		SupportedSigningAlgs: a.cfg.SigningAlgorithms,
+	}
+
+	if skew := a.cfg.ClockSkew; skew > 0 {
+		verifierCfg.Now = func() time.Time { return time.Now().Add(-skew) }
+	}
+
+	// The provider's key set refetches the JWKS when a token names a key ID
+	// it has not seen, so issuer key rotation needs no restart.
 	a.mu.Lock()
-	a.verifier = provider.Verifier(&oidc.Config{ClientID: a.cfg.ClientID})
+	a.verifier = provider.Verifier(verifierCfg)
 	a.mu.Unlock()
 
-	a.log.Info("External OIDC authenticator initialized")
+	a.log.WithFields(logrus.Fields{
+		"signing_algorithms": a.cfg.SigningAlgorithms,
+		"clock_skew":         a.cfg.ClockSkew,
+	}).Info("External OIDC authenticator initialized")
 
 	return nil
 }
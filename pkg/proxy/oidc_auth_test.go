@@ -2,6 +2,10 @@ package proxy
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
@@ -9,6 +13,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -174,3 +179,242 @@ func signedRSAToken(t *testing.T, privateKey *rsa.PrivateKey, issuer, audience,
 
 	return signed
 }
+
+// testIssuer is an OIDC issuer whose published key set can be swapped to
+// simulate key rotation.
+type testIssuer struct {
+	server *httptest.Server
+
+	mu   sync.Mutex
+	keys []map[string]string
+}
+
+func newTestIssuer(t *testing.T, keys ...map[string]string) *testIssuer {
+	t.Helper()
+
+	issuer := &testIssuer{keys: keys}
+
+	mux := http.NewServeMux()
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": issuer.keys})
+	})
+
+	return issuer
+}
+
+func (i *testIssuer) setKeys(keys ...map[string]string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.keys = keys
+}
+
+func (i *testIssuer) authenticator(t *testing.T, cfg OIDCAuthenticatorConfig) Authenticator {
+	t.Helper()
+
+	cfg.IssuerURL = i.server.URL
+	cfg.ClientID = "panda-proxy"
+
+	authenticator, err := NewOIDCAuthenticator(logrus.New(), cfg)
+	if err != nil {
+		t.Fatalf("NewOIDCAuthenticator failed: %v", err)
+	}
+
+	if err := authenticator.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	return authenticator
+}
+
+// authStatus returns the status the authenticator's middleware answers a
+// request bearing rawToken with.
+func authStatus(authenticator Authenticator, rawToken string) int {
+	handler := authenticator.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/clickhouse/query", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	ecdhKey, _ := key.PublicKey.ECDH()
+	point := ecdhKey.Bytes() // 0x04 || X || Y
+	size := (len(point) - 1) / 2
+
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"use": "sig",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+		"y":   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+	}
+}
+
+func okpJWK(kid string, key ed25519.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "OKP",
+		"kid": kid,
+		"use": "sig",
+		"crv": "Ed25519",
+		"x":   base64.RawURLEncoding.EncodeToString(key),
+	}
+}
+
+func signedToken(
+	t *testing.T,
+	method jwt.SigningMethod,
+	kid string,
+	key crypto.Signer,
+	issuer string,
+	expiresAt time.Time,
+) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"iss": issuer,
+		"aud": []string{"panda-proxy"},
+		"sub": "user-123",
+		"iat": time.Now().Add(-time.Hour).Unix(),
+		"exp": expiresAt.Unix(),
+	})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString failed: %v", err)
+	}
+
+	return signed
+}
+
+func TestOIDCAuthenticatorAcceptsECAndEdDSAKeys(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	issuer := newTestIssuer(t, ecJWK("ec-key", ecKey), okpJWK("ed-key", edPublic))
+	expiresAt := time.Now().Add(time.Hour)
+	esToken := signedToken(t, jwt.SigningMethodES256, "ec-key", ecKey, issuer.server.URL, expiresAt)
+	edToken := signedToken(t, jwt.SigningMethodEdDSA, "ed-key", edPrivate, issuer.server.URL, expiresAt)
+
+	configured := issuer.authenticator(t, OIDCAuthenticatorConfig{SigningAlgorithms: []string{"ES256", "EdDSA"}})
+	for name, token := range map[string]string{"ES256": esToken, "EdDSA": edToken} {
+		if status := authStatus(configured, token); status != http.StatusNoContent {
+			t.Fatalf("%s: expected status %d, got %d", name, http.StatusNoContent, status)
+		}
+	}
+
+	// The issuer advertises no algorithms, so the default is RS256 only.
+	defaults := issuer.authenticator(t, OIDCAuthenticatorConfig{})
+	if status := authStatus(defaults, esToken); status != http.StatusUnauthorized {
+		t.Fatalf("expected ES256 to be rejected by default, got status %d", status)
+	}
+
+	esOnly := issuer.authenticator(t, OIDCAuthenticatorConfig{SigningAlgorithms: []string{"ES256"}})
+	if status := authStatus(esOnly, edToken); status != http.StatusUnauthorized {
+		t.Fatalf("expected EdDSA to be rejected when not configured, got status %d", status)
+	}
+}
+
+func TestOIDCAuthenticatorRefreshesKeysOnUnknownKeyID(t *testing.T) {
+	t.Parallel()
+
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	issuer := newTestIssuer(t, ecJWK("old-key", oldKey))
+	authenticator := issuer.authenticator(t, OIDCAuthenticatorConfig{SigningAlgorithms: []string{"ES256"}})
+	expiresAt := time.Now().Add(time.Hour)
+
+	oldToken := signedToken(t, jwt.SigningMethodES256, "old-key", oldKey, issuer.server.URL, expiresAt)
+	if status := authStatus(authenticator, oldToken); status != http.StatusNoContent {
+		t.Fatalf("expected status %d before rotation, got %d", http.StatusNoContent, status)
+	}
+
+	issuer.setKeys(ecJWK("new-key", newKey))
+
+	newToken := signedToken(t, jwt.SigningMethodES256, "new-key", newKey, issuer.server.URL, expiresAt)
+	if status := authStatus(authenticator, newToken); status != http.StatusNoContent {
+		t.Fatalf("expected a token signed with the rotated key to be accepted, got status %d", status)
+	}
+}
+
+func TestOIDCAuthenticatorClockSkew(t *testing.T) {
+	t.Parallel()
+
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	issuer := newTestIssuer(t, okpJWK("ed-key", edPrivate.Public().(ed25519.PublicKey)))
+	justExpired := signedToken(t, jwt.SigningMethodEdDSA, "ed-key", edPrivate, issuer.server.URL,
+		time.Now().Add(-30*time.Second))
+
+	strict := issuer.authenticator(t, OIDCAuthenticatorConfig{SigningAlgorithms: []string{"EdDSA"}})
+	if status := authStatus(strict, justExpired); status != http.StatusUnauthorized {
+		t.Fatalf("expected an expired token to be rejected without skew, got status %d", status)
+	}
+
+	lenient := issuer.authenticator(t, OIDCAuthenticatorConfig{
+		SigningAlgorithms: []string{"EdDSA"},
+		ClockSkew:         time.Minute,
+	})
+	if status := authStatus(lenient, justExpired); status != http.StatusNoContent {
+		t.Fatalf("expected a token within the clock skew to be accepted, got status %d", status)
+	}
+}
+
+func TestNewOIDCAuthenticatorRejectsInvalidVerificationSettings(t *testing.T) {
+	t.Parallel()
+
+	invalid := map[string]OIDCAuthenticatorConfig{
+		"hmac":          {SigningAlgorithms: []string{"HS256"}},
+		"none":          {SigningAlgorithms: []string{"none"}},
+		"negative skew": {ClockSkew: -time.Second},
+		"skew too long": {ClockSkew: time.Hour},
+	}
+
+	for name, cfg := range invalid {
+		cfg.IssuerURL = "https://issuer.example"
+		cfg.ClientID = "panda-proxy"
+
+		if _, err := NewOIDCAuthenticator(logrus.New(), cfg); err == nil {
+			t.Fatalf("%s: expected NewOIDCAuthenticator to fail", name)
+		}
+	}
+}
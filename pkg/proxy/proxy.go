@@ -4,6 +4,7 @@ package proxy
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/ethpandaops/panda/pkg/types"
 )
@@ -27,6 +28,14 @@ type Service interface {
 	// RevokeToken is a no-op for client-managed bearer tokens.
 	RevokeToken(executionID string)
 
+	// SignRequest adds HMAC signature headers to a server-to-proxy request.
+	// It is a no-op when no signing key is configured.
+	SignRequest(req *http.Request) error
+
+	// Transport returns the round tripper for server-to-proxy requests,
+	// carrying the client certificate when the proxy requires mTLS.
+	Transport() http.RoundTripper
+
 	// ClickHouseDatasources returns the list of ClickHouse datasource names.
 	ClickHouseDatasources() []string
 	// ClickHouseDatasourceInfo returns detailed ClickHouse datasource info.
@@ -42,6 +51,21 @@ type Service interface {
 	// LokiDatasourceInfo returns detailed Loki datasource info.
 	LokiDatasourceInfo() []types.DatasourceInfo
 
+	// GrafanaDatasources returns the list of Grafana instance names.
+	GrafanaDatasources() []string
+	// GrafanaDatasourceInfo returns detailed Grafana instance info.
+	GrafanaDatasourceInfo() []types.DatasourceInfo
+
+	// HTTPJSONDatasources returns the list of HTTP JSON endpoint names.
+	HTTPJSONDatasources() []string
+	// HTTPJSONDatasourceInfo returns detailed HTTP JSON endpoint info.
+	HTTPJSONDatasourceInfo() []types.DatasourceInfo
+
+	// GitHubDatasources returns the list of readable GitHub repositories.
+	GitHubDatasources() []string
+	// GitHubDatasourceInfo returns detailed GitHub repository info.
+	GitHubDatasourceInfo() []types.DatasourceInfo
+
 	// EthNodeAvailable returns true if ethnode proxy access is configured.
 	EthNodeAvailable() bool
 
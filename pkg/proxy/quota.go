@@ -0,0 +1,306 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+// clickhouseSummaryHeader carries ClickHouse's query statistics.
+const clickhouseSummaryHeader = "X-ClickHouse-Summary"
+
+// quotaExemptPaths are health probes that do not count against quotas.
+var quotaExemptPaths = map[string]bool{
+	"/clickhouse/ping":    true,
+	"/prometheus/-/ready": true,
+	"/loki/ready":         true,
+}
+
+// QuotaTracker enforces per-datasource query and scan budgets. Budgets are
+// shared by every user of a datasource and reset at the top of each UTC
+// hour (queries) and day (bytes scanned).
+type QuotaTracker struct {
+	log logrus.FieldLogger
+	now func() time.Time
+
+	mu     sync.Mutex
+	quotas map[string]*datasourceQuota // "type:name" -> usage
+}
+
+type datasourceQuota struct {
+	dsType string
+	name   string
+	cfg    QuotaConfig
+
+	hour    time.Time
+	queries int
+	day     time.Time
+	bytes   int64
+}
+
+// QuotaUsage is the usage of one datasource's quota.
+type QuotaUsage struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	MaxQueriesPerHour int       `json:"max_queries_per_hour,omitempty"`
+	QueriesThisHour   int       `json:"queries_this_hour"`
+	QueriesRemaining  *int      `json:"queries_remaining,omitempty"`
+	HourResetsAt      time.Time `json:"hour_resets_at"`
+
+	MaxBytesScannedPerDay int64     `json:"max_bytes_scanned_per_day,omitempty"`
+	BytesScannedToday     int64     `json:"bytes_scanned_today"`
+	BytesRemaining        *int64    `json:"bytes_remaining,omitempty"`
+	DayResetsAt           time.Time `json:"day_resets_at"`
+}
+
+// QuotaUsageResponse is the response from the /quota endpoint.
+type QuotaUsageResponse struct {
+	Datasources []QuotaUsage `json:"datasources"`
+}
+
+// NewQuotaTracker creates a tracker for every datasource with a quota, or
+// returns nil when none is configured.
+func NewQuotaTracker(log logrus.FieldLogger, cfg ServerConfig) *QuotaTracker {
+	t := &QuotaTracker{
+		log:    log.WithField("component", "quota"),
+		now:    time.Now,
+		quotas: make(map[string]*datasourceQuota, 4),
+	}
+
+	add := func(dsType string, base BaseDatasourceConfig) {
+		if base.Quota.MaxQueriesPerHour > 0 || base.Quota.MaxBytesScannedPerDay > 0 {
+			t.quotas[ruleKey(dsType, base.Name)] = &datasourceQuota{dsType: dsType, name: base.Name, cfg: base.Quota}
+		}
+	}
+
+	for _, ds := range cfg.ClickHouse {
+		add("clickhouse", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Prometheus {
+		add("prometheus", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Loki {
+		add("loki", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.Grafana {
+		add("grafana", ds.BaseDatasourceConfig)
+	}
+
+	for _, ds := range cfg.HTTPJSON {
+		add("httpjson", ds.BaseDatasourceConfig)
+	}
+
+	if len(t.quotas) == 0 {
+		return nil
+	}
+
+	return t
+}
+
+// Middleware returns an HTTP middleware that rejects requests to a
+// datasource whose budget is spent: 429 for the hourly query quota and 413
+// for the daily bytes-scanned budget.
+func (t *QuotaTracker) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := t.quotas[ruleKey(extractDatasourceType(r.URL.Path), r.Header.Get(handlers.DatasourceHeader))]
+			if q == nil || quotaExemptPaths[strings.TrimSuffix(r.URL.Path, "/")] {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if status, message, resetsAt := t.reserve(q); status != 0 {
+				reason := "queries"
+				if status == http.StatusRequestEntityTooLarge {
+					reason = "bytes_scanned"
+				}
+
+				ProxyQuotaRejectionsTotal.WithLabelValues(q.dsType, q.name, reason).Inc()
+				t.log.WithFields(logrus.Fields{
+					"datasource": q.name,
+					"status":     status,
+				}).Debug("Quota exceeded")
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(resetsAt.Sub(t.now()).Seconds())+1))
+				http.Error(w, message, status)
+
+				return
+			}
+
+			if q.cfg.MaxBytesScannedPerDay > 0 && q.dsType == "clickhouse" {
+				// ClickHouse only reports the final read_bytes in the summary
+				// header when it holds the response until the query ends.
+				params := r.URL.Query()
+				params.Set("wait_end_of_query", "1")
+				r.URL.RawQuery = params.Encode()
+			}
+
+			next.ServeHTTP(w, r)
+
+			if q.cfg.MaxBytesScannedPerDay > 0 && w.Header().Get(handlers.CacheHeader) != "HIT" {
+				if scanned := readBytes(w.Header().Get(clickhouseSummaryHeader)); scanned > 0 {
+					t.addBytes(q, scanned)
+				}
+			}
+		})
+	}
+}
+
+// reserve counts a query against q, or returns the rejection status,
+// message and reset time when a budget is spent.
+func (t *QuotaTracker) reserve(q *datasourceQuota) (int, string, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hour, day := t.roll(q)
+
+	if q.cfg.MaxBytesScannedPerDay > 0 && q.bytes >= q.cfg.MaxBytesScannedPerDay {
+		return http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"quota exceeded: %s %q has scanned its daily budget of %d bytes; it resets at %s. Read quota://usage for remaining budgets.",
+			q.dsType, q.name, q.cfg.MaxBytesScannedPerDay, day.Format(time.RFC3339),
+		), day
+	}
+
+	if q.cfg.MaxQueriesPerHour > 0 && q.queries >= q.cfg.MaxQueriesPerHour {
+		return http.StatusTooManyRequests, fmt.Sprintf(
+			"quota exceeded: %s %q allows %d queries per hour; it resets at %s. Read quota://usage for remaining budgets.",
+			q.dsType, q.name, q.cfg.MaxQueriesPerHour, hour.Format(time.RFC3339),
+		), hour
+	}
+
+	q.queries++
+
+	return 0, "", time.Time{}
+}
+
+func (t *QuotaTracker) addBytes(q *datasourceQuota, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.roll(q)
+	q.bytes += n
+}
+
+// roll starts new windows for q when the current ones have ended and
+// returns when they reset. Callers must hold t.mu.
+func (t *QuotaTracker) roll(q *datasourceQuota) (time.Time, time.Time) {
+	now := t.now().UTC()
+	hour := now.Truncate(time.Hour)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if !q.hour.Equal(hour) {
+		q.hour, q.queries = hour, 0
+	}
+
+	if !q.day.Equal(day) {
+		q.day, q.bytes = day, 0
+	}
+
+	return hour.Add(time.Hour), day.AddDate(0, 0, 1)
+}
+
+// Usage returns the usage of every datasource with a quota that allowed
+// reports true for, sorted by type and name.
+func (t *QuotaTracker) Usage(allowed func(dsType, name string) bool) QuotaUsageResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resp := QuotaUsageResponse{Datasources: make([]QuotaUsage, 0, len(t.quotas))}
+
+	for _, q := range t.quotas {
+		if !allowed(q.dsType, q.name) {
+			continue
+		}
+
+		hourReset, dayReset := t.roll(q)
+		usage := QuotaUsage{
+			Type:                  q.dsType,
+			Name:                  q.name,
+			MaxQueriesPerHour:     q.cfg.MaxQueriesPerHour,
+			QueriesThisHour:       q.queries,
+			HourResetsAt:          hourReset,
+			MaxBytesScannedPerDay: q.cfg.MaxBytesScannedPerDay,
+			BytesScannedToday:     q.bytes,
+			DayResetsAt:           dayReset,
+		}
+
+		if q.cfg.MaxQueriesPerHour > 0 {
+			remaining := max(q.cfg.MaxQueriesPerHour-q.queries, 0)
+			usage.QueriesRemaining = &remaining
+		}
+
+		if q.cfg.MaxBytesScannedPerDay > 0 {
+			remaining := max(q.cfg.MaxBytesScannedPerDay-q.bytes, 0)
+			usage.BytesRemaining = &remaining
+		}
+
+		resp.Datasources = append(resp.Datasources, usage)
+	}
+
+	sort.Slice(resp.Datasources, func(i, j int) bool {
+		a, b := resp.Datasources[i], resp.Datasources[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		return a.Name < b.Name
+	})
+
+	return resp
+}
+
+// readBytes extracts read_bytes from an X-ClickHouse-Summary header, whose
+// values ClickHouse encodes as strings.
+func readBytes(summary string) int64 {
+	if summary == "" {
+		return 0
+	}
+
+	var parsed struct {
+		ReadBytes json.Number `json:"read_bytes"`
+	}
+
+	if err := json.Unmarshal([]byte(summary), &parsed); err != nil {
+		return 0
+	}
+
+	n, err := parsed.ReadBytes.Int64()
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// handleQuota reports quota usage for the datasources the caller may access.
+func (s *server) handleQuota(w http.ResponseWriter, r *http.Request) {
+	allowed := func(dsType, name string) bool {
+		return s.authorizer == nil || s.authorizer.isAllowed(r.Context(), dsType, name)
+	}
+
+	resp := QuotaUsageResponse{Datasources: []QuotaUsage{}}
+	if s.quotas != nil {
+		resp = s.quotas.Usage(allowed)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log.WithError(err).Error("Failed to encode quota response")
+	}
+}
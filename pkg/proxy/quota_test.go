@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+)
+
+func TestQuotaTracker(t *testing.T) {
+	cfg := ServerConfig{
+		ClickHouse: []ClickHouseClusterConfig{
+			{BaseDatasourceConfig: BaseDatasourceConfig{
+				Name:  "xatu",
+				Quota: QuotaConfig{MaxQueriesPerHour: 3, MaxBytesScannedPerDay: 1000},
+			}},
+			{BaseDatasourceConfig: BaseDatasourceConfig{Name: "unlimited"}},
+		},
+	}
+
+	tracker := NewQuotaTracker(logrus.New(), cfg)
+	require.NotNil(t, tracker)
+
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	var upstreamParams []string
+
+	handler := tracker.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamParams = append(upstreamParams, r.URL.RawQuery)
+		w.Header().Set(clickhouseSummaryHeader, `{"read_rows":"10","read_bytes":"600"}`)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(path, datasource string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(handlers.DatasourceHeader, datasource)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, do("/clickhouse/", "xatu").Code)
+	assert.Equal(t, "wait_end_of_query=1", upstreamParams[0])
+	assert.Equal(t, http.StatusOK, do("/clickhouse/ping", "xatu").Code, "health probes are exempt")
+	assert.Equal(t, http.StatusOK, do("/clickhouse/", "xatu").Code)
+
+	// 1200 bytes scanned: the daily budget is spent.
+	rec := do("/clickhouse/", "xatu")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "daily budget of 1000 bytes")
+	assert.Equal(t, "48601", rec.Header().Get("Retry-After"))
+
+	for range 5 {
+		assert.Equal(t, http.StatusOK, do("/clickhouse/", "unlimited").Code)
+	}
+
+	usage := tracker.Usage(func(string, string) bool { return true })
+	require.Len(t, usage.Datasources, 1)
+	assert.Equal(t, 2, usage.Datasources[0].QueriesThisHour)
+	assert.Equal(t, 1, *usage.Datasources[0].QueriesRemaining)
+	assert.Equal(t, int64(1200), usage.Datasources[0].BytesScannedToday)
+	assert.Equal(t, int64(0), *usage.Datasources[0].BytesRemaining)
+	assert.Empty(t, tracker.Usage(func(string, string) bool { return false }).Datasources)
+
+	// A new day resets both windows; the hourly query quota then applies.
+	now = now.Add(24 * time.Hour)
+
+	tracker.quotas[ruleKey("clickhouse", "xatu")].cfg.MaxBytesScannedPerDay = 0
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, do("/clickhouse/", "xatu").Code)
+	}
+
+	rec = do("/clickhouse/", "xatu")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "allows 3 queries per hour")
+	assert.Equal(t, "1801", rec.Header().Get("Retry-After"))
+}
+
+func TestNewQuotaTrackerWithoutQuotas(t *testing.T) {
+	assert.Nil(t, NewQuotaTracker(logrus.New(), testConfig()))
+}
+
+func TestValidateQuotas(t *testing.T) {
+	cfg := testConfig()
+	cfg.Prometheus[0].Quota = QuotaConfig{MaxBytesScannedPerDay: 10}
+	assert.ErrorContains(t, cfg.validateQuotas(), "only supported for clickhouse")
+
+	cfg = testConfig()
+	cfg.ClickHouse[0].Quota = QuotaConfig{MaxQueriesPerHour: -1}
+	assert.ErrorContains(t, cfg.validateQuotas(), "must not be negative")
+
+	cfg = testConfig()
+	cfg.ClickHouse[0].Quota = QuotaConfig{MaxQueriesPerHour: 100, MaxBytesScannedPerDay: 1 << 40}
+	assert.NoError(t, cfg.validateQuotas())
+}
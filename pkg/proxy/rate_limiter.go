@@ -1,14 +1,27 @@
 package proxy
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
+
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
+// RateLimitError is the body of a request rejected by the rate limiter.
+type RateLimitError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	types.RateLimitState
+}
+
 // RateLimiter provides per-user rate limiting for the proxy.
 type RateLimiter struct {
 	log      logrus.FieldLogger
@@ -17,6 +30,7 @@ type RateLimiter struct {
 	mu       sync.RWMutex
 	stopCh   chan struct{}
 	stopped  bool
+	now      func() time.Time
 }
 
 // RateLimiterConfig configures the rate limiter.
@@ -35,6 +49,7 @@ func NewRateLimiter(log logrus.FieldLogger, cfg RateLimiterConfig) *RateLimiter
 		cfg:      cfg,
 		limiters: make(map[string]*rate.Limiter, 64),
 		stopCh:   make(chan struct{}),
+		now:      time.Now,
 	}
 
 	// Start cleanup goroutine.
@@ -75,7 +90,62 @@ func (rl *RateLimiter) Allow(userID string) bool {
 	return rl.getLimiter(userID).Allow()
 }
 
-// Middleware returns an HTTP middleware that enforces rate limiting.
+// reserve takes a request from userID's limit if one is available and
+// returns the limit's state afterwards. The state is advisory: concurrent
+// requests by the same user may spend tokens between the two reads.
+func (rl *RateLimiter) reserve(userID string) (bool, types.RateLimitState) {
+	now := rl.now()
+	limiter := rl.getLimiter(userID)
+	allowed := limiter.AllowN(now, 1)
+
+	return allowed, rl.state(limiter.TokensAt(now), now)
+}
+
+// state derives a RateLimitState from the tokens left in a limiter's bucket,
+// which refills at RequestsPerMinute up to BurstSize.
+func (rl *RateLimiter) state(tokens float64, now time.Time) types.RateLimitState {
+	state := types.RateLimitState{
+		Limit:             rl.cfg.BurstSize,
+		Remaining:         max(int(math.Floor(tokens)), 0),
+		RequestsPerMinute: rl.cfg.RequestsPerMinute,
+		RetryAt:           now,
+		ResetAt:           now,
+	}
+
+	perSecond := float64(rl.cfg.RequestsPerMinute) / 60
+	if perSecond <= 0 {
+		return state
+	}
+
+	if tokens < 1 {
+		state.RetryAfterSeconds = ceilSeconds((1 - tokens) / perSecond)
+		state.RetryAt = now.Add(time.Duration(state.RetryAfterSeconds) * time.Second)
+	}
+
+	if burst := float64(rl.cfg.BurstSize); tokens < burst {
+		state.ResetAt = now.Add(time.Duration(ceilSeconds((burst-tokens)/perSecond)) * time.Second)
+	}
+
+	return state
+}
+
+// setHeaders sets the RateLimit fields of draft-ietf-httpapi-ratelimit-headers
+// (draft 07). The policy describes the bucket as BurstSize requests per the
+// window it takes to refill from empty.
+func (rl *RateLimiter) setHeaders(h http.Header, state types.RateLimitState) {
+	h.Set("RateLimit-Limit", strconv.Itoa(state.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(state.ResetAt.Sub(rl.now()).Seconds())))
+
+	if rl.cfg.RequestsPerMinute > 0 {
+		window := ceilSeconds(float64(rl.cfg.BurstSize) * 60 / float64(rl.cfg.RequestsPerMinute))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rl.cfg.BurstSize, window))
+	}
+}
+
+// Middleware returns an HTTP middleware that enforces rate limiting. Every
+// response carries RateLimit headers; rejections get a 429 with Retry-After
+// and a RateLimitError body.
 func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -87,13 +157,33 @@ func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			if !rl.Allow(userID) {
-				rl.log.WithField("user_id", userID).Debug("Rate limit exceeded")
+			allowed, state := rl.reserve(userID)
+			rl.setHeaders(w.Header(), state)
+
+			if !allowed {
+				rl.log.WithFields(logrus.Fields{
+					"user_id":     userID,
+					"retry_after": state.RetryAfterSeconds,
+				}).Debug("Rate limit exceeded")
 
 				ProxyRateLimitRejectionsTotal.WithLabelValues(extractDatasourceType(r.URL.Path)).Inc()
 
-				w.Header().Set("Retry-After", "60")
-				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				body := RateLimitError{
+					Error: types.RateLimitErrorCode,
+					Message: fmt.Sprintf(
+						"rate limit exceeded: %d requests per minute with bursts of %d; retry after %ds (%s).",
+						state.RequestsPerMinute, state.Limit, state.RetryAfterSeconds, state.RetryAt.Format(time.RFC3339),
+					),
+					RateLimitState: state,
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", strconv.Itoa(state.RetryAfterSeconds))
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				if err := json.NewEncoder(w).Encode(body); err != nil {
+					rl.log.WithError(err).Error("Failed to encode rate limit error")
+				}
 
 				return
 			}
@@ -103,6 +193,11 @@ func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
+// ceilSeconds rounds seconds up to a whole number.
+func ceilSeconds(seconds float64) int {
+	return max(int(math.Ceil(seconds)), 0)
+}
+
 // Stop stops the rate limiter cleanup goroutine.
 func (rl *RateLimiter) Stop() {
 	rl.mu.Lock()
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestRateLimiterReportsStateFromBucket(t *testing.T) {
+	t.Parallel()
+
+	rl := NewRateLimiter(logrus.New(), RateLimiterConfig{RequestsPerMinute: 60, BurstSize: 2})
+	defer rl.Stop()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rl.now = func() time.Time { return now }
+
+	handler := rl.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/clickhouse/", nil)
+		req = req.WithContext(withAuthUser(req.Context(), &AuthUser{Subject: "user-123"}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	for i, want := range []struct{ remaining, reset string }{{"1", "1"}, {"0", "2"}} {
+		rec := request()
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: expected status %d, got %d", i+1, http.StatusNoContent, rec.Code)
+		}
+
+		if got := rec.Header().Get("RateLimit-Remaining"); got != want.remaining {
+			t.Fatalf("request %d: expected RateLimit-Remaining %s, got %q", i+1, want.remaining, got)
+		}
+
+		if got := rec.Header().Get("RateLimit-Reset"); got != want.reset {
+			t.Fatalf("request %d: expected RateLimit-Reset %s, got %q", i+1, want.reset, got)
+		}
+
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=2" {
+			t.Fatalf("request %d: expected RateLimit-Policy 2;w=2, got %q", i+1, got)
+		}
+	}
+
+	rec := request()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+
+	var body RateLimitError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding rate limit error: %v", err)
+	}
+
+	if body.Error != types.RateLimitErrorCode || body.Remaining != 0 || body.Limit != 2 || body.RequestsPerMinute != 60 {
+		t.Fatalf("unexpected rate limit error: %+v", body)
+	}
+
+	if !body.RetryAt.Equal(now.Add(time.Second)) || !body.ResetAt.Equal(now.Add(2*time.Second)) {
+		t.Fatalf("expected retry at %s and reset at %s, got %+v", now.Add(time.Second), now.Add(2*time.Second), body)
+	}
+
+	now = now.Add(time.Second)
+
+	if rec := request(); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected a request after Retry-After to be allowed, got status %d", rec.Code)
+	}
+}
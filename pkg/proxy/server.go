@@ -2,10 +2,12 @@ package proxy
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -14,7 +16,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
 	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -38,6 +42,15 @@ type Server interface {
 
 	// LokiDatasources returns the list of Loki datasource names.
 	LokiDatasources() []string
+
+	// GrafanaDatasources returns the list of Grafana instance names.
+	GrafanaDatasources() []string
+
+	// HTTPJSONDatasources returns the list of HTTP JSON endpoint names.
+	HTTPJSONDatasources() []string
+
+	// GitHubDatasources returns the list of readable GitHub repositories.
+	GitHubDatasources() []string
 }
 
 // server implements the Server interface.
@@ -48,18 +61,31 @@ type server struct {
 	mux     *chi.Mux
 	url     string
 
+	// listener, when set, is served instead of binding ListenAddr.
+	listener net.Listener
+
 	authenticator Authenticator
 	authService   simpleauth.SimpleService
 	authorizer    *Authorizer
+	verifier      *RequestVerifier
 	rateLimiter   *RateLimiter
+	quotas        *QuotaTracker
+	maintenance   *MaintenanceGate
 	auditor       *Auditor
 
 	clickhouseHandler *handlers.ClickHouseHandler
 	prometheusHandler *handlers.PrometheusHandler
 	lokiHandler       *handlers.LokiHandler
+	grafanaHandler    *handlers.GrafanaHandler
+	httpJSONHandler   *handlers.HTTPJSONHandler
 	ethNodeHandler    *handlers.EthNodeHandler
+	gitHubHandler     *handlers.GitHubHandler
 	embeddingService  *EmbeddingService
 
+	// handlersMu guards the upstream handlers, which ReloadCredentials
+	// rebuilds when credentials rotate.
+	handlersMu sync.RWMutex
+
 	mu      sync.RWMutex
 	started bool
 }
@@ -110,8 +136,10 @@ func newServer(log logrus.FieldLogger, cfg ServerConfig, hostURL, port string) (
 		s.authenticator = NewSimpleServiceAuthenticator(authSvc)
 	case AuthModeOIDC:
 		oidcAuth, err := NewOIDCAuthenticator(log, OIDCAuthenticatorConfig{
-			IssuerURL: cfg.Auth.IssuerURL,
-			ClientID:  cfg.Auth.ClientID,
+			IssuerURL:         cfg.Auth.IssuerURL,
+			ClientID:          cfg.Auth.ClientID,
+			SigningAlgorithms: cfg.Auth.SigningAlgorithms,
+			ClockSkew:         cfg.Auth.ClockSkew,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("creating OIDC authenticator: %w", err)
@@ -122,6 +150,15 @@ func newServer(log logrus.FieldLogger, cfg ServerConfig, hostURL, port string) (
 		return nil, fmt.Errorf("unsupported auth mode: %s", cfg.Auth.Mode)
 	}
 
+	// Create request signature verifier if a signing key is configured.
+	if cfg.Auth.RequestSigning.SecretKey != "" {
+		s.verifier = NewRequestVerifier(log, RequestVerifierConfig{
+			SecretKey:    cfg.Auth.RequestSigning.SecretKey,
+			MaxClockSkew: cfg.Auth.RequestSigning.MaxClockSkew,
+			MaxBodyBytes: cfg.Auth.RequestSigning.MaxBodyBytes,
+		})
+	}
+
 	// Create rate limiter if enabled.
 	if cfg.RateLimiting.Enabled {
 		s.rateLimiter = NewRateLimiter(log, RateLimiterConfig{
@@ -132,30 +169,34 @@ func newServer(log logrus.FieldLogger, cfg ServerConfig, hostURL, port string) (
 
 	// Create auditor if enabled.
 	if cfg.Audit.Enabled {
-		s.auditor = NewAuditor(log)
+		sinks, err := cfg.Audit.Sinks.Build()
+		if err != nil {
+			return nil, fmt.Errorf("creating audit sinks: %w", err)
+		}
+
+		var dispatcher *audit.Dispatcher
+		if len(sinks) > 0 {
+			dispatcher = audit.NewDispatcher(log, audit.DispatcherConfig{
+				BufferSize:    cfg.Audit.BufferSize,
+				BatchSize:     cfg.Audit.BatchSize,
+				FlushInterval: cfg.Audit.FlushInterval,
+			}, sinks)
+		}
+
+		s.auditor = NewAuditor(log, dispatcher, cfg.Audit.RecentSize)
 	}
 
 	// Create authorizer for per-datasource access control.
 	s.authorizer = NewAuthorizer(log, cfg)
 
-	// Create handlers from config.
-	chConfigs, promConfigs, lokiConfigs, ethNodeConfig := cfg.ToHandlerConfigs()
-
-	if len(chConfigs) > 0 {
-		s.clickhouseHandler = handlers.NewClickHouseHandler(log, chConfigs)
-	}
-
-	if len(promConfigs) > 0 {
-		s.prometheusHandler = handlers.NewPrometheusHandler(log, promConfigs)
-	}
+	// Track per-datasource quotas, if any are configured.
+	s.quotas = NewQuotaTracker(log, cfg)
 
-	if len(lokiConfigs) > 0 {
-		s.lokiHandler = handlers.NewLokiHandler(log, lokiConfigs)
-	}
+	// Reject requests during datasource maintenance windows.
+	s.maintenance = NewMaintenanceGate(log, cfg)
 
-	if ethNodeConfig != nil {
-		s.ethNodeHandler = handlers.NewEthNodeHandler(log, *ethNodeConfig)
-	}
+	// Create handlers from config.
+	s.buildHandlers(cfg)
 
 	// Create embedding service if configured.
 	if cfg.Embedding != nil {
@@ -175,7 +216,12 @@ func newServer(log logrus.FieldLogger, cfg ServerConfig, hostURL, port string) (
 	}
 
 	if s.url == "" {
-		s.url = fmt.Sprintf("http://localhost:%s", port)
+		scheme := "http"
+		if cfg.Server.TLS.Enabled() {
+			scheme = "https"
+		}
+
+		s.url = fmt.Sprintf("%s://localhost:%s", scheme, port)
 	}
 
 	// Register routes.
@@ -184,6 +230,48 @@ func newServer(log logrus.FieldLogger, cfg ServerConfig, hostURL, port string) (
 	return s, nil
 }
 
+// buildHandlers creates the upstream handlers, which hold the datasource
+// credentials, from cfg.
+func (s *server) buildHandlers(cfg ServerConfig) {
+	chConfigs, promConfigs, lokiConfigs, grafanaConfigs, httpJSONConfigs, ethNodeConfig, gitHubConfig := cfg.ToHandlerConfigs()
+
+	if len(chConfigs) > 0 {
+		var cacheCfg *handlers.ClickHouseCacheConfig
+		if cfg.ClickHouseCache.Enabled {
+			cacheCfg = &handlers.ClickHouseCacheConfig{
+				TTL:          cfg.ClickHouseCache.TTL,
+				MaxSizeBytes: int64(cfg.ClickHouseCache.MaxSizeMB) * 1024 * 1024,
+			}
+		}
+
+		s.clickhouseHandler = handlers.NewClickHouseHandler(s.log, chConfigs, cacheCfg)
+	}
+
+	if len(promConfigs) > 0 {
+		s.prometheusHandler = handlers.NewPrometheusHandler(s.log, promConfigs)
+	}
+
+	if len(lokiConfigs) > 0 {
+		s.lokiHandler = handlers.NewLokiHandler(s.log, lokiConfigs)
+	}
+
+	if len(grafanaConfigs) > 0 {
+		s.grafanaHandler = handlers.NewGrafanaHandler(s.log, grafanaConfigs)
+	}
+
+	if len(httpJSONConfigs) > 0 {
+		s.httpJSONHandler = handlers.NewHTTPJSONHandler(s.log, httpJSONConfigs)
+	}
+
+	if ethNodeConfig != nil {
+		s.ethNodeHandler = handlers.NewEthNodeHandler(s.log, *ethNodeConfig)
+	}
+
+	if gitHubConfig != nil {
+		s.gitHubHandler = handlers.NewGitHubHandler(s.log, *gitHubConfig)
+	}
+}
+
 // registerRoutes sets up the HTTP routes.
 func (s *server) registerRoutes() {
 	// Health check endpoint (no auth required).
@@ -223,6 +311,12 @@ func (s *server) registerRoutes() {
 
 	s.mux.Handle("/datasources", s.metricsMiddleware(chain(http.HandlerFunc(s.handleDatasources))))
 
+	s.mux.Method(http.MethodGet, "/quota", s.metricsMiddleware(chain(http.HandlerFunc(s.handleQuota))))
+
+	if s.auditor != nil {
+		s.mux.Method(http.MethodGet, "/audit", s.metricsMiddleware(chain(http.HandlerFunc(s.handleAudit))))
+	}
+
 	if s.embeddingService != nil {
 		s.mux.Method(http.MethodPost, "/embed", s.metricsMiddleware(chain(http.HandlerFunc(s.handleEmbed))))
 		s.mux.Method(http.MethodPost, "/embed/check", s.metricsMiddleware(chain(http.HandlerFunc(s.handleEmbedCheck))))
@@ -230,20 +324,32 @@ func (s *server) registerRoutes() {
 
 	// Authenticated routes.
 	if s.clickhouseHandler != nil {
-		s.handleSubtreeRoute("/clickhouse", s.metricsMiddleware(chain(s.clickhouseHandler)))
+		s.handleSubtreeRoute("/clickhouse", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.clickhouseHandler }))))
 	}
 
 	if s.prometheusHandler != nil {
-		s.handleSubtreeRoute("/prometheus", s.metricsMiddleware(chain(s.prometheusHandler)))
+		s.handleSubtreeRoute("/prometheus", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.prometheusHandler }))))
 	}
 
 	if s.lokiHandler != nil {
-		s.handleSubtreeRoute("/loki", s.metricsMiddleware(chain(s.lokiHandler)))
+		s.handleSubtreeRoute("/loki", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.lokiHandler }))))
+	}
+
+	if s.grafanaHandler != nil {
+		s.handleSubtreeRoute("/grafana", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.grafanaHandler }))))
+	}
+
+	if s.httpJSONHandler != nil {
+		s.handleSubtreeRoute("/httpjson", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.httpJSONHandler }))))
 	}
 
 	if s.ethNodeHandler != nil {
-		s.handleSubtreeRoute("/beacon", s.metricsMiddleware(chain(s.ethNodeHandler)))
-		s.handleSubtreeRoute("/execution", s.metricsMiddleware(chain(s.ethNodeHandler)))
+		s.handleSubtreeRoute("/beacon", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.ethNodeHandler }))))
+		s.handleSubtreeRoute("/execution", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.ethNodeHandler }))))
+	}
+
+	if s.gitHubHandler != nil {
+		s.handleSubtreeRoute("/github", s.metricsMiddleware(chain(s.upstream(func() http.Handler { return s.gitHubHandler }))))
 	}
 }
 
@@ -260,7 +366,18 @@ func (s *server) buildMiddlewareChain() func(http.Handler) http.Handler {
 	return func(handler http.Handler) http.Handler {
 		h := handler
 
-		// Rate limiting (innermost).
+		// Datasource quotas (innermost).
+		if s.quotas != nil {
+			h = s.quotas.Middleware()(h)
+		}
+
+		// Maintenance windows, before quotas so rejected requests are not
+		// counted against them.
+		if s.maintenance != nil {
+			h = s.maintenance.Middleware()(h)
+		}
+
+		// Per-user rate limiting.
 		if s.rateLimiter != nil {
 			h = s.rateLimiter.Middleware()(h)
 		}
@@ -276,9 +393,18 @@ func (s *server) buildMiddlewareChain() func(http.Handler) http.Handler {
 			h = s.auditor.Middleware()(h)
 		}
 
-		// Authentication (outermost).
+		// Record who the request is for, for audit entries and query tags.
+		h = requestIdentityMiddleware(h)
+
+		// Authentication.
 		h = s.authenticator.Middleware()(h)
 
+		// Request signature verification (outermost) so that a leaked bearer
+		// token alone is not enough to reach authenticated routes.
+		if s.verifier != nil {
+			h = s.verifier.Middleware()(h)
+		}
+
 		return h
 	}
 }
@@ -289,9 +415,15 @@ type DatasourcesResponse struct {
 	ClickHouse         []string               `json:"clickhouse,omitempty"`
 	Prometheus         []string               `json:"prometheus,omitempty"`
 	Loki               []string               `json:"loki,omitempty"`
+	Grafana            []string               `json:"grafana,omitempty"`
+	HTTPJSON           []string               `json:"httpjson,omitempty"`
 	ClickHouseInfo     []types.DatasourceInfo `json:"clickhouse_info,omitempty"`
 	PrometheusInfo     []types.DatasourceInfo `json:"prometheus_info,omitempty"`
 	LokiInfo           []types.DatasourceInfo `json:"loki_info,omitempty"`
+	GrafanaInfo        []types.DatasourceInfo `json:"grafana_info,omitempty"`
+	HTTPJSONInfo       []types.DatasourceInfo `json:"httpjson_info,omitempty"`
+	GitHub             []string               `json:"github,omitempty"`
+	GitHubInfo         []types.DatasourceInfo `json:"github_info,omitempty"`
 	EthNodeAvailable   bool                   `json:"ethnode_available,omitempty"`
 	EmbeddingAvailable bool                   `json:"embedding_available,omitempty"`
 	EmbeddingModel     string                 `json:"embedding_model,omitempty"`
@@ -304,9 +436,15 @@ func (s *server) handleDatasources(w http.ResponseWriter, r *http.Request) {
 		ClickHouse:         s.ClickHouseDatasources(),
 		Prometheus:         s.PrometheusDatasources(),
 		Loki:               s.LokiDatasources(),
+		Grafana:            s.GrafanaDatasources(),
+		HTTPJSON:           s.HTTPJSONDatasources(),
 		ClickHouseInfo:     s.ClickHouseDatasourceInfo(),
 		PrometheusInfo:     s.PrometheusDatasourceInfo(),
 		LokiInfo:           s.LokiDatasourceInfo(),
+		GrafanaInfo:        s.GrafanaDatasourceInfo(),
+		HTTPJSONInfo:       s.HTTPJSONDatasourceInfo(),
+		GitHub:             s.GitHubDatasources(),
+		GitHubInfo:         s.GitHubDatasourceInfo(),
 		EthNodeAvailable:   s.EthNodeAvailable(),
 		EmbeddingAvailable: s.EmbeddingAvailable(),
 		EmbeddingModel:     s.EmbeddingModel(),
@@ -324,6 +462,45 @@ func (s *server) handleDatasources(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleAudit searches recent audit entries. It is restricted to members of
+// audit.admin_orgs, or open to everyone in auth mode "none".
+func (s *server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if !s.isAuditAdmin(r.Context()) {
+		http.Error(w, "audit search requires membership of audit.admin_orgs", http.StatusForbidden)
+
+		return
+	}
+
+	query, err := audit.ParseQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(s.auditor.Search(query)); err != nil {
+		s.log.WithError(err).Error("Failed to encode audit response")
+	}
+}
+
+// isAuditAdmin reports whether the caller may search audit entries.
+func (s *server) isAuditAdmin(ctx context.Context) bool {
+	if s.cfg.Auth.Mode == AuthModeNone {
+		return true
+	}
+
+	for _, org := range getUserOrgs(ctx) {
+		if slices.Contains(s.cfg.Audit.AdminOrgs, org) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // handleEmbed handles embedding requests by delegating to the embedding service.
 func (s *server) handleEmbed(w http.ResponseWriter, r *http.Request) {
 	var req EmbedRequest
@@ -439,13 +616,35 @@ func (s *server) Start(ctx context.Context) error {
 	}
 
 	// Create listener first to detect port conflicts immediately.
-	listener, err := net.Listen("tcp", s.cfg.Server.ListenAddr)
-	if err != nil {
-		return fmt.Errorf("binding to %s: %w", s.cfg.Server.ListenAddr, err)
+	listener := s.listener
+	if listener == nil {
+		var err error
+
+		listener, err = net.Listen("tcp", s.cfg.Server.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("binding to %s: %w", s.cfg.Server.ListenAddr, err)
+		}
+	}
+
+	var handler http.Handler = s.mux
+
+	if tlsCfg := s.cfg.Server.TLS; tlsCfg.Enabled() {
+		serverTLS, err := tlsCfg.TLSConfig()
+		if err != nil {
+			_ = listener.Close()
+
+			return fmt.Errorf("configuring TLS: %w", err)
+		}
+
+		listener = tls.NewListener(listener, serverTLS)
+
+		if tlsCfg.RequiresClientCert() {
+			handler = tlsconfig.RequireClientCert(handler, "/health", "/ready")
+		}
 	}
 
 	s.httpSrv = &http.Server{
-		Handler:           s.mux,
+		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       s.cfg.Server.ReadTimeout,
 		WriteTimeout:      s.cfg.Server.WriteTimeout,
@@ -453,7 +652,11 @@ func (s *server) Start(ctx context.Context) error {
 		BaseContext:       func(_ net.Listener) context.Context { return ctx },
 	}
 
-	s.log.WithField("addr", s.cfg.Server.ListenAddr).Info("Starting proxy server")
+	s.log.WithFields(logrus.Fields{
+		"addr": s.cfg.Server.ListenAddr,
+		"tls":  s.cfg.Server.TLS.Enabled(),
+		"mtls": s.cfg.Server.TLS.RequiresClientCert(),
+	}).Info("Starting proxy server")
 
 	// Start server in background with the already-bound listener.
 	go func() {
@@ -462,6 +665,14 @@ func (s *server) Start(ctx context.Context) error {
 		}
 	}()
 
+	if s.auditor != nil {
+		s.auditor.Start()
+	}
+
+	if interval := s.cfg.Secrets.RefreshInterval; interval > 0 && s.cfg.Path() != "" {
+		go s.refreshCredentials(ctx, s.cfg.Path(), interval)
+	}
+
 	s.started = true
 
 	return nil
@@ -494,12 +705,24 @@ func (s *server) Stop(ctx context.Context) error {
 	}
 
 	// Shutdown HTTP server.
+	var shutdownErr error
 	if s.httpSrv != nil {
 		if err := s.httpSrv.Shutdown(ctx); err != nil {
-			return fmt.Errorf("shutting down proxy server: %w", err)
+			shutdownErr = fmt.Errorf("shutting down proxy server: %w", err)
+		}
+	}
+
+	// Flush audit entries once no more requests can arrive.
+	if s.auditor != nil {
+		if err := s.auditor.Stop(ctx); err != nil {
+			s.log.WithError(err).Warn("Error flushing audit sinks")
 		}
 	}
 
+	if shutdownErr != nil {
+		return shutdownErr
+	}
+
 	s.started = false
 	s.log.Info("Proxy server stopped")
 
@@ -518,8 +741,19 @@ func (s *server) RegisterToken(executionID string) string {
 func (s *server) RevokeToken(executionID string) {
 }
 
+func (s *server) Transport() http.RoundTripper {
+	return http.DefaultTransport
+}
+
+func (s *server) SignRequest(_ *http.Request) error {
+	return nil
+}
+
 // ClickHouseDatasources returns the list of ClickHouse datasource names.
 func (s *server) ClickHouseDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
 	if s.clickhouseHandler == nil {
 		return nil
 	}
@@ -533,12 +767,16 @@ func (s *server) ClickHouseDatasourceInfo() []types.DatasourceInfo {
 		return nil
 	}
 
+	breakers := s.circuitBreakers("clickhouse")
+
 	result := make([]types.DatasourceInfo, 0, len(s.cfg.ClickHouse))
 	for _, ch := range s.cfg.ClickHouse {
 		info := types.DatasourceInfo{
-			Type:        "clickhouse",
-			Name:        ch.Name,
-			Description: ch.Description,
+			Type:           "clickhouse",
+			Name:           ch.Name,
+			Description:    ch.Description,
+			Maintenance:    ch.Maintenance,
+			CircuitBreaker: breakers[ch.Name],
 		}
 		if ch.Database != "" {
 			info.Metadata = map[string]string{
@@ -553,6 +791,9 @@ func (s *server) ClickHouseDatasourceInfo() []types.DatasourceInfo {
 
 // PrometheusDatasources returns the list of Prometheus datasource names.
 func (s *server) PrometheusDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
 	if s.prometheusHandler == nil {
 		return nil
 	}
@@ -566,18 +807,29 @@ func (s *server) PrometheusDatasourceInfo() []types.DatasourceInfo {
 		return nil
 	}
 
+	breakers := s.circuitBreakers("prometheus")
+
 	result := make([]types.DatasourceInfo, 0, len(s.cfg.Prometheus))
 	for _, prom := range s.cfg.Prometheus {
 		info := types.DatasourceInfo{
-			Type:        "prometheus",
-			Name:        prom.Name,
-			Description: prom.Description,
+			Type:           "prometheus",
+			Name:           prom.Name,
+			Description:    prom.Description,
+			Maintenance:    prom.Maintenance,
+			CircuitBreaker: breakers[prom.Name],
 		}
 		if prom.URL != "" {
 			info.Metadata = map[string]string{
 				"url": prom.URL,
 			}
 		}
+		if prom.SelfMonitoring {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string, 1)
+			}
+
+			info.Metadata["self_monitoring"] = "true"
+		}
 		result = append(result, info)
 	}
 
@@ -586,6 +838,9 @@ func (s *server) PrometheusDatasourceInfo() []types.DatasourceInfo {
 
 // LokiDatasources returns the list of Loki datasource names.
 func (s *server) LokiDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
 	if s.lokiHandler == nil {
 		return nil
 	}
@@ -599,12 +854,16 @@ func (s *server) LokiDatasourceInfo() []types.DatasourceInfo {
 		return nil
 	}
 
+	breakers := s.circuitBreakers("loki")
+
 	result := make([]types.DatasourceInfo, 0, len(s.cfg.Loki))
 	for _, loki := range s.cfg.Loki {
 		info := types.DatasourceInfo{
-			Type:        "loki",
-			Name:        loki.Name,
-			Description: loki.Description,
+			Type:           "loki",
+			Name:           loki.Name,
+			Description:    loki.Description,
+			Maintenance:    loki.Maintenance,
+			CircuitBreaker: breakers[loki.Name],
 		}
 		if loki.URL != "" {
 			info.Metadata = map[string]string{
@@ -617,8 +876,153 @@ func (s *server) LokiDatasourceInfo() []types.DatasourceInfo {
 	return result
 }
 
+// GrafanaDatasources returns the list of Grafana instance names.
+func (s *server) GrafanaDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	if s.grafanaHandler == nil {
+		return nil
+	}
+
+	return s.grafanaHandler.Instances()
+}
+
+// GrafanaDatasourceInfo returns detailed Grafana instance info.
+func (s *server) GrafanaDatasourceInfo() []types.DatasourceInfo {
+	if len(s.cfg.Grafana) == 0 {
+		return nil
+	}
+
+	breakers := s.circuitBreakers("grafana")
+
+	result := make([]types.DatasourceInfo, 0, len(s.cfg.Grafana))
+	for _, grafana := range s.cfg.Grafana {
+		info := types.DatasourceInfo{
+			Type:           "grafana",
+			Name:           grafana.Name,
+			Description:    grafana.Description,
+			Maintenance:    grafana.Maintenance,
+			CircuitBreaker: breakers[grafana.Name],
+		}
+		if grafana.URL != "" {
+			info.Metadata = map[string]string{
+				"url": grafana.URL,
+			}
+		}
+		result = append(result, info)
+	}
+
+	return result
+}
+
+// HTTPJSONDatasources returns the list of HTTP JSON endpoint names.
+func (s *server) HTTPJSONDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	if s.httpJSONHandler == nil {
+		return nil
+	}
+
+	return s.httpJSONHandler.Instances()
+}
+
+// HTTPJSONDatasourceInfo returns detailed HTTP JSON endpoint info. The
+// upstream URL and headers stay in the proxy; callers only learn which
+// paths they may request.
+func (s *server) HTTPJSONDatasourceInfo() []types.DatasourceInfo {
+	if len(s.cfg.HTTPJSON) == 0 {
+		return nil
+	}
+
+	breakers := s.circuitBreakers("httpjson")
+
+	result := make([]types.DatasourceInfo, 0, len(s.cfg.HTTPJSON))
+	for _, endpoint := range s.cfg.HTTPJSON {
+		info := types.DatasourceInfo{
+			Type:           "httpjson",
+			Name:           endpoint.Name,
+			Description:    endpoint.Description,
+			Maintenance:    endpoint.Maintenance,
+			CircuitBreaker: breakers[endpoint.Name],
+			Metadata: map[string]string{
+				"allowed_paths": strings.Join(endpoint.AllowedPaths, ","),
+			},
+		}
+		if endpoint.Network != "" {
+			info.Metadata["network"] = endpoint.Network
+		}
+		result = append(result, info)
+	}
+
+	return result
+}
+
+// GitHubDatasources returns the list of readable GitHub repositories.
+func (s *server) GitHubDatasources() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	if s.gitHubHandler == nil {
+		return nil
+	}
+
+	return s.gitHubHandler.Repos()
+}
+
+// GitHubDatasourceInfo returns detailed GitHub repository info. The token
+// stays in the proxy.
+func (s *server) GitHubDatasourceInfo() []types.DatasourceInfo {
+	if s.cfg.GitHub == nil {
+		return nil
+	}
+
+	result := make([]types.DatasourceInfo, 0, len(s.cfg.GitHub.Repos))
+	for _, repo := range s.cfg.GitHub.Repos {
+		info := types.DatasourceInfo{
+			Type:        "github",
+			Name:        repo.Name,
+			Description: repo.Description,
+		}
+		if repo.Client != "" {
+			info.Metadata = map[string]string{
+				"client": repo.Client,
+			}
+		}
+		result = append(result, info)
+	}
+
+	return result
+}
+
+// circuitBreakers returns the circuit breaker state of dsType's datasources
+// by name.
+func (s *server) circuitBreakers(dsType string) map[string]*types.CircuitBreakerStatus {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	switch {
+	case dsType == "clickhouse" && s.clickhouseHandler != nil:
+		return s.clickhouseHandler.CircuitBreakers()
+	case dsType == "prometheus" && s.prometheusHandler != nil:
+		return s.prometheusHandler.CircuitBreakers()
+	case dsType == "loki" && s.lokiHandler != nil:
+		return s.lokiHandler.CircuitBreakers()
+	case dsType == "grafana" && s.grafanaHandler != nil:
+		return s.grafanaHandler.CircuitBreakers()
+	case dsType == "httpjson" && s.httpJSONHandler != nil:
+		return s.httpJSONHandler.CircuitBreakers()
+	default:
+		return nil
+	}
+}
+
 // EthNodeAvailable returns true if the ethnode handler is configured.
 func (s *server) EthNodeAvailable() bool {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
 	return s.ethNodeHandler != nil
 }
 
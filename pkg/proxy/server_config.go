@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -10,8 +12,13 @@ import (
 	"gopkg.in/yaml.v3"
 
 	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/config/secrets"
 	"github.com/ethpandaops/panda/pkg/configpath"
+	"github.com/ethpandaops/panda/pkg/maintenance"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
 	"github.com/ethpandaops/panda/pkg/proxy/handlers"
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // ServerConfig is the configuration for the proxy server.
@@ -26,15 +33,34 @@ type ServerConfig struct {
 	// ClickHouse holds ClickHouse cluster configurations.
 	ClickHouse []ClickHouseClusterConfig `yaml:"clickhouse,omitempty"`
 
+	// ClickHouseCache holds the optional ClickHouse response cache configuration.
+	ClickHouseCache ClickHouseCacheConfig `yaml:"clickhouse_cache,omitempty"`
+
+	// ResponseLimits caps the size of upstream responses per handler.
+	ResponseLimits ResponseLimitsConfig `yaml:"response_limits,omitempty"`
+
+	// QueryTags tags upstream queries with the user and execution they were
+	// made for.
+	QueryTags QueryTagsConfig `yaml:"query_tags,omitempty"`
+
 	// Prometheus holds Prometheus instance configurations.
 	Prometheus []PrometheusInstanceConfig `yaml:"prometheus,omitempty"`
 
 	// Loki holds Loki instance configurations.
 	Loki []LokiInstanceConfig `yaml:"loki,omitempty"`
 
+	// Grafana holds Grafana instance configurations.
+	Grafana []GrafanaInstanceConfig `yaml:"grafana,omitempty"`
+
+	// HTTPJSON holds generic JSON HTTP endpoint configurations.
+	HTTPJSON []HTTPJSONEndpointConfig `yaml:"httpjson,omitempty"`
+
 	// EthNode holds Ethereum node API access configuration.
 	EthNode *EthNodeInstanceConfig `yaml:"ethnode,omitempty"`
 
+	// GitHub holds GitHub API access configuration.
+	GitHub *GitHubInstanceConfig `yaml:"github,omitempty"`
+
 	// RateLimiting holds rate limiting configuration.
 	RateLimiting RateLimitConfig `yaml:"rate_limiting"`
 
@@ -46,6 +72,18 @@ type ServerConfig struct {
 
 	// Embedding holds optional embedding API configuration.
 	Embedding *EmbeddingConfig `yaml:"embedding,omitempty"`
+
+	// DatasourceScopes map user groups and orgs to the datasources they may
+	// use, on top of each datasource's allowed_orgs. Users in no listed group
+	// see none.
+	DatasourceScopes []simpleauth.DatasourceScope `yaml:"datasource_scopes,omitempty"`
+
+	// Secrets configures the Vault and Kubernetes stores ${vault:...} and
+	// ${k8s:...} references are read from, and how often datasource
+	// credentials are re-read.
+	Secrets secrets.Config `yaml:"secrets,omitempty"`
+
+	path string
 }
 
 // HTTPServerConfig holds HTTP server configuration.
@@ -61,6 +99,10 @@ type HTTPServerConfig struct {
 
 	// IdleTimeout is the maximum amount of time to wait for the next request.
 	IdleTimeout time.Duration `yaml:"idle_timeout,omitempty"`
+
+	// TLS serves the proxy over TLS. Setting client_ca_file requires client
+	// certificates (mTLS) on every route except /health and /ready.
+	TLS tlsconfig.ServerConfig `yaml:"tls,omitempty"`
 }
 
 // AuthConfig holds authentication configuration for the proxy.
@@ -74,6 +116,15 @@ type AuthConfig struct {
 	// ClientID is the OIDC client identifier expected in bearer token audiences.
 	ClientID string `yaml:"client_id,omitempty"`
 
+	// SigningAlgorithms are the JWS algorithms accepted from the OIDC issuer,
+	// e.g. ["ES256", "EdDSA"]. Defaults to those in the issuer's discovery
+	// document, or RS256.
+	SigningAlgorithms []string `yaml:"signing_algorithms,omitempty"`
+
+	// ClockSkew is how long after expiry an OIDC token is still accepted
+	// (max 5m). Defaults to 0.
+	ClockSkew time.Duration `yaml:"clock_skew,omitempty"`
+
 	// GitHub configures the GitHub OAuth app used for user authentication.
 	GitHub *simpleauth.GitHubConfig `yaml:"github,omitempty"`
 
@@ -91,6 +142,24 @@ type AuthConfig struct {
 
 	// SuccessPage customizes the OAuth callback success page shown in the browser.
 	SuccessPage *simpleauth.SuccessPageConfig `yaml:"success_page,omitempty"`
+
+	// RequestSigning requires HMAC-signed requests from the MCP server in
+	// addition to bearer tokens.
+	RequestSigning RequestSigningConfig `yaml:"request_signing"`
+}
+
+// RequestSigningConfig holds HMAC request signing configuration.
+type RequestSigningConfig struct {
+	// SecretKey is the HMAC key shared with the MCP server.
+	// Signature verification is enforced when this is set.
+	SecretKey string `yaml:"secret_key,omitempty"`
+
+	// MaxClockSkew is the maximum accepted age of a request signature (default: 5m).
+	MaxClockSkew time.Duration `yaml:"max_clock_skew,omitempty"`
+
+	// MaxBodyBytes is the largest request body hashed for verification;
+	// larger requests get a 413 (default: 32 MiB).
+	MaxBodyBytes int64 `yaml:"max_body_bytes,omitempty"`
 }
 
 // DatasourceConfig is the interface every datasource config must satisfy.
@@ -106,9 +175,153 @@ type DatasourceConfig interface {
 // Embed this in every datasource config struct to get compile-time enforcement
 // of authorization support via the DatasourceConfig interface.
 type BaseDatasourceConfig struct {
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description,omitempty"`
-	AllowedOrgs []string `yaml:"allowed_orgs,omitempty"`
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description,omitempty"`
+	AllowedOrgs []string    `yaml:"allowed_orgs,omitempty"`
+	Quota       QuotaConfig `yaml:"quota,omitempty"`
+
+	// Maintenance lists recurring windows during which the proxy rejects
+	// requests to this datasource with a MAINTENANCE error.
+	Maintenance []types.MaintenanceWindow `yaml:"maintenance,omitempty"`
+
+	// Resilience configures retries and a circuit breaker for the upstream.
+	Resilience ResilienceConfig `yaml:"resilience,omitempty"`
+
+	// Transport tunes the upstream connection pool.
+	Transport TransportConfig `yaml:"transport,omitempty"`
+}
+
+// TransportConfig tunes a datasource's upstream connection pool.
+type TransportConfig struct {
+	// MaxIdleConns caps idle connections across all hosts (default: 100).
+	MaxIdleConns int `yaml:"max_idle_conns,omitempty"`
+
+	// MaxIdleConnsPerHost caps idle connections kept per host (default: 10).
+	// Raise it for bursty workloads so connections are reused, not redialed.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host,omitempty"`
+
+	// MaxConnsPerHost caps connections per host, including active ones (default: unlimited).
+	MaxConnsPerHost int `yaml:"max_conns_per_host,omitempty"`
+
+	// IdleConnTimeout closes connections idle for longer (default: 90s).
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout,omitempty"`
+
+	// TLSSessionCacheSize is the number of TLS sessions kept for resumption
+	// (default: 64). A negative value disables resumption.
+	TLSSessionCacheSize int `yaml:"tls_session_cache_size,omitempty"`
+
+	// HTTP2 negotiates HTTP/2 with upstreams that support it.
+	HTTP2 bool `yaml:"http2,omitempty"`
+}
+
+func (c TransportConfig) toHandler() handlers.TransportTuning {
+	return handlers.TransportTuning{
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
+		TLSSessionCacheSize: c.TLSSessionCacheSize,
+		HTTP2:               c.HTTP2,
+	}
+}
+
+func (c TransportConfig) validate() error {
+	if c.MaxIdleConns < 0 || c.MaxIdleConnsPerHost < 0 || c.MaxConnsPerHost < 0 || c.IdleConnTimeout < 0 {
+		return errors.New("max_idle_conns, max_idle_conns_per_host, max_conns_per_host and idle_conn_timeout must not be negative")
+	}
+
+	return nil
+}
+
+// ResilienceConfig holds a datasource's retry policy and circuit breaker.
+type ResilienceConfig struct {
+	// Retry retries connection failures and 502, 503 and 504 responses.
+	Retry RetryConfig `yaml:"retry,omitempty"`
+
+	// CircuitBreaker fails requests fast while the upstream keeps failing.
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker,omitempty"`
+}
+
+// RetryConfig holds a datasource's retry policy.
+type RetryConfig struct {
+	// Attempts is the total number of attempts per request (default: 1, no retries).
+	Attempts int `yaml:"attempts,omitempty"`
+
+	// Backoff is the wait before the first retry, doubled for each further one (default: 200ms).
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+}
+
+// CircuitBreakerConfig holds a datasource's circuit breaker settings.
+type CircuitBreakerConfig struct {
+	// Enabled turns the breaker on.
+	Enabled bool `yaml:"enabled"`
+
+	// ErrorRate is the failed fraction of a window's requests that opens
+	// the breaker (default: 0.5).
+	ErrorRate float64 `yaml:"error_rate,omitempty"`
+
+	// MinRequests is the number of requests a window needs before the
+	// error rate is considered (default: 10).
+	MinRequests int `yaml:"min_requests,omitempty"`
+
+	// SlowThreshold counts responses slower than this as failures (default: off).
+	SlowThreshold time.Duration `yaml:"slow_threshold,omitempty"`
+
+	// Window is how long outcomes are counted before starting over (default: 1m).
+	Window time.Duration `yaml:"window,omitempty"`
+
+	// OpenDuration is how long the breaker rejects requests before letting
+	// a probe through (default: 30s).
+	OpenDuration time.Duration `yaml:"open_duration,omitempty"`
+}
+
+func (c ResilienceConfig) toHandler() handlers.Resilience {
+	return handlers.Resilience{
+		Retry: handlers.RetryPolicy{
+			Attempts: c.Retry.Attempts,
+			Backoff:  c.Retry.Backoff,
+		},
+		CircuitBreaker: handlers.CircuitBreakerPolicy{
+			Enabled:       c.CircuitBreaker.Enabled,
+			ErrorRate:     c.CircuitBreaker.ErrorRate,
+			MinRequests:   c.CircuitBreaker.MinRequests,
+			SlowThreshold: c.CircuitBreaker.SlowThreshold,
+			Window:        c.CircuitBreaker.Window,
+			OpenDuration:  c.CircuitBreaker.OpenDuration,
+		},
+	}
+}
+
+func (c ResilienceConfig) validate() error {
+	retry, breaker := c.Retry, c.CircuitBreaker
+
+	if retry.Attempts < 0 || retry.Backoff < 0 {
+		return errors.New("retry.attempts and retry.backoff must not be negative")
+	}
+
+	if retry.Attempts > 10 {
+		return errors.New("retry.attempts must be at most 10")
+	}
+
+	if breaker.ErrorRate < 0 || breaker.ErrorRate > 1 {
+		return errors.New("circuit_breaker.error_rate must be between 0 and 1")
+	}
+
+	if breaker.MinRequests < 0 || breaker.SlowThreshold < 0 || breaker.Window < 0 || breaker.OpenDuration < 0 {
+		return errors.New("circuit_breaker settings must not be negative")
+	}
+
+	return nil
+}
+
+// QuotaConfig limits how much a datasource is used, across all users.
+type QuotaConfig struct {
+	// MaxQueriesPerHour caps requests per UTC hour (0 = unlimited).
+	MaxQueriesPerHour int `yaml:"max_queries_per_hour,omitempty"`
+
+	// MaxBytesScannedPerDay caps the bytes ClickHouse reports reading per
+	// UTC day (0 = unlimited). Only supported for ClickHouse.
+	MaxBytesScannedPerDay int64 `yaml:"max_bytes_scanned_per_day,omitempty"`
 }
 
 // DatasourceName returns the datasource name.
@@ -125,7 +338,10 @@ var (
 	_ DatasourceConfig = ClickHouseClusterConfig{}
 	_ DatasourceConfig = PrometheusInstanceConfig{}
 	_ DatasourceConfig = LokiInstanceConfig{}
+	_ DatasourceConfig = GrafanaInstanceConfig{}
+	_ DatasourceConfig = HTTPJSONEndpointConfig{}
 	_ DatasourceConfig = EthNodeInstanceConfig{}
+	_ DatasourceConfig = GitHubInstanceConfig{}
 )
 
 // ClickHouseClusterConfig holds ClickHouse cluster configuration.
@@ -139,6 +355,230 @@ type ClickHouseClusterConfig struct {
 	Secure               bool   `yaml:"secure"`
 	SkipVerify           bool   `yaml:"skip_verify,omitempty"`
 	Timeout              int    `yaml:"timeout,omitempty"`
+
+	// Guardrails caps query cost and requires partition filters on large
+	// tables. Mutating statements are rejected regardless.
+	Guardrails ClickHouseGuardrailsConfig `yaml:"guardrails,omitempty"`
+}
+
+// ClickHouseGuardrailsConfig holds query guardrails for a ClickHouse cluster.
+type ClickHouseGuardrailsConfig struct {
+	// MaxExecutionTime caps the max_execution_time setting of each query, in
+	// seconds. Requests asking for more in their parameters, or not setting
+	// it, get this value; queries asking for more in SETTINGS are rejected.
+	MaxExecutionTime int `yaml:"max_execution_time,omitempty"`
+
+	// MaxRowsToRead caps the max_rows_to_read setting of each query, the
+	// same way as MaxExecutionTime.
+	MaxRowsToRead int64 `yaml:"max_rows_to_read,omitempty"`
+
+	// RequiredFilters lists large tables whose queries must filter on one of
+	// the given (partition) columns.
+	RequiredFilters []ClickHouseRequiredFilterConfig `yaml:"required_filters,omitempty"`
+}
+
+// ClickHouseRequiredFilterConfig requires queries reading Table to filter on
+// at least one of Columns.
+type ClickHouseRequiredFilterConfig struct {
+	// Table is the table name, without its database.
+	Table string `yaml:"table"`
+
+	// Columns are the accepted filter columns.
+	Columns []string `yaml:"columns"`
+}
+
+// validateQuotas checks that quotas are non-negative and that byte budgets
+// are only set on ClickHouse datasources, the only ones that report them.
+func (c *ServerConfig) validateQuotas() error {
+	check := func(dsType string, i int, quota QuotaConfig) error {
+		if quota.MaxQueriesPerHour < 0 || quota.MaxBytesScannedPerDay < 0 {
+			return fmt.Errorf("%s[%d].quota limits must not be negative", dsType, i)
+		}
+
+		if dsType != "clickhouse" && quota.MaxBytesScannedPerDay > 0 {
+			return fmt.Errorf("%s[%d].quota.max_bytes_scanned_per_day is only supported for clickhouse", dsType, i)
+		}
+
+		return nil
+	}
+
+	var errs []error
+
+	for i, ds := range c.ClickHouse {
+		errs = append(errs, check("clickhouse", i, ds.Quota))
+	}
+
+	for i, ds := range c.Prometheus {
+		errs = append(errs, check("prometheus", i, ds.Quota))
+	}
+
+	for i, ds := range c.Loki {
+		errs = append(errs, check("loki", i, ds.Quota))
+	}
+
+	for i, ds := range c.Grafana {
+		errs = append(errs, check("grafana", i, ds.Quota))
+	}
+
+	for i, ds := range c.HTTPJSON {
+		errs = append(errs, check("httpjson", i, ds.Quota))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateMaintenance checks every datasource's maintenance windows.
+func (c *ServerConfig) validateMaintenance() error {
+	check := func(dsType string, i int, windows []types.MaintenanceWindow) error {
+		var errs []error
+
+		for j, w := range windows {
+			if err := maintenance.Validate(w); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d].maintenance[%d]: %w", dsType, i, j, err))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	var errs []error
+
+	for i, ds := range c.ClickHouse {
+		errs = append(errs, check("clickhouse", i, ds.Maintenance))
+	}
+
+	for i, ds := range c.Prometheus {
+		errs = append(errs, check("prometheus", i, ds.Maintenance))
+	}
+
+	for i, ds := range c.Loki {
+		errs = append(errs, check("loki", i, ds.Maintenance))
+	}
+
+	for i, ds := range c.Grafana {
+		errs = append(errs, check("grafana", i, ds.Maintenance))
+	}
+
+	for i, ds := range c.HTTPJSON {
+		errs = append(errs, check("httpjson", i, ds.Maintenance))
+	}
+
+	if c.EthNode != nil && len(c.EthNode.Maintenance) > 0 {
+		errs = append(errs, errors.New("ethnode.maintenance is not supported"))
+	}
+
+	if c.GitHub != nil && len(c.GitHub.Maintenance) > 0 {
+		errs = append(errs, errors.New("github.maintenance is not supported"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateResilience checks every datasource's retry and circuit breaker
+// settings.
+func (c *ServerConfig) validateResilience() error {
+	check := func(dsType string, i int, resilience ResilienceConfig) error {
+		if err := resilience.validate(); err != nil {
+			return fmt.Errorf("%s[%d].resilience.%w", dsType, i, err)
+		}
+
+		return nil
+	}
+
+	var errs []error
+
+	for i, ds := range c.ClickHouse {
+		errs = append(errs, check("clickhouse", i, ds.Resilience))
+	}
+
+	for i, ds := range c.Prometheus {
+		errs = append(errs, check("prometheus", i, ds.Resilience))
+	}
+
+	for i, ds := range c.Loki {
+		errs = append(errs, check("loki", i, ds.Resilience))
+	}
+
+	for i, ds := range c.Grafana {
+		errs = append(errs, check("grafana", i, ds.Resilience))
+	}
+
+	for i, ds := range c.HTTPJSON {
+		errs = append(errs, check("httpjson", i, ds.Resilience))
+	}
+
+	if c.EthNode != nil && c.EthNode.Resilience != (ResilienceConfig{}) {
+		errs = append(errs, errors.New("ethnode.resilience is not supported"))
+	}
+
+	if c.GitHub != nil && c.GitHub.Resilience != (ResilienceConfig{}) {
+		errs = append(errs, errors.New("github.resilience is not supported"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTransports checks every datasource's connection pool settings.
+func (c *ServerConfig) validateTransports() error {
+	check := func(dsType string, i int, transport TransportConfig) error {
+		if err := transport.validate(); err != nil {
+			return fmt.Errorf("%s[%d].transport: %w", dsType, i, err)
+		}
+
+		return nil
+	}
+
+	var errs []error
+
+	for i, ds := range c.ClickHouse {
+		errs = append(errs, check("clickhouse", i, ds.Transport))
+	}
+
+	for i, ds := range c.Prometheus {
+		errs = append(errs, check("prometheus", i, ds.Transport))
+	}
+
+	for i, ds := range c.Loki {
+		errs = append(errs, check("loki", i, ds.Transport))
+	}
+
+	for i, ds := range c.Grafana {
+		errs = append(errs, check("grafana", i, ds.Transport))
+	}
+
+	for i, ds := range c.HTTPJSON {
+		errs = append(errs, check("httpjson", i, ds.Transport))
+	}
+
+	if c.EthNode != nil {
+		if err := c.EthNode.Transport.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ethnode.transport: %w", err))
+		}
+	}
+
+	if c.GitHub != nil {
+		if err := c.GitHub.Transport.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("github.transport: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c ClickHouseGuardrailsConfig) toHandler() handlers.ClickHouseGuardrails {
+	g := handlers.ClickHouseGuardrails{
+		MaxExecutionTime: c.MaxExecutionTime,
+		MaxRowsToRead:    c.MaxRowsToRead,
+	}
+
+	for _, filter := range c.RequiredFilters {
+		g.RequiredFilters = append(g.RequiredFilters, handlers.ClickHouseRequiredFilter{
+			Table:   filter.Table,
+			Columns: filter.Columns,
+		})
+	}
+
+	return g
 }
 
 // PrometheusInstanceConfig holds Prometheus instance configuration.
@@ -147,6 +587,41 @@ type PrometheusInstanceConfig struct {
 	URL                  string `yaml:"url"`
 	Username             string `yaml:"username,omitempty"`
 	Password             string `yaml:"password,omitempty"`
+	// SelfMonitoring marks the instance that scrapes the panda server and
+	// proxy, making it the datasource of the self module.
+	SelfMonitoring bool `yaml:"self_monitoring,omitempty"`
+
+	// Downsample limits range query matrices so large results do not
+	// exhaust sandbox memory.
+	Downsample PrometheusDownsampleConfig `yaml:"downsample,omitempty"`
+
+	// AllowedRoutes extends the built-in read-only route allowlist, as
+	// "METHOD /path" entries. Paths ending in "/" match as prefixes.
+	AllowedRoutes []string `yaml:"allowed_routes,omitempty"`
+}
+
+// PrometheusDownsampleConfig limits the size of range query responses.
+// Reduced responses carry a Prometheus warning and the X-Panda-Downsampled
+// header.
+type PrometheusDownsampleConfig struct {
+	// MaxPointsPerSeries thins each series to at most this many points.
+	MaxPointsPerSeries int `yaml:"max_points_per_series,omitempty"`
+
+	// MaxSeries drops series beyond the first MaxSeries.
+	MaxSeries int `yaml:"max_series,omitempty"`
+}
+
+// parseRoutes converts validated "METHOD /path" entries to handler routes.
+func parseRoutes(entries []string) []handlers.Route {
+	routes := make([]handlers.Route, 0, len(entries))
+
+	for _, entry := range entries {
+		if route, err := handlers.ParseRoute(entry); err == nil {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
 }
 
 // LokiInstanceConfig holds Loki instance configuration.
@@ -155,6 +630,36 @@ type LokiInstanceConfig struct {
 	URL                  string `yaml:"url"`
 	Username             string `yaml:"username,omitempty"`
 	Password             string `yaml:"password,omitempty"`
+
+	// AllowedRoutes extends the built-in read-only route allowlist, as
+	// "METHOD /path" entries. Paths ending in "/" match as prefixes.
+	AllowedRoutes []string `yaml:"allowed_routes,omitempty"`
+}
+
+// GrafanaInstanceConfig holds Grafana instance configuration.
+// APIKey is a service account token; Username/Password are used when it is unset.
+type GrafanaInstanceConfig struct {
+	BaseDatasourceConfig `yaml:",inline"`
+	URL                  string `yaml:"url"`
+	APIKey               string `yaml:"api_key,omitempty"`
+	Username             string `yaml:"username,omitempty"`
+	Password             string `yaml:"password,omitempty"`
+	SkipVerify           bool   `yaml:"skip_verify,omitempty"`
+	Timeout              int    `yaml:"timeout,omitempty"`
+}
+
+// HTTPJSONEndpointConfig holds configuration for a generic JSON HTTP service.
+// Headers are added to every upstream request, so credentials stay in the
+// proxy. Only GET requests to AllowedPaths are forwarded: entries ending in
+// "/" match as prefixes, all others must match exactly.
+type HTTPJSONEndpointConfig struct {
+	BaseDatasourceConfig `yaml:",inline"`
+	URL                  string            `yaml:"url"`
+	Network              string            `yaml:"network,omitempty"`
+	Headers              map[string]string `yaml:"headers,omitempty"`
+	AllowedPaths         []string          `yaml:"allowed_paths"`
+	SkipVerify           bool              `yaml:"skip_verify,omitempty"`
+	Timeout              int               `yaml:"timeout,omitempty"`
 }
 
 // EthNodeInstanceConfig holds Ethereum node API access configuration.
@@ -165,6 +670,26 @@ type EthNodeInstanceConfig struct {
 	Password             string `yaml:"password"`
 }
 
+// GitHubInstanceConfig holds GitHub API access configuration. A single token
+// is used for every request, and only the listed repositories can be read.
+type GitHubInstanceConfig struct {
+	BaseDatasourceConfig `yaml:",inline"`
+	// URL is the API base URL (default: https://api.github.com).
+	URL     string             `yaml:"url,omitempty"`
+	Token   string             `yaml:"token"`
+	Repos   []GitHubRepoConfig `yaml:"repos"`
+	Timeout int                `yaml:"timeout,omitempty"`
+}
+
+// GitHubRepoConfig is a repository readable through the GitHub proxy.
+type GitHubRepoConfig struct {
+	// Name is the "owner/repo" full name.
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// Client is the Ethereum client the repository releases, e.g. "geth".
+	Client string `yaml:"client,omitempty"`
+}
+
 // RateLimitConfig holds rate limiting configuration.
 type RateLimitConfig struct {
 	// Enabled controls whether rate limiting is active.
@@ -177,10 +702,89 @@ type RateLimitConfig struct {
 	BurstSize int `yaml:"burst_size,omitempty"`
 }
 
+// ClickHouseCacheConfig holds configuration for caching read-only ClickHouse
+// query responses in the proxy.
+type ClickHouseCacheConfig struct {
+	// Enabled controls whether responses are cached.
+	Enabled bool `yaml:"enabled"`
+
+	// TTL is how long a cached response is served (default: 5m).
+	TTL time.Duration `yaml:"ttl,omitempty"`
+
+	// MaxSizeMB bounds the total cached response size (default: 256).
+	// Responses larger than a tenth of it are not cached.
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+}
+
+// ResponseLimitsConfig caps the decoded size of upstream responses per
+// handler, in MB, so one oversized result can't exhaust the proxy's memory.
+// A negative value removes a handler's limit.
+type ResponseLimitsConfig struct {
+	// ClickHouseMB defaults to 1024.
+	ClickHouseMB int `yaml:"clickhouse_mb,omitempty"`
+
+	// PrometheusMB defaults to 256.
+	PrometheusMB int `yaml:"prometheus_mb,omitempty"`
+
+	// LokiMB defaults to 256.
+	LokiMB int `yaml:"loki_mb,omitempty"`
+
+	// GrafanaMB defaults to 256.
+	GrafanaMB int `yaml:"grafana_mb,omitempty"`
+
+	// HTTPJSONMB defaults to 64.
+	HTTPJSONMB int `yaml:"httpjson_mb,omitempty"`
+
+	// EthNodeMB defaults to 1024; beacon states are large.
+	EthNodeMB int `yaml:"ethnode_mb,omitempty"`
+
+	// GitHubMB defaults to 64.
+	GitHubMB int `yaml:"github_mb,omitempty"`
+}
+
+// QueryTagsConfig controls tagging of upstream queries with the user and
+// execution behind them, so upstream operators can attribute load.
+type QueryTagsConfig struct {
+	// Enabled sets ClickHouse's log_comment and adds X-Panda-User,
+	// X-Panda-On-Behalf-Of and X-Panda-Execution-ID headers to ClickHouse,
+	// Prometheus, Loki and Grafana requests. ClickHouse users must be
+	// allowed to change settings (readonly=2 rather than readonly=1).
+	Enabled bool `yaml:"enabled"`
+}
+
+// responseLimitBytes converts a limit in MB to bytes, 0 meaning unlimited.
+func responseLimitBytes(mb int) int64 {
+	if mb <= 0 {
+		return 0
+	}
+
+	return int64(mb) * 1024 * 1024
+}
+
 // AuditConfig holds audit logging configuration.
 type AuditConfig struct {
 	// Enabled controls whether audit logging is active.
 	Enabled bool `yaml:"enabled"`
+
+	// Sinks export audit entries in addition to the process log.
+	Sinks audit.SinksConfig `yaml:"sinks,omitempty"`
+
+	// BufferSize bounds entries held in memory per sink (default: 10000).
+	BufferSize int `yaml:"buffer_size,omitempty"`
+
+	// BatchSize is the number of entries that triggers an early flush (default: 500).
+	BatchSize int `yaml:"batch_size,omitempty"`
+
+	// FlushInterval is the longest entries are buffered before export (default: 5s).
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+
+	// RecentSize is the number of recent entries kept in memory for the
+	// /audit search endpoint (default: 10000).
+	RecentSize int `yaml:"recent_size,omitempty"`
+
+	// AdminOrgs lists the orgs/groups allowed to search recent entries.
+	// When empty, /audit is only available in auth mode "none".
+	AdminOrgs []string `yaml:"admin_orgs,omitempty"`
 }
 
 // EmbeddingConfig holds configuration for the remote embedding API.
@@ -252,6 +856,10 @@ func (c *ServerConfig) ApplyDefaults() {
 		c.Auth.RefreshTokenTTL = 30 * 24 * time.Hour
 	}
 
+	if c.Auth.RequestSigning.MaxClockSkew == 0 {
+		c.Auth.RequestSigning.MaxClockSkew = defaultMaxClockSkew
+	}
+
 	// Rate limiting defaults.
 	if c.RateLimiting.RequestsPerMinute == 0 {
 		c.RateLimiting.RequestsPerMinute = 60
@@ -261,6 +869,61 @@ func (c *ServerConfig) ApplyDefaults() {
 		c.RateLimiting.BurstSize = 10
 	}
 
+	// ClickHouse cache defaults.
+	if c.ClickHouseCache.TTL == 0 {
+		c.ClickHouseCache.TTL = 5 * time.Minute
+	}
+
+	if c.ClickHouseCache.MaxSizeMB == 0 {
+		c.ClickHouseCache.MaxSizeMB = 256
+	}
+
+	// Response limit defaults.
+	if c.ResponseLimits.ClickHouseMB == 0 {
+		c.ResponseLimits.ClickHouseMB = 1024
+	}
+
+	if c.ResponseLimits.PrometheusMB == 0 {
+		c.ResponseLimits.PrometheusMB = 256
+	}
+
+	if c.ResponseLimits.LokiMB == 0 {
+		c.ResponseLimits.LokiMB = 256
+	}
+
+	if c.ResponseLimits.GrafanaMB == 0 {
+		c.ResponseLimits.GrafanaMB = 256
+	}
+
+	if c.ResponseLimits.HTTPJSONMB == 0 {
+		c.ResponseLimits.HTTPJSONMB = 64
+	}
+
+	if c.ResponseLimits.EthNodeMB == 0 {
+		c.ResponseLimits.EthNodeMB = 1024
+	}
+
+	if c.ResponseLimits.GitHubMB == 0 {
+		c.ResponseLimits.GitHubMB = 64
+	}
+
+	// Audit defaults.
+	if c.Audit.BufferSize == 0 {
+		c.Audit.BufferSize = 10000
+	}
+
+	if c.Audit.BatchSize == 0 {
+		c.Audit.BatchSize = 500
+	}
+
+	if c.Audit.FlushInterval == 0 {
+		c.Audit.FlushInterval = 5 * time.Second
+	}
+
+	if c.Audit.RecentSize == 0 {
+		c.Audit.RecentSize = 10000
+	}
+
 	// Metrics defaults.
 	if c.Metrics.Port == 0 {
 		c.Metrics.Port = 9090
@@ -329,6 +992,26 @@ func (c *ServerConfig) Validate() error {
 		if strings.TrimSpace(c.Auth.ClientID) == "" {
 			return fmt.Errorf("auth.client_id is required when auth.mode is 'oidc'")
 		}
+
+		if err := validateOIDCVerification(c.Auth.SigningAlgorithms, c.Auth.ClockSkew); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err := c.Server.TLS.Validate(); err != nil {
+		return fmt.Errorf("server.tls: %w", err)
+	}
+
+	if c.ClickHouseCache.TTL < 0 || c.ClickHouseCache.MaxSizeMB < 0 {
+		return fmt.Errorf("clickhouse_cache.ttl and clickhouse_cache.max_size_mb must not be negative")
+	}
+
+	if err := c.Audit.Sinks.Validate(); err != nil {
+		return fmt.Errorf("audit.sinks.%w", err)
+	}
+
+	if c.Audit.BufferSize < 0 || c.Audit.BatchSize < 0 || c.Audit.FlushInterval < 0 || c.Audit.RecentSize < 0 {
+		return fmt.Errorf("audit.buffer_size, audit.batch_size, audit.flush_interval and audit.recent_size must not be negative")
 	}
 
 	// Validate embedding config.
@@ -343,8 +1026,33 @@ func (c *ServerConfig) Validate() error {
 	}
 
 	// Validate at least one datasource is configured.
-	if len(c.ClickHouse) == 0 && len(c.Prometheus) == 0 && len(c.Loki) == 0 && c.EthNode == nil {
-		return fmt.Errorf("at least one datasource (clickhouse, prometheus, loki, or ethnode) must be configured")
+	if len(c.ClickHouse) == 0 && len(c.Prometheus) == 0 && len(c.Loki) == 0 && len(c.Grafana) == 0 &&
+		len(c.HTTPJSON) == 0 && c.EthNode == nil && c.GitHub == nil {
+		return fmt.Errorf("at least one datasource (clickhouse, prometheus, loki, grafana, httpjson, ethnode, or github) must be configured")
+	}
+
+	if err := c.Secrets.Validate(); err != nil {
+		return fmt.Errorf("secrets.%w", err)
+	}
+
+	if err := simpleauth.ValidateDatasourceScopes(c.DatasourceScopes); err != nil {
+		return fmt.Errorf("datasource_scopes: %w", err)
+	}
+
+	if err := c.validateQuotas(); err != nil {
+		return err
+	}
+
+	if err := c.validateMaintenance(); err != nil {
+		return err
+	}
+
+	if err := c.validateResilience(); err != nil {
+		return err
+	}
+
+	if err := c.validateTransports(); err != nil {
+		return err
 	}
 
 	// Validate ClickHouse configs.
@@ -356,9 +1064,21 @@ func (c *ServerConfig) Validate() error {
 		if ch.Host == "" {
 			return fmt.Errorf("clickhouse[%d].host is required", i)
 		}
+
+		if ch.Guardrails.MaxExecutionTime < 0 || ch.Guardrails.MaxRowsToRead < 0 {
+			return fmt.Errorf("clickhouse[%d].guardrails limits must not be negative", i)
+		}
+
+		for j, filter := range ch.Guardrails.RequiredFilters {
+			if filter.Table == "" || len(filter.Columns) == 0 {
+				return fmt.Errorf("clickhouse[%d].guardrails.required_filters[%d] needs a table and at least one column", i, j)
+			}
+		}
 	}
 
 	// Validate Prometheus configs.
+	selfMonitoring := ""
+
 	for i, prom := range c.Prometheus {
 		if prom.Name == "" {
 			return fmt.Errorf("prometheus[%d].name is required", i)
@@ -367,6 +1087,27 @@ func (c *ServerConfig) Validate() error {
 		if prom.URL == "" {
 			return fmt.Errorf("prometheus[%d].url is required", i)
 		}
+
+		if prom.Downsample.MaxPointsPerSeries < 0 || prom.Downsample.MaxSeries < 0 {
+			return fmt.Errorf("prometheus[%d].downsample limits must not be negative", i)
+		}
+
+		for j, route := range prom.AllowedRoutes {
+			if _, err := handlers.ParseRoute(route); err != nil {
+				return fmt.Errorf("prometheus[%d].allowed_routes[%d]: %w", i, j, err)
+			}
+		}
+
+		if prom.SelfMonitoring {
+			if selfMonitoring != "" {
+				return fmt.Errorf(
+					"prometheus[%d].self_monitoring is already set on %q; only one instance may scrape the deployment",
+					i, selfMonitoring,
+				)
+			}
+
+			selfMonitoring = prom.Name
+		}
 	}
 
 	// Validate Loki configs.
@@ -378,13 +1119,77 @@ func (c *ServerConfig) Validate() error {
 		if loki.URL == "" {
 			return fmt.Errorf("loki[%d].url is required", i)
 		}
+
+		for j, route := range loki.AllowedRoutes {
+			if _, err := handlers.ParseRoute(route); err != nil {
+				return fmt.Errorf("loki[%d].allowed_routes[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	// Validate Grafana configs.
+	for i, grafana := range c.Grafana {
+		if grafana.Name == "" {
+			return fmt.Errorf("grafana[%d].name is required", i)
+		}
+
+		if grafana.URL == "" {
+			return fmt.Errorf("grafana[%d].url is required", i)
+		}
+	}
+
+	// Validate HTTP JSON endpoint configs.
+	for i, endpoint := range c.HTTPJSON {
+		if endpoint.Name == "" {
+			return fmt.Errorf("httpjson[%d].name is required", i)
+		}
+
+		if endpoint.URL == "" {
+			return fmt.Errorf("httpjson[%d].url is required", i)
+		}
+
+		if len(endpoint.AllowedPaths) == 0 {
+			return fmt.Errorf("httpjson[%d].allowed_paths must list at least one path", i)
+		}
+
+		for j, path := range endpoint.AllowedPaths {
+			if !strings.HasPrefix(path, "/") || strings.Contains(path, "..") {
+				return fmt.Errorf("httpjson[%d].allowed_paths[%d] must be an absolute path without '..'", i, j)
+			}
+		}
+	}
+
+	// Validate GitHub config.
+	if c.GitHub != nil {
+		if c.GitHub.Token == "" {
+			return fmt.Errorf("github.token is required")
+		}
+
+		if len(c.GitHub.Repos) == 0 {
+			return fmt.Errorf("github.repos must list at least one repository")
+		}
+
+		for i, repo := range c.GitHub.Repos {
+			owner, name, ok := strings.Cut(repo.Name, "/")
+			if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+				return fmt.Errorf("github.repos[%d].name must be \"owner/repo\", got %q", i, repo.Name)
+			}
+		}
 	}
 
 	return nil
 }
 
 // ToHandlerConfigs converts the server config to handler configs.
-func (c *ServerConfig) ToHandlerConfigs() ([]handlers.ClickHouseConfig, []handlers.PrometheusConfig, []handlers.LokiConfig, *handlers.EthNodeConfig) {
+func (c *ServerConfig) ToHandlerConfigs() (
+	[]handlers.ClickHouseConfig,
+	[]handlers.PrometheusConfig,
+	[]handlers.LokiConfig,
+	[]handlers.GrafanaConfig,
+	[]handlers.HTTPJSONConfig,
+	*handlers.EthNodeConfig,
+	*handlers.GitHubConfig,
+) {
 	// Convert ClickHouse configs.
 	chConfigs := make([]handlers.ClickHouseConfig, len(c.ClickHouse))
 	for i, ch := range c.ClickHouse {
@@ -399,6 +1204,12 @@ func (c *ServerConfig) ToHandlerConfigs() ([]handlers.ClickHouseConfig, []handle
 			Secure:      ch.Secure,
 			SkipVerify:  ch.SkipVerify,
 			Timeout:     ch.Timeout,
+			Guardrails:  ch.Guardrails.toHandler(),
+
+			QueryTags:        c.QueryTags.Enabled,
+			Resilience:       ch.Resilience.toHandler(),
+			Transport:        ch.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.ClickHouseMB),
 		}
 	}
 
@@ -411,6 +1222,15 @@ func (c *ServerConfig) ToHandlerConfigs() ([]handlers.ClickHouseConfig, []handle
 			URL:         prom.URL,
 			Username:    prom.Username,
 			Password:    prom.Password,
+			Downsample: handlers.PrometheusDownsample{
+				MaxPointsPerSeries: prom.Downsample.MaxPointsPerSeries,
+				MaxSeries:          prom.Downsample.MaxSeries,
+			},
+			AllowedRoutes:    parseRoutes(prom.AllowedRoutes),
+			QueryTags:        c.QueryTags.Enabled,
+			Resilience:       prom.Resilience.toHandler(),
+			Transport:        prom.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.PrometheusMB),
 		}
 	}
 
@@ -423,6 +1243,50 @@ func (c *ServerConfig) ToHandlerConfigs() ([]handlers.ClickHouseConfig, []handle
 			URL:         loki.URL,
 			Username:    loki.Username,
 			Password:    loki.Password,
+
+			AllowedRoutes:    parseRoutes(loki.AllowedRoutes),
+			QueryTags:        c.QueryTags.Enabled,
+			Resilience:       loki.Resilience.toHandler(),
+			Transport:        loki.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.LokiMB),
+		}
+	}
+
+	// Convert Grafana configs.
+	grafanaConfigs := make([]handlers.GrafanaConfig, len(c.Grafana))
+	for i, grafana := range c.Grafana {
+		grafanaConfigs[i] = handlers.GrafanaConfig{
+			Name:        grafana.Name,
+			Description: grafana.Description,
+			URL:         grafana.URL,
+			APIKey:      grafana.APIKey,
+			Username:    grafana.Username,
+			Password:    grafana.Password,
+			SkipVerify:  grafana.SkipVerify,
+			Timeout:     grafana.Timeout,
+
+			QueryTags:        c.QueryTags.Enabled,
+			Resilience:       grafana.Resilience.toHandler(),
+			Transport:        grafana.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.GrafanaMB),
+		}
+	}
+
+	// Convert HTTP JSON endpoint configs.
+	httpJSONConfigs := make([]handlers.HTTPJSONConfig, len(c.HTTPJSON))
+	for i, endpoint := range c.HTTPJSON {
+		httpJSONConfigs[i] = handlers.HTTPJSONConfig{
+			Name:         endpoint.Name,
+			Description:  endpoint.Description,
+			URL:          endpoint.URL,
+			Headers:      endpoint.Headers,
+			AllowedPaths: endpoint.AllowedPaths,
+			SkipVerify:   endpoint.SkipVerify,
+			Timeout:      endpoint.Timeout,
+
+			Resilience:       endpoint.Resilience.toHandler(),
+			Transport:        endpoint.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.HTTPJSONMB),
 		}
 	}
 
@@ -432,10 +1296,32 @@ func (c *ServerConfig) ToHandlerConfigs() ([]handlers.ClickHouseConfig, []handle
 		ethNodeConfig = &handlers.EthNodeConfig{
 			Username: c.EthNode.Username,
 			Password: c.EthNode.Password,
+
+			Transport:        c.EthNode.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.EthNodeMB),
 		}
 	}
 
-	return chConfigs, promConfigs, lokiConfigs, ethNodeConfig
+	// Convert GitHub config.
+	var gitHubConfig *handlers.GitHubConfig
+	if c.GitHub != nil {
+		repos := make([]string, len(c.GitHub.Repos))
+		for i, repo := range c.GitHub.Repos {
+			repos[i] = repo.Name
+		}
+
+		gitHubConfig = &handlers.GitHubConfig{
+			URL:     c.GitHub.URL,
+			Token:   c.GitHub.Token,
+			Repos:   repos,
+			Timeout: c.GitHub.Timeout,
+
+			Transport:        c.GitHub.Transport.toHandler(),
+			MaxResponseBytes: responseLimitBytes(c.ResponseLimits.GitHubMB),
+		}
+	}
+
+	return chConfigs, promConfigs, lokiConfigs, grafanaConfigs, httpJSONConfigs, ethNodeConfig, gitHubConfig
 }
 
 // envVarWithDefaultPattern matches ${VAR_NAME:-default} patterns.
@@ -464,15 +1350,37 @@ func LoadServerConfig(path string) (*ServerConfig, error) {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
+	// Resolve Vault and Kubernetes secret references with the stores the
+	// config itself declares, then parse again.
+	if secrets.HasRefs(substituted) {
+		resolved, err := secrets.Apply(context.Background(), cfg.Secrets, substituted)
+		if err != nil {
+			return nil, fmt.Errorf("resolving secrets: %w", err)
+		}
+
+		cfg = ServerConfig{}
+		if err := yaml.Unmarshal([]byte(resolved), &cfg); err != nil {
+			return nil, fmt.Errorf("parsing config: %w", err)
+		}
+	}
+
 	cfg.ApplyDefaults()
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
+	cfg.path = resolvedPath
+
 	return &cfg, nil
 }
 
+// Path returns the resolved path this config was loaded from, or "" when it
+// was not loaded from a file.
+func (c *ServerConfig) Path() string {
+	return c.path
+}
+
 // substituteEnvVars replaces ${VAR_NAME} and ${VAR_NAME:-default} patterns with environment variable values.
 // Lines that are comments (starting with #) are skipped.
 // Missing environment variables without defaults are replaced with empty strings (lenient mode).
@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	simpleauth "github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
 )
 
 func TestRegisterRoutesMatchesClickHouseSubpaths(t *testing.T) {
@@ -42,6 +44,200 @@ func TestRegisterRoutesMatchesClickHouseSubpaths(t *testing.T) {
 	}
 }
 
+func TestGrafanaRoutesAreAllowlisted(t *testing.T) {
+	t.Parallel()
+
+	var upstreamAuth []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamAuth = append(upstreamAuth, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	cfg := ServerConfig{
+		Auth: AuthConfig{Mode: AuthModeNone},
+		Grafana: []GrafanaInstanceConfig{
+			{BaseDatasourceConfig: BaseDatasourceConfig{Name: "ops"}, URL: upstream.URL, APIKey: "sa-token"},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/grafana/api/search", http.StatusOK},
+		{http.MethodGet, "/grafana/api/dashboards/uid/abc", http.StatusOK},
+		{http.MethodPost, "/grafana/api/ds/query", http.StatusOK},
+		{http.MethodDelete, "/grafana/api/dashboards/uid/abc", http.StatusForbidden},
+		{http.MethodGet, "/grafana/api/admin/users", http.StatusForbidden},
+		{http.MethodGet, "/grafana/api/dashboards/uid/../../admin/users", http.StatusForbidden},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-Datasource", "ops")
+		srv.mux.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.want, rec.Code)
+		}
+	}
+
+	if len(upstreamAuth) != 3 {
+		t.Fatalf("expected 3 upstream requests, got %v", upstreamAuth)
+	}
+
+	if upstreamAuth[0] != "GET /api/search Bearer sa-token" {
+		t.Fatalf("unexpected upstream request %q", upstreamAuth[0])
+	}
+}
+
+func TestGitHubRoutesAreAllowlisted(t *testing.T) {
+	t.Parallel()
+
+	var upstreamRequests []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamRequests = append(upstreamRequests, r.URL.Path+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	cfg := ServerConfig{
+		Auth: AuthConfig{Mode: AuthModeNone},
+		GitHub: &GitHubInstanceConfig{
+			URL:   upstream.URL,
+			Token: "gh-token",
+			Repos: []GitHubRepoConfig{{Name: "sigp/lighthouse", Client: "lighthouse"}},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/github/repos/sigp/lighthouse/releases", http.StatusOK},
+		{http.MethodGet, "/github/repos/Sigp/Lighthouse/releases/tags/v7.0.0", http.StatusOK},
+		{http.MethodGet, "/github/search/issues?q=sync+repo:sigp/lighthouse", http.StatusOK},
+		{http.MethodPost, "/github/repos/sigp/lighthouse/issues", http.StatusForbidden},
+		{http.MethodGet, "/github/repos/sigp/lighthouse/actions/secrets", http.StatusForbidden},
+		{http.MethodGet, "/github/repos/ethereum/go-ethereum/releases", http.StatusForbidden},
+		{http.MethodGet, "/github/repos/sigp/lighthouse/releases/../../../other/repo", http.StatusForbidden},
+		{http.MethodGet, "/github/search/issues?q=sync", http.StatusForbidden},
+		{http.MethodGet, "/github/search/issues?q=repo:sigp/lighthouse+org:ethereum", http.StatusForbidden},
+		{http.MethodGet, "/github/user", http.StatusForbidden},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		srv.mux.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.want, rec.Code)
+		}
+	}
+
+	if len(upstreamRequests) != 3 {
+		t.Fatalf("expected 3 upstream requests, got %v", upstreamRequests)
+	}
+
+	if upstreamRequests[0] != "/repos/sigp/lighthouse/releases Bearer gh-token" {
+		t.Fatalf("unexpected upstream request %q", upstreamRequests[0])
+	}
+
+	if repos := srv.GitHubDatasources(); len(repos) != 1 || repos[0] != "sigp/lighthouse" {
+		t.Fatalf("unexpected GitHub datasources %v", repos)
+	}
+}
+
+func TestHTTPJSONPathsAreAllowlisted(t *testing.T) {
+	t.Parallel()
+
+	var upstreamRequests []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamRequests = append(upstreamRequests, r.URL.RequestURI()+" "+r.Header.Get("Authorization")+" "+r.Header.Get("X-Datasource"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	cfg := ServerConfig{
+		Auth: AuthConfig{Mode: AuthModeNone},
+		HTTPJSON: []HTTPJSONEndpointConfig{
+			{
+				BaseDatasourceConfig: BaseDatasourceConfig{Name: "faucet"},
+				URL:                  upstream.URL,
+				Headers:              map[string]string{"Authorization": "Bearer faucet-token"},
+				AllowedPaths:         []string{"/api/v1/status", "/api/v1/claims/"},
+			},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/httpjson/api/v1/status?verbose=true", http.StatusOK},
+		{http.MethodGet, "/httpjson/api/v1/claims/0xabc", http.StatusOK},
+		{http.MethodGet, "/httpjson/api/v1/status/extra", http.StatusForbidden},
+		{http.MethodGet, "/httpjson/api/v1/admin", http.StatusForbidden},
+		{http.MethodGet, "/httpjson/api/v1/claims/../admin", http.StatusForbidden},
+		{http.MethodPost, "/httpjson/api/v1/status", http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-Datasource", "faucet")
+		req.Header.Set("Authorization", "Bearer sandbox-token")
+		srv.mux.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.want, rec.Code)
+		}
+	}
+
+	if len(upstreamRequests) != 2 {
+		t.Fatalf("expected 2 upstream requests, got %v", upstreamRequests)
+	}
+
+	if upstreamRequests[0] != "/api/v1/status?verbose=true Bearer faucet-token " {
+		t.Fatalf("unexpected upstream request %q", upstreamRequests[0])
+	}
+
+	info := srv.HTTPJSONDatasourceInfo()
+	if len(info) != 1 || info[0].Metadata["allowed_paths"] != "/api/v1/status,/api/v1/claims/" {
+		t.Fatalf("unexpected datasource info %+v", info)
+	}
+
+	if _, ok := info[0].Metadata["url"]; ok {
+		t.Fatalf("datasource info must not expose the upstream URL: %+v", info[0])
+	}
+}
+
 func TestMetricsDatasourceLabelUsesConfiguredNamesOnly(t *testing.T) {
 	t.Parallel()
 
@@ -223,3 +419,126 @@ func TestBrandingEndpointReturns204WhenNotConfigured(t *testing.T) {
 		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
 	}
 }
+
+func TestAuditEndpointSearchesRecentEntries(t *testing.T) {
+	t.Parallel()
+
+	cfg := ServerConfig{
+		Auth:  AuthConfig{Mode: AuthModeNone},
+		Audit: AuditConfig{Enabled: true},
+	}
+	cfg.ApplyDefaults()
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	srv.mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/datasources", nil))
+
+	rec := httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit?datasource=datasources&status=2xx", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var got audit.SearchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(got.Entries) != 1 || got.Entries[0].Path != "/datasources" {
+		t.Fatalf("expected the /datasources request, got %+v", got.Entries)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit?status=bogus", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for invalid filter, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestAuditEndpointRequiresAdminOrg(t *testing.T) {
+	t.Parallel()
+
+	srv := &server{cfg: ServerConfig{
+		Auth:  AuthConfig{Mode: AuthModeOIDC},
+		Audit: AuditConfig{AdminOrgs: []string{"auditors"}},
+	}}
+
+	admin := withAuthUser(context.Background(), &AuthUser{Subject: "a", Groups: []string{"auditors"}})
+	member := withAuthUser(context.Background(), &AuthUser{Subject: "b", Groups: []string{"ethpandaops"}})
+
+	if !srv.isAuditAdmin(admin) {
+		t.Fatal("expected auditors group to be allowed")
+	}
+
+	if srv.isAuditAdmin(member) {
+		t.Fatal("expected non-admin group to be denied")
+	}
+
+	if srv.isAuditAdmin(context.Background()) {
+		t.Fatal("expected unauthenticated caller to be denied")
+	}
+}
+
+func TestDatasourcesReportCircuitBreakers(t *testing.T) {
+	t.Parallel()
+
+	cfg := ServerConfig{
+		Auth: AuthConfig{Mode: AuthModeNone},
+		Loki: []LokiInstanceConfig{
+			{
+				BaseDatasourceConfig: BaseDatasourceConfig{
+					Name: "guarded",
+					Resilience: ResilienceConfig{
+						CircuitBreaker: CircuitBreakerConfig{Enabled: true},
+					},
+				},
+				URL: "http://loki.test",
+			},
+			{
+				BaseDatasourceConfig: BaseDatasourceConfig{Name: "plain"},
+				URL:                  "http://loki.test",
+			},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	if err != nil {
+		t.Fatalf("newServer failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/datasources", nil))
+
+	var got DatasourcesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	breakers := make(map[string]string, len(got.LokiInfo))
+
+	for _, info := range got.LokiInfo {
+		if info.CircuitBreaker != nil {
+			breakers[info.Name] = info.CircuitBreaker.State
+		}
+	}
+
+	if len(breakers) != 1 || breakers["guarded"] != "closed" {
+		t.Fatalf("expected a closed breaker for guarded only, got %v", breakers)
+	}
+
+	cfg.Loki[1].Resilience.CircuitBreaker.ErrorRate = 2
+
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error for error_rate above 1")
+	}
+}
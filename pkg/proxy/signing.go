@@ -0,0 +1,255 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SignatureHeader carries the hex-encoded HMAC-SHA256 request signature.
+	SignatureHeader = "X-Panda-Signature"
+
+	// SignatureTimestampHeader carries the unix timestamp the signature was created at.
+	SignatureTimestampHeader = "X-Panda-Signature-Timestamp"
+
+	// ContentSHA256Header carries the hex-encoded SHA-256 hash of the request body.
+	ContentSHA256Header = "X-Panda-Content-SHA256"
+
+	// signatureVersion prefixes the canonical string so the scheme can evolve.
+	signatureVersion = "v1"
+
+	// defaultMaxClockSkew is the default tolerated difference between the
+	// signature timestamp and the proxy clock.
+	defaultMaxClockSkew = 5 * time.Minute
+
+	// defaultMaxSignedBodyBytes is the default largest request body the
+	// verifier buffers to hash. Verification runs before authentication, so
+	// this bounds what an anonymous client can make the proxy hold.
+	defaultMaxSignedBodyBytes = 32 << 20
+)
+
+// errRequestBodyTooLarge is returned when a request body exceeds the
+// verifier's limit.
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// SignRequest adds HMAC request signature headers to req using the shared key.
+// The signature covers the method, path, query, timestamp and body hash so a
+// leaked bearer token alone cannot be replayed against the proxy. The request
+// body is buffered when it cannot be re-read via GetBody.
+func SignRequest(req *http.Request, key string, now time.Time) error {
+	if key == "" {
+		return nil
+	}
+
+	bodyHash, err := hashRequestBody(req, 0)
+	if err != nil {
+		return fmt.Errorf("hashing request body: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req.Header.Set(SignatureTimestampHeader, timestamp)
+	req.Header.Set(ContentSHA256Header, bodyHash)
+	req.Header.Set(SignatureHeader, computeSignature(key, req, timestamp, bodyHash))
+
+	return nil
+}
+
+// computeSignature returns the hex-encoded HMAC of the canonical request string.
+func computeSignature(key string, req *http.Request, timestamp, bodyHash string) string {
+	canonical := strings.Join([]string{
+		signatureVersion,
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		timestamp,
+		bodyHash,
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(canonical))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashRequestBody returns the hex SHA-256 of the request body, leaving the
+// body readable for the next consumer. A positive limit rejects bodies larger
+// than limit bytes with errRequestBodyTooLarge before buffering them.
+func hashRequestBody(req *http.Request, limit int64) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return hashBytes(nil), nil
+	}
+
+	if limit > 0 && req.ContentLength > limit {
+		return "", errRequestBodyTooLarge
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = body.Close() }()
+
+		h := sha256.New()
+
+		n, err := io.Copy(h, limitBody(body, limit))
+		if err != nil {
+			return "", err
+		}
+
+		if limit > 0 && n > limit {
+			return "", errRequestBodyTooLarge
+		}
+
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	data, err := io.ReadAll(limitBody(req.Body, limit))
+	if err != nil {
+		return "", err
+	}
+
+	if limit > 0 && int64(len(data)) > limit {
+		return "", errRequestBodyTooLarge
+	}
+
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	return hashBytes(data), nil
+}
+
+// limitBody reads at most one byte past a positive limit, so callers can tell
+// an over-limit body apart from one exactly at the limit.
+func limitBody(body io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return body
+	}
+
+	return io.LimitReader(body, limit+1)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestVerifierConfig configures the request signature verifier.
+type RequestVerifierConfig struct {
+	// SecretKey is the shared HMAC key. Verification is skipped when empty.
+	SecretKey string
+
+	// MaxClockSkew is the maximum accepted age of a signature timestamp.
+	MaxClockSkew time.Duration
+
+	// MaxBodyBytes is the largest request body accepted for hashing.
+	MaxBodyBytes int64
+}
+
+// RequestVerifier rejects requests that do not carry a valid HMAC signature
+// produced by the MCP server's signing key.
+type RequestVerifier struct {
+	log logrus.FieldLogger
+	cfg RequestVerifierConfig
+	now func() time.Time
+}
+
+// NewRequestVerifier creates a new request signature verifier.
+func NewRequestVerifier(log logrus.FieldLogger, cfg RequestVerifierConfig) *RequestVerifier {
+	if cfg.MaxClockSkew <= 0 {
+		cfg.MaxClockSkew = defaultMaxClockSkew
+	}
+
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultMaxSignedBodyBytes
+	}
+
+	return &RequestVerifier{
+		log: log.WithField("component", "request-verifier"),
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Verify checks the signature headers on r. On success the request body is
+// restored so downstream handlers can read it.
+func (v *RequestVerifier) Verify(r *http.Request) error {
+	signature := strings.TrimSpace(r.Header.Get(SignatureHeader))
+	timestamp := strings.TrimSpace(r.Header.Get(SignatureTimestampHeader))
+
+	if signature == "" || timestamp == "" {
+		return fmt.Errorf("missing request signature")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp")
+	}
+
+	skew := v.now().Sub(time.Unix(unix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > v.cfg.MaxClockSkew {
+		return fmt.Errorf("signature timestamp outside allowed clock skew")
+	}
+
+	bodyHash, err := hashRequestBody(r, v.cfg.MaxBodyBytes)
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+
+	if claimed := r.Header.Get(ContentSHA256Header); claimed != "" && !hmac.Equal([]byte(claimed), []byte(bodyHash)) {
+		return fmt.Errorf("request body hash mismatch")
+	}
+
+	expected := computeSignature(v.cfg.SecretKey, r, timestamp, bodyHash)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("invalid request signature")
+	}
+
+	return nil
+}
+
+// Middleware returns an HTTP middleware that enforces request signatures.
+func (v *RequestVerifier) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := v.Verify(r); err != nil {
+				v.log.WithError(err).WithFields(logrus.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				}).Debug("Rejected unsigned or invalid request")
+
+				status := http.StatusUnauthorized
+				if errors.Is(err, errRequestBodyTooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+
+				http.Error(w, err.Error(), status)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
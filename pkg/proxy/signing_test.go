@@ -0,0 +1,167 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedRequest(t *testing.T, key, body string, now time.Time) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, "http://proxy.test/clickhouse/?default_format=JSON", strings.NewReader(body))
+	require.NoError(t, err)
+	require.NoError(t, SignRequest(req, key, now))
+
+	// Simulate the request arriving at the proxy.
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	incoming := httptest.NewRequest(req.Method, req.URL.String(), strings.NewReader(string(data)))
+	incoming.Header = req.Header.Clone()
+
+	return incoming
+}
+
+func TestRequestVerifierAcceptsValidSignature(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	verifier := NewRequestVerifier(logrus.New(), RequestVerifierConfig{SecretKey: "secret"})
+	verifier.now = func() time.Time { return now }
+
+	req := signedRequest(t, "secret", "SELECT 1", now)
+	require.NoError(t, verifier.Verify(req))
+
+	// The body must remain readable for downstream handlers.
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", string(body))
+}
+
+func TestRequestVerifierRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		mutate func(*http.Request) *http.Request
+		at     time.Time
+	}{
+		{
+			name: "missing signature",
+			mutate: func(r *http.Request) *http.Request {
+				r.Header.Del(SignatureHeader)
+				return r
+			},
+			at: now,
+		},
+		{
+			name: "tampered body",
+			mutate: func(r *http.Request) *http.Request {
+				tampered := httptest.NewRequest(r.Method, r.URL.String(), strings.NewReader("DROP TABLE x"))
+				tampered.Header = r.Header.Clone()
+				return tampered
+			},
+			at: now,
+		},
+		{
+			name: "tampered query",
+			mutate: func(r *http.Request) *http.Request {
+				r.URL.RawQuery = "default_format=CSV"
+				return r
+			},
+			at: now,
+		},
+		{
+			name:   "expired timestamp",
+			mutate: func(r *http.Request) *http.Request { return r },
+			at:     now.Add(10 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := NewRequestVerifier(logrus.New(), RequestVerifierConfig{SecretKey: "secret"})
+			verifier.now = func() time.Time { return tt.at }
+
+			req := tt.mutate(signedRequest(t, "secret", "SELECT 1", now))
+			assert.Error(t, verifier.Verify(req))
+		})
+	}
+}
+
+func TestRequestVerifierRejectsWrongKey(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	verifier := NewRequestVerifier(logrus.New(), RequestVerifierConfig{SecretKey: "secret"})
+	verifier.now = func() time.Time { return now }
+
+	req := signedRequest(t, "other", "SELECT 1", now)
+	assert.Error(t, verifier.Verify(req))
+}
+
+func TestRequestVerifierRejectsOversizedBody(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	verifier := NewRequestVerifier(logrus.New(), RequestVerifierConfig{SecretKey: "secret", MaxBodyBytes: 8})
+	verifier.now = func() time.Time { return now }
+
+	reached := false
+	handler := verifier.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// A declared length over the limit is rejected without reading the body.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest(t, "secret", "SELECT 1234", now))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// So is a body without a length that turns out to be over the limit.
+	chunked := signedRequest(t, "secret", "SELECT 1234", now)
+	chunked.ContentLength = -1
+	chunked.Body = io.NopCloser(strings.NewReader("SELECT 1234"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, chunked)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, reached)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest(t, "secret", "SELECT 1", now))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestServerRequiresSignatureWhenConfigured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.Auth.RequestSigning.SecretKey = "secret"
+
+	srv, err := newServer(logrus.New(), cfg, "http://proxy.test", "18081")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/datasources", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	signed := httptest.NewRequest(http.MethodGet, "/datasources", nil)
+	require.NoError(t, SignRequest(signed, "secret", time.Now()))
+
+	rec = httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, signed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
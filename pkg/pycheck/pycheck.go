@@ -0,0 +1,401 @@
+// Package pycheck statically inspects sandbox Python code without running
+// it. It tokenizes the code, reports unbalanced brackets and unterminated
+// strings, and extracts the ethpandaops modules it imports and the module
+// functions it calls, resolving string arguments where it can.
+package pycheck
+
+import (
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Package is the sandbox library whose imports and calls are extracted.
+const Package = "ethpandaops"
+
+// Import is an ethpandaops module imported by the code.
+type Import struct {
+	Module string
+	// Function is set for "from ethpandaops.<module> import <function>".
+	Function string
+	Line     int
+}
+
+// Result is what Analyze found in the code.
+type Result struct {
+	Imports []Import
+	Calls   []types.PythonCall
+	// SyntaxError is the first syntax problem found, if any. Imports and
+	// calls before it are still reported.
+	SyntaxError *types.LintFinding
+}
+
+// binding is what a name refers to after an import.
+type binding struct {
+	// pkg is set when the name is the ethpandaops package itself.
+	pkg      bool
+	module   string
+	function string
+}
+
+type analyzer struct {
+	result   *Result
+	bindings map[string]binding
+	// strings maps names assigned a string literal to its value.
+	strings map[string]string
+}
+
+// Analyze inspects code. It never executes it.
+func Analyze(code string) *Result {
+	a := &analyzer{
+		result:   &Result{},
+		bindings: make(map[string]binding, 4),
+		strings:  make(map[string]string, 4),
+	}
+
+	tokens, syntaxErr := tokenize(code)
+	if syntaxErr != nil {
+		a.result.SyntaxError = &types.LintFinding{
+			Line:     syntaxErr.line,
+			Severity: types.LintSeverityError,
+			Kind:     "syntax_error",
+			Message:  "syntax error: " + syntaxErr.message,
+		}
+	}
+
+	start := 0
+
+	for i, tok := range tokens {
+		if tok.kind != tokenEnd {
+			continue
+		}
+
+		a.statement(tokens[start:i])
+		start = i + 1
+	}
+
+	if start < len(tokens) {
+		a.statement(tokens[start:])
+	}
+
+	return a.result
+}
+
+// statement analyzes one logical line.
+func (a *analyzer) statement(tokens []token) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	switch {
+	case isName(tokens[0], "import"):
+		a.importStatement(tokens[1:])
+
+		return
+	case isName(tokens[0], "from"):
+		a.fromStatement(tokens[1:])
+
+		return
+	}
+
+	a.calls(tokens)
+	a.assignment(tokens)
+}
+
+// importStatement handles "import a.b [as c], d".
+func (a *analyzer) importStatement(tokens []token) {
+	for _, part := range splitTopLevel(tokens, ",") {
+		path, alias := dottedName(part)
+		if len(path) == 0 || path[0] != Package {
+			continue
+		}
+
+		line := part[0].line
+
+		switch {
+		case len(path) == 1:
+			a.bind(aliasOr(alias, Package), binding{pkg: true})
+		case alias != "":
+			a.bind(alias, binding{module: path[1]})
+			a.result.Imports = append(a.result.Imports, Import{Module: path[1], Line: line})
+		default:
+			a.bind(Package, binding{pkg: true})
+			a.result.Imports = append(a.result.Imports, Import{Module: path[1], Line: line})
+		}
+	}
+}
+
+// fromStatement handles "from ethpandaops[.module] import x [as y], ...".
+func (a *analyzer) fromStatement(tokens []token) {
+	importAt := -1
+
+	for i, tok := range tokens {
+		if isName(tok, "import") {
+			importAt = i
+
+			break
+		}
+	}
+
+	if importAt < 0 {
+		return
+	}
+
+	path, _ := dottedName(tokens[:importAt])
+	if len(path) == 0 || path[0] != Package {
+		return
+	}
+
+	names := tokens[importAt+1:]
+	if len(names) > 0 && names[0].value == "(" {
+		names = names[1:]
+		if len(names) > 0 && names[len(names)-1].value == ")" {
+			names = names[:len(names)-1]
+		}
+	}
+
+	for _, part := range splitTopLevel(names, ",") {
+		name, alias := dottedName(part)
+		if len(name) != 1 || name[0] == "*" {
+			continue
+		}
+
+		line := part[0].line
+
+		if len(path) == 1 {
+			a.bind(aliasOr(alias, name[0]), binding{module: name[0]})
+			a.result.Imports = append(a.result.Imports, Import{Module: name[0], Line: line})
+
+			continue
+		}
+
+		a.bind(aliasOr(alias, name[0]), binding{module: path[1], function: name[0]})
+		a.result.Imports = append(a.result.Imports, Import{Module: path[1], Function: name[0], Line: line})
+	}
+}
+
+// calls records every call of an ethpandaops module function in tokens.
+func (a *analyzer) calls(tokens []token) {
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].kind != tokenName || (i > 0 && tokens[i-1].value == ".") {
+			continue
+		}
+
+		b, ok := a.bindings[tokens[i].value]
+		if !ok {
+			continue
+		}
+
+		module, function, open := b.module, b.function, i+1
+
+		switch {
+		case b.pkg:
+			// ethpandaops.<module>.<function>(
+			if !isAttribute(tokens, i+1) || !isAttribute(tokens, i+3) {
+				continue
+			}
+
+			module, function, open = tokens[i+2].value, tokens[i+4].value, i+5
+		case function == "":
+			// <module>.<function>(
+			if !isAttribute(tokens, i+1) {
+				continue
+			}
+
+			function, open = tokens[i+2].value, i+3
+		}
+
+		if open >= len(tokens) || tokens[open].value != "(" {
+			continue
+		}
+
+		a.result.Calls = append(a.result.Calls, types.PythonCall{
+			Module:   module,
+			Function: function,
+			Line:     tokens[i].line,
+			Args:     a.arguments(tokens[open:]),
+		})
+	}
+}
+
+// arguments parses the call arguments in tokens, which start at the call's
+// opening parenthesis.
+func (a *analyzer) arguments(tokens []token) []types.PythonArg {
+	end := matchingBracket(tokens)
+	if end < 0 {
+		return nil
+	}
+
+	var args []types.PythonArg
+
+	for _, part := range splitTopLevel(tokens[1:end], ",") {
+		if len(part) == 0 {
+			continue
+		}
+
+		arg := types.PythonArg{}
+
+		if len(part) > 2 && part[0].kind == tokenName && part[1].value == "=" {
+			arg.Keyword = part[0].value
+			part = part[2:]
+		}
+
+		arg.Value, arg.Known = a.stringValue(part)
+		args = append(args, arg)
+	}
+
+	return args
+}
+
+// assignment tracks "name = <string>" so later calls can resolve name.
+func (a *analyzer) assignment(tokens []token) {
+	if len(tokens) < 3 || tokens[0].kind != tokenName || tokens[1].value != "=" {
+		return
+	}
+
+	name := tokens[0].value
+
+	// A rebound import name no longer refers to the module.
+	delete(a.bindings, name)
+
+	if value, ok := a.stringValue(tokens[2:]); ok {
+		a.strings[name] = value
+
+		return
+	}
+
+	delete(a.strings, name)
+}
+
+// stringValue returns the value of an expression made of adjacent string
+// literals, optionally parenthesized, or of a name assigned one.
+func (a *analyzer) stringValue(tokens []token) (string, bool) {
+	for len(tokens) > 1 && tokens[0].value == "(" && matchingBracket(tokens) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	if len(tokens) == 1 && tokens[0].kind == tokenName {
+		value, ok := a.strings[tokens[0].value]
+
+		return value, ok
+	}
+
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	var value string
+
+	for _, tok := range tokens {
+		if tok.kind != tokenString {
+			return "", false
+		}
+
+		value += tok.value
+	}
+
+	return value, true
+}
+
+func (a *analyzer) bind(name string, b binding) {
+	a.bindings[name] = b
+	delete(a.strings, name)
+}
+
+// dottedName parses "a.b.c [as d]".
+func dottedName(tokens []token) ([]string, string) {
+	var (
+		path  []string
+		alias string
+	)
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		switch {
+		case isName(tok, "as"):
+			if i+1 < len(tokens) {
+				alias = tokens[i+1].value
+			}
+
+			return path, alias
+		case tok.kind == tokenName || tok.value == "*":
+			path = append(path, tok.value)
+		case tok.value != ".":
+			return path, alias
+		}
+	}
+
+	return path, alias
+}
+
+// splitTopLevel splits tokens on sep outside brackets.
+func splitTopLevel(tokens []token, sep string) [][]token {
+	var (
+		parts [][]token
+		depth int
+		start int
+	)
+
+	for i, tok := range tokens {
+		switch tok.value {
+		case "(", "[", "{":
+			if tok.kind == tokenOp {
+				depth++
+			}
+		case ")", "]", "}":
+			if tok.kind == tokenOp {
+				depth--
+			}
+		case sep:
+			if tok.kind == tokenOp && depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, tokens[start:])
+}
+
+// matchingBracket returns the index of the bracket closing tokens[0], or -1.
+func matchingBracket(tokens []token) int {
+	depth := 0
+
+	for i, tok := range tokens {
+		if tok.kind != tokenOp {
+			continue
+		}
+
+		switch tok.value {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// isAttribute reports whether tokens[i:] starts with "." and a name.
+func isAttribute(tokens []token, i int) bool {
+	if i+1 >= len(tokens) || tokens[i].kind != tokenOp || tokens[i].value != "." {
+		return false
+	}
+
+	return tokens[i+1].kind == tokenName
+}
+
+func isName(tok token, name string) bool {
+	return tok.kind == tokenName && tok.value == name
+}
+
+func aliasOr(alias, name string) string {
+	if alias != "" {
+		return alias
+	}
+
+	return name
+}
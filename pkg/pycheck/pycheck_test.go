@@ -0,0 +1,122 @@
+package pycheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestAnalyzeCalls(t *testing.T) {
+	code := `import pandas as pd
+from ethpandaops import clickhouse, prometheus as prom
+from ethpandaops.loki import query as logs
+import ethpandaops
+
+SQL = """
+SELECT count()
+FROM beacon_api_eth_v1_events_block
+"""
+
+df = clickhouse.query("xatu", SQL)  # comment with clickhouse.query("ignored")
+prom.query(datasource="ethpandaops", promql=f"up{{job='{job}'}}")
+logs("ops", '{app="geth"}'); ethpandaops.dora.get_slot("hoodi", 1)
+clickhouse.query_raw(
+    "xatu-cbt",
+    "SELECT 1 "
+    "FROM mainnet.fct_block_head",
+)
+pd.read_csv("x.csv")
+`
+
+	result := Analyze(code)
+	require.Nil(t, result.SyntaxError)
+
+	assert.Equal(t, []Import{
+		{Module: "clickhouse", Line: 2},
+		{Module: "prometheus", Line: 2},
+		{Module: "loki", Function: "query", Line: 3},
+	}, result.Imports)
+
+	require.Len(t, result.Calls, 5)
+
+	call := result.Calls[0]
+	assert.Equal(t, "clickhouse", call.Module)
+	assert.Equal(t, "query", call.Function)
+	assert.Equal(t, 11, call.Line)
+
+	cluster, ok := call.Arg(0, "cluster")
+	assert.True(t, ok)
+	assert.Equal(t, "xatu", cluster)
+
+	sql, ok := call.Arg(1, "sql")
+	assert.True(t, ok)
+	assert.Contains(t, sql, "FROM beacon_api_eth_v1_events_block")
+
+	call = result.Calls[1]
+	assert.Equal(t, "prometheus", call.Module)
+	datasource, ok := call.Arg(0, "datasource")
+	assert.True(t, ok)
+	assert.Equal(t, "ethpandaops", datasource)
+
+	assert.Equal(t, types.PythonCall{
+		Module:   "loki",
+		Function: "query",
+		Line:     13,
+		Args:     []types.PythonArg{{Value: "ops", Known: true}, {Value: `{app="geth"}`, Known: true}},
+	}, result.Calls[2])
+
+	assert.Equal(t, "dora", result.Calls[3].Module)
+	assert.Equal(t, "get_slot", result.Calls[3].Function)
+
+	call = result.Calls[4]
+	assert.Equal(t, 14, call.Line)
+	sql, ok = call.Arg(1, "sql")
+	assert.True(t, ok)
+	assert.Equal(t, "SELECT 1 FROM mainnet.fct_block_head", sql)
+}
+
+func TestAnalyzeUnresolvedArguments(t *testing.T) {
+	result := Analyze(`from ethpandaops import clickhouse
+cluster = pick()
+clickhouse.query(cluster, "SELECT " + cols)
+clickhouse = None
+clickhouse.query("xatu", "SELECT 1")
+`)
+
+	require.Len(t, result.Calls, 1)
+
+	_, ok := result.Calls[0].Arg(0, "cluster")
+	assert.False(t, ok)
+
+	_, ok = result.Calls[0].Arg(1, "sql")
+	assert.False(t, ok)
+}
+
+func TestAnalyzeSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		line    int
+		message string
+	}{
+		{name: "unclosed bracket", code: "x = foo(\n  1,\n", line: 1, message: "'(' was never closed"},
+		{name: "mismatched bracket", code: "x = [1, 2)\n", line: 1, message: "closing ')' does not match '[' opened on line 1"},
+		{name: "unmatched bracket", code: "x = 1\ny = 2)\n", line: 2, message: "unmatched ')'"},
+		{name: "unterminated string", code: "x = 'abc\ny = 1\n", line: 1, message: "unterminated string literal"},
+		{name: "unterminated triple quote", code: "x = 1\nsql = \"\"\"SELECT\n", line: 2, message: "unterminated triple-quoted string literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Analyze(tt.code)
+			require.NotNil(t, result.SyntaxError)
+			assert.Equal(t, tt.line, result.SyntaxError.Line)
+			assert.Equal(t, "syntax error: "+tt.message, result.SyntaxError.Message)
+		})
+	}
+
+	assert.Nil(t, Analyze("s = 'it\\'s'\nt = r'\\d+'\nu = \"\"\"a\n'b'\n\"\"\"\n").SyntaxError)
+}
@@ -0,0 +1,231 @@
+package pycheck
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenName tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOp
+	// tokenEnd ends a logical line: a newline outside brackets or a ";".
+	tokenEnd
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// syntaxError is the first tokenization problem found.
+type syntaxError struct {
+	line    int
+	message string
+}
+
+var closingBrackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// tokenize splits code into tokens. Comments and whitespace are dropped and
+// string tokens hold the literal's text without prefix or quotes. Tokens up
+// to the first syntax error are returned along with it.
+func tokenize(code string) ([]token, *syntaxError) {
+	var (
+		tokens []token
+		opened []token // unclosed brackets
+	)
+
+	line := 1
+
+	emitEnd := func() {
+		if len(tokens) > 0 && tokens[len(tokens)-1].kind != tokenEnd {
+			tokens = append(tokens, token{kind: tokenEnd, line: line})
+		}
+	}
+
+	for i := 0; i < len(code); {
+		c := code[i]
+
+		switch {
+		case c == '\n':
+			if len(opened) == 0 {
+				emitEnd()
+			}
+
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case c == '\\' && i+1 < len(code) && (code[i+1] == '\n' || code[i+1] == '\r'):
+			// Explicit line continuation.
+			i++
+			if code[i] == '\r' && i+1 < len(code) && code[i+1] == '\n' {
+				i++
+			}
+
+			line++
+			i++
+		case c == '#':
+			for i < len(code) && code[i] != '\n' {
+				i++
+			}
+		case c == ';' && len(opened) == 0:
+			emitEnd()
+			i++
+		case isNameStart(code, i):
+			start := i
+			for i < len(code) && isNameChar(code, i) {
+				_, size := utf8.DecodeRuneInString(code[i:])
+				i += size
+			}
+
+			word := code[start:i]
+
+			if i < len(code) && (code[i] == '\'' || code[i] == '"') && isStringPrefix(word) {
+				value, end, lines, err := scanString(code, i, line)
+				if err != nil {
+					return tokens, err
+				}
+
+				tokens = append(tokens, token{kind: tokenString, value: value, line: line})
+				line += lines
+				i = end
+
+				continue
+			}
+
+			tokens = append(tokens, token{kind: tokenName, value: word, line: line})
+		case c == '\'' || c == '"':
+			value, end, lines, err := scanString(code, i, line)
+			if err != nil {
+				return tokens, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value, line: line})
+			line += lines
+			i = end
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(code) && code[i+1] >= '0' && code[i+1] <= '9'):
+			start := i
+			for i < len(code) && (isNameChar(code, i) || code[i] == '.') {
+				_, size := utf8.DecodeRuneInString(code[i:])
+				i += size
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: code[start:i], line: line})
+		case c == '(' || c == '[' || c == '{':
+			tok := token{kind: tokenOp, value: string(c), line: line}
+			opened = append(opened, tok)
+			tokens = append(tokens, tok)
+			i++
+		case c == ')' || c == ']' || c == '}':
+			if len(opened) == 0 {
+				return tokens, &syntaxError{line: line, message: fmt.Sprintf("unmatched '%c'", c)}
+			}
+
+			open := opened[len(opened)-1]
+			if open.value[0] != closingBrackets[c] {
+				return tokens, &syntaxError{
+					line:    line,
+					message: fmt.Sprintf("closing '%c' does not match '%s' opened on line %d", c, open.value, open.line),
+				}
+			}
+
+			opened = opened[:len(opened)-1]
+			tokens = append(tokens, token{kind: tokenOp, value: string(c), line: line})
+			i++
+		default:
+			op := string(c)
+			if i+1 < len(code) && strings.Contains("=!<>", string(c)) && code[i+1] == '=' {
+				op = code[i : i+2]
+			}
+
+			tokens = append(tokens, token{kind: tokenOp, value: op, line: line})
+			i += len(op)
+		}
+	}
+
+	if len(opened) > 0 {
+		open := opened[len(opened)-1]
+
+		return tokens, &syntaxError{line: open.line, message: fmt.Sprintf("'%s' was never closed", open.value)}
+	}
+
+	emitEnd()
+
+	return tokens, nil
+}
+
+// scanString scans the string literal whose opening quote is at code[i]. It
+// returns the literal's content, the offset after its closing quote and the
+// number of newlines it spans.
+func scanString(code string, i, line int) (string, int, int, *syntaxError) {
+	quote := code[i]
+
+	delimiter := string(quote)
+	if strings.HasPrefix(code[i:], strings.Repeat(string(quote), 3)) {
+		delimiter = strings.Repeat(string(quote), 3)
+	}
+
+	start := i + len(delimiter)
+	lines := 0
+
+	for j := start; j < len(code); j++ {
+		switch {
+		case code[j] == '\\':
+			// Escapes, including in raw strings, never end the literal.
+			if j+1 < len(code) && code[j+1] == '\n' {
+				lines++
+			}
+
+			j++
+		case code[j] == '\n':
+			if len(delimiter) == 1 {
+				return "", 0, 0, &syntaxError{line: line, message: "unterminated string literal"}
+			}
+
+			lines++
+		case strings.HasPrefix(code[j:], delimiter):
+			return code[start:j], j + len(delimiter), lines, nil
+		}
+	}
+
+	if len(delimiter) == 3 {
+		return "", 0, 0, &syntaxError{line: line, message: "unterminated triple-quoted string literal"}
+	}
+
+	return "", 0, 0, &syntaxError{line: line, message: "unterminated string literal"}
+}
+
+// isStringPrefix reports whether word is a string literal prefix such as
+// "f", "rb" or "u".
+func isStringPrefix(word string) bool {
+	if len(word) > 2 {
+		return false
+	}
+
+	for _, r := range strings.ToLower(word) {
+		if !strings.ContainsRune("rbuf", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNameStart(code string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(code[i:])
+
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameChar(code string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(code[i:])
+
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
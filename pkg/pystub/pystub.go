@@ -0,0 +1,187 @@
+// Package pystub generates .pyi type stubs for the ethpandaops sandbox
+// library from the registered module documentation.
+package pystub
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// Package is the Python package the stubs describe.
+const Package = "ethpandaops"
+
+// File is a single generated stub file.
+type File struct {
+	// Path is the file path relative to the stub root, e.g. "ethpandaops/clickhouse.pyi".
+	Path    string
+	Content string
+}
+
+// signaturePattern splits "[module.]name(params) -> returns" signatures.
+var signaturePattern = regexp.MustCompile(`^(?:[A-Za-z_]\w*\.)?([A-Za-z_]\w*)\((.*)\)\s*(?:->\s*(.+))?$`)
+
+// Generate renders one stub file per module plus the package __init__.pyi.
+// Files are returned in a stable order.
+func Generate(docs map[string]types.ModuleDoc) []File {
+	names := sortedKeys(docs)
+	files := make([]File, 0, len(names)+1)
+
+	var init strings.Builder
+
+	init.WriteString(header)
+	init.WriteString("\n")
+
+	for _, name := range names {
+		fmt.Fprintf(&init, "from . import %s as %s\n", name, name)
+	}
+
+	files = append(files, File{Path: Package + "/__init__.pyi", Content: init.String()})
+
+	for _, name := range names {
+		files = append(files, File{
+			Path:    Package + "/" + name + ".pyi",
+			Content: renderModule(docs[name]),
+		})
+	}
+
+	return files
+}
+
+// Bundle concatenates generated files into a single document, each file
+// introduced by a path comment.
+func Bundle(files []File) string {
+	var sb strings.Builder
+
+	for i, file := range files {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "# ==> %s <==\n%s", file.Path, file.Content)
+	}
+
+	return sb.String()
+}
+
+const header = "# Generated by panda from the ethpandaops module documentation. Do not edit.\n"
+
+func renderModule(doc types.ModuleDoc) string {
+	var body strings.Builder
+
+	usesPandas := false
+
+	for _, fn := range sortedKeys(doc.Functions) {
+		stub := renderFunction(fn, doc.Functions[fn])
+		if strings.Contains(stub, "pandas.") {
+			usesPandas = true
+		}
+
+		body.WriteString("\n")
+		body.WriteString(stub)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(header)
+
+	if doc.Description != "" {
+		fmt.Fprintf(&sb, "%s\n", docstring(doc.Description, ""))
+	}
+
+	sb.WriteString("\nfrom typing import Any\n")
+
+	if usesPandas {
+		sb.WriteString("\nimport pandas\n")
+	}
+
+	sb.WriteString(body.String())
+
+	return sb.String()
+}
+
+func renderFunction(name string, fd types.FunctionDoc) string {
+	params, returns := "*args: Any, **kwargs: Any", "Any"
+
+	if matches := signaturePattern.FindStringSubmatch(strings.TrimSpace(fd.Signature)); matches != nil {
+		params = strings.TrimSpace(matches[2])
+
+		if ret := strings.TrimSpace(matches[3]); ret != "" {
+			returns = normalizeType(ret)
+		}
+	}
+
+	var doc strings.Builder
+
+	doc.WriteString(fd.Description)
+
+	if len(fd.Parameters) > 0 {
+		doc.WriteString("\n\nArgs:")
+
+		for _, param := range sortedKeys(fd.Parameters) {
+			fmt.Fprintf(&doc, "\n    %s: %s", param, fd.Parameters[param])
+		}
+	}
+
+	if fd.Returns != "" {
+		fmt.Fprintf(&doc, "\n\nReturns:\n    %s", fd.Returns)
+	}
+
+	if example := strings.TrimSpace(fd.Example); example != "" {
+		doc.WriteString("\n\nExample:")
+
+		for _, line := range strings.Split(example, "\n") {
+			doc.WriteString("\n    " + line)
+		}
+	}
+
+	return fmt.Sprintf("def %s(%s) -> %s:\n%s\n    ...\n", name, params, returns, docstring(strings.TrimSpace(doc.String()), "    "))
+}
+
+// normalizeType maps the informal return types used in signatures onto valid
+// annotations.
+func normalizeType(t string) string {
+	parts := strings.Split(t, "|")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "any" {
+			part = "Any"
+		}
+
+		parts[i] = part
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func docstring(text, indent string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"""`, `\"\"\"`)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i > 0 && line != "" {
+			lines[i] = indent + line
+		}
+	}
+
+	if len(lines) == 1 {
+		return indent + `"""` + lines[0] + `"""`
+	}
+
+	return indent + `"""` + strings.Join(lines, "\n") + "\n" + indent + `"""`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
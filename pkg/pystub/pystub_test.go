@@ -0,0 +1,59 @@
+package pystub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestGenerate(t *testing.T) {
+	files := Generate(map[string]types.ModuleDoc{
+		"clickhouse": {
+			Description: "Query ClickHouse",
+			Functions: map[string]types.FunctionDoc{
+				"query": {
+					Signature:   "clickhouse.query(cluster: str, sql: str) -> pandas.DataFrame",
+					Description: "Run a query",
+					Parameters:  map[string]string{"cluster": "Cluster name", "sql": "SQL text"},
+					Returns:     "DataFrame",
+					Example:     `df = clickhouse.query("xatu", "SELECT 1")`,
+				},
+			},
+		},
+		"dora": {
+			Functions: map[string]types.FunctionDoc{
+				"get_external_bounds": {Signature: "get_external_bounds(network, id=None) -> list|dict"},
+				"execution_rpc":       {Signature: "execution_rpc(network, method) -> any"},
+				"broken":              {Signature: "not a signature"},
+			},
+		},
+	})
+
+	require.Len(t, files, 3)
+	assert.Equal(t, "ethpandaops/__init__.pyi", files[0].Path)
+	assert.Contains(t, files[0].Content, "from . import clickhouse as clickhouse\nfrom . import dora as dora\n")
+
+	assert.Equal(t, "ethpandaops/clickhouse.pyi", files[1].Path)
+	assert.Contains(t, files[1].Content, "import pandas\n")
+	assert.Contains(t, files[1].Content, "def query(cluster: str, sql: str) -> pandas.DataFrame:\n")
+	assert.Contains(t, files[1].Content, "    Args:\n        cluster: Cluster name\n        sql: SQL text\n")
+	assert.Contains(t, files[1].Content, `        df = clickhouse.query("xatu", "SELECT 1")`)
+
+	dora := files[2].Content
+	assert.NotContains(t, dora, "import pandas")
+	assert.Contains(t, dora, "def get_external_bounds(network, id=None) -> list | dict:\n")
+	assert.Contains(t, dora, "def execution_rpc(network, method) -> Any:\n")
+	assert.Contains(t, dora, "def broken(*args: Any, **kwargs: Any) -> Any:\n")
+}
+
+func TestBundle(t *testing.T) {
+	bundle := Bundle([]File{
+		{Path: "ethpandaops/a.pyi", Content: "A\n"},
+		{Path: "ethpandaops/b.pyi", Content: "B\n"},
+	})
+
+	assert.Equal(t, "# ==> ethpandaops/a.pyi <==\nA\n\n# ==> ethpandaops/b.pyi <==\nB\n", bundle)
+}
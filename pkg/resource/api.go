@@ -2,19 +2,39 @@ package resource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/pystub"
 	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/pkg/storage"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
-// RegisterAPIResources registers the python://ethpandaops resource
-// with the registry.
+// StubsURI is the URI of the generated Python type stubs resource.
+const StubsURI = "python://ethpandaops/stubs.pyi"
+
+// apiLibrary is the Python library documented by the API resources.
+const apiLibrary = "ethpandaops"
+
+var (
+	// moduleDocURIPattern matches python://ethpandaops/{module} URIs.
+	moduleDocURIPattern = regexp.MustCompile(`^python://ethpandaops/([a-z0-9_]+)$`)
+
+	// moduleDocJSONURIPattern matches python://ethpandaops/{module}.json URIs.
+	moduleDocJSONURIPattern = regexp.MustCompile(`^python://ethpandaops/([a-z0-9_]+)\.json$`)
+)
+
+// RegisterAPIResources registers the python://ethpandaops resource, the
+// per-module python://ethpandaops/{module} templates and the generated type
+// stubs with the registry.
 func RegisterAPIResources(log logrus.FieldLogger, reg Registry, moduleReg *module.Registry) {
 	log = log.WithField("resource", "api")
 
@@ -22,53 +42,85 @@ func RegisterAPIResources(log logrus.FieldLogger, reg Registry, moduleReg *modul
 		Resource: mcp.NewResource(
 			"python://ethpandaops",
 			"ethpandaops Python Library API",
-			mcp.WithResourceDescription("API documentation for the ethpandaops Python library"),
+			mcp.WithResourceDescription("API documentation for every module of the ethpandaops Python library"),
 			mcp.WithMIMEType("application/json"),
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.9),
 		),
 		Handler: createAPIHandler(moduleReg),
 	})
 
+	reg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"python://ethpandaops/{module}",
+			"ethpandaops Module API",
+			mcp.WithTemplateDescription("API documentation for a single ethpandaops module (e.g. clickhouse, storage)"),
+			mcp.WithTemplateMIMEType("text/markdown"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.9),
+		),
+		Pattern: moduleDocURIPattern,
+		Handler: createModuleDocHandler(moduleReg),
+	})
+
+	reg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"python://ethpandaops/{module}.json",
+			"ethpandaops Module API (JSON)",
+			mcp.WithTemplateDescription("Machine-readable API documentation for a single ethpandaops module"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.7),
+		),
+		Pattern: moduleDocJSONURIPattern,
+		Handler: createModuleDocJSONHandler(moduleReg),
+	})
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			StubsURI,
+			"ethpandaops Python Type Stubs",
+			mcp.WithResourceDescription("Generated .pyi type stubs for every ethpandaops module, for checking function names, parameters and return types"),
+			mcp.WithMIMEType("text/x-python"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: func(_ context.Context, _ string) (string, error) {
+			return pystub.Bundle(pystub.Generate(apiDocs(moduleReg))), nil
+		},
+	})
+
 	log.Debug("Registered API resources")
 }
 
+// apiDocs returns the docs for all initialized modules plus the
+// platform-owned storage module.
+func apiDocs(moduleReg *module.Registry) map[string]types.ModuleDoc {
+	modules := moduleReg.PythonAPIDocs()
+
+	modules["storage"] = storage.PythonAPIDoc()
+
+	return modules
+}
+
+// moduleDoc looks up the docs for a single module.
+func moduleDoc(moduleReg *module.Registry, name string) (types.ModuleDoc, error) {
+	docs := apiDocs(moduleReg)
+
+	doc, ok := docs[name]
+	if !ok {
+		return types.ModuleDoc{}, fmt.Errorf("unknown module %q (available: %s)", name, strings.Join(sortedKeys(docs), ", "))
+	}
+
+	return doc, nil
+}
+
 func createAPIHandler(moduleReg *module.Registry) ReadHandler {
 	return func(_ context.Context, _ string) (string, error) {
-		modules := moduleReg.PythonAPIDocs()
-
-		// Add platform-owned storage module.
-		modules["storage"] = types.ModuleDoc{
-			Description: "Upload files to storage for sharing",
-			Functions: map[string]types.FunctionDoc{
-				"upload": {
-					Signature:   "storage.upload(local_path: str, remote_name: str = None) -> str",
-					Description: "Upload a local file to storage and return the public URL",
-					Parameters: map[string]string{
-						"local_path":  "Path to file (e.g., '/workspace/chart.png')",
-						"remote_name": "Optional: custom name for the stored file",
-					},
-					Returns: "Public URL string",
-				},
-				"list_files": {
-					Signature:   "storage.list_files(prefix: str = '') -> list[dict]",
-					Description: "List uploaded files",
-					Returns:     "List of dicts with 'key', 'size', 'last_modified'",
-				},
-				"get_url": {
-					Signature:   "storage.get_url(key: str) -> str",
-					Description: "Get public URL for a stored file",
-					Returns:     "Public URL string",
-				},
-			},
-		}
-
 		response := serverapi.APIDocResponse{
-			Library:     "ethpandaops",
-			Description: "Data access library for Ethereum network analytics. Import: from ethpandaops import clickhouse, prometheus, loki, storage",
-			Modules:     modules,
+			Library: apiLibrary,
+			Description: "Data access library for Ethereum network analytics. Import: from ethpandaops import clickhouse, prometheus, loki, storage. " +
+				"Read python://ethpandaops/{module} for a single module.",
+			Modules: apiDocs(moduleReg),
 		}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling API docs: %w", err)
 		}
@@ -76,3 +128,104 @@ func createAPIHandler(moduleReg *module.Registry) ReadHandler {
 		return string(data), nil
 	}
 }
+
+// createModuleDocHandler returns a handler for python://ethpandaops/{module}.
+func createModuleDocHandler(moduleReg *module.Registry) ReadHandler {
+	return func(_ context.Context, uri string) (string, error) {
+		matches := moduleDocURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid module docs URI: %s", uri)
+		}
+
+		doc, err := moduleDoc(moduleReg, matches[1])
+		if err != nil {
+			return "", err
+		}
+
+		return renderModuleDocMarkdown(matches[1], doc), nil
+	}
+}
+
+// createModuleDocJSONHandler returns a handler for python://ethpandaops/{module}.json.
+func createModuleDocJSONHandler(moduleReg *module.Registry) ReadHandler {
+	return func(_ context.Context, uri string) (string, error) {
+		matches := moduleDocJSONURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid module docs URI: %s", uri)
+		}
+
+		doc, err := moduleDoc(moduleReg, matches[1])
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(serverapi.ModuleAPIDocResponse{
+			Library:   apiLibrary,
+			Module:    matches[1],
+			Import:    moduleImport(matches[1]),
+			ModuleDoc: doc,
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling module docs: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// renderModuleDocMarkdown renders one module's docs with functions and
+// parameters in a stable order.
+func renderModuleDocMarkdown(name string, doc types.ModuleDoc) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s.%s\n\n", apiLibrary, name)
+
+	if doc.Description != "" {
+		sb.WriteString(doc.Description + "\n\n")
+	}
+
+	fmt.Fprintf(&sb, "```python\n%s\n```\n", moduleImport(name))
+
+	for _, fn := range sortedKeys(doc.Functions) {
+		fd := doc.Functions[fn]
+
+		fmt.Fprintf(&sb, "\n## %s\n\n```python\n%s\n```\n", fn, fd.Signature)
+
+		if fd.Description != "" {
+			sb.WriteString("\n" + fd.Description + "\n")
+		}
+
+		if len(fd.Parameters) > 0 {
+			sb.WriteString("\n**Parameters:**\n\n")
+
+			for _, param := range sortedKeys(fd.Parameters) {
+				fmt.Fprintf(&sb, "- `%s`: %s\n", param, fd.Parameters[param])
+			}
+		}
+
+		if fd.Returns != "" {
+			fmt.Fprintf(&sb, "\n**Returns:** %s\n", fd.Returns)
+		}
+
+		if example := strings.TrimSpace(fd.Example); example != "" {
+			fmt.Fprintf(&sb, "\n**Example:**\n\n```python\n%s\n```\n", example)
+		}
+	}
+
+	return sb.String()
+}
+
+func moduleImport(name string) string {
+	return fmt.Sprintf("from %s import %s", apiLibrary, name)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,73 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/serverapi"
+)
+
+func newAPITestRegistry(t *testing.T) Registry {
+	t.Helper()
+
+	log := logrus.New()
+	reg := NewRegistry(log)
+	RegisterAPIResources(log, reg, module.NewRegistry(log))
+
+	return reg
+}
+
+func TestModuleDocResources(t *testing.T) {
+	reg := newAPITestRegistry(t)
+	ctx := context.Background()
+
+	t.Run("markdown", func(t *testing.T) {
+		content, mimeType, err := reg.Read(ctx, "python://ethpandaops/storage")
+		require.NoError(t, err)
+		assert.Equal(t, "text/markdown", mimeType)
+		assert.Contains(t, content, "# ethpandaops.storage")
+		assert.Contains(t, content, "from ethpandaops import storage")
+		assert.Less(t, strings.Index(content, "## get_url"), strings.Index(content, "## upload"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content, mimeType, err := reg.Read(ctx, "python://ethpandaops/storage.json")
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", mimeType)
+
+		var doc serverapi.ModuleAPIDocResponse
+		require.NoError(t, json.Unmarshal([]byte(content), &doc))
+		assert.Equal(t, "storage", doc.Module)
+		assert.Equal(t, "from ethpandaops import storage", doc.Import)
+		assert.Contains(t, doc.Functions, "upload")
+	})
+
+	t.Run("unknown module", func(t *testing.T) {
+		_, _, err := reg.Read(ctx, "python://ethpandaops/nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "available: storage")
+	})
+
+	t.Run("full docs still served", func(t *testing.T) {
+		content, _, err := reg.Read(ctx, "python://ethpandaops")
+		require.NoError(t, err)
+
+		var docs serverapi.APIDocResponse
+		require.NoError(t, json.Unmarshal([]byte(content), &docs))
+		assert.Contains(t, docs.Modules, "storage")
+	})
+	t.Run("stubs", func(t *testing.T) {
+		content, mimeType, err := reg.Read(ctx, StubsURI)
+		require.NoError(t, err)
+		assert.Equal(t, "text/x-python", mimeType)
+		assert.Contains(t, content, "# ==> ethpandaops/storage.pyi <==")
+		assert.Contains(t, content, "def upload(local_path: str, remote_name: str = None) -> str:")
+	})
+}
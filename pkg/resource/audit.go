@@ -0,0 +1,114 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
+)
+
+// auditSearchURIPattern matches audit://search with optional query parameters.
+var auditSearchURIPattern = regexp.MustCompile(`^audit://search(\?.*)?$`)
+
+// ErrAuditForbidden is returned when the caller is not an audit admin.
+var ErrAuditForbidden = errors.New("audit resources require membership of server.admin_orgs")
+
+// AuditSearcher searches the proxy's recent audit entries.
+type AuditSearcher interface {
+	Search(ctx context.Context, q audit.Query) (*audit.SearchResponse, error)
+}
+
+// AuditResponse is the response for audit://recent and audit://search.
+type AuditResponse struct {
+	Entries  []audit.Entry `json:"entries"`
+	Retained int           `json:"retained"`
+	Usage    string        `json:"usage"`
+}
+
+// RegisterAuditResources registers the admin-only proxy audit resources.
+// Reads are allowed only for users in one of adminOrgs.
+func RegisterAuditResources(log logrus.FieldLogger, reg Registry, searcher AuditSearcher, adminOrgs []string) {
+	log = log.WithField("resource", "audit")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"audit://recent",
+			"Recent Proxy Audit",
+			mcp.WithResourceDescription("Most recent proxy requests with user, datasource, status and duration (admins only)"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleUser}, 0.2),
+		),
+		Handler: createAuditHandler(searcher, adminOrgs),
+	})
+
+	reg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"audit://search{?user,datasource,since,until,status,limit}",
+			"Search Proxy Audit",
+			mcp.WithTemplateDescription("Proxy audit entries filtered by user, datasource, time range (RFC 3339 or a duration such as 1h) and status (403 or 4xx); admins only"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleUser}, 0.2),
+		),
+		Pattern: auditSearchURIPattern,
+		Handler: createAuditHandler(searcher, adminOrgs),
+	})
+
+	log.Debug("Registered audit resources")
+}
+
+// createAuditHandler returns a handler for audit://recent and audit://search.
+func createAuditHandler(searcher AuditSearcher, adminOrgs []string) ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		if !isAuditAdmin(ctx, adminOrgs) {
+			return "", ErrAuditForbidden
+		}
+
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return "", fmt.Errorf("invalid audit URI: %w", err)
+		}
+
+		query, err := audit.ParseQuery(parsed.Query(), time.Now())
+		if err != nil {
+			return "", err
+		}
+
+		result, err := searcher.Search(ctx, query)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(AuditResponse{
+			Entries:  result.Entries,
+			Retained: result.Retained,
+			Usage:    "Newest first. Filter with audit://search?user=&datasource=&since=1h&until=&status=4xx&limit=100.",
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling audit entries: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// isAuditAdmin reports whether the authenticated user is in one of adminOrgs.
+// Unauthenticated callers are never admins.
+func isAuditAdmin(ctx context.Context, adminOrgs []string) bool {
+	for _, group := range auth.GetAuthGroups(ctx) {
+		if slices.Contains(adminOrgs, group) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
+)
+
+type stubAuditSearcher struct {
+	queries []audit.Query
+}
+
+func (s *stubAuditSearcher) Search(_ context.Context, q audit.Query) (*audit.SearchResponse, error) {
+	s.queries = append(s.queries, q)
+
+	return &audit.SearchResponse{}, nil
+}
+
+func TestAuditResourcesRequireAdmin(t *testing.T) {
+	searcher := &stubAuditSearcher{}
+	reg := NewRegistry(logrus.New())
+	RegisterAuditResources(logrus.New(), reg, searcher, []string{"auditors"})
+
+	require.Len(t, reg.ListStatic(), 1)
+	require.Len(t, reg.ListTemplates(), 1)
+
+	for _, uri := range []string{"audit://recent", "audit://search?user=alice&status=4xx"} {
+		_, _, err := reg.Read(context.Background(), uri)
+		require.ErrorIs(t, err, ErrAuditForbidden, uri)
+	}
+
+	assert.Empty(t, searcher.queries, "unauthenticated reads never reach the proxy")
+}
@@ -0,0 +1,139 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// HealthChecker reports cached module and proxy health.
+type HealthChecker interface {
+	Check(ctx context.Context) []types.ModuleHealth
+	CheckProxy(ctx context.Context, proxySvc proxy.Service) types.HealthProbe
+}
+
+// DatasourcesHealthResponse is the response for datasources://health.
+type DatasourcesHealthResponse struct {
+	Proxy       types.HealthProbe  `json:"proxy"`
+	Datasources []DatasourceHealth `json:"datasources"`
+}
+
+// DatasourceHealth is a datasource with whether it can be queried right now.
+type DatasourceHealth struct {
+	types.DatasourceInfo
+	// Health is the status of the datasource's latest probe: healthy,
+	// unhealthy, or not_probed for modules without health probes.
+	Health string             `json:"health"`
+	Probe  *types.HealthProbe `json:"probe,omitempty"`
+	// Usable is true when the proxy is reachable, no maintenance window is
+	// active, the proxy's circuit breaker is not open and the latest probe,
+	// if any, succeeded.
+	Usable bool `json:"usable"`
+	// Alternatives names the usable datasources of the same type, listed
+	// when this one is not usable.
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// RegisterDatasourceHealthResource registers datasources://health, which
+// merges datasource info with health probes and proxy reachability.
+func RegisterDatasourceHealthResource(
+	log logrus.FieldLogger,
+	reg Registry,
+	moduleReg *module.Registry,
+	policy *auth.DatasourcePolicy,
+	checker HealthChecker,
+	proxySvc proxy.Service,
+) {
+	log = log.WithField("resource", "datasources_health")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"datasources://health",
+			"Datasource Health",
+			mcp.WithResourceDescription("Which datasources are usable right now, from health probes, maintenance windows, circuit breakers and proxy reachability, with usable alternatives for those that are not"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.7),
+		),
+		Handler: createDatasourceHealthHandler(NewDatasourceProvider(moduleReg, policy), checker, proxySvc),
+	})
+
+	log.Debug("Registered datasource health resource")
+}
+
+func createDatasourceHealthHandler(provider *DatasourceProvider, checker HealthChecker, proxySvc proxy.Service) ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		response := DatasourcesHealthResponse{
+			Proxy: types.HealthProbe{Datasource: "proxy", Error: "no proxy configured"},
+		}
+
+		if proxySvc != nil {
+			response.Proxy = checker.CheckProxy(ctx, proxySvc)
+		}
+
+		response.Datasources = datasourceHealth(provider.DatasourceInfoFor(ctx), checker.Check(ctx), response.Proxy.Healthy)
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling datasource health: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// datasourceHealth matches each datasource with the probe of the same name
+// from the module of its type.
+func datasourceHealth(infos []types.DatasourceInfo, modules []types.ModuleHealth, proxyReachable bool) []DatasourceHealth {
+	probes := make(map[string]types.HealthProbe, len(infos))
+
+	for _, m := range modules {
+		for _, probe := range m.Probes {
+			probes[m.Module+"/"+probe.Datasource] = probe
+		}
+	}
+
+	result := make([]DatasourceHealth, 0, len(infos))
+	usable := make(map[string][]string, 4)
+
+	for _, info := range infos {
+		health := DatasourceHealth{DatasourceInfo: info, Health: types.HealthStatusNotProbed}
+
+		probeOK := true
+
+		if probe, ok := probes[info.Type+"/"+info.Name]; ok {
+			health.Probe = &probe
+			health.Health = types.HealthStatusUnhealthy
+			probeOK = probe.Healthy
+
+			if probe.Healthy {
+				health.Health = types.HealthStatusHealthy
+			}
+		}
+
+		inMaintenance := info.Status != nil && !info.Status.Available
+		breakerOpen := info.CircuitBreaker != nil && info.CircuitBreaker.State == types.CircuitBreakerOpen
+		health.Usable = proxyReachable && probeOK && !inMaintenance && !breakerOpen
+
+		if health.Usable {
+			usable[info.Type] = append(usable[info.Type], info.Name)
+		}
+
+		result = append(result, health)
+	}
+
+	for i := range result {
+		if !result[i].Usable {
+			result[i].Alternatives = usable[result[i].Type]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestDatasourceHealth(t *testing.T) {
+	infos := []types.DatasourceInfo{
+		{Type: "clickhouse", Name: "xatu"},
+		{Type: "clickhouse", Name: "xatu-cbt"},
+		{Type: "clickhouse", Name: "archive", Status: &types.DatasourceStatus{Available: false, Reason: "upgrade"}},
+		{Type: "loki", Name: "logs"},
+		{Type: "loki", Name: "flapping", CircuitBreaker: &types.CircuitBreakerStatus{State: types.CircuitBreakerOpen}},
+	}
+	modules := []types.ModuleHealth{
+		{Module: "clickhouse", Probes: []types.HealthProbe{
+			{Datasource: "xatu", Error: "status 503"},
+			{Datasource: "xatu-cbt", Healthy: true},
+			{Datasource: "archive", Healthy: true},
+		}},
+	}
+
+	result := datasourceHealth(infos, modules, true)
+	require.Len(t, result, 5)
+
+	byName := make(map[string]DatasourceHealth, len(result))
+	for _, health := range result {
+		byName[health.Name] = health
+	}
+
+	assert.False(t, byName["xatu"].Usable)
+	assert.Equal(t, types.HealthStatusUnhealthy, byName["xatu"].Health)
+	assert.Equal(t, []string{"xatu-cbt"}, byName["xatu"].Alternatives)
+
+	assert.True(t, byName["xatu-cbt"].Usable)
+	assert.Empty(t, byName["xatu-cbt"].Alternatives)
+
+	// Maintenance makes a healthy datasource unusable.
+	assert.False(t, byName["archive"].Usable)
+	assert.Equal(t, types.HealthStatusHealthy, byName["archive"].Health)
+
+	// Datasources without probes are usable while the proxy is reachable.
+	assert.True(t, byName["logs"].Usable)
+	assert.Equal(t, types.HealthStatusNotProbed, byName["logs"].Health)
+
+	// So does an open circuit breaker.
+	assert.False(t, byName["flapping"].Usable)
+	assert.Equal(t, []string{"logs"}, byName["flapping"].Alternatives)
+
+	for _, health := range datasourceHealth(infos, modules, false) {
+		assert.False(t, health.Usable, health.Name)
+	}
+}
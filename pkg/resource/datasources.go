@@ -2,12 +2,13 @@ package resource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
@@ -20,12 +21,15 @@ type DatasourcesJSONResponse struct {
 // DatasourceProvider provides datasource information from the module registry.
 type DatasourceProvider struct {
 	moduleReg *module.Registry
+	policy    *auth.DatasourcePolicy
 }
 
-// NewDatasourceProvider creates a new datasource provider.
-func NewDatasourceProvider(moduleReg *module.Registry) *DatasourceProvider {
+// NewDatasourceProvider creates a new datasource provider. policy scopes the
+// datasources each user sees and may be nil.
+func NewDatasourceProvider(moduleReg *module.Registry, policy *auth.DatasourcePolicy) *DatasourceProvider {
 	return &DatasourceProvider{
 		moduleReg: moduleReg,
+		policy:    policy,
 	}
 }
 
@@ -38,22 +42,28 @@ func (p *DatasourceProvider) DatasourceInfo() []types.DatasourceInfo {
 	return p.moduleReg.DatasourceInfo()
 }
 
+// DatasourceInfoFor returns the datasources the user in ctx may use.
+func (p *DatasourceProvider) DatasourceInfoFor(ctx context.Context) []types.DatasourceInfo {
+	return p.policy.Filter(auth.GetAuthGroups(ctx), p.DatasourceInfo())
+}
+
 // RegisterDatasourcesResources registers the datasources:// resources
 // with the registry.
 func RegisterDatasourcesResources(
 	log logrus.FieldLogger,
 	reg Registry,
 	moduleReg *module.Registry,
+	policy *auth.DatasourcePolicy,
 ) {
 	log = log.WithField("resource", "datasources")
-	provider := NewDatasourceProvider(moduleReg)
+	provider := NewDatasourceProvider(moduleReg, policy)
 
 	// datasources://list - all datasources
 	reg.RegisterStatic(StaticResource{
 		Resource: mcp.NewResource(
 			"datasources://list",
 			"All Datasources",
-			mcp.WithResourceDescription("List of all configured datasources (ClickHouse, Prometheus, Loki)"),
+			mcp.WithResourceDescription("List of all configured datasources (ClickHouse, Prometheus, Loki, Grafana, HTTP JSON)"),
 			mcp.WithMIMEType("application/json"),
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.8),
 		),
@@ -96,12 +106,48 @@ func RegisterDatasourcesResources(
 		Handler: createDatasourcesHandler(provider, "loki"),
 	})
 
+	// datasources://grafana
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"datasources://grafana",
+			"Grafana Datasources",
+			mcp.WithResourceDescription("Configured Grafana instances for dashboard lookup and panel queries"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.7),
+		),
+		Handler: createDatasourcesHandler(provider, "grafana"),
+	})
+
+	// datasources://github
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"datasources://github",
+			"GitHub Repositories",
+			mcp.WithResourceDescription("Client and tooling repositories whose issues, pull requests and releases are readable"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createDatasourcesHandler(provider, "github"),
+	})
+
+	// datasources://httpjson
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"datasources://httpjson",
+			"HTTP JSON Datasources",
+			mcp.WithResourceDescription("Operator-declared JSON HTTP endpoints and the paths each one exposes"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createDatasourcesHandler(provider, "httpjson"),
+	})
+
 	log.Debug("Registered datasources resources")
 }
 
 func createDatasourcesHandler(provider *DatasourceProvider, filterType string) ReadHandler {
-	return func(_ context.Context, _ string) (string, error) {
-		allInfos := provider.DatasourceInfo()
+	return func(ctx context.Context, _ string) (string, error) {
+		allInfos := provider.DatasourceInfoFor(ctx)
 
 		var filtered []types.DatasourceInfo
 		if filterType == "" {
@@ -121,7 +167,7 @@ func createDatasourcesHandler(provider *DatasourceProvider, filterType string) R
 
 		response := DatasourcesJSONResponse{Datasources: filtered}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling datasources: %w", err)
 		}
@@ -152,8 +152,13 @@ func (idx *EIPIndex) Search(query string, limit int) ([]EIPSearchResult, error)
 		results = append(results, scored{eipIdx: eipIdx, score: score})
 	}
 
+	// bestByEIP is a map, so break score ties by index for a stable order.
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].score > results[j].score
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+
+		return results[i].eipIdx < results[j].eipIdx
 	})
 
 	if limit > len(results) {
@@ -3,6 +3,7 @@ package resource
 import (
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -29,6 +30,8 @@ type indexedExample struct {
 // ExampleIndex provides semantic search over query examples.
 type ExampleIndex struct {
 	embedder embedding.Embedder
+
+	mu       sync.RWMutex
 	examples []indexedExample
 }
 
@@ -88,6 +91,9 @@ func (idx *ExampleIndex) Search(query string, limit int) ([]SearchResult, error)
 		score float64
 	}
 
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
 	scores := make([]scored, 0, len(idx.examples))
 	for i, ex := range idx.examples {
 		scores = append(scores, scored{index: i, score: dotProduct(queryVec, ex.Vector)})
@@ -115,6 +121,26 @@ func (idx *ExampleIndex) Search(query string, limit int) ([]SearchResult, error)
 	return results, nil
 }
 
+// Add embeds and indexes an example added after the index was built.
+func (idx *ExampleIndex) Add(categoryKey, categoryName string, example types.Example) error {
+	vector, err := idx.embedder.Embed(example.Name + ". " + example.Description)
+	if err != nil {
+		return fmt.Errorf("embedding example: %w", err)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.examples = append(idx.examples, indexedExample{
+		CategoryKey:  categoryKey,
+		CategoryName: categoryName,
+		Example:      example,
+		Vector:       vector,
+	})
+
+	return nil
+}
+
 // Close releases resources held by the index.
 func (idx *ExampleIndex) Close() error {
 	return idx.embedder.Close()
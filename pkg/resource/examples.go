@@ -2,17 +2,37 @@ package resource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
-// RegisterExamplesResources registers the examples://queries resource.
+// ExamplesPageResponse is the response for examples://queries read with list
+// parameters. Examples are paged in category key order.
+type ExamplesPageResponse struct {
+	Categories map[string]ExamplesPageCategory `json:"categories"`
+	Total      int                             `json:"total"`
+	NextCursor string                          `json:"next_cursor,omitempty"`
+}
+
+// ExamplesPageCategory holds the examples of one category on a page: in
+// full in the detailed view, or only their names in the compact view.
+type ExamplesPageCategory struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Examples     []types.Example `json:"examples,omitempty"`
+	ExampleNames []string        `json:"example_names,omitempty"`
+}
+
+// RegisterExamplesResources registers the examples://queries and
+// examples://coverage resources.
 func RegisterExamplesResources(log logrus.FieldLogger, reg Registry, moduleReg *module.Registry) {
 	log = log.WithField("resource", "examples")
 
@@ -25,16 +45,34 @@ func RegisterExamplesResources(log logrus.FieldLogger, reg Registry, moduleReg *
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
 		),
 		Handler: createExamplesHandler(moduleReg),
+		Paged:   true,
+	})
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"examples://coverage",
+			"Examples Coverage",
+			mcp.WithResourceDescription("Discovered ClickHouse tables and Prometheus metrics with and without query examples"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.2),
+		),
+		Handler: createExamplesCoverageHandler(moduleReg),
 	})
 
 	log.Debug("Registered examples resources")
 }
 
 func createExamplesHandler(moduleReg *module.Registry) ReadHandler {
-	return func(_ context.Context, _ string) (string, error) {
+	return func(ctx context.Context, _ string) (string, error) {
 		examples := moduleReg.Examples()
 
-		data, err := json.MarshalIndent(examples, "", "  ")
+		// Without list parameters the resource keeps its original shape.
+		var response any = examples
+		if params := types.GetListParams(ctx); params.Paged() || params.Compact() {
+			response = pageExamples(examples, params)
+		}
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling examples: %w", err)
 		}
@@ -47,3 +85,47 @@ func createExamplesHandler(moduleReg *module.Registry) ReadHandler {
 func GetQueryExamples(moduleReg *module.Registry) map[string]types.ExampleCategory {
 	return moduleReg.Examples()
 }
+
+// pageExamples returns the requested page of examples across categories.
+func pageExamples(examples map[string]types.ExampleCategory, params types.ListParams) ExamplesPageResponse {
+	type entry struct {
+		category string
+		example  types.Example
+	}
+
+	keys := slices.Sorted(maps.Keys(examples))
+	entries := make([]entry, 0, 64)
+
+	for _, key := range keys {
+		for _, example := range examples[key].Examples {
+			entries = append(entries, entry{category: key, example: example})
+		}
+	}
+
+	start, end, next := params.Page(len(entries))
+	response := ExamplesPageResponse{
+		Categories: make(map[string]ExamplesPageCategory, len(keys)),
+		Total:      len(entries),
+		NextCursor: next,
+	}
+
+	for _, e := range entries[start:end] {
+		category, ok := response.Categories[e.category]
+		if !ok {
+			category = ExamplesPageCategory{
+				Name:        examples[e.category].Name,
+				Description: examples[e.category].Description,
+			}
+		}
+
+		if params.Compact() {
+			category.ExampleNames = append(category.ExampleNames, e.example.Name)
+		} else {
+			category.Examples = append(category.Examples, e.example)
+		}
+
+		response.Categories[e.category] = category
+	}
+
+	return response
+}
@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// identifierPattern matches table and metric names inside example queries.
+// Colons are included for Prometheus recording rule names.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_:][A-Za-z0-9_:]*`)
+
+// ExamplesCoverageResponse is the response for examples://coverage.
+type ExamplesCoverageResponse struct {
+	Summary   []CoverageSummary      `json:"summary"`
+	Uncovered []types.CoverageTarget `json:"uncovered"`
+	Covered   []CoveredTarget        `json:"covered"`
+	Errors    map[string]string      `json:"errors,omitempty"`
+}
+
+// CoverageSummary counts covered and uncovered targets of one kind.
+type CoverageSummary struct {
+	Kind      string  `json:"kind"`
+	Total     int     `json:"total"`
+	Covered   int     `json:"covered"`
+	Uncovered int     `json:"uncovered"`
+	Percent   float64 `json:"percent"`
+}
+
+// CoveredTarget is a target referenced by at least one example.
+type CoveredTarget struct {
+	types.CoverageTarget
+	Examples []string `json:"examples"`
+}
+
+func createExamplesCoverageHandler(moduleReg *module.Registry) ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		targets, errs := moduleReg.CoverageTargets(ctx)
+
+		response := BuildExamplesCoverage(moduleReg.Examples(), targets)
+
+		if len(errs) > 0 {
+			response.Errors = make(map[string]string, len(errs))
+			for name, err := range errs {
+				response.Errors[name] = err.Error()
+			}
+		}
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling examples coverage: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// BuildExamplesCoverage reports which targets are referenced by name in at
+// least one example query. Names must appear as a whole identifier, so
+// "fct_block" does not cover "fct_block_head".
+func BuildExamplesCoverage(examples map[string]types.ExampleCategory, targets []types.CoverageTarget) ExamplesCoverageResponse {
+	references := make(map[string][]string, 256)
+
+	for categoryKey, category := range examples {
+		for _, example := range category.Examples {
+			label := categoryKey + "/" + example.Name
+			seen := make(map[string]struct{}, 16)
+
+			for _, ident := range identifierPattern.FindAllString(example.Query, -1) {
+				if _, ok := seen[ident]; ok {
+					continue
+				}
+
+				seen[ident] = struct{}{}
+				references[ident] = append(references[ident], label)
+			}
+		}
+	}
+
+	response := ExamplesCoverageResponse{
+		Uncovered: make([]types.CoverageTarget, 0),
+		Covered:   make([]CoveredTarget, 0),
+	}
+
+	summaries := make(map[string]*CoverageSummary, 2)
+
+	for _, target := range targets {
+		summary, ok := summaries[target.Kind]
+		if !ok {
+			summary = &CoverageSummary{Kind: target.Kind}
+			summaries[target.Kind] = summary
+		}
+
+		summary.Total++
+
+		labels := references[target.Name]
+		if len(labels) == 0 {
+			summary.Uncovered++
+			response.Uncovered = append(response.Uncovered, target)
+
+			continue
+		}
+
+		sorted := append([]string(nil), labels...)
+		sort.Strings(sorted)
+
+		summary.Covered++
+		response.Covered = append(response.Covered, CoveredTarget{CoverageTarget: target, Examples: sorted})
+	}
+
+	response.Summary = make([]CoverageSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.Total > 0 {
+			summary.Percent = float64(summary.Covered) * 100 / float64(summary.Total)
+		}
+
+		response.Summary = append(response.Summary, *summary)
+	}
+
+	sort.Slice(response.Summary, func(i, j int) bool {
+		return response.Summary[i].Kind < response.Summary[j].Kind
+	})
+
+	sort.Slice(response.Uncovered, func(i, j int) bool {
+		return lessTarget(response.Uncovered[i], response.Uncovered[j])
+	})
+
+	sort.Slice(response.Covered, func(i, j int) bool {
+		return lessTarget(response.Covered[i].CoverageTarget, response.Covered[j].CoverageTarget)
+	})
+
+	return response
+}
+
+func lessTarget(a, b types.CoverageTarget) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+
+	if a.Datasource != b.Datasource {
+		return a.Datasource < b.Datasource
+	}
+
+	return a.Name < b.Name
+}
@@ -0,0 +1,44 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestBuildExamplesCoverage(t *testing.T) {
+	examples := map[string]types.ExampleCategory{
+		"blocks": {
+			Name: "Blocks",
+			Examples: []types.Example{
+				{Name: "Head blocks", Query: "SELECT slot FROM mainnet.fct_block_head LIMIT 10"},
+				{Name: "Peer count", Query: `prometheus.query("prod", "sum(libp2p_peers) by (instance)")`},
+			},
+		},
+	}
+
+	targets := []types.CoverageTarget{
+		{Kind: types.CoverageKindClickHouseTable, Name: "fct_block_head", Datasource: "xatu-cbt"},
+		{Kind: types.CoverageKindClickHouseTable, Name: "fct_block", Datasource: "xatu-cbt"},
+		{Kind: types.CoverageKindPrometheusMetric, Name: "libp2p_peers", Datasource: "prod"},
+		{Kind: types.CoverageKindPrometheusMetric, Name: "up", Datasource: "prod"},
+	}
+
+	report := BuildExamplesCoverage(examples, targets)
+
+	require.Len(t, report.Summary, 2)
+	assert.Equal(t, CoverageSummary{Kind: types.CoverageKindClickHouseTable, Total: 2, Covered: 1, Uncovered: 1, Percent: 50}, report.Summary[0])
+	assert.Equal(t, CoverageSummary{Kind: types.CoverageKindPrometheusMetric, Total: 2, Covered: 1, Uncovered: 1, Percent: 50}, report.Summary[1])
+
+	require.Len(t, report.Uncovered, 2)
+	assert.Equal(t, "fct_block", report.Uncovered[0].Name)
+	assert.Equal(t, "up", report.Uncovered[1].Name)
+
+	require.Len(t, report.Covered, 2)
+	assert.Equal(t, "fct_block_head", report.Covered[0].Name)
+	assert.Equal(t, []string{"blocks/Head blocks"}, report.Covered[0].Examples)
+	assert.Equal(t, []string{"blocks/Peer count"}, report.Covered[1].Examples)
+}
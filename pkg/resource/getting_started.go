@@ -43,7 +43,7 @@ engine used by MCP clients via ` + "`execute_python`" + `. It provides:
 - **Workspace persistence** between calls (files saved to ` + "`/workspace/`" + ` survive across executions)
 - **Multi-turn workflows** (query → save → load → plot across separate calls)
 - **Token efficiency** (one command handles any datasource type)
-- **Full ethpandaops library** (clickhouse, prometheus, loki, dora, ethnode, storage)
+- **Full ethpandaops library** (clickhouse, prometheus, loki, grafana, github, http_json, dora, ethnode, storage)
 
 While module-specific CLI commands exist (e.g. ` + "`panda clickhouse query`" + `), **prefer
 ` + "`panda execute`" + `** because it supports multi-step workflows with workspace persistence
@@ -97,7 +97,9 @@ plt.savefig("/workspace/chart.png")
 url = storage.upload("/workspace/chart.png")
 ` + "```" + `
 
-Use ` + "`storage.upload()`" + ` for permanent public URLs (see ` + "`python://ethpandaops`" + ` for API details).
+Charts and images saved to ` + "`/workspace/`" + ` are uploaded automatically and returned as ` + "`[artifact]`" + ` lines with public URLs; share those instead of re-uploading. Use ` + "`storage.upload()`" + ` for other files (see ` + "`python://ethpandaops/storage`" + ` for API details).
+
+Use ` + "`ethpandaops.result(obj)`" + ` to return a JSON value (dicts, lists, DataFrames, numbers) in the response's structured result instead of parsing stdout. Only the last call counts; the limit is 1 MiB.
 `
 
 // gettingStartedFooterCLI contains CLI-specific tips.
@@ -129,7 +131,9 @@ print(url)
 '
 ` + "```" + `
 
-Use ` + "`storage.upload()`" + ` for permanent public URLs (see ` + "`panda docs storage`" + ` for API details).
+Charts and images saved to ` + "`/workspace/`" + ` are uploaded automatically and printed as ` + "`[artifact]`" + ` lines on stderr. Use ` + "`storage.upload()`" + ` for other files (see ` + "`panda docs storage`" + ` for API details).
+
+Use ` + "`ethpandaops.result(obj)`" + ` to return a JSON value; it is printed as ` + "`[result]`" + ` on stderr and as the ` + "`result`" + ` field with ` + "`--json`" + `.
 `
 
 // RegisterGettingStartedResources registers the panda://getting-started
@@ -0,0 +1,111 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/history"
+)
+
+// executionURIPattern matches history://executions/{id} URIs.
+var executionURIPattern = regexp.MustCompile(`^history://executions/(.+)$`)
+
+// ExecutionHistory provides access to recorded sandbox executions.
+type ExecutionHistory interface {
+	ListExecutions(ctx context.Context, filter history.Filter) ([]history.Record, error)
+	GetExecution(ctx context.Context, executionID, ownerID string) (*history.Record, error)
+}
+
+// ExecutionsResponse is the response for history://executions.
+type ExecutionsResponse struct {
+	Executions []history.Record `json:"executions"`
+	Usage      string           `json:"usage"`
+}
+
+// RegisterHistoryResources registers the execution history resources with the registry.
+func RegisterHistoryResources(log logrus.FieldLogger, reg Registry, executions ExecutionHistory) {
+	log = log.WithField("resource", "history")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"history://executions",
+			"Execution History",
+			mcp.WithResourceDescription("Recent sandbox executions with session, code hash, duration and exit code"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Handler: createExecutionsHandler(executions),
+	})
+
+	reg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"history://executions/{id}",
+			"Execution Details",
+			mcp.WithTemplateDescription("A recorded execution including its full code, for auditing or re-running"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Pattern: executionURIPattern,
+		Handler: createExecutionDetailHandler(executions),
+	})
+
+	log.Debug("Registered history resources")
+}
+
+// createExecutionsHandler returns a handler for history://executions.
+func createExecutionsHandler(executions ExecutionHistory) ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		records, err := executions.ListExecutions(ctx, history.Filter{OwnerID: callerID(ctx)})
+		if err != nil {
+			return "", err
+		}
+
+		summaries := make([]history.Record, 0, len(records))
+		for _, record := range records {
+			summaries = append(summaries, record.Summary())
+		}
+
+		data, err := canonicaljson.MarshalIndent(ExecutionsResponse{
+			Executions: summaries,
+			Usage:      "Code is truncated; read history://executions/{execution_id} for the full snippet.",
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling executions: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// createExecutionDetailHandler returns a handler for history://executions/{id}.
+func createExecutionDetailHandler(executions ExecutionHistory) ReadHandler {
+	return func(ctx context.Context, uri string) (string, error) {
+		matches := executionURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid execution URI: %s", uri)
+		}
+
+		record, err := executions.GetExecution(ctx, matches[1], callerID(ctx))
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling execution: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// callerID returns the authenticated caller's owner ID, or "" when auth is disabled.
+func callerID(ctx context.Context) string {
+	return auth.OwnerID(ctx)
+}
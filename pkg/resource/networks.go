@@ -2,16 +2,21 @@ package resource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 // networkURIPattern matches networks://{name} URIs.
@@ -25,11 +30,15 @@ type NetworkSummary struct {
 	Status   string   `json:"status"`
 }
 
-// NetworksActiveResponse is the response for networks://active.
+// NetworksActiveResponse is the response for networks://active. The compact
+// view lists only network names.
 type NetworksActiveResponse struct {
-	Networks []NetworkSummary `json:"networks"`
-	Groups   []string         `json:"groups"`
-	Usage    string           `json:"usage"`
+	Networks   []NetworkSummary `json:"networks,omitempty"`
+	Names      []string         `json:"names,omitempty"`
+	Groups     []string         `json:"groups"`
+	Usage      string           `json:"usage"`
+	Total      int              `json:"total,omitempty"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 // NetworkWithClusters wraps a discovery.Network with xatu-specific cluster info.
@@ -38,15 +47,39 @@ type NetworkWithClusters struct {
 	Clusters []string `json:"clusters"`
 }
 
-// NetworksAllResponse is the response for networks://all.
+// NetworksAllResponse is the response for networks://all. The compact view
+// lists only network names.
 type NetworksAllResponse struct {
-	Networks map[string]NetworkWithClusters `json:"networks"`
-	Groups   []string                       `json:"groups"`
+	Networks   map[string]NetworkWithClusters `json:"networks,omitempty"`
+	Names      []string                       `json:"names,omitempty"`
+	Groups     []string                       `json:"groups"`
+	Total      int                            `json:"total,omitempty"`
+	NextCursor string                         `json:"next_cursor,omitempty"`
 }
 
 // NetworkDetailResponse is the response for networks://{name} (single network).
 type NetworkDetailResponse struct {
-	Network NetworkWithClusters `json:"network"`
+	Network   NetworkWithClusters     `json:"network"`
+	Lifecycle cartographoor.Lifecycle `json:"lifecycle"`
+}
+
+// NetworksLifecycleResponse is the response for networks://lifecycle.
+type NetworksLifecycleResponse struct {
+	Networks map[string]cartographoor.Lifecycle `json:"networks"`
+	// Upcoming lists future forks, blob capacity changes and shutdowns of
+	// active networks, soonest first.
+	Upcoming []LifecycleEvent `json:"upcoming"`
+	Usage    string           `json:"usage"`
+}
+
+// LifecycleEvent is a dated event in a network's lifecycle.
+type LifecycleEvent struct {
+	Network string `json:"network"`
+	// Event is fork, blob_schedule or shutdown.
+	Event string    `json:"event"`
+	Name  string    `json:"name,omitempty"`
+	Epoch *uint64   `json:"epoch,omitempty"`
+	Time  time.Time `json:"time"`
 }
 
 // GroupDetailResponse is the response for networks://{group} (devnet group).
@@ -69,6 +102,7 @@ func RegisterNetworksResources(log logrus.FieldLogger, reg Registry, client cart
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.7),
 		),
 		Handler: createActiveNetworksHandler(client),
+		Paged:   true,
 	})
 
 	// Register networks://all - all networks including inactive
@@ -81,6 +115,31 @@ func RegisterNetworksResources(log logrus.FieldLogger, reg Registry, client cart
 			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
 		),
 		Handler: createAllNetworksHandler(client),
+		Paged:   true,
+	})
+
+	// Register networks://meta - refresh status of the network data
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"networks://meta",
+			"Network Data Status",
+			mcp.WithResourceDescription("Where the network list came from, when it was last refreshed, whether it is stale, and which networks were added, overridden or hidden by config"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.3),
+		),
+		Handler: createNetworksMetaHandler(client),
+	})
+
+	// Register networks://lifecycle - genesis, forks and shutdowns
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"networks://lifecycle",
+			"Network Lifecycle",
+			mcp.WithResourceDescription("Genesis time, slot timing, fork epochs and times, blob schedule and planned shutdowns of active networks, with upcoming events. Use it for slot/timestamp conversion and fork-aware queries"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.6),
+		),
+		Handler: createNetworksLifecycleHandler(client),
 	})
 
 	// Register networks://{name} - single network or devnet group
@@ -96,33 +155,73 @@ func RegisterNetworksResources(log logrus.FieldLogger, reg Registry, client cart
 		Handler: createNetworkDetailHandler(log, client),
 	})
 
+	client.OnUpdate(func(changed []string) {
+		reg.NotifyUpdated(networkURIs(client, changed)...)
+	})
+
 	log.Debug("Registered networks resources")
 }
 
+// networkURIs returns the resources affected by changes to the named
+// networks: both listings, each network and each devnet group containing one.
+func networkURIs(client cartographoor.CartographoorClient, changed []string) []string {
+	uris := []string{"networks://active", "networks://all", "networks://meta", "networks://lifecycle"}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, name := range changed {
+		isChanged[name] = true
+		uris = append(uris, "networks://"+name)
+	}
+
+	for _, group := range client.GetGroups() {
+		members, _ := client.GetGroup(group)
+		for name := range members {
+			if isChanged[name] {
+				uris = append(uris, "networks://"+group)
+
+				break
+			}
+		}
+	}
+
+	return uris
+}
+
 // createActiveNetworksHandler returns a handler for networks://active.
 func createActiveNetworksHandler(client cartographoor.CartographoorClient) ReadHandler {
-	return func(_ context.Context, _ string) (string, error) {
+	return func(ctx context.Context, _ string) (string, error) {
 		networks := client.GetActiveNetworks()
-		groups := client.GetGroups()
+		params := types.GetListParams(ctx)
+		names := slices.Sorted(maps.Keys(networks))
+		start, end, next := params.Page(len(names))
 
-		summaries := make([]NetworkSummary, 0, len(networks))
+		response := NetworksActiveResponse{
+			Groups:     client.GetGroups(),
+			Usage:      "Use networks://{name} for full network details or networks://{group} for all networks in a devnet group",
+			NextCursor: next,
+		}
 
-		for _, network := range networks {
-			summaries = append(summaries, NetworkSummary{
-				Name:     network.Name,
-				ChainID:  network.ChainID,
-				Clusters: client.GetClusters(network),
-				Status:   network.Status,
-			})
+		if params.Paged() {
+			response.Total = len(names)
 		}
 
-		response := NetworksActiveResponse{
-			Networks: summaries,
-			Groups:   groups,
-			Usage:    "Use networks://{name} for full network details or networks://{group} for all networks in a devnet group",
+		if params.Compact() {
+			response.Names = names[start:end]
+		} else {
+			response.Networks = make([]NetworkSummary, 0, end-start)
+
+			for _, name := range names[start:end] {
+				network := networks[name]
+				response.Networks = append(response.Networks, NetworkSummary{
+					Name:     network.Name,
+					ChainID:  network.ChainID,
+					Clusters: client.GetClusters(network),
+					Status:   network.Status,
+				})
+			}
 		}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling response: %w", err)
 		}
@@ -133,25 +232,47 @@ func createActiveNetworksHandler(client cartographoor.CartographoorClient) ReadH
 
 // createAllNetworksHandler returns a handler for networks://all.
 func createAllNetworksHandler(client cartographoor.CartographoorClient) ReadHandler {
-	return func(_ context.Context, _ string) (string, error) {
+	return func(ctx context.Context, _ string) (string, error) {
 		networks := client.GetAllNetworks()
-		groups := client.GetGroups()
+		params := types.GetListParams(ctx)
+		names := slices.Sorted(maps.Keys(networks))
+		start, end, next := params.Page(len(names))
 
-		networksWithClusters := make(map[string]NetworkWithClusters, len(networks))
+		response := NetworksAllResponse{
+			Groups:     client.GetGroups(),
+			NextCursor: next,
+		}
 
-		for name, network := range networks {
-			networksWithClusters[name] = NetworkWithClusters{
-				Network:  network,
-				Clusters: client.GetClusters(network),
+		if params.Paged() {
+			response.Total = len(names)
+		}
+
+		if params.Compact() {
+			response.Names = names[start:end]
+		} else {
+			response.Networks = make(map[string]NetworkWithClusters, end-start)
+
+			for _, name := range names[start:end] {
+				response.Networks[name] = NetworkWithClusters{
+					Network:  networks[name],
+					Clusters: client.GetClusters(networks[name]),
+				}
 			}
 		}
 
-		response := NetworksAllResponse{
-			Networks: networksWithClusters,
-			Groups:   groups,
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling response: %w", err)
 		}
 
-		data, err := json.MarshalIndent(response, "", "  ")
+		return string(data), nil
+	}
+}
+
+// createNetworksMetaHandler returns a handler for networks://meta.
+func createNetworksMetaHandler(client cartographoor.CartographoorClient) ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		data, err := canonicaljson.MarshalIndent(client.Status(), "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("marshaling response: %w", err)
 		}
@@ -160,6 +281,75 @@ func createAllNetworksHandler(client cartographoor.CartographoorClient) ReadHand
 	}
 }
 
+// createNetworksLifecycleHandler returns a handler for networks://lifecycle.
+func createNetworksLifecycleHandler(client cartographoor.CartographoorClient) ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		networks := client.GetActiveNetworks()
+
+		response := NetworksLifecycleResponse{
+			Networks: make(map[string]cartographoor.Lifecycle, len(networks)),
+			Usage: "slot = (unix_time - genesis_time) / seconds_per_slot; epoch = slot / slots_per_epoch. " +
+				"Use networks://{name} for the full network details",
+		}
+
+		for name, network := range networks {
+			response.Networks[name] = client.GetLifecycle(network)
+		}
+
+		response.Upcoming = upcomingEvents(response.Networks, time.Now())
+
+		data, err := canonicaljson.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling response: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// upcomingEvents returns the dated lifecycle events after now, soonest first.
+func upcomingEvents(lifecycles map[string]cartographoor.Lifecycle, now time.Time) []LifecycleEvent {
+	events := make([]LifecycleEvent, 0, 8)
+
+	for name, lc := range lifecycles {
+		for _, fork := range lc.Forks {
+			if fork.Time != nil && fork.Time.After(now) {
+				epoch := fork.Epoch
+				events = append(events, LifecycleEvent{
+					Network: name, Event: "fork", Name: fork.Name, Epoch: &epoch, Time: *fork.Time,
+				})
+			}
+		}
+
+		for _, entry := range lc.BlobSchedule {
+			if entry.Time != nil && entry.Time.After(now) {
+				epoch := entry.Epoch
+				events = append(events, LifecycleEvent{
+					Network: name, Event: "blob_schedule", Epoch: &epoch, Time: *entry.Time,
+				})
+			}
+		}
+
+		if lc.Shutdown != nil && lc.Shutdown.After(now) {
+			events = append(events, LifecycleEvent{Network: name, Event: "shutdown", Time: *lc.Shutdown})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+
+		if events[i].Network != events[j].Network {
+			return events[i].Network < events[j].Network
+		}
+
+		return events[i].Name < events[j].Name
+	})
+
+	return events
+}
+
 // createNetworkDetailHandler returns a handler for networks://{name}.
 func createNetworkDetailHandler(log logrus.FieldLogger, client cartographoor.CartographoorClient) ReadHandler {
 	return func(_ context.Context, uri string) (string, error) {
@@ -177,9 +367,10 @@ func createNetworkDetailHandler(log logrus.FieldLogger, client cartographoor.Car
 					Network:  network,
 					Clusters: client.GetClusters(network),
 				},
+				Lifecycle: client.GetLifecycle(network),
 			}
 
-			data, err := json.MarshalIndent(response, "", "  ")
+			data, err := canonicaljson.MarshalIndent(response, "", "  ")
 			if err != nil {
 				return "", fmt.Errorf("marshaling response: %w", err)
 			}
@@ -203,7 +394,7 @@ func createNetworkDetailHandler(log logrus.FieldLogger, client cartographoor.Car
 				Networks: networksWithClusters,
 			}
 
-			data, err := json.MarshalIndent(response, "", "  ")
+			data, err := canonicaljson.MarshalIndent(response, "", "  ")
 			if err != nil {
 				return "", fmt.Errorf("marshaling response: %w", err)
 			}
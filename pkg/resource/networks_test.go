@@ -0,0 +1,46 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/cartographoor"
+)
+
+func TestUpcomingEvents(t *testing.T) {
+	genesis := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := genesis.Add(10 * 32 * 12 * time.Second)
+
+	network := discovery.Network{
+		Name:          "devnet-1",
+		GenesisConfig: &discovery.GenesisConfig{GenesisTime: uint64(genesis.Unix())},
+		Forks: &discovery.ForksConfig{Consensus: map[string]discovery.ForkConfig{
+			"electra": {Epoch: 0},
+			"fulu":    {Epoch: 20},
+			"gloas":   {Epoch: 18446744073709551615},
+		}},
+	}
+
+	shutdown := genesis.Add(30 * 24 * time.Hour)
+	lc := cartographoor.NetworkLifecycle(network, cartographoor.NetworkOverride{Shutdown: shutdown}, now)
+
+	require.NotNil(t, lc.CurrentEpoch)
+	assert.Equal(t, uint64(10), *lc.CurrentEpoch)
+	assert.Equal(t, "electra", lc.CurrentFork)
+	require.Len(t, lc.Forks, 3)
+	assert.False(t, lc.Forks[2].Scheduled)
+	assert.Nil(t, lc.Forks[2].Time)
+
+	events := upcomingEvents(map[string]cartographoor.Lifecycle{"devnet-1": lc}, now)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "fork", events[0].Event)
+	assert.Equal(t, "fulu", events[0].Name)
+	assert.Equal(t, genesis.Add(20*32*12*time.Second), events[0].Time)
+	assert.Equal(t, "shutdown", events[1].Event)
+	assert.Equal(t, shutdown, events[1].Time)
+}
@@ -0,0 +1,79 @@
+package resource
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// maxListLimit caps the page size of a list resource read.
+	maxListLimit = 1000
+
+	// listQueryTemplate is the RFC 6570 query expansion appended to paged
+	// resource URIs, so MCP clients can read them with list parameters.
+	listQueryTemplate = "{?cursor,limit,view}"
+)
+
+// parseListQuery parses the list parameters in the query of a paged
+// resource URI.
+func parseListQuery(rawQuery string) (types.ListParams, error) {
+	params := types.ListParams{View: types.ListViewDetailed}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return params, fmt.Errorf("invalid resource URI query: %w", err)
+	}
+
+	for key, values := range query {
+		value := values[len(values)-1]
+
+		switch key {
+		case "cursor":
+			if value == "" {
+				continue
+			}
+
+			if params.Offset, err = types.DecodeListCursor(value); err != nil {
+				return params, err
+			}
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 1 || limit > maxListLimit {
+				return params, fmt.Errorf("limit must be between 1 and %d, got %q", maxListLimit, value)
+			}
+
+			params.Limit = limit
+		case "view":
+			switch types.ListView(value) {
+			case types.ListViewDetailed, types.ListViewCompact:
+				params.View = types.ListView(value)
+			default:
+				return params, fmt.Errorf("view must be %q or %q, got %q",
+					types.ListViewCompact, types.ListViewDetailed, value)
+			}
+		default:
+			return params, fmt.Errorf("unknown resource URI parameter %q", key)
+		}
+	}
+
+	return params, nil
+}
+
+// listTemplate returns the template under which MCP clients read a paged
+// resource with list parameters.
+func listTemplate(res mcp.Resource) mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		res.URI+listQueryTemplate,
+		res.Name+" (paged)",
+		mcp.WithTemplateDescription(fmt.Sprintf(
+			"%s. Page with cursor (next_cursor of the previous page) and limit (1-%d); view=compact lists names only.",
+			strings.TrimSuffix(res.Description, "."), maxListLimit)),
+		mcp.WithTemplateMIMEType(res.MIMEType),
+	)
+}
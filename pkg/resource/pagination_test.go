@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestParseListQuery(t *testing.T) {
+	params, err := parseListQuery("limit=10&view=compact&cursor=" + types.EncodeListCursor(20))
+	require.NoError(t, err)
+	assert.Equal(t, types.ListParams{Offset: 20, Limit: 10, View: types.ListViewCompact}, params)
+
+	for _, query := range []string{"limit=0", "limit=abc", "view=full", "cursor=!!", "page=2"} {
+		_, err := parseListQuery(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestListParamsPage(t *testing.T) {
+	params := types.ListParams{Limit: 2}
+
+	start, end, next := params.Page(5)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 2, end)
+	require.NotEmpty(t, next)
+
+	params.Offset, _ = types.DecodeListCursor(next)
+	start, end, next = params.Page(5)
+	assert.Equal(t, []int{2, 4}, []int{start, end})
+
+	params.Offset, _ = types.DecodeListCursor(next)
+	start, end, next = params.Page(5)
+	assert.Equal(t, []int{4, 5}, []int{start, end})
+	assert.Empty(t, next)
+}
+
+func TestRegistryReadListParams(t *testing.T) {
+	reg := NewRegistry(logrus.New())
+	handler := func(ctx context.Context, uri string) (string, error) {
+		params := types.GetListParams(ctx)
+		data, err := json.Marshal(map[string]any{"uri": uri, "limit": params.Limit, "view": params.View})
+
+		return string(data), err
+	}
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource("demo://items", "Items", mcp.WithResourceDescription("All items.")),
+		Handler:  handler,
+		Paged:    true,
+	})
+	reg.RegisterStatic(StaticResource{Resource: mcp.NewResource("demo://info", "Info"), Handler: handler})
+
+	content, _, err := reg.Read(context.Background(), "demo://items?limit=5&view=compact")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"uri":"demo://items","limit":5,"view":"compact"}`, content)
+
+	content, _, err = reg.Read(context.Background(), "demo://items")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"uri":"demo://items","limit":0,"view":"detailed"}`, content)
+
+	_, _, err = reg.Read(context.Background(), "demo://info?limit=5")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support list parameters")
+
+	templates := reg.ListTemplates()
+	require.Len(t, templates, 1)
+	assert.Equal(t, "demo://items{?cursor,limit,view}", templates[0].URITemplate.Raw())
+}
+
+func TestPageExamples(t *testing.T) {
+	examples := map[string]types.ExampleCategory{
+		"b": {Name: "B", Examples: []types.Example{{Name: "b1", Query: "SELECT 1"}}},
+		"a": {Name: "A", Examples: []types.Example{{Name: "a1"}, {Name: "a2"}}},
+	}
+
+	page := pageExamples(examples, types.ListParams{Limit: 2, View: types.ListViewCompact})
+	assert.Equal(t, 3, page.Total)
+	require.NotEmpty(t, page.NextCursor)
+	require.Len(t, page.Categories, 1)
+	assert.Equal(t, []string{"a1", "a2"}, page.Categories["a"].ExampleNames)
+	assert.Empty(t, page.Categories["a"].Examples)
+
+	offset, err := types.DecodeListCursor(page.NextCursor)
+	require.NoError(t, err)
+
+	page = pageExamples(examples, types.ListParams{Offset: offset, Limit: 2, View: types.ListViewDetailed})
+	assert.Empty(t, page.NextCursor)
+	require.Len(t, page.Categories, 1)
+	assert.Equal(t, "SELECT 1", page.Categories["b"].Examples[0].Query)
+}
@@ -0,0 +1,62 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/proxy"
+)
+
+// QuotaReader reads datasource quota usage from the proxy.
+type QuotaReader interface {
+	Usage(ctx context.Context) (*proxy.QuotaUsageResponse, error)
+}
+
+// QuotaUsageResponse is the response for quota://usage.
+type QuotaUsageResponse struct {
+	Datasources []proxy.QuotaUsage `json:"datasources"`
+	Usage       string             `json:"usage"`
+}
+
+// RegisterQuotaResources registers the quota://usage resource.
+func RegisterQuotaResources(log logrus.FieldLogger, reg Registry, quotas QuotaReader) {
+	log = log.WithField("resource", "quota")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"quota://usage",
+			"Datasource Quota Usage",
+			mcp.WithResourceDescription("Remaining hourly query and daily scan budgets for datasources with quotas"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.5),
+		),
+		Handler: createQuotaUsageHandler(quotas),
+	})
+
+	log.Debug("Registered quota resources")
+}
+
+// createQuotaUsageHandler returns a handler for quota://usage.
+func createQuotaUsageHandler(quotas QuotaReader) ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		usage, err := quotas.Usage(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(QuotaUsageResponse{
+			Datasources: usage.Datasources,
+			Usage: "Datasources not listed are unlimited. Queries over the hourly quota get HTTP 429 and " +
+				"queries after the daily scan budget is spent get HTTP 413 until the reset time.",
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling quota usage: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
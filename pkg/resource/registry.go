@@ -4,11 +4,13 @@ package resource
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -36,7 +38,28 @@ type Registry interface {
 	ListTemplates() []mcp.ResourceTemplate
 
 	// Read reads a resource by URI and returns its content, mime type, and any error.
+	// Paged resources accept list parameters in the URI query.
 	Read(ctx context.Context, uri string) (content string, mimeType string, err error)
+
+	// ForModule returns a registry that attributes every resource
+	// registered through it to the named module.
+	ForModule(name string) module.ResourceRegistry
+
+	// SetModuleFilter hides the resources of modules for which active
+	// returns false from listings and reads.
+	SetModuleFilter(active func(name string) bool)
+
+	// ModuleStatic returns the static resources registered by a module,
+	// regardless of the module filter.
+	ModuleStatic(name string) []mcp.Resource
+
+	// NotifyUpdated reports that the content of the given resource URIs
+	// changed. It is a no-op until an update handler is set.
+	NotifyUpdated(uris ...string)
+
+	// SetUpdateHandler sets the function that receives NotifyUpdated calls,
+	// such as the server's notification batcher.
+	SetUpdateHandler(fn func(uris []string))
 }
 
 // registry is the default implementation of Registry.
@@ -45,6 +68,8 @@ type registry struct {
 	mu        sync.RWMutex
 	static    []StaticResource
 	templates []TemplateResource
+	active    func(name string) bool
+	onUpdate  func(uris []string)
 }
 
 // NewRegistry creates a new resource registry.
@@ -85,9 +110,11 @@ func (r *registry) ListStatic() []mcp.Resource {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	resources := make([]mcp.Resource, len(r.static))
-	for i, s := range r.static {
-		resources[i] = s.Resource
+	resources := make([]mcp.Resource, 0, len(r.static))
+	for _, s := range r.static {
+		if r.visible(s.Module) {
+			resources = append(resources, s.Resource)
+		}
 	}
 
 	return resources
@@ -98,25 +125,53 @@ func (r *registry) ListTemplates() []mcp.ResourceTemplate {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	templates := make([]mcp.ResourceTemplate, len(r.templates))
-	for i, t := range r.templates {
-		templates[i] = t.Template
+	templates := make([]mcp.ResourceTemplate, 0, len(r.templates))
+	for _, t := range r.templates {
+		if r.visible(t.Module) {
+			templates = append(templates, t.Template)
+		}
+	}
+
+	for _, s := range r.static {
+		if s.Paged && r.visible(s.Module) {
+			templates = append(templates, listTemplate(s.Resource))
+		}
 	}
 
 	return templates
 }
 
-// Read reads a resource by URI and returns its content and mime type.
+// Read reads a resource by URI and returns its content and mime type. The
+// query of a paged resource URI is passed to its handler as ListParams.
 func (r *registry) Read(ctx context.Context, uri string) (string, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	r.log.WithField("uri", uri).Debug("Reading resource")
 
+	base, rawQuery, hasQuery := strings.Cut(uri, "?")
+
 	// Check static resources first
 	for _, s := range r.static {
-		if s.Resource.URI == uri {
-			content, err := s.Handler(ctx, uri)
+		if s.Resource.URI == base {
+			if !r.visible(s.Module) {
+				return "", "", fmt.Errorf("module %q is disabled", s.Module)
+			}
+
+			params := types.ListParams{View: types.ListViewDetailed}
+
+			if hasQuery {
+				if !s.Paged {
+					return "", "", fmt.Errorf("resource %s does not support list parameters", base)
+				}
+
+				var err error
+				if params, err = parseListQuery(rawQuery); err != nil {
+					return "", "", err
+				}
+			}
+
+			content, err := s.Handler(types.WithListParams(ctx, params), base)
 			if err != nil {
 				return "", "", fmt.Errorf("reading static resource %s: %w", uri, err)
 			}
@@ -128,6 +183,10 @@ func (r *registry) Read(ctx context.Context, uri string) (string, string, error)
 	// Check template resources
 	for _, t := range r.templates {
 		if t.Pattern.MatchString(uri) {
+			if !r.visible(t.Module) {
+				return "", "", fmt.Errorf("module %q is disabled", t.Module)
+			}
+
 			content, err := t.Handler(ctx, uri)
 			if err != nil {
 				return "", "", fmt.Errorf("reading template resource %s: %w", uri, err)
@@ -140,5 +199,86 @@ func (r *registry) Read(ctx context.Context, uri string) (string, string, error)
 	return "", "", fmt.Errorf("unknown resource URI: %s", uri)
 }
 
-// Compile-time check that registry implements Registry.
-var _ Registry = (*registry)(nil)
+// ForModule returns a registry that attributes every resource registered
+// through it to the named module.
+func (r *registry) ForModule(name string) module.ResourceRegistry {
+	return &moduleRegistry{registry: r, module: name}
+}
+
+// SetModuleFilter hides the resources of modules for which active returns false.
+func (r *registry) SetModuleFilter(active func(name string) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active = active
+}
+
+// ModuleStatic returns the static resources registered by a module.
+func (r *registry) ModuleStatic(name string) []mcp.Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var resources []mcp.Resource
+	for _, s := range r.static {
+		if s.Module == name {
+			resources = append(resources, s.Resource)
+		}
+	}
+
+	return resources
+}
+
+// NotifyUpdated forwards changed resource URIs to the update handler.
+func (r *registry) NotifyUpdated(uris ...string) {
+	if len(uris) == 0 {
+		return
+	}
+
+	r.mu.RLock()
+	onUpdate := r.onUpdate
+	r.mu.RUnlock()
+
+	if onUpdate != nil {
+		onUpdate(uris)
+	}
+}
+
+// SetUpdateHandler sets the function that receives NotifyUpdated calls.
+func (r *registry) SetUpdateHandler(fn func(uris []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onUpdate = fn
+}
+
+// visible reports whether resources owned by the module should be served.
+// Callers must hold r.mu.
+func (r *registry) visible(name string) bool {
+	return name == "" || r.active == nil || r.active(name)
+}
+
+// moduleRegistry stamps the owning module onto registered resources.
+type moduleRegistry struct {
+	registry *registry
+	module   string
+}
+
+func (m *moduleRegistry) RegisterStatic(res StaticResource) {
+	res.Module = m.module
+	m.registry.RegisterStatic(res)
+}
+
+func (m *moduleRegistry) RegisterTemplate(res TemplateResource) {
+	res.Module = m.module
+	m.registry.RegisterTemplate(res)
+}
+
+func (m *moduleRegistry) NotifyUpdated(uris ...string) {
+	m.registry.NotifyUpdated(uris...)
+}
+
+// Compile-time checks.
+var (
+	_ Registry                = (*registry)(nil)
+	_ module.ResourceNotifier = (*moduleRegistry)(nil)
+)
@@ -0,0 +1,51 @@
+package resource
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryModuleFilter(t *testing.T) {
+	reg := NewRegistry(logrus.New())
+	handler := func(_ context.Context, uri string) (string, error) { return uri, nil }
+
+	reg.RegisterStatic(StaticResource{Resource: mcp.NewResource("core://a", "A"), Handler: handler})
+
+	modReg := reg.ForModule("demo")
+	modReg.RegisterStatic(StaticResource{Resource: mcp.NewResource("demo://b", "B"), Handler: handler})
+	modReg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate("demo://items/{id}", "Item"),
+		Pattern:  regexp.MustCompile(`^demo://items/.+$`),
+		Handler:  handler,
+	})
+
+	disabled := false
+	reg.SetModuleFilter(func(name string) bool { return !(name == "demo" && disabled) })
+
+	assert.Len(t, reg.ListStatic(), 2)
+	assert.Len(t, reg.ListTemplates(), 1)
+
+	disabled = true
+
+	static := reg.ListStatic()
+	require.Len(t, static, 1)
+	assert.Equal(t, "core://a", static[0].URI)
+	assert.Empty(t, reg.ListTemplates())
+
+	_, _, err := reg.Read(context.Background(), "demo://items/1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `module "demo" is disabled`)
+
+	_, _, err = reg.Read(context.Background(), "core://a")
+	require.NoError(t, err)
+
+	modStatic := reg.ModuleStatic("demo")
+	require.Len(t, modStatic, 1)
+	assert.Equal(t, "demo://b", modStatic[0].URI)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -25,8 +26,10 @@ type indexedRunbook struct {
 
 // RunbookIndex provides semantic search over runbooks.
 type RunbookIndex struct {
+	log      logrus.FieldLogger
 	embedder embedding.Embedder
 	runbooks []indexedRunbook
+	mu       sync.RWMutex
 }
 
 // NewRunbookIndex creates and populates a semantic search index from runbooks
@@ -56,6 +59,7 @@ func NewRunbookIndex(
 	log.WithField("runbook_count", len(indexed)).Info("Runbook index built")
 
 	return &RunbookIndex{
+		log:      log,
 		embedder: embedder,
 		runbooks: indexed,
 	}, nil
@@ -68,6 +72,9 @@ func (idx *RunbookIndex) Search(query string, limit int) ([]RunbookSearchResult,
 		return nil, fmt.Errorf("embedding query: %w", err)
 	}
 
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
 	type scored struct {
 		index int
 		score float64
@@ -98,6 +105,53 @@ func (idx *RunbookIndex) Search(query string, limit int) ([]RunbookSearchResult,
 	return results, nil
 }
 
+// Replace swaps the indexed runbooks for runbooks. Only runbooks whose
+// search text changed are embedded again.
+func (idx *RunbookIndex) Replace(runbooks []types.Runbook) error {
+	idx.mu.RLock()
+	known := make(map[string][]float32, len(idx.runbooks))
+	for _, rb := range idx.runbooks {
+		known[buildRunbookSearchText(rb.Runbook)] = rb.Vector
+	}
+	idx.mu.RUnlock()
+
+	indexed := make([]indexedRunbook, len(runbooks))
+	texts := make([]string, 0, len(runbooks))
+	missing := make([]int, 0, len(runbooks))
+
+	for i, rb := range runbooks {
+		text := buildRunbookSearchText(rb)
+		indexed[i] = indexedRunbook{Runbook: rb, Vector: known[text]}
+
+		if indexed[i].Vector == nil {
+			texts = append(texts, text)
+			missing = append(missing, i)
+		}
+	}
+
+	if len(texts) > 0 {
+		vectors, err := idx.embedder.EmbedBatch(texts)
+		if err != nil {
+			return fmt.Errorf("batch embedding runbooks: %w", err)
+		}
+
+		for j, i := range missing {
+			indexed[i].Vector = vectors[j]
+		}
+	}
+
+	idx.mu.Lock()
+	idx.runbooks = indexed
+	idx.mu.Unlock()
+
+	idx.log.WithFields(logrus.Fields{
+		"runbook_count": len(indexed),
+		"embedded":      len(texts),
+	}).Info("Runbook index updated")
+
+	return nil
+}
+
 // buildRunbookSearchText creates the text to embed for semantic search.
 // Indexes name, description, tags, and overview (first paragraph before code).
 func buildRunbookSearchText(rb types.Runbook) string {
@@ -0,0 +1,50 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// countingEmbedder counts how many texts were batch embedded.
+type countingEmbedder struct {
+	stubEmbedder
+	embedded int
+}
+
+func (c *countingEmbedder) EmbedBatch(texts []string) ([][]float32, error) {
+	c.embedded += len(texts)
+
+	return c.stubEmbedder.EmbedBatch(texts)
+}
+
+func TestRunbookIndex_Replace(t *testing.T) {
+	embedder := &countingEmbedder{stubEmbedder: stubEmbedder{dim: 8}}
+	builtin := types.Runbook{Name: "Builtin", Description: "Built in", Source: "builtin"}
+
+	idx, err := NewRunbookIndex(logrus.New(), embedder, []types.Runbook{builtin})
+	require.NoError(t, err)
+	require.Equal(t, 1, embedder.embedded)
+
+	team := types.Runbook{Name: "Team", Description: "Private team runbook", Source: "team"}
+	require.NoError(t, idx.Replace([]types.Runbook{builtin, team}))
+
+	// Only the new runbook is embedded.
+	assert.Equal(t, 2, embedder.embedded)
+
+	results, err := idx.Search(buildRunbookSearchText(team), 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "team", results[0].Runbook.Source)
+
+	require.NoError(t, idx.Replace([]types.Runbook{builtin}))
+
+	results, err = idx.Search("anything", 5)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Builtin", results[0].Runbook.Name)
+}
@@ -0,0 +1,101 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/schedule"
+)
+
+// scheduleRunsURIPattern matches schedule://runs/{job} URIs.
+var scheduleRunsURIPattern = regexp.MustCompile(`^schedule://runs/(.+)$`)
+
+// ScheduledJobs provides the scheduler's jobs and their recent runs.
+type ScheduledJobs interface {
+	Jobs() []schedule.JobStatus
+	Runs(name string) ([]schedule.Run, error)
+}
+
+// ScheduleJobsResponse is the response for schedule://jobs.
+type ScheduleJobsResponse struct {
+	Jobs  []schedule.JobStatus `json:"jobs"`
+	Usage string               `json:"usage"`
+}
+
+// ScheduleRunsResponse is the response for schedule://runs/{job}.
+type ScheduleRunsResponse struct {
+	Job  string         `json:"job"`
+	Runs []schedule.Run `json:"runs"`
+}
+
+// RegisterScheduleResources registers the scheduled execution resources with the registry.
+func RegisterScheduleResources(log logrus.FieldLogger, reg Registry, jobs ScheduledJobs) {
+	log = log.WithField("resource", "schedule")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"schedule://jobs",
+			"Scheduled Jobs",
+			mcp.WithResourceDescription("Configured scheduled checks with their cron, next run and latest result"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Handler: createScheduleJobsHandler(jobs),
+	})
+
+	reg.RegisterTemplate(TemplateResource{
+		Template: mcp.NewResourceTemplate(
+			"schedule://runs/{job}",
+			"Scheduled Runs",
+			mcp.WithTemplateDescription("Recent runs of a scheduled job, newest first, with output and a link to the stored result"),
+			mcp.WithTemplateMIMEType("application/json"),
+			mcp.WithTemplateAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Pattern: scheduleRunsURIPattern,
+		Handler: createScheduleRunsHandler(jobs),
+	})
+
+	log.Debug("Registered schedule resources")
+}
+
+// createScheduleJobsHandler returns a handler for schedule://jobs.
+func createScheduleJobsHandler(jobs ScheduledJobs) ReadHandler {
+	return func(_ context.Context, _ string) (string, error) {
+		data, err := canonicaljson.MarshalIndent(ScheduleJobsResponse{
+			Jobs:  jobs.Jobs(),
+			Usage: "Read schedule://runs/{name} for a job's recent runs and their output.",
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling scheduled jobs: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// createScheduleRunsHandler returns a handler for schedule://runs/{job}.
+func createScheduleRunsHandler(jobs ScheduledJobs) ReadHandler {
+	return func(_ context.Context, uri string) (string, error) {
+		matches := scheduleRunsURIPattern.FindStringSubmatch(uri)
+		if len(matches) != 2 {
+			return "", fmt.Errorf("invalid schedule runs URI: %s", uri)
+		}
+
+		runs, err := jobs.Runs(matches[1])
+		if err != nil {
+			return "", err
+		}
+
+		data, err := canonicaljson.MarshalIndent(ScheduleRunsResponse{Job: matches[1], Runs: runs}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling scheduled runs: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
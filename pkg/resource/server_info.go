@@ -0,0 +1,121 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/internal/version"
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+)
+
+// ServerInfoResponse is the response for server://info.
+type ServerInfoResponse struct {
+	Version string      `json:"version"`
+	Sandbox SandboxInfo `json:"sandbox"`
+}
+
+// SandboxInfo describes the sandbox backend and the execution profiles
+// available to the caller.
+type SandboxInfo struct {
+	Backend         string             `json:"backend"`
+	SessionsEnabled bool               `json:"sessions_enabled"`
+	Default         ProfileInfo        `json:"default"`
+	Profiles        []ProfileInfo      `json:"profiles,omitempty"`
+	GPU             *sandbox.GPUStatus `json:"gpu,omitempty"`
+}
+
+// ProfileInfo summarizes an execution profile.
+type ProfileInfo struct {
+	Name        string  `json:"name,omitempty"`
+	MemoryLimit string  `json:"memory_limit"`
+	CPULimit    float64 `json:"cpu_limit"`
+	Timeout     int     `json:"timeout"`
+	GPUs        int     `json:"gpus,omitempty"`
+	Restricted  bool    `json:"restricted,omitempty"`
+	Allowed     bool    `json:"allowed"`
+}
+
+// RegisterServerInfoResources registers the server://info resource.
+func RegisterServerInfoResources(log logrus.FieldLogger, reg Registry, cfg *config.Config, sandboxSvc sandbox.Service) {
+	log = log.WithField("resource", "server_info")
+
+	reg.RegisterStatic(StaticResource{
+		Resource: mcp.NewResource(
+			"server://info",
+			"Server Info",
+			mcp.WithResourceDescription("Server version, sandbox backend, execution profiles the caller may use and GPU availability"),
+			mcp.WithMIMEType("application/json"),
+			mcp.WithAnnotations([]mcp.Role{mcp.RoleAssistant}, 0.4),
+		),
+		Handler: createServerInfoHandler(cfg, sandboxSvc),
+	})
+
+	log.Debug("Registered server info resource")
+}
+
+// createServerInfoHandler returns a handler for server://info.
+func createServerInfoHandler(cfg *config.Config, sandboxSvc sandbox.Service) ReadHandler {
+	return func(ctx context.Context, _ string) (string, error) {
+		groups := auth.GetAuthGroups(ctx)
+
+		defaults, err := cfg.Sandbox.Profile("")
+		if err != nil {
+			return "", err
+		}
+
+		info := SandboxInfo{
+			Backend:         sandboxSvc.Name(),
+			SessionsEnabled: sandboxSvc.SessionsEnabled(),
+			Default:         profileInfo("", defaults, groups),
+			Profiles:        make([]ProfileInfo, 0, len(cfg.Sandbox.Profiles)),
+		}
+
+		for name := range cfg.Sandbox.Profiles {
+			profile, err := cfg.Sandbox.Profile(name)
+			if err != nil {
+				return "", err
+			}
+
+			info.Profiles = append(info.Profiles, profileInfo(name, profile, groups))
+		}
+
+		sort.Slice(info.Profiles, func(i, j int) bool {
+			return info.Profiles[i].Name < info.Profiles[j].Name
+		})
+
+		if reporter, ok := sandboxSvc.(sandbox.GPUReporter); ok {
+			if status := reporter.GPUStatus(); len(status.Profiles) > 0 {
+				info.GPU = &status
+			}
+		}
+
+		data, err := canonicaljson.MarshalIndent(ServerInfoResponse{
+			Version: version.Version,
+			Sandbox: info,
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling server info: %w", err)
+		}
+
+		return string(data), nil
+	}
+}
+
+func profileInfo(name string, profile config.ExecutionProfile, groups []string) ProfileInfo {
+	return ProfileInfo{
+		Name:        name,
+		MemoryLimit: profile.MemoryLimit,
+		CPULimit:    profile.CPULimit,
+		Timeout:     profile.Timeout,
+		GPUs:        profile.GPUs,
+		Restricted:  len(profile.AllowedGroups) > 0,
+		Allowed:     profile.Allows(groups),
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -16,7 +16,6 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
@@ -24,6 +23,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/wheelcache"
 )
 
 // Container label keys for identifying and managing ethpandaops-panda containers.
@@ -38,6 +39,8 @@ const (
 	LabelOwnerID = "io.ethpandaops-panda.owner-id"
 	// LabelInstance identifies which server instance created this container.
 	LabelInstance = "io.ethpandaops-panda.instance"
+	// LabelProfile stores the execution profile a session container was created with.
+	LabelProfile = "io.ethpandaops-panda.profile"
 )
 
 // parseContainerCreatedAt extracts the creation time from container labels.
@@ -69,8 +72,28 @@ type DockerBackend struct {
 	sessionManager *SessionManager
 
 	// securityConfigFunc returns the security configuration.
-	// This allows gVisor backend to override with gVisor-specific config.
+	// This allows the gVisor and Firecracker backends to override the runtime.
 	securityConfigFunc SecurityConfigFunc
+
+	// gpuSlots bounds concurrent executions of GPU profiles.
+	gpuSlots chan struct{}
+
+	// packageCache is the shared package cache, nil when disabled.
+	packageCache *wheelcache.Cache
+
+	// stopPrewarm cancels a package cache prewarm still running at Stop.
+	stopPrewarm context.CancelFunc
+
+	// imagePulledAt records when each sandbox image was last pulled.
+	imagePulledAt map[string]time.Time
+	imagesMu      sync.Mutex
+
+	// repullDone stops the floating image re-pull loop.
+	repullDone chan struct{}
+	repullWG   sync.WaitGroup
+
+	// pool is the warm container pool, nil when disabled.
+	pool *containerPool
 }
 
 // NewDockerBackend creates a new Docker sandbox backend.
@@ -80,6 +103,12 @@ func NewDockerBackend(cfg config.SandboxConfig, log logrus.FieldLogger) (*Docker
 		log:                log.WithField("component", "sandbox.docker"),
 		activeContainers:   make(map[string]string, 16),
 		securityConfigFunc: DefaultSecurityConfig,
+		gpuSlots:           make(chan struct{}, max(cfg.GPU.MaxConcurrent, 1)),
+		imagePulledAt:      make(map[string]time.Time, 2),
+	}
+
+	if cfg.PackageCache.Enabled {
+		backend.packageCache = wheelcache.New(cfg.PackageCache.Dir, int64(cfg.PackageCache.MaxSizeMB)*1024*1024)
 	}
 
 	// Create session manager with callbacks for container queries and cleanup.
@@ -120,6 +149,19 @@ func (b *DockerBackend) Start(ctx context.Context) error {
 
 	b.client = dockerClient
 
+	if err := b.startServices(ctx); err != nil {
+		return err
+	}
+
+	b.log.WithField("image", b.cfg.Image).Info("Docker sandbox backend started")
+
+	return nil
+}
+
+// startServices runs the start-up shared by the Docker, gVisor and
+// Firecracker backends once the Docker client is connected and any runtime
+// they need is verified.
+func (b *DockerBackend) startServices(ctx context.Context) error {
 	// Clean up expired orphaned containers from previous runs.
 	// Only removes containers older than max session duration to avoid
 	// disrupting active sessions from other server instances.
@@ -132,17 +174,35 @@ func (b *DockerBackend) Start(ctx context.Context) error {
 		return fmt.Errorf("ensuring sandbox image: %w", err)
 	}
 
+	// Create and verify the shared package cache if enabled.
+	if err := b.initPackageCache(); err != nil {
+		return err
+	}
+
 	// Ensure the configured network exists (auto-creates if missing).
 	if err := b.ensureNetwork(ctx); err != nil {
 		return fmt.Errorf("ensuring sandbox network: %w", err)
 	}
 
+	// Build wheels for the allowed packages in the background.
+	if b.packageCache != nil && b.cfg.Packages.Enabled && b.cfg.Packages.Prewarm {
+		prewarmCtx, cancel := context.WithCancel(context.Background())
+		b.stopPrewarm = cancel
+
+		go b.prewarmPackages(prewarmCtx)
+	}
+
 	// Start session manager if enabled.
 	if err := b.sessionManager.Start(ctx); err != nil {
 		return fmt.Errorf("starting session manager: %w", err)
 	}
 
-	b.log.WithField("image", b.cfg.Image).Info("Docker sandbox backend started")
+	b.startImageRepull()
+
+	if b.cfg.Pool.Size > 0 && b.sessionManager.Enabled() {
+		b.pool = newContainerPool(b)
+		b.pool.start(ctx)
+	}
 
 	return nil
 }
@@ -151,6 +211,17 @@ func (b *DockerBackend) Start(ctx context.Context) error {
 func (b *DockerBackend) Stop(ctx context.Context) error {
 	b.log.Info("Stopping Docker sandbox backend")
 
+	if b.stopPrewarm != nil {
+		b.stopPrewarm()
+	}
+
+	b.stopImageRepull()
+
+	if b.pool != nil {
+		b.pool.stop(ctx)
+		b.pool = nil
+	}
+
 	// Stop session manager first (this will cleanup session containers).
 	if err := b.sessionManager.Stop(ctx); err != nil {
 		b.log.WithError(err).Warn("Failed to stop session manager")
@@ -194,6 +265,10 @@ func (b *DockerBackend) Execute(ctx context.Context, req ExecuteRequest) (*Execu
 		return nil, fmt.Errorf("docker client not initialized, call Start() first")
 	}
 
+	if len(req.Packages) > 0 && req.SessionID == "" && !b.sessionManager.Enabled() {
+		return nil, ErrPackagesNeedSession
+	}
+
 	// If a session ID is provided, execute in the existing session.
 	if req.SessionID != "" {
 		return b.executeInSession(ctx, req)
@@ -210,11 +285,22 @@ func (b *DockerBackend) Execute(ctx context.Context, req ExecuteRequest) (*Execu
 
 // executeEphemeral runs code in a new container that is destroyed after execution.
 func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest) (*ExecutionResult, error) {
+	profile, err := b.cfg.Profile(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := b.acquireGPUSlot(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	executionID := uuid.New().String()
 	timeout := req.Timeout
 
 	if timeout == 0 {
-		timeout = time.Duration(b.cfg.Timeout) * time.Second
+		timeout = time.Duration(profile.Timeout) * time.Second
 	}
 
 	log := b.log.WithField("execution_id", executionID)
@@ -250,7 +336,7 @@ func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest
 	env["ETHPANDAOPS_EXECUTION_ID"] = executionID
 
 	// Build container configuration.
-	containerConfig, hostConfig, err := b.buildContainerConfig(sharedDir, outputDir, env)
+	containerConfig, hostConfig, err := b.buildContainerConfig(sharedDir, outputDir, env, profile)
 	if err != nil {
 		return nil, fmt.Errorf("building container config: %w", err)
 	}
@@ -285,6 +371,22 @@ func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest
 
 	log.Debug("Container started")
 
+	// Docker samples stats about once a second, so executions shorter than
+	// that may report no usage.
+	sampler := b.startUsageSampler(execCtx, containerID, nil)
+	defer sampler.stop()
+
+	// Stream output live while the container runs if the caller asked for it.
+	if req.streaming() {
+		streamCtx, streamCancel := context.WithCancel(execCtx)
+		streamDone := b.followContainerLogs(streamCtx, containerID, req)
+
+		defer func() {
+			streamCancel()
+			<-streamDone
+		}()
+	}
+
 	// Wait for container to finish or timeout.
 	result, err := b.waitForContainer(execCtx, containerID, timeout)
 	if err != nil {
@@ -299,6 +401,7 @@ func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest
 	}
 
 	duration := time.Since(startTime).Seconds()
+	usage := sampler.stop()
 
 	// Collect output files.
 	outputFiles, err := b.collectOutputFiles(outputDir)
@@ -308,6 +411,7 @@ func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest
 
 	// Read metrics if present.
 	metrics := b.readMetrics(outputDir)
+	structured := b.readResult(filepath.Join(outputDir, filepath.Base(ResultPath(executionID))))
 
 	log.WithFields(logrus.Fields{
 		"exit_code": result.exitCode,
@@ -321,31 +425,40 @@ func (b *DockerBackend) executeEphemeral(ctx context.Context, req ExecuteRequest
 		ExecutionID:     executionID,
 		OutputFiles:     outputFiles,
 		Metrics:         metrics,
+		Result:          structured,
 		DurationSeconds: duration,
+		Usage:           usage,
 	}, nil
 }
 
 // executeWithNewSession creates a new session container and executes code in it.
 func (b *DockerBackend) executeWithNewSession(ctx context.Context, req ExecuteRequest) (*ExecutionResult, error) {
+	profile, err := b.cfg.Profile(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := b.acquireGPUSlot(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	timeout := req.Timeout
 	if timeout == 0 {
-		timeout = time.Duration(b.cfg.Timeout) * time.Second
+		timeout = time.Duration(profile.Timeout) * time.Second
 	}
 
-	// Generate session ID upfront so it can be stored in container labels.
-	sessionID := b.sessionManager.GenerateSessionID()
+	// Create the session container, or claim a warm one from the pool.
+	sessionID, containerID, err := b.newSessionContainer(ctx, req.Env, req.OwnerID, req.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("creating session container: %w", err)
+	}
 
 	log := b.log.WithFields(logrus.Fields{
 		"mode":       "new-session",
 		"session_id": sessionID,
 	})
-	log.Debug("Creating new session container")
-
-	// Create the session container with session ID in labels.
-	containerID, err := b.createSessionContainer(ctx, sessionID, req.Env, req.OwnerID)
-	if err != nil {
-		return nil, fmt.Errorf("creating session container: %w", err)
-	}
 
 	// Record initial access time for TTL tracking.
 	b.sessionManager.RecordAccess(sessionID)
@@ -356,6 +469,7 @@ func (b *DockerBackend) executeWithNewSession(ctx context.Context, req ExecuteRe
 	session := &Session{
 		ID:          sessionID,
 		OwnerID:     req.OwnerID,
+		Profile:     req.Profile,
 		ContainerID: containerID,
 		CreatedAt:   time.Now(),
 		LastUsed:    time.Now(),
@@ -366,7 +480,7 @@ func (b *DockerBackend) executeWithNewSession(ctx context.Context, req ExecuteRe
 	defer b.sessionManager.unmarkExecuting(sessionID)
 
 	// Execute the code in the session.
-	result, err := b.execInContainer(ctx, session, req.Code, timeout, req.Env)
+	result, err := b.execInContainer(ctx, session, req, timeout)
 	if err != nil {
 		return nil, fmt.Errorf("executing in session: %w", err)
 	}
@@ -381,11 +495,6 @@ func (b *DockerBackend) executeWithNewSession(ctx context.Context, req ExecuteRe
 
 // executeInSession executes code in an existing session container.
 func (b *DockerBackend) executeInSession(ctx context.Context, req ExecuteRequest) (*ExecutionResult, error) {
-	timeout := req.Timeout
-	if timeout == 0 {
-		timeout = time.Duration(b.cfg.Timeout) * time.Second
-	}
-
 	log := b.log.WithFields(logrus.Fields{
 		"mode":       "existing-session",
 		"session_id": req.SessionID,
@@ -397,6 +506,27 @@ func (b *DockerBackend) executeInSession(ctx context.Context, req ExecuteRequest
 		return nil, fmt.Errorf("getting session: %w", err)
 	}
 
+	// A session keeps the profile it was created with; its container can't be resized.
+	if req.Profile != "" && req.Profile != session.Profile {
+		return nil, fmt.Errorf("%w: %s is not using profile %q", ErrSessionProfileFixed, session.ID, req.Profile)
+	}
+
+	profile, err := b.cfg.Profile(session.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := b.acquireGPUSlot(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = time.Duration(profile.Timeout) * time.Second
+	}
+
 	log.Debug("Executing in existing session")
 
 	// Mark session as executing to prevent TTL-based purging during execution.
@@ -404,7 +534,7 @@ func (b *DockerBackend) executeInSession(ctx context.Context, req ExecuteRequest
 	defer b.sessionManager.unmarkExecuting(req.SessionID)
 
 	// Execute the code in the session.
-	result, err := b.execInContainer(ctx, session, req.Code, timeout, req.Env)
+	result, err := b.execInContainer(ctx, session, req, timeout)
 	if err != nil {
 		return nil, fmt.Errorf("executing in session: %w", err)
 	}
@@ -417,9 +547,46 @@ func (b *DockerBackend) executeInSession(ctx context.Context, req ExecuteRequest
 	return result, nil
 }
 
+// newSessionContainer returns the session ID and container of a new
+// session. Sessions with the default profile claim a warm pool container
+// when one is ready; the pool container's env holds only the sandbox
+// defaults, and the session env is passed to every exec instead.
+func (b *DockerBackend) newSessionContainer(
+	ctx context.Context,
+	env map[string]string,
+	ownerID, profileName string,
+) (string, string, error) {
+	if b.pool != nil && profileName == "" {
+		if c, ok := b.pool.claim(ctx, ownerID); ok {
+			return c.sessionID, c.containerID, nil
+		}
+	}
+
+	// Generate session ID upfront so it can be stored in container labels.
+	sessionID := b.sessionManager.GenerateSessionID()
+
+	containerID, err := b.createSessionContainer(ctx, sessionID, env, ownerID, profileName)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sessionID, containerID, nil
+}
+
 // createSessionContainer creates a long-running container for session use.
-// sessionID is stored in container labels for stateless session recovery.
-func (b *DockerBackend) createSessionContainer(ctx context.Context, sessionID string, env map[string]string, ownerID string) (string, error) {
+// sessionID and profileName are stored in container labels for stateless
+// session recovery.
+func (b *DockerBackend) createSessionContainer(
+	ctx context.Context,
+	sessionID string,
+	env map[string]string,
+	ownerID, profileName string,
+) (string, error) {
+	profile, err := b.cfg.Profile(profileName)
+	if err != nil {
+		return "", err
+	}
+
 	// Merge environment variables with defaults.
 	containerEnv := SandboxEnvDefaults()
 
@@ -449,11 +616,27 @@ func (b *DockerBackend) createSessionContainer(ctx context.Context, sessionID st
 		labels[LabelOwnerID] = ownerID
 	}
 
+	if profileName != "" {
+		labels[LabelProfile] = profileName
+	}
+
+	return b.startSessionContainer(ctx, "", labels, envSlice, profile)
+}
+
+// startSessionContainer creates and starts a long-running session container
+// and prepares its /workspace and /output directories.
+func (b *DockerBackend) startSessionContainer(
+	ctx context.Context,
+	name string,
+	labels map[string]string,
+	env []string,
+	profile config.ExecutionProfile,
+) (string, error) {
 	// Session container runs sleep infinity and we exec into it.
 	containerConfig := &container.Config{
-		Image:      b.cfg.Image,
+		Image:      profile.Image,
 		Cmd:        []string{"sleep", "infinity"},
-		Env:        envSlice,
+		Env:        env,
 		User:       "nobody",
 		WorkingDir: "/workspace",
 		Labels:     labels,
@@ -461,12 +644,15 @@ func (b *DockerBackend) createSessionContainer(ctx context.Context, sessionID st
 
 	// Create workspace directory inside container.
 	hostConfig := &container.HostConfig{
-		NetworkMode: container.NetworkMode(b.cfg.Network),
+		NetworkMode: container.NetworkMode(profile.Network),
 		ExtraHosts:  []string{"host.docker.internal:host-gateway"},
 	}
+	b.applyDNSConfig(hostConfig)
+	applyGPUConfig(hostConfig, profile)
+	b.applyPackageCache(containerConfig, hostConfig)
 
 	// Apply security configuration.
-	securityCfg, err := b.getSecurityConfig()
+	securityCfg, err := b.getSecurityConfig(profile)
 	if err != nil {
 		return "", fmt.Errorf("getting security config: %w", err)
 	}
@@ -475,7 +661,7 @@ func (b *DockerBackend) createSessionContainer(ctx context.Context, sessionID st
 	securityCfg.ApplyToHostConfig(hostConfig)
 
 	// Create container.
-	resp, err := b.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
+	resp, err := b.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, name)
 	if err != nil {
 		return "", fmt.Errorf("creating container: %w", err)
 	}
@@ -538,9 +724,8 @@ func (b *DockerBackend) createSessionDirs(ctx context.Context, containerID strin
 func (b *DockerBackend) execInContainer(
 	ctx context.Context,
 	session *Session,
-	code string,
+	req ExecuteRequest,
 	timeout time.Duration,
-	env map[string]string,
 ) (*ExecutionResult, error) {
 	executionID := uuid.New().String()
 	log := b.log.WithFields(logrus.Fields{
@@ -549,6 +734,13 @@ func (b *DockerBackend) execInContainer(
 		"container_id": session.ContainerID,
 	})
 
+	// Install requested packages first; they don't count toward the timeout.
+	if len(req.Packages) > 0 {
+		if err := b.installSessionPackages(ctx, session, req.Packages); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create execution context with timeout.
 	execCtx, cancel := context.WithTimeout(ctx, timeout+5*time.Second)
 	defer cancel()
@@ -557,7 +749,7 @@ func (b *DockerBackend) execInContainer(
 	scriptPath := fmt.Sprintf("/tmp/script_%s.py", executionID)
 
 	// Use base64 encoding to safely transfer code without shell injection via heredoc.
-	encoded := base64.StdEncoding.EncodeToString([]byte(code))
+	encoded := base64.StdEncoding.EncodeToString([]byte(req.Code))
 	writeCmd := []string{"sh", "-c", fmt.Sprintf("echo %s | base64 -d > %s", encoded, scriptPath)}
 
 	writeConfig := container.ExecOptions{
@@ -578,8 +770,8 @@ func (b *DockerBackend) execInContainer(
 	// Execute the script with ETHPANDAOPS_EXECUTION_ID env var for storage.upload().
 	startTime := time.Now()
 
-	execEnv := make([]string, 0, len(env)+1)
-	for k, v := range env {
+	execEnv := make([]string, 0, len(req.Env)+1)
+	for k, v := range req.Env {
 		if k == "ETHPANDAOPS_EXECUTION_ID" {
 			continue
 		}
@@ -587,13 +779,28 @@ func (b *DockerBackend) execInContainer(
 	}
 	execEnv = append(execEnv, "ETHPANDAOPS_EXECUTION_ID="+executionID)
 
+	// Requested packages are installed into this user base.
+	if b.cfg.Packages.Enabled {
+		execEnv = append(execEnv, "PYTHONUSERBASE="+packagesUserBase)
+	}
+
 	execConfig := container.ExecOptions{
-		Cmd:          []string{"python", scriptPath},
+		Cmd:          b.sessionCommand(scriptPath, timeout),
 		AttachStdout: true,
 		AttachStderr: true,
 		Env:          execEnv,
 	}
 
+	// The session container outlives the execution, so usage is measured
+	// from a sample taken now; without one it is left unreported.
+	var sampler *usageSampler
+	if baseline, err := b.containerUsage(execCtx, session.ContainerID); err == nil {
+		sampler = b.startUsageSampler(execCtx, session.ContainerID, &baseline)
+		defer sampler.stop()
+	} else {
+		log.WithError(err).Debug("Failed to sample session container stats")
+	}
+
 	execResp, err := b.client.ContainerExecCreate(execCtx, session.ContainerID, execConfig)
 	if err != nil {
 		return nil, fmt.Errorf("creating exec: %w", err)
@@ -612,7 +819,7 @@ func (b *DockerBackend) execInContainer(
 	done := make(chan error, 1)
 
 	go func() {
-		_, err := stdcopy.StdCopy(&stdout, &stderr, attachResp.Reader)
+		_, err := stdcopy.StdCopy(teeWriter(&stdout, req.Stdout), teeWriter(&stderr, req.Stderr), attachResp.Reader)
 		done <- err
 	}()
 
@@ -638,7 +845,7 @@ func (b *DockerBackend) execInContainer(
 			_ = b.client.ContainerExecStart(cleanupCtx, cleanupResp.ID, container.ExecStartOptions{})
 		}
 
-		return nil, fmt.Errorf("execution timed out after %s", timeout)
+		return nil, fmt.Errorf("%w after %s", ErrExecutionTimeout, timeout)
 	}
 
 	// Get exit code.
@@ -649,8 +856,19 @@ func (b *DockerBackend) execInContainer(
 
 	duration := time.Since(startTime).Seconds()
 
-	// Cleanup the script file.
-	cleanupCmd := []string{"rm", "-f", scriptPath}
+	if sampler != nil {
+		if final, err := b.containerUsage(ctx, session.ContainerID); err == nil {
+			sampler.record(final)
+		}
+	}
+
+	usage := sampler.stop()
+
+	resultPath := ResultPath(executionID)
+	structured := b.readSessionResult(ctx, session.ContainerID, resultPath)
+
+	// Cleanup the script and result files.
+	cleanupCmd := []string{"rm", "-f", scriptPath, resultPath}
 
 	cleanupConfig := container.ExecOptions{
 		Cmd: cleanupCmd,
@@ -671,10 +889,27 @@ func (b *DockerBackend) execInContainer(
 		Stderr:          stderr.String(),
 		ExitCode:        inspectResp.ExitCode,
 		ExecutionID:     executionID,
+		Result:          structured,
 		DurationSeconds: duration,
+		Usage:           usage,
 	}, nil
 }
 
+// sessionCommand returns the command that runs a script in a session
+// container. In kernel mode the script is handed to the session's
+// long-lived Python kernel, which keeps its namespace between executions
+// and enforces the timeout itself.
+func (b *DockerBackend) sessionCommand(scriptPath string, timeout time.Duration) []string {
+	if !b.cfg.Sessions.Kernel {
+		return []string{"python", scriptPath}
+	}
+
+	return []string{
+		"python", "-m", "ethpandaops._kernel", "run", scriptPath,
+		strconv.Itoa(int(timeout.Seconds())),
+	}
+}
+
 // collectSessionFiles lists files in the session's /workspace directory.
 func (b *DockerBackend) collectSessionFiles(ctx context.Context, containerID string) []SessionFile {
 	execConfig := container.ExecOptions{
@@ -780,6 +1015,7 @@ func (b *DockerBackend) createExecutionDirs(executionID string) (string, error)
 func (b *DockerBackend) buildContainerConfig(
 	sharedDir, outputDir string,
 	env map[string]string,
+	profile config.ExecutionProfile,
 ) (*container.Config, *container.HostConfig, error) {
 	// Merge environment variables with defaults.
 	containerEnv := SandboxEnvDefaults()
@@ -803,7 +1039,7 @@ func (b *DockerBackend) buildContainerConfig(
 	}
 
 	containerConfig := &container.Config{
-		Image:  b.cfg.Image,
+		Image:  profile.Image,
 		Cmd:    []string{"python", "/shared/script.py"},
 		Env:    envSlice,
 		User:   "nobody",
@@ -825,13 +1061,16 @@ func (b *DockerBackend) buildContainerConfig(
 	}
 
 	hostConfig := &container.HostConfig{
-		NetworkMode: container.NetworkMode(b.cfg.Network),
+		NetworkMode: container.NetworkMode(profile.Network),
 		Mounts:      CreateMounts(hostSharedDir, hostOutputDir),
 		ExtraHosts:  []string{"host.docker.internal:host-gateway"},
 	}
+	b.applyDNSConfig(hostConfig)
+	applyGPUConfig(hostConfig, profile)
+	b.applyPackageCache(containerConfig, hostConfig)
 
 	// Apply security configuration.
-	securityCfg, err := b.getSecurityConfig()
+	securityCfg, err := b.getSecurityConfig(profile)
 	if err != nil {
 		return nil, nil, fmt.Errorf("getting security config: %w", err)
 	}
@@ -841,9 +1080,80 @@ func (b *DockerBackend) buildContainerConfig(
 	return containerConfig, hostConfig, nil
 }
 
-// getSecurityConfig returns the security configuration for this backend.
-func (b *DockerBackend) getSecurityConfig() (*SecurityConfig, error) {
-	return b.securityConfigFunc(b.cfg.MemoryLimit, b.cfg.CPULimit)
+// applyDNSConfig applies the configured resolvers, search domains and
+// extra hosts entries to a sandbox container.
+func (b *DockerBackend) applyDNSConfig(hostConfig *container.HostConfig) {
+	dns := b.cfg.DNS
+
+	hostConfig.DNS = append(hostConfig.DNS, dns.Nameservers...)
+	hostConfig.DNSSearch = append(hostConfig.DNSSearch, dns.Search...)
+	hostConfig.DNSOptions = append(hostConfig.DNSOptions, dns.Options...)
+	hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, dns.ExtraHosts...)
+}
+
+// applyGPUConfig requests GPU passthrough for profiles that ask for it, the
+// equivalent of docker run --gpus.
+func applyGPUConfig(hostConfig *container.HostConfig, profile config.ExecutionProfile) {
+	if !profile.UsesGPU() {
+		return
+	}
+
+	hostConfig.DeviceRequests = append(hostConfig.DeviceRequests, container.DeviceRequest{
+		Count:        profile.GPUs,
+		Capabilities: [][]string{{"gpu"}},
+	})
+}
+
+// acquireGPUSlot waits for a free GPU execution slot when the profile
+// requests GPUs. The returned func releases the slot.
+func (b *DockerBackend) acquireGPUSlot(ctx context.Context, profile config.ExecutionProfile) (func(), error) {
+	if !profile.UsesGPU() {
+		return func() {}, nil
+	}
+
+	select {
+	case b.gpuSlots <- struct{}{}:
+		return func() { <-b.gpuSlots }, nil
+	default:
+	}
+
+	observability.SandboxGPUSlotWaiters.Inc()
+	defer observability.SandboxGPUSlotWaiters.Dec()
+
+	select {
+	case b.gpuSlots <- struct{}{}:
+		return func() { <-b.gpuSlots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a GPU slot: %w", ctx.Err())
+	}
+}
+
+// GPUStatus implements GPUReporter.
+func (b *DockerBackend) GPUStatus() GPUStatus {
+	profiles := make([]string, 0, len(b.cfg.Profiles))
+
+	for name, profile := range b.cfg.Profiles {
+		if profile.UsesGPU() {
+			profiles = append(profiles, name)
+		}
+	}
+
+	sort.Strings(profiles)
+
+	inUse := len(b.gpuSlots)
+
+	return GPUStatus{
+		Profiles:      profiles,
+		MaxConcurrent: cap(b.gpuSlots),
+		InUse:         inUse,
+		Available:     cap(b.gpuSlots) - inUse,
+	}
+}
+
+// getSecurityConfig returns the security configuration for this backend,
+// sized by the given execution profile.
+func (b *DockerBackend) getSecurityConfig(profile config.ExecutionProfile) (*SecurityConfig, error) {
+	return b.securityConfigFunc(profile.MemoryLimit, profile.CPULimit)
 }
 
 // waitForContainer waits for a container to finish and returns its output.
@@ -877,7 +1187,7 @@ func (b *DockerBackend) waitForContainer(
 			exitCode: int(status.StatusCode),
 		}, nil
 	case <-waitCtx.Done():
-		return nil, fmt.Errorf("execution timed out after %s", timeout)
+		return nil, fmt.Errorf("%w after %s", ErrExecutionTimeout, timeout)
 	}
 
 	return nil, fmt.Errorf("unexpected wait state")
@@ -1002,14 +1312,8 @@ func (b *DockerBackend) getSessionContainer(ctx context.Context, sessionID strin
 		return nil, nil
 	}
 
-	c := containers[0]
-
-	return &SessionContainer{
-		ContainerID: c.ID,
-		SessionID:   sessionID,
-		OwnerID:     c.Labels[LabelOwnerID],
-		CreatedAt:   parseContainerCreatedAt(c.Labels, c.Created),
-	}, nil
+	// Unclaimed pool containers aren't sessions yet.
+	return sessionContainerFromSummary(containers[0]), nil
 }
 
 // listAllSessionContainers queries Docker for all session containers.
@@ -1034,17 +1338,13 @@ func (b *DockerBackend) listAllSessionContainers(ctx context.Context) ([]*Sessio
 	result := make([]*SessionContainer, 0, len(containers))
 
 	for _, c := range containers {
-		sessionID := c.Labels[LabelSessionID]
-		if sessionID == "" {
+		if c.Labels[LabelSessionID] == "" {
 			continue
 		}
 
-		result = append(result, &SessionContainer{
-			ContainerID: c.ID,
-			SessionID:   sessionID,
-			OwnerID:     c.Labels[LabelOwnerID],
-			CreatedAt:   parseContainerCreatedAt(c.Labels, c.Created),
-		})
+		if sc := sessionContainerFromSummary(c); sc != nil {
+			result = append(result, sc)
+		}
 	}
 
 	return result, nil
@@ -1095,6 +1395,11 @@ func (b *DockerBackend) ListSessions(ctx context.Context, ownerID string) ([]Ses
 	return sessions, nil
 }
 
+// RestoreSessionAccess seeds a session's last-used time for TTL tracking.
+func (b *DockerBackend) RestoreSessionAccess(sessionID string, lastUsed time.Time) {
+	b.sessionManager.RestoreAccess(sessionID, lastUsed)
+}
+
 // CreateSession creates a new empty session and returns its ID.
 func (b *DockerBackend) CreateSession(ctx context.Context, ownerID string, env map[string]string) (string, error) {
 	if b.client == nil {
@@ -1114,20 +1419,16 @@ func (b *DockerBackend) CreateSession(ctx context.Context, ownerID string, env m
 		)
 	}
 
-	// Generate session ID.
-	sessionID := b.sessionManager.GenerateSessionID()
+	// Create the session container, or claim a warm one from the pool.
+	sessionID, _, err := b.newSessionContainer(ctx, env, ownerID, "")
+	if err != nil {
+		return "", fmt.Errorf("creating session container: %w", err)
+	}
 
 	log := b.log.WithFields(logrus.Fields{
 		"session_id": sessionID,
 		"owner_id":   ownerID,
 	})
-	log.Debug("Creating new session")
-
-	// Create the session container.
-	_, err := b.createSessionContainer(ctx, sessionID, env, ownerID)
-	if err != nil {
-		return "", fmt.Errorf("creating session container: %w", err)
-	}
 
 	// Record initial access time for TTL tracking.
 	b.sessionManager.RecordAccess(sessionID)
@@ -1195,13 +1496,19 @@ func (b *DockerBackend) cleanupExpiredContainers(ctx context.Context) error {
 
 	for _, c := range containers {
 		createdAt := parseContainerCreatedAt(c.Labels, c.Created)
+		sessionID := c.Labels[LabelSessionID]
+		ownerID := c.Labels[LabelOwnerID]
+
+		// Claimed pool containers record their claim time in the name.
+		if sc := sessionContainerFromSummary(c); sc != nil {
+			createdAt, ownerID = sc.CreatedAt, sc.OwnerID
+		}
+
 		if now.Sub(createdAt) <= maxAge {
 			continue
 		}
 
 		// Container is expired, remove it.
-		sessionID := c.Labels[LabelSessionID]
-		ownerID := c.Labels[LabelOwnerID]
 
 		b.log.WithFields(logrus.Fields{
 			"container_id": c.ID[:12],
@@ -1228,9 +1535,32 @@ func (b *DockerBackend) cleanupExpiredContainers(ctx context.Context) error {
 	return nil
 }
 
-// ensureImage ensures the sandbox image is available locally.
+// ensureImage ensures the sandbox image and every profile image are
+// available locally.
 func (b *DockerBackend) ensureImage(ctx context.Context) error {
-	_, err := b.client.ImageInspect(ctx, b.cfg.Image)
+	for _, ref := range b.profileValues(func(p config.ExecutionProfile) string { return p.Image }) {
+		var err error
+		if b.cfg.Images.PullOnStart {
+			_, err = b.pullImage(ctx, ref)
+		} else {
+			err = b.ensureImageRef(ctx, ref)
+		}
+
+		if err != nil {
+			return fmt.Errorf("image %q: %w", ref, err)
+		}
+
+		if err := b.verifyImageDigest(ctx, ref); err != nil {
+			return fmt.Errorf("image %q: %w", ref, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureImageRef pulls a single image if it is not already present.
+func (b *DockerBackend) ensureImageRef(ctx context.Context, ref string) error {
+	_, err := b.client.ImageInspect(ctx, ref)
 	if err == nil {
 		return nil
 	}
@@ -1240,36 +1570,67 @@ func (b *DockerBackend) ensureImage(ctx context.Context) error {
 	}
 
 	// Image not found, try to pull it.
-	b.log.WithField("image", b.cfg.Image).Info("Pulling sandbox image")
+	_, err = b.pullImage(ctx, ref)
 
-	reader, err := b.client.ImagePull(ctx, b.cfg.Image, image.PullOptions{})
-	if err != nil {
-		return fmt.Errorf("pulling image: %w", err)
+	return err
+}
+
+// profileValues returns the distinct non-empty values of a field across the
+// default settings and every configured execution profile.
+func (b *DockerBackend) profileValues(field func(config.ExecutionProfile) string) []string {
+	names := make([]string, 0, len(b.cfg.Profiles)+1)
+	names = append(names, "")
+
+	for name := range b.cfg.Profiles {
+		names = append(names, name)
+	}
+
+	seen := make(map[string]struct{}, len(names))
+	values := make([]string, 0, len(names))
+
+	for _, name := range names {
+		profile, err := b.cfg.Profile(name)
+		if err != nil {
+			continue
+		}
+
+		value := field(profile)
+		if _, ok := seen[value]; ok || value == "" {
+			continue
+		}
+
+		seen[value] = struct{}{}
+		values = append(values, value)
 	}
-	defer func() { _ = reader.Close() }()
 
-	// Consume the pull output.
-	if _, err := io.Copy(io.Discard, reader); err != nil {
-		return fmt.Errorf("reading pull output: %w", err)
+	return values
+}
+
+// ensureNetwork ensures the configured Docker networks, including those of
+// execution profiles, exist.
+func (b *DockerBackend) ensureNetwork(ctx context.Context) error {
+	for _, name := range b.profileValues(func(p config.ExecutionProfile) string { return p.Network }) {
+		if err := b.ensureNetworkName(ctx, name); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// ensureNetwork ensures the configured Docker network exists.
+// ensureNetworkName ensures a single Docker network exists.
 // For user-defined networks, it checks if the network exists and creates it
 // if missing. This enables running outside docker compose without requiring
 // manual network creation. Built-in network modes (host, none, bridge,
 // default) are skipped.
-func (b *DockerBackend) ensureNetwork(ctx context.Context) error {
-	networkMode := container.NetworkMode(b.cfg.Network)
+func (b *DockerBackend) ensureNetworkName(ctx context.Context, networkName string) error {
+	networkMode := container.NetworkMode(networkName)
 
 	// Skip for empty or built-in network modes.
 	if !networkMode.IsUserDefined() {
 		return nil
 	}
 
-	networkName := b.cfg.Network
 	log := b.log.WithField("network", networkName)
 
 	// Check if the network already exists.
@@ -65,19 +65,8 @@ func (b *GVisorBackend) Start(ctx context.Context) error {
 		return fmt.Errorf("verifying gvisor runtime: %w", err)
 	}
 
-	// Ensure the sandbox image is available.
-	if err := b.ensureImage(ctx); err != nil {
-		return fmt.Errorf("ensuring sandbox image: %w", err)
-	}
-
-	// Ensure the configured network exists (auto-creates if missing).
-	if err := b.ensureNetwork(ctx); err != nil {
-		return fmt.Errorf("ensuring sandbox network: %w", err)
-	}
-
-	// Start session manager if enabled.
-	if err := b.sessionManager.Start(ctx); err != nil {
-		return fmt.Errorf("starting session manager: %w", err)
+	if err := b.startServices(ctx); err != nil {
+		return err
 	}
 
 	b.log.WithField("image", b.cfg.Image).Info("gVisor sandbox backend started")
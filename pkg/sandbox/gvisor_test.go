@@ -0,0 +1,113 @@
+package sandbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+)
+
+var dockerAPIVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// fakeDockerDaemon answers the Docker API calls a backend makes on start:
+// gVisor is installed, the sandbox image is present and no containers exist.
+// Container creation fails so the pool never actually fills.
+type fakeDockerDaemon struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (d *fakeDockerDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := dockerAPIVersionPrefix.ReplaceAllString(r.URL.Path, "")
+
+	d.mu.Lock()
+	d.requests = append(d.requests, r.Method+" "+path+"?"+r.URL.RawQuery)
+	d.mu.Unlock()
+
+	w.Header().Set("Api-Version", "1.43")
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case path == "/_ping":
+		_, _ = w.Write([]byte("OK"))
+	case path == "/info":
+		_, _ = w.Write([]byte(`{"Runtimes":{"runsc":{"path":"runsc"}}}`))
+	case path == "/containers/json":
+		_, _ = w.Write([]byte(`[]`))
+	case strings.HasPrefix(path, "/images/") && strings.HasSuffix(path, "/json"):
+		_, _ = w.Write([]byte(`{"Id":"sha256:0000000000000000000000000000000000000000000000000000000000000000"}`))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"not supported by fake daemon"}`))
+	}
+}
+
+// sawPoolList reports whether the stale pool container sweep ran.
+func (d *fakeDockerDaemon) sawPoolList() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, req := range d.requests {
+		if strings.HasPrefix(req, "GET /containers/json?") && strings.Contains(req, LabelPool) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestGVisorBackendStartsWarmPool(t *testing.T) {
+	daemon := &fakeDockerDaemon{}
+	srv := httptest.NewServer(daemon)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("DOCKER_HOST", "tcp://"+strings.TrimPrefix(srv.URL, "http://"))
+	t.Setenv("DOCKER_API_VERSION", "1.43")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	t.Setenv("DOCKER_CERT_PATH", "")
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	backend, err := NewGVisorBackend(config.SandboxConfig{
+		Backend: "gvisor",
+		Image:   "sandbox:test",
+		Timeout: 30,
+		Network: "none",
+		Sessions: config.SessionConfig{
+			TTL:         time.Minute,
+			MaxDuration: time.Hour,
+			MaxSessions: 1,
+		},
+		Pool: config.SandboxPoolConfig{Size: 1},
+	}, log)
+	if err != nil {
+		t.Fatalf("NewGVisorBackend: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := backend.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if backend.pool == nil {
+		t.Fatal("warm pool was not started")
+	}
+
+	if !daemon.sawPoolList() {
+		t.Fatal("warm pool did not sweep stale pool containers on start")
+	}
+
+	if err := backend.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
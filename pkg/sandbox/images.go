@@ -0,0 +1,296 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+// ErrImagesUnsupported is returned when the backend doesn't manage images.
+var ErrImagesUnsupported = errors.New("sandbox backend does not manage images")
+
+// ImageStatus describes a sandbox image as currently present on the Docker host.
+type ImageStatus struct {
+	// Ref is the configured image reference.
+	Ref string
+	// Profiles lists the execution profiles using the image; "" is the
+	// default settings.
+	Profiles []string
+	// ID is the local image ID, empty when the image is missing.
+	ID string
+	// Digest is the registry digest of the local image, when known.
+	Digest string
+	// Pinned reports whether Ref is pinned by digest.
+	Pinned bool
+	// Floating reports whether Ref is :latest or untagged and so re-pulled
+	// when sandbox.images.repull_interval is set.
+	Floating bool
+	// Created is when the image was built.
+	Created time.Time
+	// Size is the image size in bytes.
+	Size int64
+	// PulledAt is when this server last pulled the image, if it has.
+	PulledAt time.Time
+	// Error reports why the image couldn't be inspected.
+	Error string
+}
+
+// ImageManager is implemented by backends that manage sandbox images.
+type ImageManager interface {
+	// ImageStatus reports every configured sandbox image.
+	ImageStatus(ctx context.Context) ([]ImageStatus, error)
+	// PullImages pulls every configured sandbox image now, verifies pinned
+	// digests and reports the result.
+	PullImages(ctx context.Context) ([]ImageStatus, error)
+}
+
+// imageRef is a parsed image reference.
+type imageRef struct {
+	digest   string
+	floating bool
+}
+
+func parseImageRef(ref string) (imageRef, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return imageRef{}, fmt.Errorf("parsing image reference: %w", err)
+	}
+
+	if digested, ok := named.(reference.Digested); ok {
+		return imageRef{digest: digested.Digest().String()}, nil
+	}
+
+	tagged, ok := named.(reference.Tagged)
+
+	return imageRef{floating: !ok || tagged.Tag() == "latest"}, nil
+}
+
+// pullImage pulls ref and reports whether the local image changed.
+func (b *DockerBackend) pullImage(ctx context.Context, ref string) (bool, error) {
+	var before string
+	if inspect, err := b.client.ImageInspect(ctx, ref); err == nil {
+		before = inspect.ID
+	}
+
+	b.log.WithField("image", ref).Info("Pulling sandbox image")
+
+	reader, err := b.client.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		observability.SandboxImagePullsTotal.WithLabelValues("error").Inc()
+
+		return false, fmt.Errorf("pulling image: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	// Consume the pull output.
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		observability.SandboxImagePullsTotal.WithLabelValues("error").Inc()
+
+		return false, fmt.Errorf("reading pull output: %w", err)
+	}
+
+	inspect, err := b.client.ImageInspect(ctx, ref)
+	if err != nil {
+		observability.SandboxImagePullsTotal.WithLabelValues("error").Inc()
+
+		return false, fmt.Errorf("inspecting pulled image: %w", err)
+	}
+
+	b.imagesMu.Lock()
+	b.imagePulledAt[ref] = time.Now()
+	b.imagesMu.Unlock()
+
+	changed := inspect.ID != before
+	if changed {
+		observability.SandboxImagePullsTotal.WithLabelValues("updated").Inc()
+
+		if before != "" {
+			b.log.WithField("image", ref).WithField("id", inspect.ID).Info("Sandbox image updated")
+		}
+	} else {
+		observability.SandboxImagePullsTotal.WithLabelValues("unchanged").Inc()
+	}
+
+	return changed, nil
+}
+
+// verifyImageDigest checks that the local image of a digest-pinned
+// reference carries that digest.
+func (b *DockerBackend) verifyImageDigest(ctx context.Context, ref string) error {
+	parsed, err := parseImageRef(ref)
+	if err != nil || parsed.digest == "" {
+		return err
+	}
+
+	inspect, err := b.client.ImageInspect(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("inspecting image: %w", err)
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		if strings.HasSuffix(repoDigest, "@"+parsed.digest) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("local image %s does not match pinned digest %s", inspect.ID, parsed.digest)
+}
+
+// imageProfiles maps each configured image to the profiles using it.
+func (b *DockerBackend) imageProfiles() map[string][]string {
+	names := append([]string{""}, slices.Sorted(maps.Keys(b.cfg.Profiles))...)
+	profiles := make(map[string][]string, len(names))
+
+	for _, name := range names {
+		if profile, err := b.cfg.Profile(name); err == nil {
+			profiles[profile.Image] = append(profiles[profile.Image], name)
+		}
+	}
+
+	return profiles
+}
+
+// ImageStatus implements ImageManager.
+func (b *DockerBackend) ImageStatus(ctx context.Context) ([]ImageStatus, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf("docker client not initialized")
+	}
+
+	refs := b.profileValues(func(p config.ExecutionProfile) string { return p.Image })
+	profiles := b.imageProfiles()
+	statuses := make([]ImageStatus, 0, len(refs))
+
+	for _, ref := range refs {
+		status := ImageStatus{Ref: ref, Profiles: profiles[ref]}
+
+		if parsed, err := parseImageRef(ref); err == nil {
+			status.Pinned, status.Floating = parsed.digest != "", parsed.floating
+		}
+
+		b.imagesMu.Lock()
+		status.PulledAt = b.imagePulledAt[ref]
+		b.imagesMu.Unlock()
+
+		inspect, err := b.client.ImageInspect(ctx, ref)
+
+		switch {
+		case errdefs.IsNotFound(err):
+			status.Error = "image not present"
+		case err != nil:
+			status.Error = err.Error()
+		default:
+			status.ID = inspect.ID
+			status.Size = inspect.Size
+
+			if len(inspect.RepoDigests) > 0 {
+				status.Digest = inspect.RepoDigests[0]
+				if _, digest, ok := strings.Cut(status.Digest, "@"); ok {
+					status.Digest = digest
+				}
+			}
+
+			if created, err := time.Parse(time.RFC3339Nano, inspect.Created); err == nil {
+				status.Created = created
+			}
+
+			if err := b.verifyImageDigest(ctx, ref); err != nil {
+				status.Error = err.Error()
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// PullImages implements ImageManager.
+func (b *DockerBackend) PullImages(ctx context.Context) ([]ImageStatus, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf("docker client not initialized")
+	}
+
+	for _, ref := range b.profileValues(func(p config.ExecutionProfile) string { return p.Image }) {
+		if _, err := b.pullImage(ctx, ref); err != nil {
+			return nil, fmt.Errorf("image %q: %w", ref, err)
+		}
+
+		if err := b.verifyImageDigest(ctx, ref); err != nil {
+			return nil, fmt.Errorf("image %q: %w", ref, err)
+		}
+	}
+
+	return b.ImageStatus(ctx)
+}
+
+// startImageRepull re-pulls floating images every repull_interval so
+// sessions created afterwards use the newest push.
+func (b *DockerBackend) startImageRepull() {
+	interval := b.cfg.Images.RepullInterval
+	if interval <= 0 {
+		return
+	}
+
+	var floating []string
+
+	for _, ref := range b.profileValues(func(p config.ExecutionProfile) string { return p.Image }) {
+		if parsed, err := parseImageRef(ref); err == nil && parsed.floating {
+			floating = append(floating, ref)
+		}
+	}
+
+	if len(floating) == 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	b.repullDone = done
+	b.repullWG.Add(1)
+
+	go func() {
+		defer b.repullWG.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, ref := range floating {
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+
+					if _, err := b.pullImage(ctx, ref); err != nil {
+						b.log.WithError(err).WithField("image", ref).Warn("Failed to re-pull sandbox image")
+					}
+
+					cancel()
+				}
+			}
+		}
+	}()
+}
+
+// stopImageRepull stops the re-pull loop, if running.
+func (b *DockerBackend) stopImageRepull() {
+	if b.repullDone == nil {
+		return
+	}
+
+	close(b.repullDone)
+	b.repullWG.Wait()
+	b.repullDone = nil
+}
@@ -0,0 +1,227 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/google/uuid"
+
+	"github.com/ethpandaops/panda/pkg/wheelcache"
+)
+
+const (
+	// PackageCacheMountPath is where the package cache is mounted in sandboxes.
+	PackageCacheMountPath = "/opt/panda/wheels"
+
+	// packageBuildTimeout bounds a single package cache build.
+	packageBuildTimeout = 10 * time.Minute
+)
+
+// ErrPackageCacheDisabled is returned when sandbox.package_cache is not enabled.
+var ErrPackageCacheDisabled = errors.New("package cache is disabled; set sandbox.package_cache.enabled")
+
+// packageSpecPattern matches pip requirement specifiers such as
+// "polars", "polars==1.2.0" or "pandas[performance]>=2". Options starting
+// with "-" are rejected so callers can't redirect pip to another index.
+var packageSpecPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._\-]*(\[[A-Za-z0-9._,\-]+\])?([<>=!~]=?[A-Za-z0-9.*+!\-]+)?(,[<>=!~]=?[A-Za-z0-9.*+!\-]+)*$`)
+
+// PackageBuildResult is the outcome of adding packages to the cache.
+type PackageBuildResult struct {
+	Added  []wheelcache.Entry
+	Log    string
+	Report *wheelcache.Report
+}
+
+// PackageCacher is implemented by backends that maintain the shared package cache.
+type PackageCacher interface {
+	// PackageCacheStatus verifies the cache and reports its contents.
+	PackageCacheStatus(ctx context.Context) (*wheelcache.Report, error)
+	// BuildPackageCache builds wheels for packages and their dependencies into the cache.
+	BuildPackageCache(ctx context.Context, packages []string) (*PackageBuildResult, error)
+}
+
+// initPackageCache creates and verifies the package cache when enabled.
+func (b *DockerBackend) initPackageCache() error {
+	if b.packageCache == nil {
+		return nil
+	}
+
+	if err := b.packageCache.Init(); err != nil {
+		return fmt.Errorf("initializing package cache: %w", err)
+	}
+
+	report, err := b.packageCache.Verify()
+	if err != nil {
+		return fmt.Errorf("verifying package cache: %w", err)
+	}
+
+	if len(report.Removed) > 0 {
+		b.log.WithField("removed", report.Removed).Warn("Removed package cache files failing integrity checks")
+	}
+
+	return nil
+}
+
+// applyPackageCache mounts the package cache read-only and points pip at it.
+func (b *DockerBackend) applyPackageCache(containerConfig *container.Config, hostConfig *container.HostConfig) {
+	if b.packageCache == nil {
+		return
+	}
+
+	hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   b.packageCacheHostPath(wheelcache.WheelsDir),
+		Target:   PackageCacheMountPath,
+		ReadOnly: true,
+	})
+
+	containerConfig.Env = append(containerConfig.Env,
+		"PIP_FIND_LINKS="+PackageCacheMountPath,
+		"PIP_PREFER_BINARY=1",
+	)
+}
+
+// packageCacheHostPath returns a path under the cache as seen by the Docker daemon.
+func (b *DockerBackend) packageCacheHostPath(rel string) string {
+	root := b.cfg.PackageCache.Dir
+	if b.cfg.PackageCache.HostDir != "" {
+		root = b.cfg.PackageCache.HostDir
+	}
+
+	return filepath.Join(root, rel)
+}
+
+// PackageCacheStatus implements PackageCacher.
+func (b *DockerBackend) PackageCacheStatus(_ context.Context) (*wheelcache.Report, error) {
+	if b.packageCache == nil {
+		return nil, ErrPackageCacheDisabled
+	}
+
+	return b.packageCache.Verify()
+}
+
+// BuildPackageCache implements PackageCacher. Wheels are built by pip in a
+// throwaway container from the sandbox image, so they match its Python
+// version and platform, then imported into the cache.
+func (b *DockerBackend) BuildPackageCache(ctx context.Context, packages []string) (*PackageBuildResult, error) {
+	if b.packageCache == nil {
+		return nil, ErrPackageCacheDisabled
+	}
+
+	if b.client == nil {
+		return nil, fmt.Errorf("docker client not initialized")
+	}
+
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("at least one package is required")
+	}
+
+	for _, pkg := range packages {
+		if !packageSpecPattern.MatchString(pkg) {
+			return nil, fmt.Errorf("invalid package specifier %q", pkg)
+		}
+	}
+
+	buildID := uuid.New().String()
+
+	staging, err := b.packageCache.NewStaging(buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := os.RemoveAll(staging); err != nil {
+			b.log.WithError(err).Warn("Failed to remove package cache staging directory")
+		}
+	}()
+
+	profile, err := b.cfg.Profile("")
+	if err != nil {
+		return nil, err
+	}
+
+	containerConfig := &container.Config{
+		Image: profile.Image,
+		Cmd:   append([]string{"pip", "wheel", "--no-cache-dir", "--wheel-dir", "/staging"}, packages...),
+		Env:   []string{"HOME=/tmp", "XDG_CACHE_HOME=/tmp"},
+		User:  "nobody",
+		Labels: map[string]string{
+			LabelManaged:   "true",
+			LabelCreatedAt: strconv.FormatInt(time.Now().Unix(), 10),
+		},
+	}
+
+	hostConfig := &container.HostConfig{
+		NetworkMode: container.NetworkMode(profile.Network),
+		Mounts: []mount.Mount{{
+			Type:   mount.TypeBind,
+			Source: b.packageCacheHostPath(filepath.Join(wheelcache.StagingDir, buildID)),
+			Target: "/staging",
+		}},
+	}
+	b.applyDNSConfig(hostConfig)
+
+	securityCfg, err := b.getSecurityConfig(profile)
+	if err != nil {
+		return nil, fmt.Errorf("getting security config: %w", err)
+	}
+
+	securityCfg.ApplyToHostConfig(hostConfig)
+
+	log := b.log.WithField("packages", packages)
+	log.Info("Building package cache wheels")
+
+	buildCtx, cancel := context.WithTimeout(ctx, packageBuildTimeout+5*time.Second)
+	defer cancel()
+
+	resp, err := b.client.ContainerCreate(buildCtx, containerConfig, hostConfig, nil, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("creating build container: %w", err)
+	}
+
+	defer func() {
+		if err := b.forceRemoveContainer(context.Background(), resp.ID); err != nil {
+			log.WithError(err).Warn("Failed to remove build container")
+		}
+	}()
+
+	if err := b.client.ContainerStart(buildCtx, resp.ID, container.StartOptions{}); err != nil {
+		return nil, fmt.Errorf("starting build container: %w", err)
+	}
+
+	result, err := b.waitForContainer(buildCtx, resp.ID, packageBuildTimeout)
+	if err != nil {
+		if killErr := b.forceKillContainer(context.Background(), resp.ID); killErr != nil {
+			log.WithError(killErr).Warn("Failed to kill build container")
+		}
+
+		return nil, fmt.Errorf("building wheels: %w", err)
+	}
+
+	buildLog := result.stdout + result.stderr
+	if result.exitCode != 0 {
+		return nil, fmt.Errorf("pip wheel exited with code %d: %s", result.exitCode, buildLog)
+	}
+
+	added, err := b.packageCache.Import(staging)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := b.packageCache.Verify()
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("wheels", len(added)).Info("Package cache updated")
+
+	return &PackageBuildResult{Added: added, Log: buildLog, Report: report}, nil
+}
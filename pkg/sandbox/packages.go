@@ -0,0 +1,142 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// packagesUserBase is the pip user base requested packages are installed
+	// into inside session containers. Python adds its site-packages to
+	// sys.path through PYTHONUSERBASE.
+	packagesUserBase = "/tmp/panda-packages"
+
+	// packageInstallTimeout bounds installing an execution's packages.
+	packageInstallTimeout = 5 * time.Minute
+
+	// maxInstallLogBytes bounds the pip output included in install errors.
+	maxInstallLogBytes = 4096
+)
+
+// ErrPackagesNeedSession is returned when packages are requested for an
+// execution that doesn't run in a session.
+var ErrPackagesNeedSession = errors.New("installing packages requires sessions; enable sandbox.sessions")
+
+// packageNameSeparators matches the runs of separators PEP 503
+// normalization collapses.
+var packageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePackageName returns the PEP 503 normalized form of a project
+// name, so "Scikit_Learn" and "scikit-learn" compare equal.
+func NormalizePackageName(name string) string {
+	return strings.ToLower(packageNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// PackageName validates a pip requirement specifier such as "scipy>=1.11"
+// and returns its normalized project name.
+func PackageName(spec string) (string, error) {
+	if !packageSpecPattern.MatchString(spec) {
+		return "", fmt.Errorf("invalid package specifier %q", spec)
+	}
+
+	name := spec
+	if i := strings.IndexAny(name, "[<>=!~"); i >= 0 {
+		name = name[:i]
+	}
+
+	return NormalizePackageName(name), nil
+}
+
+// installSessionPackages pip installs packages into the session's user
+// site. Packages already installed are left as they are, so requesting the
+// same packages on every execution is cheap.
+func (b *DockerBackend) installSessionPackages(ctx context.Context, session *Session, packages []string) error {
+	for _, pkg := range packages {
+		if _, err := PackageName(pkg); err != nil {
+			return err
+		}
+	}
+
+	cmd := []string{
+		"pip", "install", "--user", "--quiet",
+		"--disable-pip-version-check", "--no-warn-script-location",
+	}
+	if b.cfg.Packages.CacheOnly {
+		cmd = append(cmd, "--no-index")
+	}
+
+	cmd = append(cmd, "--")
+	cmd = append(cmd, packages...)
+
+	installCtx, cancel := context.WithTimeout(ctx, packageInstallTimeout)
+	defer cancel()
+
+	log := b.log.WithField("session_id", session.ID).WithField("packages", packages)
+	log.Debug("Installing session packages")
+
+	execResp, err := b.client.ContainerExecCreate(installCtx, session.ContainerID, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          []string{"HOME=/tmp", "PYTHONUSERBASE=" + packagesUserBase},
+	})
+	if err != nil {
+		return fmt.Errorf("creating install exec: %w", err)
+	}
+
+	attachResp, err := b.client.ContainerExecAttach(installCtx, execResp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("attaching to install exec: %w", err)
+	}
+	defer attachResp.Close()
+
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, attachResp.Reader); err != nil {
+		if installCtx.Err() != nil {
+			return fmt.Errorf("installing packages timed out after %s", packageInstallTimeout)
+		}
+
+		return fmt.Errorf("reading install output: %w", err)
+	}
+
+	inspectResp, err := b.client.ContainerExecInspect(ctx, execResp.ID)
+	if err != nil {
+		return fmt.Errorf("inspecting install exec: %w", err)
+	}
+
+	if inspectResp.ExitCode != 0 {
+		installLog := output.String()
+		if len(installLog) > maxInstallLogBytes {
+			installLog = "..." + installLog[len(installLog)-maxInstallLogBytes:]
+		}
+
+		return fmt.Errorf("pip install exited with code %d: %s", inspectResp.ExitCode, strings.TrimSpace(installLog))
+	}
+
+	log.Info("Installed session packages")
+
+	return nil
+}
+
+// prewarmPackages builds wheels for the allowed packages into the package
+// cache so sessions install them without reaching a package index.
+func (b *DockerBackend) prewarmPackages(ctx context.Context) {
+	packages := b.cfg.Packages.Allowed
+
+	result, err := b.BuildPackageCache(ctx, packages)
+	if err != nil {
+		b.log.WithError(err).WithField("packages", packages).Warn("Failed to prewarm package cache")
+
+		return
+	}
+
+	b.log.WithField("wheels", len(result.Added)).Info("Prewarmed package cache")
+}
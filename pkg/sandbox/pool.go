@@ -0,0 +1,356 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+const (
+	// LabelPool marks containers created for the warm pool. A pool container
+	// gets its session ID when it is created; claiming it renames it to
+	// record the owner and claim time, since labels can't be changed.
+	LabelPool = "io.ethpandaops-panda.pool"
+
+	// poolNamePrefix names unclaimed pool containers.
+	poolNamePrefix = "panda-pool-"
+
+	// claimedNamePrefix names claimed pool containers as
+	// panda-session-<session>.<owner>.<claimed unix time>.
+	claimedNamePrefix = "panda-session-"
+
+	// poolCheckInterval is how often the pool is topped up and idle
+	// containers are recycled.
+	poolCheckInterval = 30 * time.Second
+
+	// poolCreateTimeout bounds starting one pool container.
+	poolCreateTimeout = 2 * time.Minute
+)
+
+// poolOwnerPattern matches owner IDs that can be encoded in a container name.
+var poolOwnerPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// pooledContainer is a warm container waiting to be claimed.
+type pooledContainer struct {
+	containerID string
+	sessionID   string
+	createdAt   time.Time
+}
+
+// containerPool keeps started session containers with the default profile
+// ready, so a new session skips container start-up.
+type containerPool struct {
+	backend *DockerBackend
+	cfg     config.SandboxPoolConfig
+	log     logrus.FieldLogger
+
+	mu    sync.Mutex
+	ready []pooledContainer
+
+	refill chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newContainerPool(b *DockerBackend) *containerPool {
+	return &containerPool{
+		backend: b,
+		cfg:     b.cfg.Pool,
+		log:     b.log.WithField("component", "sandbox.pool"),
+		ready:   make([]pooledContainer, 0, b.cfg.Pool.Size),
+		refill:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// start removes pool containers left over by a previous run and starts
+// filling the pool in the background.
+func (p *containerPool) start(ctx context.Context) {
+	p.removeStale(ctx)
+
+	p.wg.Add(1)
+
+	go p.run()
+
+	p.log.WithField("size", p.cfg.Size).Info("Started warm container pool")
+}
+
+// stop stops the refill loop and removes the containers nobody claimed.
+func (p *containerPool) stop(ctx context.Context) {
+	close(p.done)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	ready := p.ready
+	p.ready = nil
+	p.mu.Unlock()
+
+	for _, c := range ready {
+		p.remove(ctx, c)
+	}
+
+	observability.SandboxPoolReady.Set(0)
+}
+
+// claim hands a warm container to ownerID as a new session. It reports
+// false when the pool is empty, in which case the caller starts a
+// container itself.
+func (p *containerPool) claim(ctx context.Context, ownerID string) (pooledContainer, bool) {
+	if !poolOwnerPattern.MatchString(ownerID) {
+		return pooledContainer{}, false
+	}
+
+	defer p.signalRefill()
+
+	for {
+		p.mu.Lock()
+
+		if len(p.ready) == 0 {
+			p.mu.Unlock()
+			observability.SandboxPoolClaimsTotal.WithLabelValues("miss").Inc()
+
+			return pooledContainer{}, false
+		}
+
+		c := p.ready[0]
+		p.ready = p.ready[1:]
+		observability.SandboxPoolReady.Set(float64(len(p.ready)))
+		p.mu.Unlock()
+
+		// Renaming is atomic in Docker, so the name is the durable record of
+		// who claimed the container and when.
+		name := claimedContainerName(c.sessionID, ownerID, time.Now())
+		if err := p.backend.client.ContainerRename(ctx, c.containerID, name); err != nil {
+			p.log.WithError(err).WithField("session_id", c.sessionID).Warn("Failed to claim pool container")
+			p.remove(ctx, c)
+
+			continue
+		}
+
+		observability.SandboxPoolClaimsTotal.WithLabelValues("hit").Inc()
+
+		return c, true
+	}
+}
+
+func (p *containerPool) signalRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (p *containerPool) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(poolCheckInterval)
+	defer ticker.Stop()
+
+	p.maintain()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		case <-p.refill:
+		}
+
+		p.maintain()
+	}
+}
+
+// maintain replaces containers idle longer than max_idle and tops the pool
+// back up to its size.
+func (p *containerPool) maintain() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	p.mu.Lock()
+
+	var expired []pooledContainer
+
+	fresh := p.ready[:0]
+	for _, c := range p.ready {
+		if time.Since(c.createdAt) > p.cfg.MaxIdle {
+			expired = append(expired, c)
+		} else {
+			fresh = append(fresh, c)
+		}
+	}
+
+	p.ready = fresh
+	missing := p.cfg.Size - len(p.ready)
+	p.mu.Unlock()
+
+	for _, c := range expired {
+		p.remove(ctx, c)
+		observability.SandboxPoolRecycledTotal.Inc()
+	}
+
+	for range missing {
+		if ctx.Err() != nil {
+			return
+		}
+
+		c, err := p.create(ctx)
+		if err != nil {
+			p.log.WithError(err).Warn("Failed to start pool container")
+
+			return
+		}
+
+		p.mu.Lock()
+		p.ready = append(p.ready, c)
+		observability.SandboxPoolReady.Set(float64(len(p.ready)))
+		p.mu.Unlock()
+	}
+}
+
+// create starts a pool container with the default profile. Only the
+// standard sandbox env is baked in; credentials are passed per execution.
+func (p *containerPool) create(ctx context.Context) (pooledContainer, error) {
+	b := p.backend
+
+	profile, err := b.cfg.Profile("")
+	if err != nil {
+		return pooledContainer{}, err
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, poolCreateTimeout)
+	defer cancel()
+
+	sessionID := b.sessionManager.GenerateSessionID()
+	now := time.Now()
+
+	labels := map[string]string{
+		LabelManaged:   "true",
+		LabelSessionID: sessionID,
+		LabelCreatedAt: strconv.FormatInt(now.Unix(), 10),
+		LabelPool:      "true",
+	}
+
+	if b.cfg.Instance != "" {
+		labels[LabelInstance] = b.cfg.Instance
+	}
+
+	env := make([]string, 0, 8)
+	for k, v := range SandboxEnvDefaults() {
+		env = append(env, k+"="+v)
+	}
+
+	containerID, err := b.startSessionContainer(createCtx, poolNamePrefix+sessionID, labels, env, profile)
+	if err != nil {
+		return pooledContainer{}, fmt.Errorf("starting container: %w", err)
+	}
+
+	return pooledContainer{containerID: containerID, sessionID: sessionID, createdAt: now}, nil
+}
+
+func (p *containerPool) remove(ctx context.Context, c pooledContainer) {
+	if err := p.backend.forceRemoveContainer(ctx, c.containerID); err != nil {
+		p.log.WithError(err).WithField("session_id", c.sessionID).Warn("Failed to remove pool container")
+	}
+}
+
+// removeStale removes unclaimed pool containers this instance created
+// before a restart; their sessions were never handed out.
+func (p *containerPool) removeStale(ctx context.Context) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", LabelManaged+"=true")
+	filterArgs.Add("label", LabelPool)
+
+	if p.backend.cfg.Instance != "" {
+		filterArgs.Add("label", LabelInstance+"="+p.backend.cfg.Instance)
+	}
+
+	containers, err := p.backend.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filterArgs,
+	})
+	if err != nil {
+		p.log.WithError(err).Warn("Failed to list stale pool containers")
+
+		return
+	}
+
+	for _, c := range containers {
+		if c.Labels[LabelInstance] != p.backend.cfg.Instance || !isUnclaimedPoolContainer(c) {
+			continue
+		}
+
+		p.remove(ctx, pooledContainer{containerID: c.ID, sessionID: c.Labels[LabelSessionID]})
+	}
+}
+
+func claimedContainerName(sessionID, ownerID string, claimedAt time.Time) string {
+	return fmt.Sprintf("%s%s.%s.%d", claimedNamePrefix, sessionID, ownerID, claimedAt.Unix())
+}
+
+func containerName(c container.Summary) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// isUnclaimedPoolContainer reports whether c is a pool container still
+// waiting to be claimed.
+func isUnclaimedPoolContainer(c container.Summary) bool {
+	return c.Labels[LabelPool] != "" && !strings.HasPrefix(containerName(c), claimedNamePrefix)
+}
+
+// sessionContainerFromSummary builds a SessionContainer from a listed
+// container. Owner and creation time of a claimed pool container come from
+// its name. It returns nil for unclaimed pool containers.
+func sessionContainerFromSummary(c container.Summary) *SessionContainer {
+	sc := &SessionContainer{
+		ContainerID: c.ID,
+		SessionID:   c.Labels[LabelSessionID],
+		OwnerID:     c.Labels[LabelOwnerID],
+		Profile:     c.Labels[LabelProfile],
+		CreatedAt:   parseContainerCreatedAt(c.Labels, c.Created),
+	}
+
+	if c.Labels[LabelPool] == "" {
+		return sc
+	}
+
+	rest, ok := strings.CutPrefix(containerName(c), claimedNamePrefix)
+	if !ok {
+		return nil
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 || parts[0] != sc.SessionID {
+		return nil
+	}
+
+	sc.OwnerID = parts[1]
+
+	if claimedUnix, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
+		sc.CreatedAt = time.Unix(claimedUnix, 0)
+	}
+
+	return sc
+}
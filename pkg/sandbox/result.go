@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MaxResultSize is the largest structured result an execution can return
+// with ethpandaops.result().
+const MaxResultSize = 1 << 20
+
+// ResultPath returns the in-sandbox path ethpandaops.result() writes an
+// execution's structured result to. The file is hidden, so it is not listed
+// as an output file.
+func ResultPath(executionID string) string {
+	return fmt.Sprintf("/output/.result-%s.json", executionID)
+}
+
+// parseResult validates a structured result file's contents.
+func parseResult(data []byte) (json.RawMessage, error) {
+	if len(data) > MaxResultSize {
+		return nil, fmt.Errorf("result exceeds maximum size of %d bytes", MaxResultSize)
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("result is not valid JSON")
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// readResult reads an ephemeral execution's structured result file if present.
+func (b *DockerBackend) readResult(resultPath string) json.RawMessage {
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		return nil
+	}
+
+	result, err := parseResult(data)
+	if err != nil {
+		b.log.WithError(err).Warn("Ignoring execution result")
+
+		return nil
+	}
+
+	return result
+}
+
+// readSessionResult reads and parses the structured result file a session
+// execution wrote, if any.
+func (b *DockerBackend) readSessionResult(ctx context.Context, containerID, resultPath string) json.RawMessage {
+	reader, stat, err := b.client.CopyFromContainer(ctx, containerID, resultPath)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = reader.Close() }()
+
+	if !stat.Mode.IsRegular() || stat.Size > MaxResultSize {
+		b.log.WithField("size", stat.Size).Warn("Ignoring execution result")
+
+		return nil
+	}
+
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(tr, MaxResultSize+1))
+	if err != nil {
+		return nil
+	}
+
+	result, err := parseResult(data)
+	if err != nil {
+		b.log.WithError(err).Warn("Ignoring execution result")
+
+		return nil
+	}
+
+	return result
+}
@@ -3,14 +3,21 @@ package sandbox
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
+// ErrExecutionTimeout is returned when code execution exceeds its timeout.
+var ErrExecutionTimeout = errors.New("execution timed out")
+
 // Service defines the interface for sandbox code execution backends.
 type Service interface {
 	// Start initializes the sandbox backend (e.g., connecting to Docker).
@@ -36,6 +43,15 @@ type Service interface {
 	CanCreateSession(ctx context.Context, ownerID string) (bool, int, int)
 	// SessionsEnabled returns whether sessions are enabled.
 	SessionsEnabled() bool
+	// WriteSessionFile writes data to a path under the session's /workspace directory.
+	// If ownerID is non-empty, verifies ownership first.
+	WriteSessionFile(ctx context.Context, sessionID, ownerID, name string, data []byte) error
+	// ReadSessionFile reads a file under the session's /workspace directory.
+	// If ownerID is non-empty, verifies ownership first.
+	ReadSessionFile(ctx context.Context, sessionID, ownerID, name string) ([]byte, error)
+	// RestoreSessionAccess seeds a session's last-used time from metadata
+	// saved before a restart, so its idle TTL counts from then.
+	RestoreSessionAccess(sessionID string, lastUsed time.Time)
 }
 
 // ExecuteRequest contains the parameters for code execution.
@@ -52,6 +68,16 @@ type ExecuteRequest struct {
 	// OwnerID is the GitHub user ID that owns the session.
 	// Required for session creation and verification.
 	OwnerID string
+	// Profile names a configured execution profile to size the container
+	// with. If empty, the top-level sandbox settings are used.
+	Profile string
+	// Packages are pip requirement specifiers installed into the session
+	// before the code runs. The caller enforces sandbox.packages policy.
+	Packages []string
+	// Stdout and Stderr, if set, receive output live as the code runs.
+	// The complete output is still returned in ExecutionResult.
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // ExecutionResult contains the output from code execution.
@@ -68,8 +94,23 @@ type ExecutionResult struct {
 	OutputFiles []string
 	// Metrics contains any metrics reported by the executed code.
 	Metrics map[string]any
+	// Result is the JSON value the code passed to ethpandaops.result(), nil
+	// when it reported none.
+	Result json.RawMessage
 	// DurationSeconds is the wall-clock execution time.
 	DurationSeconds float64
+	// Usage is the resources the execution consumed, nil when the backend
+	// couldn't measure them.
+	Usage *ResourceUsage
+	// Hints are suggestions for known errors in the output, added by the
+	// execution service from module error signatures.
+	Hints []types.ErrorHint
+	// RateLimit is the state reported by the last proxy rate limit rejection
+	// in the output, nil when the execution was not rate limited.
+	RateLimit *types.RateLimitState
+	// Artifacts are charts the execution saved to its workspace, uploaded
+	// to storage by the execution service.
+	Artifacts []Artifact
 
 	// Session-related fields (only populated when sessions are enabled).
 	// SessionID is the session identifier. Can be used to reuse this session.
@@ -87,6 +128,15 @@ type SessionFile struct {
 	Modified time.Time `json:"modified"`
 }
 
+// Artifact is a workspace file uploaded to storage after an execution.
+type Artifact struct {
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+}
+
 // SessionInfo represents information about an active session.
 type SessionInfo struct {
 	ID             string        `json:"session_id"`
@@ -96,6 +146,20 @@ type SessionInfo struct {
 	WorkspaceFiles []SessionFile `json:"workspace_files"`
 }
 
+// GPUStatus reports GPU execution capacity.
+type GPUStatus struct {
+	// Profiles lists the execution profiles that request GPUs.
+	Profiles      []string `json:"profiles"`
+	MaxConcurrent int      `json:"max_concurrent"`
+	InUse         int      `json:"in_use"`
+	Available     int      `json:"available"`
+}
+
+// GPUReporter is implemented by backends that schedule GPU executions.
+type GPUReporter interface {
+	GPUStatus() GPUStatus
+}
+
 // BackendType represents the available sandbox backend types.
 type BackendType string
 
@@ -104,6 +168,9 @@ const (
 	BackendDocker BackendType = "docker"
 	// BackendGVisor uses Docker with gVisor runtime for enhanced isolation.
 	BackendGVisor BackendType = "gvisor"
+	// BackendFirecracker uses Docker with a Kata Containers Firecracker runtime,
+	// running each container in its own microVM.
+	BackendFirecracker BackendType = "firecracker"
 )
 
 // New creates a new sandbox service based on the configuration.
@@ -115,6 +182,8 @@ func New(cfg config.SandboxConfig, log logrus.FieldLogger) (Service, error) {
 		return NewDockerBackend(cfg, log)
 	case BackendGVisor:
 		return NewGVisorBackend(cfg, log)
+	case BackendFirecracker:
+		return NewFirecrackerBackend(cfg, log)
 	default:
 		return nil, fmt.Errorf("unsupported sandbox backend: %s", cfg.Backend)
 	}
@@ -124,4 +193,8 @@ func New(cfg config.SandboxConfig, log logrus.FieldLogger) (Service, error) {
 var (
 	_ Service = (*DockerBackend)(nil)
 	_ Service = (*GVisorBackend)(nil)
+	_ Service = (*FirecrackerBackend)(nil)
+
+	_ GPUReporter   = (*DockerBackend)(nil)
+	_ PackageCacher = (*DockerBackend)(nil)
 )
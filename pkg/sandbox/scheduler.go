@@ -0,0 +1,195 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+// ErrQueueFull is returned when an execution can't start yet and the
+// scheduler's queue is already full.
+var ErrQueueFull = errors.New("too many executions are queued; try again later")
+
+// Scheduler caps how many executions run at once, globally and per owner.
+// Executions over a limit wait in a first-in, first-out queue; a queued
+// execution is skipped over only while its own owner is at their limit.
+type Scheduler struct {
+	cfg config.SandboxSchedulerConfig
+
+	mu       sync.Mutex
+	running  int
+	perOwner map[string]int
+	queue    []*schedulerWaiter
+}
+
+type schedulerWaiter struct {
+	ownerID string
+	// admitted is closed once the waiter holds a slot.
+	admitted chan struct{}
+	// moved is signalled when the waiter's queue position changes.
+	moved chan struct{}
+}
+
+// NewScheduler creates a scheduler enforcing cfg. A zero limit is unlimited.
+func NewScheduler(cfg config.SandboxSchedulerConfig) *Scheduler {
+	return &Scheduler{
+		cfg:      cfg,
+		perOwner: make(map[string]int, 16),
+	}
+}
+
+// Acquire waits for an execution slot for ownerID. Per-owner limits apply
+// only to authenticated owners. While the execution is queued, onQueued (if
+// set) is called with its 1-based queue position whenever it changes, and
+// with 0 once the execution leaves the queue to run. The returned func
+// releases the slot.
+func (s *Scheduler) Acquire(ctx context.Context, ownerID string, onQueued func(position int)) (func(), error) {
+	s.mu.Lock()
+
+	// Waiters still queued are blocked by a limit that either blocks this
+	// execution too or is their own owner's, so running now is fair.
+	if s.canRunLocked(ownerID) {
+		s.startLocked(ownerID)
+		s.mu.Unlock()
+
+		return s.releaseFunc(ownerID), nil
+	}
+
+	if len(s.queue) >= s.cfg.MaxQueue {
+		s.mu.Unlock()
+		observability.SandboxSchedulerRejectedTotal.Inc()
+
+		return nil, ErrQueueFull
+	}
+
+	w := &schedulerWaiter{
+		ownerID:  ownerID,
+		admitted: make(chan struct{}),
+		moved:    make(chan struct{}, 1),
+	}
+	s.queue = append(s.queue, w)
+	position := len(s.queue)
+	observability.SandboxSchedulerQueued.Set(float64(position))
+	s.mu.Unlock()
+
+	report := func(position int) {
+		if onQueued != nil {
+			onQueued(position)
+		}
+	}
+
+	report(position)
+
+	for {
+		select {
+		case <-w.admitted:
+			report(0)
+
+			return s.releaseFunc(ownerID), nil
+		case <-w.moved:
+			s.mu.Lock()
+			current := slices.Index(s.queue, w) + 1
+			s.mu.Unlock()
+
+			// Zero means it was just admitted; the admitted case reports it.
+			if current != 0 && current != position {
+				position = current
+				report(position)
+			}
+		case <-ctx.Done():
+			s.mu.Lock()
+
+			if i := slices.Index(s.queue, w); i >= 0 {
+				s.queue = slices.Delete(s.queue, i, i+1)
+				s.notifyMovedLocked(i)
+				s.mu.Unlock()
+			} else {
+				// Admitted while giving up; hand the slot on.
+				s.mu.Unlock()
+				s.releaseFunc(ownerID)()
+			}
+
+			return nil, fmt.Errorf("waiting for an execution slot: %w", ctx.Err())
+		}
+	}
+}
+
+func (s *Scheduler) canRunLocked(ownerID string) bool {
+	if s.cfg.MaxConcurrent > 0 && s.running >= s.cfg.MaxConcurrent {
+		return false
+	}
+
+	return ownerID == "" || s.cfg.MaxPerOwner == 0 || s.perOwner[ownerID] < s.cfg.MaxPerOwner
+}
+
+func (s *Scheduler) startLocked(ownerID string) {
+	s.running++
+
+	if ownerID != "" {
+		s.perOwner[ownerID]++
+	}
+}
+
+func (s *Scheduler) releaseFunc(ownerID string) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.running--
+
+			if ownerID != "" {
+				if s.perOwner[ownerID]--; s.perOwner[ownerID] <= 0 {
+					delete(s.perOwner, ownerID)
+				}
+			}
+
+			s.dispatchLocked()
+		})
+	}
+}
+
+// dispatchLocked admits queued waiters, oldest first, while slots allow.
+func (s *Scheduler) dispatchLocked() {
+	first := -1
+
+	for i := 0; i < len(s.queue); {
+		w := s.queue[i]
+		if !s.canRunLocked(w.ownerID) {
+			i++
+
+			continue
+		}
+
+		s.startLocked(w.ownerID)
+		close(w.admitted)
+		s.queue = slices.Delete(s.queue, i, i+1)
+
+		if first < 0 {
+			first = i
+		}
+	}
+
+	if first >= 0 {
+		s.notifyMovedLocked(first)
+	}
+}
+
+// notifyMovedLocked tells waiters from index i on that they moved up.
+func (s *Scheduler) notifyMovedLocked(i int) {
+	for _, w := range s.queue[i:] {
+		select {
+		case w.moved <- struct{}{}:
+		default:
+		}
+	}
+
+	observability.SandboxSchedulerQueued.Set(float64(len(s.queue)))
+}
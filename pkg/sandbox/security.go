@@ -28,7 +28,8 @@ type SecurityConfig struct {
 	CPUPeriod int64
 	// TmpfsSize is the size of the /tmp tmpfs mount.
 	TmpfsSize string
-	// Runtime specifies the container runtime (e.g., "" for default, "runsc" for gVisor).
+	// Runtime specifies the container runtime (e.g., "" for default, "runsc" for gVisor,
+	// "kata-fc" for Firecracker).
 	Runtime string
 }
 
@@ -71,6 +72,22 @@ func GVisorSecurityConfig(memoryLimit string, cpuLimit float64) (*SecurityConfig
 	return cfg, nil
 }
 
+// FirecrackerSecurityConfig returns a SecurityConfigFunc for Firecracker microVM
+// execution via the given Kata Containers runtime (e.g. "kata-fc").
+// Each container runs inside its own lightweight VM with a dedicated guest kernel.
+func FirecrackerSecurityConfig(runtime string) SecurityConfigFunc {
+	return func(memoryLimit string, cpuLimit float64) (*SecurityConfig, error) {
+		cfg, err := DefaultSecurityConfig(memoryLimit, cpuLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Runtime = runtime
+
+		return cfg, nil
+	}
+}
+
 // ApplyToHostConfig applies security settings to a Docker HostConfig.
 func (s *SecurityConfig) ApplyToHostConfig(hostConfig *container.HostConfig) {
 	// Resource limits.
@@ -84,7 +101,7 @@ func (s *SecurityConfig) ApplyToHostConfig(hostConfig *container.HostConfig) {
 	hostConfig.SecurityOpt = s.SecurityOpts
 	hostConfig.CapDrop = s.DropCapabilities
 
-	// Runtime (for gVisor).
+	// Runtime (for gVisor and Firecracker).
 	if s.Runtime != "" {
 		hostConfig.Runtime = s.Runtime
 	}
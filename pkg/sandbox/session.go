@@ -2,6 +2,7 @@ package sandbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -13,11 +14,22 @@ import (
 	"github.com/ethpandaops/panda/pkg/config"
 )
 
+// Session lookup errors. They describe a bad session ID or profile in the
+// request rather than a sandbox failure.
+var (
+	ErrSessionsDisabled    = errors.New("sessions are disabled")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionNotOwned     = errors.New("session not owned by caller")
+	ErrSessionExpired      = errors.New("session has expired")
+	ErrSessionProfileFixed = errors.New("session was created with a different profile")
+)
+
 // Session represents a persistent sandbox execution environment.
 // This is a transient view constructed from container state, not stored in memory.
 type Session struct {
 	ID          string
 	OwnerID     string // Optional owner ID for session binding
+	Profile     string // Execution profile the container was created with
 	ContainerID string
 	CreatedAt   time.Time
 	LastUsed    time.Time
@@ -29,6 +41,7 @@ type SessionContainer struct {
 	ContainerID string
 	SessionID   string
 	OwnerID     string
+	Profile     string
 	CreatedAt   time.Time
 }
 
@@ -102,7 +115,8 @@ func (m *SessionManager) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop terminates the cleanup goroutine and destroys all active sessions.
+// Stop terminates the cleanup goroutine and destroys all active sessions,
+// unless sessions are kept on shutdown.
 func (m *SessionManager) Stop(ctx context.Context) error {
 	if !m.cfg.IsEnabled() {
 		return nil
@@ -113,20 +127,10 @@ func (m *SessionManager) Stop(ctx context.Context) error {
 	close(m.done)
 	m.wg.Wait()
 
-	// Query all session containers and clean them up.
-	containers, err := m.containerListAll(ctx)
-	if err != nil {
-		m.log.WithError(err).Warn("Failed to list session containers during shutdown")
+	if m.cfg.KeepOnShutdown {
+		m.log.Info("Leaving session containers running for the next server instance")
 	} else {
-		for _, c := range containers {
-			if err := m.cleanupCallback(ctx, c.ContainerID); err != nil {
-				m.log.WithFields(logrus.Fields{
-					"session_id":   c.SessionID,
-					"container_id": c.ContainerID,
-					"error":        err,
-				}).Warn("Failed to cleanup session during shutdown")
-			}
-		}
+		m.destroyAll(ctx)
 	}
 
 	// Clear state maps.
@@ -140,6 +144,26 @@ func (m *SessionManager) Stop(ctx context.Context) error {
 	return nil
 }
 
+// destroyAll removes every session container.
+func (m *SessionManager) destroyAll(ctx context.Context) {
+	containers, err := m.containerListAll(ctx)
+	if err != nil {
+		m.log.WithError(err).Warn("Failed to list session containers during shutdown")
+
+		return
+	}
+
+	for _, c := range containers {
+		if err := m.cleanupCallback(ctx, c.ContainerID); err != nil {
+			m.log.WithFields(logrus.Fields{
+				"session_id":   c.SessionID,
+				"container_id": c.ContainerID,
+				"error":        err,
+			}).Warn("Failed to cleanup session during shutdown")
+		}
+	}
+}
+
 // GenerateSessionID creates a new session ID.
 // The caller is responsible for setting this on the container label.
 func (m *SessionManager) GenerateSessionID() string {
@@ -154,6 +178,17 @@ func (m *SessionManager) RecordAccess(sessionID string) {
 	m.lastUsed[sessionID] = time.Now()
 }
 
+// RestoreAccess seeds a session's last access time, e.g. from metadata saved
+// before a restart, unless the session has been used since.
+func (m *SessionManager) RestoreAccess(sessionID string, lastUsed time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.lastUsed[sessionID]; !ok {
+		m.lastUsed[sessionID] = lastUsed
+	}
+}
+
 // markExecuting increments the active execution count for a session.
 // Sessions with active executions are protected from TTL-based purging.
 func (m *SessionManager) markExecuting(sessionID string) {
@@ -193,7 +228,7 @@ func (m *SessionManager) ActiveSessionCount(ctx context.Context) int {
 // Session state is queried from Docker; only lastUsed is tracked in memory.
 func (m *SessionManager) Get(ctx context.Context, sessionID string, ownerID string) (*Session, error) {
 	if !m.cfg.IsEnabled() {
-		return nil, fmt.Errorf("sessions are disabled")
+		return nil, ErrSessionsDisabled
 	}
 
 	// Query Docker for the session container.
@@ -203,12 +238,12 @@ func (m *SessionManager) Get(ctx context.Context, sessionID string, ownerID stri
 	}
 
 	if container == nil {
-		return nil, fmt.Errorf("session %s not found", sessionID)
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
 	}
 
 	// Verify ownership if ownerID is provided.
 	if ownerID != "" && container.OwnerID != "" && container.OwnerID != ownerID {
-		return nil, fmt.Errorf("session %s not owned by caller", sessionID)
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotOwned, sessionID)
 	}
 
 	// Check if session has exceeded max duration.
@@ -239,6 +274,7 @@ func (m *SessionManager) Get(ctx context.Context, sessionID string, ownerID stri
 	session := &Session{
 		ID:          container.SessionID,
 		OwnerID:     container.OwnerID,
+		Profile:     container.Profile,
 		ContainerID: container.ContainerID,
 		CreatedAt:   container.CreatedAt,
 		LastUsed:    now,
@@ -284,7 +320,7 @@ func (m *SessionManager) expireSession(sessionID, containerID, reason string) er
 	delete(m.lastUsed, sessionID)
 	m.mu.Unlock()
 
-	return fmt.Errorf("session %s has expired (%s)", sessionID, reason)
+	return fmt.Errorf("%w: %s (%s)", ErrSessionExpired, sessionID, reason)
 }
 
 // Destroy removes a session and triggers cleanup callback.
@@ -297,12 +333,12 @@ func (m *SessionManager) Destroy(ctx context.Context, sessionID, ownerID string)
 	}
 
 	if container == nil {
-		return fmt.Errorf("session %s not found", sessionID)
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
 	}
 
 	// Verify ownership if ownerID is provided.
 	if ownerID != "" && container.OwnerID != "" && container.OwnerID != ownerID {
-		return fmt.Errorf("session %s not owned by caller", sessionID)
+		return fmt.Errorf("%w: %s", ErrSessionNotOwned, sessionID)
 	}
 
 	// Remove from lastUsed map.
@@ -0,0 +1,52 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// streaming reports whether the request asked for live output.
+func (r ExecuteRequest) streaming() bool {
+	return r.Stdout != nil || r.Stderr != nil
+}
+
+// teeWriter returns a writer that writes to buf and, if set, to live.
+func teeWriter(buf io.Writer, live io.Writer) io.Writer {
+	if live == nil {
+		return buf
+	}
+
+	return io.MultiWriter(buf, live)
+}
+
+// followContainerLogs copies a running container's output to the request's
+// live writers until the container exits or ctx is cancelled. The returned
+// channel is closed once copying has stopped.
+func (b *DockerBackend) followContainerLogs(ctx context.Context, containerID string, req ExecuteRequest) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		logReader, err := b.client.ContainerLogs(ctx, containerID, container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     true,
+		})
+		if err != nil {
+			b.log.WithError(err).Debug("Failed to follow container logs")
+
+			return
+		}
+		defer func() { _ = logReader.Close() }()
+
+		if _, err := stdcopy.StdCopy(teeWriter(io.Discard, req.Stdout), teeWriter(io.Discard, req.Stderr), logReader); err != nil && ctx.Err() == nil {
+			b.log.WithError(err).Debug("Error streaming container logs")
+		}
+	}()
+
+	return done
+}
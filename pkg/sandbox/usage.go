@@ -0,0 +1,210 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ResourceUsage is what an execution consumed, from the container's cgroup
+// stats as reported by Docker. Session executions report the change across
+// the execution; memory is the container's peak while it ran.
+type ResourceUsage struct {
+	PeakMemoryBytes uint64  `json:"peak_memory_bytes"`
+	CPUSeconds      float64 `json:"cpu_seconds"`
+	ReadBytes       uint64  `json:"read_bytes"`
+	WrittenBytes    uint64  `json:"written_bytes"`
+	NetworkRxBytes  uint64  `json:"network_rx_bytes"`
+	NetworkTxBytes  uint64  `json:"network_tx_bytes"`
+}
+
+// String formats the usage for execution footers.
+func (u ResourceUsage) String() string {
+	return fmt.Sprintf("peak_mem=%s cpu=%.2fs read=%s written=%s net_rx=%s net_tx=%s",
+		formatBytes(u.PeakMemoryBytes), u.CPUSeconds, formatBytes(u.ReadBytes),
+		formatBytes(u.WrittenBytes), formatBytes(u.NetworkRxBytes), formatBytes(u.NetworkTxBytes))
+}
+
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// usageCounters are the cumulative counters in one stats sample.
+type usageCounters struct {
+	cpuNanos     uint64
+	readBytes    uint64
+	writtenBytes uint64
+	rxBytes      uint64
+	txBytes      uint64
+}
+
+// sampleUsage extracts the cumulative counters and working set memory from
+// a stats sample. Page cache that can be reclaimed is left out of memory,
+// as docker stats does.
+func sampleUsage(stats container.StatsResponse) (usageCounters, uint64) {
+	counters := usageCounters{cpuNanos: stats.CPUStats.CPUUsage.TotalUsage}
+
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			counters.readBytes += entry.Value
+		case "write":
+			counters.writtenBytes += entry.Value
+		}
+	}
+
+	for _, network := range stats.Networks {
+		counters.rxBytes += network.RxBytes
+		counters.txBytes += network.TxBytes
+	}
+
+	memory := stats.MemoryStats.Usage
+
+	// cgroup v2 reports inactive_file, v1 total_inactive_file.
+	inactive, ok := stats.MemoryStats.Stats["inactive_file"]
+	if !ok {
+		inactive = stats.MemoryStats.Stats["total_inactive_file"]
+	}
+
+	if inactive < memory {
+		memory -= inactive
+	}
+
+	return counters, memory
+}
+
+// usageSampler follows a container's stats while an execution runs.
+type usageSampler struct {
+	mu       sync.Mutex
+	baseline usageCounters
+	last     usageCounters
+	peak     uint64
+	sampled  bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startUsageSampler starts following containerID's stats. baseline is a
+// sample taken when the execution started, or nil for a container started
+// for the execution, whose counters start at zero.
+func (b *DockerBackend) startUsageSampler(
+	ctx context.Context,
+	containerID string,
+	baseline *container.StatsResponse,
+) *usageSampler {
+	sampleCtx, cancel := context.WithCancel(ctx)
+	s := &usageSampler{cancel: cancel, done: make(chan struct{})}
+
+	if baseline != nil {
+		s.baseline, _ = sampleUsage(*baseline)
+		s.record(*baseline)
+	}
+
+	go func() {
+		defer close(s.done)
+
+		resp, err := b.client.ContainerStats(sampleCtx, containerID, true)
+		if err != nil {
+			b.log.WithError(err).Debug("Failed to follow container stats")
+
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		decoder := json.NewDecoder(resp.Body)
+
+		for {
+			var stats container.StatsResponse
+			if err := decoder.Decode(&stats); err != nil {
+				return
+			}
+
+			// A stopped container reports empty stats.
+			if stats.Read.IsZero() || stats.PidsStats.Current == 0 {
+				continue
+			}
+
+			s.record(stats)
+		}
+	}()
+
+	return s
+}
+
+func (s *usageSampler) record(stats container.StatsResponse) {
+	counters, memory := sampleUsage(stats)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last = counters
+	s.peak = max(s.peak, memory)
+	s.sampled = true
+}
+
+// stop stops following stats and returns the execution's usage, or nil if
+// no sample was taken. It may be called more than once, and on nil.
+func (s *usageSampler) stop() *ResourceUsage {
+	if s == nil {
+		return nil
+	}
+
+	s.cancel()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.sampled {
+		return nil
+	}
+
+	delta := func(now, before uint64) uint64 {
+		if now < before {
+			return 0
+		}
+
+		return now - before
+	}
+
+	return &ResourceUsage{
+		PeakMemoryBytes: s.peak,
+		CPUSeconds:      float64(delta(s.last.cpuNanos, s.baseline.cpuNanos)) / 1e9,
+		ReadBytes:       delta(s.last.readBytes, s.baseline.readBytes),
+		WrittenBytes:    delta(s.last.writtenBytes, s.baseline.writtenBytes),
+		NetworkRxBytes:  delta(s.last.rxBytes, s.baseline.rxBytes),
+		NetworkTxBytes:  delta(s.last.txBytes, s.baseline.txBytes),
+	}
+}
+
+// containerUsage takes a single stats sample of a running container.
+func (b *DockerBackend) containerUsage(ctx context.Context, containerID string) (container.StatsResponse, error) {
+	var stats container.StatsResponse
+
+	resp, err := b.client.ContainerStatsOneShot(ctx, containerID)
+	if err != nil {
+		return stats, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+
+	return stats, err
+}
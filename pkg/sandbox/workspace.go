@@ -0,0 +1,183 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+)
+
+const (
+	// MaxWorkspaceFileSize is the largest file that can be written to or read
+	// from a session workspace in a single call.
+	MaxWorkspaceFileSize = 100 << 20
+
+	// workspaceDir is the session workspace directory inside session containers.
+	workspaceDir = "/workspace"
+
+	// nobodyID is the uid/gid of the "nobody" user sandbox code runs as.
+	nobodyID = 65534
+)
+
+// CleanWorkspacePath validates a path relative to the session workspace and
+// returns it in canonical form. Absolute paths and paths escaping the
+// workspace are rejected.
+func CleanWorkspacePath(name string) (string, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(name), workspaceDir+"/")
+	if trimmed == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	if strings.HasPrefix(trimmed, "/") {
+		return "", fmt.Errorf("path %q must be relative to %s", name, workspaceDir)
+	}
+
+	cleaned := path.Clean(trimmed)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("path %q escapes the workspace", name)
+	}
+
+	return cleaned, nil
+}
+
+// WriteSessionFile writes data to a path under the session's /workspace directory.
+// If ownerID is non-empty, verifies ownership first.
+func (b *DockerBackend) WriteSessionFile(ctx context.Context, sessionID, ownerID, name string, data []byte) error {
+	if b.client == nil {
+		return fmt.Errorf("docker client not initialized")
+	}
+
+	if len(data) > MaxWorkspaceFileSize {
+		return fmt.Errorf("file exceeds maximum size of %d bytes", MaxWorkspaceFileSize)
+	}
+
+	rel, err := CleanWorkspacePath(name)
+	if err != nil {
+		return err
+	}
+
+	session, err := b.sessionManager.Get(ctx, sessionID, ownerID)
+	if err != nil {
+		return fmt.Errorf("getting session: %w", err)
+	}
+
+	archive, err := workspaceArchive(rel, data)
+	if err != nil {
+		return fmt.Errorf("building archive: %w", err)
+	}
+
+	if err := b.client.CopyToContainer(
+		ctx, session.ContainerID, workspaceDir, archive, container.CopyToContainerOptions{},
+	); err != nil {
+		return fmt.Errorf("copying file into session: %w", err)
+	}
+
+	b.log.WithField("session_id", sessionID).WithField("path", rel).Debug("Wrote workspace file")
+
+	return nil
+}
+
+// ReadSessionFile reads a file under the session's /workspace directory.
+// If ownerID is non-empty, verifies ownership first.
+func (b *DockerBackend) ReadSessionFile(ctx context.Context, sessionID, ownerID, name string) ([]byte, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf("docker client not initialized")
+	}
+
+	rel, err := CleanWorkspacePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := b.sessionManager.Get(ctx, sessionID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("getting session: %w", err)
+	}
+
+	reader, stat, err := b.client.CopyFromContainer(ctx, session.ContainerID, path.Join(workspaceDir, rel))
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("file %s not found in session %s", rel, sessionID)
+		}
+
+		return nil, fmt.Errorf("copying file from session: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if !stat.Mode.IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", rel)
+	}
+
+	if stat.Size > MaxWorkspaceFileSize {
+		return nil, fmt.Errorf("file exceeds maximum size of %d bytes", MaxWorkspaceFileSize)
+	}
+
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(tr, MaxWorkspaceFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	return data, nil
+}
+
+// workspaceArchive builds a tar archive containing a single file at rel,
+// including entries for any parent directories so they are writable by the
+// sandbox user.
+func workspaceArchive(rel string, data []byte) (io.Reader, error) {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+
+	var dirs []string
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+
+	for _, dir := range dirs {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir + "/",
+			Mode:     0o777,
+			ModTime:  now,
+			Uid:      nobodyID,
+			Gid:      nobodyID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     rel,
+		Mode:     0o666,
+		Size:     int64(len(data)),
+		ModTime:  now,
+		Uid:      nobodyID,
+		Gid:      nobodyID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
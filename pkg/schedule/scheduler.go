@@ -0,0 +1,381 @@
+// Package schedule runs configured Python snippets and runbook checks on
+// cron schedules and keeps their recent results.
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/cronexpr"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+const (
+	// StorageNamespace is the storage namespace holding run results.
+	StorageNamespace = "schedules"
+
+	// OwnerID owns scheduled executions in history and session limits.
+	OwnerID = "scheduler"
+
+	// maxKeptOutput caps the stdout and stderr kept in memory per run. The
+	// full output is in the stored result.
+	maxKeptOutput = 16 << 10
+)
+
+var (
+	// ErrUnknownJob is returned for a job name that isn't configured.
+	ErrUnknownJob = errors.New("unknown scheduled job")
+
+	// ErrRunning is returned when a job is triggered while it runs.
+	ErrRunning = errors.New("scheduled job is already running")
+)
+
+// Executor runs code in the sandbox.
+type Executor interface {
+	Execute(ctx context.Context, req execsvc.ExecuteRequest) (*sandbox.ExecutionResult, error)
+}
+
+// Job is a scheduled execution.
+type Job struct {
+	Name     string
+	Schedule *cronexpr.Expr
+	// Source describes where the code comes from, such as "file:check.py"
+	// or "runbook:Investigate Finality Delay".
+	Source string
+	// Code returns the code to run. It is called on every run so edits to
+	// files and runbooks apply without a restart.
+	Code    func() (string, error)
+	Timeout int
+	Profile string
+}
+
+// Config configures the scheduler.
+type Config struct {
+	// KeepRuns is how many recent runs are kept per job.
+	KeepRuns int
+}
+
+// Run is the result of one scheduled execution.
+type Run struct {
+	Job             string          `json:"job"`
+	Trigger         string          `json:"trigger"`
+	StartedAt       time.Time       `json:"started_at"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	ExecutionID     string          `json:"execution_id,omitempty"`
+	ExitCode        int             `json:"exit_code"`
+	Error           string          `json:"error,omitempty"`
+	Stdout          string          `json:"stdout,omitempty"`
+	Stderr          string          `json:"stderr,omitempty"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	// URL locates the full result in storage.
+	URL string `json:"url,omitempty"`
+}
+
+// Status reports "success", "failure" (nonzero exit) or "error" (the code
+// couldn't be resolved or run).
+func (r Run) Status() string {
+	switch {
+	case r.Error != "":
+		return "error"
+	case r.ExitCode != 0:
+		return "failure"
+	default:
+		return "success"
+	}
+}
+
+// JobStatus describes a job and its latest run.
+type JobStatus struct {
+	Name    string    `json:"name"`
+	Cron    string    `json:"cron"`
+	Source  string    `json:"source"`
+	NextRun time.Time `json:"next_run"`
+	Running bool      `json:"running"`
+	LastRun *Run      `json:"last_run,omitempty"`
+}
+
+// Scheduler runs jobs on their schedules. Runs of one job never overlap:
+// a run still going when the next is due makes the scheduler skip ahead.
+type Scheduler struct {
+	log      logrus.FieldLogger
+	cfg      Config
+	jobs     map[string]Job
+	order    []string
+	executor Executor
+	storage  storage.Service
+	onFailed func(Run)
+
+	mu      sync.Mutex
+	runs    map[string][]Run
+	running map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a scheduler. storageSvc may be nil, in which case results are
+// only kept in memory.
+func New(log logrus.FieldLogger, cfg Config, jobs []Job, executor Executor, storageSvc storage.Service) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		log:      log.WithField("component", "scheduler"),
+		cfg:      cfg,
+		jobs:     make(map[string]Job, len(jobs)),
+		order:    make([]string, 0, len(jobs)),
+		executor: executor,
+		storage:  storageSvc,
+		runs:     make(map[string][]Run, len(jobs)),
+		running:  make(map[string]bool, len(jobs)),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	for _, job := range jobs {
+		s.jobs[job.Name] = job
+		s.order = append(s.order, job.Name)
+	}
+
+	return s
+}
+
+// SetFailureHook sets fn to be called with each run that doesn't succeed.
+func (s *Scheduler) SetFailureHook(fn func(Run)) {
+	s.onFailed = fn
+}
+
+// Start runs every job on its schedule in the background.
+func (s *Scheduler) Start() {
+	for _, name := range s.order {
+		job := s.jobs[name]
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			s.loop(job)
+		}()
+	}
+
+	s.log.WithField("jobs", len(s.order)).Info("Scheduler started")
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	for {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			s.log.WithField("job", job.Name).Warn("Scheduled job never runs")
+
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.run(s.ctx, job, "schedule"); errors.Is(err, ErrRunning) {
+			s.log.WithField("job", job.Name).Warn("Skipped scheduled run: previous run is still going")
+		}
+	}
+}
+
+// Trigger runs a job now, outside its schedule, and returns the result.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*Run, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+
+	return s.run(ctx, job, "manual")
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job, trigger string) (*Run, error) {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+
+		return nil, ErrRunning
+	}
+
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.Name)
+		s.mu.Unlock()
+	}()
+
+	run := Run{Job: job.Name, Trigger: trigger, StartedAt: time.Now().UTC()}
+
+	code, err := job.Code()
+	if err == nil {
+		var result *sandbox.ExecutionResult
+
+		result, err = s.executor.Execute(ctx, execsvc.ExecuteRequest{
+			Code:    code,
+			Timeout: job.Timeout,
+			OwnerID: OwnerID,
+			Profile: job.Profile,
+		})
+		if err == nil {
+			run.ExecutionID = result.ExecutionID
+			run.ExitCode = result.ExitCode
+			run.Stdout = result.Stdout
+			run.Stderr = result.Stderr
+			run.Result = result.Result
+		}
+	}
+
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	run.DurationSeconds = time.Since(run.StartedAt).Seconds()
+
+	if s.storage != nil {
+		if url, err := s.upload(run); err != nil {
+			s.log.WithError(err).WithField("job", job.Name).Warn("Failed to store scheduled run result")
+		} else {
+			run.URL = url
+		}
+	}
+
+	observability.ScheduledRunsTotal.WithLabelValues(job.Name, run.Status()).Inc()
+
+	// Each run is logged with its outcome so log pipelines such as Loki
+	// can alert on scheduled checks.
+	s.log.WithFields(logrus.Fields{
+		"job":          job.Name,
+		"trigger":      trigger,
+		"status":       run.Status(),
+		"execution_id": run.ExecutionID,
+		"exit_code":    run.ExitCode,
+		"duration_s":   run.DurationSeconds,
+		"error":        run.Error,
+		"url":          run.URL,
+	}).Info("Scheduled run finished")
+
+	run.Stdout = truncateOutput(run.Stdout)
+	run.Stderr = truncateOutput(run.Stderr)
+
+	s.record(run)
+
+	if s.onFailed != nil && run.Status() != "success" {
+		s.onFailed(run)
+	}
+
+	return &run, nil
+}
+
+// upload stores the full run as JSON under schedules/<job>/<time>.json.
+func (s *Scheduler) upload(run Run) (string, error) {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding run: %w", err)
+	}
+
+	name := fmt.Sprintf("%s/%s.json", run.Job, run.StartedAt.Format("20060102T150405.000Z"))
+
+	_, url, err := s.storage.Upload(StorageNamespace, name, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	return url, nil
+}
+
+func (s *Scheduler) record(run Run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := append(s.runs[run.Job], run)
+	if keep := s.cfg.KeepRuns; keep > 0 && len(runs) > keep {
+		runs = runs[len(runs)-keep:]
+	}
+
+	s.runs[run.Job] = runs
+}
+
+// Jobs returns every job with its next run and latest result, by name.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]JobStatus, 0, len(s.jobs))
+
+	for _, name := range s.order {
+		job := s.jobs[name]
+		status := JobStatus{
+			Name:    name,
+			Cron:    job.Schedule.String(),
+			Source:  job.Source,
+			NextRun: job.Schedule.Next(now),
+			Running: s.running[name],
+		}
+
+		if runs := s.runs[name]; len(runs) > 0 {
+			last := runs[len(runs)-1]
+			status.LastRun = &last
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses
+}
+
+// Runs returns a job's recent runs, newest first.
+func (s *Scheduler) Runs(name string) ([]Run, error) {
+	if _, ok := s.jobs[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.runs[name]
+	out := make([]Run, len(runs))
+
+	for i, run := range runs {
+		out[len(runs)-1-i] = run
+	}
+
+	return out, nil
+}
+
+func truncateOutput(output string) string {
+	if len(output) <= maxKeptOutput {
+		return output
+	}
+
+	return output[:maxKeptOutput] + "\n... (truncated; see the stored result)"
+}
@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/cronexpr"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+type fakeExecutor struct {
+	requests []execsvc.ExecuteRequest
+	exitCode int
+	err      error
+}
+
+func (f *fakeExecutor) Execute(_ context.Context, req execsvc.ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &sandbox.ExecutionResult{
+		ExecutionID: fmt.Sprintf("exec-%d", len(f.requests)),
+		ExitCode:    f.exitCode,
+		Stdout:      fmt.Sprintf("run %d\n", len(f.requests)),
+	}, nil
+}
+
+func testJob(t *testing.T, name string) Job {
+	t.Helper()
+
+	expr, err := cronexpr.Parse("@hourly")
+	require.NoError(t, err)
+
+	return Job{
+		Name:     name,
+		Schedule: expr,
+		Source:   "code",
+		Code:     func() (string, error) { return "print('ok')", nil },
+		Timeout:  30,
+		Profile:  "default",
+	}
+}
+
+func newTestLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	return log
+}
+
+func TestTriggerRecordsRuns(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{}
+	storageSvc := storage.New(afero.NewMemMapFs(), "/data", "http://localhost:2480")
+	s := New(newTestLogger(), Config{KeepRuns: 2}, []Job{testJob(t, "gaps")}, executor, storageSvc)
+
+	for range 3 {
+		run, err := s.Trigger(context.Background(), "gaps")
+		require.NoError(t, err)
+		assert.Equal(t, "success", run.Status())
+		assert.Equal(t, "manual", run.Trigger)
+		assert.True(t, strings.HasPrefix(run.URL, "http://localhost:2480/api/v1/storage/files/schedules/gaps/"), run.URL)
+	}
+
+	require.Len(t, executor.requests, 3)
+	assert.Equal(t, OwnerID, executor.requests[0].OwnerID)
+	assert.Equal(t, 30, executor.requests[0].Timeout)
+	assert.Equal(t, "default", executor.requests[0].Profile)
+
+	runs, err := s.Runs("gaps")
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "exec-3", runs[0].ExecutionID)
+	assert.Equal(t, "exec-2", runs[1].ExecutionID)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "@hourly", jobs[0].Cron)
+	assert.False(t, jobs[0].NextRun.IsZero())
+	require.NotNil(t, jobs[0].LastRun)
+	assert.Equal(t, "exec-3", jobs[0].LastRun.ExecutionID)
+}
+
+func TestTriggerStatuses(t *testing.T) {
+	t.Parallel()
+
+	failing := &fakeExecutor{exitCode: 1}
+	s := New(newTestLogger(), Config{}, []Job{testJob(t, "check")}, failing, nil)
+
+	var failed []Run
+
+	s.SetFailureHook(func(run Run) { failed = append(failed, run) })
+
+	run, err := s.Trigger(context.Background(), "check")
+	require.NoError(t, err)
+	assert.Equal(t, "failure", run.Status())
+	assert.Empty(t, run.URL)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "exec-1", failed[0].ExecutionID)
+
+	broken := &fakeExecutor{err: errors.New("sandbox unavailable")}
+	s = New(newTestLogger(), Config{}, []Job{testJob(t, "check")}, broken, nil)
+
+	run, err = s.Trigger(context.Background(), "check")
+	require.NoError(t, err)
+	assert.Equal(t, "error", run.Status())
+	assert.Equal(t, "sandbox unavailable", run.Error)
+
+	job := testJob(t, "missing-file")
+	job.Code = func() (string, error) { return "", errors.New("reading check.py: no such file") }
+	executor := &fakeExecutor{}
+	s = New(newTestLogger(), Config{}, []Job{job}, executor, nil)
+
+	run, err = s.Trigger(context.Background(), "missing-file")
+	require.NoError(t, err)
+	assert.Equal(t, "error", run.Status())
+	assert.Empty(t, executor.requests)
+}
+
+func TestUnknownJob(t *testing.T) {
+	t.Parallel()
+
+	s := New(newTestLogger(), Config{}, nil, &fakeExecutor{}, nil)
+
+	_, err := s.Trigger(context.Background(), "nope")
+	assert.ErrorIs(t, err, ErrUnknownJob)
+
+	_, err = s.Runs("nope")
+	assert.ErrorIs(t, err, ErrUnknownJob)
+}
+
+func TestJobsSortedByName(t *testing.T) {
+	t.Parallel()
+
+	s := New(newTestLogger(), Config{}, []Job{testJob(t, "b"), testJob(t, "a")}, &fakeExecutor{}, nil)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "a", jobs[0].Name)
+	assert.Equal(t, "b", jobs[1].Name)
+	assert.Nil(t, jobs[0].LastRun)
+}
@@ -0,0 +1,229 @@
+package searchruntime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/resource"
+	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/runbooks"
+)
+
+// runbookGitTimeout bounds one clone or fetch of a git runbook source.
+const runbookGitTimeout = 2 * time.Minute
+
+// runbookSources loads runbooks from configured directories and git
+// repositories and keeps the runbook registry and index up to date with them.
+type runbookSources struct {
+	log     logrus.FieldLogger
+	cfg     config.RunbooksConfig
+	offline bool
+
+	// initial holds what each source held at start-up, for registries built
+	// before the refresh loops start.
+	initial map[string][]types.Runbook
+
+	// mu serializes updates so the index always follows the registry.
+	mu       sync.Mutex
+	registry *runbooks.Registry
+	index    *resource.RunbookIndex
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newRunbookSources(log logrus.FieldLogger, cfg config.RunbooksConfig, offline bool) *runbookSources {
+	return &runbookSources{
+		log:     log.WithField("component", "runbook_sources"),
+		cfg:     cfg,
+		offline: offline,
+		initial: make(map[string][]types.Runbook, len(cfg.Sources)),
+	}
+}
+
+// readAll reads every source once. A source that can't be read is logged
+// and retried on its next refresh.
+func (s *runbookSources) readAll(ctx context.Context) {
+	for _, src := range s.cfg.Sources {
+		loaded, err := s.read(ctx, src)
+		if err != nil {
+			s.log.WithError(err).WithField("source", src.Name).Warn("Failed to load runbook source")
+
+			continue
+		}
+
+		s.initial[src.Name] = loaded
+	}
+}
+
+// register adds the runbooks read at start-up to registry.
+func (s *runbookSources) register(registry *runbooks.Registry) {
+	for _, src := range s.cfg.Sources {
+		if loaded, ok := s.initial[src.Name]; ok {
+			registry.SetSource(src.Name, loaded)
+		}
+	}
+}
+
+// start refreshes each source on its interval in the background, updating
+// registry and index when a source changes.
+func (s *runbookSources) start(registry *runbooks.Registry, index *resource.RunbookIndex) {
+	if len(s.cfg.Sources) == 0 {
+		return
+	}
+
+	s.registry = registry
+	s.index = index
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, src := range s.cfg.Sources {
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(src.RefreshInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.refresh(ctx, src)
+				}
+			}
+		}()
+	}
+
+	s.log.WithField("sources", len(s.cfg.Sources)).Info("Runbook source refresh started")
+}
+
+// stop stops the refresh loops. It is safe to call when they never started.
+func (s *runbookSources) stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *runbookSources) refresh(ctx context.Context, src config.RunbookSourceConfig) {
+	log := s.log.WithField("source", src.Name)
+
+	loaded, err := s.read(ctx, src)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Warn("Failed to refresh runbook source")
+		}
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.registry.SetSource(src.Name, loaded) {
+		return
+	}
+
+	if err := s.index.Replace(s.registry.All()); err != nil {
+		log.WithError(err).Warn("Failed to re-index runbooks")
+
+		return
+	}
+
+	log.WithField("runbooks", len(loaded)).Info("Runbook source updated")
+}
+
+// read loads a source's runbooks, updating the checkout of a git source
+// first. If the update fails, the last checkout is read instead. Offline,
+// git sources are only read from an existing checkout.
+func (s *runbookSources) read(ctx context.Context, src config.RunbookSourceConfig) ([]types.Runbook, error) {
+	dir := src.Dir
+
+	if src.Git != "" {
+		checkout := filepath.Join(s.cfg.CheckoutDir, src.Name)
+
+		if !s.offline {
+			if err := syncGitCheckout(ctx, checkout, src.Git, src.Ref); err != nil {
+				if _, statErr := os.Stat(filepath.Join(checkout, ".git")); statErr != nil {
+					return nil, err
+				}
+
+				s.log.WithError(err).WithField("source", src.Name).
+					Warn("Failed to update runbook checkout, using the last one")
+			}
+		}
+
+		dir = filepath.Join(checkout, src.Path)
+	}
+
+	return runbooks.LoadDir(dir, src.Name)
+}
+
+// syncGitCheckout clones url at ref into dir, or updates an existing clone
+// to the latest commit of ref. An empty ref follows the remote's HEAD.
+func syncGitCheckout(ctx context.Context, dir, url, ref string) error {
+	ctx, cancel := context.WithTimeout(ctx, runbookGitTimeout)
+	defer cancel()
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return fmt.Errorf("creating checkout directory: %w", err)
+		}
+
+		// Clear whatever an interrupted clone left behind.
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("removing incomplete checkout: %w", err)
+		}
+
+		args := []string{"clone", "--depth", "1"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+
+		return runGit(ctx, "", append(args, "--", url, dir)...)
+	}
+
+	// The URL may have changed in the config since the clone.
+	if err := runGit(ctx, dir, "remote", "set-url", "origin", url); err != nil {
+		return err
+	}
+
+	target := ref
+	if target == "" {
+		target = "HEAD"
+	}
+
+	if err := runGit(ctx, dir, "fetch", "--depth", "1", "origin", target); err != nil {
+		return err
+	}
+
+	return runGit(ctx, dir, "reset", "--hard", "FETCH_HEAD")
+}
+
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Fail instead of waiting for credentials on a terminal nobody watches.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -3,10 +3,12 @@ package searchruntime
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/panda/pkg/cache"
+	"github.com/ethpandaops/panda/pkg/config"
 	"github.com/ethpandaops/panda/pkg/eips"
 	"github.com/ethpandaops/panda/pkg/embedding"
 	"github.com/ethpandaops/panda/pkg/module"
@@ -22,54 +24,117 @@ type Runtime struct {
 	RunbookIndex    *resource.RunbookIndex
 	EIPRegistry     *eips.Registry
 	EIPIndex        *resource.EIPIndex
-	embedder        embedding.Embedder
+	// KeywordOnly explains why search uses the lexical embedder instead of
+	// semantic embeddings. It is empty when semantic search is available.
+	KeywordOnly    string
+	embedder       embedding.Embedder
+	runbookSources *runbookSources
 }
 
 // Build creates a new search runtime with example, runbook, and EIP indices.
-// Embedding is provided by the proxy's remote embedding service.
+// Embedding is provided by the proxy's remote embedding service, or by an
+// OpenAI-compatible API when searchCfg selects the "openai" backend.
+// Runbooks from runbookCfg's sources are indexed alongside the built-in ones
+// and refreshed in the background until Close.
 // cacheDir enables a local filesystem cache for embedding vectors when non-empty.
+// In offline mode indices use a local lexical embedder and EIPs are loaded
+// from the disk cache only, so no outbound calls are made. When the backend
+// is unavailable, or embedding the indices fails, search degrades to the same
+// lexical embedder rather than failing.
 func Build(
 	ctx context.Context,
 	log logrus.FieldLogger,
 	moduleRegistry *module.Registry,
 	proxyService proxy.Service,
+	searchCfg config.SemanticSearchConfig,
+	runbookCfg config.RunbooksConfig,
 	cacheDir string,
+	offline bool,
 ) (*Runtime, error) {
-	if proxyService == nil {
-		return nil, fmt.Errorf("proxy service is required for semantic search")
+	runtime := &Runtime{runbookSources: newRunbookSources(log, runbookCfg, offline)}
+	runtime.runbookSources.readAll(ctx)
+
+	if offline {
+		log.Info("Offline mode: using lexical search instead of remote embeddings")
+
+		runtime.useKeywordOnly("offline mode")
+	} else if remote, err := buildEmbedder(log, proxyService, searchCfg, cacheDir); err != nil {
+		log.WithError(err).Warn("Semantic search unavailable, falling back to keyword search")
+
+		runtime.useKeywordOnly(err.Error())
+	} else {
+		runtime.embedder = remote
 	}
 
-	if !proxyService.EmbeddingAvailable() {
-		return nil, fmt.Errorf("proxy embedding not available: ensure the proxy has embedding configured")
+	err := runtime.buildIndices(log, moduleRegistry)
+	if err != nil && runtime.KeywordOnly == "" {
+		log.WithError(err).Warn("Building semantic search indices failed, falling back to keyword search")
+
+		_ = runtime.embedder.Close()
+		runtime.useKeywordOnly(fmt.Sprintf("embedding failed: %v", err))
+
+		err = runtime.buildIndices(log, moduleRegistry)
 	}
 
-	model := proxyService.EmbeddingModel()
+	if err != nil {
+		_ = runtime.Close()
+		return nil, err
+	}
 
-	log.WithField("model", model).
-		Info("Using remote embedder via proxy")
+	if runtime.RunbookIndex == nil {
+		return runtime, nil
+	}
 
-	var localCache cache.Cache
+	runtime.runbookSources.start(runtime.RunbookRegistry, runtime.RunbookIndex)
 
-	if cacheDir != "" {
-		var err error
+	// Build EIP index (non-fatal — gracefully disabled if GitHub unreachable).
+	var eipReg *eips.Registry
 
-		localCache, err = cache.NewFilesystem(cacheDir)
-		if err != nil {
-			log.WithError(err).Warn("Failed to create local embedding cache, continuing without")
-		} else {
-			log.WithField("dir", cacheDir).Info("Local embedding cache enabled")
-		}
+	if offline {
+		eipReg, err = eips.NewCachedRegistry(log, "")
+	} else {
+		log.Info("Fetching EIPs from GitHub for search index")
+
+		eipReg, err = eips.NewRegistry(ctx, log, "")
 	}
 
-	embedder := embedding.NewRemote(
-		log,
-		proxyService.URL(),
-		func() string { return proxyService.RegisterToken("embedding") },
-		localCache,
-		model,
-	)
+	if err != nil {
+		log.WithError(err).Warn("Failed to initialize EIP registry — EIP search disabled")
+
+		return runtime, nil
+	}
+
+	if eipReg.Count() == 0 {
+		log.Warn("No EIPs found, EIP search will be disabled")
+
+		return runtime, nil
+	}
+
+	log.WithField("eips", eipReg.Count()).Info("Building EIP search index")
+
+	eipIndex, err := resource.NewEIPIndex(log, runtime.embedder, eipReg.All())
+	if err != nil {
+		log.WithError(err).Warn("Failed to build EIP index — EIP search disabled")
+
+		return runtime, nil
+	}
+
+	runtime.EIPRegistry = eipReg
+	runtime.EIPIndex = eipIndex
+	log.Info("Semantic search EIP index built")
+
+	return runtime, nil
+}
+
+// useKeywordOnly switches the runtime to the lexical embedder.
+func (r *Runtime) useKeywordOnly(reason string) {
+	r.embedder = embedding.NewLexical()
+	r.KeywordOnly = reason
+}
 
-	runtime := &Runtime{embedder: embedder}
+// buildIndices builds the example and runbook indices with r.embedder.
+func (r *Runtime) buildIndices(log logrus.FieldLogger, moduleRegistry *module.Registry) error {
+	r.ExampleIndex, r.RunbookIndex = nil, nil
 
 	examples := resource.GetQueryExamples(moduleRegistry)
 	exampleCount := 0
@@ -79,67 +144,118 @@ func Build(
 
 	log.WithField("examples", exampleCount).Info("Building example search index")
 
-	exampleIndex, err := resource.NewExampleIndex(log, embedder, examples)
+	exampleIndex, err := resource.NewExampleIndex(log, r.embedder, examples)
 	if err != nil {
-		_ = runtime.Close()
-		return nil, fmt.Errorf("building example index: %w", err)
+		return fmt.Errorf("building example index: %w", err)
 	}
 
-	runtime.ExampleIndex = exampleIndex
+	r.ExampleIndex = exampleIndex
 
 	runbookReg, err := runbooks.NewRegistry(log)
 	if err != nil {
-		_ = runtime.Close()
-		return nil, fmt.Errorf("creating runbook registry: %w", err)
+		return fmt.Errorf("creating runbook registry: %w", err)
 	}
 
-	runtime.RunbookRegistry = runbookReg
+	r.runbookSources.register(runbookReg)
+	r.RunbookRegistry = runbookReg
 
 	if runbookReg.Count() == 0 {
 		log.Warn("No runbooks found, runbook search will be disabled")
-		return runtime, nil
+		return nil
 	}
 
 	log.WithField("runbooks", runbookReg.Count()).Info("Building runbook search index")
 
-	runbookIndex, err := resource.NewRunbookIndex(log, embedder, runbookReg.All())
+	runbookIndex, err := resource.NewRunbookIndex(log, r.embedder, runbookReg.All())
 	if err != nil {
-		_ = runtime.Close()
-		return nil, fmt.Errorf("building runbook index: %w", err)
+		return fmt.Errorf("building runbook index: %w", err)
 	}
 
-	runtime.RunbookIndex = runbookIndex
-
-	// Build EIP index (non-fatal — gracefully disabled if GitHub unreachable).
-	log.Info("Fetching EIPs from GitHub for search index")
+	r.RunbookIndex = runbookIndex
 
-	eipReg, err := eips.NewRegistry(ctx, log, "")
-	if err != nil {
-		log.WithError(err).Warn("Failed to initialize EIP registry — EIP search disabled")
+	return nil
+}
 
-		return runtime, nil
+// buildEmbedder creates the embedder for the configured backend.
+func buildEmbedder(
+	log logrus.FieldLogger,
+	proxyService proxy.Service,
+	searchCfg config.SemanticSearchConfig,
+	cacheDir string,
+) (embedding.Embedder, error) {
+	if searchCfg.Backend != config.EmbeddingBackendOpenAI {
+		return buildRemoteEmbedder(log, proxyService, cacheDir)
 	}
 
-	if eipReg.Count() == 0 {
-		log.Warn("No EIPs found, EIP search will be disabled")
+	openAI := searchCfg.OpenAI
 
-		return runtime, nil
+	log.WithFields(logrus.Fields{
+		"url":   openAI.URL,
+		"model": openAI.Model,
+	}).Info("Using OpenAI-compatible embedder")
+
+	apiKey := os.Getenv(openAI.APIKeyEnv)
+	if apiKey == "" {
+		log.WithField("env", openAI.APIKeyEnv).Info("No embeddings API key set, sending requests without one")
 	}
 
-	log.WithField("eips", eipReg.Count()).Info("Building EIP search index")
+	return embedding.NewOpenAI(log, embedding.OpenAIConfig{
+		URL:        openAI.URL,
+		Model:      openAI.Model,
+		APIKey:     apiKey,
+		Dimensions: openAI.Dimensions,
+	}, newLocalCache(log, cacheDir)), nil
+}
+
+// newLocalCache opens the local embedding vector cache, or returns nil when
+// cacheDir is empty or the cache can't be created.
+func newLocalCache(log logrus.FieldLogger, cacheDir string) cache.Cache {
+	if cacheDir == "" {
+		return nil
+	}
 
-	eipIndex, err := resource.NewEIPIndex(log, embedder, eipReg.All())
+	localCache, err := cache.NewFilesystem(cacheDir)
 	if err != nil {
-		log.WithError(err).Warn("Failed to build EIP index — EIP search disabled")
+		log.WithError(err).Warn("Failed to create local embedding cache, continuing without")
 
-		return runtime, nil
+		return nil
 	}
 
-	runtime.EIPRegistry = eipReg
-	runtime.EIPIndex = eipIndex
-	log.Info("Semantic search EIP index built")
+	log.WithField("dir", cacheDir).Info("Local embedding cache enabled")
 
-	return runtime, nil
+	return localCache
+}
+
+// buildRemoteEmbedder creates the proxy-backed embedder.
+func buildRemoteEmbedder(
+	log logrus.FieldLogger,
+	proxyService proxy.Service,
+	cacheDir string,
+) (*embedding.RemoteEmbedder, error) {
+	if proxyService == nil {
+		return nil, fmt.Errorf("proxy service is required for semantic search")
+	}
+
+	if !proxyService.EmbeddingAvailable() {
+		return nil, fmt.Errorf("proxy embedding not available: ensure the proxy has embedding configured")
+	}
+
+	model := proxyService.EmbeddingModel()
+
+	log.WithField("model", model).
+		Info("Using remote embedder via proxy")
+
+	embedder := embedding.NewRemote(
+		log,
+		proxyService.URL(),
+		func() string { return proxyService.RegisterToken("embedding") },
+		newLocalCache(log, cacheDir),
+		model,
+	)
+	embedder.SetRequestSigner(proxyService.SignRequest)
+	embedder.SetTransport(proxyService.Transport())
+
+	return embedder, nil
 }
 
 // Close releases resources held by the runtime.
@@ -148,6 +264,10 @@ func (r *Runtime) Close() error {
 		return nil
 	}
 
+	if r.runbookSources != nil {
+		r.runbookSources.stop()
+	}
+
 	if r.ExampleIndex != nil {
 		return r.ExampleIndex.Close()
 	}
@@ -1,14 +1,17 @@
 package searchsvc
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
 	"strings"
 
 	"github.com/ethpandaops/panda/pkg/eips"
+	"github.com/ethpandaops/panda/pkg/exampleusage"
 	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/resource"
+	"github.com/ethpandaops/panda/pkg/types"
 	"github.com/ethpandaops/panda/runbooks"
 )
 
@@ -26,14 +29,23 @@ const (
 	MinRunbookScore       = 0.25
 	MinEIPScore           = 0.25
 	exampleFilterOverscan = 3
+	exampleUsageOverscan  = 2
 	runbookFilterOverscan = 2
 	eipFilterOverscan     = 3
 )
 
+// ErrExampleNotFound is returned when rating an example that doesn't exist.
+var ErrExampleNotFound = errors.New("example not found")
+
 type ExampleSearcher interface {
 	Search(query string, limit int) ([]resource.SearchResult, error)
 }
 
+// ExampleIndexer adds examples to a search index after it is built.
+type ExampleIndexer interface {
+	Add(categoryKey, categoryName string, example types.Example) error
+}
+
 type RunbookSearcher interface {
 	Search(query string, limit int) ([]resource.RunbookSearchResult, error)
 }
@@ -62,6 +74,9 @@ type SearchExampleResult struct {
 	Query           string  `json:"query"`
 	TargetCluster   string  `json:"target_cluster"`
 	SimilarityScore float64 `json:"similarity_score"`
+	// Uses is how often the example was used after being found, when usage
+	// ranking is enabled.
+	Uses int `json:"uses,omitempty"`
 }
 
 type SearchExamplesResponse struct {
@@ -71,6 +86,7 @@ type SearchExamplesResponse struct {
 	TotalMatches        int                    `json:"total_matches"`
 	Results             []*SearchExampleResult `json:"results"`
 	AvailableCategories []string               `json:"available_categories"`
+	Warning             string                 `json:"warning,omitempty"`
 }
 
 type SearchRunbookResult struct {
@@ -80,6 +96,7 @@ type SearchRunbookResult struct {
 	Prerequisites   []string `json:"prerequisites"`
 	Content         string   `json:"content"`
 	FilePath        string   `json:"file_path"`
+	Source          string   `json:"source,omitempty"`
 	SimilarityScore float64  `json:"similarity_score"`
 }
 
@@ -90,6 +107,7 @@ type SearchRunbooksResponse struct {
 	TotalMatches  int                    `json:"total_matches"`
 	Results       []*SearchRunbookResult `json:"results"`
 	AvailableTags []string               `json:"available_tags"`
+	Warning       string                 `json:"warning,omitempty"`
 }
 
 // SearchEIPResult represents a single EIP search result.
@@ -113,6 +131,7 @@ type SearchAllResponse struct {
 	Examples *SearchExamplesResponse `json:"examples,omitempty"`
 	Runbooks *SearchRunbooksResponse `json:"runbooks,omitempty"`
 	EIPs     *SearchEIPsResponse     `json:"eips,omitempty"`
+	Warning  string                  `json:"warning,omitempty"`
 }
 
 // SearchEIPsResponse is the response for EIP search.
@@ -127,6 +146,7 @@ type SearchEIPsResponse struct {
 	AvailableStatuses   []string           `json:"available_statuses"`
 	AvailableCategories []string           `json:"available_categories"`
 	AvailableTypes      []string           `json:"available_types"`
+	Warning             string             `json:"warning,omitempty"`
 }
 
 // Service provides search across examples, runbooks, and EIPs.
@@ -137,6 +157,9 @@ type Service struct {
 	runbookReg   RunbookTagProvider
 	eipIndex     EIPSearcher
 	eipReg       EIPMetadataProvider
+	warning      string
+	usage        *exampleusage.Store
+	usageWeight  float64
 }
 
 // New creates a new search service.
@@ -158,6 +181,57 @@ func New(
 	}
 }
 
+// SetKeywordOnly marks the indices as built with keyword matching rather
+// than semantic embeddings, so responses carry a warning. reason explains why
+// semantic search is unavailable.
+func (s *Service) SetKeywordOnly(reason string) {
+	if reason == "" {
+		s.warning = ""
+		return
+	}
+
+	s.warning = fmt.Sprintf(
+		"semantic search is unavailable (%s); results are keyword matches, so use the terms found in names, descriptions and tags",
+		reason,
+	)
+}
+
+// SetUsage enables usage-weighted example ranking: up to weight times an
+// example's popularity in store is added to its similarity when ranking.
+func (s *Service) SetUsage(store *exampleusage.Store, weight float64) {
+	s.usage = store
+	s.usageWeight = weight
+}
+
+// NoteShown records the examples returned to ownerID, so a later execution
+// reusing one of them counts as a use. It is a no-op without usage ranking.
+func (s *Service) NoteShown(ownerID string, results []*SearchExampleResult) {
+	if s.usage == nil {
+		return
+	}
+
+	for _, result := range results {
+		s.usage.Shown(ownerID, exampleusage.Key{
+			CategoryKey: result.CategoryKey,
+			ExampleName: result.ExampleName,
+		}, result.Query)
+	}
+}
+
+// RateExample records explicit feedback on whether an example was useful.
+func (s *Service) RateExample(categoryKey, exampleName string, useful bool) (exampleusage.Stats, error) {
+	if s.usage == nil {
+		return exampleusage.Stats{}, errors.New("example usage ranking is disabled")
+	}
+
+	category, ok := resource.GetQueryExamples(s.moduleReg)[categoryKey]
+	if !ok || !slices.ContainsFunc(category.Examples, func(e types.Example) bool { return e.Name == exampleName }) {
+		return exampleusage.Stats{}, fmt.Errorf("%w: %q in %q", ErrExampleNotFound, exampleName, categoryKey)
+	}
+
+	return s.usage.Rate(exampleusage.Key{CategoryKey: categoryKey, ExampleName: exampleName}, useful)
+}
+
 // NormalizeSearchType validates and normalizes a search type string.
 func NormalizeSearchType(searchType string) (string, error) {
 	switch strings.TrimSpace(strings.ToLower(searchType)) {
@@ -202,12 +276,19 @@ func (s *Service) SearchExamples(query, categoryFilter string, limit int) (*Sear
 		searchLimit = limit * exampleFilterOverscan
 	}
 
+	// Look further down the list so popular examples can move up.
+	if s.usage != nil {
+		searchLimit *= exampleUsageOverscan
+	}
+
 	results, err := s.exampleIndex.Search(query, searchLimit)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
 	searchResults := make([]*SearchExampleResult, 0, len(results))
+	rank := make(map[*SearchExampleResult]float64, len(results))
+
 	for _, result := range results {
 		if result.Score < MinExampleScore {
 			continue
@@ -217,7 +298,7 @@ func (s *Service) SearchExamples(query, categoryFilter string, limit int) (*Sear
 			continue
 		}
 
-		searchResults = append(searchResults, &SearchExampleResult{
+		searchResult := &SearchExampleResult{
 			CategoryKey:     result.CategoryKey,
 			CategoryName:    result.CategoryName,
 			ExampleName:     result.Example.Name,
@@ -225,11 +306,24 @@ func (s *Service) SearchExamples(query, categoryFilter string, limit int) (*Sear
 			Query:           result.Example.Query,
 			TargetCluster:   result.Example.Cluster,
 			SimilarityScore: result.Score,
-		})
+		}
+		rank[searchResult] = result.Score
 
-		if len(searchResults) >= limit {
-			break
+		if s.usage != nil {
+			key := exampleusage.Key{CategoryKey: result.CategoryKey, ExampleName: result.Example.Name}
+			searchResult.Uses = s.usage.Get(key).Uses
+			rank[searchResult] += s.usageWeight * s.usage.Popularity(key)
 		}
+
+		searchResults = append(searchResults, searchResult)
+	}
+
+	sort.SliceStable(searchResults, func(i, j int) bool {
+		return rank[searchResults[i]] > rank[searchResults[j]]
+	})
+
+	if len(searchResults) > limit {
+		searchResults = searchResults[:limit]
 	}
 
 	return &SearchExamplesResponse{
@@ -239,9 +333,20 @@ func (s *Service) SearchExamples(query, categoryFilter string, limit int) (*Sear
 		TotalMatches:        len(searchResults),
 		Results:             searchResults,
 		AvailableCategories: categories,
+		Warning:             s.warning,
 	}, nil
 }
 
+// IndexExample makes a newly added example searchable.
+func (s *Service) IndexExample(categoryKey, categoryName string, example types.Example) error {
+	indexer, ok := s.exampleIndex.(ExampleIndexer)
+	if !ok {
+		return fmt.Errorf("example search index does not support adding examples")
+	}
+
+	return indexer.Add(categoryKey, categoryName, example)
+}
+
 func (s *Service) SearchRunbooks(query, tagFilter string, limit int) (*SearchRunbooksResponse, error) {
 	if s.runbookIndex == nil || s.runbookReg == nil {
 		return nil, fmt.Errorf("runbook search index not available")
@@ -287,6 +392,7 @@ func (s *Service) SearchRunbooks(query, tagFilter string, limit int) (*SearchRun
 			Prerequisites:   result.Runbook.Prerequisites,
 			Content:         result.Runbook.Content,
 			FilePath:        result.Runbook.FilePath,
+			Source:          result.Runbook.Source,
 			SimilarityScore: result.Score,
 		})
 
@@ -302,6 +408,7 @@ func (s *Service) SearchRunbooks(query, tagFilter string, limit int) (*SearchRun
 		TotalMatches:  len(searchResults),
 		Results:       searchResults,
 		AvailableTags: availableTags,
+		Warning:       s.warning,
 	}, nil
 }
 
@@ -404,14 +511,16 @@ func (s *Service) SearchEIPs(
 		AvailableStatuses:   availableStatuses,
 		AvailableCategories: availableCategories,
 		AvailableTypes:      availableTypes,
+		Warning:             s.warning,
 	}, nil
 }
 
 // SearchAll searches across all available indices and merges results.
 func (s *Service) SearchAll(query string, limit int) (*SearchAllResponse, error) {
 	resp := &SearchAllResponse{
-		Type:  "all",
-		Query: query,
+		Type:    "all",
+		Query:   query,
+		Warning: s.warning,
 	}
 
 	if s.exampleIndex != nil {
@@ -1,42 +1,93 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/history"
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/searchsvc"
 	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/pkg/storage"
+	"github.com/ethpandaops/panda/pkg/tool"
 	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/pkg/userexamples"
 )
 
 func (s *service) mountAPIRoutes(r chi.Router) {
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/datasources", s.handleAPIDatasources)
 		r.Get("/proxy/auth", s.handleAPIProxyAuthMetadata)
-		r.Get("/search/examples", s.handleAPISearchExamples)
-		r.Get("/search/runbooks", s.handleAPISearchRunbooks)
-		r.Get("/search/eips", s.handleAPISearchEIPs)
-		r.Post("/execute", s.handleAPIExecute)
-		r.Get("/sessions", s.handleAPIListSessions)
-		r.Post("/sessions", s.handleAPICreateSession)
-		r.Delete("/sessions/{sessionID}", s.handleAPIDestroySession)
+		r.Group(func(r chi.Router) {
+			r.Use(s.toolScopeMiddleware(tool.SearchToolName))
+			r.Get("/search/examples", s.handleAPISearchExamples)
+			r.Post("/search/examples/rate", s.handleAPIRateExample)
+			r.Get("/search/runbooks", s.handleAPISearchRunbooks)
+			r.Get("/search/eips", s.handleAPISearchEIPs)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(s.toolScopeMiddleware(tool.ExecutePythonToolName))
+			r.Post("/execute", s.handleAPIExecute)
+			r.Post("/execute/stream", s.handleAPIExecuteStream)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(s.toolScopeMiddleware(tool.ManageSessionToolName))
+			r.Get("/sessions", s.handleAPIListSessions)
+			r.Post("/sessions", s.handleAPICreateSession)
+			r.Delete("/sessions/{sessionID}", s.handleAPIDestroySession)
+			r.Put("/sessions/{sessionID}/files/*", s.handleAPIPutSessionFile)
+			r.Post("/sessions/{sessionID}/env", s.handleAPISetSessionEnv)
+			r.Post("/sessions/{sessionID}/notebook", s.handleAPIExportNotebook)
+			r.Get("/sessions/{sessionID}/files/*", s.handleAPIGetSessionFile)
+		})
+		r.Get("/executions", s.handleAPIListExecutions)
+		r.Get("/executions/{executionID}", s.handleAPIGetExecution)
+		r.Post("/executions/{executionID}/promote", s.handleAPIPromoteExecution)
+		r.Get("/schedules", s.handleAPIListSchedules)
+		r.Get("/schedules/{name}/runs", s.handleAPIScheduleRuns)
 		r.Get("/resources", s.handleAPIListResources)
 		r.Get("/resources/read", s.handleAPIReadResource)
 		r.HandleFunc("/operations/{operationID}", s.handleAPIOperation)
 
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(s.adminAuthMiddleware)
+			r.Get("/modules", s.handleAdminListModules)
+			r.Post("/modules/{name}/disable", s.handleAdminDisableModule)
+			r.Post("/modules/{name}/enable", s.handleAdminEnableModule)
+			r.Get("/package-cache", s.handleAdminPackageCacheStatus)
+			r.Post("/package-cache", s.handleAdminAddPackages)
+			r.Get("/images", s.handleAdminImages)
+			r.Post("/images/pull", s.handleAdminPullImages)
+			r.Get("/audit", s.handleAdminAudit)
+			r.Post("/schedules/{name}/run", s.handleAdminRunSchedule)
+		})
+
 		// Public file serving (no auth — same as MinIO anonymous download).
 		r.Get("/storage/files/*", s.handleStorageServeFile)
 
+		// Presigned session uploads; the URL's signature is the credential.
+		r.Put("/storage/uploads/{sessionID}/*", s.handleStorageUpload)
+
 		r.Route("/runtime", func(r chi.Router) {
 			r.Use(s.runtimeAuthMiddleware)
 			r.HandleFunc("/operations/{operationID}", s.handleAPIOperation)
@@ -51,6 +102,81 @@ type runtimeContextKey string
 
 const runtimeExecutionIDKey runtimeContextKey = "runtime_execution_id"
 
+// toolScopeMiddleware rejects API requests for the work of toolName when the
+// tool policy or the policy engine denies the caller that tool, so the CLI
+// cannot reach what the matching MCP tool would refuse.
+func (s *service) toolScopeMiddleware(toolName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := s.toolPolicy.Authorize(r.Context(), toolName); err != nil {
+				writeAPIError(w, http.StatusForbidden, err.Error())
+
+				return
+			}
+
+			if s.policyEngine != nil {
+				args, err := apiToolArgs(r)
+				if err != nil {
+					writeAPIError(w, http.StatusBadRequest, err.Error())
+
+					return
+				}
+
+				if err := s.policyEngine.AuthorizeTool(r.Context(), toolName, args); err != nil {
+					s.log.WithError(err).WithField("tool", toolName).Debug("API request denied by policy engine")
+					writeAPIError(w, http.StatusForbidden, err.Error())
+
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiToolArgs returns the URL parameters and JSON body fields of an API
+// request as the tool arguments the policy engine sees for the matching MCP
+// call. The body is restored for the handler.
+func apiToolArgs(r *http.Request) (map[string]any, error) {
+	args := make(map[string]any, len(r.URL.Query()))
+
+	for name, values := range r.URL.Query() {
+		args[name] = values[0]
+	}
+
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		for i, name := range rctx.URLParams.Keys {
+			if name != "*" {
+				args[name] = rctx.URLParams.Values[i]
+			}
+		}
+	}
+
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return args, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// The handler reports malformed bodies.
+		return args, nil
+	}
+
+	for name, value := range fields {
+		args[name] = value
+	}
+
+	return args, nil
+}
+
 func (s *service) runtimeAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if s.runtimeTokens == nil {
@@ -92,7 +218,7 @@ func (s *service) handleAPIDatasources(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filterType := strings.TrimSpace(r.URL.Query().Get("type"))
-	all := s.moduleRegistry.DatasourceInfo()
+	all := s.scopedDatasources(r.Context(), s.moduleRegistry.DatasourceInfo())
 
 	if filterType != "" {
 		filtered := make([]types.DatasourceInfo, 0, len(all))
@@ -132,9 +258,42 @@ func (s *service) handleAPISearchExamples(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	s.searchService.NoteShown(authOwnerID(r), resp.Results)
+
 	writeJSON(w, http.StatusOK, resp)
 }
 
+func (s *service) handleAPIRateExample(w http.ResponseWriter, r *http.Request) {
+	if s.searchService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "search service is unavailable")
+		return
+	}
+
+	var req serverapi.RateExampleRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("decoding request: %v", err))
+		return
+	}
+
+	stats, err := s.searchService.RateExample(req.CategoryKey, req.ExampleName, req.Useful)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, searchsvc.ErrExampleNotFound) {
+			status = http.StatusNotFound
+		}
+
+		writeAPIError(w, status, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.RateExampleResponse{
+		CategoryKey: stats.CategoryKey,
+		ExampleName: stats.ExampleName,
+		Uses:        stats.Uses,
+		NotUseful:   stats.NotUseful,
+	})
+}
+
 func (s *service) handleAPISearchRunbooks(w http.ResponseWriter, r *http.Request) {
 	if s.searchService == nil {
 		writeAPIError(w, http.StatusServiceUnavailable, "search service is unavailable")
@@ -209,12 +368,71 @@ func (s *service) handleAPIExecute(w http.ResponseWriter, r *http.Request) {
 		Timeout:   req.Timeout,
 		SessionID: req.SessionID,
 		OwnerID:   ownerID,
+		Profile:   req.Profile,
+		Groups:    auth.GetAuthGroups(r.Context()),
+		Packages:  req.Packages,
 	})
 	if err != nil {
+		writeAPIError(w, executeErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, executeResponse(result))
+}
+
+// handleAPIExecuteStream runs code like handleAPIExecute but streams output as it
+// is produced, as newline-delimited serverapi.ExecuteStreamEvent values.
+func (s *service) handleAPIExecuteStream(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	var req serverapi.ExecuteRequest
+	if err := decodeJSON(r, &req); err != nil {
 		writeAPIError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	stream := newEventStream(w)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	stream.flush()
+
+	ownerID := authOwnerID(r)
+	result, err := s.execService.Execute(r.Context(), execsvc.ExecuteRequest{
+		Code:      req.Code,
+		Timeout:   req.Timeout,
+		SessionID: req.SessionID,
+		OwnerID:   ownerID,
+		Profile:   req.Profile,
+		Groups:    auth.GetAuthGroups(r.Context()),
+		Packages:  req.Packages,
+		Stdout:    stream.output(serverapi.ExecuteEventStdout),
+		Stderr:    stream.output(serverapi.ExecuteEventStderr),
+		OnQueued: func(position int) {
+			stream.send(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventQueued, Position: position})
+		},
+	})
+
+	if err != nil {
+		stream.close(serverapi.ExecuteStreamEvent{
+			Type:   serverapi.ExecuteEventError,
+			Error:  err.Error(),
+			Status: executeErrorStatus(err),
+		})
+
+		return
+	}
+
+	resp := executeResponse(result)
+	stream.close(serverapi.ExecuteStreamEvent{Type: serverapi.ExecuteEventResult, Result: &resp})
+}
+
+// executeResponse converts a sandbox result into its API representation.
+func executeResponse(result *sandbox.ExecutionResult) serverapi.ExecuteResponse {
 	resp := serverapi.ExecuteResponse{
 		Stdout:          result.Stdout,
 		Stderr:          result.Stderr,
@@ -222,15 +440,109 @@ func (s *service) handleAPIExecute(w http.ResponseWriter, r *http.Request) {
 		ExecutionID:     result.ExecutionID,
 		OutputFiles:     result.OutputFiles,
 		Metrics:         result.Metrics,
+		Result:          result.Result,
 		DurationSeconds: result.DurationSeconds,
 		SessionID:       result.SessionID,
 		SessionFiles:    result.SessionFiles,
+		Hints:           result.Hints,
+		RateLimit:       result.RateLimit,
+		Artifacts:       result.Artifacts,
+		Usage:           result.Usage,
 	}
 	if result.SessionTTLRemaining > 0 {
 		resp.SessionTTLRemaining = result.SessionTTLRemaining.Round(time.Second).String()
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	return resp
+}
+
+// eventStream writes newline-delimited JSON events to a response, flushing
+// after each one. Sandbox output may arrive from several goroutines and can
+// still trickle in after execution returns, so writes are serialized and
+// dropped once the stream is closed.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	flusher http.Flusher
+	closed  bool
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	flusher, _ := w.(http.Flusher)
+
+	return &eventStream{w: w, enc: json.NewEncoder(w), flusher: flusher}
+}
+
+func (e *eventStream) flush() {
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+func (e *eventStream) send(event serverapi.ExecuteStreamEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.writeLocked(event)
+}
+
+// close sends the final event and stops accepting output.
+func (e *eventStream) close(event serverapi.ExecuteStreamEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.writeLocked(event)
+	e.closed = true
+}
+
+func (e *eventStream) writeLocked(event serverapi.ExecuteStreamEvent) {
+	if e.closed {
+		return
+	}
+
+	if err := e.enc.Encode(event); err != nil {
+		// The client went away; drop further output.
+		e.closed = true
+		return
+	}
+
+	e.flush()
+}
+
+// output returns a writer that sends each write as an event of eventType.
+func (e *eventStream) output(eventType string) io.Writer {
+	return eventWriter{stream: e, eventType: eventType}
+}
+
+type eventWriter struct {
+	stream    *eventStream
+	eventType string
+}
+
+func (w eventWriter) Write(p []byte) (int, error) {
+	w.stream.send(serverapi.ExecuteStreamEvent{Type: w.eventType, Data: string(p)})
+
+	return len(p), nil
+}
+
+// executeErrorStatus maps an execution error to an HTTP status so clients can
+// tell timeouts and sandbox failures apart from invalid requests.
+func executeErrorStatus(err error) int {
+	var sandboxErr *execsvc.SandboxError
+
+	switch {
+	case errors.Is(err, sandbox.ErrExecutionTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, sandbox.ErrQueueFull):
+		return http.StatusTooManyRequests
+	case errors.Is(err, execsvc.ErrDraining):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &sandboxErr):
+		return http.StatusBadGateway
+	default:
+		return http.StatusBadRequest
+	}
 }
 
 func (s *service) handleAPIListSessions(w http.ResponseWriter, r *http.Request) {
@@ -262,6 +574,7 @@ func (s *service) handleAPIListSessions(w http.ResponseWriter, r *http.Request)
 			LastUsed:       session.LastUsed,
 			TTLRemaining:   session.TTLRemaining.Round(time.Second).String(),
 			WorkspaceFiles: session.WorkspaceFiles,
+			Env:            s.execService.SessionEnv(session.ID, authOwnerID(r)),
 		})
 	}
 
@@ -282,7 +595,12 @@ func (s *service) handleAPICreateSession(w http.ResponseWriter, r *http.Request)
 	ownerID := authOwnerID(r)
 	sessionID, err := s.execService.CreateSession(r.Context(), ownerID)
 	if err != nil {
-		writeAPIError(w, http.StatusBadRequest, err.Error())
+		status := http.StatusBadRequest
+		if errors.Is(err, execsvc.ErrDraining) {
+			status = http.StatusServiceUnavailable
+		}
+
+		writeAPIError(w, status, err.Error())
 		return
 	}
 
@@ -319,6 +637,299 @@ func (s *service) handleAPIDestroySession(w http.ResponseWriter, r *http.Request
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (s *service) handleAPISetSessionEnv(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	var req serverapi.SetSessionEnvRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("decoding request: %v", err))
+		return
+	}
+
+	if len(req.Env) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "env is required")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionID")
+
+	env, err := s.execService.SetSessionEnv(r.Context(), sessionID, authOwnerID(r), req.Env)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.SessionEnvResponse{SessionID: sessionID, Env: env})
+}
+
+func (s *service) handleAPIPutSessionFile(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionID")
+	filePath := chi.URLParam(r, "*")
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, sandbox.MaxWorkspaceFileSize))
+	if err != nil {
+		writeAPIError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("reading request body: %v", err))
+		return
+	}
+
+	if err := s.execService.WriteSessionFile(r.Context(), sessionID, authOwnerID(r), filePath, data); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.SessionFileResponse{
+		SessionID: sessionID,
+		Path:      filePath,
+		Size:      int64(len(data)),
+	})
+}
+
+func (s *service) handleAPIGetSessionFile(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionID")
+	filePath := chi.URLParam(r, "*")
+
+	data, err := s.execService.ReadSessionFile(r.Context(), sessionID, authOwnerID(r), filePath)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ServeContent answers Range requests, so large binary files such as
+	// parquet can be read in chunks.
+	w.Header().Set("Content-Type", storage.ContentType(filePath, data))
+	http.ServeContent(w, r, path.Base(filePath), time.Time{}, bytes.NewReader(data))
+}
+
+func (s *service) handleAPIListExecutions(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := s.execService.ListExecutions(r.Context(), history.Filter{
+		OwnerID:   authOwnerID(r),
+		SessionID: strings.TrimSpace(r.URL.Query().Get("session_id")),
+		Limit:     limit,
+	})
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	executions := make([]history.Record, 0, len(records))
+	for _, record := range records {
+		executions = append(executions, record.Summary())
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.ListExecutionsResponse{
+		Executions: executions,
+		Total:      len(executions),
+	})
+}
+
+func (s *service) handleAPIExportNotebook(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	sessionID := strings.TrimSpace(chi.URLParam(r, "sessionID"))
+	if sessionID == "" {
+		writeAPIError(w, http.StatusBadRequest, "sessionID is required")
+		return
+	}
+
+	records, err := s.execService.ListExecutions(r.Context(), history.Filter{
+		OwnerID:   authOwnerID(r),
+		SessionID: sessionID,
+		Limit:     math.MaxInt,
+	})
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(records) == 0 {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("no recorded executions in session %s", sessionID))
+		return
+	}
+
+	files := make(map[string][]storage.File, len(records))
+
+	for _, record := range records {
+		stored, err := s.storageService.List(record.ExecutionID, "")
+		if err != nil {
+			s.log.WithError(err).WithField("execution_id", record.ExecutionID).Warn("Failed to list execution files for notebook")
+			continue
+		}
+
+		files[record.ExecutionID] = stored
+	}
+
+	data, err := history.Notebook(sessionID, records, files)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("%s/%s.ipynb", sessionID, time.Now().UTC().Format("20060102T150405Z"))
+
+	key, fileURL, err := s.storageService.Upload(history.NotebookNamespace, name, bytes.NewReader(data))
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("uploading notebook: %v", err))
+		return
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"session_id": sessionID,
+		"executions": len(records),
+		"key":        key,
+	}).Info("Exported session notebook")
+
+	writeJSON(w, http.StatusCreated, serverapi.ExportNotebookResponse{
+		SessionID:  sessionID,
+		Key:        key,
+		URL:        fileURL,
+		Executions: len(records),
+	})
+}
+
+func (s *service) handleAPIListSchedules(w http.ResponseWriter, _ *http.Request) {
+	if s.scheduler == nil {
+		writeAPIError(w, http.StatusNotFound, "scheduled executions are disabled: set schedules.enabled in the server config")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.ListSchedulesResponse{Jobs: s.scheduler.Jobs()})
+}
+
+func (s *service) handleAPIScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeAPIError(w, http.StatusNotFound, "scheduled executions are disabled: set schedules.enabled in the server config")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+
+	runs, err := s.scheduler.Runs(name)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.ScheduleRunsResponse{Job: name, Runs: runs})
+}
+
+func (s *service) handleAPIGetExecution(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	record, err := s.execService.GetExecution(r.Context(), chi.URLParam(r, "executionID"), authOwnerID(r))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (s *service) handleAPIPromoteExecution(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	if s.userExamples == nil {
+		writeAPIError(w, http.StatusBadRequest, "example promotion is disabled: set user_examples.enabled in the server config")
+		return
+	}
+
+	var req serverapi.PromoteExampleRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("decoding request: %v", err))
+		return
+	}
+
+	executionID := chi.URLParam(r, "executionID")
+
+	record, err := s.execService.GetExecution(r.Context(), executionID, authOwnerID(r))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if record.ExitCode != 0 || record.Error != "" {
+		writeAPIError(w, http.StatusBadRequest, "only successful executions can be promoted")
+		return
+	}
+
+	example := types.Example{
+		Name:        req.Name,
+		Description: req.Description,
+		Query:       record.Code,
+		Cluster:     req.Cluster,
+	}
+
+	entry, err := s.userExamples.Add(req.Category, example)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, userexamples.ErrDuplicate) {
+			status = http.StatusConflict
+		}
+
+		writeAPIError(w, status, err.Error())
+		return
+	}
+
+	indexed := false
+	if s.searchService != nil {
+		if err := s.searchService.IndexExample(entry.CategoryKey, entry.CategoryName, entry.Example); err != nil {
+			s.log.WithError(err).WithField("execution_id", executionID).Warn("Failed to index promoted example")
+		} else {
+			indexed = true
+		}
+	}
+
+	if s.resourceRegistry != nil {
+		s.resourceRegistry.NotifyUpdated("examples://queries", "examples://coverage")
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"execution_id": executionID,
+		"category":     entry.CategoryKey,
+		"name":         entry.Example.Name,
+	}).Info("Promoted execution to example")
+
+	writeJSON(w, http.StatusCreated, serverapi.PromoteExampleResponse{
+		ExecutionID:  executionID,
+		CategoryKey:  entry.CategoryKey,
+		CategoryName: entry.CategoryName,
+		Example:      entry.Example,
+		Indexed:      indexed,
+	})
+}
+
 func (s *service) handleAPIListResources(w http.ResponseWriter, _ *http.Request) {
 	if s.resourceRegistry == nil {
 		writeAPIError(w, http.StatusServiceUnavailable, "resource registry is unavailable")
@@ -372,6 +983,11 @@ func (s *service) handleAPIReadResource(w http.ResponseWriter, r *http.Request)
 		ctx = types.WithClientContext(ctx, types.ClientContextCLI)
 	}
 
+	if err := s.policyEngine.AuthorizeResource(ctx, uri); err != nil {
+		writeAPIError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	content, mimeType, err := s.resourceRegistry.Read(ctx, uri)
 	if err != nil {
 		writeAPIError(w, http.StatusBadRequest, err.Error())
@@ -382,6 +998,15 @@ func (s *service) handleAPIReadResource(w http.ResponseWriter, r *http.Request)
 		mimeType = "text/plain; charset=utf-8"
 	}
 
+	// The content hash doubles as a strong ETag for cheap revalidation.
+	etag := `"` + canonicaljson.Hash([]byte(content)) + `"`
+	w.Header().Set("ETag", etag)
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	w.Header().Set("Content-Type", mimeType)
 	_, _ = io.WriteString(w, content)
 }
@@ -398,6 +1023,14 @@ func (s *service) handleAPIOperation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Every operation reaches a datasource through the proxy.
+	if s.offline {
+		writeAPIError(w, http.StatusServiceUnavailable, fmt.Sprintf(
+			"operation %q needs network access and is disabled in offline mode", operationID,
+		))
+		return
+	}
+
 	if moduleName := operationExtensionName(operationID); moduleName != "" &&
 		s.moduleRegistry != nil &&
 		s.moduleRegistry.Get(moduleName) != nil {
@@ -411,6 +1044,11 @@ func (s *service) handleAPIOperation(w http.ResponseWriter, r *http.Request) {
 			writeAPIError(w, http.StatusNotFound, fmt.Sprintf("module %q is not enabled", moduleName))
 			return
 		}
+
+		if s.moduleRegistry.IsDisabled(moduleName) {
+			writeAPIError(w, http.StatusServiceUnavailable, fmt.Sprintf("module %q is disabled by an administrator", moduleName))
+			return
+		}
 	}
 
 	if !s.dispatchOperation(operationID, w, r) {
@@ -444,6 +1082,17 @@ func (s *service) handleRuntimeStorageUpload(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	meta := storage.Metadata{ExecutionID: executionID}
+	if s.execService != nil {
+		if running, ok := s.execService.Running(executionID); ok {
+			meta.SessionID, meta.OwnerID = running.SessionID, running.OwnerID
+		}
+	}
+
+	if err := s.storageService.SetMetadata(meta); err != nil {
+		s.log.WithError(err).WithField("execution_id", executionID).Warn("Failed to tag upload")
+	}
+
 	writeJSON(w, http.StatusOK, serverapi.RuntimeStorageUploadResponse{
 		Key: relativeKey,
 		URL: url,
@@ -522,6 +1171,41 @@ func (s *service) handleStorageServeFile(w http.ResponseWriter, r *http.Request)
 	s.storageService.ServeFile(w, r, filePath)
 }
 
+// handleStorageUpload writes the request body into a session workspace for
+// a presigned URL issued by manage_session put_file.
+func (s *service) handleStorageUpload(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil || s.uploadSigner == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "uploads are unavailable")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionID")
+	filePath := chi.URLParam(r, "*")
+
+	ownerID, err := s.uploadSigner.Verify(sessionID, filePath, r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, sandbox.MaxWorkspaceFileSize))
+	if err != nil {
+		writeAPIError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("reading request body: %v", err))
+		return
+	}
+
+	if err := s.execService.WriteSessionFile(r.Context(), sessionID, ownerID, filePath, data); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.SessionFileResponse{
+		SessionID: sessionID,
+		Path:      filePath,
+		Size:      int64(len(data)),
+	})
+}
+
 func (s *service) proxyRequest(
 	ctx context.Context,
 	method string,
@@ -545,6 +1229,7 @@ func (s *service) proxyRequest(
 		}
 	}
 	req.Header.Del("Authorization")
+	s.setProxyIdentity(ctx, req.Header)
 
 	tokenID := fmt.Sprintf("server-api-%d", time.Now().UnixNano())
 	token := s.proxyService.RegisterToken(tokenID)
@@ -554,13 +1239,35 @@ func (s *service) proxyRequest(
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
+	if err := s.proxyService.SignRequest(req); err != nil {
+		return nil, http.StatusInternalServerError, nil, fmt.Errorf("signing proxy request: %w", err)
+	}
+
+	dsType := proxyDatasourceType(requestPath)
+	if !s.proxyDatasourceAllowed(ctx, dsType, headers.Get(proxyDatasourceHeader)) {
+		return []byte("forbidden: datasource is not available to your groups"), http.StatusForbidden, nil, nil
+	}
+
+	start := time.Now()
+
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
+		observability.ProxyClientRequestDuration.WithLabelValues(dsType, "error").Observe(time.Since(start).Seconds())
+
 		return nil, http.StatusBadGateway, nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	data, err := io.ReadAll(resp.Body)
+
+	observability.ProxyClientRequestDuration.WithLabelValues(dsType, strconv.Itoa(resp.StatusCode)).
+		Observe(time.Since(start).Seconds())
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		observability.ProxyClientRateLimitedTotal.WithLabelValues(dsType).Inc()
+		s.recordRateLimit(ctx, data)
+	}
+
 	if err != nil {
 		return nil, resp.StatusCode, resp.Header.Clone(), fmt.Errorf("reading proxy response: %w", err)
 	}
@@ -568,18 +1275,66 @@ func (s *service) proxyRequest(
 	return data, resp.StatusCode, resp.Header.Clone(), nil
 }
 
+// recordRateLimit hands the state in a proxy rate limit rejection to the
+// execution the request was made for, which reports it with its result.
+func (s *service) recordRateLimit(ctx context.Context, body []byte) {
+	executionID := runtimeExecutionID(ctx)
+	if executionID == "" || s.execService == nil {
+		return
+	}
+
+	var rejection proxy.RateLimitError
+	if err := json.Unmarshal(body, &rejection); err != nil || rejection.Error != types.RateLimitErrorCode {
+		return
+	}
+
+	s.execService.RecordRateLimit(executionID, rejection.RateLimitState)
+}
+
+// proxyDatasourceType derives the datasource type metric label from a proxy
+// request path, matching the labels the proxy itself records.
+func proxyDatasourceType(requestPath string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(requestPath, "/"), "/")
+	segment, _, _ = strings.Cut(segment, "?")
+
+	switch segment {
+	case "clickhouse", "prometheus", "loki", "grafana", "httpjson", "github", "datasources", "embed":
+		return segment
+	case "beacon", "execution":
+		return "ethnode"
+	default:
+		return "unknown"
+	}
+}
+
 func runtimeExecutionID(ctx context.Context) string {
 	value, _ := ctx.Value(runtimeExecutionIDKey).(string)
 	return value
 }
 
-func authOwnerID(r *http.Request) string {
-	user := auth.GetAuthUser(r.Context())
-	if user == nil {
-		return ""
+// setProxyIdentity names the user and execution a proxy request is for: the
+// execution behind a runtime token and its owner, or else the authenticated
+// user.
+func (s *service) setProxyIdentity(ctx context.Context, h http.Header) {
+	ownerID := auth.OwnerID(ctx)
+
+	if executionID := runtimeExecutionID(ctx); executionID != "" {
+		h.Set(proxyExecutionIDHeader, executionID)
+
+		if s.execService != nil {
+			if running, ok := s.execService.Running(executionID); ok {
+				ownerID = running.OwnerID
+			}
+		}
 	}
 
-	return fmt.Sprintf("%d", user.GitHubID)
+	if ownerID != "" {
+		h.Set(proxyOnBehalfOfHeader, ownerID)
+	}
+}
+
+func authOwnerID(r *http.Request) string {
+	return auth.OwnerID(r.Context())
 }
 
 func parseOptionalInt(r *http.Request, key string) (int, error) {
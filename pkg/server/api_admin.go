@@ -0,0 +1,339 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/schedule"
+	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/pkg/wheelcache"
+)
+
+// adminAuthMiddleware requires the configured admin token. The admin API is
+// unavailable when no token is configured, so sandboxes that can reach the
+// server cannot use it.
+func (s *service) adminAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.AdminToken == "" {
+			writeAPIError(w, http.StatusForbidden, "admin API is disabled; set server.admin_token to enable it")
+			return
+		}
+
+		authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+
+		if !strings.HasPrefix(authHeader, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.AdminToken)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *service) handleAdminListModules(w http.ResponseWriter, _ *http.Request) {
+	if s.moduleRegistry == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "module registry is unavailable")
+		return
+	}
+
+	names := s.moduleRegistry.All()
+	sort.Strings(names)
+
+	modules := make([]serverapi.ModuleStatus, 0, len(names))
+	for _, name := range names {
+		modules = append(modules, s.moduleStatus(name))
+	}
+
+	writeJSON(w, http.StatusOK, serverapi.ListModulesResponse{Modules: modules})
+}
+
+func (s *service) handleAdminDisableModule(w http.ResponseWriter, r *http.Request) {
+	s.handleAdminSetModuleState(w, r, true)
+}
+
+func (s *service) handleAdminEnableModule(w http.ResponseWriter, r *http.Request) {
+	s.handleAdminSetModuleState(w, r, false)
+}
+
+func (s *service) handleAdminSetModuleState(w http.ResponseWriter, r *http.Request, disable bool) {
+	if s.moduleRegistry == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "module registry is unavailable")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if s.moduleRegistry.Get(name) == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("unknown module %q", name))
+		return
+	}
+
+	var err error
+	if disable {
+		err = s.moduleRegistry.Disable(r.Context(), name)
+	} else {
+		err = s.moduleRegistry.Enable(r.Context(), name)
+	}
+
+	if err != nil {
+		writeAPIError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	up := 1.0
+	if disable {
+		up = 0
+	}
+
+	observability.ModuleUp.WithLabelValues(name).Set(up)
+	s.syncModuleResources(name, disable)
+
+	s.log.WithField("module", name).WithField("disabled", disable).Info("Module state changed via admin API")
+
+	writeJSON(w, http.StatusOK, s.moduleStatus(name))
+}
+
+// syncModuleResources removes or restores a module's static resources on the
+// MCP server and notifies connected clients that the resource list changed.
+// Resource templates stay registered with the MCP server; reads through them
+// are rejected by the resource registry while the module is disabled.
+func (s *service) syncModuleResources(name string, disabled bool) {
+	if s.mcpServer == nil {
+		return
+	}
+
+	resources := s.resourceRegistry.ModuleStatic(name)
+	if len(resources) == 0 {
+		s.notifications.Notify(mcp.MethodNotificationResourcesListChanged, nil)
+		return
+	}
+
+	if disabled {
+		uris := make([]string, 0, len(resources))
+		for _, res := range resources {
+			uris = append(uris, res.URI)
+		}
+
+		s.mcpServer.DeleteResources(uris...)
+
+		return
+	}
+
+	serverResources := make([]mcpserver.ServerResource, 0, len(resources))
+	for _, res := range resources {
+		serverResources = append(serverResources, mcpserver.ServerResource{
+			Resource: res,
+			Handler:  s.createResourceHandler(res.URI),
+		})
+	}
+
+	s.mcpServer.AddResources(serverResources...)
+}
+
+func (s *service) moduleStatus(name string) serverapi.ModuleStatus {
+	return serverapi.ModuleStatus{
+		Name:        name,
+		Initialized: s.moduleRegistry.IsInitialized(name),
+		Disabled:    s.moduleRegistry.IsDisabled(name),
+	}
+}
+
+func (s *service) handleAdminPackageCacheStatus(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	report, err := s.execService.PackageCacheStatus(r.Context())
+	if err != nil {
+		writeAPIError(w, packageCacheErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, packageCacheStatus(report))
+}
+
+func (s *service) handleAdminAddPackages(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	var req serverapi.AddPackagesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.execService.BuildPackageCache(r.Context(), req.Packages)
+	if err != nil {
+		writeAPIError(w, packageCacheErrorStatus(err), err.Error())
+		return
+	}
+
+	s.log.WithField("packages", req.Packages).WithField("files", len(result.Added)).Info("Package cache updated via admin API")
+
+	writeJSON(w, http.StatusOK, serverapi.AddPackagesResponse{
+		Added:  packageCacheEntries(result.Added),
+		Log:    result.Log,
+		Status: packageCacheStatus(result.Report),
+	})
+}
+
+func (s *service) handleAdminImages(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	images, err := s.execService.ImageStatus(r.Context())
+	if err != nil {
+		writeAPIError(w, imagesErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sandboxImages(images))
+}
+
+func (s *service) handleAdminPullImages(w http.ResponseWriter, r *http.Request) {
+	if s.execService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "execute service is unavailable")
+		return
+	}
+
+	images, err := s.execService.PullImages(r.Context())
+	if err != nil {
+		writeAPIError(w, imagesErrorStatus(err), err.Error())
+		return
+	}
+
+	s.log.WithField("images", len(images)).Info("Sandbox images pulled via admin API")
+
+	writeJSON(w, http.StatusOK, sandboxImages(images))
+}
+
+func (s *service) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if s.proxyService == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "proxy service is unavailable")
+		return
+	}
+
+	query, err := audit.ParseQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := proxy.NewAuditClient(s.proxyService).Search(r.Context(), query)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func packageCacheErrorStatus(err error) int {
+	if errors.Is(err, sandbox.ErrPackageCacheDisabled) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusUnprocessableEntity
+}
+
+func packageCacheStatus(report *wheelcache.Report) serverapi.PackageCacheStatusResponse {
+	return serverapi.PackageCacheStatusResponse{
+		Entries:    packageCacheEntries(report.Entries),
+		TotalBytes: report.TotalBytes,
+		MaxBytes:   report.MaxBytes,
+		Removed:    report.Removed,
+	}
+}
+
+func packageCacheEntries(entries []wheelcache.Entry) []serverapi.PackageCacheEntry {
+	out := make([]serverapi.PackageCacheEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, serverapi.PackageCacheEntry{
+			Name:    entry.Name,
+			SHA256:  entry.SHA256,
+			Size:    entry.Size,
+			AddedAt: entry.AddedAt,
+		})
+	}
+
+	return out
+}
+
+func imagesErrorStatus(err error) int {
+	if errors.Is(err, sandbox.ErrImagesUnsupported) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusBadGateway
+}
+
+func sandboxImages(images []sandbox.ImageStatus) serverapi.SandboxImagesResponse {
+	out := make([]serverapi.SandboxImage, 0, len(images))
+	for _, img := range images {
+		image := serverapi.SandboxImage{
+			Ref:      img.Ref,
+			Profiles: img.Profiles,
+			ID:       img.ID,
+			Digest:   img.Digest,
+			Pinned:   img.Pinned,
+			Floating: img.Floating,
+			Size:     img.Size,
+			Error:    img.Error,
+		}
+
+		if !img.Created.IsZero() {
+			image.Created = &img.Created
+		}
+
+		if !img.PulledAt.IsZero() {
+			image.PulledAt = &img.PulledAt
+		}
+
+		out = append(out, image)
+	}
+
+	return serverapi.SandboxImagesResponse{Images: out}
+}
+
+func (s *service) handleAdminRunSchedule(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeAPIError(w, http.StatusNotFound, "scheduled executions are disabled: set schedules.enabled in the server config")
+		return
+	}
+
+	run, err := s.scheduler.Trigger(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, schedule.ErrUnknownJob):
+			status = http.StatusNotFound
+		case errors.Is(err, schedule.ErrRunning):
+			status = http.StatusConflict
+		}
+
+		writeAPIError(w, status, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
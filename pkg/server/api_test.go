@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
+	"github.com/ethpandaops/panda/pkg/tokenstore"
+)
+
+// failingSandbox fails every execution with err.
+type failingSandbox struct {
+	sandbox.Service
+
+	err error
+}
+
+func (f *failingSandbox) Name() string { return "fake" }
+
+func (f *failingSandbox) Execute(context.Context, sandbox.ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	return nil, f.err
+}
+
+func TestAPIExecuteErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{
+			name:   "unknown session",
+			err:    fmt.Errorf("getting session: %w", fmt.Errorf("%w: abc", sandbox.ErrSessionNotFound)),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "foreign session",
+			err:    fmt.Errorf("getting session: %w", fmt.Errorf("%w: abc", sandbox.ErrSessionNotOwned)),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown profile",
+			err:    fmt.Errorf("%w %q", config.ErrUnknownProfile, "huge"),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "backend failure",
+			err:    errors.New("creating container: docker daemon unavailable"),
+			status: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Server.BaseURL = "http://localhost:2480"
+			cfg.Sandbox.Timeout = 30
+
+			s := &service{
+				execService: execsvc.New(
+					logrus.New(),
+					&failingSandbox{err: tt.err},
+					cfg,
+					module.NewRegistry(logrus.New()),
+					tokenstore.New(time.Hour),
+					nil,
+				),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/execute", strings.NewReader(`{"code":"print(1)","session_id":"abc"}`))
+			rec := httptest.NewRecorder()
+			s.handleAPIExecute(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// workspaceSandbox records the session files written to it.
+type workspaceSandbox struct {
+	sandbox.Service
+
+	files map[string]string
+}
+
+func (f *workspaceSandbox) WriteSessionFile(_ context.Context, sessionID, ownerID, name string, data []byte) error {
+	f.files[sessionID+"/"+ownerID+"/"+name] = string(data)
+
+	return nil
+}
+
+func TestStorageUploadWritesSessionFile(t *testing.T) {
+	signer, err := storage.NewUploadSigner(storage.UploadURLConfig{TTL: time.Minute}, "http://localhost:2480")
+	if err != nil {
+		t.Fatalf("NewUploadSigner: %v", err)
+	}
+
+	sb := &workspaceSandbox{files: make(map[string]string, 1)}
+	s := &service{
+		execService:  execsvc.New(logrus.New(), sb, &config.Config{}, module.NewRegistry(logrus.New()), tokenstore.New(time.Hour), nil),
+		uploadSigner: signer,
+	}
+
+	r := chi.NewRouter()
+	s.mountAPIRoutes(r)
+
+	uploadURL, _ := signer.Sign("abc", "42", "data/blocks.parquet")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, uploadURL, strings.NewReader("PAR1")))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, map[string]string{"abc/42/data/blocks.parquet": "PAR1"}, sb.files)
+
+	// The signature covers the path, so a URL can't be reused for another file.
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, strings.Replace(uploadURL, "blocks.parquet", "other.parquet", 1), strings.NewReader("x")))
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/storage"
+)
+
+// bearerPublicPaths are reachable without a bearer token: probes and the
+// proxy auth metadata the CLI needs before it can log in.
+var bearerPublicPaths = map[string]bool{
+	"/health":            true,
+	"/ready":             true,
+	"/health/modules":    true,
+	"/api/v1/proxy/auth": true,
+}
+
+// bearerPublicPrefixes are routes with credentials of their own: the admin
+// token, runtime tokens, public storage files and signed upload URLs.
+var bearerPublicPrefixes = []string{
+	"/api/v1/admin/",
+	"/api/v1/runtime/",
+	"/api/v1/storage/files/",
+	storage.UploadPathPrefix,
+}
+
+// bearerAuthMiddleware requires a valid proxy-issued bearer token on the MCP
+// and API routes when auth.bearer is set, and attaches its user to the
+// request context for tool policies, datasource scopes and state ownership.
+func (s *service) bearerAuthMiddleware(next http.Handler) http.Handler {
+	if s.bearerAuth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bearerPublicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for _, prefix := range bearerPublicPrefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			writeBearerError(w, "missing or invalid Authorization header")
+			return
+		}
+
+		user, err := s.bearerAuth.Verify(strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer ")))
+		if err != nil {
+			writeBearerError(w, err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithAuthUser(r.Context(), user)))
+	})
+}
+
+func writeBearerError(w http.ResponseWriter, description string) {
+	w.Header().Set("WWW-Authenticate",
+		fmt.Sprintf(`Bearer error="invalid_token", error_description="%s"`, description))
+	writeAPIError(w, http.StatusUnauthorized, description)
+}
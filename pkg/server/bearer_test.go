@@ -0,0 +1,200 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/tokenstore"
+)
+
+const (
+	testIssuerURL = "https://proxy.example.com"
+	testTokenKey  = "test-secret-key"
+)
+
+// mintTestToken signs a service-account token for name, a member of orgs.
+func mintTestToken(t *testing.T, name string, orgs ...string) string {
+	t.Helper()
+
+	token, _, _, err := auth.MintServiceAccountToken(auth.Config{
+		IssuerURL: testIssuerURL,
+		Tokens:    auth.TokensConfig{SecretKey: testTokenKey},
+	}, auth.ServiceAccount{
+		Name:        name,
+		Orgs:        orgs,
+		Datasources: []string{"*"},
+		Tools:       []string{"*"},
+	}, time.Now())
+	require.NoError(t, err)
+
+	return token
+}
+
+func newBearerTestService(rules []auth.ToolRule) *service {
+	cfg := &config.Config{}
+	cfg.Sandbox.Timeout = 30
+
+	return &service{
+		log: logrus.New(),
+		bearerAuth: auth.NewTokenVerifier(auth.BearerConfig{
+			IssuerURL: testIssuerURL,
+			Tokens:    auth.TokensConfig{SecretKey: testTokenKey},
+		}),
+		toolPolicy: auth.NewToolPolicy(rules),
+		execService: execsvc.New(
+			logrus.New(),
+			&failingSandbox{err: errors.New("docker daemon unavailable")},
+			cfg,
+			module.NewRegistry(logrus.New()),
+			tokenstore.New(time.Hour),
+			nil,
+		),
+	}
+}
+
+func TestBearerAuthAttachesUserToMCPRoutes(t *testing.T) {
+	s := newBearerTestService(nil)
+
+	handler := s.buildHTTPHandler(map[string]http.Handler{
+		"/mcp": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(auth.OwnerID(r.Context())))
+		}),
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{name: "health is public", path: "/health", status: http.StatusOK, body: "ok"},
+		{name: "missing token", path: "/mcp", status: http.StatusUnauthorized},
+		{name: "forged token", path: "/mcp", token: "not-a-jwt", status: http.StatusUnauthorized},
+		{name: "valid token", path: "/mcp", token: mintTestToken(t, "ci"), status: http.StatusOK, body: "service-account:ci"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.path == "/health" {
+				req.Method = http.MethodGet
+			}
+
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+
+			if tt.body != "" {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBearerAuthAppliesToolPoliciesToAPIRoutes(t *testing.T) {
+	s := newBearerTestService([]auth.ToolRule{
+		{Tools: []string{"execute_python"}, AllowedOrgs: []string{"ethpandaops"}},
+	})
+
+	handler := s.buildHTTPHandler(nil)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "missing token", status: http.StatusUnauthorized},
+		{name: "outside allowed orgs", token: mintTestToken(t, "outsider", "other"), status: http.StatusForbidden},
+		// The request reaches the handler, which fails on the sandbox.
+		{name: "member of allowed org", token: mintTestToken(t, "member", "ethpandaops"), status: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/execute", strings.NewReader(`{"code":"print(1)","session_id":"abc"}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestBearerAuthAppliesPolicyEngineToAPIRoutes(t *testing.T) {
+	var inputs []auth.PolicyInput
+
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input auth.PolicyInput `json:"input"`
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		inputs = append(inputs, body.Input)
+
+		if body.Input.User.Username == auth.ServiceAccountSubjectPrefix+"alice" {
+			_, _ = w.Write([]byte(`{"result": {"allow": true}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"result": {"allow": false, "reason": "alice only"}}`))
+	}))
+	t.Cleanup(opa.Close)
+
+	s := newBearerTestService(nil)
+	s.policyEngine = auth.NewPolicyAuthorizer(logrus.New(), &auth.PolicyEngineConfig{
+		OPA:      &auth.OPAConfig{URL: opa.URL},
+		CacheTTL: -1,
+	})
+
+	handler := s.buildHTTPHandler(nil)
+
+	execute := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/execute", strings.NewReader(`{"code":"print(1)","session_id":"abc"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := execute(mintTestToken(t, "bob", "ethpandaops"))
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "alice only")
+
+	// The request reaches the handler, which fails on the sandbox.
+	rec = execute(mintTestToken(t, "alice", "ethpandaops"))
+	assert.Equal(t, http.StatusBadGateway, rec.Code, rec.Body.String())
+
+	require.Len(t, inputs, 2)
+	assert.Equal(t, "execute_python", inputs[0].Tool)
+	assert.Equal(t, map[string]any{"code": "print(1)", "session_id": "abc"}, inputs[0].Args)
+	assert.True(t, inputs[0].User.Authenticated)
+	assert.Equal(t, auth.ServiceAccountSubjectPrefix+"bob", inputs[0].User.Subject)
+	assert.Contains(t, inputs[0].User.Groups, "ethpandaops")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -12,18 +13,29 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/ethpandaops/panda/pkg/app"
+	"github.com/ethpandaops/panda/pkg/auth"
 	"github.com/ethpandaops/panda/pkg/cartographoor"
+	"github.com/ethpandaops/panda/pkg/clientstate"
 	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/cronexpr"
+	"github.com/ethpandaops/panda/pkg/exampleusage"
 	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/history"
 	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/prompt"
+	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/resource"
 	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/schedule"
 	"github.com/ethpandaops/panda/pkg/searchruntime"
 	"github.com/ethpandaops/panda/pkg/searchsvc"
 	"github.com/ethpandaops/panda/pkg/serverapi"
 	"github.com/ethpandaops/panda/pkg/storage"
 	"github.com/ethpandaops/panda/pkg/tokenstore"
 	"github.com/ethpandaops/panda/pkg/tool"
+	"github.com/ethpandaops/panda/pkg/userexamples"
+	"github.com/ethpandaops/panda/pkg/webhook"
+	"github.com/ethpandaops/panda/runbooks"
 )
 
 // Dependencies contains all the services required to run the MCP server.
@@ -60,7 +72,31 @@ func (b *Builder) Build(ctx context.Context) (Service, error) {
 		return nil, err
 	}
 
-	searchRuntime, err := searchruntime.Build(ctx, b.log, application.ModuleRegistry, application.ProxyClient, b.cfg.Storage.CacheDir)
+	// Promoted examples join the module examples before the search index is built.
+	var userExamples *userexamples.Store
+
+	if b.cfg.UserExamples.Enabled {
+		var err error
+
+		userExamples, err = userexamples.Open(b.cfg.UserExamples.Dir)
+		if err != nil {
+			_ = application.Stop(ctx)
+			return nil, fmt.Errorf("opening user examples: %w", err)
+		}
+
+		application.ModuleRegistry.AddExamplesSource(userExamples)
+	}
+
+	searchRuntime, err := searchruntime.Build(
+		ctx,
+		b.log,
+		application.ModuleRegistry,
+		application.ProxyClient,
+		b.cfg.SemanticSearch,
+		b.cfg.Runbooks,
+		b.cfg.Storage.CacheDir,
+		b.cfg.Offline.Enabled,
+	)
 	if err != nil {
 		_ = application.Stop(ctx)
 		return nil, fmt.Errorf("building search runtime: %w", err)
@@ -74,34 +110,199 @@ func (b *Builder) Build(ctx context.Context) (Service, error) {
 		searchRuntime.EIPIndex,
 		searchRuntime.EIPRegistry,
 	)
+	searchSvc.SetKeywordOnly(searchRuntime.KeywordOnly)
+
+	var exampleUsage *exampleusage.Store
+
+	if b.cfg.ExampleUsage.Enabled {
+		exampleUsage, err = exampleusage.Open(b.cfg.ExampleUsage.Dir)
+		if err != nil {
+			_ = searchRuntime.Close()
+			_ = application.Stop(ctx)
+
+			return nil, fmt.Errorf("opening example usage: %w", err)
+		}
+
+		searchSvc.SetUsage(exampleUsage, b.cfg.ExampleUsage.Weight)
+	}
 
 	runtimeTokens := tokenstore.New(2 * time.Hour)
 
+	var historyStore history.Store
+
+	if b.cfg.History.IsEnabled() {
+		fileStore, err := history.NewFileStore(b.cfg.History.Path, b.cfg.History.MaxEntries)
+		if err != nil {
+			_ = searchRuntime.Close()
+			_ = application.Stop(ctx)
+
+			return nil, fmt.Errorf("opening execution history: %w", err)
+		}
+
+		historyStore = fileStore
+	}
+
 	execSvc := execsvc.New(
 		b.log,
 		application.Sandbox,
 		b.cfg,
 		application.ModuleRegistry,
 		runtimeTokens,
+		historyStore,
 	)
 
+	// Restore metadata of sessions kept running by the previous instance.
+	if restored, err := execSvc.LoadSessionState(b.cfg.Server.Drain.StateFile); err != nil {
+		b.log.WithError(err).Warn("Failed to restore session state")
+	} else if restored > 0 {
+		b.log.WithField("sessions", restored).Info("Restored session state")
+	}
+
+	if exampleUsage != nil {
+		execSvc.SetSuccessHook(func(ownerID, code string) {
+			credited, err := exampleUsage.RecordExecution(ownerID, code)
+			if err != nil {
+				b.log.WithError(err).Warn("Failed to record example usage")
+			}
+
+			for _, key := range credited {
+				b.log.WithFields(logrus.Fields{
+					"category": key.CategoryKey,
+					"example":  key.ExampleName,
+				}).Debug("Credited example use to execution")
+			}
+		})
+	}
+
+	// Resolve server base URL for storage URL construction.
+	serverBaseURL := strings.TrimSpace(b.cfg.Server.BaseURL)
+	if serverBaseURL == "" {
+		serverBaseURL = fmt.Sprintf("http://localhost:%d", b.cfg.Server.Port)
+	}
+
+	// Create local file storage service.
+	storageSvc := storage.New(
+		afero.NewOsFs(),
+		b.cfg.Storage.BaseDir,
+		serverBaseURL,
+	)
+
+	// Charts executions save to their workspace are uploaded here.
+	execSvc.SetStorage(storageSvc)
+
+	// Large put_file inputs are uploaded to presigned URLs instead of
+	// being sent inline.
+	uploadSigner, err := storage.NewUploadSigner(storage.UploadURLConfig{
+		TTL:        b.cfg.Storage.Uploads.URLTTL,
+		SigningKey: b.cfg.Storage.Uploads.SigningKey,
+	}, serverBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("creating upload signer: %w", err)
+	}
+
+	var historyExporter *history.Exporter
+
+	if historyStore != nil && b.cfg.History.Export.Enabled {
+		historyExporter = history.NewExporter(b.log, history.ExportConfig{
+			Interval:      b.cfg.History.Export.Interval,
+			RetentionDays: b.cfg.History.Export.RetentionDays,
+			IncludeCode:   b.cfg.History.Export.IncludeCode,
+		}, historyStore, storageSvc)
+		historyExporter.Start()
+	}
+
+	var storageRetainer *storage.Retainer
+
+	if b.cfg.Storage.Retention.Enabled {
+		storageRetainer = storage.NewRetainer(b.log, storage.RetentionConfig{
+			TTL:      b.cfg.Storage.Retention.TTL,
+			Interval: b.cfg.Storage.Retention.Interval,
+			DryRun:   b.cfg.Storage.Retention.DryRun,
+			Exclude:  b.cfg.Storage.Retention.Exclude,
+		}, storageSvc)
+		storageRetainer.Start()
+	}
+
+	var notifier *webhook.Notifier
+
+	if len(b.cfg.Webhooks.Endpoints) > 0 {
+		notifier, err = webhook.New(b.log, b.cfg.Webhooks)
+		if err != nil {
+			return nil, fmt.Errorf("creating webhooks: %w", err)
+		}
+
+		if threshold := b.cfg.Webhooks.LongExecutionThreshold; threshold > 0 && notifier.Wants(config.WebhookEventLongExecution) {
+			execSvc.SetLongRunningHook(threshold, func(executionID string, execution execsvc.RunningExecution) {
+				notifier.Notify(longExecutionEvent(executionID, execution, threshold))
+			})
+		}
+	}
+
+	var scheduler *schedule.Scheduler
+
+	if b.cfg.Schedules.Enabled {
+		jobs, err := b.buildScheduledJobs(searchRuntime.RunbookRegistry)
+		if err != nil {
+			return nil, fmt.Errorf("building scheduled jobs: %w", err)
+		}
+
+		scheduler = schedule.New(b.log, schedule.Config{KeepRuns: b.cfg.Schedules.KeepRuns}, jobs, execSvc, storageSvc)
+
+		if notifier != nil && notifier.Wants(config.WebhookEventScheduleFailure) {
+			scheduler.SetFailureHook(func(run schedule.Run) {
+				notifier.Notify(scheduleFailureEvent(run))
+			})
+		}
+
+		scheduler.Start()
+	}
+
 	// Create tool registry and register tools (MCP-server-specific).
 	toolReg := b.buildToolRegistry(
 		application.Sandbox,
 		execSvc,
 		searchSvc,
+		storageSvc,
+		uploadSigner,
+	)
+
+	// Health probe results are cached and shared by /health/modules and
+	// datasources://health.
+	healthChecker := module.NewHealthChecker(
+		application.ModuleRegistry, b.cfg.Server.HealthProbes.Interval, b.cfg.Server.HealthProbes.Timeout,
 	)
 
+	// Module health changes are only watched for when an endpoint wants them.
+	stopHealthWatch := func() {}
+	if notifier != nil && notifier.Wants(config.WebhookEventModuleHealth) {
+		stopHealthWatch = watchModuleHealth(notifier, healthChecker)
+	}
+
 	// Create resource registry and register resources (MCP-server-specific).
 	resourceReg := b.buildResourceRegistry(
 		application.Cartographoor,
 		application.ModuleRegistry,
+		healthChecker,
+		application.Sandbox,
 		toolReg,
+		execSvc,
+		application.ProxyClient,
+		scheduler,
 	)
 
 	cleanup := func(stopCtx context.Context) error {
 		var errs []error
 
+		stopHealthWatch()
+
+		if scheduler != nil {
+			scheduler.Stop()
+		}
+
+		if notifier != nil {
+			notifier.Stop()
+		}
+
 		if err := searchRuntime.Close(); err != nil {
 			errs = append(errs, err)
 		}
@@ -110,21 +311,24 @@ func (b *Builder) Build(ctx context.Context) (Service, error) {
 			errs = append(errs, err)
 		}
 
-		return errors.Join(errs...)
-	}
+		if storageRetainer != nil {
+			storageRetainer.Stop()
+		}
 
-	// Resolve server base URL for storage URL construction.
-	serverBaseURL := strings.TrimSpace(b.cfg.Server.BaseURL)
-	if serverBaseURL == "" {
-		serverBaseURL = fmt.Sprintf("http://localhost:%d", b.cfg.Server.Port)
-	}
+		if historyExporter != nil {
+			if err := historyExporter.Stop(stopCtx); err != nil {
+				errs = append(errs, err)
+			}
+		}
 
-	// Create local file storage service.
-	storageSvc := storage.New(
-		afero.NewOsFs(),
-		b.cfg.Storage.BaseDir,
-		serverBaseURL,
-	)
+		if historyStore != nil {
+			if err := historyStore.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
 
 	// Create and return the server service.
 	return NewService(
@@ -132,31 +336,141 @@ func (b *Builder) Build(ctx context.Context) (Service, error) {
 		b.cfg.Server,
 		toolReg,
 		resourceReg,
+		b.buildPromptRegistry(searchRuntime, searchSvc),
 		searchSvc,
 		execSvc,
 		application.ProxyClient,
 		storageSvc,
+		uploadSigner,
 		application.ModuleRegistry,
+		healthChecker,
 		application.Cartographoor,
 		buildProxyAuthMetadata(b.cfg),
 		runtimeTokens,
+		b.buildBearerAuth(),
+		b.buildToolPolicy(),
+		auth.NewPolicyAuthorizer(b.log, b.cfg.Auth.PolicyEngine),
+		userExamples,
+		scheduler,
+		b.cfg.Offline.Enabled,
 		cleanup,
 	), nil
 }
 
+// buildBearerAuth builds the verifier for client bearer tokens, or returns
+// nil when auth.bearer is unset and the routes stay open.
+func (b *Builder) buildBearerAuth() *auth.TokenVerifier {
+	if b.cfg.Auth.Bearer == nil {
+		return nil
+	}
+
+	b.log.WithField("issuer_url", b.cfg.Auth.Bearer.IssuerURL).Info("Requiring bearer tokens on MCP and API routes")
+
+	return auth.NewTokenVerifier(*b.cfg.Auth.Bearer)
+}
+
+// buildToolPolicy builds the tool policy from server.tool_policies. When the
+// server sends a service-account token to the proxy, every caller is also
+// limited to that token's tools, since the token is only ever checked by the
+// proxy.
+func (b *Builder) buildToolPolicy() *auth.ToolPolicy {
+	policy := auth.NewToolPolicy(b.cfg.Server.ToolPolicies)
+
+	if b.cfg.Proxy.Auth == nil || b.cfg.Proxy.Auth.Token == "" {
+		return policy
+	}
+
+	tools := auth.ServiceAccountTools(b.cfg.Proxy.Auth.Token)
+	if tools != nil {
+		b.log.WithField("tools", tools).Info("Limiting tools to the proxy service-account token's scopes")
+	}
+
+	return policy.WithTokenTools(tools)
+}
+
+// buildScheduledJobs turns the configured jobs into scheduler jobs. Code
+// from files and runbooks is read on every run.
+func (b *Builder) buildScheduledJobs(runbookReg *runbooks.Registry) ([]schedule.Job, error) {
+	jobs := make([]schedule.Job, 0, len(b.cfg.Schedules.Jobs))
+
+	for _, jobCfg := range b.cfg.Schedules.Jobs {
+		expr, err := cronexpr.Parse(jobCfg.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", jobCfg.Name, err)
+		}
+
+		job := schedule.Job{
+			Name:     jobCfg.Name,
+			Schedule: expr,
+			Timeout:  jobCfg.Timeout,
+			Profile:  jobCfg.Profile,
+		}
+
+		vars := jobCfg.Vars
+
+		switch {
+		case jobCfg.File != "":
+			path := jobCfg.File
+			job.Source = "file:" + path
+			job.Code = func() (string, error) {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return "", fmt.Errorf("reading %s: %w", path, err)
+				}
+
+				return runbooks.FillVars(string(data), vars), nil
+			}
+		case jobCfg.Runbook != "":
+			name := jobCfg.Runbook
+			job.Source = "runbook:" + name
+			job.Code = func() (string, error) {
+				if runbookReg == nil {
+					return "", fmt.Errorf("runbooks are unavailable")
+				}
+
+				rb := runbookReg.Get(name)
+				if rb == nil {
+					return "", fmt.Errorf("runbook %q not found", name)
+				}
+
+				script := runbooks.Script(rb.Content)
+				if script == "" {
+					return "", fmt.Errorf("runbook %q has no Python snippets", name)
+				}
+
+				return runbooks.FillVars(script, vars), nil
+			}
+		default:
+			code := runbooks.FillVars(jobCfg.Code, vars)
+			job.Source = "code"
+			job.Code = func() (string, error) { return code, nil }
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
 // buildToolRegistry creates and populates the tool registry.
 func (b *Builder) buildToolRegistry(
 	sandboxSvc sandbox.Service,
 	execSvc *execsvc.Service,
 	searchSvc *searchsvc.Service,
+	storageSvc storage.Service,
+	uploadSigner *storage.UploadSigner,
 ) tool.Registry {
 	reg := tool.NewRegistry(b.log)
 
+	// Per-client tool state is scoped by authenticated identity so several
+	// users can share one HTTP deployment.
+	clients := clientstate.New(clientstate.Config{})
+
 	// Register execute_python tool.
-	reg.Register(tool.NewExecutePythonTool(b.log, sandboxSvc, b.cfg, execSvc))
+	reg.Register(tool.NewExecutePythonTool(b.log, sandboxSvc, b.cfg, execSvc, clients))
 
 	// Register manage_session tool.
-	reg.Register(tool.NewManageSessionTool(b.log, execSvc))
+	reg.Register(tool.NewManageSessionTool(b.log, execSvc, storageSvc, uploadSigner))
 
 	// Register unified search tool (search runtime is required at startup).
 	reg.Register(tool.NewSearchTool(b.log, searchSvc))
@@ -166,6 +480,26 @@ func (b *Builder) buildToolRegistry(
 	return reg
 }
 
+// buildPromptRegistry creates the prompt registry with the canned
+// investigation workflows.
+func (b *Builder) buildPromptRegistry(
+	searchRuntime *searchruntime.Runtime,
+	searchSvc *searchsvc.Service,
+) prompt.Registry {
+	reg := prompt.NewRegistry(b.log)
+
+	var runbookProvider prompt.RunbookProvider
+	if searchRuntime.RunbookRegistry != nil {
+		runbookProvider = searchRuntime.RunbookRegistry
+	}
+
+	prompt.RegisterWorkflows(reg, runbookProvider, searchSvc)
+
+	b.log.WithField("prompt_count", len(reg.Definitions())).Info("Prompt registry built")
+
+	return reg
+}
+
 func buildProxyAuthMetadata(cfg *config.Config) *serverapi.ProxyAuthMetadataResponse {
 	if cfg == nil || cfg.Proxy.Auth == nil {
 		return &serverapi.ProxyAuthMetadataResponse{}
@@ -202,12 +536,21 @@ func buildProxyAuthMetadata(cfg *config.Config) *serverapi.ProxyAuthMetadataResp
 func (b *Builder) buildResourceRegistry(
 	cartographoorClient cartographoor.CartographoorClient,
 	moduleReg *module.Registry,
+	healthChecker *module.HealthChecker,
+	sandboxSvc sandbox.Service,
 	toolReg tool.Registry,
+	execSvc *execsvc.Service,
+	proxyClient proxy.Client,
+	scheduler *schedule.Scheduler,
 ) resource.Registry {
 	reg := resource.NewRegistry(b.log)
 
 	// Register datasources resources (from module registry).
-	resource.RegisterDatasourcesResources(b.log, reg, moduleReg)
+	datasourcePolicy := auth.NewDatasourcePolicy(b.cfg.Server.DatasourceScopes)
+	resource.RegisterDatasourcesResources(b.log, reg, moduleReg, datasourcePolicy)
+
+	// Register datasources://health (datasource info, probes and proxy reachability).
+	resource.RegisterDatasourceHealthResource(b.log, reg, moduleReg, datasourcePolicy, healthChecker, proxyClient)
 
 	// Register examples resources (from module registry).
 	resource.RegisterExamplesResources(b.log, reg, moduleReg)
@@ -218,6 +561,29 @@ func (b *Builder) buildResourceRegistry(
 	// Register Python library API resources (from module registry).
 	resource.RegisterAPIResources(b.log, reg, moduleReg)
 
+	// Register execution history resources.
+	if b.cfg.History.IsEnabled() {
+		resource.RegisterHistoryResources(b.log, reg, execSvc)
+	}
+
+	if proxyClient != nil {
+		// Register datasource quota usage resource.
+		resource.RegisterQuotaResources(b.log, reg, proxy.NewQuotaClient(proxyClient))
+
+		// Register admin-only proxy audit resources.
+		if len(b.cfg.Server.AdminOrgs) > 0 {
+			resource.RegisterAuditResources(b.log, reg, proxy.NewAuditClient(proxyClient), b.cfg.Server.AdminOrgs)
+		}
+	}
+
+	// Register scheduled job resources.
+	if scheduler != nil {
+		resource.RegisterScheduleResources(b.log, reg, scheduler)
+	}
+
+	// Register server info resource (sandbox profiles and GPU availability).
+	resource.RegisterServerInfoResources(b.log, reg, b.cfg, sandboxSvc)
+
 	// Register getting-started resource.
 	resource.RegisterGettingStartedResources(b.log, reg, toolReg, moduleReg)
 
@@ -228,11 +594,13 @@ func (b *Builder) buildResourceRegistry(
 			continue
 		}
 
-		if err := provider.RegisterResources(b.log, reg); err != nil {
+		if err := provider.RegisterResources(b.log, reg.ForModule(ext.Name())); err != nil {
 			b.log.WithError(err).WithField("module", ext.Name()).Warn("Failed to register module resources")
 		}
 	}
 
+	reg.SetModuleFilter(func(name string) bool { return !moduleReg.IsDisabled(name) })
+
 	staticCount := len(reg.ListStatic())
 	templateCount := len(reg.ListTemplates())
 
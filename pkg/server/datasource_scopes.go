@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// datasourceGroups returns the groups datasource access is scoped to for a
+// request: those of the execution behind a runtime token, or else those of
+// the authenticated user. nil means the request is not restricted.
+func (s *service) datasourceGroups(ctx context.Context) []string {
+	if executionID := runtimeExecutionID(ctx); executionID != "" && s.execService != nil {
+		if running, ok := s.execService.Running(executionID); ok {
+			return running.Groups
+		}
+	}
+
+	return auth.GetAuthGroups(ctx)
+}
+
+// datasourceAllowed reports whether the request may use a datasource.
+func (s *service) datasourceAllowed(ctx context.Context, dsType, name string) bool {
+	return s.datasourcePolicy.Allows(s.datasourceGroups(ctx), dsType, name)
+}
+
+// scopedDatasources returns the datasources the request may use.
+func (s *service) scopedDatasources(ctx context.Context, infos []types.DatasourceInfo) []types.DatasourceInfo {
+	return s.datasourcePolicy.Filter(s.datasourceGroups(ctx), infos)
+}
+
+// proxyDatasourceAllowed checks a proxy request against the datasource
+// scopes, like the proxy's own authorizer: ethnode and github are scoped by
+// type only, and the discovery and embedding routes are not scoped.
+func (s *service) proxyDatasourceAllowed(ctx context.Context, dsType, name string) bool {
+	switch dsType {
+	case "datasources", "embed", "unknown":
+		return true
+	case "ethnode", "github":
+		name = ""
+	}
+
+	return s.datasourceAllowed(ctx, dsType, name)
+}
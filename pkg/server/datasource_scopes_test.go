@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/serverapi"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// datasourceModule is a module that only reports datasources.
+type datasourceModule struct {
+	infos []types.DatasourceInfo
+}
+
+func (m *datasourceModule) Name() string                           { return "clickhouse" }
+func (m *datasourceModule) Init(_ []byte) error                    { return nil }
+func (m *datasourceModule) ApplyDefaults()                         {}
+func (m *datasourceModule) Validate() error                        { return nil }
+func (m *datasourceModule) Start(_ context.Context) error          { return nil }
+func (m *datasourceModule) Stop(_ context.Context) error           { return nil }
+func (m *datasourceModule) DatasourceInfo() []types.DatasourceInfo { return m.infos }
+
+func TestDatasourceScopesApplyToBearerUser(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	reg := module.NewRegistry(log)
+	reg.Add(&datasourceModule{infos: []types.DatasourceInfo{
+		{Type: "clickhouse", Name: "xatu"},
+		{Type: "clickhouse", Name: "internal"},
+	}})
+	require.NoError(t, reg.InitModule("clickhouse", nil))
+
+	s := newBearerTestService(nil)
+	s.moduleRegistry = reg
+	s.datasourcePolicy = auth.NewDatasourcePolicy([]auth.DatasourceScope{
+		{Groups: []string{"external-researchers"}, Datasources: []string{"clickhouse:xatu"}},
+		{Groups: []string{"ethpandaops"}, Datasources: []string{"*"}},
+	})
+
+	handler := s.buildHTTPHandler(nil)
+
+	tests := []struct {
+		name  string
+		token string
+		want  []string
+	}{
+		{name: "external researcher", token: mintTestToken(t, "researcher", "external-researchers"), want: []string{"xatu"}},
+		{name: "internal user", token: mintTestToken(t, "member", "ethpandaops"), want: []string{"xatu", "internal"}},
+		{name: "user in no scope", token: mintTestToken(t, "outsider", "other"), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/datasources", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var resp serverapi.DatasourcesResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+			names := make([]string, 0, len(resp.Datasources))
+			for _, info := range resp.Datasources {
+				names = append(names, info.Name)
+			}
+
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
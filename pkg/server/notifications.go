@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+const (
+	// notificationInterval is the minimum time between notification batches
+	// sent to one client.
+	notificationInterval = time.Second
+
+	// maxPendingNotifications caps the distinct notifications queued per
+	// client. Beyond it the batch collapses into one list_changed
+	// notification, which tells the client to re-list and re-read.
+	maxPendingNotifications = 50
+)
+
+// notificationBatcher coalesces notifications per client session and sends
+// them at most once per interval. A schema refresh touching hundreds of
+// tables becomes one small batch instead of a storm that queues ahead of
+// tool responses. Sessions whose notification channel is more than half
+// full are skipped until the client catches up.
+type notificationBatcher struct {
+	log        logrus.FieldLogger
+	send       func(sessionID, method string, params map[string]any) error
+	interval   time.Duration
+	maxPending int
+
+	mu       sync.Mutex
+	sessions map[string]*sessionNotifications
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// sessionNotifications holds the pending notifications of one session in
+// first-queued order, and the resource URIs the session subscribed to.
+type sessionNotifications struct {
+	session    mcpserver.ClientSession
+	subscribed map[string]struct{}
+	pending    map[string]queuedNotification
+	order      []string
+	overflow   bool
+}
+
+type queuedNotification struct {
+	method string
+	params map[string]any
+}
+
+func newNotificationBatcher(
+	log logrus.FieldLogger,
+	send func(sessionID, method string, params map[string]any) error,
+	interval time.Duration,
+	maxPending int,
+) *notificationBatcher {
+	return &notificationBatcher{
+		log:        log.WithField("component", "notifications"),
+		send:       send,
+		interval:   interval,
+		maxPending: maxPending,
+		sessions:   make(map[string]*sessionNotifications, 4),
+		done:       make(chan struct{}),
+	}
+}
+
+// Hooks returns MCP server hooks that track client sessions.
+func (b *notificationBatcher) Hooks() *mcpserver.Hooks {
+	hooks := &mcpserver.Hooks{}
+
+	hooks.AddOnRegisterSession(func(_ context.Context, session mcpserver.ClientSession) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.sessions[session.SessionID()] = &sessionNotifications{
+			session:    session,
+			subscribed: make(map[string]struct{}, 4),
+			pending:    make(map[string]queuedNotification, 8),
+		}
+	})
+
+	hooks.AddOnUnregisterSession(func(_ context.Context, session mcpserver.ClientSession) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.sessions, session.SessionID())
+	})
+
+	return hooks
+}
+
+// Subscribe records that a session wants resources/updated notifications
+// for uri. It returns false if the session is unknown.
+func (b *notificationBatcher) Subscribe(sessionID, uri string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.sessions[sessionID]
+	if !ok {
+		return false
+	}
+
+	s.subscribed[uri] = struct{}{}
+
+	return true
+}
+
+// Unsubscribe removes a session's subscription to uri. It returns false if
+// the session is unknown.
+func (b *notificationBatcher) Unsubscribe(sessionID, uri string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.sessions[sessionID]
+	if !ok {
+		return false
+	}
+
+	delete(s.subscribed, uri)
+
+	return true
+}
+
+// Notify queues a notification for every session. resources/updated
+// notifications only go to sessions subscribed to the resource URI.
+// Notifications with the same method and resource URI as one already
+// pending are coalesced.
+func (b *notificationBatcher) Notify(method string, params map[string]any) {
+	uri, hasURI := params["uri"].(string)
+
+	key := method
+	if hasURI {
+		key += " " + uri
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range b.sessions {
+		if method == mcp.MethodNotificationResourceUpdated {
+			if _, ok := s.subscribed[uri]; !ok {
+				continue
+			}
+		}
+
+		if _, ok := s.pending[key]; ok || s.overflow {
+			observability.MCPNotificationsTotal.WithLabelValues("coalesced").Inc()
+
+			continue
+		}
+
+		if len(s.pending) >= b.maxPending {
+			s.overflow = true
+			observability.MCPNotificationsTotal.WithLabelValues("coalesced").Add(float64(len(s.pending) + 1))
+
+			continue
+		}
+
+		s.pending[key] = queuedNotification{method: method, params: params}
+		s.order = append(s.order, key)
+	}
+}
+
+// Start runs the flush loop.
+func (b *notificationBatcher) Start() {
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.done:
+				return
+			case <-ticker.C:
+				b.flush()
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop. Pending notifications are discarded; clients
+// are disconnecting anyway.
+func (b *notificationBatcher) Stop() {
+	close(b.done)
+	b.wg.Wait()
+}
+
+// flush sends each session's pending batch unless the session is
+// backpressured.
+func (b *notificationBatcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, s := range b.sessions {
+		if len(s.order) == 0 && !s.overflow {
+			continue
+		}
+
+		if !s.session.Initialized() || backpressured(s.session) {
+			observability.MCPNotificationsTotal.WithLabelValues("deferred").Add(float64(len(s.order)))
+
+			continue
+		}
+
+		if s.overflow {
+			s.order = []string{mcp.MethodNotificationResourcesListChanged}
+			s.pending = map[string]queuedNotification{
+				mcp.MethodNotificationResourcesListChanged: {method: mcp.MethodNotificationResourcesListChanged},
+			}
+			s.overflow = false
+		}
+
+		sent := 0
+
+		for _, key := range s.order {
+			n := s.pending[key]
+			if err := b.send(id, n.method, n.params); err != nil {
+				b.log.WithError(err).WithField("session", id).Debug("Failed to send notification, retrying next batch")
+
+				break
+			}
+
+			delete(s.pending, key)
+
+			sent++
+		}
+
+		s.order = s.order[sent:]
+		observability.MCPNotificationsTotal.WithLabelValues("sent").Add(float64(sent))
+	}
+}
+
+// backpressured reports whether a session's notification channel is more
+// than half full.
+func backpressured(session mcpserver.ClientSession) bool {
+	ch := session.NotificationChannel()
+
+	return cap(ch) > 0 && len(ch) > cap(ch)/2
+}
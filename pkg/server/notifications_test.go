@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSession struct {
+	id string
+	ch chan mcp.JSONRPCNotification
+}
+
+func (f *fakeSession) Initialize()                                         {}
+func (f *fakeSession) Initialized() bool                                   { return true }
+func (f *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return f.ch }
+func (f *fakeSession) SessionID() string                                   { return f.id }
+
+func TestNotificationBatcher(t *testing.T) {
+	var sent []string
+
+	b := newNotificationBatcher(logrus.New(), func(sessionID, method string, params map[string]any) error {
+		sent = append(sent, fmt.Sprintf("%s %s %v", sessionID, method, params["uri"]))
+		return nil
+	}, notificationInterval, 3)
+
+	hooks := b.Hooks()
+	session := &fakeSession{id: "a", ch: make(chan mcp.JSONRPCNotification, 4)}
+	hooks.RegisterSession(t.Context(), session)
+
+	for _, uri := range []string{"clickhouse://tables", "clickhouse://tables/blocks", "networks://all"} {
+		require.True(t, b.Subscribe("a", uri))
+	}
+
+	for i := range 5 {
+		require.True(t, b.Subscribe("a", fmt.Sprintf("networks://devnet-%d", i)))
+	}
+
+	// Repeated URIs coalesce and keep their first-queued order.
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "clickhouse://tables"})
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "clickhouse://tables/blocks"})
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "clickhouse://tables"})
+	b.flush()
+
+	assert.Equal(t, []string{
+		"a notifications/resources/updated clickhouse://tables",
+		"a notifications/resources/updated clickhouse://tables/blocks",
+	}, sent)
+
+	// A backpressured session keeps its batch until the channel drains.
+	sent = nil
+
+	for range 3 {
+		session.ch <- mcp.JSONRPCNotification{}
+	}
+
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "networks://all"})
+	b.flush()
+	assert.Empty(t, sent)
+
+	for range 3 {
+		<-session.ch
+	}
+
+	b.flush()
+	assert.Equal(t, []string{"a notifications/resources/updated networks://all"}, sent)
+
+	// Too many distinct notifications collapse into one list_changed.
+	sent = nil
+
+	for i := range 5 {
+		b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": fmt.Sprintf("networks://devnet-%d", i)})
+	}
+
+	b.flush()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "a notifications/resources/list_changed <nil>", sent[0])
+
+	// Unregistered sessions receive nothing.
+	sent = nil
+
+	hooks.UnregisterSession(t.Context(), session)
+	b.Notify(mcp.MethodNotificationResourcesListChanged, nil)
+	b.flush()
+	assert.Empty(t, sent)
+}
+
+func TestNotificationBatcherSubscriptions(t *testing.T) {
+	var sent []string
+
+	b := newNotificationBatcher(logrus.New(), func(sessionID, method string, params map[string]any) error {
+		sent = append(sent, fmt.Sprintf("%s %s %v", sessionID, method, params["uri"]))
+		return nil
+	}, notificationInterval, 10)
+
+	hooks := b.Hooks()
+	hooks.RegisterSession(t.Context(), &fakeSession{id: "a", ch: make(chan mcp.JSONRPCNotification, 4)})
+	hooks.RegisterSession(t.Context(), &fakeSession{id: "b", ch: make(chan mcp.JSONRPCNotification, 4)})
+
+	assert.False(t, b.Subscribe("unknown", "networks://all"))
+	require.True(t, b.Subscribe("a", "networks://all"))
+
+	// Updates go only to subscribers; list changes go to every session.
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "networks://all"})
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "clickhouse://tables"})
+	b.Notify(mcp.MethodNotificationResourcesListChanged, nil)
+	b.flush()
+
+	assert.ElementsMatch(t, []string{
+		"a notifications/resources/updated networks://all",
+		"a notifications/resources/list_changed <nil>",
+		"b notifications/resources/list_changed <nil>",
+	}, sent)
+
+	// Unsubscribed sessions stop receiving updates.
+	sent = nil
+
+	require.True(t, b.Unsubscribe("a", "networks://all"))
+	b.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "networks://all"})
+	b.flush()
+	assert.Empty(t, sent)
+}
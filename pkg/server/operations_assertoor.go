@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	assertoormodule "github.com/ethpandaops/panda/modules/assertoor"
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+func (s *service) handleAssertoorOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "assertoor.list_networks":
+		s.handleAssertoorListNetworks(w)
+	case "assertoor.get_base_url":
+		s.handleAssertoorBaseURL(w, r)
+	case "assertoor.list_runs":
+		s.handleAssertoorGet(w, r, false)
+	case "assertoor.get_run":
+		s.handleAssertoorGet(w, r, true)
+	case "assertoor.summarize_failures":
+		s.handleAssertoorSummarizeFailures(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleAssertoorListNetworks(w http.ResponseWriter) {
+	networks, err := s.assertoorNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":          name,
+			"assertoor_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+func (s *service) handleAssertoorBaseURL(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.assertoorBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"base_url": baseURL},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+// handleAssertoorGet passes the run list, or a single run when withRun is
+// set, through unchanged.
+func (s *service) handleAssertoorGet(w http.ResponseWriter, r *http.Request, withRun bool) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path := assertoormodule.RunsPath
+
+	if withRun {
+		runID, err := assertoorRunIDArg(req.Args)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		path = assertoormodule.RunPath(runID)
+	}
+
+	baseURL, status, err := s.assertoorBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	body, contentType, status, err := s.assertoorGetRaw(r.Context(), baseURL, path)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, contentType, body)
+}
+
+func (s *service) handleAssertoorSummarizeFailures(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	runID, err := assertoorRunIDArg(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.assertoorBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	report, err := assertoormodule.SummarizeFailures(ctx, s.httpClient, baseURL, runID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	report.Network = optionalStringArg(req.Args, "network")
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: report,
+		Meta: map[string]any{"network": report.Network},
+	})
+}
+
+func (s *service) assertoorNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("assertoor is unavailable")
+	}
+
+	networks := make(map[string]string)
+	for name, network := range s.cartographoorClient.GetActiveNetworks() {
+		if network.ServiceURLs != nil && network.ServiceURLs.Assertoor != "" {
+			networks[name] = network.ServiceURLs.Assertoor
+		}
+	}
+
+	return networks, nil
+}
+
+func (s *service) assertoorBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.assertoorNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return strings.TrimRight(baseURL, "/"), http.StatusOK, nil
+}
+
+func (s *service) assertoorGetRaw(ctx context.Context, baseURL, path string) ([]byte, string, int, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("creating assertoor request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("executing assertoor request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("reading assertoor response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", resp.StatusCode, fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return body, contentType, http.StatusOK, nil
+}
+
+// assertoorRunIDArg reads the required positive run_id argument.
+func assertoorRunIDArg(args map[string]any) (uint64, error) {
+	runID := optionalIntArg(args, "run_id", 0)
+	if runID <= 0 {
+		return 0, fmt.Errorf("run_id is required and must be a positive integer")
+	}
+
+	return uint64(runID), nil
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+var (
+	// beaconIDPattern matches beacon API block and state identifiers.
+	beaconIDPattern = regexp.MustCompile(`^(head|genesis|finalized|justified|[0-9]+|0x[0-9a-fA-F]+)$`)
+
+	// beaconValidatorIDPattern matches validator indices and pubkeys.
+	beaconValidatorIDPattern = regexp.MustCompile(`^([0-9]+|0x[0-9a-fA-F]{96})$`)
+)
+
+func (s *service) handleBeaconOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "beacon.list_networks":
+		s.handleBeaconListNetworks(w)
+	case "beacon.get_block":
+		s.handleBeaconGet(w, r, "/eth/v2/beacon/blocks/%s", "block_id")
+	case "beacon.get_header":
+		s.handleBeaconGet(w, r, "/eth/v1/beacon/headers/%s", "block_id")
+	case "beacon.get_validator":
+		s.handleBeaconGet(w, r, "/eth/v1/beacon/states/%s/validators/%s", "state_id", "validator_id")
+	case "beacon.get_finality_checkpoints":
+		s.handleBeaconGet(w, r, "/eth/v1/beacon/states/%s/finality_checkpoints", "state_id")
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleBeaconListNetworks(w http.ResponseWriter) {
+	networks, err := s.beaconNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":       name,
+			"beacon_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+// handleBeaconGet resolves the network's beacon node and passes through a
+// GET of pathTemplate filled with the named identifier args, in order.
+// Block and state identifiers default to "head".
+func (s *service) handleBeaconGet(
+	w http.ResponseWriter,
+	r *http.Request,
+	pathTemplate string,
+	argNames ...string,
+) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.beaconBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	identifiers := make([]any, 0, len(argNames))
+	for _, argName := range argNames {
+		identifier, err := beaconIdentifierArg(req.Args, argName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
+	body, contentType, status, err := s.beaconAPIGetRaw(r.Context(), baseURL, fmt.Sprintf(pathTemplate, identifiers...))
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, contentType, body)
+}
+
+func beaconIdentifierArg(args map[string]any, argName string) (string, error) {
+	if argName == "validator_id" {
+		identifier, err := requiredStringArg(args, argName)
+		if err != nil {
+			return "", err
+		}
+
+		if !beaconValidatorIDPattern.MatchString(identifier) {
+			return "", fmt.Errorf("invalid validator_id %q: expected an index or 0x-prefixed pubkey", identifier)
+		}
+
+		return identifier, nil
+	}
+
+	identifier := optionalStringArg(args, argName)
+	if identifier == "" {
+		identifier = "head"
+	}
+
+	if !beaconIDPattern.MatchString(identifier) {
+		return "", fmt.Errorf("invalid %s %q: expected a slot, root, head, genesis, finalized or justified", argName, identifier)
+	}
+
+	return identifier, nil
+}
+
+func (s *service) beaconNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("beacon node APIs are unavailable")
+	}
+
+	networks := make(map[string]string)
+	for name, network := range s.cartographoorClient.GetActiveNetworks() {
+		if network.ServiceURLs != nil && network.ServiceURLs.BeaconRPC != "" {
+			networks[name] = network.ServiceURLs.BeaconRPC
+		}
+	}
+
+	return networks, nil
+}
+
+func (s *service) beaconBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.beaconNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return baseURL, http.StatusOK, nil
+}
+
+func (s *service) beaconAPIGetRaw(ctx context.Context, baseURL, path string) ([]byte, string, int, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, strings.TrimRight(baseURL, "/")+path, nil)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("creating beacon request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("executing beacon request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("reading beacon response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", resp.StatusCode, fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return body, contentType, http.StatusOK, nil
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	blobscanmodule "github.com/ethpandaops/panda/modules/blobscan"
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+var (
+	// blobscanBlockIDPattern matches block numbers and hashes.
+	blobscanBlockIDPattern = regexp.MustCompile(`^([0-9]+|0x[0-9a-fA-F]{64})$`)
+
+	// blobscanHashPattern matches versioned hashes and transaction hashes.
+	blobscanHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+)
+
+// maxBlobscanBlocks caps how many blocks get_recent_blocks returns.
+const maxBlobscanBlocks = 100
+
+func (s *service) handleBlobscanOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "blobscan.list_networks":
+		s.handleBlobscanListNetworks(w)
+	case "blobscan.get_base_url":
+		s.handleBlobscanBaseURL(w, r)
+	case "blobscan.get_recent_blocks":
+		s.handleBlobscanRecentBlocks(w, r)
+	case "blobscan.get_block":
+		s.handleBlobscanGet(w, r, "number_or_hash", blobscanBlockIDPattern, "/blocks/")
+	case "blobscan.get_blob":
+		s.handleBlobscanGet(w, r, "versioned_hash", blobscanHashPattern, "/blobs/")
+	case "blobscan.link_block":
+		s.handleBlobscanLink(w, r, "number_or_hash", blobscanBlockIDPattern, "block")
+	case "blobscan.link_blob":
+		s.handleBlobscanLink(w, r, "versioned_hash", blobscanHashPattern, "blob")
+	case "blobscan.link_tx":
+		s.handleBlobscanLink(w, r, "tx_hash", blobscanHashPattern, "tx")
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleBlobscanListNetworks(w http.ResponseWriter) {
+	networks, err := s.blobscanNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":         name,
+			"blobscan_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+func (s *service) handleBlobscanBaseURL(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.blobscanBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"base_url": baseURL},
+	})
+}
+
+func (s *service) handleBlobscanRecentBlocks(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.blobscanBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	limit := optionalIntArg(req.Args, "limit", 25)
+	if limit < 1 || limit > maxBlobscanBlocks {
+		http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxBlobscanBlocks), http.StatusBadRequest)
+		return
+	}
+
+	blocks, err := blobscanmodule.NewClient(s.httpClient).RecentBlocks(r.Context(), baseURL, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"blocks": blocks},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+func (s *service) handleBlobscanGet(
+	w http.ResponseWriter,
+	r *http.Request,
+	argName string,
+	pattern *regexp.Regexp,
+	pathPrefix string,
+) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.blobscanBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	identifier, err := blobscanIdentifierArg(req.Args, argName, pattern)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := blobscanmodule.NewClient(s.httpClient).Get(r.Context(), baseURL, pathPrefix+identifier, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, "application/json", body)
+}
+
+func (s *service) handleBlobscanLink(
+	w http.ResponseWriter,
+	r *http.Request,
+	argName string,
+	pattern *regexp.Regexp,
+	kind string,
+) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.blobscanBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	identifier, err := blobscanIdentifierArg(req.Args, argName, pattern)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"url": blobscanmodule.Link(baseURL, kind, identifier)},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+func blobscanIdentifierArg(args map[string]any, argName string, pattern *regexp.Regexp) (string, error) {
+	identifier, err := requiredStringArg(args, argName)
+	if err != nil {
+		return "", err
+	}
+
+	if !pattern.MatchString(identifier) {
+		return "", fmt.Errorf("invalid %s %q", argName, identifier)
+	}
+
+	return identifier, nil
+}
+
+func (s *service) blobscanNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("blobscan is unavailable")
+	}
+
+	return blobscanmodule.Networks(s.cartographoorClient), nil
+}
+
+func (s *service) blobscanBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.blobscanNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return baseURL, http.StatusOK, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	cbtmodule "github.com/ethpandaops/panda/modules/cbt"
 	"github.com/ethpandaops/panda/pkg/operations"
 )
 
@@ -37,6 +38,8 @@ func (s *service) handleCBTOperation(operationID string, w http.ResponseWriter,
 		s.handleCBTPassthrough(w, r, "/api/v1/interval/types")
 	case "cbt.link_model":
 		s.handleCBTLinkModel(w, r)
+	case "cbt.check_gaps":
+		s.handleCBTCheckGaps(w, r)
 	default:
 		return false
 	}
@@ -193,21 +196,49 @@ func (s *service) handleCBTLinkModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// CBT UI link: {base_url}/models/{database}/{table}
-	// ID format is "database.table".
-	parts := strings.SplitN(id, ".", 2)
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"url": cbtmodule.ModelLink(baseURL, id)},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+// handleCBTCheckGaps lists unprocessed gaps of at least min_size positions
+// across every transformation.
+func (s *service) handleCBTCheckGaps(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.cbtBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
 
-	var linkPath string
-	if len(parts) == 2 {
-		linkPath = fmt.Sprintf("/models/%s/%s", parts[0], parts[1])
-	} else {
-		linkPath = fmt.Sprintf("/models/%s", id)
+	minSize := optionalIntArg(req.Args, "min_size", 1)
+	if minSize < 0 {
+		http.Error(w, "min_size must not be negative", http.StatusBadRequest)
+		return
 	}
 
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	report, err := cbtmodule.CheckGaps(ctx, s.httpClient, baseURL, uint64(minSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	report.Network = optionalStringArg(req.Args, "network")
+
 	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
 		Kind: operations.ResultKindObject,
-		Data: map[string]any{"url": strings.TrimRight(baseURL, "/") + linkPath},
-		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+		Data: report,
+		Meta: map[string]any{"network": report.Network},
 	})
 }
 
@@ -218,7 +249,7 @@ func (s *service) cbtNetworks() (map[string]string, error) {
 
 	networks := make(map[string]string)
 	for name := range s.cartographoorClient.GetActiveNetworks() {
-		networks[name] = fmt.Sprintf("https://cbt.%s.ethpandaops.io", name)
+		networks[name] = cbtmodule.NetworkURL(name)
 	}
 
 	return networks, nil
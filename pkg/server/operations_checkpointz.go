@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	checkpointzmodule "github.com/ethpandaops/panda/modules/checkpointz"
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+func (s *service) handleCheckpointzOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "checkpointz.list_networks":
+		s.handleCheckpointzListNetworks(w)
+	case "checkpointz.get_base_url":
+		s.handleCheckpointzBaseURL(w, r, "base_url")
+	case "checkpointz.get_status":
+		s.handleCheckpointzGet(w, r, checkpointzmodule.StatusPath)
+	case "checkpointz.get_slots":
+		s.handleCheckpointzGet(w, r, checkpointzmodule.SlotsPath)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleCheckpointzListNetworks(w http.ResponseWriter) {
+	networks, err := s.checkpointzNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":            name,
+			"checkpointz_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+// handleCheckpointzBaseURL returns the network's checkpointz URL under key. It
+// is also the URL beacon nodes pass as their checkpoint sync endpoint.
+func (s *service) handleCheckpointzBaseURL(w http.ResponseWriter, r *http.Request, key string) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.checkpointzBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{key: baseURL},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+// handleCheckpointzGet passes a checkpointz API response through unchanged.
+func (s *service) handleCheckpointzGet(w http.ResponseWriter, r *http.Request, path string) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.checkpointzBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	body, contentType, status, err := s.checkpointzGetRaw(r.Context(), baseURL, path)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, contentType, body)
+}
+
+func (s *service) checkpointzNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("checkpointz is unavailable")
+	}
+
+	networks := make(map[string]string)
+	for name, network := range s.cartographoorClient.GetActiveNetworks() {
+		if network.ServiceURLs != nil && network.ServiceURLs.CheckpointSync != "" {
+			networks[name] = network.ServiceURLs.CheckpointSync
+		}
+	}
+
+	return networks, nil
+}
+
+func (s *service) checkpointzBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.checkpointzNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return strings.TrimRight(baseURL, "/"), http.StatusOK, nil
+}
+
+func (s *service) checkpointzGetRaw(ctx context.Context, baseURL, path string) ([]byte, string, int, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("creating checkpointz request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("executing checkpointz request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("reading checkpointz response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", resp.StatusCode, fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return body, contentType, http.StatusOK, nil
+}
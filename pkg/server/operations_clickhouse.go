@@ -1,20 +1,24 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
+	clickhousemodule "github.com/ethpandaops/panda/modules/clickhouse"
 	"github.com/ethpandaops/panda/pkg/operations"
 )
 
 func (s *service) handleClickHouseOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
 	switch operationID {
 	case "clickhouse.list_datasources":
-		s.handleClickHouseListDatasources(w)
+		s.handleClickHouseListDatasources(w, r)
 	case "clickhouse.query", "clickhouse.query_raw":
 		s.handleClickHouseQuery(w, r)
+	case "clickhouse.explain":
+		s.handleClickHouseExplain(w, r)
 	default:
 		return false
 	}
@@ -22,9 +26,9 @@ func (s *service) handleClickHouseOperation(operationID string, w http.ResponseW
 	return true
 }
 
-func (s *service) handleClickHouseListDatasources(w http.ResponseWriter) {
+func (s *service) handleClickHouseListDatasources(w http.ResponseWriter, r *http.Request) {
 	items := make([]map[string]any, 0)
-	for _, info := range s.proxyService.ClickHouseDatasourceInfo() {
+	for _, info := range s.scopedDatasources(r.Context(), s.proxyService.ClickHouseDatasourceInfo()) {
 		items = append(items, map[string]any{
 			"name":        info.Name,
 			"description": info.Description,
@@ -85,6 +89,60 @@ func (s *service) handleClickHouseQuery(w http.ResponseWriter, r *http.Request)
 	writePassthroughResponse(w, http.StatusOK, headers.Get("Content-Type"), body)
 }
 
+func (s *service) handleClickHouseExplain(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clusterName, err := requiredStringArg(req.Args, "cluster")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sql, err := requiredStringArg(req.Args, "sql")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := clickhousemodule.Explain(r.Context(), s.clickHouseJSONQuery, clusterName, sql)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: plan,
+	})
+}
+
+// clickHouseJSONQuery runs sql through the proxy with JSON output.
+func (s *service) clickHouseJSONQuery(ctx context.Context, datasource, sql string) ([]byte, error) {
+	body, status, _, err := s.proxyRequest(
+		ctx,
+		http.MethodPost,
+		"/clickhouse/?"+url.Values{"default_format": {"JSON"}}.Encode(),
+		strings.NewReader(sql),
+		http.Header{
+			proxyDatasourceHeader: []string{datasource},
+			"Content-Type":        []string{"text/plain"},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("query failed (%d): %s", status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
 func formatClickHouseParamValue(value any) string {
 	switch v := value.(type) {
 	case nil:
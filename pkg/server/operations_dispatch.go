@@ -8,14 +8,31 @@ import (
 
 const proxyDatasourceHeader = "X-Datasource"
 
+// Headers telling the proxy which user and execution a request is for, so
+// it can tag upstream queries with them.
+const (
+	proxyOnBehalfOfHeader  = "X-Panda-On-Behalf-Of"
+	proxyExecutionIDHeader = "X-Panda-Execution-ID"
+)
+
 func (s *service) dispatchOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
 	for _, handler := range []func(string, http.ResponseWriter, *http.Request) bool{
 		s.handleClickHouseOperation,
 		s.handlePrometheusOperation,
 		s.handleLokiOperation,
+		s.handleGrafanaOperation,
+		s.handleGitHubOperation,
+		s.handleHTTPJSONOperation,
 		s.handleDoraOperation,
+		s.handleBeaconOperation,
+		s.handleForkmonOperation,
+		s.handleBlobscanOperation,
+		s.handleCheckpointzOperation,
+		s.handleAssertoorOperation,
+		s.handleSyncoorOperation,
 		s.handleEthNodeOperation,
 		s.handleCBTOperation,
+		s.handleSelfOperation,
 	} {
 		if handler(operationID, w, r) {
 			return true
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethpandaops/panda/pkg/cartographoor"
 	"github.com/ethpandaops/panda/pkg/operations"
 )
 
@@ -115,12 +116,19 @@ func (s *service) handleDoraNetworkOverview(w http.ResponseWriter, r *http.Reque
 	}
 
 	payload, _ := data["data"].(map[string]any)
+	timing := s.doraChainTiming(r.Context(), optionalStringArg(req.Args, "network"))
+
 	overview := map[string]any{
 		"current_epoch":      payload["epoch"],
-		"current_slot":       multiplyEpoch(payload["epoch"]),
+		"current_slot":       doraCurrentSlot(timing, payload["epoch"], time.Now()),
+		"seconds_per_slot":   timing.SecondsPerSlot,
+		"slots_per_epoch":    timing.SlotsPerEpoch,
 		"finalized":          payload["finalized"],
 		"participation_rate": payload["globalparticipationrate"],
 	}
+	if timing.GenesisTime != 0 {
+		overview["genesis_time"] = timing.GenesisTime
+	}
 	if validatorInfo, ok := payload["validatorinfo"].(map[string]any); ok {
 		overview["active_validator_count"] = validatorInfo["active"]
 		overview["total_validator_count"] = validatorInfo["total"]
@@ -324,19 +332,106 @@ func (s *service) doraAPIGetRaw(
 	return body, contentType, http.StatusOK, nil
 }
 
-func multiplyEpoch(value any) any {
+// doraChainTiming returns the slot timing of a network. Cartographoor and
+// config overrides supply the genesis time and defaults; the network's
+// consensus config.yaml, when cartographoor lists one, supplies the preset
+// and slot duration.
+func (s *service) doraChainTiming(ctx context.Context, networkName string) cartographoor.ChainSpec {
+	timing := cartographoor.ChainSpec{
+		SecondsPerSlot: cartographoor.DefaultSecondsPerSlot,
+		SlotsPerEpoch:  cartographoor.DefaultSlotsPerEpoch,
+	}
+
+	if s.cartographoorClient == nil {
+		return timing
+	}
+
+	network, ok := s.cartographoorClient.GetNetwork(networkName)
+	if !ok {
+		return timing
+	}
+
+	lifecycle := s.cartographoorClient.GetLifecycle(network)
+	timing.SecondsPerSlot = lifecycle.SecondsPerSlot
+	timing.SlotsPerEpoch = lifecycle.SlotsPerEpoch
+
+	if lifecycle.GenesisTime != nil {
+		timing.GenesisTime = uint64(lifecycle.GenesisTime.Unix())
+	}
+
+	configURL := cartographoor.ConsensusConfigURL(network)
+	if configURL == "" {
+		return timing
+	}
+
+	spec, err := s.chainSpec(ctx, configURL)
+	if err != nil {
+		s.log.WithError(err).WithField("network", networkName).Debug("Failed to fetch consensus config")
+
+		return timing
+	}
+
+	if spec.SecondsPerSlot != 0 {
+		timing.SecondsPerSlot = spec.SecondsPerSlot
+	}
+
+	if spec.SlotsPerEpoch != 0 {
+		timing.SlotsPerEpoch = spec.SlotsPerEpoch
+	}
+
+	if timing.GenesisTime == 0 {
+		timing.GenesisTime = spec.GenesisTime
+	}
+
+	return timing
+}
+
+// chainSpec returns the parsed consensus config at configURL, fetching it
+// once per URL.
+func (s *service) chainSpec(ctx context.Context, configURL string) (cartographoor.ChainSpec, error) {
+	if cached, ok := s.chainSpecs.Load(configURL); ok {
+		return cached.(cartographoor.ChainSpec), nil
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	spec, err := cartographoor.FetchChainSpec(requestCtx, s.httpClient, configURL)
+	if err != nil {
+		return cartographoor.ChainSpec{}, err
+	}
+
+	s.chainSpecs.Store(configURL, spec)
+
+	return spec, nil
+}
+
+// doraCurrentSlot returns the wall-clock slot when the genesis time is known,
+// and otherwise the first slot of Dora's current epoch.
+func doraCurrentSlot(timing cartographoor.ChainSpec, epochValue any, now time.Time) any {
+	if slot, ok := timing.SlotAt(now); ok {
+		return slot
+	}
+
+	if epoch, ok := doraEpoch(epochValue); ok {
+		return epoch * timing.SlotsPerEpoch
+	}
+
+	return epochValue
+}
+
+// doraEpoch parses an epoch number from a Dora response.
+func doraEpoch(value any) (uint64, bool) {
 	switch epoch := value.(type) {
 	case float64:
-		return epoch * 32
+		return uint64(epoch), epoch >= 0
 	case json.Number:
-		if parsed, err := epoch.Int64(); err == nil {
-			return parsed * 32
-		}
+		parsed, err := strconv.ParseUint(epoch.String(), 10, 64)
+		return parsed, err == nil
 	case string:
-		if parsed, err := strconv.ParseInt(epoch, 10, 64); err == nil {
-			return parsed * 32
-		}
+		parsed, err := strconv.ParseUint(epoch, 10, 64)
+		return parsed, err == nil
 	}
 
-	return value
+	return 0, false
 }
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+// forkmonStatePath serves the head reported by each node forkmon monitors.
+const forkmonStatePath = "/state"
+
+func (s *service) handleForkmonOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "forkmon.list_networks":
+		s.handleForkmonListNetworks(w)
+	case "forkmon.get_base_url":
+		s.handleForkmonBaseURL(w, r, "base_url")
+	case "forkmon.get_state":
+		s.handleForkmonState(w, r)
+	case "forkmon.link_overview":
+		s.handleForkmonBaseURL(w, r, "url")
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleForkmonListNetworks(w http.ResponseWriter) {
+	networks, err := s.forkmonNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":        name,
+			"forkmon_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+// handleForkmonBaseURL returns the network's forkmon URL under key. The
+// forkmon UI is a single page, so its base URL is also the overview link.
+func (s *service) handleForkmonBaseURL(w http.ResponseWriter, r *http.Request, key string) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.forkmonBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{key: baseURL},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+func (s *service) handleForkmonState(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.forkmonBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	body, contentType, status, err := s.forkmonGetRaw(r.Context(), baseURL, forkmonStatePath)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, contentType, body)
+}
+
+func (s *service) forkmonNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("forkmon is unavailable")
+	}
+
+	networks := make(map[string]string)
+	for name, network := range s.cartographoorClient.GetActiveNetworks() {
+		if network.ServiceURLs != nil && network.ServiceURLs.Forkmon != "" {
+			networks[name] = network.ServiceURLs.Forkmon
+		}
+	}
+
+	return networks, nil
+}
+
+func (s *service) forkmonBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.forkmonNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return strings.TrimRight(baseURL, "/"), http.StatusOK, nil
+}
+
+func (s *service) forkmonGetRaw(ctx context.Context, baseURL, path string) ([]byte, string, int, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("creating forkmon request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("executing forkmon request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", http.StatusBadGateway, fmt.Errorf("reading forkmon response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", resp.StatusCode, fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return body, contentType, http.StatusOK, nil
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	githubmodule "github.com/ethpandaops/panda/modules/github"
+	"github.com/ethpandaops/panda/pkg/operations"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func (s *service) handleGitHubOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "github.list_repos":
+		s.handleGitHubListRepos(w, r)
+	case "github.search_issues":
+		s.handleGitHubSearchIssues(w, r)
+	case "github.list_releases":
+		s.handleGitHubListReleases(w, r)
+	case "github.get_release":
+		s.handleGitHubGetRelease(w, r)
+	case "github.client_versions":
+		s.handleGitHubClientVersions(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleGitHubListRepos(w http.ResponseWriter, r *http.Request) {
+	repos := s.gitHubRepos()
+	if !s.datasourceAllowed(r.Context(), "github", "") {
+		repos = nil
+	}
+
+	items := make([]map[string]any, 0)
+	for _, info := range repos {
+		items = append(items, map[string]any{
+			"name":        info.Name,
+			"description": info.Description,
+			"client":      info.Metadata["client"],
+		})
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"repos": items},
+	})
+}
+
+func (s *service) handleGitHubSearchIssues(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repos := make([]string, 0)
+	for _, value := range optionalSliceArg(req.Args, "repos") {
+		name, _ := value.(string)
+
+		repo, err := s.gitHubRepo(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		repos = append(repos, repo)
+	}
+
+	if len(repos) == 0 {
+		for _, info := range s.gitHubRepos() {
+			repos = append(repos, info.Name)
+		}
+	}
+
+	if len(repos) == 0 {
+		http.Error(w, "no GitHub repositories are configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	state := optionalStringArg(req.Args, "state")
+	if state != "" && state != "open" && state != "closed" {
+		http.Error(w, fmt.Sprintf("invalid state %q: must be open or closed", state), http.StatusBadRequest)
+		return
+	}
+
+	kind := optionalStringArg(req.Args, "kind")
+	if kind != "" && kind != "issue" && kind != "pr" {
+		http.Error(w, fmt.Sprintf("invalid kind %q: must be issue or pr", kind), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := githubmodule.SearchIssues(ctx, s.gitHubFetch, githubmodule.SearchQuery{
+		Text:  optionalStringArg(req.Args, "query"),
+		Repos: repos,
+		State: state,
+		Kind:  kind,
+		Limit: optionalIntArg(req.Args, "limit", 30),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: result,
+	})
+}
+
+func (s *service) handleGitHubListReleases(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repo, status, err := s.gitHubRepoArg(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	notes, _ := req.Args["notes"].(bool)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	releases, err := githubmodule.ListReleases(ctx, s.gitHubFetch, repo, optionalIntArg(req.Args, "limit", 10), notes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"releases": releases},
+		Meta: map[string]any{"repo": repo},
+	})
+}
+
+func (s *service) handleGitHubGetRelease(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repo, status, err := s.gitHubRepoArg(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	tag := optionalStringArg(req.Args, "tag")
+	if tag == "" {
+		tag = "latest"
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	release, err := githubmodule.GetRelease(ctx, s.gitHubFetch, repo, tag)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: release,
+	})
+}
+
+func (s *service) handleGitHubClientVersions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	report := githubmodule.ClientVersions(ctx, s.gitHubFetch, s.gitHubRepos())
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: report,
+	})
+}
+
+// gitHubFetch is a githubmodule.Fetcher over the proxy's /github route.
+func (s *service) gitHubFetch(ctx context.Context, path string, params url.Values, out any) error {
+	requestPath := "/github" + path
+	if len(params) > 0 {
+		requestPath += "?" + params.Encode()
+	}
+
+	body, status, _, err := s.proxyRequest(ctx, http.MethodGet, requestPath, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("github %s returned %d: %s", path, status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *service) gitHubRepos() []types.DatasourceInfo {
+	if s.proxyService == nil {
+		return nil
+	}
+
+	return s.proxyService.GitHubDatasourceInfo()
+}
+
+// gitHubRepo resolves name to a configured repository, ignoring case.
+func (s *service) gitHubRepo(name string) (string, error) {
+	repos := s.gitHubRepos()
+
+	names := make([]string, 0, len(repos))
+	for _, info := range repos {
+		if strings.EqualFold(info.Name, name) {
+			return info.Name, nil
+		}
+
+		names = append(names, info.Name)
+	}
+
+	sort.Strings(names)
+
+	return "", fmt.Errorf("unknown GitHub repository %q. Available: %v", name, names)
+}
+
+func (s *service) gitHubRepoArg(args map[string]any) (string, int, error) {
+	name, err := requiredStringArg(args, "repo")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	repo, err := s.gitHubRepo(name)
+	if err != nil {
+		return "", http.StatusNotFound, err
+	}
+
+	return repo, http.StatusOK, nil
+}
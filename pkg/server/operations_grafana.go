@@ -0,0 +1,214 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+func (s *service) handleGrafanaOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "grafana.list_datasources":
+		s.handleGrafanaListDatasources(w, r)
+	case "grafana.search_dashboards":
+		s.handleGrafanaSearchDashboards(w, r)
+	case "grafana.get_dashboard":
+		s.handleGrafanaGetDashboard(w, r)
+	case "grafana.render_panel":
+		s.handleGrafanaRenderPanel(w, r)
+	case "grafana.query_datasource":
+		s.handleGrafanaQueryDatasource(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleGrafanaListDatasources(w http.ResponseWriter, r *http.Request) {
+	items := make([]map[string]any, 0)
+	for _, info := range s.scopedDatasources(r.Context(), s.proxyService.GrafanaDatasourceInfo()) {
+		items = append(items, map[string]any{
+			"name":        info.Name,
+			"description": info.Description,
+			"url":         info.Metadata["url"],
+		})
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"datasources": items},
+	})
+}
+
+func (s *service) handleGrafanaSearchDashboards(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasource, err := requiredStringArg(req.Args, "datasource")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := url.Values{
+		"type":  {"dash-db"},
+		"limit": {fmt.Sprintf("%d", optionalIntArg(req.Args, "limit", 50))},
+	}
+
+	if query := optionalStringArg(req.Args, "query"); query != "" {
+		params.Set("query", query)
+	}
+
+	if tag := optionalStringArg(req.Args, "tag"); tag != "" {
+		params.Set("tag", tag)
+	}
+
+	s.proxyPassthroughGet(w, r, "/grafana/api/search", params, datasource)
+}
+
+func (s *service) handleGrafanaGetDashboard(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasource, err := requiredStringArg(req.Args, "datasource")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	uid, err := requiredStringArg(req.Args, "uid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.proxyPassthroughGet(w, r, "/grafana/api/dashboards/uid/"+url.PathEscape(uid), nil, datasource)
+}
+
+func (s *service) handleGrafanaRenderPanel(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasource, err := requiredStringArg(req.Args, "datasource")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	uid, err := requiredStringArg(req.Args, "uid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	panelID := optionalIntArg(req.Args, "panel_id", 0)
+	if panelID <= 0 {
+		http.Error(w, "panel_id is required", http.StatusBadRequest)
+		return
+	}
+
+	params := url.Values{
+		"panelId": {fmt.Sprintf("%d", panelID)},
+		"from":    {grafanaTimeArg(req.Args, "from", "now-6h")},
+		"to":      {grafanaTimeArg(req.Args, "to", "now")},
+		"width":   {fmt.Sprintf("%d", optionalIntArg(req.Args, "width", 1000))},
+		"height":  {fmt.Sprintf("%d", optionalIntArg(req.Args, "height", 500))},
+	}
+
+	// Grafana ignores the slug segment when resolving the dashboard by UID.
+	s.proxyPassthroughGet(w, r, "/grafana/render/d-solo/"+url.PathEscape(uid)+"/panel", params, datasource)
+}
+
+func (s *service) handleGrafanaQueryDatasource(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasource, err := requiredStringArg(req.Args, "datasource")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasourceUID, err := requiredStringArg(req.Args, "datasource_uid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := optionalMapArg(req.Args, "query")
+	if len(query) == 0 {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	target := make(map[string]any, len(query)+2)
+	maps.Copy(target, query)
+
+	target["datasource"] = map[string]any{"uid": datasourceUID}
+	if _, ok := target["refId"]; !ok {
+		target["refId"] = "A"
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"queries": []any{target},
+		"from":    grafanaTimeArg(req.Args, "from", "now-1h"),
+		"to":      grafanaTimeArg(req.Args, "to", "now"),
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("encoding query: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respBody, status, headers, err := s.proxyRequest(
+		r.Context(),
+		http.MethodPost,
+		"/grafana/api/ds/query",
+		bytes.NewReader(body),
+		http.Header{
+			proxyDatasourceHeader: []string{datasource},
+			"Content-Type":        []string{"application/json"},
+		},
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	// Grafana answers partially failed queries with 207 Multi-Status and
+	// per-refId errors in the body, which callers should see.
+	if status < 200 || status >= 300 {
+		http.Error(w, strings.TrimSpace(string(respBody)), status)
+		return
+	}
+
+	writePassthroughResponse(w, http.StatusOK, headers.Get("Content-Type"), respBody)
+}
+
+// grafanaTimeArg returns a Grafana time range argument, which Grafana parses
+// itself (relative "now-1h" or epoch milliseconds).
+func grafanaTimeArg(args map[string]any, key, fallback string) string {
+	if value := strings.TrimSpace(optionalStringArg(args, key)); value != "" {
+		return value
+	}
+
+	return fallback
+}
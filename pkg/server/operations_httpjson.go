@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	httpjsonmodule "github.com/ethpandaops/panda/modules/httpjson"
+	"github.com/ethpandaops/panda/pkg/operations"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func (s *service) handleHTTPJSONOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "http_json.list_datasources":
+		s.handleHTTPJSONListDatasources(w, r)
+	case "http_json.get":
+		s.handleHTTPJSONGet(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleHTTPJSONListDatasources(w http.ResponseWriter, r *http.Request) {
+	items := make([]map[string]any, 0)
+	for _, info := range s.scopedDatasources(r.Context(), s.proxyService.HTTPJSONDatasourceInfo()) {
+		items = append(items, map[string]any{
+			"name":          info.Name,
+			"description":   info.Description,
+			"network":       info.Metadata["network"],
+			"allowed_paths": httpjsonmodule.AllowedPaths(info),
+		})
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"datasources": items},
+	})
+}
+
+func (s *service) handleHTTPJSONGet(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasource, err := requiredStringArg(req.Args, "datasource")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path, err := requiredStringArg(req.Args, "path")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, ok := s.httpJSONDatasource(datasource)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown http_json endpoint %q", datasource), http.StatusNotFound)
+		return
+	}
+
+	// The proxy enforces the same rule; checking here gives a clearer error.
+	if !httpjsonmodule.PathAllowed(info, path) {
+		http.Error(w, fmt.Sprintf(
+			"path %q is not allowed for endpoint %q (allowed: %s)",
+			path, datasource, strings.Join(httpjsonmodule.AllowedPaths(info), ", "),
+		), http.StatusForbidden)
+		return
+	}
+
+	s.proxyPassthroughGet(w, r, "/httpjson"+path, httpJSONParams(optionalMapArg(req.Args, "params")), datasource)
+}
+
+func (s *service) httpJSONDatasource(name string) (types.DatasourceInfo, bool) {
+	for _, info := range s.proxyService.HTTPJSONDatasourceInfo() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+
+	return types.DatasourceInfo{}, false
+}
+
+// httpJSONParams converts operation params into a query string. List values
+// become repeated parameters; None values are dropped.
+func httpJSONParams(args map[string]any) url.Values {
+	params := make(url.Values, len(args))
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := args[key].(type) {
+		case nil:
+			continue
+		case []any:
+			for _, item := range value {
+				params.Add(key, formatHTTPJSONParam(item))
+			}
+		default:
+			params.Set(key, formatHTTPJSONParam(value))
+		}
+	}
+
+	return params
+}
+
+func formatHTTPJSONParam(value any) string {
+	// JSON numbers decode as float64; keep integers free of exponents.
+	if f, ok := value.(float64); ok && f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+
+	return fmt.Sprint(value)
+}
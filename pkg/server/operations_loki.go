@@ -12,7 +12,7 @@ import (
 func (s *service) handleLokiOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
 	switch operationID {
 	case "loki.list_datasources":
-		s.handleLokiListDatasources(w)
+		s.handleLokiListDatasources(w, r)
 	case "loki.query":
 		s.handleLokiQuery(w, r, true)
 	case "loki.query_instant":
@@ -28,9 +28,9 @@ func (s *service) handleLokiOperation(operationID string, w http.ResponseWriter,
 	return true
 }
 
-func (s *service) handleLokiListDatasources(w http.ResponseWriter) {
+func (s *service) handleLokiListDatasources(w http.ResponseWriter, r *http.Request) {
 	items := make([]map[string]any, 0)
-	for _, info := range s.proxyService.LokiDatasourceInfo() {
+	for _, info := range s.scopedDatasources(r.Context(), s.proxyService.LokiDatasourceInfo()) {
 		items = append(items, map[string]any{
 			"name":        info.Name,
 			"description": info.Description,
@@ -12,7 +12,7 @@ import (
 func (s *service) handlePrometheusOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
 	switch operationID {
 	case "prometheus.list_datasources":
-		s.handlePrometheusListDatasources(w)
+		s.handlePrometheusListDatasources(w, r)
 	case "prometheus.query":
 		s.handlePrometheusQuery(w, r, false)
 	case "prometheus.query_range":
@@ -28,9 +28,9 @@ func (s *service) handlePrometheusOperation(operationID string, w http.ResponseW
 	return true
 }
 
-func (s *service) handlePrometheusListDatasources(w http.ResponseWriter) {
+func (s *service) handlePrometheusListDatasources(w http.ResponseWriter, r *http.Request) {
 	items := make([]map[string]any, 0)
-	for _, info := range s.proxyService.PrometheusDatasourceInfo() {
+	for _, info := range s.scopedDatasources(r.Context(), s.proxyService.PrometheusDatasourceInfo()) {
 		items = append(items, map[string]any{
 			"name":        info.Name,
 			"description": info.Description,
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	selfmodule "github.com/ethpandaops/panda/modules/self"
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+func (s *service) handleSelfOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "self.metrics":
+		s.handleSelfMetrics(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// handleSelfMetrics returns the current samples of the server's own metrics
+// registry, the same values served on the observability /metrics endpoint.
+func (s *service) handleSelfMetrics(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	samples, err := selfmodule.Snapshot(prometheus.DefaultGatherer, optionalStringArg(req.Args, "prefix"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"samples": samples},
+	})
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	syncoormodule "github.com/ethpandaops/panda/modules/syncoor"
+	"github.com/ethpandaops/panda/pkg/operations"
+)
+
+func (s *service) handleSyncoorOperation(operationID string, w http.ResponseWriter, r *http.Request) bool {
+	switch operationID {
+	case "syncoor.list_networks":
+		s.handleSyncoorListNetworks(w)
+	case "syncoor.get_base_url":
+		s.handleSyncoorBaseURL(w, r)
+	case "syncoor.get_matrix":
+		s.handleSyncoorMatrix(w, r)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *service) handleSyncoorListNetworks(w http.ResponseWriter) {
+	networks, err := s.syncoorNetworks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(networks))
+	for name, baseURL := range networks {
+		items = append(items, map[string]any{
+			"name":        name,
+			"syncoor_url": baseURL,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"networks": items},
+	})
+}
+
+func (s *service) handleSyncoorBaseURL(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.syncoorBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: map[string]any{"base_url": baseURL},
+		Meta: map[string]any{"network": optionalStringArg(req.Args, "network")},
+	})
+}
+
+func (s *service) handleSyncoorMatrix(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeOperationRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	baseURL, status, err := s.syncoorBaseURL(req.Args)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	network := optionalStringArg(req.Args, "network")
+
+	matrix, err := syncoormodule.FetchMatrix(ctx, s.httpClient, network, baseURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeOperationResponse(s.log, w, http.StatusOK, operations.Response{
+		Kind: operations.ResultKindObject,
+		Data: matrix,
+		Meta: map[string]any{"network": network},
+	})
+}
+
+func (s *service) syncoorNetworks() (map[string]string, error) {
+	if s.cartographoorClient == nil {
+		return nil, fmt.Errorf("syncoor is unavailable")
+	}
+
+	networks := make(map[string]string)
+	for name, network := range s.cartographoorClient.GetActiveNetworks() {
+		if network.ServiceURLs != nil && network.ServiceURLs.Syncoor != "" {
+			networks[name] = network.ServiceURLs.Syncoor
+		}
+	}
+
+	return networks, nil
+}
+
+func (s *service) syncoorBaseURL(args map[string]any) (string, int, error) {
+	network, err := requiredStringArg(args, "network")
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	networks, err := s.syncoorNetworks()
+	if err != nil {
+		return "", http.StatusServiceUnavailable, err
+	}
+
+	baseURL, ok := networks[network]
+	if !ok {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", http.StatusNotFound, fmt.Errorf("unknown network %q. Available: %v", network, names)
+	}
+
+	return strings.TrimRight(baseURL, "/"), http.StatusOK, nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/proxy"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/tokenstore"
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+// fakeProxy forwards server requests to url without credentials.
+type fakeProxy struct {
+	proxy.Service
+
+	url string
+}
+
+func (p *fakeProxy) URL() string                     { return p.url }
+func (p *fakeProxy) RegisterToken(string) string     { return "none" }
+func (p *fakeProxy) RevokeToken(string)              {}
+func (p *fakeProxy) SignRequest(*http.Request) error { return nil }
+
+// operationSandbox runs code that calls one runtime operation on the server
+// with the execution's runtime token.
+type operationSandbox struct {
+	sandbox.Service
+
+	serverURL string
+}
+
+func (f *operationSandbox) Name() string { return "fake" }
+
+func (f *operationSandbox) SessionsEnabled() bool { return false }
+
+func (f *operationSandbox) Execute(ctx context.Context, req sandbox.ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	body := `{"args":{"datasource":"main","query":"up"}}`
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		f.serverURL+"/api/v1/runtime/operations/prometheus.query", strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+req.Env["ETHPANDAOPS_API_TOKEN"])
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	return &sandbox.ExecutionResult{ExitCode: 1, Stderr: resp.Status}, nil
+}
+
+func TestProxyRateLimitIsRecordedForExecution(t *testing.T) {
+	state := types.RateLimitState{
+		Limit:             10,
+		RequestsPerMinute: 60,
+		RetryAfterSeconds: 1,
+		RetryAt:           time.Date(2026, 10, 18, 12, 0, 1, 0, time.UTC),
+		ResetAt:           time.Date(2026, 10, 18, 12, 0, 10, 0, time.UTC),
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(proxy.RateLimitError{
+			Error:          types.RateLimitErrorCode,
+			Message:        "rate limit exceeded",
+			RateLimitState: state,
+		})
+	}))
+	t.Cleanup(upstream.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	cfg := &config.Config{}
+	cfg.Sandbox.Timeout = 30
+
+	tokens := tokenstore.New(time.Hour)
+	t.Cleanup(tokens.Stop)
+
+	sb := &operationSandbox{}
+	s := &service{
+		log:           log,
+		proxyService:  &fakeProxy{url: upstream.URL},
+		runtimeTokens: tokens,
+		httpClient:    &http.Client{},
+		execService:   execsvc.New(log, sb, cfg, module.NewRegistry(log), tokens, nil),
+	}
+
+	srv := httptest.NewServer(s.buildHTTPHandler(nil))
+	t.Cleanup(srv.Close)
+
+	sb.serverURL = srv.URL
+
+	result, err := s.execService.Execute(context.Background(), execsvc.ExecuteRequest{Code: "print(1)"})
+	require.NoError(t, err)
+	assert.Equal(t, "429 Too Many Requests", result.Stderr)
+	require.NotNil(t, result.RateLimit)
+	assert.Equal(t, state, *result.RateLimit)
+}
@@ -5,8 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	stdlog "log"
 	"net/http"
+	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,25 +19,34 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/panda/internal/version"
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/canonicaljson"
 	"github.com/ethpandaops/panda/pkg/cartographoor"
 	"github.com/ethpandaops/panda/pkg/config"
 	"github.com/ethpandaops/panda/pkg/execsvc"
 	"github.com/ethpandaops/panda/pkg/module"
 	"github.com/ethpandaops/panda/pkg/observability"
+	"github.com/ethpandaops/panda/pkg/prompt"
 	"github.com/ethpandaops/panda/pkg/proxy"
 	"github.com/ethpandaops/panda/pkg/resource"
+	"github.com/ethpandaops/panda/pkg/schedule"
 	"github.com/ethpandaops/panda/pkg/searchsvc"
 	"github.com/ethpandaops/panda/pkg/serverapi"
 	"github.com/ethpandaops/panda/pkg/storage"
+	"github.com/ethpandaops/panda/pkg/tlsconfig"
 	"github.com/ethpandaops/panda/pkg/tokenstore"
 	"github.com/ethpandaops/panda/pkg/tool"
 	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/pkg/userexamples"
 )
 
 // Service is the main MCP server service.
 type Service interface {
 	// Start initializes and starts the MCP server.
 	Start(ctx context.Context) error
+	// Drain stops accepting new tool calls and executions and waits for
+	// in-flight executions, up to the configured grace period, before Stop.
+	Drain(ctx context.Context)
 	// Stop gracefully shuts down the server.
 	Stop() error
 }
@@ -44,23 +57,36 @@ type service struct {
 	cfg                  config.ServerConfig
 	toolRegistry         tool.Registry
 	resourceRegistry     resource.Registry
+	promptRegistry       prompt.Registry
 	searchService        *searchsvc.Service
 	execService          *execsvc.Service
 	proxyService         proxy.Service
 	storageService       storage.Service
+	uploadSigner         *storage.UploadSigner
 	moduleRegistry       *module.Registry
+	healthChecker        *module.HealthChecker
+	bearerAuth           *auth.TokenVerifier
+	toolPolicy           *auth.ToolPolicy
+	datasourcePolicy     *auth.DatasourcePolicy
+	policyEngine         *auth.PolicyAuthorizer
+	userExamples         *userexamples.Store
+	scheduler            *schedule.Scheduler
 	cartographoorClient  cartographoor.CartographoorClient
 	proxyAuthMetadata    *serverapi.ProxyAuthMetadataResponse
 	runtimeTokens        *tokenstore.Store
+	offline              bool
 	cleanup              func(context.Context) error
 	httpClient           *http.Client
+	chainSpecs           sync.Map // consensus config URL -> cartographoor.ChainSpec
 	mcpServer            *mcpserver.MCPServer
+	notifications        *notificationBatcher
 	sseServer            *mcpserver.SSEServer
 	streamableHTTPServer *mcpserver.StreamableHTTPServer
 	httpServer           *http.Server
 	mu                   sync.Mutex
 	done                 chan struct{}
 	running              bool
+	draining             atomic.Bool
 }
 
 // NewService creates a new MCP server service.
@@ -69,31 +95,56 @@ func NewService(
 	cfg config.ServerConfig,
 	toolRegistry tool.Registry,
 	resourceRegistry resource.Registry,
+	promptRegistry prompt.Registry,
 	searchSvc *searchsvc.Service,
 	execSvc *execsvc.Service,
 	proxySvc proxy.Service,
 	storageSvc storage.Service,
+	uploadSigner *storage.UploadSigner,
 	moduleReg *module.Registry,
+	healthChecker *module.HealthChecker,
 	cartographoorClient cartographoor.CartographoorClient,
 	proxyAuthMetadata *serverapi.ProxyAuthMetadataResponse,
 	runtimeTokens *tokenstore.Store,
+	bearerAuth *auth.TokenVerifier,
+	toolPolicy *auth.ToolPolicy,
+	policyEngine *auth.PolicyAuthorizer,
+	userExamples *userexamples.Store,
+	scheduler *schedule.Scheduler,
+	offline bool,
 	cleanup func(context.Context) error,
 ) Service {
+	// Most server HTTP calls go to the proxy, so they share its TLS settings.
+	var transport http.RoundTripper
+	if proxySvc != nil {
+		transport = proxySvc.Transport()
+	}
+
 	return &service{
 		log:                 log.WithField("component", "server"),
 		cfg:                 cfg,
 		toolRegistry:        toolRegistry,
 		resourceRegistry:    resourceRegistry,
+		promptRegistry:      promptRegistry,
 		searchService:       searchSvc,
 		execService:         execSvc,
 		proxyService:        proxySvc,
 		storageService:      storageSvc,
+		uploadSigner:        uploadSigner,
 		moduleRegistry:      moduleReg,
+		healthChecker:       healthChecker,
+		bearerAuth:          bearerAuth,
+		toolPolicy:          toolPolicy,
+		datasourcePolicy:    auth.NewDatasourcePolicy(cfg.DatasourceScopes),
+		policyEngine:        policyEngine,
+		userExamples:        userExamples,
+		scheduler:           scheduler,
 		cartographoorClient: cartographoorClient,
 		proxyAuthMetadata:   proxyAuthMetadata,
 		runtimeTokens:       runtimeTokens,
+		offline:             offline,
 		cleanup:             cleanup,
-		httpClient:          &http.Client{Transport: &version.Transport{}, Timeout: 0},
+		httpClient:          &http.Client{Transport: &version.Transport{Base: transport}, Timeout: 0},
 		done:                make(chan struct{}),
 	}
 }
@@ -112,24 +163,91 @@ func (s *service) Start(ctx context.Context) error {
 
 	s.log.WithField("version", version.Version).Info("Starting MCP server")
 
+	// Resource change notifications are batched per client. The send
+	// function is only called from the flush loop, after mcpServer is set.
+	s.notifications = newNotificationBatcher(s.log, func(sessionID, method string, params map[string]any) error {
+		return s.mcpServer.SendNotificationToSpecificClient(sessionID, method, params)
+	}, notificationInterval, maxPendingNotifications)
+
 	// Create the MCP server
 	s.mcpServer = mcpserver.NewMCPServer(
 		"ethpandaops-panda",
 		version.Version,
 		mcpserver.WithToolCapabilities(true),
 		mcpserver.WithResourceCapabilities(true, true),
+		mcpserver.WithPromptCapabilities(false),
 		mcpserver.WithLogging(),
+		mcpserver.WithHooks(s.notifications.Hooks()),
 	)
 
+	s.resourceRegistry.SetUpdateHandler(func(uris []string) {
+		for _, uri := range uris {
+			s.notifications.Notify(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		}
+	})
+	s.notifications.Start()
+
 	// Register tools
 	s.registerTools()
 
 	// Register resources
 	s.registerResources()
 
+	// Register prompts
+	s.registerPrompts()
+
+	if s.cfg.Stdio {
+		stdioCtx, stop := context.WithCancel(ctx)
+		defer stop()
+
+		go s.runStdio(stdioCtx, stop)
+
+		ctx = stdioCtx
+	}
+
 	return s.runHTTP(ctx)
 }
 
+// Drain marks the server as draining so /ready fails and new tool calls are
+// rejected, waits for in-flight executions up to the grace period, and saves
+// session metadata when sessions outlive the server.
+func (s *service) Drain(ctx context.Context) {
+	if s.draining.Swap(true) {
+		return
+	}
+
+	s.log.WithField("grace_period", s.cfg.Drain.GracePeriod).Info("Draining MCP server")
+
+	if s.execService == nil {
+		return
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, s.cfg.Drain.GracePeriod)
+	defer cancel()
+
+	if remaining := s.execService.Drain(drainCtx); remaining > 0 {
+		s.log.WithField("in_flight", remaining).Warn("Grace period ended with executions still running")
+	} else {
+		s.log.Info("All in-flight executions finished")
+	}
+
+	if !s.execService.SessionsKeptOnShutdown() {
+		return
+	}
+
+	saved, err := s.execService.SaveSessionState(ctx, s.cfg.Drain.StateFile)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to save session state")
+
+		return
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"sessions": saved,
+		"path":     s.cfg.Drain.StateFile,
+	}).Info("Saved session state")
+}
+
 // Stop gracefully shuts down the server.
 func (s *service) Stop() error {
 	s.mu.Lock()
@@ -165,6 +283,11 @@ func (s *service) Stop() error {
 		}
 	}
 
+	if s.notifications != nil {
+		s.resourceRegistry.SetUpdateHandler(nil)
+		s.notifications.Stop()
+	}
+
 	if s.cleanup != nil {
 		if err := s.cleanup(shutdownCtx); err != nil {
 			s.log.WithError(err).Error("Failed to stop server dependencies")
@@ -214,9 +337,42 @@ func (s *service) registerResources() {
 	}
 }
 
-// wrapToolHandler wraps a tool handler with metrics.
+// registerPrompts registers all prompts with the MCP server.
+func (s *service) registerPrompts() {
+	for _, def := range s.promptRegistry.Definitions() {
+		s.log.WithField("prompt", def.Prompt.Name).Debug("Registering prompt with MCP server")
+
+		handler := def.Handler
+		s.mcpServer.AddPrompt(def.Prompt, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return handler(ctx, req.Params.Arguments)
+		})
+	}
+}
+
+// wrapToolHandler wraps a tool handler with the tool policy, the policy
+// engine and metrics.
 func (s *service) wrapToolHandler(toolName string, handler tool.Handler) mcpserver.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.draining.Load() {
+			observability.ToolCallsTotal.WithLabelValues(toolName, "draining").Inc()
+
+			return mcp.NewToolResultError(execsvc.ErrDraining.Error()), nil
+		}
+
+		if err := s.toolPolicy.Authorize(ctx, toolName); err != nil {
+			observability.ToolCallsTotal.WithLabelValues(toolName, "denied").Inc()
+			s.log.WithError(err).WithField("tool", toolName).Debug("Tool call denied by policy")
+
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.policyEngine.AuthorizeTool(ctx, toolName, req.GetArguments()); err != nil {
+			observability.ToolCallsTotal.WithLabelValues(toolName, "denied").Inc()
+			s.log.WithError(err).WithField("tool", toolName).Debug("Tool call denied by policy engine")
+
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		startTime := time.Now()
 
 		result, err := handler(ctx, req)
@@ -231,6 +387,13 @@ func (s *service) wrapToolHandler(toolName string, handler tool.Handler) mcpserv
 			return nil, err
 		}
 
+		// Tools report most failures as error results rather than Go errors.
+		if result != nil && result.IsError {
+			observability.ToolCallsTotal.WithLabelValues(toolName, "error").Inc()
+
+			return result, nil
+		}
+
 		observability.ToolCallsTotal.WithLabelValues(toolName, "success").Inc()
 
 		return result, nil
@@ -241,18 +404,16 @@ func (s *service) wrapToolHandler(toolName string, handler tool.Handler) mcpserv
 func (s *service) createResourceHandler(uri string) mcpserver.ResourceHandlerFunc {
 	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		ctx = types.WithClientContext(ctx, types.ClientContextMCP)
+		if err := s.policyEngine.AuthorizeResource(ctx, uri); err != nil {
+			return nil, err
+		}
+
 		content, mimeType, err := s.resourceRegistry.Read(ctx, uri)
 		if err != nil {
 			return nil, err
 		}
 
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      uri,
-				MIMEType: mimeType,
-				Text:     content,
-			},
-		}, nil
+		return resourceContents(uri, mimeType, content), nil
 	}
 }
 
@@ -260,18 +421,59 @@ func (s *service) createResourceHandler(uri string) mcpserver.ResourceHandlerFun
 func (s *service) createResourceTemplateHandler() mcpserver.ResourceTemplateHandlerFunc {
 	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		ctx = types.WithClientContext(ctx, types.ClientContextMCP)
+		if err := s.policyEngine.AuthorizeResource(ctx, req.Params.URI); err != nil {
+			return nil, err
+		}
+
 		content, mimeType, err := s.resourceRegistry.Read(ctx, req.Params.URI)
 		if err != nil {
 			return nil, err
 		}
 
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      req.Params.URI,
-				MIMEType: mimeType,
-				Text:     content,
-			},
-		}, nil
+		return resourceContents(req.Params.URI, mimeType, content), nil
+	}
+}
+
+// resourceContents wraps resource text with its content hash in _meta, so
+// clients can tell whether a cached copy is still current.
+func resourceContents(uri, mimeType, content string) []mcp.ResourceContents {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			Meta:     map[string]any{"contentHash": canonicaljson.Hash([]byte(content))},
+			URI:      uri,
+			MIMEType: mimeType,
+			Text:     content,
+		},
+	}
+}
+
+// runStdio serves MCP over stdin and stdout next to the HTTP transports.
+// Stdio clients launch the server as a subprocess, so stop is called to shut
+// the server down once the client disconnects.
+func (s *service) runStdio(ctx context.Context, stop context.CancelFunc) {
+	defer stop()
+
+	log := s.log.WithField("transport", "stdio")
+	log.Info("Running MCP server with stdio transport")
+
+	errorLog := log.WriterLevel(logrus.ErrorLevel)
+	defer func() { _ = errorLog.Close() }()
+
+	stdioServer := mcpserver.NewStdioServer(s.mcpServer)
+	stdioServer.SetErrorLogger(stdlog.New(errorLog, "", 0))
+
+	observability.ActiveConnections.Inc()
+	defer observability.ActiveConnections.Dec()
+
+	err := stdioServer.Listen(ctx, os.Stdin, os.Stdout)
+
+	switch {
+	case ctx.Err() != nil:
+		// The server is already shutting down.
+	case err != nil:
+		log.WithError(err).Error("Stdio transport failed, shutting down")
+	default:
+		log.Info("Stdio client disconnected, shutting down")
 	}
 }
 
@@ -299,10 +501,10 @@ func (s *service) runHTTP(ctx context.Context) error {
 	handler := s.buildHTTPHandler(map[string]http.Handler{
 		"/sse":       s.sseServer,
 		"/sse/*":     s.sseServer,
-		"/message":   s.sseServer,
-		"/message/*": s.sseServer,
-		"/mcp":       s.streamableHTTPServer,
-		"/mcp/*":     s.streamableHTTPServer,
+		"/message":   s.handleSubscriptions(s.sseServer, sseSessionID, true),
+		"/message/*": s.handleSubscriptions(s.sseServer, sseSessionID, true),
+		"/mcp":       s.handleSubscriptions(s.streamableHTTPServer, streamableSessionID, false),
+		"/mcp/*":     s.handleSubscriptions(s.streamableHTTPServer, streamableSessionID, false),
 	})
 
 	s.httpServer = &http.Server{
@@ -312,9 +514,36 @@ func (s *service) runHTTP(ctx context.Context) error {
 		IdleTimeout:       120 * time.Second,
 	}
 
+	if s.cfg.TLS.Enabled() {
+		tlsCfg, err := s.cfg.TLS.TLSConfig()
+		if err != nil {
+			return fmt.Errorf("configuring TLS: %w", err)
+		}
+
+		s.httpServer.TLSConfig = tlsCfg
+
+		if s.cfg.TLS.RequiresClientCert() {
+			// Sandboxes hold runtime tokens rather than client certificates,
+			// storage files are public by design, and upload URLs carry
+			// their own signature.
+			s.httpServer.Handler = tlsconfig.RequireClientCert(
+				handler, "/health", "/ready", "/api/v1/runtime/", "/api/v1/storage/files/",
+				storage.UploadPathPrefix,
+			)
+		}
+
+		s.log.WithField("mtls", s.cfg.TLS.RequiresClientCert()).Info("Serving HTTP transport over TLS")
+	}
+
 	errCh := make(chan error, 1)
 
 	go func() {
+		if s.httpServer.TLSConfig != nil {
+			errCh <- s.httpServer.ListenAndServeTLS("", "")
+
+			return
+		}
+
 		errCh <- s.httpServer.ListenAndServe()
 	}()
 
@@ -341,16 +570,15 @@ func (s *service) runHTTP(ctx context.Context) error {
 // buildHTTPHandler creates an HTTP handler with health, API, and MCP routes.
 func (s *service) buildHTTPHandler(routes map[string]http.Handler) http.Handler {
 	r := chi.NewRouter()
+	r.Use(s.bearerAuthMiddleware)
 
 	// Health endpoints.
 	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	r.Get("/ready", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ready"))
-	})
+	r.Get("/ready", s.handleReady)
+	r.Get("/health/modules", s.handleModuleHealth)
 
 	s.mountAPIRoutes(r)
 
@@ -362,5 +590,52 @@ func (s *service) buildHTTPHandler(routes map[string]http.Handler) http.Handler
 	return r
 }
 
+// handleReady reports readiness for traffic. A draining server reports 503
+// with the number of executions still running so load balancers stop
+// routing to it.
+func (s *service) handleReady(w http.ResponseWriter, _ *http.Request) {
+	if !s.draining.Load() {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ready"))
+
+		return
+	}
+
+	inFlight := 0
+	if s.execService != nil {
+		inFlight = s.execService.InFlight()
+	}
+
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = fmt.Fprintf(w, "draining: %d executions in flight", inFlight)
+}
+
+// handleModuleHealth reports the cached probe results of every active module.
+// The overall status is the worst status among probed modules.
+func (s *service) handleModuleHealth(w http.ResponseWriter, r *http.Request) {
+	if s.moduleRegistry == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "module registry is unavailable")
+		return
+	}
+
+	modules := s.healthChecker.Check(r.Context())
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Module < modules[j].Module })
+
+	response := serverapi.ModuleHealthResponse{Status: types.HealthStatusHealthy, Modules: modules}
+
+	for _, m := range modules {
+		switch m.Status {
+		case types.HealthStatusUnhealthy:
+			response.Status = types.HealthStatusUnhealthy
+		case types.HealthStatusDegraded:
+			if response.Status == types.HealthStatusHealthy {
+				response.Status = types.HealthStatusDegraded
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
 // Compile-time interface compliance check.
 var _ Service = (*service)(nil)
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/clientstate"
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/tokenstore"
+	"github.com/ethpandaops/panda/pkg/tool"
+)
+
+// sessionSandbox runs every execution in the same sandbox session.
+type sessionSandbox struct {
+	sandbox.Service
+}
+
+func (f *sessionSandbox) Name() string { return "fake" }
+
+func (f *sessionSandbox) SessionsEnabled() bool { return false }
+
+func (f *sessionSandbox) Execute(context.Context, sandbox.ExecuteRequest) (*sandbox.ExecutionResult, error) {
+	return &sandbox.ExecutionResult{Stdout: "ok", SessionID: "abc"}, nil
+}
+
+// serveMCP serves s's MCP tools and API over streamable HTTP.
+func serveMCP(t *testing.T, s *service) string {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	cfg := &config.Config{}
+	cfg.Sandbox.Timeout = 30
+
+	sb := &sessionSandbox{}
+	execSvc := execsvc.New(log, sb, cfg, module.NewRegistry(log), tokenstore.New(time.Hour), nil)
+
+	s.log = log
+	s.toolRegistry = tool.NewRegistry(log)
+	s.toolRegistry.Register(tool.NewExecutePythonTool(log, sb, cfg, execSvc, clientstate.New(clientstate.Config{})))
+	s.mcpServer = mcpserver.NewMCPServer("test", "test", mcpserver.WithToolCapabilities(true))
+	s.registerTools()
+
+	srv := httptest.NewServer(s.buildHTTPHandler(map[string]http.Handler{
+		"/mcp": mcpserver.NewStreamableHTTPServer(s.mcpServer),
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL + "/mcp"
+}
+
+// sawResourceTip opens an MCP session with token, runs code once and reports
+// whether the response carried the getting-started tip.
+func sawResourceTip(t *testing.T, url, token string) bool {
+	t.Helper()
+
+	var opts []transport.StreamableHTTPCOption
+	if token != "" {
+		opts = append(opts, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	}
+
+	c, err := mcpclient.NewStreamableHttpClient(url, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = c.Initialize(ctx, init)
+	require.NoError(t, err)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = tool.ExecutePythonToolName
+	req.Params.Arguments = map[string]any{"code": "print(1)"}
+
+	result, err := c.CallTool(ctx, req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.NotEmpty(t, result.Content)
+
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+
+	return strings.Contains(text.Text, "panda://getting-started")
+}
+
+func TestResourceTipIsTrackedPerMCPSessionWithoutAuth(t *testing.T) {
+	url := serveMCP(t, &service{})
+
+	assert.True(t, sawResourceTip(t, url, ""), "first client")
+	assert.True(t, sawResourceTip(t, url, ""), "second client on the same server")
+}
+
+func TestResourceTipIsTrackedPerAuthenticatedUser(t *testing.T) {
+	url := serveMCP(t, &service{
+		bearerAuth: auth.NewTokenVerifier(auth.BearerConfig{
+			IssuerURL: testIssuerURL,
+			Tokens:    auth.TokensConfig{SecretKey: testTokenKey},
+		}),
+	})
+
+	alice := mintTestToken(t, "alice")
+
+	assert.True(t, sawResourceTip(t, url, alice), "alice")
+	assert.False(t, sawResourceTip(t, url, alice), "alice in a new MCP session")
+	assert.True(t, sawResourceTip(t, url, mintTestToken(t, "bob")), "bob")
+}
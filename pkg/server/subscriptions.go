@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// JSON-RPC methods for resource subscriptions.
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// subscriptionRequest is the part of a JSON-RPC request needed to handle
+// resources/subscribe and resources/unsubscribe.
+type subscriptionRequest struct {
+	ID     mcp.RequestId `json:"id"`
+	Method string        `json:"method"`
+	Params struct {
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// handleSubscriptions answers resources/subscribe and resources/unsubscribe
+// requests, which the MCP library does not implement, and passes every other
+// request to next. sessionID extracts the MCP session of a request. sse
+// selects the SSE transport, which answers over the event stream instead of
+// the HTTP response.
+func (s *service) handleSubscriptions(next http.Handler, sessionID func(*http.Request) string, sse bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || s.notifications == nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Batches and anything that isn't a subscription request go to the
+		// MCP server untouched.
+		var req subscriptionRequest
+		if err := json.Unmarshal(body, &req); err != nil || req.ID.IsNil() ||
+			(req.Method != methodResourcesSubscribe && req.Method != methodResourcesUnsubscribe) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		id := sessionID(r)
+		response := s.subscribe(id, req)
+
+		if !sse {
+			writeJSON(w, http.StatusOK, response)
+
+			return
+		}
+
+		if err := s.sseServer.SendEventToSession(id, response); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// subscribe applies a subscription request for a session and returns the
+// JSON-RPC response.
+func (s *service) subscribe(sessionID string, req subscriptionRequest) any {
+	if req.Params.URI == "" {
+		return mcp.NewJSONRPCError(req.ID, mcp.INVALID_PARAMS, "uri is required", nil)
+	}
+
+	var ok bool
+
+	if req.Method == methodResourcesSubscribe {
+		ok = s.notifications.Subscribe(sessionID, req.Params.URI)
+	} else {
+		ok = s.notifications.Unsubscribe(sessionID, req.Params.URI)
+	}
+
+	if !ok {
+		return mcp.NewJSONRPCError(req.ID, mcp.INVALID_REQUEST, "unknown session", nil)
+	}
+
+	s.log.WithField("session", sessionID).WithField("uri", req.Params.URI).Debug(req.Method)
+
+	return mcp.NewJSONRPCResultResponse(req.ID, mcp.EmptyResult{})
+}
+
+// sseSessionID returns the session of an SSE message request.
+func sseSessionID(r *http.Request) string {
+	return r.URL.Query().Get("sessionId")
+}
+
+// streamableSessionID returns the session of a streamable HTTP request.
+func streamableSessionID(r *http.Request) string {
+	return r.Header.Get(mcpserver.HeaderKeySessionID)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/module"
+	"github.com/ethpandaops/panda/pkg/schedule"
+	"github.com/ethpandaops/panda/pkg/types"
+	"github.com/ethpandaops/panda/pkg/webhook"
+)
+
+// watchModuleHealth sends module_health events for status changes until
+// the returned func is called.
+func watchModuleHealth(notifier *webhook.Notifier, healthChecker *module.HealthChecker) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		healthChecker.Watch(ctx, func(previous, current types.ModuleHealth) {
+			notifier.Notify(moduleHealthEvent(previous, current))
+		})
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func moduleHealthEvent(previous, current types.ModuleHealth) webhook.Event {
+	from := previous.Status
+	if from == "" {
+		from = "unknown"
+	}
+
+	failing := make([]string, 0, len(current.Probes))
+
+	for _, probe := range current.Probes {
+		if !probe.Healthy {
+			failing = append(failing, fmt.Sprintf("%s (%s)", probe.Datasource, probe.Error))
+		}
+	}
+
+	summary := fmt.Sprintf("Module %s is %s (was %s)", current.Module, current.Status, from)
+	if len(failing) > 0 {
+		summary += ": " + strings.Join(failing, ", ")
+	}
+
+	return webhook.Event{
+		Type:    config.WebhookEventModuleHealth,
+		Summary: summary,
+		Fields: map[string]any{
+			"module":          current.Module,
+			"status":          current.Status,
+			"previous_status": previous.Status,
+			"probes":          current.Probes,
+		},
+	}
+}
+
+func scheduleFailureEvent(run schedule.Run) webhook.Event {
+	summary := fmt.Sprintf("Scheduled job %s %s", run.Job, run.Status())
+
+	switch {
+	case run.Error != "":
+		summary += ": " + run.Error
+	case run.ExitCode != 0:
+		summary += fmt.Sprintf(" with exit code %d", run.ExitCode)
+	}
+
+	if run.URL != "" {
+		summary += " " + run.URL
+	}
+
+	return webhook.Event{
+		Type:    config.WebhookEventScheduleFailure,
+		Summary: summary,
+		Fields: map[string]any{
+			"job":          run.Job,
+			"status":       run.Status(),
+			"trigger":      run.Trigger,
+			"execution_id": run.ExecutionID,
+			"exit_code":    run.ExitCode,
+			"error":        run.Error,
+			"url":          run.URL,
+		},
+	}
+}
+
+func longExecutionEvent(executionID string, execution execsvc.RunningExecution, threshold time.Duration) webhook.Event {
+	owner := execution.OwnerID
+	if owner == "" {
+		owner = "anonymous"
+	}
+
+	return webhook.Event{
+		Type:    config.WebhookEventLongExecution,
+		Summary: fmt.Sprintf("Execution %s by %s has been running for over %s", executionID, owner, threshold),
+		Fields: map[string]any{
+			"execution_id":      executionID,
+			"owner_id":          execution.OwnerID,
+			"session_id":        execution.SessionID,
+			"threshold_seconds": threshold.Seconds(),
+		},
+	}
+}
@@ -1,9 +1,13 @@
 package serverapi
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/ethpandaops/panda/pkg/history"
+	"github.com/ethpandaops/panda/pkg/proxy/audit"
 	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/schedule"
 	"github.com/ethpandaops/panda/pkg/types"
 )
 
@@ -14,6 +18,14 @@ type APIDocResponse struct {
 	Modules     map[string]types.ModuleDoc `json:"modules"`
 }
 
+// ModuleAPIDocResponse is the response for the python://ethpandaops/{module}.json resource.
+type ModuleAPIDocResponse struct {
+	Library string `json:"library"`
+	Module  string `json:"module"`
+	Import  string `json:"import"`
+	types.ModuleDoc
+}
+
 type DatasourcesResponse struct {
 	Datasources []types.DatasourceInfo `json:"datasources"`
 }
@@ -27,9 +39,10 @@ type ProxyAuthMetadataResponse struct {
 }
 
 type ResourceResponse struct {
-	URI      string `json:"uri"`
-	MIMEType string `json:"mime_type"`
-	Content  string `json:"content"`
+	URI         string `json:"uri"`
+	MIMEType    string `json:"mime_type"`
+	Content     string `json:"content"`
+	ContentHash string `json:"content_hash,omitempty"`
 }
 
 // ResourceInfo describes a single static resource.
@@ -54,6 +67,77 @@ type ListResourcesResponse struct {
 	Templates []ResourceTemplateInfo `json:"templates,omitempty"`
 }
 
+// ModuleStatus describes a compiled-in module for the admin API.
+type ModuleStatus struct {
+	Name        string `json:"name"`
+	Initialized bool   `json:"initialized"`
+	Disabled    bool   `json:"disabled"`
+}
+
+// ListModulesResponse is the response for GET /api/v1/admin/modules.
+type ListModulesResponse struct {
+	Modules []ModuleStatus `json:"modules"`
+}
+
+// ModuleHealthResponse is the response for GET /health/modules.
+type ModuleHealthResponse struct {
+	Status  string               `json:"status"`
+	Modules []types.ModuleHealth `json:"modules"`
+}
+
+// PackageCacheEntry is a package file in the shared sandbox package cache.
+type PackageCacheEntry struct {
+	Name    string    `json:"name"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// PackageCacheStatusResponse is the response for GET /api/v1/admin/package-cache.
+// Removed lists files dropped because they failed integrity checks.
+type PackageCacheStatusResponse struct {
+	Entries    []PackageCacheEntry `json:"entries"`
+	TotalBytes int64               `json:"total_bytes"`
+	MaxBytes   int64               `json:"max_bytes"`
+	Removed    []string            `json:"removed,omitempty"`
+}
+
+// AddPackagesRequest is the request for POST /api/v1/admin/package-cache.
+type AddPackagesRequest struct {
+	Packages []string `json:"packages"`
+}
+
+// AddPackagesResponse is the response for POST /api/v1/admin/package-cache.
+type AddPackagesResponse struct {
+	Added  []PackageCacheEntry        `json:"added"`
+	Log    string                     `json:"log,omitempty"`
+	Status PackageCacheStatusResponse `json:"status"`
+}
+
+// SandboxImage describes a sandbox image on the server's Docker host.
+// Profiles lists the execution profiles using it; "" is the default.
+type SandboxImage struct {
+	Ref      string     `json:"ref"`
+	Profiles []string   `json:"profiles"`
+	ID       string     `json:"id,omitempty"`
+	Digest   string     `json:"digest,omitempty"`
+	Pinned   bool       `json:"pinned"`
+	Floating bool       `json:"floating"`
+	Created  *time.Time `json:"created,omitempty"`
+	Size     int64      `json:"size,omitempty"`
+	PulledAt *time.Time `json:"pulled_at,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// SandboxImagesResponse is the response for GET /api/v1/admin/images and
+// POST /api/v1/admin/images/pull.
+type SandboxImagesResponse struct {
+	Images []SandboxImage `json:"images"`
+}
+
+// AuditSearchResponse is the response for GET /api/v1/admin/audit.
+type AuditSearchResponse = audit.SearchResponse
+
 type RuntimeStorageUploadResponse struct {
 	Key string `json:"key"`
 	URL string `json:"url"`
@@ -83,6 +167,7 @@ type SearchExampleResult struct {
 	Query           string  `json:"query"`
 	TargetCluster   string  `json:"target_cluster"`
 	SimilarityScore float64 `json:"similarity_score"`
+	Uses            int     `json:"uses,omitempty"`
 }
 
 type SearchExamplesResponse struct {
@@ -92,6 +177,7 @@ type SearchExamplesResponse struct {
 	TotalMatches        int                    `json:"total_matches"`
 	Results             []*SearchExampleResult `json:"results"`
 	AvailableCategories []string               `json:"available_categories"`
+	Warning             string                 `json:"warning,omitempty"`
 }
 
 type SearchRunbookResult struct {
@@ -101,6 +187,7 @@ type SearchRunbookResult struct {
 	Prerequisites   []string `json:"prerequisites"`
 	Content         string   `json:"content"`
 	FilePath        string   `json:"file_path"`
+	Source          string   `json:"source,omitempty"`
 	SimilarityScore float64  `json:"similarity_score"`
 }
 
@@ -111,6 +198,7 @@ type SearchRunbooksResponse struct {
 	TotalMatches  int                    `json:"total_matches"`
 	Results       []*SearchRunbookResult `json:"results"`
 	AvailableTags []string               `json:"available_tags"`
+	Warning       string                 `json:"warning,omitempty"`
 }
 
 type SearchEIPResult struct {
@@ -137,25 +225,60 @@ type SearchEIPsResponse struct {
 	AvailableStatuses   []string           `json:"available_statuses"`
 	AvailableCategories []string           `json:"available_categories"`
 	AvailableTypes      []string           `json:"available_types"`
+	Warning             string             `json:"warning,omitempty"`
 }
 
 type ExecuteRequest struct {
 	Code      string `json:"code"`
 	Timeout   int    `json:"timeout,omitempty"`
 	SessionID string `json:"session_id,omitempty"`
+	Profile   string `json:"profile,omitempty"`
+	// Packages are pip requirement specifiers installed into the session first.
+	Packages []string `json:"packages,omitempty"`
 }
 
 type ExecuteResponse struct {
-	Stdout              string                `json:"stdout,omitempty"`
-	Stderr              string                `json:"stderr,omitempty"`
-	ExitCode            int                   `json:"exit_code"`
-	ExecutionID         string                `json:"execution_id"`
-	OutputFiles         []string              `json:"output_files,omitempty"`
-	Metrics             map[string]any        `json:"metrics,omitempty"`
-	DurationSeconds     float64               `json:"duration_seconds"`
-	SessionID           string                `json:"session_id,omitempty"`
-	SessionFiles        []sandbox.SessionFile `json:"session_files,omitempty"`
-	SessionTTLRemaining string                `json:"session_ttl_remaining,omitempty"`
+	Stdout              string                 `json:"stdout,omitempty"`
+	Stderr              string                 `json:"stderr,omitempty"`
+	ExitCode            int                    `json:"exit_code"`
+	ExecutionID         string                 `json:"execution_id"`
+	OutputFiles         []string               `json:"output_files,omitempty"`
+	Metrics             map[string]any         `json:"metrics,omitempty"`
+	Result              json.RawMessage        `json:"result,omitempty"`
+	DurationSeconds     float64                `json:"duration_seconds"`
+	SessionID           string                 `json:"session_id,omitempty"`
+	SessionFiles        []sandbox.SessionFile  `json:"session_files,omitempty"`
+	SessionTTLRemaining string                 `json:"session_ttl_remaining,omitempty"`
+	Hints               []types.ErrorHint      `json:"hints,omitempty"`
+	RateLimit           *types.RateLimitState  `json:"rate_limit,omitempty"`
+	Artifacts           []sandbox.Artifact     `json:"artifacts,omitempty"`
+	Usage               *sandbox.ResourceUsage `json:"usage,omitempty"`
+}
+
+// Event types sent on the execute stream.
+const (
+	ExecuteEventStdout = "stdout"
+	ExecuteEventStderr = "stderr"
+	ExecuteEventResult = "result"
+	ExecuteEventError  = "error"
+	ExecuteEventQueued = "queued"
+)
+
+// ExecuteStreamEvent is one newline-delimited JSON event sent by
+// POST /api/v1/execute/stream. Output chunks arrive as stdout and stderr
+// events, and the stream ends with a single result or error event. An
+// execution waiting for a slot first gets queued events with its position,
+// then one without a position when it starts.
+type ExecuteStreamEvent struct {
+	Type   string           `json:"type"`
+	Data   string           `json:"data,omitempty"`
+	Result *ExecuteResponse `json:"result,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	// Status is the HTTP status the error would have been reported with on
+	// the non-streaming endpoint.
+	Status int `json:"status,omitempty"`
+	// Position is a queued execution's 1-based place in the queue.
+	Position int `json:"position,omitempty"`
 }
 
 type SessionResponse struct {
@@ -164,6 +287,7 @@ type SessionResponse struct {
 	LastUsed       time.Time             `json:"last_used"`
 	TTLRemaining   string                `json:"ttl_remaining"`
 	WorkspaceFiles []sandbox.SessionFile `json:"workspace_files,omitempty"`
+	Env            map[string]string     `json:"env,omitempty"`
 }
 
 type ListSessionsResponse struct {
@@ -176,3 +300,79 @@ type CreateSessionResponse struct {
 	SessionID    string `json:"session_id"`
 	TTLRemaining string `json:"ttl_remaining,omitempty"`
 }
+
+// SetSessionEnvRequest sets env overrides for a session's later executions.
+// An empty value removes an override.
+type SetSessionEnvRequest struct {
+	Env map[string]string `json:"env"`
+}
+
+type SessionEnvResponse struct {
+	SessionID string            `json:"session_id"`
+	Env       map[string]string `json:"env"`
+}
+
+type SessionFileResponse struct {
+	SessionID string `json:"session_id"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+}
+
+// PromoteExampleRequest promotes a successful execution's code to a search
+// example. Category defaults to "promoted".
+type PromoteExampleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	Cluster     string `json:"cluster,omitempty"`
+}
+
+type PromoteExampleResponse struct {
+	ExecutionID  string        `json:"execution_id"`
+	CategoryKey  string        `json:"category_key"`
+	CategoryName string        `json:"category_name"`
+	Example      types.Example `json:"example"`
+	// Indexed is false when the example was stored but could not be added
+	// to the search index; it is indexed on the next server start.
+	Indexed bool `json:"indexed"`
+}
+
+// RateExampleRequest records whether a search example was useful.
+type RateExampleRequest struct {
+	CategoryKey string `json:"category_key"`
+	ExampleName string `json:"example_name"`
+	Useful      bool   `json:"useful"`
+}
+
+// RateExampleResponse is the example's usage after the rating.
+type RateExampleResponse struct {
+	CategoryKey string `json:"category_key"`
+	ExampleName string `json:"example_name"`
+	Uses        int    `json:"uses"`
+	NotUseful   int    `json:"not_useful"`
+}
+
+type ListExecutionsResponse struct {
+	Executions []history.Record `json:"executions"`
+	Total      int              `json:"total"`
+}
+
+// ListSchedulesResponse lists the scheduled jobs.
+type ListSchedulesResponse struct {
+	Jobs []schedule.JobStatus `json:"jobs"`
+}
+
+// ScheduleRunsResponse lists a scheduled job's recent runs, newest first.
+type ScheduleRunsResponse struct {
+	Job  string         `json:"job"`
+	Runs []schedule.Run `json:"runs"`
+}
+
+// ExportNotebookResponse locates a session's history exported as a Jupyter
+// notebook.
+type ExportNotebookResponse struct {
+	SessionID  string `json:"session_id"`
+	Key        string `json:"key"`
+	URL        string `json:"url"`
+	Executions int    `json:"executions"`
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// contentTypes covers output formats the mime package does not know.
+var contentTypes = map[string]string{
+	".parquet": "application/vnd.apache.parquet",
+	".jsonl":   "application/jsonl",
+	".ndjson":  "application/x-ndjson",
+	".csv":     "text/csv; charset=utf-8",
+	".md":      "text/markdown; charset=utf-8",
+}
+
+// ContentType returns the media type of a file from its extension, falling
+// back to sniffing data (of which the first 512 bytes are enough).
+func ContentType(name string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(name))
+
+	if contentType, ok := contentTypes[ext]; ok {
+		return contentType
+	}
+
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,32 @@
+package storage
+
+import "github.com/ethpandaops/panda/pkg/types"
+
+// PythonAPIDoc returns the documentation for the platform-owned storage
+// module of the ethpandaops Python library.
+func PythonAPIDoc() types.ModuleDoc {
+	return types.ModuleDoc{
+		Description: "Upload files to storage for sharing",
+		Functions: map[string]types.FunctionDoc{
+			"upload": {
+				Signature:   "storage.upload(local_path: str, remote_name: str = None) -> str",
+				Description: "Upload a local file to storage and return the public URL",
+				Parameters: map[string]string{
+					"local_path":  "Path to file (e.g., '/workspace/chart.png')",
+					"remote_name": "Optional: custom name for the stored file",
+				},
+				Returns: "Public URL string",
+			},
+			"list_files": {
+				Signature:   "storage.list_files(prefix: str = '') -> list[dict]",
+				Description: "List uploaded files",
+				Returns:     "List of dicts with 'key', 'size', 'last_modified'",
+			},
+			"get_url": {
+				Signature:   "storage.get_url(key: str) -> str",
+				Description: "Get public URL for a stored file",
+				Returns:     "Public URL string",
+			},
+		},
+	}
+}
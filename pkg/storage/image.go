@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Register GIF decoding.
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// Image formats ConvertImage can encode.
+const (
+	ImageFormatPNG  = "png"
+	ImageFormatJPEG = "jpeg"
+)
+
+// DefaultJPEGQuality is the JPEG quality used when none is given.
+const DefaultJPEGQuality = 85
+
+// ImageOptions controls how ConvertImage resizes and re-encodes an image.
+type ImageOptions struct {
+	// MaxWidth scales the image down to at most this many pixels wide,
+	// keeping its aspect ratio. Zero keeps the original width.
+	MaxWidth int
+	// Format is the output format, "png" or "jpeg". Empty keeps PNG and JPEG
+	// images in their format and converts GIFs to PNG.
+	Format string
+	// Quality is the JPEG quality from 1 to 100 (default: 85).
+	Quality int
+}
+
+// ConvertImage decodes a PNG, JPEG or GIF image, scales it down to
+// opts.MaxWidth and encodes it as opts.Format. It returns the encoded image
+// and its content type. Transparent areas become white in JPEG output.
+func ConvertImage(data []byte, opts ImageOptions) ([]byte, string, error) {
+	if opts.MaxWidth < 0 {
+		return nil, "", fmt.Errorf("max width cannot be negative")
+	}
+
+	if opts.Quality < 0 || opts.Quality > 100 {
+		return nil, "", fmt.Errorf("quality must be between 1 and 100")
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding image: %w", err)
+	}
+
+	outFormat := strings.ToLower(opts.Format)
+
+	switch outFormat {
+	case "":
+		outFormat = ImageFormatPNG
+		if format == ImageFormatJPEG {
+			outFormat = ImageFormatJPEG
+		}
+	case "jpg":
+		outFormat = ImageFormatJPEG
+	case ImageFormatPNG, ImageFormatJPEG:
+	default:
+		return nil, "", fmt.Errorf("unsupported image format %q (supported: png, jpeg)", opts.Format)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width == 0 || height == 0 {
+		return nil, "", fmt.Errorf("image is empty")
+	}
+
+	img := src
+	if opts.MaxWidth > 0 && width > opts.MaxWidth {
+		img = downscale(src, opts.MaxWidth, max(height*opts.MaxWidth/width, 1))
+	}
+
+	var buf bytes.Buffer
+
+	if outFormat == ImageFormatJPEG {
+		quality := opts.Quality
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", fmt.Errorf("encoding jpeg: %w", err)
+		}
+
+		return buf.Bytes(), "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("encoding png: %w", err)
+	}
+
+	return buf.Bytes(), "image/png", nil
+}
+
+// Thumbnail decodes a PNG, JPEG or GIF image and returns a PNG scaled down so
+// its longest edge is at most maxEdge pixels. Images already that small are
+// re-encoded unscaled.
+func Thumbnail(data []byte, maxEdge int) ([]byte, error) {
+	if maxEdge <= 0 {
+		return nil, fmt.Errorf("thumbnail size must be positive")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+
+	dstWidth, dstHeight := width, height
+	if longest := max(width, height); longest > maxEdge {
+		dstWidth = max(width*maxEdge/longest, 1)
+		dstHeight = max(height*maxEdge/longest, 1)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, downscale(src, dstWidth, dstHeight)); err != nil {
+		return nil, fmt.Errorf("encoding thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// downscale resizes src to width x height by averaging the source pixels
+// each destination pixel covers.
+func downscale(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(bounds.Min.Y+(y+1)*srcHeight/height, y0+1)
+
+		for x := range width {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(bounds.Min.X+(x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8((r / n) >> 8),
+				G: uint8((g / n) >> 8),
+				B: uint8((b / n) >> 8),
+				A: uint8((a / n) >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// flatten draws img over a white background, for formats without alpha.
+func flatten(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+
+	return dst
+}
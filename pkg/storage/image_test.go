@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{name: "landscape", width: 1200, height: 600, wantW: 320, wantH: 160},
+		{name: "portrait", width: 300, height: 900, wantW: 106, wantH: 320},
+		{name: "already small", width: 100, height: 50, wantW: 100, wantH: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Thumbnail(encodePNG(t, tt.width, tt.height), 320)
+			require.NoError(t, err)
+
+			img, err := png.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantW, img.Bounds().Dx())
+			assert.Equal(t, tt.wantH, img.Bounds().Dy())
+			assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 255}, color.NRGBAModel.Convert(img.At(0, 0)))
+		})
+	}
+}
+
+func TestThumbnailRejectsNonImages(t *testing.T) {
+	t.Parallel()
+
+	_, err := Thumbnail([]byte("<svg></svg>"), 320)
+	require.Error(t, err)
+
+	_, err = Thumbnail(encodePNG(t, 10, 10), 0)
+	require.Error(t, err)
+}
+
+func TestConvertImage(t *testing.T) {
+	t.Parallel()
+
+	src := encodePNG(t, 1200, 600)
+
+	tests := []struct {
+		name        string
+		opts        ImageOptions
+		contentType string
+		wantW       int
+		wantH       int
+	}{
+		{name: "keep format and size", contentType: "image/png", wantW: 1200, wantH: 600},
+		{name: "downscale", opts: ImageOptions{MaxWidth: 400}, contentType: "image/png", wantW: 400, wantH: 200},
+		{name: "no upscale", opts: ImageOptions{MaxWidth: 4000}, contentType: "image/png", wantW: 1200, wantH: 600},
+		{name: "to jpeg", opts: ImageOptions{MaxWidth: 600, Format: "jpg", Quality: 70}, contentType: "image/jpeg", wantW: 600, wantH: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, contentType, err := ConvertImage(src, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, contentType)
+
+			img, _, err := image.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantW, img.Bounds().Dx())
+			assert.Equal(t, tt.wantH, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestConvertImageErrors(t *testing.T) {
+	t.Parallel()
+
+	src := encodePNG(t, 10, 10)
+
+	_, _, err := ConvertImage(src, ImageOptions{Format: "webp"})
+	require.ErrorContains(t, err, "unsupported image format")
+
+	_, _, err = ConvertImage(src, ImageOptions{Quality: 101})
+	require.Error(t, err)
+
+	_, _, err = ConvertImage([]byte("not an image"), ImageOptions{})
+	require.ErrorContains(t, err, "decoding image")
+}
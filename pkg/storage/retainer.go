@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+// RetentionConfig configures the Retainer.
+type RetentionConfig struct {
+	// TTL is how long uploads are kept after they were last modified.
+	TTL time.Duration
+	// Interval between sweeps.
+	Interval time.Duration
+	// DryRun logs and counts expired uploads without deleting them.
+	DryRun bool
+	// Exclude lists top-level directories that are never swept.
+	Exclude []string
+}
+
+// Retainer periodically deletes uploads older than a TTL.
+type Retainer struct {
+	log     logrus.FieldLogger
+	cfg     RetentionConfig
+	storage Service
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRetainer creates a retention worker for storageSvc.
+func NewRetainer(log logrus.FieldLogger, cfg RetentionConfig, storageSvc Service) *Retainer {
+	return &Retainer{
+		log:     log.WithField("component", "storage_retention"),
+		cfg:     cfg,
+		storage: storageSvc,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start sweeps once and then every interval in the background.
+func (r *Retainer) Start() {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		r.Sweep(time.Now())
+
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.Sweep(time.Now())
+			}
+		}
+	}()
+
+	r.log.WithFields(logrus.Fields{
+		"ttl":      r.cfg.TTL,
+		"interval": r.cfg.Interval,
+		"dry_run":  r.cfg.DryRun,
+	}).Info("Storage retention started")
+}
+
+// Stop stops the background loop.
+func (r *Retainer) Stop() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// Sweep removes uploads that expired by now and records the result.
+func (r *Retainer) Sweep(now time.Time) SweepResult {
+	result, err := r.storage.Sweep(now.Add(-r.cfg.TTL), SweepOptions{
+		Exclude: r.cfg.Exclude,
+		DryRun:  r.cfg.DryRun,
+	})
+	if err != nil {
+		observability.StorageRetentionSweepErrorsTotal.Inc()
+		r.log.WithError(err).Warn("Storage retention sweep failed")
+	}
+
+	mode := "delete"
+	if r.cfg.DryRun {
+		mode = "dry_run"
+	}
+
+	observability.StorageRetentionFilesTotal.WithLabelValues(mode).Add(float64(result.Files))
+	observability.StorageRetentionReclaimedBytesTotal.WithLabelValues(mode).Add(float64(result.Bytes))
+
+	for _, execution := range result.Executions {
+		r.log.WithFields(logrus.Fields{
+			"execution_id": execution.ExecutionID,
+			"session_id":   execution.SessionID,
+			"owner_id":     execution.OwnerID,
+			"files":        execution.Files,
+			"bytes":        execution.Bytes,
+			"dry_run":      r.cfg.DryRun,
+		}).Debug("Expired uploads")
+	}
+
+	if result.Files > 0 {
+		r.log.WithFields(logrus.Fields{
+			"files":   result.Files,
+			"bytes":   result.Bytes,
+			"dry_run": r.cfg.DryRun,
+		}).Info("Storage retention sweep finished")
+	}
+
+	return result
+}
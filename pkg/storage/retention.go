@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// metadataDir holds one JSON file of Metadata per execution. It is never
+// served or swept as an upload.
+const metadataDir = ".meta"
+
+// Metadata tags an execution's uploads with where they came from.
+type Metadata struct {
+	ExecutionID string    `json:"execution_id"`
+	SessionID   string    `json:"session_id,omitempty"`
+	OwnerID     string    `json:"owner_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SweepOptions controls a Sweep.
+type SweepOptions struct {
+	// Exclude lists top-level directories that are never swept.
+	Exclude []string
+	// DryRun reports what would be deleted without deleting it.
+	DryRun bool
+}
+
+// SweptExecution summarizes the expired files of one execution.
+type SweptExecution struct {
+	Metadata
+	Files int
+	Bytes int64
+}
+
+// SweepResult summarizes a Sweep.
+type SweepResult struct {
+	Files      int
+	Bytes      int64
+	Executions []SweptExecution
+}
+
+// SetMetadata writes meta for meta.ExecutionID, keeping the original
+// CreatedAt when the execution already has metadata.
+func (s *service) SetMetadata(meta Metadata) error {
+	name := sanitize(meta.ExecutionID)
+	if name == "" || strings.Contains(name, string(os.PathSeparator)) {
+		return fmt.Errorf("invalid execution ID %q", meta.ExecutionID)
+	}
+
+	if existing, ok := s.readMetadata(name); ok {
+		meta.CreatedAt = existing.CreatedAt
+	}
+
+	if meta.CreatedAt.IsZero() {
+		meta.CreatedAt = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+
+	dir := filepath.Join(s.baseDir, metadataDir)
+	if err := s.fs.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating metadata directory: %w", err)
+	}
+
+	if err := afero.WriteFile(s.fs, filepath.Join(dir, name+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("writing metadata: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) readMetadata(executionID string) (Metadata, bool) {
+	data, err := afero.ReadFile(s.fs, filepath.Join(s.baseDir, metadataDir, executionID+".json"))
+	if err != nil {
+		return Metadata{}, false
+	}
+
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Metadata{}, false
+	}
+
+	return meta, true
+}
+
+// Sweep deletes files last modified before cutoff, then removes execution
+// directories left empty along with their metadata.
+func (s *service) Sweep(cutoff time.Time, opts SweepOptions) (SweepResult, error) {
+	entries, err := afero.ReadDir(s.fs, s.baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return SweepResult{}, nil
+		}
+
+		return SweepResult{}, fmt.Errorf("listing storage: %w", err)
+	}
+
+	var result SweepResult
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || name == metadataDir || slices.Contains(opts.Exclude, name) {
+			continue
+		}
+
+		swept, err := s.sweepExecution(name, cutoff, opts.DryRun)
+		if err != nil {
+			return result, err
+		}
+
+		if swept.Files == 0 {
+			continue
+		}
+
+		result.Files += swept.Files
+		result.Bytes += swept.Bytes
+		result.Executions = append(result.Executions, swept)
+	}
+
+	return result, nil
+}
+
+func (s *service) sweepExecution(executionID string, cutoff time.Time, dryRun bool) (SweptExecution, error) {
+	dir := filepath.Join(s.baseDir, executionID)
+
+	swept := SweptExecution{Metadata: Metadata{ExecutionID: executionID}}
+	if meta, ok := s.readMetadata(executionID); ok {
+		swept.Metadata = meta
+	}
+
+	kept := 0
+
+	err := afero.Walk(s.fs, dir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if !info.ModTime().Before(cutoff) {
+			kept++
+
+			return nil
+		}
+
+		if !dryRun {
+			if err := s.fs.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("removing %s: %w", path, err)
+			}
+		}
+
+		swept.Files++
+		swept.Bytes += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return swept, fmt.Errorf("sweeping %s: %w", executionID, err)
+	}
+
+	if swept.Files > 0 && kept == 0 && !dryRun {
+		if err := s.fs.RemoveAll(dir); err != nil {
+			return swept, fmt.Errorf("removing %s: %w", dir, err)
+		}
+
+		metaPath := filepath.Join(s.baseDir, metadataDir, executionID+".json")
+		if err := s.fs.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return swept, fmt.Errorf("removing metadata of %s: %w", executionID, err)
+		}
+	}
+
+	return swept, nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+
+	svc, fs := newTestService()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+
+	upload := func(executionID, name, body string, modTime time.Time) {
+		_, _, err := svc.Upload(executionID, name, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		require.NoError(t, fs.Chtimes("/data/"+executionID+"/"+name, modTime, modTime))
+	}
+
+	upload("exec-old", "chart.png", "12345", old)
+	upload("exec-mixed", "old.csv", "123", old)
+	upload("exec-mixed", "new.csv", "1", now)
+	upload("history", "date=2026-01-01/executions.parquet", "1234567", old)
+
+	require.NoError(t, svc.SetMetadata(Metadata{ExecutionID: "exec-old", SessionID: "sess-1", OwnerID: "42"}))
+
+	cutoff := now.Add(-24 * time.Hour)
+	opts := SweepOptions{Exclude: []string{"history"}, DryRun: true}
+
+	dry, err := svc.Sweep(cutoff, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dry.Files)
+	assert.Equal(t, int64(8), dry.Bytes)
+
+	exists, _ := afero.Exists(fs, "/data/exec-old/chart.png")
+	assert.True(t, exists, "dry run keeps files")
+
+	opts.DryRun = false
+
+	result, err := svc.Sweep(cutoff, opts)
+	require.NoError(t, err)
+	assert.Equal(t, dry, result)
+
+	for _, execution := range result.Executions {
+		if execution.ExecutionID == "exec-old" {
+			assert.Equal(t, "sess-1", execution.SessionID)
+		}
+	}
+
+	exists, _ = afero.DirExists(fs, "/data/exec-old")
+	assert.False(t, exists, "emptied executions are removed")
+
+	exists, _ = afero.Exists(fs, "/data/.meta/exec-old.json")
+	assert.False(t, exists, "metadata goes with the execution")
+
+	exists, _ = afero.Exists(fs, "/data/exec-mixed/new.csv")
+	assert.True(t, exists)
+
+	exists, _ = afero.Exists(fs, "/data/history/date=2026-01-01/executions.parquet")
+	assert.True(t, exists, "excluded directories are not swept")
+}
+
+func TestSetMetadataKeepsCreatedAt(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService()
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.SetMetadata(Metadata{ExecutionID: "exec-1", CreatedAt: created}))
+	require.NoError(t, svc.SetMetadata(Metadata{ExecutionID: "exec-1", SessionID: "sess-1"}))
+
+	meta, ok := svc.(*service).readMetadata("exec-1")
+	require.True(t, ok)
+	assert.Equal(t, created, meta.CreatedAt)
+	assert.Equal(t, "sess-1", meta.SessionID)
+
+	require.Error(t, svc.SetMetadata(Metadata{ExecutionID: "../etc"}))
+}
+
+func TestServeFileHidesMetadata(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService()
+	require.NoError(t, svc.SetMetadata(Metadata{ExecutionID: "exec-1"}))
+
+	rec := httptest.NewRecorder()
+	svc.ServeFile(rec, httptest.NewRequest(http.MethodGet, "/", nil), ".meta/exec-1.json")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRetainerSweep(t *testing.T) {
+	t.Parallel()
+
+	svc, fs := newTestService()
+
+	_, _, err := svc.Upload("exec-1", "a.txt", bytes.NewBufferString("abc"))
+	require.NoError(t, err)
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, fs.Chtimes("/data/exec-1/a.txt", old, old))
+
+	retainer := NewRetainer(logrus.New(), RetentionConfig{TTL: time.Hour, Interval: time.Hour}, svc)
+
+	result := retainer.Sweep(time.Now())
+	assert.Equal(t, 1, result.Files)
+	assert.Equal(t, int64(3), result.Bytes)
+}
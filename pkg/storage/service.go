@@ -30,8 +30,16 @@ type Service interface {
 	List(executionID, prefix string) ([]File, error)
 	// GetURL returns the public URL for a file scoped to an execution.
 	GetURL(executionID, key string) string
+	// ReadFile returns the contents of a file scoped to an execution.
+	ReadFile(executionID, key string) ([]byte, error)
+	// Delete removes a file scoped to an execution.
+	Delete(executionID, key string) error
 	// ServeFile serves a stored file over HTTP.
 	ServeFile(w http.ResponseWriter, r *http.Request, filePath string)
+	// SetMetadata records which session and owner produced an execution's files.
+	SetMetadata(meta Metadata) error
+	// Sweep deletes files last modified before cutoff.
+	Sweep(cutoff time.Time, opts SweepOptions) (SweepResult, error)
 }
 
 type service struct {
@@ -142,6 +150,30 @@ func (s *service) GetURL(executionID, key string) string {
 	return s.fileURL(executionID, rel)
 }
 
+// ReadFile returns the contents of a stored file.
+func (s *service) ReadFile(executionID, key string) ([]byte, error) {
+	rel, err := relativeKey(executionID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return afero.ReadFile(s.fs, filepath.Join(s.baseDir, sanitize(executionID), rel))
+}
+
+// Delete removes a stored file.
+func (s *service) Delete(executionID, key string) error {
+	rel, err := relativeKey(executionID, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.fs.Remove(filepath.Join(s.baseDir, sanitize(executionID), rel)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing file: %w", err)
+	}
+
+	return nil
+}
+
 // ServeFile serves a stored file from the filesystem.
 func (s *service) ServeFile(w http.ResponseWriter, r *http.Request, filePath string) {
 	fullPath := filepath.Clean(filepath.Join(s.baseDir, filepath.FromSlash(filePath)))
@@ -152,6 +184,12 @@ func (s *service) ServeFile(w http.ResponseWriter, r *http.Request, filePath str
 		return
 	}
 
+	// Upload metadata is internal.
+	if strings.HasPrefix(strings.TrimPrefix(fullPath, filepath.Clean(s.baseDir)+string(os.PathSeparator)), metadataDir) {
+		http.NotFound(w, r)
+		return
+	}
+
 	f, err := s.fs.Open(fullPath)
 	if err != nil {
 		http.NotFound(w, r)
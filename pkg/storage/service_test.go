@@ -182,3 +182,34 @@ func TestServeFilePathTraversal(t *testing.T) {
 	svc.ServeFile(w, r, "../../etc/passwd")
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestReadFileAndDelete(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestService()
+
+	_, _, err := svc.Upload("history", "date=2026-01-01/executions.parquet", bytes.NewBufferString("data"))
+	require.NoError(t, err)
+
+	data, err := svc.ReadFile("history", "date=2026-01-01/executions.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, svc.Delete("history", "date=2026-01-01/executions.parquet"))
+	require.NoError(t, svc.Delete("history", "date=2026-01-01/executions.parquet"), "deleting a missing file is not an error")
+
+	_, err = svc.ReadFile("history", "date=2026-01-01/executions.parquet")
+	require.Error(t, err)
+}
+
+func TestContentType(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	assert.Equal(t, "application/vnd.apache.parquet", ContentType("blocks.parquet", []byte("PAR1")))
+	assert.Equal(t, "image/png", ContentType("chart.png", png))
+	assert.Equal(t, "image/png", ContentType("chart", png), "unknown extensions are sniffed")
+	assert.Equal(t, "text/plain; charset=utf-8", ContentType("notes", []byte("hello")))
+	assert.Equal(t, "application/octet-stream", ContentType("blob", []byte{0, 1, 2}))
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadPathPrefix is the server path presigned session uploads are sent to.
+const UploadPathPrefix = "/api/v1/storage/uploads/"
+
+// ErrInvalidUploadURL is returned for upload URLs that are expired, altered
+// or signed with another key.
+var ErrInvalidUploadURL = errors.New("upload URL is invalid or has expired")
+
+// UploadURLConfig configures presigned upload URLs.
+type UploadURLConfig struct {
+	// TTL is how long an issued URL stays valid.
+	TTL time.Duration
+	// SigningKey signs the URLs. When empty, a random key is generated, so
+	// URLs only work on the process that issued them.
+	SigningKey string
+}
+
+// UploadSigner issues and verifies presigned URLs for writing one file into
+// a session workspace. The URL is the only credential the upload needs, so
+// it binds the session, owner, path and expiry.
+type UploadSigner struct {
+	key     []byte
+	baseURL string
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// NewUploadSigner creates an UploadSigner for URLs under baseURL.
+func NewUploadSigner(cfg UploadURLConfig, baseURL string) (*UploadSigner, error) {
+	key := []byte(cfg.SigningKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &UploadSigner{
+		key:     key,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		ttl:     cfg.TTL,
+		now:     time.Now,
+	}, nil
+}
+
+// Sign returns a URL that accepts a PUT of name into sessionID's workspace
+// on behalf of ownerID, and when it expires.
+func (s *UploadSigner) Sign(sessionID, ownerID, name string) (string, time.Time) {
+	expires := s.now().Add(s.ttl).Truncate(time.Second)
+
+	query := url.Values{}
+	query.Set("owner", ownerID)
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", s.signature(sessionID, ownerID, name, expires.Unix()))
+
+	return s.baseURL + UploadPathPrefix + url.PathEscape(sessionID) + "/" + escapePath(name) + "?" + query.Encode(), expires
+}
+
+// Verify checks the query of an upload URL for sessionID and name and
+// returns the owner it was issued to.
+func (s *UploadSigner) Verify(sessionID, name string, query url.Values) (string, error) {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || s.now().Unix() > expires {
+		return "", ErrInvalidUploadURL
+	}
+
+	ownerID := query.Get("owner")
+	want := s.signature(sessionID, ownerID, name, expires)
+
+	if !hmac.Equal([]byte(want), []byte(query.Get("signature"))) {
+		return "", ErrInvalidUploadURL
+	}
+
+	return ownerID, nil
+}
+
+func (s *UploadSigner) signature(sessionID, ownerID, name string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(strings.Join([]string{sessionID, ownerID, name, strconv.FormatInt(expires, 10)}, "\n")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
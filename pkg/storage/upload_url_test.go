@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadSigner(t *testing.T) {
+	t.Parallel()
+
+	signer, err := NewUploadSigner(UploadURLConfig{TTL: 15 * time.Minute, SigningKey: "secret"}, "http://localhost:2480/")
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	signer.now = func() time.Time { return now }
+
+	raw, expires := signer.Sign("abc", "42", "data/blocks 1.parquet")
+	assert.Equal(t, now.Add(15*time.Minute), expires)
+	assert.True(t, strings.HasPrefix(raw, "http://localhost:2480/api/v1/storage/uploads/abc/data/blocks%201.parquet?"), raw)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	ownerID, err := signer.Verify("abc", "data/blocks 1.parquet", u.Query())
+	require.NoError(t, err)
+	assert.Equal(t, "42", ownerID)
+
+	_, err = signer.Verify("abc", "other.parquet", u.Query())
+	require.ErrorIs(t, err, ErrInvalidUploadURL, "another path")
+
+	_, err = signer.Verify("def", "data/blocks 1.parquet", u.Query())
+	require.ErrorIs(t, err, ErrInvalidUploadURL, "another session")
+
+	forged := u.Query()
+	forged.Set("owner", "7")
+	_, err = signer.Verify("abc", "data/blocks 1.parquet", forged)
+	require.ErrorIs(t, err, ErrInvalidUploadURL, "another owner")
+
+	now = now.Add(16 * time.Minute)
+	_, err = signer.Verify("abc", "data/blocks 1.parquet", u.Query())
+	require.ErrorIs(t, err, ErrInvalidUploadURL, "expired")
+}
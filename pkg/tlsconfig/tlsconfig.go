@@ -0,0 +1,170 @@
+// Package tlsconfig builds TLS and mutual TLS settings for the server and
+// proxy listeners and for server-to-proxy connections.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ServerConfig configures TLS on an HTTP listener. Setting ClientCAFile
+// turns on mutual TLS: requests must present a certificate signed by one of
+// its CAs.
+type ServerConfig struct {
+	// CertFile is the PEM server certificate chain.
+	CertFile string `yaml:"cert_file,omitempty"`
+	// KeyFile is the PEM private key of CertFile.
+	KeyFile string `yaml:"key_file,omitempty"`
+	// ClientCAFile is a PEM bundle of CAs trusted to sign client certificates.
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
+}
+
+// Enabled reports whether the listener serves TLS.
+func (c ServerConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.ClientCAFile != ""
+}
+
+// RequiresClientCert reports whether mutual TLS is on.
+func (c ServerConfig) RequiresClientCert() bool {
+	return c.ClientCAFile != ""
+}
+
+// Validate checks that certificate and key are set together and that
+// client CAs come with a server certificate.
+func (c ServerConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if c.CertFile == "" || c.KeyFile == "" {
+		return fmt.Errorf("cert_file and key_file are required when TLS is configured")
+	}
+
+	return nil
+}
+
+// TLSConfig loads the certificate and client CAs. Client certificates are
+// verified when presented and enforced per request by RequireClientCert, so
+// liveness probes can still complete the handshake without one.
+func (c ServerConfig) TLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if c.ClientCAFile != "" {
+		pool, err := loadPool(x509.NewCertPool(), c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client CAs: %w", err)
+		}
+
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return cfg, nil
+}
+
+// RequireClientCert rejects requests without a verified client certificate,
+// except those whose path equals an exempt entry or, for entries ending in
+// "/", starts with it.
+func RequireClientCert(next http.Handler, exempt ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for _, path := range exempt {
+			if r.URL.Path == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		http.Error(w, "client certificate required", http.StatusUnauthorized)
+	})
+}
+
+// ClientConfig configures TLS for outgoing connections, such as the
+// server's connection to an mTLS proxy.
+type ClientConfig struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system roots.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// CertFile is the PEM client certificate chain presented to the peer.
+	CertFile string `yaml:"cert_file,omitempty"`
+	// KeyFile is the PEM private key of CertFile.
+	KeyFile string `yaml:"key_file,omitempty"`
+}
+
+// Enabled reports whether any client TLS setting is configured.
+func (c ClientConfig) Enabled() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate checks that certificate and key are set together.
+func (c ClientConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+
+	return nil
+}
+
+// Transport returns an HTTP transport presenting the client certificate and
+// trusting the extra CAs, or http.DefaultTransport when nothing is configured.
+func (c ClientConfig) Transport() (http.RoundTripper, error) {
+	if !c.Enabled() {
+		return http.DefaultTransport, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CAFile != "" {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+
+		if cfg.RootCAs, err = loadPool(roots, c.CAFile); err != nil {
+			return nil, fmt.Errorf("loading CAs: %w", err)
+		}
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+
+	return transport, nil
+}
+
+// loadPool appends the PEM certificates in path to pool.
+func loadPool(pool *x509.CertPool, path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,125 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "server", caCert, caKey)
+	writeCert(t, dir, "client", caCert, caKey)
+
+	serverCfg := ServerConfig{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	}
+	require.NoError(t, serverCfg.Validate())
+
+	tlsCfg, err := serverCfg.TLSConfig()
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(RequireClientCert(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+		"/health", "/api/v1/runtime/",
+	))
+	srv.TLS = tlsCfg
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	get := func(cfg ClientConfig, path string) int {
+		transport, err := cfg.Transport()
+		require.NoError(t, err)
+
+		resp, err := (&http.Client{Transport: transport}).Get(srv.URL + path)
+		require.NoError(t, err)
+
+		_ = resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	withCert := ClientConfig{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+	}
+	withoutCert := ClientConfig{CAFile: filepath.Join(dir, "ca.pem")}
+
+	assert.Equal(t, http.StatusNoContent, get(withCert, "/mcp"))
+	assert.Equal(t, http.StatusUnauthorized, get(withoutCert, "/mcp"))
+	assert.Equal(t, http.StatusNoContent, get(withoutCert, "/health"))
+	assert.Equal(t, http.StatusNoContent, get(withoutCert, "/api/v1/runtime/storage/files"))
+	assert.Equal(t, http.StatusUnauthorized, get(withoutCert, "/healthz"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, ServerConfig{}.Validate())
+	assert.Error(t, ServerConfig{ClientCAFile: "ca.pem"}.Validate())
+	assert.Error(t, ServerConfig{CertFile: "cert.pem"}.Validate())
+
+	assert.NoError(t, ClientConfig{CAFile: "ca.pem"}.Validate())
+	assert.Error(t, ClientConfig{CertFile: "cert.pem"}.Validate())
+
+	transport, err := ClientConfig{}.Transport()
+	require.NoError(t, err)
+	assert.Equal(t, http.DefaultTransport, transport)
+}
+
+// writeCert writes name.pem and name-key.pem to dir. Without a parent it
+// writes a self-signed CA.
+func writeCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
@@ -62,6 +62,13 @@ func (s *Store) Validate(token string) string {
 	return entry.value
 }
 
+// TTL returns how long a registered token stays valid.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Revoke removes every token registered for value, including tokens issued
+// by refreshes.
 func (s *Store) Revoke(value string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -69,7 +76,6 @@ func (s *Store) Revoke(value string) {
 	for token, entry := range s.tokens {
 		if entry.value == value {
 			delete(s.tokens, token)
-			return
 		}
 	}
 }
@@ -4,60 +4,20 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/ethpandaops/panda/pkg/auth"
+	"github.com/ethpandaops/panda/pkg/clientstate"
 	"github.com/ethpandaops/panda/pkg/config"
 	"github.com/ethpandaops/panda/pkg/execsvc"
 	"github.com/ethpandaops/panda/pkg/sandbox"
 )
 
-const (
-	resourceTipCacheMaxSize = 1000
-	resourceTipCacheMaxAge  = 4 * time.Hour
-)
-
-type resourceTipCache struct {
-	mu      sync.Mutex
-	entries map[string]time.Time
-}
-
-var sessionsWithResourceTip = &resourceTipCache{
-	entries: make(map[string]time.Time, 64),
-}
-
-func (c *resourceTipCache) markShown(sessionKey string) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if _, exists := c.entries[sessionKey]; exists {
-		return false
-	}
-
-	if len(c.entries) >= resourceTipCacheMaxSize {
-		c.cleanupLocked()
-	}
-
-	c.entries[sessionKey] = time.Now()
-
-	return true
-}
-
-func (c *resourceTipCache) cleanupLocked() {
-	cutoff := time.Now().Add(-resourceTipCacheMaxAge)
-
-	for key, ts := range c.entries {
-		if ts.Before(cutoff) {
-			delete(c.entries, key)
-		}
-	}
-}
-
 const resourceTipMessage = `
 TIP: Read panda://getting-started for cluster rules and workflow guidance.`
 
@@ -72,48 +32,98 @@ const executePythonDescription = `Execute Python code with the ethpandaops libra
 
 **BEFORE YOUR FIRST QUERY:** Read panda://getting-started for workflow guidance and critical syntax rules.
 
-Use the search tool with ` + "`type=\"examples\"`" + ` for query patterns. Reuse session_id from responses.`
+Use the search tool with ` + "`type=\"examples\"`" + ` for query patterns. Reuse session_id from responses. Call ` + "`ethpandaops.result(obj)`" + ` to return a JSON value in the response's structured result, separate from stdout.`
+
+// executePythonOfflineNote is appended to the description in offline mode.
+const executePythonOfflineNote = `
+
+OFFLINE MODE: the server has no network access. Datasource modules (clickhouse, prometheus, loki, grafana, github, http_json, ethnode, dora, cbt) are disabled and their calls fail; only local computation, session files and storage work.`
+
+// executePythonKernelNote is appended to the description in kernel mode.
+const executePythonKernelNote = `
+
+KERNEL MODE: executions in a session share one Python process, like notebook cells. Variables, dataframes and imports persist between calls with the same session_id, and a trailing expression's value is printed. Destroy the session with manage_session to reset its state.`
 
 func NewExecutePythonTool(
 	log logrus.FieldLogger,
 	sandboxSvc sandbox.Service,
 	cfg *config.Config,
 	service *execsvc.Service,
+	clients *clientstate.Store,
 ) Definition {
+	description := executePythonDescription
+	if cfg != nil && cfg.Offline.Enabled {
+		description += executePythonOfflineNote
+	}
+
+	if cfg != nil && cfg.Sandbox.Sessions.IsEnabled() && cfg.Sandbox.Sessions.Kernel {
+		description += executePythonKernelNote
+	}
+
+	properties := map[string]any{
+		"code": map[string]any{
+			"type":        "string",
+			"description": "Python code to execute",
+		},
+		"timeout": map[string]any{
+			"type":        "integer",
+			"description": "Execution timeout in seconds (default: from config, max: 600)",
+			"minimum":     MinTimeout,
+			"maximum":     MaxTimeout,
+		},
+		"session_id": map[string]any{
+			"type":        "string",
+			"description": "Session ID from a previous call. ALWAYS pass this when available - it preserves files and is faster. Only omit on the very first call.",
+		},
+		"dry_run": map[string]any{
+			"type":        "boolean",
+			"description": "Check the code without running it: syntax, ethpandaops imports and functions, datasource names and cluster rules such as missing network filters. No sandbox is started.",
+		},
+		"profile": map[string]any{
+			"type":        "string",
+			"description": "Named execution profile from the server config (e.g. \"heavy\") for more memory, CPU, time or GPUs; server://info lists the profiles you may use. Omit for the default. A session keeps the profile it was created with.",
+		},
+	}
+
+	if cfg != nil && cfg.Sandbox.Packages.Enabled {
+		properties["packages"] = map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": packagesDescription(cfg.Sandbox.Packages),
+		}
+	}
+
 	return Definition{
 		Tool: mcp.Tool{
 			Name:        ExecutePythonToolName,
-			Description: executePythonDescription,
+			Description: description,
 			InputSchema: mcp.ToolInputSchema{
-				Type: "object",
-				Properties: map[string]any{
-					"code": map[string]any{
-						"type":        "string",
-						"description": "Python code to execute",
-					},
-					"timeout": map[string]any{
-						"type":        "integer",
-						"description": "Execution timeout in seconds (default: from config, max: 600)",
-						"minimum":     MinTimeout,
-						"maximum":     MaxTimeout,
-					},
-					"session_id": map[string]any{
-						"type":        "string",
-						"description": "Session ID from a previous call. ALWAYS pass this when available - it preserves files and is faster. Only omit on the very first call.",
-					},
-				},
-				Required: []string{"code"},
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"code"},
 			},
 		},
-		Handler: newExecutePythonHandler(log, sandboxSvc, cfg, service),
+		Handler: newExecutePythonHandler(log, sandboxSvc, cfg, service, clients),
 	}
 }
 
+// packagesDescription describes the packages argument, listing the allowed
+// packages when the server restricts them.
+func packagesDescription(cfg config.SandboxPackagesConfig) string {
+	description := "Extra Python packages to pip install into the session before running, e.g. [\"scipy\", \"statsmodels>=0.14\"]. They stay installed for the rest of the session."
+	if len(cfg.Allowed) > 0 {
+		description += " Allowed: " + strings.Join(cfg.Allowed, ", ") + "."
+	}
+
+	return description
+}
+
 func newExecutePythonHandler(
 	log logrus.FieldLogger,
 	sandboxSvc sandbox.Service,
 	cfg *config.Config,
 	service *execsvc.Service,
+	clients *clientstate.Store,
 ) Handler {
 	handlerLog := log.WithField("tool", ExecutePythonToolName)
 
@@ -127,23 +137,39 @@ func newExecutePythonHandler(
 			return CallToolError(fmt.Errorf("code is required")), nil
 		}
 
-		timeout := request.GetInt("timeout", cfg.Sandbox.Timeout)
-		if timeout < MinTimeout || timeout > MaxTimeout {
+		if request.GetBool("dry_run", false) {
+			result, err := service.DryRun(code)
+			if err != nil {
+				return CallToolError(err), nil
+			}
+
+			handlerLog.WithFields(logrus.Fields{
+				"code_length": len(code),
+				"findings":    len(result.Findings),
+			}).Info("Dry-ran Python code")
+
+			return CallToolSuccess(formatDryRunResult(result)), nil
+		}
+
+		// Zero leaves the timeout to the selected profile.
+		timeout := request.GetInt("timeout", 0)
+		if timeout != 0 && (timeout < MinTimeout || timeout > MaxTimeout) {
 			return CallToolError(fmt.Errorf("timeout must be between %d and %d seconds", MinTimeout, MaxTimeout)), nil
 		}
 
 		sessionID := request.GetString("session_id", "")
+		profile := request.GetString("profile", "")
+		packages := request.GetStringSlice("packages", nil)
 
-		var ownerID string
-		if user := auth.GetAuthUser(ctx); user != nil {
-			ownerID = fmt.Sprintf("%d", user.GitHubID)
-		}
+		ownerID := auth.OwnerID(ctx)
 
 		requestFields := logrus.Fields{
 			"code_length": len(code),
 			"timeout":     timeout,
 			"backend":     sandboxSvc.Name(),
 			"session_id":  sessionID,
+			"profile":     profile,
+			"packages":    packages,
 			"owner_id":    ownerID,
 		}
 		if cfg.Sandbox.Logging.LogCode {
@@ -156,6 +182,10 @@ func newExecutePythonHandler(
 			Timeout:   timeout,
 			SessionID: sessionID,
 			OwnerID:   ownerID,
+			Profile:   profile,
+			Groups:    auth.GetAuthGroups(ctx),
+			Packages:  packages,
+			OnQueued:  queueProgress(ctx, request),
 		})
 		if err != nil {
 			handlerLog.WithError(err).Error("Execution failed")
@@ -169,7 +199,9 @@ func newExecutePythonHandler(
 			sessionKey = result.ExecutionID
 		}
 
-		if sessionsWithResourceTip.markShown(sessionKey) {
+		// Tips are tracked per identity so one user's sessions never
+		// suppress or evict another's on a shared deployment.
+		if clients.MarkOnce(clientIdentity(ctx), "resource-tip:"+sessionKey) {
 			response += resourceTipMessage
 		}
 
@@ -186,7 +218,67 @@ func newExecutePythonHandler(
 		}
 		handlerLog.WithFields(completionFields).Info("Execution completed")
 
-		return CallToolSuccess(response), nil
+		toolResult := CallToolSuccess(response)
+
+		// Return the value passed to ethpandaops.result(), uploaded charts
+		// and any rate limit state as structured content so clients can
+		// consume them without parsing the text.
+		if len(result.Result) > 0 || len(result.Artifacts) > 0 || result.RateLimit != nil {
+			structured := map[string]any{
+				"execution_id": result.ExecutionID,
+				"exit_code":    result.ExitCode,
+				"session_id":   result.SessionID,
+			}
+
+			if len(result.Result) > 0 {
+				structured["result"] = result.Result
+			}
+
+			if len(result.Artifacts) > 0 {
+				structured["artifacts"] = result.Artifacts
+			}
+
+			if result.RateLimit != nil {
+				structured["rate_limit"] = result.RateLimit
+			}
+
+			toolResult.StructuredContent = structured
+		}
+
+		return toolResult, nil
+	}
+}
+
+// queueProgress returns a callback that reports an execution's place in
+// the scheduler queue as MCP progress notifications, or nil when the client
+// didn't ask for progress.
+func queueProgress(ctx context.Context, request mcp.CallToolRequest) func(int) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return nil
+	}
+
+	token := request.Params.Meta.ProgressToken
+	progress := 0
+
+	return func(position int) {
+		message := "running"
+		if position > 0 {
+			message = fmt.Sprintf("queued, position %d", position)
+		}
+
+		// Progress must increase with every notification.
+		progress++
+
+		_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      progress,
+			"message":       message,
+		})
 	}
 }
 
@@ -201,10 +293,34 @@ func formatExecutionResult(result *sandbox.ExecutionResult, cfg *config.Config)
 		parts = append(parts, fmt.Sprintf("[stderr]\n%s", result.Stderr))
 	}
 
+	for _, hint := range result.Hints {
+		parts = append(parts, fmt.Sprintf("[hint] %s", hint.Message))
+	}
+
+	if rl := result.RateLimit; rl != nil {
+		parts = append(parts, fmt.Sprintf(
+			"[rate_limit] proxy rate limit exceeded (%d requests/min, burst %d): next request allowed at %s, full burst at %s. Wait before retrying.",
+			rl.RequestsPerMinute, rl.Limit, rl.RetryAt.Format(time.RFC3339), rl.ResetAt.Format(time.RFC3339),
+		))
+	}
+
 	if len(result.OutputFiles) > 0 {
 		parts = append(parts, fmt.Sprintf("[files] %s", strings.Join(result.OutputFiles, ", ")))
 	}
 
+	for _, artifact := range result.Artifacts {
+		artifactInfo := fmt.Sprintf("[artifact] %s %s", artifact.Name, artifact.URL)
+		if artifact.ThumbnailURL != "" {
+			artifactInfo += " thumbnail=" + artifact.ThumbnailURL
+		}
+
+		parts = append(parts, artifactInfo)
+	}
+
+	if len(result.Result) > 0 {
+		parts = append(parts, fmt.Sprintf("[result] %s", result.Result))
+	}
+
 	if result.SessionID != "" {
 		sessionInfo := fmt.Sprintf("[session] id=%s ttl=%s → REUSE THIS session_id IN ALL SUBSEQUENT CALLS",
 			result.SessionID, result.SessionTTLRemaining.Round(time.Second))
@@ -221,11 +337,40 @@ func formatExecutionResult(result *sandbox.ExecutionResult, cfg *config.Config)
 		parts = append(parts, sessionInfo)
 	}
 
+	if result.Usage != nil {
+		parts = append(parts, "[usage] "+result.Usage.String())
+	}
+
 	parts = append(parts, fmt.Sprintf("[exit=%d duration=%.2fs]", result.ExitCode, result.DurationSeconds))
 
 	return strings.Join(parts, "\n")
 }
 
+func formatDryRunResult(result *execsvc.DryRunResult) string {
+	parts := make([]string, 0, len(result.Findings)+1)
+
+	for _, finding := range result.Findings {
+		location := ""
+		if finding.Line > 0 {
+			location = fmt.Sprintf(" line %d:", finding.Line)
+		}
+
+		parts = append(parts, fmt.Sprintf("[%s]%s %s", finding.Severity, location, finding.Message))
+	}
+
+	modules := "none"
+	if len(result.Modules) > 0 {
+		modules = strings.Join(result.Modules, ", ")
+	}
+
+	parts = append(parts, fmt.Sprintf(
+		"[dry-run] not executed; modules=%s calls=%d errors=%d warnings=%d",
+		modules, result.Calls, result.Errors(), len(result.Findings)-result.Errors(),
+	))
+
+	return strings.Join(parts, "\n")
+}
+
 func formatSize(bytes int64) string {
 	const unit = 1024
 
@@ -241,3 +386,18 @@ func formatSize(bytes int64) string {
 
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// clientIdentity returns the identity that owns per-client MCP state: the
+// authenticated owner, or the MCP session when the server runs without
+// auth, so clients sharing an unauthenticated server never share state.
+func clientIdentity(ctx context.Context) string {
+	if ownerID := auth.OwnerID(ctx); ownerID != "" {
+		return ownerID
+	}
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return "mcp-session:" + session.SessionID()
+	}
+
+	return ""
+}
@@ -1,9 +1,13 @@
 package tool
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -11,11 +15,19 @@ import (
 
 	"github.com/ethpandaops/panda/pkg/auth"
 	"github.com/ethpandaops/panda/pkg/execsvc"
+	"github.com/ethpandaops/panda/pkg/sandbox"
+	"github.com/ethpandaops/panda/pkg/storage"
 )
 
 const (
 	// ManageSessionToolName is the name of the manage_session tool.
 	ManageSessionToolName = "manage_session"
+
+	// maxInlineFileSize is the largest workspace file returned inline as
+	// base64 by get_file, and the largest chunk of a range read. Larger
+	// files are published to storage and returned as a URL unless a range
+	// is requested.
+	maxInlineFileSize = 1 << 20
 )
 
 const manageSessionDescription = `Manage sandbox sessions. Use 'list' to see active sessions, 'create' to start a new session, or 'destroy' to remove a session.
@@ -23,7 +35,10 @@ const manageSessionDescription = `Manage sandbox sessions. Use 'list' to see act
 Operations:
 - list: View all active sessions with their workspace files and TTL
 - create: Create a new empty session for use with execute_python
-- destroy: Remove a session (requires session_id)`
+- destroy: Remove a session (requires session_id)
+- put_file: Write a file into the session's /workspace (requires session_id, path, content_base64). For files over 1 MiB, omit content_base64 to get an upload_url instead, then PUT the raw bytes to it before upload_expires_at, e.g. curl -T blocks.parquet '<upload_url>'
+- get_file: Read a file from the session's /workspace (requires session_id, path). Files up to 1 MiB are returned as content_base64 with their content_type; larger files are returned as a download url. Pass offset and/or length (bytes, at most 1 MiB) to read a chunk of any file instead, e.g. the footer of a parquet file; next_offset is set while bytes remain. For PNG, JPEG and GIF images, pass max_width, format ("png" or "jpeg") and/or quality to downscale and convert the image on the server first, e.g. max_width=800 format=jpeg to fit a large chart inline
+- set_env: Set non-secret env vars for every later execution in the session (requires session_id, env), e.g. {"DEFAULT_NETWORK": "sepolia"}. Names must be on the server's allowlist; an empty value removes one`
 
 // ListSessionsResponse is the response for the list operation.
 type ListSessionsResponse struct {
@@ -39,6 +54,7 @@ type SessionDetail struct {
 	LastUsed       string              `json:"last_used"`
 	TTLRemaining   string              `json:"ttl_remaining"`
 	WorkspaceFiles []WorkspaceFileInfo `json:"workspace_files"`
+	Env            map[string]string   `json:"env,omitempty"`
 }
 
 // WorkspaceFileInfo represents a file in the session workspace.
@@ -47,6 +63,36 @@ type WorkspaceFileInfo struct {
 	Size string `json:"size"`
 }
 
+// WorkspaceFileResponse is the response for the put_file and get_file operations.
+type WorkspaceFileResponse struct {
+	SessionID     string `json:"session_id"`
+	Path          string `json:"path"`
+	Size          int    `json:"size"`
+	ContentType   string `json:"content_type,omitempty"`
+	ContentBase64 string `json:"content_base64,omitempty"`
+	URL           string `json:"url,omitempty"`
+
+	// UploadURL accepts a PUT of the file's bytes until UploadExpiresAt,
+	// for put_file calls without content.
+	UploadURL       string `json:"upload_url,omitempty"`
+	UploadExpiresAt string `json:"upload_expires_at,omitempty"`
+
+	// Offset and Length describe the chunk returned by a range read.
+	Offset *int `json:"offset,omitempty"`
+	Length *int `json:"length,omitempty"`
+	// NextOffset is where the next chunk starts, while bytes remain.
+	NextOffset *int `json:"next_offset,omitempty"`
+	// OriginalSize is the file's size before an image was downscaled or
+	// converted; Size is then the converted image's size.
+	OriginalSize int `json:"original_size,omitempty"`
+}
+
+// SessionEnvResponse is the response for the set_env operation.
+type SessionEnvResponse struct {
+	SessionID string            `json:"session_id"`
+	Env       map[string]string `json:"env"`
+}
+
 // CreateSessionResponse is the response for the create operation.
 type CreateSessionResponse struct {
 	SessionID    string `json:"session_id"`
@@ -55,18 +101,26 @@ type CreateSessionResponse struct {
 }
 
 type manageSessionHandler struct {
-	log     logrus.FieldLogger
-	service *execsvc.Service
+	log        logrus.FieldLogger
+	service    *execsvc.Service
+	storageSvc storage.Service
+	uploads    *storage.UploadSigner
 }
 
-// NewManageSessionTool creates the manage_session tool definition.
+// NewManageSessionTool creates the manage_session tool definition. uploads
+// signs the upload URLs put_file returns for large files; when nil, files
+// must be sent inline.
 func NewManageSessionTool(
 	log logrus.FieldLogger,
 	service *execsvc.Service,
+	storageSvc storage.Service,
+	uploads *storage.UploadSigner,
 ) Definition {
 	h := &manageSessionHandler{
-		log:     log.WithField("tool", ManageSessionToolName),
-		service: service,
+		log:        log.WithField("tool", ManageSessionToolName),
+		service:    service,
+		storageSvc: storageSvc,
+		uploads:    uploads,
 	}
 
 	return Definition{
@@ -78,12 +132,49 @@ func NewManageSessionTool(
 				Properties: map[string]any{
 					"operation": map[string]any{
 						"type":        "string",
-						"enum":        []string{"list", "create", "destroy"},
+						"enum":        []string{"list", "create", "destroy", "put_file", "get_file", "set_env"},
 						"description": "The operation to perform",
 					},
 					"session_id": map[string]any{
 						"type":        "string",
-						"description": "Session ID (required for destroy operation)",
+						"description": "Session ID (required for destroy, put_file, get_file and set_env operations)",
+					},
+					"path": map[string]any{
+						"type":        "string",
+						"description": "File path relative to /workspace (required for put_file and get_file operations)",
+					},
+					"content_base64": map[string]any{
+						"type":        "string",
+						"description": "Base64-encoded file content (put_file; omit to get an upload_url for large files)",
+					},
+					"offset": map[string]any{
+						"type":        "integer",
+						"description": "Byte offset to start reading at (get_file range reads; negative counts from the end)",
+					},
+					"length": map[string]any{
+						"type":        "integer",
+						"description": "Number of bytes to read, at most 1048576 (get_file range reads; default: up to 1 MiB)",
+					},
+					"max_width": map[string]any{
+						"type":        "integer",
+						"minimum":     1,
+						"description": "Downscale an image to at most this many pixels wide, keeping its aspect ratio (get_file)",
+					},
+					"format": map[string]any{
+						"type":        "string",
+						"enum":        []string{storage.ImageFormatPNG, storage.ImageFormatJPEG},
+						"description": "Convert an image to this format (get_file; default: keep PNG and JPEG, convert GIF to PNG)",
+					},
+					"quality": map[string]any{
+						"type":        "integer",
+						"minimum":     1,
+						"maximum":     100,
+						"description": "JPEG quality (get_file with format=jpeg; default: 85)",
+					},
+					"env": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+						"description":          "Env vars to set (required for set_env operation)",
 					},
 				},
 				Required: []string{"operation"},
@@ -105,10 +196,7 @@ func (h *manageSessionHandler) handle(ctx context.Context, request mcp.CallToolR
 	}
 
 	// Extract owner ID from auth context for session filtering.
-	var ownerID string
-	if user := auth.GetAuthUser(ctx); user != nil {
-		ownerID = fmt.Sprintf("%d", user.GitHubID)
-	}
+	ownerID := auth.OwnerID(ctx)
 
 	switch operation {
 	case "list":
@@ -122,6 +210,64 @@ func (h *manageSessionHandler) handle(ctx context.Context, request mcp.CallToolR
 		}
 
 		return h.handleDestroy(ctx, sessionID, ownerID)
+	case "put_file", "get_file":
+		sessionID := request.GetString("session_id", "")
+		if sessionID == "" {
+			return CallToolError(fmt.Errorf("session_id is required for %s operation", operation)), nil
+		}
+
+		path := request.GetString("path", "")
+		if path == "" {
+			return CallToolError(fmt.Errorf("path is required for %s operation", operation)), nil
+		}
+
+		if operation == "get_file" {
+			args := request.GetArguments()
+			_, hasOffset := args["offset"]
+			_, hasLength := args["length"]
+
+			var byteRange *fileRange
+			if hasOffset || hasLength {
+				byteRange = &fileRange{
+					offset: request.GetInt("offset", 0),
+					length: request.GetInt("length", maxInlineFileSize),
+				}
+			}
+
+			var imageOpts *storage.ImageOptions
+
+			_, hasMaxWidth := args["max_width"]
+			_, hasFormat := args["format"]
+			_, hasQuality := args["quality"]
+
+			if hasMaxWidth || hasFormat || hasQuality {
+				if byteRange != nil {
+					return CallToolError(fmt.Errorf("offset and length cannot be combined with max_width, format or quality")), nil
+				}
+
+				imageOpts = &storage.ImageOptions{
+					MaxWidth: request.GetInt("max_width", 0),
+					Format:   request.GetString("format", ""),
+					Quality:  request.GetInt("quality", 0),
+				}
+			}
+
+			return h.handleGetFile(ctx, sessionID, ownerID, path, byteRange, imageOpts)
+		}
+
+		return h.handlePutFile(ctx, sessionID, ownerID, path, request.GetString("content_base64", ""))
+	case "set_env":
+		sessionID := request.GetString("session_id", "")
+		if sessionID == "" {
+			return CallToolError(fmt.Errorf("session_id is required for set_env operation")), nil
+		}
+
+		env, err := envArgument(request.GetArguments()["env"])
+		if err != nil {
+			return CallToolError(err), nil
+		}
+
+		return h.handleSetEnv(ctx, sessionID, ownerID, env)
 	default:
 		return CallToolError(fmt.Errorf("unknown operation: %s", operation)), nil
 	}
@@ -151,6 +297,7 @@ func (h *manageSessionHandler) handleList(ctx context.Context, ownerID string) (
 			LastUsed:       s.LastUsed.Format(time.RFC3339),
 			TTLRemaining:   s.TTLRemaining.Round(time.Second).String(),
 			WorkspaceFiles: workspaceFiles,
+			Env:            h.service.SessionEnv(s.ID, ownerID),
 		})
 	}
 
@@ -236,3 +383,232 @@ func (h *manageSessionHandler) handleDestroy(
 
 	return CallToolSuccess(fmt.Sprintf("Session %s has been destroyed.", sessionID)), nil
 }
+
+func (h *manageSessionHandler) handlePutFile(
+	ctx context.Context,
+	sessionID, ownerID, path, contentBase64 string,
+) (*mcp.CallToolResult, error) {
+	if contentBase64 == "" {
+		return h.handlePutFileURL(sessionID, ownerID, path)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(contentBase64)
+	if err != nil {
+		return CallToolError(fmt.Errorf("decoding content_base64: %w", err)), nil
+	}
+
+	if err := h.service.WriteSessionFile(ctx, sessionID, ownerID, path, data); err != nil {
+		return CallToolError(err), nil
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"session_id": sessionID,
+		"path":       path,
+		"size":       len(data),
+	}).Info("Wrote workspace file")
+
+	return marshalWorkspaceFileResponse(&WorkspaceFileResponse{
+		SessionID: sessionID,
+		Path:      path,
+		Size:      len(data),
+	})
+}
+
+// handlePutFileURL returns a presigned URL the caller uploads a large file
+// to, so its bytes don't pass through the tool call as base64.
+func (h *manageSessionHandler) handlePutFileURL(sessionID, ownerID, path string) (*mcp.CallToolResult, error) {
+	if h.uploads == nil {
+		return CallToolError(fmt.Errorf("content_base64 is required for put_file operation")), nil
+	}
+
+	name, err := sandbox.CleanWorkspacePath(path)
+	if err != nil {
+		return CallToolError(err), nil
+	}
+
+	uploadURL, expiresAt := h.uploads.Sign(sessionID, ownerID, name)
+
+	return marshalWorkspaceFileResponse(&WorkspaceFileResponse{
+		SessionID:       sessionID,
+		Path:            name,
+		UploadURL:       uploadURL,
+		UploadExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// fileRange is a byte range requested from get_file.
+type fileRange struct {
+	offset int
+	length int
+}
+
+func (h *manageSessionHandler) handleGetFile(
+	ctx context.Context,
+	sessionID, ownerID, path string,
+	byteRange *fileRange,
+	imageOpts *storage.ImageOptions,
+) (*mcp.CallToolResult, error) {
+	name, err := sandbox.CleanWorkspacePath(path)
+	if err != nil {
+		return CallToolError(err), nil
+	}
+
+	data, err := h.service.ReadSessionFile(ctx, sessionID, ownerID, path)
+	if err != nil {
+		return CallToolError(err), nil
+	}
+
+	response := &WorkspaceFileResponse{
+		SessionID:   sessionID,
+		Path:        path,
+		Size:        len(data),
+		ContentType: storage.ContentType(path, data),
+	}
+
+	// Published copies keep only the file name, so workspace directories
+	// don't end up in storage keys. Converted images are named for their
+	// new format.
+	publishName := filepath.Base(name)
+
+	if imageOpts != nil {
+		converted, contentType, err := storage.ConvertImage(data, *imageOpts)
+		if err != nil {
+			return CallToolError(fmt.Errorf("converting %s: %w", path, err)), nil
+		}
+
+		response.OriginalSize = len(data)
+		response.Size = len(converted)
+		response.ContentType = contentType
+		publishName = imageFileName(publishName, contentType)
+		data = converted
+	}
+
+	if byteRange != nil {
+		chunk, err := readRange(data, *byteRange)
+		if err != nil {
+			return CallToolError(err), nil
+		}
+
+		offset, length := byteRange.offset, len(chunk)
+		if offset < 0 {
+			offset += len(data)
+		}
+
+		response.ContentBase64 = base64.StdEncoding.EncodeToString(chunk)
+		response.Offset, response.Length = &offset, &length
+
+		if next := offset + length; next < len(data) {
+			response.NextOffset = &next
+		}
+
+		return marshalWorkspaceFileResponse(response)
+	}
+
+	if len(data) <= maxInlineFileSize {
+		response.ContentBase64 = base64.StdEncoding.EncodeToString(data)
+
+		return marshalWorkspaceFileResponse(response)
+	}
+
+	if h.storageSvc == nil {
+		return CallToolError(fmt.Errorf(
+			"file is %s, larger than the %s inline limit, and storage is unavailable; read it in chunks with offset and length",
+			formatSize(int64(len(data))), formatSize(maxInlineFileSize),
+		)), nil
+	}
+
+	_, url, err := h.storageSvc.Upload("session-"+sessionID, publishName, bytes.NewReader(data))
+	if err != nil {
+		return CallToolError(fmt.Errorf("publishing file to storage: %w", err)), nil
+	}
+
+	response.URL = url
+
+	return marshalWorkspaceFileResponse(response)
+}
+
+// imageFileName returns name with the extension of contentType.
+func imageFileName(name, contentType string) string {
+	ext := ".png"
+	if contentType == "image/jpeg" {
+		ext = ".jpg"
+	}
+
+	if current := strings.ToLower(filepath.Ext(name)); current == ext || (ext == ".jpg" && current == ".jpeg") {
+		return name
+	}
+
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ext
+}
+
+// readRange returns the bytes of data selected by r. A negative offset
+// counts back from the end of data.
+func readRange(data []byte, r fileRange) ([]byte, error) {
+	offset := r.offset
+	if offset < 0 {
+		offset = max(len(data)+offset, 0)
+	}
+
+	if offset > len(data) {
+		return nil, fmt.Errorf("offset %d is past the end of the %d byte file", r.offset, len(data))
+	}
+
+	if r.length < 1 || r.length > maxInlineFileSize {
+		return nil, fmt.Errorf("length must be between 1 and %d", maxInlineFileSize)
+	}
+
+	return data[offset:min(offset+r.length, len(data))], nil
+}
+
+func (h *manageSessionHandler) handleSetEnv(
+	ctx context.Context,
+	sessionID, ownerID string,
+	overrides map[string]string,
+) (*mcp.CallToolResult, error) {
+	env, err := h.service.SetSessionEnv(ctx, sessionID, ownerID, overrides)
+	if err != nil {
+		return CallToolError(err), nil
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"session_id": sessionID,
+		"count":      len(env),
+	}).Debug("Set session env")
+
+	data, err := json.MarshalIndent(&SessionEnvResponse{SessionID: sessionID, Env: env}, "", "  ")
+	if err != nil {
+		return CallToolError(fmt.Errorf("marshaling response: %w", err)), nil
+	}
+
+	return CallToolSuccess(string(data)), nil
+}
+
+// envArgument converts the set_env env argument to a string map.
+func envArgument(raw any) (map[string]string, error) {
+	values, ok := raw.(map[string]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("env is required for set_env operation and must be an object of strings")
+	}
+
+	env := make(map[string]string, len(values))
+
+	for name, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("env var %q must be a string", name)
+		}
+
+		env[name] = str
+	}
+
+	return env, nil
+}
+
+func marshalWorkspaceFileResponse(response *WorkspaceFileResponse) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return CallToolError(fmt.Errorf("marshaling response: %w", err)), nil
+	}
+
+	return CallToolSuccess(string(data)), nil
+}
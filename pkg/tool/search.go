@@ -8,6 +8,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/panda/pkg/auth"
 	"github.com/ethpandaops/panda/pkg/searchsvc"
 )
 
@@ -89,7 +90,7 @@ func NewSearchTool(
 }
 
 func (h *searchHandler) handle(
-	_ context.Context,
+	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	h.log.Debug("Handling search request")
@@ -101,7 +102,7 @@ func (h *searchHandler) handle(
 
 	rawType := request.GetString("type", "")
 	if rawType == "" {
-		return h.searchAll(request, query)
+		return h.searchAll(ctx, request, query)
 	}
 
 	searchType, err := searchsvc.NormalizeSearchType(rawType)
@@ -111,7 +112,7 @@ func (h *searchHandler) handle(
 
 	switch searchType {
 	case searchsvc.SearchTypeExamples:
-		return h.searchExamples(request, query)
+		return h.searchExamples(ctx, request, query)
 	case searchsvc.SearchTypeRunbooks:
 		return h.searchRunbooks(request, query)
 	case searchsvc.SearchTypeEIPs:
@@ -122,6 +123,7 @@ func (h *searchHandler) handle(
 }
 
 func (h *searchHandler) searchAll(
+	ctx context.Context,
 	request mcp.CallToolRequest,
 	query string,
 ) (*mcp.CallToolResult, error) {
@@ -133,6 +135,10 @@ func (h *searchHandler) searchAll(
 		return CallToolError(err), nil
 	}
 
+	if response.Examples != nil {
+		h.service.NoteShown(auth.OwnerID(ctx), response.Examples.Results)
+	}
+
 	data, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return CallToolError(fmt.Errorf("marshaling response: %w", err)), nil
@@ -147,6 +153,7 @@ func (h *searchHandler) searchAll(
 }
 
 func (h *searchHandler) searchExamples(
+	ctx context.Context,
 	request mcp.CallToolRequest,
 	query string,
 ) (*mcp.CallToolResult, error) {
@@ -163,6 +170,8 @@ func (h *searchHandler) searchExamples(
 		return CallToolError(err), nil
 	}
 
+	h.service.NoteShown(auth.OwnerID(ctx), response.Results)
+
 	data, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return CallToolError(fmt.Errorf("marshaling response: %w", err)), nil
@@ -2,7 +2,10 @@ package types
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -46,6 +49,11 @@ type ReadHandler func(ctx context.Context, uri string) (string, error)
 type StaticResource struct {
 	Resource mcp.Resource
 	Handler  ReadHandler
+	// Module names the module that registered the resource, if any.
+	Module string
+	// Paged marks a list resource whose handler honors the ListParams of
+	// the read, given in the URI query.
+	Paged bool
 }
 
 // TemplateResource is a resource with a URI pattern.
@@ -53,4 +61,89 @@ type TemplateResource struct {
 	Template mcp.ResourceTemplate
 	Pattern  *regexp.Regexp
 	Handler  ReadHandler
+	// Module names the module that registered the resource, if any.
+	Module string
+}
+
+// ListView selects how much of each item a list resource returns.
+type ListView string
+
+const (
+	// ListViewDetailed returns every item in full. It is the default.
+	ListViewDetailed ListView = "detailed"
+	// ListViewCompact returns only the names of the items.
+	ListViewCompact ListView = "compact"
+)
+
+// ListParams are the paging and view parameters of a list resource read,
+// given in the resource URI query: ?cursor=...&limit=N&view=compact.
+type ListParams struct {
+	// Offset is the position of the first item, decoded from the cursor.
+	Offset int
+	// Limit caps the items returned; zero returns all remaining items.
+	Limit int
+	View  ListView
+}
+
+// Paged reports whether the read asked for a page rather than the whole list.
+func (p ListParams) Paged() bool {
+	return p.Limit > 0 || p.Offset > 0
+}
+
+// Compact reports whether the read asked for the compact view.
+func (p ListParams) Compact() bool {
+	return p.View == ListViewCompact
+}
+
+// Page returns the bounds [start, end) of the requested page of a list of n
+// items, and the cursor of the next page, which is empty on the last page.
+func (p ListParams) Page(n int) (start, end int, next string) {
+	start = min(p.Offset, n)
+	end = n
+
+	if p.Limit > 0 && start+p.Limit < n {
+		end = start + p.Limit
+		next = EncodeListCursor(end)
+	}
+
+	return start, end, next
+}
+
+// EncodeListCursor returns the opaque cursor of the page starting at offset.
+func EncodeListCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// DecodeListCursor returns the offset of a cursor from EncodeListCursor.
+func DecodeListCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return offset, nil
+}
+
+type listParamsKeyType struct{}
+
+var listParamsKey = listParamsKeyType{}
+
+// GetListParams extracts the list parameters of a resource read from a
+// context. It returns the whole list in the detailed view by default.
+func GetListParams(ctx context.Context) ListParams {
+	if v, ok := ctx.Value(listParamsKey).(ListParams); ok {
+		return v
+	}
+
+	return ListParams{View: ListViewDetailed}
+}
+
+// WithListParams returns a new context with the given list parameters.
+func WithListParams(ctx context.Context, p ListParams) context.Context {
+	return context.WithValue(ctx, listParamsKey, p)
 }
@@ -16,4 +16,7 @@ type Runbook struct {
 	Content string `yaml:"-" json:"content"`
 	// FilePath is the source file for debugging.
 	FilePath string `yaml:"-" json:"file_path"`
+	// Source names where the runbook came from: "builtin" or a configured
+	// runbook source.
+	Source string `yaml:"-" json:"source,omitempty"`
 }
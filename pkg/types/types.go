@@ -2,6 +2,12 @@
 // and modules to avoid circular dependencies.
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 // DatasourceInfo describes a configured datasource for the
 // datasources:// MCP resources.
 type DatasourceInfo struct {
@@ -13,6 +19,245 @@ type DatasourceInfo struct {
 	Description string `json:"description,omitempty"`
 	// Metadata contains type-specific metadata (e.g. database, url).
 	Metadata map[string]string `json:"metadata,omitempty"`
+	// Maintenance lists the datasource's recurring maintenance windows.
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
+	// Status is whether the datasource is usable right now. It is only set
+	// for datasources with maintenance windows.
+	Status *DatasourceStatus `json:"status,omitempty"`
+	// CircuitBreaker is the proxy's circuit breaker state for the
+	// datasource, when one is configured.
+	CircuitBreaker *CircuitBreakerStatus `json:"circuit_breaker,omitempty"`
+}
+
+// SampleRowsConfig controls the sample rows attached to table schema
+// resources during schema discovery.
+type SampleRowsConfig struct {
+	// Enabled fetches sample rows for every discovered table. Off by default.
+	Enabled bool `yaml:"enabled"`
+	// Rows is the number of rows fetched per table.
+	Rows int `yaml:"rows,omitempty"`
+	// MaxValueLength truncates longer values, in characters. Arrays, maps
+	// and tuples are truncated by their JSON encoding.
+	MaxValueLength int `yaml:"max_value_length,omitempty"`
+	// RedactColumns are glob patterns matched against "column" and
+	// "table.column"; values of matching columns are replaced.
+	RedactColumns []string `yaml:"redact_columns,omitempty"`
+}
+
+// SavedQuery is an operator-curated query offered to agents as a starting
+// point, e.g. the LogQL for a service's errors.
+type SavedQuery struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Datasource limits the query to one datasource. Empty means any.
+	Datasource string   `yaml:"datasource,omitempty" json:"datasource,omitempty"`
+	Query      string   `yaml:"query" json:"query"`
+	Tags       []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// MaintenanceWindow is a recurring window during which a datasource is
+// unavailable. It starts whenever Schedule, a five-field cron expression
+// evaluated in UTC, fires and lasts Duration.
+type MaintenanceWindow struct {
+	Schedule string        `yaml:"schedule"`
+	Duration time.Duration `yaml:"duration"`
+	Reason   string        `yaml:"reason,omitempty"`
+}
+
+type maintenanceWindowJSON struct {
+	Schedule string `json:"schedule"`
+	Duration string `json:"duration"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// MarshalJSON encodes Duration as a Go duration string such as "2h0m0s".
+func (w MaintenanceWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(maintenanceWindowJSON{
+		Schedule: w.Schedule,
+		Duration: w.Duration.String(),
+		Reason:   w.Reason,
+	})
+}
+
+// UnmarshalJSON decodes the encoding written by MarshalJSON.
+func (w *MaintenanceWindow) UnmarshalJSON(data []byte) error {
+	var raw maintenanceWindowJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(raw.Duration)
+	if err != nil {
+		return fmt.Errorf("parsing maintenance duration: %w", err)
+	}
+
+	*w = MaintenanceWindow{Schedule: raw.Schedule, Duration: duration, Reason: raw.Reason}
+
+	return nil
+}
+
+// DatasourceStatus is the current availability of a datasource.
+type DatasourceStatus struct {
+	// Available is false while a maintenance window is active.
+	Available bool `json:"available"`
+	// Reason explains why the datasource is unavailable.
+	Reason string `json:"reason,omitempty"`
+	// Until is when the active maintenance window ends.
+	Until *time.Time `json:"until,omitempty"`
+	// NextMaintenance is when the next maintenance window starts.
+	NextMaintenance *time.Time `json:"next_maintenance,omitempty"`
+}
+
+// Circuit breaker states.
+const (
+	CircuitBreakerClosed   = "closed"
+	CircuitBreakerOpen     = "open"
+	CircuitBreakerHalfOpen = "half_open"
+)
+
+// CircuitBreakerStatus is the state of the proxy's circuit breaker for one
+// datasource.
+type CircuitBreakerStatus struct {
+	// State is "closed", "open" or "half_open".
+	State string `json:"state"`
+	// Requests and Failures count the current window's outcomes while the
+	// breaker is closed.
+	Requests int `json:"requests,omitempty"`
+	Failures int `json:"failures,omitempty"`
+	// RetryAt is when an open breaker lets the next probe request through.
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+// HealthProbe is the result of probing one datasource with a real request.
+type HealthProbe struct {
+	// Datasource is the datasource, instance or network that was probed.
+	Datasource string `json:"datasource"`
+	// Healthy is true when the probe request succeeded.
+	Healthy bool `json:"healthy"`
+	// LatencyMS is the probe round-trip time in milliseconds.
+	LatencyMS float64 `json:"latency_ms"`
+	// Error describes why the probe failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorHint is a targeted suggestion derived from an error in execution output.
+type ErrorHint struct {
+	// Kind identifies the error signature, e.g. "clickhouse_wrong_cluster".
+	Kind string `json:"kind"`
+	// Message is the suggestion shown alongside the execution result.
+	Message string `json:"message"`
+}
+
+// RateLimitErrorCode identifies proxy rate limit rejections in error bodies.
+const RateLimitErrorCode = "RATE_LIMITED"
+
+// RateLimitState is a user's proxy rate limit as of one request.
+type RateLimitState struct {
+	// Limit is the burst size: the most requests allowed back to back.
+	Limit int `json:"limit"`
+	// Remaining is how many requests could be made immediately after this one.
+	Remaining int `json:"remaining"`
+	// RequestsPerMinute is the rate at which spent requests are refilled.
+	RequestsPerMinute int `json:"requests_per_minute"`
+	// RetryAfterSeconds is how long until the next request is allowed, 0
+	// when one is allowed now.
+	RetryAfterSeconds int `json:"retry_after_seconds"`
+	// RetryAt is when the next request is allowed.
+	RetryAt time.Time `json:"retry_at"`
+	// ResetAt is when the full burst is available again.
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// Lint finding severities.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+// LintFinding is a problem found in code by a dry run, before it executes.
+type LintFinding struct {
+	// Line is the 1-based source line, or 0 when the finding has no location.
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	// Kind identifies the check, e.g. "clickhouse_missing_network_filter".
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// PythonCall is a call to an ethpandaops module function found in code by a
+// dry run.
+type PythonCall struct {
+	// Module is the ethpandaops module, e.g. "clickhouse".
+	Module   string
+	Function string
+	Line     int
+	Args     []PythonArg
+}
+
+// PythonArg is an argument of a PythonCall. Value is only known when the
+// argument is a string literal or a name bound to one.
+type PythonArg struct {
+	// Keyword is the parameter name of a keyword argument.
+	Keyword string
+	Value   string
+	Known   bool
+}
+
+// Arg returns the known string value of the argument at position pos, or of
+// the keyword argument named keyword.
+func (c PythonCall) Arg(pos int, keyword string) (string, bool) {
+	positional := 0
+
+	for _, arg := range c.Args {
+		if arg.Keyword != "" {
+			if arg.Keyword == keyword {
+				return arg.Value, arg.Known
+			}
+
+			continue
+		}
+
+		if positional == pos {
+			return arg.Value, arg.Known
+		}
+
+		positional++
+	}
+
+	return "", false
+}
+
+// Module health statuses.
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+	HealthStatusNotProbed = "not_probed"
+)
+
+// ModuleHealth is the probed health of one module.
+type ModuleHealth struct {
+	Module    string        `json:"module"`
+	Status    string        `json:"status"`
+	Probes    []HealthProbe `json:"probes,omitempty"`
+	CheckedAt *time.Time    `json:"checked_at,omitempty"`
+}
+
+// Coverage target kinds.
+const (
+	CoverageKindClickHouseTable  = "clickhouse_table"
+	CoverageKindPrometheusMetric = "prometheus_metric"
+)
+
+// CoverageTarget is a discovered object, such as a table or metric, that
+// query examples are expected to reference.
+type CoverageTarget struct {
+	// Kind is the object kind (e.g. "clickhouse_table").
+	Kind string `json:"kind"`
+	// Name is the table or metric name as it appears in queries.
+	Name string `json:"name"`
+	// Datasource is the cluster or instance the object was discovered in.
+	Datasource string `json:"datasource,omitempty"`
 }
 
 // ExampleCategory represents a category of query examples.
@@ -0,0 +1,176 @@
+// Package userexamples stores query examples promoted from successful
+// executions, alongside the examples compiled into modules.
+package userexamples
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+const (
+	// DefaultCategory is the category promoted examples go to when none is given.
+	DefaultCategory = "promoted"
+
+	// fileName is the examples file inside the store directory. It uses the
+	// same layout as the modules' examples.yaml.
+	fileName = "examples.yaml"
+)
+
+// ErrDuplicate is returned when a category already has an example with the
+// same name.
+var ErrDuplicate = errors.New("an example with this name already exists in the category")
+
+var categoryPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// Entry is an example as stored, with its category.
+type Entry struct {
+	CategoryKey  string
+	CategoryName string
+	Example      types.Example
+}
+
+// Store persists promoted examples to a YAML file and serves them as an
+// examples source for the module registry.
+type Store struct {
+	mu         sync.RWMutex
+	path       string
+	categories map[string]types.ExampleCategory
+}
+
+// Open loads (or creates) the store in dir.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating examples directory: %w", err)
+	}
+
+	s := &Store{
+		path:       filepath.Join(dir, fileName),
+		categories: make(map[string]types.ExampleCategory, 1),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading examples: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &s.categories); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+
+	if s.categories == nil {
+		s.categories = make(map[string]types.ExampleCategory, 1)
+	}
+
+	return s, nil
+}
+
+// Examples returns a copy of the stored examples by category key.
+func (s *Store) Examples() map[string]types.ExampleCategory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]types.ExampleCategory, len(s.categories))
+	for key, category := range s.categories {
+		category.Examples = slices.Clone(category.Examples)
+		result[key] = category
+	}
+
+	return result
+}
+
+// Add stores example under categoryKey, creating the category when needed.
+// An empty key uses DefaultCategory.
+func (s *Store) Add(categoryKey string, example types.Example) (Entry, error) {
+	if categoryKey == "" {
+		categoryKey = DefaultCategory
+	}
+
+	if !categoryPattern.MatchString(categoryKey) {
+		return Entry{}, fmt.Errorf("category %q must be lowercase letters, digits and underscores", categoryKey)
+	}
+
+	example.Name = strings.TrimSpace(example.Name)
+	example.Description = strings.TrimSpace(example.Description)
+	example.Query = strings.TrimSpace(example.Query)
+
+	if example.Name == "" || example.Description == "" || example.Query == "" {
+		return Entry{}, errors.New("name, description and query are required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, ok := s.categories[categoryKey]
+	if !ok {
+		category = types.ExampleCategory{
+			Name:        categoryName(categoryKey),
+			Description: "Examples promoted from successful executions",
+		}
+	}
+
+	for _, existing := range category.Examples {
+		if strings.EqualFold(existing.Name, example.Name) {
+			return Entry{}, fmt.Errorf("%w: %q in %s", ErrDuplicate, example.Name, categoryKey)
+		}
+	}
+
+	category.Examples = append(category.Examples, example)
+
+	updated := maps.Clone(s.categories)
+	updated[categoryKey] = category
+
+	if err := s.write(updated); err != nil {
+		return Entry{}, err
+	}
+
+	s.categories = updated
+
+	return Entry{CategoryKey: categoryKey, CategoryName: category.Name, Example: example}, nil
+}
+
+// write replaces the examples file atomically. Callers must hold s.mu.
+func (s *Store) write(categories map[string]types.ExampleCategory) error {
+	data, err := yaml.Marshal(categories)
+	if err != nil {
+		return fmt.Errorf("marshaling examples: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing examples: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replacing examples: %w", err)
+	}
+
+	return nil
+}
+
+// categoryName derives a display name from a category key, e.g.
+// "block_timing" becomes "Block Timing".
+func categoryName(key string) string {
+	words := strings.Split(key, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,61 @@
+package userexamples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
+)
+
+func TestStoreAddAndReload(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	require.NoError(t, err)
+	assert.Empty(t, store.Examples())
+
+	entry, err := store.Add("", types.Example{
+		Name:        " Block arrival ",
+		Description: "Block arrival times by client",
+		Query:       "SELECT 1\n",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCategory, entry.CategoryKey)
+	assert.Equal(t, "Promoted", entry.CategoryName)
+	assert.Equal(t, "Block arrival", entry.Example.Name)
+	assert.Equal(t, "SELECT 1", entry.Example.Query)
+
+	_, err = store.Add("block_timing", types.Example{Name: "a", Description: "b", Query: "c"})
+	require.NoError(t, err)
+
+	reopened, err := Open(dir)
+	require.NoError(t, err)
+
+	examples := reopened.Examples()
+	require.Len(t, examples, 2)
+	assert.Equal(t, "Block Timing", examples["block_timing"].Name)
+	require.Len(t, examples[DefaultCategory].Examples, 1)
+	assert.Equal(t, "Block arrival", examples[DefaultCategory].Examples[0].Name)
+}
+
+func TestStoreAddRejectsInvalid(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	example := types.Example{Name: "Block arrival", Description: "d", Query: "q"}
+
+	_, err = store.Add("", example)
+	require.NoError(t, err)
+
+	example.Name = "block ARRIVAL"
+	_, err = store.Add("", example)
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	_, err = store.Add("Bad-Category", types.Example{Name: "x", Description: "d", Query: "q"})
+	require.Error(t, err)
+
+	_, err = store.Add("", types.Example{Name: "x", Query: "q"})
+	require.Error(t, err)
+}
@@ -0,0 +1,238 @@
+// Package webhook posts server events, such as module health changes and
+// failed scheduled runs, to Slack, Discord or generic HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/panda/pkg/config"
+	"github.com/ethpandaops/panda/pkg/observability"
+)
+
+// maxDiscordContent is Discord's message length limit.
+const maxDiscordContent = 2000
+
+// Event is something endpoints are notified about.
+type Event struct {
+	// Type is one of the config.WebhookEvent* constants.
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Summary is a one-line description, the default message text.
+	Summary string `json:"summary"`
+	// Fields carry event details, e.g. "module" and "status" for
+	// module_health or "job" and "url" for schedule_failure.
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+type endpoint struct {
+	cfg      config.WebhookEndpointConfig
+	events   map[string]struct{}
+	template *template.Template
+}
+
+func (e *endpoint) wants(eventType string) bool {
+	if len(e.events) == 0 {
+		return true
+	}
+
+	_, ok := e.events[eventType]
+
+	return ok
+}
+
+// Notifier delivers events to the configured endpoints in the background,
+// retrying failed deliveries with exponential backoff.
+type Notifier struct {
+	log       logrus.FieldLogger
+	cfg       config.WebhooksConfig
+	endpoints []*endpoint
+	client    *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a notifier for validated configuration.
+func New(log logrus.FieldLogger, cfg config.WebhooksConfig) (*Notifier, error) {
+	endpoints := make([]*endpoint, 0, len(cfg.Endpoints))
+
+	for _, endpointCfg := range cfg.Endpoints {
+		tmpl, err := endpointCfg.ParseTemplate()
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %q: %w", endpointCfg.Name, err)
+		}
+
+		events := make(map[string]struct{}, len(endpointCfg.Events))
+		for _, event := range endpointCfg.Events {
+			events[event] = struct{}{}
+		}
+
+		endpoints = append(endpoints, &endpoint{cfg: endpointCfg, events: events, template: tmpl})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		log:       log.WithField("component", "webhooks"),
+		cfg:       cfg,
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+// Wants reports whether any endpoint receives eventType, so callers can
+// skip work for events nobody listens to.
+func (n *Notifier) Wants(eventType string) bool {
+	for _, e := range n.endpoints {
+		if e.wants(eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Notify sends event to every endpoint subscribed to it without blocking.
+func (n *Notifier) Notify(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	for _, e := range n.endpoints {
+		if !e.wants(event.Type) {
+			continue
+		}
+
+		n.wg.Add(1)
+
+		go func() {
+			defer n.wg.Done()
+
+			n.deliver(e, event)
+		}()
+	}
+}
+
+// Stop abandons pending retries and waits for requests in flight, which
+// are bounded by the configured timeout.
+func (n *Notifier) Stop() {
+	n.cancel()
+	n.wg.Wait()
+}
+
+func (n *Notifier) deliver(e *endpoint, event Event) {
+	log := n.log.WithFields(logrus.Fields{"endpoint": e.cfg.Name, "event": event.Type})
+
+	body, err := e.payload(event)
+	if err != nil {
+		log.WithError(err).Warn("Failed to render webhook payload")
+		observability.WebhookDeliveriesTotal.WithLabelValues(e.cfg.Name, event.Type, "failed").Inc()
+
+		return
+	}
+
+	backoff := n.cfg.Backoff
+
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(e, body)
+		if err == nil {
+			observability.WebhookDeliveriesTotal.WithLabelValues(e.cfg.Name, event.Type, "delivered").Inc()
+
+			return
+		}
+
+		if !retry || attempt >= n.cfg.Attempts {
+			log.WithError(err).WithField("attempts", attempt).Warn("Failed to deliver webhook")
+			observability.WebhookDeliveriesTotal.WithLabelValues(e.cfg.Name, event.Type, "failed").Inc()
+
+			return
+		}
+
+		log.WithError(err).WithField("attempt", attempt).Debug("Webhook delivery failed, retrying")
+
+		select {
+		case <-n.ctx.Done():
+			observability.WebhookDeliveriesTotal.WithLabelValues(e.cfg.Name, event.Type, "failed").Inc()
+
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// post sends one request. It reports whether a failure is worth retrying:
+// connection errors, 429 and 5xx responses are.
+func (n *Notifier) post(e *endpoint, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, e.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range e.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// payload renders the request body for event in the endpoint's format.
+func (e *endpoint) payload(event Event) ([]byte, error) {
+	text := event.Summary
+
+	if e.template != nil {
+		var buf bytes.Buffer
+		if err := e.template.Execute(&buf, event); err != nil {
+			return nil, fmt.Errorf("executing template: %w", err)
+		}
+
+		text = buf.String()
+	}
+
+	switch e.cfg.Format {
+	case config.WebhookFormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case config.WebhookFormatDiscord:
+		if len(text) > maxDiscordContent {
+			text = text[:maxDiscordContent-3] + "..."
+		}
+
+		return json.Marshal(map[string]string{"content": text})
+	default:
+		if e.template != nil {
+			return []byte(text), nil
+		}
+
+		return json.Marshal(event)
+	}
+}
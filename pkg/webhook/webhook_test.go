@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/config"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int
+}
+
+// serve answers with the queued statuses, then 200.
+func (r *receiver) serve(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.bodies...)
+}
+
+func newTestNotifier(t *testing.T, endpoints ...config.WebhookEndpointConfig) *Notifier {
+	t.Helper()
+
+	cfg := config.WebhooksConfig{
+		Endpoints: endpoints,
+		Attempts:  3,
+		Backoff:   time.Millisecond,
+		Timeout:   time.Second,
+	}
+	require.NoError(t, cfg.Validate())
+
+	n, err := New(logrus.New(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(n.Stop)
+
+	return n
+}
+
+var testEvent = Event{
+	Type:    config.WebhookEventScheduleFailure,
+	Time:    time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+	Summary: "Scheduled job gaps failure with exit code 1",
+	Fields:  map[string]any{"job": "gaps", "exit_code": 1},
+}
+
+func TestNotifyFormats(t *testing.T) {
+	t.Parallel()
+
+	rcv := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rcv.serve))
+	t.Cleanup(srv.Close)
+
+	n := newTestNotifier(t,
+		config.WebhookEndpointConfig{Name: "slack", URL: srv.URL, Format: config.WebhookFormatSlack},
+		config.WebhookEndpointConfig{
+			Name:     "discord",
+			URL:      srv.URL,
+			Format:   config.WebhookFormatDiscord,
+			Template: "**{{.Fields.job}}** failed",
+		},
+		config.WebhookEndpointConfig{
+			Name:    "generic",
+			URL:     srv.URL,
+			Headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		config.WebhookEndpointConfig{
+			Name:     "templated",
+			URL:      srv.URL,
+			Template: `{"alert": {{json .Summary}}, "job": "{{.Fields.job}}"}`,
+		},
+	)
+
+	n.Notify(testEvent)
+	n.wg.Wait()
+
+	assert.ElementsMatch(t, []string{
+		`{"text":"Scheduled job gaps failure with exit code 1"}`,
+		`{"content":"**gaps** failed"}`,
+		`{"type":"schedule_failure","time":"2026-03-10T12:00:00Z","summary":"Scheduled job gaps failure with exit code 1","fields":{"exit_code":1,"job":"gaps"}}`,
+		`{"alert": "Scheduled job gaps failure with exit code 1", "job": "gaps"}`,
+	}, rcv.received())
+
+	authorized := 0
+
+	for _, header := range rcv.headers {
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+		if header.Get("Authorization") == "Bearer secret" {
+			authorized++
+		}
+	}
+
+	assert.Equal(t, 1, authorized)
+}
+
+func TestNotifyRetries(t *testing.T) {
+	t.Parallel()
+
+	rcv := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(http.HandlerFunc(rcv.serve))
+	t.Cleanup(srv.Close)
+
+	n := newTestNotifier(t, config.WebhookEndpointConfig{Name: "flaky", URL: srv.URL})
+	n.Notify(testEvent)
+	n.wg.Wait()
+
+	assert.Len(t, rcv.received(), 3)
+
+	// Client errors aren't retried.
+	rcv = &receiver{statuses: []int{http.StatusBadRequest}}
+	srv = httptest.NewServer(http.HandlerFunc(rcv.serve))
+	t.Cleanup(srv.Close)
+
+	n = newTestNotifier(t, config.WebhookEndpointConfig{Name: "bad", URL: srv.URL})
+	n.Notify(testEvent)
+	n.wg.Wait()
+
+	assert.Len(t, rcv.received(), 1)
+
+	// Attempts are capped.
+	rcv = &receiver{statuses: []int{500, 500, 500, 500}}
+	srv = httptest.NewServer(http.HandlerFunc(rcv.serve))
+	t.Cleanup(srv.Close)
+
+	n = newTestNotifier(t, config.WebhookEndpointConfig{Name: "down", URL: srv.URL})
+	n.Notify(testEvent)
+	n.wg.Wait()
+
+	assert.Len(t, rcv.received(), 3)
+}
+
+func TestNotifyEventFilter(t *testing.T) {
+	t.Parallel()
+
+	rcv := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rcv.serve))
+	t.Cleanup(srv.Close)
+
+	n := newTestNotifier(t, config.WebhookEndpointConfig{
+		Name:   "health",
+		URL:    srv.URL,
+		Events: []string{config.WebhookEventModuleHealth},
+	})
+
+	assert.True(t, n.Wants(config.WebhookEventModuleHealth))
+	assert.False(t, n.Wants(config.WebhookEventScheduleFailure))
+
+	n.Notify(testEvent)
+	n.Notify(Event{Type: config.WebhookEventModuleHealth, Summary: "Module loki is unhealthy (was healthy)"})
+	n.wg.Wait()
+
+	received := rcv.received()
+	require.Len(t, received, 1)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(received[0]), &event))
+	assert.Equal(t, config.WebhookEventModuleHealth, event.Type)
+	assert.False(t, event.Time.IsZero())
+}
@@ -0,0 +1,305 @@
+// Package wheelcache manages the shared Python package cache mounted
+// read-only into sandbox containers. Wheels are built into a staging
+// directory, checked against the cache size limit, hashed into a manifest
+// and moved into place. Verify re-hashes cached files so a tampered or
+// truncated wheel is removed instead of being served to sandboxes.
+package wheelcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// WheelsDir is the subdirectory holding cached packages. Only this
+	// directory is mounted into sandboxes.
+	WheelsDir = "wheels"
+
+	// StagingDir holds in-progress builds before they are imported.
+	StagingDir = "staging"
+
+	// manifestFile records the checksum of every cached package.
+	manifestFile = "manifest.json"
+)
+
+// Entry describes a cached package file.
+type Entry struct {
+	Name    string    `json:"name"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Report summarizes the cache contents after verification.
+type Report struct {
+	Entries    []Entry  `json:"entries"`
+	TotalBytes int64    `json:"total_bytes"`
+	MaxBytes   int64    `json:"max_bytes"`
+	Removed    []string `json:"removed,omitempty"`
+}
+
+// Cache is a size-limited package cache rooted at a directory.
+type Cache struct {
+	root     string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// New creates a cache rooted at root holding at most maxBytes of packages.
+func New(root string, maxBytes int64) *Cache {
+	return &Cache{root: root, maxBytes: maxBytes}
+}
+
+// WheelsPath returns the directory mounted into sandboxes.
+func (c *Cache) WheelsPath() string {
+	return filepath.Join(c.root, WheelsDir)
+}
+
+// Init creates the cache directories.
+func (c *Cache) Init() error {
+	for _, dir := range []string{c.WheelsPath(), filepath.Join(c.root, StagingDir)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// NewStaging creates an empty, world-writable staging directory for a build
+// running as an unprivileged container user. Remove it once imported.
+func (c *Cache) NewStaging(id string) (string, error) {
+	dir := filepath.Join(c.root, StagingDir, id)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating staging directory: %w", err)
+	}
+
+	if err := os.Chmod(dir, 0777); err != nil {
+		return "", fmt.Errorf("setting staging directory permissions: %w", err)
+	}
+
+	return dir, nil
+}
+
+// Import moves the packages in staging into the cache and records their
+// checksums. The whole import is rejected if it would exceed the size limit.
+// Packages already cached under the same name are replaced.
+func (c *Cache) Import(staging string) ([]Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	manifest, err := c.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(staging)
+	if err != nil {
+		return nil, fmt.Errorf("reading staging directory: %w", err)
+	}
+
+	staged := make([]Entry, 0, len(files))
+	total := manifest.totalBytes()
+
+	for _, file := range files {
+		if !file.Type().IsRegular() || !isPackageFile(file.Name()) {
+			continue
+		}
+
+		entry, err := hashFile(filepath.Join(staging, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		total += entry.Size
+		if existing, ok := manifest[entry.Name]; ok {
+			total -= existing.Size
+		}
+
+		staged = append(staged, entry)
+	}
+
+	if c.maxBytes > 0 && total > c.maxBytes {
+		return nil, fmt.Errorf("import would grow the cache to %d bytes, over the %d byte limit", total, c.maxBytes)
+	}
+
+	now := time.Now().UTC()
+
+	for i := range staged {
+		staged[i].AddedAt = now
+
+		if err := os.Rename(filepath.Join(staging, staged[i].Name), filepath.Join(c.WheelsPath(), staged[i].Name)); err != nil {
+			return nil, fmt.Errorf("moving %s into cache: %w", staged[i].Name, err)
+		}
+
+		manifest[staged[i].Name] = staged[i]
+	}
+
+	if err := c.writeManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	return staged, nil
+}
+
+// Verify re-hashes every cached package against the manifest and removes
+// files that are missing from it or whose checksum no longer matches.
+func (c *Cache) Verify() (*Report, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	manifest, err := c.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(c.WheelsPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	report := &Report{MaxBytes: c.maxBytes}
+	seen := make(map[string]struct{}, len(files))
+
+	for _, file := range files {
+		name := file.Name()
+		seen[name] = struct{}{}
+
+		expected, ok := manifest[name]
+		if ok && file.Type().IsRegular() {
+			actual, err := hashFile(filepath.Join(c.WheelsPath(), name))
+			if err != nil {
+				return nil, err
+			}
+
+			if actual.SHA256 == expected.SHA256 {
+				continue
+			}
+		}
+
+		if err := os.RemoveAll(filepath.Join(c.WheelsPath(), name)); err != nil {
+			return nil, fmt.Errorf("removing %s: %w", name, err)
+		}
+
+		delete(manifest, name)
+		report.Removed = append(report.Removed, name)
+	}
+
+	// Drop manifest entries whose file has gone.
+	for name := range manifest {
+		if _, ok := seen[name]; !ok {
+			delete(manifest, name)
+			report.Removed = append(report.Removed, name)
+		}
+	}
+
+	if len(report.Removed) > 0 {
+		if err := c.writeManifest(manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Entries = manifest.entries()
+	report.TotalBytes = manifest.totalBytes()
+	sort.Strings(report.Removed)
+
+	return report, nil
+}
+
+type manifestEntries map[string]Entry
+
+func (m manifestEntries) totalBytes() int64 {
+	var total int64
+	for _, entry := range m {
+		total += entry.Size
+	}
+
+	return total
+}
+
+func (m manifestEntries) entries() []Entry {
+	entries := make([]Entry, 0, len(m))
+	for _, entry := range m {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries
+}
+
+func (c *Cache) readManifest() (manifestEntries, error) {
+	data, err := os.ReadFile(filepath.Join(c.root, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifestEntries{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading cache manifest: %w", err)
+	}
+
+	manifest := manifestEntries{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding cache manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func (c *Cache) writeManifest(manifest manifestEntries) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cache manifest: %w", err)
+	}
+
+	path := filepath.Join(c.root, manifestFile)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing cache manifest: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replacing cache manifest: %w", err)
+	}
+
+	return nil
+}
+
+func hashFile(path string) (Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return Entry{}, fmt.Errorf("hashing %s: %w", path, err)
+	}
+
+	return Entry{
+		Name:   filepath.Base(path),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		Size:   size,
+	}, nil
+}
+
+// isPackageFile reports whether name is a file pip can install from a
+// find-links directory.
+func isPackageFile(name string) bool {
+	return strings.HasSuffix(name, ".whl") || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".zip")
+}
@@ -0,0 +1,69 @@
+package wheelcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stageFile(t *testing.T, cache *Cache, name, content string) string {
+	t.Helper()
+
+	staging, err := cache.NewStaging(t.Name())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(staging, name), []byte(content), 0644))
+
+	return staging
+}
+
+func TestImportAndVerify(t *testing.T) {
+	cache := New(t.TempDir(), 0)
+	require.NoError(t, cache.Init())
+
+	entries, err := cache.Import(stageFile(t, cache, "polars-1.0-py3-none-any.whl", "wheel"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(5), entries[0].Size)
+
+	report, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Empty(t, report.Removed)
+	assert.Equal(t, int64(5), report.TotalBytes)
+	require.Len(t, report.Entries, 1)
+	assert.Equal(t, "polars-1.0-py3-none-any.whl", report.Entries[0].Name)
+}
+
+func TestImportRejectsOverLimit(t *testing.T) {
+	cache := New(t.TempDir(), 4)
+	require.NoError(t, cache.Init())
+
+	_, err := cache.Import(stageFile(t, cache, "big-1.0-py3-none-any.whl", "too large"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limit")
+
+	report, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Empty(t, report.Entries)
+}
+
+func TestVerifyRemovesTamperedAndUnknownFiles(t *testing.T) {
+	cache := New(t.TempDir(), 0)
+	require.NoError(t, cache.Init())
+
+	_, err := cache.Import(stageFile(t, cache, "a-1.0-py3-none-any.whl", "original"))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(cache.WheelsPath(), "a-1.0-py3-none-any.whl"), []byte("tampered"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(cache.WheelsPath(), "b-1.0-py3-none-any.whl"), []byte("unlisted"), 0644))
+
+	report, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a-1.0-py3-none-any.whl", "b-1.0-py3-none-any.whl"}, report.Removed)
+	assert.Empty(t, report.Entries)
+
+	_, err = os.Stat(filepath.Join(cache.WheelsPath(), "a-1.0-py3-none-any.whl"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
+	"os"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -15,32 +17,69 @@ import (
 //go:embed *.md
 var runbookFiles embed.FS
 
+// BuiltinSource is the Source of the runbooks embedded in the binary.
+const BuiltinSource = "builtin"
+
 // Load reads all embedded markdown files and parses them into Runbook objects.
 // Each file must have YAML frontmatter delimited by "---" markers.
 func Load() ([]types.Runbook, error) {
-	entries, err := runbookFiles.ReadDir(".")
+	return loadFS(runbookFiles, BuiltinSource)
+}
+
+// LoadDir reads all markdown files under dir, including subdirectories, and
+// parses them into Runbook objects tagged with source. FilePath is relative
+// to dir.
+func LoadDir(dir, source string) ([]types.Runbook, error) {
+	info, err := os.Stat(dir)
 	if err != nil {
 		return nil, fmt.Errorf("reading runbook directory: %w", err)
 	}
 
-	runbooks := make([]types.Runbook, 0, len(entries))
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return loadFS(os.DirFS(dir), source)
+}
+
+func loadFS(fsys fs.FS, source string) ([]types.Runbook, error) {
+	runbooks := make([]types.Runbook, 0, 16)
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
 
-		data, err := runbookFiles.ReadFile(entry.Name())
+		if entry.IsDir() {
+			// Skip VCS metadata and other hidden directories.
+			if path != "." && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(entry.Name(), ".md") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
-			return nil, fmt.Errorf("reading runbook %s: %w", entry.Name(), err)
+			return fmt.Errorf("reading runbook %s: %w", path, err)
 		}
 
-		rb, err := parseRunbook(data, entry.Name())
+		rb, err := parseRunbook(data, path)
 		if err != nil {
-			return nil, fmt.Errorf("parsing runbook %s: %w", entry.Name(), err)
+			return fmt.Errorf("parsing runbook %s: %w", path, err)
 		}
 
+		rb.Source = source
 		runbooks = append(runbooks, rb)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return runbooks, nil
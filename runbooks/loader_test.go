@@ -1,9 +1,14 @@
 package runbooks
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/panda/pkg/types"
 )
 
 func TestLoad(t *testing.T) {
@@ -63,3 +68,51 @@ func TestSplitFrontmatter(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+
+	runbook := "---\nname: Team Runbook\ndescription: A private runbook\n---\nSteps"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "team.md"), []byte(runbook), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "ignored.md"), []byte("not a runbook"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("ignored"), 0o644))
+
+	runbooks, err := LoadDir(dir, "team")
+	require.NoError(t, err)
+	require.Len(t, runbooks, 1)
+	require.Equal(t, "Team Runbook", runbooks[0].Name)
+	require.Equal(t, "team", runbooks[0].Source)
+	require.Equal(t, "nested/team.md", runbooks[0].FilePath)
+
+	_, err = LoadDir(filepath.Join(dir, "missing"), "team")
+	require.Error(t, err)
+}
+
+func TestRegistrySetSource(t *testing.T) {
+	reg, err := NewRegistry(logrus.New())
+	require.NoError(t, err)
+
+	builtin := reg.All()
+	require.NotEmpty(t, builtin)
+	require.Equal(t, BuiltinSource, builtin[0].Source)
+
+	team := []types.Runbook{
+		{Name: "Team Runbook", Description: "A private runbook", Source: "team"},
+		{Name: builtin[0].Name, Description: "Shadows a built-in runbook", Source: "team"},
+	}
+
+	require.True(t, reg.SetSource("team", team))
+	require.False(t, reg.SetSource("team", team), "unchanged source must report no change")
+
+	// The built-in runbook keeps its name.
+	require.Equal(t, len(builtin)+1, reg.Count())
+	require.Equal(t, BuiltinSource, reg.Get(builtin[0].Name).Source)
+	require.Equal(t, "team", reg.Get("Team Runbook").Source)
+
+	require.True(t, reg.SetSource("team", nil))
+	require.Nil(t, reg.Get("Team Runbook"))
+	require.Equal(t, len(builtin), reg.Count())
+}
@@ -2,6 +2,7 @@ package runbooks
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -10,11 +11,17 @@ import (
 )
 
 // Registry holds loaded runbooks and provides access for indexing and search.
+// Besides the embedded runbooks it holds runbooks from external sources,
+// which can be replaced while the registry is in use.
 type Registry struct {
 	log      logrus.FieldLogger
 	runbooks []types.Runbook
 	byName   map[string]*types.Runbook
-	mu       sync.RWMutex
+	// sources holds each source's runbooks; order is the order sources were
+	// first set, which decides who keeps a name that appears twice.
+	sources map[string][]types.Runbook
+	order   []string
+	mu      sync.RWMutex
 }
 
 // NewRegistry creates a new runbook registry and loads all embedded runbooks.
@@ -26,18 +33,66 @@ func NewRegistry(log logrus.FieldLogger) (*Registry, error) {
 		return nil, fmt.Errorf("loading runbooks: %w", err)
 	}
 
-	byName := make(map[string]*types.Runbook, len(runbooks))
-	for i := range runbooks {
-		byName[runbooks[i].Name] = &runbooks[i]
+	r := &Registry{
+		log:     log,
+		sources: map[string][]types.Runbook{BuiltinSource: runbooks},
+		order:   []string{BuiltinSource},
 	}
+	r.rebuildLocked()
 
-	log.WithField("runbook_count", len(runbooks)).Info("Runbook registry loaded")
+	log.WithField("runbook_count", len(r.runbooks)).Info("Runbook registry loaded")
 
-	return &Registry{
-		log:      log,
-		runbooks: runbooks,
-		byName:   byName,
-	}, nil
+	return r, nil
+}
+
+// SetSource replaces the runbooks of an external source. Runbooks whose name
+// is already taken by the built-in runbooks or an earlier source are left
+// out. It reports whether anything changed.
+func (r *Registry) SetSource(source string, runbooks []types.Runbook) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.sources[source]
+	if ok && reflect.DeepEqual(existing, runbooks) {
+		return false
+	}
+
+	if !ok {
+		r.order = append(r.order, source)
+	}
+
+	r.sources[source] = runbooks
+	r.rebuildLocked()
+
+	return true
+}
+
+// rebuildLocked flattens sources into runbooks and byName.
+func (r *Registry) rebuildLocked() {
+	r.runbooks = make([]types.Runbook, 0, len(r.runbooks))
+	taken := make(map[string]string, len(r.runbooks))
+
+	for _, source := range r.order {
+		for _, rb := range r.sources[source] {
+			if owner, dup := taken[rb.Name]; dup {
+				r.log.WithFields(logrus.Fields{
+					"runbook":  rb.Name,
+					"source":   source,
+					"taken_by": owner,
+				}).Warn("Skipping runbook with a name that is already taken")
+
+				continue
+			}
+
+			taken[rb.Name] = source
+			r.runbooks = append(r.runbooks, rb)
+		}
+	}
+
+	r.byName = make(map[string]*types.Runbook, len(r.runbooks))
+	for i := range r.runbooks {
+		r.byName[r.runbooks[i].Name] = &r.runbooks[i]
+	}
 }
 
 // All returns all loaded runbooks.
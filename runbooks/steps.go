@@ -0,0 +1,123 @@
+package runbooks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// stepNumberPattern matches the "1. " numbering of step headings, which is
+// left out of step titles.
+var stepNumberPattern = regexp.MustCompile(`^\d+[.)]\s+`)
+
+// Step is one step of a runbook: the prose leading up to a Python snippet
+// and the snippet itself. Prose after the last snippet becomes a final step
+// without code.
+type Step struct {
+	Title string
+	Text  string
+	Code  string
+}
+
+// Steps splits runbook markdown into steps, one per python fence. A step is
+// titled by the last heading before its snippet.
+func Steps(content string) []Step {
+	var (
+		steps []Step
+		title string
+		text  []string
+		code  []string
+		fence string
+	)
+
+	// titleLine indexes the title's heading in text, which the step title
+	// replaces.
+	titleLine := -1
+
+	flushText := func() string {
+		if titleLine >= 0 {
+			text = append(text[:titleLine], text[titleLine+1:]...)
+		}
+
+		return strings.TrimSpace(strings.Join(text, "\n"))
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence == "python":
+			if trimmed == "```" {
+				if title == "" {
+					title = fmt.Sprintf("Step %d", len(steps)+1)
+				}
+
+				steps = append(steps, Step{
+					Title: title,
+					Text:  flushText(),
+					Code:  strings.TrimSpace(strings.Join(code, "\n")),
+				})
+
+				text, code, fence, title, titleLine = nil, nil, "", "", -1
+
+				continue
+			}
+
+			code = append(code, line)
+		case fence != "":
+			if trimmed == "```" {
+				fence = ""
+			}
+
+			text = append(text, line)
+		case strings.HasPrefix(trimmed, "```"):
+			fence = strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			if fence == "" {
+				fence = "text"
+			}
+
+			if fence != "python" {
+				text = append(text, line)
+			}
+		case strings.HasPrefix(trimmed, "#"):
+			title = stepNumberPattern.ReplaceAllString(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), "")
+			titleLine = len(text)
+			text = append(text, line)
+		default:
+			text = append(text, line)
+		}
+	}
+
+	if rest := flushText(); rest != "" {
+		if title == "" {
+			title = "Notes"
+		}
+
+		steps = append(steps, Step{Title: title, Text: rest})
+	}
+
+	return steps
+}
+
+// Script joins a runbook's snippets into one program, so later steps see the
+// variables earlier ones defined.
+func Script(content string) string {
+	snippets := make([]string, 0, 8)
+
+	for _, step := range Steps(content) {
+		if step.Code != "" {
+			snippets = append(snippets, fmt.Sprintf("# %s\n%s", step.Title, step.Code))
+		}
+	}
+
+	return strings.Join(snippets, "\n\n")
+}
+
+// FillVars replaces each {key} placeholder in code with its value.
+func FillVars(code string, vars map[string]string) string {
+	for key, value := range vars {
+		code = strings.ReplaceAll(code, "{"+key+"}", value)
+	}
+
+	return code
+}
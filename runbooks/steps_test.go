@@ -0,0 +1,53 @@
+package runbooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSteps(t *testing.T) {
+	t.Parallel()
+
+	content := "Intro text.\n\n" +
+		"## Steps\n\n" +
+		"### 1. Count blocks\n\n" +
+		"```python\nprint(clickhouse.query(\"xatu\", \"SELECT 1\"))\n```\n\n" +
+		"Check the count.\n\n" +
+		"```sql\n# not a heading\nSELECT 2\n```\n\n" +
+		"```python\nprint(2)\n```\n\n" +
+		"## What to look for\n\n" +
+		"- Gaps\n"
+
+	steps := Steps(content)
+	require.Len(t, steps, 3)
+
+	assert.Equal(t, Step{
+		Title: "Count blocks",
+		Text:  "Intro text.\n\n## Steps",
+		Code:  `print(clickhouse.query("xatu", "SELECT 1"))`,
+	}, steps[0])
+
+	assert.Equal(t, "Step 2", steps[1].Title)
+	assert.Equal(t, "Check the count.\n\n```sql\n# not a heading\nSELECT 2\n```", steps[1].Text)
+	assert.Equal(t, "print(2)", steps[1].Code)
+
+	assert.Equal(t, Step{Title: "What to look for", Text: "- Gaps"}, steps[2])
+}
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+
+	content := "### 1. Load\n\n```python\ndf = load()\n```\n\n### 2. Show\n\n```python\nprint(df)\n```\n\n## Notes\n\n- none\n"
+
+	assert.Equal(t, "# Load\ndf = load()\n\n# Show\nprint(df)", Script(content))
+}
+
+func TestFillVars(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{"network": "mainnet"}
+
+	assert.Equal(t, `q("mainnet"), {other}`, FillVars(`q("{network}"), {other}`, vars))
+}
@@ -4,7 +4,11 @@ This library provides direct access to Ethereum network data:
 - ClickHouse: Raw and aggregated blockchain data
 - Prometheus: Infrastructure metrics
 - Loki: Log data
+- Grafana: Dashboards, panel renders and panel queries
+- http_json: Operator-declared JSON HTTP services
+- self_metrics: The panda deployment's own metrics
 - Storage: S3-compatible file storage for outputs
+- result(): Return a JSON value in the execute_python response
 
 Use list_datasources() on each module to discover available datasources or
 check the datasources://list MCP resource.
@@ -24,19 +28,24 @@ Example usage:
 
     # Upload output file
     url = storage.upload("/workspace/chart.png")
+
+    # Return a machine-readable result alongside stdout
+    from ethpandaops import result
+    result({"rows": len(df)})
 """
 
 from . import storage
+from ._result import result
 
 # Integration modules are assembled at Docker build time
 # and can be imported as: from ethpandaops import clickhouse, prometheus, loki
-__all__ = ["storage"]
+__all__ = ["result", "storage"]
 __version__ = "0.1.0"
 
 
 def __getattr__(name):
-    """Lazy import for integration modules (clickhouse, prometheus, loki, dora)."""
-    if name in ("cbt", "clickhouse", "prometheus", "loki", "dora", "ethnode"):
+    """Lazy import for integration modules (clickhouse, prometheus, loki, grafana, github, http_json, dora, beacon, forkmon, blobscan, checkpointz, assertoor, syncoor, self_metrics)."""
+    if name in ("cbt", "clickhouse", "prometheus", "loki", "grafana", "github", "http_json", "dora", "beacon", "forkmon", "blobscan", "checkpointz", "assertoor", "syncoor", "ethnode", "self_metrics"):
         import importlib
 
         mod = importlib.import_module(f".{name}", __name__)
@@ -0,0 +1,268 @@
+"""Stateful execution kernel for session containers.
+
+With kernel mode enabled the server runs each session execution as
+``python -m ethpandaops._kernel run <script>`` instead of ``python <script>``.
+The first run starts a long-lived kernel process in the container, and every
+run sends its code to that kernel over a Unix socket. The kernel executes
+all code in one namespace, so variables, dataframes and imports persist
+across executions in the session, as in a Jupyter notebook.
+
+Only output written through ``sys.stdout`` and ``sys.stderr`` is returned;
+output written straight to file descriptors 1 and 2 (e.g. by subprocesses)
+is discarded. Use ``subprocess.run(..., capture_output=True)`` and print it.
+"""
+
+from __future__ import annotations
+
+import ast
+import importlib
+import json
+import os
+import signal
+import site
+import socket
+import subprocess
+import sys
+import time
+import traceback
+from typing import Any
+
+SOCKET_PATH = "/tmp/panda-kernel.sock"
+
+# How long a run waits for a freshly started kernel to listen.
+_START_TIMEOUT = 15.0
+
+
+class _Timeout(BaseException):
+    """Raised inside user code when an execution exceeds its timeout."""
+
+
+class _StreamWriter:
+    """File-like object that forwards writes to the client as frames."""
+
+    def __init__(self, conn: socket.socket, stream: str) -> None:
+        self._conn = conn
+        self._stream = stream
+        self.closed = False
+
+    def write(self, data: str) -> int:
+        if data:
+            _send_frame(self._conn, {"stream": self._stream, "data": data})
+        return len(data)
+
+    def flush(self) -> None:
+        pass
+
+    def isatty(self) -> bool:
+        return False
+
+    def writable(self) -> bool:
+        return True
+
+    @property
+    def encoding(self) -> str:
+        return "utf-8"
+
+
+def _send_frame(conn: socket.socket, frame: dict[str, Any]) -> None:
+    try:
+        conn.sendall(json.dumps(frame).encode("utf-8") + b"\n")
+    except OSError:
+        # The client went away (e.g. the server timed the run out); keep
+        # executing so the namespace stays consistent.
+        pass
+
+
+def _apply_env(env: dict[str, str]) -> None:
+    """Replace the kernel's environment with the run's and refresh the
+    server API settings cached by already-imported modules."""
+    os.environ.clear()
+    os.environ.update(env)
+
+    runtime = sys.modules.get("ethpandaops._runtime")
+    if runtime is not None:
+        runtime._API_URL = env.get("ETHPANDAOPS_API_URL", "")
+        runtime._API_TOKEN = env.get("ETHPANDAOPS_API_TOKEN", "")
+
+    # Packages requested after the kernel started land in a user site that
+    # may not have existed when python set up sys.path.
+    user_site = site.getusersitepackages()
+    if os.path.isdir(user_site) and user_site not in sys.path:
+        site.addsitedir(user_site)
+    importlib.invalidate_caches()
+
+
+def _execute(code: str, filename: str, namespace: dict[str, Any]) -> int:
+    """Run code in namespace and return its exit code. When the last
+    statement is an expression its repr is printed, as in a notebook."""
+    tree = ast.parse(code, filename, "exec")
+
+    last = None
+    if tree.body and isinstance(tree.body[-1], ast.Expr):
+        last = ast.Expression(tree.body.pop().value)
+
+    exec(compile(tree, filename, "exec"), namespace)
+
+    if last is not None:
+        value = eval(compile(last, filename, "eval"), namespace)
+        if value is not None:
+            namespace["_"] = value
+            print(repr(value))
+
+    return 0
+
+
+def _serve_one(conn: socket.socket, namespace: dict[str, Any]) -> None:
+    reader = conn.makefile("rb")
+    try:
+        request = json.loads(reader.readline())
+    except (OSError, ValueError):
+        return
+    finally:
+        reader.close()
+
+    _apply_env(request.get("env") or {})
+
+    filename = request.get("filename") or "<kernel>"
+    timeout = int(request.get("timeout") or 0)
+
+    stdout, stderr = sys.stdout, sys.stderr
+    sys.stdout = _StreamWriter(conn, "stdout")
+    sys.stderr = _StreamWriter(conn, "stderr")
+    sys.argv = [filename]
+
+    exit_code = 0
+    try:
+        if timeout > 0:
+            signal.alarm(timeout)
+        exit_code = _execute(request.get("code", ""), filename, namespace)
+    except _Timeout:
+        print(f"Execution timed out after {timeout}s", file=sys.stderr)
+        exit_code = 124
+    except SystemExit as exc:
+        if exc.code is None:
+            exit_code = 0
+        elif isinstance(exc.code, int):
+            exit_code = exc.code
+        else:
+            print(exc.code, file=sys.stderr)
+            exit_code = 1
+    except BaseException as exc:  # noqa: BLE001 - report everything, like python does
+        # Hide the kernel's own frames so tracebacks match plain python.
+        tb = exc.__traceback__
+        while tb is not None and tb.tb_frame.f_code.co_filename == __file__:
+            tb = tb.tb_next
+        traceback.print_exception(type(exc), exc, tb)
+        exit_code = 1
+    finally:
+        signal.alarm(0)
+        sys.stdout.flush()
+        sys.stderr.flush()
+        sys.stdout, sys.stderr = stdout, stderr
+
+    _send_frame(conn, {"exit_code": exit_code})
+
+
+def serve() -> None:
+    """Run the kernel, executing one request at a time until killed."""
+
+    def on_alarm(signum: int, frame: Any) -> None:
+        raise _Timeout()
+
+    signal.signal(signal.SIGALRM, on_alarm)
+
+    try:
+        os.unlink(SOCKET_PATH)
+    except FileNotFoundError:
+        pass
+
+    server = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+    server.bind(SOCKET_PATH)
+    server.listen(8)
+
+    namespace: dict[str, Any] = {"__name__": "__main__", "__builtins__": __builtins__}
+
+    while True:
+        conn, _ = server.accept()
+        with conn:
+            _serve_one(conn, namespace)
+
+
+def _connect() -> socket.socket:
+    """Connect to the kernel, starting it on first use."""
+    try:
+        conn = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+        conn.connect(SOCKET_PATH)
+        return conn
+    except OSError:
+        conn.close()
+
+    subprocess.Popen(
+        [sys.executable, "-m", "ethpandaops._kernel", "serve"],
+        stdin=subprocess.DEVNULL,
+        stdout=subprocess.DEVNULL,
+        stderr=subprocess.DEVNULL,
+        start_new_session=True,
+    )
+
+    deadline = time.monotonic() + _START_TIMEOUT
+    while True:
+        conn = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+        try:
+            conn.connect(SOCKET_PATH)
+            return conn
+        except OSError:
+            conn.close()
+            if time.monotonic() > deadline:
+                raise RuntimeError("kernel did not start") from None
+            time.sleep(0.05)
+
+
+def run(script_path: str, timeout: int) -> int:
+    """Execute a script in the kernel, relaying its output."""
+    with open(script_path, encoding="utf-8") as f:
+        code = f.read()
+
+    conn = _connect()
+    with conn:
+        request = {
+            "code": code,
+            "filename": script_path,
+            "timeout": timeout,
+            "env": dict(os.environ),
+        }
+        conn.sendall(json.dumps(request).encode("utf-8") + b"\n")
+
+        with conn.makefile("rb") as reader:
+            for line in reader:
+                frame = json.loads(line)
+                if "exit_code" in frame:
+                    return int(frame["exit_code"])
+
+                out = sys.stdout if frame.get("stream") == "stdout" else sys.stderr
+                out.write(frame.get("data", ""))
+                out.flush()
+
+    print("kernel exited during execution; session state was lost", file=sys.stderr)
+    try:
+        os.unlink(SOCKET_PATH)
+    except OSError:
+        pass
+    return 1
+
+
+def main(argv: list[str]) -> int:
+    if len(argv) >= 1 and argv[0] == "serve":
+        serve()
+        return 0
+
+    if len(argv) >= 2 and argv[0] == "run":
+        timeout = int(argv[2]) if len(argv) >= 3 else 0
+        return run(argv[1], timeout)
+
+    print("usage: python -m ethpandaops._kernel serve | run <script> [timeout]", file=sys.stderr)
+    return 2
+
+
+if __name__ == "__main__":
+    sys.exit(main(sys.argv[1:]))
@@ -0,0 +1,77 @@
+"""Structured result channel from the sandbox to the tool response.
+
+The value passed to result() is serialized as JSON and returned in the
+execute_python response's "result" field, separate from stdout, so callers
+can chain computations without parsing printed text.
+
+Example:
+    from ethpandaops import clickhouse, result
+
+    df = clickhouse.query("xatu", "SELECT count() AS blocks FROM ...")
+    result({"blocks": int(df["blocks"][0])})
+"""
+
+import datetime
+import decimal
+import json
+import os
+
+_OUTPUT_DIR = "/output"
+_MAX_RESULT_SIZE = 1 << 20
+
+
+def _default(obj):
+    """Convert values the json module cannot serialize natively."""
+    # pandas DataFrame and Series.
+    if hasattr(obj, "to_dict") and hasattr(obj, "columns"):
+        return obj.to_dict(orient="records")
+    if hasattr(obj, "to_dict"):
+        return obj.to_dict()
+    # numpy arrays and scalars.
+    if hasattr(obj, "tolist"):
+        return obj.tolist()
+    if hasattr(obj, "item"):
+        return obj.item()
+    if isinstance(obj, (datetime.datetime, datetime.date, datetime.time)):
+        return obj.isoformat()
+    if isinstance(obj, datetime.timedelta):
+        return obj.total_seconds()
+    if isinstance(obj, decimal.Decimal):
+        return float(obj)
+    if isinstance(obj, (set, frozenset, tuple)):
+        return list(obj)
+    if isinstance(obj, bytes):
+        return obj.hex()
+    raise TypeError(f"Object of type {type(obj).__name__} is not JSON serializable")
+
+
+def result(obj) -> None:
+    """Return a JSON-serializable value as this execution's structured result.
+
+    Calling result() again replaces the previous value; only the last call is
+    returned. DataFrames become lists of records, numpy values become lists or
+    scalars, and dates become ISO 8601 strings.
+
+    Args:
+        obj: The value to return.
+
+    Raises:
+        ValueError: If the serialized value exceeds 1 MiB.
+        RuntimeError: If called outside a sandbox execution.
+    """
+    execution_id = os.environ.get("ETHPANDAOPS_EXECUTION_ID", "")
+    if not execution_id:
+        raise RuntimeError("result() is only available inside execute_python")
+
+    data = json.dumps(obj, default=_default, allow_nan=False)
+    if len(data.encode("utf-8")) > _MAX_RESULT_SIZE:
+        raise ValueError(
+            f"Result is {len(data.encode('utf-8'))} bytes; the limit is {_MAX_RESULT_SIZE}. "
+            "Return a summary and upload the full data with storage.upload()."
+        )
+
+    path = os.path.join(_OUTPUT_DIR, f".result-{execution_id}.json")
+    tmp_path = f"{path}.tmp"
+    with open(tmp_path, "w", encoding="utf-8") as f:
+        f.write(data)
+    os.replace(tmp_path, path)
@@ -14,6 +14,10 @@ import pandas as pd
 _API_URL = os.environ.get("ETHPANDAOPS_API_URL", "")
 _API_TOKEN = os.environ.get("ETHPANDAOPS_API_TOKEN", "")
 
+# In sessions the server rotates a fresh token into this file before the
+# execution's token expires.
+_TOKEN_FILE = "/workspace/.panda/api_token"
+
 
 def _check_api_config() -> None:
     if not _API_URL or not _API_TOKEN:
@@ -31,12 +35,39 @@ def _get_client() -> httpx.Client:
     )
 
 
+def _refresh_token() -> bool:
+    """Switch to the rotated token file if it holds a different token."""
+    global _API_TOKEN
+
+    try:
+        with open(_TOKEN_FILE, encoding="utf-8") as f:
+            token = f.read().strip()
+    except OSError:
+        return False
+
+    if not token or token == _API_TOKEN:
+        return False
+
+    _API_TOKEN = token
+    return True
+
+
+def send(client: httpx.Client, method: str, path: str, **kwargs: Any) -> httpx.Response:
+    """Send a server API request, retrying once with a rotated token on 401."""
+    response = client.request(method, path, **kwargs)
+    if response.status_code != 401 or not _refresh_token():
+        return response
+
+    client.headers["Authorization"] = f"Bearer {_API_TOKEN}"
+    return client.request(method, path, **kwargs)
+
+
 def _invoke_bytes(
     operation: str, args: dict[str, Any] | None = None
 ) -> tuple[bytes, str]:
     payload = {"args": args or {}}
     with _get_client() as client:
-        response = client.post(f"/api/v1/runtime/operations/{operation}", json=payload)
+        response = send(client, "POST", f"/api/v1/runtime/operations/{operation}", json=payload)
         body = response.read()
         if not response.is_success:
             raise ValueError(
@@ -75,6 +106,11 @@ def invoke(operation: str, args: dict[str, Any] | None = None) -> dict[str, Any]
     return data
 
 
+def invoke_bytes(operation: str, args: dict[str, Any] | None = None) -> bytes:
+    body, _ = _invoke_bytes(operation, args)
+    return body
+
+
 def invoke_json(operation: str, args: dict[str, Any] | None = None) -> Any:
     body, _ = _invoke_bytes(operation, args)
     return _decode_json(body, operation)
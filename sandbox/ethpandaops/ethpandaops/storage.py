@@ -63,7 +63,9 @@ def upload(local_path: str, remote_name: str | None = None) -> str:
 
     with _get_client() as client:
         with open(path, "rb") as f:
-            response = client.post(
+            response = _runtime.send(
+                client,
+                "POST",
                 "/api/v1/runtime/storage/upload",
                 content=f.read(),
                 params={"name": remote_name},
@@ -115,7 +117,7 @@ def list_files(prefix: str = "") -> list[dict]:
         params["prefix"] = prefix
 
     with _get_client() as client:
-        response = client.get("/api/v1/runtime/storage/files", params=params)
+        response = _runtime.send(client, "GET", "/api/v1/runtime/storage/files", params=params)
         response.raise_for_status()
         payload = response.json()
 
@@ -133,7 +135,7 @@ def get_url(key: str) -> str:
         Public URL for the file.
     """
     with _get_client() as client:
-        response = client.get("/api/v1/runtime/storage/url", params={"key": key})
+        response = _runtime.send(client, "GET", "/api/v1/runtime/storage/url", params={"key": key})
         response.raise_for_status()
         payload = response.json()
 